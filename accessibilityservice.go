@@ -0,0 +1,23 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rzxx/ben/internal/announce"
+)
+
+type AccessibilityService struct {
+	announce *announce.Service
+}
+
+func NewAccessibilityService(announceService *announce.Service) *AccessibilityService {
+	return &AccessibilityService{announce: announceService}
+}
+
+func (s *AccessibilityService) GetVerboseAnnouncements() bool {
+	return s.announce.Verbose()
+}
+
+func (s *AccessibilityService) SetVerboseAnnouncements(enabled bool) error {
+	return s.announce.SetVerbose(context.Background(), enabled)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/palette"
+)
+
+const (
+	// ambientFeedBurst pages may be fetched at once, e.g. the first page
+	// and a prefetched second one; after that one page per
+	// ambientFeedInterval.
+	ambientFeedBurst    = 3
+	ambientFeedInterval = 5 * time.Second
+)
+
+var ErrAmbientFeedRateLimited = errors.New("ambient cover feed requested too often, try again shortly")
+
+// ambientLimiter is a token bucket refilled at one page per
+// ambientFeedInterval.
+type ambientLimiter struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+func (l *ambientLimiter) allow(now time.Time) bool {
+	if l.updatedAt.IsZero() {
+		l.tokens = ambientFeedBurst
+	} else {
+		l.tokens = min(l.tokens+float64(now.Sub(l.updatedAt))/float64(ambientFeedInterval), ambientFeedBurst)
+	}
+	l.updatedAt = now
+
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// GetAmbientCovers returns a page of the shuffled high-resolution cover
+// feed for the idle ambient display, each cover with its palette. Pass the
+// seed and next offset of the previous page to continue the same shuffle.
+// Missing palettes are extracted and stored before the page is returned.
+func (s *ThemeService) GetAmbientCovers(seed int64, offset int, limit int) (library.AmbientCoverPage, error) {
+	s.ambientMu.Lock()
+	allowed := s.ambientLimiter.allow(time.Now())
+	s.ambientMu.Unlock()
+	if !allowed {
+		return library.AmbientCoverPage{}, ErrAmbientFeedRateLimited
+	}
+
+	ctx := context.Background()
+	page, err := s.browse.ListAmbientCovers(ctx, seed, offset, limit)
+	if err != nil {
+		return library.AmbientCoverPage{}, err
+	}
+
+	coverIDs := make([]int64, 0, len(page.Covers))
+	for _, cover := range page.Covers {
+		coverIDs = append(coverIDs, cover.CoverID)
+	}
+	stored, err := s.browse.GetCoverPalettes(ctx, coverIDs)
+	if err != nil {
+		return library.AmbientCoverPage{}, err
+	}
+
+	options := palette.DefaultExtractOptions()
+	for index := range page.Covers {
+		cover := &page.Covers[index]
+		if themePalette, ok := stored[cover.CoverID]; ok {
+			cover.Palette = &themePalette
+			continue
+		}
+
+		themePalette, err := s.GenerateFromCover(cover.CachePath, options)
+		if err != nil {
+			// The slide still shows; the display falls back to the
+			// dominant color.
+			continue
+		}
+		cover.Palette = &themePalette
+		_ = s.browse.SaveCoverPalette(ctx, cover.CoverID, themePalette)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+type AudiobookService struct {
+	audiobooks *library.AudiobookRepository
+	rescanner  libraryRescanner
+}
+
+type libraryRescanner interface {
+	TriggerScan() error
+}
+
+func NewAudiobookService(audiobooks *library.AudiobookRepository, rescanner libraryRescanner) *AudiobookService {
+	return &AudiobookService{audiobooks: audiobooks, rescanner: rescanner}
+}
+
+func (s *AudiobookService) ListAudiobookFolders() ([]library.AudiobookFolder, error) {
+	return s.audiobooks.ListFolders(context.Background())
+}
+
+// SetAudiobookFolder marks a watched root or folder as audiobooks and asks
+// the scanner to rebuild albums and artists without them.
+func (s *AudiobookService) SetAudiobookFolder(path string, audiobook bool) ([]library.AudiobookFolder, error) {
+	cleaned, err := normalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.audiobooks.SetFolder(context.Background(), cleaned, audiobook); err != nil {
+		return nil, err
+	}
+
+	_ = s.rescanner.TriggerScan()
+	return s.audiobooks.ListFolders(context.Background())
+}
+
+func (s *AudiobookService) ListAudiobooks(search string, limit int, offset int) (library.AudiobooksPage, error) {
+	return s.audiobooks.ListAudiobooks(context.Background(), search, limit, offset)
+}
+
+func (s *AudiobookService) GetAudiobook(title string, author string) (library.AudiobookDetail, error) {
+	return s.audiobooks.GetAudiobook(context.Background(), title, author)
+}
+
+// audiobookResumeStore lets the player resume audiobook chapters where they
+// were left. Lookup errors count as "not resumable" so playback never fails
+// because of them.
+type audiobookResumeStore struct {
+	audiobooks *library.AudiobookRepository
+}
+
+func (s audiobookResumeStore) ResumePosition(trackID int64) (int, bool) {
+	positionMS, ok, err := s.audiobooks.ResumePosition(context.Background(), trackID)
+	if err != nil {
+		return 0, false
+	}
+
+	return positionMS, ok
+}
+
+func (s audiobookResumeStore) SaveResumePosition(trackID int64, positionMS int, durationMS *int) {
+	_ = s.audiobooks.SaveResumePosition(context.Background(), trackID, positionMS, durationMS)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rzxx/ben/internal/backup"
+	"github.com/rzxx/ben/internal/jobs"
+)
+
+type BackupService struct {
+	backup *backup.Service
+	jobs   *jobs.Manager
+}
+
+func NewBackupService(backupService *backup.Service, jobManager *jobs.Manager) *BackupService {
+	return &BackupService{backup: backupService, jobs: jobManager}
+}
+
+func (s *BackupService) GetBackupStatus() (backup.Status, error) {
+	return s.backup.Status(context.Background())
+}
+
+func (s *BackupService) SetBackupConfig(config backup.Config) (backup.Status, error) {
+	return s.backup.SetConfig(context.Background(), config)
+}
+
+func (s *BackupService) BackupNow() (backup.Result, error) {
+	return runJob(s.jobs, JobKindBackup, "Backup", s.backup.BackupNow)
+}
+
+func (s *BackupService) ListBackups() ([]string, error) {
+	return s.backup.ListBackups(context.Background())
+}
+
+func (s *BackupService) PreviewRestore(name string, passphrase string) (backup.RestorePreview, error) {
+	return s.backup.PreviewRestore(context.Background(), name, passphrase)
+}
+
+func (s *BackupService) RestoreBackup(name string, passphrase string, replaceExisting bool) (backup.RestorePreview, error) {
+	return s.backup.Restore(context.Background(), name, passphrase, replaceExisting)
+}
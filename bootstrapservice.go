@@ -1,11 +1,12 @@
 package main
 
 import (
-	"ben/internal/library"
-	"ben/internal/player"
-	"ben/internal/queue"
-	"ben/internal/scanner"
 	"context"
+
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/player"
+	"github.com/rzxx/ben/internal/queue"
+	"github.com/rzxx/ben/internal/scanner"
 )
 
 const (
@@ -26,6 +27,7 @@ type BootstrapService struct {
 	queue      *queue.Service
 	player     *player.Service
 	scanner    *scanner.Service
+	startup    *StartupTimer
 }
 
 func NewBootstrapService(
@@ -33,12 +35,14 @@ func NewBootstrapService(
 	queueService *queue.Service,
 	playerService *player.Service,
 	scannerService *scanner.Service,
+	startup *StartupTimer,
 ) *BootstrapService {
 	return &BootstrapService{
 		browseRepo: browseRepo,
 		queue:      queueService,
 		player:     playerService,
 		scanner:    scannerService,
+		startup:    startup,
 	}
 }
 
@@ -56,6 +60,7 @@ func (s *BootstrapService) GetInitialState(albumsLimit int, albumsOffset int) (S
 		"",
 		albumsLimit,
 		albumsOffset,
+		false,
 	)
 	if err != nil {
 		return StartupSnapshot{}, err
@@ -69,3 +74,9 @@ func (s *BootstrapService) GetInitialState(albumsLimit int, albumsOffset int) (S
 		ThemeModePreference: defaultThemeModePreferenceAtBootup,
 	}, nil
 }
+
+// GetStartupReport returns how long each startup phase took, including the
+// loading deferred until after the window showed.
+func (s *BootstrapService) GetStartupReport() StartupReport {
+	return s.startup.Report()
+}
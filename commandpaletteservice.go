@@ -0,0 +1,23 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rzxx/ben/internal/commandpalette"
+)
+
+type CommandPaletteService struct {
+	palette *commandpalette.Service
+}
+
+func NewCommandPaletteService(paletteService *commandpalette.Service) *CommandPaletteService {
+	return &CommandPaletteService{palette: paletteService}
+}
+
+func (s *CommandPaletteService) Search(query string, limit int) ([]commandpalette.Result, error) {
+	return s.palette.Search(context.Background(), query, limit)
+}
+
+func (s *CommandPaletteService) ListActions() []commandpalette.Action {
+	return commandpalette.Actions()
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rzxx/ben/internal/coverfetch"
+	"github.com/rzxx/ben/internal/scanner"
+)
+
+type CoverFetchService struct {
+	fetcher *coverfetch.Service
+	scanner *scanner.Service
+}
+
+func NewCoverFetchService(fetcher *coverfetch.Service, scanService *scanner.Service) *CoverFetchService {
+	return &CoverFetchService{fetcher: fetcher, scanner: scanService}
+}
+
+// FetchAlbumCover downloads a cover for an album that has none from Cover
+// Art Archive or iTunes, caches it with its thumbnails and links it to the
+// album. It only runs when the user asks for it.
+func (s *CoverFetchService) FetchAlbumCover(title string, albumArtist string) (scanner.AlbumCoverChange, error) {
+	ctx := context.Background()
+	cover, err := s.fetcher.FetchAlbumCover(ctx, title, albumArtist)
+	if err != nil {
+		return scanner.AlbumCoverChange{Album: title, AlbumArtist: albumArtist}, err
+	}
+
+	return s.scanner.AttachAlbumCover(ctx, title, albumArtist, cover.Data, cover.URL)
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"ben/internal/coverart"
 	"database/sql"
 	"errors"
 	"net/http"
@@ -10,6 +9,8 @@ import (
 	"strings"
 
 	"go.senan.xyz/taglib"
+
+	"github.com/rzxx/ben/internal/coverart"
 )
 
 type CoverService struct {
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/rzxx/ben/internal/eventbus"
+	"github.com/rzxx/ben/internal/jobs"
+	"github.com/rzxx/ben/internal/player"
+	"github.com/rzxx/ben/internal/scanner"
+)
+
+const JobKindCoverWarmup = "coverWarmup"
+
+// coverWarmupDelay spaces out the covers so the warm-up never competes with
+// the UI for CPU. Only as many covers as the theme cache holds are warmed,
+// since older palettes would be evicted again straight away.
+const coverWarmupDelay = 150 * time.Millisecond
+
+// CoverWarmer pre-generates thumbnails and theme palettes for covers added by
+// a scan, so the first scroll through new albums does not generate them on
+// demand. It runs as a low-priority job and stops as soon as playback starts.
+type CoverWarmer struct {
+	db     *sql.DB
+	theme  *ThemeService
+	player *player.Service
+	jobs   *jobs.Manager
+	bus    *eventbus.Bus
+
+	mu          sync.Mutex
+	lastCoverID int64
+	cancel      context.CancelFunc
+}
+
+func NewCoverWarmer(database *sql.DB, themeService *ThemeService, playerService *player.Service, jobManager *jobs.Manager, bus *eventbus.Bus) *CoverWarmer {
+	warmer := &CoverWarmer{db: database, theme: themeService, player: playerService, jobs: jobManager, bus: bus}
+	eventbus.Subscribe(bus, player.EventStateChanged, warmer.HandlePlayerState)
+
+	return warmer
+}
+
+// Start records the covers that already exist, so only covers added after
+// launch are warmed, and starts listening for completed scans.
+func (w *CoverWarmer) Start() error {
+	var lastCoverID sql.NullInt64
+	if err := w.db.QueryRow("SELECT MAX(id) FROM covers").Scan(&lastCoverID); err != nil {
+		return fmt.Errorf("read latest cover: %w", err)
+	}
+
+	w.mu.Lock()
+	w.lastCoverID = lastCoverID.Int64
+	w.mu.Unlock()
+	eventbus.Subscribe(w.bus, scanner.EventProgress, w.HandleScanProgress)
+	return nil
+}
+
+// HandleScanProgress starts a warm-up when a scan completes.
+func (w *CoverWarmer) HandleScanProgress(progress scanner.Progress) {
+	if progress.Status != "completed" {
+		return
+	}
+	if w.player.GetState().Status == player.StatusPlaying {
+		return
+	}
+
+	w.mu.Lock()
+	if w.cancel != nil {
+		w.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	afterID := w.lastCoverID
+	w.mu.Unlock()
+
+	go w.run(ctx, cancel, afterID)
+}
+
+// HandlePlayerState cancels a running warm-up once playback starts.
+func (w *CoverWarmer) HandlePlayerState(state player.State) {
+	if state.Status != player.StatusPlaying {
+		return
+	}
+
+	w.mu.Lock()
+	cancel := w.cancel
+	w.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+func (w *CoverWarmer) run(ctx context.Context, cancel context.CancelFunc, afterID int64) {
+	defer func() {
+		w.mu.Lock()
+		w.cancel = nil
+		w.mu.Unlock()
+		cancel()
+	}()
+
+	covers, latestID, err := w.newCovers(ctx, afterID)
+	if err != nil {
+		log.Printf("cover warm-up skipped: %v", err)
+		return
+	}
+	if len(covers) == 0 {
+		return
+	}
+
+	task := w.jobs.Start(JobKindCoverWarmup, "Cover warm-up", cancel)
+	options := w.theme.DefaultOptions()
+	options.WorkerCount = 1
+	for index, cachePath := range covers {
+		select {
+		case <-ctx.Done():
+			task.Fail(ctx.Err())
+			return
+		case <-time.After(coverWarmupDelay):
+		}
+
+		// Covers that fail here are generated on demand as before.
+		_ = scanner.EnsureCoverThumbnails(cachePath)
+		_, _ = w.theme.GenerateFromCover(cachePath, options)
+		task.Progress((index+1)*100/len(covers), fmt.Sprintf("%d of %d covers", index+1, len(covers)))
+	}
+
+	w.mu.Lock()
+	w.lastCoverID = max(w.lastCoverID, latestID)
+	w.mu.Unlock()
+	task.Complete("")
+}
+
+// newCovers lists the newest covers added after afterID, newest first, and
+// the highest cover ID seen.
+func (w *CoverWarmer) newCovers(ctx context.Context, afterID int64) ([]string, int64, error) {
+	rows, err := w.db.QueryContext(
+		ctx,
+		`SELECT id, cache_path
+		FROM covers
+		WHERE id > ? AND cache_path IS NOT NULL AND TRIM(cache_path) <> ''
+		ORDER BY id DESC
+		LIMIT ?`,
+		afterID,
+		maxThemeCacheEntries,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query new covers: %w", err)
+	}
+	defer rows.Close()
+
+	covers := make([]string, 0)
+	latestID := afterID
+	for rows.Next() {
+		var coverID int64
+		var cachePath string
+		if err := rows.Scan(&coverID, &cachePath); err != nil {
+			return nil, 0, fmt.Errorf("scan new cover: %w", err)
+		}
+		covers = append(covers, cachePath)
+		latestID = max(latestID, coverID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate new covers: %w", err)
+	}
+
+	return covers, latestID, nil
+}
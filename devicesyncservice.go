@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rzxx/ben/internal/devicesync"
+	"github.com/rzxx/ben/internal/jobs"
+	"github.com/rzxx/ben/internal/snapshot"
+)
+
+// syncMergedTables are the tables a sync bundle import merges into.
+var syncMergedTables = []string{"synced_play_stats", "track_ratings"}
+
+type DeviceSyncService struct {
+	sync      *devicesync.Service
+	jobs      *jobs.Manager
+	snapshots *snapshot.Service
+}
+
+func NewDeviceSyncService(syncService *devicesync.Service, jobManager *jobs.Manager, snapshots *snapshot.Service) *DeviceSyncService {
+	return &DeviceSyncService{sync: syncService, jobs: jobManager, snapshots: snapshots}
+}
+
+func (s *DeviceSyncService) GetSyncStatus() (devicesync.Status, error) {
+	return s.sync.Status(context.Background())
+}
+
+func (s *DeviceSyncService) ExportSyncBundle(path string) error {
+	_, err := runJob(s.jobs, JobKindSyncExport, "Sync bundle export", func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, s.sync.ExportToFile(ctx, path)
+	})
+	return err
+}
+
+// ImportSyncBundle snapshots the merged tables first so the merge can be
+// rolled back from the snapshot service.
+func (s *DeviceSyncService) ImportSyncBundle(path string) (devicesync.ImportResult, error) {
+	ctx := context.Background()
+	if err := s.snapshots.Capture(ctx, "Import sync bundle", syncMergedTables...); err != nil {
+		return devicesync.ImportResult{}, err
+	}
+
+	return s.sync.ImportFromFile(ctx, path)
+}
+
+func (s *DeviceSyncService) SetSyncFolder(path string) error {
+	return s.sync.SetFolder(context.Background(), path)
+}
+
+func (s *DeviceSyncService) SyncFolderNow() ([]devicesync.ImportResult, error) {
+	ctx := context.Background()
+	if err := s.snapshots.Capture(ctx, "Sync folder", syncMergedTables...); err != nil {
+		return nil, err
+	}
+
+	return s.sync.SyncFolderNow(ctx)
+}
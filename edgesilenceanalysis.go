@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/rzxx/ben/internal/eventbus"
+	"github.com/rzxx/ben/internal/jobs"
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/player"
+	"github.com/rzxx/ben/internal/scanner"
+)
+
+const JobKindEdgeSilence = "edgeSilence"
+
+const (
+	// edgeSilenceBatchSize is how many tracks are read from the database at
+	// a time, so a large library is not listed in one go.
+	edgeSilenceBatchSize = 200
+	// edgeSilenceDelay spaces out the decodes so the analysis never
+	// competes with playback for CPU.
+	edgeSilenceDelay = 100 * time.Millisecond
+)
+
+// EdgeSilenceAnalyzer measures the silence at the start and end of new and
+// changed tracks with ffmpeg after each scan. Crossfade trims the silence
+// and album mix detection looks for albums without it between tracks.
+type EdgeSilenceAnalyzer struct {
+	silences *library.EdgeSilenceRepository
+	player   *player.Service
+	jobs     *jobs.Manager
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+func NewEdgeSilenceAnalyzer(silences *library.EdgeSilenceRepository, playerService *player.Service, jobManager *jobs.Manager, bus *eventbus.Bus) *EdgeSilenceAnalyzer {
+	analyzer := &EdgeSilenceAnalyzer{silences: silences, player: playerService, jobs: jobManager}
+	eventbus.Subscribe(bus, scanner.EventProgress, analyzer.HandleScanProgress)
+
+	return analyzer
+}
+
+// HandleScanProgress starts an analysis when a scan completes.
+func (a *EdgeSilenceAnalyzer) HandleScanProgress(progress scanner.Progress) {
+	if progress.Status != "completed" {
+		return
+	}
+	if !a.player.GetTranscodeFallback().FFmpegAvailable {
+		return
+	}
+
+	a.mu.Lock()
+	if a.cancel != nil {
+		a.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancel = cancel
+	a.mu.Unlock()
+
+	go a.run(ctx, cancel)
+}
+
+func (a *EdgeSilenceAnalyzer) run(ctx context.Context, cancel context.CancelFunc) {
+	defer func() {
+		a.mu.Lock()
+		a.cancel = nil
+		a.mu.Unlock()
+		cancel()
+	}()
+
+	total, err := a.silences.CountPendingEdgeSilence(ctx)
+	if err != nil {
+		log.Printf("edge silence analysis skipped: %v", err)
+		return
+	}
+	if total == 0 {
+		return
+	}
+	targets, err := a.silences.ListPendingEdgeSilence(ctx, edgeSilenceBatchSize)
+	if err != nil {
+		log.Printf("edge silence analysis skipped: %v", err)
+		return
+	}
+
+	task := a.jobs.Start(JobKindEdgeSilence, "Silence analysis", cancel)
+	analyzed := 0
+	for len(targets) > 0 {
+		for _, target := range targets {
+			select {
+			case <-ctx.Done():
+				a.refreshPlayer(analyzed)
+				task.Fail(ctx.Err())
+				return
+			case <-time.After(edgeSilenceDelay):
+			}
+
+			silence, analyzeErr := player.AnalyzeEdgeSilence(ctx, target.Path, target.DurationMS)
+			if ctx.Err() != nil {
+				continue
+			}
+			if analyzeErr != nil {
+				// Files ffmpeg cannot read are retried once they change.
+				err = a.silences.MarkEdgeSilenceFailed(ctx, target)
+			} else {
+				err = a.silences.SaveEdgeSilence(ctx, target, library.EdgeSilence{
+					LeadMS:     silence.LeadMS,
+					TailMS:     silence.TailMS,
+					DurationMS: silence.DurationMS,
+				})
+			}
+			if err != nil {
+				a.refreshPlayer(analyzed)
+				task.Fail(err)
+				return
+			}
+
+			analyzed++
+			task.Progress(analyzed*100/max(total, analyzed), fmt.Sprintf("%d of %d tracks", analyzed, max(total, analyzed)))
+		}
+
+		targets, err = a.silences.ListPendingEdgeSilence(ctx, edgeSilenceBatchSize)
+		if err != nil {
+			a.refreshPlayer(analyzed)
+			task.Fail(err)
+			return
+		}
+	}
+
+	a.refreshPlayer(analyzed)
+	task.Complete(fmt.Sprintf("%d tracks analyzed", analyzed))
+}
+
+// refreshPlayer drops the player's cached mix lookups and trims, which
+// depend on the analysis.
+func (a *EdgeSilenceAnalyzer) refreshPlayer(analyzed int) {
+	if analyzed == 0 {
+		return
+	}
+
+	a.player.InvalidateContinuousMix()
+	a.player.InvalidateTrackTrims()
+}
@@ -1,25 +0,0 @@
-// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
-// This file is automatically generated. DO NOT EDIT
-
-import * as BootstrapService from "./bootstrapservice.js";
-import * as LibraryService from "./libraryservice.js";
-import * as PlayerService from "./playerservice.js";
-import * as QueueService from "./queueservice.js";
-import * as ScannerService from "./scannerservice.js";
-import * as SettingsService from "./settingsservice.js";
-import * as StatsService from "./statsservice.js";
-import * as ThemeService from "./themeservice.js";
-export {
-    BootstrapService,
-    LibraryService,
-    PlayerService,
-    QueueService,
-    ScannerService,
-    SettingsService,
-    StatsService,
-    ThemeService
-};
-
-export {
-    StartupSnapshot
-} from "./models.js";
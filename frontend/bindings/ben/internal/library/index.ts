@@ -1,16 +0,0 @@
-// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
-// This file is automatically generated. DO NOT EDIT
-
-export {
-    AlbumDetail,
-    AlbumSummary,
-    AlbumsPage,
-    ArtistDetail,
-    ArtistSummary,
-    ArtistTopTrack,
-    ArtistsPage,
-    PageInfo,
-    TrackSummary,
-    TracksPage,
-    WatchedRoot
-} from "./models.js";
@@ -1,434 +0,0 @@
-// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
-// This file is automatically generated. DO NOT EDIT
-
-// eslint-disable-next-line @typescript-eslint/ban-ts-comment
-// @ts-ignore: Unused imports
-import { Create as $Create } from "@wailsio/runtime";
-
-export class AlbumDetail {
-    "title": string;
-    "albumArtist": string;
-    "year"?: number | null;
-    "trackCount": number;
-    "coverPath"?: string | null;
-    "tracks": TrackSummary[];
-    "page": PageInfo;
-
-    /** Creates a new AlbumDetail instance. */
-    constructor($$source: Partial<AlbumDetail> = {}) {
-        if (!("title" in $$source)) {
-            this["title"] = "";
-        }
-        if (!("albumArtist" in $$source)) {
-            this["albumArtist"] = "";
-        }
-        if (!("trackCount" in $$source)) {
-            this["trackCount"] = 0;
-        }
-        if (!("tracks" in $$source)) {
-            this["tracks"] = [];
-        }
-        if (!("page" in $$source)) {
-            this["page"] = (new PageInfo());
-        }
-
-        Object.assign(this, $$source);
-    }
-
-    /**
-     * Creates a new AlbumDetail instance from a string or object.
-     */
-    static createFrom($$source: any = {}): AlbumDetail {
-        const $$createField5_0 = $$createType1;
-        const $$createField6_0 = $$createType2;
-        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        if ("tracks" in $$parsedSource) {
-            $$parsedSource["tracks"] = $$createField5_0($$parsedSource["tracks"]);
-        }
-        if ("page" in $$parsedSource) {
-            $$parsedSource["page"] = $$createField6_0($$parsedSource["page"]);
-        }
-        return new AlbumDetail($$parsedSource as Partial<AlbumDetail>);
-    }
-}
-
-export class AlbumSummary {
-    "title": string;
-    "albumArtist": string;
-    "year"?: number | null;
-    "trackCount": number;
-    "coverPath"?: string | null;
-
-    /** Creates a new AlbumSummary instance. */
-    constructor($$source: Partial<AlbumSummary> = {}) {
-        if (!("title" in $$source)) {
-            this["title"] = "";
-        }
-        if (!("albumArtist" in $$source)) {
-            this["albumArtist"] = "";
-        }
-        if (!("trackCount" in $$source)) {
-            this["trackCount"] = 0;
-        }
-
-        Object.assign(this, $$source);
-    }
-
-    /**
-     * Creates a new AlbumSummary instance from a string or object.
-     */
-    static createFrom($$source: any = {}): AlbumSummary {
-        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        return new AlbumSummary($$parsedSource as Partial<AlbumSummary>);
-    }
-}
-
-export class AlbumsPage {
-    "items": AlbumSummary[];
-    "page": PageInfo;
-
-    /** Creates a new AlbumsPage instance. */
-    constructor($$source: Partial<AlbumsPage> = {}) {
-        if (!("items" in $$source)) {
-            this["items"] = [];
-        }
-        if (!("page" in $$source)) {
-            this["page"] = (new PageInfo());
-        }
-
-        Object.assign(this, $$source);
-    }
-
-    /**
-     * Creates a new AlbumsPage instance from a string or object.
-     */
-    static createFrom($$source: any = {}): AlbumsPage {
-        const $$createField0_0 = $$createType4;
-        const $$createField1_0 = $$createType2;
-        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        if ("items" in $$parsedSource) {
-            $$parsedSource["items"] = $$createField0_0($$parsedSource["items"]);
-        }
-        if ("page" in $$parsedSource) {
-            $$parsedSource["page"] = $$createField1_0($$parsedSource["page"]);
-        }
-        return new AlbumsPage($$parsedSource as Partial<AlbumsPage>);
-    }
-}
-
-export class ArtistDetail {
-    "name": string;
-    "trackCount": number;
-    "albumCount": number;
-    "albums": AlbumSummary[];
-    "page": PageInfo;
-
-    /** Creates a new ArtistDetail instance. */
-    constructor($$source: Partial<ArtistDetail> = {}) {
-        if (!("name" in $$source)) {
-            this["name"] = "";
-        }
-        if (!("trackCount" in $$source)) {
-            this["trackCount"] = 0;
-        }
-        if (!("albumCount" in $$source)) {
-            this["albumCount"] = 0;
-        }
-        if (!("albums" in $$source)) {
-            this["albums"] = [];
-        }
-        if (!("page" in $$source)) {
-            this["page"] = (new PageInfo());
-        }
-
-        Object.assign(this, $$source);
-    }
-
-    /**
-     * Creates a new ArtistDetail instance from a string or object.
-     */
-    static createFrom($$source: any = {}): ArtistDetail {
-        const $$createField3_0 = $$createType4;
-        const $$createField4_0 = $$createType2;
-        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        if ("albums" in $$parsedSource) {
-            $$parsedSource["albums"] = $$createField3_0($$parsedSource["albums"]);
-        }
-        if ("page" in $$parsedSource) {
-            $$parsedSource["page"] = $$createField4_0($$parsedSource["page"]);
-        }
-        return new ArtistDetail($$parsedSource as Partial<ArtistDetail>);
-    }
-}
-
-export class ArtistSummary {
-    "name": string;
-    "trackCount": number;
-    "albumCount": number;
-
-    /** Creates a new ArtistSummary instance. */
-    constructor($$source: Partial<ArtistSummary> = {}) {
-        if (!("name" in $$source)) {
-            this["name"] = "";
-        }
-        if (!("trackCount" in $$source)) {
-            this["trackCount"] = 0;
-        }
-        if (!("albumCount" in $$source)) {
-            this["albumCount"] = 0;
-        }
-
-        Object.assign(this, $$source);
-    }
-
-    /**
-     * Creates a new ArtistSummary instance from a string or object.
-     */
-    static createFrom($$source: any = {}): ArtistSummary {
-        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        return new ArtistSummary($$parsedSource as Partial<ArtistSummary>);
-    }
-}
-
-export class ArtistTopTrack {
-    "trackId": number;
-    "title": string;
-    "artist": string;
-    "album": string;
-    "albumArtist": string;
-    "discNo"?: number | null;
-    "trackNo"?: number | null;
-    "durationMs"?: number | null;
-    "path": string;
-    "coverPath"?: string | null;
-    "playedMs": number;
-    "completeCount": number;
-    "skipCount": number;
-    "partialCount": number;
-
-    /** Creates a new ArtistTopTrack instance. */
-    constructor($$source: Partial<ArtistTopTrack> = {}) {
-        if (!("trackId" in $$source)) {
-            this["trackId"] = 0;
-        }
-        if (!("title" in $$source)) {
-            this["title"] = "";
-        }
-        if (!("artist" in $$source)) {
-            this["artist"] = "";
-        }
-        if (!("album" in $$source)) {
-            this["album"] = "";
-        }
-        if (!("albumArtist" in $$source)) {
-            this["albumArtist"] = "";
-        }
-        if (!("path" in $$source)) {
-            this["path"] = "";
-        }
-        if (!("playedMs" in $$source)) {
-            this["playedMs"] = 0;
-        }
-        if (!("completeCount" in $$source)) {
-            this["completeCount"] = 0;
-        }
-        if (!("skipCount" in $$source)) {
-            this["skipCount"] = 0;
-        }
-        if (!("partialCount" in $$source)) {
-            this["partialCount"] = 0;
-        }
-
-        Object.assign(this, $$source);
-    }
-
-    /**
-     * Creates a new ArtistTopTrack instance from a string or object.
-     */
-    static createFrom($$source: any = {}): ArtistTopTrack {
-        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        return new ArtistTopTrack($$parsedSource as Partial<ArtistTopTrack>);
-    }
-}
-
-export class ArtistsPage {
-    "items": ArtistSummary[];
-    "page": PageInfo;
-
-    /** Creates a new ArtistsPage instance. */
-    constructor($$source: Partial<ArtistsPage> = {}) {
-        if (!("items" in $$source)) {
-            this["items"] = [];
-        }
-        if (!("page" in $$source)) {
-            this["page"] = (new PageInfo());
-        }
-
-        Object.assign(this, $$source);
-    }
-
-    /**
-     * Creates a new ArtistsPage instance from a string or object.
-     */
-    static createFrom($$source: any = {}): ArtistsPage {
-        const $$createField0_0 = $$createType6;
-        const $$createField1_0 = $$createType2;
-        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        if ("items" in $$parsedSource) {
-            $$parsedSource["items"] = $$createField0_0($$parsedSource["items"]);
-        }
-        if ("page" in $$parsedSource) {
-            $$parsedSource["page"] = $$createField1_0($$parsedSource["page"]);
-        }
-        return new ArtistsPage($$parsedSource as Partial<ArtistsPage>);
-    }
-}
-
-export class PageInfo {
-    "limit": number;
-    "offset": number;
-    "total": number;
-
-    /** Creates a new PageInfo instance. */
-    constructor($$source: Partial<PageInfo> = {}) {
-        if (!("limit" in $$source)) {
-            this["limit"] = 0;
-        }
-        if (!("offset" in $$source)) {
-            this["offset"] = 0;
-        }
-        if (!("total" in $$source)) {
-            this["total"] = 0;
-        }
-
-        Object.assign(this, $$source);
-    }
-
-    /**
-     * Creates a new PageInfo instance from a string or object.
-     */
-    static createFrom($$source: any = {}): PageInfo {
-        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        return new PageInfo($$parsedSource as Partial<PageInfo>);
-    }
-}
-
-export class TrackSummary {
-    "id": number;
-    "title": string;
-    "artist": string;
-    "album": string;
-    "albumArtist": string;
-    "discNo"?: number | null;
-    "trackNo"?: number | null;
-    "durationMs"?: number | null;
-    "path": string;
-    "coverPath"?: string | null;
-
-    /** Creates a new TrackSummary instance. */
-    constructor($$source: Partial<TrackSummary> = {}) {
-        if (!("id" in $$source)) {
-            this["id"] = 0;
-        }
-        if (!("title" in $$source)) {
-            this["title"] = "";
-        }
-        if (!("artist" in $$source)) {
-            this["artist"] = "";
-        }
-        if (!("album" in $$source)) {
-            this["album"] = "";
-        }
-        if (!("albumArtist" in $$source)) {
-            this["albumArtist"] = "";
-        }
-        if (!("path" in $$source)) {
-            this["path"] = "";
-        }
-
-        Object.assign(this, $$source);
-    }
-
-    /**
-     * Creates a new TrackSummary instance from a string or object.
-     */
-    static createFrom($$source: any = {}): TrackSummary {
-        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        return new TrackSummary($$parsedSource as Partial<TrackSummary>);
-    }
-}
-
-export class TracksPage {
-    "items": TrackSummary[];
-    "page": PageInfo;
-
-    /** Creates a new TracksPage instance. */
-    constructor($$source: Partial<TracksPage> = {}) {
-        if (!("items" in $$source)) {
-            this["items"] = [];
-        }
-        if (!("page" in $$source)) {
-            this["page"] = (new PageInfo());
-        }
-
-        Object.assign(this, $$source);
-    }
-
-    /**
-     * Creates a new TracksPage instance from a string or object.
-     */
-    static createFrom($$source: any = {}): TracksPage {
-        const $$createField0_0 = $$createType1;
-        const $$createField1_0 = $$createType2;
-        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        if ("items" in $$parsedSource) {
-            $$parsedSource["items"] = $$createField0_0($$parsedSource["items"]);
-        }
-        if ("page" in $$parsedSource) {
-            $$parsedSource["page"] = $$createField1_0($$parsedSource["page"]);
-        }
-        return new TracksPage($$parsedSource as Partial<TracksPage>);
-    }
-}
-
-export class WatchedRoot {
-    "id": number;
-    "path": string;
-    "enabled": boolean;
-    "createdAt": string;
-
-    /** Creates a new WatchedRoot instance. */
-    constructor($$source: Partial<WatchedRoot> = {}) {
-        if (!("id" in $$source)) {
-            this["id"] = 0;
-        }
-        if (!("path" in $$source)) {
-            this["path"] = "";
-        }
-        if (!("enabled" in $$source)) {
-            this["enabled"] = false;
-        }
-        if (!("createdAt" in $$source)) {
-            this["createdAt"] = "";
-        }
-
-        Object.assign(this, $$source);
-    }
-
-    /**
-     * Creates a new WatchedRoot instance from a string or object.
-     */
-    static createFrom($$source: any = {}): WatchedRoot {
-        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        return new WatchedRoot($$parsedSource as Partial<WatchedRoot>);
-    }
-}
-
-// Private type creation functions
-const $$createType0 = TrackSummary.createFrom;
-const $$createType1 = $Create.Array($$createType0);
-const $$createType2 = PageInfo.createFrom;
-const $$createType3 = AlbumSummary.createFrom;
-const $$createType4 = $Create.Array($$createType3);
-const $$createType5 = ArtistSummary.createFrom;
-const $$createType6 = $Create.Array($$createType5);
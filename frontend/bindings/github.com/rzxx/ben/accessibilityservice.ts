@@ -0,0 +1,14 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+export function GetVerboseAnnouncements(): $CancellablePromise<boolean> {
+    return $Call.ByID(2240767357);
+}
+
+export function SetVerboseAnnouncements(enabled: boolean): $CancellablePromise<void> {
+    return $Call.ByID(3919970697, enabled);
+}
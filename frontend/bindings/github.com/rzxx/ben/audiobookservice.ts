@@ -0,0 +1,44 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as library$0 from "./internal/library/models.js";
+
+export function GetAudiobook(title: string, author: string): $CancellablePromise<library$0.AudiobookDetail> {
+    return $Call.ByID(214386373, title, author).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function ListAudiobookFolders(): $CancellablePromise<library$0.AudiobookFolder[]> {
+    return $Call.ByID(3484286250).then(($result: any) => {
+        return $$createType2($result);
+    });
+}
+
+export function ListAudiobooks(search: string, limit: number, offset: number): $CancellablePromise<library$0.AudiobooksPage> {
+    return $Call.ByID(3816422498, search, limit, offset).then(($result: any) => {
+        return $$createType3($result);
+    });
+}
+
+/**
+ * SetAudiobookFolder marks a watched root or folder as audiobooks and asks
+ * the scanner to rebuild albums and artists without them.
+ */
+export function SetAudiobookFolder(path: string, audiobook: boolean): $CancellablePromise<library$0.AudiobookFolder[]> {
+    return $Call.ByID(1972718313, path, audiobook).then(($result: any) => {
+        return $$createType2($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = library$0.AudiobookDetail.createFrom;
+const $$createType1 = library$0.AudiobookFolder.createFrom;
+const $$createType2 = $Create.Array($$createType1);
+const $$createType3 = library$0.AudiobooksPage.createFrom;
@@ -0,0 +1,52 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as backup$0 from "./internal/backup/models.js";
+
+export function BackupNow(): $CancellablePromise<backup$0.Result> {
+    return $Call.ByID(2386899979).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function GetBackupStatus(): $CancellablePromise<backup$0.Status> {
+    return $Call.ByID(1352543593).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+export function ListBackups(): $CancellablePromise<string[]> {
+    return $Call.ByID(2077789142).then(($result: any) => {
+        return $$createType2($result);
+    });
+}
+
+export function PreviewRestore(name: string, passphrase: string): $CancellablePromise<backup$0.RestorePreview> {
+    return $Call.ByID(2973940905, name, passphrase).then(($result: any) => {
+        return $$createType3($result);
+    });
+}
+
+export function RestoreBackup(name: string, passphrase: string, replaceExisting: boolean): $CancellablePromise<backup$0.RestorePreview> {
+    return $Call.ByID(1697621657, name, passphrase, replaceExisting).then(($result: any) => {
+        return $$createType3($result);
+    });
+}
+
+export function SetBackupConfig(config: backup$0.Config): $CancellablePromise<backup$0.Status> {
+    return $Call.ByID(1933007577, config).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = backup$0.Result.createFrom;
+const $$createType1 = backup$0.Status.createFrom;
+const $$createType2 = $Create.Array($Create.Any);
+const $$createType3 = backup$0.RestorePreview.createFrom;
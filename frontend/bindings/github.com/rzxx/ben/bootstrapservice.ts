@@ -15,5 +15,16 @@ export function GetInitialState(albumsLimit: number, albumsOffset: number): $Can
     });
 }
 
+/**
+ * GetStartupReport returns how long each startup phase took, including the
+ * loading deferred until after the window showed.
+ */
+export function GetStartupReport(): $CancellablePromise<$models.StartupReport> {
+    return $Call.ByID(2515741324).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
 // Private type creation functions
 const $$createType0 = $models.StartupSnapshot.createFrom;
+const $$createType1 = $models.StartupReport.createFrom;
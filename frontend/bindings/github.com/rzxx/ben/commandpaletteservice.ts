@@ -0,0 +1,28 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as commandpalette$0 from "./internal/commandpalette/models.js";
+
+export function ListActions(): $CancellablePromise<commandpalette$0.Action[]> {
+    return $Call.ByID(2256879960).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+export function Search(query: string, limit: number): $CancellablePromise<commandpalette$0.Result[]> {
+    return $Call.ByID(864312081, query, limit).then(($result: any) => {
+        return $$createType3($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = commandpalette$0.Action.createFrom;
+const $$createType1 = $Create.Array($$createType0);
+const $$createType2 = commandpalette$0.Result.createFrom;
+const $$createType3 = $Create.Array($$createType2);
@@ -9,23 +9,16 @@ import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Cr
 // @ts-ignore: Unused imports
 import * as scanner$0 from "./internal/scanner/models.js";
 
-export function GetStatus(): $CancellablePromise<scanner$0.Status> {
-    return $Call.ByID(966301467).then(($result: any) => {
+/**
+ * FetchAlbumCover downloads a cover for an album that has none from Cover
+ * Art Archive or iTunes, caches it with its thumbnails and links it to the
+ * album. It only runs when the user asks for it.
+ */
+export function FetchAlbumCover(title: string, albumArtist: string): $CancellablePromise<scanner$0.AlbumCoverChange> {
+    return $Call.ByID(3786472450, title, albumArtist).then(($result: any) => {
         return $$createType0($result);
     });
 }
 
-export function TriggerFullScan(): $CancellablePromise<void> {
-    return $Call.ByID(1583516401);
-}
-
-export function TriggerIncrementalScan(): $CancellablePromise<void> {
-    return $Call.ByID(4128331878);
-}
-
-export function TriggerScan(): $CancellablePromise<void> {
-    return $Call.ByID(1754448716);
-}
-
 // Private type creation functions
-const $$createType0 = scanner$0.Status.createFrom;
+const $$createType0 = scanner$0.AlbumCoverChange.createFrom;
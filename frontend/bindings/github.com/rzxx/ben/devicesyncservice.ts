@@ -0,0 +1,45 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as devicesync$0 from "./internal/devicesync/models.js";
+
+export function ExportSyncBundle(path: string): $CancellablePromise<void> {
+    return $Call.ByID(1272508505, path);
+}
+
+export function GetSyncStatus(): $CancellablePromise<devicesync$0.Status> {
+    return $Call.ByID(1846937397).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+/**
+ * ImportSyncBundle snapshots the merged tables first so the merge can be
+ * rolled back from the snapshot service.
+ */
+export function ImportSyncBundle(path: string): $CancellablePromise<devicesync$0.ImportResult> {
+    return $Call.ByID(2523204558, path).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+export function SetSyncFolder(path: string): $CancellablePromise<void> {
+    return $Call.ByID(1805765571, path);
+}
+
+export function SyncFolderNow(): $CancellablePromise<devicesync$0.ImportResult[]> {
+    return $Call.ByID(391084069).then(($result: any) => {
+        return $$createType2($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = devicesync$0.Status.createFrom;
+const $$createType1 = devicesync$0.ImportResult.createFrom;
+const $$createType2 = $Create.Array($$createType1);
@@ -0,0 +1,71 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+import * as AccessibilityService from "./accessibilityservice.js";
+import * as AudiobookService from "./audiobookservice.js";
+import * as BackupService from "./backupservice.js";
+import * as BootstrapService from "./bootstrapservice.js";
+import * as CommandPaletteService from "./commandpaletteservice.js";
+import * as CoverFetchService from "./coverfetchservice.js";
+import * as DeviceSyncService from "./devicesyncservice.js";
+import * as JobsService from "./jobsservice.js";
+import * as KeybindingsService from "./keybindingsservice.js";
+import * as LibraryService from "./libraryservice.js";
+import * as LocaleService from "./localeservice.js";
+import * as LyricsService from "./lyricsservice.js";
+import * as MetadataService from "./metadataservice.js";
+import * as MiniPlayerService from "./miniplayerservice.js";
+import * as NotificationService from "./notificationservice.js";
+import * as PlayerService from "./playerservice.js";
+import * as PlaylistService from "./playlistservice.js";
+import * as QueueService from "./queueservice.js";
+import * as ScannerService from "./scannerservice.js";
+import * as SettingsService from "./settingsservice.js";
+import * as SnapshotService from "./snapshotservice.js";
+import * as StatsService from "./statsservice.js";
+import * as StatusService from "./statusservice.js";
+import * as TagEditorService from "./tageditorservice.js";
+import * as ThemeService from "./themeservice.js";
+import * as UndoService from "./undoservice.js";
+export {
+    AccessibilityService,
+    AudiobookService,
+    BackupService,
+    BootstrapService,
+    CommandPaletteService,
+    CoverFetchService,
+    DeviceSyncService,
+    JobsService,
+    KeybindingsService,
+    LibraryService,
+    LocaleService,
+    LyricsService,
+    MetadataService,
+    MiniPlayerService,
+    NotificationService,
+    PlayerService,
+    PlaylistService,
+    QueueService,
+    ScannerService,
+    SettingsService,
+    SnapshotService,
+    StatsService,
+    StatusService,
+    TagEditorService,
+    ThemeService,
+    UndoService
+};
+
+export {
+    AlbumPalette,
+    AlbumPaletteBatch,
+    AppStatus,
+    AppStatusIssue,
+    BackgroundJob,
+    CoverCacheStatus,
+    DatabaseStatus,
+    MiniPlayerState,
+    StartupMark,
+    StartupReport,
+    StartupSnapshot
+} from "./models.js";
@@ -0,0 +1,206 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * Config describes the backup destination and schedule. Secrets are kept in
+ * the keychain and never returned to the frontend; leaving them empty in
+ * SetConfig keeps the stored values.
+ */
+export class Config {
+    "kind": string;
+    "url": string;
+    "username"?: string;
+    "password"?: string;
+    "bucket"?: string;
+    "region"?: string;
+    "prefix"?: string;
+    "accessKey"?: string;
+    "secretKey"?: string;
+    "passphrase"?: string;
+    "intervalHours": number;
+
+    /** Creates a new Config instance. */
+    constructor($$source: Partial<Config> = {}) {
+        if (!("kind" in $$source)) {
+            this["kind"] = "";
+        }
+        if (!("url" in $$source)) {
+            this["url"] = "";
+        }
+        if (!("intervalHours" in $$source)) {
+            this["intervalHours"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Config instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Config {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Config($$parsedSource as Partial<Config>);
+    }
+}
+
+/**
+ * Manifest describes a backup so a restore can be previewed before the
+ * local library is touched.
+ */
+export class Manifest {
+    "formatVersion": number;
+    "createdAt": string;
+    "hostname": string;
+    "lastMigration": string;
+    "trackCount": number;
+    "playlistCount": number;
+
+    /** Creates a new Manifest instance. */
+    constructor($$source: Partial<Manifest> = {}) {
+        if (!("formatVersion" in $$source)) {
+            this["formatVersion"] = 0;
+        }
+        if (!("createdAt" in $$source)) {
+            this["createdAt"] = "";
+        }
+        if (!("hostname" in $$source)) {
+            this["hostname"] = "";
+        }
+        if (!("lastMigration" in $$source)) {
+            this["lastMigration"] = "";
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+        if (!("playlistCount" in $$source)) {
+            this["playlistCount"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Manifest instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Manifest {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Manifest($$parsedSource as Partial<Manifest>);
+    }
+}
+
+export class RestorePreview {
+    "name": string;
+    "manifest": Manifest;
+    "localTrackCount": number;
+    "localPlaylistCount": number;
+    "conflict": boolean;
+    "restartRequired": boolean;
+
+    /** Creates a new RestorePreview instance. */
+    constructor($$source: Partial<RestorePreview> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("manifest" in $$source)) {
+            this["manifest"] = (new Manifest());
+        }
+        if (!("localTrackCount" in $$source)) {
+            this["localTrackCount"] = 0;
+        }
+        if (!("localPlaylistCount" in $$source)) {
+            this["localPlaylistCount"] = 0;
+        }
+        if (!("conflict" in $$source)) {
+            this["conflict"] = false;
+        }
+        if (!("restartRequired" in $$source)) {
+            this["restartRequired"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new RestorePreview instance from a string or object.
+     */
+    static createFrom($$source: any = {}): RestorePreview {
+        const $$createField1_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("manifest" in $$parsedSource) {
+            $$parsedSource["manifest"] = $$createField1_0($$parsedSource["manifest"]);
+        }
+        return new RestorePreview($$parsedSource as Partial<RestorePreview>);
+    }
+}
+
+export class Result {
+    "name": string;
+    "createdAt": string;
+    "sizeBytes": number;
+
+    /** Creates a new Result instance. */
+    constructor($$source: Partial<Result> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("createdAt" in $$source)) {
+            this["createdAt"] = "";
+        }
+        if (!("sizeBytes" in $$source)) {
+            this["sizeBytes"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Result instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Result {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Result($$parsedSource as Partial<Result>);
+    }
+}
+
+export class Status {
+    "config": Config;
+    "hasPassphrase": boolean;
+    "running": boolean;
+    "lastRunAt"?: string | null;
+    "lastError"?: string | null;
+
+    /** Creates a new Status instance. */
+    constructor($$source: Partial<Status> = {}) {
+        if (!("config" in $$source)) {
+            this["config"] = (new Config());
+        }
+        if (!("hasPassphrase" in $$source)) {
+            this["hasPassphrase"] = false;
+        }
+        if (!("running" in $$source)) {
+            this["running"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Status instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Status {
+        const $$createField0_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("config" in $$parsedSource) {
+            $$parsedSource["config"] = $$createField0_0($$parsedSource["config"]);
+        }
+        return new Status($$parsedSource as Partial<Status>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = Manifest.createFrom;
+const $$createType1 = Config.createFrom;
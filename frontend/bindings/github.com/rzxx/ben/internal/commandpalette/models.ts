@@ -0,0 +1,106 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * Action is a palette command that is not bound to a library item.
+ */
+export class Action {
+    "id": string;
+    "title": string;
+    "category": string;
+
+    /** Creates a new Action instance. */
+    constructor($$source: Partial<Action> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = "";
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("category" in $$source)) {
+            this["category"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Action instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Action {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Action($$parsedSource as Partial<Action>);
+    }
+}
+
+/**
+ * Invocation tells the frontend what to do with a result; only the fields of
+ * the result's kind are set.
+ */
+export class Invocation {
+    "commandId"?: string;
+    "artistName"?: string;
+    "albumTitle"?: string;
+    "albumArtist"?: string;
+    "playlistId"?: number;
+    "trackId"?: number;
+
+    /** Creates a new Invocation instance. */
+    constructor($$source: Partial<Invocation> = {}) {
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Invocation instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Invocation {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Invocation($$parsedSource as Partial<Invocation>);
+    }
+}
+
+export class Result {
+    "kind": string;
+    "title": string;
+    "subtitle"?: string;
+    "score": number;
+    "invocation": Invocation;
+
+    /** Creates a new Result instance. */
+    constructor($$source: Partial<Result> = {}) {
+        if (!("kind" in $$source)) {
+            this["kind"] = "";
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("score" in $$source)) {
+            this["score"] = 0;
+        }
+        if (!("invocation" in $$source)) {
+            this["invocation"] = (new Invocation());
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Result instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Result {
+        const $$createField4_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("invocation" in $$parsedSource) {
+            $$parsedSource["invocation"] = $$createField4_0($$parsedSource["invocation"]);
+        }
+        return new Result($$parsedSource as Partial<Result>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = Invocation.createFrom;
@@ -2,6 +2,6 @@
 // This file is automatically generated. DO NOT EDIT
 
 export {
-    Progress,
+    ImportResult,
     Status
 } from "./models.js";
@@ -0,0 +1,79 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * ImportResult summarizes how one bundle was merged.
+ */
+export class ImportResult {
+    "deviceId": string;
+    "deviceName": string;
+    "playStatsApplied": number;
+    "ratingsApplied": number;
+    "olderSkipped": number;
+    "unmatched": number;
+
+    /** Creates a new ImportResult instance. */
+    constructor($$source: Partial<ImportResult> = {}) {
+        if (!("deviceId" in $$source)) {
+            this["deviceId"] = "";
+        }
+        if (!("deviceName" in $$source)) {
+            this["deviceName"] = "";
+        }
+        if (!("playStatsApplied" in $$source)) {
+            this["playStatsApplied"] = 0;
+        }
+        if (!("ratingsApplied" in $$source)) {
+            this["ratingsApplied"] = 0;
+        }
+        if (!("olderSkipped" in $$source)) {
+            this["olderSkipped"] = 0;
+        }
+        if (!("unmatched" in $$source)) {
+            this["unmatched"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ImportResult instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ImportResult {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ImportResult($$parsedSource as Partial<ImportResult>);
+    }
+}
+
+export class Status {
+    "deviceId": string;
+    "deviceName": string;
+    "folder": string;
+
+    /** Creates a new Status instance. */
+    constructor($$source: Partial<Status> = {}) {
+        if (!("deviceId" in $$source)) {
+            this["deviceId"] = "";
+        }
+        if (!("deviceName" in $$source)) {
+            this["deviceName"] = "";
+        }
+        if (!("folder" in $$source)) {
+            this["folder"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Status instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Status {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Status($$parsedSource as Partial<Status>);
+    }
+}
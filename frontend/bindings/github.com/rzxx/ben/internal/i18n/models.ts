@@ -0,0 +1,81 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * LocaleOption is a locale the backend ships messages for, named in its own
+ * language so the picker stays readable whatever is selected.
+ */
+export class LocaleOption {
+    "code": string;
+    "name": string;
+
+    /** Creates a new LocaleOption instance. */
+    constructor($$source: Partial<LocaleOption> = {}) {
+        if (!("code" in $$source)) {
+            this["code"] = "";
+        }
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new LocaleOption instance from a string or object.
+     */
+    static createFrom($$source: any = {}): LocaleOption {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new LocaleOption($$parsedSource as Partial<LocaleOption>);
+    }
+}
+
+/**
+ * LocaleState is what the frontend needs to render backend values in the
+ * selected locale.
+ */
+export class LocaleState {
+    "locale": string;
+    "supported": LocaleOption[];
+    "messages": { [_: string]: string };
+
+    /** Creates a new LocaleState instance. */
+    constructor($$source: Partial<LocaleState> = {}) {
+        if (!("locale" in $$source)) {
+            this["locale"] = "";
+        }
+        if (!("supported" in $$source)) {
+            this["supported"] = [];
+        }
+        if (!("messages" in $$source)) {
+            this["messages"] = {};
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new LocaleState instance from a string or object.
+     */
+    static createFrom($$source: any = {}): LocaleState {
+        const $$createField1_0 = $$createType1;
+        const $$createField2_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("supported" in $$parsedSource) {
+            $$parsedSource["supported"] = $$createField1_0($$parsedSource["supported"]);
+        }
+        if ("messages" in $$parsedSource) {
+            $$parsedSource["messages"] = $$createField2_0($$parsedSource["messages"]);
+        }
+        return new LocaleState($$parsedSource as Partial<LocaleState>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = LocaleOption.createFrom;
+const $$createType1 = $Create.Array($$createType0);
+const $$createType2 = $Create.Map($Create.Any, $Create.Any);
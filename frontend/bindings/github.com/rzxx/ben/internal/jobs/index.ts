@@ -2,6 +2,5 @@
 // This file is automatically generated. DO NOT EDIT
 
 export {
-    ShuffleDebugState,
-    State
+    Job
 } from "./models.js";
@@ -0,0 +1,62 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * Job is one unit of background work as the UI sees it. Progress runs from
+ * 0 to 100.
+ */
+export class Job {
+    "id": string;
+    "kind": string;
+    "label": string;
+    "state": string;
+    "progress": number;
+    "message"?: string;
+    "error"?: string;
+    "cancelable": boolean;
+    "cancelRequested": boolean;
+    "startedAt": string;
+    "finishedAt"?: string;
+
+    /** Creates a new Job instance. */
+    constructor($$source: Partial<Job> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = "";
+        }
+        if (!("kind" in $$source)) {
+            this["kind"] = "";
+        }
+        if (!("label" in $$source)) {
+            this["label"] = "";
+        }
+        if (!("state" in $$source)) {
+            this["state"] = "";
+        }
+        if (!("progress" in $$source)) {
+            this["progress"] = 0;
+        }
+        if (!("cancelable" in $$source)) {
+            this["cancelable"] = false;
+        }
+        if (!("cancelRequested" in $$source)) {
+            this["cancelRequested"] = false;
+        }
+        if (!("startedAt" in $$source)) {
+            this["startedAt"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Job instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Job {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Job($$parsedSource as Partial<Job>);
+    }
+}
@@ -0,0 +1,61 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * Binding is a command together with the keys currently bound to it.
+ */
+export class Binding {
+    "id": string;
+    "title": string;
+    "category": string;
+    "defaultKeys": string[];
+    "keys": string[];
+    "customized": boolean;
+
+    /** Creates a new Binding instance. */
+    constructor($$source: Partial<Binding> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = "";
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("category" in $$source)) {
+            this["category"] = "";
+        }
+        if (!("defaultKeys" in $$source)) {
+            this["defaultKeys"] = [];
+        }
+        if (!("keys" in $$source)) {
+            this["keys"] = [];
+        }
+        if (!("customized" in $$source)) {
+            this["customized"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Binding instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Binding {
+        const $$createField3_0 = $$createType0;
+        const $$createField4_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("defaultKeys" in $$parsedSource) {
+            $$parsedSource["defaultKeys"] = $$createField3_0($$parsedSource["defaultKeys"]);
+        }
+        if ("keys" in $$parsedSource) {
+            $$parsedSource["keys"] = $$createField4_0($$parsedSource["keys"]);
+        }
+        return new Binding($$parsedSource as Partial<Binding>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = $Create.Array($Create.Any);
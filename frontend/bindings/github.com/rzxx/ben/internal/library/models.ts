@@ -0,0 +1,2423 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as palette$0 from "../palette/models.js";
+
+/**
+ * AlbumCoverPreview carries what a grid cell needs before the real thumbnail
+ * loads. Fields stay empty for albums without a cover or whose placeholder
+ * has not been computed yet.
+ */
+export class AlbumCoverPreview {
+    "title": string;
+    "albumArtist": string;
+    "thumbnailUrl"?: string | null;
+    "dominantColor"?: string | null;
+    "blurHash"?: string | null;
+
+    /** Creates a new AlbumCoverPreview instance. */
+    constructor($$source: Partial<AlbumCoverPreview> = {}) {
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AlbumCoverPreview instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AlbumCoverPreview {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new AlbumCoverPreview($$parsedSource as Partial<AlbumCoverPreview>);
+    }
+}
+
+export class AlbumDetail {
+    "title": string;
+    "albumArtist": string;
+    "year"?: number | null;
+    "trackCount": number;
+    "coverPath"?: string | null;
+    "rating"?: number | null;
+    "favorite"?: boolean;
+    "progress": AlbumProgress;
+    "tracks": TrackSummary[];
+    "page": PageInfo;
+
+    /** Creates a new AlbumDetail instance. */
+    constructor($$source: Partial<AlbumDetail> = {}) {
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+        if (!("progress" in $$source)) {
+            this["progress"] = (new AlbumProgress());
+        }
+        if (!("tracks" in $$source)) {
+            this["tracks"] = [];
+        }
+        if (!("page" in $$source)) {
+            this["page"] = (new PageInfo());
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AlbumDetail instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AlbumDetail {
+        const $$createField7_0 = $$createType0;
+        const $$createField8_0 = $$createType2;
+        const $$createField9_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("progress" in $$parsedSource) {
+            $$parsedSource["progress"] = $$createField7_0($$parsedSource["progress"]);
+        }
+        if ("tracks" in $$parsedSource) {
+            $$parsedSource["tracks"] = $$createField8_0($$parsedSource["tracks"]);
+        }
+        if ("page" in $$parsedSource) {
+            $$parsedSource["page"] = $$createField9_0($$parsedSource["page"]);
+        }
+        return new AlbumDetail($$parsedSource as Partial<AlbumDetail>);
+    }
+}
+
+/**
+ * AlbumDiscGap lists the absent track numbers of one disc.
+ */
+export class AlbumDiscGap {
+    "discNo": number;
+    "expectedTracks": number;
+    "missingTrackNos": number[];
+
+    /** Creates a new AlbumDiscGap instance. */
+    constructor($$source: Partial<AlbumDiscGap> = {}) {
+        if (!("discNo" in $$source)) {
+            this["discNo"] = 0;
+        }
+        if (!("expectedTracks" in $$source)) {
+            this["expectedTracks"] = 0;
+        }
+        if (!("missingTrackNos" in $$source)) {
+            this["missingTrackNos"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AlbumDiscGap instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AlbumDiscGap {
+        const $$createField2_0 = $$createType4;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("missingTrackNos" in $$parsedSource) {
+            $$parsedSource["missingTrackNos"] = $$createField2_0($$parsedSource["missingTrackNos"]);
+        }
+        return new AlbumDiscGap($$parsedSource as Partial<AlbumDiscGap>);
+    }
+}
+
+export class AlbumKey {
+    "title": string;
+    "albumArtist": string;
+
+    /** Creates a new AlbumKey instance. */
+    constructor($$source: Partial<AlbumKey> = {}) {
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AlbumKey instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AlbumKey {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new AlbumKey($$parsedSource as Partial<AlbumKey>);
+    }
+}
+
+/**
+ * AlbumMix describes whether an album plays as one continuous mix. Continuous
+ * mixes are played gapless with crossfade disabled.
+ */
+export class AlbumMix {
+    "title": string;
+    "albumArtist": string;
+    "detected": boolean;
+    "override"?: boolean | null;
+    "continuousMix": boolean;
+
+    /** Creates a new AlbumMix instance. */
+    constructor($$source: Partial<AlbumMix> = {}) {
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+        if (!("detected" in $$source)) {
+            this["detected"] = false;
+        }
+        if (!("continuousMix" in $$source)) {
+            this["continuousMix"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AlbumMix instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AlbumMix {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new AlbumMix($$parsedSource as Partial<AlbumMix>);
+    }
+}
+
+/**
+ * AlbumProgress tracks how much of an album has been heard. A track counts
+ * once it has been played to the end at least once; PercentListened weighs
+ * tracks by duration so a long closing track counts for more than an intro.
+ */
+export class AlbumProgress {
+    "completedTracks": number;
+    "totalTracks": number;
+    "percentListened": number;
+
+    /** Creates a new AlbumProgress instance. */
+    constructor($$source: Partial<AlbumProgress> = {}) {
+        if (!("completedTracks" in $$source)) {
+            this["completedTracks"] = 0;
+        }
+        if (!("totalTracks" in $$source)) {
+            this["totalTracks"] = 0;
+        }
+        if (!("percentListened" in $$source)) {
+            this["percentListened"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AlbumProgress instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AlbumProgress {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new AlbumProgress($$parsedSource as Partial<AlbumProgress>);
+    }
+}
+
+export class AlbumRating {
+    "title": string;
+    "albumArtist": string;
+    "rating"?: number | null;
+    "favorite": boolean;
+    "updatedAt"?: string;
+
+    /** Creates a new AlbumRating instance. */
+    constructor($$source: Partial<AlbumRating> = {}) {
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+        if (!("favorite" in $$source)) {
+            this["favorite"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AlbumRating instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AlbumRating {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new AlbumRating($$parsedSource as Partial<AlbumRating>);
+    }
+}
+
+export class AlbumSummary {
+    "title": string;
+    "albumArtist": string;
+    "year"?: number | null;
+    "trackCount": number;
+    "coverPath"?: string | null;
+    "playCount"?: number | null;
+    "lastPlayedAt"?: string | null;
+    "rating"?: number | null;
+    "favorite"?: boolean;
+    "addedAt"?: string | null;
+
+    /** Creates a new AlbumSummary instance. */
+    constructor($$source: Partial<AlbumSummary> = {}) {
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AlbumSummary instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AlbumSummary {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new AlbumSummary($$parsedSource as Partial<AlbumSummary>);
+    }
+}
+
+export class AlbumVolumeOffset {
+    "title": string;
+    "albumArtist": string;
+    "offsetDb"?: number | null;
+
+    /** Creates a new AlbumVolumeOffset instance. */
+    constructor($$source: Partial<AlbumVolumeOffset> = {}) {
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AlbumVolumeOffset instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AlbumVolumeOffset {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new AlbumVolumeOffset($$parsedSource as Partial<AlbumVolumeOffset>);
+    }
+}
+
+export class AlbumsPage {
+    "items": AlbumSummary[];
+    "page": PageInfo;
+
+    /** Creates a new AlbumsPage instance. */
+    constructor($$source: Partial<AlbumsPage> = {}) {
+        if (!("items" in $$source)) {
+            this["items"] = [];
+        }
+        if (!("page" in $$source)) {
+            this["page"] = (new PageInfo());
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AlbumsPage instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AlbumsPage {
+        const $$createField0_0 = $$createType6;
+        const $$createField1_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("items" in $$parsedSource) {
+            $$parsedSource["items"] = $$createField0_0($$parsedSource["items"]);
+        }
+        if ("page" in $$parsedSource) {
+            $$parsedSource["page"] = $$createField1_0($$parsedSource["page"]);
+        }
+        return new AlbumsPage($$parsedSource as Partial<AlbumsPage>);
+    }
+}
+
+/**
+ * AmbientCover is one slide of the ambient display. Palette is filled in by
+ * the theme service.
+ */
+export class AmbientCover {
+    "coverId": number;
+    "title": string;
+    "albumArtist": string;
+    "imageUrl": string;
+    "width"?: number;
+    "height"?: number;
+    "dominantColor"?: string | null;
+    "palette"?: palette$0.ThemePalette | null;
+
+    /** Creates a new AmbientCover instance. */
+    constructor($$source: Partial<AmbientCover> = {}) {
+        if (!("coverId" in $$source)) {
+            this["coverId"] = 0;
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+        if (!("imageUrl" in $$source)) {
+            this["imageUrl"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AmbientCover instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AmbientCover {
+        const $$createField7_0 = $$createType8;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("palette" in $$parsedSource) {
+            $$parsedSource["palette"] = $$createField7_0($$parsedSource["palette"]);
+        }
+        return new AmbientCover($$parsedSource as Partial<AmbientCover>);
+    }
+}
+
+/**
+ * AmbientCoverPage is a page of the shuffled cover feed. The order is fixed
+ * by Seed, so pages fetched with the same seed never repeat a cover.
+ */
+export class AmbientCoverPage {
+    "seed": number;
+    "offset": number;
+    "nextOffset": number;
+    "total": number;
+    "covers": AmbientCover[];
+
+    /** Creates a new AmbientCoverPage instance. */
+    constructor($$source: Partial<AmbientCoverPage> = {}) {
+        if (!("seed" in $$source)) {
+            this["seed"] = 0;
+        }
+        if (!("offset" in $$source)) {
+            this["offset"] = 0;
+        }
+        if (!("nextOffset" in $$source)) {
+            this["nextOffset"] = 0;
+        }
+        if (!("total" in $$source)) {
+            this["total"] = 0;
+        }
+        if (!("covers" in $$source)) {
+            this["covers"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AmbientCoverPage instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AmbientCoverPage {
+        const $$createField4_0 = $$createType10;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("covers" in $$parsedSource) {
+            $$parsedSource["covers"] = $$createField4_0($$parsedSource["covers"]);
+        }
+        return new AmbientCoverPage($$parsedSource as Partial<AmbientCoverPage>);
+    }
+}
+
+export class ArtistDetail {
+    "name": string;
+    "trackCount": number;
+    "albumCount": number;
+    "albums": AlbumSummary[];
+    "page": PageInfo;
+
+    /** Creates a new ArtistDetail instance. */
+    constructor($$source: Partial<ArtistDetail> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+        if (!("albumCount" in $$source)) {
+            this["albumCount"] = 0;
+        }
+        if (!("albums" in $$source)) {
+            this["albums"] = [];
+        }
+        if (!("page" in $$source)) {
+            this["page"] = (new PageInfo());
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ArtistDetail instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ArtistDetail {
+        const $$createField3_0 = $$createType6;
+        const $$createField4_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("albums" in $$parsedSource) {
+            $$parsedSource["albums"] = $$createField3_0($$parsedSource["albums"]);
+        }
+        if ("page" in $$parsedSource) {
+            $$parsedSource["page"] = $$createField4_0($$parsedSource["page"]);
+        }
+        return new ArtistDetail($$parsedSource as Partial<ArtistDetail>);
+    }
+}
+
+export class ArtistSummary {
+    "name": string;
+    "trackCount": number;
+    "albumCount": number;
+
+    /** Creates a new ArtistSummary instance. */
+    constructor($$source: Partial<ArtistSummary> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+        if (!("albumCount" in $$source)) {
+            this["albumCount"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ArtistSummary instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ArtistSummary {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ArtistSummary($$parsedSource as Partial<ArtistSummary>);
+    }
+}
+
+export class ArtistTimeline {
+    "name": string;
+    "years": TimelineYear[];
+    "totalPlays": number;
+
+    /** Creates a new ArtistTimeline instance. */
+    constructor($$source: Partial<ArtistTimeline> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("years" in $$source)) {
+            this["years"] = [];
+        }
+        if (!("totalPlays" in $$source)) {
+            this["totalPlays"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ArtistTimeline instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ArtistTimeline {
+        const $$createField1_0 = $$createType12;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("years" in $$parsedSource) {
+            $$parsedSource["years"] = $$createField1_0($$parsedSource["years"]);
+        }
+        return new ArtistTimeline($$parsedSource as Partial<ArtistTimeline>);
+    }
+}
+
+export class ArtistTopTrack {
+    "trackId": number;
+    "title": string;
+    "artist": string;
+    "album": string;
+    "albumArtist": string;
+    "discNo"?: number | null;
+    "trackNo"?: number | null;
+    "durationMs"?: number | null;
+    "path": string;
+    "coverPath"?: string | null;
+    "playedMs": number;
+    "completeCount": number;
+    "skipCount": number;
+    "partialCount": number;
+
+    /** Creates a new ArtistTopTrack instance. */
+    constructor($$source: Partial<ArtistTopTrack> = {}) {
+        if (!("trackId" in $$source)) {
+            this["trackId"] = 0;
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("artist" in $$source)) {
+            this["artist"] = "";
+        }
+        if (!("album" in $$source)) {
+            this["album"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("playedMs" in $$source)) {
+            this["playedMs"] = 0;
+        }
+        if (!("completeCount" in $$source)) {
+            this["completeCount"] = 0;
+        }
+        if (!("skipCount" in $$source)) {
+            this["skipCount"] = 0;
+        }
+        if (!("partialCount" in $$source)) {
+            this["partialCount"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ArtistTopTrack instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ArtistTopTrack {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ArtistTopTrack($$parsedSource as Partial<ArtistTopTrack>);
+    }
+}
+
+export class ArtistsPage {
+    "items": ArtistSummary[];
+    "page": PageInfo;
+
+    /** Creates a new ArtistsPage instance. */
+    constructor($$source: Partial<ArtistsPage> = {}) {
+        if (!("items" in $$source)) {
+            this["items"] = [];
+        }
+        if (!("page" in $$source)) {
+            this["page"] = (new PageInfo());
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ArtistsPage instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ArtistsPage {
+        const $$createField0_0 = $$createType14;
+        const $$createField1_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("items" in $$parsedSource) {
+            $$parsedSource["items"] = $$createField0_0($$parsedSource["items"]);
+        }
+        if ("page" in $$parsedSource) {
+            $$parsedSource["page"] = $$createField1_0($$parsedSource["page"]);
+        }
+        return new ArtistsPage($$parsedSource as Partial<ArtistsPage>);
+    }
+}
+
+/**
+ * Audiobook groups the chapters of a book by album title and album artist,
+ * the same way albums are grouped for music.
+ */
+export class Audiobook {
+    "title": string;
+    "author": string;
+    "chapterCount": number;
+    "completedChapters": number;
+    "durationMs": number;
+    "listenedMs": number;
+    "percentListened": number;
+    "lastListenedAt"?: string | null;
+    "coverPath"?: string | null;
+
+    /** Creates a new Audiobook instance. */
+    constructor($$source: Partial<Audiobook> = {}) {
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("author" in $$source)) {
+            this["author"] = "";
+        }
+        if (!("chapterCount" in $$source)) {
+            this["chapterCount"] = 0;
+        }
+        if (!("completedChapters" in $$source)) {
+            this["completedChapters"] = 0;
+        }
+        if (!("durationMs" in $$source)) {
+            this["durationMs"] = 0;
+        }
+        if (!("listenedMs" in $$source)) {
+            this["listenedMs"] = 0;
+        }
+        if (!("percentListened" in $$source)) {
+            this["percentListened"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Audiobook instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Audiobook {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Audiobook($$parsedSource as Partial<Audiobook>);
+    }
+}
+
+/**
+ * AudiobookChapter is one track of a book with its own resume position.
+ */
+export class AudiobookChapter {
+    "track": TrackSummary;
+    "resumePositionMs": number;
+    "completed": boolean;
+
+    /** Creates a new AudiobookChapter instance. */
+    constructor($$source: Partial<AudiobookChapter> = {}) {
+        if (!("track" in $$source)) {
+            this["track"] = (new TrackSummary());
+        }
+        if (!("resumePositionMs" in $$source)) {
+            this["resumePositionMs"] = 0;
+        }
+        if (!("completed" in $$source)) {
+            this["completed"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AudiobookChapter instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AudiobookChapter {
+        const $$createField0_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("track" in $$parsedSource) {
+            $$parsedSource["track"] = $$createField0_0($$parsedSource["track"]);
+        }
+        return new AudiobookChapter($$parsedSource as Partial<AudiobookChapter>);
+    }
+}
+
+/**
+ * AudiobookDetail lists the chapters of a book. ResumeTrackID points at the
+ * chapter listened to most recently, or the first unfinished one.
+ */
+export class AudiobookDetail {
+    "title": string;
+    "author": string;
+    "chapterCount": number;
+    "completedChapters": number;
+    "durationMs": number;
+    "listenedMs": number;
+    "percentListened": number;
+    "lastListenedAt"?: string | null;
+    "coverPath"?: string | null;
+    "chapters": AudiobookChapter[];
+    "resumeTrackId"?: number | null;
+
+    /** Creates a new AudiobookDetail instance. */
+    constructor($$source: Partial<AudiobookDetail> = {}) {
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("author" in $$source)) {
+            this["author"] = "";
+        }
+        if (!("chapterCount" in $$source)) {
+            this["chapterCount"] = 0;
+        }
+        if (!("completedChapters" in $$source)) {
+            this["completedChapters"] = 0;
+        }
+        if (!("durationMs" in $$source)) {
+            this["durationMs"] = 0;
+        }
+        if (!("listenedMs" in $$source)) {
+            this["listenedMs"] = 0;
+        }
+        if (!("percentListened" in $$source)) {
+            this["percentListened"] = 0;
+        }
+        if (!("chapters" in $$source)) {
+            this["chapters"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AudiobookDetail instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AudiobookDetail {
+        const $$createField9_0 = $$createType16;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("chapters" in $$parsedSource) {
+            $$parsedSource["chapters"] = $$createField9_0($$parsedSource["chapters"]);
+        }
+        return new AudiobookDetail($$parsedSource as Partial<AudiobookDetail>);
+    }
+}
+
+/**
+ * AudiobookFolder is a watched root or folder whose tracks are audiobooks.
+ */
+export class AudiobookFolder {
+    "path": string;
+    "trackCount": number;
+    "createdAt": string;
+
+    /** Creates a new AudiobookFolder instance. */
+    constructor($$source: Partial<AudiobookFolder> = {}) {
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+        if (!("createdAt" in $$source)) {
+            this["createdAt"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AudiobookFolder instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AudiobookFolder {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new AudiobookFolder($$parsedSource as Partial<AudiobookFolder>);
+    }
+}
+
+export class AudiobooksPage {
+    "items": Audiobook[];
+    "page": PageInfo;
+
+    /** Creates a new AudiobooksPage instance. */
+    constructor($$source: Partial<AudiobooksPage> = {}) {
+        if (!("items" in $$source)) {
+            this["items"] = [];
+        }
+        if (!("page" in $$source)) {
+            this["page"] = (new PageInfo());
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AudiobooksPage instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AudiobooksPage {
+        const $$createField0_0 = $$createType18;
+        const $$createField1_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("items" in $$parsedSource) {
+            $$parsedSource["items"] = $$createField0_0($$parsedSource["items"]);
+        }
+        if ("page" in $$parsedSource) {
+            $$parsedSource["page"] = $$createField1_0($$parsedSource["page"]);
+        }
+        return new AudiobooksPage($$parsedSource as Partial<AudiobooksPage>);
+    }
+}
+
+export class BoxSetDetail {
+    "name": string;
+    "albumArtist": string;
+    "source": string;
+    "albumCount": number;
+    "trackCount": number;
+    "coverPath"?: string | null;
+    "albums": AlbumSummary[];
+
+    /** Creates a new BoxSetDetail instance. */
+    constructor($$source: Partial<BoxSetDetail> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+        if (!("source" in $$source)) {
+            this["source"] = "";
+        }
+        if (!("albumCount" in $$source)) {
+            this["albumCount"] = 0;
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+        if (!("albums" in $$source)) {
+            this["albums"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new BoxSetDetail instance from a string or object.
+     */
+    static createFrom($$source: any = {}): BoxSetDetail {
+        const $$createField6_0 = $$createType6;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("albums" in $$parsedSource) {
+            $$parsedSource["albums"] = $$createField6_0($$parsedSource["albums"]);
+        }
+        return new BoxSetDetail($$parsedSource as Partial<BoxSetDetail>);
+    }
+}
+
+/**
+ * BoxSetSummary is a parent release grouping several albums, either through
+ * a shared BOXSET or GROUPING tag or through a common parent folder.
+ */
+export class BoxSetSummary {
+    "name": string;
+    "albumArtist": string;
+    "source": string;
+    "albumCount": number;
+    "trackCount": number;
+    "coverPath"?: string | null;
+
+    /** Creates a new BoxSetSummary instance. */
+    constructor($$source: Partial<BoxSetSummary> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+        if (!("source" in $$source)) {
+            this["source"] = "";
+        }
+        if (!("albumCount" in $$source)) {
+            this["albumCount"] = 0;
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new BoxSetSummary instance from a string or object.
+     */
+    static createFrom($$source: any = {}): BoxSetSummary {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new BoxSetSummary($$parsedSource as Partial<BoxSetSummary>);
+    }
+}
+
+/**
+ * ComposerDetail pages through the works of a composer in catalog order.
+ */
+export class ComposerDetail {
+    "name": string;
+    "workCount": number;
+    "trackCount": number;
+    "albumCount": number;
+    "works": WorkSummary[];
+    "page": PageInfo;
+
+    /** Creates a new ComposerDetail instance. */
+    constructor($$source: Partial<ComposerDetail> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("workCount" in $$source)) {
+            this["workCount"] = 0;
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+        if (!("albumCount" in $$source)) {
+            this["albumCount"] = 0;
+        }
+        if (!("works" in $$source)) {
+            this["works"] = [];
+        }
+        if (!("page" in $$source)) {
+            this["page"] = (new PageInfo());
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ComposerDetail instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ComposerDetail {
+        const $$createField4_0 = $$createType20;
+        const $$createField5_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("works" in $$parsedSource) {
+            $$parsedSource["works"] = $$createField4_0($$parsedSource["works"]);
+        }
+        if ("page" in $$parsedSource) {
+            $$parsedSource["page"] = $$createField5_0($$parsedSource["page"]);
+        }
+        return new ComposerDetail($$parsedSource as Partial<ComposerDetail>);
+    }
+}
+
+export class ComposerSummary {
+    "name": string;
+    "workCount": number;
+    "trackCount": number;
+    "albumCount": number;
+
+    /** Creates a new ComposerSummary instance. */
+    constructor($$source: Partial<ComposerSummary> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("workCount" in $$source)) {
+            this["workCount"] = 0;
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+        if (!("albumCount" in $$source)) {
+            this["albumCount"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ComposerSummary instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ComposerSummary {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ComposerSummary($$parsedSource as Partial<ComposerSummary>);
+    }
+}
+
+export class ComposersPage {
+    "items": ComposerSummary[];
+    "page": PageInfo;
+
+    /** Creates a new ComposersPage instance. */
+    constructor($$source: Partial<ComposersPage> = {}) {
+        if (!("items" in $$source)) {
+            this["items"] = [];
+        }
+        if (!("page" in $$source)) {
+            this["page"] = (new PageInfo());
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ComposersPage instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ComposersPage {
+        const $$createField0_0 = $$createType22;
+        const $$createField1_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("items" in $$parsedSource) {
+            $$parsedSource["items"] = $$createField0_0($$parsedSource["items"]);
+        }
+        if ("page" in $$parsedSource) {
+            $$parsedSource["page"] = $$createField1_0($$parsedSource["page"]);
+        }
+        return new ComposersPage($$parsedSource as Partial<ComposersPage>);
+    }
+}
+
+/**
+ * CoverReport helps curate artwork. SharedCovers lists covers used by more
+ * than one album, which often means wrong or placeholder art; AlbumsWithout
+ * lists albums with no cover. The counts cover the whole library, while the
+ * lists are capped by the report limit.
+ */
+export class CoverReport {
+    "sharedCovers": SharedCover[];
+    "sharedCoverCount": number;
+    "sharedAlbumCount": number;
+    "albumsWithout": AlbumKey[];
+    "albumsWithoutCount": number;
+
+    /** Creates a new CoverReport instance. */
+    constructor($$source: Partial<CoverReport> = {}) {
+        if (!("sharedCovers" in $$source)) {
+            this["sharedCovers"] = [];
+        }
+        if (!("sharedCoverCount" in $$source)) {
+            this["sharedCoverCount"] = 0;
+        }
+        if (!("sharedAlbumCount" in $$source)) {
+            this["sharedAlbumCount"] = 0;
+        }
+        if (!("albumsWithout" in $$source)) {
+            this["albumsWithout"] = [];
+        }
+        if (!("albumsWithoutCount" in $$source)) {
+            this["albumsWithoutCount"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new CoverReport instance from a string or object.
+     */
+    static createFrom($$source: any = {}): CoverReport {
+        const $$createField0_0 = $$createType24;
+        const $$createField3_0 = $$createType26;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("sharedCovers" in $$parsedSource) {
+            $$parsedSource["sharedCovers"] = $$createField0_0($$parsedSource["sharedCovers"]);
+        }
+        if ("albumsWithout" in $$parsedSource) {
+            $$parsedSource["albumsWithout"] = $$createField3_0($$parsedSource["albumsWithout"]);
+        }
+        return new CoverReport($$parsedSource as Partial<CoverReport>);
+    }
+}
+
+/**
+ * DuplicateAlbum is one side of a duplicate album candidate. Album ids
+ * change on every scan, so merging goes through TrackIDs.
+ */
+export class DuplicateAlbum {
+    "albumId": number;
+    "title": string;
+    "albumArtist": string;
+    "year"?: number | null;
+    "trackCount": number;
+    "coverPath"?: string | null;
+    "trackIds": number[];
+
+    /** Creates a new DuplicateAlbum instance. */
+    constructor($$source: Partial<DuplicateAlbum> = {}) {
+        if (!("albumId" in $$source)) {
+            this["albumId"] = 0;
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+        if (!("trackIds" in $$source)) {
+            this["trackIds"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DuplicateAlbum instance from a string or object.
+     */
+    static createFrom($$source: any = {}): DuplicateAlbum {
+        const $$createField6_0 = $$createType27;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("trackIds" in $$parsedSource) {
+            $$parsedSource["trackIds"] = $$createField6_0($$parsedSource["trackIds"]);
+        }
+        return new DuplicateAlbum($$parsedSource as Partial<DuplicateAlbum>);
+    }
+}
+
+/**
+ * DuplicateAlbumCandidate is a pair of albums that are likely the same
+ * album ripped twice. Confidence runs from 0 to 1; CoverDistance is the
+ * number of differing cover hash bits, when both albums have a hashed
+ * cover.
+ */
+export class DuplicateAlbumCandidate {
+    "confidence": number;
+    "matchedBy": string[];
+    "coverDistance"?: number | null;
+    "titleSimilarity": number;
+    "artistSimilarity": number;
+    "trackOverlap": number;
+    "albums": DuplicateAlbum[];
+
+    /** Creates a new DuplicateAlbumCandidate instance. */
+    constructor($$source: Partial<DuplicateAlbumCandidate> = {}) {
+        if (!("confidence" in $$source)) {
+            this["confidence"] = 0;
+        }
+        if (!("matchedBy" in $$source)) {
+            this["matchedBy"] = [];
+        }
+        if (!("titleSimilarity" in $$source)) {
+            this["titleSimilarity"] = 0;
+        }
+        if (!("artistSimilarity" in $$source)) {
+            this["artistSimilarity"] = 0;
+        }
+        if (!("trackOverlap" in $$source)) {
+            this["trackOverlap"] = 0;
+        }
+        if (!("albums" in $$source)) {
+            this["albums"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DuplicateAlbumCandidate instance from a string or object.
+     */
+    static createFrom($$source: any = {}): DuplicateAlbumCandidate {
+        const $$createField1_0 = $$createType28;
+        const $$createField6_0 = $$createType30;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("matchedBy" in $$parsedSource) {
+            $$parsedSource["matchedBy"] = $$createField1_0($$parsedSource["matchedBy"]);
+        }
+        if ("albums" in $$parsedSource) {
+            $$parsedSource["albums"] = $$createField6_0($$parsedSource["albums"]);
+        }
+        return new DuplicateAlbumCandidate($$parsedSource as Partial<DuplicateAlbumCandidate>);
+    }
+}
+
+/**
+ * DuplicateAlbumReport lists the candidates at or above MinConfidence,
+ * most confident first.
+ */
+export class DuplicateAlbumReport {
+    "minConfidence": number;
+    "candidates": DuplicateAlbumCandidate[];
+
+    /** Creates a new DuplicateAlbumReport instance. */
+    constructor($$source: Partial<DuplicateAlbumReport> = {}) {
+        if (!("minConfidence" in $$source)) {
+            this["minConfidence"] = 0;
+        }
+        if (!("candidates" in $$source)) {
+            this["candidates"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DuplicateAlbumReport instance from a string or object.
+     */
+    static createFrom($$source: any = {}): DuplicateAlbumReport {
+        const $$createField1_0 = $$createType32;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("candidates" in $$parsedSource) {
+            $$parsedSource["candidates"] = $$createField1_0($$parsedSource["candidates"]);
+        }
+        return new DuplicateAlbumReport($$parsedSource as Partial<DuplicateAlbumReport>);
+    }
+}
+
+/**
+ * DuplicateGroup is a set of tracks that are the same recording.
+ * PreferredTrackID is the best version, the one kept by KeepBestDuplicates.
+ */
+export class DuplicateGroup {
+    "matchedBy": string[];
+    "preferredTrackId": number;
+    "tracks": DuplicateTrack[];
+
+    /** Creates a new DuplicateGroup instance. */
+    constructor($$source: Partial<DuplicateGroup> = {}) {
+        if (!("matchedBy" in $$source)) {
+            this["matchedBy"] = [];
+        }
+        if (!("preferredTrackId" in $$source)) {
+            this["preferredTrackId"] = 0;
+        }
+        if (!("tracks" in $$source)) {
+            this["tracks"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DuplicateGroup instance from a string or object.
+     */
+    static createFrom($$source: any = {}): DuplicateGroup {
+        const $$createField0_0 = $$createType28;
+        const $$createField2_0 = $$createType34;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("matchedBy" in $$parsedSource) {
+            $$parsedSource["matchedBy"] = $$createField0_0($$parsedSource["matchedBy"]);
+        }
+        if ("tracks" in $$parsedSource) {
+            $$parsedSource["tracks"] = $$createField2_0($$parsedSource["tracks"]);
+        }
+        return new DuplicateGroup($$parsedSource as Partial<DuplicateGroup>);
+    }
+}
+
+/**
+ * DuplicateReport lists the duplicate groups of the library. Duplicates
+ * counts the tracks beyond the preferred one of each group.
+ */
+export class DuplicateReport {
+    "toleranceMs": number;
+    "duplicates": number;
+    "hidden": number;
+    "groups": DuplicateGroup[];
+
+    /** Creates a new DuplicateReport instance. */
+    constructor($$source: Partial<DuplicateReport> = {}) {
+        if (!("toleranceMs" in $$source)) {
+            this["toleranceMs"] = 0;
+        }
+        if (!("duplicates" in $$source)) {
+            this["duplicates"] = 0;
+        }
+        if (!("hidden" in $$source)) {
+            this["hidden"] = 0;
+        }
+        if (!("groups" in $$source)) {
+            this["groups"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DuplicateReport instance from a string or object.
+     */
+    static createFrom($$source: any = {}): DuplicateReport {
+        const $$createField3_0 = $$createType36;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("groups" in $$parsedSource) {
+            $$parsedSource["groups"] = $$createField3_0($$parsedSource["groups"]);
+        }
+        return new DuplicateReport($$parsedSource as Partial<DuplicateReport>);
+    }
+}
+
+export class DuplicateTrack {
+    "trackId": number;
+    "title": string;
+    "artist": string;
+    "album": string;
+    "path": string;
+    "durationMs": number;
+    "codec": string;
+    "bitrate": number;
+    "sampleRate": number;
+    "bitDepth": number;
+    "lossless": boolean;
+    "hidden": boolean;
+
+    /** Creates a new DuplicateTrack instance. */
+    constructor($$source: Partial<DuplicateTrack> = {}) {
+        if (!("trackId" in $$source)) {
+            this["trackId"] = 0;
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("artist" in $$source)) {
+            this["artist"] = "";
+        }
+        if (!("album" in $$source)) {
+            this["album"] = "";
+        }
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("durationMs" in $$source)) {
+            this["durationMs"] = 0;
+        }
+        if (!("codec" in $$source)) {
+            this["codec"] = "";
+        }
+        if (!("bitrate" in $$source)) {
+            this["bitrate"] = 0;
+        }
+        if (!("sampleRate" in $$source)) {
+            this["sampleRate"] = 0;
+        }
+        if (!("bitDepth" in $$source)) {
+            this["bitDepth"] = 0;
+        }
+        if (!("lossless" in $$source)) {
+            this["lossless"] = false;
+        }
+        if (!("hidden" in $$source)) {
+            this["hidden"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DuplicateTrack instance from a string or object.
+     */
+    static createFrom($$source: any = {}): DuplicateTrack {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new DuplicateTrack($$parsedSource as Partial<DuplicateTrack>);
+    }
+}
+
+/**
+ * GenreDetail lists the albums with a track of the genre and the genre's
+ * most played tracks.
+ */
+export class GenreDetail {
+    "name": string;
+    "trackCount": number;
+    "albumCount": number;
+    "albums": AlbumSummary[];
+    "topTracks": TrackSummary[];
+    "page": PageInfo;
+
+    /** Creates a new GenreDetail instance. */
+    constructor($$source: Partial<GenreDetail> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+        if (!("albumCount" in $$source)) {
+            this["albumCount"] = 0;
+        }
+        if (!("albums" in $$source)) {
+            this["albums"] = [];
+        }
+        if (!("topTracks" in $$source)) {
+            this["topTracks"] = [];
+        }
+        if (!("page" in $$source)) {
+            this["page"] = (new PageInfo());
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new GenreDetail instance from a string or object.
+     */
+    static createFrom($$source: any = {}): GenreDetail {
+        const $$createField3_0 = $$createType6;
+        const $$createField4_0 = $$createType2;
+        const $$createField5_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("albums" in $$parsedSource) {
+            $$parsedSource["albums"] = $$createField3_0($$parsedSource["albums"]);
+        }
+        if ("topTracks" in $$parsedSource) {
+            $$parsedSource["topTracks"] = $$createField4_0($$parsedSource["topTracks"]);
+        }
+        if ("page" in $$parsedSource) {
+            $$parsedSource["page"] = $$createField5_0($$parsedSource["page"]);
+        }
+        return new GenreDetail($$parsedSource as Partial<GenreDetail>);
+    }
+}
+
+export class GenreSummary {
+    "name": string;
+    "trackCount": number;
+    "albumCount": number;
+
+    /** Creates a new GenreSummary instance. */
+    constructor($$source: Partial<GenreSummary> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+        if (!("albumCount" in $$source)) {
+            this["albumCount"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new GenreSummary instance from a string or object.
+     */
+    static createFrom($$source: any = {}): GenreSummary {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new GenreSummary($$parsedSource as Partial<GenreSummary>);
+    }
+}
+
+/**
+ * IncompleteAlbum is an album with fewer tracks on disk than its release
+ * track list names.
+ */
+export class IncompleteAlbum {
+    "title": string;
+    "albumArtist": string;
+    "musicBrainzAlbumId"?: string | null;
+    "expectedTracks": number;
+    "presentTracks": number;
+    "discs": AlbumDiscGap[];
+
+    /** Creates a new IncompleteAlbum instance. */
+    constructor($$source: Partial<IncompleteAlbum> = {}) {
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+        if (!("expectedTracks" in $$source)) {
+            this["expectedTracks"] = 0;
+        }
+        if (!("presentTracks" in $$source)) {
+            this["presentTracks"] = 0;
+        }
+        if (!("discs" in $$source)) {
+            this["discs"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new IncompleteAlbum instance from a string or object.
+     */
+    static createFrom($$source: any = {}): IncompleteAlbum {
+        const $$createField5_0 = $$createType38;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("discs" in $$parsedSource) {
+            $$parsedSource["discs"] = $$createField5_0($$parsedSource["discs"]);
+        }
+        return new IncompleteAlbum($$parsedSource as Partial<IncompleteAlbum>);
+    }
+}
+
+/**
+ * IndexSection is a jump-to section of a sorted listing: the offset of its
+ * first item and how many items follow under the same label.
+ */
+export class IndexSection {
+    "label": string;
+    "script": string;
+    "offset": number;
+    "count": number;
+
+    /** Creates a new IndexSection instance. */
+    constructor($$source: Partial<IndexSection> = {}) {
+        if (!("label" in $$source)) {
+            this["label"] = "";
+        }
+        if (!("script" in $$source)) {
+            this["script"] = "";
+        }
+        if (!("offset" in $$source)) {
+            this["offset"] = 0;
+        }
+        if (!("count" in $$source)) {
+            this["count"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new IndexSection instance from a string or object.
+     */
+    static createFrom($$source: any = {}): IndexSection {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new IndexSection($$parsedSource as Partial<IndexSection>);
+    }
+}
+
+/**
+ * LanguageSummary counts the tracks detected as one script and language.
+ * Language is empty where only the script is known.
+ */
+export class LanguageSummary {
+    "script": string;
+    "language": string;
+    "trackCount": number;
+
+    /** Creates a new LanguageSummary instance. */
+    constructor($$source: Partial<LanguageSummary> = {}) {
+        if (!("script" in $$source)) {
+            this["script"] = "";
+        }
+        if (!("language" in $$source)) {
+            this["language"] = "";
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new LanguageSummary instance from a string or object.
+     */
+    static createFrom($$source: any = {}): LanguageSummary {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new LanguageSummary($$parsedSource as Partial<LanguageSummary>);
+    }
+}
+
+export class MoodSummary {
+    "mood": string;
+    "trackCount": number;
+
+    /** Creates a new MoodSummary instance. */
+    constructor($$source: Partial<MoodSummary> = {}) {
+        if (!("mood" in $$source)) {
+            this["mood"] = "";
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new MoodSummary instance from a string or object.
+     */
+    static createFrom($$source: any = {}): MoodSummary {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new MoodSummary($$parsedSource as Partial<MoodSummary>);
+    }
+}
+
+export class PageInfo {
+    "limit": number;
+    "offset": number;
+    "total": number;
+
+    /** Creates a new PageInfo instance. */
+    constructor($$source: Partial<PageInfo> = {}) {
+        if (!("limit" in $$source)) {
+            this["limit"] = 0;
+        }
+        if (!("offset" in $$source)) {
+            this["offset"] = 0;
+        }
+        if (!("total" in $$source)) {
+            this["total"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new PageInfo instance from a string or object.
+     */
+    static createFrom($$source: any = {}): PageInfo {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new PageInfo($$parsedSource as Partial<PageInfo>);
+    }
+}
+
+export class ProvenanceSummary {
+    "field": string;
+    "value": string;
+    "trackCount": number;
+
+    /** Creates a new ProvenanceSummary instance. */
+    constructor($$source: Partial<ProvenanceSummary> = {}) {
+        if (!("field" in $$source)) {
+            this["field"] = "";
+        }
+        if (!("value" in $$source)) {
+            this["value"] = "";
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ProvenanceSummary instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ProvenanceSummary {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ProvenanceSummary($$parsedSource as Partial<ProvenanceSummary>);
+    }
+}
+
+/**
+ * RatingFilter narrows track and album listings to favorites or to a
+ * minimum star rating; the zero value does not filter.
+ */
+export class RatingFilter {
+    "favoritesOnly": boolean;
+    "minRating": number;
+
+    /** Creates a new RatingFilter instance. */
+    constructor($$source: Partial<RatingFilter> = {}) {
+        if (!("favoritesOnly" in $$source)) {
+            this["favoritesOnly"] = false;
+        }
+        if (!("minRating" in $$source)) {
+            this["minRating"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new RatingFilter instance from a string or object.
+     */
+    static createFrom($$source: any = {}): RatingFilter {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new RatingFilter($$parsedSource as Partial<RatingFilter>);
+    }
+}
+
+/**
+ * RootRules exclude files below a watched root from scans and the file
+ * watcher, on top of its .benignore files. Exclude holds gitignore-style
+ * patterns relative to the root, such as "** /Podcasts/**" or "*.tmp".
+ */
+export class RootRules {
+    "exclude": string[];
+    "skipHidden": boolean;
+    "minFileSize": number;
+
+    /** Creates a new RootRules instance. */
+    constructor($$source: Partial<RootRules> = {}) {
+        if (!("exclude" in $$source)) {
+            this["exclude"] = [];
+        }
+        if (!("skipHidden" in $$source)) {
+            this["skipHidden"] = false;
+        }
+        if (!("minFileSize" in $$source)) {
+            this["minFileSize"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new RootRules instance from a string or object.
+     */
+    static createFrom($$source: any = {}): RootRules {
+        const $$createField0_0 = $$createType28;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("exclude" in $$parsedSource) {
+            $$parsedSource["exclude"] = $$createField0_0($$parsedSource["exclude"]);
+        }
+        return new RootRules($$parsedSource as Partial<RootRules>);
+    }
+}
+
+/**
+ * RootSchedule sets when a root is scanned without being asked: when the
+ * app starts and every RescanHours hours, 0 for never. Scheduled scans wait
+ * out the quiet hours, local hours from QuietHoursStart up to QuietHoursEnd,
+ * which may wrap midnight; equal hours mean no quiet hours.
+ */
+export class RootSchedule {
+    "scanOnStartup": boolean;
+    "rescanHours": number;
+    "quietHoursStart": number;
+    "quietHoursEnd": number;
+
+    /** Creates a new RootSchedule instance. */
+    constructor($$source: Partial<RootSchedule> = {}) {
+        if (!("scanOnStartup" in $$source)) {
+            this["scanOnStartup"] = false;
+        }
+        if (!("rescanHours" in $$source)) {
+            this["rescanHours"] = 0;
+        }
+        if (!("quietHoursStart" in $$source)) {
+            this["quietHoursStart"] = 0;
+        }
+        if (!("quietHoursEnd" in $$source)) {
+            this["quietHoursEnd"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new RootSchedule instance from a string or object.
+     */
+    static createFrom($$source: any = {}): RootSchedule {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new RootSchedule($$parsedSource as Partial<RootSchedule>);
+    }
+}
+
+/**
+ * SharedCover is one cover image and the albums using it, in artist and
+ * title order.
+ */
+export class SharedCover {
+    "hash": string;
+    "thumbnailUrl"?: string | null;
+    "albums": AlbumKey[];
+
+    /** Creates a new SharedCover instance. */
+    constructor($$source: Partial<SharedCover> = {}) {
+        if (!("hash" in $$source)) {
+            this["hash"] = "";
+        }
+        if (!("albums" in $$source)) {
+            this["albums"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SharedCover instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SharedCover {
+        const $$createField2_0 = $$createType26;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("albums" in $$parsedSource) {
+            $$parsedSource["albums"] = $$createField2_0($$parsedSource["albums"]);
+        }
+        return new SharedCover($$parsedSource as Partial<SharedCover>);
+    }
+}
+
+export class TimelineAlbum {
+    "title": string;
+    "albumArtist": string;
+    "year"?: number | null;
+    "trackCount": number;
+    "coverPath"?: string | null;
+    "playCount"?: number | null;
+    "lastPlayedAt"?: string | null;
+    "rating"?: number | null;
+    "favorite"?: boolean;
+    "addedAt"?: string | null;
+    "firstPlayedAt"?: string | null;
+    "totalPlays": number;
+    "playedMs": number;
+
+    /** Creates a new TimelineAlbum instance. */
+    constructor($$source: Partial<TimelineAlbum> = {}) {
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+        if (!("totalPlays" in $$source)) {
+            this["totalPlays"] = 0;
+        }
+        if (!("playedMs" in $$source)) {
+            this["playedMs"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TimelineAlbum instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TimelineAlbum {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new TimelineAlbum($$parsedSource as Partial<TimelineAlbum>);
+    }
+}
+
+/**
+ * TimelineYear groups an artist's albums by release year. Year is nil for
+ * albums without a year tag, which are listed last.
+ */
+export class TimelineYear {
+    "year"?: number | null;
+    "albums": TimelineAlbum[];
+    "totalPlays": number;
+
+    /** Creates a new TimelineYear instance. */
+    constructor($$source: Partial<TimelineYear> = {}) {
+        if (!("albums" in $$source)) {
+            this["albums"] = [];
+        }
+        if (!("totalPlays" in $$source)) {
+            this["totalPlays"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TimelineYear instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TimelineYear {
+        const $$createField1_0 = $$createType40;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("albums" in $$parsedSource) {
+            $$parsedSource["albums"] = $$createField1_0($$parsedSource["albums"]);
+        }
+        return new TimelineYear($$parsedSource as Partial<TimelineYear>);
+    }
+}
+
+export class TrackLinkGroup {
+    "canonicalTrackId": number;
+    "trackIds": number[];
+
+    /** Creates a new TrackLinkGroup instance. */
+    constructor($$source: Partial<TrackLinkGroup> = {}) {
+        if (!("canonicalTrackId" in $$source)) {
+            this["canonicalTrackId"] = 0;
+        }
+        if (!("trackIds" in $$source)) {
+            this["trackIds"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrackLinkGroup instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TrackLinkGroup {
+        const $$createField1_0 = $$createType27;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("trackIds" in $$parsedSource) {
+            $$parsedSource["trackIds"] = $$createField1_0($$parsedSource["trackIds"]);
+        }
+        return new TrackLinkGroup($$parsedSource as Partial<TrackLinkGroup>);
+    }
+}
+
+/**
+ * TrackMoods lists the moods of a track. Moods holds every mood, the ones
+ * assigned by hand and the estimated ones; Auto is the estimated subset.
+ */
+export class TrackMoods {
+    "trackId": number;
+    "moods": string[];
+    "auto": string[];
+
+    /** Creates a new TrackMoods instance. */
+    constructor($$source: Partial<TrackMoods> = {}) {
+        if (!("trackId" in $$source)) {
+            this["trackId"] = 0;
+        }
+        if (!("moods" in $$source)) {
+            this["moods"] = [];
+        }
+        if (!("auto" in $$source)) {
+            this["auto"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrackMoods instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TrackMoods {
+        const $$createField1_0 = $$createType28;
+        const $$createField2_0 = $$createType28;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("moods" in $$parsedSource) {
+            $$parsedSource["moods"] = $$createField1_0($$parsedSource["moods"]);
+        }
+        if ("auto" in $$parsedSource) {
+            $$parsedSource["auto"] = $$createField2_0($$parsedSource["auto"]);
+        }
+        return new TrackMoods($$parsedSource as Partial<TrackMoods>);
+    }
+}
+
+/**
+ * TrackProvenance records where the file of a track came from. Empty
+ * fields are unknown. FromTags reports provenance imported from tags that
+ * was not edited by hand since.
+ */
+export class TrackProvenance {
+    "trackId": number;
+    "purchasedFrom": string;
+    "rippedFrom": string;
+    "downloadSource": string;
+    "fromTags": boolean;
+
+    /** Creates a new TrackProvenance instance. */
+    constructor($$source: Partial<TrackProvenance> = {}) {
+        if (!("trackId" in $$source)) {
+            this["trackId"] = 0;
+        }
+        if (!("purchasedFrom" in $$source)) {
+            this["purchasedFrom"] = "";
+        }
+        if (!("rippedFrom" in $$source)) {
+            this["rippedFrom"] = "";
+        }
+        if (!("downloadSource" in $$source)) {
+            this["downloadSource"] = "";
+        }
+        if (!("fromTags" in $$source)) {
+            this["fromTags"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrackProvenance instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TrackProvenance {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new TrackProvenance($$parsedSource as Partial<TrackProvenance>);
+    }
+}
+
+export class TrackRating {
+    "trackId": number;
+    "rating"?: number | null;
+    "favorite": boolean;
+    "banned": boolean;
+    "updatedAt": string;
+
+    /** Creates a new TrackRating instance. */
+    constructor($$source: Partial<TrackRating> = {}) {
+        if (!("trackId" in $$source)) {
+            this["trackId"] = 0;
+        }
+        if (!("favorite" in $$source)) {
+            this["favorite"] = false;
+        }
+        if (!("banned" in $$source)) {
+            this["banned"] = false;
+        }
+        if (!("updatedAt" in $$source)) {
+            this["updatedAt"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrackRating instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TrackRating {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new TrackRating($$parsedSource as Partial<TrackRating>);
+    }
+}
+
+export class TrackSummary {
+    "id": number;
+    "title": string;
+    "artist": string;
+    "album": string;
+    "albumArtist": string;
+    "discNo"?: number | null;
+    "trackNo"?: number | null;
+    "durationMs"?: number | null;
+    "path": string;
+    "coverPath"?: string | null;
+    "playCount"?: number | null;
+    "lastPlayedAt"?: string | null;
+    "isVideo"?: boolean;
+    "rating"?: number | null;
+    "favorite"?: boolean;
+
+    /** Creates a new TrackSummary instance. */
+    constructor($$source: Partial<TrackSummary> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = 0;
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("artist" in $$source)) {
+            this["artist"] = "";
+        }
+        if (!("album" in $$source)) {
+            this["album"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrackSummary instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TrackSummary {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new TrackSummary($$parsedSource as Partial<TrackSummary>);
+    }
+}
+
+/**
+ * TrackTrim skips the start and end of a track, e.g. a long intro or a
+ * hidden track after minutes of silence. A nil EndMS plays to the end of the
+ * file.
+ */
+export class TrackTrim {
+    "trackId": number;
+    "startMs": number;
+    "endMs"?: number | null;
+
+    /** Creates a new TrackTrim instance. */
+    constructor($$source: Partial<TrackTrim> = {}) {
+        if (!("trackId" in $$source)) {
+            this["trackId"] = 0;
+        }
+        if (!("startMs" in $$source)) {
+            this["startMs"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrackTrim instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TrackTrim {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new TrackTrim($$parsedSource as Partial<TrackTrim>);
+    }
+}
+
+/**
+ * TrackVolumeOffset is the manual loudness correction for a track. The album
+ * and track offsets add up, so a track offset fine-tunes its album's.
+ */
+export class TrackVolumeOffset {
+    "trackId": number;
+    "trackOffsetDb"?: number | null;
+    "albumOffsetDb"?: number | null;
+    "effectiveDb": number;
+
+    /** Creates a new TrackVolumeOffset instance. */
+    constructor($$source: Partial<TrackVolumeOffset> = {}) {
+        if (!("trackId" in $$source)) {
+            this["trackId"] = 0;
+        }
+        if (!("effectiveDb" in $$source)) {
+            this["effectiveDb"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrackVolumeOffset instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TrackVolumeOffset {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new TrackVolumeOffset($$parsedSource as Partial<TrackVolumeOffset>);
+    }
+}
+
+export class TracksPage {
+    "items": TrackSummary[];
+    "page": PageInfo;
+
+    /** Creates a new TracksPage instance. */
+    constructor($$source: Partial<TracksPage> = {}) {
+        if (!("items" in $$source)) {
+            this["items"] = [];
+        }
+        if (!("page" in $$source)) {
+            this["page"] = (new PageInfo());
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TracksPage instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TracksPage {
+        const $$createField0_0 = $$createType2;
+        const $$createField1_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("items" in $$parsedSource) {
+            $$parsedSource["items"] = $$createField0_0($$parsedSource["items"]);
+        }
+        if ("page" in $$parsedSource) {
+            $$parsedSource["page"] = $$createField1_0($$parsedSource["page"]);
+        }
+        return new TracksPage($$parsedSource as Partial<TracksPage>);
+    }
+}
+
+export class WatchedRoot {
+    "id": number;
+    "path": string;
+    "enabled": boolean;
+    "createdAt": string;
+    "kind": string;
+    "username"?: string;
+    "offline": boolean;
+    "lastCheckedAt"?: string | null;
+    "lastError"?: string | null;
+    "rules": RootRules;
+    "schedule": RootSchedule;
+
+    /**
+     * LastScheduledScanAt is when the scheduler last rescanned the root.
+     */
+    "lastScheduledScanAt"?: string | null;
+
+    /** Creates a new WatchedRoot instance. */
+    constructor($$source: Partial<WatchedRoot> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = 0;
+        }
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("enabled" in $$source)) {
+            this["enabled"] = false;
+        }
+        if (!("createdAt" in $$source)) {
+            this["createdAt"] = "";
+        }
+        if (!("kind" in $$source)) {
+            this["kind"] = "";
+        }
+        if (!("offline" in $$source)) {
+            this["offline"] = false;
+        }
+        if (!("rules" in $$source)) {
+            this["rules"] = (new RootRules());
+        }
+        if (!("schedule" in $$source)) {
+            this["schedule"] = (new RootSchedule());
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new WatchedRoot instance from a string or object.
+     */
+    static createFrom($$source: any = {}): WatchedRoot {
+        const $$createField9_0 = $$createType41;
+        const $$createField10_0 = $$createType42;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("rules" in $$parsedSource) {
+            $$parsedSource["rules"] = $$createField9_0($$parsedSource["rules"]);
+        }
+        if ("schedule" in $$parsedSource) {
+            $$parsedSource["schedule"] = $$createField10_0($$parsedSource["schedule"]);
+        }
+        return new WatchedRoot($$parsedSource as Partial<WatchedRoot>);
+    }
+}
+
+export class WorkDetail {
+    "title": string;
+    "composer": string;
+    "trackCount": number;
+    "recordings": WorkRecording[];
+
+    /** Creates a new WorkDetail instance. */
+    constructor($$source: Partial<WorkDetail> = {}) {
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("composer" in $$source)) {
+            this["composer"] = "";
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+        if (!("recordings" in $$source)) {
+            this["recordings"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new WorkDetail instance from a string or object.
+     */
+    static createFrom($$source: any = {}): WorkDetail {
+        const $$createField3_0 = $$createType44;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("recordings" in $$parsedSource) {
+            $$parsedSource["recordings"] = $$createField3_0($$parsedSource["recordings"]);
+        }
+        return new WorkDetail($$parsedSource as Partial<WorkDetail>);
+    }
+}
+
+/**
+ * WorkRecording is one album's recording of a work, with its movements in
+ * order.
+ */
+export class WorkRecording {
+    "albumTitle": string;
+    "albumArtist": string;
+    "conductor"?: string | null;
+    "year"?: number | null;
+    "coverPath"?: string | null;
+    "tracks": TrackSummary[];
+
+    /** Creates a new WorkRecording instance. */
+    constructor($$source: Partial<WorkRecording> = {}) {
+        if (!("albumTitle" in $$source)) {
+            this["albumTitle"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+        if (!("tracks" in $$source)) {
+            this["tracks"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new WorkRecording instance from a string or object.
+     */
+    static createFrom($$source: any = {}): WorkRecording {
+        const $$createField5_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("tracks" in $$parsedSource) {
+            $$parsedSource["tracks"] = $$createField5_0($$parsedSource["tracks"]);
+        }
+        return new WorkRecording($$parsedSource as Partial<WorkRecording>);
+    }
+}
+
+/**
+ * WorkSummary is a work of a composer; RecordingCount counts the albums it
+ * is recorded on.
+ */
+export class WorkSummary {
+    "title": string;
+    "composer": string;
+    "trackCount": number;
+    "recordingCount": number;
+
+    /** Creates a new WorkSummary instance. */
+    constructor($$source: Partial<WorkSummary> = {}) {
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("composer" in $$source)) {
+            this["composer"] = "";
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+        if (!("recordingCount" in $$source)) {
+            this["recordingCount"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new WorkSummary instance from a string or object.
+     */
+    static createFrom($$source: any = {}): WorkSummary {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new WorkSummary($$parsedSource as Partial<WorkSummary>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = AlbumProgress.createFrom;
+const $$createType1 = TrackSummary.createFrom;
+const $$createType2 = $Create.Array($$createType1);
+const $$createType3 = PageInfo.createFrom;
+const $$createType4 = $Create.Array($Create.Any);
+const $$createType5 = AlbumSummary.createFrom;
+const $$createType6 = $Create.Array($$createType5);
+const $$createType7 = palette$0.ThemePalette.createFrom;
+const $$createType8 = $Create.Nullable($$createType7);
+const $$createType9 = AmbientCover.createFrom;
+const $$createType10 = $Create.Array($$createType9);
+const $$createType11 = TimelineYear.createFrom;
+const $$createType12 = $Create.Array($$createType11);
+const $$createType13 = ArtistSummary.createFrom;
+const $$createType14 = $Create.Array($$createType13);
+const $$createType15 = AudiobookChapter.createFrom;
+const $$createType16 = $Create.Array($$createType15);
+const $$createType17 = Audiobook.createFrom;
+const $$createType18 = $Create.Array($$createType17);
+const $$createType19 = WorkSummary.createFrom;
+const $$createType20 = $Create.Array($$createType19);
+const $$createType21 = ComposerSummary.createFrom;
+const $$createType22 = $Create.Array($$createType21);
+const $$createType23 = SharedCover.createFrom;
+const $$createType24 = $Create.Array($$createType23);
+const $$createType25 = AlbumKey.createFrom;
+const $$createType26 = $Create.Array($$createType25);
+const $$createType27 = $Create.Array($Create.Any);
+const $$createType28 = $Create.Array($Create.Any);
+const $$createType29 = DuplicateAlbum.createFrom;
+const $$createType30 = $Create.Array($$createType29);
+const $$createType31 = DuplicateAlbumCandidate.createFrom;
+const $$createType32 = $Create.Array($$createType31);
+const $$createType33 = DuplicateTrack.createFrom;
+const $$createType34 = $Create.Array($$createType33);
+const $$createType35 = DuplicateGroup.createFrom;
+const $$createType36 = $Create.Array($$createType35);
+const $$createType37 = AlbumDiscGap.createFrom;
+const $$createType38 = $Create.Array($$createType37);
+const $$createType39 = TimelineAlbum.createFrom;
+const $$createType40 = $Create.Array($$createType39);
+const $$createType41 = RootRules.createFrom;
+const $$createType42 = RootSchedule.createFrom;
+const $$createType43 = WorkRecording.createFrom;
+const $$createType44 = $Create.Array($$createType43);
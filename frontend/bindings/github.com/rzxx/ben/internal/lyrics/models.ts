@@ -0,0 +1,75 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * Line is one synced lyric line. TimeMS is where the line starts in the
+ * file.
+ */
+export class Line {
+    "timeMs": number;
+    "text": string;
+
+    /** Creates a new Line instance. */
+    constructor($$source: Partial<Line> = {}) {
+        if (!("timeMs" in $$source)) {
+            this["timeMs"] = 0;
+        }
+        if (!("text" in $$source)) {
+            this["text"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Line instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Line {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Line($$parsedSource as Partial<Line>);
+    }
+}
+
+/**
+ * Lyrics are the synced lines of a track.
+ */
+export class Lyrics {
+    "trackId": number;
+    "synced": boolean;
+    "lines": Line[];
+
+    /** Creates a new Lyrics instance. */
+    constructor($$source: Partial<Lyrics> = {}) {
+        if (!("trackId" in $$source)) {
+            this["trackId"] = 0;
+        }
+        if (!("synced" in $$source)) {
+            this["synced"] = false;
+        }
+        if (!("lines" in $$source)) {
+            this["lines"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Lyrics instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Lyrics {
+        const $$createField2_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("lines" in $$parsedSource) {
+            $$parsedSource["lines"] = $$createField2_0($$parsedSource["lines"]);
+        }
+        return new Lyrics($$parsedSource as Partial<Lyrics>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = Line.createFrom;
+const $$createType1 = $Create.Array($$createType0);
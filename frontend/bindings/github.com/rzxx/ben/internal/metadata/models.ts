@@ -0,0 +1,284 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * Candidate is a possible match for a track. Score runs from 0 to 100.
+ */
+export class Candidate {
+    "source": string;
+    "score": number;
+    "recordingId": string;
+    "releaseId": string;
+    "releaseGroupId": string;
+    "title": string;
+    "artist": string;
+    "album": string;
+    "albumArtist": string;
+    "year"?: number | null;
+    "genre": string;
+
+    /** Creates a new Candidate instance. */
+    constructor($$source: Partial<Candidate> = {}) {
+        if (!("source" in $$source)) {
+            this["source"] = "";
+        }
+        if (!("score" in $$source)) {
+            this["score"] = 0;
+        }
+        if (!("recordingId" in $$source)) {
+            this["recordingId"] = "";
+        }
+        if (!("releaseId" in $$source)) {
+            this["releaseId"] = "";
+        }
+        if (!("releaseGroupId" in $$source)) {
+            this["releaseGroupId"] = "";
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("artist" in $$source)) {
+            this["artist"] = "";
+        }
+        if (!("album" in $$source)) {
+            this["album"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+        if (!("genre" in $$source)) {
+            this["genre"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Candidate instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Candidate {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Candidate($$parsedSource as Partial<Candidate>);
+    }
+}
+
+/**
+ * EnrichResult counts the tracks an enrich action filled fields in.
+ */
+export class EnrichResult {
+    "tracks": number;
+    "updated": number;
+    "years": number;
+    "albumArtists": number;
+    "genres": number;
+    "releases": number;
+
+    /** Creates a new EnrichResult instance. */
+    constructor($$source: Partial<EnrichResult> = {}) {
+        if (!("tracks" in $$source)) {
+            this["tracks"] = 0;
+        }
+        if (!("updated" in $$source)) {
+            this["updated"] = 0;
+        }
+        if (!("years" in $$source)) {
+            this["years"] = 0;
+        }
+        if (!("albumArtists" in $$source)) {
+            this["albumArtists"] = 0;
+        }
+        if (!("genres" in $$source)) {
+            this["genres"] = 0;
+        }
+        if (!("releases" in $$source)) {
+            this["releases"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new EnrichResult instance from a string or object.
+     */
+    static createFrom($$source: any = {}): EnrichResult {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new EnrichResult($$parsedSource as Partial<EnrichResult>);
+    }
+}
+
+export class GenreInferenceResult {
+    "untagged": number;
+    "inferred": number;
+
+    /** Creates a new GenreInferenceResult instance. */
+    constructor($$source: Partial<GenreInferenceResult> = {}) {
+        if (!("untagged" in $$source)) {
+            this["untagged"] = 0;
+        }
+        if (!("inferred" in $$source)) {
+            this["inferred"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new GenreInferenceResult instance from a string or object.
+     */
+    static createFrom($$source: any = {}): GenreInferenceResult {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new GenreInferenceResult($$parsedSource as Partial<GenreInferenceResult>);
+    }
+}
+
+/**
+ * InferredGenre is a probable genre for a track without a genre tag.
+ * Confidence runs from 0 to 1; Votes is the number of tagged tracks it was
+ * inferred from.
+ */
+export class InferredGenre {
+    "trackId": number;
+    "title": string;
+    "artist": string;
+    "album": string;
+    "genre": string;
+    "source": string;
+    "confidence": number;
+    "votes": number;
+
+    /** Creates a new InferredGenre instance. */
+    constructor($$source: Partial<InferredGenre> = {}) {
+        if (!("trackId" in $$source)) {
+            this["trackId"] = 0;
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("artist" in $$source)) {
+            this["artist"] = "";
+        }
+        if (!("album" in $$source)) {
+            this["album"] = "";
+        }
+        if (!("genre" in $$source)) {
+            this["genre"] = "";
+        }
+        if (!("source" in $$source)) {
+            this["source"] = "";
+        }
+        if (!("confidence" in $$source)) {
+            this["confidence"] = 0;
+        }
+        if (!("votes" in $$source)) {
+            this["votes"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new InferredGenre instance from a string or object.
+     */
+    static createFrom($$source: any = {}): InferredGenre {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new InferredGenre($$parsedSource as Partial<InferredGenre>);
+    }
+}
+
+/**
+ * ReleaseTrackListResult counts the releases RefreshReleaseTrackLists
+ * looked up. Releases cached within the cache lifetime are not counted.
+ */
+export class ReleaseTrackListResult {
+    "releases": number;
+    "fetched": number;
+    "failed": number;
+
+    /** Creates a new ReleaseTrackListResult instance. */
+    constructor($$source: Partial<ReleaseTrackListResult> = {}) {
+        if (!("releases" in $$source)) {
+            this["releases"] = 0;
+        }
+        if (!("fetched" in $$source)) {
+            this["fetched"] = 0;
+        }
+        if (!("failed" in $$source)) {
+            this["failed"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ReleaseTrackListResult instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ReleaseTrackListResult {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ReleaseTrackListResult($$parsedSource as Partial<ReleaseTrackListResult>);
+    }
+}
+
+/**
+ * TrackLookup is a track's current metadata and the candidates found for it.
+ */
+export class TrackLookup {
+    "trackId": number;
+    "title": string;
+    "artist": string;
+    "album": string;
+    "albumArtist": string;
+    "year"?: number | null;
+    "genre": string;
+    "cached": boolean;
+    "candidates": Candidate[];
+
+    /** Creates a new TrackLookup instance. */
+    constructor($$source: Partial<TrackLookup> = {}) {
+        if (!("trackId" in $$source)) {
+            this["trackId"] = 0;
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("artist" in $$source)) {
+            this["artist"] = "";
+        }
+        if (!("album" in $$source)) {
+            this["album"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+        if (!("genre" in $$source)) {
+            this["genre"] = "";
+        }
+        if (!("cached" in $$source)) {
+            this["cached"] = false;
+        }
+        if (!("candidates" in $$source)) {
+            this["candidates"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrackLookup instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TrackLookup {
+        const $$createField8_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("candidates" in $$parsedSource) {
+            $$parsedSource["candidates"] = $$createField8_0($$parsedSource["candidates"]);
+        }
+        return new TrackLookup($$parsedSource as Partial<TrackLookup>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = Candidate.createFrom;
+const $$createType1 = $Create.Array($$createType0);
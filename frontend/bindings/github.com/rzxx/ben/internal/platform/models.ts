@@ -0,0 +1,40 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * TrackNotifications controls the native notification shown when a new
+ * track starts. WhenFocused and WhenUnfocused pick whether it is shown
+ * while the player window has focus and while it does not.
+ */
+export class TrackNotifications {
+    "enabled": boolean;
+    "whenFocused": boolean;
+    "whenUnfocused": boolean;
+
+    /** Creates a new TrackNotifications instance. */
+    constructor($$source: Partial<TrackNotifications> = {}) {
+        if (!("enabled" in $$source)) {
+            this["enabled"] = false;
+        }
+        if (!("whenFocused" in $$source)) {
+            this["whenFocused"] = false;
+        }
+        if (!("whenUnfocused" in $$source)) {
+            this["whenUnfocused"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrackNotifications instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TrackNotifications {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new TrackNotifications($$parsedSource as Partial<TrackNotifications>);
+    }
+}
@@ -0,0 +1,767 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as library$0 from "../library/models.js";
+
+/**
+ * AudioAccessibility folds stereo down to mono and shifts the output between
+ * the left and right channel. Balance runs from -1 (left only) to 1 (right
+ * only); the louder side always stays at full level.
+ */
+export class AudioAccessibility {
+    "mono": boolean;
+    "balance": number;
+
+    /** Creates a new AudioAccessibility instance. */
+    constructor($$source: Partial<AudioAccessibility> = {}) {
+        if (!("mono" in $$source)) {
+            this["mono"] = false;
+        }
+        if (!("balance" in $$source)) {
+            this["balance"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AudioAccessibility instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AudioAccessibility {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new AudioAccessibility($$parsedSource as Partial<AudioAccessibility>);
+    }
+}
+
+export class BackendOption {
+    "name": string;
+    "value": string;
+
+    /** Creates a new BackendOption instance. */
+    constructor($$source: Partial<BackendOption> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("value" in $$source)) {
+            this["value"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new BackendOption instance from a string or object.
+     */
+    static createFrom($$source: any = {}): BackendOption {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new BackendOption($$parsedSource as Partial<BackendOption>);
+    }
+}
+
+/**
+ * BackendOptions are passed to the playback backend before it starts.
+ * Filters is prepended to the accessibility filter chain; audio filters go
+ * there rather than into an "af" option.
+ */
+export class BackendOptions {
+    "options": BackendOption[];
+    "filters": string;
+
+    /** Creates a new BackendOptions instance. */
+    constructor($$source: Partial<BackendOptions> = {}) {
+        if (!("options" in $$source)) {
+            this["options"] = [];
+        }
+        if (!("filters" in $$source)) {
+            this["filters"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new BackendOptions instance from a string or object.
+     */
+    static createFrom($$source: any = {}): BackendOptions {
+        const $$createField0_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("options" in $$parsedSource) {
+            $$parsedSource["options"] = $$createField0_0($$parsedSource["options"]);
+        }
+        return new BackendOptions($$parsedSource as Partial<BackendOptions>);
+    }
+}
+
+/**
+ * BackendOptionsStatus compares the saved options with the ones the backend
+ * started with. FallbackError is set when the backend rejected the saved
+ * options at startup and was started without them.
+ */
+export class BackendOptionsStatus {
+    "saved": BackendOptions;
+    "applied": BackendOptions;
+    "restartRequired": boolean;
+    "fallbackError"?: string;
+
+    /** Creates a new BackendOptionsStatus instance. */
+    constructor($$source: Partial<BackendOptionsStatus> = {}) {
+        if (!("saved" in $$source)) {
+            this["saved"] = (new BackendOptions());
+        }
+        if (!("applied" in $$source)) {
+            this["applied"] = (new BackendOptions());
+        }
+        if (!("restartRequired" in $$source)) {
+            this["restartRequired"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new BackendOptionsStatus instance from a string or object.
+     */
+    static createFrom($$source: any = {}): BackendOptionsStatus {
+        const $$createField0_0 = $$createType2;
+        const $$createField1_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("saved" in $$parsedSource) {
+            $$parsedSource["saved"] = $$createField0_0($$parsedSource["saved"]);
+        }
+        if ("applied" in $$parsedSource) {
+            $$parsedSource["applied"] = $$createField1_0($$parsedSource["applied"]);
+        }
+        return new BackendOptionsStatus($$parsedSource as Partial<BackendOptionsStatus>);
+    }
+}
+
+/**
+ * BackendStatus reports whether audio output is available. Error holds the
+ * reason the backend could not be started.
+ */
+export class BackendStatus {
+    "available": boolean;
+    "version"?: string;
+    "error"?: string;
+
+    /** Creates a new BackendStatus instance. */
+    constructor($$source: Partial<BackendStatus> = {}) {
+        if (!("available" in $$source)) {
+            this["available"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new BackendStatus instance from a string or object.
+     */
+    static createFrom($$source: any = {}): BackendStatus {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new BackendStatus($$parsedSource as Partial<BackendStatus>);
+    }
+}
+
+/**
+ * Equalizer is a 10-band graphic equalizer. Gains are in dB, one per entry
+ * of EqualizerBands. Preset names the built-in preset the gains match, or is
+ * empty once a band was adjusted by hand.
+ */
+export class Equalizer {
+    "enabled": boolean;
+    "preset": string;
+    "gains": number[];
+
+    /** Creates a new Equalizer instance. */
+    constructor($$source: Partial<Equalizer> = {}) {
+        if (!("enabled" in $$source)) {
+            this["enabled"] = false;
+        }
+        if (!("preset" in $$source)) {
+            this["preset"] = "";
+        }
+        if (!("gains" in $$source)) {
+            this["gains"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Equalizer instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Equalizer {
+        const $$createField2_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("gains" in $$parsedSource) {
+            $$parsedSource["gains"] = $$createField2_0($$parsedSource["gains"]);
+        }
+        return new Equalizer($$parsedSource as Partial<Equalizer>);
+    }
+}
+
+/**
+ * EqualizerPreset is a named set of band gains.
+ */
+export class EqualizerPreset {
+    "id": string;
+    "name": string;
+    "gains": number[];
+
+    /** Creates a new EqualizerPreset instance. */
+    constructor($$source: Partial<EqualizerPreset> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = "";
+        }
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("gains" in $$source)) {
+            this["gains"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new EqualizerPreset instance from a string or object.
+     */
+    static createFrom($$source: any = {}): EqualizerPreset {
+        const $$createField2_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("gains" in $$parsedSource) {
+            $$parsedSource["gains"] = $$createField2_0($$parsedSource["gains"]);
+        }
+        return new EqualizerPreset($$parsedSource as Partial<EqualizerPreset>);
+    }
+}
+
+/**
+ * LevelingState reports the adjustment applied to the current track.
+ * Estimated is set for tracks without loudness tags, which are leveled
+ * from the tagged tracks around them in the queue.
+ */
+export class LevelingState {
+    "gainDb": number;
+    "estimated": boolean;
+
+    /** Creates a new LevelingState instance. */
+    constructor($$source: Partial<LevelingState> = {}) {
+        if (!("gainDb" in $$source)) {
+            this["gainDb"] = 0;
+        }
+        if (!("estimated" in $$source)) {
+            this["estimated"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new LevelingState instance from a string or object.
+     */
+    static createFrom($$source: any = {}): LevelingState {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new LevelingState($$parsedSource as Partial<LevelingState>);
+    }
+}
+
+export class PlaybackDiagnosticEvent {
+    "id": number;
+    "trackId": number;
+    "event": string;
+    "durationMs": number;
+    "path": string;
+    "detail"?: string;
+    "recordedAt": string;
+
+    /** Creates a new PlaybackDiagnosticEvent instance. */
+    constructor($$source: Partial<PlaybackDiagnosticEvent> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = 0;
+        }
+        if (!("trackId" in $$source)) {
+            this["trackId"] = 0;
+        }
+        if (!("event" in $$source)) {
+            this["event"] = "";
+        }
+        if (!("durationMs" in $$source)) {
+            this["durationMs"] = 0;
+        }
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("recordedAt" in $$source)) {
+            this["recordedAt"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new PlaybackDiagnosticEvent instance from a string or object.
+     */
+    static createFrom($$source: any = {}): PlaybackDiagnosticEvent {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new PlaybackDiagnosticEvent($$parsedSource as Partial<PlaybackDiagnosticEvent>);
+    }
+}
+
+/**
+ * PlaybackDiagnostics lists the logged tracks, those with the most
+ * underruns first, and the latest events.
+ */
+export class PlaybackDiagnostics {
+    "enabled": boolean;
+    "tracks": TrackPlaybackDiagnostics[];
+    "events": PlaybackDiagnosticEvent[];
+
+    /** Creates a new PlaybackDiagnostics instance. */
+    constructor($$source: Partial<PlaybackDiagnostics> = {}) {
+        if (!("enabled" in $$source)) {
+            this["enabled"] = false;
+        }
+        if (!("tracks" in $$source)) {
+            this["tracks"] = [];
+        }
+        if (!("events" in $$source)) {
+            this["events"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new PlaybackDiagnostics instance from a string or object.
+     */
+    static createFrom($$source: any = {}): PlaybackDiagnostics {
+        const $$createField1_0 = $$createType5;
+        const $$createField2_0 = $$createType7;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("tracks" in $$parsedSource) {
+            $$parsedSource["tracks"] = $$createField1_0($$parsedSource["tracks"]);
+        }
+        if ("events" in $$parsedSource) {
+            $$parsedSource["events"] = $$createField2_0($$parsedSource["events"]);
+        }
+        return new PlaybackDiagnostics($$parsedSource as Partial<PlaybackDiagnostics>);
+    }
+}
+
+/**
+ * PreviewState describes the excerpt playing on the preview backend.
+ */
+export class PreviewState {
+    "active": boolean;
+    "trackId"?: number;
+    "startMs": number;
+    "durationMs": number;
+    "startedAt"?: string;
+
+    /** Creates a new PreviewState instance. */
+    constructor($$source: Partial<PreviewState> = {}) {
+        if (!("active" in $$source)) {
+            this["active"] = false;
+        }
+        if (!("startMs" in $$source)) {
+            this["startMs"] = 0;
+        }
+        if (!("durationMs" in $$source)) {
+            this["durationMs"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new PreviewState instance from a string or object.
+     */
+    static createFrom($$source: any = {}): PreviewState {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new PreviewState($$parsedSource as Partial<PreviewState>);
+    }
+}
+
+/**
+ * QuietHours caps the volume between Start and End, given as local "HH:MM"
+ * times. A schedule whose start is after its end runs past midnight.
+ */
+export class QuietHours {
+    "enabled": boolean;
+    "start": string;
+    "end": string;
+    "maxVolume": number;
+
+    /** Creates a new QuietHours instance. */
+    constructor($$source: Partial<QuietHours> = {}) {
+        if (!("enabled" in $$source)) {
+            this["enabled"] = false;
+        }
+        if (!("start" in $$source)) {
+            this["start"] = "";
+        }
+        if (!("end" in $$source)) {
+            this["end"] = "";
+        }
+        if (!("maxVolume" in $$source)) {
+            this["maxVolume"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new QuietHours instance from a string or object.
+     */
+    static createFrom($$source: any = {}): QuietHours {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new QuietHours($$parsedSource as Partial<QuietHours>);
+    }
+}
+
+/**
+ * SampleRatePolicy controls the sample rate the output device runs at.
+ * FixedRate is only used by the fixed mode.
+ */
+export class SampleRatePolicy {
+    "mode": string;
+    "fixedRate": number;
+
+    /** Creates a new SampleRatePolicy instance. */
+    constructor($$source: Partial<SampleRatePolicy> = {}) {
+        if (!("mode" in $$source)) {
+            this["mode"] = "";
+        }
+        if (!("fixedRate" in $$source)) {
+            this["fixedRate"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SampleRatePolicy instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SampleRatePolicy {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new SampleRatePolicy($$parsedSource as Partial<SampleRatePolicy>);
+    }
+}
+
+/**
+ * SleepTimerState is the running sleep timer as reported in State.
+ */
+export class SleepTimerState {
+    "endsAt": string;
+    "remainingMs": number;
+    "fadeOut": boolean;
+
+    /** Creates a new SleepTimerState instance. */
+    constructor($$source: Partial<SleepTimerState> = {}) {
+        if (!("endsAt" in $$source)) {
+            this["endsAt"] = "";
+        }
+        if (!("remainingMs" in $$source)) {
+            this["remainingMs"] = 0;
+        }
+        if (!("fadeOut" in $$source)) {
+            this["fadeOut"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SleepTimerState instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SleepTimerState {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new SleepTimerState($$parsedSource as Partial<SleepTimerState>);
+    }
+}
+
+export class State {
+    "status": string;
+    "positionMs": number;
+    "volume": number;
+    "currentTrack"?: library$0.TrackSummary | null;
+    "currentIndex": number;
+    "queueLength": number;
+    "durationMs"?: number | null;
+    "crossfadeMs": number;
+    "continuousMix": boolean;
+    "quietHoursActive": boolean;
+    "outputSampleRate"?: number;
+    "replayGainMode": string;
+    "sleepTimer"?: SleepTimerState | null;
+    "stopAfterCurrent": boolean;
+    "transcode"?: TranscodeState | null;
+    "leveling"?: LevelingState | null;
+    "updatedAt": string;
+
+    /** Creates a new State instance. */
+    constructor($$source: Partial<State> = {}) {
+        if (!("status" in $$source)) {
+            this["status"] = "";
+        }
+        if (!("positionMs" in $$source)) {
+            this["positionMs"] = 0;
+        }
+        if (!("volume" in $$source)) {
+            this["volume"] = 0;
+        }
+        if (!("currentIndex" in $$source)) {
+            this["currentIndex"] = 0;
+        }
+        if (!("queueLength" in $$source)) {
+            this["queueLength"] = 0;
+        }
+        if (!("crossfadeMs" in $$source)) {
+            this["crossfadeMs"] = 0;
+        }
+        if (!("continuousMix" in $$source)) {
+            this["continuousMix"] = false;
+        }
+        if (!("quietHoursActive" in $$source)) {
+            this["quietHoursActive"] = false;
+        }
+        if (!("replayGainMode" in $$source)) {
+            this["replayGainMode"] = "";
+        }
+        if (!("stopAfterCurrent" in $$source)) {
+            this["stopAfterCurrent"] = false;
+        }
+        if (!("updatedAt" in $$source)) {
+            this["updatedAt"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new State instance from a string or object.
+     */
+    static createFrom($$source: any = {}): State {
+        const $$createField3_0 = $$createType9;
+        const $$createField12_0 = $$createType11;
+        const $$createField14_0 = $$createType13;
+        const $$createField15_0 = $$createType15;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("currentTrack" in $$parsedSource) {
+            $$parsedSource["currentTrack"] = $$createField3_0($$parsedSource["currentTrack"]);
+        }
+        if ("sleepTimer" in $$parsedSource) {
+            $$parsedSource["sleepTimer"] = $$createField12_0($$parsedSource["sleepTimer"]);
+        }
+        if ("transcode" in $$parsedSource) {
+            $$parsedSource["transcode"] = $$createField14_0($$parsedSource["transcode"]);
+        }
+        if ("leveling" in $$parsedSource) {
+            $$parsedSource["leveling"] = $$createField15_0($$parsedSource["leveling"]);
+        }
+        return new State($$parsedSource as Partial<State>);
+    }
+}
+
+/**
+ * TrackPlaybackDiagnostics sums up the log of one track. Failures counts
+ * loads and seeks that did not apply.
+ */
+export class TrackPlaybackDiagnostics {
+    "trackId": number;
+    "title": string;
+    "artist": string;
+    "path": string;
+    "loads": number;
+    "averageLoadMs": number;
+    "maxLoadMs": number;
+    "seeks": number;
+    "averageSeekMs": number;
+    "maxSeekMs": number;
+    "underruns": number;
+    "underrunMs": number;
+    "failures": number;
+    "lastRecordedAt": string;
+
+    /** Creates a new TrackPlaybackDiagnostics instance. */
+    constructor($$source: Partial<TrackPlaybackDiagnostics> = {}) {
+        if (!("trackId" in $$source)) {
+            this["trackId"] = 0;
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("artist" in $$source)) {
+            this["artist"] = "";
+        }
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("loads" in $$source)) {
+            this["loads"] = 0;
+        }
+        if (!("averageLoadMs" in $$source)) {
+            this["averageLoadMs"] = 0;
+        }
+        if (!("maxLoadMs" in $$source)) {
+            this["maxLoadMs"] = 0;
+        }
+        if (!("seeks" in $$source)) {
+            this["seeks"] = 0;
+        }
+        if (!("averageSeekMs" in $$source)) {
+            this["averageSeekMs"] = 0;
+        }
+        if (!("maxSeekMs" in $$source)) {
+            this["maxSeekMs"] = 0;
+        }
+        if (!("underruns" in $$source)) {
+            this["underruns"] = 0;
+        }
+        if (!("underrunMs" in $$source)) {
+            this["underrunMs"] = 0;
+        }
+        if (!("failures" in $$source)) {
+            this["failures"] = 0;
+        }
+        if (!("lastRecordedAt" in $$source)) {
+            this["lastRecordedAt"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrackPlaybackDiagnostics instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TrackPlaybackDiagnostics {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new TrackPlaybackDiagnostics($$parsedSource as Partial<TrackPlaybackDiagnostics>);
+    }
+}
+
+/**
+ * TranscodeFallbackStatus reports the saved preferences and whether ffmpeg
+ * can be found, without which the fallback never runs.
+ */
+export class TranscodeFallbackStatus {
+    "preferences": TranscodePreferences;
+    "ffmpegAvailable": boolean;
+    "ffmpegPath"?: string;
+
+    /** Creates a new TranscodeFallbackStatus instance. */
+    constructor($$source: Partial<TranscodeFallbackStatus> = {}) {
+        if (!("preferences" in $$source)) {
+            this["preferences"] = (new TranscodePreferences());
+        }
+        if (!("ffmpegAvailable" in $$source)) {
+            this["ffmpegAvailable"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TranscodeFallbackStatus instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TranscodeFallbackStatus {
+        const $$createField0_0 = $$createType16;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("preferences" in $$parsedSource) {
+            $$parsedSource["preferences"] = $$createField0_0($$parsedSource["preferences"]);
+        }
+        return new TranscodeFallbackStatus($$parsedSource as Partial<TranscodeFallbackStatus>);
+    }
+}
+
+/**
+ * TranscodePreferences choose the format a file the backend cannot decode
+ * is converted to with ffmpeg. Codecs overrides DefaultFormat per codec,
+ * e.g. {"wma": "wav"} or {"ape": "off"}.
+ */
+export class TranscodePreferences {
+    "defaultFormat": string;
+    "codecs": { [_: string]: string };
+
+    /** Creates a new TranscodePreferences instance. */
+    constructor($$source: Partial<TranscodePreferences> = {}) {
+        if (!("defaultFormat" in $$source)) {
+            this["defaultFormat"] = "";
+        }
+        if (!("codecs" in $$source)) {
+            this["codecs"] = {};
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TranscodePreferences instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TranscodePreferences {
+        const $$createField1_0 = $$createType17;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("codecs" in $$parsedSource) {
+            $$parsedSource["codecs"] = $$createField1_0($$parsedSource["codecs"]);
+        }
+        return new TranscodePreferences($$parsedSource as Partial<TranscodePreferences>);
+    }
+}
+
+/**
+ * TranscodeState tells that the current track plays, or is being prepared
+ * to play, from a transcoded copy. Error is set when the fallback failed.
+ */
+export class TranscodeState {
+    "status": string;
+    "codec"?: string;
+    "format": string;
+    "error"?: string;
+
+    /** Creates a new TranscodeState instance. */
+    constructor($$source: Partial<TranscodeState> = {}) {
+        if (!("status" in $$source)) {
+            this["status"] = "";
+        }
+        if (!("format" in $$source)) {
+            this["format"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TranscodeState instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TranscodeState {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new TranscodeState($$parsedSource as Partial<TranscodeState>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = BackendOption.createFrom;
+const $$createType1 = $Create.Array($$createType0);
+const $$createType2 = BackendOptions.createFrom;
+const $$createType3 = $Create.Array($Create.Any);
+const $$createType4 = TrackPlaybackDiagnostics.createFrom;
+const $$createType5 = $Create.Array($$createType4);
+const $$createType6 = PlaybackDiagnosticEvent.createFrom;
+const $$createType7 = $Create.Array($$createType6);
+const $$createType8 = library$0.TrackSummary.createFrom;
+const $$createType9 = $Create.Nullable($$createType8);
+const $$createType10 = SleepTimerState.createFrom;
+const $$createType11 = $Create.Nullable($$createType10);
+const $$createType12 = TranscodeState.createFrom;
+const $$createType13 = $Create.Nullable($$createType12);
+const $$createType14 = LevelingState.createFrom;
+const $$createType15 = $Create.Nullable($$createType14);
+const $$createType16 = TranscodePreferences.createFrom;
+const $$createType17 = $Create.Map($Create.Any, $Create.Any);
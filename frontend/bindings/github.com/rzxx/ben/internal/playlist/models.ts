@@ -0,0 +1,196 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as library$0 from "../library/models.js";
+
+export class Detail {
+    "playlist": Playlist;
+    "tracks": library$0.TrackSummary[];
+
+    /** Creates a new Detail instance. */
+    constructor($$source: Partial<Detail> = {}) {
+        if (!("playlist" in $$source)) {
+            this["playlist"] = (new Playlist());
+        }
+        if (!("tracks" in $$source)) {
+            this["tracks"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Detail instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Detail {
+        const $$createField0_0 = $$createType0;
+        const $$createField1_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("playlist" in $$parsedSource) {
+            $$parsedSource["playlist"] = $$createField0_0($$parsedSource["playlist"]);
+        }
+        if ("tracks" in $$parsedSource) {
+            $$parsedSource["tracks"] = $$createField1_0($$parsedSource["tracks"]);
+        }
+        return new Detail($$parsedSource as Partial<Detail>);
+    }
+}
+
+/**
+ * Folder groups playlists in the sidebar. Folders nest; ParentID is nil for
+ * top-level folders.
+ */
+export class Folder {
+    "id": number;
+    "name": string;
+    "parentId"?: number | null;
+    "createdAt": string;
+    "updatedAt": string;
+
+    /** Creates a new Folder instance. */
+    constructor($$source: Partial<Folder> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = 0;
+        }
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("createdAt" in $$source)) {
+            this["createdAt"] = "";
+        }
+        if (!("updatedAt" in $$source)) {
+            this["updatedAt"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Folder instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Folder {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Folder($$parsedSource as Partial<Folder>);
+    }
+}
+
+/**
+ * PlaybackSettings is the playback behavior applied while a playlist plays
+ * from the queue. Nil fields leave the user's current setting alone. Gapless
+ * turns crossfade off and wins over CrossfadeMS.
+ */
+export class PlaybackSettings {
+    "shuffle"?: boolean | null;
+    "repeatMode"?: string | null;
+    "crossfadeMs"?: number | null;
+    "gapless"?: boolean | null;
+
+    /** Creates a new PlaybackSettings instance. */
+    constructor($$source: Partial<PlaybackSettings> = {}) {
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new PlaybackSettings instance from a string or object.
+     */
+    static createFrom($$source: any = {}): PlaybackSettings {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new PlaybackSettings($$parsedSource as Partial<PlaybackSettings>);
+    }
+}
+
+export class Playlist {
+    "id": number;
+    "name": string;
+    "trackCount": number;
+    "durationMs": number;
+    "syncPath"?: string | null;
+    "folderId"?: number | null;
+    "tags": string[];
+    "playback"?: PlaybackSettings | null;
+    "createdAt": string;
+    "updatedAt": string;
+
+    /** Creates a new Playlist instance. */
+    constructor($$source: Partial<Playlist> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = 0;
+        }
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+        if (!("durationMs" in $$source)) {
+            this["durationMs"] = 0;
+        }
+        if (!("tags" in $$source)) {
+            this["tags"] = [];
+        }
+        if (!("createdAt" in $$source)) {
+            this["createdAt"] = "";
+        }
+        if (!("updatedAt" in $$source)) {
+            this["updatedAt"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Playlist instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Playlist {
+        const $$createField6_0 = $$createType3;
+        const $$createField7_0 = $$createType5;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("tags" in $$parsedSource) {
+            $$parsedSource["tags"] = $$createField6_0($$parsedSource["tags"]);
+        }
+        if ("playback" in $$parsedSource) {
+            $$parsedSource["playback"] = $$createField7_0($$parsedSource["playback"]);
+        }
+        return new Playlist($$parsedSource as Partial<Playlist>);
+    }
+}
+
+export class Tag {
+    "name": string;
+    "playlistCount": number;
+
+    /** Creates a new Tag instance. */
+    constructor($$source: Partial<Tag> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("playlistCount" in $$source)) {
+            this["playlistCount"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Tag instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Tag {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Tag($$parsedSource as Partial<Tag>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = Playlist.createFrom;
+const $$createType1 = library$0.TrackSummary.createFrom;
+const $$createType2 = $Create.Array($$createType1);
+const $$createType3 = $Create.Array($Create.Any);
+const $$createType4 = PlaybackSettings.createFrom;
+const $$createType5 = $Create.Nullable($$createType4);
@@ -0,0 +1,313 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as library$0 from "../library/models.js";
+
+/**
+ * ContextCursor is the remembered position inside an album, artist or
+ * playlist: the track that was current when the queue last played from it,
+ * and that track's position among the context's entries.
+ */
+export class ContextCursor {
+    "source": Source;
+    "trackId": number;
+    "contextIndex": number;
+    "updatedAt": string;
+
+    /** Creates a new ContextCursor instance. */
+    constructor($$source: Partial<ContextCursor> = {}) {
+        if (!("source" in $$source)) {
+            this["source"] = (new Source());
+        }
+        if (!("trackId" in $$source)) {
+            this["trackId"] = 0;
+        }
+        if (!("contextIndex" in $$source)) {
+            this["contextIndex"] = 0;
+        }
+        if (!("updatedAt" in $$source)) {
+            this["updatedAt"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ContextCursor instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ContextCursor {
+        const $$createField0_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("source" in $$parsedSource) {
+            $$parsedSource["source"] = $$createField0_0($$parsedSource["source"]);
+        }
+        return new ContextCursor($$parsedSource as Partial<ContextCursor>);
+    }
+}
+
+export class QueueImportResult {
+    "state": State;
+    "matched": number;
+    "missing": string[];
+
+    /** Creates a new QueueImportResult instance. */
+    constructor($$source: Partial<QueueImportResult> = {}) {
+        if (!("state" in $$source)) {
+            this["state"] = (new State());
+        }
+        if (!("matched" in $$source)) {
+            this["matched"] = 0;
+        }
+        if (!("missing" in $$source)) {
+            this["missing"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new QueueImportResult instance from a string or object.
+     */
+    static createFrom($$source: any = {}): QueueImportResult {
+        const $$createField0_0 = $$createType1;
+        const $$createField2_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("state" in $$parsedSource) {
+            $$parsedSource["state"] = $$createField0_0($$parsedSource["state"]);
+        }
+        if ("missing" in $$parsedSource) {
+            $$parsedSource["missing"] = $$createField2_0($$parsedSource["missing"]);
+        }
+        return new QueueImportResult($$parsedSource as Partial<QueueImportResult>);
+    }
+}
+
+export class ShuffleDebugState {
+    "sessionVersion": number;
+    "cycleVersion": number;
+    "cycleLength": number;
+    "cycleProgress": number;
+    "recentWindow": number;
+    "guardPrefix": number;
+    "trailIndices": number[];
+    "upcoming": number[];
+    "recentIndices": number[];
+
+    /** Creates a new ShuffleDebugState instance. */
+    constructor($$source: Partial<ShuffleDebugState> = {}) {
+        if (!("sessionVersion" in $$source)) {
+            this["sessionVersion"] = 0;
+        }
+        if (!("cycleVersion" in $$source)) {
+            this["cycleVersion"] = 0;
+        }
+        if (!("cycleLength" in $$source)) {
+            this["cycleLength"] = 0;
+        }
+        if (!("cycleProgress" in $$source)) {
+            this["cycleProgress"] = 0;
+        }
+        if (!("recentWindow" in $$source)) {
+            this["recentWindow"] = 0;
+        }
+        if (!("guardPrefix" in $$source)) {
+            this["guardPrefix"] = 0;
+        }
+        if (!("trailIndices" in $$source)) {
+            this["trailIndices"] = [];
+        }
+        if (!("upcoming" in $$source)) {
+            this["upcoming"] = [];
+        }
+        if (!("recentIndices" in $$source)) {
+            this["recentIndices"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ShuffleDebugState instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ShuffleDebugState {
+        const $$createField6_0 = $$createType3;
+        const $$createField7_0 = $$createType3;
+        const $$createField8_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("trailIndices" in $$parsedSource) {
+            $$parsedSource["trailIndices"] = $$createField6_0($$parsedSource["trailIndices"]);
+        }
+        if ("upcoming" in $$parsedSource) {
+            $$parsedSource["upcoming"] = $$createField7_0($$parsedSource["upcoming"]);
+        }
+        if ("recentIndices" in $$parsedSource) {
+            $$parsedSource["recentIndices"] = $$createField8_0($$parsedSource["recentIndices"]);
+        }
+        return new ShuffleDebugState($$parsedSource as Partial<ShuffleDebugState>);
+    }
+}
+
+/**
+ * Source records where a queue entry came from so the UI can navigate back
+ * to it. Only the fields of the source's kind are set.
+ */
+export class Source {
+    "kind": string;
+    "albumTitle"?: string;
+    "albumArtist"?: string;
+    "artistName"?: string;
+    "playlistId"?: number;
+    "query"?: string;
+
+    /** Creates a new Source instance. */
+    constructor($$source: Partial<Source> = {}) {
+        if (!("kind" in $$source)) {
+            this["kind"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Source instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Source {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Source($$parsedSource as Partial<Source>);
+    }
+}
+
+/**
+ * SourceLocation is a resolved "go to source" target. Available is false
+ * when the album, artist or playlist no longer exists; TrackPosition is the
+ * track's zero-based position in a playlist source, or -1.
+ */
+export class SourceLocation {
+    "source": Source;
+    "trackId": number;
+    "available": boolean;
+    "trackPosition": number;
+
+    /** Creates a new SourceLocation instance. */
+    constructor($$source: Partial<SourceLocation> = {}) {
+        if (!("source" in $$source)) {
+            this["source"] = (new Source());
+        }
+        if (!("trackId" in $$source)) {
+            this["trackId"] = 0;
+        }
+        if (!("available" in $$source)) {
+            this["available"] = false;
+        }
+        if (!("trackPosition" in $$source)) {
+            this["trackPosition"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SourceLocation instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SourceLocation {
+        const $$createField0_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("source" in $$parsedSource) {
+            $$parsedSource["source"] = $$createField0_0($$parsedSource["source"]);
+        }
+        return new SourceLocation($$parsedSource as Partial<SourceLocation>);
+    }
+}
+
+export class State {
+    "entries": library$0.TrackSummary[];
+    "sources": Source[];
+    "currentIndex": number;
+    "currentTrack"?: library$0.TrackSummary | null;
+    "repeatMode": string;
+    "shuffle": boolean;
+    "shuffleVideos": boolean;
+    "albumContextUpNext": boolean;
+    "preferBestVersion": boolean;
+    "shuffleDebug"?: ShuffleDebugState | null;
+    "total": number;
+    "updatedAt": string;
+
+    /** Creates a new State instance. */
+    constructor($$source: Partial<State> = {}) {
+        if (!("entries" in $$source)) {
+            this["entries"] = [];
+        }
+        if (!("sources" in $$source)) {
+            this["sources"] = [];
+        }
+        if (!("currentIndex" in $$source)) {
+            this["currentIndex"] = 0;
+        }
+        if (!("repeatMode" in $$source)) {
+            this["repeatMode"] = "";
+        }
+        if (!("shuffle" in $$source)) {
+            this["shuffle"] = false;
+        }
+        if (!("shuffleVideos" in $$source)) {
+            this["shuffleVideos"] = false;
+        }
+        if (!("albumContextUpNext" in $$source)) {
+            this["albumContextUpNext"] = false;
+        }
+        if (!("preferBestVersion" in $$source)) {
+            this["preferBestVersion"] = false;
+        }
+        if (!("total" in $$source)) {
+            this["total"] = 0;
+        }
+        if (!("updatedAt" in $$source)) {
+            this["updatedAt"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new State instance from a string or object.
+     */
+    static createFrom($$source: any = {}): State {
+        const $$createField0_0 = $$createType5;
+        const $$createField1_0 = $$createType6;
+        const $$createField3_0 = $$createType7;
+        const $$createField9_0 = $$createType9;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("entries" in $$parsedSource) {
+            $$parsedSource["entries"] = $$createField0_0($$parsedSource["entries"]);
+        }
+        if ("sources" in $$parsedSource) {
+            $$parsedSource["sources"] = $$createField1_0($$parsedSource["sources"]);
+        }
+        if ("currentTrack" in $$parsedSource) {
+            $$parsedSource["currentTrack"] = $$createField3_0($$parsedSource["currentTrack"]);
+        }
+        if ("shuffleDebug" in $$parsedSource) {
+            $$parsedSource["shuffleDebug"] = $$createField9_0($$parsedSource["shuffleDebug"]);
+        }
+        return new State($$parsedSource as Partial<State>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = Source.createFrom;
+const $$createType1 = State.createFrom;
+const $$createType2 = $Create.Array($Create.Any);
+const $$createType3 = $Create.Array($Create.Any);
+const $$createType4 = library$0.TrackSummary.createFrom;
+const $$createType5 = $Create.Array($$createType4);
+const $$createType6 = $Create.Array($$createType0);
+const $$createType7 = $Create.Nullable($$createType4);
+const $$createType8 = ShuffleDebugState.createFrom;
+const $$createType9 = $Create.Nullable($$createType8);
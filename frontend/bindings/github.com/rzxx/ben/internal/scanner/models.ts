@@ -0,0 +1,602 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * AlbumChange sums up what scans changed in one album. A track that
+ * changed in several scans is listed once per scan.
+ */
+export class AlbumChange {
+    "album": string;
+    "albumArtist": string;
+    "lastScanId": number;
+    "changedAt": string;
+    "added": ChangedTrack[];
+    "removed": ChangedTrack[];
+    "updated": ChangedTrack[];
+    "coverChanged": boolean;
+
+    /** Creates a new AlbumChange instance. */
+    constructor($$source: Partial<AlbumChange> = {}) {
+        if (!("album" in $$source)) {
+            this["album"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+        if (!("lastScanId" in $$source)) {
+            this["lastScanId"] = 0;
+        }
+        if (!("changedAt" in $$source)) {
+            this["changedAt"] = "";
+        }
+        if (!("added" in $$source)) {
+            this["added"] = [];
+        }
+        if (!("removed" in $$source)) {
+            this["removed"] = [];
+        }
+        if (!("updated" in $$source)) {
+            this["updated"] = [];
+        }
+        if (!("coverChanged" in $$source)) {
+            this["coverChanged"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AlbumChange instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AlbumChange {
+        const $$createField4_0 = $$createType1;
+        const $$createField5_0 = $$createType1;
+        const $$createField6_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("added" in $$parsedSource) {
+            $$parsedSource["added"] = $$createField4_0($$parsedSource["added"]);
+        }
+        if ("removed" in $$parsedSource) {
+            $$parsedSource["removed"] = $$createField5_0($$parsedSource["removed"]);
+        }
+        if ("updated" in $$parsedSource) {
+            $$parsedSource["updated"] = $$createField6_0($$parsedSource["updated"]);
+        }
+        return new AlbumChange($$parsedSource as Partial<AlbumChange>);
+    }
+}
+
+/**
+ * AlbumCoverChange is an album and the cache path of its new cover, empty
+ * when the album no longer has one.
+ */
+export class AlbumCoverChange {
+    "album": string;
+    "albumArtist": string;
+    "coverPath": string;
+
+    /** Creates a new AlbumCoverChange instance. */
+    constructor($$source: Partial<AlbumCoverChange> = {}) {
+        if (!("album" in $$source)) {
+            this["album"] = "";
+        }
+        if (!("albumArtist" in $$source)) {
+            this["albumArtist"] = "";
+        }
+        if (!("coverPath" in $$source)) {
+            this["coverPath"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AlbumCoverChange instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AlbumCoverChange {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new AlbumCoverChange($$parsedSource as Partial<AlbumCoverChange>);
+    }
+}
+
+/**
+ * AutoImportConfig describes the auto-import folder. An empty Folder
+ * disables it. Imported files go to the watched root RootID, either at the
+ * same relative path or, with Organize, at the path Pattern builds from
+ * their tags.
+ */
+export class AutoImportConfig {
+    "folder": string;
+    "rootId": number;
+    "organize": boolean;
+    "pattern": string;
+
+    /** Creates a new AutoImportConfig instance. */
+    constructor($$source: Partial<AutoImportConfig> = {}) {
+        if (!("folder" in $$source)) {
+            this["folder"] = "";
+        }
+        if (!("rootId" in $$source)) {
+            this["rootId"] = 0;
+        }
+        if (!("organize" in $$source)) {
+            this["organize"] = false;
+        }
+        if (!("pattern" in $$source)) {
+            this["pattern"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AutoImportConfig instance from a string or object.
+     */
+    static createFrom($$source: any = {}): AutoImportConfig {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new AutoImportConfig($$parsedSource as Partial<AutoImportConfig>);
+    }
+}
+
+export class ChangedTrack {
+    "trackId": number;
+    "title": string;
+    "artist": string;
+
+    /** Creates a new ChangedTrack instance. */
+    constructor($$source: Partial<ChangedTrack> = {}) {
+        if (!("trackId" in $$source)) {
+            this["trackId"] = 0;
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("artist" in $$source)) {
+            this["artist"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ChangedTrack instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ChangedTrack {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ChangedTrack($$parsedSource as Partial<ChangedTrack>);
+    }
+}
+
+/**
+ * ChecksumReport counts the outcome of a verification run. Stored files got
+ * their first checksum, or a new one because they were rewritten since.
+ */
+export class ChecksumReport {
+    "checked": number;
+    "stored": number;
+    "verified": number;
+    "unreadable": number;
+    "mismatches": CorruptedChecksum[];
+
+    /** Creates a new ChecksumReport instance. */
+    constructor($$source: Partial<ChecksumReport> = {}) {
+        if (!("checked" in $$source)) {
+            this["checked"] = 0;
+        }
+        if (!("stored" in $$source)) {
+            this["stored"] = 0;
+        }
+        if (!("verified" in $$source)) {
+            this["verified"] = 0;
+        }
+        if (!("unreadable" in $$source)) {
+            this["unreadable"] = 0;
+        }
+        if (!("mismatches" in $$source)) {
+            this["mismatches"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ChecksumReport instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ChecksumReport {
+        const $$createField4_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("mismatches" in $$parsedSource) {
+            $$parsedSource["mismatches"] = $$createField4_0($$parsedSource["mismatches"]);
+        }
+        return new ChecksumReport($$parsedSource as Partial<ChecksumReport>);
+    }
+}
+
+/**
+ * ChecksumScope limits a verification run to one root, to the files under
+ * Path, or both. The zero scope covers every present file.
+ */
+export class ChecksumScope {
+    "rootId": number;
+    "path": string;
+
+    /** Creates a new ChecksumScope instance. */
+    constructor($$source: Partial<ChecksumScope> = {}) {
+        if (!("rootId" in $$source)) {
+            this["rootId"] = 0;
+        }
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ChecksumScope instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ChecksumScope {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ChecksumScope($$parsedSource as Partial<ChecksumScope>);
+    }
+}
+
+/**
+ * CorruptedChecksum is a file whose content no longer matches its stored
+ * checksum.
+ */
+export class CorruptedChecksum {
+    "fileId": number;
+    "trackId"?: number;
+    "path": string;
+    "title": string;
+    "artist": string;
+    "checksum": string;
+    "actualChecksum": string;
+    "computedAt": string;
+    "verifiedAt": string;
+
+    /** Creates a new CorruptedChecksum instance. */
+    constructor($$source: Partial<CorruptedChecksum> = {}) {
+        if (!("fileId" in $$source)) {
+            this["fileId"] = 0;
+        }
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("artist" in $$source)) {
+            this["artist"] = "";
+        }
+        if (!("checksum" in $$source)) {
+            this["checksum"] = "";
+        }
+        if (!("actualChecksum" in $$source)) {
+            this["actualChecksum"] = "";
+        }
+        if (!("computedAt" in $$source)) {
+            this["computedAt"] = "";
+        }
+        if (!("verifiedAt" in $$source)) {
+            this["verifiedAt"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new CorruptedChecksum instance from a string or object.
+     */
+    static createFrom($$source: any = {}): CorruptedChecksum {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new CorruptedChecksum($$parsedSource as Partial<CorruptedChecksum>);
+    }
+}
+
+export class ImportFailure {
+    "path": string;
+    "error": string;
+
+    /** Creates a new ImportFailure instance. */
+    constructor($$source: Partial<ImportFailure> = {}) {
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("error" in $$source)) {
+            this["error"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ImportFailure instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ImportFailure {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ImportFailure($$parsedSource as Partial<ImportFailure>);
+    }
+}
+
+export class ImportResult {
+    "imported": ImportedFile[];
+    "failed": ImportFailure[];
+    "at": string;
+
+    /** Creates a new ImportResult instance. */
+    constructor($$source: Partial<ImportResult> = {}) {
+        if (!("imported" in $$source)) {
+            this["imported"] = [];
+        }
+        if (!("failed" in $$source)) {
+            this["failed"] = [];
+        }
+        if (!("at" in $$source)) {
+            this["at"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ImportResult instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ImportResult {
+        const $$createField0_0 = $$createType5;
+        const $$createField1_0 = $$createType7;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("imported" in $$parsedSource) {
+            $$parsedSource["imported"] = $$createField0_0($$parsedSource["imported"]);
+        }
+        if ("failed" in $$parsedSource) {
+            $$parsedSource["failed"] = $$createField1_0($$parsedSource["failed"]);
+        }
+        return new ImportResult($$parsedSource as Partial<ImportResult>);
+    }
+}
+
+export class ImportedFile {
+    "from": string;
+    "to": string;
+
+    /** Creates a new ImportedFile instance. */
+    constructor($$source: Partial<ImportedFile> = {}) {
+        if (!("from" in $$source)) {
+            this["from"] = "";
+        }
+        if (!("to" in $$source)) {
+            this["to"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ImportedFile instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ImportedFile {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ImportedFile($$parsedSource as Partial<ImportedFile>);
+    }
+}
+
+/**
+ * LibraryChanges lists the albums that scans after a given scan changed.
+ * LatestScanID is the newest scan with changes; pass it to the next call to
+ * only see what changed since.
+ */
+export class LibraryChanges {
+    "latestScanId": number;
+    "albums": AlbumChange[];
+
+    /** Creates a new LibraryChanges instance. */
+    constructor($$source: Partial<LibraryChanges> = {}) {
+        if (!("latestScanId" in $$source)) {
+            this["latestScanId"] = 0;
+        }
+        if (!("albums" in $$source)) {
+            this["albums"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new LibraryChanges instance from a string or object.
+     */
+    static createFrom($$source: any = {}): LibraryChanges {
+        const $$createField1_0 = $$createType9;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("albums" in $$parsedSource) {
+            $$parsedSource["albums"] = $$createField1_0($$parsedSource["albums"]);
+        }
+        return new LibraryChanges($$parsedSource as Partial<LibraryChanges>);
+    }
+}
+
+/**
+ * Progress is the payload of EventProgress. While local folders are
+ * scanned it also carries per-root counts, the file being processed, the
+ * throughput in files per second and the estimated seconds remaining.
+ */
+export class Progress {
+    "phase": string;
+    "message": string;
+    "percent": number;
+    "status": string;
+    "at": string;
+    "roots"?: RootProgress[];
+    "currentPath"?: string;
+    "filesPerSecond"?: number;
+    "etaSeconds"?: number | null;
+
+    /** Creates a new Progress instance. */
+    constructor($$source: Partial<Progress> = {}) {
+        if (!("phase" in $$source)) {
+            this["phase"] = "";
+        }
+        if (!("message" in $$source)) {
+            this["message"] = "";
+        }
+        if (!("percent" in $$source)) {
+            this["percent"] = 0;
+        }
+        if (!("status" in $$source)) {
+            this["status"] = "";
+        }
+        if (!("at" in $$source)) {
+            this["at"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Progress instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Progress {
+        const $$createField5_0 = $$createType11;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("roots" in $$parsedSource) {
+            $$parsedSource["roots"] = $$createField5_0($$parsedSource["roots"]);
+        }
+        return new Progress($$parsedSource as Partial<Progress>);
+    }
+}
+
+/**
+ * RootProgress is the progress of a scan through one watched folder.
+ * Discovered is the number of audio files and archives a quick walk found
+ * before the scan; Remaining is what is left of them.
+ */
+export class RootProgress {
+    "rootId": number;
+    "path": string;
+    "discovered": number;
+    "processed": number;
+    "remaining": number;
+    "done": boolean;
+
+    /** Creates a new RootProgress instance. */
+    constructor($$source: Partial<RootProgress> = {}) {
+        if (!("rootId" in $$source)) {
+            this["rootId"] = 0;
+        }
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("discovered" in $$source)) {
+            this["discovered"] = 0;
+        }
+        if (!("processed" in $$source)) {
+            this["processed"] = 0;
+        }
+        if (!("remaining" in $$source)) {
+            this["remaining"] = 0;
+        }
+        if (!("done" in $$source)) {
+            this["done"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new RootProgress instance from a string or object.
+     */
+    static createFrom($$source: any = {}): RootProgress {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new RootProgress($$parsedSource as Partial<RootProgress>);
+    }
+}
+
+/**
+ * Status describes the scanner. LastStatus is how the last scan ended:
+ * "completed", "failed" or "canceled".
+ */
+export class Status {
+    "running": boolean;
+    "lastRunAt": string;
+    "lastMode"?: string;
+    "lastStatus"?: string;
+    "lastError"?: string;
+    "lastFilesSeen": number;
+    "lastIndexed": number;
+    "lastSkipped": number;
+
+    /** Creates a new Status instance. */
+    constructor($$source: Partial<Status> = {}) {
+        if (!("running" in $$source)) {
+            this["running"] = false;
+        }
+        if (!("lastRunAt" in $$source)) {
+            this["lastRunAt"] = "";
+        }
+        if (!("lastFilesSeen" in $$source)) {
+            this["lastFilesSeen"] = 0;
+        }
+        if (!("lastIndexed" in $$source)) {
+            this["lastIndexed"] = 0;
+        }
+        if (!("lastSkipped" in $$source)) {
+            this["lastSkipped"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Status instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Status {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Status($$parsedSource as Partial<Status>);
+    }
+}
+
+/**
+ * WatcherStatus reports whether library folders are watched for changes and
+ * the last error the watcher ran into, if any.
+ */
+export class WatcherStatus {
+    "watching": boolean;
+    "lastError"?: string;
+    "lastErrorAt"?: string;
+
+    /** Creates a new WatcherStatus instance. */
+    constructor($$source: Partial<WatcherStatus> = {}) {
+        if (!("watching" in $$source)) {
+            this["watching"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new WatcherStatus instance from a string or object.
+     */
+    static createFrom($$source: any = {}): WatcherStatus {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new WatcherStatus($$parsedSource as Partial<WatcherStatus>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = ChangedTrack.createFrom;
+const $$createType1 = $Create.Array($$createType0);
+const $$createType2 = CorruptedChecksum.createFrom;
+const $$createType3 = $Create.Array($$createType2);
+const $$createType4 = ImportedFile.createFrom;
+const $$createType5 = $Create.Array($$createType4);
+const $$createType6 = ImportFailure.createFrom;
+const $$createType7 = $Create.Array($$createType6);
+const $$createType8 = AlbumChange.createFrom;
+const $$createType9 = $Create.Array($$createType8);
+const $$createType10 = RootProgress.createFrom;
+const $$createType11 = $Create.Array($$createType10);
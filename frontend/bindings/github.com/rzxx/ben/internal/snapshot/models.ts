@@ -0,0 +1,55 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * Info describes the snapshot taken before a bulk operation. Kind is set
+ * for operations that changed files, which a Restorer reverts.
+ */
+export class Info {
+    "id": number;
+    "label": string;
+    "tables": string[];
+    "kind"?: string;
+    "createdAt": string;
+    "expiresAt": string;
+
+    /** Creates a new Info instance. */
+    constructor($$source: Partial<Info> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = 0;
+        }
+        if (!("label" in $$source)) {
+            this["label"] = "";
+        }
+        if (!("tables" in $$source)) {
+            this["tables"] = [];
+        }
+        if (!("createdAt" in $$source)) {
+            this["createdAt"] = "";
+        }
+        if (!("expiresAt" in $$source)) {
+            this["expiresAt"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Info instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Info {
+        const $$createField2_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("tables" in $$parsedSource) {
+            $$parsedSource["tables"] = $$createField2_0($$parsedSource["tables"]);
+        }
+        return new Info($$parsedSource as Partial<Info>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = $Create.Array($Create.Any);
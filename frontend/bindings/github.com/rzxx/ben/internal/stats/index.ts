@@ -6,15 +6,25 @@ export {
     ArtistStat,
     Dashboard,
     DashboardDiscovery,
+    DashboardLayout,
+    DashboardModule,
     DashboardQuality,
     DashboardSummary,
     GenreStat,
     HeatmapDay,
     HourStat,
+    ListeningImportResult,
+    ListeningPair,
+    ListeningSession,
     ListeningStreak,
     Overview,
     ReplayTrackStat,
+    SessionContext,
+    SessionLabelStat,
+    SessionLabelState,
     SessionStats,
     TrackStat,
+    TrackVersionStats,
+    TrimSuggestion,
     WeekdayStat
 } from "./models.js";
@@ -5,6 +5,10 @@
 // @ts-ignore: Unused imports
 import { Create as $Create } from "@wailsio/runtime";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as queue$0 from "../queue/models.js";
+
 export class AlbumStat {
     "title": string;
     "albumArtist": string;
@@ -74,6 +78,7 @@ export class ArtistStat {
 
 export class Dashboard {
     "range": string;
+    "modules": string[];
     "windowStart"?: string | null;
     "generatedAt": string;
     "summary": DashboardSummary;
@@ -91,13 +96,18 @@ export class Dashboard {
     "peakHour": number;
     "peakWeekday": number;
     "session": SessionStats;
+    "sessionLabels": SessionLabelStat[];
     "behaviorWindowDays": number;
+    "collapseVersions"?: string;
 
     /** Creates a new Dashboard instance. */
     constructor($$source: Partial<Dashboard> = {}) {
         if (!("range" in $$source)) {
             this["range"] = "";
         }
+        if (!("modules" in $$source)) {
+            this["modules"] = [];
+        }
         if (!("generatedAt" in $$source)) {
             this["generatedAt"] = "";
         }
@@ -146,6 +156,9 @@ export class Dashboard {
         if (!("session" in $$source)) {
             this["session"] = (new SessionStats());
         }
+        if (!("sessionLabels" in $$source)) {
+            this["sessionLabels"] = [];
+        }
         if (!("behaviorWindowDays" in $$source)) {
             this["behaviorWindowDays"] = 0;
         }
@@ -157,58 +170,66 @@ export class Dashboard {
      * Creates a new Dashboard instance from a string or object.
      */
     static createFrom($$source: any = {}): Dashboard {
-        const $$createField3_0 = $$createType0;
+        const $$createField1_0 = $$createType0;
         const $$createField4_0 = $$createType1;
         const $$createField5_0 = $$createType2;
         const $$createField6_0 = $$createType3;
-        const $$createField7_0 = $$createType5;
-        const $$createField8_0 = $$createType7;
-        const $$createField9_0 = $$createType9;
-        const $$createField10_0 = $$createType11;
-        const $$createField11_0 = $$createType13;
-        const $$createField12_0 = $$createType15;
-        const $$createField13_0 = $$createType17;
-        const $$createField14_0 = $$createType19;
-        const $$createField17_0 = $$createType20;
+        const $$createField7_0 = $$createType4;
+        const $$createField8_0 = $$createType6;
+        const $$createField9_0 = $$createType8;
+        const $$createField10_0 = $$createType10;
+        const $$createField11_0 = $$createType12;
+        const $$createField12_0 = $$createType14;
+        const $$createField13_0 = $$createType16;
+        const $$createField14_0 = $$createType18;
+        const $$createField15_0 = $$createType20;
+        const $$createField18_0 = $$createType21;
+        const $$createField19_0 = $$createType23;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("modules" in $$parsedSource) {
+            $$parsedSource["modules"] = $$createField1_0($$parsedSource["modules"]);
+        }
         if ("summary" in $$parsedSource) {
-            $$parsedSource["summary"] = $$createField3_0($$parsedSource["summary"]);
+            $$parsedSource["summary"] = $$createField4_0($$parsedSource["summary"]);
         }
         if ("quality" in $$parsedSource) {
-            $$parsedSource["quality"] = $$createField4_0($$parsedSource["quality"]);
+            $$parsedSource["quality"] = $$createField5_0($$parsedSource["quality"]);
         }
         if ("discovery" in $$parsedSource) {
-            $$parsedSource["discovery"] = $$createField5_0($$parsedSource["discovery"]);
+            $$parsedSource["discovery"] = $$createField6_0($$parsedSource["discovery"]);
         }
         if ("streak" in $$parsedSource) {
-            $$parsedSource["streak"] = $$createField6_0($$parsedSource["streak"]);
+            $$parsedSource["streak"] = $$createField7_0($$parsedSource["streak"]);
         }
         if ("heatmap" in $$parsedSource) {
-            $$parsedSource["heatmap"] = $$createField7_0($$parsedSource["heatmap"]);
+            $$parsedSource["heatmap"] = $$createField8_0($$parsedSource["heatmap"]);
         }
         if ("topTracks" in $$parsedSource) {
-            $$parsedSource["topTracks"] = $$createField8_0($$parsedSource["topTracks"]);
+            $$parsedSource["topTracks"] = $$createField9_0($$parsedSource["topTracks"]);
         }
         if ("topArtists" in $$parsedSource) {
-            $$parsedSource["topArtists"] = $$createField9_0($$parsedSource["topArtists"]);
+            $$parsedSource["topArtists"] = $$createField10_0($$parsedSource["topArtists"]);
         }
         if ("topAlbums" in $$parsedSource) {
-            $$parsedSource["topAlbums"] = $$createField10_0($$parsedSource["topAlbums"]);
+            $$parsedSource["topAlbums"] = $$createField11_0($$parsedSource["topAlbums"]);
         }
         if ("topGenres" in $$parsedSource) {
-            $$parsedSource["topGenres"] = $$createField11_0($$parsedSource["topGenres"]);
+            $$parsedSource["topGenres"] = $$createField12_0($$parsedSource["topGenres"]);
         }
         if ("replayTracks" in $$parsedSource) {
-            $$parsedSource["replayTracks"] = $$createField12_0($$parsedSource["replayTracks"]);
+            $$parsedSource["replayTracks"] = $$createField13_0($$parsedSource["replayTracks"]);
         }
         if ("hourlyProfile" in $$parsedSource) {
-            $$parsedSource["hourlyProfile"] = $$createField13_0($$parsedSource["hourlyProfile"]);
+            $$parsedSource["hourlyProfile"] = $$createField14_0($$parsedSource["hourlyProfile"]);
         }
         if ("weekdayProfile" in $$parsedSource) {
-            $$parsedSource["weekdayProfile"] = $$createField14_0($$parsedSource["weekdayProfile"]);
+            $$parsedSource["weekdayProfile"] = $$createField15_0($$parsedSource["weekdayProfile"]);
         }
         if ("session" in $$parsedSource) {
-            $$parsedSource["session"] = $$createField17_0($$parsedSource["session"]);
+            $$parsedSource["session"] = $$createField18_0($$parsedSource["session"]);
+        }
+        if ("sessionLabels" in $$parsedSource) {
+            $$parsedSource["sessionLabels"] = $$createField19_0($$parsedSource["sessionLabels"]);
         }
         return new Dashboard($$parsedSource as Partial<Dashboard>);
     }
@@ -251,6 +272,61 @@ export class DashboardDiscovery {
     }
 }
 
+/**
+ * DashboardLayout lists the enabled modules in display order. Modules that
+ * are left out are not queried.
+ */
+export class DashboardLayout {
+    "modules": DashboardModule[];
+
+    /** Creates a new DashboardLayout instance. */
+    constructor($$source: Partial<DashboardLayout> = {}) {
+        if (!("modules" in $$source)) {
+            this["modules"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DashboardLayout instance from a string or object.
+     */
+    static createFrom($$source: any = {}): DashboardLayout {
+        const $$createField0_0 = $$createType25;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("modules" in $$parsedSource) {
+            $$parsedSource["modules"] = $$createField0_0($$parsedSource["modules"]);
+        }
+        return new DashboardLayout($$parsedSource as Partial<DashboardLayout>);
+    }
+}
+
+/**
+ * DashboardModule is one enabled dashboard module. Limit sets the length of
+ * top lists; zero uses the limit passed to GetDashboard.
+ */
+export class DashboardModule {
+    "id": string;
+    "limit"?: number;
+
+    /** Creates a new DashboardModule instance. */
+    constructor($$source: Partial<DashboardModule> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DashboardModule instance from a string or object.
+     */
+    static createFrom($$source: any = {}): DashboardModule {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new DashboardModule($$parsedSource as Partial<DashboardModule>);
+    }
+}
+
 export class DashboardQuality {
     "score": number;
 
@@ -428,6 +504,159 @@ export class HourStat {
     }
 }
 
+/**
+ * ListeningImportResult summarizes an imported listening log. Plays already
+ * imported from the same device are counted as duplicates, so a log can be
+ * imported again after the device appended to it.
+ */
+export class ListeningImportResult {
+    "device": string;
+    "imported": number;
+    "duplicates": number;
+    "unmatched": number;
+    "invalid": number;
+    "unmatchedSamples": string[];
+
+    /** Creates a new ListeningImportResult instance. */
+    constructor($$source: Partial<ListeningImportResult> = {}) {
+        if (!("device" in $$source)) {
+            this["device"] = "";
+        }
+        if (!("imported" in $$source)) {
+            this["imported"] = 0;
+        }
+        if (!("duplicates" in $$source)) {
+            this["duplicates"] = 0;
+        }
+        if (!("unmatched" in $$source)) {
+            this["unmatched"] = 0;
+        }
+        if (!("invalid" in $$source)) {
+            this["invalid"] = 0;
+        }
+        if (!("unmatchedSamples" in $$source)) {
+            this["unmatchedSamples"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ListeningImportResult instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ListeningImportResult {
+        const $$createField5_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("unmatchedSamples" in $$parsedSource) {
+            $$parsedSource["unmatchedSamples"] = $$createField5_0($$parsedSource["unmatchedSamples"]);
+        }
+        return new ListeningImportResult($$parsedSource as Partial<ListeningImportResult>);
+    }
+}
+
+/**
+ * ListeningPair reports that To was played right after From in Sessions
+ * listening sessions. For artist pairs the artist fields repeat the names.
+ */
+export class ListeningPair {
+    "kind": string;
+    "from": string;
+    "fromArtist": string;
+    "to": string;
+    "toArtist": string;
+    "sessions": number;
+    "lastPlayedAt": string;
+
+    /** Creates a new ListeningPair instance. */
+    constructor($$source: Partial<ListeningPair> = {}) {
+        if (!("kind" in $$source)) {
+            this["kind"] = "";
+        }
+        if (!("from" in $$source)) {
+            this["from"] = "";
+        }
+        if (!("fromArtist" in $$source)) {
+            this["fromArtist"] = "";
+        }
+        if (!("to" in $$source)) {
+            this["to"] = "";
+        }
+        if (!("toArtist" in $$source)) {
+            this["toArtist"] = "";
+        }
+        if (!("sessions" in $$source)) {
+            this["sessions"] = 0;
+        }
+        if (!("lastPlayedAt" in $$source)) {
+            this["lastPlayedAt"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ListeningPair instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ListeningPair {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ListeningPair($$parsedSource as Partial<ListeningPair>);
+    }
+}
+
+/**
+ * ListeningSession is a recorded session: plays with no gap longer than the
+ * dashboard's session gap. EndedAt is when its last track started. Weekday is numbered like SQLite's %w and, with
+ * PartOfDay, lets the UI name it like "Friday evening".
+ */
+export class ListeningSession {
+    "id": number;
+    "startedAt": string;
+    "endedAt": string;
+    "weekday": number;
+    "partOfDay": string;
+    "trackCount": number;
+    "contexts": SessionContext[];
+
+    /** Creates a new ListeningSession instance. */
+    constructor($$source: Partial<ListeningSession> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = 0;
+        }
+        if (!("startedAt" in $$source)) {
+            this["startedAt"] = "";
+        }
+        if (!("endedAt" in $$source)) {
+            this["endedAt"] = "";
+        }
+        if (!("weekday" in $$source)) {
+            this["weekday"] = 0;
+        }
+        if (!("partOfDay" in $$source)) {
+            this["partOfDay"] = "";
+        }
+        if (!("trackCount" in $$source)) {
+            this["trackCount"] = 0;
+        }
+        if (!("contexts" in $$source)) {
+            this["contexts"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ListeningSession instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ListeningSession {
+        const $$createField6_0 = $$createType27;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("contexts" in $$parsedSource) {
+            $$parsedSource["contexts"] = $$createField6_0($$parsedSource["contexts"]);
+        }
+        return new ListeningSession($$parsedSource as Partial<ListeningSession>);
+    }
+}
+
 export class ListeningStreak {
     "currentDays": number;
     "longestDays": number;
@@ -494,8 +723,8 @@ export class Overview {
      * Creates a new Overview instance from a string or object.
      */
     static createFrom($$source: any = {}): Overview {
-        const $$createField5_0 = $$createType7;
-        const $$createField6_0 = $$createType9;
+        const $$createField5_0 = $$createType8;
+        const $$createField6_0 = $$createType10;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
         if ("topTracks" in $$parsedSource) {
             $$parsedSource["topTracks"] = $$createField5_0($$parsedSource["topTracks"]);
@@ -557,6 +786,102 @@ export class ReplayTrackStat {
     }
 }
 
+/**
+ * SessionContext is one stretch of a session played from the same source,
+ * such as an album or a playlist.
+ */
+export class SessionContext {
+    "source": queue$0.Source;
+    "label": string;
+    "startedAt": string;
+
+    /** Creates a new SessionContext instance. */
+    constructor($$source: Partial<SessionContext> = {}) {
+        if (!("source" in $$source)) {
+            this["source"] = (new queue$0.Source());
+        }
+        if (!("label" in $$source)) {
+            this["label"] = "";
+        }
+        if (!("startedAt" in $$source)) {
+            this["startedAt"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SessionContext instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SessionContext {
+        const $$createField0_0 = $$createType28;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("source" in $$parsedSource) {
+            $$parsedSource["source"] = $$createField0_0($$parsedSource["source"]);
+        }
+        return new SessionContext($$parsedSource as Partial<SessionContext>);
+    }
+}
+
+export class SessionLabelStat {
+    "label": string;
+    "playedMs": number;
+    "playCount": number;
+    "share": number;
+
+    /** Creates a new SessionLabelStat instance. */
+    constructor($$source: Partial<SessionLabelStat> = {}) {
+        if (!("label" in $$source)) {
+            this["label"] = "";
+        }
+        if (!("playedMs" in $$source)) {
+            this["playedMs"] = 0;
+        }
+        if (!("playCount" in $$source)) {
+            this["playCount"] = 0;
+        }
+        if (!("share" in $$source)) {
+            this["share"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SessionLabelStat instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SessionLabelStat {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new SessionLabelStat($$parsedSource as Partial<SessionLabelStat>);
+    }
+}
+
+/**
+ * SessionLabelState describes the label attached to plays recorded right now.
+ */
+export class SessionLabelState {
+    "label"?: string;
+    "active": boolean;
+    "startedAt"?: string | null;
+
+    /** Creates a new SessionLabelState instance. */
+    constructor($$source: Partial<SessionLabelState> = {}) {
+        if (!("active" in $$source)) {
+            this["active"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SessionLabelState instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SessionLabelState {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new SessionLabelState($$parsedSource as Partial<SessionLabelState>);
+    }
+}
+
 export class SessionStats {
     "sessionCount": number;
     "totalPlayedMs": number;
@@ -600,6 +925,7 @@ export class TrackStat {
     "completeCount": number;
     "skipCount": number;
     "partialCount": number;
+    "versionCount"?: number;
 
     /** Creates a new TrackStat instance. */
     constructor($$source: Partial<TrackStat> = {}) {
@@ -640,6 +966,98 @@ export class TrackStat {
     }
 }
 
+export class TrackVersionStats {
+    "canonicalTrackId": number;
+    "combined": boolean;
+    "totals": TrackStat;
+    "versions": TrackStat[];
+
+    /** Creates a new TrackVersionStats instance. */
+    constructor($$source: Partial<TrackVersionStats> = {}) {
+        if (!("canonicalTrackId" in $$source)) {
+            this["canonicalTrackId"] = 0;
+        }
+        if (!("combined" in $$source)) {
+            this["combined"] = false;
+        }
+        if (!("totals" in $$source)) {
+            this["totals"] = (new TrackStat());
+        }
+        if (!("versions" in $$source)) {
+            this["versions"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrackVersionStats instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TrackVersionStats {
+        const $$createField2_0 = $$createType7;
+        const $$createField3_0 = $$createType8;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("totals" in $$parsedSource) {
+            $$parsedSource["totals"] = $$createField2_0($$parsedSource["totals"]);
+        }
+        if ("versions" in $$parsedSource) {
+            $$parsedSource["versions"] = $$createField3_0($$parsedSource["versions"]);
+        }
+        return new TrackVersionStats($$parsedSource as Partial<TrackVersionStats>);
+    }
+}
+
+/**
+ * TrimSuggestion proposes a start trim for a track the listener keeps
+ * seeking past the intro of.
+ */
+export class TrimSuggestion {
+    "trackId": number;
+    "title": string;
+    "artist": string;
+    "album": string;
+    "coverPath"?: string | null;
+    "durationMs"?: number | null;
+    "suggestedStartMs": number;
+    "seekCount": number;
+    "playCount": number;
+
+    /** Creates a new TrimSuggestion instance. */
+    constructor($$source: Partial<TrimSuggestion> = {}) {
+        if (!("trackId" in $$source)) {
+            this["trackId"] = 0;
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("artist" in $$source)) {
+            this["artist"] = "";
+        }
+        if (!("album" in $$source)) {
+            this["album"] = "";
+        }
+        if (!("suggestedStartMs" in $$source)) {
+            this["suggestedStartMs"] = 0;
+        }
+        if (!("seekCount" in $$source)) {
+            this["seekCount"] = 0;
+        }
+        if (!("playCount" in $$source)) {
+            this["playCount"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrimSuggestion instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TrimSuggestion {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new TrimSuggestion($$parsedSource as Partial<TrimSuggestion>);
+    }
+}
+
 export class WeekdayStat {
     "weekday": number;
     "label": string;
@@ -674,24 +1092,32 @@ export class WeekdayStat {
 }
 
 // Private type creation functions
-const $$createType0 = DashboardSummary.createFrom;
-const $$createType1 = DashboardQuality.createFrom;
-const $$createType2 = DashboardDiscovery.createFrom;
-const $$createType3 = ListeningStreak.createFrom;
-const $$createType4 = HeatmapDay.createFrom;
-const $$createType5 = $Create.Array($$createType4);
-const $$createType6 = TrackStat.createFrom;
-const $$createType7 = $Create.Array($$createType6);
-const $$createType8 = ArtistStat.createFrom;
-const $$createType9 = $Create.Array($$createType8);
-const $$createType10 = AlbumStat.createFrom;
-const $$createType11 = $Create.Array($$createType10);
-const $$createType12 = GenreStat.createFrom;
-const $$createType13 = $Create.Array($$createType12);
-const $$createType14 = ReplayTrackStat.createFrom;
-const $$createType15 = $Create.Array($$createType14);
-const $$createType16 = HourStat.createFrom;
-const $$createType17 = $Create.Array($$createType16);
-const $$createType18 = WeekdayStat.createFrom;
-const $$createType19 = $Create.Array($$createType18);
-const $$createType20 = SessionStats.createFrom;
+const $$createType0 = $Create.Array($Create.Any);
+const $$createType1 = DashboardSummary.createFrom;
+const $$createType2 = DashboardQuality.createFrom;
+const $$createType3 = DashboardDiscovery.createFrom;
+const $$createType4 = ListeningStreak.createFrom;
+const $$createType5 = HeatmapDay.createFrom;
+const $$createType6 = $Create.Array($$createType5);
+const $$createType7 = TrackStat.createFrom;
+const $$createType8 = $Create.Array($$createType7);
+const $$createType9 = ArtistStat.createFrom;
+const $$createType10 = $Create.Array($$createType9);
+const $$createType11 = AlbumStat.createFrom;
+const $$createType12 = $Create.Array($$createType11);
+const $$createType13 = GenreStat.createFrom;
+const $$createType14 = $Create.Array($$createType13);
+const $$createType15 = ReplayTrackStat.createFrom;
+const $$createType16 = $Create.Array($$createType15);
+const $$createType17 = HourStat.createFrom;
+const $$createType18 = $Create.Array($$createType17);
+const $$createType19 = WeekdayStat.createFrom;
+const $$createType20 = $Create.Array($$createType19);
+const $$createType21 = SessionStats.createFrom;
+const $$createType22 = SessionLabelStat.createFrom;
+const $$createType23 = $Create.Array($$createType22);
+const $$createType24 = DashboardModule.createFrom;
+const $$createType25 = $Create.Array($$createType24);
+const $$createType26 = SessionContext.createFrom;
+const $$createType27 = $Create.Array($$createType26);
+const $$createType28 = queue$0.Source.createFrom;
@@ -0,0 +1,105 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * Edit holds the tags to change. Nil fields keep what the files have.
+ */
+export class Edit {
+    "title"?: string | null;
+    "artist"?: string | null;
+    "album"?: string | null;
+    "trackNo"?: number | null;
+    "genre"?: string | null;
+
+    /** Creates a new Edit instance. */
+    constructor($$source: Partial<Edit> = {}) {
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Edit instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Edit {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Edit($$parsedSource as Partial<Edit>);
+    }
+}
+
+/**
+ * Failure is a track whose file could not be written.
+ */
+export class Failure {
+    "trackId": number;
+    "path": string;
+    "error": string;
+
+    /** Creates a new Failure instance. */
+    constructor($$source: Partial<Failure> = {}) {
+        if (!("trackId" in $$source)) {
+            this["trackId"] = 0;
+        }
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("error" in $$source)) {
+            this["error"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Failure instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Failure {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Failure($$parsedSource as Partial<Failure>);
+    }
+}
+
+/**
+ * Result lists the tracks whose files were written and those that failed.
+ * Changes holds what was written to each file so the edit can be undone.
+ */
+export class Result {
+    "updated": number[];
+    "failed": Failure[];
+
+    /** Creates a new Result instance. */
+    constructor($$source: Partial<Result> = {}) {
+        if (!("updated" in $$source)) {
+            this["updated"] = [];
+        }
+        if (!("failed" in $$source)) {
+            this["failed"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Result instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Result {
+        const $$createField0_0 = $$createType0;
+        const $$createField1_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("updated" in $$parsedSource) {
+            $$parsedSource["updated"] = $$createField0_0($$parsedSource["updated"]);
+        }
+        if ("failed" in $$parsedSource) {
+            $$parsedSource["failed"] = $$createField1_0($$parsedSource["failed"]);
+        }
+        return new Result($$parsedSource as Partial<Result>);
+    }
+}
+
+// Private type creation functions
+const $$createType0 = $Create.Array($Create.Any);
+const $$createType1 = Failure.createFrom;
+const $$createType2 = $Create.Array($$createType1);
@@ -0,0 +1,41 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+export class State {
+    "canUndo": boolean;
+    "canRedo": boolean;
+    "undoLabel"?: string;
+    "redoLabel"?: string;
+    "undoCount": number;
+    "redoCount": number;
+
+    /** Creates a new State instance. */
+    constructor($$source: Partial<State> = {}) {
+        if (!("canUndo" in $$source)) {
+            this["canUndo"] = false;
+        }
+        if (!("canRedo" in $$source)) {
+            this["canRedo"] = false;
+        }
+        if (!("undoCount" in $$source)) {
+            this["undoCount"] = 0;
+        }
+        if (!("redoCount" in $$source)) {
+            this["redoCount"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new State instance from a string or object.
+     */
+    static createFrom($$source: any = {}): State {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new State($$parsedSource as Partial<State>);
+    }
+}
@@ -0,0 +1,35 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as jobs$0 from "./internal/jobs/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as scanner$0 from "./internal/scanner/models.js";
+
+export function CancelJob(id: string): $CancellablePromise<void> {
+    return $Call.ByID(2680360434, id);
+}
+
+/**
+ * HandleScanProgress turns the scanner's progress events into one job per
+ * scan. Watcher events are not scans and are left to the status endpoint.
+ */
+export function HandleScanProgress(progress: scanner$0.Progress): $CancellablePromise<void> {
+    return $Call.ByID(3703388973, progress);
+}
+
+export function ListJobs(): $CancellablePromise<jobs$0.Job[]> {
+    return $Call.ByID(1748162593).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = jobs$0.Job.createFrom;
+const $$createType1 = $Create.Array($$createType0);
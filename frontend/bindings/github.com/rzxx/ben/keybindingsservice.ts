@@ -0,0 +1,38 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as keybindings$0 from "./internal/keybindings/models.js";
+
+export function ListKeybindings(): $CancellablePromise<keybindings$0.Binding[]> {
+    return $Call.ByID(308647421).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+export function ResetAllKeybindings(): $CancellablePromise<keybindings$0.Binding[]> {
+    return $Call.ByID(2563209699).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+export function ResetKeybinding(commandID: string): $CancellablePromise<keybindings$0.Binding[]> {
+    return $Call.ByID(968995871, commandID).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+export function SetKeybinding(commandID: string, keys: string[]): $CancellablePromise<keybindings$0.Binding[]> {
+    return $Call.ByID(3987998092, commandID, keys).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = keybindings$0.Binding.createFrom;
+const $$createType1 = $Create.Array($$createType0);
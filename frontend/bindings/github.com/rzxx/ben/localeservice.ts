@@ -0,0 +1,25 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as i18n$0 from "./internal/i18n/models.js";
+
+export function GetLocale(): $CancellablePromise<i18n$0.LocaleState> {
+    return $Call.ByID(3463069383).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function SetLocale(locale: string): $CancellablePromise<i18n$0.LocaleState> {
+    return $Call.ByID(2917563835, locale).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = i18n$0.LocaleState.createFrom;
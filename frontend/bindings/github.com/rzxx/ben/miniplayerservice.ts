@@ -0,0 +1,29 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as $models from "./models.js";
+
+export function GetMiniPlayerState(): $CancellablePromise<$models.MiniPlayerState> {
+    return $Call.ByID(1037980824).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+/**
+ * ToggleMiniPlayer opens the compact always-on-top player, or closes it and
+ * remembers where it was.
+ */
+export function ToggleMiniPlayer(): $CancellablePromise<$models.MiniPlayerState> {
+    return $Call.ByID(3366762009).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = $models.MiniPlayerState.createFrom;
@@ -0,0 +1,29 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as platform$0 from "./internal/platform/models.js";
+
+/**
+ * GetTrackNotifications returns when a native notification is shown for a
+ * new track.
+ */
+export function GetTrackNotifications(): $CancellablePromise<platform$0.TrackNotifications> {
+    return $Call.ByID(3432741503).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function SetTrackNotifications(config: platform$0.TrackNotifications): $CancellablePromise<platform$0.TrackNotifications> {
+    return $Call.ByID(4177812435, config).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = platform$0.TrackNotifications.createFrom;
@@ -0,0 +1,334 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as library$0 from "./internal/library/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as player$0 from "./internal/player/models.js";
+
+export function CancelSleepTimer(): $CancellablePromise<player$0.State> {
+    return $Call.ByID(3534373162).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function ClearPlaybackDiagnostics(): $CancellablePromise<void> {
+    return $Call.ByID(2423888168);
+}
+
+export function ClearTrackTrims(trackID: number): $CancellablePromise<void> {
+    return $Call.ByID(259386585, trackID);
+}
+
+export function GetAlbumMix(title: string, albumArtist: string): $CancellablePromise<library$0.AlbumMix> {
+    return $Call.ByID(3393161067, title, albumArtist).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+export function GetAlbumVolumeOffset(title: string, albumArtist: string): $CancellablePromise<library$0.AlbumVolumeOffset> {
+    return $Call.ByID(4018029098, title, albumArtist).then(($result: any) => {
+        return $$createType2($result);
+    });
+}
+
+export function GetAudioAccessibility(): $CancellablePromise<player$0.AudioAccessibility> {
+    return $Call.ByID(2502734462).then(($result: any) => {
+        return $$createType3($result);
+    });
+}
+
+export function GetBackendOptions(): $CancellablePromise<player$0.BackendOptionsStatus> {
+    return $Call.ByID(2205335162).then(($result: any) => {
+        return $$createType4($result);
+    });
+}
+
+export function GetEqualizer(): $CancellablePromise<player$0.Equalizer> {
+    return $Call.ByID(433455420).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
+export function GetEqualizerPresets(): $CancellablePromise<player$0.EqualizerPreset[]> {
+    return $Call.ByID(525679216).then(($result: any) => {
+        return $$createType7($result);
+    });
+}
+
+/**
+ * GetPlaybackDiagnostics returns the playback log of every track, or of one
+ * track when trackID is set.
+ */
+export function GetPlaybackDiagnostics(trackID: number, limit: number): $CancellablePromise<player$0.PlaybackDiagnostics> {
+    return $Call.ByID(3750240007, trackID, limit).then(($result: any) => {
+        return $$createType8($result);
+    });
+}
+
+export function GetPreviewState(): $CancellablePromise<player$0.PreviewState> {
+    return $Call.ByID(2577877245).then(($result: any) => {
+        return $$createType9($result);
+    });
+}
+
+export function GetQuietHours(): $CancellablePromise<player$0.QuietHours> {
+    return $Call.ByID(1109598875).then(($result: any) => {
+        return $$createType10($result);
+    });
+}
+
+export function GetReplayGainMode(): $CancellablePromise<string> {
+    return $Call.ByID(4078320599);
+}
+
+export function GetSampleRatePolicy(): $CancellablePromise<player$0.SampleRatePolicy> {
+    return $Call.ByID(2154216030).then(($result: any) => {
+        return $$createType11($result);
+    });
+}
+
+export function GetState(): $CancellablePromise<player$0.State> {
+    return $Call.ByID(2798124289).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function GetTrackTrims(trackID: number): $CancellablePromise<library$0.TrackTrim> {
+    return $Call.ByID(1196166456, trackID).then(($result: any) => {
+        return $$createType12($result);
+    });
+}
+
+export function GetTrackVolumeOffset(trackID: number): $CancellablePromise<library$0.TrackVolumeOffset> {
+    return $Call.ByID(2298528386, trackID).then(($result: any) => {
+        return $$createType13($result);
+    });
+}
+
+export function GetTranscodeFallback(): $CancellablePromise<player$0.TranscodeFallbackStatus> {
+    return $Call.ByID(2179398841).then(($result: any) => {
+        return $$createType14($result);
+    });
+}
+
+export function Next(): $CancellablePromise<player$0.State> {
+    return $Call.ByID(1009561457).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function Pause(): $CancellablePromise<player$0.State> {
+    return $Call.ByID(191671602).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function Play(): $CancellablePromise<player$0.State> {
+    return $Call.ByID(1808111650).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function PlayPreview(trackID: number, startMS: number, durationMS: number): $CancellablePromise<player$0.PreviewState> {
+    return $Call.ByID(3313744990, trackID, startMS, durationMS).then(($result: any) => {
+        return $$createType9($result);
+    });
+}
+
+export function PlaybackDiagnosticsEnabled(): $CancellablePromise<boolean> {
+    return $Call.ByID(397822472);
+}
+
+export function Previous(): $CancellablePromise<player$0.State> {
+    return $Call.ByID(2487521925).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function ResetBackendOptions(): $CancellablePromise<player$0.BackendOptionsStatus> {
+    return $Call.ByID(3658850445).then(($result: any) => {
+        return $$createType4($result);
+    });
+}
+
+export function Seek(positionMS: number): $CancellablePromise<player$0.State> {
+    return $Call.ByID(1479346536, positionMS).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+/**
+ * SetAlbumMixOverride forces an album on or off continuous-mix playback; a
+ * nil override returns it to tag-based detection.
+ */
+export function SetAlbumMixOverride(title: string, albumArtist: string, override: boolean | null): $CancellablePromise<library$0.AlbumMix> {
+    return $Call.ByID(2532667711, title, albumArtist, override).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+/**
+ * SetAlbumVolumeOffset stores a dB correction for every track of an album;
+ * nil clears it.
+ */
+export function SetAlbumVolumeOffset(title: string, albumArtist: string, offsetDB: number | null): $CancellablePromise<library$0.AlbumVolumeOffset> {
+    return $Call.ByID(3222622150, title, albumArtist, offsetDB).then(($result: any) => {
+        return $$createType2($result);
+    });
+}
+
+export function SetAudioAccessibility(config: player$0.AudioAccessibility): $CancellablePromise<player$0.AudioAccessibility> {
+    return $Call.ByID(3224444930, config).then(($result: any) => {
+        return $$createType3($result);
+    });
+}
+
+export function SetBackendOptions(config: player$0.BackendOptions): $CancellablePromise<player$0.BackendOptionsStatus> {
+    return $Call.ByID(2889019270, config).then(($result: any) => {
+        return $$createType4($result);
+    });
+}
+
+export function SetCrossfade(crossfadeMS: number): $CancellablePromise<player$0.State> {
+    return $Call.ByID(845842446, crossfadeMS).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+/**
+ * SetEqualizer saves the 10-band equalizer. Passing only a preset name
+ * loads that preset's gains.
+ */
+export function SetEqualizer(config: player$0.Equalizer): $CancellablePromise<player$0.Equalizer> {
+    return $Call.ByID(847190888, config).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
+/**
+ * SetPlaybackDiagnosticsEnabled turns the technical playback log of load
+ * times, seek latencies and underruns on or off.
+ */
+export function SetPlaybackDiagnosticsEnabled(enabled: boolean): $CancellablePromise<boolean> {
+    return $Call.ByID(1942409944, enabled);
+}
+
+export function SetQuietHours(config: player$0.QuietHours): $CancellablePromise<player$0.QuietHours> {
+    return $Call.ByID(1274380631, config).then(($result: any) => {
+        return $$createType10($result);
+    });
+}
+
+/**
+ * SetReplayGainMode picks loudness normalization: "off", "track" or
+ * "album".
+ */
+export function SetReplayGainMode(mode: string): $CancellablePromise<string> {
+    return $Call.ByID(746941243, mode);
+}
+
+export function SetSampleRatePolicy(policy: player$0.SampleRatePolicy): $CancellablePromise<player$0.SampleRatePolicy> {
+    return $Call.ByID(3805388650, policy).then(($result: any) => {
+        return $$createType11($result);
+    });
+}
+
+/**
+ * SetSmartLevelingEnabled turns on leveling of the queue to an even
+ * loudness, including tracks without ReplayGain tags.
+ */
+export function SetSmartLevelingEnabled(enabled: boolean): $CancellablePromise<boolean> {
+    return $Call.ByID(3991055372, enabled);
+}
+
+export function SetStopAfterCurrent(enabled: boolean): $CancellablePromise<player$0.State> {
+    return $Call.ByID(2631317043, enabled).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+/**
+ * SetTrackTrims makes playback of a track start at startMS and stop at endMS;
+ * a nil endMS plays to the end of the file.
+ */
+export function SetTrackTrims(trackID: number, startMS: number, endMS: number | null): $CancellablePromise<library$0.TrackTrim> {
+    return $Call.ByID(1469164420, trackID, startMS, endMS).then(($result: any) => {
+        return $$createType12($result);
+    });
+}
+
+/**
+ * SetTrackVolumeOffset stores a dB correction for one track; nil clears it.
+ */
+export function SetTrackVolumeOffset(trackID: number, offsetDB: number | null): $CancellablePromise<library$0.TrackVolumeOffset> {
+    return $Call.ByID(2846104158, trackID, offsetDB).then(($result: any) => {
+        return $$createType13($result);
+    });
+}
+
+/**
+ * SetTranscodeFallback picks the format, "flac", "wav" or "off", files the
+ * backend cannot decode are converted to with ffmpeg, by default and per
+ * codec.
+ */
+export function SetTranscodeFallback(preferences: player$0.TranscodePreferences): $CancellablePromise<player$0.TranscodeFallbackStatus> {
+    return $Call.ByID(3302965525, preferences).then(($result: any) => {
+        return $$createType14($result);
+    });
+}
+
+export function SetVolume(volume: number): $CancellablePromise<player$0.State> {
+    return $Call.ByID(671101282, volume).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function SmartLevelingEnabled(): $CancellablePromise<boolean> {
+    return $Call.ByID(3638268636);
+}
+
+/**
+ * StartSleepTimer pauses playback after durationMinutes, optionally fading
+ * the volume out first.
+ */
+export function StartSleepTimer(durationMinutes: number, fadeOut: boolean): $CancellablePromise<player$0.State> {
+    return $Call.ByID(3812179188, durationMinutes, fadeOut).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function StopPreview(): $CancellablePromise<player$0.PreviewState> {
+    return $Call.ByID(3114410268).then(($result: any) => {
+        return $$createType9($result);
+    });
+}
+
+export function TogglePlayback(): $CancellablePromise<player$0.State> {
+    return $Call.ByID(427686917).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = player$0.State.createFrom;
+const $$createType1 = library$0.AlbumMix.createFrom;
+const $$createType2 = library$0.AlbumVolumeOffset.createFrom;
+const $$createType3 = player$0.AudioAccessibility.createFrom;
+const $$createType4 = player$0.BackendOptionsStatus.createFrom;
+const $$createType5 = player$0.Equalizer.createFrom;
+const $$createType6 = player$0.EqualizerPreset.createFrom;
+const $$createType7 = $Create.Array($$createType6);
+const $$createType8 = player$0.PlaybackDiagnostics.createFrom;
+const $$createType9 = player$0.PreviewState.createFrom;
+const $$createType10 = player$0.QuietHours.createFrom;
+const $$createType11 = player$0.SampleRatePolicy.createFrom;
+const $$createType12 = library$0.TrackTrim.createFrom;
+const $$createType13 = library$0.TrackVolumeOffset.createFrom;
+const $$createType14 = player$0.TranscodeFallbackStatus.createFrom;
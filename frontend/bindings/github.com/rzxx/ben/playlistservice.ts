@@ -0,0 +1,171 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as playlist$0 from "./internal/playlist/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as queue$0 from "./internal/queue/models.js";
+
+export function AddTracksToPlaylist(id: number, trackIDs: number[]): $CancellablePromise<playlist$0.Playlist> {
+    return $Call.ByID(2241007833, id, trackIDs).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function AppendPlaylistToQueue(id: number): $CancellablePromise<queue$0.State> {
+    return $Call.ByID(2332873923, id).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+export function CreatePlaylist(name: string, trackIDs: number[]): $CancellablePromise<playlist$0.Playlist> {
+    return $Call.ByID(3050620365, name, trackIDs).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function CreatePlaylistFolder(name: string, parentID: number | null): $CancellablePromise<playlist$0.Folder> {
+    return $Call.ByID(2444399525, name, parentID).then(($result: any) => {
+        return $$createType2($result);
+    });
+}
+
+export function DeletePlaylist(id: number): $CancellablePromise<void> {
+    return $Call.ByID(2497002694, id);
+}
+
+export function DeletePlaylistFolder(id: number): $CancellablePromise<void> {
+    return $Call.ByID(61161398, id);
+}
+
+export function GetPlaylist(id: number): $CancellablePromise<playlist$0.Detail> {
+    return $Call.ByID(3588046843, id).then(($result: any) => {
+        return $$createType3($result);
+    });
+}
+
+export function GetPlaylistPlaybackSettings(id: number): $CancellablePromise<playlist$0.PlaybackSettings> {
+    return $Call.ByID(1245953803, id).then(($result: any) => {
+        return $$createType4($result);
+    });
+}
+
+export function GetSyncFolder(): $CancellablePromise<string> {
+    return $Call.ByID(1316901778);
+}
+
+export function ListPlaylistFolders(): $CancellablePromise<playlist$0.Folder[]> {
+    return $Call.ByID(2644045674).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
+export function ListPlaylistTags(): $CancellablePromise<playlist$0.Tag[]> {
+    return $Call.ByID(1592141600).then(($result: any) => {
+        return $$createType7($result);
+    });
+}
+
+export function ListPlaylists(): $CancellablePromise<playlist$0.Playlist[]> {
+    return $Call.ByID(1004082594).then(($result: any) => {
+        return $$createType8($result);
+    });
+}
+
+export function ListPlaylistsByTag(tag: string): $CancellablePromise<playlist$0.Playlist[]> {
+    return $Call.ByID(2851135337, tag).then(($result: any) => {
+        return $$createType8($result);
+    });
+}
+
+export function MovePlaylist(id: number, folderID: number | null): $CancellablePromise<playlist$0.Playlist> {
+    return $Call.ByID(4077365044, id, folderID).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function MovePlaylistFolder(id: number, parentID: number | null): $CancellablePromise<playlist$0.Folder> {
+    return $Call.ByID(4275973888, id, parentID).then(($result: any) => {
+        return $$createType2($result);
+    });
+}
+
+export function MovePlaylistTrack(id: number, $from: number, to: number): $CancellablePromise<playlist$0.Playlist> {
+    return $Call.ByID(4171378251, id, $from, to).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+/**
+ * PlayPlaylist replaces the queue with the playlist, starting at
+ * startIndex, the same way album and artist queues are started.
+ */
+export function PlayPlaylist(id: number, startIndex: number): $CancellablePromise<queue$0.State> {
+    return $Call.ByID(288637263, id, startIndex).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+/**
+ * RemovePlaylistTracks removes tracks by their positions in GetPlaylist.
+ */
+export function RemovePlaylistTracks(id: number, positions: number[]): $CancellablePromise<playlist$0.Playlist> {
+    return $Call.ByID(801286177, id, positions).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function RenamePlaylist(id: number, name: string): $CancellablePromise<playlist$0.Playlist> {
+    return $Call.ByID(135355951, id, name).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function RenamePlaylistFolder(id: number, name: string): $CancellablePromise<playlist$0.Folder> {
+    return $Call.ByID(3940409947, id, name).then(($result: any) => {
+        return $$createType2($result);
+    });
+}
+
+export function SetPlaylistPlaybackSettings(id: number, settings: playlist$0.PlaybackSettings): $CancellablePromise<playlist$0.Playlist> {
+    return $Call.ByID(1900313207, id, settings).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function SetPlaylistTags(id: number, tags: string[]): $CancellablePromise<playlist$0.Playlist> {
+    return $Call.ByID(3487054074, id, tags).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function SetPlaylistTracks(id: number, trackIDs: number[]): $CancellablePromise<playlist$0.Playlist> {
+    return $Call.ByID(3071924863, id, trackIDs).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function SetSyncFolder(path: string): $CancellablePromise<void> {
+    return $Call.ByID(258382774, path);
+}
+
+export function SyncFolderNow(): $CancellablePromise<void> {
+    return $Call.ByID(1423765344);
+}
+
+// Private type creation functions
+const $$createType0 = playlist$0.Playlist.createFrom;
+const $$createType1 = queue$0.State.createFrom;
+const $$createType2 = playlist$0.Folder.createFrom;
+const $$createType3 = playlist$0.Detail.createFrom;
+const $$createType4 = playlist$0.PlaybackSettings.createFrom;
+const $$createType5 = $Create.Array($$createType2);
+const $$createType6 = playlist$0.Tag.createFrom;
+const $$createType7 = $Create.Array($$createType6);
+const $$createType8 = $Create.Array($$createType0);
@@ -0,0 +1,169 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as library$0 from "./internal/library/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as scanner$0 from "./internal/scanner/models.js";
+
+export function CancelScan(): $CancellablePromise<boolean> {
+    return $Call.ByID(3103848340);
+}
+
+/**
+ * GetArchiveIndexing reports whether scans index audio inside .zip
+ * archives.
+ */
+export function GetArchiveIndexing(): $CancellablePromise<boolean> {
+    return $Call.ByID(815134289);
+}
+
+/**
+ * GetAutoImportConfig returns the folder whose new files are moved into the
+ * library, and how they are organized there.
+ */
+export function GetAutoImportConfig(): $CancellablePromise<scanner$0.AutoImportConfig> {
+    return $Call.ByID(3048431933).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+/**
+ * GetExcludeRules returns the exclude rules applied to every watched root
+ * on top of its own.
+ */
+export function GetExcludeRules(): $CancellablePromise<library$0.RootRules> {
+    return $Call.ByID(3682511432).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+/**
+ * GetFingerprinting reports whether scans compute Chromaprint fingerprints
+ * with fpcalc, used to find duplicates and relink moved files.
+ */
+export function GetFingerprinting(): $CancellablePromise<boolean> {
+    return $Call.ByID(477885025);
+}
+
+/**
+ * GetLibraryChanges lists the albums that scans after sinceScanID added,
+ * removed or updated tracks in.
+ */
+export function GetLibraryChanges(sinceScanID: number): $CancellablePromise<scanner$0.LibraryChanges> {
+    return $Call.ByID(3905254341, sinceScanID).then(($result: any) => {
+        return $$createType2($result);
+    });
+}
+
+/**
+ * GetRatingTagPrecedence returns "database" or "tags", the side that wins
+ * when imported rating tags disagree with a stored rating.
+ */
+export function GetRatingTagPrecedence(): $CancellablePromise<string> {
+    return $Call.ByID(1364364008);
+}
+
+/**
+ * GetScanWorkers returns how many files scans read tags and artwork from
+ * at once, 0 for one per CPU.
+ */
+export function GetScanWorkers(): $CancellablePromise<number> {
+    return $Call.ByID(323664267);
+}
+
+export function GetStatus(): $CancellablePromise<scanner$0.Status> {
+    return $Call.ByID(966301467).then(($result: any) => {
+        return $$createType3($result);
+    });
+}
+
+/**
+ * GetTagVerification reports whether scans hash the tag regions of files
+ * whose size and modification time did not change.
+ */
+export function GetTagVerification(): $CancellablePromise<boolean> {
+    return $Call.ByID(2044290712);
+}
+
+export function ImportNow(): $CancellablePromise<scanner$0.ImportResult> {
+    return $Call.ByID(1957040190).then(($result: any) => {
+        return $$createType4($result);
+    });
+}
+
+export function ListCorruptedChecksums(): $CancellablePromise<scanner$0.CorruptedChecksum[]> {
+    return $Call.ByID(2731620727).then(($result: any) => {
+        return $$createType6($result);
+    });
+}
+
+export function SetArchiveIndexing(enabled: boolean): $CancellablePromise<boolean> {
+    return $Call.ByID(3063684141, enabled);
+}
+
+export function SetAutoImportConfig(config: scanner$0.AutoImportConfig): $CancellablePromise<scanner$0.AutoImportConfig> {
+    return $Call.ByID(672293449, config).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function SetExcludeRules(rules: library$0.RootRules): $CancellablePromise<library$0.RootRules> {
+    return $Call.ByID(2139616340, rules).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+export function SetFingerprinting(enabled: boolean): $CancellablePromise<boolean> {
+    return $Call.ByID(117241789, enabled);
+}
+
+export function SetRatingTagPrecedence(precedence: string): $CancellablePromise<string> {
+    return $Call.ByID(234071124, precedence);
+}
+
+export function SetScanWorkers(workers: number): $CancellablePromise<number> {
+    return $Call.ByID(2405472111, workers);
+}
+
+export function SetTagVerification(enabled: boolean): $CancellablePromise<boolean> {
+    return $Call.ByID(835953500, enabled);
+}
+
+export function TriggerFullScan(): $CancellablePromise<void> {
+    return $Call.ByID(1583516401);
+}
+
+export function TriggerIncrementalScan(): $CancellablePromise<void> {
+    return $Call.ByID(4128331878);
+}
+
+export function TriggerScan(): $CancellablePromise<void> {
+    return $Call.ByID(1754448716);
+}
+
+/**
+ * VerifyFiles checksums the files in scope as a job: the first run stores
+ * checksums, later runs report files that changed without being rewritten.
+ */
+export function VerifyFiles(scope: scanner$0.ChecksumScope): $CancellablePromise<scanner$0.ChecksumReport> {
+    return $Call.ByID(1035441717, scope).then(($result: any) => {
+        return $$createType7($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = scanner$0.AutoImportConfig.createFrom;
+const $$createType1 = library$0.RootRules.createFrom;
+const $$createType2 = scanner$0.LibraryChanges.createFrom;
+const $$createType3 = scanner$0.Status.createFrom;
+const $$createType4 = scanner$0.ImportResult.createFrom;
+const $$createType5 = scanner$0.CorruptedChecksum.createFrom;
+const $$createType6 = $Create.Array($$createType5);
+const $$createType7 = scanner$0.ChecksumReport.createFrom;
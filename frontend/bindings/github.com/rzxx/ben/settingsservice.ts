@@ -0,0 +1,66 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as library$0 from "./internal/library/models.js";
+
+/**
+ * AddRemoteRoot registers a WebDAV or SFTP share as a library root.
+ * Credentials may be passed separately or embedded in the URL; explicit
+ * values win. The password is kept in the keychain, not the library.
+ */
+export function AddRemoteRoot(rawURL: string, username: string, password: string): $CancellablePromise<library$0.WatchedRoot> {
+    return $Call.ByID(418106965, rawURL, username, password).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function AddWatchedRoot(path: string): $CancellablePromise<library$0.WatchedRoot> {
+    return $Call.ByID(345313763, path).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function ListWatchedRoots(): $CancellablePromise<library$0.WatchedRoot[]> {
+    return $Call.ByID(687846755).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+export function RemoveWatchedRoot(id: number): $CancellablePromise<void> {
+    return $Call.ByID(4081202698, id);
+}
+
+export function SetWatchedRootEnabled(id: number, enabled: boolean): $CancellablePromise<void> {
+    return $Call.ByID(583001791, id, enabled);
+}
+
+/**
+ * UpdateWatchedRootRules replaces the exclude patterns, hidden file and
+ * minimum size rules of a root. The root is rescanned, so files the rules
+ * now exclude leave the library.
+ */
+export function UpdateWatchedRootRules(id: number, rules: library$0.RootRules): $CancellablePromise<library$0.WatchedRoot> {
+    return $Call.ByID(1362800436, id, rules).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+/**
+ * UpdateWatchedRootSchedule replaces when a root is scanned automatically:
+ * on startup, every few hours, and outside its quiet hours.
+ */
+export function UpdateWatchedRootSchedule(id: number, schedule: library$0.RootSchedule): $CancellablePromise<library$0.WatchedRoot> {
+    return $Call.ByID(56187352, id, schedule).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = library$0.WatchedRoot.createFrom;
+const $$createType1 = $Create.Array($$createType0);
@@ -0,0 +1,41 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as snapshot$0 from "./internal/snapshot/models.js";
+
+/**
+ * GetLastOperation returns the operation that can still be rolled back, or
+ * nil when there is none.
+ */
+export function GetLastOperation(): $CancellablePromise<snapshot$0.Info | null> {
+    return $Call.ByID(220431152).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+/**
+ * ListOperations returns the operations that can still be rolled back,
+ * newest first. RollbackLastOperation rolls back the first.
+ */
+export function ListOperations(): $CancellablePromise<snapshot$0.Info[]> {
+    return $Call.ByID(3451143223).then(($result: any) => {
+        return $$createType2($result);
+    });
+}
+
+export function RollbackLastOperation(): $CancellablePromise<snapshot$0.Info> {
+    return $Call.ByID(2569350166).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = snapshot$0.Info.createFrom;
+const $$createType1 = $Create.Nullable($$createType0);
+const $$createType2 = $Create.Array($$createType0);
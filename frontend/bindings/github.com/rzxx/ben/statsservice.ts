@@ -0,0 +1,180 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as library$0 from "./internal/library/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as queue$0 from "./internal/queue/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as stats$0 from "./internal/stats/models.js";
+
+/**
+ * ApplyTrimSuggestion trims the intro the listener keeps skipping.
+ */
+export function ApplyTrimSuggestion(trackID: number): $CancellablePromise<library$0.TrackTrim> {
+    return $Call.ByID(3746803998, trackID).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function DismissTrimSuggestion(trackID: number): $CancellablePromise<void> {
+    return $Call.ByID(732152546, trackID);
+}
+
+export function EndSessionLabel(): $CancellablePromise<stats$0.SessionLabelState> {
+    return $Call.ByID(1647718713).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+export function GetDashboard(rangeKey: string, limit: number): $CancellablePromise<stats$0.Dashboard> {
+    return $Call.ByID(1674425190, rangeKey, limit).then(($result: any) => {
+        return $$createType2($result);
+    });
+}
+
+export function GetDashboardCollapsed(rangeKey: string, limit: number, collapse: string): $CancellablePromise<stats$0.Dashboard> {
+    return $Call.ByID(1443512119, rangeKey, limit, collapse).then(($result: any) => {
+        return $$createType2($result);
+    });
+}
+
+export function GetDashboardLayout(): $CancellablePromise<stats$0.DashboardLayout> {
+    return $Call.ByID(1451567648).then(($result: any) => {
+        return $$createType3($result);
+    });
+}
+
+/**
+ * GetHeartbeatInterval returns the seconds of listening time each stats
+ * heartbeat records.
+ */
+export function GetHeartbeatInterval(): $CancellablePromise<number> {
+    return $Call.ByID(160451359);
+}
+
+/**
+ * GetListeningPairs lists albums or artists often played one after the
+ * other; kind is "album" or "artist".
+ */
+export function GetListeningPairs(kind: string, $from: string, limit: number): $CancellablePromise<stats$0.ListeningPair[]> {
+    return $Call.ByID(1389192100, kind, $from, limit).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
+/**
+ * GetListeningSessions lists recorded listening sessions with the albums,
+ * playlists and other sources played in each, newest first.
+ */
+export function GetListeningSessions(limit: number): $CancellablePromise<stats$0.ListeningSession[]> {
+    return $Call.ByID(3670698654, limit).then(($result: any) => {
+        return $$createType7($result);
+    });
+}
+
+export function GetOverview(limit: number): $CancellablePromise<stats$0.Overview> {
+    return $Call.ByID(191675605, limit).then(($result: any) => {
+        return $$createType8($result);
+    });
+}
+
+export function GetSessionLabel(): $CancellablePromise<stats$0.SessionLabelState> {
+    return $Call.ByID(4003690288).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+export function GetSessionSnapshots(): $CancellablePromise<boolean> {
+    return $Call.ByID(3758863951);
+}
+
+export function GetTopTracks(rangeKey: string, limit: number, combineLinked: boolean): $CancellablePromise<stats$0.TrackStat[]> {
+    return $Call.ByID(3278411057, rangeKey, limit, combineLinked).then(($result: any) => {
+        return $$createType10($result);
+    });
+}
+
+export function GetTrackStats(trackID: number, combineLinked: boolean): $CancellablePromise<stats$0.TrackVersionStats> {
+    return $Call.ByID(8941612, trackID, combineLinked).then(($result: any) => {
+        return $$createType11($result);
+    });
+}
+
+export function GetTrackVersions(trackID: number, collapse: string): $CancellablePromise<stats$0.TrackVersionStats> {
+    return $Call.ByID(3158695212, trackID, collapse).then(($result: any) => {
+        return $$createType11($result);
+    });
+}
+
+export function GetTrimSuggestions(limit: number): $CancellablePromise<stats$0.TrimSuggestion[]> {
+    return $Call.ByID(3089706331, limit).then(($result: any) => {
+        return $$createType13($result);
+    });
+}
+
+export function ImportListeningLog(path: string, device: string): $CancellablePromise<stats$0.ListeningImportResult> {
+    return $Call.ByID(1116740874, path, device).then(($result: any) => {
+        return $$createType14($result);
+    });
+}
+
+/**
+ * LoadListeningSession replaces the queue with the one a session ended with.
+ */
+export function LoadListeningSession(id: number): $CancellablePromise<queue$0.State> {
+    return $Call.ByID(3849489181, id).then(($result: any) => {
+        return $$createType15($result);
+    });
+}
+
+export function ResetDashboardLayout(): $CancellablePromise<stats$0.DashboardLayout> {
+    return $Call.ByID(1394178761).then(($result: any) => {
+        return $$createType3($result);
+    });
+}
+
+export function SetDashboardLayout(layout: stats$0.DashboardLayout): $CancellablePromise<stats$0.DashboardLayout> {
+    return $Call.ByID(1186510788, layout).then(($result: any) => {
+        return $$createType3($result);
+    });
+}
+
+export function SetHeartbeatInterval(seconds: number): $CancellablePromise<number> {
+    return $Call.ByID(1686921475, seconds);
+}
+
+export function SetSessionSnapshots(enabled: boolean): $CancellablePromise<boolean> {
+    return $Call.ByID(3725892163, enabled);
+}
+
+export function StartSessionLabel(label: string): $CancellablePromise<stats$0.SessionLabelState> {
+    return $Call.ByID(4046464460, label).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = library$0.TrackTrim.createFrom;
+const $$createType1 = stats$0.SessionLabelState.createFrom;
+const $$createType2 = stats$0.Dashboard.createFrom;
+const $$createType3 = stats$0.DashboardLayout.createFrom;
+const $$createType4 = stats$0.ListeningPair.createFrom;
+const $$createType5 = $Create.Array($$createType4);
+const $$createType6 = stats$0.ListeningSession.createFrom;
+const $$createType7 = $Create.Array($$createType6);
+const $$createType8 = stats$0.Overview.createFrom;
+const $$createType9 = stats$0.TrackStat.createFrom;
+const $$createType10 = $Create.Array($$createType9);
+const $$createType11 = stats$0.TrackVersionStats.createFrom;
+const $$createType12 = stats$0.TrimSuggestion.createFrom;
+const $$createType13 = $Create.Array($$createType12);
+const $$createType14 = stats$0.ListeningImportResult.createFrom;
+const $$createType15 = queue$0.State.createFrom;
@@ -7,20 +7,24 @@ import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Cr
 
 // eslint-disable-next-line @typescript-eslint/ban-ts-comment
 // @ts-ignore: Unused imports
-import * as stats$0 from "./internal/stats/models.js";
+import * as $models from "./models.js";
 
-export function GetDashboard(rangeKey: string, limit: number): $CancellablePromise<stats$0.Dashboard> {
-    return $Call.ByID(1674425190, rangeKey, limit).then(($result: any) => {
+export function GetAppStatus(): $CancellablePromise<$models.AppStatus> {
+    return $Call.ByID(2911899006).then(($result: any) => {
         return $$createType0($result);
     });
 }
 
-export function GetOverview(limit: number): $CancellablePromise<stats$0.Overview> {
-    return $Call.ByID(191675605, limit).then(($result: any) => {
+/**
+ * RecheckDatabase runs the integrity check now instead of reusing the last
+ * result.
+ */
+export function RecheckDatabase(): $CancellablePromise<$models.DatabaseStatus> {
+    return $Call.ByID(1969377967).then(($result: any) => {
         return $$createType1($result);
     });
 }
 
 // Private type creation functions
-const $$createType0 = stats$0.Dashboard.createFrom;
-const $$createType1 = stats$0.Overview.createFrom;
+const $$createType0 = $models.AppStatus.createFrom;
+const $$createType1 = $models.DatabaseStatus.createFrom;
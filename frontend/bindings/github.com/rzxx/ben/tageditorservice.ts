@@ -0,0 +1,26 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as tageditor$0 from "./internal/tageditor/models.js";
+
+/**
+ * EditTracks writes the edited tags to the files of the tracks and rescans
+ * them, so the library and its albums follow the new tags. Undo writes back
+ * the tags the files had. Batch edits are also kept by the snapshot service,
+ * so they can be rolled back after later edits pushed them out of the undo
+ * history.
+ */
+export function EditTracks(trackIDs: number[], edit: tageditor$0.Edit): $CancellablePromise<tageditor$0.Result> {
+    return $Call.ByID(2703773320, trackIDs, edit).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = tageditor$0.Result.createFrom;
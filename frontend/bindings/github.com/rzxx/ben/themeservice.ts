@@ -0,0 +1,58 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as library$0 from "./internal/library/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as palette$0 from "./internal/palette/models.js";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as $models from "./models.js";
+
+export function DefaultOptions(): $CancellablePromise<palette$0.ExtractOptions> {
+    return $Call.ByID(756867029).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+/**
+ * ExtractPalettesForAlbums returns the stored palettes of the albums'
+ * covers and extracts the missing ones in the background with a bounded
+ * worker pool, so an album grid can be themed with one call.
+ */
+export function ExtractPalettesForAlbums(keys: library$0.AlbumKey[]): $CancellablePromise<$models.AlbumPaletteBatch> {
+    return $Call.ByID(1285110916, keys).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+export function GenerateFromCover(coverPath: string, options: palette$0.ExtractOptions): $CancellablePromise<palette$0.ThemePalette> {
+    return $Call.ByID(3858850, coverPath, options).then(($result: any) => {
+        return $$createType2($result);
+    });
+}
+
+/**
+ * GetAmbientCovers returns a page of the shuffled high-resolution cover
+ * feed for the idle ambient display, each cover with its palette. Pass the
+ * seed and next offset of the previous page to continue the same shuffle.
+ * Missing palettes are extracted and stored before the page is returned.
+ */
+export function GetAmbientCovers(seed: number, offset: number, limit: number): $CancellablePromise<library$0.AmbientCoverPage> {
+    return $Call.ByID(2757583948, seed, offset, limit).then(($result: any) => {
+        return $$createType3($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = palette$0.ExtractOptions.createFrom;
+const $$createType1 = $models.AlbumPaletteBatch.createFrom;
+const $$createType2 = palette$0.ThemePalette.createFrom;
+const $$createType3 = library$0.AmbientCoverPage.createFrom;
@@ -0,0 +1,31 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as undo$0 from "./internal/undo/models.js";
+
+export function GetState(): $CancellablePromise<undo$0.State> {
+    return $Call.ByID(1695116258).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function Redo(): $CancellablePromise<undo$0.State> {
+    return $Call.ByID(4166221491).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+export function Undo(): $CancellablePromise<undo$0.State> {
+    return $Call.ByID(900613073).then(($result: any) => {
+        return $$createType0($result);
+    });
+}
+
+// Private type creation functions
+const $$createType0 = undo$0.State.createFrom;
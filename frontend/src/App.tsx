@@ -1,9 +1,22 @@
 import { Router } from "wouter";
 import { AppShell } from "./app/AppShell";
+import { MiniPlayerShell } from "./app/MiniPlayerShell";
 import { AppProviders } from "./app/providers/AppProviders";
 import { appLocation } from "./app/routing/appLocation";
 
+// The mini player window loads the same frontend with ?window=mini.
+const isMiniPlayerWindow =
+  new URLSearchParams(window.location.search).get("window") === "mini";
+
 function App() {
+  if (isMiniPlayerWindow) {
+    return (
+      <AppProviders>
+        <MiniPlayerShell />
+      </AppProviders>
+    );
+  }
+
   return (
     <Router
       hook={appLocation.hook}
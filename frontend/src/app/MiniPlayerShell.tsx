@@ -0,0 +1,43 @@
+import { Window } from "@wailsio/runtime";
+import { MiniPlayer } from "../features/player/MiniPlayer";
+import {
+  usePlaybackActions,
+  usePlaybackCurrentTrack,
+  usePlaybackProgressDurationMS,
+  usePlaybackProgressPositionMS,
+  usePlaybackQueueState,
+  usePlaybackStatus,
+  usePlaybackTransportBusy,
+} from "./state/playback/playbackSelectors";
+import { formatDuration } from "./utils/appUtils";
+
+// MiniPlayerShell is the whole UI of the always-on-top mini player window,
+// opened with ?window=mini. It shares the playback store with the main
+// window, so both follow the same player and queue events.
+export function MiniPlayerShell() {
+  const playbackActions = usePlaybackActions();
+  const currentTrack = usePlaybackCurrentTrack();
+  const playerStatus = usePlaybackStatus();
+  const positionMs = usePlaybackProgressPositionMS();
+  const durationMs = usePlaybackProgressDurationMS();
+  const queueState = usePlaybackQueueState();
+  const transportBusy = usePlaybackTransportBusy();
+
+  return (
+    <MiniPlayer
+      currentTrack={currentTrack}
+      playerStatus={playerStatus}
+      positionMs={positionMs}
+      durationMs={durationMs}
+      queueTotal={queueState.total}
+      transportBusy={transportBusy}
+      onPreviousTrack={playbackActions.previousTrack}
+      onTogglePlayback={playbackActions.togglePlayback}
+      onNextTrack={playbackActions.nextTrack}
+      onClose={() => {
+        void Window.Close();
+      }}
+      formatDuration={formatDuration}
+    />
+  );
+}
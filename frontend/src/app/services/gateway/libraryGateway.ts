@@ -5,7 +5,7 @@ import {
   ListAlbums as listAlbumsBinding,
   ListArtists as listArtistsBinding,
   ListTracks as listTracksBinding,
-} from "../../../../bindings/ben/libraryservice";
+} from "../../../../bindings/github.com/rzxx/ben/libraryservice";
 import type {
   AlbumDetail,
   ArtistDetail,
@@ -3,7 +3,7 @@ import {
   GetAlbumQueueTrackIDsFromTrack as getAlbumQueueTrackIDsFromTrackBinding,
   GetArtistQueueTrackIDs as getArtistQueueTrackIDsBinding,
   GetArtistQueueTrackIDsFromTopTrack as getArtistQueueTrackIDsFromTopTrackBinding,
-} from "../../../../bindings/ben/libraryservice";
+} from "../../../../bindings/github.com/rzxx/ben/libraryservice";
 import {
   Next as nextTrackBinding,
   Play as playBinding,
@@ -11,7 +11,7 @@ import {
   Seek as seekBinding,
   SetVolume as setVolumeBinding,
   TogglePlayback as togglePlaybackBinding,
-} from "../../../../bindings/ben/playerservice";
+} from "../../../../bindings/github.com/rzxx/ben/playerservice";
 import {
   AppendTracks as appendTracksBinding,
   Clear as clearQueueBinding,
@@ -20,7 +20,7 @@ import {
   SetQueue as setQueueBinding,
   SetRepeatMode as setQueueRepeatModeBinding,
   SetShuffle as setQueueShuffleBinding,
-} from "../../../../bindings/ben/queueservice";
+} from "../../../../bindings/github.com/rzxx/ben/queueservice";
 import type { PlayerState, QueueState } from "../../../features/types";
 import { executeGatewayRequest, type GatewayRequest, type GatewayRequestOptions } from "./gatewayUtils";
 
@@ -3,13 +3,13 @@ import {
   TriggerFullScan as triggerFullScanBinding,
   TriggerIncrementalScan as triggerIncrementalScanBinding,
   TriggerScan as triggerScanBinding,
-} from "../../../../bindings/ben/scannerservice";
+} from "../../../../bindings/github.com/rzxx/ben/scannerservice";
 import {
   AddWatchedRoot as addWatchedRootBinding,
   ListWatchedRoots as listWatchedRootsBinding,
   RemoveWatchedRoot as removeWatchedRootBinding,
   SetWatchedRootEnabled as setWatchedRootEnabledBinding,
-} from "../../../../bindings/ben/settingsservice";
+} from "../../../../bindings/github.com/rzxx/ben/settingsservice";
 import type { ScanStatus, WatchedRoot } from "../../../features/types";
 import { executeGatewayRequest, type GatewayRequest, type GatewayRequestOptions } from "./gatewayUtils";
 
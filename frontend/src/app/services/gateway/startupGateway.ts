@@ -1,5 +1,5 @@
-import { GetInitialState as getInitialStateBinding } from "../../../../bindings/ben/bootstrapservice";
-import type { StartupSnapshot } from "../../../../bindings/ben/models";
+import { GetInitialState as getInitialStateBinding } from "../../../../bindings/github.com/rzxx/ben/bootstrapservice";
+import type { StartupSnapshot } from "../../../../bindings/github.com/rzxx/ben/models";
 import {
   executeGatewayRequest,
   type GatewayRequest,
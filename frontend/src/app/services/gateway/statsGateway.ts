@@ -1,7 +1,7 @@
 import {
   GetDashboard as getDashboardBinding,
   GetOverview as getOverviewBinding,
-} from "../../../../bindings/ben/statsservice";
+} from "../../../../bindings/github.com/rzxx/ben/statsservice";
 import type { StatsDashboard, StatsOverview, StatsRange } from "../../../features/types";
 import { executeGatewayRequest, type GatewayRequest, type GatewayRequestOptions } from "./gatewayUtils";
 
@@ -1,6 +1,6 @@
 import {
   GenerateFromCover as generateFromCoverBinding,
-} from "../../../../bindings/ben/themeservice";
+} from "../../../../bindings/github.com/rzxx/ben/themeservice";
 import type { ThemeExtractOptions, ThemePalette } from "../../../features/types";
 import { executeGatewayRequest, type GatewayRequest, type GatewayRequestOptions } from "./gatewayUtils";
 
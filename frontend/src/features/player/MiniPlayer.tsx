@@ -0,0 +1,108 @@
+import { Pause, Play, SkipBack, SkipForward, X } from "lucide-react";
+import { CoverArt } from "../../shared/components/CoverArt";
+import { LibraryTrack } from "../types";
+
+type MiniPlayerProps = {
+  currentTrack?: LibraryTrack;
+  playerStatus: string;
+  positionMs: number;
+  durationMs: number;
+  queueTotal: number;
+  transportBusy: boolean;
+  onPreviousTrack: () => Promise<void>;
+  onTogglePlayback: () => Promise<void>;
+  onNextTrack: () => Promise<void>;
+  onClose: () => void;
+  formatDuration: (durationMS?: number) => string;
+};
+
+export function MiniPlayer(props: MiniPlayerProps) {
+  const isPlaying = props.playerStatus === "playing";
+  const currentTrack = props.currentTrack;
+  const hasCurrentTrack = currentTrack !== undefined;
+  const progress =
+    props.durationMs > 0
+      ? Math.min(Math.max(props.positionMs / props.durationMs, 0), 1)
+      : 0;
+
+  return (
+    <div className="wails-drag bg-theme-50 text-theme-900 dark:bg-theme-950 dark:text-theme-100 relative flex h-dvh flex-col overflow-hidden select-none">
+      <div className="flex min-h-0 flex-1 items-center gap-3 px-3 py-2">
+        <CoverArt
+          coverPath={currentTrack?.coverPath}
+          alt={currentTrack ? `${currentTrack.album} cover` : "No cover"}
+          variant="player"
+          className="h-16 w-16 shrink-0 rounded-md"
+          loading="eager"
+        />
+
+        <div className="flex min-w-0 flex-1 flex-col gap-1.5">
+          <div className="min-w-0">
+            <p className="truncate text-sm font-medium">
+              {currentTrack ? currentTrack.title : "No track selected"}
+            </p>
+            <p className="text-theme-600 dark:text-theme-400 truncate text-xs">
+              {currentTrack ? currentTrack.artist : "Select a track in the main window"}
+            </p>
+          </div>
+
+          <div className="wails-no-drag flex items-center gap-1">
+            <button
+              type="button"
+              onClick={() => void props.onPreviousTrack()}
+              disabled={!hasCurrentTrack || props.transportBusy}
+              className="text-theme-700 hover:text-theme-600 disabled:text-theme-400 dark:text-theme-200 dark:hover:text-theme-100 dark:disabled:text-theme-600 cursor-pointer rounded p-1.5 transition-colors disabled:cursor-not-allowed"
+              aria-label="Previous track"
+            >
+              <SkipBack size={14} fill="currentColor" />
+            </button>
+
+            <button
+              type="button"
+              onClick={() => void props.onTogglePlayback()}
+              disabled={props.queueTotal === 0 || props.transportBusy}
+              className="bg-accent-700 text-accent-50 hover:bg-accent-600 dark:bg-accent-50 dark:text-accent-900 dark:hover:bg-accent-200 cursor-pointer rounded-full p-2 transition disabled:cursor-not-allowed disabled:opacity-50"
+              aria-label={isPlaying ? "Pause" : "Play"}
+            >
+              {isPlaying ? (
+                <Pause size={14} fill="currentColor" />
+              ) : (
+                <Play size={14} fill="currentColor" />
+              )}
+            </button>
+
+            <button
+              type="button"
+              onClick={() => void props.onNextTrack()}
+              disabled={!hasCurrentTrack || props.transportBusy}
+              className="text-theme-700 hover:text-theme-600 disabled:text-theme-400 dark:text-theme-200 dark:hover:text-theme-100 dark:disabled:text-theme-600 cursor-pointer rounded p-1.5 transition-colors disabled:cursor-not-allowed"
+              aria-label="Next track"
+            >
+              <SkipForward size={14} fill="currentColor" />
+            </button>
+
+            <span className="text-theme-600 dark:text-theme-400 ml-auto text-xs tabular-nums">
+              {props.formatDuration(props.positionMs)} / {props.formatDuration(props.durationMs)}
+            </span>
+          </div>
+        </div>
+
+        <button
+          type="button"
+          onClick={props.onClose}
+          className="wails-no-drag text-theme-500 hover:text-theme-800 dark:hover:text-theme-100 self-start cursor-pointer rounded p-1 transition-colors"
+          aria-label="Close mini player"
+        >
+          <X size={14} />
+        </button>
+      </div>
+
+      <div className="bg-theme-300 h-1 w-full shrink-0 dark:bg-black/50">
+        <div
+          className="bg-theme-600 dark:bg-theme-300 h-full"
+          style={{ width: `${progress * 100}%` }}
+        />
+      </div>
+    </div>
+  );
+}
@@ -0,0 +1,184 @@
+package announce
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rzxx/ben/internal/eventbus"
+	"github.com/rzxx/ben/internal/i18n"
+	"github.com/rzxx/ben/internal/player"
+	"github.com/rzxx/ben/internal/settings"
+)
+
+const EventAnnouncement = "accessibility:announcement"
+
+// VerboseSettingKey enables descriptive announcements for screen readers.
+const VerboseSettingKey = "accessibility.verbose_announcements"
+
+const (
+	KindNowPlaying = "now-playing"
+	KindPaused     = "paused"
+	KindResumed    = "resumed"
+	KindStopped    = "stopped"
+)
+
+type Emitter func(eventName string, payload any)
+
+// Announcement is a sentence describing a playback change, along with the
+// metadata it was built from so the frontend can render its own variant.
+type Announcement struct {
+	Kind        string `json:"kind"`
+	Message     string `json:"message"`
+	TrackID     int64  `json:"trackId,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Artist      string `json:"artist,omitempty"`
+	Album       string `json:"album,omitempty"`
+	DiscNo      *int   `json:"discNo,omitempty"`
+	TrackNo     *int   `json:"trackNo,omitempty"`
+	DurationMS  *int   `json:"durationMs,omitempty"`
+	QueueIndex  int    `json:"queueIndex"`
+	QueueLength int    `json:"queueLength"`
+	At          string `json:"at"`
+}
+
+// Service turns player state changes into announcements while verbose mode
+// is on. Position ticks are ignored; only track and status changes speak.
+type Service struct {
+	mu          sync.Mutex
+	store       *settings.Store
+	localizer   *i18n.Localizer
+	emit        Emitter
+	verbose     bool
+	lastTrackID int64
+	lastStatus  string
+}
+
+func NewService(store *settings.Store, bus *eventbus.Bus) *Service {
+	service := &Service{
+		store:     store,
+		emit:      bus.Publish,
+		localizer: i18n.NewLocalizer(i18n.DefaultLocale),
+	}
+	service.verbose = store.GetString(context.Background(), VerboseSettingKey, "") == "true"
+	eventbus.Subscribe(bus, player.EventStateChanged, service.HandlePlayerState)
+
+	return service
+}
+
+func (s *Service) SetLocalizer(localizer *i18n.Localizer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.localizer = localizer
+}
+
+func (s *Service) Verbose() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.verbose
+}
+
+func (s *Service) SetVerbose(ctx context.Context, verbose bool) error {
+	if err := s.store.Set(ctx, VerboseSettingKey, strconv.FormatBool(verbose)); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.verbose = verbose
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *Service) HandlePlayerState(state player.State) {
+	s.mu.Lock()
+	previousTrackID := s.lastTrackID
+	previousStatus := s.lastStatus
+	s.lastStatus = state.Status
+	s.lastTrackID = 0
+	if state.CurrentTrack != nil {
+		s.lastTrackID = state.CurrentTrack.ID
+	}
+	verbose := s.verbose
+	localizer := s.localizer
+	emitter := s.emit
+	s.mu.Unlock()
+
+	if !verbose || emitter == nil {
+		return
+	}
+
+	announcement, ok := buildAnnouncement(localizer, state, previousTrackID, previousStatus)
+	if !ok {
+		return
+	}
+
+	emitter(EventAnnouncement, announcement)
+}
+
+func buildAnnouncement(localizer *i18n.Localizer, state player.State, previousTrackID int64, previousStatus string) (Announcement, bool) {
+	announcement := Announcement{
+		QueueIndex:  state.CurrentIndex,
+		QueueLength: state.QueueLength,
+		DurationMS:  state.DurationMS,
+		At:          time.Now().UTC().Format(time.RFC3339),
+	}
+
+	track := state.CurrentTrack
+	if track != nil {
+		announcement.TrackID = track.ID
+		announcement.Title = localizer.Placeholder(labelOr(track.Title, i18n.UnknownTitle))
+		announcement.Artist = localizer.Placeholder(labelOr(track.Artist, i18n.UnknownArtist))
+		announcement.Album = localizer.Placeholder(labelOr(track.Album, i18n.UnknownAlbum))
+		announcement.DiscNo = track.DiscNo
+		announcement.TrackNo = track.TrackNo
+	}
+
+	switch {
+	case track == nil || state.Status == player.StatusIdle:
+		if previousStatus == "" || previousStatus == player.StatusIdle {
+			return Announcement{}, false
+		}
+		announcement.Kind = KindStopped
+		announcement.Message = localizer.T("announce.stopped")
+	case track.ID != previousTrackID && state.Status == player.StatusPlaying:
+		announcement.Kind = KindNowPlaying
+		announcement.Message = nowPlayingMessage(localizer, announcement)
+	case state.Status == previousStatus:
+		return Announcement{}, false
+	case state.Status == player.StatusPaused:
+		announcement.Kind = KindPaused
+		announcement.Message = localizer.T("announce.paused", announcement.Title)
+	case state.Status == player.StatusPlaying:
+		announcement.Kind = KindResumed
+		announcement.Message = localizer.T("announce.resumed", announcement.Title)
+	default:
+		return Announcement{}, false
+	}
+
+	return announcement, true
+}
+
+func nowPlayingMessage(localizer *i18n.Localizer, announcement Announcement) string {
+	parts := []string{localizer.T("announce.nowPlaying", announcement.Title, announcement.Artist)}
+	parts = append(parts, localizer.T("announce.fromAlbum", announcement.Album))
+	if announcement.TrackNo != nil && *announcement.TrackNo > 0 {
+		parts = append(parts, localizer.T("announce.trackNumber", *announcement.TrackNo))
+	}
+	if announcement.DurationMS != nil && *announcement.DurationMS > 0 {
+		totalSeconds := *announcement.DurationMS / 1000
+		parts = append(parts, localizer.T("announce.duration", totalSeconds/60, totalSeconds%60))
+	}
+
+	return strings.Join(parts, ", ") + "."
+}
+
+func labelOr(value string, fallback string) string {
+	if trimmed := strings.TrimSpace(value); trimmed != "" {
+		return trimmed
+	}
+
+	return fallback
+}
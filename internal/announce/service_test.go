@@ -0,0 +1,71 @@
+package announce
+
+import (
+	"testing"
+
+	"github.com/rzxx/ben/internal/i18n"
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/player"
+)
+
+func TestBuildAnnouncementDescribesNewTrack(t *testing.T) {
+	t.Parallel()
+
+	trackNo := 3
+	durationMS := 245000
+	state := player.State{
+		Status:     player.StatusPlaying,
+		DurationMS: &durationMS,
+		CurrentTrack: &library.TrackSummary{
+			ID:      7,
+			Title:   "Teardrop",
+			Artist:  "Massive Attack",
+			Album:   "Mezzanine",
+			TrackNo: &trackNo,
+		},
+	}
+
+	announcement, ok := buildAnnouncement(i18n.NewLocalizer("en"), state, 6, player.StatusPlaying)
+	if !ok {
+		t.Fatal("expected an announcement for a track change")
+	}
+	if announcement.Kind != KindNowPlaying {
+		t.Fatalf("kind = %q", announcement.Kind)
+	}
+
+	want := "Now playing Teardrop by Massive Attack, from Mezzanine, track 3, 4 minutes 5 seconds."
+	if announcement.Message != want {
+		t.Fatalf("message = %q, want %q", announcement.Message, want)
+	}
+}
+
+func TestBuildAnnouncementIgnoresPositionTicks(t *testing.T) {
+	t.Parallel()
+
+	state := player.State{
+		Status:       player.StatusPlaying,
+		PositionMS:   12000,
+		CurrentTrack: &library.TrackSummary{ID: 7, Title: "Teardrop"},
+	}
+
+	if _, ok := buildAnnouncement(i18n.NewLocalizer("en"), state, 7, player.StatusPlaying); ok {
+		t.Fatal("expected no announcement while the same track keeps playing")
+	}
+}
+
+func TestBuildAnnouncementLocalizesPlaceholders(t *testing.T) {
+	t.Parallel()
+
+	state := player.State{
+		Status:       player.StatusPaused,
+		CurrentTrack: &library.TrackSummary{ID: 7},
+	}
+
+	announcement, ok := buildAnnouncement(i18n.NewLocalizer("de"), state, 7, player.StatusPlaying)
+	if !ok {
+		t.Fatal("expected a pause announcement")
+	}
+	if announcement.Message != "Unbekannter Titel pausiert" {
+		t.Fatalf("message = %q", announcement.Message)
+	}
+}
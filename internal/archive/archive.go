@@ -0,0 +1,232 @@
+package archive
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const Extension = ".zip"
+
+// defaultCacheBytes bounds the extraction cache; the least recently played
+// entries are removed first.
+const defaultCacheBytes = 2 << 30
+
+var ErrEntryNotFound = errors.New("archive entry not found")
+
+// IsArchive reports whether path names a zip archive by its extension.
+func IsArchive(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), Extension)
+}
+
+// EntryPath is the library path of a zip entry. The archive acts as a
+// folder, so a removed archive takes its entries with it like a removed
+// folder does.
+func EntryPath(archivePath string, entryName string) string {
+	return filepath.Join(archivePath, filepath.FromSlash(entryName))
+}
+
+// SplitEntryPath splits a library path into the archive file and the name of
+// the entry inside it. It is false for paths outside archives, including
+// folders that merely end in .zip.
+func SplitEntryPath(path string) (string, string, bool) {
+	cleanPath := filepath.Clean(path)
+	separator := string(filepath.Separator)
+	lowerPath := strings.ToLower(cleanPath)
+	marker := Extension + separator
+
+	offset := 0
+	for {
+		index := strings.Index(lowerPath[offset:], marker)
+		if index < 0 {
+			return "", "", false
+		}
+		end := offset + index + len(Extension)
+		archivePath := cleanPath[:end]
+		if info, err := os.Stat(archivePath); err == nil && info.Mode().IsRegular() {
+			return archivePath, filepath.ToSlash(cleanPath[end+len(separator):]), true
+		}
+		offset = end
+	}
+}
+
+// IsEntryPath reports whether path names an entry inside an archive.
+func IsEntryPath(path string) bool {
+	_, _, ok := SplitEntryPath(path)
+	return ok
+}
+
+// ValidEntryName rejects directories and names that would escape the
+// archive's folder when joined to its path.
+func ValidEntryName(name string) bool {
+	if name == "" || strings.HasSuffix(name, "/") {
+		return false
+	}
+
+	return filepath.IsLocal(filepath.FromSlash(name))
+}
+
+// ExtractFile writes a zip entry to dest, creating its folder.
+func ExtractFile(file *zip.File, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("create folder for %s: %w", file.Name, err)
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("open archive entry %s: %w", file.Name, err)
+	}
+	defer reader.Close()
+
+	output, err := os.CreateTemp(filepath.Dir(dest), ".extract-*")
+	if err != nil {
+		return fmt.Errorf("create file for %s: %w", file.Name, err)
+	}
+	tempPath := output.Name()
+
+	if _, err := io.Copy(output, reader); err != nil {
+		output.Close()
+		os.Remove(tempPath)
+		return fmt.Errorf("extract archive entry %s: %w", file.Name, err)
+	}
+	if err := output.Close(); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("extract archive entry %s: %w", file.Name, err)
+	}
+	if err := os.Rename(tempPath, dest); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("move extracted entry %s: %w", file.Name, err)
+	}
+
+	return nil
+}
+
+// Cache extracts archive entries for playback. Extracted files are keyed by
+// the archive's modification time, so a replaced archive is extracted again.
+type Cache struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+}
+
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir, maxBytes: defaultCacheBytes}
+}
+
+// Resolve returns a playable file for path: the extracted entry for paths
+// inside an archive and path itself otherwise. Extraction failures return
+// path so the backend reports the file as unplayable.
+func (c *Cache) Resolve(path string) string {
+	if c == nil {
+		return path
+	}
+
+	archivePath, entryName, ok := SplitEntryPath(path)
+	if !ok {
+		return path
+	}
+
+	extracted, err := c.Extract(archivePath, entryName)
+	if err != nil {
+		return path
+	}
+
+	return extracted
+}
+
+// Extract returns the cached copy of an archive entry, extracting it first
+// when needed.
+func (c *Cache) Extract(archivePath string, entryName string) (string, error) {
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("stat archive %s: %w", archivePath, err)
+	}
+
+	key := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%d", archivePath, entryName, info.ModTime().UnixNano())))
+	dest := filepath.Join(c.dir, hex.EncodeToString(key[:16])+strings.ToLower(filepath.Ext(entryName)))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := os.Stat(dest); err == nil {
+		// The modification time orders the cache by last use.
+		now := time.Now()
+		_ = os.Chtimes(dest, now, now)
+		return dest, nil
+	}
+
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("open archive %s: %w", archivePath, err)
+	}
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		if file.Name != entryName {
+			continue
+		}
+		if err := ExtractFile(file, dest); err != nil {
+			return "", err
+		}
+		c.pruneLocked(dest)
+		return dest, nil
+	}
+
+	return "", fmt.Errorf("%w: %s in %s", ErrEntryNotFound, entryName, archivePath)
+}
+
+// pruneLocked removes the least recently used files until the cache fits,
+// keeping the file that was just extracted.
+func (c *Cache) pruneLocked(keep string) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	type cachedFile struct {
+		path    string
+		size    int64
+		modTime int64
+	}
+	files := make([]cachedFile, 0, len(entries))
+	total := int64(0)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cachedFile{
+			path:    filepath.Join(c.dir, entry.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime().UnixNano(),
+		})
+		total += info.Size()
+	}
+
+	sort.Slice(files, func(i int, j int) bool {
+		return files[i].modTime < files[j].modTime
+	})
+	for _, file := range files {
+		if total <= c.maxBytes {
+			return
+		}
+		if file.path == keep {
+			continue
+		}
+		if os.Remove(file.path) == nil {
+			total -= file.size
+		}
+	}
+}
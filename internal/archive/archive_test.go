@@ -0,0 +1,100 @@
+package archive
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeArchiveForTest(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create archive: %v", err)
+	}
+	writer := zip.NewWriter(file)
+	for name, content := range entries {
+		entry, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("create entry %s: %v", name, err)
+		}
+		if _, err := entry.Write([]byte(content)); err != nil {
+			t.Fatalf("write entry %s: %v", name, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close archive writer: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("close archive: %v", err)
+	}
+}
+
+func TestSplitEntryPathFindsTheArchiveFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "Artist - Album.ZIP")
+	writeArchiveForTest(t, archivePath, map[string]string{"Disc 1/01 Intro.flac": "audio"})
+
+	gotArchive, gotEntry, ok := SplitEntryPath(EntryPath(archivePath, "Disc 1/01 Intro.flac"))
+	if !ok || gotArchive != archivePath || gotEntry != "Disc 1/01 Intro.flac" {
+		t.Fatalf("expected %s and entry, got %q %q %v", archivePath, gotArchive, gotEntry, ok)
+	}
+
+	folder := filepath.Join(dir, "folder.zip")
+	if err := os.MkdirAll(folder, 0o755); err != nil {
+		t.Fatalf("create folder: %v", err)
+	}
+	if _, _, ok := SplitEntryPath(filepath.Join(folder, "01.flac")); ok {
+		t.Fatal("expected a folder named like an archive not to count")
+	}
+	if _, _, ok := SplitEntryPath(filepath.Join(dir, "01.flac")); ok {
+		t.Fatal("expected a plain path not to count")
+	}
+}
+
+func TestValidEntryNameRejectsEscapes(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"", "Disc 1/", "../evil.flac", "/abs.flac"} {
+		if ValidEntryName(name) {
+			t.Fatalf("expected %q to be rejected", name)
+		}
+	}
+	if !ValidEntryName("Disc 1/01.flac") {
+		t.Fatal("expected a nested entry to be accepted")
+	}
+}
+
+func TestCacheResolveExtractsEntries(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "album.zip")
+	writeArchiveForTest(t, archivePath, map[string]string{"01 Song.mp3": "first", "02 Song.mp3": "second"})
+
+	cache := NewCache(filepath.Join(dir, "cache"))
+	resolved := cache.Resolve(EntryPath(archivePath, "02 Song.mp3"))
+	content, err := os.ReadFile(resolved)
+	if err != nil {
+		t.Fatalf("read extracted entry: %v", err)
+	}
+	if string(content) != "second" || filepath.Ext(resolved) != ".mp3" {
+		t.Fatalf("expected extracted mp3 with content, got %s %q", resolved, content)
+	}
+	if again := cache.Resolve(EntryPath(archivePath, "02 Song.mp3")); again != resolved {
+		t.Fatalf("expected the cached copy to be reused, got %s", again)
+	}
+
+	plain := filepath.Join(dir, "song.flac")
+	if got := cache.Resolve(plain); got != plain {
+		t.Fatalf("expected a plain path to pass through, got %s", got)
+	}
+	missing := EntryPath(archivePath, "03 Missing.mp3")
+	if got := cache.Resolve(missing); got != missing {
+		t.Fatalf("expected a missing entry to pass through, got %s", got)
+	}
+}
@@ -0,0 +1,186 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	_ "modernc.org/sqlite"
+)
+
+const (
+	archiveFormatVersion = 1
+	manifestEntryName    = "manifest.json"
+	databaseEntryName    = "library.db"
+)
+
+// Manifest describes a backup so a restore can be previewed before the
+// local library is touched.
+type Manifest struct {
+	FormatVersion int    `json:"formatVersion"`
+	CreatedAt     string `json:"createdAt"`
+	Hostname      string `json:"hostname"`
+	LastMigration string `json:"lastMigration"`
+	TrackCount    int    `json:"trackCount"`
+	PlaylistCount int    `json:"playlistCount"`
+}
+
+type libraryCounts struct {
+	tracks        int
+	playlists     int
+	lastMigration string
+}
+
+// snapshotDatabase writes a consistent copy of the live database. VACUUM
+// INTO runs inside SQLite, so playlists and settings stored in the same
+// file are captured at the same point in time as the library.
+func snapshotDatabase(ctx context.Context, database *sql.DB, destination string) error {
+	if err := os.Remove(destination); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("clear snapshot path: %w", err)
+	}
+
+	if _, err := database.ExecContext(ctx, "VACUUM INTO ?", destination); err != nil {
+		return fmt.Errorf("snapshot database: %w", err)
+	}
+
+	return nil
+}
+
+// redactSnapshot strips secrets older versions stored in the database from
+// a snapshot: the backup destination's secrets and remote root passwords.
+// Secure delete keeps the old values out of the freed pages.
+func redactSnapshot(ctx context.Context, snapshotPath string) error {
+	snapshot, err := sql.Open("sqlite", snapshotPath)
+	if err != nil {
+		return fmt.Errorf("open database snapshot: %w", err)
+	}
+	defer snapshot.Close()
+	snapshot.SetMaxOpenConns(1)
+
+	for _, statement := range []string{
+		"PRAGMA secure_delete = ON",
+		`UPDATE app_settings
+		 SET value = json_remove(value, '$.password', '$.secretKey', '$.passphrase')
+		 WHERE key = '` + ConfigSettingKey + `' AND json_valid(value)`,
+		"UPDATE watched_roots SET remote_password = NULL WHERE remote_password IS NOT NULL",
+	} {
+		if _, err := snapshot.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("redact database snapshot: %w", err)
+		}
+	}
+
+	return snapshot.Close()
+}
+
+func readLibraryCounts(ctx context.Context, database *sql.DB) (libraryCounts, error) {
+	counts := libraryCounts{}
+	if err := database.QueryRowContext(
+		ctx,
+		`SELECT
+			(SELECT COUNT(*) FROM tracks),
+			(SELECT COUNT(*) FROM playlists),
+			COALESCE((SELECT MAX(name) FROM schema_migrations), '')`,
+	).Scan(&counts.tracks, &counts.playlists, &counts.lastMigration); err != nil {
+		return libraryCounts{}, fmt.Errorf("read library counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+func writeArchive(manifest Manifest, snapshotPath string) ([]byte, error) {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode backup manifest: %w", err)
+	}
+
+	manifestWriter, err := archive.Create(manifestEntryName)
+	if err != nil {
+		return nil, fmt.Errorf("write backup manifest: %w", err)
+	}
+	if _, err := manifestWriter.Write(manifestJSON); err != nil {
+		return nil, fmt.Errorf("write backup manifest: %w", err)
+	}
+
+	snapshot, err := os.Open(snapshotPath)
+	if err != nil {
+		return nil, fmt.Errorf("open database snapshot: %w", err)
+	}
+	defer snapshot.Close()
+
+	databaseWriter, err := archive.Create(databaseEntryName)
+	if err != nil {
+		return nil, fmt.Errorf("write backup database: %w", err)
+	}
+	if _, err := io.Copy(databaseWriter, snapshot); err != nil {
+		return nil, fmt.Errorf("write backup database: %w", err)
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("finish backup archive: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func readManifest(archiveData []byte) (Manifest, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archiveData), int64(len(archiveData)))
+	if err != nil {
+		return Manifest{}, fmt.Errorf("open backup archive: %w", err)
+	}
+
+	file, err := reader.Open(manifestEntryName)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("backup manifest is missing: %w", err)
+	}
+	defer file.Close()
+
+	var manifest Manifest
+	if err := json.NewDecoder(file).Decode(&manifest); err != nil {
+		return Manifest{}, fmt.Errorf("decode backup manifest: %w", err)
+	}
+	if manifest.FormatVersion > archiveFormatVersion {
+		return Manifest{}, fmt.Errorf("backup format %d is newer than this version of Ben supports", manifest.FormatVersion)
+	}
+
+	return manifest, nil
+}
+
+func extractDatabase(archiveData []byte, destination string) error {
+	reader, err := zip.NewReader(bytes.NewReader(archiveData), int64(len(archiveData)))
+	if err != nil {
+		return fmt.Errorf("open backup archive: %w", err)
+	}
+
+	source, err := reader.Open(databaseEntryName)
+	if err != nil {
+		return fmt.Errorf("backup database is missing: %w", err)
+	}
+	defer source.Close()
+
+	if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+		return fmt.Errorf("create restore folder: %w", err)
+	}
+
+	target, err := os.Create(destination)
+	if err != nil {
+		return fmt.Errorf("create restore file: %w", err)
+	}
+
+	if _, err := io.Copy(target, source); err != nil {
+		target.Close()
+		os.Remove(destination)
+		return fmt.Errorf("extract backup database: %w", err)
+	}
+
+	return target.Close()
+}
@@ -0,0 +1,106 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// Encrypted backups start with a fixed magic header followed by the key
+// derivation salt and the AES-GCM nonce.
+var encryptedMagic = []byte("BENBAK1\n")
+
+const (
+	saltSize         = 16
+	keySize          = 32
+	pbkdf2Iterations = 600_000
+)
+
+var ErrWrongPassphrase = errors.New("backup passphrase is incorrect or the backup is damaged")
+
+var ErrNotABackup = errors.New("file is not a Ben backup")
+
+func encrypt(plaintext []byte, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("backup passphrase is required")
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generate backup salt: %w", err)
+	}
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate backup nonce: %w", err)
+	}
+
+	header := make([]byte, 0, len(encryptedMagic)+len(salt)+len(nonce))
+	header = append(header, encryptedMagic...)
+	header = append(header, salt...)
+	header = append(header, nonce...)
+
+	// The header is authenticated as additional data so a tampered salt or
+	// nonce fails the same way as tampered ciphertext.
+	return aead.Seal(header, nonce, plaintext, header), nil
+}
+
+func decrypt(payload []byte, passphrase string) ([]byte, error) {
+	if !bytes.HasPrefix(payload, encryptedMagic) {
+		return nil, ErrNotABackup
+	}
+
+	rest := payload[len(encryptedMagic):]
+	if len(rest) < saltSize {
+		return nil, ErrNotABackup
+	}
+	salt := rest[:saltSize]
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	headerSize := len(encryptedMagic) + saltSize + aead.NonceSize()
+	if len(payload) < headerSize+aead.Overhead() {
+		return nil, ErrNotABackup
+	}
+
+	header := payload[:headerSize]
+	nonce := payload[len(encryptedMagic)+saltSize : headerSize]
+	plaintext, err := aead.Open(nil, nonce, payload[headerSize:], header)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	return plaintext, nil
+}
+
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("derive backup key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create backup cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create backup cipher mode: %w", err)
+	}
+
+	return aead, nil
+}
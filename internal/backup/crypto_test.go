@@ -0,0 +1,48 @@
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestEncryptRoundTripsAndRejectsWrongPassphrase(t *testing.T) {
+	t.Parallel()
+
+	plaintext := []byte("library snapshot")
+	payload, err := encrypt(plaintext, "correct horse")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if bytes.Contains(payload, plaintext) {
+		t.Fatalf("payload contains plaintext")
+	}
+
+	decrypted, err := decrypt(payload, "correct horse")
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Fatalf("unexpected plaintext %q", decrypted)
+	}
+
+	if _, err := decrypt(payload, "wrong"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("expected wrong passphrase error, got %v", err)
+	}
+	if _, err := decrypt([]byte("not a backup"), "correct horse"); !errors.Is(err, ErrNotABackup) {
+		t.Fatalf("expected not a backup error, got %v", err)
+	}
+}
+
+func TestFilterBackupNamesSortsNewestFirst(t *testing.T) {
+	t.Parallel()
+
+	names := filterBackupNames([]string{
+		"ben-backup-20260101T000000Z.benbak",
+		"notes.txt",
+		"ben-backup-20260301T000000Z.benbak",
+	})
+	if len(names) != 2 || names[0] != "ben-backup-20260301T000000Z.benbak" {
+		t.Fatalf("unexpected backup names %v", names)
+	}
+}
@@ -0,0 +1,283 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// s3Target stores backups in an S3-compatible bucket using path-style
+// requests, which AWS, MinIO, Backblaze B2 and most NAS gateways accept.
+type s3Target struct {
+	endpoint  *url.URL
+	bucket    string
+	prefix    string
+	region    string
+	accessKey string
+	secretKey string
+	http      *http.Client
+	now       func() time.Time
+}
+
+func newS3Target(config Config) (*s3Target, error) {
+	endpoint := strings.TrimSpace(config.URL)
+	if endpoint == "" {
+		endpoint = "https://s3.amazonaws.com"
+	}
+
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", config.URL)
+	}
+	if strings.TrimSpace(config.Bucket) == "" {
+		return nil, errors.New("s3 bucket is required")
+	}
+	if config.AccessKey == "" || config.SecretKey == "" {
+		return nil, errors.New("s3 access key and secret key are required")
+	}
+
+	region := strings.TrimSpace(config.Region)
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	prefix := strings.Trim(strings.TrimSpace(config.Prefix), "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	return &s3Target{
+		endpoint:  parsed,
+		bucket:    strings.TrimSpace(config.Bucket),
+		prefix:    prefix,
+		region:    region,
+		accessKey: config.AccessKey,
+		secretKey: config.SecretKey,
+		http:      &http.Client{},
+		now:       time.Now,
+	}, nil
+}
+
+func (t *s3Target) Put(ctx context.Context, name string, data []byte) error {
+	response, err := t.do(ctx, http.MethodPut, t.prefix+name, nil, data)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return s3ResponseError("upload backup", response)
+	}
+
+	return nil
+}
+
+func (t *s3Target) Get(ctx context.Context, name string) ([]byte, error) {
+	response, err := t.do(ctx, http.MethodGet, t.prefix+name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, s3ResponseError("download backup", response)
+	}
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("download backup: %w", err)
+	}
+
+	return data, nil
+}
+
+func (t *s3Target) List(ctx context.Context) ([]string, error) {
+	names := make([]string, 0)
+	continuation := ""
+
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", t.prefix)
+		if continuation != "" {
+			query.Set("continuation-token", continuation)
+		}
+
+		response, err := t.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode != http.StatusOK {
+			err := s3ResponseError("list backups", response)
+			response.Body.Close()
+			return nil, err
+		}
+
+		var result struct {
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		decodeErr := xml.NewDecoder(response.Body).Decode(&result)
+		response.Body.Close()
+		if decodeErr != nil {
+			return nil, fmt.Errorf("decode backup listing: %w", decodeErr)
+		}
+
+		for _, object := range result.Contents {
+			name := strings.TrimPrefix(object.Key, t.prefix)
+			if name != "" && !strings.Contains(name, "/") {
+				names = append(names, name)
+			}
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		continuation = result.NextContinuationToken
+	}
+
+	return filterBackupNames(names), nil
+}
+
+func (t *s3Target) do(ctx context.Context, method string, key string, query url.Values, body []byte) (*http.Response, error) {
+	requestURL := *t.endpoint
+	requestURL.Path = "/" + t.bucket + "/" + key
+	requestURL.RawPath = canonicalURIPath(requestURL.Path)
+	requestURL.RawQuery = canonicalQueryString(query)
+
+	request, err := http.NewRequestWithContext(ctx, method, requestURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build s3 request: %w", err)
+	}
+	request.ContentLength = int64(len(body))
+
+	t.sign(request, body)
+
+	response, err := t.http.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s %s: %w", method, key, err)
+	}
+
+	return response, nil
+}
+
+// sign applies AWS Signature Version 4 headers to request.
+func (t *s3Target) sign(request *http.Request, body []byte) {
+	now := t.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	shortDate := now.Format("20060102")
+
+	payloadHash := sha256.Sum256(body)
+	payloadHex := hex.EncodeToString(payloadHash[:])
+
+	request.Header.Set("x-amz-date", amzDate)
+	request.Header.Set("x-amz-content-sha256", payloadHex)
+
+	canonicalHeaders := "host:" + request.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHex + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		canonicalURIPath(request.URL.Path),
+		request.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHex,
+	}, "\n")
+
+	scope := shortDate + "/" + t.region + "/s3/aws4_request"
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+t.secretKey), shortDate)
+	signingKey = hmacSHA256(signingKey, t.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	request.Header.Set(
+		"Authorization",
+		"AWS4-HMAC-SHA256 Credential="+t.accessKey+"/"+scope+", SignedHeaders="+signedHeaders+", Signature="+signature,
+	)
+}
+
+func hmacSHA256(key []byte, value string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+func canonicalURIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = s3Escape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func canonicalQueryString(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, s3Escape(key)+"="+s3Escape(value))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+// s3Escape percent-encodes everything except RFC 3986 unreserved
+// characters, as SigV4 requires.
+func s3Escape(value string) string {
+	var builder strings.Builder
+	for _, b := range []byte(value) {
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') ||
+			b == '-' || b == '_' || b == '.' || b == '~' {
+			builder.WriteByte(b)
+			continue
+		}
+		fmt.Fprintf(&builder, "%%%02X", b)
+	}
+
+	return builder.String()
+}
+
+func s3ResponseError(action string, response *http.Response) error {
+	var body struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if err := xml.NewDecoder(io.LimitReader(response.Body, 64<<10)).Decode(&body); err == nil && body.Code != "" {
+		return fmt.Errorf("%s: %s: %s", action, body.Code, body.Message)
+	}
+
+	return fmt.Errorf("%s: %s", action, response.Status)
+}
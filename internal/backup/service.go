@@ -0,0 +1,525 @@
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rzxx/ben/internal/secrets"
+	"github.com/rzxx/ben/internal/settings"
+)
+
+const (
+	ConfigSettingKey    = "backup.config"
+	LastRunSettingKey   = "backup.last_run_at"
+	LastErrorSettingKey = "backup.last_error"
+)
+
+// Keychain entries of the backup secrets. The config in app_settings never
+// holds them, so they do not end up in the backups they protect.
+const (
+	passwordSecretKey   = "backup/password"
+	secretKeySecretKey  = "backup/secret-key"
+	passphraseSecretKey = "backup/passphrase"
+)
+
+const schedulerTick = 10 * time.Minute
+
+// pendingRestoreSuffix names the staged database that replaces the live one
+// on the next start; the open database cannot be swapped while running.
+const pendingRestoreSuffix = ".restore"
+
+var ErrRestoreConflict = errors.New("this machine already has a library; confirm replacing it to restore")
+
+var ErrBackupRunning = errors.New("a backup is already running")
+
+// Config describes the backup destination and schedule. Secrets are kept in
+// the keychain and never returned to the frontend; leaving them empty in
+// SetConfig keeps the stored values.
+type Config struct {
+	Kind          string `json:"kind"`
+	URL           string `json:"url"`
+	Username      string `json:"username,omitempty"`
+	Password      string `json:"password,omitempty"`
+	Bucket        string `json:"bucket,omitempty"`
+	Region        string `json:"region,omitempty"`
+	Prefix        string `json:"prefix,omitempty"`
+	AccessKey     string `json:"accessKey,omitempty"`
+	SecretKey     string `json:"secretKey,omitempty"`
+	Passphrase    string `json:"passphrase,omitempty"`
+	IntervalHours int    `json:"intervalHours"`
+}
+
+type Status struct {
+	Config        Config  `json:"config"`
+	HasPassphrase bool    `json:"hasPassphrase"`
+	Running       bool    `json:"running"`
+	LastRunAt     *string `json:"lastRunAt,omitempty"`
+	LastError     *string `json:"lastError,omitempty"`
+}
+
+type Result struct {
+	Name      string `json:"name"`
+	CreatedAt string `json:"createdAt"`
+	SizeBytes int    `json:"sizeBytes"`
+}
+
+type RestorePreview struct {
+	Name               string   `json:"name"`
+	Manifest           Manifest `json:"manifest"`
+	LocalTrackCount    int      `json:"localTrackCount"`
+	LocalPlaylistCount int      `json:"localPlaylistCount"`
+	Conflict           bool     `json:"conflict"`
+	RestartRequired    bool     `json:"restartRequired"`
+}
+
+type Service struct {
+	mu      sync.Mutex
+	db      *sql.DB
+	store   *settings.Store
+	secrets *secrets.Store
+	dbPath  string
+	running bool
+	stop    chan struct{}
+}
+
+func NewService(database *sql.DB, store *settings.Store, secretStore *secrets.Store, dbPath string) *Service {
+	return &Service{
+		db:      database,
+		store:   store,
+		secrets: secretStore,
+		dbPath:  dbPath,
+	}
+}
+
+func (s *Service) Status(ctx context.Context) (Status, error) {
+	config, err := s.loadConfig(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+
+	s.mu.Lock()
+	running := s.running
+	s.mu.Unlock()
+
+	status := Status{
+		Config:        redactConfig(config),
+		HasPassphrase: config.Passphrase != "",
+		Running:       running,
+	}
+	if value, ok, _ := s.store.Get(ctx, LastRunSettingKey); ok {
+		status.LastRunAt = &value
+	}
+	if value, ok, _ := s.store.Get(ctx, LastErrorSettingKey); ok && value != "" {
+		status.LastError = &value
+	}
+
+	return status, nil
+}
+
+func (s *Service) SetConfig(ctx context.Context, config Config) (Status, error) {
+	current, err := s.loadConfig(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+
+	config.Kind = strings.ToLower(strings.TrimSpace(config.Kind))
+	config.URL = strings.TrimSpace(config.URL)
+	if config.IntervalHours < 0 {
+		return Status{}, errors.New("backup interval cannot be negative")
+	}
+	if config.Password == "" {
+		config.Password = current.Password
+	}
+	if config.SecretKey == "" {
+		config.SecretKey = current.SecretKey
+	}
+	if config.Passphrase == "" {
+		config.Passphrase = current.Passphrase
+	}
+
+	if config.Kind != "" {
+		if _, err := newTarget(config); err != nil {
+			return Status{}, err
+		}
+	}
+
+	if err := s.storeSecrets(config); err != nil {
+		return Status{}, err
+	}
+	if err := s.saveConfig(ctx, config); err != nil {
+		return Status{}, err
+	}
+
+	return s.Status(ctx)
+}
+
+// BackupNow snapshots the database, encrypts it and uploads it to the
+// configured destination.
+func (s *Service) BackupNow(ctx context.Context) (Result, error) {
+	if !s.beginRun() {
+		return Result{}, ErrBackupRunning
+	}
+	defer s.endRun()
+
+	result, err := s.runBackup(ctx)
+
+	lastError := ""
+	if err != nil {
+		lastError = err.Error()
+	}
+	_ = s.store.Set(ctx, LastErrorSettingKey, lastError)
+	if err == nil {
+		_ = s.store.Set(ctx, LastRunSettingKey, result.CreatedAt)
+	}
+
+	return result, err
+}
+
+func (s *Service) ListBackups(ctx context.Context) ([]string, error) {
+	config, err := s.loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := newTarget(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return target.List(ctx)
+}
+
+// PreviewRestore decrypts a backup and compares it with the local library
+// without changing anything.
+func (s *Service) PreviewRestore(ctx context.Context, name string, passphrase string) (RestorePreview, error) {
+	preview, _, err := s.fetchBackup(ctx, name, passphrase)
+	return preview, err
+}
+
+// Restore stages a backup to replace the local database on the next start.
+// When the local library already has tracks or playlists the restore is
+// refused with ErrRestoreConflict unless replaceExisting is set; the
+// replaced database is kept next to the new one rather than deleted.
+func (s *Service) Restore(ctx context.Context, name string, passphrase string, replaceExisting bool) (RestorePreview, error) {
+	preview, archiveData, err := s.fetchBackup(ctx, name, passphrase)
+	if err != nil {
+		return RestorePreview{}, err
+	}
+	if preview.Conflict && !replaceExisting {
+		return preview, ErrRestoreConflict
+	}
+
+	if err := extractDatabase(archiveData, s.dbPath+pendingRestoreSuffix); err != nil {
+		return RestorePreview{}, err
+	}
+
+	preview.RestartRequired = true
+	return preview, nil
+}
+
+func (s *Service) Start() {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	stopCh := make(chan struct{})
+	s.stop = stopCh
+	s.mu.Unlock()
+
+	go s.scheduleLoop(stopCh)
+}
+
+func (s *Service) Stop() {
+	s.mu.Lock()
+	stopCh := s.stop
+	s.stop = nil
+	s.mu.Unlock()
+
+	if stopCh != nil {
+		close(stopCh)
+	}
+}
+
+func (s *Service) scheduleLoop(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(schedulerTick)
+	defer ticker.Stop()
+
+	for {
+		s.runScheduledBackup()
+
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) runScheduledBackup() {
+	ctx := context.Background()
+
+	config, err := s.loadConfig(ctx)
+	if err != nil || config.Kind == "" || config.Passphrase == "" || config.IntervalHours <= 0 {
+		return
+	}
+
+	if lastRun, ok, _ := s.store.Get(ctx, LastRunSettingKey); ok {
+		lastRunAt, parseErr := time.Parse(time.RFC3339, lastRun)
+		if parseErr == nil && time.Since(lastRunAt) < time.Duration(config.IntervalHours)*time.Hour {
+			return
+		}
+	}
+
+	if _, err := s.BackupNow(ctx); err != nil && !errors.Is(err, ErrBackupRunning) {
+		log.Printf("scheduled backup failed: %v", err)
+	}
+}
+
+func (s *Service) runBackup(ctx context.Context) (Result, error) {
+	config, err := s.loadConfig(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+
+	target, err := newTarget(config)
+	if err != nil {
+		return Result{}, err
+	}
+	if config.Passphrase == "" {
+		return Result{}, errors.New("backup passphrase is not configured")
+	}
+
+	counts, err := readLibraryCounts(ctx, s.db)
+	if err != nil {
+		return Result{}, err
+	}
+
+	snapshotDir, err := os.MkdirTemp("", "ben-backup-*")
+	if err != nil {
+		return Result{}, fmt.Errorf("create backup staging folder: %w", err)
+	}
+	defer os.RemoveAll(snapshotDir)
+
+	snapshotPath := filepath.Join(snapshotDir, databaseEntryName)
+	if err := snapshotDatabase(ctx, s.db, snapshotPath); err != nil {
+		return Result{}, err
+	}
+	if err := redactSnapshot(ctx, snapshotPath); err != nil {
+		return Result{}, err
+	}
+
+	createdAt := time.Now().UTC()
+	hostname, _ := os.Hostname()
+	archiveData, err := writeArchive(Manifest{
+		FormatVersion: archiveFormatVersion,
+		CreatedAt:     createdAt.Format(time.RFC3339),
+		Hostname:      hostname,
+		LastMigration: counts.lastMigration,
+		TrackCount:    counts.tracks,
+		PlaylistCount: counts.playlists,
+	}, snapshotPath)
+	if err != nil {
+		return Result{}, err
+	}
+
+	payload, err := encrypt(archiveData, config.Passphrase)
+	if err != nil {
+		return Result{}, err
+	}
+
+	name := "ben-backup-" + createdAt.Format("20060102T150405Z") + backupFileExtension
+	if err := target.Put(ctx, name, payload); err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Name:      name,
+		CreatedAt: createdAt.Format(time.RFC3339),
+		SizeBytes: len(payload),
+	}, nil
+}
+
+func (s *Service) fetchBackup(ctx context.Context, name string, passphrase string) (RestorePreview, []byte, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return RestorePreview{}, nil, errors.New("invalid backup name")
+	}
+
+	config, err := s.loadConfig(ctx)
+	if err != nil {
+		return RestorePreview{}, nil, err
+	}
+	if passphrase == "" {
+		passphrase = config.Passphrase
+	}
+
+	target, err := newTarget(config)
+	if err != nil {
+		return RestorePreview{}, nil, err
+	}
+
+	payload, err := target.Get(ctx, name)
+	if err != nil {
+		return RestorePreview{}, nil, err
+	}
+
+	archiveData, err := decrypt(payload, passphrase)
+	if err != nil {
+		return RestorePreview{}, nil, err
+	}
+
+	manifest, err := readManifest(archiveData)
+	if err != nil {
+		return RestorePreview{}, nil, err
+	}
+
+	local, err := readLibraryCounts(ctx, s.db)
+	if err != nil {
+		return RestorePreview{}, nil, err
+	}
+	// Migration file names sort by number, so a later name means the backup
+	// carries schema this build cannot open.
+	if manifest.LastMigration > local.lastMigration {
+		return RestorePreview{}, nil, fmt.Errorf("backup was made by a newer version of Ben (schema %s)", manifest.LastMigration)
+	}
+
+	return RestorePreview{
+		Name:               name,
+		Manifest:           manifest,
+		LocalTrackCount:    local.tracks,
+		LocalPlaylistCount: local.playlists,
+		Conflict:           local.tracks > 0 || local.playlists > 0,
+	}, archiveData, nil
+}
+
+// loadConfig returns the saved config with its secrets read from the
+// keychain. Secrets that older versions saved in the config are moved to
+// the keychain first; if the keychain refuses them they stay where they
+// are, and backups still strip them from the snapshot.
+func (s *Service) loadConfig(ctx context.Context) (Config, error) {
+	value, ok, err := s.store.Get(ctx, ConfigSettingKey)
+	if err != nil || !ok {
+		return Config{}, err
+	}
+
+	var config Config
+	if err := json.Unmarshal([]byte(value), &config); err != nil {
+		return Config{}, fmt.Errorf("decode backup config: %w", err)
+	}
+
+	if config.Password != "" || config.SecretKey != "" || config.Passphrase != "" {
+		if err := s.storeSecrets(config); err != nil {
+			log.Printf("move backup secrets to the keychain: %v", err)
+			return config, nil
+		}
+		if err := s.saveConfig(ctx, config); err != nil {
+			return Config{}, err
+		}
+	}
+
+	for key, secret := range map[string]*string{
+		passwordSecretKey:   &config.Password,
+		secretKeySecretKey:  &config.SecretKey,
+		passphraseSecretKey: &config.Passphrase,
+	} {
+		value, _, err := s.secrets.Get(key)
+		if err != nil {
+			return Config{}, err
+		}
+		*secret = value
+	}
+
+	return config, nil
+}
+
+// saveConfig stores config without its secrets.
+func (s *Service) saveConfig(ctx context.Context, config Config) error {
+	encoded, err := json.Marshal(redactConfig(config))
+	if err != nil {
+		return fmt.Errorf("encode backup config: %w", err)
+	}
+
+	return s.store.Set(ctx, ConfigSettingKey, string(encoded))
+}
+
+// storeSecrets writes the secrets of config to the keychain. Empty secrets
+// are left alone; SetConfig fills them from the stored config first.
+func (s *Service) storeSecrets(config Config) error {
+	for key, secret := range map[string]string{
+		passwordSecretKey:   config.Password,
+		secretKeySecretKey:  config.SecretKey,
+		passphraseSecretKey: config.Passphrase,
+	} {
+		if secret == "" {
+			continue
+		}
+		if err := s.secrets.Set(key, secret); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) beginRun() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return false
+	}
+	s.running = true
+	return true
+}
+
+func (s *Service) endRun() {
+	s.mu.Lock()
+	s.running = false
+	s.mu.Unlock()
+}
+
+func redactConfig(config Config) Config {
+	config.Password = ""
+	config.SecretKey = ""
+	config.Passphrase = ""
+	return config
+}
+
+// ApplyPendingRestore swaps a staged restore into place before the database
+// is opened. The replaced database and its WAL files are renamed rather than
+// deleted so a mistaken restore can be undone by hand.
+func ApplyPendingRestore(dbPath string) (bool, error) {
+	stagedPath := dbPath + pendingRestoreSuffix
+	if _, err := os.Stat(stagedPath); errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("check staged restore: %w", err)
+	}
+
+	keptSuffix := ".before-restore-" + time.Now().UTC().Format("20060102T150405Z")
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		currentPath := dbPath + suffix
+		if _, err := os.Stat(currentPath); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err := os.Rename(currentPath, dbPath+keptSuffix+suffix); err != nil {
+			return false, fmt.Errorf("keep replaced database: %w", err)
+		}
+	}
+
+	if err := os.Rename(stagedPath, dbPath); err != nil {
+		return false, fmt.Errorf("apply staged restore: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,152 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rzxx/ben/internal/db"
+	"github.com/rzxx/ben/internal/secrets"
+	"github.com/rzxx/ben/internal/settings"
+	"github.com/zalando/go-keyring"
+)
+
+func TestSetConfigKeepsSecretsInTheKeychain(t *testing.T) {
+	keyring.MockInit()
+	service, store := newBackupServiceForTest(t)
+	ctx := context.Background()
+
+	if _, err := service.SetConfig(ctx, Config{
+		Kind:       TargetKindWebDAV,
+		URL:        "https://nas.local/backups",
+		Username:   "alice",
+		Password:   "dav-secret",
+		Passphrase: "correct horse",
+	}); err != nil {
+		t.Fatalf("set config: %v", err)
+	}
+
+	stored, _, err := store.Get(ctx, ConfigSettingKey)
+	if err != nil {
+		t.Fatalf("read stored config: %v", err)
+	}
+	if strings.Contains(stored, "dav-secret") || strings.Contains(stored, "correct horse") {
+		t.Fatalf("expected no secrets in app_settings, got %s", stored)
+	}
+
+	// Empty secrets keep the stored ones.
+	status, err := service.SetConfig(ctx, Config{Kind: TargetKindWebDAV, URL: "https://nas.local/backups", Username: "alice", IntervalHours: 24})
+	if err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+	if !status.HasPassphrase || status.Config.Password != "" || status.Config.Passphrase != "" {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	config, err := service.loadConfig(ctx)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if config.Password != "dav-secret" || config.Passphrase != "correct horse" || config.IntervalHours != 24 {
+		t.Fatalf("unexpected loaded config %+v", config)
+	}
+}
+
+func TestLoadConfigMovesLegacySecretsToTheKeychain(t *testing.T) {
+	keyring.MockInit()
+	service, store := newBackupServiceForTest(t)
+	ctx := context.Background()
+
+	legacy := `{"kind":"s3","url":"https://s3.local","bucket":"b","accessKey":"AK","secretKey":"legacy-secret","passphrase":"legacy-pass","intervalHours":0}`
+	if err := store.Set(ctx, ConfigSettingKey, legacy); err != nil {
+		t.Fatalf("store legacy config: %v", err)
+	}
+
+	config, err := service.loadConfig(ctx)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if config.SecretKey != "legacy-secret" || config.Passphrase != "legacy-pass" || config.AccessKey != "AK" {
+		t.Fatalf("unexpected loaded config %+v", config)
+	}
+
+	stored, _, _ := store.Get(ctx, ConfigSettingKey)
+	if strings.Contains(stored, "legacy-secret") || strings.Contains(stored, "legacy-pass") {
+		t.Fatalf("expected the legacy secrets to leave app_settings, got %s", stored)
+	}
+	if value, _, _ := service.secrets.Get(secretKeySecretKey); value != "legacy-secret" {
+		t.Fatalf("expected the secret key in the keychain, got %q", value)
+	}
+}
+
+func TestRedactSnapshotStripsStoredSecrets(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.Bootstrap(filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatalf("bootstrap db: %v", err)
+	}
+	defer database.Close()
+	ctx := context.Background()
+
+	store := settings.NewStore(database)
+	if err := store.Set(ctx, ConfigSettingKey, `{"kind":"webdav","url":"https://nas.local","password":"snapshot-secret-1","passphrase":"snapshot-secret-2"}`); err != nil {
+		t.Fatalf("store config: %v", err)
+	}
+	if _, err := database.Exec(
+		"INSERT INTO watched_roots(path, enabled, kind, remote_password) VALUES ('https://nas.local/music/', 1, 'webdav', 'snapshot-secret-3')",
+	); err != nil {
+		t.Fatalf("insert remote root: %v", err)
+	}
+
+	snapshotPath := filepath.Join(t.TempDir(), databaseEntryName)
+	if err := snapshotDatabase(ctx, database, snapshotPath); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	if err := redactSnapshot(ctx, snapshotPath); err != nil {
+		t.Fatalf("redact snapshot: %v", err)
+	}
+
+	snapshot, err := sql.Open("sqlite", snapshotPath)
+	if err != nil {
+		t.Fatalf("open snapshot: %v", err)
+	}
+	defer snapshot.Close()
+
+	var value string
+	if err := snapshot.QueryRow("SELECT value FROM app_settings WHERE key = ?", ConfigSettingKey).Scan(&value); err != nil {
+		t.Fatalf("read snapshot config: %v", err)
+	}
+	if !strings.Contains(value, "nas.local") || strings.Contains(value, "password") || strings.Contains(value, "passphrase") {
+		t.Fatalf("expected the destination without secrets, got %s", value)
+	}
+	snapshot.Close()
+
+	content, err := os.ReadFile(snapshotPath)
+	if err != nil {
+		t.Fatalf("read snapshot file: %v", err)
+	}
+	if bytes.Contains(content, []byte("snapshot-secret")) {
+		t.Fatalf("expected no secret left in the snapshot file")
+	}
+}
+
+func newBackupServiceForTest(t *testing.T) (*Service, *settings.Store) {
+	t.Helper()
+
+	dbPath := filepath.Join(t.TempDir(), "library.db")
+	database, err := db.Bootstrap(dbPath)
+	if err != nil {
+		t.Fatalf("bootstrap db: %v", err)
+	}
+	t.Cleanup(func() {
+		database.Close()
+	})
+
+	store := settings.NewStore(database)
+	return NewService(database, store, secrets.NewStore("ben-test"), dbPath), store
+}
@@ -0,0 +1,116 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/remote"
+)
+
+const (
+	TargetKindWebDAV = "webdav"
+	TargetKindS3     = "s3"
+)
+
+// backupFileExtension marks encrypted backup objects so listings can skip
+// unrelated files that share the destination folder or bucket prefix.
+const backupFileExtension = ".benbak"
+
+// Target is a remote location that stores backup objects by name.
+type Target interface {
+	Put(ctx context.Context, name string, data []byte) error
+	Get(ctx context.Context, name string) ([]byte, error)
+	List(ctx context.Context) ([]string, error)
+}
+
+func newTarget(config Config) (Target, error) {
+	switch config.Kind {
+	case TargetKindWebDAV:
+		rootURL, urlUsername, urlPassword, err := remote.NormalizeURL(config.URL)
+		if err != nil {
+			return nil, err
+		}
+		if remote.KindOf(rootURL) != library.RootKindWebDAV {
+			return nil, errors.New("backup destination must be a WebDAV address")
+		}
+
+		username := config.Username
+		password := config.Password
+		if username == "" {
+			username = urlUsername
+			password = urlPassword
+		}
+
+		client, err := remote.NewClient(rootURL, username, password)
+		if err != nil {
+			return nil, err
+		}
+		return &webDAVTarget{client: client}, nil
+	case TargetKindS3:
+		return newS3Target(config)
+	case "":
+		return nil, errors.New("backup destination is not configured")
+	default:
+		return nil, fmt.Errorf("unsupported backup destination %q", config.Kind)
+	}
+}
+
+type webDAVTarget struct {
+	client *remote.Client
+}
+
+func (t *webDAVTarget) Put(ctx context.Context, name string, data []byte) error {
+	return t.client.Upload(ctx, name, data)
+}
+
+func (t *webDAVTarget) Get(ctx context.Context, name string) ([]byte, error) {
+	tempFile, err := os.CreateTemp("", "ben-backup-*")
+	if err != nil {
+		return nil, fmt.Errorf("create backup download file: %w", err)
+	}
+	tempPath := tempFile.Name()
+	tempFile.Close()
+	defer os.Remove(tempPath)
+
+	if err := t.client.Download(ctx, t.client.FileURL(name), tempPath); err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(tempPath)
+}
+
+func (t *webDAVTarget) List(ctx context.Context) ([]string, error) {
+	entries, err := t.client.Walk(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if strings.Contains(entry.RelativePath, "/") {
+			continue
+		}
+		names = append(names, entry.RelativePath)
+	}
+
+	return filterBackupNames(names), nil
+}
+
+func filterBackupNames(names []string) []string {
+	filtered := make([]string, 0, len(names))
+	for _, name := range names {
+		if path.Ext(name) == backupFileExtension {
+			filtered = append(filtered, name)
+		}
+	}
+
+	// Names embed a UTC timestamp, so reverse lexical order is newest first.
+	sort.Sort(sort.Reverse(sort.StringSlice(filtered)))
+	return filtered
+}
@@ -0,0 +1,117 @@
+package commandpalette
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	scoreMatch       = 1
+	scoreConsecutive = 5
+	scoreBoundary    = 8
+	scorePrefix      = 20
+	scoreExact       = 40
+	maxGapPenalty    = 3
+)
+
+// fuzzyScore matches the letters of query in order against candidate and
+// rewards runs of consecutive letters and matches at word starts. Whitespace
+// in the query is ignored so "dk sd" still finds "Dark Side".
+func fuzzyScore(query string, candidate string) (int, bool) {
+	needle := []rune(strings.Join(strings.Fields(strings.ToLower(query)), ""))
+	haystack := []rune(strings.ToLower(candidate))
+	if len(needle) == 0 {
+		return 0, true
+	}
+	if len(needle) > len(haystack) {
+		return 0, false
+	}
+
+	best := -1
+	for start, r := range haystack {
+		if r != needle[0] {
+			continue
+		}
+		if score, ok := scoreFrom(needle, haystack, start); ok && score > best {
+			best = score
+		}
+	}
+	if best < 0 {
+		return 0, false
+	}
+
+	trimmedCandidate := strings.ToLower(strings.TrimSpace(candidate))
+	trimmedQuery := strings.ToLower(strings.TrimSpace(query))
+	if trimmedCandidate == trimmedQuery {
+		best += scoreExact
+	} else if strings.HasPrefix(trimmedCandidate, trimmedQuery) {
+		best += scorePrefix
+	}
+
+	return best, true
+}
+
+func scoreFrom(needle []rune, haystack []rune, start int) (int, bool) {
+	score := 0
+	previous := -1
+	position := start
+	for _, r := range needle {
+		for position < len(haystack) && haystack[position] != r {
+			position++
+		}
+		if position >= len(haystack) {
+			return 0, false
+		}
+
+		score += scoreMatch
+		if isWordStart(haystack, position) {
+			score += scoreBoundary
+		}
+		if previous >= 0 {
+			if position == previous+1 {
+				score += scoreConsecutive
+			} else {
+				score -= min(position-previous-1, maxGapPenalty)
+			}
+		}
+
+		previous = position
+		position++
+	}
+
+	return score, true
+}
+
+func isWordStart(value []rune, index int) bool {
+	if index == 0 {
+		return true
+	}
+
+	previous := value[index-1]
+	return !unicode.IsLetter(previous) && !unicode.IsDigit(previous)
+}
+
+// likePattern turns a query into a LIKE pattern matching its letters in
+// order, so SQLite can pre-filter candidates before they are scored.
+func likePattern(query string) string {
+	var builder strings.Builder
+	builder.WriteString("%")
+	for _, r := range strings.ToLower(query) {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		if r == '%' || r == '_' || r == '\\' {
+			builder.WriteRune('\\')
+		}
+		builder.WriteRune(r)
+		builder.WriteString("%")
+	}
+
+	return builder.String()
+}
+
+// substringKey is the lowercased query used to rank candidates that contain
+// it verbatim ahead of scattered matches when SQLite trims the candidate set.
+func substringKey(query string) string {
+	return strings.ToLower(strings.TrimSpace(query))
+}
@@ -0,0 +1,57 @@
+package commandpalette
+
+import "testing"
+
+func TestFuzzyScoreMatchesLettersInOrder(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := fuzzyScore("dsm", "Dark Side of the Moon"); !ok {
+		t.Fatal("expected initials to match")
+	}
+	if _, ok := fuzzyScore("msd", "Dark Side of the Moon"); ok {
+		t.Fatal("expected out-of-order letters not to match")
+	}
+	if _, ok := fuzzyScore("dk sd", "Dark Side"); !ok {
+		t.Fatal("expected whitespace in the query to be ignored")
+	}
+}
+
+func TestFuzzyScoreRanksPrefixAndWordStartsHigher(t *testing.T) {
+	t.Parallel()
+
+	exact, _ := fuzzyScore("start full scan", "Start full scan")
+	prefix, _ := fuzzyScore("start", "Start full scan")
+	inner, _ := fuzzyScore("start", "Restart playback")
+	if !(exact > prefix && prefix > inner) {
+		t.Fatalf("unexpected ranking: exact=%d prefix=%d inner=%d", exact, prefix, inner)
+	}
+
+	boundary, _ := fuzzyScore("fs", "Full Scan")
+	scattered, _ := fuzzyScore("fs", "Offset")
+	if boundary <= scattered {
+		t.Fatalf("expected word starts to rank higher: boundary=%d scattered=%d", boundary, scattered)
+	}
+}
+
+func TestLikePatternEscapesWildcards(t *testing.T) {
+	t.Parallel()
+
+	if got := likePattern("A_b %"); got != `%a%\_%b%\%%` {
+		t.Fatalf("unexpected pattern %q", got)
+	}
+}
+
+func TestActionsIncludeKeyboardCommands(t *testing.T) {
+	t.Parallel()
+
+	seen := map[string]bool{}
+	for _, action := range Actions() {
+		if seen[action.ID] {
+			t.Fatalf("duplicate action %q", action.ID)
+		}
+		seen[action.ID] = true
+	}
+	if !seen["player.togglePlayback"] || !seen["scanner.fullScan"] {
+		t.Fatalf("expected keyboard and app actions, got %v", seen)
+	}
+}
@@ -0,0 +1,304 @@
+package commandpalette
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rzxx/ben/internal/keybindings"
+)
+
+const (
+	KindAction   = "action"
+	KindArtist   = "artist"
+	KindAlbum    = "album"
+	KindPlaylist = "playlist"
+	KindTrack    = "track"
+)
+
+const (
+	defaultResultLimit = 20
+	maxResultLimit     = 50
+	candidateLimit     = 60
+)
+
+// kindOrder breaks score ties so commands and broad matches come before
+// individual tracks.
+var kindOrder = map[string]int{
+	KindAction:   0,
+	KindArtist:   1,
+	KindAlbum:    2,
+	KindPlaylist: 3,
+	KindTrack:    4,
+}
+
+// Action is a palette command that is not bound to a library item.
+type Action struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Category string `json:"category"`
+}
+
+// appActions are invoked from the palette only; keyboard commands are added
+// from the keybindings registry.
+var appActions = []Action{
+	{ID: "scanner.fullScan", Title: "Start full scan", Category: "Library"},
+	{ID: "scanner.incrementalScan", Title: "Start incremental scan", Category: "Library"},
+	{ID: "backup.now", Title: "Back up library now", Category: "Library"},
+	{ID: "navigation.settings", Title: "Open settings", Category: "Navigation"},
+	{ID: "accessibility.toggleVerboseAnnouncements", Title: "Toggle verbose announcements", Category: "Accessibility"},
+}
+
+// Invocation tells the frontend what to do with a result; only the fields of
+// the result's kind are set.
+type Invocation struct {
+	CommandID   string `json:"commandId,omitempty"`
+	ArtistName  string `json:"artistName,omitempty"`
+	AlbumTitle  string `json:"albumTitle,omitempty"`
+	AlbumArtist string `json:"albumArtist,omitempty"`
+	PlaylistID  int64  `json:"playlistId,omitempty"`
+	TrackID     int64  `json:"trackId,omitempty"`
+}
+
+type Result struct {
+	Kind       string     `json:"kind"`
+	Title      string     `json:"title"`
+	Subtitle   string     `json:"subtitle,omitempty"`
+	Score      int        `json:"score"`
+	Invocation Invocation `json:"invocation"`
+}
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(database *sql.DB) *Service {
+	return &Service{db: database}
+}
+
+func Actions() []Action {
+	actions := make([]Action, 0, len(appActions))
+	for _, command := range keybindings.Commands() {
+		actions = append(actions, Action{ID: command.ID, Title: command.Title, Category: command.Category})
+	}
+
+	return append(actions, appActions...)
+}
+
+// Search fuzzy-matches query against actions, artists, albums, playlists and
+// tracks and returns the best results across all kinds.
+func (s *Service) Search(ctx context.Context, query string, limit int) ([]Result, error) {
+	if limit <= 0 {
+		limit = defaultResultLimit
+	}
+	if limit > maxResultLimit {
+		limit = maxResultLimit
+	}
+
+	if strings.TrimSpace(query) == "" {
+		return []Result{}, nil
+	}
+
+	results := make([]Result, 0)
+	for _, action := range Actions() {
+		score, ok := fuzzyScore(query, action.Title)
+		if !ok {
+			continue
+		}
+		results = append(results, Result{
+			Kind:       KindAction,
+			Title:      action.Title,
+			Subtitle:   action.Category,
+			Score:      score,
+			Invocation: Invocation{CommandID: action.ID},
+		})
+	}
+
+	for _, search := range []func(context.Context, string) ([]Result, error){
+		s.searchArtists,
+		s.searchAlbums,
+		s.searchPlaylists,
+		s.searchTracks,
+	} {
+		found, err := search(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, found...)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if kindOrder[results[i].Kind] != kindOrder[results[j].Kind] {
+			return kindOrder[results[i].Kind] < kindOrder[results[j].Kind]
+		}
+		return results[i].Title < results[j].Title
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+func (s *Service) searchArtists(ctx context.Context, query string) ([]Result, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT a.name
+		FROM artists a
+		WHERE LOWER(a.name) LIKE ? ESCAPE '\'
+		ORDER BY instr(LOWER(a.name), ?) = 0, LENGTH(a.name)
+		LIMIT ?
+	`, likePattern(query), substringKey(query), candidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("search artists: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]Result, 0)
+	for rows.Next() {
+		var name string
+		if scanErr := rows.Scan(&name); scanErr != nil {
+			return nil, fmt.Errorf("scan artist search row: %w", scanErr)
+		}
+		if score, ok := fuzzyScore(query, name); ok {
+			results = append(results, Result{
+				Kind:       KindArtist,
+				Title:      name,
+				Score:      score,
+				Invocation: Invocation{ArtistName: name},
+			})
+		}
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate artist search rows: %w", rowsErr)
+	}
+
+	return results, nil
+}
+
+func (s *Service) searchAlbums(ctx context.Context, query string) ([]Result, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album') AS album_title,
+			COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist') AS album_artist_name
+		FROM albums a
+		WHERE LOWER(COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album')) LIKE ? ESCAPE '\'
+		ORDER BY instr(LOWER(album_title), ?) = 0, LENGTH(album_title)
+		LIMIT ?
+	`, likePattern(query), substringKey(query), candidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("search albums: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]Result, 0)
+	for rows.Next() {
+		var title string
+		var albumArtist string
+		if scanErr := rows.Scan(&title, &albumArtist); scanErr != nil {
+			return nil, fmt.Errorf("scan album search row: %w", scanErr)
+		}
+		if score, ok := fuzzyScore(query, title); ok {
+			results = append(results, Result{
+				Kind:       KindAlbum,
+				Title:      title,
+				Subtitle:   albumArtist,
+				Score:      score,
+				Invocation: Invocation{AlbumTitle: title, AlbumArtist: albumArtist},
+			})
+		}
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate album search rows: %w", rowsErr)
+	}
+
+	return results, nil
+}
+
+func (s *Service) searchPlaylists(ctx context.Context, query string) ([]Result, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT p.id, p.name
+		FROM playlists p
+		WHERE LOWER(p.name) LIKE ? ESCAPE '\'
+		ORDER BY instr(LOWER(p.name), ?) = 0, LENGTH(p.name)
+		LIMIT ?
+	`, likePattern(query), substringKey(query), candidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("search playlists: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]Result, 0)
+	for rows.Next() {
+		var id int64
+		var name string
+		if scanErr := rows.Scan(&id, &name); scanErr != nil {
+			return nil, fmt.Errorf("scan playlist search row: %w", scanErr)
+		}
+		if score, ok := fuzzyScore(query, name); ok {
+			results = append(results, Result{
+				Kind:       KindPlaylist,
+				Title:      name,
+				Score:      score,
+				Invocation: Invocation{PlaylistID: id},
+			})
+		}
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate playlist search rows: %w", rowsErr)
+	}
+
+	return results, nil
+}
+
+func (s *Service) searchTracks(ctx context.Context, query string) ([]Result, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			t.id,
+			COALESCE(NULLIF(TRIM(t.title), ''), 'Unknown Title') AS track_title,
+			COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist') AS track_artist
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		WHERE f.file_exists = 1
+		  AND LOWER(COALESCE(NULLIF(TRIM(t.title), ''), 'Unknown Title')) LIKE ? ESCAPE '\'
+		ORDER BY instr(LOWER(track_title), ?) = 0, LENGTH(track_title)
+		LIMIT ?
+	`, likePattern(query), substringKey(query), candidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("search tracks: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]Result, 0)
+	for rows.Next() {
+		var id int64
+		var title string
+		var artist string
+		if scanErr := rows.Scan(&id, &title, &artist); scanErr != nil {
+			return nil, fmt.Errorf("scan track search row: %w", scanErr)
+		}
+		if score, ok := fuzzyScore(query, title); ok {
+			results = append(results, Result{
+				Kind:       KindTrack,
+				Title:      title,
+				Subtitle:   artist,
+				Score:      score,
+				Invocation: Invocation{TrackID: id},
+			})
+		}
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate track search rows: %w", rowsErr)
+	}
+
+	return results, nil
+}
@@ -7,9 +7,10 @@ import (
 )
 
 type Paths struct {
-	BaseDir       string
-	DBPath        string
-	CoverCacheDir string
+	BaseDir         string
+	DBPath          string
+	CoverCacheDir   string
+	ArchiveCacheDir string
 }
 
 func ResolvePaths(appSlug string) (Paths, error) {
@@ -20,6 +21,7 @@ func ResolvePaths(appSlug string) (Paths, error) {
 
 	baseDir := filepath.Join(configDir, appSlug)
 	coverCacheDir := filepath.Join(baseDir, "covers")
+	archiveCacheDir := filepath.Join(baseDir, "archive-cache")
 	dbPath := filepath.Join(baseDir, "library.db")
 
 	if err := os.MkdirAll(baseDir, 0o755); err != nil {
@@ -30,9 +32,14 @@ func ResolvePaths(appSlug string) (Paths, error) {
 		return Paths{}, fmt.Errorf("create cover cache dir: %w", err)
 	}
 
+	if err := os.MkdirAll(archiveCacheDir, 0o755); err != nil {
+		return Paths{}, fmt.Errorf("create archive cache dir: %w", err)
+	}
+
 	return Paths{
-		BaseDir:       baseDir,
-		DBPath:        dbPath,
-		CoverCacheDir: coverCacheDir,
+		BaseDir:         baseDir,
+		DBPath:          dbPath,
+		CoverCacheDir:   coverCacheDir,
+		ArchiveCacheDir: archiveCacheDir,
 	}, nil
 }
@@ -0,0 +1,154 @@
+package coverart
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"math/bits"
+	"os"
+	"sort"
+	"strconv"
+)
+
+const (
+	// perceptualSampleSize is the side of the grayscale square the DCT runs
+	// on; perceptualHashSize is the side of the low frequency block kept.
+	perceptualSampleSize = 32
+	perceptualHashSize   = 8
+)
+
+// PerceptualHashFromFile decodes the image at path with whichever formats
+// the caller has registered.
+func PerceptualHashFromFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open cover %s: %w", path, err)
+	}
+	defer file.Close()
+
+	decoded, _, err := image.Decode(file)
+	if err != nil {
+		return "", fmt.Errorf("decode cover %s: %w", path, err)
+	}
+
+	return PerceptualHashFromImage(decoded)
+}
+
+// PerceptualHashFromImage returns the 64-bit DCT hash of the image as 16
+// hex digits. Re-encoded, resized or slightly recolored copies of a cover
+// hash within a few bits of each other.
+func PerceptualHashFromImage(source image.Image) (string, error) {
+	bounds := source.Bounds()
+	if bounds.Dx() <= 0 || bounds.Dy() <= 0 {
+		return "", errors.New("cover image is empty")
+	}
+
+	// The image is squashed to a square, so the hash ignores cropping
+	// borders of a few pixels but not different aspect ratios.
+	luma := make([]float64, perceptualSampleSize*perceptualSampleSize)
+	for y := 0; y < perceptualSampleSize; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/perceptualSampleSize
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/perceptualSampleSize)
+		for x := 0; x < perceptualSampleSize; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/perceptualSampleSize
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/perceptualSampleSize)
+
+			sum, count := 0.0, 0.0
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					r, g, b, _ := source.At(sx, sy).RGBA()
+					sum += 0.299*float64(r>>8) + 0.587*float64(g>>8) + 0.114*float64(b>>8)
+					count++
+				}
+			}
+			luma[y*perceptualSampleSize+x] = sum / count
+		}
+	}
+
+	coefficients := lowFrequencyDCT(luma)
+	// The DC term is the average brightness and would dominate the median.
+	sorted := append([]float64(nil), coefficients[1:]...)
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+	for index, coefficient := range coefficients {
+		if coefficient > median {
+			hash |= 1 << uint(63-index)
+		}
+	}
+
+	return fmt.Sprintf("%016x", hash), nil
+}
+
+// PerceptualHash is a parsed hash from PerceptualHashFromImage.
+type PerceptualHash uint64
+
+// ParsePerceptualHash parses the 16 hex digits of a hash, reporting false
+// for anything else.
+func ParsePerceptualHash(value string) (PerceptualHash, bool) {
+	if len(value) != 16 {
+		return 0, false
+	}
+	hash, err := strconv.ParseUint(value, 16, 64)
+	if err != nil {
+		return 0, false
+	}
+	return PerceptualHash(hash), true
+}
+
+// Distance returns the number of bits h and other differ in.
+func (h PerceptualHash) Distance(other PerceptualHash) int {
+	return bits.OnesCount64(uint64(h ^ other))
+}
+
+// PerceptualHashDistance returns the number of differing bits of two
+// hashes from PerceptualHashFromImage, or false if either is not one.
+func PerceptualHashDistance(left string, right string) (int, bool) {
+	leftHash, leftOK := ParsePerceptualHash(left)
+	rightHash, rightOK := ParsePerceptualHash(right)
+	if !leftOK || !rightOK {
+		return 0, false
+	}
+
+	return leftHash.Distance(rightHash), true
+}
+
+// lowFrequencyDCT returns the top-left perceptualHashSize square of the 2D
+// DCT-II of the perceptualSampleSize square samples, row by row.
+func lowFrequencyDCT(samples []float64) []float64 {
+	const n = perceptualSampleSize
+
+	cosines := make([]float64, perceptualHashSize*n)
+	for u := 0; u < perceptualHashSize; u++ {
+		for x := 0; x < n; x++ {
+			cosines[u*n+x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * n))
+		}
+	}
+
+	// Rows first, then columns, keeping only the frequencies the hash uses.
+	rows := make([]float64, n*perceptualHashSize)
+	for y := 0; y < n; y++ {
+		for u := 0; u < perceptualHashSize; u++ {
+			sum := 0.0
+			for x := 0; x < n; x++ {
+				sum += samples[y*n+x] * cosines[u*n+x]
+			}
+			rows[y*perceptualHashSize+u] = sum
+		}
+	}
+
+	coefficients := make([]float64, perceptualHashSize*perceptualHashSize)
+	for v := 0; v < perceptualHashSize; v++ {
+		for u := 0; u < perceptualHashSize; u++ {
+			sum := 0.0
+			for y := 0; y < n; y++ {
+				sum += rows[y*perceptualHashSize+u] * cosines[v*n+y]
+			}
+			coefficients[v*perceptualHashSize+u] = sum
+		}
+	}
+
+	return coefficients
+}
@@ -0,0 +1,75 @@
+package coverart
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// testCover draws a disc and a bar on a gradient, scaled to size, with
+// tint added to the green channel.
+func testCover(size int, tint uint8) *image.NRGBA {
+	source := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			fx, fy := float64(x)/float64(size), float64(y)/float64(size)
+			value := uint8(40 + 80*fy)
+			if (fx-0.35)*(fx-0.35)+(fy-0.4)*(fy-0.4) < 0.04 {
+				value = 230
+			}
+			if fy > 0.75 && fy < 0.85 && fx > 0.5 {
+				value = 10
+			}
+			source.Set(x, y, color.NRGBA{R: value, G: value/2 + tint, B: value / 3, A: 255})
+		}
+	}
+	return source
+}
+
+func TestPerceptualHashMatchesResizedCopies(t *testing.T) {
+	t.Parallel()
+
+	original, err := PerceptualHashFromImage(testCover(600, 0))
+	if err != nil {
+		t.Fatalf("hash original: %v", err)
+	}
+	resized, err := PerceptualHashFromImage(testCover(150, 8))
+	if err != nil {
+		t.Fatalf("hash resized: %v", err)
+	}
+	if len(original) != 16 {
+		t.Fatalf("expected 16 hex digits, got %q", original)
+	}
+	if distance, ok := PerceptualHashDistance(original, resized); !ok || distance > 6 {
+		t.Fatalf("expected a close match, got distance %d (%v)", distance, ok)
+	}
+
+	flipped := image.NewNRGBA(image.Rect(0, 0, 600, 600))
+	source := testCover(600, 0)
+	for y := 0; y < 600; y++ {
+		for x := 0; x < 600; x++ {
+			flipped.Set(x, y, source.At(599-x, 599-y))
+		}
+	}
+	other, err := PerceptualHashFromImage(flipped)
+	if err != nil {
+		t.Fatalf("hash flipped: %v", err)
+	}
+	if distance, ok := PerceptualHashDistance(original, other); !ok || distance < 16 {
+		t.Fatalf("expected a different cover to be far, got distance %d (%v)", distance, ok)
+	}
+}
+
+func TestPerceptualHashDistanceRejectsInvalidHashes(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := PerceptualHashDistance("", "0000000000000000"); ok {
+		t.Fatal("expected an empty hash to be rejected")
+	}
+	if _, ok := PerceptualHashDistance("zz00000000000000", "0000000000000000"); ok {
+		t.Fatal("expected a malformed hash to be rejected")
+	}
+	if distance, ok := PerceptualHashDistance("ffffffffffffffff", "0000000000000000"); !ok || distance != 64 {
+		t.Fatalf("expected distance 64, got %d", distance)
+	}
+}
@@ -0,0 +1,209 @@
+package coverart
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"os"
+	"strings"
+)
+
+// placeholderSampleSize bounds the image the placeholder is computed from;
+// a blurred preview does not need more detail than this.
+const placeholderSampleSize = 32
+
+const (
+	blurHashComponentsX = 4
+	blurHashComponentsY = 3
+)
+
+const blurHashAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// Placeholder is what a grid paints before the real thumbnail arrives.
+type Placeholder struct {
+	DominantColor string
+	BlurHash      string
+}
+
+// PlaceholderFromFile decodes the image at path with whichever formats the
+// caller has registered.
+func PlaceholderFromFile(path string) (Placeholder, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Placeholder{}, fmt.Errorf("open cover %s: %w", path, err)
+	}
+	defer file.Close()
+
+	decoded, _, err := image.Decode(file)
+	if err != nil {
+		return Placeholder{}, fmt.Errorf("decode cover %s: %w", path, err)
+	}
+
+	return PlaceholderFromImage(decoded)
+}
+
+// PlaceholderFromImage returns the average color of the image as #rrggbb and
+// its BlurHash with 4x3 components.
+func PlaceholderFromImage(source image.Image) (Placeholder, error) {
+	pixels, width, height := sampleLinearPixels(source, placeholderSampleSize)
+	if width == 0 || height == 0 {
+		return Placeholder{}, errors.New("cover image is empty")
+	}
+
+	factors := make([][3]float64, 0, blurHashComponentsX*blurHashComponentsY)
+	for j := 0; j < blurHashComponentsY; j++ {
+		for i := 0; i < blurHashComponentsX; i++ {
+			factors = append(factors, blurHashFactor(pixels, width, height, i, j))
+		}
+	}
+
+	dc := factors[0]
+	dominant := fmt.Sprintf("#%02x%02x%02x", linearToSRGB(dc[0]), linearToSRGB(dc[1]), linearToSRGB(dc[2]))
+
+	return Placeholder{
+		DominantColor: dominant,
+		BlurHash:      encodeBlurHash(factors),
+	}, nil
+}
+
+// sampleLinearPixels box-filters the image down to at most maxSize pixels on
+// its longer side and converts the result to linear RGB.
+func sampleLinearPixels(source image.Image, maxSize int) ([][3]float64, int, int) {
+	bounds := source.Bounds()
+	sourceWidth := bounds.Dx()
+	sourceHeight := bounds.Dy()
+	if sourceWidth <= 0 || sourceHeight <= 0 {
+		return nil, 0, 0
+	}
+
+	width := min(sourceWidth, maxSize)
+	height := min(sourceHeight, maxSize)
+	if sourceWidth > sourceHeight {
+		height = max(1, sourceHeight*width/sourceWidth)
+	} else {
+		width = max(1, sourceWidth*height/sourceHeight)
+	}
+
+	pixels := make([][3]float64, width*height)
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*sourceHeight/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*sourceHeight/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*sourceWidth/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*sourceWidth/width)
+
+			var sum [3]float64
+			count := 0.0
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					r, g, b, _ := source.At(sx, sy).RGBA()
+					sum[0] += sRGBToLinear(int(r >> 8))
+					sum[1] += sRGBToLinear(int(g >> 8))
+					sum[2] += sRGBToLinear(int(b >> 8))
+					count++
+				}
+			}
+
+			pixels[y*width+x] = [3]float64{sum[0] / count, sum[1] / count, sum[2] / count}
+		}
+	}
+
+	return pixels, width, height
+}
+
+func blurHashFactor(pixels [][3]float64, width int, height int, componentX int, componentY int) [3]float64 {
+	normalisation := 2.0
+	if componentX == 0 && componentY == 0 {
+		normalisation = 1
+	}
+
+	var result [3]float64
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			basis := normalisation *
+				math.Cos(math.Pi*float64(componentX)*float64(x)/float64(width)) *
+				math.Cos(math.Pi*float64(componentY)*float64(y)/float64(height))
+			pixel := pixels[y*width+x]
+			result[0] += basis * pixel[0]
+			result[1] += basis * pixel[1]
+			result[2] += basis * pixel[2]
+		}
+	}
+
+	scale := 1 / float64(width*height)
+	result[0] *= scale
+	result[1] *= scale
+	result[2] *= scale
+
+	return result
+}
+
+func encodeBlurHash(factors [][3]float64) string {
+	var builder strings.Builder
+
+	sizeFlag := (blurHashComponentsX - 1) + (blurHashComponentsY-1)*9
+	builder.WriteString(encodeBase83(sizeFlag, 1))
+
+	maximumValue := 1.0
+	if len(factors) > 1 {
+		actualMaximum := 0.0
+		for _, factor := range factors[1:] {
+			actualMaximum = math.Max(actualMaximum, math.Max(math.Abs(factor[0]), math.Max(math.Abs(factor[1]), math.Abs(factor[2]))))
+		}
+		quantisedMaximum := int(math.Max(0, math.Min(82, math.Floor(actualMaximum*166-0.5))))
+		maximumValue = float64(quantisedMaximum+1) / 166
+		builder.WriteString(encodeBase83(quantisedMaximum, 1))
+	} else {
+		builder.WriteString(encodeBase83(0, 1))
+	}
+
+	dc := factors[0]
+	builder.WriteString(encodeBase83((linearToSRGB(dc[0])<<16)+(linearToSRGB(dc[1])<<8)+linearToSRGB(dc[2]), 4))
+
+	for _, factor := range factors[1:] {
+		quantR := quantiseAC(factor[0], maximumValue)
+		quantG := quantiseAC(factor[1], maximumValue)
+		quantB := quantiseAC(factor[2], maximumValue)
+		builder.WriteString(encodeBase83(quantR*19*19+quantG*19+quantB, 2))
+	}
+
+	return builder.String()
+}
+
+func quantiseAC(value float64, maximumValue float64) int {
+	quantised := math.Floor(signPow(value/maximumValue, 0.5)*9 + 9.5)
+	return int(math.Max(0, math.Min(18, quantised)))
+}
+
+func signPow(value float64, exponent float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exponent), value)
+}
+
+func encodeBase83(value int, length int) string {
+	encoded := make([]byte, length)
+	for i := 1; i <= length; i++ {
+		digit := (value / int(math.Pow(83, float64(length-i)))) % 83
+		encoded[i-1] = blurHashAlphabet[digit]
+	}
+
+	return string(encoded)
+}
+
+func sRGBToLinear(value int) float64 {
+	v := float64(value) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(value float64) int {
+	v := math.Max(0, math.Min(1, value))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
@@ -0,0 +1,52 @@
+package coverart
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestPlaceholderFromImageSolidColor(t *testing.T) {
+	t.Parallel()
+
+	source := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			source.Set(x, y, color.NRGBA{R: 200, G: 40, B: 90, A: 255})
+		}
+	}
+
+	placeholder, err := PlaceholderFromImage(source)
+	if err != nil {
+		t.Fatalf("placeholder: %v", err)
+	}
+
+	if placeholder.DominantColor != "#c8285a" {
+		t.Fatalf("expected #c8285a, got %s", placeholder.DominantColor)
+	}
+	if len(placeholder.BlurHash) != 4+2*blurHashComponentsX*blurHashComponentsY {
+		t.Fatalf("unexpected blurhash length %d: %s", len(placeholder.BlurHash), placeholder.BlurHash)
+	}
+	if placeholder.BlurHash[0] != 'L' {
+		t.Fatalf("expected 4x3 size flag, got %q", placeholder.BlurHash[0])
+	}
+}
+
+func TestPlaceholderFromImageRejectsEmptyImage(t *testing.T) {
+	t.Parallel()
+
+	if _, err := PlaceholderFromImage(image.NewNRGBA(image.Rect(0, 0, 0, 0))); err == nil {
+		t.Fatal("expected error for empty image")
+	}
+}
+
+func TestEncodeBase83(t *testing.T) {
+	t.Parallel()
+
+	if got := encodeBase83(0, 2); got != "00" {
+		t.Fatalf("expected 00, got %s", got)
+	}
+	if got := encodeBase83(83*83-1, 2); got != "~~" {
+		t.Fatalf("expected ~~, got %s", got)
+	}
+}
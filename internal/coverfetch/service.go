@@ -0,0 +1,160 @@
+// Package coverfetch downloads album art from Cover Art Archive or the
+// iTunes Search API for albums that have neither embedded nor sidecar
+// covers. Nothing is fetched on its own: each download follows an explicit
+// request for one album.
+package coverfetch
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	SourceCoverArtArchive = "coverartarchive"
+	SourceITunes          = "itunes"
+)
+
+var (
+	ErrAlbumNotFound = errors.New("album not found")
+	ErrAlbumHasCover = errors.New("album already has a cover")
+	ErrNoCoverFound  = errors.New("no cover found online")
+)
+
+// Cover is a downloaded image and where it came from.
+type Cover struct {
+	Source string
+	URL    string
+	Data   []byte
+}
+
+// albumInfo is what the sources search by.
+type albumInfo struct {
+	title          string
+	albumArtist    string
+	releaseIDs     []string
+	releaseGroupID string
+}
+
+type Service struct {
+	db     *sql.DB
+	client *httpClient
+}
+
+func NewService(database *sql.DB) *Service {
+	return &Service{db: database, client: newHTTPClient()}
+}
+
+// FetchAlbumCover finds and downloads a cover for an album without one. It
+// tries Cover Art Archive for the MusicBrainz releases the album's tracks
+// are tagged or enriched with, then searches iTunes by artist and title.
+func (s *Service) FetchAlbumCover(ctx context.Context, title string, albumArtist string) (Cover, error) {
+	album, err := s.loadAlbum(ctx, title, albumArtist)
+	if err != nil {
+		return Cover{}, err
+	}
+
+	for _, releaseID := range album.releaseIDs {
+		cover, err := fetchCoverArtArchive(ctx, s.client, "release", releaseID)
+		if err == nil {
+			return cover, nil
+		}
+		if ctx.Err() != nil {
+			return Cover{}, ctx.Err()
+		}
+	}
+	if album.releaseGroupID != "" {
+		if cover, err := fetchCoverArtArchive(ctx, s.client, "release-group", album.releaseGroupID); err == nil {
+			return cover, nil
+		}
+		if ctx.Err() != nil {
+			return Cover{}, ctx.Err()
+		}
+	}
+
+	cover, err := fetchITunesCover(ctx, s.client, album.title, album.albumArtist)
+	if err != nil {
+		return Cover{}, err
+	}
+
+	return cover, nil
+}
+
+func (s *Service) loadAlbum(ctx context.Context, title string, albumArtist string) (albumInfo, error) {
+	album := albumInfo{title: title, albumArtist: albumArtist}
+	if s.db == nil {
+		return album, ErrAlbumNotFound
+	}
+
+	var coverID sql.NullInt64
+	err := s.db.QueryRowContext(
+		ctx,
+		"SELECT cover_id FROM albums WHERE title = ? AND album_artist = ?",
+		title,
+		albumArtist,
+	).Scan(&coverID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return album, ErrAlbumNotFound
+	}
+	if err != nil {
+		return album, fmt.Errorf("get album for cover fetch: %w", err)
+	}
+	if coverID.Valid {
+		return album, ErrAlbumHasCover
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			COALESCE(e.release_id, ''),
+			COALESCE(e.release_group_id, ''),
+			CASE WHEN json_valid(t.tags_json) THEN COALESCE(json_extract(t.tags_json, '$.taglib_tags.MUSICBRAINZ_ALBUMID[0]'), '') ELSE '' END,
+			CASE WHEN json_valid(t.tags_json) THEN COALESCE(json_extract(t.tags_json, '$.taglib_tags.MUSICBRAINZ_RELEASEGROUPID[0]'), '') ELSE '' END
+		FROM album_tracks at
+		JOIN albums a ON a.id = at.album_id
+		JOIN tracks t ON t.id = at.track_id
+		LEFT JOIN track_enrichments e ON e.track_id = t.id
+		WHERE a.title = ? AND a.album_artist = ?
+		ORDER BY COALESCE(at.disc_no, 0), COALESCE(at.track_no, 0), t.id
+	`, title, albumArtist)
+	if err != nil {
+		return album, fmt.Errorf("query album releases: %w", err)
+	}
+	defer rows.Close()
+
+	seen := make(map[string]struct{})
+	for rows.Next() {
+		var enrichedRelease, enrichedGroup, taggedRelease, taggedGroup string
+		if err := rows.Scan(&enrichedRelease, &enrichedGroup, &taggedRelease, &taggedGroup); err != nil {
+			return album, fmt.Errorf("scan album release: %w", err)
+		}
+		for _, releaseID := range []string{taggedRelease, enrichedRelease} {
+			releaseID = strings.TrimSpace(releaseID)
+			if releaseID == "" {
+				continue
+			}
+			if _, ok := seen[releaseID]; ok {
+				continue
+			}
+			seen[releaseID] = struct{}{}
+			album.releaseIDs = append(album.releaseIDs, releaseID)
+		}
+		if album.releaseGroupID == "" {
+			album.releaseGroupID = strings.TrimSpace(taggedGroup)
+		}
+		if album.releaseGroupID == "" {
+			album.releaseGroupID = strings.TrimSpace(enrichedGroup)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return album, fmt.Errorf("iterate album releases: %w", err)
+	}
+
+	// A few releases are plenty; the rest are usually reissues of the same.
+	if len(album.releaseIDs) > maxReleaseAttempts {
+		album.releaseIDs = album.releaseIDs[:maxReleaseAttempts]
+	}
+
+	return album, nil
+}
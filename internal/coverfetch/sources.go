@@ -0,0 +1,148 @@
+package coverfetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	coverArtArchiveURL = "https://coverartarchive.org"
+	iTunesSearchURL    = "https://itunes.apple.com/search"
+	userAgent          = "Ben/1.0 (desktop music player)"
+	requestTimeout     = 20 * time.Second
+	maxImageBytes      = 16 << 20
+	maxResponseBytes   = 1 << 20
+	maxReleaseAttempts = 3
+	iTunesResultLimit  = 10
+)
+
+// iTunesArtworkSize is the edge length requested from iTunes, which serves
+// any size by rewriting the artwork URL.
+const iTunesArtworkSize = "1000x1000bb"
+
+type httpClient struct {
+	http *http.Client
+}
+
+func newHTTPClient() *httpClient {
+	return &httpClient{http: &http.Client{Timeout: requestTimeout}}
+}
+
+func (c *httpClient) get(ctx context.Context, requestURL string, limit int64) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build cover request: %w", err)
+	}
+	request.Header.Set("User-Agent", userAgent)
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("cover request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, ErrNoCoverFound
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cover request: %s", response.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("read cover response: %w", err)
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("cover response is larger than %d bytes", limit)
+	}
+
+	return body, nil
+}
+
+// fetchCoverArtArchive downloads the front cover of a MusicBrainz release or
+// release group. kind is "release" or "release-group".
+func fetchCoverArtArchive(ctx context.Context, client *httpClient, kind string, id string) (Cover, error) {
+	coverURL := fmt.Sprintf("%s/%s/%s/front-1200", coverArtArchiveURL, kind, url.PathEscape(id))
+	data, err := client.get(ctx, coverURL, maxImageBytes)
+	if err != nil {
+		return Cover{}, err
+	}
+
+	return Cover{Source: SourceCoverArtArchive, URL: coverURL, Data: data}, nil
+}
+
+type iTunesSearch struct {
+	Results []iTunesAlbum `json:"results"`
+}
+
+type iTunesAlbum struct {
+	CollectionName string `json:"collectionName"`
+	ArtistName     string `json:"artistName"`
+	ArtworkURL100  string `json:"artworkUrl100"`
+}
+
+// fetchITunesCover searches iTunes for the album and downloads the artwork
+// of the result whose title and artist match.
+func fetchITunesCover(ctx context.Context, client *httpClient, title string, albumArtist string) (Cover, error) {
+	values := url.Values{}
+	values.Set("term", strings.TrimSpace(albumArtist+" "+title))
+	values.Set("entity", "album")
+	values.Set("limit", fmt.Sprint(iTunesResultLimit))
+
+	body, err := client.get(ctx, iTunesSearchURL+"?"+values.Encode(), maxResponseBytes)
+	if err != nil {
+		return Cover{}, fmt.Errorf("search itunes: %w", err)
+	}
+
+	var search iTunesSearch
+	if err := json.Unmarshal(body, &search); err != nil {
+		return Cover{}, fmt.Errorf("decode itunes search: %w", err)
+	}
+
+	artworkURL, ok := matchITunesAlbum(search.Results, title, albumArtist)
+	if !ok {
+		return Cover{}, ErrNoCoverFound
+	}
+
+	data, err := client.get(ctx, artworkURL, maxImageBytes)
+	if err != nil {
+		return Cover{}, err
+	}
+
+	return Cover{Source: SourceITunes, URL: artworkURL, Data: data}, nil
+}
+
+// matchITunesAlbum picks the artwork of the first result with the same title
+// and artist, ignoring case and edition suffixes such as "(Deluxe)".
+func matchITunesAlbum(results []iTunesAlbum, title string, albumArtist string) (string, bool) {
+	wantTitle := comparableTitle(title)
+	wantArtist := comparableTitle(albumArtist)
+	for _, result := range results {
+		if result.ArtworkURL100 == "" {
+			continue
+		}
+		if comparableTitle(result.CollectionName) != wantTitle {
+			continue
+		}
+		if comparableTitle(result.ArtistName) != wantArtist {
+			continue
+		}
+		return strings.Replace(result.ArtworkURL100, "100x100bb", iTunesArtworkSize, 1), true
+	}
+
+	return "", false
+}
+
+func comparableTitle(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if index := strings.IndexAny(value, "(["); index > 0 {
+		value = strings.TrimSpace(value[:index])
+	}
+	return strings.Join(strings.Fields(value), " ")
+}
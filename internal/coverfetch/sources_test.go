@@ -0,0 +1,39 @@
+package coverfetch
+
+import "testing"
+
+func TestMatchITunesAlbumRequiresTitleAndArtist(t *testing.T) {
+	t.Parallel()
+
+	results := []iTunesAlbum{
+		{CollectionName: "Blue", ArtistName: "Someone Else", ArtworkURL100: "https://example.com/a/100x100bb.jpg"},
+		{CollectionName: "Blue (Deluxe Edition)", ArtistName: "Joni Mitchell", ArtworkURL100: "https://example.com/b/100x100bb.jpg"},
+	}
+
+	artwork, ok := matchITunesAlbum(results, "Blue", "joni  mitchell")
+	if !ok {
+		t.Fatalf("expected a match")
+	}
+	if artwork != "https://example.com/b/"+iTunesArtworkSize+".jpg" {
+		t.Fatalf("expected the upsized artwork of the matching album, got %q", artwork)
+	}
+
+	if _, ok := matchITunesAlbum(results, "Court and Spark", "Joni Mitchell"); ok {
+		t.Fatalf("expected no match for a different album")
+	}
+}
+
+func TestComparableTitleDropsEditionSuffix(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		" Abbey Road  (Remastered) ": "abbey road",
+		"Kid A [Mnesia]":             "kid a",
+		"(What's the Story)":         "(what's the story)",
+	}
+	for input, want := range cases {
+		if got := comparableTitle(input); got != want {
+			t.Fatalf("comparableTitle(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
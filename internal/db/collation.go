@@ -0,0 +1,30 @@
+package db
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"modernc.org/sqlite"
+)
+
+// LocaleCollation orders text for the user's locale. Queries opt in with
+// ORDER BY ... COLLATE LOCALE; the comparison is swapped when the locale
+// setting changes.
+const LocaleCollation = "LOCALE"
+
+var localeCompare atomic.Pointer[func(left string, right string) int]
+
+func init() {
+	sqlite.MustRegisterCollationUtf8(LocaleCollation, func(left string, right string) int {
+		if compare := localeCompare.Load(); compare != nil {
+			return (*compare)(left, right)
+		}
+
+		return strings.Compare(strings.ToLower(left), strings.ToLower(right))
+	})
+}
+
+// SetLocaleCompare installs the comparison used by the LOCALE collation.
+func SetLocaleCompare(compare func(left string, right string) int) {
+	localeCompare.Store(&compare)
+}
@@ -5,12 +5,58 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
 
+// PoolConfig sizes a connection pool and lists the pragmas applied to each
+// of its connections. Zero limits keep the database/sql defaults.
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxIdleTime time.Duration
+	Pragmas         []string
+}
+
+// DefaultWritePool configures the main connection, which migrations, scans
+// and every write go through.
+func DefaultWritePool() PoolConfig {
+	return PoolConfig{
+		Pragmas: []string{
+			"journal_mode(WAL)",
+			"foreign_keys(1)",
+			"busy_timeout(5000)",
+		},
+	}
+}
+
+// DefaultReadPool sizes the read-only pool used by browse and stats
+// queries. WAL lets these readers run while a scan holds the write lock,
+// and query_only makes a stray write fail instead of taking the lock.
+func DefaultReadPool() PoolConfig {
+	return PoolConfig{
+		MaxOpenConns:    4,
+		MaxIdleConns:    2,
+		ConnMaxIdleTime: 5 * time.Minute,
+		Pragmas: []string{
+			"busy_timeout(5000)",
+			"foreign_keys(1)",
+			"query_only(1)",
+		},
+	}
+}
+
+// Bootstrap opens and migrates the database with the default write pool.
 func Bootstrap(dbPath string) (*sql.DB, error) {
-	database, err := Open(dbPath)
+	return BootstrapPool(dbPath, DefaultWritePool())
+}
+
+// BootstrapPool opens the database with config and runs the pending
+// migrations.
+func BootstrapPool(dbPath string, config PoolConfig) (*sql.DB, error) {
+	database, err := Open(dbPath, config)
 	if err != nil {
 		return nil, err
 	}
@@ -23,33 +69,69 @@ func Bootstrap(dbPath string) (*sql.DB, error) {
 	return database, nil
 }
 
-func Open(dbPath string) (*sql.DB, error) {
+// Open opens the database at dbPath, creating its directory, with the
+// limits and pragmas of config.
+func Open(dbPath string, config PoolConfig) (*sql.DB, error) {
 	if err := os.MkdirAll(filepath.Dir(dbPath), 0o755); err != nil {
 		return nil, fmt.Errorf("create db directory: %w", err)
 	}
 
-	database, err := sql.Open("sqlite", dbPath)
+	database, err := sql.Open("sqlite", dataSourceName(dbPath, config))
 	if err != nil {
 		return nil, fmt.Errorf("open sqlite: %w", err)
 	}
+	applyPoolConfig(database, config)
 
-	pragmas := []string{
-		"PRAGMA journal_mode=WAL;",
-		"PRAGMA foreign_keys=ON;",
-		"PRAGMA busy_timeout=5000;",
+	if err := database.Ping(); err != nil {
+		database.Close()
+		return nil, fmt.Errorf("ping sqlite: %w", err)
 	}
 
-	for _, pragma := range pragmas {
-		if _, err := database.Exec(pragma); err != nil {
-			database.Close()
-			return nil, fmt.Errorf("apply sqlite pragma %q: %w", pragma, err)
-		}
+	return database, nil
+}
+
+// OpenReadPool opens a separate read-only pool on an already migrated
+// database, so UI reads never queue behind long write transactions on the
+// main connection.
+func OpenReadPool(dbPath string, config PoolConfig) (*sql.DB, error) {
+	database, err := sql.Open("sqlite", dataSourceName(dbPath, config))
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite read pool: %w", err)
 	}
+	applyPoolConfig(database, config)
 
 	if err := database.Ping(); err != nil {
 		database.Close()
-		return nil, fmt.Errorf("ping sqlite: %w", err)
+		return nil, fmt.Errorf("ping sqlite read pool: %w", err)
 	}
 
 	return database, nil
 }
+
+// applyPoolConfig applies the non-zero limits of config to database.
+func applyPoolConfig(database *sql.DB, config PoolConfig) {
+	if config.MaxOpenConns > 0 {
+		database.SetMaxOpenConns(config.MaxOpenConns)
+	}
+	if config.MaxIdleConns > 0 {
+		database.SetMaxIdleConns(config.MaxIdleConns)
+	}
+	if config.ConnMaxIdleTime > 0 {
+		database.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+	}
+}
+
+// dataSourceName passes the pragmas of config in the connection string, so
+// every connection the pool opens gets them, not just the first.
+func dataSourceName(dbPath string, config PoolConfig) string {
+	if len(config.Pragmas) == 0 {
+		return dbPath
+	}
+
+	query := make([]string, 0, len(config.Pragmas))
+	for _, pragma := range config.Pragmas {
+		query = append(query, "_pragma="+pragma)
+	}
+
+	return dbPath + "?" + strings.Join(query, "&")
+}
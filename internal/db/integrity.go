@@ -0,0 +1,22 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// QuickCheck runs SQLite's quick_check, which verifies the database file
+// without the index cross-checks of a full integrity check. The result is empty
+// when the database is healthy and otherwise holds the first problem found.
+func QuickCheck(ctx context.Context, database *sql.DB) (string, error) {
+	var result string
+	if err := database.QueryRowContext(ctx, "PRAGMA quick_check(1)").Scan(&result); err != nil {
+		return "", fmt.Errorf("run sqlite quick check: %w", err)
+	}
+	if result == "ok" {
+		return "", nil
+	}
+
+	return result, nil
+}
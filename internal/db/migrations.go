@@ -77,3 +77,26 @@ func migrationApplied(database *sql.DB, name string) (bool, error) {
 
 	return count > 0, nil
 }
+
+// PendingMigrations lists the embedded migrations database has not applied
+// yet, e.g. when it was last opened by an older build.
+func PendingMigrations(database *sql.DB) ([]string, error) {
+	entries, err := fs.Glob(migrationsFS, "migrations/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("list migrations: %w", err)
+	}
+	sort.Strings(entries)
+
+	pending := make([]string, 0)
+	for _, name := range entries {
+		applied, err := migrationApplied(database, name)
+		if err != nil {
+			return nil, err
+		}
+		if !applied {
+			pending = append(pending, name)
+		}
+	}
+
+	return pending, nil
+}
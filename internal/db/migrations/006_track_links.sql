@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS track_links (
+    track_id INTEGER PRIMARY KEY,
+    canonical_track_id INTEGER NOT NULL,
+    linked_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    CHECK (track_id <> canonical_track_id),
+    FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE,
+    FOREIGN KEY(canonical_track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_track_links_canonical_track_id ON track_links(canonical_track_id);
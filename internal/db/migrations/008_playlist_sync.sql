@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS app_settings (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+-- A synced playlist is bound to its .m3u8 file; the hash of the file as
+-- last imported or exported tells its own writes from outside edits.
+ALTER TABLE playlists ADD COLUMN sync_path TEXT;
+ALTER TABLE playlists ADD COLUMN sync_hash TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_playlists_sync_path ON playlists(sync_path);
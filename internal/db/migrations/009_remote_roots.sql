@@ -0,0 +1,6 @@
+ALTER TABLE watched_roots ADD COLUMN kind TEXT NOT NULL DEFAULT 'local';
+ALTER TABLE watched_roots ADD COLUMN remote_username TEXT;
+ALTER TABLE watched_roots ADD COLUMN remote_password TEXT;
+ALTER TABLE watched_roots ADD COLUMN offline INTEGER NOT NULL DEFAULT 0 CHECK (offline IN (0, 1));
+ALTER TABLE watched_roots ADD COLUMN last_checked_at TEXT;
+ALTER TABLE watched_roots ADD COLUMN last_error TEXT;
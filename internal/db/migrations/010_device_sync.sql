@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS track_ratings (
+    track_id INTEGER PRIMARY KEY,
+    rating INTEGER CHECK (rating IS NULL OR (rating >= 1 AND rating <= 5)),
+    favorite INTEGER NOT NULL DEFAULT 0 CHECK (favorite IN (0, 1)),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS synced_play_stats (
+    device_id TEXT NOT NULL,
+    day TEXT NOT NULL,
+    track_id INTEGER NOT NULL,
+    played_ms INTEGER NOT NULL DEFAULT 0,
+    heartbeat_count INTEGER NOT NULL DEFAULT 0,
+    complete_count INTEGER NOT NULL DEFAULT 0,
+    skip_count INTEGER NOT NULL DEFAULT 0,
+    partial_count INTEGER NOT NULL DEFAULT 0,
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY(device_id, day, track_id),
+    FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_synced_play_stats_track_day ON synced_play_stats(track_id, day);
+
+CREATE VIEW IF NOT EXISTS play_stats_combined AS
+SELECT day, track_id, played_ms, heartbeat_count, complete_count, skip_count, partial_count
+FROM play_stats_daily
+UNION ALL
+SELECT day, track_id, played_ms, heartbeat_count, complete_count, skip_count, partial_count
+FROM synced_play_stats;
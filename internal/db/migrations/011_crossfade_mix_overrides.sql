@@ -0,0 +1,12 @@
+ALTER TABLE playback_state
+ADD COLUMN crossfade_ms INTEGER NOT NULL DEFAULT 0 CHECK (crossfade_ms >= 0 AND crossfade_ms <= 12000);
+
+-- Albums are rebuilt on every scan, so overrides are keyed by the normalized
+-- album title and album artist instead of albums.id.
+CREATE TABLE IF NOT EXISTS album_mix_overrides (
+    album_title_key TEXT NOT NULL,
+    album_artist_key TEXT NOT NULL,
+    continuous_mix INTEGER NOT NULL CHECK (continuous_mix IN (0, 1)),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    PRIMARY KEY(album_title_key, album_artist_key)
+);
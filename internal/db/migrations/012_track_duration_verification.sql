@@ -0,0 +1,2 @@
+ALTER TABLE tracks
+ADD COLUMN duration_verified_at TEXT;
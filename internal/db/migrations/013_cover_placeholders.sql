@@ -0,0 +1,5 @@
+ALTER TABLE covers
+ADD COLUMN dominant_color TEXT;
+
+ALTER TABLE covers
+ADD COLUMN blurhash TEXT;
@@ -0,0 +1,2 @@
+ALTER TABLE track_ratings
+ADD COLUMN banned INTEGER NOT NULL DEFAULT 0 CHECK (banned IN (0, 1));
@@ -0,0 +1,2 @@
+ALTER TABLE tracks
+ADD COLUMN rating_tags_imported_at TEXT;
@@ -0,0 +1,15 @@
+ALTER TABLE play_events
+ADD COLUMN session_label TEXT;
+
+CREATE TABLE IF NOT EXISTS play_stats_label_daily (
+    day TEXT NOT NULL,
+    label TEXT NOT NULL,
+    played_ms INTEGER NOT NULL DEFAULT 0,
+    complete_count INTEGER NOT NULL DEFAULT 0,
+    skip_count INTEGER NOT NULL DEFAULT 0,
+    partial_count INTEGER NOT NULL DEFAULT 0,
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    PRIMARY KEY(day, label)
+);
+
+CREATE INDEX IF NOT EXISTS idx_play_events_session_label_ts ON play_events(session_label, ts);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS track_volume_offsets (
+    track_id INTEGER PRIMARY KEY,
+    offset_db REAL NOT NULL CHECK (offset_db >= -12 AND offset_db <= 12),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+
+-- Keyed like album_mix_overrides because albums are rebuilt on every scan.
+CREATE TABLE IF NOT EXISTS album_volume_offsets (
+    album_title_key TEXT NOT NULL,
+    album_artist_key TEXT NOT NULL,
+    offset_db REAL NOT NULL CHECK (offset_db >= -12 AND offset_db <= 12),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    PRIMARY KEY(album_title_key, album_artist_key)
+);
@@ -0,0 +1,17 @@
+ALTER TABLE files
+ADD COLUMN is_audiobook INTEGER NOT NULL DEFAULT 0 CHECK (is_audiobook IN (0, 1));
+
+CREATE TABLE IF NOT EXISTS audiobook_folders (
+    path TEXT PRIMARY KEY,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS audiobook_positions (
+    track_id INTEGER PRIMARY KEY,
+    position_ms INTEGER NOT NULL DEFAULT 0,
+    completed INTEGER NOT NULL DEFAULT 0 CHECK (completed IN (0, 1)),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_files_is_audiobook ON files(is_audiobook);
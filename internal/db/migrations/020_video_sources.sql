@@ -0,0 +1,2 @@
+ALTER TABLE files
+ADD COLUMN is_video INTEGER NOT NULL DEFAULT 0 CHECK (is_video IN (0, 1));
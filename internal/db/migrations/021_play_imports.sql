@@ -0,0 +1,13 @@
+ALTER TABLE play_events
+ADD COLUMN device TEXT;
+
+CREATE TABLE IF NOT EXISTS play_imports (
+    device TEXT NOT NULL,
+    track_id INTEGER NOT NULL,
+    ts TEXT NOT NULL,
+    imported_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    PRIMARY KEY(device, track_id, ts),
+    FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_play_events_device_ts ON play_events(device, ts);
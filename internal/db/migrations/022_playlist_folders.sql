@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS playlist_folders (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    parent_id INTEGER,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    FOREIGN KEY(parent_id) REFERENCES playlist_folders(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_playlist_folders_parent_id ON playlist_folders(parent_id);
+
+ALTER TABLE playlists
+ADD COLUMN folder_id INTEGER REFERENCES playlist_folders(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_playlists_folder_id ON playlists(folder_id);
+
+CREATE TABLE IF NOT EXISTS playlist_tags (
+    playlist_id INTEGER NOT NULL,
+    tag TEXT NOT NULL,
+    PRIMARY KEY(playlist_id, tag),
+    FOREIGN KEY(playlist_id) REFERENCES playlists(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_playlist_tags_tag ON playlist_tags(tag);
@@ -0,0 +1,2 @@
+ALTER TABLE playlists
+ADD COLUMN playback_json TEXT;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS track_trims (
+    track_id INTEGER PRIMARY KEY,
+    start_ms INTEGER NOT NULL DEFAULT 0 CHECK (start_ms >= 0),
+    end_ms INTEGER CHECK (end_ms IS NULL OR end_ms > start_ms),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
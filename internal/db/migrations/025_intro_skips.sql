@@ -0,0 +1,11 @@
+-- One row per play that started at the top of a track. skipped_to_ms is
+-- where the listener seeked to within the intro, or NULL if they let it play.
+CREATE TABLE IF NOT EXISTS track_intro_observations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    track_id INTEGER NOT NULL,
+    skipped_to_ms INTEGER,
+    ts TEXT NOT NULL,
+    FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_track_intro_observations_track_ts ON track_intro_observations(track_id, ts);
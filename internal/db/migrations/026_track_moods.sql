@@ -0,0 +1,12 @@
+-- source is 'user' for moods assigned by hand and 'auto' for moods estimated
+-- from tags; estimation only ever replaces its own rows.
+CREATE TABLE IF NOT EXISTS track_moods (
+    track_id INTEGER NOT NULL,
+    mood TEXT NOT NULL,
+    source TEXT NOT NULL DEFAULT 'user' CHECK (source IN ('user', 'auto')),
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    PRIMARY KEY(track_id, mood),
+    FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_track_moods_mood ON track_moods(mood);
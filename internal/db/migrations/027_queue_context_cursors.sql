@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS queue_context_cursors (
+    context_key TEXT PRIMARY KEY,
+    source TEXT NOT NULL,
+    track_id INTEGER NOT NULL,
+    context_index INTEGER NOT NULL DEFAULT 0,
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_context_cursors_updated_at ON queue_context_cursors(updated_at);
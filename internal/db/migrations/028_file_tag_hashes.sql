@@ -0,0 +1,2 @@
+ALTER TABLE files
+ADD COLUMN tag_hash TEXT;
@@ -0,0 +1,12 @@
+-- source is 'user' for provenance edited by hand and 'tag' for provenance
+-- imported from COMMENT/WWWAUDIOFILE tags; importing only replaces its own
+-- rows.
+CREATE TABLE IF NOT EXISTS file_provenance (
+    file_id INTEGER PRIMARY KEY,
+    purchased_from TEXT NOT NULL DEFAULT '',
+    ripped_from TEXT NOT NULL DEFAULT '',
+    download_source TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL DEFAULT 'user' CHECK (source IN ('user', 'tag')),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    FOREIGN KEY(file_id) REFERENCES files(id) ON DELETE CASCADE
+);
@@ -0,0 +1,13 @@
+-- Gains are in dB relative to the ReplayGain reference level; R128 gains
+-- from Opus files are converted on scan. Peaks are linear sample peaks.
+ALTER TABLE tracks
+ADD COLUMN replaygain_track_gain REAL;
+
+ALTER TABLE tracks
+ADD COLUMN replaygain_track_peak REAL;
+
+ALTER TABLE tracks
+ADD COLUMN replaygain_album_gain REAL;
+
+ALTER TABLE tracks
+ADD COLUMN replaygain_album_peak REAL;
@@ -0,0 +1,13 @@
+-- contexts_json lists the queue sources played in order; queue_json holds
+-- the queue as it was when the last track of the session started, which is
+-- also ended_at.
+CREATE TABLE IF NOT EXISTS listening_sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    started_at TEXT NOT NULL,
+    ended_at TEXT NOT NULL,
+    track_count INTEGER NOT NULL DEFAULT 0,
+    contexts_json TEXT NOT NULL DEFAULT '[]',
+    queue_json TEXT NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS idx_listening_sessions_started_at ON listening_sessions(started_at);
@@ -0,0 +1,13 @@
+-- Palettes extracted with the default options, kept per cover. cover_hash
+-- is the hash of the cover when the palette was made; a palette whose hash
+-- no longer matches is stale. The primary color is split out for sorting.
+CREATE TABLE IF NOT EXISTS cover_palettes (
+    cover_id INTEGER PRIMARY KEY,
+    cover_hash TEXT,
+    palette_json TEXT NOT NULL,
+    primary_hue REAL,
+    primary_lightness REAL,
+    primary_chroma REAL,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    FOREIGN KEY(cover_id) REFERENCES covers(id) ON DELETE CASCADE
+);
@@ -0,0 +1,25 @@
+-- metadata_lookups caches online metadata lookups by a normalized query key,
+-- so repeated lookups of the same track do not hit the provider again.
+CREATE TABLE IF NOT EXISTS metadata_lookups (
+    cache_key TEXT PRIMARY KEY,
+    candidates_json TEXT NOT NULL,
+    fetched_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+-- track_enrichments holds values the user chose to apply from a lookup.
+-- Scans fill them back into tracks whose tags leave the field empty.
+CREATE TABLE IF NOT EXISTS track_enrichments (
+    track_id INTEGER PRIMARY KEY,
+    year INTEGER,
+    album_artist TEXT NOT NULL DEFAULT '',
+    genre TEXT NOT NULL DEFAULT '',
+    recording_id TEXT NOT NULL DEFAULT '',
+    release_id TEXT NOT NULL DEFAULT '',
+    release_group_id TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL,
+    applied_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_track_enrichments_release_group
+    ON track_enrichments(release_group_id);
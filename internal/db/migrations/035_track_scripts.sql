@@ -0,0 +1,14 @@
+-- script and language are detected from a track's title, artist and album.
+-- A NULL script means detection has not run for the track yet; scans fill
+-- it in. search_text holds the folded text track search matches against.
+ALTER TABLE tracks
+ADD COLUMN script TEXT;
+
+ALTER TABLE tracks
+ADD COLUMN language TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE tracks
+ADD COLUMN search_text TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_tracks_script_language
+    ON tracks(script, language);
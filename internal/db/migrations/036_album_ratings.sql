@@ -0,0 +1,13 @@
+-- Albums are rebuilt from their tracks on every scan and get new ids, so
+-- album ratings are keyed by the album's display title and album artist.
+CREATE TABLE IF NOT EXISTS album_ratings (
+    title TEXT NOT NULL COLLATE NOCASE,
+    album_artist TEXT NOT NULL COLLATE NOCASE,
+    rating INTEGER CHECK (rating IS NULL OR (rating >= 1 AND rating <= 5)),
+    favorite INTEGER NOT NULL DEFAULT 0 CHECK (favorite IN (0, 1)),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    PRIMARY KEY(title, album_artist)
+);
+
+CREATE INDEX IF NOT EXISTS idx_track_ratings_favorite_rating
+    ON track_ratings(favorite, rating);
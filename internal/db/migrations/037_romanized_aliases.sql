@@ -0,0 +1,20 @@
+-- Latin aliases of names in other scripts, from sort tags such as
+-- ARTISTSORT or transliterated, so "utada" finds 宇多田ヒカル. Artists and
+-- albums take theirs from their tracks when the library is rebuilt.
+ALTER TABLE tracks
+ADD COLUMN artist_romanized TEXT;
+
+ALTER TABLE tracks
+ADD COLUMN album_artist_romanized TEXT;
+
+ALTER TABLE tracks
+ADD COLUMN album_romanized TEXT;
+
+ALTER TABLE artists
+ADD COLUMN romanized TEXT;
+
+ALTER TABLE albums
+ADD COLUMN romanized TEXT;
+
+ALTER TABLE albums
+ADD COLUMN artist_romanized TEXT;
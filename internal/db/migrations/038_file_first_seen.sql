@@ -0,0 +1,10 @@
+-- first_seen_at is when a scan first indexed a file, for "recently added".
+-- Files indexed before it existed only know when they were last seen.
+ALTER TABLE files
+ADD COLUMN first_seen_at TEXT;
+
+UPDATE files
+SET first_seen_at = last_seen_at
+WHERE first_seen_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_files_first_seen_at ON files(first_seen_at);
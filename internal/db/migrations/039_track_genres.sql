@@ -0,0 +1,12 @@
+-- track_genres holds each genre of a track separately, split from
+-- multi-value GENRE tags such as "Rock; Pop". tracks.genre keeps the tag as
+-- written. position keeps the tag order, so position 0 is the main genre.
+CREATE TABLE IF NOT EXISTS track_genres (
+    track_id INTEGER NOT NULL,
+    genre TEXT NOT NULL COLLATE NOCASE,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY(track_id, genre),
+    FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_track_genres_genre ON track_genres(genre);
@@ -0,0 +1,20 @@
+-- track_artists holds each artist credited on a track, split from
+-- multi-value ARTIST/ARTISTS tags and credits such as "A feat. B".
+-- tracks.artist keeps the credit as displayed. position keeps the credit
+-- order, so position 0 is the main artist. Tracks without an artist are
+-- credited to "Unknown Artist" so every track has a row.
+CREATE TABLE IF NOT EXISTS track_artists (
+    track_id INTEGER NOT NULL,
+    artist TEXT NOT NULL COLLATE NOCASE,
+    position INTEGER NOT NULL DEFAULT 0,
+    romanized TEXT,
+    PRIMARY KEY(track_id, artist),
+    FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_track_artists_artist ON track_artists(artist);
+
+-- Existing tracks keep their whole credit until the next scan splits it.
+INSERT OR IGNORE INTO track_artists(track_id, artist, position, romanized)
+SELECT id, COALESCE(NULLIF(TRIM(artist), ''), 'Unknown Artist'), 0, artist_romanized
+FROM tracks;
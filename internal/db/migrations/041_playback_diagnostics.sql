@@ -0,0 +1,16 @@
+-- playback_diagnostics is the opt-in technical playback log: how long the
+-- backend took to load a track or apply a seek, and how long playback
+-- stalled waiting for data. duration_ms is the measured time; detail holds
+-- the error when a load or seek failed.
+CREATE TABLE IF NOT EXISTS playback_diagnostics (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    track_id INTEGER NOT NULL,
+    event_type TEXT NOT NULL CHECK(event_type IN ('load', 'seek', 'underrun')),
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    path TEXT NOT NULL DEFAULT '',
+    detail TEXT NOT NULL DEFAULT '',
+    recorded_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_playback_diagnostics_track ON playback_diagnostics(track_id, recorded_at);
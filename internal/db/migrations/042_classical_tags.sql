@@ -0,0 +1,29 @@
+-- Classical tags: the COMPOSER, CONDUCTOR and WORK of a track and its
+-- movement within the work. catalog_key orders works by opus or catalog
+-- number, see library.CatalogSortKey; albums take the composer and the
+-- lowest catalog key of their tracks. Rescans fill them in.
+ALTER TABLE tracks
+ADD COLUMN composer TEXT;
+
+ALTER TABLE tracks
+ADD COLUMN conductor TEXT;
+
+ALTER TABLE tracks
+ADD COLUMN work TEXT;
+
+ALTER TABLE tracks
+ADD COLUMN movement_no INTEGER;
+
+ALTER TABLE tracks
+ADD COLUMN movement_name TEXT;
+
+ALTER TABLE tracks
+ADD COLUMN catalog_key TEXT;
+
+ALTER TABLE albums
+ADD COLUMN composer TEXT;
+
+ALTER TABLE albums
+ADD COLUMN catalog_key TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_tracks_composer_work ON tracks(composer COLLATE NOCASE, work COLLATE NOCASE);
@@ -0,0 +1,14 @@
+-- hash_quick has been in the schema since the start; scans now fill it
+-- with the size and a hash of both ends of a file, so identical copies
+-- of a file share it.
+CREATE INDEX IF NOT EXISTS idx_files_hash_quick ON files(hash_quick);
+
+-- hidden_duplicates holds the tracks hidden from browsing as duplicates of
+-- kept_track_id. The files stay in the library and can be shown again.
+CREATE TABLE IF NOT EXISTS hidden_duplicates (
+    track_id INTEGER PRIMARY KEY,
+    kept_track_id INTEGER NOT NULL,
+    hidden_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE,
+    FOREIGN KEY(kept_track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
@@ -0,0 +1,11 @@
+-- Chromaprint fingerprints of files, filled by the optional fingerprint
+-- scan phase. An empty fingerprint marks a file fpcalc could not read, so
+-- it is not retried until the file changes. fingerprint_duration is the
+-- length in seconds AcoustID lookups need.
+ALTER TABLE files
+ADD COLUMN fingerprint TEXT;
+
+ALTER TABLE files
+ADD COLUMN fingerprint_duration REAL;
+
+CREATE INDEX IF NOT EXISTS idx_files_fingerprint ON files(fingerprint);
@@ -0,0 +1,18 @@
+-- Full-file SHA-256 checksums stored by VerifyFiles, with the size and
+-- modification time the file had when it was hashed. A later run that finds
+-- the same size and time but a different hash marks the row 'mismatch':
+-- the file changed on disk without being written, i.e. bit rot.
+-- actual_checksum is the hash the failed verification computed.
+CREATE TABLE IF NOT EXISTS file_checksums (
+    file_id INTEGER PRIMARY KEY,
+    checksum TEXT NOT NULL,
+    size INTEGER NOT NULL,
+    mtime_ns INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'ok' CHECK (status IN ('ok', 'mismatch')),
+    actual_checksum TEXT,
+    computed_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    verified_at TEXT,
+    FOREIGN KEY(file_id) REFERENCES files(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_checksums_status ON file_checksums(status);
@@ -0,0 +1,16 @@
+-- identity_key identifies a track by its tags, so the scanner can hand the
+-- track row, with its play history, ratings and playlist entries, to the
+-- file it was moved to even when neither a fingerprint nor the quick hash
+-- matches. The expression is the scanner's trackIdentityExpr; tracks
+-- without a title or artist get no key.
+ALTER TABLE tracks
+ADD COLUMN identity_key TEXT;
+
+UPDATE tracks
+SET identity_key = CASE
+    WHEN TRIM(COALESCE(title, '')) = '' OR TRIM(COALESCE(artist, '')) = '' THEN NULL
+    ELSE LOWER(TRIM(artist)) || char(31) || LOWER(TRIM(COALESCE(album, ''))) || char(31)
+        || COALESCE(disc_no, 0) || char(31) || COALESCE(track_no, 0) || char(31) || LOWER(TRIM(title))
+END;
+
+CREATE INDEX IF NOT EXISTS idx_tracks_identity_key ON tracks(identity_key);
@@ -0,0 +1,5 @@
+-- phash is the perceptual hash of a cover, 16 hex digits, so covers of the
+-- same album ripped twice can be matched although their bytes differ.
+-- Scans fill it in like the placeholders.
+ALTER TABLE covers
+ADD COLUMN phash TEXT;
@@ -0,0 +1,14 @@
+-- track_inferred_genres holds a probable genre for tracks without a genre
+-- tag, voted by their album siblings or the artist's other tracks. They are
+-- kept apart from tagged genres until the user confirms them, which records
+-- the genre as an enrichment. Dismissed ones are not inferred again.
+CREATE TABLE IF NOT EXISTS track_inferred_genres (
+    track_id INTEGER PRIMARY KEY,
+    genre TEXT NOT NULL,
+    source TEXT NOT NULL,
+    confidence REAL NOT NULL,
+    votes INTEGER NOT NULL DEFAULT 0,
+    dismissed INTEGER NOT NULL DEFAULT 0,
+    inferred_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
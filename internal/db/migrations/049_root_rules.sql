@@ -0,0 +1,11 @@
+-- Exclude rules of a watched root, applied by scans and the file watcher on
+-- top of .benignore files. exclude_patterns holds gitignore-style patterns
+-- relative to the root, one per line; min_file_size is in bytes.
+ALTER TABLE watched_roots
+ADD COLUMN exclude_patterns TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE watched_roots
+ADD COLUMN skip_hidden INTEGER NOT NULL DEFAULT 0 CHECK (skip_hidden IN (0, 1));
+
+ALTER TABLE watched_roots
+ADD COLUMN min_file_size INTEGER NOT NULL DEFAULT 0;
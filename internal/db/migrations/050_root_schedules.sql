@@ -0,0 +1,18 @@
+-- Scan schedule of a watched root. scan_on_startup rescans it when the app
+-- starts and rescan_interval_hours, when above 0, that often. Scheduled
+-- scans wait while the local hour is from quiet_hours_start up to
+-- quiet_hours_end, which may wrap midnight; equal hours mean none.
+ALTER TABLE watched_roots
+ADD COLUMN scan_on_startup INTEGER NOT NULL DEFAULT 1 CHECK (scan_on_startup IN (0, 1));
+
+ALTER TABLE watched_roots
+ADD COLUMN rescan_interval_hours INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE watched_roots
+ADD COLUMN quiet_hours_start INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE watched_roots
+ADD COLUMN quiet_hours_end INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE watched_roots
+ADD COLUMN last_scheduled_scan_at TEXT;
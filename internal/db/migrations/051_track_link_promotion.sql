@@ -0,0 +1,14 @@
+-- Deleting the canonical track of a version group, e.g. when the scanner
+-- drops a track whose file is gone, promotes the lowest remaining track id
+-- instead of letting the cascade dissolve the whole group. Unlinking a
+-- canonical track promotes the same way in TrackLinkRepository.Unlink.
+CREATE TRIGGER IF NOT EXISTS track_links_promote_on_track_delete
+BEFORE DELETE ON tracks
+WHEN EXISTS (SELECT 1 FROM track_links WHERE canonical_track_id = OLD.id)
+BEGIN
+    UPDATE track_links
+    SET canonical_track_id = (SELECT MIN(track_id) FROM track_links WHERE canonical_track_id = OLD.id)
+    WHERE canonical_track_id = OLD.id
+      AND track_id <> (SELECT MIN(track_id) FROM track_links WHERE canonical_track_id = OLD.id);
+    DELETE FROM track_links WHERE canonical_track_id = OLD.id;
+END;
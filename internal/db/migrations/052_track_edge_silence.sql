@@ -0,0 +1,14 @@
+-- Silence measured at the start and end of a track's audio by decoding it.
+-- file_mtime_ns is the mtime of the file that was analyzed, so a rewritten
+-- file is analyzed again. failed marks files the decoder could not read,
+-- which are not retried until they change.
+CREATE TABLE IF NOT EXISTS track_edge_silence (
+    track_id INTEGER PRIMARY KEY,
+    lead_ms INTEGER NOT NULL DEFAULT 0 CHECK (lead_ms >= 0),
+    tail_ms INTEGER NOT NULL DEFAULT 0 CHECK (tail_ms >= 0),
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    file_mtime_ns INTEGER NOT NULL,
+    failed INTEGER NOT NULL DEFAULT 0 CHECK (failed IN (0, 1)),
+    analyzed_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
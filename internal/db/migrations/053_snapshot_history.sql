@@ -0,0 +1,16 @@
+-- Snapshots are kept as a short history instead of a single row, so the
+-- last few bulk operations can be rolled back newest first. Table copies
+-- are named library_snapshot_<id>_<table>. files_kind and files_json hold
+-- the file changes of operations outside the database, such as tag edits
+-- and imports, which the restorer registered for the kind reverts. Copies
+-- of the old single snapshot are dropped by the snapshot service.
+DROP TABLE IF EXISTS library_snapshots;
+
+CREATE TABLE library_snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    label TEXT NOT NULL,
+    tables_json TEXT NOT NULL DEFAULT '[]',
+    files_kind TEXT NOT NULL DEFAULT '',
+    files_json TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
@@ -0,0 +1,9 @@
+-- release_track_lists caches the track count of each disc of a MusicBrainz
+-- release, so albums can be checked for gaps against the release itself.
+CREATE TABLE IF NOT EXISTS release_track_lists (
+    release_id TEXT NOT NULL,
+    disc_no INTEGER NOT NULL,
+    track_count INTEGER NOT NULL,
+    fetched_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    PRIMARY KEY(release_id, disc_no)
+);
@@ -0,0 +1,137 @@
+package devicesync
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const bundleFormatVersion = 1
+
+// timestampLayout matches SQLite's strftime('%Y-%m-%dT%H:%M:%fZ') so stored
+// and imported timestamps compare correctly as strings.
+const timestampLayout = "2006-01-02T15:04:05.000Z"
+
+// Bundle is the portable snapshot two installations exchange. Tracks are
+// identified by a metadata key because row ids differ between machines.
+type Bundle struct {
+	FormatVersion int              `json:"formatVersion"`
+	DeviceID      string           `json:"deviceId"`
+	DeviceName    string           `json:"deviceName"`
+	ExportedAt    string           `json:"exportedAt"`
+	PlayStats     []PlayStatRecord `json:"playStats"`
+	Ratings       []RatingRecord   `json:"ratings"`
+}
+
+// PlayStatRecord is one device's listening totals for a track on one day.
+// Only the device that produced the plays ever changes the record, so last
+// write wins without double counting when bundles are relayed.
+type PlayStatRecord struct {
+	DeviceID       string `json:"deviceId"`
+	Day            string `json:"day"`
+	TrackKey       string `json:"trackKey"`
+	PlayedMS       int    `json:"playedMs"`
+	HeartbeatCount int    `json:"heartbeatCount"`
+	CompleteCount  int    `json:"completeCount"`
+	SkipCount      int    `json:"skipCount"`
+	PartialCount   int    `json:"partialCount"`
+	UpdatedAt      string `json:"updatedAt"`
+}
+
+type RatingRecord struct {
+	TrackKey  string `json:"trackKey"`
+	Rating    *int   `json:"rating,omitempty"`
+	Favorite  bool   `json:"favorite"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+type trackKeyIndex struct {
+	keyByID map[int64]string
+	idByKey map[string]int64
+}
+
+// trackKey joins the normalized identifying tags of a track. Disc and track
+// numbers keep album versions of the same song apart.
+func trackKey(artist string, album string, title string, discNo sql.NullInt64, trackNo sql.NullInt64) string {
+	parts := []string{
+		normalizeKeyPart(artist),
+		normalizeKeyPart(album),
+		normalizeKeyPart(title),
+		nullableIntKey(discNo),
+		nullableIntKey(trackNo),
+	}
+
+	return strings.Join(parts, "\x1f")
+}
+
+func normalizeKeyPart(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
+
+func nullableIntKey(value sql.NullInt64) string {
+	if !value.Valid {
+		return ""
+	}
+
+	return strconv.FormatInt(value.Int64, 10)
+}
+
+type syncQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func loadTrackKeys(ctx context.Context, queryer syncQueryer) (trackKeyIndex, error) {
+	rows, err := queryer.QueryContext(ctx, `
+		SELECT
+			t.id,
+			COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist'),
+			COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album'),
+			COALESCE(NULLIF(TRIM(t.title), ''), 'Unknown Title'),
+			t.disc_no,
+			t.track_no
+		FROM tracks t
+		ORDER BY t.id
+	`)
+	if err != nil {
+		return trackKeyIndex{}, fmt.Errorf("list tracks for sync: %w", err)
+	}
+	defer rows.Close()
+
+	index := trackKeyIndex{
+		keyByID: make(map[int64]string),
+		idByKey: make(map[string]int64),
+	}
+	for rows.Next() {
+		var id int64
+		var artist, album, title string
+		var discNo, trackNo sql.NullInt64
+		if err := rows.Scan(&id, &artist, &album, &title, &discNo, &trackNo); err != nil {
+			return trackKeyIndex{}, fmt.Errorf("scan track for sync: %w", err)
+		}
+
+		key := trackKey(artist, album, title, discNo, trackNo)
+		index.keyByID[id] = key
+		// Duplicate files of the same recording resolve to the oldest row.
+		if _, exists := index.idByKey[key]; !exists {
+			index.idByKey[key] = id
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return trackKeyIndex{}, fmt.Errorf("iterate tracks for sync: %w", err)
+	}
+
+	return index, nil
+}
+
+// normalizeTimestamp rewrites any RFC 3339 timestamp into timestampLayout.
+func normalizeTimestamp(value string) (string, bool) {
+	parsed, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(value))
+	if err != nil {
+		return "", false
+	}
+
+	return parsed.UTC().Format(timestampLayout), true
+}
@@ -0,0 +1,41 @@
+package devicesync
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestTrackKeyNormalizesCaseAndWhitespace(t *testing.T) {
+	t.Parallel()
+
+	disc := sql.NullInt64{Int64: 1, Valid: true}
+	track := sql.NullInt64{Int64: 7, Valid: true}
+
+	left := trackKey("The  Band", "Album", "Song Title", disc, track)
+	right := trackKey("the band", " album ", "SONG   title", disc, track)
+	if left != right {
+		t.Fatalf("expected equal keys, got %q and %q", left, right)
+	}
+
+	if other := trackKey("The Band", "Album", "Song Title", disc, sql.NullInt64{}); other == left {
+		t.Fatalf("expected track number to distinguish keys")
+	}
+}
+
+func TestNormalizeTimestampProducesSortableUTC(t *testing.T) {
+	t.Parallel()
+
+	earlier, ok := normalizeTimestamp("2026-03-01T10:00:00+02:00")
+	if !ok || earlier != "2026-03-01T08:00:00.000Z" {
+		t.Fatalf("unexpected normalized timestamp %q", earlier)
+	}
+
+	later, ok := normalizeTimestamp("2026-03-01T08:00:00.5Z")
+	if !ok || !(later > earlier) {
+		t.Fatalf("expected %q to sort after %q", later, earlier)
+	}
+
+	if _, ok := normalizeTimestamp("yesterday"); ok {
+		t.Fatalf("expected invalid timestamp to be rejected")
+	}
+}
@@ -0,0 +1,624 @@
+package devicesync
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rzxx/ben/internal/settings"
+	"github.com/rzxx/ben/internal/stats"
+)
+
+const (
+	DeviceIDSettingKey = "sync.device_id"
+	FolderSettingKey   = "sync.folder"
+)
+
+const folderSyncInterval = 15 * time.Minute
+
+const bundleFilePrefix = "ben-sync-"
+
+// ImportResult summarizes how one bundle was merged.
+type ImportResult struct {
+	DeviceID         string `json:"deviceId"`
+	DeviceName       string `json:"deviceName"`
+	PlayStatsApplied int    `json:"playStatsApplied"`
+	RatingsApplied   int    `json:"ratingsApplied"`
+	OlderSkipped     int    `json:"olderSkipped"`
+	Unmatched        int    `json:"unmatched"`
+}
+
+type Status struct {
+	DeviceID   string `json:"deviceId"`
+	DeviceName string `json:"deviceName"`
+	Folder     string `json:"folder"`
+}
+
+type Service struct {
+	mu    sync.Mutex
+	db    *sql.DB
+	store *settings.Store
+	stop  chan struct{}
+}
+
+func NewService(database *sql.DB, store *settings.Store) *Service {
+	return &Service{db: database, store: store}
+}
+
+func (s *Service) Status(ctx context.Context) (Status, error) {
+	deviceID, err := s.deviceID(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+
+	return Status{
+		DeviceID:   deviceID,
+		DeviceName: deviceName(),
+		Folder:     s.store.GetString(ctx, FolderSettingKey, ""),
+	}, nil
+}
+
+// Export collects this device's play stats, the stats it has received from
+// other devices, and every rating and favorite.
+func (s *Service) Export(ctx context.Context) (Bundle, error) {
+	deviceID, err := s.deviceID(ctx)
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	index, err := loadTrackKeys(ctx, s.db)
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	bundle := Bundle{
+		FormatVersion: bundleFormatVersion,
+		DeviceID:      deviceID,
+		DeviceName:    deviceName(),
+		ExportedAt:    time.Now().UTC().Format(timestampLayout),
+		PlayStats:     make([]PlayStatRecord, 0),
+		Ratings:       make([]RatingRecord, 0),
+	}
+
+	if err := s.exportLocalPlayStats(ctx, deviceID, index, &bundle); err != nil {
+		return Bundle{}, err
+	}
+	if err := s.exportSyncedPlayStats(ctx, index, &bundle); err != nil {
+		return Bundle{}, err
+	}
+	if err := s.exportRatings(ctx, index, &bundle); err != nil {
+		return Bundle{}, err
+	}
+
+	return bundle, nil
+}
+
+func (s *Service) ExportToFile(ctx context.Context, path string) error {
+	bundle, err := s.Export(ctx)
+	if err != nil {
+		return err
+	}
+
+	return writeBundle(path, bundle)
+}
+
+func (s *Service) ImportFromFile(ctx context.Context, path string) (ImportResult, error) {
+	bundle, err := readBundle(path)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	return s.Import(ctx, bundle)
+}
+
+// Import merges a bundle from another device. Each record carries its own
+// timestamp and only replaces local data that is older.
+func (s *Service) Import(ctx context.Context, bundle Bundle) (ImportResult, error) {
+	if bundle.FormatVersion > bundleFormatVersion {
+		return ImportResult{}, fmt.Errorf("sync bundle format %d is newer than this version of Ben supports", bundle.FormatVersion)
+	}
+
+	deviceID, err := s.deviceID(ctx)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	if bundle.DeviceID == deviceID {
+		return ImportResult{}, errors.New("sync bundle was exported by this device")
+	}
+
+	result := ImportResult{DeviceID: bundle.DeviceID, DeviceName: bundle.DeviceName}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("begin sync import tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	index, err := loadTrackKeys(ctx, tx)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	for _, record := range bundle.PlayStats {
+		// Our own plays are authoritative locally and never come back in.
+		if record.DeviceID == "" || record.DeviceID == deviceID {
+			continue
+		}
+
+		trackID, ok := index.idByKey[record.TrackKey]
+		updatedAt, validTime := normalizeTimestamp(record.UpdatedAt)
+		if _, dayErr := time.Parse(time.DateOnly, record.Day); !ok || !validTime || dayErr != nil {
+			result.Unmatched++
+			continue
+		}
+
+		applied, err := upsertSyncedPlayStat(ctx, tx, trackID, updatedAt, record)
+		if err != nil {
+			return ImportResult{}, err
+		}
+		if applied {
+			result.PlayStatsApplied++
+		} else {
+			result.OlderSkipped++
+		}
+	}
+
+	for _, record := range bundle.Ratings {
+		trackID, ok := index.idByKey[record.TrackKey]
+		updatedAt, validTime := normalizeTimestamp(record.UpdatedAt)
+		if !ok || !validTime {
+			result.Unmatched++
+			continue
+		}
+		if record.Rating != nil && (*record.Rating < 1 || *record.Rating > 5) {
+			result.Unmatched++
+			continue
+		}
+
+		applied, err := upsertRating(ctx, tx, trackID, updatedAt, record)
+		if err != nil {
+			return ImportResult{}, err
+		}
+		if applied {
+			result.RatingsApplied++
+		} else {
+			result.OlderSkipped++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ImportResult{}, fmt.Errorf("commit sync import tx: %w", err)
+	}
+
+	return result, nil
+}
+
+// SetFolder configures a shared folder (e.g. a synced cloud drive) that
+// every paired device writes its bundle into. An empty path disables it.
+func (s *Service) SetFolder(ctx context.Context, path string) error {
+	folder := strings.TrimSpace(path)
+	if folder == "" {
+		return s.store.Delete(ctx, FolderSettingKey)
+	}
+
+	absFolder, err := filepath.Abs(folder)
+	if err != nil {
+		return fmt.Errorf("resolve sync folder: %w", err)
+	}
+	info, err := os.Stat(absFolder)
+	if err != nil {
+		return fmt.Errorf("sync folder: %w", err)
+	}
+	if !info.IsDir() {
+		return errors.New("sync folder must be a directory")
+	}
+
+	return s.store.Set(ctx, FolderSettingKey, filepath.Clean(absFolder))
+}
+
+// SyncFolderNow imports every other device's bundle from the shared folder
+// and then writes this device's bundle, so the written file already
+// contains what was just merged.
+func (s *Service) SyncFolderNow(ctx context.Context) ([]ImportResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	folder := s.store.GetString(ctx, FolderSettingKey, "")
+	if folder == "" {
+		return nil, errors.New("sync folder is not configured")
+	}
+
+	deviceID, err := s.deviceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return nil, fmt.Errorf("read sync folder: %w", err)
+	}
+
+	ownFile := bundleFilePrefix + deviceID + ".json"
+	results := make([]ImportResult, 0)
+	var syncErrs []error
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || name == ownFile || !strings.HasPrefix(name, bundleFilePrefix) || filepath.Ext(name) != ".json" {
+			continue
+		}
+
+		result, err := s.ImportFromFile(ctx, filepath.Join(folder, name))
+		if err != nil {
+			syncErrs = append(syncErrs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		results = append(results, result)
+	}
+
+	if err := s.ExportToFile(ctx, filepath.Join(folder, ownFile)); err != nil {
+		syncErrs = append(syncErrs, err)
+	}
+
+	return results, errors.Join(syncErrs...)
+}
+
+func (s *Service) Start() {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	stopCh := make(chan struct{})
+	s.stop = stopCh
+	s.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(folderSyncInterval)
+		defer ticker.Stop()
+
+		for {
+			ctx := context.Background()
+			if s.store.GetString(ctx, FolderSettingKey, "") != "" {
+				if _, err := s.SyncFolderNow(ctx); err != nil {
+					log.Printf("device sync: %v", err)
+				}
+			}
+
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *Service) Stop() {
+	s.mu.Lock()
+	stopCh := s.stop
+	s.stop = nil
+	s.mu.Unlock()
+
+	if stopCh != nil {
+		close(stopCh)
+	}
+}
+
+func (s *Service) exportLocalPlayStats(ctx context.Context, deviceID string, index trackKeyIndex, bundle *Bundle) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			day,
+			track_id,
+			COALESCE(SUM(played_ms), 0),
+			COALESCE(SUM(heartbeat_count), 0),
+			COALESCE(SUM(complete_count), 0),
+			COALESCE(SUM(skip_count), 0),
+			COALESCE(SUM(partial_count), 0),
+			MAX(updated_at)
+		FROM (
+			SELECT
+				substr(ts, 1, 10) AS day,
+				track_id,
+				CASE WHEN event_type = ? THEN COALESCE(position_ms, 0) ELSE 0 END AS played_ms,
+				CASE WHEN event_type = ? THEN 1 ELSE 0 END AS heartbeat_count,
+				CASE WHEN event_type = ? THEN 1 ELSE 0 END AS complete_count,
+				CASE WHEN event_type = ? THEN 1 ELSE 0 END AS skip_count,
+				CASE WHEN event_type = ? THEN 1 ELSE 0 END AS partial_count,
+				ts AS updated_at
+			FROM play_events
+			UNION ALL
+			SELECT day, track_id, played_ms, heartbeat_count, complete_count, skip_count, partial_count, updated_at
+			FROM play_stats_daily
+		) AS metrics
+		GROUP BY day, track_id
+	`,
+		stats.EventHeartbeat,
+		stats.EventHeartbeat,
+		stats.EventComplete,
+		stats.EventSkip,
+		stats.EventPartial,
+	)
+	if err != nil {
+		return fmt.Errorf("export play stats: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		record := PlayStatRecord{DeviceID: deviceID}
+		var trackID int64
+		if err := rows.Scan(
+			&record.Day,
+			&trackID,
+			&record.PlayedMS,
+			&record.HeartbeatCount,
+			&record.CompleteCount,
+			&record.SkipCount,
+			&record.PartialCount,
+			&record.UpdatedAt,
+		); err != nil {
+			return fmt.Errorf("scan play stat for export: %w", err)
+		}
+
+		key, ok := index.keyByID[trackID]
+		updatedAt, validTime := normalizeTimestamp(record.UpdatedAt)
+		if !ok || !validTime {
+			continue
+		}
+		record.TrackKey = key
+		record.UpdatedAt = updatedAt
+		bundle.PlayStats = append(bundle.PlayStats, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate play stats for export: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) exportSyncedPlayStats(ctx context.Context, index trackKeyIndex, bundle *Bundle) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT device_id, day, track_id, played_ms, heartbeat_count, complete_count, skip_count, partial_count, updated_at
+		FROM synced_play_stats
+	`)
+	if err != nil {
+		return fmt.Errorf("export synced play stats: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record PlayStatRecord
+		var trackID int64
+		if err := rows.Scan(
+			&record.DeviceID,
+			&record.Day,
+			&trackID,
+			&record.PlayedMS,
+			&record.HeartbeatCount,
+			&record.CompleteCount,
+			&record.SkipCount,
+			&record.PartialCount,
+			&record.UpdatedAt,
+		); err != nil {
+			return fmt.Errorf("scan synced play stat for export: %w", err)
+		}
+
+		key, ok := index.keyByID[trackID]
+		if !ok {
+			continue
+		}
+		record.TrackKey = key
+		bundle.PlayStats = append(bundle.PlayStats, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate synced play stats for export: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) exportRatings(ctx context.Context, index trackKeyIndex, bundle *Bundle) error {
+	rows, err := s.db.QueryContext(ctx, "SELECT track_id, rating, favorite, updated_at FROM track_ratings")
+	if err != nil {
+		return fmt.Errorf("export ratings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record RatingRecord
+		var trackID int64
+		var rating sql.NullInt64
+		var favoriteInt int
+		if err := rows.Scan(&trackID, &rating, &favoriteInt, &record.UpdatedAt); err != nil {
+			return fmt.Errorf("scan rating for export: %w", err)
+		}
+
+		key, ok := index.keyByID[trackID]
+		if !ok {
+			continue
+		}
+		record.TrackKey = key
+		record.Favorite = favoriteInt == 1
+		if rating.Valid {
+			value := int(rating.Int64)
+			record.Rating = &value
+		}
+		bundle.Ratings = append(bundle.Ratings, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate ratings for export: %w", err)
+	}
+
+	return nil
+}
+
+func upsertSyncedPlayStat(ctx context.Context, tx *sql.Tx, trackID int64, updatedAt string, record PlayStatRecord) (bool, error) {
+	result, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO synced_play_stats(
+			device_id,
+			day,
+			track_id,
+			played_ms,
+			heartbeat_count,
+			complete_count,
+			skip_count,
+			partial_count,
+			updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(device_id, day, track_id) DO UPDATE SET
+			played_ms = excluded.played_ms,
+			heartbeat_count = excluded.heartbeat_count,
+			complete_count = excluded.complete_count,
+			skip_count = excluded.skip_count,
+			partial_count = excluded.partial_count,
+			updated_at = excluded.updated_at
+		WHERE excluded.updated_at > synced_play_stats.updated_at`,
+		record.DeviceID,
+		record.Day,
+		trackID,
+		record.PlayedMS,
+		record.HeartbeatCount,
+		record.CompleteCount,
+		record.SkipCount,
+		record.PartialCount,
+		updatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("merge play stats for track %d: %w", trackID, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("read merged play stats count: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func upsertRating(ctx context.Context, tx *sql.Tx, trackID int64, updatedAt string, record RatingRecord) (bool, error) {
+	favoriteInt := 0
+	if record.Favorite {
+		favoriteInt = 1
+	}
+
+	var rating any
+	if record.Rating != nil {
+		rating = *record.Rating
+	}
+
+	result, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO track_ratings(track_id, rating, favorite, updated_at)
+		 VALUES (?, ?, ?, ?)
+		 ON CONFLICT(track_id) DO UPDATE SET
+		 	rating = excluded.rating,
+		 	favorite = excluded.favorite,
+		 	updated_at = excluded.updated_at
+		 WHERE excluded.updated_at > track_ratings.updated_at`,
+		trackID,
+		rating,
+		favoriteInt,
+		updatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("merge rating for track %d: %w", trackID, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("read merged rating count: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (s *Service) deviceID(ctx context.Context) (string, error) {
+	if value, ok, err := s.store.Get(ctx, DeviceIDSettingKey); err != nil {
+		return "", err
+	} else if ok && value != "" {
+		return value, nil
+	}
+
+	buffer := make([]byte, 16)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", fmt.Errorf("generate device id: %w", err)
+	}
+
+	deviceID := hex.EncodeToString(buffer)
+	if err := s.store.Set(ctx, DeviceIDSettingKey, deviceID); err != nil {
+		return "", err
+	}
+
+	return deviceID, nil
+}
+
+func deviceName() string {
+	hostname, err := os.Hostname()
+	if err != nil || strings.TrimSpace(hostname) == "" {
+		return "Unknown device"
+	}
+
+	return hostname
+}
+
+func writeBundle(path string, bundle Bundle) error {
+	content, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode sync bundle: %w", err)
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(path), ".ben-sync-*")
+	if err != nil {
+		return fmt.Errorf("write sync bundle: %w", err)
+	}
+	tempPath := tempFile.Name()
+
+	if _, err := tempFile.Write(content); err != nil {
+		tempFile.Close()
+		os.Remove(tempPath)
+		return fmt.Errorf("write sync bundle: %w", err)
+	}
+	if err := tempFile.Close(); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("write sync bundle: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("write sync bundle: %w", err)
+	}
+
+	return nil
+}
+
+func readBundle(path string) (Bundle, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("read sync bundle: %w", err)
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(content, &bundle); err != nil {
+		return Bundle{}, fmt.Errorf("decode sync bundle: %w", err)
+	}
+	if strings.TrimSpace(bundle.DeviceID) == "" {
+		return Bundle{}, errors.New("sync bundle has no device id")
+	}
+
+	return bundle, nil
+}
@@ -0,0 +1,295 @@
+package devicesync
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/rzxx/ben/internal/db"
+	"github.com/rzxx/ben/internal/settings"
+	"github.com/rzxx/ben/internal/stats"
+)
+
+func newSyncServiceForTest(t *testing.T, deviceID string) (*Service, *sql.DB) {
+	t.Helper()
+
+	database, err := db.Bootstrap(filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatalf("bootstrap test database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	store := settings.NewStore(database)
+	if err := store.Set(context.Background(), DeviceIDSettingKey, deviceID); err != nil {
+		t.Fatalf("set device id: %v", err)
+	}
+
+	return NewService(database, store), database
+}
+
+func insertTrackForSyncTest(t *testing.T, database *sql.DB, title string) int64 {
+	t.Helper()
+
+	fileResult, err := database.Exec(
+		`INSERT INTO files(path, size, mtime_ns, file_exists, last_seen_at) VALUES (?, 123, 1, 1, '2026-03-01T00:00:00Z')`,
+		filepath.Join("Music", title+".flac"),
+	)
+	if err != nil {
+		t.Fatalf("insert file row: %v", err)
+	}
+	fileID, err := fileResult.LastInsertId()
+	if err != nil {
+		t.Fatalf("read file id: %v", err)
+	}
+
+	trackResult, err := database.Exec(
+		`INSERT INTO tracks(file_id, title, artist, album, album_artist, track_no, duration_ms, tags_json) VALUES (?, ?, 'Band', 'Album', 'Band', 1, 240000, '{}')`,
+		fileID,
+		title,
+	)
+	if err != nil {
+		t.Fatalf("insert track row: %v", err)
+	}
+	trackID, err := trackResult.LastInsertId()
+	if err != nil {
+		t.Fatalf("read track id: %v", err)
+	}
+
+	return trackID
+}
+
+func setRatingForSyncTest(t *testing.T, database *sql.DB, trackID int64, rating int, updatedAt string) {
+	t.Helper()
+
+	if _, err := database.Exec(
+		`INSERT INTO track_ratings(track_id, rating, favorite, updated_at) VALUES (?, ?, 0, ?)
+		 ON CONFLICT(track_id) DO UPDATE SET rating = excluded.rating, updated_at = excluded.updated_at`,
+		trackID,
+		rating,
+		updatedAt,
+	); err != nil {
+		t.Fatalf("set rating: %v", err)
+	}
+}
+
+func ratingForSyncTest(t *testing.T, database *sql.DB, trackID int64) int {
+	t.Helper()
+
+	var rating int
+	if err := database.QueryRow(`SELECT rating FROM track_ratings WHERE track_id = ?`, trackID).Scan(&rating); err != nil {
+		t.Fatalf("read rating: %v", err)
+	}
+
+	return rating
+}
+
+func syncedPlayedMSForSyncTest(t *testing.T, database *sql.DB, deviceID string, trackID int64) int {
+	t.Helper()
+
+	var playedMS int
+	if err := database.QueryRow(
+		`SELECT played_ms FROM synced_play_stats WHERE device_id = ? AND day = '2026-03-01' AND track_id = ?`,
+		deviceID,
+		trackID,
+	).Scan(&playedMS); err != nil {
+		t.Fatalf("read synced play stat: %v", err)
+	}
+
+	return playedMS
+}
+
+func TestImportKeepsTheNewestRecord(t *testing.T) {
+	t.Parallel()
+
+	service, database := newSyncServiceForTest(t, "local")
+	trackID := insertTrackForSyncTest(t, database, "Song")
+	setRatingForSyncTest(t, database, trackID, 3, "2026-03-01T10:00:00.000Z")
+	if _, err := database.Exec(
+		`INSERT INTO synced_play_stats(device_id, day, track_id, played_ms, updated_at) VALUES ('laptop', '2026-03-01', ?, 1000, '2026-03-01T10:00:00.000Z')`,
+		trackID,
+	); err != nil {
+		t.Fatalf("insert synced play stat: %v", err)
+	}
+
+	index, err := loadTrackKeys(context.Background(), database)
+	if err != nil {
+		t.Fatalf("load track keys: %v", err)
+	}
+	key := index.keyByID[trackID]
+
+	cases := []struct {
+		name       string
+		updatedAt  string
+		wantRating int
+		wantPlayed int
+		wantResult ImportResult
+	}{
+		{
+			name:       "older",
+			updatedAt:  "2026-03-01T09:59:59.999Z",
+			wantRating: 3,
+			wantPlayed: 1000,
+			wantResult: ImportResult{OlderSkipped: 2},
+		},
+		{
+			name:       "same time",
+			updatedAt:  "2026-03-01T10:00:00Z",
+			wantRating: 3,
+			wantPlayed: 1000,
+			wantResult: ImportResult{OlderSkipped: 2},
+		},
+		{
+			// Offsets are normalized to UTC before they are compared.
+			name:       "newer",
+			updatedAt:  "2026-03-01T12:00:00.001+02:00",
+			wantRating: 5,
+			wantPlayed: 2000,
+			wantResult: ImportResult{PlayStatsApplied: 1, RatingsApplied: 1},
+		},
+	}
+	for _, tc := range cases {
+		rating := 5
+		result, err := service.Import(context.Background(), Bundle{
+			FormatVersion: bundleFormatVersion,
+			DeviceID:      "laptop",
+			PlayStats: []PlayStatRecord{
+				{DeviceID: "laptop", Day: "2026-03-01", TrackKey: key, PlayedMS: 2000, UpdatedAt: tc.updatedAt},
+			},
+			Ratings: []RatingRecord{{TrackKey: key, Rating: &rating, UpdatedAt: tc.updatedAt}},
+		})
+		if err != nil {
+			t.Fatalf("%s: import: %v", tc.name, err)
+		}
+
+		tc.wantResult.DeviceID = "laptop"
+		if result != tc.wantResult {
+			t.Fatalf("%s: got %+v, want %+v", tc.name, result, tc.wantResult)
+		}
+		if got := ratingForSyncTest(t, database, trackID); got != tc.wantRating {
+			t.Fatalf("%s: expected rating %d, got %d", tc.name, tc.wantRating, got)
+		}
+		if got := syncedPlayedMSForSyncTest(t, database, "laptop", trackID); got != tc.wantPlayed {
+			t.Fatalf("%s: expected %d ms played, got %d", tc.name, tc.wantPlayed, got)
+		}
+	}
+}
+
+func TestImportSkipsUnknownTracksAndOwnPlays(t *testing.T) {
+	t.Parallel()
+
+	service, database := newSyncServiceForTest(t, "local")
+	insertTrackForSyncTest(t, database, "Song")
+
+	rating := 9
+	result, err := service.Import(context.Background(), Bundle{
+		FormatVersion: bundleFormatVersion,
+		DeviceID:      "laptop",
+		PlayStats: []PlayStatRecord{
+			{DeviceID: "local", Day: "2026-03-01", TrackKey: "missing", UpdatedAt: "2026-03-01T10:00:00Z"},
+			{DeviceID: "laptop", Day: "2026-03-01", TrackKey: "missing", UpdatedAt: "2026-03-01T10:00:00Z"},
+		},
+		Ratings: []RatingRecord{{TrackKey: "missing", Rating: &rating, UpdatedAt: "not a time"}},
+	})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.Unmatched != 2 || result.PlayStatsApplied != 0 || result.RatingsApplied != 0 {
+		t.Fatalf("expected two unmatched records and our own plays ignored, got %+v", result)
+	}
+
+	if _, err := service.Import(context.Background(), Bundle{FormatVersion: bundleFormatVersion, DeviceID: "local"}); err == nil {
+		t.Fatalf("expected a bundle of this device to be rejected")
+	}
+	if _, err := service.Import(context.Background(), Bundle{FormatVersion: bundleFormatVersion + 1, DeviceID: "laptop"}); err == nil {
+		t.Fatalf("expected a newer bundle format to be rejected")
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	desktop, desktopDB := newSyncServiceForTest(t, "desktop")
+	laptop, laptopDB := newSyncServiceForTest(t, "laptop")
+
+	desktopTrack := insertTrackForSyncTest(t, desktopDB, "Song")
+	insertTrackForSyncTest(t, laptopDB, "Intro")
+	laptopTrack := insertTrackForSyncTest(t, laptopDB, "Song")
+
+	for _, event := range []struct {
+		eventType  string
+		positionMS int
+		ts         string
+	}{
+		{eventType: stats.EventHeartbeat, positionMS: 30000, ts: "2026-03-01T10:00:00.000Z"},
+		{eventType: stats.EventComplete, positionMS: 240000, ts: "2026-03-01T10:04:00.000Z"},
+	} {
+		if _, err := desktopDB.Exec(
+			`INSERT INTO play_events(track_id, event_type, position_ms, ts) VALUES (?, ?, ?, ?)`,
+			desktopTrack,
+			event.eventType,
+			event.positionMS,
+			event.ts,
+		); err != nil {
+			t.Fatalf("insert play event: %v", err)
+		}
+	}
+	setRatingForSyncTest(t, desktopDB, desktopTrack, 4, "2026-03-01T11:00:00.000Z")
+
+	path := filepath.Join(t.TempDir(), "bundle.json")
+	if err := desktop.ExportToFile(ctx, path); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	result, err := laptop.ImportFromFile(ctx, path)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.DeviceID != "desktop" || result.PlayStatsApplied != 1 || result.RatingsApplied != 1 || result.Unmatched != 0 {
+		t.Fatalf("unexpected import result %+v", result)
+	}
+
+	// Row ids differ between the devices; the track is matched by its tags.
+	var completeCount int
+	if err := laptopDB.QueryRow(
+		`SELECT complete_count FROM synced_play_stats WHERE device_id = 'desktop' AND day = '2026-03-01' AND track_id = ?`,
+		laptopTrack,
+	).Scan(&completeCount); err != nil {
+		t.Fatalf("read synced play stat: %v", err)
+	}
+	if completeCount != 1 || syncedPlayedMSForSyncTest(t, laptopDB, "desktop", laptopTrack) != 30000 {
+		t.Fatalf("expected the desktop plays on the laptop, got %d completes", completeCount)
+	}
+	if rating := ratingForSyncTest(t, laptopDB, laptopTrack); rating != 4 {
+		t.Fatalf("expected the desktop rating on the laptop, got %d", rating)
+	}
+
+	// Importing the same bundle again changes nothing.
+	again, err := laptop.ImportFromFile(ctx, path)
+	if err != nil {
+		t.Fatalf("import again: %v", err)
+	}
+	if again.PlayStatsApplied != 0 || again.RatingsApplied != 0 || again.OlderSkipped != 2 {
+		t.Fatalf("expected the second import skipped, got %+v", again)
+	}
+
+	// The laptop relays the desktop plays back; they are not counted twice.
+	relayed, err := laptop.Export(ctx)
+	if err != nil {
+		t.Fatalf("export from the laptop: %v", err)
+	}
+	back, err := desktop.Import(ctx, relayed)
+	if err != nil {
+		t.Fatalf("import on the desktop: %v", err)
+	}
+	if back.PlayStatsApplied != 0 || back.RatingsApplied != 0 {
+		t.Fatalf("expected nothing new on the desktop, got %+v", back)
+	}
+	var syncedRows int
+	if err := desktopDB.QueryRow(`SELECT COUNT(1) FROM synced_play_stats`).Scan(&syncedRows); err != nil {
+		t.Fatalf("count synced play stats: %v", err)
+	}
+	if syncedRows != 0 {
+		t.Fatalf("expected the desktop's own plays kept out of synced stats, got %d rows", syncedRows)
+	}
+}
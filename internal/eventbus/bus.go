@@ -0,0 +1,131 @@
+package eventbus
+
+import (
+	"sort"
+	"sync"
+)
+
+// Handler receives every event published on the bus.
+type Handler func(eventName string, payload any)
+
+type subscription struct {
+	id      int
+	handler Handler
+}
+
+// Bus delivers events published by the domain services to the modules that
+// subscribed to them. Modules receive the bus in their constructor, publish
+// through Bus.Publish and subscribe to the events they handle themselves.
+// A nil bus drops every event, which is what tests without listeners use.
+//
+// Delivery is synchronous and in subscription order, so a handler sees the
+// events of one publisher in the order they were published. Handlers must
+// not block; long work belongs on a goroutine.
+type Bus struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[string][]subscription
+	all      []subscription
+}
+
+func New() *Bus {
+	return &Bus{handlers: make(map[string][]subscription)}
+}
+
+// Publish delivers payload to the subscribers of eventName and then to the
+// subscribers of every event.
+func (b *Bus) Publish(eventName string, payload any) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	named := b.handlers[eventName]
+	all := b.all
+	b.mu.RUnlock()
+
+	for _, subscription := range named {
+		subscription.handler(eventName, payload)
+	}
+	for _, subscription := range all {
+		subscription.handler(eventName, payload)
+	}
+}
+
+// SubscribeAll registers handler for every event, e.g. to forward them to
+// the frontend. The returned func removes the subscription.
+func (b *Bus) SubscribeAll(handler Handler) func() {
+	if b == nil {
+		return func() {}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextSubscriptionIDLocked()
+	b.all = appendSubscription(b.all, subscription{id: id, handler: handler})
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.all = removeSubscription(b.all, id)
+	}
+}
+
+func (b *Bus) subscribe(eventName string, handler Handler) func() {
+	if b == nil {
+		return func() {}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextSubscriptionIDLocked()
+	b.handlers[eventName] = appendSubscription(b.handlers[eventName], subscription{id: id, handler: handler})
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		remaining := removeSubscription(b.handlers[eventName], id)
+		if len(remaining) == 0 {
+			delete(b.handlers, eventName)
+			return
+		}
+		b.handlers[eventName] = remaining
+	}
+}
+
+func (b *Bus) nextSubscriptionIDLocked() int {
+	b.nextID++
+	return b.nextID
+}
+
+// Subscribe registers a typed handler for eventName. Payloads of another type
+// are ignored, so a handler never has to assert the payload itself. The
+// returned func removes the subscription.
+func Subscribe[T any](bus *Bus, eventName string, handler func(T)) func() {
+	return bus.subscribe(eventName, func(_ string, payload any) {
+		if typed, ok := payload.(T); ok {
+			handler(typed)
+		}
+	})
+}
+
+// appendSubscription copies the slice so Publish can iterate a snapshot
+// without holding the lock.
+func appendSubscription(subscriptions []subscription, added subscription) []subscription {
+	next := make([]subscription, 0, len(subscriptions)+1)
+	next = append(next, subscriptions...)
+	return append(next, added)
+}
+
+func removeSubscription(subscriptions []subscription, id int) []subscription {
+	index := sort.Search(len(subscriptions), func(i int) bool {
+		return subscriptions[i].id >= id
+	})
+	if index >= len(subscriptions) || subscriptions[index].id != id {
+		return subscriptions
+	}
+
+	next := make([]subscription, 0, len(subscriptions)-1)
+	next = append(next, subscriptions[:index]...)
+	return append(next, subscriptions[index+1:]...)
+}
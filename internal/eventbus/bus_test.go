@@ -0,0 +1,50 @@
+package eventbus
+
+import "testing"
+
+type testState struct {
+	Value int
+}
+
+func TestSubscribeDeliversTypedPayloads(t *testing.T) {
+	t.Parallel()
+
+	bus := New()
+	received := make([]int, 0)
+	unsubscribe := Subscribe(bus, "test:changed", func(state testState) {
+		received = append(received, state.Value)
+	})
+
+	bus.Publish("test:changed", testState{Value: 1})
+	bus.Publish("test:changed", "not a state")
+	bus.Publish("test:other", testState{Value: 2})
+	unsubscribe()
+	bus.Publish("test:changed", testState{Value: 3})
+
+	if len(received) != 1 || received[0] != 1 {
+		t.Fatalf("expected only the first matching payload, got %v", received)
+	}
+}
+
+func TestSubscribeAllSeesEveryEventInOrder(t *testing.T) {
+	t.Parallel()
+
+	bus := New()
+	names := make([]string, 0)
+	first := Subscribe(bus, "a", func(int) {})
+	unsubscribe := bus.SubscribeAll(func(eventName string, _ any) {
+		names = append(names, eventName)
+	})
+	second := Subscribe(bus, "b", func(int) {})
+	first()
+	second()
+
+	bus.Publish("a", 1)
+	bus.Publish("b", 2)
+	unsubscribe()
+	bus.Publish("c", 3)
+
+	if len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Fatalf("unexpected forwarded events %v", names)
+	}
+}
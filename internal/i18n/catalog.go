@@ -0,0 +1,228 @@
+package i18n
+
+const DefaultLocale = "en"
+
+// LocaleOption is a locale the backend ships messages for, named in its own
+// language so the picker stays readable whatever is selected.
+type LocaleOption struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+var supportedLocales = []LocaleOption{
+	{Code: "en", Name: "English"},
+	{Code: "de", Name: "Deutsch"},
+	{Code: "es", Name: "Español"},
+	{Code: "fr", Name: "Français"},
+	{Code: "ru", Name: "Русский"},
+}
+
+// Placeholder labels stored in queries and returned as artist, album, title
+// and genre names. They stay in English because the frontend passes them back
+// as lookup keys; the catalog carries their translations for display.
+const (
+	UnknownArtist = "Unknown Artist"
+	UnknownAlbum  = "Unknown Album"
+	UnknownTitle  = "Unknown Title"
+	UnknownGenre  = "Unknown Genre"
+)
+
+var placeholderKeys = map[string]string{
+	UnknownArtist: "unknown.artist",
+	UnknownAlbum:  "unknown.album",
+	UnknownTitle:  "unknown.title",
+	UnknownGenre:  "unknown.genre",
+}
+
+var catalogs = map[string]map[string]string{
+	"en": {
+		"unknown.artist":            UnknownArtist,
+		"unknown.album":             UnknownAlbum,
+		"unknown.title":             UnknownTitle,
+		"unknown.genre":             UnknownGenre,
+		"weekday.0":                 "Sun",
+		"weekday.1":                 "Mon",
+		"weekday.2":                 "Tue",
+		"weekday.3":                 "Wed",
+		"weekday.4":                 "Thu",
+		"weekday.5":                 "Fri",
+		"weekday.6":                 "Sat",
+		"scan.start.full":           "Starting full scan",
+		"scan.start.incremental":    "Starting incremental scan",
+		"scan.start.repair":         "Starting repair scan",
+		"scan.noRoots":              "No enabled watched folders configured",
+		"scan.applyingChanges":      "Applying %d filesystem change(s)",
+		"scan.verifying":            "No queued filesystem events, running full incremental verification",
+		"scan.countingFiles":        "Counting files",
+		"scan.scanningRoot":         "Scanning %s",
+		"scan.scanningRemote":       "Scanning remote %s",
+		"scan.remoteOffline":        "%s is offline, keeping its indexed tracks",
+		"scan.fingerprinting":       "Fingerprinting audio files",
+		"scan.removingStale":        "Removing stale track entries",
+		"scan.refreshingCatalog":    "Refreshing artists, albums, and album track mappings",
+		"scan.noChanges":            "No library changes detected, skipping derived catalog refresh",
+		"scan.complete.full":        "Full scan complete: %d files seen, %d indexed, %d skipped",
+		"scan.complete.incremental": "Incremental scan complete: %d files seen, %d indexed, %d skipped",
+		"scan.complete.repair":      "Repair scan complete: %d files seen, %d indexed, %d skipped",
+		"scan.canceled":             "Scan canceled, files indexed so far were kept",
+		"announce.nowPlaying":       "Now playing %s by %s",
+		"announce.fromAlbum":        "from %s",
+		"announce.trackNumber":      "track %d",
+		"announce.duration":         "%d minutes %d seconds",
+		"announce.paused":           "Paused %s",
+		"announce.resumed":          "Resumed %s",
+		"announce.stopped":          "Playback stopped",
+	},
+	"de": {
+		"unknown.artist":            "Unbekannter Künstler",
+		"unknown.album":             "Unbekanntes Album",
+		"unknown.title":             "Unbekannter Titel",
+		"unknown.genre":             "Unbekanntes Genre",
+		"weekday.0":                 "So",
+		"weekday.1":                 "Mo",
+		"weekday.2":                 "Di",
+		"weekday.3":                 "Mi",
+		"weekday.4":                 "Do",
+		"weekday.5":                 "Fr",
+		"weekday.6":                 "Sa",
+		"scan.start.full":           "Vollständiger Scan wird gestartet",
+		"scan.start.incremental":    "Inkrementeller Scan wird gestartet",
+		"scan.start.repair":         "Reparaturscan wird gestartet",
+		"scan.noRoots":              "Keine aktivierten überwachten Ordner eingerichtet",
+		"scan.applyingChanges":      "%d Dateisystemänderung(en) werden übernommen",
+		"scan.verifying":            "Keine ausstehenden Dateisystemereignisse, vollständige Prüfung läuft",
+		"scan.countingFiles":        "Dateien werden gezählt",
+		"scan.scanningRoot":         "%s wird gescannt",
+		"scan.scanningRemote":       "Entfernter Ordner %s wird gescannt",
+		"scan.remoteOffline":        "%s ist offline, die indizierten Titel bleiben erhalten",
+		"scan.fingerprinting":       "Audio-Fingerabdrücke werden berechnet",
+		"scan.removingStale":        "Veraltete Titeleinträge werden entfernt",
+		"scan.refreshingCatalog":    "Künstler, Alben und Albumtitel werden aktualisiert",
+		"scan.noChanges":            "Keine Änderungen an der Bibliothek, Katalogaktualisierung wird übersprungen",
+		"scan.complete.full":        "Vollständiger Scan abgeschlossen: %d Dateien gefunden, %d indiziert, %d übersprungen",
+		"scan.complete.incremental": "Inkrementeller Scan abgeschlossen: %d Dateien gefunden, %d indiziert, %d übersprungen",
+		"scan.complete.repair":      "Reparaturscan abgeschlossen: %d Dateien gefunden, %d indiziert, %d übersprungen",
+		"scan.canceled":             "Scan abgebrochen, bereits indizierte Dateien bleiben erhalten",
+		"announce.nowPlaying":       "Jetzt läuft %s von %s",
+		"announce.fromAlbum":        "aus %s",
+		"announce.trackNumber":      "Titel %d",
+		"announce.duration":         "%d Minuten %d Sekunden",
+		"announce.paused":           "%s pausiert",
+		"announce.resumed":          "%s fortgesetzt",
+		"announce.stopped":          "Wiedergabe beendet",
+	},
+	"es": {
+		"unknown.artist":            "Artista desconocido",
+		"unknown.album":             "Álbum desconocido",
+		"unknown.title":             "Título desconocido",
+		"unknown.genre":             "Género desconocido",
+		"weekday.0":                 "dom",
+		"weekday.1":                 "lun",
+		"weekday.2":                 "mar",
+		"weekday.3":                 "mié",
+		"weekday.4":                 "jue",
+		"weekday.5":                 "vie",
+		"weekday.6":                 "sáb",
+		"scan.start.full":           "Iniciando análisis completo",
+		"scan.start.incremental":    "Iniciando análisis incremental",
+		"scan.start.repair":         "Iniciando análisis de reparación",
+		"scan.noRoots":              "No hay carpetas vigiladas activadas",
+		"scan.applyingChanges":      "Aplicando %d cambio(s) del sistema de archivos",
+		"scan.verifying":            "No hay eventos pendientes, ejecutando una verificación completa",
+		"scan.countingFiles":        "Contando archivos",
+		"scan.scanningRoot":         "Analizando %s",
+		"scan.scanningRemote":       "Analizando la carpeta remota %s",
+		"scan.remoteOffline":        "%s no está disponible, se conservan sus pistas indexadas",
+		"scan.fingerprinting":       "Calculando huellas de audio",
+		"scan.removingStale":        "Eliminando entradas de pistas obsoletas",
+		"scan.refreshingCatalog":    "Actualizando artistas, álbumes y pistas de álbum",
+		"scan.noChanges":            "No hay cambios en la biblioteca, se omite la actualización del catálogo",
+		"scan.complete.full":        "Análisis completo terminado: %d archivos vistos, %d indexados, %d omitidos",
+		"scan.complete.incremental": "Análisis incremental terminado: %d archivos vistos, %d indexados, %d omitidos",
+		"scan.complete.repair":      "Análisis de reparación terminado: %d archivos vistos, %d indexados, %d omitidos",
+		"scan.canceled":             "Escaneo cancelado, se conservan los archivos ya indexados",
+		"announce.nowPlaying":       "Reproduciendo %s de %s",
+		"announce.fromAlbum":        "del álbum %s",
+		"announce.trackNumber":      "pista %d",
+		"announce.duration":         "%d minutos %d segundos",
+		"announce.paused":           "%s en pausa",
+		"announce.resumed":          "%s reanudada",
+		"announce.stopped":          "Reproducción detenida",
+	},
+	"fr": {
+		"unknown.artist":            "Artiste inconnu",
+		"unknown.album":             "Album inconnu",
+		"unknown.title":             "Titre inconnu",
+		"unknown.genre":             "Genre inconnu",
+		"weekday.0":                 "dim.",
+		"weekday.1":                 "lun.",
+		"weekday.2":                 "mar.",
+		"weekday.3":                 "mer.",
+		"weekday.4":                 "jeu.",
+		"weekday.5":                 "ven.",
+		"weekday.6":                 "sam.",
+		"scan.start.full":           "Démarrage de l’analyse complète",
+		"scan.start.incremental":    "Démarrage de l’analyse incrémentale",
+		"scan.start.repair":         "Démarrage de l’analyse de réparation",
+		"scan.noRoots":              "Aucun dossier surveillé activé",
+		"scan.applyingChanges":      "Application de %d modification(s) du système de fichiers",
+		"scan.verifying":            "Aucun événement en attente, vérification complète en cours",
+		"scan.countingFiles":        "Comptage des fichiers",
+		"scan.scanningRoot":         "Analyse de %s",
+		"scan.scanningRemote":       "Analyse du dossier distant %s",
+		"scan.remoteOffline":        "%s est hors ligne, ses pistes indexées sont conservées",
+		"scan.fingerprinting":       "Calcul des empreintes audio",
+		"scan.removingStale":        "Suppression des pistes obsolètes",
+		"scan.refreshingCatalog":    "Mise à jour des artistes, albums et pistes d’album",
+		"scan.noChanges":            "Aucun changement dans la bibliothèque, mise à jour du catalogue ignorée",
+		"scan.complete.full":        "Analyse complète terminée : %d fichiers vus, %d indexés, %d ignorés",
+		"scan.complete.incremental": "Analyse incrémentale terminée : %d fichiers vus, %d indexés, %d ignorés",
+		"scan.complete.repair":      "Analyse de réparation terminée : %d fichiers vus, %d indexés, %d ignorés",
+		"scan.canceled":             "Analyse annulée, les fichiers déjà indexés sont conservés",
+		"announce.nowPlaying":       "Lecture de %s par %s",
+		"announce.fromAlbum":        "de l’album %s",
+		"announce.trackNumber":      "piste %d",
+		"announce.duration":         "%d minutes %d secondes",
+		"announce.paused":           "%s en pause",
+		"announce.resumed":          "Reprise de %s",
+		"announce.stopped":          "Lecture arrêtée",
+	},
+	"ru": {
+		"unknown.artist":            "Неизвестный исполнитель",
+		"unknown.album":             "Неизвестный альбом",
+		"unknown.title":             "Без названия",
+		"unknown.genre":             "Неизвестный жанр",
+		"weekday.0":                 "Вс",
+		"weekday.1":                 "Пн",
+		"weekday.2":                 "Вт",
+		"weekday.3":                 "Ср",
+		"weekday.4":                 "Чт",
+		"weekday.5":                 "Пт",
+		"weekday.6":                 "Сб",
+		"scan.start.full":           "Запуск полного сканирования",
+		"scan.start.incremental":    "Запуск инкрементного сканирования",
+		"scan.start.repair":         "Запуск восстановительного сканирования",
+		"scan.noRoots":              "Нет включённых отслеживаемых папок",
+		"scan.applyingChanges":      "Применение изменений файловой системы: %d",
+		"scan.verifying":            "Нет ожидающих событий, выполняется полная проверка",
+		"scan.countingFiles":        "Подсчёт файлов",
+		"scan.scanningRoot":         "Сканирование %s",
+		"scan.scanningRemote":       "Сканирование удалённой папки %s",
+		"scan.remoteOffline":        "%s недоступна, проиндексированные треки сохранены",
+		"scan.fingerprinting":       "Вычисление аудиоотпечатков",
+		"scan.removingStale":        "Удаление устаревших записей треков",
+		"scan.refreshingCatalog":    "Обновление исполнителей, альбомов и треков альбомов",
+		"scan.noChanges":            "Изменений в библиотеке нет, обновление каталога пропущено",
+		"scan.complete.full":        "Полное сканирование завершено: найдено файлов %d, проиндексировано %d, пропущено %d",
+		"scan.complete.incremental": "Инкрементное сканирование завершено: найдено файлов %d, проиндексировано %d, пропущено %d",
+		"scan.complete.repair":      "Восстановительное сканирование завершено: найдено файлов %d, проиндексировано %d, пропущено %d",
+		"scan.canceled":             "Сканирование отменено, уже проиндексированные файлы сохранены",
+		"announce.nowPlaying":       "Сейчас играет %s, исполнитель %s",
+		"announce.fromAlbum":        "альбом %s",
+		"announce.trackNumber":      "трек %d",
+		"announce.duration":         "%d мин %d с",
+		"announce.paused":           "%s на паузе",
+		"announce.resumed":          "%s продолжается",
+		"announce.stopped":          "Воспроизведение остановлено",
+	},
+}
@@ -0,0 +1,129 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// Localizer formats backend messages and compares strings for one locale.
+type Localizer struct {
+	locale   string
+	messages map[string]string
+	mu       sync.Mutex
+	collator *collate.Collator
+}
+
+// NewLocalizer returns a localizer for the closest supported locale; region
+// variants such as "de-AT" fall back to their base language.
+func NewLocalizer(locale string) *Localizer {
+	code := matchLocale(locale)
+	tag := language.Make(code)
+
+	return &Localizer{
+		locale:   code,
+		messages: catalogs[code],
+		collator: collate.New(tag, collate.IgnoreCase, collate.Numeric),
+	}
+}
+
+func (l *Localizer) Locale() string {
+	return l.locale
+}
+
+// T formats the message stored under key, falling back to English and then
+// to the key itself so a missing translation never blanks the UI.
+func (l *Localizer) T(key string, args ...any) string {
+	message, ok := l.messages[key]
+	if !ok {
+		message, ok = catalogs[DefaultLocale][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+
+	return fmt.Sprintf(message, args...)
+}
+
+// Weekday returns the short label for a day numbered like SQLite's %w, with
+// Sunday as 0.
+func (l *Localizer) Weekday(day int) string {
+	if day < 0 || day > 6 {
+		return ""
+	}
+
+	return l.T(fmt.Sprintf("weekday.%d", day))
+}
+
+// Placeholder translates one of the Unknown* labels and returns any other
+// value unchanged.
+func (l *Localizer) Placeholder(value string) string {
+	key, ok := placeholderKeys[value]
+	if !ok {
+		return value
+	}
+
+	return l.T(key)
+}
+
+// Compare orders strings the way a reader of the locale expects: accents
+// sort next to their base letter, case is ignored and digits compare by
+// numeric value.
+func (l *Localizer) Compare(left string, right string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.collator.CompareString(left, right)
+}
+
+// Catalog returns every message of the locale with English filling gaps.
+func (l *Localizer) Catalog() map[string]string {
+	catalog := make(map[string]string, len(catalogs[DefaultLocale]))
+	for key, message := range catalogs[DefaultLocale] {
+		catalog[key] = message
+	}
+	for key, message := range l.messages {
+		catalog[key] = message
+	}
+
+	return catalog
+}
+
+func SupportedLocales() []LocaleOption {
+	return append([]LocaleOption(nil), supportedLocales...)
+}
+
+func matchLocale(locale string) string {
+	if code, ok := supportedLocale(locale); ok {
+		return code
+	}
+
+	return DefaultLocale
+}
+
+// supportedLocale maps a BCP 47 or POSIX style locale onto a shipped
+// catalog, trying the base language when the region is not covered.
+func supportedLocale(locale string) (string, bool) {
+	normalized := strings.ToLower(strings.TrimSpace(locale))
+	normalized, _, _ = strings.Cut(normalized, ".")
+	normalized = strings.ReplaceAll(normalized, "_", "-")
+	if normalized == "" {
+		return "", false
+	}
+
+	if _, ok := catalogs[normalized]; ok {
+		return normalized, true
+	}
+
+	base, _, _ := strings.Cut(normalized, "-")
+	if _, ok := catalogs[base]; ok {
+		return base, true
+	}
+
+	return "", false
+}
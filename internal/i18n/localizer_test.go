@@ -0,0 +1,77 @@
+package i18n
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestCatalogsCoverEnglishKeys(t *testing.T) {
+	t.Parallel()
+
+	for locale, messages := range catalogs {
+		for key, english := range catalogs[DefaultLocale] {
+			message, ok := messages[key]
+			if !ok {
+				t.Errorf("%s: missing %q", locale, key)
+				continue
+			}
+			if strings.Count(message, "%") != strings.Count(english, "%") {
+				t.Errorf("%s: %q has different format verbs than English", locale, key)
+			}
+		}
+	}
+}
+
+func TestNewLocalizerMatchesRegionalLocales(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"":            "en",
+		"de_AT.UTF-8": "de",
+		"fr-CA":       "fr",
+		"pt-BR":       "en",
+		"RU":          "ru",
+	}
+
+	for input, want := range cases {
+		if got := NewLocalizer(input).Locale(); got != want {
+			t.Errorf("NewLocalizer(%q).Locale() = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestLocalizerTranslatesPlaceholdersAndFallsBack(t *testing.T) {
+	t.Parallel()
+
+	german := NewLocalizer("de")
+	if got := german.Placeholder(UnknownArtist); got != "Unbekannter Künstler" {
+		t.Fatalf("placeholder = %q", got)
+	}
+	if got := german.Placeholder("Portishead"); got != "Portishead" {
+		t.Fatalf("regular name changed to %q", got)
+	}
+	if got := german.T("scan.scanningRoot", "/music"); got != "/music wird gescannt" {
+		t.Fatalf("formatted message = %q", got)
+	}
+	if got := german.T("missing.key"); got != "missing.key" {
+		t.Fatalf("missing key = %q", got)
+	}
+}
+
+func TestLocalizerCompareSortsAccentsWithBaseLetters(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"Zebra", "Österreich", "apple", "Oasis", "track 10", "track 9"}
+	localizer := NewLocalizer("de")
+	sort.Slice(names, func(i, j int) bool {
+		return localizer.Compare(names[i], names[j]) < 0
+	})
+
+	want := []string{"apple", "Oasis", "Österreich", "track 9", "track 10", "Zebra"}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("sorted = %v, want %v", names, want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/rzxx/ben/internal/eventbus"
+	"github.com/rzxx/ben/internal/settings"
+)
+
+const EventLocaleChanged = "locale:changed"
+
+const LocaleSettingKey = "app.locale"
+
+type Emitter func(eventName string, payload any)
+
+// LocaleState is what the frontend needs to render backend values in the
+// selected locale.
+type LocaleState struct {
+	Locale    string            `json:"locale"`
+	Supported []LocaleOption    `json:"supported"`
+	Messages  map[string]string `json:"messages"`
+}
+
+// Service owns the locale setting and hands out the active Localizer.
+type Service struct {
+	mu        sync.Mutex
+	store     *settings.Store
+	current   *Localizer
+	emit      Emitter
+	listeners []func(*Localizer)
+}
+
+func NewService(store *settings.Store, bus *eventbus.Bus) *Service {
+	return &Service{store: store, emit: bus.Publish, current: NewLocalizer(DefaultLocale)}
+}
+
+// OnChange registers a callback that runs with the new localizer whenever
+// the locale changes, and once immediately with the current one.
+func (s *Service) OnChange(listener func(*Localizer)) {
+	s.mu.Lock()
+	s.listeners = append(s.listeners, listener)
+	current := s.current
+	s.mu.Unlock()
+
+	listener(current)
+}
+
+// Load applies the stored locale, or fallbackLocale (typically the system
+// locale) when none was chosen yet.
+func (s *Service) Load(ctx context.Context, fallbackLocale string) {
+	locale := s.store.GetString(ctx, LocaleSettingKey, fallbackLocale)
+	s.apply(NewLocalizer(locale))
+}
+
+func (s *Service) Current() *Localizer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current
+}
+
+// T formats a message in the active locale.
+func (s *Service) T(key string, args ...any) string {
+	return s.Current().T(key, args...)
+}
+
+func (s *Service) State() LocaleState {
+	localizer := s.Current()
+	return LocaleState{
+		Locale:    localizer.Locale(),
+		Supported: SupportedLocales(),
+		Messages:  localizer.Catalog(),
+	}
+}
+
+func (s *Service) SetLocale(ctx context.Context, locale string) (LocaleState, error) {
+	if _, ok := supportedLocale(locale); !ok {
+		return s.State(), fmt.Errorf("unsupported locale %q", locale)
+	}
+
+	localizer := NewLocalizer(locale)
+	if err := s.store.Set(ctx, LocaleSettingKey, localizer.Locale()); err != nil {
+		return s.State(), err
+	}
+
+	s.apply(localizer)
+
+	state := s.State()
+	s.mu.Lock()
+	emitter := s.emit
+	s.mu.Unlock()
+	if emitter != nil {
+		emitter(EventLocaleChanged, state)
+	}
+
+	return state, nil
+}
+
+func (s *Service) apply(localizer *Localizer) {
+	s.mu.Lock()
+	s.current = localizer
+	listeners := append([]func(*Localizer){}, s.listeners...)
+	s.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(localizer)
+	}
+}
+
+// SystemLocale reads the locale from the POSIX environment variables and
+// returns "" when none is set, e.g. on Windows.
+func SystemLocale() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" && value != "C" && value != "POSIX" {
+			return value
+		}
+	}
+
+	return ""
+}
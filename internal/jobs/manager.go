@@ -0,0 +1,259 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rzxx/ben/internal/eventbus"
+)
+
+const EventChanged = "jobs:changed"
+
+const (
+	StateRunning   = "running"
+	StateCompleted = "completed"
+	StateFailed    = "failed"
+	StateCanceled  = "canceled"
+)
+
+// finishedJobLimit is how many finished jobs are kept for the UI to show.
+const finishedJobLimit = 20
+
+var ErrJobNotFound = errors.New("job not found")
+
+type Emitter func(eventName string, payload any)
+
+// Job is one unit of background work as the UI sees it. Progress runs from
+// 0 to 100.
+type Job struct {
+	ID              string `json:"id"`
+	Kind            string `json:"kind"`
+	Label           string `json:"label"`
+	State           string `json:"state"`
+	Progress        int    `json:"progress"`
+	Message         string `json:"message,omitempty"`
+	Error           string `json:"error,omitempty"`
+	Cancelable      bool   `json:"cancelable"`
+	CancelRequested bool   `json:"cancelRequested"`
+	StartedAt       string `json:"startedAt"`
+	FinishedAt      string `json:"finishedAt,omitempty"`
+}
+
+type entry struct {
+	job    Job
+	cancel func()
+}
+
+// Manager tracks running and recently finished jobs and emits the whole list
+// on every change, so subscribers never have to merge partial updates.
+type Manager struct {
+	mu     sync.Mutex
+	nextID int
+	jobs   []*entry
+	emit   Emitter
+	now    func() time.Time
+}
+
+func NewManager(bus *eventbus.Bus) *Manager {
+	return &Manager{now: time.Now, emit: bus.Publish}
+}
+
+// Start registers a running job. cancel is called when the user cancels the
+// job and may be nil for work that cannot be stopped; the job keeps running
+// until the returned task reports how it ended.
+func (m *Manager) Start(kind string, label string, cancel func()) *Task {
+	m.mu.Lock()
+	m.nextID++
+	id := "job-" + strconv.Itoa(m.nextID)
+	m.jobs = append(m.jobs, &entry{
+		job: Job{
+			ID:         id,
+			Kind:       kind,
+			Label:      label,
+			State:      StateRunning,
+			Cancelable: cancel != nil,
+			StartedAt:  m.timestampLocked(),
+		},
+		cancel: cancel,
+	})
+	jobs, emit := m.snapshotLocked()
+	m.mu.Unlock()
+
+	m.notify(emit, jobs)
+	return &Task{manager: m, id: id}
+}
+
+// List returns running jobs and recently finished ones, oldest first.
+func (m *Manager) List() []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jobs, _ := m.snapshotLocked()
+	return jobs
+}
+
+// Cancel asks a running job to stop. The job is marked canceled once its
+// task reports the failure the cancellation caused.
+func (m *Manager) Cancel(id string) error {
+	m.mu.Lock()
+	current := m.findLocked(id)
+	if current == nil {
+		m.mu.Unlock()
+		return ErrJobNotFound
+	}
+	if current.job.State != StateRunning {
+		m.mu.Unlock()
+		return fmt.Errorf("job %s is not running", id)
+	}
+	if current.cancel == nil {
+		m.mu.Unlock()
+		return fmt.Errorf("job %s cannot be canceled", id)
+	}
+	if current.job.CancelRequested {
+		m.mu.Unlock()
+		return nil
+	}
+
+	current.job.CancelRequested = true
+	cancel := current.cancel
+	jobs, emit := m.snapshotLocked()
+	m.mu.Unlock()
+
+	cancel()
+	m.notify(emit, jobs)
+	return nil
+}
+
+func (m *Manager) update(id string, apply func(job *Job)) {
+	m.mu.Lock()
+	current := m.findLocked(id)
+	if current == nil || current.job.State != StateRunning {
+		m.mu.Unlock()
+		return
+	}
+
+	apply(&current.job)
+	if current.job.State != StateRunning {
+		current.job.FinishedAt = m.timestampLocked()
+		current.cancel = nil
+		m.pruneLocked()
+	}
+	jobs, emit := m.snapshotLocked()
+	m.mu.Unlock()
+
+	m.notify(emit, jobs)
+}
+
+func (m *Manager) findLocked(id string) *entry {
+	for _, current := range m.jobs {
+		if current.job.ID == id {
+			return current
+		}
+	}
+
+	return nil
+}
+
+// pruneLocked drops the oldest finished jobs beyond finishedJobLimit.
+func (m *Manager) pruneLocked() {
+	finished := 0
+	for _, current := range m.jobs {
+		if current.job.State != StateRunning {
+			finished++
+		}
+	}
+
+	kept := m.jobs[:0]
+	for _, current := range m.jobs {
+		if current.job.State != StateRunning && finished > finishedJobLimit {
+			finished--
+			continue
+		}
+		kept = append(kept, current)
+	}
+	m.jobs = kept
+}
+
+func (m *Manager) snapshotLocked() ([]Job, Emitter) {
+	jobs := make([]Job, 0, len(m.jobs))
+	for _, current := range m.jobs {
+		jobs = append(jobs, current.job)
+	}
+
+	return jobs, m.emit
+}
+
+func (m *Manager) timestampLocked() string {
+	return m.now().UTC().Format(time.RFC3339)
+}
+
+func (m *Manager) notify(emit Emitter, jobs []Job) {
+	if emit != nil {
+		emit(EventChanged, jobs)
+	}
+}
+
+// Task reports the progress of one job. Calls after the job finished are
+// ignored.
+type Task struct {
+	manager *Manager
+	id      string
+}
+
+func (t *Task) ID() string {
+	return t.id
+}
+
+func (t *Task) Progress(percent int, message string) {
+	t.manager.update(t.id, func(job *Job) {
+		job.Progress = clampPercent(percent)
+		job.Message = message
+	})
+}
+
+func (t *Task) Complete(message string) {
+	t.manager.update(t.id, func(job *Job) {
+		job.State = StateCompleted
+		job.Progress = 100
+		if message != "" {
+			job.Message = message
+		}
+	})
+}
+
+// Fail ends the job with err. A job the user asked to cancel ends as
+// canceled instead, whatever error the interrupted work returned.
+func (t *Task) Fail(err error) {
+	t.manager.update(t.id, func(job *Job) {
+		if job.CancelRequested {
+			job.State = StateCanceled
+			return
+		}
+
+		job.State = StateFailed
+		if err != nil {
+			job.Error = err.Error()
+		}
+	})
+}
+
+// Canceled ends the job as canceled, for work that was stopped some other
+// way than through Manager.Cancel.
+func (t *Task) Canceled() {
+	t.manager.update(t.id, func(job *Job) {
+		job.State = StateCanceled
+	})
+}
+
+func clampPercent(percent int) int {
+	if percent < 0 {
+		return 0
+	}
+	if percent > 100 {
+		return 100
+	}
+
+	return percent
+}
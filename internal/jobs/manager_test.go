@@ -0,0 +1,87 @@
+package jobs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rzxx/ben/internal/eventbus"
+)
+
+func TestManagerTracksJobLifecycle(t *testing.T) {
+	t.Parallel()
+
+	bus := eventbus.New()
+	manager := NewManager(bus)
+	events := 0
+	eventbus.Subscribe(bus, EventChanged, func([]Job) {
+		events++
+	})
+
+	task := manager.Start("scan", "Library scan", nil)
+	task.Progress(140, "Indexing")
+	jobs := manager.List()
+	if len(jobs) != 1 || jobs[0].State != StateRunning || jobs[0].Progress != 100 || jobs[0].Message != "Indexing" {
+		t.Fatalf("unexpected running job %+v", jobs)
+	}
+	if err := manager.Cancel(task.ID()); err == nil {
+		t.Fatalf("expected job without cancel func to refuse cancellation")
+	}
+
+	task.Fail(errors.New("disk gone"))
+	task.Complete("ignored")
+	jobs = manager.List()
+	if jobs[0].State != StateFailed || jobs[0].Error != "disk gone" || jobs[0].FinishedAt == "" {
+		t.Fatalf("unexpected failed job %+v", jobs[0])
+	}
+	if events != 3 {
+		t.Fatalf("expected 3 change events, got %d", events)
+	}
+}
+
+func TestManagerCancelMarksJobCanceled(t *testing.T) {
+	t.Parallel()
+
+	manager := NewManager(nil)
+	canceled := false
+	task := manager.Start("backup", "Backup", func() { canceled = true })
+
+	if err := manager.Cancel(task.ID()); err != nil {
+		t.Fatalf("cancel job: %v", err)
+	}
+	if !canceled || !manager.List()[0].CancelRequested {
+		t.Fatalf("expected cancel func to run and request to be recorded")
+	}
+
+	task.Fail(errors.New("context canceled"))
+	if job := manager.List()[0]; job.State != StateCanceled || job.Error != "" {
+		t.Fatalf("expected canceled job, got %+v", job)
+	}
+
+	other := NewManager(nil)
+	other.Start("scan", "Library scan", nil).Canceled()
+	if job := other.List()[0]; job.State != StateCanceled || job.FinishedAt == "" {
+		t.Fatalf("expected the stopped job to be canceled, got %+v", job)
+	}
+
+	if err := manager.Cancel("job-99"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected unknown job error, got %v", err)
+	}
+}
+
+func TestManagerKeepsRecentFinishedJobs(t *testing.T) {
+	t.Parallel()
+
+	manager := NewManager(nil)
+	running := manager.Start("scan", "Running", nil)
+	for index := 0; index < finishedJobLimit+5; index++ {
+		manager.Start("export", "Export", nil).Complete("")
+	}
+
+	jobs := manager.List()
+	if len(jobs) != finishedJobLimit+1 {
+		t.Fatalf("expected %d jobs, got %d", finishedJobLimit+1, len(jobs))
+	}
+	if jobs[0].ID != running.ID() {
+		t.Fatalf("expected running job to be kept")
+	}
+}
@@ -0,0 +1,170 @@
+package keybindings
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrUnknownCommand = errors.New("unknown command")
+
+var ErrInvalidAccelerator = errors.New("invalid key binding")
+
+// Command is an action the frontend can trigger from the keyboard. IDs are
+// stable because stored bindings refer to them.
+type Command struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Category    string   `json:"category"`
+	DefaultKeys []string `json:"defaultKeys"`
+}
+
+// commands lists every bindable action. "Mod" is Cmd on macOS and Ctrl
+// elsewhere so defaults read naturally on each platform.
+var commands = []Command{
+	{ID: "player.togglePlayback", Title: "Play / pause", Category: "Playback", DefaultKeys: []string{"Space"}},
+	{ID: "player.next", Title: "Next track", Category: "Playback", DefaultKeys: []string{"Mod+ArrowRight"}},
+	{ID: "player.previous", Title: "Previous track", Category: "Playback", DefaultKeys: []string{"Mod+ArrowLeft"}},
+	{ID: "player.seekForward", Title: "Seek forward", Category: "Playback", DefaultKeys: []string{"Shift+ArrowRight"}},
+	{ID: "player.seekBackward", Title: "Seek backward", Category: "Playback", DefaultKeys: []string{"Shift+ArrowLeft"}},
+	{ID: "player.volumeUp", Title: "Volume up", Category: "Playback", DefaultKeys: []string{"Mod+ArrowUp"}},
+	{ID: "player.volumeDown", Title: "Volume down", Category: "Playback", DefaultKeys: []string{"Mod+ArrowDown"}},
+	{ID: "navigation.focusSearch", Title: "Focus search", Category: "Navigation", DefaultKeys: []string{"Mod+F", "/"}},
+	{ID: "navigation.nextView", Title: "Next view", Category: "Navigation", DefaultKeys: []string{"Mod+]"}},
+	{ID: "navigation.previousView", Title: "Previous view", Category: "Navigation", DefaultKeys: []string{"Mod+["}},
+	{ID: "navigation.back", Title: "Go back", Category: "Navigation", DefaultKeys: []string{"Alt+ArrowLeft"}},
+	{ID: "navigation.forward", Title: "Go forward", Category: "Navigation", DefaultKeys: []string{"Alt+ArrowRight"}},
+	{ID: "queue.togglePanel", Title: "Toggle queue panel", Category: "Queue", DefaultKeys: []string{"Mod+J"}},
+	{ID: "edit.undo", Title: "Undo", Category: "Edit", DefaultKeys: []string{"Mod+Z"}},
+	{ID: "edit.redo", Title: "Redo", Category: "Edit", DefaultKeys: []string{"Mod+Shift+Z"}},
+	{ID: "window.toggleMiniPlayer", Title: "Toggle mini player", Category: "Window", DefaultKeys: []string{"Mod+Shift+M"}},
+}
+
+// Commands returns every bindable action in display order.
+func Commands() []Command {
+	return append([]Command(nil), commands...)
+}
+
+func findCommand(id string) (Command, bool) {
+	for _, command := range commands {
+		if command.ID == id {
+			return command, true
+		}
+	}
+
+	return Command{}, false
+}
+
+var modifierOrder = []string{"Mod", "Ctrl", "Alt", "Shift", "Meta"}
+
+var modifierAliases = map[string]string{
+	"mod":       "Mod",
+	"cmdorctrl": "Mod",
+	"ctrl":      "Ctrl",
+	"control":   "Ctrl",
+	"alt":       "Alt",
+	"option":    "Alt",
+	"opt":       "Alt",
+	"shift":     "Shift",
+	"meta":      "Meta",
+	"cmd":       "Meta",
+	"command":   "Meta",
+	"super":     "Meta",
+	"win":       "Meta",
+}
+
+var namedKeys = map[string]string{
+	"space":              "Space",
+	"enter":              "Enter",
+	"return":             "Enter",
+	"escape":             "Escape",
+	"esc":                "Escape",
+	"tab":                "Tab",
+	"backspace":          "Backspace",
+	"delete":             "Delete",
+	"del":                "Delete",
+	"home":               "Home",
+	"end":                "End",
+	"pageup":             "PageUp",
+	"pagedown":           "PageDown",
+	"arrowup":            "ArrowUp",
+	"up":                 "ArrowUp",
+	"arrowdown":          "ArrowDown",
+	"down":               "ArrowDown",
+	"arrowleft":          "ArrowLeft",
+	"left":               "ArrowLeft",
+	"arrowright":         "ArrowRight",
+	"right":              "ArrowRight",
+	"plus":               "Plus",
+	"mediaplaypause":     "MediaPlayPause",
+	"mediatracknext":     "MediaTrackNext",
+	"mediatrackprevious": "MediaTrackPrevious",
+}
+
+// NormalizeAccelerator parses a binding such as "ctrl+shift+k" into its
+// canonical form ("Ctrl+Shift+K") so equal bindings compare equal. Exactly
+// one non-modifier key is required.
+func NormalizeAccelerator(value string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return "", fmt.Errorf("%w: binding is empty", ErrInvalidAccelerator)
+	}
+
+	modifiers := make(map[string]bool)
+	key := ""
+	for _, part := range strings.Split(trimmed, "+") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return "", fmt.Errorf("%w %q: use \"Plus\" for the + key", ErrInvalidAccelerator, value)
+		}
+
+		if modifier, ok := modifierAliases[strings.ToLower(part)]; ok {
+			modifiers[modifier] = true
+			continue
+		}
+
+		normalizedKey, ok := normalizeKey(part)
+		if !ok {
+			return "", fmt.Errorf("%w %q: unknown key %q", ErrInvalidAccelerator, value, part)
+		}
+		if key != "" {
+			return "", fmt.Errorf("%w %q: only one non-modifier key is allowed", ErrInvalidAccelerator, value)
+		}
+		key = normalizedKey
+	}
+
+	if key == "" {
+		return "", fmt.Errorf("%w %q: a non-modifier key is required", ErrInvalidAccelerator, value)
+	}
+
+	parts := make([]string, 0, len(modifiers)+1)
+	for _, modifier := range modifierOrder {
+		if modifiers[modifier] {
+			parts = append(parts, modifier)
+		}
+	}
+	parts = append(parts, key)
+
+	return strings.Join(parts, "+"), nil
+}
+
+func normalizeKey(value string) (string, bool) {
+	if named, ok := namedKeys[strings.ToLower(value)]; ok {
+		return named, true
+	}
+
+	runes := []rune(value)
+	if len(runes) == 1 {
+		return strings.ToUpper(value), true
+	}
+
+	upper := strings.ToUpper(value)
+	if strings.HasPrefix(upper, "F") && !strings.HasPrefix(upper, "F0") {
+		if number, err := strconv.Atoi(upper[1:]); err == nil && number >= 1 && number <= 24 {
+			return upper, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,63 @@
+package keybindings
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeAccelerator(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"space":            "Space",
+		"ctrl+shift+k":     "Ctrl+Shift+K",
+		"Shift + Ctrl + k": "Ctrl+Shift+K",
+		"cmdorctrl+f":      "Mod+F",
+		"option+left":      "Alt+ArrowLeft",
+		"f12":              "F12",
+		"/":                "/",
+		"mod+plus":         "Mod+Plus",
+	}
+
+	for input, want := range cases {
+		got, err := NormalizeAccelerator(input)
+		if err != nil {
+			t.Errorf("NormalizeAccelerator(%q) error: %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("NormalizeAccelerator(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestNormalizeAcceleratorRejectsInvalidBindings(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"", "ctrl+shift", "ctrl+a+b", "ctrl++", "f25", "f0", "hyper+k"} {
+		if _, err := NormalizeAccelerator(input); !errors.Is(err, ErrInvalidAccelerator) {
+			t.Errorf("NormalizeAccelerator(%q) error = %v, want ErrInvalidAccelerator", input, err)
+		}
+	}
+}
+
+func TestDefaultBindingsAreCanonicalAndUnique(t *testing.T) {
+	t.Parallel()
+
+	owners := make(map[string]string)
+	for _, binding := range resolveBindings(nil) {
+		for _, key := range binding.Keys {
+			normalized, err := NormalizeAccelerator(key)
+			if err != nil {
+				t.Fatalf("default %q for %s: %v", key, binding.ID, err)
+			}
+			if normalized != key {
+				t.Errorf("default %q for %s is not canonical, want %q", key, binding.ID, normalized)
+			}
+			if owner, taken := owners[key]; taken {
+				t.Errorf("default %q is bound to both %s and %s", key, owner, binding.ID)
+			}
+			owners[key] = binding.ID
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package keybindings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rzxx/ben/internal/eventbus"
+	"github.com/rzxx/ben/internal/settings"
+)
+
+const EventChanged = "keybindings:changed"
+
+// OverridesSettingKey stores only the bindings the user changed, keyed by
+// command ID, so new defaults reach users who never customized them.
+const OverridesSettingKey = "keybindings.overrides"
+
+var ErrBindingConflict = errors.New("key binding is already in use")
+
+type Emitter func(eventName string, payload any)
+
+// Binding is a command together with the keys currently bound to it.
+type Binding struct {
+	Command
+	Keys       []string `json:"keys"`
+	Customized bool     `json:"customized"`
+}
+
+type Service struct {
+	mu    sync.Mutex
+	store *settings.Store
+	emit  Emitter
+}
+
+func NewService(store *settings.Store, bus *eventbus.Bus) *Service {
+	return &Service{store: store, emit: bus.Publish}
+}
+
+func (s *Service) List(ctx context.Context) ([]Binding, error) {
+	overrides, err := s.loadOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveBindings(overrides), nil
+}
+
+// SetBinding replaces the keys bound to a command. An empty list unbinds it.
+// A key already bound to another command is rejected so one shortcut never
+// triggers two actions.
+func (s *Service) SetBinding(ctx context.Context, commandID string, keys []string) ([]Binding, error) {
+	if _, ok := findCommand(commandID); !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownCommand, commandID)
+	}
+
+	normalized := make([]string, 0, len(keys))
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		accelerator, err := NormalizeAccelerator(key)
+		if err != nil {
+			return nil, err
+		}
+		if _, duplicate := seen[accelerator]; duplicate {
+			continue
+		}
+		seen[accelerator] = struct{}{}
+		normalized = append(normalized, accelerator)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	overrides, err := s.loadOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, binding := range resolveBindings(overrides) {
+		if binding.ID == commandID {
+			continue
+		}
+		for _, key := range binding.Keys {
+			if _, clash := seen[key]; clash {
+				return nil, fmt.Errorf("%w: %s is bound to %q", ErrBindingConflict, key, binding.Title)
+			}
+		}
+	}
+
+	overrides[commandID] = normalized
+	return s.saveOverridesLocked(ctx, overrides)
+}
+
+// ResetBinding restores a command's default keys.
+func (s *Service) ResetBinding(ctx context.Context, commandID string) ([]Binding, error) {
+	if _, ok := findCommand(commandID); !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownCommand, commandID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	overrides, err := s.loadOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(overrides, commandID)
+	return s.saveOverridesLocked(ctx, overrides)
+}
+
+func (s *Service) ResetAll(ctx context.Context) ([]Binding, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.saveOverridesLocked(ctx, map[string][]string{})
+}
+
+func (s *Service) loadOverrides(ctx context.Context) (map[string][]string, error) {
+	overrides := make(map[string][]string)
+
+	raw, ok, err := s.store.Get(ctx, OverridesSettingKey)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || raw == "" {
+		return overrides, nil
+	}
+
+	if err := json.Unmarshal([]byte(raw), &overrides); err != nil {
+		return nil, fmt.Errorf("decode key bindings: %w", err)
+	}
+
+	return overrides, nil
+}
+
+func (s *Service) saveOverridesLocked(ctx context.Context, overrides map[string][]string) ([]Binding, error) {
+	if len(overrides) == 0 {
+		if err := s.store.Delete(ctx, OverridesSettingKey); err != nil {
+			return nil, err
+		}
+	} else {
+		encoded, err := json.Marshal(overrides)
+		if err != nil {
+			return nil, fmt.Errorf("encode key bindings: %w", err)
+		}
+		if err := s.store.Set(ctx, OverridesSettingKey, string(encoded)); err != nil {
+			return nil, err
+		}
+	}
+
+	bindings := resolveBindings(overrides)
+	if s.emit != nil {
+		s.emit(EventChanged, bindings)
+	}
+
+	return bindings, nil
+}
+
+// resolveBindings applies stored overrides on top of the defaults. Overrides
+// for commands that no longer exist are ignored.
+func resolveBindings(overrides map[string][]string) []Binding {
+	bindings := make([]Binding, 0, len(commands))
+	for _, command := range commands {
+		binding := Binding{Command: command, Keys: append([]string(nil), command.DefaultKeys...)}
+		if keys, ok := overrides[command.ID]; ok {
+			binding.Keys = append([]string{}, keys...)
+			binding.Customized = true
+		}
+		bindings = append(bindings, binding)
+	}
+
+	return bindings
+}
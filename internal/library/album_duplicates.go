@@ -0,0 +1,357 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/rzxx/ben/internal/coverart"
+)
+
+const (
+	// DuplicateMatchCover marks albums whose covers look alike, by their
+	// perceptual hashes.
+	DuplicateMatchCover = "cover"
+	// DuplicateMatchTitle marks albums with the same title once edition
+	// and disc suffixes are stripped.
+	DuplicateMatchTitle = "title"
+	// DuplicateMatchTracklist marks albums sharing most of their track
+	// titles.
+	DuplicateMatchTracklist = "tracklist"
+)
+
+const (
+	defaultAlbumDuplicateConfidence = 0.7
+	// maxCoverDistance is the largest number of differing perceptual hash
+	// bits at which two covers still count as the same artwork.
+	maxCoverDistance = 12
+	// Weights of the signals in an album pair's confidence.
+	coverWeight     = 0.4
+	titleWeight     = 0.25
+	artistWeight    = 0.15
+	tracklistWeight = 0.2
+	// coverlessFactor scales the confidence of pairs matched by their
+	// metadata alone, when either album has no hashed cover.
+	coverlessFactor = 0.8
+)
+
+// albumEditionSuffix matches the bracketed edition, remaster and disc notes
+// rips of the same album differ by, e.g. "(Deluxe Edition)" or "[Disc 1]".
+var albumEditionSuffix = regexp.MustCompile(`(?i)\s*[\(\[\{][^\)\]\}]*(edition|remaster|version|deluxe|expanded|anniversary|bonus|disc|cd|mono|stereo|explicit|clean|flac|mp3|web|vinyl)[^\)\]\}]*[\)\]\}]`)
+
+// albumDiscSuffix matches a trailing disc number outside brackets, e.g.
+// "Album - Disc 2" or "Album CD1".
+var albumDiscSuffix = regexp.MustCompile(`(?i)[\s\-:]*(disc|disk|cd)\s*\d+\s*$`)
+
+// DuplicateAlbum is one side of a duplicate album candidate. Album ids
+// change on every scan, so merging goes through TrackIDs.
+type DuplicateAlbum struct {
+	AlbumID     int64   `json:"albumId"`
+	Title       string  `json:"title"`
+	AlbumArtist string  `json:"albumArtist"`
+	Year        *int    `json:"year,omitempty"`
+	TrackCount  int     `json:"trackCount"`
+	CoverPath   *string `json:"coverPath,omitempty"`
+	TrackIDs    []int64 `json:"trackIds"`
+}
+
+// DuplicateAlbumCandidate is a pair of albums that are likely the same
+// album ripped twice. Confidence runs from 0 to 1; CoverDistance is the
+// number of differing cover hash bits, when both albums have a hashed
+// cover.
+type DuplicateAlbumCandidate struct {
+	Confidence       float64          `json:"confidence"`
+	MatchedBy        []string         `json:"matchedBy"`
+	CoverDistance    *int             `json:"coverDistance,omitempty"`
+	TitleSimilarity  float64          `json:"titleSimilarity"`
+	ArtistSimilarity float64          `json:"artistSimilarity"`
+	TrackOverlap     float64          `json:"trackOverlap"`
+	Albums           []DuplicateAlbum `json:"albums"`
+}
+
+// DuplicateAlbumReport lists the candidates at or above MinConfidence,
+// most confident first.
+type DuplicateAlbumReport struct {
+	MinConfidence float64                   `json:"minConfidence"`
+	Candidates    []DuplicateAlbumCandidate `json:"candidates"`
+}
+
+type albumDuplicateCandidate struct {
+	album       DuplicateAlbum
+	coverHash   string
+	cover       coverart.PerceptualHash
+	hasCover    bool
+	titleKey    string
+	artistKey   string
+	trackTitles map[string]struct{}
+}
+
+// FindDuplicateAlbums pairs albums whose covers look alike or whose titles
+// match once edition and disc notes are stripped, and scores each pair by
+// cover distance and title, artist and track list similarity. Pairs below
+// minConfidence, 0.7 when not positive, are left out.
+func (f *DuplicateFinder) FindDuplicateAlbums(ctx context.Context, minConfidence float64) (DuplicateAlbumReport, error) {
+	if minConfidence <= 0 {
+		minConfidence = defaultAlbumDuplicateConfidence
+	}
+	minConfidence = min(minConfidence, 1)
+
+	rows, err := f.db.QueryContext(ctx, `
+		SELECT
+			a.id,
+			COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album'),
+			COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist'),
+			a.year,
+			cover.cache_path,
+			COALESCE(cover.phash, ''),
+			t.id,
+			COALESCE(TRIM(t.title), '')
+		FROM albums a
+		JOIN album_tracks at ON at.album_id = a.id
+		JOIN tracks t ON t.id = at.track_id
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN covers cover ON cover.id = a.cover_id
+		WHERE f.file_exists = 1
+		  AND f.is_audiobook = 0
+		  AND NOT EXISTS (SELECT 1 FROM hidden_duplicates hd WHERE hd.track_id = t.id)
+		ORDER BY a.id, t.id
+	`)
+	if err != nil {
+		return DuplicateAlbumReport{}, fmt.Errorf("list duplicate album candidates: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := make([]albumDuplicateCandidate, 0)
+	for rows.Next() {
+		var (
+			albumID    int64
+			title      string
+			artist     string
+			year       sql.NullInt64
+			coverPath  sql.NullString
+			coverHash  string
+			trackID    int64
+			trackTitle string
+		)
+		if scanErr := rows.Scan(&albumID, &title, &artist, &year, &coverPath, &coverHash, &trackID, &trackTitle); scanErr != nil {
+			return DuplicateAlbumReport{}, fmt.Errorf("scan duplicate album candidate: %w", scanErr)
+		}
+
+		if len(candidates) == 0 || candidates[len(candidates)-1].album.AlbumID != albumID {
+			candidates = append(candidates, albumDuplicateCandidate{
+				album: DuplicateAlbum{
+					AlbumID:     albumID,
+					Title:       title,
+					AlbumArtist: artist,
+					Year:        intPointer(year),
+					CoverPath:   stringPointer(coverPath),
+					TrackIDs:    []int64{},
+				},
+				coverHash:   coverHash,
+				titleKey:    albumTitleKey(title),
+				artistKey:   similarityKey(artist),
+				trackTitles: make(map[string]struct{}),
+			})
+		}
+		candidate := &candidates[len(candidates)-1]
+		candidate.album.TrackIDs = append(candidate.album.TrackIDs, trackID)
+		candidate.album.TrackCount++
+		if key := similarityKey(trackTitle); key != "" {
+			candidate.trackTitles[key] = struct{}{}
+		}
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return DuplicateAlbumReport{}, fmt.Errorf("iterate duplicate album candidates: %w", rowsErr)
+	}
+
+	return DuplicateAlbumReport{
+		MinConfidence: minConfidence,
+		Candidates:    matchDuplicateAlbums(candidates, minConfidence),
+	}, nil
+}
+
+// matchDuplicateAlbums scores the pairs of candidates whose covers are
+// within maxCoverDistance or whose title keys are equal; comparing every
+// pair by its metadata would not scale to large libraries.
+func matchDuplicateAlbums(candidates []albumDuplicateCandidate, minConfidence float64) []DuplicateAlbumCandidate {
+	type pair struct{ left, right int }
+	pairs := make(map[pair]struct{})
+
+	hashed := make([]int, 0, len(candidates))
+	byTitle := make(map[string][]int)
+	for index := range candidates {
+		candidate := &candidates[index]
+		candidate.cover, candidate.hasCover = coverart.ParsePerceptualHash(candidate.coverHash)
+		if candidate.hasCover {
+			hashed = append(hashed, index)
+		}
+		if candidate.titleKey != "" {
+			byTitle[candidate.titleKey] = append(byTitle[candidate.titleKey], index)
+		}
+	}
+	for position, left := range hashed {
+		for _, right := range hashed[position+1:] {
+			if candidates[left].cover.Distance(candidates[right].cover) <= maxCoverDistance {
+				pairs[pair{left, right}] = struct{}{}
+			}
+		}
+	}
+	for _, indexes := range byTitle {
+		for position, left := range indexes {
+			for _, right := range indexes[position+1:] {
+				pairs[pair{left, right}] = struct{}{}
+			}
+		}
+	}
+
+	matches := make([]DuplicateAlbumCandidate, 0)
+	for key := range pairs {
+		match := scoreDuplicateAlbums(candidates[key.left], candidates[key.right])
+		if match.Confidence >= minConfidence {
+			matches = append(matches, match)
+		}
+	}
+
+	sort.Slice(matches, func(i int, j int) bool {
+		if matches[i].Confidence != matches[j].Confidence {
+			return matches[i].Confidence > matches[j].Confidence
+		}
+		left, right := matches[i].Albums[0], matches[j].Albums[0]
+		if !strings.EqualFold(left.AlbumArtist, right.AlbumArtist) {
+			return strings.ToLower(left.AlbumArtist) < strings.ToLower(right.AlbumArtist)
+		}
+		if !strings.EqualFold(left.Title, right.Title) {
+			return strings.ToLower(left.Title) < strings.ToLower(right.Title)
+		}
+		return left.AlbumID < right.AlbumID
+	})
+
+	return matches
+}
+
+func scoreDuplicateAlbums(left albumDuplicateCandidate, right albumDuplicateCandidate) DuplicateAlbumCandidate {
+	match := DuplicateAlbumCandidate{
+		MatchedBy:        []string{},
+		TitleSimilarity:  stringSimilarity(left.titleKey, right.titleKey),
+		ArtistSimilarity: stringSimilarity(left.artistKey, right.artistKey),
+		TrackOverlap:     setOverlap(left.trackTitles, right.trackTitles),
+		Albums:           []DuplicateAlbum{left.album, right.album},
+	}
+
+	metadata := titleWeight*match.TitleSimilarity + artistWeight*match.ArtistSimilarity + tracklistWeight*match.TrackOverlap
+	if left.hasCover && right.hasCover {
+		distance := left.cover.Distance(right.cover)
+		match.CoverDistance = &distance
+		coverSimilarity := max(0, 1-float64(distance)/float64(2*maxCoverDistance))
+		if distance <= maxCoverDistance {
+			match.MatchedBy = append(match.MatchedBy, DuplicateMatchCover)
+		}
+		match.Confidence = coverWeight*coverSimilarity + metadata
+	} else {
+		match.Confidence = coverlessFactor * metadata / (titleWeight + artistWeight + tracklistWeight)
+	}
+	match.Confidence = math.Round(match.Confidence*100) / 100
+
+	if left.titleKey != "" && left.titleKey == right.titleKey {
+		match.MatchedBy = append(match.MatchedBy, DuplicateMatchTitle)
+	}
+	if match.TrackOverlap >= 0.5 {
+		match.MatchedBy = append(match.MatchedBy, DuplicateMatchTracklist)
+	}
+
+	return match
+}
+
+// albumTitleKey strips the edition and disc notes of an album title before
+// folding it like similarityKey.
+func albumTitleKey(title string) string {
+	if strings.EqualFold(title, "Unknown Album") {
+		return ""
+	}
+	stripped := albumEditionSuffix.ReplaceAllString(title, "")
+	stripped = albumDiscSuffix.ReplaceAllString(stripped, "")
+	if key := similarityKey(stripped); key != "" {
+		return key
+	}
+	return similarityKey(title)
+}
+
+// similarityKey lowercases value and keeps only its letters and digits,
+// single-spaced, so punctuation and spacing differences do not count.
+// Apostrophes are dropped without splitting the word.
+func similarityKey(value string) string {
+	var builder strings.Builder
+	space := false
+	for _, r := range strings.ToLower(value) {
+		if r == '\'' || r == '’' {
+			continue
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && builder.Len() > 0 {
+				builder.WriteByte(' ')
+			}
+			builder.WriteRune(r)
+			space = false
+			continue
+		}
+		space = true
+	}
+	return builder.String()
+}
+
+// stringSimilarity is 1 minus the edit distance of left and right relative
+// to the longer one.
+func stringSimilarity(left string, right string) float64 {
+	if left == right {
+		return 1
+	}
+	leftRunes, rightRunes := []rune(left), []rune(right)
+	longest := max(len(leftRunes), len(rightRunes))
+	if longest == 0 {
+		return 1
+	}
+
+	previous := make([]int, len(rightRunes)+1)
+	current := make([]int, len(rightRunes)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(leftRunes); i++ {
+		current[0] = i
+		for j := 1; j <= len(rightRunes); j++ {
+			cost := 1
+			if leftRunes[i-1] == rightRunes[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return 1 - float64(previous[len(rightRunes)])/float64(longest)
+}
+
+// setOverlap is the share of the smaller set found in the other, so a
+// deluxe edition with bonus tracks still overlaps its plain rip fully.
+func setOverlap(left map[string]struct{}, right map[string]struct{}) float64 {
+	smaller, larger := left, right
+	if len(smaller) > len(larger) {
+		smaller, larger = larger, smaller
+	}
+	if len(smaller) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for key := range smaller {
+		if _, ok := larger[key]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(smaller))
+}
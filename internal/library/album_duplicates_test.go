@@ -0,0 +1,95 @@
+package library
+
+import (
+	"slices"
+	"testing"
+)
+
+func albumCandidate(albumID int64, title string, artist string, coverHash string, tracks ...string) albumDuplicateCandidate {
+	candidate := albumDuplicateCandidate{
+		album:       DuplicateAlbum{AlbumID: albumID, Title: title, AlbumArtist: artist, TrackCount: len(tracks)},
+		coverHash:   coverHash,
+		titleKey:    albumTitleKey(title),
+		artistKey:   similarityKey(artist),
+		trackTitles: make(map[string]struct{}),
+	}
+	for _, track := range tracks {
+		candidate.trackTitles[similarityKey(track)] = struct{}{}
+	}
+	return candidate
+}
+
+func TestMatchDuplicateAlbumsScoresCoverAndMetadata(t *testing.T) {
+	t.Parallel()
+
+	candidates := []albumDuplicateCandidate{
+		albumCandidate(1, "Blue Train", "John Coltrane", "f0f0f0f0f0f0f0f0", "Blue Train", "Moment's Notice", "Locomotion"),
+		// A second rip: re-encoded cover, edition suffix, an extra track.
+		albumCandidate(2, "Blue Train (Deluxe Edition)", "John Coltrane", "f0f0f0f0f0f0f0f3", "Blue Train", "Moments Notice", "Locomotion", "Lazy Bird"),
+		// Same cover template, different album.
+		albumCandidate(3, "Giant Steps", "John Coltrane", "f0f0f0f0f0f0f0f1", "Giant Steps", "Naima"),
+		// No cover, same title and tracks as 1.
+		albumCandidate(4, "Blue Train - Disc 1", "John Coltrane", "", "Blue Train", "Moment's Notice", "Locomotion"),
+		albumCandidate(5, "Kind of Blue", "Miles Davis", "0f0f0f0f0f0f0f0f", "So What"),
+	}
+
+	matches := matchDuplicateAlbums(candidates, defaultAlbumDuplicateConfidence)
+	if len(matches) != 3 {
+		t.Fatalf("expected the three Blue Train pairs, got %+v", matches)
+	}
+
+	best := matches[0]
+	if ids := []int64{best.Albums[0].AlbumID, best.Albums[1].AlbumID}; !slices.Equal(ids, []int64{1, 2}) {
+		t.Fatalf("expected the two rips with covers to be the best match, got %v", ids)
+	}
+	if best.CoverDistance == nil || *best.CoverDistance != 2 {
+		t.Fatalf("expected a cover distance of 2, got %v", best.CoverDistance)
+	}
+	if !slices.Equal(best.MatchedBy, []string{DuplicateMatchCover, DuplicateMatchTitle, DuplicateMatchTracklist}) {
+		t.Fatalf("unexpected signals %v", best.MatchedBy)
+	}
+	if best.Confidence < 0.9 || best.TrackOverlap != 1 {
+		t.Fatalf("expected a confident match with full overlap, got %+v", best)
+	}
+
+	for _, match := range matches {
+		for _, album := range match.Albums {
+			if album.AlbumID == 3 || album.AlbumID == 5 {
+				t.Fatalf("expected album %d not to match, got %+v", album.AlbumID, match)
+			}
+		}
+		if match.Albums[0].AlbumID == 4 || match.Albums[1].AlbumID == 4 {
+			if match.CoverDistance != nil || match.Confidence > coverlessFactor {
+				t.Fatalf("expected a coverless match to be capped, got %+v", match)
+			}
+		}
+	}
+}
+
+func TestAlbumTitleKeyStripsEditionNotes(t *testing.T) {
+	t.Parallel()
+
+	for title, expected := range map[string]string{
+		"Abbey Road (2019 Remaster)": "abbey road",
+		"Abbey Road [Disc 2]":        "abbey road",
+		"Abbey Road CD1":             "abbey road",
+		"Abbey Road!":                "abbey road",
+		"(What's the Story) Morning": "whats the story morning",
+		"Unknown Album":              "",
+	} {
+		if key := albumTitleKey(title); key != expected {
+			t.Fatalf("expected %q for %q, got %q", expected, title, key)
+		}
+	}
+}
+
+func TestStringSimilarity(t *testing.T) {
+	t.Parallel()
+
+	if similarity := stringSimilarity("kitten", "sitting"); similarity < 0.57 || similarity > 0.58 {
+		t.Fatalf("expected 4/7, got %f", similarity)
+	}
+	if stringSimilarity("", "") != 1 || stringSimilarity("abc", "") != 0 {
+		t.Fatal("unexpected similarity of empty strings")
+	}
+}
@@ -0,0 +1,230 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IncompleteAlbum is an album with fewer tracks on disk than its release
+// track list names.
+type IncompleteAlbum struct {
+	Title              string         `json:"title"`
+	AlbumArtist        string         `json:"albumArtist"`
+	MusicBrainzAlbumID *string        `json:"musicBrainzAlbumId,omitempty"`
+	ExpectedTracks     int            `json:"expectedTracks"`
+	PresentTracks      int            `json:"presentTracks"`
+	Discs              []AlbumDiscGap `json:"discs"`
+}
+
+// AlbumDiscGap lists the absent track numbers of one disc.
+type AlbumDiscGap struct {
+	DiscNo          int   `json:"discNo"`
+	ExpectedTracks  int   `json:"expectedTracks"`
+	MissingTrackNos []int `json:"missingTrackNos"`
+}
+
+type albumGapTrack struct {
+	discNo     int
+	trackNo    int
+	trackTotal int
+}
+
+// GetIncompleteAlbums compares each album against its release track list.
+// The track counts of the MusicBrainz release, looked up by the release id
+// in its tags or chosen when enriching, are used when they were fetched.
+// Otherwise the per-disc track total in the tags is (TRACKTOTAL,
+// TOTALTRACKS or the "n/total" form of TRACKNUMBER); albums without either
+// are skipped since their gaps cannot be told apart from a short release.
+func (r *BrowseRepository) GetIncompleteAlbums(ctx context.Context) ([]IncompleteAlbum, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album') AS album_title,
+			COALESCE(NULLIF(TRIM(t.album_artist), ''), COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) AS album_artist_name,
+			COALESCE(t.disc_no, 1),
+			t.track_no,
+			CASE WHEN json_valid(t.tags_json) THEN json_extract(t.tags_json, '$.taglib_tags.TRACKTOTAL[0]') END,
+			CASE WHEN json_valid(t.tags_json) THEN json_extract(t.tags_json, '$.taglib_tags.TOTALTRACKS[0]') END,
+			CASE WHEN json_valid(t.tags_json) THEN json_extract(t.tags_json, '$.taglib_tags.TRACKNUMBER[0]') END,
+			COALESCE(
+				CASE WHEN json_valid(t.tags_json) THEN NULLIF(TRIM(json_extract(t.tags_json, '$.taglib_tags.MUSICBRAINZ_ALBUMID[0]')), '') END,
+				NULLIF(TRIM(e.release_id), '')
+			)
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN track_enrichments e ON e.track_id = t.id
+		WHERE f.file_exists = 1
+		  AND f.is_audiobook = 0
+		  AND t.track_no IS NOT NULL
+		ORDER BY album_artist_name COLLATE LOCALE, album_title COLLATE LOCALE
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query album track numbers: %w", err)
+	}
+	defer rows.Close()
+
+	type albumGroup struct {
+		album  IncompleteAlbum
+		tracks []albumGapTrack
+	}
+
+	groups := make([]*albumGroup, 0)
+	groupsByKey := make(map[string]*albumGroup)
+	for rows.Next() {
+		var title string
+		var albumArtist string
+		var discNo int
+		var trackNo int
+		var trackTotal sql.NullString
+		var totalTracks sql.NullString
+		var trackNumber sql.NullString
+		var releaseID sql.NullString
+		if scanErr := rows.Scan(&title, &albumArtist, &discNo, &trackNo, &trackTotal, &totalTracks, &trackNumber, &releaseID); scanErr != nil {
+			return nil, fmt.Errorf("scan album track number row: %w", scanErr)
+		}
+
+		key := strings.ToLower(title) + "\x00" + strings.ToLower(albumArtist)
+		group, ok := groupsByKey[key]
+		if !ok {
+			group = &albumGroup{album: IncompleteAlbum{Title: title, AlbumArtist: albumArtist}}
+			groupsByKey[key] = group
+			groups = append(groups, group)
+		}
+		if group.album.MusicBrainzAlbumID == nil {
+			group.album.MusicBrainzAlbumID = stringPointer(releaseID)
+		}
+
+		group.tracks = append(group.tracks, albumGapTrack{
+			discNo:     max(discNo, 1),
+			trackNo:    trackNo,
+			trackTotal: parseTrackTotal(trackTotal.String, totalTracks.String, trackNumber.String),
+		})
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate album track number rows: %w", rowsErr)
+	}
+
+	releaseTotals, err := r.releaseTrackCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	albums := make([]IncompleteAlbum, 0)
+	for _, group := range groups {
+		album := group.album
+		var totals map[int]int
+		if album.MusicBrainzAlbumID != nil {
+			totals = releaseTotals[strings.ToLower(*album.MusicBrainzAlbumID)]
+		}
+		album.Discs, album.ExpectedTracks, album.PresentTracks = findAlbumGaps(group.tracks, totals)
+		if len(album.Discs) == 0 {
+			continue
+		}
+		albums = append(albums, album)
+	}
+
+	return albums, nil
+}
+
+// releaseTrackCounts returns the cached track count of each disc by
+// release id.
+func (r *BrowseRepository) releaseTrackCounts(ctx context.Context) (map[string]map[int]int, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT release_id, disc_no, track_count FROM release_track_lists")
+	if err != nil {
+		return nil, fmt.Errorf("query release track lists: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]map[int]int)
+	for rows.Next() {
+		var releaseID string
+		var discNo int
+		var trackCount int
+		if scanErr := rows.Scan(&releaseID, &discNo, &trackCount); scanErr != nil {
+			return nil, fmt.Errorf("scan release track list row: %w", scanErr)
+		}
+		if counts[releaseID] == nil {
+			counts[releaseID] = make(map[int]int)
+		}
+		counts[releaseID][discNo] = trackCount
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate release track list rows: %w", rowsErr)
+	}
+
+	return counts, nil
+}
+
+// findAlbumGaps returns the discs with missing track numbers, plus the
+// expected and present track counts over the discs that declare a total.
+// The release's own counts replace the totals in tags when there are any,
+// so a disc with no track on disk is listed as well.
+func findAlbumGaps(tracks []albumGapTrack, releaseTotals map[int]int) ([]AlbumDiscGap, int, int) {
+	totals := make(map[int]int)
+	present := make(map[int]map[int]struct{})
+	for _, track := range tracks {
+		if track.trackNo <= 0 {
+			continue
+		}
+		if present[track.discNo] == nil {
+			present[track.discNo] = make(map[int]struct{})
+		}
+		present[track.discNo][track.trackNo] = struct{}{}
+		totals[track.discNo] = max(totals[track.discNo], track.trackTotal)
+	}
+	if len(releaseTotals) > 0 {
+		totals = releaseTotals
+	}
+
+	discNos := make([]int, 0, len(totals))
+	for discNo, total := range totals {
+		if total > 0 {
+			discNos = append(discNos, discNo)
+		}
+	}
+	sort.Ints(discNos)
+
+	gaps := make([]AlbumDiscGap, 0)
+	expectedTracks := 0
+	presentTracks := 0
+	for _, discNo := range discNos {
+		total := totals[discNo]
+		expectedTracks += total
+
+		missing := make([]int, 0)
+		for trackNo := 1; trackNo <= total; trackNo++ {
+			if _, ok := present[discNo][trackNo]; ok {
+				presentTracks++
+				continue
+			}
+			missing = append(missing, trackNo)
+		}
+
+		if len(missing) > 0 {
+			gaps = append(gaps, AlbumDiscGap{DiscNo: discNo, ExpectedTracks: total, MissingTrackNos: missing})
+		}
+	}
+
+	return gaps, expectedTracks, presentTracks
+}
+
+func parseTrackTotal(trackTotal string, totalTracks string, trackNumber string) int {
+	for _, value := range []string{trackTotal, totalTracks} {
+		if parsed, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+
+	if _, total, ok := strings.Cut(trackNumber, "/"); ok {
+		if parsed, err := strconv.Atoi(strings.TrimSpace(total)); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,64 @@
+package library
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindAlbumGaps_ListsMissingTrackNumbersPerDisc(t *testing.T) {
+	t.Parallel()
+
+	tracks := []albumGapTrack{
+		{discNo: 1, trackNo: 1, trackTotal: 4},
+		{discNo: 1, trackNo: 3, trackTotal: 4},
+		{discNo: 2, trackNo: 1, trackTotal: 2},
+		{discNo: 2, trackNo: 2, trackTotal: 2},
+		{discNo: 3, trackNo: 5},
+	}
+
+	gaps, expected, present := findAlbumGaps(tracks, nil)
+
+	want := []AlbumDiscGap{{DiscNo: 1, ExpectedTracks: 4, MissingTrackNos: []int{2, 4}}}
+	if !reflect.DeepEqual(gaps, want) {
+		t.Fatalf("unexpected gaps: got %+v, want %+v", gaps, want)
+	}
+	if expected != 6 || present != 4 {
+		t.Fatalf("unexpected counts: expected=%d present=%d", expected, present)
+	}
+}
+
+func TestFindAlbumGaps_PrefersTheReleaseTrackCounts(t *testing.T) {
+	t.Parallel()
+
+	tracks := []albumGapTrack{
+		{discNo: 1, trackNo: 1, trackTotal: 2},
+		{discNo: 1, trackNo: 2, trackTotal: 2},
+	}
+
+	gaps, expected, present := findAlbumGaps(tracks, map[int]int{1: 3, 2: 2})
+
+	want := []AlbumDiscGap{
+		{DiscNo: 1, ExpectedTracks: 3, MissingTrackNos: []int{3}},
+		{DiscNo: 2, ExpectedTracks: 2, MissingTrackNos: []int{1, 2}},
+	}
+	if !reflect.DeepEqual(gaps, want) {
+		t.Fatalf("unexpected gaps: got %+v, want %+v", gaps, want)
+	}
+	if expected != 5 || present != 2 {
+		t.Fatalf("unexpected counts: expected=%d present=%d", expected, present)
+	}
+}
+
+func TestParseTrackTotal_PrefersExplicitTotal(t *testing.T) {
+	t.Parallel()
+
+	if got := parseTrackTotal("12", "", "3/10"); got != 12 {
+		t.Fatalf("expected 12, got %d", got)
+	}
+	if got := parseTrackTotal("", "", "3/10"); got != 10 {
+		t.Fatalf("expected 10, got %d", got)
+	}
+	if got := parseTrackTotal("", "", "3"); got != 0 {
+		t.Fatalf("expected 0, got %d", got)
+	}
+}
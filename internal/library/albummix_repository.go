@@ -0,0 +1,194 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// continuousMixTrackCondition flags tracks that belong to a DJ or continuous
+// mix by their tags and album naming conventions. GetAlbumMix also detects
+// albums whose analyzed tracks run into each other without silence, and the
+// per-album override corrects the cases both get wrong.
+const continuousMixTrackCondition = `(
+	(json_valid(t.tags_json) AND json_extract(t.tags_json, '$.taglib_tags.DJMIXER') IS NOT NULL)
+	OR LOWER(TRIM(COALESCE(t.genre, ''))) IN ('dj mix', 'dj-mix', 'continuous mix', 'mixed')
+	OR LOWER(COALESCE(t.album, '')) LIKE '%dj mix%'
+	OR LOWER(COALESCE(t.album, '')) LIKE '%dj-mix%'
+	OR LOWER(COALESCE(t.album, '')) LIKE '%continuous mix%'
+	OR LOWER(COALESCE(t.album, '')) LIKE '%(mixed)%'
+	OR LOWER(COALESCE(t.album, '')) LIKE '%mixed by %'
+)`
+
+// AlbumMix describes whether an album plays as one continuous mix. Continuous
+// mixes are played gapless with crossfade disabled.
+type AlbumMix struct {
+	Title         string `json:"title"`
+	AlbumArtist   string `json:"albumArtist"`
+	Detected      bool   `json:"detected"`
+	Override      *bool  `json:"override,omitempty"`
+	ContinuousMix bool   `json:"continuousMix"`
+}
+
+type AlbumMixRepository struct {
+	db *sql.DB
+}
+
+func NewAlbumMixRepository(database *sql.DB) *AlbumMixRepository {
+	return &AlbumMixRepository{db: database}
+}
+
+func (r *AlbumMixRepository) GetAlbumMix(ctx context.Context, title string, albumArtist string) (AlbumMix, error) {
+	albumTitle := strings.TrimSpace(title)
+	artistName := strings.TrimSpace(albumArtist)
+	if albumTitle == "" {
+		return AlbumMix{}, errors.New("album title is required")
+	}
+	if artistName == "" {
+		return AlbumMix{}, errors.New("album artist is required")
+	}
+
+	var trackCount, analyzedCount int
+	var mixTrackCount, seamlessLeads, seamlessTails sql.NullInt64
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(1),
+			SUM(CASE WHEN `+continuousMixTrackCondition+` THEN 1 ELSE 0 END),
+			COUNT(es.track_id),
+			SUM(CASE WHEN es.lead_ms <= ? THEN 1 ELSE 0 END),
+			SUM(CASE WHEN es.tail_ms <= ? THEN 1 ELSE 0 END)
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN track_edge_silence es ON es.track_id = t.id AND es.failed = 0
+		WHERE f.file_exists = 1
+		  AND LOWER(COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album')) = LOWER(?)
+		  AND LOWER(COALESCE(NULLIF(TRIM(t.album_artist), ''), COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist'))) = LOWER(?)
+	`, EdgeSilenceSeamlessMS, EdgeSilenceSeamlessMS, albumTitle, artistName).Scan(
+		&trackCount,
+		&mixTrackCount,
+		&analyzedCount,
+		&seamlessLeads,
+		&seamlessTails,
+	); err != nil {
+		return AlbumMix{}, fmt.Errorf("detect continuous mix for %q by %q: %w", albumTitle, artistName, err)
+	}
+	if trackCount == 0 {
+		return AlbumMix{}, ErrAlbumNotFound
+	}
+
+	mix := AlbumMix{
+		Title:       albumTitle,
+		AlbumArtist: artistName,
+		Detected: (mixTrackCount.Valid && mixTrackCount.Int64 > 0) ||
+			seamlessByEdgeSilence(trackCount, analyzedCount, int(seamlessLeads.Int64), int(seamlessTails.Int64)),
+	}
+
+	var overrideInt int
+	err := r.db.QueryRowContext(
+		ctx,
+		"SELECT continuous_mix FROM album_mix_overrides WHERE album_title_key = ? AND album_artist_key = ?",
+		albumMixKey(albumTitle),
+		albumMixKey(artistName),
+	).Scan(&overrideInt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return AlbumMix{}, fmt.Errorf("get continuous mix override for %q by %q: %w", albumTitle, artistName, err)
+	}
+	if err == nil {
+		override := overrideInt == 1
+		mix.Override = &override
+	}
+
+	mix.ContinuousMix = mix.Detected
+	if mix.Override != nil {
+		mix.ContinuousMix = *mix.Override
+	}
+
+	return mix, nil
+}
+
+// SetAlbumMixOverride forces an album to play as a continuous mix or as
+// separate tracks. A nil override returns the album to detection.
+func (r *AlbumMixRepository) SetAlbumMixOverride(ctx context.Context, title string, albumArtist string, override *bool) (AlbumMix, error) {
+	albumTitle := strings.TrimSpace(title)
+	artistName := strings.TrimSpace(albumArtist)
+	if albumTitle == "" {
+		return AlbumMix{}, errors.New("album title is required")
+	}
+	if artistName == "" {
+		return AlbumMix{}, errors.New("album artist is required")
+	}
+
+	if override == nil {
+		if _, err := r.db.ExecContext(
+			ctx,
+			"DELETE FROM album_mix_overrides WHERE album_title_key = ? AND album_artist_key = ?",
+			albumMixKey(albumTitle),
+			albumMixKey(artistName),
+		); err != nil {
+			return AlbumMix{}, fmt.Errorf("clear continuous mix override for %q by %q: %w", albumTitle, artistName, err)
+		}
+
+		return r.GetAlbumMix(ctx, albumTitle, artistName)
+	}
+
+	continuousMix := 0
+	if *override {
+		continuousMix = 1
+	}
+
+	if _, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO album_mix_overrides(album_title_key, album_artist_key, continuous_mix, updated_at)
+		 VALUES (?, ?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+		 ON CONFLICT(album_title_key, album_artist_key) DO UPDATE SET
+		 	continuous_mix = excluded.continuous_mix,
+		 	updated_at = excluded.updated_at`,
+		albumMixKey(albumTitle),
+		albumMixKey(artistName),
+		continuousMix,
+	); err != nil {
+		return AlbumMix{}, fmt.Errorf("set continuous mix override for %q by %q: %w", albumTitle, artistName, err)
+	}
+
+	return r.GetAlbumMix(ctx, albumTitle, artistName)
+}
+
+// IsContinuousMixTrack reports whether the album a track belongs to should
+// play as a continuous mix.
+func (r *AlbumMixRepository) IsContinuousMixTrack(ctx context.Context, trackID int64) (bool, error) {
+	var albumTitle, artistName string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album'),
+			COALESCE(NULLIF(TRIM(t.album_artist), ''), COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist'))
+		FROM tracks t
+		WHERE t.id = ?
+	`, trackID).Scan(&albumTitle, &artistName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrTrackNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("get album for track %d: %w", trackID, err)
+	}
+
+	// Loose tracks share the "Unknown Album" bucket and never form a mix.
+	if albumTitle == "Unknown Album" {
+		return false, nil
+	}
+
+	mix, err := r.GetAlbumMix(ctx, albumTitle, artistName)
+	if errors.Is(err, ErrAlbumNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return mix.ContinuousMix, nil
+}
+
+func albumMixKey(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
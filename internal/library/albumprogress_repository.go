@@ -0,0 +1,60 @@
+package library
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// AlbumProgress tracks how much of an album has been heard. A track counts
+// once it has been played to the end at least once; PercentListened weighs
+// tracks by duration so a long closing track counts for more than an intro.
+type AlbumProgress struct {
+	CompletedTracks int     `json:"completedTracks"`
+	TotalTracks     int     `json:"totalTracks"`
+	PercentListened float64 `json:"percentListened"`
+}
+
+func (r *BrowseRepository) albumProgress(ctx context.Context, albumID int64) (AlbumProgress, error) {
+	var progress AlbumProgress
+	var completedMS int64
+	var totalMS int64
+	if err := r.db.QueryRowContext(ctx, `
+		WITH completed AS (
+			SELECT track_id FROM play_events WHERE event_type = 'complete'
+			UNION
+			SELECT track_id FROM play_stats_combined WHERE complete_count > 0
+		)
+		SELECT
+			COUNT(1),
+			COALESCE(SUM(CASE WHEN t.id IN (SELECT track_id FROM completed) THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(COALESCE(t.duration_ms, 0)), 0),
+			COALESCE(SUM(CASE WHEN t.id IN (SELECT track_id FROM completed) THEN COALESCE(t.duration_ms, 0) ELSE 0 END), 0)
+		FROM album_tracks at
+		JOIN tracks t ON t.id = at.track_id
+		JOIN files f ON f.id = t.file_id
+		WHERE at.album_id = ?
+		  AND f.file_exists = 1
+	`, albumID).Scan(&progress.TotalTracks, &progress.CompletedTracks, &totalMS, &completedMS); err != nil {
+		return AlbumProgress{}, fmt.Errorf("get album progress for %d: %w", albumID, err)
+	}
+
+	progress.PercentListened = listenedPercent(progress.CompletedTracks, progress.TotalTracks, completedMS, totalMS)
+	return progress, nil
+}
+
+// listenedPercent falls back to counting tracks when durations are unknown
+// and rounds to one decimal place.
+func listenedPercent(completedTracks int, totalTracks int, completedMS int64, totalMS int64) float64 {
+	var ratio float64
+	switch {
+	case totalMS > 0:
+		ratio = float64(completedMS) / float64(totalMS)
+	case totalTracks > 0:
+		ratio = float64(completedTracks) / float64(totalTracks)
+	default:
+		return 0
+	}
+
+	return math.Round(min(ratio, 1)*1000) / 10
+}
@@ -0,0 +1,17 @@
+package library
+
+import "testing"
+
+func TestListenedPercentWeighsByDuration(t *testing.T) {
+	t.Parallel()
+
+	if got := listenedPercent(1, 2, 60_000, 240_000); got != 25 {
+		t.Fatalf("expected duration weighted percent 25, got %v", got)
+	}
+	if got := listenedPercent(1, 3, 0, 0); got != 33.3 {
+		t.Fatalf("expected track count fallback 33.3, got %v", got)
+	}
+	if got := listenedPercent(0, 0, 0, 0); got != 0 {
+		t.Fatalf("expected empty album to be 0, got %v", got)
+	}
+}
@@ -0,0 +1,58 @@
+package library
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	AlbumSortArtist = "artist"
+	// AlbumSortColor orders albums by the primary color of their cover for
+	// a color-wall view: colorful covers by hue, then gray ones from light
+	// to dark, then covers without a stored palette.
+	AlbumSortColor = "color"
+	// AlbumSortClassical orders albums by composer, then by the opus or
+	// catalog number of their works, so a composer's works read in
+	// catalog order. Albums without a composer come last.
+	AlbumSortClassical = "classical"
+)
+
+// grayCoverMaxChroma is the OKLCH chroma below which a cover counts as
+// black, white or gray and is not ordered by hue.
+const grayCoverMaxChroma = 0.04
+
+// colorSortHueBuckets splits the hue circle so neighbouring covers of one
+// hue are ordered by lightness rather than by tiny hue differences.
+const colorSortHueBuckets = 24
+
+// albumArtistTitleOrderSQL breaks ties between names that collate equal,
+// such as Han names, by their romanized aliases.
+const albumArtistTitleOrderSQL = `COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist') COLLATE LOCALE, a.artist_romanized COLLATE LOCALE, COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album') COLLATE LOCALE, a.romanized COLLATE LOCALE`
+
+// albumOrderSQL returns the ORDER BY terms for a sort mode. The color terms
+// read the palette joined as "palette".
+func albumOrderSQL(sortMode string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(sortMode)) {
+	case "", AlbumSortArtist:
+		return albumArtistTitleOrderSQL, nil
+	case AlbumSortColor:
+		return fmt.Sprintf(`
+			CASE
+				WHEN palette.primary_hue IS NULL THEN 2
+				WHEN palette.primary_chroma < %[1]g THEN 1
+				ELSE 0
+			END,
+			CASE WHEN palette.primary_chroma >= %[1]g THEN CAST(palette.primary_hue * %[2]d / 360 AS INTEGER) END,
+			palette.primary_lightness DESC,
+			%[3]s`, grayCoverMaxChroma, colorSortHueBuckets, albumArtistTitleOrderSQL), nil
+	case AlbumSortClassical:
+		return `
+			CASE WHEN a.composer IS NULL THEN 1 ELSE 0 END,
+			a.composer COLLATE LOCALE,
+			CASE WHEN a.catalog_key IS NULL THEN 1 ELSE 0 END,
+			a.catalog_key,
+			` + albumArtistTitleOrderSQL, nil
+	default:
+		return "", fmt.Errorf("unknown album sort %q", sortMode)
+	}
+}
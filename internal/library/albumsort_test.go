@@ -0,0 +1,35 @@
+package library
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAlbumOrderSQLModes(t *testing.T) {
+	t.Parallel()
+
+	artistOrder, err := albumOrderSQL("")
+	if err != nil || artistOrder != albumArtistTitleOrderSQL {
+		t.Fatalf("expected the artist order by default, got %q (%v)", artistOrder, err)
+	}
+
+	colorOrder, err := albumOrderSQL(" Color ")
+	if err != nil {
+		t.Fatalf("color order: %v", err)
+	}
+	if !strings.Contains(colorOrder, "palette.primary_hue") || !strings.HasSuffix(colorOrder, albumArtistTitleOrderSQL) {
+		t.Fatalf("expected hue ordering with an artist tie-break, got %q", colorOrder)
+	}
+
+	classicalOrder, err := albumOrderSQL("classical")
+	if err != nil {
+		t.Fatalf("classical order: %v", err)
+	}
+	if !strings.Contains(classicalOrder, "a.catalog_key") || !strings.HasSuffix(classicalOrder, albumArtistTitleOrderSQL) {
+		t.Fatalf("expected catalog ordering with an artist tie-break, got %q", classicalOrder)
+	}
+
+	if _, err := albumOrderSQL("rainbow"); err == nil {
+		t.Fatal("expected an unknown sort to be rejected")
+	}
+}
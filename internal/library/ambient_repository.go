@@ -0,0 +1,144 @@
+package library
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+	"strings"
+
+	"github.com/rzxx/ben/internal/coverart"
+	"github.com/rzxx/ben/internal/palette"
+)
+
+const (
+	defaultAmbientCoversLimit = 12
+	maxAmbientCoversLimit     = 48
+	// minAmbientCoverSize is the shorter side, in pixels, a cover needs to
+	// be shown full screen. Smaller covers are only used when the library
+	// has no large ones.
+	minAmbientCoverSize = 800
+)
+
+// AmbientCover is one slide of the ambient display. Palette is filled in by
+// the theme service.
+type AmbientCover struct {
+	CoverID       int64                 `json:"coverId"`
+	Title         string                `json:"title"`
+	AlbumArtist   string                `json:"albumArtist"`
+	ImageURL      string                `json:"imageUrl"`
+	Width         int                   `json:"width,omitempty"`
+	Height        int                   `json:"height,omitempty"`
+	DominantColor *string               `json:"dominantColor,omitempty"`
+	Palette       *palette.ThemePalette `json:"palette,omitempty"`
+	CachePath     string                `json:"-"`
+}
+
+// AmbientCoverPage is a page of the shuffled cover feed. The order is fixed
+// by Seed, so pages fetched with the same seed never repeat a cover.
+type AmbientCoverPage struct {
+	Seed       int64          `json:"seed"`
+	Offset     int            `json:"offset"`
+	NextOffset int            `json:"nextOffset"`
+	Total      int            `json:"total"`
+	Covers     []AmbientCover `json:"covers"`
+}
+
+// ListAmbientCovers returns a page of album covers in an order shuffled by
+// seed. A seed of 0 picks a new one, which the page reports for the
+// following requests; the feed wraps around once every cover was shown.
+func (r *BrowseRepository) ListAmbientCovers(ctx context.Context, seed int64, offset int, limit int) (AmbientCoverPage, error) {
+	if limit <= 0 {
+		limit = defaultAmbientCoversLimit
+	}
+	limit = min(limit, maxAmbientCoversLimit)
+	if seed <= 0 {
+		// Seeds stay below 2^53 so they survive a round trip through
+		// JavaScript numbers.
+		seed = rand.Int64N(1<<53) + 1
+	}
+
+	covers, err := r.listAmbientCovers(ctx, minAmbientCoverSize)
+	if err != nil {
+		return AmbientCoverPage{}, err
+	}
+	if len(covers) == 0 {
+		if covers, err = r.listAmbientCovers(ctx, 0); err != nil {
+			return AmbientCoverPage{}, err
+		}
+	}
+
+	page := AmbientCoverPage{Seed: seed, Total: len(covers), Covers: []AmbientCover{}}
+	if len(covers) == 0 {
+		return page, nil
+	}
+
+	shuffleAmbientCovers(covers, seed)
+	page.Offset = max(offset, 0) % len(covers)
+	for index := range min(limit, len(covers)) {
+		page.Covers = append(page.Covers, covers[(page.Offset+index)%len(covers)])
+	}
+	page.NextOffset = (page.Offset + len(page.Covers)) % len(covers)
+
+	return page, nil
+}
+
+// listAmbientCovers lists covers with a shorter side of at least minSize,
+// each with the first album using it, in cover order.
+func (r *BrowseRepository) listAmbientCovers(ctx context.Context, minSize int) ([]AmbientCover, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			cover.id,
+			COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album'),
+			COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist'),
+			cover.cache_path,
+			COALESCE(cover.width, 0),
+			COALESCE(cover.height, 0),
+			cover.dominant_color
+		FROM covers cover
+		JOIN albums a ON a.id = (
+			SELECT MIN(candidate.id) FROM albums candidate WHERE candidate.cover_id = cover.id
+		)
+		WHERE cover.cache_path IS NOT NULL AND TRIM(cover.cache_path) <> ''
+		  AND (? = 0 OR MIN(COALESCE(cover.width, 0), COALESCE(cover.height, 0)) >= ?)
+		ORDER BY cover.id
+	`, minSize, minSize)
+	if err != nil {
+		return nil, fmt.Errorf("list ambient covers: %w", err)
+	}
+	defer rows.Close()
+
+	covers := make([]AmbientCover, 0)
+	for rows.Next() {
+		var cover AmbientCover
+		var dominantColor *string
+		if scanErr := rows.Scan(
+			&cover.CoverID,
+			&cover.Title,
+			&cover.AlbumArtist,
+			&cover.CachePath,
+			&cover.Width,
+			&cover.Height,
+			&dominantColor,
+		); scanErr != nil {
+			return nil, fmt.Errorf("scan ambient cover: %w", scanErr)
+		}
+		cover.ImageURL = "/covers?path=" + url.QueryEscape(strings.TrimSpace(cover.CachePath)) + "&variant=" + coverart.VariantOriginal
+		cover.DominantColor = dominantColor
+		covers = append(covers, cover)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate ambient covers: %w", rowsErr)
+	}
+
+	return covers, nil
+}
+
+// shuffleAmbientCovers shuffles covers in place, the same way for the same
+// seed and covers.
+func shuffleAmbientCovers(covers []AmbientCover, seed int64) {
+	shuffler := rand.New(rand.NewPCG(uint64(seed), uint64(seed)*0x9e3779b97f4a7c15))
+	shuffler.Shuffle(len(covers), func(i int, j int) {
+		covers[i], covers[j] = covers[j], covers[i]
+	})
+}
@@ -0,0 +1,44 @@
+package library
+
+import (
+	"slices"
+	"testing"
+)
+
+func ambientCoverIDs(covers []AmbientCover) []int64 {
+	ids := make([]int64, 0, len(covers))
+	for _, cover := range covers {
+		ids = append(ids, cover.CoverID)
+	}
+	return ids
+}
+
+func TestShuffleAmbientCoversIsStablePerSeed(t *testing.T) {
+	t.Parallel()
+
+	build := func() []AmbientCover {
+		covers := make([]AmbientCover, 0, 20)
+		for id := int64(1); id <= 20; id++ {
+			covers = append(covers, AmbientCover{CoverID: id})
+		}
+		return covers
+	}
+
+	first, second, other := build(), build(), build()
+	shuffleAmbientCovers(first, 42)
+	shuffleAmbientCovers(second, 42)
+	shuffleAmbientCovers(other, 43)
+
+	if !slices.Equal(ambientCoverIDs(first), ambientCoverIDs(second)) {
+		t.Fatalf("expected the same order for the same seed, got %v and %v", ambientCoverIDs(first), ambientCoverIDs(second))
+	}
+	if slices.Equal(ambientCoverIDs(first), ambientCoverIDs(other)) {
+		t.Fatalf("expected another seed to give another order, got %v", ambientCoverIDs(other))
+	}
+
+	sorted := ambientCoverIDs(first)
+	slices.Sort(sorted)
+	if !slices.Equal(sorted, ambientCoverIDs(build())) {
+		t.Fatalf("expected every cover exactly once, got %v", ambientCoverIDs(first))
+	}
+}
@@ -0,0 +1,82 @@
+package library
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// artistCreditPattern matches the separators between the artists of one
+// credit: ";" and featuring credits such as "feat.", "ft." or "featuring",
+// with an opening bracket before them.
+var artistCreditPattern = regexp.MustCompile(`(?i)\s*;\s*|\s*[(\[]?\s*\b(?:feat\.|feat|ft\.|featuring)\s+`)
+
+// SplitArtists splits artist credits such as "A feat. B", "A; B" or "A/B"
+// into single artists, in credit order and without duplicates. A "/" only
+// separates names longer than two letters on both sides, so "AC/DC" stays
+// whole.
+func SplitArtists(values ...string) []string {
+	seen := make(map[string]struct{})
+	artists := make([]string, 0, len(values))
+	for _, value := range values {
+		for _, credit := range splitArtistCredits(value) {
+			for _, part := range splitArtistSlashes(credit) {
+				artist := strings.Join(strings.Fields(part), " ")
+				if artist == "" {
+					continue
+				}
+				key := strings.ToLower(artist)
+				if _, duplicate := seen[key]; duplicate {
+					continue
+				}
+				seen[key] = struct{}{}
+				artists = append(artists, artist)
+			}
+		}
+	}
+
+	return artists
+}
+
+// splitArtistCredits splits value at its separators. A featuring credit
+// opened with a bracket, as in "A (feat. B)", loses its closing bracket.
+func splitArtistCredits(value string) []string {
+	credits := make([]string, 0, 2)
+	closing := ""
+	start := 0
+	for _, match := range artistCreditPattern.FindAllStringIndex(value, -1) {
+		credits = append(credits, trimClosingBracket(value[start:match[0]], closing))
+		closing = ""
+		switch opener := strings.TrimSpace(value[match[0]:match[1]]); {
+		case strings.HasPrefix(opener, "("):
+			closing = ")"
+		case strings.HasPrefix(opener, "["):
+			closing = "]"
+		}
+		start = match[1]
+	}
+
+	return append(credits, trimClosingBracket(value[start:], closing))
+}
+
+func trimClosingBracket(credit string, closing string) string {
+	if closing == "" {
+		return credit
+	}
+
+	return strings.TrimSuffix(strings.TrimSpace(credit), closing)
+}
+
+func splitArtistSlashes(credit string) []string {
+	parts := strings.Split(credit, "/")
+	if len(parts) == 1 {
+		return parts
+	}
+	for _, part := range parts {
+		if utf8.RuneCountInString(strings.TrimSpace(part)) <= 2 {
+			return []string{credit}
+		}
+	}
+
+	return parts
+}
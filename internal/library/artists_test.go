@@ -0,0 +1,32 @@
+package library
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitArtists(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string][]string{
+		"Daft Punk feat. Pharrell Williams":   {"Daft Punk", "Pharrell Williams"},
+		"Calvin Harris (Feat. Rihanna)":       {"Calvin Harris", "Rihanna"},
+		"Artist One; Artist Two ;artist one":  {"Artist One", "Artist Two"},
+		"Simon/Garfunkel":                     {"Simon", "Garfunkel"},
+		"AC/DC":                               {"AC/DC"},
+		"Sunn O)))":                           {"Sunn O)))"},
+		"Earth, Wind & Fire ft. The Emotions": {"Earth, Wind & Fire", "The Emotions"},
+		"Featherweight [featuring Someone]":   {"Featherweight", "Someone"},
+		"  ":                                  {},
+	}
+
+	for input, want := range cases {
+		if got := SplitArtists(input); !slices.Equal(got, want) {
+			t.Fatalf("expected %q for %q, got %q", want, input, got)
+		}
+	}
+
+	if got := SplitArtists("A; B", "B", "C"); !slices.Equal(got, []string{"A", "B", "C"}) {
+		t.Fatalf("expected values of a multi-value tag to be merged, got %q", got)
+	}
+}
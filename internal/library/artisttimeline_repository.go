@@ -0,0 +1,158 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type TimelineAlbum struct {
+	AlbumSummary
+	FirstPlayedAt *string `json:"firstPlayedAt,omitempty"`
+	TotalPlays    int     `json:"totalPlays"`
+	PlayedMS      int     `json:"playedMs"`
+}
+
+// TimelineYear groups an artist's albums by release year. Year is nil for
+// albums without a year tag, which are listed last.
+type TimelineYear struct {
+	Year       *int            `json:"year,omitempty"`
+	Albums     []TimelineAlbum `json:"albums"`
+	TotalPlays int             `json:"totalPlays"`
+}
+
+type ArtistTimeline struct {
+	Name       string         `json:"name"`
+	Years      []TimelineYear `json:"years"`
+	TotalPlays int            `json:"totalPlays"`
+}
+
+// GetArtistTimeline lists every album the artist appears on, oldest first,
+// with when it was first heard and how much it has been played since.
+func (r *BrowseRepository) GetArtistTimeline(ctx context.Context, name string) (ArtistTimeline, error) {
+	artistName := strings.TrimSpace(name)
+	if artistName == "" {
+		return ArtistTimeline{}, errors.New("artist name is required")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		WITH artist_tracks AS (
+			SELECT t.id AS track_id
+			FROM tracks t
+			JOIN files f ON f.id = t.file_id
+			WHERE f.file_exists = 1
+			  AND f.is_audiobook = 0
+			  AND `+trackArtistSQL("t")+`
+		),
+		track_metrics AS (
+			SELECT
+				track_id,
+				SUM(play_count) AS play_count,
+				SUM(played_ms) AS played_ms,
+				MIN(first_played_at) AS first_played_at
+			FROM (
+				SELECT
+					track_id,
+					CASE WHEN event_type IN ('complete', 'skip', 'partial') THEN 1 ELSE 0 END AS play_count,
+					CASE WHEN event_type = 'heartbeat' THEN COALESCE(position_ms, 0) ELSE 0 END AS played_ms,
+					ts AS first_played_at
+				FROM play_events
+				WHERE track_id IN (SELECT track_id FROM artist_tracks)
+				UNION ALL
+				SELECT
+					track_id,
+					complete_count + skip_count + partial_count,
+					played_ms,
+					CASE WHEN played_ms > 0 OR complete_count + skip_count + partial_count > 0 THEN day END
+				FROM play_stats_combined
+				WHERE track_id IN (SELECT track_id FROM artist_tracks)
+			) metrics
+			GROUP BY track_id
+		)
+		SELECT
+			COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album') AS album_title,
+			COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist') AS album_artist_name,
+			a.year,
+			COUNT(1) AS track_count,
+			cover.cache_path,
+			MIN(tm.first_played_at),
+			COALESCE(SUM(tm.play_count), 0),
+			COALESCE(SUM(tm.played_ms), 0)
+		FROM albums a
+		JOIN album_tracks at ON at.album_id = a.id
+		JOIN artist_tracks artist_track ON artist_track.track_id = at.track_id
+		LEFT JOIN track_metrics tm ON tm.track_id = at.track_id
+		LEFT JOIN covers cover ON cover.id = a.cover_id
+		GROUP BY a.id, album_title, album_artist_name, a.year, cover.cache_path
+		ORDER BY a.year IS NULL, a.year, album_title COLLATE LOCALE
+	`, artistName)
+	if err != nil {
+		return ArtistTimeline{}, fmt.Errorf("list artist timeline for %q: %w", artistName, err)
+	}
+	defer rows.Close()
+
+	albums := make([]TimelineAlbum, 0)
+	for rows.Next() {
+		var album TimelineAlbum
+		var year sql.NullInt64
+		var coverPath sql.NullString
+		var firstPlayedAt sql.NullString
+		if scanErr := rows.Scan(
+			&album.Title,
+			&album.AlbumArtist,
+			&year,
+			&album.TrackCount,
+			&coverPath,
+			&firstPlayedAt,
+			&album.TotalPlays,
+			&album.PlayedMS,
+		); scanErr != nil {
+			return ArtistTimeline{}, fmt.Errorf("scan artist timeline row for %q: %w", artistName, scanErr)
+		}
+		album.Year = intPointer(year)
+		album.CoverPath = stringPointer(coverPath)
+		album.FirstPlayedAt = stringPointer(firstPlayedAt)
+		albums = append(albums, album)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return ArtistTimeline{}, fmt.Errorf("iterate artist timeline rows for %q: %w", artistName, rowsErr)
+	}
+
+	if len(albums) == 0 {
+		return ArtistTimeline{}, ErrArtistNotFound
+	}
+
+	timeline := ArtistTimeline{Name: artistName, Years: groupTimelineYears(albums)}
+	for _, year := range timeline.Years {
+		timeline.TotalPlays += year.TotalPlays
+	}
+
+	return timeline, nil
+}
+
+// groupTimelineYears expects albums already ordered by year.
+func groupTimelineYears(albums []TimelineAlbum) []TimelineYear {
+	years := make([]TimelineYear, 0)
+	for _, album := range albums {
+		last := len(years) - 1
+		if last < 0 || !sameYear(years[last].Year, album.Year) {
+			years = append(years, TimelineYear{Year: album.Year, Albums: make([]TimelineAlbum, 0, 1)})
+			last++
+		}
+		years[last].Albums = append(years[last].Albums, album)
+		years[last].TotalPlays += album.TotalPlays
+	}
+
+	return years
+}
+
+func sameYear(left *int, right *int) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+
+	return *left == *right
+}
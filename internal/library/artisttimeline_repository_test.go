@@ -0,0 +1,26 @@
+package library
+
+import "testing"
+
+func TestGroupTimelineYearsKeepsUnknownYearSeparate(t *testing.T) {
+	t.Parallel()
+
+	year := func(value int) *int { return &value }
+	albums := []TimelineAlbum{
+		{AlbumSummary: AlbumSummary{Title: "Debut", Year: year(1999)}, TotalPlays: 3},
+		{AlbumSummary: AlbumSummary{Title: "Live", Year: year(1999)}, TotalPlays: 1},
+		{AlbumSummary: AlbumSummary{Title: "Second", Year: year(2003)}},
+		{AlbumSummary: AlbumSummary{Title: "Demos"}, TotalPlays: 2},
+	}
+
+	years := groupTimelineYears(albums)
+	if len(years) != 3 {
+		t.Fatalf("expected 3 year groups, got %d", len(years))
+	}
+	if *years[0].Year != 1999 || len(years[0].Albums) != 2 || years[0].TotalPlays != 4 {
+		t.Fatalf("unexpected first year group %+v", years[0])
+	}
+	if years[2].Year != nil || years[2].Albums[0].Title != "Demos" {
+		t.Fatalf("expected albums without a year last, got %+v", years[2])
+	}
+}
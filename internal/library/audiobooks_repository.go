@@ -0,0 +1,455 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+var ErrAudiobookNotFound = errors.New("audiobook not found")
+
+// audiobookFinishedMarginMS treats a chapter stopped this close to its end
+// as finished, so the next listen starts at the following chapter.
+const audiobookFinishedMarginMS = 10000
+
+// AudiobookFolder is a watched root or folder whose tracks are audiobooks.
+type AudiobookFolder struct {
+	Path       string `json:"path"`
+	TrackCount int    `json:"trackCount"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+// Audiobook groups the chapters of a book by album title and album artist,
+// the same way albums are grouped for music.
+type Audiobook struct {
+	Title             string  `json:"title"`
+	Author            string  `json:"author"`
+	ChapterCount      int     `json:"chapterCount"`
+	CompletedChapters int     `json:"completedChapters"`
+	DurationMS        int64   `json:"durationMs"`
+	ListenedMS        int64   `json:"listenedMs"`
+	PercentListened   float64 `json:"percentListened"`
+	LastListenedAt    *string `json:"lastListenedAt,omitempty"`
+	CoverPath         *string `json:"coverPath,omitempty"`
+}
+
+type AudiobooksPage struct {
+	Items []Audiobook `json:"items"`
+	Page  PageInfo    `json:"page"`
+}
+
+// AudiobookChapter is one track of a book with its own resume position.
+type AudiobookChapter struct {
+	Track            TrackSummary `json:"track"`
+	ResumePositionMS int          `json:"resumePositionMs"`
+	Completed        bool         `json:"completed"`
+}
+
+// AudiobookDetail lists the chapters of a book. ResumeTrackID points at the
+// chapter listened to most recently, or the first unfinished one.
+type AudiobookDetail struct {
+	Audiobook
+	Chapters      []AudiobookChapter `json:"chapters"`
+	ResumeTrackID *int64             `json:"resumeTrackId,omitempty"`
+}
+
+type AudiobookRepository struct {
+	db *sql.DB
+}
+
+func NewAudiobookRepository(database *sql.DB) *AudiobookRepository {
+	return &AudiobookRepository{db: database}
+}
+
+type execContexter interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// RefreshAudiobookFlags marks files below an audiobook folder. The scanner
+// calls it before rebuilding albums and artists so audiobooks stay out of
+// music browsing.
+func RefreshAudiobookFlags(ctx context.Context, db execContexter) error {
+	separator := string(filepath.Separator)
+	if _, err := db.ExecContext(ctx, `
+		UPDATE files
+		SET is_audiobook = CASE
+			WHEN EXISTS (
+				SELECT 1
+				FROM audiobook_folders af
+				WHERE files.path = af.path
+				   OR substr(files.path, 1, length(rtrim(af.path, ?)) + 1) = rtrim(af.path, ?) || ?
+			) THEN 1
+			ELSE 0
+		END
+	`, separator, separator, separator); err != nil {
+		return fmt.Errorf("refresh audiobook flags: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AudiobookRepository) ListFolders(ctx context.Context) ([]AudiobookFolder, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			af.path,
+			af.created_at,
+			(
+				SELECT COUNT(1)
+				FROM tracks t
+				JOIN files f ON f.id = t.file_id
+				WHERE f.file_exists = 1
+				  AND (f.path = af.path OR substr(f.path, 1, length(rtrim(af.path, ?)) + 1) = rtrim(af.path, ?) || ?)
+			) AS track_count
+		FROM audiobook_folders af
+		ORDER BY af.path
+	`, string(filepath.Separator), string(filepath.Separator), string(filepath.Separator))
+	if err != nil {
+		return nil, fmt.Errorf("list audiobook folders: %w", err)
+	}
+	defer rows.Close()
+
+	folders := make([]AudiobookFolder, 0)
+	for rows.Next() {
+		var folder AudiobookFolder
+		if scanErr := rows.Scan(&folder.Path, &folder.CreatedAt, &folder.TrackCount); scanErr != nil {
+			return nil, fmt.Errorf("scan audiobook folder row: %w", scanErr)
+		}
+		folders = append(folders, folder)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate audiobook folder rows: %w", rowsErr)
+	}
+
+	return folders, nil
+}
+
+// SetFolder marks or unmarks a folder as audiobooks and updates the file
+// flags right away. Albums and artists only reflect the change after the
+// next scan rebuilds them.
+func (r *AudiobookRepository) SetFolder(ctx context.Context, path string, audiobook bool) error {
+	cleaned := strings.TrimSpace(path)
+	if cleaned == "" {
+		return errors.New("folder path is required")
+	}
+	cleaned = filepath.Clean(cleaned)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin audiobook folder tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if audiobook {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO audiobook_folders(path) VALUES (?) ON CONFLICT(path) DO NOTHING", cleaned); err != nil {
+			return fmt.Errorf("add audiobook folder: %w", err)
+		}
+	} else {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM audiobook_folders WHERE path = ?", cleaned); err != nil {
+			return fmt.Errorf("remove audiobook folder: %w", err)
+		}
+	}
+
+	if err := RefreshAudiobookFlags(ctx, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit audiobook folder tx: %w", err)
+	}
+
+	return nil
+}
+
+const audiobookTracksSQL = `
+	SELECT
+		t.id AS track_id,
+		COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album') AS book_title,
+		COALESCE(NULLIF(TRIM(t.album_artist), ''), COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) AS book_author,
+		COALESCE(t.duration_ms, 0) AS duration_ms,
+		COALESCE(p.position_ms, 0) AS position_ms,
+		COALESCE(p.completed, 0) AS completed,
+		p.updated_at AS listened_at,
+		t.file_id AS file_id,
+		t.disc_no AS disc_no,
+		t.track_no AS track_no
+	FROM tracks t
+	JOIN files f ON f.id = t.file_id
+	LEFT JOIN audiobook_positions p ON p.track_id = t.id
+	WHERE f.file_exists = 1
+	  AND f.is_audiobook = 1
+`
+
+const audiobookSummarySelect = `
+	SELECT
+		bt.book_title,
+		bt.book_author,
+		COUNT(1),
+		COALESCE(SUM(bt.completed), 0),
+		COALESCE(SUM(bt.duration_ms), 0),
+		COALESCE(SUM(CASE WHEN bt.completed = 1 THEN bt.duration_ms ELSE bt.position_ms END), 0),
+		MAX(bt.listened_at),
+		(
+			SELECT cover.cache_path
+			FROM book_tracks bt2
+			JOIN covers cover ON cover.source_file_id = bt2.file_id
+			WHERE bt2.book_title = bt.book_title
+			  AND bt2.book_author = bt.book_author
+			ORDER BY COALESCE(bt2.disc_no, 0), COALESCE(bt2.track_no, 0), bt2.track_id
+			LIMIT 1
+		)
+	FROM book_tracks bt
+`
+
+// ListAudiobooks puts books in progress first, most recent first, and the
+// rest by author and title.
+func (r *AudiobookRepository) ListAudiobooks(ctx context.Context, search string, limit int, offset int) (AudiobooksPage, error) {
+	limit, offset = normalizePagination(limit, offset, defaultBrowseLimit)
+
+	whereSQL := "1 = 1"
+	args := make([]any, 0, 4)
+	if pattern := makeSearchPattern(search); pattern != "" {
+		whereSQL = "(LOWER(bt.book_title) LIKE ? OR LOWER(bt.book_author) LIKE ?)"
+		args = append(args, pattern, pattern)
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `
+		WITH book_tracks AS (`+audiobookTracksSQL+`)
+		SELECT COUNT(1)
+		FROM (
+			SELECT 1
+			FROM book_tracks bt
+			WHERE `+whereSQL+`
+			GROUP BY bt.book_title, bt.book_author
+		)
+	`, args...).Scan(&total); err != nil {
+		return AudiobooksPage{}, fmt.Errorf("count audiobooks: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		WITH book_tracks AS (`+audiobookTracksSQL+`)
+		`+audiobookSummarySelect+`
+		WHERE `+whereSQL+`
+		GROUP BY bt.book_title, bt.book_author
+		ORDER BY MAX(bt.listened_at) IS NULL, MAX(bt.listened_at) DESC, bt.book_author COLLATE LOCALE, bt.book_title COLLATE LOCALE
+		LIMIT ?
+		OFFSET ?
+	`, append(cloneArgs(args), limit, offset)...)
+	if err != nil {
+		return AudiobooksPage{}, fmt.Errorf("list audiobooks: %w", err)
+	}
+	defer rows.Close()
+
+	books := make([]Audiobook, 0)
+	for rows.Next() {
+		book, scanErr := scanAudiobook(rows)
+		if scanErr != nil {
+			return AudiobooksPage{}, fmt.Errorf("scan audiobook row: %w", scanErr)
+		}
+		books = append(books, book)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return AudiobooksPage{}, fmt.Errorf("iterate audiobook rows: %w", rowsErr)
+	}
+
+	return AudiobooksPage{
+		Items: books,
+		Page: PageInfo{
+			Limit:  limit,
+			Offset: offset,
+			Total:  total,
+		},
+	}, nil
+}
+
+func (r *AudiobookRepository) GetAudiobook(ctx context.Context, title string, author string) (AudiobookDetail, error) {
+	bookTitle := strings.TrimSpace(title)
+	bookAuthor := strings.TrimSpace(author)
+	if bookTitle == "" || bookAuthor == "" {
+		return AudiobookDetail{}, errors.New("audiobook title and author are required")
+	}
+
+	row := r.db.QueryRowContext(ctx, `
+		WITH book_tracks AS (`+audiobookTracksSQL+`)
+		`+audiobookSummarySelect+`
+		WHERE LOWER(bt.book_title) = LOWER(?)
+		  AND LOWER(bt.book_author) = LOWER(?)
+		GROUP BY bt.book_title, bt.book_author
+	`, bookTitle, bookAuthor)
+	book, err := scanAudiobook(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return AudiobookDetail{}, ErrAudiobookNotFound
+	}
+	if err != nil {
+		return AudiobookDetail{}, fmt.Errorf("get audiobook %q by %q: %w", bookTitle, bookAuthor, err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			t.id,
+			COALESCE(NULLIF(TRIM(t.title), ''), 'Unknown Title') AS track_title,
+			COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist') AS track_artist,
+			COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album') AS track_album,
+			COALESCE(NULLIF(TRIM(t.album_artist), ''), COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) AS track_album_artist,
+			t.disc_no,
+			t.track_no,
+			t.duration_ms,
+			f.path,
+			cover.cache_path,
+			COALESCE(p.position_ms, 0),
+			COALESCE(p.completed, 0),
+			p.updated_at
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN covers cover ON cover.source_file_id = t.file_id
+		LEFT JOIN audiobook_positions p ON p.track_id = t.id
+		WHERE f.file_exists = 1
+		  AND f.is_audiobook = 1
+		  AND LOWER(COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album')) = LOWER(?)
+		  AND LOWER(COALESCE(NULLIF(TRIM(t.album_artist), ''), COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist'))) = LOWER(?)
+		ORDER BY COALESCE(t.disc_no, 0), COALESCE(t.track_no, 0), f.path
+	`, book.Title, book.Author)
+	if err != nil {
+		return AudiobookDetail{}, fmt.Errorf("list audiobook chapters for %q by %q: %w", bookTitle, bookAuthor, err)
+	}
+	defer rows.Close()
+
+	detail := AudiobookDetail{Audiobook: book, Chapters: make([]AudiobookChapter, 0)}
+	var lastListenedAt string
+	for rows.Next() {
+		var chapter AudiobookChapter
+		var discNo sql.NullInt64
+		var trackNo sql.NullInt64
+		var durationMS sql.NullInt64
+		var coverPath sql.NullString
+		var completed int
+		var listenedAt sql.NullString
+		if scanErr := rows.Scan(
+			&chapter.Track.ID,
+			&chapter.Track.Title,
+			&chapter.Track.Artist,
+			&chapter.Track.Album,
+			&chapter.Track.AlbumArtist,
+			&discNo,
+			&trackNo,
+			&durationMS,
+			&chapter.Track.Path,
+			&coverPath,
+			&chapter.ResumePositionMS,
+			&completed,
+			&listenedAt,
+		); scanErr != nil {
+			return AudiobookDetail{}, fmt.Errorf("scan audiobook chapter row: %w", scanErr)
+		}
+		chapter.Track.DiscNo = intPointer(discNo)
+		chapter.Track.TrackNo = intPointer(trackNo)
+		chapter.Track.DurationMS = intPointer(durationMS)
+		chapter.Track.CoverPath = stringPointer(coverPath)
+		chapter.Completed = completed == 1
+		detail.Chapters = append(detail.Chapters, chapter)
+
+		if !chapter.Completed && chapter.ResumePositionMS > 0 && listenedAt.Valid && listenedAt.String > lastListenedAt {
+			lastListenedAt = listenedAt.String
+			trackID := chapter.Track.ID
+			detail.ResumeTrackID = &trackID
+		}
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return AudiobookDetail{}, fmt.Errorf("iterate audiobook chapter rows: %w", rowsErr)
+	}
+
+	if detail.ResumeTrackID == nil {
+		for _, chapter := range detail.Chapters {
+			if !chapter.Completed {
+				trackID := chapter.Track.ID
+				detail.ResumeTrackID = &trackID
+				break
+			}
+		}
+	}
+
+	return detail, nil
+}
+
+// ResumePosition reports where an audiobook track should start. ok is false
+// for music tracks, which are never resumed.
+func (r *AudiobookRepository) ResumePosition(ctx context.Context, trackID int64) (int, bool, error) {
+	var positionMS int
+	var audiobook int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			f.is_audiobook,
+			CASE WHEN COALESCE(p.completed, 0) = 1 THEN 0 ELSE COALESCE(p.position_ms, 0) END
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN audiobook_positions p ON p.track_id = t.id
+		WHERE t.id = ?
+	`, trackID).Scan(&audiobook, &positionMS)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("get audiobook position for %d: %w", trackID, err)
+	}
+
+	return positionMS, audiobook == 1, nil
+}
+
+// SaveResumePosition stores the position of an audiobook track. A position
+// near the end marks the chapter finished and resets it to the start.
+func (r *AudiobookRepository) SaveResumePosition(ctx context.Context, trackID int64, positionMS int, durationMS *int) error {
+	completed := 0
+	if positionMS < 0 {
+		positionMS = 0
+	}
+	if durationMS != nil && *durationMS > 0 && positionMS >= *durationMS-audiobookFinishedMarginMS {
+		completed = 1
+		positionMS = 0
+	}
+
+	if _, err := r.db.ExecContext(ctx, `
+		INSERT INTO audiobook_positions(track_id, position_ms, completed, updated_at)
+		VALUES (?, ?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+		ON CONFLICT(track_id) DO UPDATE SET
+			position_ms = excluded.position_ms,
+			completed = CASE WHEN excluded.completed = 1 THEN 1 ELSE audiobook_positions.completed END,
+			updated_at = excluded.updated_at
+	`, trackID, positionMS, completed); err != nil {
+		return fmt.Errorf("save audiobook position for %d: %w", trackID, err)
+	}
+
+	return nil
+}
+
+type audiobookScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAudiobook(row audiobookScanner) (Audiobook, error) {
+	var book Audiobook
+	var lastListenedAt sql.NullString
+	var coverPath sql.NullString
+	if err := row.Scan(
+		&book.Title,
+		&book.Author,
+		&book.ChapterCount,
+		&book.CompletedChapters,
+		&book.DurationMS,
+		&book.ListenedMS,
+		&lastListenedAt,
+		&coverPath,
+	); err != nil {
+		return Audiobook{}, err
+	}
+
+	book.LastListenedAt = stringPointer(lastListenedAt)
+	book.CoverPath = stringPointer(coverPath)
+	book.PercentListened = listenedPercent(book.CompletedChapters, book.ChapterCount, book.ListenedMS, book.DurationMS)
+	return book, nil
+}
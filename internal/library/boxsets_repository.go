@@ -0,0 +1,232 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrBoxSetNotFound = errors.New("box set not found")
+
+const (
+	BoxSetSourceTag    = "tag"
+	BoxSetSourceFolder = "folder"
+)
+
+const variousArtistsLabel = "Various Artists"
+
+// BoxSetSummary is a parent release grouping several albums, either through
+// a shared BOXSET or GROUPING tag or through a common parent folder.
+type BoxSetSummary struct {
+	Name        string  `json:"name"`
+	AlbumArtist string  `json:"albumArtist"`
+	Source      string  `json:"source"`
+	AlbumCount  int     `json:"albumCount"`
+	TrackCount  int     `json:"trackCount"`
+	CoverPath   *string `json:"coverPath,omitempty"`
+}
+
+type BoxSetDetail struct {
+	BoxSetSummary
+	Albums []AlbumSummary `json:"albums"`
+}
+
+type boxSetAlbum struct {
+	album      AlbumSummary
+	tag        string
+	folder     string
+	parent     string
+	parentName string
+	rootPath   string
+}
+
+type boxSetGroup struct {
+	summary BoxSetSummary
+	albums  []AlbumSummary
+}
+
+func (r *BrowseRepository) ListBoxSets(ctx context.Context) ([]BoxSetSummary, error) {
+	groups, err := r.loadBoxSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	boxSets := make([]BoxSetSummary, 0, len(groups))
+	for _, group := range groups {
+		boxSets = append(boxSets, group.summary)
+	}
+
+	return boxSets, nil
+}
+
+// GetBoxSetDetail returns the member albums of a box set ordered by year and
+// then title, so numbered volumes and discs follow each other.
+func (r *BrowseRepository) GetBoxSetDetail(ctx context.Context, name string, albumArtist string) (BoxSetDetail, error) {
+	boxSetName := strings.TrimSpace(name)
+	artistName := strings.TrimSpace(albumArtist)
+	if boxSetName == "" {
+		return BoxSetDetail{}, errors.New("box set name is required")
+	}
+	if artistName == "" {
+		return BoxSetDetail{}, errors.New("album artist is required")
+	}
+
+	groups, err := r.loadBoxSets(ctx)
+	if err != nil {
+		return BoxSetDetail{}, err
+	}
+
+	for _, group := range groups {
+		if strings.EqualFold(group.summary.Name, boxSetName) && strings.EqualFold(group.summary.AlbumArtist, artistName) {
+			return BoxSetDetail{BoxSetSummary: group.summary, Albums: group.albums}, nil
+		}
+	}
+
+	return BoxSetDetail{}, ErrBoxSetNotFound
+}
+
+func (r *BrowseRepository) loadBoxSets(ctx context.Context) ([]boxSetGroup, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			a.id,
+			COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album') AS album_title,
+			COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist') AS album_artist_name,
+			a.year,
+			cover.cache_path,
+			CASE WHEN json_valid(t.tags_json) THEN COALESCE(
+				NULLIF(TRIM(json_extract(t.tags_json, '$.taglib_tags.BOXSET[0]')), ''),
+				NULLIF(TRIM(json_extract(t.tags_json, '$.taglib_tags.GROUPING[0]')), '')
+			) END AS box_set_tag,
+			f.path,
+			COALESCE(root.path, '')
+		FROM albums a
+		JOIN album_tracks at ON at.album_id = a.id
+		JOIN tracks t ON t.id = at.track_id
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN watched_roots root ON root.id = f.root_id
+		LEFT JOIN covers cover ON cover.id = a.cover_id
+		WHERE f.file_exists = 1
+		  AND f.is_audiobook = 0
+		ORDER BY a.year IS NULL, a.year, album_title COLLATE LOCALE, a.id, at.disc_no, at.track_no
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query box set albums: %w", err)
+	}
+	defer rows.Close()
+
+	albums := make([]*boxSetAlbum, 0)
+	albumsByID := make(map[int64]*boxSetAlbum)
+	for rows.Next() {
+		var albumID int64
+		var album AlbumSummary
+		var year sql.NullInt64
+		var coverPath sql.NullString
+		var tag sql.NullString
+		var filePath string
+		var rootPath string
+		if scanErr := rows.Scan(&albumID, &album.Title, &album.AlbumArtist, &year, &coverPath, &tag, &filePath, &rootPath); scanErr != nil {
+			return nil, fmt.Errorf("scan box set album row: %w", scanErr)
+		}
+
+		member, ok := albumsByID[albumID]
+		if !ok {
+			album.Year = intPointer(year)
+			album.CoverPath = stringPointer(coverPath)
+			folder := parentPath(filePath)
+			member = &boxSetAlbum{
+				album:      album,
+				folder:     folder,
+				parent:     parentPath(folder),
+				parentName: baseName(parentPath(folder)),
+				rootPath:   strings.TrimRight(rootPath, `/\`),
+			}
+			albumsByID[albumID] = member
+			albums = append(albums, member)
+		}
+
+		member.album.TrackCount++
+		if member.tag == "" && tag.Valid {
+			member.tag = strings.TrimSpace(tag.String)
+		}
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate box set album rows: %w", rowsErr)
+	}
+
+	return groupBoxSets(albums), nil
+}
+
+// groupBoxSets groups tagged albums by tag and album artist, then groups the
+// remaining albums by parent folder. A folder only counts as a box set when
+// it is neither a watched root nor named after the artist, since artist
+// folders holding separate albums are the common layout.
+func groupBoxSets(albums []*boxSetAlbum) []boxSetGroup {
+	groups := make([]*boxSetGroup, 0)
+	groupsByKey := make(map[string]*boxSetGroup)
+	add := func(key string, name string, source string, member *boxSetAlbum) {
+		group, ok := groupsByKey[key]
+		if !ok {
+			group = &boxSetGroup{summary: BoxSetSummary{
+				Name:        name,
+				AlbumArtist: member.album.AlbumArtist,
+				Source:      source,
+			}}
+			groupsByKey[key] = group
+			groups = append(groups, group)
+		}
+		if !strings.EqualFold(group.summary.AlbumArtist, member.album.AlbumArtist) {
+			group.summary.AlbumArtist = variousArtistsLabel
+		}
+		if group.summary.CoverPath == nil {
+			group.summary.CoverPath = member.album.CoverPath
+		}
+		group.summary.AlbumCount++
+		group.summary.TrackCount += member.album.TrackCount
+		group.albums = append(group.albums, member.album)
+	}
+
+	for _, member := range albums {
+		if member.tag != "" {
+			add(BoxSetSourceTag+"\x00"+strings.ToLower(member.tag)+"\x00"+strings.ToLower(member.album.AlbumArtist), member.tag, BoxSetSourceTag, member)
+			continue
+		}
+
+		if member.parent == "" || member.parentName == "" ||
+			member.parent == member.folder ||
+			strings.EqualFold(member.parent, member.rootPath) ||
+			strings.EqualFold(member.parentName, member.album.AlbumArtist) {
+			continue
+		}
+		add(BoxSetSourceFolder+"\x00"+strings.ToLower(member.parent), member.parentName, BoxSetSourceFolder, member)
+	}
+
+	boxSets := make([]boxSetGroup, 0, len(groups))
+	for _, group := range groups {
+		if group.summary.AlbumCount < 2 {
+			continue
+		}
+		boxSets = append(boxSets, *group)
+	}
+
+	return boxSets
+}
+
+// parentPath and baseName accept both separators because remote roots keep
+// forward slashes on every platform.
+func parentPath(value string) string {
+	trimmed := strings.TrimRight(value, `/\`)
+	index := strings.LastIndexAny(trimmed, `/\`)
+	if index <= 0 {
+		return ""
+	}
+
+	return trimmed[:index]
+}
+
+func baseName(value string) string {
+	trimmed := strings.TrimRight(value, `/\`)
+	return trimmed[strings.LastIndexAny(trimmed, `/\`)+1:]
+}
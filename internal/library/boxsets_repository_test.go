@@ -0,0 +1,46 @@
+package library
+
+import "testing"
+
+func TestGroupBoxSets_GroupsByTagThenParentFolder(t *testing.T) {
+	t.Parallel()
+
+	member := func(title string, artist string, tag string, filePath string) *boxSetAlbum {
+		folder := parentPath(filePath)
+		return &boxSetAlbum{
+			album:      AlbumSummary{Title: title, AlbumArtist: artist, TrackCount: 2},
+			tag:        tag,
+			folder:     folder,
+			parent:     parentPath(folder),
+			parentName: baseName(parentPath(folder)),
+			rootPath:   "/music",
+		}
+	}
+
+	groups := groupBoxSets([]*boxSetAlbum{
+		member("Disc 1", "Band", "Anthology", "/music/Band/Anthology/CD1/01.flac"),
+		member("Disc 2", "Band", "anthology", "/music/Band/Anthology/CD2/01.flac"),
+		member("Live 1", "Band", "", "/music/Band/Live Box/Vol 1/01.flac"),
+		member("Live 2", "Band", "", "/music/Band/Live Box/Vol 2/01.flac"),
+		member("Debut", "Band", "", "/music/Band/Debut/01.flac"),
+		member("Second", "Band", "", "/music/Band/Second/01.flac"),
+		member("Loose", "Other", "", "/music/Loose/01.flac"),
+	})
+
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 box sets, got %d: %+v", len(groups), groups)
+	}
+
+	tagged := groups[0]
+	if tagged.summary.Name != "Anthology" || tagged.summary.Source != BoxSetSourceTag || tagged.summary.AlbumCount != 2 || tagged.summary.TrackCount != 4 {
+		t.Fatalf("unexpected tagged box set: %+v", tagged.summary)
+	}
+
+	folder := groups[1]
+	if folder.summary.Name != "Live Box" || folder.summary.Source != BoxSetSourceFolder || folder.summary.AlbumArtist != "Band" {
+		t.Fatalf("unexpected folder box set: %+v", folder.summary)
+	}
+	if folder.albums[0].Title != "Live 1" || folder.albums[1].Title != "Live 2" {
+		t.Fatalf("unexpected member order: %+v", folder.albums)
+	}
+}
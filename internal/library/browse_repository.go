@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 var ErrArtistNotFound = errors.New("artist not found")
@@ -25,24 +27,34 @@ type ArtistSummary struct {
 }
 
 type AlbumSummary struct {
-	Title       string  `json:"title"`
-	AlbumArtist string  `json:"albumArtist"`
-	Year        *int    `json:"year,omitempty"`
-	TrackCount  int     `json:"trackCount"`
-	CoverPath   *string `json:"coverPath,omitempty"`
+	Title        string  `json:"title"`
+	AlbumArtist  string  `json:"albumArtist"`
+	Year         *int    `json:"year,omitempty"`
+	TrackCount   int     `json:"trackCount"`
+	CoverPath    *string `json:"coverPath,omitempty"`
+	PlayCount    *int    `json:"playCount,omitempty"`
+	LastPlayedAt *string `json:"lastPlayedAt,omitempty"`
+	Rating       *int    `json:"rating,omitempty"`
+	Favorite     bool    `json:"favorite,omitempty"`
+	AddedAt      *string `json:"addedAt,omitempty"`
 }
 
 type TrackSummary struct {
-	ID          int64   `json:"id"`
-	Title       string  `json:"title"`
-	Artist      string  `json:"artist"`
-	Album       string  `json:"album"`
-	AlbumArtist string  `json:"albumArtist"`
-	DiscNo      *int    `json:"discNo,omitempty"`
-	TrackNo     *int    `json:"trackNo,omitempty"`
-	DurationMS  *int    `json:"durationMs,omitempty"`
-	Path        string  `json:"path"`
-	CoverPath   *string `json:"coverPath,omitempty"`
+	ID           int64   `json:"id"`
+	Title        string  `json:"title"`
+	Artist       string  `json:"artist"`
+	Album        string  `json:"album"`
+	AlbumArtist  string  `json:"albumArtist"`
+	DiscNo       *int    `json:"discNo,omitempty"`
+	TrackNo      *int    `json:"trackNo,omitempty"`
+	DurationMS   *int    `json:"durationMs,omitempty"`
+	Path         string  `json:"path"`
+	CoverPath    *string `json:"coverPath,omitempty"`
+	PlayCount    *int    `json:"playCount,omitempty"`
+	LastPlayedAt *string `json:"lastPlayedAt,omitempty"`
+	IsVideo      bool    `json:"isVideo,omitempty"`
+	Rating       *int    `json:"rating,omitempty"`
+	Favorite     bool    `json:"favorite,omitempty"`
 }
 
 type ArtistsPage struct {
@@ -74,6 +86,9 @@ type AlbumDetail struct {
 	Year        *int           `json:"year,omitempty"`
 	TrackCount  int            `json:"trackCount"`
 	CoverPath   *string        `json:"coverPath,omitempty"`
+	Rating      *int           `json:"rating,omitempty"`
+	Favorite    bool           `json:"favorite,omitempty"`
+	Progress    AlbumProgress  `json:"progress"`
 	Tracks      []TrackSummary `json:"tracks"`
 	Page        PageInfo       `json:"page"`
 }
@@ -95,8 +110,11 @@ type ArtistTopTrack struct {
 	PartialCount  int     `json:"partialCount"`
 }
 
+// BrowseRepository reads through db, which may be a read-only pool, and
+// writes through writer.
 type BrowseRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	writer *sql.DB
 }
 
 const defaultBrowseLimit = 24
@@ -106,7 +124,15 @@ const defaultDetailLimit = 18
 const maxBrowseLimit = 200
 
 func NewBrowseRepository(database *sql.DB) *BrowseRepository {
-	return &BrowseRepository{db: database}
+	return &BrowseRepository{db: database, writer: database}
+}
+
+// SetReadPool moves browse queries to a separate read-only pool. It must be
+// called before the repository is shared.
+func (r *BrowseRepository) SetReadPool(reader *sql.DB) {
+	if reader != nil {
+		r.db = reader
+	}
 }
 
 func (r *BrowseRepository) ListArtists(ctx context.Context, search string, limit int, offset int) (ArtistsPage, error) {
@@ -116,8 +142,8 @@ func (r *BrowseRepository) ListArtists(ctx context.Context, search string, limit
 	args := make([]any, 0, 2)
 
 	if pattern := makeSearchPattern(search); pattern != "" {
-		whereClauses = append(whereClauses, "LOWER(a.name) LIKE ?")
-		args = append(args, pattern)
+		whereClauses = append(whereClauses, "(LOWER(a.name) LIKE ? OR LOWER(COALESCE(a.romanized, '')) LIKE ?)")
+		args = append(args, pattern, pattern)
 	}
 
 	whereSQL := strings.Join(whereClauses, " AND ")
@@ -141,12 +167,15 @@ func (r *BrowseRepository) ListArtists(ctx context.Context, search string, limit
 		FROM artists a
 		LEFT JOIN (
 			SELECT
-				COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist') AS artist_name,
+				MIN(ta.artist) AS artist_name,
 				COUNT(1) AS track_count
-			FROM tracks t
+			FROM track_artists ta
+			JOIN tracks t ON t.id = ta.track_id
 			JOIN files f ON f.id = t.file_id
 			WHERE f.file_exists = 1
-			GROUP BY artist_name
+			  AND f.is_audiobook = 0
+			  AND NOT EXISTS (SELECT 1 FROM hidden_duplicates hd WHERE hd.track_id = t.id)
+			GROUP BY ta.artist
 		) track_totals ON LOWER(track_totals.artist_name) = LOWER(a.name)
 		LEFT JOIN (
 			SELECT
@@ -156,7 +185,7 @@ func (r *BrowseRepository) ListArtists(ctx context.Context, search string, limit
 			GROUP BY artist_name
 		) album_totals ON LOWER(album_totals.artist_name) = LOWER(a.name)
 		WHERE %s
-		ORDER BY LOWER(COALESCE(NULLIF(TRIM(a.sort_name), ''), a.name)), LOWER(a.name)
+		ORDER BY COALESCE(NULLIF(TRIM(a.sort_name), ''), a.name) COLLATE LOCALE, a.romanized COLLATE LOCALE, a.name COLLATE LOCALE
 		LIMIT ?
 		OFFSET ?
 	`, whereSQL)
@@ -192,15 +221,52 @@ func (r *BrowseRepository) ListArtists(ctx context.Context, search string, limit
 	}, nil
 }
 
-func (r *BrowseRepository) ListAlbums(ctx context.Context, search string, artist string, limit int, offset int) (AlbumsPage, error) {
+// ListAlbums pages through albums. withStats adds play counts and the last
+// played time to each album at the cost of one extra query.
+func (r *BrowseRepository) ListAlbums(ctx context.Context, search string, artist string, limit int, offset int, withStats bool) (AlbumsPage, error) {
+	return r.ListAlbumsSorted(ctx, search, artist, AlbumSortArtist, limit, offset, withStats)
+}
+
+// ListAlbumsSorted is ListAlbums with a sort mode, one of the AlbumSort
+// constants.
+func (r *BrowseRepository) ListAlbumsSorted(ctx context.Context, search string, artist string, sortMode string, limit int, offset int, withStats bool) (AlbumsPage, error) {
+	return r.listAlbums(ctx, search, artist, sortMode, RatingFilter{}, limit, offset, withStats)
+}
+
+// ListAlbumsByRating is ListAlbumsSorted narrowed to favorite or rated
+// albums.
+func (r *BrowseRepository) ListAlbumsByRating(ctx context.Context, search string, artist string, sortMode string, filter RatingFilter, limit int, offset int, withStats bool) (AlbumsPage, error) {
+	return r.listAlbums(ctx, search, artist, sortMode, filter, limit, offset, withStats)
+}
+
+// trackArtistSQL matches the tracks of alias credited to an artist, alone
+// or next to others as in "A feat. B". It takes the artist name as its
+// argument.
+func trackArtistSQL(alias string) string {
+	return "EXISTS (SELECT 1 FROM track_artists credit WHERE credit.track_id = " + alias + ".id AND credit.artist = ?)"
+}
+
+// albumRatingJoinSQL joins album_ratings by the listed title and album
+// artist, since album ids change on every scan.
+const albumRatingJoinSQL = `
+		LEFT JOIN album_ratings ar
+			ON ar.title = COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album')
+			AND ar.album_artist = COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist')`
+
+func (r *BrowseRepository) listAlbums(ctx context.Context, search string, artist string, sortMode string, rating RatingFilter, limit int, offset int, withStats bool) (AlbumsPage, error) {
+	orderSQL, err := albumOrderSQL(sortMode)
+	if err != nil {
+		return AlbumsPage{}, err
+	}
+
 	limit, offset = normalizePagination(limit, offset, defaultBrowseLimit)
 
 	whereClauses := []string{"1 = 1"}
 	args := make([]any, 0, 8)
 
 	if pattern := makeSearchPattern(search); pattern != "" {
-		whereClauses = append(whereClauses, `(LOWER(COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album')) LIKE ? OR LOWER(COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist')) LIKE ?)`)
-		args = append(args, pattern, pattern)
+		whereClauses = append(whereClauses, `(LOWER(COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album')) LIKE ? OR LOWER(COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist')) LIKE ? OR LOWER(COALESCE(a.romanized, '')) LIKE ? OR LOWER(COALESCE(a.artist_romanized, '')) LIKE ?)`)
+		args = append(args, pattern, pattern, pattern, pattern)
 	}
 
 	if artistFilter := strings.TrimSpace(artist); artistFilter != "" {
@@ -208,11 +274,13 @@ func (r *BrowseRepository) ListAlbums(ctx context.Context, search string, artist
 		args = append(args, artistFilter)
 	}
 
+	whereClauses, args = appendRatingFilter(whereClauses, args, "ar", rating)
+
 	whereSQL := strings.Join(whereClauses, " AND ")
 
 	countQuery := fmt.Sprintf(`
 		SELECT COUNT(1)
-		FROM albums a
+		FROM albums a`+albumRatingJoinSQL+`
 		WHERE %s
 	`, whereSQL)
 
@@ -223,11 +291,14 @@ func (r *BrowseRepository) ListAlbums(ctx context.Context, search string, artist
 
 	listQuery := fmt.Sprintf(`
 		SELECT
+			a.id,
 			COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album') AS album_title,
 			COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist') AS album_artist_name,
 			a.year,
 			COALESCE(track_totals.track_count, 0) AS track_count,
-			cover.cache_path
+			cover.cache_path,
+			ar.rating,
+			COALESCE(ar.favorite, 0)
 		FROM albums a
 		LEFT JOIN (
 			SELECT at.album_id, COUNT(1) AS track_count
@@ -235,14 +306,17 @@ func (r *BrowseRepository) ListAlbums(ctx context.Context, search string, artist
 			JOIN tracks t ON t.id = at.track_id
 			JOIN files f ON f.id = t.file_id
 			WHERE f.file_exists = 1
+			  AND f.is_audiobook = 0
+			  AND NOT EXISTS (SELECT 1 FROM hidden_duplicates hd WHERE hd.track_id = t.id)
 			GROUP BY at.album_id
 		) track_totals ON track_totals.album_id = a.id
 		LEFT JOIN covers cover ON cover.id = a.cover_id
+		LEFT JOIN cover_palettes palette ON palette.cover_id = cover.id AND palette.cover_hash IS cover.hash`+albumRatingJoinSQL+`
 		WHERE %s
-		ORDER BY LOWER(COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist')), LOWER(COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album'))
+		ORDER BY %s
 		LIMIT ?
 		OFFSET ?
-	`, whereSQL)
+	`, whereSQL, orderSQL)
 
 	listArgs := append(cloneArgs(args), limit, offset)
 
@@ -253,21 +327,33 @@ func (r *BrowseRepository) ListAlbums(ctx context.Context, search string, artist
 	defer rows.Close()
 
 	albums := make([]AlbumSummary, 0)
+	albumIDs := make([]int64, 0)
 	for rows.Next() {
+		var albumID int64
 		var album AlbumSummary
 		var year sql.NullInt64
 		var coverPath sql.NullString
-		if scanErr := rows.Scan(&album.Title, &album.AlbumArtist, &year, &album.TrackCount, &coverPath); scanErr != nil {
+		var ratingValue sql.NullInt64
+		if scanErr := rows.Scan(&albumID, &album.Title, &album.AlbumArtist, &year, &album.TrackCount, &coverPath, &ratingValue, &album.Favorite); scanErr != nil {
 			return AlbumsPage{}, fmt.Errorf("scan album row: %w", scanErr)
 		}
 		album.Year = intPointer(year)
 		album.CoverPath = stringPointer(coverPath)
+		album.Rating = intPointer(ratingValue)
 		albums = append(albums, album)
+		albumIDs = append(albumIDs, albumID)
 	}
 
 	if rowsErr := rows.Err(); rowsErr != nil {
 		return AlbumsPage{}, fmt.Errorf("iterate album rows: %w", rowsErr)
 	}
+	rows.Close()
+
+	if withStats {
+		if err := r.attachAlbumPlayStats(ctx, albums, albumIDs); err != nil {
+			return AlbumsPage{}, err
+		}
+	}
 
 	return AlbumsPage{
 		Items: albums,
@@ -279,33 +365,103 @@ func (r *BrowseRepository) ListAlbums(ctx context.Context, search string, artist
 	}, nil
 }
 
-func (r *BrowseRepository) ListTracks(ctx context.Context, search string, artist string, album string, limit int, offset int) (TracksPage, error) {
+// trackFilter narrows a track listing; empty fields do not filter.
+type trackFilter struct {
+	search string
+	artist string
+	album  string
+	moods  []string
+	// script and language narrow to tracks detected as written in them.
+	script   string
+	language string
+	rating   RatingFilter
+	// trackIDs narrows to the given tracks.
+	trackIDs []int64
+	// provenance narrows to one provenance field; an empty value matches
+	// any file with the field set.
+	provenance *provenanceFilter
+}
+
+type provenanceFilter struct {
+	column string
+	value  string
+}
+
+// ListTracks pages through tracks; withStats works as in ListAlbums.
+func (r *BrowseRepository) ListTracks(ctx context.Context, search string, artist string, album string, limit int, offset int, withStats bool) (TracksPage, error) {
+	return r.listTracks(ctx, trackFilter{search: search, artist: artist, album: album}, limit, offset, withStats)
+}
+
+// ListTracksByRating is ListTracks narrowed to favorite or rated tracks.
+func (r *BrowseRepository) ListTracksByRating(ctx context.Context, search string, artist string, album string, filter RatingFilter, limit int, offset int, withStats bool) (TracksPage, error) {
+	return r.listTracks(ctx, trackFilter{search: search, artist: artist, album: album, rating: filter}, limit, offset, withStats)
+}
+
+func (r *BrowseRepository) listTracks(ctx context.Context, filter trackFilter, limit int, offset int, withStats bool) (TracksPage, error) {
 	limit, offset = normalizePagination(limit, offset, defaultBrowseLimit)
 
-	whereClauses := []string{"f.file_exists = 1"}
+	whereClauses := []string{"f.file_exists = 1", "f.is_audiobook = 0", "NOT EXISTS (SELECT 1 FROM hidden_duplicates hd WHERE hd.track_id = t.id)"}
 	args := make([]any, 0, 10)
 
-	if pattern := makeSearchPattern(search); pattern != "" {
-		whereClauses = append(whereClauses, `(LOWER(COALESCE(NULLIF(TRIM(t.title), ''), 'Unknown Title')) LIKE ? OR LOWER(COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) LIKE ? OR LOWER(COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album')) LIKE ?)`)
-		args = append(args, pattern, pattern, pattern)
+	// Every term must match the folded title, artist or album; tracks not
+	// yet given a search text by a scan match the plain lowercased fields.
+	for _, term := range SearchTerms(filter.search) {
+		whereClauses = append(whereClauses, `COALESCE(t.search_text, LOWER(COALESCE(t.title, '') || char(10) || COALESCE(t.artist, '') || char(10) || COALESCE(t.album, ''))) LIKE ?`)
+		args = append(args, "%"+term+"%")
 	}
 
-	if artistFilter := strings.TrimSpace(artist); artistFilter != "" {
-		whereClauses = append(whereClauses, "LOWER(COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) = LOWER(?)")
+	if filter.script != "" {
+		whereClauses = append(whereClauses, "t.script = ?")
+		args = append(args, filter.script)
+	}
+
+	if filter.language != "" {
+		whereClauses = append(whereClauses, "t.language = ?")
+		args = append(args, filter.language)
+	}
+
+	if artistFilter := strings.TrimSpace(filter.artist); artistFilter != "" {
+		whereClauses = append(whereClauses, trackArtistSQL("t"))
 		args = append(args, artistFilter)
 	}
 
-	if albumFilter := strings.TrimSpace(album); albumFilter != "" {
+	if albumFilter := strings.TrimSpace(filter.album); albumFilter != "" {
 		whereClauses = append(whereClauses, "LOWER(COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album')) = LOWER(?)")
 		args = append(args, albumFilter)
 	}
 
+	if len(filter.moods) > 0 {
+		whereClauses = append(whereClauses, fmt.Sprintf("EXISTS (SELECT 1 FROM track_moods tm WHERE tm.track_id = t.id AND tm.mood IN (%s))", sqlPlaceholders(len(filter.moods))))
+		for _, mood := range filter.moods {
+			args = append(args, mood)
+		}
+	}
+
+	if filter.provenance != nil {
+		if filter.provenance.value == "" {
+			whereClauses = append(whereClauses, fmt.Sprintf("EXISTS (SELECT 1 FROM file_provenance fp WHERE fp.file_id = t.file_id AND fp.%s <> '')", filter.provenance.column))
+		} else {
+			whereClauses = append(whereClauses, fmt.Sprintf("EXISTS (SELECT 1 FROM file_provenance fp WHERE fp.file_id = t.file_id AND LOWER(fp.%s) = LOWER(?))", filter.provenance.column))
+			args = append(args, filter.provenance.value)
+		}
+	}
+
+	if len(filter.trackIDs) > 0 {
+		whereClauses = append(whereClauses, fmt.Sprintf("t.id IN (%s)", sqlPlaceholders(len(filter.trackIDs))))
+		for _, trackID := range filter.trackIDs {
+			args = append(args, trackID)
+		}
+	}
+
+	whereClauses, args = appendRatingFilter(whereClauses, args, "tr", filter.rating)
+
 	whereSQL := strings.Join(whereClauses, " AND ")
 
 	countQuery := fmt.Sprintf(`
 		SELECT COUNT(1)
 		FROM tracks t
 		JOIN files f ON f.id = t.file_id
+		LEFT JOIN track_ratings tr ON tr.track_id = t.id
 		WHERE %s
 	`, whereSQL)
 
@@ -325,17 +481,20 @@ func (r *BrowseRepository) ListTracks(ctx context.Context, search string, artist
 			t.track_no,
 			t.duration_ms,
 			f.path,
-			cover.cache_path
+			cover.cache_path,
+			tr.rating,
+			COALESCE(tr.favorite, 0)
 		FROM tracks t
 		JOIN files f ON f.id = t.file_id
 		LEFT JOIN covers cover ON cover.source_file_id = t.file_id
+		LEFT JOIN track_ratings tr ON tr.track_id = t.id
 		WHERE %s
 		ORDER BY
-			LOWER(track_artist),
-			LOWER(track_album),
+			track_artist COLLATE LOCALE,
+			track_album COLLATE LOCALE,
 			COALESCE(t.disc_no, 0),
 			COALESCE(t.track_no, 0),
-			LOWER(track_title)
+			track_title COLLATE LOCALE
 		LIMIT ?
 		OFFSET ?
 	`, whereSQL)
@@ -355,6 +514,7 @@ func (r *BrowseRepository) ListTracks(ctx context.Context, search string, artist
 		var trackNo sql.NullInt64
 		var durationMS sql.NullInt64
 		var coverPath sql.NullString
+		var ratingValue sql.NullInt64
 		if scanErr := rows.Scan(
 			&track.ID,
 			&track.Title,
@@ -366,6 +526,8 @@ func (r *BrowseRepository) ListTracks(ctx context.Context, search string, artist
 			&durationMS,
 			&track.Path,
 			&coverPath,
+			&ratingValue,
+			&track.Favorite,
 		); scanErr != nil {
 			return TracksPage{}, fmt.Errorf("scan track row: %w", scanErr)
 		}
@@ -373,12 +535,20 @@ func (r *BrowseRepository) ListTracks(ctx context.Context, search string, artist
 		track.TrackNo = intPointer(trackNo)
 		track.DurationMS = intPointer(durationMS)
 		track.CoverPath = stringPointer(coverPath)
+		track.Rating = intPointer(ratingValue)
 		tracks = append(tracks, track)
 	}
 
 	if rowsErr := rows.Err(); rowsErr != nil {
 		return TracksPage{}, fmt.Errorf("iterate track rows: %w", rowsErr)
 	}
+	rows.Close()
+
+	if withStats {
+		if err := r.attachTrackPlayStats(ctx, tracks); err != nil {
+			return TracksPage{}, err
+		}
+	}
 
 	return TracksPage{
 		Items: tracks,
@@ -407,12 +577,16 @@ func (r *BrowseRepository) GetArtistDetail(ctx context.Context, name string, lim
 				JOIN tracks t2 ON t2.id = at.track_id
 				JOIN files f2 ON f2.id = t2.file_id
 				WHERE f2.file_exists = 1
-				  AND LOWER(COALESCE(NULLIF(TRIM(t2.artist), ''), 'Unknown Artist')) = LOWER(?)
+				  AND f2.is_audiobook = 0
+				  AND NOT EXISTS (SELECT 1 FROM hidden_duplicates hd2 WHERE hd2.track_id = t2.id)
+				  AND `+trackArtistSQL("t2")+`
 			), 0)
 		FROM tracks t
 		JOIN files f ON f.id = t.file_id
 		WHERE f.file_exists = 1
-		  AND LOWER(COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) = LOWER(?)
+		  AND f.is_audiobook = 0
+		  AND NOT EXISTS (SELECT 1 FROM hidden_duplicates hd WHERE hd.track_id = t.id)
+		  AND `+trackArtistSQL("t")+`
 	`, artistName, artistName).Scan(&trackCount, &albumCount); err != nil {
 		return ArtistDetail{}, fmt.Errorf("get artist totals for %q: %w", artistName, err)
 	}
@@ -436,7 +610,9 @@ func (r *BrowseRepository) GetArtistDetail(ctx context.Context, name string, lim
 		JOIN files f ON f.id = t.file_id
 		LEFT JOIN covers cover ON cover.id = a.cover_id
 		WHERE f.file_exists = 1
-		  AND LOWER(COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) = LOWER(?)
+		  AND f.is_audiobook = 0
+		  AND NOT EXISTS (SELECT 1 FROM hidden_duplicates hd WHERE hd.track_id = t.id)
+		  AND `+trackArtistSQL("t")+`
 		GROUP BY a.id, album_title, album_artist_name, a.year, cover.cache_path
 		ORDER BY LOWER(COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album'))
 		LIMIT ?
@@ -491,6 +667,7 @@ func (r *BrowseRepository) GetAlbumDetail(ctx context.Context, title string, alb
 	var detail AlbumDetail
 	var year sql.NullInt64
 	var coverPath sql.NullString
+	var albumRating sql.NullInt64
 	if err := r.db.QueryRowContext(ctx, `
 		SELECT
 			a.id,
@@ -498,7 +675,9 @@ func (r *BrowseRepository) GetAlbumDetail(ctx context.Context, title string, alb
 			COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist') AS album_artist_name,
 			a.year,
 			COALESCE(track_totals.track_count, 0) AS track_count,
-			cover.cache_path
+			cover.cache_path,
+			ar.rating,
+			COALESCE(ar.favorite, 0)
 		FROM albums a
 		LEFT JOIN (
 			SELECT at.album_id, COUNT(1) AS track_count
@@ -506,13 +685,15 @@ func (r *BrowseRepository) GetAlbumDetail(ctx context.Context, title string, alb
 			JOIN tracks t ON t.id = at.track_id
 			JOIN files f ON f.id = t.file_id
 			WHERE f.file_exists = 1
+			  AND f.is_audiobook = 0
+			  AND NOT EXISTS (SELECT 1 FROM hidden_duplicates hd WHERE hd.track_id = t.id)
 			GROUP BY at.album_id
 		) track_totals ON track_totals.album_id = a.id
-		LEFT JOIN covers cover ON cover.id = a.cover_id
+		LEFT JOIN covers cover ON cover.id = a.cover_id`+albumRatingJoinSQL+`
 		WHERE LOWER(COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album')) = LOWER(?)
 		  AND LOWER(COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist')) = LOWER(?)
 		LIMIT 1
-	`, albumTitle, artistName).Scan(&albumID, &detail.Title, &detail.AlbumArtist, &year, &detail.TrackCount, &coverPath); err != nil {
+	`, albumTitle, artistName).Scan(&albumID, &detail.Title, &detail.AlbumArtist, &year, &detail.TrackCount, &coverPath, &albumRating, &detail.Favorite); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return AlbumDetail{}, ErrAlbumNotFound
 		}
@@ -521,6 +702,7 @@ func (r *BrowseRepository) GetAlbumDetail(ctx context.Context, title string, alb
 
 	detail.Year = intPointer(year)
 	detail.CoverPath = stringPointer(coverPath)
+	detail.Rating = intPointer(albumRating)
 
 	limit, offset = normalizePagination(limit, offset, defaultDetailLimit)
 
@@ -535,13 +717,18 @@ func (r *BrowseRepository) GetAlbumDetail(ctx context.Context, title string, alb
 			t.track_no,
 			t.duration_ms,
 			f.path,
-			cover.cache_path
+			cover.cache_path,
+			tr.rating,
+			COALESCE(tr.favorite, 0)
 		FROM album_tracks at
 		JOIN tracks t ON t.id = at.track_id
 		JOIN files f ON f.id = t.file_id
 		LEFT JOIN covers cover ON cover.source_file_id = t.file_id
+		LEFT JOIN track_ratings tr ON tr.track_id = t.id
 		WHERE at.album_id = ?
 		  AND f.file_exists = 1
+		  AND f.is_audiobook = 0
+		  AND NOT EXISTS (SELECT 1 FROM hidden_duplicates hd WHERE hd.track_id = t.id)
 		ORDER BY
 			COALESCE(at.disc_no, t.disc_no, 0),
 			COALESCE(at.track_no, t.track_no, 0),
@@ -561,6 +748,7 @@ func (r *BrowseRepository) GetAlbumDetail(ctx context.Context, title string, alb
 		var trackNo sql.NullInt64
 		var durationMS sql.NullInt64
 		var coverPath sql.NullString
+		var ratingValue sql.NullInt64
 		if scanErr := rows.Scan(
 			&track.ID,
 			&track.Title,
@@ -572,6 +760,8 @@ func (r *BrowseRepository) GetAlbumDetail(ctx context.Context, title string, alb
 			&durationMS,
 			&track.Path,
 			&coverPath,
+			&ratingValue,
+			&track.Favorite,
 		); scanErr != nil {
 			return AlbumDetail{}, fmt.Errorf("scan album track row for %q by %q: %w", albumTitle, artistName, scanErr)
 		}
@@ -579,12 +769,20 @@ func (r *BrowseRepository) GetAlbumDetail(ctx context.Context, title string, alb
 		track.TrackNo = intPointer(trackNo)
 		track.DurationMS = intPointer(durationMS)
 		track.CoverPath = stringPointer(coverPath)
+		track.Rating = intPointer(ratingValue)
 		tracks = append(tracks, track)
 	}
 
 	if rowsErr := rows.Err(); rowsErr != nil {
 		return AlbumDetail{}, fmt.Errorf("iterate album tracks for %q by %q: %w", albumTitle, artistName, rowsErr)
 	}
+	rows.Close()
+
+	progress, err := r.albumProgress(ctx, albumID)
+	if err != nil {
+		return AlbumDetail{}, err
+	}
+	detail.Progress = progress
 
 	detail.Tracks = tracks
 	detail.Page = PageInfo{
@@ -674,7 +872,7 @@ func (r *BrowseRepository) GetArtistTopTracks(ctx context.Context, artist string
 					complete_count,
 					skip_count,
 					partial_count
-				FROM play_stats_daily
+				FROM play_stats_combined
 			) metrics
 			GROUP BY track_id
 		)
@@ -698,7 +896,9 @@ func (r *BrowseRepository) GetArtistTopTracks(ctx context.Context, artist string
 		JOIN files f ON f.id = t.file_id
 		LEFT JOIN covers cover ON cover.source_file_id = t.file_id
 		WHERE f.file_exists = 1
-		  AND LOWER(COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) = LOWER(?)
+		  AND f.is_audiobook = 0
+		  AND NOT EXISTS (SELECT 1 FROM hidden_duplicates hd WHERE hd.track_id = t.id)
+		  AND `+trackArtistSQL("t")+`
 		  AND (
 			tm.played_ms > 0
 			OR tm.complete_count > 0
@@ -818,6 +1018,8 @@ func (r *BrowseRepository) listAlbumTrackIDs(ctx context.Context, title string,
 		JOIN files f ON f.id = t.file_id
 		WHERE at.album_id = ?
 		  AND f.file_exists = 1
+		  AND f.is_audiobook = 0
+		  AND NOT EXISTS (SELECT 1 FROM hidden_duplicates hd WHERE hd.track_id = t.id)
 		ORDER BY
 			COALESCE(at.disc_no, t.disc_no, 0),
 			COALESCE(at.track_no, t.track_no, 0),
@@ -857,7 +1059,9 @@ func (r *BrowseRepository) listArtistTrackIDsByAlbumOrder(ctx context.Context, a
 		LEFT JOIN album_tracks at ON at.track_id = t.id
 		LEFT JOIN albums a ON a.id = at.album_id
 		WHERE f.file_exists = 1
-		  AND LOWER(COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) = LOWER(?)
+		  AND f.is_audiobook = 0
+		  AND NOT EXISTS (SELECT 1 FROM hidden_duplicates hd WHERE hd.track_id = t.id)
+		  AND `+trackArtistSQL("t")+`
 		ORDER BY
 			CASE WHEN a.year IS NULL THEN 1 ELSE 0 END,
 			a.year DESC,
@@ -911,7 +1115,7 @@ func (r *BrowseRepository) listArtistTrackIDsByStatsOrder(ctx context.Context, a
 					complete_count,
 					skip_count,
 					partial_count
-				FROM play_stats_daily
+				FROM play_stats_combined
 			) metrics
 			GROUP BY track_id
 		)
@@ -920,7 +1124,9 @@ func (r *BrowseRepository) listArtistTrackIDsByStatsOrder(ctx context.Context, a
 		JOIN tracks t ON t.id = tm.track_id
 		JOIN files f ON f.id = t.file_id
 		WHERE f.file_exists = 1
-		  AND LOWER(COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) = LOWER(?)
+		  AND f.is_audiobook = 0
+		  AND NOT EXISTS (SELECT 1 FROM hidden_duplicates hd WHERE hd.track_id = t.id)
+		  AND `+trackArtistSQL("t")+`
 		  AND (
 			tm.played_ms > 0
 			OR tm.complete_count > 0
@@ -1008,13 +1214,27 @@ func normalizePagination(limit int, offset int, defaultLimit int) (int, int) {
 	return limit, offset
 }
 
+// appendRatingFilter narrows a listing joined to a rating table under
+// alias to favorites and to ratings of at least filter.MinRating.
+func appendRatingFilter(whereClauses []string, args []any, alias string, filter RatingFilter) ([]string, []any) {
+	if filter.FavoritesOnly {
+		whereClauses = append(whereClauses, alias+".favorite = 1")
+	}
+	if filter.MinRating > 0 {
+		whereClauses = append(whereClauses, alias+".rating >= ?")
+		args = append(args, filter.MinRating)
+	}
+
+	return whereClauses, args
+}
+
 func makeSearchPattern(search string) string {
 	trimmed := strings.TrimSpace(search)
 	if trimmed == "" {
 		return ""
 	}
 
-	return "%" + strings.ToLower(trimmed) + "%"
+	return "%" + strings.ToLower(norm.NFKC.String(trimmed)) + "%"
 }
 
 func cloneArgs(args []any) []any {
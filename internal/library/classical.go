@@ -0,0 +1,73 @@
+package library
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// catalogNumberPattern matches opus and catalog numbers such as "Op. 27
+// No. 2", "BWV 1007", "K. 525" or "D 960". Catalog letters without a number
+// after them, as in "in D major", do not match.
+var catalogNumberPattern = regexp.MustCompile(`(?i)\b(op(?:us)?|bwv|kv|k|d|hwv|rv|woo|twv|sz|hob|s|l)\.?\s*([0-9]+)([a-z]?)\b(?:\s*,?\s*(?:no|nr)\.?\s*([0-9]+))?`)
+
+// movementPattern matches a movement written as a Roman numeral at the
+// start of a title or after the work, as in "Symphony No. 5: I. Allegro".
+var movementPattern = regexp.MustCompile(`(?:^|:\s*)([IVXL]+)\.\s`)
+
+var catalogAliases = map[string]string{"opus": "op", "kv": "k"}
+
+// CatalogSortKey returns a key that orders works by their catalog number,
+// numerically and with the number within an opus: "Op. 2" before "Op. 10",
+// "Op. 27 No. 1" before "Op. 27 No. 2". It reads the first text with a
+// catalog number and returns "" when none has one.
+func CatalogSortKey(texts ...string) string {
+	for _, text := range texts {
+		match := catalogNumberPattern.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+
+		catalog := strings.ToLower(match[1])
+		if alias, ok := catalogAliases[catalog]; ok {
+			catalog = alias
+		}
+		number, _ := strconv.Atoi(match[2])
+		subNumber := 0
+		if match[4] != "" {
+			subNumber, _ = strconv.Atoi(match[4])
+		}
+
+		return fmt.Sprintf("%s %06d%s %04d", catalog, number, strings.ToLower(match[3]), subNumber)
+	}
+
+	return ""
+}
+
+// MovementNumber reads the movement number from a title such as
+// "II. Adagio", for tracks without a MOVEMENTNUMBER tag. It returns 0 when
+// the title has none.
+func MovementNumber(title string) int {
+	match := movementPattern.FindStringSubmatch(title)
+	if match == nil {
+		return 0
+	}
+
+	return romanNumeral(match[1])
+}
+
+func romanNumeral(numeral string) int {
+	values := map[byte]int{'I': 1, 'V': 5, 'X': 10, 'L': 50}
+	total := 0
+	for index := 0; index < len(numeral); index++ {
+		value := values[numeral[index]]
+		if index+1 < len(numeral) && values[numeral[index+1]] > value {
+			total -= value
+		} else {
+			total += value
+		}
+	}
+
+	return total
+}
@@ -0,0 +1,351 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrComposerNotFound = errors.New("composer not found")
+
+var ErrWorkNotFound = errors.New("work not found")
+
+type ComposerSummary struct {
+	Name       string `json:"name"`
+	WorkCount  int    `json:"workCount"`
+	TrackCount int    `json:"trackCount"`
+	AlbumCount int    `json:"albumCount"`
+}
+
+type ComposersPage struct {
+	Items []ComposerSummary `json:"items"`
+	Page  PageInfo          `json:"page"`
+}
+
+// WorkSummary is a work of a composer; RecordingCount counts the albums it
+// is recorded on.
+type WorkSummary struct {
+	Title          string `json:"title"`
+	Composer       string `json:"composer"`
+	TrackCount     int    `json:"trackCount"`
+	RecordingCount int    `json:"recordingCount"`
+}
+
+// ComposerDetail pages through the works of a composer in catalog order.
+type ComposerDetail struct {
+	Name       string        `json:"name"`
+	WorkCount  int           `json:"workCount"`
+	TrackCount int           `json:"trackCount"`
+	AlbumCount int           `json:"albumCount"`
+	Works      []WorkSummary `json:"works"`
+	Page       PageInfo      `json:"page"`
+}
+
+// WorkRecording is one album's recording of a work, with its movements in
+// order.
+type WorkRecording struct {
+	AlbumTitle  string         `json:"albumTitle"`
+	AlbumArtist string         `json:"albumArtist"`
+	Conductor   *string        `json:"conductor,omitempty"`
+	Year        *int           `json:"year,omitempty"`
+	CoverPath   *string        `json:"coverPath,omitempty"`
+	Tracks      []TrackSummary `json:"tracks"`
+}
+
+type WorkDetail struct {
+	Title      string          `json:"title"`
+	Composer   string          `json:"composer"`
+	TrackCount int             `json:"trackCount"`
+	Recordings []WorkRecording `json:"recordings"`
+}
+
+// workTrackOrderSQL plays a work recording by recording, each by movement.
+const workTrackOrderSQL = `
+	COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album') COLLATE LOCALE,
+	COALESCE(NULLIF(TRIM(t.album_artist), ''), COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) COLLATE LOCALE,
+	CASE WHEN t.movement_no IS NULL THEN 1 ELSE 0 END,
+	t.movement_no,
+	COALESCE(t.disc_no, 0),
+	COALESCE(t.track_no, 0),
+	t.id`
+
+// ListComposers pages through the composers of playable tracks.
+func (r *BrowseRepository) ListComposers(ctx context.Context, search string, limit int, offset int) (ComposersPage, error) {
+	limit, offset = normalizePagination(limit, offset, defaultBrowseLimit)
+
+	whereClauses := []string{
+		"f.file_exists = 1",
+		"f.is_audiobook = 0",
+		"NULLIF(TRIM(t.composer), '') IS NOT NULL",
+	}
+	args := make([]any, 0, 1)
+	if pattern := makeSearchPattern(search); pattern != "" {
+		whereClauses = append(whereClauses, "LOWER(t.composer) LIKE ?")
+		args = append(args, pattern)
+	}
+	whereSQL := strings.Join(whereClauses, " AND ")
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, fmt.Sprintf(`
+		SELECT COUNT(1)
+		FROM (
+			SELECT 1
+			FROM tracks t
+			JOIN files f ON f.id = t.file_id
+			WHERE %s
+			GROUP BY t.composer COLLATE NOCASE
+		) composer_rows
+	`, whereSQL), args...).Scan(&total); err != nil {
+		return ComposersPage{}, fmt.Errorf("count composers: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			MIN(t.composer) AS composer_name,
+			COUNT(DISTINCT LOWER(NULLIF(TRIM(t.work), ''))),
+			COUNT(DISTINCT t.id),
+			COUNT(DISTINCT at.album_id)
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN album_tracks at ON at.track_id = t.id
+		WHERE %s
+		GROUP BY t.composer COLLATE NOCASE
+		ORDER BY composer_name COLLATE LOCALE
+		LIMIT ?
+		OFFSET ?
+	`, whereSQL), append(cloneArgs(args), limit, offset)...)
+	if err != nil {
+		return ComposersPage{}, fmt.Errorf("list composers: %w", err)
+	}
+	defer rows.Close()
+
+	composers := make([]ComposerSummary, 0)
+	for rows.Next() {
+		var composer ComposerSummary
+		if scanErr := rows.Scan(&composer.Name, &composer.WorkCount, &composer.TrackCount, &composer.AlbumCount); scanErr != nil {
+			return ComposersPage{}, fmt.Errorf("scan composer row: %w", scanErr)
+		}
+		composers = append(composers, composer)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return ComposersPage{}, fmt.Errorf("iterate composer rows: %w", rowsErr)
+	}
+
+	return ComposersPage{
+		Items: composers,
+		Page: PageInfo{
+			Limit:  limit,
+			Offset: offset,
+			Total:  total,
+		},
+	}, nil
+}
+
+// GetComposerDetail pages through the works of a composer, ordered by
+// opus or catalog number and then by title.
+func (r *BrowseRepository) GetComposerDetail(ctx context.Context, name string, limit int, offset int) (ComposerDetail, error) {
+	composerName := strings.TrimSpace(name)
+	if composerName == "" {
+		return ComposerDetail{}, errors.New("composer name is required")
+	}
+
+	detail := ComposerDetail{Name: composerName}
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT
+			COALESCE(MIN(t.composer), ?),
+			COUNT(DISTINCT LOWER(NULLIF(TRIM(t.work), ''))),
+			COUNT(DISTINCT t.id),
+			COUNT(DISTINCT at.album_id)
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN album_tracks at ON at.track_id = t.id
+		WHERE t.composer = ? COLLATE NOCASE
+		  AND f.file_exists = 1
+		  AND f.is_audiobook = 0
+	`, composerName, composerName).Scan(&detail.Name, &detail.WorkCount, &detail.TrackCount, &detail.AlbumCount); err != nil {
+		return ComposerDetail{}, fmt.Errorf("count composer %q: %w", composerName, err)
+	}
+	if detail.TrackCount == 0 {
+		return ComposerDetail{}, ErrComposerNotFound
+	}
+
+	limit, offset = normalizePagination(limit, offset, defaultDetailLimit)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			MIN(t.work) AS work_title,
+			COUNT(DISTINCT t.id),
+			COUNT(DISTINCT at.album_id)
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN album_tracks at ON at.track_id = t.id
+		WHERE t.composer = ? COLLATE NOCASE
+		  AND NULLIF(TRIM(t.work), '') IS NOT NULL
+		  AND f.file_exists = 1
+		  AND f.is_audiobook = 0
+		GROUP BY t.work COLLATE NOCASE
+		ORDER BY
+			CASE WHEN MIN(t.catalog_key) IS NULL THEN 1 ELSE 0 END,
+			MIN(t.catalog_key),
+			work_title COLLATE LOCALE
+		LIMIT ?
+		OFFSET ?
+	`, composerName, limit, offset)
+	if err != nil {
+		return ComposerDetail{}, fmt.Errorf("list works of composer %q: %w", composerName, err)
+	}
+	defer rows.Close()
+
+	works := make([]WorkSummary, 0)
+	for rows.Next() {
+		work := WorkSummary{Composer: detail.Name}
+		if scanErr := rows.Scan(&work.Title, &work.TrackCount, &work.RecordingCount); scanErr != nil {
+			return ComposerDetail{}, fmt.Errorf("scan work of composer %q: %w", composerName, scanErr)
+		}
+		works = append(works, work)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return ComposerDetail{}, fmt.Errorf("iterate works of composer %q: %w", composerName, rowsErr)
+	}
+
+	detail.Works = works
+	detail.Page = PageInfo{
+		Limit:  limit,
+		Offset: offset,
+		Total:  detail.WorkCount,
+	}
+
+	return detail, nil
+}
+
+// GetWorkDetail lists the recordings of a work, one per album, with their
+// movements in order.
+func (r *BrowseRepository) GetWorkDetail(ctx context.Context, composer string, work string) (WorkDetail, error) {
+	composerName := strings.TrimSpace(composer)
+	workTitle := strings.TrimSpace(work)
+	if composerName == "" || workTitle == "" {
+		return WorkDetail{}, errors.New("composer and work are required")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			t.id,
+			t.composer,
+			t.work,
+			COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album') AS album_title,
+			COALESCE(NULLIF(TRIM(t.album_artist), ''), COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) AS album_artist_name,
+			NULLIF(TRIM(t.conductor), ''),
+			t.year,
+			cover.cache_path
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN covers cover ON cover.source_file_id = t.file_id
+		WHERE t.composer = ? COLLATE NOCASE
+		  AND t.work = ? COLLATE NOCASE
+		  AND f.file_exists = 1
+		  AND f.is_audiobook = 0
+		ORDER BY `+workTrackOrderSQL+`
+	`, composerName, workTitle)
+	if err != nil {
+		return WorkDetail{}, fmt.Errorf("list recordings of work %q: %w", workTitle, err)
+	}
+	defer rows.Close()
+
+	detail := WorkDetail{Title: workTitle, Composer: composerName, Recordings: []WorkRecording{}}
+	trackIDs := make([]int64, 0)
+	recordingOf := make(map[int64]int)
+	for rows.Next() {
+		var trackID int64
+		var recording WorkRecording
+		var conductor sql.NullString
+		var year sql.NullInt64
+		var coverPath sql.NullString
+		if scanErr := rows.Scan(&trackID, &detail.Composer, &detail.Title, &recording.AlbumTitle, &recording.AlbumArtist, &conductor, &year, &coverPath); scanErr != nil {
+			return WorkDetail{}, fmt.Errorf("scan recording of work %q: %w", workTitle, scanErr)
+		}
+
+		last := len(detail.Recordings) - 1
+		if last < 0 || detail.Recordings[last].AlbumTitle != recording.AlbumTitle || detail.Recordings[last].AlbumArtist != recording.AlbumArtist {
+			recording.Conductor = stringPointer(conductor)
+			recording.Year = intPointer(year)
+			recording.CoverPath = stringPointer(coverPath)
+			recording.Tracks = []TrackSummary{}
+			detail.Recordings = append(detail.Recordings, recording)
+			last++
+		} else if detail.Recordings[last].CoverPath == nil {
+			detail.Recordings[last].CoverPath = stringPointer(coverPath)
+		}
+		trackIDs = append(trackIDs, trackID)
+		recordingOf[trackID] = last
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return WorkDetail{}, fmt.Errorf("iterate recordings of work %q: %w", workTitle, rowsErr)
+	}
+	rows.Close()
+
+	if len(trackIDs) == 0 {
+		return WorkDetail{}, ErrWorkNotFound
+	}
+
+	page, err := r.listTracks(ctx, trackFilter{trackIDs: trackIDs}, len(trackIDs), 0, false)
+	if err != nil {
+		return WorkDetail{}, err
+	}
+	byID := make(map[int64]TrackSummary, len(page.Items))
+	for _, track := range page.Items {
+		byID[track.ID] = track
+	}
+	for _, trackID := range trackIDs {
+		if track, ok := byID[trackID]; ok {
+			recording := &detail.Recordings[recordingOf[trackID]]
+			recording.Tracks = append(recording.Tracks, track)
+		}
+	}
+	detail.TrackCount = len(trackIDs)
+
+	return detail, nil
+}
+
+// GetWorkQueueTrackIDs returns the tracks of a work, recording by
+// recording in movement order, for playing a whole work.
+func (r *BrowseRepository) GetWorkQueueTrackIDs(ctx context.Context, composer string, work string) ([]int64, error) {
+	composerName := strings.TrimSpace(composer)
+	workTitle := strings.TrimSpace(work)
+	if composerName == "" || workTitle == "" {
+		return nil, errors.New("composer and work are required")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.id
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		WHERE t.composer = ? COLLATE NOCASE
+		  AND t.work = ? COLLATE NOCASE
+		  AND f.file_exists = 1
+		  AND f.is_audiobook = 0
+		ORDER BY `+workTrackOrderSQL+`
+	`, composerName, workTitle)
+	if err != nil {
+		return nil, fmt.Errorf("list queue tracks of work %q: %w", workTitle, err)
+	}
+	defer rows.Close()
+
+	trackIDs := make([]int64, 0)
+	for rows.Next() {
+		var trackID int64
+		if scanErr := rows.Scan(&trackID); scanErr != nil {
+			return nil, fmt.Errorf("scan queue track of work %q: %w", workTitle, scanErr)
+		}
+		trackIDs = append(trackIDs, trackID)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate queue tracks of work %q: %w", workTitle, rowsErr)
+	}
+	if len(trackIDs) == 0 {
+		return nil, ErrWorkNotFound
+	}
+
+	return trackIDs, nil
+}
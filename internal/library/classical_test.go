@@ -0,0 +1,46 @@
+package library
+
+import "testing"
+
+func TestCatalogSortKey(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"Piano Sonata No. 14 in C-sharp minor, Op. 27 No. 2": "op 000027 0002",
+		"Symphony No. 5 in C minor, Opus 67":                 "op 000067 0000",
+		"Cello Suite No. 1, BWV 1007":                        "bwv 001007 0000",
+		"Eine kleine Nachtmusik, KV 525":                     "k 000525 0000",
+		"Piano Sonata in B-flat major, D. 960":               "d 000960 0000",
+		"Etudes, Op. 10a":                                    "op 000010a 0000",
+		"Symphony in D major":                                "",
+	}
+	for text, want := range cases {
+		if got := CatalogSortKey(text); got != want {
+			t.Fatalf("expected %q for %q, got %q", want, text, got)
+		}
+	}
+
+	if CatalogSortKey("Op. 2") >= CatalogSortKey("Op. 10") {
+		t.Fatal("expected Op. 2 to sort before Op. 10")
+	}
+	if got := CatalogSortKey("", "Sonata, Op. 13"); got != "op 000013 0000" {
+		t.Fatalf("expected the first text with a catalog number, got %q", got)
+	}
+}
+
+func TestMovementNumber(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]int{
+		"II. Adagio sostenuto":                     2,
+		"Symphony No. 5: IV. Allegro":              4,
+		"Piano Sonata No. 14: XIV. Presto agitato": 14,
+		"Isolde's Liebestod":                       0,
+		"IX":                                       0,
+	}
+	for title, want := range cases {
+		if got := MovementNumber(title); got != want {
+			t.Fatalf("expected %d for %q, got %d", want, title, got)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rzxx/ben/internal/palette"
+)
+
+// AlbumCover is the cover an album key resolves to. CoverID is 0 for
+// albums without a cover.
+type AlbumCover struct {
+	Title       string `json:"title"`
+	AlbumArtist string `json:"albumArtist"`
+	CoverID     int64  `json:"coverId"`
+	CachePath   string `json:"cachePath"`
+}
+
+// GetAlbumCovers resolves album keys to their covers, in the order given.
+func (r *BrowseRepository) GetAlbumCovers(ctx context.Context, keys []AlbumKey) ([]AlbumCover, error) {
+	if len(keys) > MaxAlbumCoverPreviewBatch {
+		return nil, ErrTooManyAlbumKeys
+	}
+
+	covers := make([]AlbumCover, len(keys))
+	if len(keys) == 0 {
+		return covers, nil
+	}
+
+	valueRows := make([]string, 0, len(keys))
+	args := make([]any, 0, len(keys)*3)
+	for index, key := range keys {
+		title := strings.TrimSpace(key.Title)
+		artistName := strings.TrimSpace(key.AlbumArtist)
+		if title == "" {
+			return nil, errors.New("album title is required")
+		}
+		if artistName == "" {
+			return nil, errors.New("album artist is required")
+		}
+
+		covers[index] = AlbumCover{Title: title, AlbumArtist: artistName}
+		valueRows = append(valueRows, "(?, ?, ?)")
+		args = append(args, index, title, artistName)
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		WITH requested(position, title, album_artist) AS (
+			VALUES %s
+		)
+		SELECT requested.position, cover.id, cover.cache_path
+		FROM requested
+		JOIN albums a
+			ON LOWER(COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album')) = LOWER(requested.title)
+			AND LOWER(COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist')) = LOWER(requested.album_artist)
+		JOIN covers cover ON cover.id = a.cover_id
+		WHERE cover.cache_path IS NOT NULL AND TRIM(cover.cache_path) <> ''
+	`, strings.Join(valueRows, ", ")), args...)
+	if err != nil {
+		return nil, fmt.Errorf("query album covers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var position int
+		var coverID int64
+		var cachePath string
+		if scanErr := rows.Scan(&position, &coverID, &cachePath); scanErr != nil {
+			return nil, fmt.Errorf("scan album cover row: %w", scanErr)
+		}
+		if position < 0 || position >= len(covers) {
+			continue
+		}
+		covers[position].CoverID = coverID
+		covers[position].CachePath = cachePath
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate album cover rows: %w", rowsErr)
+	}
+
+	return covers, nil
+}
+
+// GetCoverPalettes returns the stored palettes of covers whose image has
+// not changed since the palette was made.
+func (r *BrowseRepository) GetCoverPalettes(ctx context.Context, coverIDs []int64) (map[int64]palette.ThemePalette, error) {
+	palettes := make(map[int64]palette.ThemePalette, len(coverIDs))
+	if len(coverIDs) == 0 {
+		return palettes, nil
+	}
+
+	args := make([]any, 0, len(coverIDs))
+	for _, coverID := range coverIDs {
+		args = append(args, coverID)
+	}
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT cp.cover_id, cp.palette_json
+		FROM cover_palettes cp
+		JOIN covers cover ON cover.id = cp.cover_id
+		WHERE cp.cover_id IN (%s)
+		  AND cp.cover_hash IS cover.hash
+	`, sqlPlaceholders(len(coverIDs))), args...)
+	if err != nil {
+		return nil, fmt.Errorf("query cover palettes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var coverID int64
+		var paletteJSON string
+		if scanErr := rows.Scan(&coverID, &paletteJSON); scanErr != nil {
+			return nil, fmt.Errorf("scan cover palette row: %w", scanErr)
+		}
+		var stored palette.ThemePalette
+		if json.Unmarshal([]byte(paletteJSON), &stored) == nil {
+			palettes[coverID] = stored
+		}
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate cover palette rows: %w", rowsErr)
+	}
+
+	return palettes, nil
+}
+
+// SaveCoverPalette stores the default-options palette of a cover.
+func (r *BrowseRepository) SaveCoverPalette(ctx context.Context, coverID int64, themePalette palette.ThemePalette) error {
+	encoded, err := json.Marshal(themePalette)
+	if err != nil {
+		return fmt.Errorf("encode cover palette: %w", err)
+	}
+
+	var hue, lightness, chroma sql.NullFloat64
+	if primary := themePalette.Primary; primary != nil {
+		hue = sql.NullFloat64{Float64: primary.Hue, Valid: true}
+		lightness = sql.NullFloat64{Float64: primary.Lightness, Valid: true}
+		chroma = sql.NullFloat64{Float64: primary.Chroma, Valid: true}
+	}
+
+	if _, err := r.writer.ExecContext(ctx, `
+		INSERT INTO cover_palettes(cover_id, cover_hash, palette_json, primary_hue, primary_lightness, primary_chroma, created_at)
+		SELECT id, hash, ?, ?, ?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+		FROM covers
+		WHERE id = ?
+		ON CONFLICT(cover_id) DO UPDATE SET
+			cover_hash = excluded.cover_hash,
+			palette_json = excluded.palette_json,
+			primary_hue = excluded.primary_hue,
+			primary_lightness = excluded.primary_lightness,
+			primary_chroma = excluded.primary_chroma,
+			created_at = excluded.created_at
+	`, string(encoded), hue, lightness, chroma, coverID); err != nil {
+		return fmt.Errorf("save palette for cover %d: %w", coverID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/rzxx/ben/internal/coverart"
+)
+
+// MaxAlbumCoverPreviewBatch caps how many albums one preview request may ask
+// for, which keeps the statement well under SQLite's variable limit.
+const MaxAlbumCoverPreviewBatch = 200
+
+var ErrTooManyAlbumKeys = fmt.Errorf("at most %d album keys per request", MaxAlbumCoverPreviewBatch)
+
+type AlbumKey struct {
+	Title       string `json:"title"`
+	AlbumArtist string `json:"albumArtist"`
+}
+
+// AlbumCoverPreview carries what a grid cell needs before the real thumbnail
+// loads. Fields stay empty for albums without a cover or whose placeholder
+// has not been computed yet.
+type AlbumCoverPreview struct {
+	Title         string  `json:"title"`
+	AlbumArtist   string  `json:"albumArtist"`
+	ThumbnailURL  *string `json:"thumbnailUrl,omitempty"`
+	DominantColor *string `json:"dominantColor,omitempty"`
+	BlurHash      *string `json:"blurHash,omitempty"`
+}
+
+// GetAlbumCoverPreviews returns one preview per key, in the order given.
+func (r *BrowseRepository) GetAlbumCoverPreviews(ctx context.Context, keys []AlbumKey) ([]AlbumCoverPreview, error) {
+	if len(keys) > MaxAlbumCoverPreviewBatch {
+		return nil, ErrTooManyAlbumKeys
+	}
+
+	previews := make([]AlbumCoverPreview, len(keys))
+	if len(keys) == 0 {
+		return previews, nil
+	}
+
+	valueRows := make([]string, 0, len(keys))
+	args := make([]any, 0, len(keys)*3)
+	for index, key := range keys {
+		title := strings.TrimSpace(key.Title)
+		artistName := strings.TrimSpace(key.AlbumArtist)
+		if title == "" {
+			return nil, errors.New("album title is required")
+		}
+		if artistName == "" {
+			return nil, errors.New("album artist is required")
+		}
+
+		previews[index] = AlbumCoverPreview{Title: title, AlbumArtist: artistName}
+		valueRows = append(valueRows, "(?, ?, ?)")
+		args = append(args, index, title, artistName)
+	}
+
+	query := fmt.Sprintf(`
+		WITH requested(position, title, album_artist) AS (
+			VALUES %s
+		)
+		SELECT
+			requested.position,
+			cover.cache_path,
+			cover.dominant_color,
+			cover.blurhash
+		FROM requested
+		JOIN albums a
+			ON LOWER(COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album')) = LOWER(requested.title)
+			AND LOWER(COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist')) = LOWER(requested.album_artist)
+		JOIN covers cover ON cover.id = a.cover_id
+	`, strings.Join(valueRows, ", "))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query album cover previews: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var position int
+		var cachePath sql.NullString
+		var dominantColor sql.NullString
+		var blurHash sql.NullString
+		if scanErr := rows.Scan(&position, &cachePath, &dominantColor, &blurHash); scanErr != nil {
+			return nil, fmt.Errorf("scan album cover preview row: %w", scanErr)
+		}
+		if position < 0 || position >= len(previews) {
+			continue
+		}
+
+		preview := &previews[position]
+		preview.ThumbnailURL = thumbnailURL(cachePath)
+		preview.DominantColor = stringPointer(dominantColor)
+		preview.BlurHash = stringPointer(blurHash)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate album cover preview rows: %w", rowsErr)
+	}
+
+	return previews, nil
+}
+
+// thumbnailURL points at the smallest variant served by the covers route.
+func thumbnailURL(cachePath sql.NullString) *string {
+	path := strings.TrimSpace(cachePath.String)
+	if !cachePath.Valid || path == "" {
+		return nil
+	}
+
+	value := "/covers?path=" + url.QueryEscape(path) + "&variant=" + coverart.VariantPlayer
+	return &value
+}
@@ -0,0 +1,158 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+const defaultCoverReportLimit = 100
+
+// CoverReport helps curate artwork. SharedCovers lists covers used by more
+// than one album, which often means wrong or placeholder art; AlbumsWithout
+// lists albums with no cover. The counts cover the whole library, while the
+// lists are capped by the report limit.
+type CoverReport struct {
+	SharedCovers       []SharedCover `json:"sharedCovers"`
+	SharedCoverCount   int           `json:"sharedCoverCount"`
+	SharedAlbumCount   int           `json:"sharedAlbumCount"`
+	AlbumsWithout      []AlbumKey    `json:"albumsWithout"`
+	AlbumsWithoutCount int           `json:"albumsWithoutCount"`
+}
+
+// SharedCover is one cover image and the albums using it, in artist and
+// title order.
+type SharedCover struct {
+	Hash         string     `json:"hash"`
+	ThumbnailURL *string    `json:"thumbnailUrl,omitempty"`
+	Albums       []AlbumKey `json:"albums"`
+}
+
+// GetCoverReport lists covers shared by several albums, most shared first,
+// and albums without a cover. limit caps each list.
+func (r *BrowseRepository) GetCoverReport(ctx context.Context, limit int) (CoverReport, error) {
+	if limit <= 0 {
+		limit = defaultCoverReportLimit
+	}
+
+	report := CoverReport{SharedCovers: make([]SharedCover, 0), AlbumsWithout: make([]AlbumKey, 0)}
+	if err := r.readSharedCovers(ctx, limit, &report); err != nil {
+		return CoverReport{}, err
+	}
+	if err := r.readAlbumsWithoutCovers(ctx, limit, &report); err != nil {
+		return CoverReport{}, err
+	}
+
+	return report, nil
+}
+
+func (r *BrowseRepository) readSharedCovers(ctx context.Context, limit int, report *CoverReport) error {
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(1), COALESCE(SUM(album_count), 0)
+		FROM (
+			SELECT COUNT(DISTINCT a.id) AS album_count
+			FROM albums a
+			JOIN covers cover ON cover.id = a.cover_id
+			WHERE cover.hash IS NOT NULL AND cover.hash <> ''
+			GROUP BY cover.hash
+			HAVING COUNT(DISTINCT a.id) > 1
+		)
+	`).Scan(&report.SharedCoverCount, &report.SharedAlbumCount); err != nil {
+		return fmt.Errorf("count shared covers: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		WITH shared AS (
+			SELECT cover.hash, COUNT(DISTINCT a.id) AS album_count, MIN(cover.cache_path) AS cache_path
+			FROM albums a
+			JOIN covers cover ON cover.id = a.cover_id
+			WHERE cover.hash IS NOT NULL AND cover.hash <> ''
+			GROUP BY cover.hash
+			HAVING COUNT(DISTINCT a.id) > 1
+			ORDER BY album_count DESC, cover.hash
+			LIMIT ?
+		)
+		SELECT DISTINCT
+			shared.hash,
+			shared.album_count,
+			shared.cache_path,
+			COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album'),
+			COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist')
+		FROM shared
+		JOIN covers cover ON cover.hash = shared.hash
+		JOIN albums a ON a.cover_id = cover.id
+		ORDER BY shared.album_count DESC, shared.hash,
+			COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist') COLLATE LOCALE,
+			COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album') COLLATE LOCALE
+	`, limit)
+	if err != nil {
+		return fmt.Errorf("list shared covers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hash string
+		var albumCount int
+		var cachePath sql.NullString
+		var album AlbumKey
+		if scanErr := rows.Scan(&hash, &albumCount, &cachePath, &album.Title, &album.AlbumArtist); scanErr != nil {
+			return fmt.Errorf("scan shared cover row: %w", scanErr)
+		}
+
+		last := len(report.SharedCovers) - 1
+		if last < 0 || report.SharedCovers[last].Hash != hash {
+			report.SharedCovers = append(report.SharedCovers, SharedCover{
+				Hash:         hash,
+				ThumbnailURL: thumbnailURL(cachePath),
+				Albums:       make([]AlbumKey, 0, albumCount),
+			})
+			last++
+		}
+		report.SharedCovers[last].Albums = append(report.SharedCovers[last].Albums, album)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return fmt.Errorf("iterate shared cover rows: %w", rowsErr)
+	}
+
+	return nil
+}
+
+func (r *BrowseRepository) readAlbumsWithoutCovers(ctx context.Context, limit int, report *CoverReport) error {
+	const withoutCoverSQL = `
+		FROM albums a
+		LEFT JOIN covers cover ON cover.id = a.cover_id
+		WHERE cover.id IS NULL OR cover.cache_path IS NULL OR TRIM(cover.cache_path) = ''
+	`
+
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(1) "+withoutCoverSQL).Scan(&report.AlbumsWithoutCount); err != nil {
+		return fmt.Errorf("count albums without covers: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album'),
+			COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist')
+		`+withoutCoverSQL+`
+		ORDER BY `+albumArtistTitleOrderSQL+`
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return fmt.Errorf("list albums without covers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var album AlbumKey
+		if scanErr := rows.Scan(&album.Title, &album.AlbumArtist); scanErr != nil {
+			return fmt.Errorf("scan album without cover row: %w", scanErr)
+		}
+		report.AlbumsWithout = append(report.AlbumsWithout, album)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return fmt.Errorf("iterate albums without cover rows: %w", rowsErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,559 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	// DuplicateMatchFile marks tracks whose files are identical copies.
+	DuplicateMatchFile = "file"
+	// DuplicateMatchFingerprint marks tracks with the same Chromaprint
+	// fingerprint, stored when fingerprinting during scans is on.
+	DuplicateMatchFingerprint = "fingerprint"
+	// DuplicateMatchMetadata marks tracks with the same title and artist
+	// and durations within the tolerance, e.g. an MP3 and a FLAC rip.
+	DuplicateMatchMetadata = "metadata"
+)
+
+const (
+	defaultDuplicateToleranceMS = 2000
+	maxDuplicateToleranceMS     = 30000
+)
+
+type DuplicateTrack struct {
+	TrackID    int64  `json:"trackId"`
+	Title      string `json:"title"`
+	Artist     string `json:"artist"`
+	Album      string `json:"album"`
+	Path       string `json:"path"`
+	DurationMS int    `json:"durationMs"`
+	Codec      string `json:"codec"`
+	Bitrate    int    `json:"bitrate"`
+	SampleRate int    `json:"sampleRate"`
+	BitDepth   int    `json:"bitDepth"`
+	Lossless   bool   `json:"lossless"`
+	Hidden     bool   `json:"hidden"`
+}
+
+// Quality is what the versions of a group are ranked by.
+func (t DuplicateTrack) Quality() AudioQuality {
+	return AudioQuality{Lossless: t.Lossless, BitDepth: t.BitDepth, SampleRate: t.SampleRate, Bitrate: t.Bitrate}
+}
+
+// DuplicateGroup is a set of tracks that are the same recording.
+// PreferredTrackID is the best version, the one kept by KeepBestDuplicates.
+type DuplicateGroup struct {
+	MatchedBy        []string         `json:"matchedBy"`
+	PreferredTrackID int64            `json:"preferredTrackId"`
+	Tracks           []DuplicateTrack `json:"tracks"`
+}
+
+// DuplicateReport lists the duplicate groups of the library. Duplicates
+// counts the tracks beyond the preferred one of each group.
+type DuplicateReport struct {
+	ToleranceMS int              `json:"toleranceMs"`
+	Duplicates  int              `json:"duplicates"`
+	Hidden      int              `json:"hidden"`
+	Groups      []DuplicateGroup `json:"groups"`
+}
+
+type duplicateCandidate struct {
+	track       DuplicateTrack
+	quickHash   string
+	fingerprint string
+}
+
+type DuplicateFinder struct {
+	db *sql.DB
+}
+
+func NewDuplicateFinder(database *sql.DB) *DuplicateFinder {
+	return &DuplicateFinder{db: database}
+}
+
+// FindDuplicates groups tracks whose files are identical or share a
+// fingerprint, or whose title and artist match and whose durations differ
+// by at most toleranceMS. Hidden duplicates are part of the report so they
+// can be shown again.
+func (f *DuplicateFinder) FindDuplicates(ctx context.Context, toleranceMS int) (DuplicateReport, error) {
+	toleranceMS = normalizeDuplicateTolerance(toleranceMS)
+
+	candidates, err := f.listCandidates(ctx, "", "")
+	if err != nil {
+		return DuplicateReport{}, err
+	}
+
+	report := DuplicateReport{ToleranceMS: toleranceMS, Groups: groupDuplicates(candidates, toleranceMS)}
+	for _, group := range report.Groups {
+		report.Duplicates += len(group.Tracks) - 1
+		for _, track := range group.Tracks {
+			if track.Hidden {
+				report.Hidden++
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// DuplicatesOf returns the duplicate groups, at the default tolerance, that
+// contain one of trackIDs. Only the tracks sharing a file, fingerprint or
+// title and artist with trackIDs are read, so it is cheap enough to run
+// whenever a queue is built.
+func (f *DuplicateFinder) DuplicatesOf(ctx context.Context, trackIDs []int64) ([]DuplicateGroup, error) {
+	groups := make([]DuplicateGroup, 0)
+	if len(trackIDs) == 0 {
+		return groups, nil
+	}
+
+	requested := make(map[int64]struct{}, len(trackIDs))
+	args := make([]any, 0, len(trackIDs))
+	for _, trackID := range trackIDs {
+		requested[trackID] = struct{}{}
+		args = append(args, trackID)
+	}
+
+	with := fmt.Sprintf(`
+		WITH requested AS (
+			SELECT
+				NULLIF(rf.hash_quick, '') AS hash_quick,
+				NULLIF(rf.fingerprint, '') AS fingerprint,
+				LOWER(NULLIF(TRIM(rt.title), '')) AS title,
+				LOWER(COALESCE(NULLIF(TRIM(rt.artist), ''), 'Unknown Artist')) AS artist
+			FROM tracks rt
+			JOIN files rf ON rf.id = rt.file_id
+			WHERE rt.id IN (%s)
+		)
+	`, sqlPlaceholders(len(trackIDs)))
+	filter := `AND (
+			f.hash_quick IN (SELECT hash_quick FROM requested)
+			OR f.fingerprint IN (SELECT fingerprint FROM requested)
+			OR (LOWER(TRIM(t.title)), LOWER(COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist'))) IN (SELECT title, artist FROM requested)
+		  )`
+	candidates, err := f.listCandidates(ctx, with, filter, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groupDuplicates(candidates, defaultDuplicateToleranceMS) {
+		if slices.ContainsFunc(group.Tracks, func(track DuplicateTrack) bool {
+			_, ok := requested[track.TrackID]
+			return ok
+		}) {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups, nil
+}
+
+// listCandidates reads the present music tracks that can be duplicates.
+// with is put before the query and filter adds conditions on the tracks t
+// and files f; both may be empty.
+func (f *DuplicateFinder) listCandidates(ctx context.Context, with string, filter string, args ...any) ([]duplicateCandidate, error) {
+	rows, err := f.db.QueryContext(ctx, with+fmt.Sprintf(`
+		SELECT
+			t.id,
+			COALESCE(NULLIF(TRIM(t.title), ''), ''),
+			COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist'),
+			COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album'),
+			f.path,
+			COALESCE(t.duration_ms, 0),
+			LOWER(COALESCE(TRIM(t.codec), '')),
+			COALESCE(t.bitrate, 0),
+			COALESCE(t.sample_rate, 0),
+			COALESCE(t.bit_depth, 0),
+			hd.track_id IS NOT NULL,
+			COALESCE(f.hash_quick, ''),
+			COALESCE(f.fingerprint, '')
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN hidden_duplicates hd ON hd.track_id = t.id
+		WHERE f.file_exists = 1
+		  AND f.is_audiobook = 0
+		  %s
+		ORDER BY t.id
+	`, filter), args...)
+	if err != nil {
+		return nil, fmt.Errorf("list duplicate candidates: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := make([]duplicateCandidate, 0)
+	for rows.Next() {
+		var candidate duplicateCandidate
+		track := &candidate.track
+		if scanErr := rows.Scan(
+			&track.TrackID,
+			&track.Title,
+			&track.Artist,
+			&track.Album,
+			&track.Path,
+			&track.DurationMS,
+			&track.Codec,
+			&track.Bitrate,
+			&track.SampleRate,
+			&track.BitDepth,
+			&track.Hidden,
+			&candidate.quickHash,
+			&candidate.fingerprint,
+		); scanErr != nil {
+			return nil, fmt.Errorf("scan duplicate candidate: %w", scanErr)
+		}
+		track.Lossless = IsLosslessCodec(track.Codec)
+		candidates = append(candidates, candidate)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate duplicate candidates: %w", rowsErr)
+	}
+
+	return candidates, nil
+}
+
+// HideDuplicates hides trackIDs from browsing as duplicates of keptTrackID,
+// which is shown again if it was hidden itself. It returns how many tracks
+// were hidden.
+func (f *DuplicateFinder) HideDuplicates(ctx context.Context, keptTrackID int64, trackIDs []int64) (int, error) {
+	if keptTrackID <= 0 {
+		return 0, errors.New("kept track is required")
+	}
+
+	tx, err := f.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin hide duplicates tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	hidden, err := hideDuplicates(ctx, tx, keptTrackID, trackIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit hidden duplicates: %w", err)
+	}
+
+	return hidden, nil
+}
+
+// ShowDuplicates makes hidden duplicates browsable again.
+func (f *DuplicateFinder) ShowDuplicates(ctx context.Context, trackIDs []int64) error {
+	if len(trackIDs) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(trackIDs))
+	for _, trackID := range trackIDs {
+		args = append(args, trackID)
+	}
+	if _, err := f.db.ExecContext(
+		ctx,
+		fmt.Sprintf("DELETE FROM hidden_duplicates WHERE track_id IN (%s)", sqlPlaceholders(len(trackIDs))),
+		args...,
+	); err != nil {
+		return fmt.Errorf("show duplicates: %w", err)
+	}
+
+	return nil
+}
+
+// HiddenDuplicate is a track hidden from browsing as a duplicate of
+// KeptTrackID.
+type HiddenDuplicate struct {
+	TrackID     int64
+	KeptTrackID int64
+}
+
+// ListHiddenDuplicates returns every hidden duplicate, so a change to them
+// can be undone with RestoreHiddenDuplicates.
+func (f *DuplicateFinder) ListHiddenDuplicates(ctx context.Context) ([]HiddenDuplicate, error) {
+	rows, err := f.db.QueryContext(ctx, "SELECT track_id, kept_track_id FROM hidden_duplicates ORDER BY track_id")
+	if err != nil {
+		return nil, fmt.Errorf("query hidden duplicates: %w", err)
+	}
+	defer rows.Close()
+
+	hidden := make([]HiddenDuplicate, 0)
+	for rows.Next() {
+		var duplicate HiddenDuplicate
+		if err := rows.Scan(&duplicate.TrackID, &duplicate.KeptTrackID); err != nil {
+			return nil, fmt.Errorf("scan hidden duplicate: %w", err)
+		}
+		hidden = append(hidden, duplicate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate hidden duplicates: %w", err)
+	}
+
+	return hidden, nil
+}
+
+// RestoreHiddenDuplicates replaces the hidden duplicates with hidden.
+// Tracks removed from the library since are skipped.
+func (f *DuplicateFinder) RestoreHiddenDuplicates(ctx context.Context, hidden []HiddenDuplicate) error {
+	tx, err := f.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin restore hidden duplicates tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM hidden_duplicates"); err != nil {
+		return fmt.Errorf("clear hidden duplicates: %w", err)
+	}
+	for _, duplicate := range hidden {
+		if _, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO hidden_duplicates(track_id, kept_track_id)
+			 SELECT ?, ?
+			 WHERE EXISTS (SELECT 1 FROM tracks WHERE id = ?)
+			   AND EXISTS (SELECT 1 FROM tracks WHERE id = ?)`,
+			duplicate.TrackID,
+			duplicate.KeptTrackID,
+			duplicate.TrackID,
+			duplicate.KeptTrackID,
+		); err != nil {
+			return fmt.Errorf("restore hidden duplicate %d: %w", duplicate.TrackID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit restored hidden duplicates: %w", err)
+	}
+
+	return nil
+}
+
+// KeepBestDuplicates hides every track of each duplicate group except its
+// preferred version: lossless first, then the higher bit depth, sample rate
+// and bitrate. It returns how many tracks were hidden.
+func (f *DuplicateFinder) KeepBestDuplicates(ctx context.Context, toleranceMS int) (int, error) {
+	report, err := f.FindDuplicates(ctx, toleranceMS)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := f.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin keep best duplicates tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	hidden := 0
+	for _, group := range report.Groups {
+		trackIDs := make([]int64, 0, len(group.Tracks))
+		for _, track := range group.Tracks {
+			trackIDs = append(trackIDs, track.TrackID)
+		}
+
+		count, err := hideDuplicates(ctx, tx, group.PreferredTrackID, trackIDs)
+		if err != nil {
+			return 0, err
+		}
+		hidden += count
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit kept duplicates: %w", err)
+	}
+
+	return hidden, nil
+}
+
+func hideDuplicates(ctx context.Context, tx *sql.Tx, keptTrackID int64, trackIDs []int64) (int, error) {
+	if err := ensureTrackExists(ctx, tx, keptTrackID); err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM hidden_duplicates WHERE track_id = ?", keptTrackID); err != nil {
+		return 0, fmt.Errorf("show kept track %d: %w", keptTrackID, err)
+	}
+
+	hidden := 0
+	for _, trackID := range trackIDs {
+		if trackID == keptTrackID {
+			continue
+		}
+		if err := ensureTrackExists(ctx, tx, trackID); err != nil {
+			return 0, err
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO hidden_duplicates(track_id, kept_track_id)
+			 VALUES (?, ?)
+			 ON CONFLICT(track_id) DO UPDATE SET
+			 	kept_track_id = excluded.kept_track_id,
+			 	hidden_at = excluded.hidden_at`,
+			trackID,
+			keptTrackID,
+		); err != nil {
+			return 0, fmt.Errorf("hide duplicate track %d: %w", trackID, err)
+		}
+		hidden++
+	}
+
+	return hidden, nil
+}
+
+func normalizeDuplicateTolerance(toleranceMS int) int {
+	if toleranceMS <= 0 {
+		return defaultDuplicateToleranceMS
+	}
+	return min(toleranceMS, maxDuplicateToleranceMS)
+}
+
+// groupDuplicates links candidates sharing a quick hash or fingerprint, and
+// candidates with the same title and artist whose durations are within
+// toleranceMS of the next shorter one. Tracks without a title or duration
+// only match as identical files or fingerprints.
+func groupDuplicates(candidates []duplicateCandidate, toleranceMS int) []DuplicateGroup {
+	parent := make([]int, len(candidates))
+	for index := range parent {
+		parent[index] = index
+	}
+	var find func(int) int
+	find = func(index int) int {
+		if parent[index] != index {
+			parent[index] = find(parent[index])
+		}
+		return parent[index]
+	}
+	union := func(a int, b int) {
+		rootA, rootB := find(a), find(b)
+		if rootA != rootB {
+			parent[max(rootA, rootB)] = min(rootA, rootB)
+		}
+	}
+
+	byHash := make(map[string]int)
+	byFingerprint := make(map[string]int)
+	byMetadata := make(map[string][]int)
+	for index, candidate := range candidates {
+		if candidate.quickHash != "" {
+			if first, ok := byHash[candidate.quickHash]; ok {
+				union(first, index)
+			} else {
+				byHash[candidate.quickHash] = index
+			}
+		}
+		if candidate.fingerprint != "" {
+			if first, ok := byFingerprint[candidate.fingerprint]; ok {
+				union(first, index)
+			} else {
+				byFingerprint[candidate.fingerprint] = index
+			}
+		}
+
+		if candidate.track.Title != "" && candidate.track.DurationMS > 0 {
+			key := duplicateMetadataKey(candidate.track)
+			byMetadata[key] = append(byMetadata[key], index)
+		}
+	}
+
+	metadataMatched := make(map[int]struct{})
+	for _, indexes := range byMetadata {
+		sort.SliceStable(indexes, func(i int, j int) bool {
+			return candidates[indexes[i]].track.DurationMS < candidates[indexes[j]].track.DurationMS
+		})
+		for position := 1; position < len(indexes); position++ {
+			previous, current := indexes[position-1], indexes[position]
+			if candidates[current].track.DurationMS-candidates[previous].track.DurationMS <= toleranceMS {
+				union(previous, current)
+				metadataMatched[previous] = struct{}{}
+				metadataMatched[current] = struct{}{}
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	roots := make([]int, 0)
+	for index := range candidates {
+		root := find(index)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], index)
+	}
+
+	groups := make([]DuplicateGroup, 0)
+	for _, root := range roots {
+		indexes := members[root]
+		if len(indexes) < 2 {
+			continue
+		}
+
+		group := DuplicateGroup{MatchedBy: []string{}, Tracks: make([]DuplicateTrack, 0, len(indexes))}
+		hashes := make(map[string]int)
+		fingerprints := make(map[string]int)
+		metadata := false
+		preferred := candidates[indexes[0]].track
+		for _, index := range indexes {
+			candidate := candidates[index]
+			group.Tracks = append(group.Tracks, candidate.track)
+			if candidate.quickHash != "" {
+				hashes[candidate.quickHash]++
+			}
+			if candidate.fingerprint != "" {
+				fingerprints[candidate.fingerprint]++
+			}
+			if _, ok := metadataMatched[index]; ok {
+				metadata = true
+			}
+			if betterDuplicate(candidate.track, preferred) {
+				preferred = candidate.track
+			}
+		}
+		for _, count := range hashes {
+			if count > 1 {
+				group.MatchedBy = append(group.MatchedBy, DuplicateMatchFile)
+				break
+			}
+		}
+		for _, count := range fingerprints {
+			if count > 1 {
+				group.MatchedBy = append(group.MatchedBy, DuplicateMatchFingerprint)
+				break
+			}
+		}
+		if metadata {
+			group.MatchedBy = append(group.MatchedBy, DuplicateMatchMetadata)
+		}
+		group.PreferredTrackID = preferred.TrackID
+		groups = append(groups, group)
+	}
+
+	sort.SliceStable(groups, func(i int, j int) bool {
+		left, right := groups[i].Tracks[0], groups[j].Tracks[0]
+		if !strings.EqualFold(left.Artist, right.Artist) {
+			return strings.ToLower(left.Artist) < strings.ToLower(right.Artist)
+		}
+		return strings.ToLower(left.Title) < strings.ToLower(right.Title)
+	})
+
+	return groups
+}
+
+func duplicateMetadataKey(track DuplicateTrack) string {
+	return strings.ToLower(strings.TrimSpace(track.Title)) + "\x00" + strings.ToLower(strings.TrimSpace(track.Artist))
+}
+
+// betterDuplicate ranks versions by BetterQuality. Ties keep the track
+// that is not hidden.
+func betterDuplicate(candidate DuplicateTrack, current DuplicateTrack) bool {
+	if candidate.Quality() != current.Quality() {
+		return BetterQuality(candidate.Quality(), current.Quality())
+	}
+
+	return !candidate.Hidden && current.Hidden
+}
@@ -0,0 +1,143 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"testing"
+)
+
+func duplicateGroupTrackIDs(group DuplicateGroup) []int64 {
+	ids := make([]int64, 0, len(group.Tracks))
+	for _, track := range group.Tracks {
+		ids = append(ids, track.TrackID)
+	}
+	return ids
+}
+
+func TestGroupDuplicatesMatchesFilesAndMetadata(t *testing.T) {
+	t.Parallel()
+
+	candidates := []duplicateCandidate{
+		{track: DuplicateTrack{TrackID: 1, Title: "Song", Artist: "Band", DurationMS: 200000, Codec: "mp3", Bitrate: 320}, quickHash: "a"},
+		{track: DuplicateTrack{TrackID: 2, Title: "song", Artist: "band", DurationMS: 201500, Codec: "flac", Lossless: true}, quickHash: "b"},
+		{track: DuplicateTrack{TrackID: 3, Title: "Song", Artist: "Band", DurationMS: 240000, Codec: "mp3", Bitrate: 320}, quickHash: "c"},
+		{track: DuplicateTrack{TrackID: 4, Title: "Other", Artist: "Band", DurationMS: 100000, Bitrate: 128}, quickHash: "d"},
+		{track: DuplicateTrack{TrackID: 5, Title: "Renamed", Artist: "Band", DurationMS: 100000, Bitrate: 128}, quickHash: "d"},
+		{track: DuplicateTrack{TrackID: 6, Title: "", Artist: "Band", DurationMS: 100000}, quickHash: "e"},
+		{track: DuplicateTrack{TrackID: 7, Title: "", Artist: "Band", DurationMS: 100000}, quickHash: "f"},
+	}
+
+	groups := groupDuplicates(candidates, 2000)
+	if len(groups) != 2 {
+		t.Fatalf("expected two duplicate groups, got %+v", groups)
+	}
+
+	if ids := duplicateGroupTrackIDs(groups[0]); !slices.Equal(ids, []int64{4, 5}) {
+		t.Fatalf("expected the identical files to be grouped first, got %v", ids)
+	}
+	if !slices.Equal(groups[0].MatchedBy, []string{DuplicateMatchFile}) {
+		t.Fatalf("expected a file match, got %v", groups[0].MatchedBy)
+	}
+
+	if ids := duplicateGroupTrackIDs(groups[1]); !slices.Equal(ids, []int64{1, 2}) {
+		t.Fatalf("expected the versions within the tolerance to be grouped, got %v", ids)
+	}
+	if !slices.Equal(groups[1].MatchedBy, []string{DuplicateMatchMetadata}) {
+		t.Fatalf("expected a metadata match, got %v", groups[1].MatchedBy)
+	}
+	if groups[1].PreferredTrackID != 2 {
+		t.Fatalf("expected the lossless version to be preferred, got %d", groups[1].PreferredTrackID)
+	}
+}
+
+func TestBetterDuplicatePrefersQualityThenVisibleTracks(t *testing.T) {
+	t.Parallel()
+
+	low := DuplicateTrack{TrackID: 1, Bitrate: 192}
+	high := DuplicateTrack{TrackID: 2, Bitrate: 320}
+	if !betterDuplicate(high, low) || betterDuplicate(low, high) {
+		t.Fatal("expected the higher bitrate to win")
+	}
+
+	hidden := DuplicateTrack{TrackID: 3, Bitrate: 320, Hidden: true}
+	if !betterDuplicate(high, hidden) || betterDuplicate(hidden, high) {
+		t.Fatal("expected the visible track to win a tie")
+	}
+}
+
+func TestGroupDuplicatesMatchesFingerprints(t *testing.T) {
+	t.Parallel()
+
+	groups := groupDuplicates([]duplicateCandidate{
+		{track: DuplicateTrack{TrackID: 1, Title: "Intro", Artist: "Band", DurationMS: 60000}, quickHash: "a", fingerprint: "AQAA"},
+		{track: DuplicateTrack{TrackID: 2, Title: "Track 01", Artist: "Unknown Artist", DurationMS: 61000}, quickHash: "b", fingerprint: "AQAA"},
+		{track: DuplicateTrack{TrackID: 3, Title: "Outro", Artist: "Band", DurationMS: 60000}, quickHash: "c", fingerprint: ""},
+	}, 2000)
+
+	if len(groups) != 1 || !slices.Equal(duplicateGroupTrackIDs(groups[0]), []int64{1, 2}) {
+		t.Fatalf("expected the tracks with the same fingerprint to be grouped, got %+v", groups)
+	}
+	if !slices.Equal(groups[0].MatchedBy, []string{DuplicateMatchFingerprint}) {
+		t.Fatalf("expected a fingerprint match, got %v", groups[0].MatchedBy)
+	}
+}
+
+func TestRestoreHiddenDuplicatesUndoesHiding(t *testing.T) {
+	t.Parallel()
+
+	_, database := newTrackLinkRepositoryForTest(t)
+	finder := NewDuplicateFinder(database)
+	ctx := context.Background()
+	kept := insertLinkTrackForTest(t, database, "Kept")
+	first := insertLinkTrackForTest(t, database, "First")
+	second := insertLinkTrackForTest(t, database, "Second")
+
+	if _, err := finder.HideDuplicates(ctx, kept, []int64{first}); err != nil {
+		t.Fatalf("hide first: %v", err)
+	}
+	before, err := finder.ListHiddenDuplicates(ctx)
+	if err != nil {
+		t.Fatalf("list hidden: %v", err)
+	}
+
+	if _, err := finder.HideDuplicates(ctx, second, []int64{kept, first}); err != nil {
+		t.Fatalf("hide around second: %v", err)
+	}
+	after, err := finder.ListHiddenDuplicates(ctx)
+	if err != nil {
+		t.Fatalf("list hidden: %v", err)
+	}
+	if len(after) != 2 {
+		t.Fatalf("expected two hidden tracks, got %+v", after)
+	}
+
+	if err := finder.RestoreHiddenDuplicates(ctx, before); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	restored, _ := finder.ListHiddenDuplicates(ctx)
+	if !slices.Equal(restored, []HiddenDuplicate{{TrackID: first, KeptTrackID: kept}}) {
+		t.Fatalf("expected the first hide restored, got %+v", restored)
+	}
+
+	// Tracks removed in the meantime are skipped instead of failing.
+	if _, err := database.Exec("DELETE FROM tracks WHERE id = ?", second); err != nil {
+		t.Fatalf("delete track: %v", err)
+	}
+	if err := finder.RestoreHiddenDuplicates(ctx, after); err != nil {
+		t.Fatalf("restore after delete: %v", err)
+	}
+	assertHiddenDuplicateCount(t, database, 0)
+}
+
+func assertHiddenDuplicateCount(t *testing.T, database *sql.DB, want int) {
+	t.Helper()
+
+	var count int
+	if err := database.QueryRow("SELECT COUNT(1) FROM hidden_duplicates").Scan(&count); err != nil {
+		t.Fatalf("count hidden duplicates: %v", err)
+	}
+	if count != want {
+		t.Fatalf("expected %d hidden duplicates, got %d", want, count)
+	}
+}
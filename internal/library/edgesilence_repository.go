@@ -0,0 +1,158 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// EdgeSilenceSeamlessMS is the longest silence at a track edge that still
+// counts as a seamless transition, e.g. between the tracks of a DJ mix.
+const EdgeSilenceSeamlessMS = 150
+
+// EdgeSilence is how much silence a track starts and ends with, measured
+// by decoding its audio. DurationMS is the decoded length.
+type EdgeSilence struct {
+	LeadMS     int
+	TailMS     int
+	DurationMS int
+}
+
+// EdgeSilenceTarget is a local file waiting for edge silence analysis.
+type EdgeSilenceTarget struct {
+	TrackID    int64
+	Path       string
+	MtimeNS    int64
+	DurationMS int
+}
+
+type EdgeSilenceRepository struct {
+	db *sql.DB
+}
+
+func NewEdgeSilenceRepository(database *sql.DB) *EdgeSilenceRepository {
+	return &EdgeSilenceRepository{db: database}
+}
+
+// pendingEdgeSilenceFrom selects tracks that were never analyzed or whose
+// file changed since. Remote files are skipped, since analysis decodes the
+// whole file.
+const pendingEdgeSilenceFrom = `
+	FROM tracks t
+	JOIN files f ON f.id = t.file_id
+	LEFT JOIN track_edge_silence es ON es.track_id = t.id
+	WHERE f.file_exists = 1
+	  AND f.path NOT LIKE '%://%'
+	  AND (es.track_id IS NULL OR es.file_mtime_ns <> f.mtime_ns)`
+
+// CountPendingEdgeSilence returns how many tracks wait for analysis.
+func (r *EdgeSilenceRepository) CountPendingEdgeSilence(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(1)"+pendingEdgeSilenceFrom).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count tracks pending edge silence analysis: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListPendingEdgeSilence returns up to limit tracks waiting for analysis.
+func (r *EdgeSilenceRepository) ListPendingEdgeSilence(ctx context.Context, limit int) ([]EdgeSilenceTarget, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		"SELECT t.id, f.path, f.mtime_ns, COALESCE(t.duration_ms, 0)"+pendingEdgeSilenceFrom+" ORDER BY t.id LIMIT ?",
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query tracks pending edge silence analysis: %w", err)
+	}
+	defer rows.Close()
+
+	targets := make([]EdgeSilenceTarget, 0)
+	for rows.Next() {
+		var target EdgeSilenceTarget
+		if err := rows.Scan(&target.TrackID, &target.Path, &target.MtimeNS, &target.DurationMS); err != nil {
+			return nil, fmt.Errorf("scan track pending edge silence analysis: %w", err)
+		}
+		targets = append(targets, target)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate tracks pending edge silence analysis: %w", err)
+	}
+
+	return targets, nil
+}
+
+// SaveEdgeSilence stores the analysis of target.
+func (r *EdgeSilenceRepository) SaveEdgeSilence(ctx context.Context, target EdgeSilenceTarget, silence EdgeSilence) error {
+	return r.save(ctx, target, silence, false)
+}
+
+// MarkEdgeSilenceFailed records that target could not be decoded, so it is
+// not retried until its file changes.
+func (r *EdgeSilenceRepository) MarkEdgeSilenceFailed(ctx context.Context, target EdgeSilenceTarget) error {
+	return r.save(ctx, target, EdgeSilence{}, true)
+}
+
+func (r *EdgeSilenceRepository) save(ctx context.Context, target EdgeSilenceTarget, silence EdgeSilence, failed bool) error {
+	failedInt := 0
+	if failed {
+		failedInt = 1
+	}
+
+	if _, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO track_edge_silence(track_id, lead_ms, tail_ms, duration_ms, file_mtime_ns, failed, analyzed_at)
+		 VALUES (?, ?, ?, ?, ?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+		 ON CONFLICT(track_id) DO UPDATE SET
+		 	lead_ms = excluded.lead_ms,
+		 	tail_ms = excluded.tail_ms,
+		 	duration_ms = excluded.duration_ms,
+		 	file_mtime_ns = excluded.file_mtime_ns,
+		 	failed = excluded.failed,
+		 	analyzed_at = excluded.analyzed_at`,
+		target.TrackID,
+		max(silence.LeadMS, 0),
+		max(silence.TailMS, 0),
+		max(silence.DurationMS, 0),
+		target.MtimeNS,
+		failedInt,
+	); err != nil {
+		return fmt.Errorf("save edge silence of track %d: %w", target.TrackID, err)
+	}
+
+	return nil
+}
+
+// GetTrackEdgeSilence returns the analysis of a track. ok is false while the
+// track was not analyzed or could not be decoded.
+func (r *EdgeSilenceRepository) GetTrackEdgeSilence(ctx context.Context, trackID int64) (EdgeSilence, bool, error) {
+	var silence EdgeSilence
+	err := r.db.QueryRowContext(
+		ctx,
+		"SELECT lead_ms, tail_ms, duration_ms FROM track_edge_silence WHERE track_id = ? AND failed = 0",
+		trackID,
+	).Scan(&silence.LeadMS, &silence.TailMS, &silence.DurationMS)
+	if errors.Is(err, sql.ErrNoRows) {
+		return EdgeSilence{}, false, nil
+	}
+	if err != nil {
+		return EdgeSilence{}, false, fmt.Errorf("get edge silence of track %d: %w", trackID, err)
+	}
+
+	return silence, true, nil
+}
+
+// seamlessByEdgeSilence reports whether an album's tracks run into each
+// other without silence. Every track must be analyzed and at most one may
+// start with silence, the first, and at most one may end with it, the
+// last. Live albums cut between songs qualify too, and they need the same
+// gapless playback without a fade. A single transition says too little, so
+// at least three tracks are needed.
+func seamlessByEdgeSilence(trackCount int, analyzedCount int, seamlessLeads int, seamlessTails int) bool {
+	if trackCount < 3 || analyzedCount < trackCount {
+		return false
+	}
+
+	return seamlessLeads >= trackCount-1 && seamlessTails >= trackCount-1
+}
@@ -0,0 +1,138 @@
+package library
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/rzxx/ben/internal/db"
+)
+
+func TestSeamlessByEdgeSilenceAllowsSilenceOnlyAtTheAlbumEdges(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name                           string
+		tracks, analyzed, leads, tails int
+		want                           bool
+	}{
+		{name: "all seamless", tracks: 5, analyzed: 5, leads: 5, tails: 5, want: true},
+		{name: "silent album edges", tracks: 5, analyzed: 5, leads: 4, tails: 4, want: true},
+		{name: "silent gap inside", tracks: 5, analyzed: 5, leads: 3, tails: 4, want: false},
+		{name: "not fully analyzed", tracks: 5, analyzed: 4, leads: 4, tails: 4, want: false},
+		{name: "too few tracks", tracks: 2, analyzed: 2, leads: 2, tails: 2, want: false},
+	}
+	for _, tc := range cases {
+		if got := seamlessByEdgeSilence(tc.tracks, tc.analyzed, tc.leads, tc.tails); got != tc.want {
+			t.Fatalf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestGetAlbumMixDetectsAlbumsWithoutSilenceBetweenTracks(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.Bootstrap(filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatalf("bootstrap test database: %v", err)
+	}
+	defer database.Close()
+	ctx := context.Background()
+
+	mixes := NewAlbumMixRepository(database)
+	silences := NewEdgeSilenceRepository(database)
+
+	trackIDs := make([]int64, 0, 3)
+	for index := range 3 {
+		fileResult, err := database.Exec(
+			`INSERT INTO files(path, size, mtime_ns, file_exists, last_seen_at) VALUES (?, 123, 7, 1, '2026-01-01T00:00:00Z')`,
+			fmt.Sprintf("/music/mix/%d.flac", index+1),
+		)
+		if err != nil {
+			t.Fatalf("insert file row: %v", err)
+		}
+		fileID, _ := fileResult.LastInsertId()
+		trackResult, err := database.Exec(
+			`INSERT INTO tracks(file_id, title, artist, album, album_artist, track_no, duration_ms, tags_json) VALUES (?, ?, 'DJ', 'Night Set', 'DJ', ?, 240000, '{}')`,
+			fileID,
+			fmt.Sprintf("Part %d", index+1),
+			index+1,
+		)
+		if err != nil {
+			t.Fatalf("insert track row: %v", err)
+		}
+		trackID, _ := trackResult.LastInsertId()
+		trackIDs = append(trackIDs, trackID)
+	}
+
+	if count, err := silences.CountPendingEdgeSilence(ctx); err != nil || count != 3 {
+		t.Fatalf("expected 3 tracks pending, got %d err=%v", count, err)
+	}
+	pending, err := silences.ListPendingEdgeSilence(ctx, 10)
+	if err != nil {
+		t.Fatalf("list pending: %v", err)
+	}
+	if len(pending) != 3 || pending[0].MtimeNS != 7 || pending[0].DurationMS != 240000 {
+		t.Fatalf("expected every track pending, got %+v", pending)
+	}
+
+	// The set starts and ends with silence but runs through in between.
+	edges := []EdgeSilence{
+		{LeadMS: 2000, TailMS: 0, DurationMS: 240000},
+		{LeadMS: 0, TailMS: 40, DurationMS: 240000},
+		{LeadMS: 30, TailMS: 3000, DurationMS: 240000},
+	}
+	for index, target := range pending[:2] {
+		if err := silences.SaveEdgeSilence(ctx, target, edges[index]); err != nil {
+			t.Fatalf("save edge silence: %v", err)
+		}
+	}
+
+	mix, err := mixes.GetAlbumMix(ctx, "Night Set", "DJ")
+	if err != nil {
+		t.Fatalf("get album mix: %v", err)
+	}
+	if mix.Detected {
+		t.Fatalf("expected no detection before every track is analyzed")
+	}
+
+	if err := silences.SaveEdgeSilence(ctx, pending[2], edges[2]); err != nil {
+		t.Fatalf("save edge silence: %v", err)
+	}
+	mix, err = mixes.GetAlbumMix(ctx, "Night Set", "DJ")
+	if err != nil {
+		t.Fatalf("get album mix: %v", err)
+	}
+	if !mix.Detected || !mix.ContinuousMix {
+		t.Fatalf("expected the seamless album to be detected, got %+v", mix)
+	}
+
+	silence, ok, err := silences.GetTrackEdgeSilence(ctx, trackIDs[2])
+	if err != nil || !ok || silence != edges[2] {
+		t.Fatalf("unexpected stored edge silence %+v ok=%v err=%v", silence, ok, err)
+	}
+
+	// A rewritten file is analyzed again.
+	if _, err := database.Exec("UPDATE files SET mtime_ns = 8 WHERE path = '/music/mix/2.flac'"); err != nil {
+		t.Fatalf("touch file: %v", err)
+	}
+	pending, err = silences.ListPendingEdgeSilence(ctx, 10)
+	if err != nil {
+		t.Fatalf("list pending: %v", err)
+	}
+	if len(pending) != 1 || pending[0].TrackID != trackIDs[1] {
+		t.Fatalf("expected only the changed track pending, got %+v", pending)
+	}
+
+	if err := silences.MarkEdgeSilenceFailed(ctx, pending[0]); err != nil {
+		t.Fatalf("mark failed: %v", err)
+	}
+	if _, ok, _ := silences.GetTrackEdgeSilence(ctx, trackIDs[1]); ok {
+		t.Fatalf("expected a failed analysis to report no silence")
+	}
+	mix, _ = mixes.GetAlbumMix(ctx, "Night Set", "DJ")
+	if mix.Detected {
+		t.Fatalf("expected a failed analysis to stop detection")
+	}
+}
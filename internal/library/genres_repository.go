@@ -0,0 +1,299 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var ErrGenreNotFound = errors.New("genre not found")
+
+const defaultGenreTopTracks = 10
+
+const maxGenreQueueLength = 2000
+
+type GenreSummary struct {
+	Name       string `json:"name"`
+	TrackCount int    `json:"trackCount"`
+	AlbumCount int    `json:"albumCount"`
+}
+
+// GenreDetail lists the albums with a track of the genre and the genre's
+// most played tracks.
+type GenreDetail struct {
+	Name       string         `json:"name"`
+	TrackCount int            `json:"trackCount"`
+	AlbumCount int            `json:"albumCount"`
+	Albums     []AlbumSummary `json:"albums"`
+	TopTracks  []TrackSummary `json:"topTracks"`
+	Page       PageInfo       `json:"page"`
+}
+
+type GenreRepository struct {
+	db     *sql.DB
+	browse *BrowseRepository
+}
+
+func NewGenreRepository(database *sql.DB) *GenreRepository {
+	return &GenreRepository{db: database, browse: NewBrowseRepository(database)}
+}
+
+// SplitGenres splits multi-value genre tags such as "Rock; Pop" or
+// "Jazz, Fusion" into single genres, in tag order and without duplicates.
+func SplitGenres(values ...string) []string {
+	seen := make(map[string]struct{})
+	genres := make([]string, 0, len(values))
+	for _, value := range values {
+		for _, part := range strings.FieldsFunc(value, func(r rune) bool {
+			return r == ';' || r == ',' || unicode.IsControl(r)
+		}) {
+			genre := strings.Join(strings.Fields(part), " ")
+			if genre == "" {
+				continue
+			}
+			key := strings.ToLower(genre)
+			if _, duplicate := seen[key]; duplicate {
+				continue
+			}
+			seen[key] = struct{}{}
+			genres = append(genres, genre)
+		}
+	}
+
+	return genres
+}
+
+// ListGenres lists every genre of playable tracks with its track and album
+// counts.
+func (r *GenreRepository) ListGenres(ctx context.Context) ([]GenreSummary, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			MIN(g.genre) AS genre_name,
+			COUNT(DISTINCT g.track_id) AS track_count,
+			COUNT(DISTINCT at.album_id) AS album_count
+		FROM track_genres g
+		JOIN tracks t ON t.id = g.track_id
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN album_tracks at ON at.track_id = t.id
+		WHERE f.file_exists = 1
+		  AND f.is_audiobook = 0
+		GROUP BY g.genre
+		ORDER BY genre_name COLLATE LOCALE
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list genres: %w", err)
+	}
+	defer rows.Close()
+
+	genres := make([]GenreSummary, 0)
+	for rows.Next() {
+		var genre GenreSummary
+		if err := rows.Scan(&genre.Name, &genre.TrackCount, &genre.AlbumCount); err != nil {
+			return nil, fmt.Errorf("scan genre: %w", err)
+		}
+		genres = append(genres, genre)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate genres: %w", err)
+	}
+
+	return genres, nil
+}
+
+// GetGenreDetail pages through the albums of a genre and adds its most
+// played tracks.
+func (r *GenreRepository) GetGenreDetail(ctx context.Context, name string, limit int, offset int) (GenreDetail, error) {
+	genreName := strings.TrimSpace(name)
+	if genreName == "" {
+		return GenreDetail{}, errors.New("genre name is required")
+	}
+
+	detail := GenreDetail{Name: genreName}
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(MIN(g.genre), ?), COUNT(DISTINCT g.track_id), COUNT(DISTINCT at.album_id)
+		FROM track_genres g
+		JOIN tracks t ON t.id = g.track_id
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN album_tracks at ON at.track_id = t.id
+		WHERE g.genre = ?
+		  AND f.file_exists = 1
+		  AND f.is_audiobook = 0
+	`, genreName, genreName).Scan(&detail.Name, &detail.TrackCount, &detail.AlbumCount); err != nil {
+		return GenreDetail{}, fmt.Errorf("count genre %q: %w", genreName, err)
+	}
+	if detail.TrackCount == 0 {
+		return GenreDetail{}, ErrGenreNotFound
+	}
+
+	limit, offset = normalizePagination(limit, offset, defaultDetailLimit)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album') AS album_title,
+			COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist') AS album_artist_name,
+			a.year,
+			COUNT(1) AS track_count,
+			cover.cache_path,
+			ar.rating,
+			COALESCE(ar.favorite, 0)
+		FROM albums a
+		JOIN album_tracks at ON at.album_id = a.id
+		JOIN tracks t ON t.id = at.track_id
+		JOIN files f ON f.id = t.file_id
+		JOIN track_genres g ON g.track_id = t.id
+		LEFT JOIN covers cover ON cover.id = a.cover_id`+albumRatingJoinSQL+`
+		WHERE g.genre = ?
+		  AND f.file_exists = 1
+		  AND f.is_audiobook = 0
+		GROUP BY a.id
+		ORDER BY `+albumArtistTitleOrderSQL+`
+		LIMIT ?
+		OFFSET ?
+	`, genreName, limit, offset)
+	if err != nil {
+		return GenreDetail{}, fmt.Errorf("list albums of genre %q: %w", genreName, err)
+	}
+	defer rows.Close()
+
+	albums := make([]AlbumSummary, 0)
+	for rows.Next() {
+		var album AlbumSummary
+		var year sql.NullInt64
+		var coverPath sql.NullString
+		var rating sql.NullInt64
+		if scanErr := rows.Scan(&album.Title, &album.AlbumArtist, &year, &album.TrackCount, &coverPath, &rating, &album.Favorite); scanErr != nil {
+			return GenreDetail{}, fmt.Errorf("scan album of genre %q: %w", genreName, scanErr)
+		}
+		album.Year = intPointer(year)
+		album.CoverPath = stringPointer(coverPath)
+		album.Rating = intPointer(rating)
+		albums = append(albums, album)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return GenreDetail{}, fmt.Errorf("iterate albums of genre %q: %w", genreName, rowsErr)
+	}
+	rows.Close()
+
+	topTracks, err := r.listTopTracks(ctx, genreName, defaultGenreTopTracks)
+	if err != nil {
+		return GenreDetail{}, err
+	}
+
+	detail.Albums = albums
+	detail.TopTracks = topTracks
+	detail.Page = PageInfo{
+		Limit:  limit,
+		Offset: offset,
+		Total:  detail.AlbumCount,
+	}
+
+	return detail, nil
+}
+
+// listTopTracks returns the genre's tracks with the most completed plays,
+// with their play stats.
+func (r *GenreRepository) listTopTracks(ctx context.Context, genreName string, limit int) ([]TrackSummary, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT g.track_id
+		FROM track_genres g
+		JOIN tracks t ON t.id = g.track_id
+		JOIN files f ON f.id = t.file_id
+		JOIN play_stats_combined ps ON ps.track_id = g.track_id
+		WHERE g.genre = ?
+		  AND f.file_exists = 1
+		  AND f.is_audiobook = 0
+		GROUP BY g.track_id
+		HAVING SUM(ps.complete_count) > 0
+		ORDER BY SUM(ps.complete_count) DESC, SUM(ps.played_ms) DESC, g.track_id
+		LIMIT ?
+	`, genreName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list top tracks of genre %q: %w", genreName, err)
+	}
+	defer rows.Close()
+
+	trackIDs := make([]int64, 0, limit)
+	for rows.Next() {
+		var trackID int64
+		if scanErr := rows.Scan(&trackID); scanErr != nil {
+			return nil, fmt.Errorf("scan top track of genre %q: %w", genreName, scanErr)
+		}
+		trackIDs = append(trackIDs, trackID)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate top tracks of genre %q: %w", genreName, rowsErr)
+	}
+	rows.Close()
+
+	if len(trackIDs) == 0 {
+		return []TrackSummary{}, nil
+	}
+
+	page, err := r.browse.listTracks(ctx, trackFilter{trackIDs: trackIDs}, len(trackIDs), 0, true)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]TrackSummary, len(page.Items))
+	for _, track := range page.Items {
+		byID[track.ID] = track
+	}
+	tracks := make([]TrackSummary, 0, len(trackIDs))
+	for _, trackID := range trackIDs {
+		if track, ok := byID[trackID]; ok {
+			tracks = append(tracks, track)
+		}
+	}
+
+	return tracks, nil
+}
+
+// GetGenreQueueTrackIDs returns the tracks of a genre in album order, for
+// playing a whole genre.
+func (r *GenreRepository) GetGenreQueueTrackIDs(ctx context.Context, name string) ([]int64, error) {
+	genreName := strings.TrimSpace(name)
+	if genreName == "" {
+		return nil, errors.New("genre name is required")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.id
+		FROM track_genres g
+		JOIN tracks t ON t.id = g.track_id
+		JOIN files f ON f.id = t.file_id
+		WHERE g.genre = ?
+		  AND f.file_exists = 1
+		  AND f.is_audiobook = 0
+		ORDER BY
+			COALESCE(NULLIF(TRIM(t.album_artist), ''), COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) COLLATE LOCALE,
+			COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album') COLLATE LOCALE,
+			COALESCE(t.disc_no, 0),
+			COALESCE(t.track_no, 0),
+			t.id
+		LIMIT ?
+	`, genreName, maxGenreQueueLength)
+	if err != nil {
+		return nil, fmt.Errorf("list queue tracks of genre %q: %w", genreName, err)
+	}
+	defer rows.Close()
+
+	trackIDs := make([]int64, 0)
+	for rows.Next() {
+		var trackID int64
+		if scanErr := rows.Scan(&trackID); scanErr != nil {
+			return nil, fmt.Errorf("scan queue track of genre %q: %w", genreName, scanErr)
+		}
+		trackIDs = append(trackIDs, trackID)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate queue tracks of genre %q: %w", genreName, rowsErr)
+	}
+	if len(trackIDs) == 0 {
+		return nil, ErrGenreNotFound
+	}
+
+	return trackIDs, nil
+}
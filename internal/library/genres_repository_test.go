@@ -0,0 +1,16 @@
+package library
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitGenresSplitsMultiValueTags(t *testing.T) {
+	t.Parallel()
+
+	got := SplitGenres("Rock; Pop", "Jazz,  Fusion", "rock", " ", "Hip Hop\x00R&B")
+	want := []string{"Rock", "Pop", "Jazz", "Fusion", "Hip Hop", "R&B"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("SplitGenres = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,381 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	MoodSourceUser = "user"
+	MoodSourceAuto = "auto"
+)
+
+// Moods estimated from the BPM tag. Tempo is the only audio feature the
+// library knows, so estimation only tells calm tracks from energetic ones.
+const (
+	MoodCalm      = "calm"
+	MoodUpbeat    = "upbeat"
+	MoodEnergetic = "energetic"
+)
+
+const maxMoodLength = 40
+
+const (
+	calmMaxBPM      = 90
+	upbeatMinBPM    = 115
+	energeticMinBPM = 140
+)
+
+const maxMoodQueueLength = 500
+
+// TrackMoods lists the moods of a track. Moods holds every mood, the ones
+// assigned by hand and the estimated ones; Auto is the estimated subset.
+type TrackMoods struct {
+	TrackID int64    `json:"trackId"`
+	Moods   []string `json:"moods"`
+	Auto    []string `json:"auto"`
+}
+
+type MoodSummary struct {
+	Mood       string `json:"mood"`
+	TrackCount int    `json:"trackCount"`
+}
+
+type MoodRepository struct {
+	db     *sql.DB
+	browse *BrowseRepository
+}
+
+func NewMoodRepository(database *sql.DB) *MoodRepository {
+	return &MoodRepository{db: database, browse: NewBrowseRepository(database)}
+}
+
+func (r *MoodRepository) GetTrackMoods(ctx context.Context, trackID int64) (TrackMoods, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT mood, source FROM track_moods WHERE track_id = ? ORDER BY mood", trackID)
+	if err != nil {
+		return TrackMoods{}, fmt.Errorf("get moods for track %d: %w", trackID, err)
+	}
+	defer rows.Close()
+
+	moods := TrackMoods{TrackID: trackID, Moods: make([]string, 0), Auto: make([]string, 0)}
+	for rows.Next() {
+		var mood string
+		var source string
+		if err := rows.Scan(&mood, &source); err != nil {
+			return TrackMoods{}, fmt.Errorf("scan mood for track %d: %w", trackID, err)
+		}
+		moods.Moods = append(moods.Moods, mood)
+		if source == MoodSourceAuto {
+			moods.Auto = append(moods.Auto, mood)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return TrackMoods{}, fmt.Errorf("iterate moods for track %d: %w", trackID, err)
+	}
+
+	return moods, nil
+}
+
+// SetTrackMoods replaces the moods of a track with moods. Moods are stored
+// lowercase; an estimated mood that is kept becomes a mood set by hand, and
+// an estimated one that is left out is removed.
+func (r *MoodRepository) SetTrackMoods(ctx context.Context, trackID int64, moods []string) (TrackMoods, error) {
+	normalized, err := normalizeMoods(moods)
+	if err != nil {
+		return TrackMoods{}, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return TrackMoods{}, fmt.Errorf("begin track moods tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := ensureTrackExists(ctx, tx, trackID); err != nil {
+		return TrackMoods{}, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM track_moods WHERE track_id = ?", trackID); err != nil {
+		return TrackMoods{}, fmt.Errorf("clear moods for track %d: %w", trackID, err)
+	}
+	for _, mood := range normalized {
+		if _, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO track_moods(track_id, mood, source) VALUES (?, ?, ?)",
+			trackID,
+			mood,
+			MoodSourceUser,
+		); err != nil {
+			return TrackMoods{}, fmt.Errorf("add mood %q to track %d: %w", mood, trackID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return TrackMoods{}, fmt.Errorf("commit track moods tx: %w", err)
+	}
+
+	return r.GetTrackMoods(ctx, trackID)
+}
+
+// ListMoods lists every mood with the number of playable tracks carrying it.
+func (r *MoodRepository) ListMoods(ctx context.Context) ([]MoodSummary, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.mood, COUNT(DISTINCT m.track_id)
+		FROM track_moods m
+		JOIN tracks t ON t.id = m.track_id
+		JOIN files f ON f.id = t.file_id
+		WHERE f.file_exists = 1
+		  AND f.is_audiobook = 0
+		GROUP BY m.mood
+		ORDER BY m.mood
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list moods: %w", err)
+	}
+	defer rows.Close()
+
+	moods := make([]MoodSummary, 0)
+	for rows.Next() {
+		var summary MoodSummary
+		if err := rows.Scan(&summary.Mood, &summary.TrackCount); err != nil {
+			return nil, fmt.Errorf("scan mood: %w", err)
+		}
+		moods = append(moods, summary)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate moods: %w", err)
+	}
+
+	return moods, nil
+}
+
+// ListTracksByMood browses the tracks carrying any of moods, narrowed by
+// search like ListTracks.
+func (r *MoodRepository) ListTracksByMood(ctx context.Context, moods []string, search string, limit int, offset int, withStats bool) (TracksPage, error) {
+	normalized, err := normalizeMoods(moods)
+	if err != nil {
+		return TracksPage{}, err
+	}
+	if len(normalized) == 0 {
+		return TracksPage{}, errors.New("at least one mood is required")
+	}
+
+	return r.browse.listTracks(ctx, trackFilter{search: search, moods: normalized}, limit, offset, withStats)
+}
+
+// GetMoodQueueTrackIDs builds a shuffled queue from the tracks carrying any
+// of moods. Tracks matching more of the moods come first, and banned tracks
+// are left out.
+func (r *MoodRepository) GetMoodQueueTrackIDs(ctx context.Context, moods []string) ([]int64, error) {
+	normalized, err := normalizeMoods(moods)
+	if err != nil {
+		return nil, err
+	}
+	if len(normalized) == 0 {
+		return nil, errors.New("at least one mood is required")
+	}
+
+	args := make([]any, 0, len(normalized))
+	for _, mood := range normalized {
+		args = append(args, mood)
+	}
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT m.track_id, COUNT(1) AS matches
+		FROM track_moods m
+		JOIN tracks t ON t.id = m.track_id
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN track_ratings tr ON tr.track_id = t.id
+		WHERE m.mood IN (%s)
+		  AND f.file_exists = 1
+		  AND f.is_audiobook = 0
+		  AND COALESCE(tr.banned, 0) = 0
+		GROUP BY m.track_id
+	`, sqlPlaceholders(len(normalized))), args...)
+	if err != nil {
+		return nil, fmt.Errorf("list mood tracks: %w", err)
+	}
+	defer rows.Close()
+
+	byMatches := make(map[int][]int64)
+	for rows.Next() {
+		var trackID int64
+		var matches int
+		if err := rows.Scan(&trackID, &matches); err != nil {
+			return nil, fmt.Errorf("scan mood track: %w", err)
+		}
+		byMatches[matches] = append(byMatches[matches], trackID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate mood tracks: %w", err)
+	}
+
+	trackIDs := make([]int64, 0)
+	for matches := len(normalized); matches > 0 && len(trackIDs) < maxMoodQueueLength; matches-- {
+		group := byMatches[matches]
+		rand.Shuffle(len(group), func(i int, j int) {
+			group[i], group[j] = group[j], group[i]
+		})
+		trackIDs = append(trackIDs, group...)
+	}
+	if len(trackIDs) > maxMoodQueueLength {
+		trackIDs = trackIDs[:maxMoodQueueLength]
+	}
+
+	return trackIDs, nil
+}
+
+// EstimateMoods replaces the estimated moods of every track from its tags:
+// MOOD tags are taken as they are, and the BPM tag is mapped to calm,
+// upbeat or energetic. Moods set by hand are never touched. It returns the
+// number of tracks that got an estimated mood.
+func (r *MoodRepository) EstimateMoods(ctx context.Context) (int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.id, t.tags_json
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		WHERE f.file_exists = 1
+		  AND f.is_audiobook = 0
+		  AND json_valid(t.tags_json)
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("list track tags: %w", err)
+	}
+
+	estimates := make(map[int64][]string)
+	for rows.Next() {
+		var trackID int64
+		var tagsJSON string
+		if err := rows.Scan(&trackID, &tagsJSON); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan track tags: %w", err)
+		}
+		if moods := estimateMoodsFromTags(tagsJSON); len(moods) > 0 {
+			estimates[trackID] = moods
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, fmt.Errorf("iterate track tags: %w", err)
+	}
+	rows.Close()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin mood estimation tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM track_moods WHERE source = ?", MoodSourceAuto); err != nil {
+		return 0, fmt.Errorf("clear estimated moods: %w", err)
+	}
+	for trackID, moods := range estimates {
+		for _, mood := range moods {
+			if _, err := tx.ExecContext(
+				ctx,
+				"INSERT OR IGNORE INTO track_moods(track_id, mood, source) VALUES (?, ?, ?)",
+				trackID,
+				mood,
+				MoodSourceAuto,
+			); err != nil {
+				return 0, fmt.Errorf("add estimated mood to track %d: %w", trackID, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit mood estimation tx: %w", err)
+	}
+
+	return len(estimates), nil
+}
+
+func estimateMoodsFromTags(tagsJSON string) []string {
+	var tags struct {
+		TaglibTags map[string][]string `json:"taglib_tags"`
+	}
+	if err := json.Unmarshal([]byte(tagsJSON), &tags); err != nil {
+		return nil
+	}
+
+	candidates := make([]string, 0)
+	for _, value := range tags.TaglibTags["MOOD"] {
+		candidates = append(candidates, strings.FieldsFunc(value, func(r rune) bool {
+			return r == ';' || r == ',' || r == '/'
+		})...)
+	}
+	for _, value := range tags.TaglibTags["BPM"] {
+		if mood := moodFromBPM(value); mood != "" {
+			candidates = append(candidates, mood)
+			break
+		}
+	}
+
+	moods, err := normalizeMoods(candidates)
+	if err != nil {
+		return nil
+	}
+
+	return moods
+}
+
+func moodFromBPM(value string) string {
+	bpm, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || bpm <= 0 {
+		return ""
+	}
+
+	switch {
+	case bpm >= energeticMinBPM:
+		return MoodEnergetic
+	case bpm >= upbeatMinBPM:
+		return MoodUpbeat
+	case bpm <= calmMaxBPM:
+		return MoodCalm
+	default:
+		return ""
+	}
+}
+
+func normalizeMood(mood string) string {
+	visible := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, mood)
+
+	return strings.ToLower(strings.Join(strings.Fields(visible), " "))
+}
+
+func normalizeMoods(moods []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(moods))
+	normalized := make([]string, 0, len(moods))
+	for _, mood := range moods {
+		normalizedMood := normalizeMood(mood)
+		if normalizedMood == "" {
+			continue
+		}
+		if len([]rune(normalizedMood)) > maxMoodLength {
+			return nil, fmt.Errorf("moods must be at most %d characters", maxMoodLength)
+		}
+		if _, duplicate := seen[normalizedMood]; duplicate {
+			continue
+		}
+		seen[normalizedMood] = struct{}{}
+		normalized = append(normalized, normalizedMood)
+	}
+
+	sort.Strings(normalized)
+	return normalized, nil
+}
@@ -0,0 +1,42 @@
+package library
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeMoodsLowercasesAndDedupes(t *testing.T) {
+	t.Parallel()
+
+	moods, err := normalizeMoods([]string{"  Chill ", "chill", "Late\tNight", "", "calm"})
+	if err != nil {
+		t.Fatalf("normalize moods: %v", err)
+	}
+	if want := []string{"calm", "chill", "late night"}; !slices.Equal(moods, want) {
+		t.Fatalf("expected %v, got %v", want, moods)
+	}
+
+	if _, err := normalizeMoods([]string{strings.Repeat("x", maxMoodLength+1)}); err == nil {
+		t.Fatal("expected an overlong mood to be rejected")
+	}
+}
+
+func TestEstimateMoodsFromTagsUsesMoodAndBPM(t *testing.T) {
+	t.Parallel()
+
+	moods := estimateMoodsFromTags(`{"taglib_tags":{"MOOD":["Happy; Dreamy"],"BPM":["148"]}}`)
+	if want := []string{"dreamy", MoodEnergetic, "happy"}; !slices.Equal(moods, want) {
+		t.Fatalf("expected %v, got %v", want, moods)
+	}
+
+	if moods := estimateMoodsFromTags(`{"taglib_tags":{"BPM":["72.5"]}}`); !slices.Equal(moods, []string{MoodCalm}) {
+		t.Fatalf("expected slow tempo to be calm, got %v", moods)
+	}
+	if moods := estimateMoodsFromTags(`{"taglib_tags":{"BPM":["100"]}}`); len(moods) != 0 {
+		t.Fatalf("expected mid tempo to get no mood, got %v", moods)
+	}
+	if moods := estimateMoodsFromTags(`{"taglib_tags":{"BPM":["fast"]}}`); len(moods) != 0 {
+		t.Fatalf("expected unreadable tempo to get no mood, got %v", moods)
+	}
+}
@@ -0,0 +1,129 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// playCountSQL counts completed, skipped and partial plays per track from the
+// raw events that have not been rolled up yet and from the daily rollups.
+// Rollup rows only know the day, so lastPlayedAt falls back to a date once
+// the events themselves have been pruned.
+const playCountSQL = `
+	SELECT track_id, 1 AS play_count, ts AS last_played_at
+	FROM play_events
+	WHERE event_type IN ('complete', 'skip', 'partial')
+	  AND track_id IN (%[1]s)
+	UNION ALL
+	SELECT
+		track_id,
+		complete_count + skip_count + partial_count AS play_count,
+		CASE WHEN complete_count + skip_count + partial_count > 0 THEN day END AS last_played_at
+	FROM play_stats_combined
+	WHERE track_id IN (%[1]s)
+`
+
+type playStats struct {
+	count        int
+	lastPlayedAt sql.NullString
+}
+
+func (r *BrowseRepository) attachTrackPlayStats(ctx context.Context, tracks []TrackSummary) error {
+	if len(tracks) == 0 {
+		return nil
+	}
+
+	trackIDs := make([]any, 0, len(tracks))
+	for _, track := range tracks {
+		trackIDs = append(trackIDs, track.ID)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT track_id, SUM(play_count), MAX(last_played_at)
+		FROM (`+playCountSQL+`)
+		GROUP BY track_id
+	`, sqlPlaceholders(len(trackIDs)))
+
+	stats, err := r.queryPlayStats(ctx, query, append(cloneArgs(trackIDs), trackIDs...))
+	if err != nil {
+		return fmt.Errorf("load track play stats: %w", err)
+	}
+
+	for index := range tracks {
+		tracks[index].PlayCount, tracks[index].LastPlayedAt = stats[tracks[index].ID].pointers()
+	}
+
+	return nil
+}
+
+func (r *BrowseRepository) attachAlbumPlayStats(ctx context.Context, albums []AlbumSummary, albumIDs []int64) error {
+	if len(albumIDs) == 0 {
+		return nil
+	}
+
+	ids := make([]any, 0, len(albumIDs))
+	for _, albumID := range albumIDs {
+		ids = append(ids, albumID)
+	}
+	trackSelect := "SELECT at.track_id FROM album_tracks at WHERE at.album_id IN (" + sqlPlaceholders(len(albumIDs)) + ")"
+
+	query := fmt.Sprintf(`
+		SELECT at.album_id, SUM(plays.play_count), MAX(plays.last_played_at)
+		FROM (`+playCountSQL+`) plays
+		JOIN album_tracks at ON at.track_id = plays.track_id
+		WHERE at.album_id IN (%[2]s)
+		GROUP BY at.album_id
+	`, trackSelect, sqlPlaceholders(len(albumIDs)))
+	args := make([]any, 0, len(ids)*3)
+	for range 3 {
+		args = append(args, ids...)
+	}
+
+	stats, err := r.queryPlayStats(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("load album play stats: %w", err)
+	}
+
+	for index, albumID := range albumIDs {
+		albums[index].PlayCount, albums[index].LastPlayedAt = stats[albumID].pointers()
+	}
+
+	return nil
+}
+
+func (r *BrowseRepository) queryPlayStats(ctx context.Context, query string, args []any) (map[int64]playStats, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make(map[int64]playStats)
+	for rows.Next() {
+		var id int64
+		var item playStats
+		if scanErr := rows.Scan(&id, &item.count, &item.lastPlayedAt); scanErr != nil {
+			return nil, fmt.Errorf("scan play stats row: %w", scanErr)
+		}
+		stats[id] = item
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate play stats rows: %w", rowsErr)
+	}
+
+	return stats, nil
+}
+
+// pointers reports never-played items as a zero count without a timestamp,
+// so a present playCount always means stats were requested.
+func (s playStats) pointers() (*int, *string) {
+	count := s.count
+	return &count, stringPointer(s.lastPlayedAt)
+}
+
+func sqlPlaceholders(count int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", count), ", ")
+}
@@ -0,0 +1,350 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	ProvenanceSourceUser = "user"
+	ProvenanceSourceTag  = "tag"
+)
+
+// Provenance fields, as accepted by ListProvenanceValues and
+// ListTracksByProvenance.
+const (
+	ProvenancePurchasedFrom  = "purchasedFrom"
+	ProvenanceRippedFrom     = "rippedFrom"
+	ProvenanceDownloadSource = "downloadSource"
+)
+
+const maxProvenanceLength = 200
+
+var provenanceColumns = map[string]string{
+	ProvenancePurchasedFrom:  "purchased_from",
+	ProvenanceRippedFrom:     "ripped_from",
+	ProvenanceDownloadSource: "download_source",
+}
+
+// COMMENT prefixes that name where a file came from, checked in order.
+var provenanceCommentPrefixes = []struct {
+	prefix string
+	field  string
+}{
+	{"purchased from ", ProvenancePurchasedFrom},
+	{"purchased at ", ProvenancePurchasedFrom},
+	{"bought from ", ProvenancePurchasedFrom},
+	{"bought at ", ProvenancePurchasedFrom},
+	{"ripped from ", ProvenanceRippedFrom},
+	{"ripped with ", ProvenanceRippedFrom},
+	{"downloaded from ", ProvenanceDownloadSource},
+}
+
+// TrackProvenance records where the file of a track came from. Empty
+// fields are unknown. FromTags reports provenance imported from tags that
+// was not edited by hand since.
+type TrackProvenance struct {
+	TrackID        int64  `json:"trackId"`
+	PurchasedFrom  string `json:"purchasedFrom"`
+	RippedFrom     string `json:"rippedFrom"`
+	DownloadSource string `json:"downloadSource"`
+	FromTags       bool   `json:"fromTags"`
+}
+
+type ProvenanceSummary struct {
+	Field      string `json:"field"`
+	Value      string `json:"value"`
+	TrackCount int    `json:"trackCount"`
+}
+
+type ProvenanceRepository struct {
+	db     *sql.DB
+	browse *BrowseRepository
+}
+
+func NewProvenanceRepository(database *sql.DB) *ProvenanceRepository {
+	return &ProvenanceRepository{db: database, browse: NewBrowseRepository(database)}
+}
+
+func (r *ProvenanceRepository) GetTrackProvenance(ctx context.Context, trackID int64) (TrackProvenance, error) {
+	provenance := TrackProvenance{TrackID: trackID}
+	var source sql.NullString
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(p.purchased_from, ''), COALESCE(p.ripped_from, ''), COALESCE(p.download_source, ''), p.source
+		FROM tracks t
+		LEFT JOIN file_provenance p ON p.file_id = t.file_id
+		WHERE t.id = ?
+	`, trackID).Scan(&provenance.PurchasedFrom, &provenance.RippedFrom, &provenance.DownloadSource, &source)
+	if errors.Is(err, sql.ErrNoRows) {
+		return TrackProvenance{}, fmt.Errorf("%w: %d", ErrTrackNotFound, trackID)
+	}
+	if err != nil {
+		return TrackProvenance{}, fmt.Errorf("get provenance for track %d: %w", trackID, err)
+	}
+	provenance.FromTags = source.String == ProvenanceSourceTag
+
+	return provenance, nil
+}
+
+// SetTrackProvenance replaces the provenance of the file behind a track.
+// Clearing every field forgets the provenance, so a later tag import may
+// fill it again.
+func (r *ProvenanceRepository) SetTrackProvenance(ctx context.Context, trackID int64, provenance TrackProvenance) (TrackProvenance, error) {
+	normalized, err := normalizeProvenance(provenance)
+	if err != nil {
+		return TrackProvenance{}, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return TrackProvenance{}, fmt.Errorf("begin track provenance tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var fileID int64
+	err = tx.QueryRowContext(ctx, "SELECT file_id FROM tracks WHERE id = ?", trackID).Scan(&fileID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return TrackProvenance{}, fmt.Errorf("%w: %d", ErrTrackNotFound, trackID)
+	}
+	if err != nil {
+		return TrackProvenance{}, fmt.Errorf("get file for track %d: %w", trackID, err)
+	}
+
+	if normalized.PurchasedFrom == "" && normalized.RippedFrom == "" && normalized.DownloadSource == "" {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM file_provenance WHERE file_id = ?", fileID); err != nil {
+			return TrackProvenance{}, fmt.Errorf("clear provenance for track %d: %w", trackID, err)
+		}
+	} else if _, err := tx.ExecContext(ctx, `
+		INSERT INTO file_provenance(file_id, purchased_from, ripped_from, download_source, source, updated_at)
+		VALUES (?, ?, ?, ?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+		ON CONFLICT(file_id) DO UPDATE SET
+			purchased_from = excluded.purchased_from,
+			ripped_from = excluded.ripped_from,
+			download_source = excluded.download_source,
+			source = excluded.source,
+			updated_at = excluded.updated_at
+	`, fileID, normalized.PurchasedFrom, normalized.RippedFrom, normalized.DownloadSource, ProvenanceSourceUser); err != nil {
+		return TrackProvenance{}, fmt.Errorf("set provenance for track %d: %w", trackID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return TrackProvenance{}, fmt.Errorf("commit track provenance tx: %w", err)
+	}
+
+	return r.GetTrackProvenance(ctx, trackID)
+}
+
+// ImportProvenanceFromTags replaces the provenance imported from tags of
+// every file: WWWAUDIOFILE names the download source, and COMMENT lines
+// like "Purchased from Bandcamp" or "Ripped from CD" fill the matching
+// field. Provenance edited by hand is never touched. It returns the number
+// of files that got provenance.
+func (r *ProvenanceRepository) ImportProvenanceFromTags(ctx context.Context) (int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.file_id, t.tags_json
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		WHERE f.file_exists = 1
+		  AND json_valid(t.tags_json)
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("list track tags: %w", err)
+	}
+
+	imported := make(map[int64]TrackProvenance)
+	for rows.Next() {
+		var fileID int64
+		var tagsJSON string
+		if err := rows.Scan(&fileID, &tagsJSON); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan track tags: %w", err)
+		}
+		if provenance, ok := provenanceFromTags(tagsJSON); ok {
+			imported[fileID] = provenance
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, fmt.Errorf("iterate track tags: %w", err)
+	}
+	rows.Close()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin provenance import tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM file_provenance WHERE source = ?", ProvenanceSourceTag); err != nil {
+		return 0, fmt.Errorf("clear imported provenance: %w", err)
+	}
+	for fileID, provenance := range imported {
+		if _, err := tx.ExecContext(
+			ctx,
+			`INSERT OR IGNORE INTO file_provenance(file_id, purchased_from, ripped_from, download_source, source)
+			 VALUES (?, ?, ?, ?, ?)`,
+			fileID,
+			provenance.PurchasedFrom,
+			provenance.RippedFrom,
+			provenance.DownloadSource,
+			ProvenanceSourceTag,
+		); err != nil {
+			return 0, fmt.Errorf("import provenance for file %d: %w", fileID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit provenance import tx: %w", err)
+	}
+
+	return len(imported), nil
+}
+
+// ListProvenanceValues lists the values of a provenance field with the
+// number of playable tracks carrying each.
+func (r *ProvenanceRepository) ListProvenanceValues(ctx context.Context, field string) ([]ProvenanceSummary, error) {
+	column, ok := provenanceColumns[field]
+	if !ok {
+		return nil, fmt.Errorf("unknown provenance field %q", field)
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT p.%[1]s, COUNT(DISTINCT t.id)
+		FROM file_provenance p
+		JOIN tracks t ON t.file_id = p.file_id
+		JOIN files f ON f.id = p.file_id
+		WHERE p.%[1]s <> ''
+		  AND f.file_exists = 1
+		  AND f.is_audiobook = 0
+		GROUP BY p.%[1]s COLLATE NOCASE
+		ORDER BY p.%[1]s COLLATE NOCASE
+	`, column))
+	if err != nil {
+		return nil, fmt.Errorf("list provenance values: %w", err)
+	}
+	defer rows.Close()
+
+	values := make([]ProvenanceSummary, 0)
+	for rows.Next() {
+		summary := ProvenanceSummary{Field: field}
+		if err := rows.Scan(&summary.Value, &summary.TrackCount); err != nil {
+			return nil, fmt.Errorf("scan provenance value: %w", err)
+		}
+		values = append(values, summary)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate provenance values: %w", err)
+	}
+
+	return values, nil
+}
+
+// ListTracksByProvenance browses the tracks whose provenance field equals
+// value, ignoring case, narrowed by search like ListTracks. An empty value
+// matches every track with the field set.
+func (r *ProvenanceRepository) ListTracksByProvenance(ctx context.Context, field string, value string, search string, limit int, offset int, withStats bool) (TracksPage, error) {
+	column, ok := provenanceColumns[field]
+	if !ok {
+		return TracksPage{}, fmt.Errorf("unknown provenance field %q", field)
+	}
+
+	filter := trackFilter{
+		search:     search,
+		provenance: &provenanceFilter{column: column, value: normalizeProvenanceValue(value)},
+	}
+	return r.browse.listTracks(ctx, filter, limit, offset, withStats)
+}
+
+func provenanceFromTags(tagsJSON string) (TrackProvenance, bool) {
+	var tags struct {
+		TaglibTags map[string][]string `json:"taglib_tags"`
+	}
+	if err := json.Unmarshal([]byte(tagsJSON), &tags); err != nil {
+		return TrackProvenance{}, false
+	}
+
+	provenance := TrackProvenance{}
+	// TagLib reports the ID3v2 WOAF frame as FILEWEBPAGE.
+	for _, key := range []string{"WWWAUDIOFILE", "FILEWEBPAGE"} {
+		for _, value := range tags.TaglibTags[key] {
+			if provenance.DownloadSource == "" {
+				provenance.DownloadSource = normalizeProvenanceValue(value)
+			}
+		}
+	}
+
+	for _, comment := range tags.TaglibTags["COMMENT"] {
+		for _, line := range strings.Split(comment, "\n") {
+			field, value := provenanceFromComment(line)
+			switch {
+			case value == "":
+			case field == ProvenancePurchasedFrom && provenance.PurchasedFrom == "":
+				provenance.PurchasedFrom = value
+			case field == ProvenanceRippedFrom && provenance.RippedFrom == "":
+				provenance.RippedFrom = value
+			case field == ProvenanceDownloadSource && provenance.DownloadSource == "":
+				provenance.DownloadSource = value
+			}
+		}
+	}
+
+	found := provenance.PurchasedFrom != "" || provenance.RippedFrom != "" || provenance.DownloadSource != ""
+	if !found {
+		return TrackProvenance{}, false
+	}
+	normalized, err := normalizeProvenance(provenance)
+	if err != nil {
+		return TrackProvenance{}, false
+	}
+
+	return normalized, true
+}
+
+func provenanceFromComment(line string) (string, string) {
+	trimmed := strings.TrimSpace(line)
+	lower := strings.ToLower(trimmed)
+	for _, candidate := range provenanceCommentPrefixes {
+		if strings.HasPrefix(lower, candidate.prefix) {
+			value := strings.TrimRight(trimmed[len(candidate.prefix):], ".!;, ")
+			return candidate.field, normalizeProvenanceValue(value)
+		}
+	}
+
+	return "", ""
+}
+
+func normalizeProvenanceValue(value string) string {
+	visible := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, value)
+
+	return strings.Join(strings.Fields(visible), " ")
+}
+
+func normalizeProvenance(provenance TrackProvenance) (TrackProvenance, error) {
+	normalized := TrackProvenance{
+		TrackID:        provenance.TrackID,
+		PurchasedFrom:  normalizeProvenanceValue(provenance.PurchasedFrom),
+		RippedFrom:     normalizeProvenanceValue(provenance.RippedFrom),
+		DownloadSource: normalizeProvenanceValue(provenance.DownloadSource),
+	}
+	for _, value := range []string{normalized.PurchasedFrom, normalized.RippedFrom, normalized.DownloadSource} {
+		if len([]rune(value)) > maxProvenanceLength {
+			return TrackProvenance{}, fmt.Errorf("provenance must be at most %d characters", maxProvenanceLength)
+		}
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,49 @@
+package library
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProvenanceFromTagsReadsCommentsAndFileURL(t *testing.T) {
+	t.Parallel()
+
+	provenance, ok := provenanceFromTags(`{"taglib_tags":{"COMMENT":["Ripped from CD with EAC\nPurchased from  Bandcamp."],"WWWAUDIOFILE":["https://example.com/track"]}}`)
+	if !ok {
+		t.Fatal("expected provenance from tags")
+	}
+	if provenance.PurchasedFrom != "Bandcamp" {
+		t.Fatalf("expected purchase source Bandcamp, got %q", provenance.PurchasedFrom)
+	}
+	if provenance.RippedFrom != "CD with EAC" {
+		t.Fatalf("expected rip source, got %q", provenance.RippedFrom)
+	}
+	if provenance.DownloadSource != "https://example.com/track" {
+		t.Fatalf("expected download source from WWWAUDIOFILE, got %q", provenance.DownloadSource)
+	}
+
+	provenance, ok = provenanceFromTags(`{"taglib_tags":{"COMMENT":["Downloaded from the artist site"]}}`)
+	if !ok || provenance.DownloadSource != "the artist site" {
+		t.Fatalf("expected download source from comment, got %+v", provenance)
+	}
+
+	if _, ok := provenanceFromTags(`{"taglib_tags":{"COMMENT":["Great track"]}}`); ok {
+		t.Fatal("expected an unrelated comment to give no provenance")
+	}
+}
+
+func TestNormalizeProvenanceRejectsOverlongValues(t *testing.T) {
+	t.Parallel()
+
+	normalized, err := normalizeProvenance(TrackProvenance{PurchasedFrom: "  Record\tStore "})
+	if err != nil {
+		t.Fatalf("normalize provenance: %v", err)
+	}
+	if normalized.PurchasedFrom != "Record Store" {
+		t.Fatalf("expected collapsed whitespace, got %q", normalized.PurchasedFrom)
+	}
+
+	if _, err := normalizeProvenance(TrackProvenance{RippedFrom: strings.Repeat("x", maxProvenanceLength+1)}); err == nil {
+		t.Fatal("expected an overlong provenance to be rejected")
+	}
+}
@@ -0,0 +1,45 @@
+package library
+
+var losslessCodecs = map[string]struct{}{
+	"flac": {},
+	"alac": {},
+	"wav":  {},
+	"aiff": {},
+	"aif":  {},
+	"ape":  {},
+	"wv":   {},
+	"dsf":  {},
+	"dff":  {},
+}
+
+// AudioQuality is what the versions of a recording are ranked by, when
+// duplicates are hidden and when a queue swaps a track for its best
+// version.
+type AudioQuality struct {
+	Lossless   bool
+	BitDepth   int
+	SampleRate int
+	Bitrate    int
+}
+
+// IsLosslessCodec reports whether codec, as stored on tracks, is lossless.
+func IsLosslessCodec(codec string) bool {
+	_, ok := losslessCodecs[codec]
+	return ok
+}
+
+// BetterQuality ranks lossless files first, then bit depth, sample rate and
+// bitrate.
+func BetterQuality(candidate AudioQuality, current AudioQuality) bool {
+	if candidate.Lossless != current.Lossless {
+		return candidate.Lossless
+	}
+	if candidate.BitDepth != current.BitDepth {
+		return candidate.BitDepth > current.BitDepth
+	}
+	if candidate.SampleRate != current.SampleRate {
+		return candidate.SampleRate > current.SampleRate
+	}
+
+	return candidate.Bitrate > current.Bitrate
+}
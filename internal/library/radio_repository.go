@@ -0,0 +1,313 @@
+package library
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+)
+
+const artistRadioLength = 100
+
+// artistRadioFreshness is how many tracks must play before a track may come
+// back. A library too small to fill the radio yields a shorter list rather
+// than breaking the guarantee.
+const artistRadioFreshness = 50
+
+// artistRadioSeedEvery puts one track by the seed artist in every few slots.
+const artistRadioSeedEvery = 3
+
+// artistRadioEraYears is how far from the seed artist's median year a track
+// still counts as the same era.
+const artistRadioEraYears = 8
+
+// artistRadioMinScore keeps weak matches out of the radio.
+const artistRadioMinScore = 0.35
+
+// artistRadioJitter varies the order between runs among similarly scored
+// tracks.
+const artistRadioJitter = 0.15
+
+type radioTrack struct {
+	id        int64
+	artistKey string
+	genres    []string
+	year      int
+	completes int
+	skips     int
+	rating    int
+	favorite  bool
+	score     float64
+}
+
+type radioProfile struct {
+	genres     map[string]float64
+	medianYear int
+}
+
+// BuildArtistRadio returns an ordered list of track IDs for a radio seeded
+// by artist: the artist's own tracks mixed with tracks by other artists that
+// share its genres and era, ranked by how well they match and how the
+// listener has received them. Banned tracks are never picked, and no track
+// repeats within artistRadioFreshness entries.
+func (r *BrowseRepository) BuildArtistRadio(ctx context.Context, artist string) ([]int64, error) {
+	artistName := strings.TrimSpace(artist)
+	if artistName == "" {
+		return nil, errors.New("artist name is required")
+	}
+
+	tracks, err := r.listRadioTracks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seedKey := strings.ToLower(artistName)
+	seed := make([]radioTrack, 0)
+	others := make([]radioTrack, 0, len(tracks))
+	for _, track := range tracks {
+		if track.artistKey == seedKey {
+			seed = append(seed, track)
+		} else {
+			others = append(others, track)
+		}
+	}
+	if len(seed) == 0 {
+		return nil, ErrArtistNotFound
+	}
+
+	profile := buildRadioProfile(seed)
+	for index := range seed {
+		seed[index].score = radioAffinity(seed[index]) + rand.Float64()*artistRadioJitter
+	}
+	matches := make([]radioTrack, 0, len(others))
+	for _, track := range others {
+		score := scoreRadioTrack(profile, track)
+		if score < artistRadioMinScore {
+			continue
+		}
+		track.score = score + rand.Float64()*artistRadioJitter
+		matches = append(matches, track)
+	}
+
+	return mixArtistRadio(seed, matches, artistRadioLength, artistRadioFreshness), nil
+}
+
+func (r *BrowseRepository) listRadioTracks(ctx context.Context) ([]radioTrack, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		WITH track_metrics AS (
+			SELECT
+				track_id,
+				COALESCE(SUM(complete_count), 0) AS complete_count,
+				COALESCE(SUM(skip_count), 0) AS skip_count
+			FROM (
+				SELECT
+					track_id,
+					CASE WHEN event_type = 'complete' THEN 1 ELSE 0 END AS complete_count,
+					CASE WHEN event_type = 'skip' THEN 1 ELSE 0 END AS skip_count
+				FROM play_events
+				UNION ALL
+				SELECT track_id, complete_count, skip_count
+				FROM play_stats_combined
+			) metrics
+			GROUP BY track_id
+		)
+		SELECT
+			t.id,
+			LOWER(COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')),
+			COALESCE(t.genre, ''),
+			COALESCE(t.year, (
+				SELECT MAX(a.year)
+				FROM album_tracks at
+				JOIN albums a ON a.id = at.album_id
+				WHERE at.track_id = t.id
+			), 0),
+			COALESCE(tm.complete_count, 0),
+			COALESCE(tm.skip_count, 0),
+			COALESCE(tr.rating, 0),
+			COALESCE(tr.favorite, 0)
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN track_metrics tm ON tm.track_id = t.id
+		LEFT JOIN track_ratings tr ON tr.track_id = t.id
+		WHERE f.file_exists = 1
+		  AND f.is_audiobook = 0
+		  AND COALESCE(tr.banned, 0) = 0
+		ORDER BY t.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list radio tracks: %w", err)
+	}
+	defer rows.Close()
+
+	tracks := make([]radioTrack, 0)
+	for rows.Next() {
+		var track radioTrack
+		var genre string
+		var favorite int
+		var year sql.NullInt64
+		if err := rows.Scan(&track.id, &track.artistKey, &genre, &year, &track.completes, &track.skips, &track.rating, &favorite); err != nil {
+			return nil, fmt.Errorf("scan radio track: %w", err)
+		}
+		track.genres = splitGenres(genre)
+		track.year = int(year.Int64)
+		track.favorite = favorite == 1
+		tracks = append(tracks, track)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate radio tracks: %w", err)
+	}
+
+	return tracks, nil
+}
+
+// buildRadioProfile weighs each genre by the share of the seed artist's
+// tracks that carry it and takes the median year as the artist's era.
+func buildRadioProfile(seed []radioTrack) radioProfile {
+	profile := radioProfile{genres: make(map[string]float64)}
+	years := make([]int, 0, len(seed))
+	for _, track := range seed {
+		for _, genre := range track.genres {
+			profile.genres[genre] += 1 / float64(len(seed))
+		}
+		if track.year > 0 {
+			years = append(years, track.year)
+		}
+	}
+	if len(years) > 0 {
+		slices.Sort(years)
+		profile.medianYear = years[len(years)/2]
+	}
+
+	return profile
+}
+
+// scoreRadioTrack rates a track by another artist between 0 and 1. Genre
+// weighs most; when the seed artist has no genre tags, era and affinity
+// decide alone. Unknown years count as a half match.
+func scoreRadioTrack(profile radioProfile, track radioTrack) float64 {
+	genreScore := 0.0
+	for _, genre := range track.genres {
+		genreScore = math.Max(genreScore, profile.genres[genre])
+	}
+	if len(profile.genres) > 0 && genreScore == 0 {
+		return 0
+	}
+	if len(profile.genres) == 0 {
+		genreScore = 0.5
+	}
+
+	eraScore := 0.5
+	if profile.medianYear > 0 && track.year > 0 {
+		distance := math.Abs(float64(track.year - profile.medianYear))
+		eraScore = math.Max(0, 1-distance/artistRadioEraYears)
+	}
+
+	return 0.5*genreScore + 0.3*eraScore + 0.2*radioAffinity(track)
+}
+
+// radioAffinity turns plays, ratings and favorites into a score between 0
+// and 1, where 0.5 is a track the listener has no opinion of yet.
+func radioAffinity(track radioTrack) float64 {
+	affinity := 0.5 + 0.05*float64(track.completes-track.skips)
+	if track.rating > 0 {
+		affinity += 0.1 * float64(track.rating-3)
+	}
+	if track.favorite {
+		affinity += 0.2
+	}
+
+	return math.Max(0, math.Min(1, affinity))
+}
+
+// mixArtistRadio interleaves the seed artist's tracks with the matches, best
+// first, putting a seed track in every artistRadioSeedEvery slots and
+// avoiding the same other artist twice in a row. A track only returns after
+// freshness other entries; the radio ends early when nothing can be played
+// without breaking that.
+func mixArtistRadio(seed []radioTrack, matches []radioTrack, length int, freshness int) []int64 {
+	byScore := func(a radioTrack, b radioTrack) int {
+		if order := cmp.Compare(b.score, a.score); order != 0 {
+			return order
+		}
+		return cmp.Compare(a.id, b.id)
+	}
+	seed = slices.Clone(seed)
+	matches = slices.Clone(matches)
+	slices.SortStableFunc(seed, byScore)
+	slices.SortStableFunc(matches, byScore)
+
+	radio := make([]int64, 0, length)
+	lastPlayed := make(map[int64]int)
+	fresh := func(track radioTrack) bool {
+		index, played := lastPlayed[track.id]
+		return !played || len(radio)-index > freshness
+	}
+
+	lastArtist := ""
+	for len(radio) < length {
+		var track radioTrack
+		ok := false
+		if len(radio)%artistRadioSeedEvery == 0 {
+			track, ok = pickRadioTrack(seed, fresh, "")
+		}
+		if !ok {
+			track, ok = pickRadioTrack(matches, fresh, lastArtist)
+		}
+		if !ok {
+			track, ok = pickRadioTrack(seed, fresh, "")
+		}
+		if !ok {
+			break
+		}
+
+		lastPlayed[track.id] = len(radio)
+		lastArtist = track.artistKey
+		radio = append(radio, track.id)
+	}
+
+	return radio
+}
+
+// pickRadioTrack returns the best fresh track, preferably not by
+// avoidArtist.
+func pickRadioTrack(tracks []radioTrack, fresh func(radioTrack) bool, avoidArtist string) (radioTrack, bool) {
+	fallback := -1
+	for index, track := range tracks {
+		if !fresh(track) {
+			continue
+		}
+		if avoidArtist == "" || track.artistKey != avoidArtist {
+			return track, true
+		}
+		if fallback < 0 {
+			fallback = index
+		}
+	}
+	if fallback >= 0 {
+		return tracks[fallback], true
+	}
+
+	return radioTrack{}, false
+}
+
+// splitGenres normalizes a genre tag into its lowercase parts, e.g.
+// "Rock; Post-Punk" into "rock" and "post-punk".
+func splitGenres(genre string) []string {
+	parts := strings.FieldsFunc(strings.ToLower(genre), func(r rune) bool {
+		return r == ';' || r == '/' || r == ',' || r == '|' || r == '\x00'
+	})
+
+	genres := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if trimmed := strings.TrimSpace(part); trimmed != "" && !slices.Contains(genres, trimmed) {
+			genres = append(genres, trimmed)
+		}
+	}
+
+	return genres
+}
@@ -0,0 +1,67 @@
+package library
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMixArtistRadioInterleavesSeedTracks(t *testing.T) {
+	t.Parallel()
+
+	seed := []radioTrack{
+		{id: 1, artistKey: "seed", score: 0.9},
+		{id: 2, artistKey: "seed", score: 0.8},
+	}
+	matches := []radioTrack{
+		{id: 10, artistKey: "a", score: 0.9},
+		{id: 11, artistKey: "a", score: 0.8},
+		{id: 12, artistKey: "b", score: 0.7},
+		{id: 13, artistKey: "c", score: 0.6},
+	}
+
+	radio := mixArtistRadio(seed, matches, 6, 10)
+	want := []int64{1, 10, 12, 2, 11, 13}
+	if !reflect.DeepEqual(radio, want) {
+		t.Fatalf("radio = %v, want %v", radio, want)
+	}
+}
+
+func TestMixArtistRadioKeepsTracksFresh(t *testing.T) {
+	t.Parallel()
+
+	seed := []radioTrack{{id: 1, artistKey: "seed", score: 1}}
+	matches := []radioTrack{
+		{id: 10, artistKey: "a", score: 0.9},
+		{id: 11, artistKey: "b", score: 0.8},
+	}
+
+	radio := mixArtistRadio(seed, matches, 20, 3)
+	lastSeen := make(map[int64]int)
+	for index, trackID := range radio {
+		if previous, seen := lastSeen[trackID]; seen && index-previous <= 3 {
+			t.Fatalf("track %d repeats after %d entries in %v", trackID, index-previous, radio)
+		}
+		lastSeen[trackID] = index
+	}
+	if len(radio) != 3 {
+		t.Fatalf("expected a pool of 3 tracks to stop after 3 entries with freshness 3, got %v", radio)
+	}
+}
+
+func TestScoreRadioTrackNeedsASharedGenre(t *testing.T) {
+	t.Parallel()
+
+	profile := buildRadioProfile([]radioTrack{
+		{genres: splitGenres("Post-Punk; Rock"), year: 1980},
+		{genres: splitGenres("post-punk"), year: 1982},
+	})
+
+	closeMatch := scoreRadioTrack(profile, radioTrack{genres: []string{"post-punk"}, year: 1981})
+	otherEra := scoreRadioTrack(profile, radioTrack{genres: []string{"post-punk"}, year: 2015})
+	if closeMatch <= otherEra {
+		t.Fatalf("expected the same era to score higher, got %v and %v", closeMatch, otherEra)
+	}
+	if score := scoreRadioTrack(profile, radioTrack{genres: []string{"jazz"}, year: 1981}); score != 0 {
+		t.Fatalf("expected no score without a shared genre, got %v", score)
+	}
+}
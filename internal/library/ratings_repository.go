@@ -0,0 +1,271 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidRating = errors.New("rating must be between 1 and 5")
+
+type TrackRating struct {
+	TrackID   int64  `json:"trackId"`
+	Rating    *int   `json:"rating,omitempty"`
+	Favorite  bool   `json:"favorite"`
+	Banned    bool   `json:"banned"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+type AlbumRating struct {
+	Title       string `json:"title"`
+	AlbumArtist string `json:"albumArtist"`
+	Rating      *int   `json:"rating,omitempty"`
+	Favorite    bool   `json:"favorite"`
+	UpdatedAt   string `json:"updatedAt,omitempty"`
+}
+
+// RatingFilter narrows track and album listings to favorites or to a
+// minimum star rating; the zero value does not filter.
+type RatingFilter struct {
+	FavoritesOnly bool `json:"favoritesOnly"`
+	MinRating     int  `json:"minRating"`
+}
+
+type RatingRepository struct {
+	db *sql.DB
+}
+
+func NewRatingRepository(database *sql.DB) *RatingRepository {
+	return &RatingRepository{db: database}
+}
+
+// SetFavorite marks or unmarks a track as a favorite. Unfavoriting keeps the
+// row with a fresh timestamp so device sync can tell a removal from a
+// favorite that was never set.
+func (r *RatingRepository) SetFavorite(ctx context.Context, trackID int64, favorite bool) (TrackRating, error) {
+	favoriteInt := 0
+	if favorite {
+		favoriteInt = 1
+	}
+
+	return r.upsert(
+		ctx,
+		trackID,
+		`INSERT INTO track_ratings(track_id, favorite, updated_at)
+		 VALUES (?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+		 ON CONFLICT(track_id) DO UPDATE SET
+		 	favorite = excluded.favorite,
+		 	updated_at = excluded.updated_at`,
+		trackID,
+		favoriteInt,
+	)
+}
+
+// SetRating stores a 1-5 star rating; a rating of 0 clears it.
+func (r *RatingRepository) SetRating(ctx context.Context, trackID int64, rating int) (TrackRating, error) {
+	if rating < 0 || rating > 5 {
+		return TrackRating{}, ErrInvalidRating
+	}
+
+	return r.upsert(
+		ctx,
+		trackID,
+		`INSERT INTO track_ratings(track_id, rating, updated_at)
+		 VALUES (?, NULLIF(?, 0), strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+		 ON CONFLICT(track_id) DO UPDATE SET
+		 	rating = excluded.rating,
+		 	updated_at = excluded.updated_at`,
+		trackID,
+		rating,
+	)
+}
+
+// SetBanned marks a track as "never play this". Banned tracks stay browsable
+// and play when picked directly, but shuffle and autoplay skip them.
+func (r *RatingRepository) SetBanned(ctx context.Context, trackID int64, banned bool) (TrackRating, error) {
+	bannedInt := 0
+	if banned {
+		bannedInt = 1
+	}
+
+	return r.upsert(
+		ctx,
+		trackID,
+		`INSERT INTO track_ratings(track_id, banned, updated_at)
+		 VALUES (?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+		 ON CONFLICT(track_id) DO UPDATE SET
+		 	banned = excluded.banned,
+		 	updated_at = excluded.updated_at`,
+		trackID,
+		bannedInt,
+	)
+}
+
+func (r *RatingRepository) ListBannedTrackIDs(ctx context.Context) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT track_id FROM track_ratings WHERE banned = 1 ORDER BY track_id")
+	if err != nil {
+		return nil, fmt.Errorf("list banned tracks: %w", err)
+	}
+	defer rows.Close()
+
+	trackIDs := make([]int64, 0)
+	for rows.Next() {
+		var trackID int64
+		if scanErr := rows.Scan(&trackID); scanErr != nil {
+			return nil, fmt.Errorf("scan banned track row: %w", scanErr)
+		}
+		trackIDs = append(trackIDs, trackID)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate banned track rows: %w", rowsErr)
+	}
+
+	return trackIDs, nil
+}
+
+func (r *RatingRepository) Get(ctx context.Context, trackID int64) (TrackRating, error) {
+	rating := TrackRating{TrackID: trackID}
+	var ratingValue sql.NullInt64
+	var favoriteInt int
+	var bannedInt int
+	err := r.db.QueryRowContext(
+		ctx,
+		"SELECT rating, favorite, banned, updated_at FROM track_ratings WHERE track_id = ?",
+		trackID,
+	).Scan(&ratingValue, &favoriteInt, &bannedInt, &rating.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return rating, nil
+	}
+	if err != nil {
+		return TrackRating{}, fmt.Errorf("get rating for track %d: %w", trackID, err)
+	}
+
+	if ratingValue.Valid {
+		value := int(ratingValue.Int64)
+		rating.Rating = &value
+	}
+	rating.Favorite = favoriteInt == 1
+	rating.Banned = bannedInt == 1
+
+	return rating, nil
+}
+
+// SetAlbumFavorite marks or unmarks an album as a favorite. Albums are
+// keyed by title and album artist as listed, since scans rebuild album ids.
+func (r *RatingRepository) SetAlbumFavorite(ctx context.Context, title string, albumArtist string, favorite bool) (AlbumRating, error) {
+	favoriteInt := 0
+	if favorite {
+		favoriteInt = 1
+	}
+
+	return r.upsertAlbum(
+		ctx,
+		title,
+		albumArtist,
+		`INSERT INTO album_ratings(title, album_artist, favorite, updated_at)
+		 VALUES (?, ?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+		 ON CONFLICT(title, album_artist) DO UPDATE SET
+		 	favorite = excluded.favorite,
+		 	updated_at = excluded.updated_at`,
+		favoriteInt,
+	)
+}
+
+// SetAlbumRating stores a 1-5 star album rating; a rating of 0 clears it.
+func (r *RatingRepository) SetAlbumRating(ctx context.Context, title string, albumArtist string, rating int) (AlbumRating, error) {
+	if rating < 0 || rating > 5 {
+		return AlbumRating{}, ErrInvalidRating
+	}
+
+	return r.upsertAlbum(
+		ctx,
+		title,
+		albumArtist,
+		`INSERT INTO album_ratings(title, album_artist, rating, updated_at)
+		 VALUES (?, ?, NULLIF(?, 0), strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+		 ON CONFLICT(title, album_artist) DO UPDATE SET
+		 	rating = excluded.rating,
+		 	updated_at = excluded.updated_at`,
+		rating,
+	)
+}
+
+func (r *RatingRepository) GetAlbumRating(ctx context.Context, title string, albumArtist string) (AlbumRating, error) {
+	albumTitle := strings.TrimSpace(title)
+	artistName := strings.TrimSpace(albumArtist)
+	rating := AlbumRating{Title: albumTitle, AlbumArtist: artistName}
+
+	var ratingValue sql.NullInt64
+	var favoriteInt int
+	err := r.db.QueryRowContext(
+		ctx,
+		"SELECT rating, favorite, updated_at FROM album_ratings WHERE title = ? AND album_artist = ?",
+		albumTitle,
+		artistName,
+	).Scan(&ratingValue, &favoriteInt, &rating.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return rating, nil
+	}
+	if err != nil {
+		return AlbumRating{}, fmt.Errorf("get rating for album %q by %q: %w", albumTitle, artistName, err)
+	}
+
+	rating.Rating = intPointer(ratingValue)
+	rating.Favorite = favoriteInt == 1
+
+	return rating, nil
+}
+
+func (r *RatingRepository) upsertAlbum(ctx context.Context, title string, albumArtist string, query string, value int) (AlbumRating, error) {
+	albumTitle := strings.TrimSpace(title)
+	artistName := strings.TrimSpace(albumArtist)
+	if albumTitle == "" || artistName == "" {
+		return AlbumRating{}, ErrAlbumNotFound
+	}
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(1)
+		FROM albums a
+		WHERE LOWER(COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album')) = LOWER(?)
+		  AND LOWER(COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist')) = LOWER(?)
+	`, albumTitle, artistName).Scan(&count); err != nil {
+		return AlbumRating{}, fmt.Errorf("check album %q by %q: %w", albumTitle, artistName, err)
+	}
+	if count == 0 {
+		return AlbumRating{}, ErrAlbumNotFound
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, albumTitle, artistName, value); err != nil {
+		return AlbumRating{}, fmt.Errorf("update rating for album %q by %q: %w", albumTitle, artistName, err)
+	}
+
+	return r.GetAlbumRating(ctx, albumTitle, artistName)
+}
+
+func (r *RatingRepository) upsert(ctx context.Context, trackID int64, query string, args ...any) (TrackRating, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return TrackRating{}, fmt.Errorf("begin track rating tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := ensureTrackExists(ctx, tx, trackID); err != nil {
+		return TrackRating{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return TrackRating{}, fmt.Errorf("update rating for track %d: %w", trackID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return TrackRating{}, fmt.Errorf("commit track rating tx: %w", err)
+	}
+
+	return r.Get(ctx, trackID)
+}
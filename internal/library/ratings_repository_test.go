@@ -0,0 +1,23 @@
+package library
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAppendRatingFilterNarrowsFavoritesAndMinimumRating(t *testing.T) {
+	t.Parallel()
+
+	whereClauses, args := appendRatingFilter([]string{"1 = 1"}, nil, "tr", RatingFilter{})
+	if !slices.Equal(whereClauses, []string{"1 = 1"}) || len(args) != 0 {
+		t.Fatalf("expected the zero filter not to narrow, got %q %v", whereClauses, args)
+	}
+
+	whereClauses, args = appendRatingFilter([]string{"1 = 1"}, nil, "ar", RatingFilter{FavoritesOnly: true, MinRating: 4})
+	if !slices.Equal(whereClauses, []string{"1 = 1", "ar.favorite = 1", "ar.rating >= ?"}) {
+		t.Fatalf("unexpected where clauses %q", whereClauses)
+	}
+	if len(args) != 1 || args[0] != 4 {
+		t.Fatalf("unexpected args %v", args)
+	}
+}
@@ -0,0 +1,195 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// recentPlaysSQL finds when each track was last played, from raw events and
+// from the daily rollups once the events are pruned. Skips do not count as
+// plays here.
+const recentPlaysSQL = `
+	WITH plays AS (
+		SELECT track_id, MAX(ts) AS last_played_at
+		FROM play_events
+		WHERE event_type IN ('complete', 'partial')
+		GROUP BY track_id
+		UNION ALL
+		SELECT track_id, MAX(day) AS last_played_at
+		FROM play_stats_combined
+		WHERE complete_count + partial_count > 0
+		GROUP BY track_id
+	),
+	recent_plays AS (
+		SELECT track_id, MAX(last_played_at) AS last_played_at
+		FROM plays
+		GROUP BY track_id
+	)
+`
+
+// ListRecentlyAdded pages through albums newest first, by when a scan first
+// indexed their files. Files indexed in one scan share a time, so ties fall
+// back to the order the files were indexed in.
+func (r *BrowseRepository) ListRecentlyAdded(ctx context.Context, limit int, offset int) (AlbumsPage, error) {
+	limit, offset = normalizePagination(limit, offset, defaultBrowseLimit)
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(1) FROM albums").Scan(&total); err != nil {
+		return AlbumsPage{}, fmt.Errorf("count albums: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album') AS album_title,
+			COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist') AS album_artist_name,
+			a.year,
+			COUNT(1) AS track_count,
+			cover.cache_path,
+			ar.rating,
+			COALESCE(ar.favorite, 0),
+			MIN(f.first_seen_at) AS added_at
+		FROM albums a
+		JOIN album_tracks at ON at.album_id = a.id
+		JOIN tracks t ON t.id = at.track_id
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN covers cover ON cover.id = a.cover_id`+albumRatingJoinSQL+`
+		WHERE f.file_exists = 1
+		  AND f.is_audiobook = 0
+		GROUP BY a.id
+		ORDER BY added_at DESC, MAX(f.id) DESC
+		LIMIT ?
+		OFFSET ?
+	`, limit, offset)
+	if err != nil {
+		return AlbumsPage{}, fmt.Errorf("list recently added albums: %w", err)
+	}
+	defer rows.Close()
+
+	albums := make([]AlbumSummary, 0)
+	for rows.Next() {
+		var album AlbumSummary
+		var year sql.NullInt64
+		var coverPath sql.NullString
+		var rating sql.NullInt64
+		var addedAt sql.NullString
+		if scanErr := rows.Scan(&album.Title, &album.AlbumArtist, &year, &album.TrackCount, &coverPath, &rating, &album.Favorite, &addedAt); scanErr != nil {
+			return AlbumsPage{}, fmt.Errorf("scan recently added album row: %w", scanErr)
+		}
+		album.Year = intPointer(year)
+		album.CoverPath = stringPointer(coverPath)
+		album.Rating = intPointer(rating)
+		album.AddedAt = stringPointer(addedAt)
+		albums = append(albums, album)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return AlbumsPage{}, fmt.Errorf("iterate recently added album rows: %w", rowsErr)
+	}
+
+	return AlbumsPage{
+		Items: albums,
+		Page: PageInfo{
+			Limit:  limit,
+			Offset: offset,
+			Total:  total,
+		},
+	}, nil
+}
+
+// ListRecentlyPlayed pages through played tracks, most recently played
+// first, with LastPlayedAt set.
+func (r *BrowseRepository) ListRecentlyPlayed(ctx context.Context, limit int, offset int) (TracksPage, error) {
+	limit, offset = normalizePagination(limit, offset, defaultBrowseLimit)
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, recentPlaysSQL+`
+		SELECT COUNT(1)
+		FROM recent_plays rp
+		JOIN tracks t ON t.id = rp.track_id
+		JOIN files f ON f.id = t.file_id
+		WHERE f.file_exists = 1
+		  AND f.is_audiobook = 0
+	`).Scan(&total); err != nil {
+		return TracksPage{}, fmt.Errorf("count recently played tracks: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, recentPlaysSQL+`
+		SELECT
+			t.id,
+			COALESCE(NULLIF(TRIM(t.title), ''), 'Unknown Title') AS track_title,
+			COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist') AS track_artist,
+			COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album') AS track_album,
+			COALESCE(NULLIF(TRIM(t.album_artist), ''), COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) AS track_album_artist,
+			t.disc_no,
+			t.track_no,
+			t.duration_ms,
+			f.path,
+			cover.cache_path,
+			tr.rating,
+			COALESCE(tr.favorite, 0),
+			rp.last_played_at
+		FROM recent_plays rp
+		JOIN tracks t ON t.id = rp.track_id
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN covers cover ON cover.source_file_id = t.file_id
+		LEFT JOIN track_ratings tr ON tr.track_id = t.id
+		WHERE f.file_exists = 1
+		  AND f.is_audiobook = 0
+		ORDER BY rp.last_played_at DESC, t.id DESC
+		LIMIT ?
+		OFFSET ?
+	`, limit, offset)
+	if err != nil {
+		return TracksPage{}, fmt.Errorf("list recently played tracks: %w", err)
+	}
+	defer rows.Close()
+
+	tracks := make([]TrackSummary, 0)
+	for rows.Next() {
+		var track TrackSummary
+		var discNo sql.NullInt64
+		var trackNo sql.NullInt64
+		var durationMS sql.NullInt64
+		var coverPath sql.NullString
+		var rating sql.NullInt64
+		var lastPlayedAt sql.NullString
+		if scanErr := rows.Scan(
+			&track.ID,
+			&track.Title,
+			&track.Artist,
+			&track.Album,
+			&track.AlbumArtist,
+			&discNo,
+			&trackNo,
+			&durationMS,
+			&track.Path,
+			&coverPath,
+			&rating,
+			&track.Favorite,
+			&lastPlayedAt,
+		); scanErr != nil {
+			return TracksPage{}, fmt.Errorf("scan recently played track row: %w", scanErr)
+		}
+		track.DiscNo = intPointer(discNo)
+		track.TrackNo = intPointer(trackNo)
+		track.DurationMS = intPointer(durationMS)
+		track.CoverPath = stringPointer(coverPath)
+		track.Rating = intPointer(rating)
+		track.LastPlayedAt = stringPointer(lastPlayedAt)
+		tracks = append(tracks, track)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return TracksPage{}, fmt.Errorf("iterate recently played track rows: %w", rowsErr)
+	}
+
+	return TracksPage{
+		Items: tracks,
+		Page: PageInfo{
+			Limit:  limit,
+			Offset: offset,
+			Total:  total,
+		},
+	}, nil
+}
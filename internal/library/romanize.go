@@ -0,0 +1,152 @@
+package library
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// RomanizedAlias returns the Latin alias a name in another script is also
+// searched and sorted by: its sort tag, such as ARTISTSORT, when that is
+// written in Latin, otherwise a transliteration of its Cyrillic, kana or
+// Hangul letters. Han characters have no reading to transliterate and are
+// left out. It returns "" when the name needs no alias.
+func RomanizedAlias(name string, sortName string) string {
+	name = strings.TrimSpace(name)
+	sortName = strings.TrimSpace(sortName)
+
+	if script, _ := DetectScript(name); script == "" || script == ScriptLatin {
+		return ""
+	}
+
+	if script, _ := DetectScript(sortName); script == ScriptLatin {
+		return sortName
+	}
+
+	return Romanize(name)
+}
+
+// Romanize transliterates Cyrillic, kana and Hangul letters into lowercase
+// Latin. Latin letters, digits and spaces are kept; other characters are
+// dropped. It returns "" when nothing is left to read.
+func Romanize(text string) string {
+	runes := []rune(norm.NFKC.String(text))
+
+	var builder strings.Builder
+	for index := 0; index < len(runes); index++ {
+		r := unicode.ToLower(runes[index])
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			builder.WriteString(cyrillicLatin[r])
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana) || r == 'ー':
+			consumed := romanizeKana(&builder, runes[index:])
+			index += consumed - 1
+		case r >= 0xAC00 && r <= 0xD7A3:
+			builder.WriteString(romanizeHangul(r))
+		case unicode.Is(unicode.Latin, r) || unicode.IsDigit(r):
+			builder.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-' || r == '\'':
+			builder.WriteRune(r)
+		}
+	}
+
+	romanized := strings.Join(strings.Fields(builder.String()), " ")
+	if !strings.ContainsFunc(romanized, unicode.IsLetter) {
+		return ""
+	}
+
+	return romanized
+}
+
+var cyrillicLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u",
+	'ђ': "dj", 'ј': "j", 'љ': "lj", 'њ': "nj", 'ћ': "c", 'џ': "dz",
+}
+
+// kanaLatin is modified Hepburn for single hiragana; katakana is read
+// through katakanaToHiragana first.
+var kanaLatin = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n", 'ゔ': "vu",
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+	'ゃ': "ya", 'ゅ': "yu", 'ょ': "yo", 'ゎ': "wa",
+}
+
+// romanizeKana writes the reading of the kana at the start of runes and
+// returns how many runes it read: a syllable with a following small ya, yu
+// or yo is read as one, and a small tsu doubles the next consonant.
+func romanizeKana(builder *strings.Builder, runes []rune) int {
+	current := katakanaToHiragana(runes[0])
+	if current == 'ー' {
+		return 1
+	}
+
+	if current == 'っ' {
+		if len(runes) > 1 {
+			next := kanaLatin[katakanaToHiragana(runes[1])]
+			if next != "" && !strings.ContainsRune("aiueon", rune(next[0])) {
+				if strings.HasPrefix(next, "ch") {
+					builder.WriteByte('t')
+				} else {
+					builder.WriteByte(next[0])
+				}
+			}
+		}
+		return 1
+	}
+
+	reading := kanaLatin[current]
+	if len(runes) > 1 && reading != "" {
+		switch small := katakanaToHiragana(runes[1]); small {
+		case 'ゃ', 'ゅ', 'ょ':
+			vowel := kanaLatin[small][1:]
+			switch {
+			case strings.HasPrefix(reading, "sh"), strings.HasPrefix(reading, "ch"), reading == "ji":
+				builder.WriteString(reading[:len(reading)-1] + vowel)
+				return 2
+			case strings.HasSuffix(reading, "i") && len(reading) == 2:
+				builder.WriteString(reading[:1] + "y" + vowel)
+				return 2
+			}
+		}
+	}
+
+	builder.WriteString(reading)
+	return 1
+}
+
+var (
+	hangulInitialLatin = []string{"g", "kk", "n", "d", "tt", "r", "m", "b", "pp", "s", "ss", "", "j", "jj", "ch", "k", "t", "p", "h"}
+	hangulMedialLatin  = []string{"a", "ae", "ya", "yae", "eo", "e", "yeo", "ye", "o", "wa", "wae", "oe", "yo", "u", "wo", "we", "wi", "yu", "eu", "ui", "i"}
+	hangulFinalLatin   = []string{"", "k", "k", "k", "n", "n", "n", "t", "l", "k", "m", "l", "l", "l", "p", "l", "m", "p", "p", "t", "t", "ng", "t", "t", "k", "t", "p", "t"}
+)
+
+// romanizeHangul reads a Hangul syllable in the Revised Romanization,
+// syllable by syllable without the sound changes across syllables.
+func romanizeHangul(r rune) string {
+	offset := int(r - 0xAC00)
+	initial := offset / 588
+	medial := (offset % 588) / 28
+	final := offset % 28
+
+	return hangulInitialLatin[initial] + hangulMedialLatin[medial] + hangulFinalLatin[final]
+}
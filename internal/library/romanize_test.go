@@ -0,0 +1,44 @@
+package library
+
+import "testing"
+
+func TestRomanizeReadsCyrillicKanaAndHangul(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"Земфира":     "zemfira",
+		"Океан Ельзи": "okean elzi",
+		"ゆず":          "yuzu",
+		"きゃりーぱみゅぱみゅ":  "kyaripamyupamyu",
+		"がっこう":        "gakkou",
+		"ガガガSP":       "gagagasp",
+		"방탄소년단":       "bangtansonyeondan",
+		"宇多田ヒカル":      "hikaru",
+		"坂本龍一":        "",
+	}
+	for input, want := range cases {
+		if got := Romanize(input); got != want {
+			t.Fatalf("Romanize(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestRomanizedAliasPrefersLatinSortTag(t *testing.T) {
+	t.Parallel()
+
+	if got := RomanizedAlias("宇多田ヒカル", "Utada, Hikaru"); got != "Utada, Hikaru" {
+		t.Fatalf("expected the sort tag, got %q", got)
+	}
+	if got := RomanizedAlias("Кино", ""); got != "kino" {
+		t.Fatalf("expected a transliteration, got %q", got)
+	}
+	if got := RomanizedAlias("Beatles", "Beatles, The"); got != "" {
+		t.Fatalf("expected no alias for a Latin name, got %q", got)
+	}
+	if got := RomanizedAlias("Miles Davis", ""); got != "" {
+		t.Fatalf("expected no alias for a Latin name, got %q", got)
+	}
+	if got := RomanizedAlias("Кино", "кино"); got != "kino" {
+		t.Fatalf("expected a Cyrillic sort tag to be transliterated, got %q", got)
+	}
+}
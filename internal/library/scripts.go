@@ -0,0 +1,252 @@
+package library
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Scripts detected in track metadata. ScriptCJK covers Han, kana and Hangul.
+const (
+	ScriptLatin    = "latin"
+	ScriptCyrillic = "cyrillic"
+	ScriptCJK      = "cjk"
+	ScriptOther    = "other"
+)
+
+// Languages guessed from the script. Latin text is not guessed; its
+// language cannot be told from the letters alone.
+const (
+	LanguageJapanese   = "ja"
+	LanguageKorean     = "ko"
+	LanguageChinese    = "zh"
+	LanguageRussian    = "ru"
+	LanguageUkrainian  = "uk"
+	LanguageBelarusian = "be"
+	LanguageSerbian    = "sr"
+)
+
+// HanSection is the index section of names starting with a Han character,
+// which have no reading to sort them into smaller sections by.
+const HanSection = "漢"
+
+// DigitSection is the index section of names starting with a digit or
+// without any letter.
+const DigitSection = "#"
+
+// DetectScript returns the script texts are written in and a guess at the
+// language, both empty when texts have no letters.
+func DetectScript(texts ...string) (string, string) {
+	counts := map[string]int{}
+	var kana, hangul, han int
+	var ukrainian, belarusian, serbian bool
+
+	for _, text := range texts {
+		for _, r := range text {
+			script := runeScript(r)
+			if script == "" {
+				continue
+			}
+			counts[script]++
+
+			switch {
+			case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+				kana++
+			case unicode.Is(unicode.Hangul, r):
+				hangul++
+			case unicode.Is(unicode.Han, r):
+				han++
+			}
+			switch unicode.ToLower(r) {
+			case 'і', 'ї', 'є', 'ґ':
+				ukrainian = true
+			case 'ў':
+				belarusian = true
+			case 'ђ', 'ј', 'љ', 'њ', 'ћ', 'џ':
+				serbian = true
+			}
+		}
+	}
+
+	// Latin mixes freely into other scripts, as romanized artist names or
+	// "feat." and "Remix", so any other script present wins over it.
+	best := ""
+	for _, script := range []string{ScriptCyrillic, ScriptCJK, ScriptOther} {
+		if counts[script] > counts[best] {
+			best = script
+		}
+	}
+	if best == "" && counts[ScriptLatin] > 0 {
+		best = ScriptLatin
+	}
+
+	switch best {
+	case ScriptCJK:
+		switch {
+		case kana > 0 && kana >= hangul:
+			return best, LanguageJapanese
+		case hangul > 0:
+			return best, LanguageKorean
+		case han > 0:
+			return best, LanguageChinese
+		}
+	case ScriptCyrillic:
+		switch {
+		case ukrainian:
+			return best, LanguageUkrainian
+		case belarusian:
+			return best, LanguageBelarusian
+		case serbian:
+			return best, LanguageSerbian
+		default:
+			return best, LanguageRussian
+		}
+	}
+
+	return best, ""
+}
+
+func runeScript(r rune) string {
+	switch {
+	case !unicode.IsLetter(r):
+		return ""
+	case unicode.Is(unicode.Latin, r):
+		return ScriptLatin
+	case unicode.Is(unicode.Cyrillic, r):
+		return ScriptCyrillic
+	case isCJK(r):
+		return ScriptCJK
+	default:
+		return ScriptOther
+	}
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) || r == 'ー'
+}
+
+// SearchText folds texts into the form track search matches against:
+// lowercased, full-width letters narrowed, katakana read as hiragana and
+// spaces between CJK characters dropped, since CJK titles are spaced
+// inconsistently. Texts are kept on separate lines so a term never
+// matches across two fields.
+func SearchText(texts ...string) string {
+	lines := make([]string, 0, len(texts))
+	for _, text := range texts {
+		if folded := foldSearchText(text); folded != "" {
+			lines = append(lines, folded)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SearchTerms splits a search into the folded terms that must all match.
+func SearchTerms(search string) []string {
+	return strings.Fields(foldSearchText(search))
+}
+
+func foldSearchText(text string) string {
+	folded := []rune(strings.ToLower(norm.NFKC.String(strings.TrimSpace(text))))
+
+	var builder strings.Builder
+	for index := 0; index < len(folded); index++ {
+		r := folded[index]
+		if unicode.IsSpace(r) {
+			end := index
+			for end < len(folded) && unicode.IsSpace(folded[end]) {
+				end++
+			}
+			if index > 0 && end < len(folded) && isCJK(folded[index-1]) && isCJK(folded[end]) {
+				index = end - 1
+				continue
+			}
+			builder.WriteRune(' ')
+			index = end - 1
+			continue
+		}
+		builder.WriteRune(katakanaToHiragana(r))
+	}
+
+	return strings.TrimSpace(builder.String())
+}
+
+func katakanaToHiragana(r rune) rune {
+	if r >= 'ァ' && r <= 'ヶ' {
+		return r - ('ァ' - 'ぁ')
+	}
+	return r
+}
+
+// hangulInitials are the initial consonants of Hangul syllables in
+// Unicode order; doubled consonants share the section of the single one.
+var hangulInitials = []rune("ㄱㄱㄴㄷㄷㄹㅁㅂㅂㅅㅅㅇㅈㅈㅊㅋㅌㅍㅎ")
+
+// kanaRows are the first hiragana of each gojūon row, used as the index
+// sections of Japanese names.
+var kanaRows = []struct {
+	last rune
+	head string
+}{
+	{'お', "あ"},
+	{'ご', "か"},
+	{'ぞ', "さ"},
+	{'ど', "た"},
+	{'の', "な"},
+	{'ぽ', "は"},
+	{'も', "ま"},
+	{'よ', "や"},
+	{'ろ', "ら"},
+	{'ゖ', "わ"},
+}
+
+// SectionOf returns the jump-to section a name is listed under and the
+// section's script: A–Z for Latin names, the capital letter for Cyrillic,
+// the gojūon row for kana, the initial consonant for Hangul and one shared
+// section for Han.
+func SectionOf(name string) (string, string) {
+	for _, r := range norm.NFKC.String(name) {
+		if unicode.IsDigit(r) {
+			return DigitSection, ""
+		}
+		if !unicode.IsLetter(r) {
+			continue
+		}
+
+		switch runeScript(r) {
+		case ScriptLatin:
+			base := []rune(norm.NFD.String(string(r)))[0]
+			return string(unicode.ToUpper(base)), ScriptLatin
+		case ScriptCyrillic:
+			return string(unicode.ToUpper(r)), ScriptCyrillic
+		case ScriptCJK:
+			return cjkSection(r), ScriptCJK
+		default:
+			return string(unicode.ToUpper(r)), ScriptOther
+		}
+	}
+
+	return DigitSection, ""
+}
+
+func cjkSection(r rune) string {
+	switch {
+	case r >= 0xAC00 && r <= 0xD7A3:
+		return string(hangulInitials[(r-0xAC00)/588])
+	case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+		r = katakanaToHiragana(r)
+		if r == 'ゔ' {
+			return "あ"
+		}
+		for _, row := range kanaRows {
+			if r <= row.last {
+				return row.head
+			}
+		}
+		return "わ"
+	case unicode.Is(unicode.Hangul, r):
+		return string(r)
+	default:
+		return HanSection
+	}
+}
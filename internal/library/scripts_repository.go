@@ -0,0 +1,134 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LanguageSummary counts the tracks detected as one script and language.
+// Language is empty where only the script is known.
+type LanguageSummary struct {
+	Script     string `json:"script"`
+	Language   string `json:"language"`
+	TrackCount int    `json:"trackCount"`
+}
+
+// IndexSection is a jump-to section of a sorted listing: the offset of its
+// first item and how many items follow under the same label.
+type IndexSection struct {
+	Label  string `json:"label"`
+	Script string `json:"script"`
+	Offset int    `json:"offset"`
+	Count  int    `json:"count"`
+}
+
+type ScriptRepository struct {
+	db     *sql.DB
+	browse *BrowseRepository
+}
+
+func NewScriptRepository(database *sql.DB) *ScriptRepository {
+	return &ScriptRepository{db: database, browse: NewBrowseRepository(database)}
+}
+
+// ListLanguages lists the scripts and languages tracks were detected as,
+// most common first.
+func (r *ScriptRepository) ListLanguages(ctx context.Context) ([]LanguageSummary, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.script, t.language, COUNT(1) AS track_count
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		WHERE f.file_exists = 1
+		  AND f.is_audiobook = 0
+		  AND COALESCE(t.script, '') <> ''
+		GROUP BY t.script, t.language
+		ORDER BY track_count DESC, t.script, t.language
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list languages: %w", err)
+	}
+	defer rows.Close()
+
+	languages := make([]LanguageSummary, 0)
+	for rows.Next() {
+		var summary LanguageSummary
+		if err := rows.Scan(&summary.Script, &summary.Language, &summary.TrackCount); err != nil {
+			return nil, fmt.Errorf("scan language: %w", err)
+		}
+		languages = append(languages, summary)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate languages: %w", err)
+	}
+
+	return languages, nil
+}
+
+// ListTracksByLanguage browses the tracks detected as script, narrowed to
+// language when given, and by search like ListTracks.
+func (r *ScriptRepository) ListTracksByLanguage(ctx context.Context, script string, language string, search string, limit int, offset int, withStats bool) (TracksPage, error) {
+	script = strings.ToLower(strings.TrimSpace(script))
+	language = strings.ToLower(strings.TrimSpace(language))
+	if script == "" && language == "" {
+		return TracksPage{}, errors.New("a script or language is required")
+	}
+
+	return r.browse.listTracks(ctx, trackFilter{search: search, script: script, language: language}, limit, offset, withStats)
+}
+
+// ListArtistSections returns the jump-to sections of the artist list, in
+// the order ListArtists returns artists.
+func (r *ScriptRepository) ListArtistSections(ctx context.Context) ([]IndexSection, error) {
+	return r.listSections(ctx, `
+		SELECT a.name
+		FROM artists a
+		ORDER BY COALESCE(NULLIF(TRIM(a.sort_name), ''), a.name) COLLATE LOCALE, a.romanized COLLATE LOCALE, a.name COLLATE LOCALE
+	`)
+}
+
+// ListAlbumSections returns the jump-to sections of the album list by album
+// artist, in the order ListAlbums returns albums.
+func (r *ScriptRepository) ListAlbumSections(ctx context.Context) ([]IndexSection, error) {
+	return r.listSections(ctx, `
+		SELECT COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist')
+		FROM albums a
+		ORDER BY `+albumArtistTitleOrderSQL)
+}
+
+// listSections groups the names query returns into sections. A label that
+// comes back after another section, as the locale order may interleave
+// scripts, counts toward its first section so each label appears once.
+func (r *ScriptRepository) listSections(ctx context.Context, query string) ([]IndexSection, error) {
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list index sections: %w", err)
+	}
+	defer rows.Close()
+
+	sections := make([]IndexSection, 0)
+	byLabel := make(map[string]int)
+	offset := 0
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("scan index section name: %w", err)
+		}
+
+		label, script := SectionOf(name)
+		if index, ok := byLabel[label]; ok {
+			sections[index].Count++
+		} else {
+			byLabel[label] = len(sections)
+			sections = append(sections, IndexSection{Label: label, Script: script, Offset: offset, Count: 1})
+		}
+		offset++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate index sections: %w", err)
+	}
+
+	return sections, nil
+}
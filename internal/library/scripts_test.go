@@ -0,0 +1,73 @@
+package library
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDetectScriptPicksDominantScriptAndLanguage(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		texts    []string
+		script   string
+		language string
+	}{
+		{[]string{"Blue in Green", "Miles Davis"}, ScriptLatin, ""},
+		{[]string{"Группа крови", "Кино"}, ScriptCyrillic, LanguageRussian},
+		{[]string{"Обійми", "Океан Ельзи"}, ScriptCyrillic, LanguageUkrainian},
+		{[]string{"夜に駆ける", "YOASOBI"}, ScriptCJK, LanguageJapanese},
+		{[]string{"봄날", "BTS"}, ScriptCJK, LanguageKorean},
+		{[]string{"月亮代表我的心", "邓丽君"}, ScriptCJK, LanguageChinese},
+		{[]string{"1999", "--"}, "", ""},
+	}
+
+	for _, tc := range cases {
+		script, language := DetectScript(tc.texts...)
+		if script != tc.script || language != tc.language {
+			t.Fatalf("DetectScript(%q) = %q, %q; want %q, %q", tc.texts, script, language, tc.script, tc.language)
+		}
+	}
+}
+
+func TestSearchTextFoldsWidthKanaAndCJKSpacing(t *testing.T) {
+	t.Parallel()
+
+	if got := SearchText("君の 名は", "ＲＡＤＷＩＭＰＳ"); got != "君の名は\nradwimps" {
+		t.Fatalf("unexpected search text %q", got)
+	}
+	if got := SearchText("カタカナ Song"); got != "かたかな song" {
+		t.Fatalf("unexpected search text %q", got)
+	}
+	if got := SearchTerms("  ＲＡＤ  カタ "); !slices.Equal(got, []string{"rad", "かた"}) {
+		t.Fatalf("unexpected search terms %q", got)
+	}
+}
+
+func TestSectionOfPerScript(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		section string
+		script  string
+	}{
+		{"Étienne Daho", "E", ScriptLatin},
+		{"'Til Tuesday", "T", ScriptLatin},
+		{"2Pac", DigitSection, ""},
+		{"Земфира", "З", ScriptCyrillic},
+		{"ゆず", "や", ScriptCJK},
+		{"ガガガSP", "か", ScriptCJK},
+		{"방탄소년단", "ㅂ", ScriptCJK},
+		{"까치", "ㄱ", ScriptCJK},
+		{"坂本龍一", HanSection, ScriptCJK},
+		{"...", DigitSection, ""},
+	}
+
+	for _, tc := range cases {
+		section, script := SectionOf(tc.name)
+		if section != tc.section || script != tc.script {
+			t.Fatalf("SectionOf(%q) = %q, %q; want %q, %q", tc.name, section, script, tc.section, tc.script)
+		}
+	}
+}
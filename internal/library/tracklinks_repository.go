@@ -0,0 +1,241 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var ErrTrackNotFound = errors.New("track not found")
+
+type TrackLinkGroup struct {
+	CanonicalTrackID int64   `json:"canonicalTrackId"`
+	TrackIDs         []int64 `json:"trackIds"`
+}
+
+type TrackLinkRepository struct {
+	db *sql.DB
+}
+
+func NewTrackLinkRepository(database *sql.DB) *TrackLinkRepository {
+	return &TrackLinkRepository{db: database}
+}
+
+// Link attaches trackIDs to the version group of canonicalTrackID. Groups are
+// kept flat: if the canonical track is itself linked, its own canonical is
+// used, and tracks that were canonical for other versions bring those along.
+func (r *TrackLinkRepository) Link(ctx context.Context, canonicalTrackID int64, trackIDs []int64) (TrackLinkGroup, error) {
+	if canonicalTrackID <= 0 {
+		return TrackLinkGroup{}, errors.New("canonical track is required")
+	}
+	if len(trackIDs) == 0 {
+		return TrackLinkGroup{}, errors.New("at least one track is required")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return TrackLinkGroup{}, fmt.Errorf("begin track link tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := ensureTrackExists(ctx, tx, canonicalTrackID); err != nil {
+		return TrackLinkGroup{}, err
+	}
+
+	rootTrackID, err := resolveCanonicalTrackID(ctx, tx, canonicalTrackID)
+	if err != nil {
+		return TrackLinkGroup{}, err
+	}
+
+	seenIDs := make(map[int64]struct{}, len(trackIDs))
+	for _, trackID := range trackIDs {
+		if trackID == rootTrackID {
+			continue
+		}
+		if _, alreadySeen := seenIDs[trackID]; alreadySeen {
+			continue
+		}
+		seenIDs[trackID] = struct{}{}
+
+		if err := ensureTrackExists(ctx, tx, trackID); err != nil {
+			return TrackLinkGroup{}, err
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			"UPDATE track_links SET canonical_track_id = ? WHERE canonical_track_id = ?",
+			rootTrackID,
+			trackID,
+		); err != nil {
+			return TrackLinkGroup{}, fmt.Errorf("move linked versions of track %d: %w", trackID, err)
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO track_links(track_id, canonical_track_id)
+			 VALUES (?, ?)
+			 ON CONFLICT(track_id) DO UPDATE SET
+			 	canonical_track_id = excluded.canonical_track_id,
+			 	linked_at = excluded.linked_at`,
+			trackID,
+			rootTrackID,
+		); err != nil {
+			return TrackLinkGroup{}, fmt.Errorf("link track %d to %d: %w", trackID, rootTrackID, err)
+		}
+	}
+
+	group, err := readTrackLinkGroup(ctx, tx, rootTrackID)
+	if err != nil {
+		return TrackLinkGroup{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return TrackLinkGroup{}, fmt.Errorf("commit track links: %w", err)
+	}
+
+	return group, nil
+}
+
+// Unlink removes each track from its version group. Unlinking a canonical track
+// promotes the lowest remaining track id so the rest of the group stays linked.
+func (r *TrackLinkRepository) Unlink(ctx context.Context, trackIDs []int64) error {
+	if len(trackIDs) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin track unlink tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, trackID := range trackIDs {
+		result, err := tx.ExecContext(ctx, "DELETE FROM track_links WHERE track_id = ?", trackID)
+		if err != nil {
+			return fmt.Errorf("unlink track %d: %w", trackID, err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("read unlinked track count: %w", err)
+		}
+		if rowsAffected > 0 {
+			continue
+		}
+
+		var promotedTrackID sql.NullInt64
+		if err := tx.QueryRowContext(
+			ctx,
+			"SELECT MIN(track_id) FROM track_links WHERE canonical_track_id = ?",
+			trackID,
+		).Scan(&promotedTrackID); err != nil {
+			return fmt.Errorf("read linked versions of track %d: %w", trackID, err)
+		}
+		if !promotedTrackID.Valid {
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM track_links WHERE track_id = ?", promotedTrackID.Int64); err != nil {
+			return fmt.Errorf("promote track %d: %w", promotedTrackID.Int64, err)
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			"UPDATE track_links SET canonical_track_id = ? WHERE canonical_track_id = ?",
+			promotedTrackID.Int64,
+			trackID,
+		); err != nil {
+			return fmt.Errorf("move linked versions of track %d: %w", trackID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit track unlinks: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TrackLinkRepository) GetGroup(ctx context.Context, trackID int64) (TrackLinkGroup, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return TrackLinkGroup{}, fmt.Errorf("begin track link read tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := ensureTrackExists(ctx, tx, trackID); err != nil {
+		return TrackLinkGroup{}, err
+	}
+
+	rootTrackID, err := resolveCanonicalTrackID(ctx, tx, trackID)
+	if err != nil {
+		return TrackLinkGroup{}, err
+	}
+
+	return readTrackLinkGroup(ctx, tx, rootTrackID)
+}
+
+func ensureTrackExists(ctx context.Context, tx *sql.Tx, trackID int64) error {
+	var count int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(1) FROM tracks WHERE id = ?", trackID).Scan(&count); err != nil {
+		return fmt.Errorf("check track %d: %w", trackID, err)
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: %d", ErrTrackNotFound, trackID)
+	}
+
+	return nil
+}
+
+func resolveCanonicalTrackID(ctx context.Context, tx *sql.Tx, trackID int64) (int64, error) {
+	var canonicalTrackID int64
+	err := tx.QueryRowContext(
+		ctx,
+		"SELECT canonical_track_id FROM track_links WHERE track_id = ?",
+		trackID,
+	).Scan(&canonicalTrackID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return trackID, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("resolve canonical track for %d: %w", trackID, err)
+	}
+
+	return canonicalTrackID, nil
+}
+
+func readTrackLinkGroup(ctx context.Context, tx *sql.Tx, canonicalTrackID int64) (TrackLinkGroup, error) {
+	rows, err := tx.QueryContext(
+		ctx,
+		"SELECT track_id FROM track_links WHERE canonical_track_id = ? ORDER BY track_id",
+		canonicalTrackID,
+	)
+	if err != nil {
+		return TrackLinkGroup{}, fmt.Errorf("list linked versions of track %d: %w", canonicalTrackID, err)
+	}
+	defer rows.Close()
+
+	group := TrackLinkGroup{
+		CanonicalTrackID: canonicalTrackID,
+		TrackIDs:         []int64{canonicalTrackID},
+	}
+	for rows.Next() {
+		var trackID int64
+		if scanErr := rows.Scan(&trackID); scanErr != nil {
+			return TrackLinkGroup{}, fmt.Errorf("scan linked track id: %w", scanErr)
+		}
+		group.TrackIDs = append(group.TrackIDs, trackID)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return TrackLinkGroup{}, fmt.Errorf("iterate linked track ids: %w", rowsErr)
+	}
+
+	return group, nil
+}
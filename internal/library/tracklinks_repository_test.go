@@ -0,0 +1,155 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/rzxx/ben/internal/db"
+)
+
+func TestUnlinkCanonicalTrackPromotesLowestVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo, database := newTrackLinkRepositoryForTest(t)
+	canonical := insertLinkTrackForTest(t, database, "Original")
+	cover := insertLinkTrackForTest(t, database, "Cover")
+	live := insertLinkTrackForTest(t, database, "Live")
+
+	if _, err := repo.Link(ctx, canonical, []int64{live, cover}); err != nil {
+		t.Fatalf("link tracks: %v", err)
+	}
+	if err := repo.Unlink(ctx, []int64{canonical}); err != nil {
+		t.Fatalf("unlink canonical track: %v", err)
+	}
+
+	group, err := repo.GetGroup(ctx, live)
+	if err != nil {
+		t.Fatalf("get group: %v", err)
+	}
+	if group.CanonicalTrackID != cover || !slices.Equal(group.TrackIDs, []int64{cover, live}) {
+		t.Fatalf("expected the lowest remaining version to be promoted, got %+v", group)
+	}
+
+	unlinked, err := repo.GetGroup(ctx, canonical)
+	if err != nil {
+		t.Fatalf("get unlinked track group: %v", err)
+	}
+	if !slices.Equal(unlinked.TrackIDs, []int64{canonical}) {
+		t.Fatalf("expected the unlinked track to stand alone, got %+v", unlinked)
+	}
+}
+
+func TestDeletingCanonicalTrackPromotesLowestVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo, database := newTrackLinkRepositoryForTest(t)
+	canonical := insertLinkTrackForTest(t, database, "Original")
+	cover := insertLinkTrackForTest(t, database, "Cover")
+	live := insertLinkTrackForTest(t, database, "Live")
+
+	if _, err := repo.Link(ctx, canonical, []int64{cover, live}); err != nil {
+		t.Fatalf("link tracks: %v", err)
+	}
+	if _, err := database.Exec("DELETE FROM tracks WHERE id = ?", canonical); err != nil {
+		t.Fatalf("delete canonical track: %v", err)
+	}
+
+	group, err := repo.GetGroup(ctx, live)
+	if err != nil {
+		t.Fatalf("get group: %v", err)
+	}
+	if group.CanonicalTrackID != cover || !slices.Equal(group.TrackIDs, []int64{cover, live}) {
+		t.Fatalf("expected the group to survive its missing canonical track, got %+v", group)
+	}
+
+	if _, err := repo.GetGroup(ctx, canonical); !errors.Is(err, ErrTrackNotFound) {
+		t.Fatalf("expected the deleted track to be not found, got %v", err)
+	}
+}
+
+func TestDeletingCanonicalTrackOfPairLeavesNoLinks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo, database := newTrackLinkRepositoryForTest(t)
+	canonical := insertLinkTrackForTest(t, database, "Original")
+	cover := insertLinkTrackForTest(t, database, "Cover")
+
+	if _, err := repo.Link(ctx, canonical, []int64{cover}); err != nil {
+		t.Fatalf("link tracks: %v", err)
+	}
+	if _, err := database.Exec("DELETE FROM tracks WHERE id IN (?, ?)", canonical, cover); err != nil {
+		t.Fatalf("delete both tracks: %v", err)
+	}
+
+	var links int
+	if err := database.QueryRow("SELECT COUNT(1) FROM track_links").Scan(&links); err != nil {
+		t.Fatalf("count links: %v", err)
+	}
+	if links != 0 {
+		t.Fatalf("expected no dangling links, got %d", links)
+	}
+}
+
+func TestLinkRejectsMissingCanonicalTrack(t *testing.T) {
+	t.Parallel()
+
+	repo, database := newTrackLinkRepositoryForTest(t)
+	cover := insertLinkTrackForTest(t, database, "Cover")
+
+	if _, err := repo.Link(context.Background(), cover+100, []int64{cover}); !errors.Is(err, ErrTrackNotFound) {
+		t.Fatalf("expected a missing canonical track to be rejected, got %v", err)
+	}
+}
+
+func newTrackLinkRepositoryForTest(t *testing.T) (*TrackLinkRepository, *sql.DB) {
+	t.Helper()
+
+	database, err := db.Bootstrap(filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatalf("bootstrap test database: %v", err)
+	}
+	t.Cleanup(func() {
+		database.Close()
+	})
+
+	return NewTrackLinkRepository(database), database
+}
+
+func insertLinkTrackForTest(t *testing.T, database *sql.DB, title string) int64 {
+	t.Helper()
+
+	fileResult, err := database.Exec(
+		`INSERT INTO files(path, size, mtime_ns, file_exists, last_seen_at) VALUES (?, 123, 1, 1, '2026-01-01T00:00:00Z')`,
+		fmt.Sprintf("/music/%s.flac", title),
+	)
+	if err != nil {
+		t.Fatalf("insert file row: %v", err)
+	}
+	fileID, err := fileResult.LastInsertId()
+	if err != nil {
+		t.Fatalf("read file id: %v", err)
+	}
+
+	trackResult, err := database.Exec(
+		`INSERT INTO tracks(file_id, title, artist, album, album_artist, duration_ms, tags_json) VALUES (?, ?, 'Artist', 'Album', 'Artist', 180000, '{}')`,
+		fileID,
+		title,
+	)
+	if err != nil {
+		t.Fatalf("insert track row: %v", err)
+	}
+	trackID, err := trackResult.LastInsertId()
+	if err != nil {
+		t.Fatalf("read track id: %v", err)
+	}
+
+	return trackID
+}
@@ -0,0 +1,108 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidTrackTrim = errors.New("trim end must come after its start and within the track")
+
+// TrackTrim skips the start and end of a track, e.g. a long intro or a
+// hidden track after minutes of silence. A nil EndMS plays to the end of the
+// file.
+type TrackTrim struct {
+	TrackID int64 `json:"trackId"`
+	StartMS int   `json:"startMs"`
+	EndMS   *int  `json:"endMs,omitempty"`
+}
+
+type TrackTrimRepository struct {
+	db *sql.DB
+}
+
+func NewTrackTrimRepository(database *sql.DB) *TrackTrimRepository {
+	return &TrackTrimRepository{db: database}
+}
+
+func (r *TrackTrimRepository) GetTrackTrims(ctx context.Context, trackID int64) (TrackTrim, error) {
+	var startMS sql.NullInt64
+	var endMS sql.NullInt64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT tt.start_ms, tt.end_ms
+		FROM tracks t
+		LEFT JOIN track_trims tt ON tt.track_id = t.id
+		WHERE t.id = ?
+	`, trackID).Scan(&startMS, &endMS)
+	if errors.Is(err, sql.ErrNoRows) {
+		return TrackTrim{}, ErrTrackNotFound
+	}
+	if err != nil {
+		return TrackTrim{}, fmt.Errorf("get trims for track %d: %w", trackID, err)
+	}
+
+	return TrackTrim{TrackID: trackID, StartMS: int(startMS.Int64), EndMS: intPointer(endMS)}, nil
+}
+
+// SetTrackTrims stores where playback of a track starts and stops. The end
+// must come after the start and neither may pass the track's duration when
+// it is known.
+func (r *TrackTrimRepository) SetTrackTrims(ctx context.Context, trackID int64, startMS int, endMS *int) (TrackTrim, error) {
+	var durationMS sql.NullInt64
+	err := r.db.QueryRowContext(ctx, "SELECT duration_ms FROM tracks WHERE id = ?", trackID).Scan(&durationMS)
+	if errors.Is(err, sql.ErrNoRows) {
+		return TrackTrim{}, ErrTrackNotFound
+	}
+	if err != nil {
+		return TrackTrim{}, fmt.Errorf("get duration for track %d: %w", trackID, err)
+	}
+
+	if err := validateTrackTrim(startMS, endMS, intPointer(durationMS)); err != nil {
+		return TrackTrim{}, err
+	}
+	if startMS == 0 && endMS == nil {
+		return TrackTrim{TrackID: trackID}, r.ClearTrackTrims(ctx, trackID)
+	}
+
+	if _, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO track_trims(track_id, start_ms, end_ms, updated_at)
+		 VALUES (?, ?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+		 ON CONFLICT(track_id) DO UPDATE SET
+		 	start_ms = excluded.start_ms,
+		 	end_ms = excluded.end_ms,
+		 	updated_at = excluded.updated_at`,
+		trackID,
+		startMS,
+		endMS,
+	); err != nil {
+		return TrackTrim{}, fmt.Errorf("set trims for track %d: %w", trackID, err)
+	}
+
+	return r.GetTrackTrims(ctx, trackID)
+}
+
+func (r *TrackTrimRepository) ClearTrackTrims(ctx context.Context, trackID int64) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM track_trims WHERE track_id = ?", trackID); err != nil {
+		return fmt.Errorf("clear trims for track %d: %w", trackID, err)
+	}
+
+	return nil
+}
+
+func validateTrackTrim(startMS int, endMS *int, durationMS *int) error {
+	if startMS < 0 {
+		return ErrInvalidTrackTrim
+	}
+	if endMS != nil && *endMS <= startMS {
+		return ErrInvalidTrackTrim
+	}
+	if durationMS != nil && *durationMS > 0 {
+		if startMS >= *durationMS || (endMS != nil && *endMS > *durationMS) {
+			return ErrInvalidTrackTrim
+		}
+	}
+
+	return nil
+}
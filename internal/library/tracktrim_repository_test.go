@@ -0,0 +1,31 @@
+package library
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateTrackTrimKeepsTrimsInsideTheTrack(t *testing.T) {
+	t.Parallel()
+
+	durationMS := 300_000
+	endMS := 290_000
+	if err := validateTrackTrim(15_000, &endMS, &durationMS); err != nil {
+		t.Fatalf("expected trim inside the track to be valid, got %v", err)
+	}
+
+	beforeStart := 10_000
+	if err := validateTrackTrim(15_000, &beforeStart, &durationMS); !errors.Is(err, ErrInvalidTrackTrim) {
+		t.Fatalf("expected end before start to be rejected, got %v", err)
+	}
+	pastEnd := 301_000
+	if err := validateTrackTrim(0, &pastEnd, &durationMS); !errors.Is(err, ErrInvalidTrackTrim) {
+		t.Fatalf("expected end past the duration to be rejected, got %v", err)
+	}
+	if err := validateTrackTrim(-1, nil, nil); !errors.Is(err, ErrInvalidTrackTrim) {
+		t.Fatalf("expected negative start to be rejected, got %v", err)
+	}
+	if err := validateTrackTrim(400_000, nil, nil); err != nil {
+		t.Fatalf("expected unknown duration to allow any start, got %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// MaxVolumeOffsetDB bounds a single offset; the combined album and track
+// offset is clamped to the same range.
+const MaxVolumeOffsetDB = 12.0
+
+var ErrVolumeOffsetOutOfRange = fmt.Errorf("volume offset must be between -%.0f and %.0f dB", MaxVolumeOffsetDB, MaxVolumeOffsetDB)
+
+// TrackVolumeOffset is the manual loudness correction for a track. The album
+// and track offsets add up, so a track offset fine-tunes its album's.
+type TrackVolumeOffset struct {
+	TrackID       int64    `json:"trackId"`
+	TrackOffsetDB *float64 `json:"trackOffsetDb,omitempty"`
+	AlbumOffsetDB *float64 `json:"albumOffsetDb,omitempty"`
+	EffectiveDB   float64  `json:"effectiveDb"`
+}
+
+type AlbumVolumeOffset struct {
+	Title       string   `json:"title"`
+	AlbumArtist string   `json:"albumArtist"`
+	OffsetDB    *float64 `json:"offsetDb,omitempty"`
+}
+
+type VolumeOffsetRepository struct {
+	db *sql.DB
+}
+
+func NewVolumeOffsetRepository(database *sql.DB) *VolumeOffsetRepository {
+	return &VolumeOffsetRepository{db: database}
+}
+
+func (r *VolumeOffsetRepository) GetTrackVolumeOffset(ctx context.Context, trackID int64) (TrackVolumeOffset, error) {
+	var trackOffset sql.NullFloat64
+	var albumOffset sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT tvo.offset_db, avo.offset_db
+		FROM tracks t
+		LEFT JOIN track_volume_offsets tvo ON tvo.track_id = t.id
+		LEFT JOIN album_volume_offsets avo
+			ON avo.album_title_key = LOWER(COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album'))
+			AND avo.album_artist_key = LOWER(COALESCE(NULLIF(TRIM(t.album_artist), ''), COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')))
+		WHERE t.id = ?
+	`, trackID).Scan(&trackOffset, &albumOffset)
+	if errors.Is(err, sql.ErrNoRows) {
+		return TrackVolumeOffset{}, ErrTrackNotFound
+	}
+	if err != nil {
+		return TrackVolumeOffset{}, fmt.Errorf("get volume offset for track %d: %w", trackID, err)
+	}
+
+	offset := TrackVolumeOffset{
+		TrackID:       trackID,
+		TrackOffsetDB: floatPointer(trackOffset),
+		AlbumOffsetDB: floatPointer(albumOffset),
+	}
+	offset.EffectiveDB = math.Max(-MaxVolumeOffsetDB, math.Min(MaxVolumeOffsetDB, trackOffset.Float64+albumOffset.Float64))
+
+	return offset, nil
+}
+
+// SetTrackVolumeOffset stores offsetDB for a track; nil clears it.
+func (r *VolumeOffsetRepository) SetTrackVolumeOffset(ctx context.Context, trackID int64, offsetDB *float64) (TrackVolumeOffset, error) {
+	if offsetDB == nil {
+		if _, err := r.db.ExecContext(ctx, "DELETE FROM track_volume_offsets WHERE track_id = ?", trackID); err != nil {
+			return TrackVolumeOffset{}, fmt.Errorf("clear volume offset for track %d: %w", trackID, err)
+		}
+
+		return r.GetTrackVolumeOffset(ctx, trackID)
+	}
+
+	if err := validateVolumeOffset(*offsetDB); err != nil {
+		return TrackVolumeOffset{}, err
+	}
+
+	if _, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO track_volume_offsets(track_id, offset_db, updated_at)
+		 VALUES (?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+		 ON CONFLICT(track_id) DO UPDATE SET
+		 	offset_db = excluded.offset_db,
+		 	updated_at = excluded.updated_at`,
+		trackID,
+		*offsetDB,
+	); err != nil {
+		return TrackVolumeOffset{}, fmt.Errorf("set volume offset for track %d: %w", trackID, err)
+	}
+
+	return r.GetTrackVolumeOffset(ctx, trackID)
+}
+
+func (r *VolumeOffsetRepository) GetAlbumVolumeOffset(ctx context.Context, title string, albumArtist string) (AlbumVolumeOffset, error) {
+	albumTitle, artistName, err := volumeOffsetAlbumKey(title, albumArtist)
+	if err != nil {
+		return AlbumVolumeOffset{}, err
+	}
+
+	offset := AlbumVolumeOffset{Title: albumTitle, AlbumArtist: artistName}
+	var offsetDB float64
+	err = r.db.QueryRowContext(
+		ctx,
+		"SELECT offset_db FROM album_volume_offsets WHERE album_title_key = ? AND album_artist_key = ?",
+		albumMixKey(albumTitle),
+		albumMixKey(artistName),
+	).Scan(&offsetDB)
+	if errors.Is(err, sql.ErrNoRows) {
+		return offset, nil
+	}
+	if err != nil {
+		return AlbumVolumeOffset{}, fmt.Errorf("get volume offset for %q by %q: %w", albumTitle, artistName, err)
+	}
+
+	offset.OffsetDB = &offsetDB
+	return offset, nil
+}
+
+// SetAlbumVolumeOffset stores offsetDB for an album; nil clears it.
+func (r *VolumeOffsetRepository) SetAlbumVolumeOffset(ctx context.Context, title string, albumArtist string, offsetDB *float64) (AlbumVolumeOffset, error) {
+	albumTitle, artistName, err := volumeOffsetAlbumKey(title, albumArtist)
+	if err != nil {
+		return AlbumVolumeOffset{}, err
+	}
+
+	if offsetDB == nil {
+		if _, err := r.db.ExecContext(
+			ctx,
+			"DELETE FROM album_volume_offsets WHERE album_title_key = ? AND album_artist_key = ?",
+			albumMixKey(albumTitle),
+			albumMixKey(artistName),
+		); err != nil {
+			return AlbumVolumeOffset{}, fmt.Errorf("clear volume offset for %q by %q: %w", albumTitle, artistName, err)
+		}
+
+		return r.GetAlbumVolumeOffset(ctx, albumTitle, artistName)
+	}
+
+	if err := validateVolumeOffset(*offsetDB); err != nil {
+		return AlbumVolumeOffset{}, err
+	}
+
+	if _, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO album_volume_offsets(album_title_key, album_artist_key, offset_db, updated_at)
+		 VALUES (?, ?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+		 ON CONFLICT(album_title_key, album_artist_key) DO UPDATE SET
+		 	offset_db = excluded.offset_db,
+		 	updated_at = excluded.updated_at`,
+		albumMixKey(albumTitle),
+		albumMixKey(artistName),
+		*offsetDB,
+	); err != nil {
+		return AlbumVolumeOffset{}, fmt.Errorf("set volume offset for %q by %q: %w", albumTitle, artistName, err)
+	}
+
+	return r.GetAlbumVolumeOffset(ctx, albumTitle, artistName)
+}
+
+func volumeOffsetAlbumKey(title string, albumArtist string) (string, string, error) {
+	albumTitle := strings.TrimSpace(title)
+	artistName := strings.TrimSpace(albumArtist)
+	if albumTitle == "" {
+		return "", "", errors.New("album title is required")
+	}
+	if artistName == "" {
+		return "", "", errors.New("album artist is required")
+	}
+
+	return albumTitle, artistName, nil
+}
+
+func validateVolumeOffset(offsetDB float64) error {
+	if math.IsNaN(offsetDB) || offsetDB < -MaxVolumeOffsetDB || offsetDB > MaxVolumeOffsetDB {
+		return ErrVolumeOffsetOutOfRange
+	}
+
+	return nil
+}
+
+func floatPointer(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+
+	result := value.Float64
+	return &result
+}
@@ -5,30 +5,149 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"path"
 	"strings"
+	"sync/atomic"
 )
 
 var ErrWatchedRootNotFound = errors.New("watched root not found")
 
+const (
+	RootKindLocal  = "local"
+	RootKindWebDAV = "webdav"
+	RootKindSFTP   = "sftp"
+)
+
 type WatchedRoot struct {
-	ID        int64  `json:"id"`
-	Path      string `json:"path"`
-	Enabled   bool   `json:"enabled"`
-	CreatedAt string `json:"createdAt"`
+	ID            int64        `json:"id"`
+	Path          string       `json:"path"`
+	Enabled       bool         `json:"enabled"`
+	CreatedAt     string       `json:"createdAt"`
+	Kind          string       `json:"kind"`
+	Username      string       `json:"username,omitempty"`
+	Offline       bool         `json:"offline"`
+	LastCheckedAt *string      `json:"lastCheckedAt,omitempty"`
+	LastError     *string      `json:"lastError,omitempty"`
+	Rules         RootRules    `json:"rules"`
+	Schedule      RootSchedule `json:"schedule"`
+	// LastScheduledScanAt is when the scheduler last rescanned the root.
+	LastScheduledScanAt *string `json:"lastScheduledScanAt,omitempty"`
+}
+
+// RootRules exclude files below a watched root from scans and the file
+// watcher, on top of its .benignore files. Exclude holds gitignore-style
+// patterns relative to the root, such as "**/Podcasts/**" or "*.tmp".
+type RootRules struct {
+	Exclude     []string `json:"exclude"`
+	SkipHidden  bool     `json:"skipHidden"`
+	MinFileSize int64    `json:"minFileSize"`
+}
+
+// NormalizeRootRules trims and dedupes the patterns and rejects malformed
+// ones.
+func NormalizeRootRules(rules RootRules) (RootRules, error) {
+	normalized := RootRules{Exclude: make([]string, 0, len(rules.Exclude)), SkipHidden: rules.SkipHidden}
+	seen := make(map[string]struct{}, len(rules.Exclude))
+	for _, pattern := range rules.Exclude {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		if _, ok := seen[pattern]; ok {
+			continue
+		}
+		for _, segment := range strings.Split(strings.TrimPrefix(pattern, "!"), "/") {
+			if _, err := path.Match(segment, ""); err != nil {
+				return RootRules{}, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+			}
+		}
+		seen[pattern] = struct{}{}
+		normalized.Exclude = append(normalized.Exclude, pattern)
+	}
+	if rules.MinFileSize < 0 {
+		return RootRules{}, errors.New("minimum file size cannot be negative")
+	}
+	normalized.MinFileSize = rules.MinFileSize
+
+	return normalized, nil
+}
+
+// MaxRescanHours bounds the rescan interval of a root to a year.
+const MaxRescanHours = 24 * 365
+
+// RootSchedule sets when a root is scanned without being asked: when the
+// app starts and every RescanHours hours, 0 for never. Scheduled scans wait
+// out the quiet hours, local hours from QuietHoursStart up to QuietHoursEnd,
+// which may wrap midnight; equal hours mean no quiet hours.
+type RootSchedule struct {
+	ScanOnStartup   bool `json:"scanOnStartup"`
+	RescanHours     int  `json:"rescanHours"`
+	QuietHoursStart int  `json:"quietHoursStart"`
+	QuietHoursEnd   int  `json:"quietHoursEnd"`
+}
+
+// NormalizeRootSchedule checks the hours of a schedule.
+func NormalizeRootSchedule(schedule RootSchedule) (RootSchedule, error) {
+	if schedule.RescanHours < 0 || schedule.RescanHours > MaxRescanHours {
+		return RootSchedule{}, fmt.Errorf("rescan interval must be 0 to %d hours", MaxRescanHours)
+	}
+	if schedule.QuietHoursStart < 0 || schedule.QuietHoursStart > 23 || schedule.QuietHoursEnd < 0 || schedule.QuietHoursEnd > 23 {
+		return RootSchedule{}, errors.New("quiet hours must be 0 to 23")
+	}
+
+	return schedule, nil
+}
+
+// Merge combines global rules with the rules of a root: both sets of
+// patterns apply, global ones first, and the stricter of the other rules
+// wins.
+func (r RootRules) Merge(root RootRules) RootRules {
+	merged := RootRules{
+		Exclude:     make([]string, 0, len(r.Exclude)+len(root.Exclude)),
+		SkipHidden:  r.SkipHidden || root.SkipHidden,
+		MinFileSize: max(r.MinFileSize, root.MinFileSize),
+	}
+	merged.Exclude = append(merged.Exclude, r.Exclude...)
+	merged.Exclude = append(merged.Exclude, root.Exclude...)
+
+	return merged
+}
+
+// IsRemote reports whether the root is served over the network instead of
+// the local filesystem. Remote roots are never watched and their paths are
+// URLs rather than filesystem paths.
+func (r WatchedRoot) IsRemote() bool {
+	return r.Kind != "" && r.Kind != RootKindLocal
+}
+
+const watchedRootColumns = `id, path, enabled, created_at, kind, COALESCE(remote_username, ''),
+	offline, last_checked_at, last_error, exclude_patterns, skip_hidden,
+	min_file_size, scan_on_startup, rescan_interval_hours, quiet_hours_start, quiet_hours_end,
+	last_scheduled_scan_at`
+
+type watchedRootScanner interface {
+	Scan(dest ...any) error
 }
 
 type WatchedRootRepository struct {
-	db *sql.DB
+	db         *sql.DB
+	generation atomic.Int64
 }
 
 func NewWatchedRootRepository(database *sql.DB) *WatchedRootRepository {
 	return &WatchedRootRepository{db: database}
 }
 
+// Generation changes whenever a root is added, removed or edited, so callers
+// can cache the list and reload it only when it changed.
+func (r *WatchedRootRepository) Generation() int64 {
+	return r.generation.Load()
+}
+
 func (r *WatchedRootRepository) List(ctx context.Context) ([]WatchedRoot, error) {
 	rows, err := r.db.QueryContext(
 		ctx,
-		"SELECT id, path, enabled, created_at FROM watched_roots ORDER BY path COLLATE NOCASE",
+		"SELECT "+watchedRootColumns+" FROM watched_roots ORDER BY path COLLATE NOCASE",
 	)
 	if err != nil {
 		return nil, fmt.Errorf("list watched roots: %w", err)
@@ -37,12 +156,10 @@ func (r *WatchedRootRepository) List(ctx context.Context) ([]WatchedRoot, error)
 
 	roots := make([]WatchedRoot, 0)
 	for rows.Next() {
-		var root WatchedRoot
-		var enabledInt int
-		if err := rows.Scan(&root.ID, &root.Path, &enabledInt, &root.CreatedAt); err != nil {
+		root, err := scanWatchedRoot(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan watched root row: %w", err)
 		}
-		root.Enabled = enabledInt == 1
 		roots = append(roots, root)
 	}
 
@@ -72,17 +189,48 @@ func (r *WatchedRootRepository) Add(ctx context.Context, path string) (WatchedRo
 		return WatchedRoot{}, fmt.Errorf("read watched root id: %w", err)
 	}
 
+	r.generation.Add(1)
+	return r.GetByID(ctx, id)
+}
+
+// AddRemote registers a network root. The URL is stored as the root path so
+// indexed files keep their remote URL as identity; the password is kept in
+// the keychain by the caller and only attached when a request is made.
+func (r *WatchedRootRepository) AddRemote(ctx context.Context, kind string, rootURL string, username string) (WatchedRoot, error) {
+	if strings.TrimSpace(rootURL) == "" {
+		return WatchedRoot{}, errors.New("url is required")
+	}
+	if kind == "" || kind == RootKindLocal {
+		return WatchedRoot{}, fmt.Errorf("invalid remote root kind %q", kind)
+	}
+
+	result, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO watched_roots(path, enabled, kind, remote_username)
+		 VALUES (?, 1, ?, NULLIF(?, ''))`,
+		rootURL,
+		kind,
+		username,
+	)
+	if err != nil {
+		return WatchedRoot{}, fmt.Errorf("insert remote root: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return WatchedRoot{}, fmt.Errorf("read remote root id: %w", err)
+	}
+
+	r.generation.Add(1)
 	return r.GetByID(ctx, id)
 }
 
 func (r *WatchedRootRepository) GetByID(ctx context.Context, id int64) (WatchedRoot, error) {
-	var root WatchedRoot
-	var enabledInt int
-	err := r.db.QueryRowContext(
+	root, err := scanWatchedRoot(r.db.QueryRowContext(
 		ctx,
-		"SELECT id, path, enabled, created_at FROM watched_roots WHERE id = ?",
+		"SELECT "+watchedRootColumns+" FROM watched_roots WHERE id = ?",
 		id,
-	).Scan(&root.ID, &root.Path, &enabledInt, &root.CreatedAt)
+	))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return WatchedRoot{}, ErrWatchedRootNotFound
@@ -90,7 +238,6 @@ func (r *WatchedRootRepository) GetByID(ctx context.Context, id int64) (WatchedR
 		return WatchedRoot{}, fmt.Errorf("get watched root %d: %w", id, err)
 	}
 
-	root.Enabled = enabledInt == 1
 	return root, nil
 }
 
@@ -118,6 +265,86 @@ func (r *WatchedRootRepository) SetEnabled(ctx context.Context, id int64, enable
 		return ErrWatchedRootNotFound
 	}
 
+	r.generation.Add(1)
+	return nil
+}
+
+// UpdateRules replaces the exclude rules of a root.
+func (r *WatchedRootRepository) UpdateRules(ctx context.Context, id int64, rules RootRules) (WatchedRoot, error) {
+	rules, err := NormalizeRootRules(rules)
+	if err != nil {
+		return WatchedRoot{}, err
+	}
+
+	result, err := r.db.ExecContext(
+		ctx,
+		"UPDATE watched_roots SET exclude_patterns = ?, skip_hidden = ?, min_file_size = ? WHERE id = ?",
+		strings.Join(rules.Exclude, "\n"),
+		rules.SkipHidden,
+		rules.MinFileSize,
+		id,
+	)
+	if err != nil {
+		return WatchedRoot{}, fmt.Errorf("update rules of watched root %d: %w", id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return WatchedRoot{}, fmt.Errorf("read updated watched root count: %w", err)
+	}
+	if rowsAffected == 0 {
+		return WatchedRoot{}, ErrWatchedRootNotFound
+	}
+
+	r.generation.Add(1)
+	return r.GetByID(ctx, id)
+}
+
+// UpdateSchedule replaces the scan schedule of a root.
+func (r *WatchedRootRepository) UpdateSchedule(ctx context.Context, id int64, schedule RootSchedule) (WatchedRoot, error) {
+	schedule, err := NormalizeRootSchedule(schedule)
+	if err != nil {
+		return WatchedRoot{}, err
+	}
+
+	result, err := r.db.ExecContext(
+		ctx,
+		`UPDATE watched_roots
+		 SET scan_on_startup = ?, rescan_interval_hours = ?, quiet_hours_start = ?, quiet_hours_end = ?
+		 WHERE id = ?`,
+		schedule.ScanOnStartup,
+		schedule.RescanHours,
+		schedule.QuietHoursStart,
+		schedule.QuietHoursEnd,
+		id,
+	)
+	if err != nil {
+		return WatchedRoot{}, fmt.Errorf("update schedule of watched root %d: %w", id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return WatchedRoot{}, fmt.Errorf("read updated watched root count: %w", err)
+	}
+	if rowsAffected == 0 {
+		return WatchedRoot{}, ErrWatchedRootNotFound
+	}
+
+	r.generation.Add(1)
+	return r.GetByID(ctx, id)
+}
+
+// MarkScheduledScan records when the scheduler last rescanned a root.
+func (r *WatchedRootRepository) MarkScheduledScan(ctx context.Context, id int64, scannedAt string) error {
+	if _, err := r.db.ExecContext(
+		ctx,
+		"UPDATE watched_roots SET last_scheduled_scan_at = ? WHERE id = ?",
+		scannedAt,
+		id,
+	); err != nil {
+		return fmt.Errorf("mark scheduled scan of watched root %d: %w", id, err)
+	}
+
 	return nil
 }
 
@@ -135,5 +362,103 @@ func (r *WatchedRootRepository) Delete(ctx context.Context, id int64) error {
 		return ErrWatchedRootNotFound
 	}
 
+	r.generation.Add(1)
 	return nil
 }
+
+// LegacyPasswords returns the remote root passwords that older versions
+// stored in the database, keyed by root id.
+func (r *WatchedRootRepository) LegacyPasswords(ctx context.Context) (map[int64]string, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		"SELECT id, remote_password FROM watched_roots WHERE remote_password IS NOT NULL AND remote_password <> ''",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list stored remote passwords: %w", err)
+	}
+	defer rows.Close()
+
+	passwords := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var password string
+		if err := rows.Scan(&id, &password); err != nil {
+			return nil, fmt.Errorf("scan stored remote password: %w", err)
+		}
+		passwords[id] = password
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate stored remote passwords: %w", err)
+	}
+
+	return passwords, nil
+}
+
+// ClearLegacyPassword drops the password stored in the database for a root
+// once it moved to the keychain.
+func (r *WatchedRootRepository) ClearLegacyPassword(ctx context.Context, id int64) error {
+	if _, err := r.db.ExecContext(ctx, "UPDATE watched_roots SET remote_password = NULL WHERE id = ?", id); err != nil {
+		return fmt.Errorf("clear stored password of watched root %d: %w", id, err)
+	}
+
+	return nil
+}
+
+func scanWatchedRoot(row watchedRootScanner) (WatchedRoot, error) {
+	var root WatchedRoot
+	var enabledInt int
+	var offlineInt int
+	var lastCheckedAt sql.NullString
+	var lastError sql.NullString
+	var excludePatterns string
+	var skipHiddenInt int
+	var scanOnStartupInt int
+	var lastScheduledScanAt sql.NullString
+	if err := row.Scan(
+		&root.ID,
+		&root.Path,
+		&enabledInt,
+		&root.CreatedAt,
+		&root.Kind,
+		&root.Username,
+		&offlineInt,
+		&lastCheckedAt,
+		&lastError,
+		&excludePatterns,
+		&skipHiddenInt,
+		&root.Rules.MinFileSize,
+		&scanOnStartupInt,
+		&root.Schedule.RescanHours,
+		&root.Schedule.QuietHoursStart,
+		&root.Schedule.QuietHoursEnd,
+		&lastScheduledScanAt,
+	); err != nil {
+		return WatchedRoot{}, err
+	}
+
+	root.Enabled = enabledInt == 1
+	root.Offline = offlineInt == 1
+	root.Rules.SkipHidden = skipHiddenInt == 1
+	root.Schedule.ScanOnStartup = scanOnStartupInt == 1
+	if lastScheduledScanAt.Valid {
+		value := lastScheduledScanAt.String
+		root.LastScheduledScanAt = &value
+	}
+	root.Rules.Exclude = make([]string, 0)
+	for _, pattern := range strings.Split(excludePatterns, "\n") {
+		if pattern != "" {
+			root.Rules.Exclude = append(root.Rules.Exclude, pattern)
+		}
+	}
+	if lastCheckedAt.Valid {
+		value := lastCheckedAt.String
+		root.LastCheckedAt = &value
+	}
+	if lastError.Valid && lastError.String != "" {
+		value := lastError.String
+		root.LastError = &value
+	}
+
+	return root, nil
+}
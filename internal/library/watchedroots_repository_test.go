@@ -0,0 +1,39 @@
+package library
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeRootRules(t *testing.T) {
+	t.Parallel()
+
+	rules, err := NormalizeRootRules(RootRules{
+		Exclude:     []string{" **/Podcasts/** ", "", "# note", "*.tmp", "*.tmp"},
+		SkipHidden:  true,
+		MinFileSize: 1024,
+	})
+	if err != nil {
+		t.Fatalf("normalize rules: %v", err)
+	}
+	if !slices.Equal(rules.Exclude, []string{"**/Podcasts/**", "*.tmp"}) || !rules.SkipHidden || rules.MinFileSize != 1024 {
+		t.Fatalf("unexpected rules %+v", rules)
+	}
+
+	if _, err := NormalizeRootRules(RootRules{Exclude: []string{"[a-"}}); err == nil {
+		t.Fatal("expected a malformed pattern to be rejected")
+	}
+	if _, err := NormalizeRootRules(RootRules{MinFileSize: -1}); err == nil {
+		t.Fatal("expected a negative size to be rejected")
+	}
+}
+
+func TestRootRulesMergeKeepsStricterRules(t *testing.T) {
+	t.Parallel()
+
+	global := RootRules{Exclude: []string{"*.tmp"}, MinFileSize: 4096}
+	merged := global.Merge(RootRules{Exclude: []string{"Samples/"}, SkipHidden: true, MinFileSize: 1024})
+	if !slices.Equal(merged.Exclude, []string{"*.tmp", "Samples/"}) || !merged.SkipHidden || merged.MinFileSize != 4096 {
+		t.Fatalf("unexpected merged rules %+v", merged)
+	}
+}
@@ -0,0 +1,94 @@
+package lyrics
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+var lrcTimeTag = regexp.MustCompile(`^\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+
+var lrcOffsetTag = regexp.MustCompile(`(?i)^\[offset:\s*([+-]?\d+)\s*\]$`)
+
+// Line is one synced lyric line. TimeMS is where the line starts in the
+// file.
+type Line struct {
+	TimeMS int    `json:"timeMs"`
+	Text   string `json:"text"`
+}
+
+// ParseLRC reads the synced lines of an LRC file, sorted by time. A line may
+// carry several time tags when it repeats, and the [offset:] tag shifts every
+// line; a positive offset shows lyrics earlier. Lines without time tags and
+// other metadata tags are ignored.
+func ParseLRC(content []byte) []Line {
+	content = bytes.TrimPrefix(content, utf8BOM)
+
+	offsetMS := 0
+	lines := make([]Line, 0)
+	for _, rawLine := range strings.Split(string(content), "\n") {
+		line := strings.TrimSpace(strings.TrimSuffix(rawLine, "\r"))
+		if line == "" {
+			continue
+		}
+
+		if match := lrcOffsetTag.FindStringSubmatch(line); match != nil {
+			offsetMS, _ = strconv.Atoi(match[1])
+			continue
+		}
+
+		times := make([]int, 0, 1)
+		for {
+			match := lrcTimeTag.FindStringSubmatch(line)
+			if match == nil {
+				break
+			}
+			times = append(times, lrcTimestampMS(match[1], match[2], match[3]))
+			line = line[len(match[0]):]
+		}
+
+		text := strings.TrimSpace(line)
+		for _, timeMS := range times {
+			lines = append(lines, Line{TimeMS: timeMS, Text: text})
+		}
+	}
+
+	for index := range lines {
+		lines[index].TimeMS = max(lines[index].TimeMS-offsetMS, 0)
+	}
+	sort.SliceStable(lines, func(i int, j int) bool {
+		return lines[i].TimeMS < lines[j].TimeMS
+	})
+
+	return lines
+}
+
+func lrcTimestampMS(minutes string, seconds string, fraction string) int {
+	minuteValue, _ := strconv.Atoi(minutes)
+	secondValue, _ := strconv.Atoi(seconds)
+
+	fractionMS := 0
+	if fraction != "" {
+		fractionMS, _ = strconv.Atoi(fraction)
+		switch len(fraction) {
+		case 1:
+			fractionMS *= 100
+		case 2:
+			fractionMS *= 10
+		}
+	}
+
+	return (minuteValue*60+secondValue)*1000 + fractionMS
+}
+
+// lineIndexAt returns the index of the line showing at positionMS, or -1
+// before the first line.
+func lineIndexAt(lines []Line, positionMS int) int {
+	return sort.Search(len(lines), func(index int) bool {
+		return lines[index].TimeMS > positionMS
+	}) - 1
+}
@@ -0,0 +1,47 @@
+package lyrics
+
+import "testing"
+
+func TestParseLRCSortsRepeatedAndOffsetLines(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("\ufeff[ar:Someone]\n[offset:+500]\n[00:12.30]First line\r\n[00:05.5][01:02.345]Chorus\nno time tag\n")
+	lines := ParseLRC(content)
+
+	want := []Line{
+		{TimeMS: 5000, Text: "Chorus"},
+		{TimeMS: 11800, Text: "First line"},
+		{TimeMS: 61845, Text: "Chorus"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got %+v", len(want), lines)
+	}
+	for index := range want {
+		if lines[index] != want[index] {
+			t.Fatalf("line %d = %+v, want %+v", index, lines[index], want[index])
+		}
+	}
+}
+
+func TestPositionAtReportsCurrentAndNextLine(t *testing.T) {
+	t.Parallel()
+
+	loaded := loadedLyrics{
+		trackID:     4,
+		trimStartMS: 10000,
+		lines: []Line{
+			{TimeMS: 12000, Text: "one"},
+			{TimeMS: 15000, Text: "two"},
+		},
+	}
+
+	before := positionAt(loaded, 1000)
+	if before.LineIndex != -1 || before.Line != nil || before.Next == nil || before.Next.TimeMS != 2000 {
+		t.Fatalf("expected only the first line as next, got %+v", before)
+	}
+
+	last := positionAt(loaded, 6000)
+	if last.LineIndex != 1 || last.Line == nil || last.Line.Text != "two" || last.Next != nil {
+		t.Fatalf("expected the last line without a next one, got %+v", last)
+	}
+}
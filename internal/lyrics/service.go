@@ -0,0 +1,199 @@
+package lyrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/rzxx/ben/internal/eventbus"
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/player"
+)
+
+// EventPosition carries the lyric line at the playback position. It is only
+// published when the line changes, so the frontend can highlight lines
+// without polling.
+const EventPosition = "lyrics:position"
+
+type Emitter func(eventName string, payload any)
+
+// Lyrics are the synced lines of a track.
+type Lyrics struct {
+	TrackID int64  `json:"trackId"`
+	Synced  bool   `json:"synced"`
+	Lines   []Line `json:"lines"`
+}
+
+// Position is the current and next lyric line of the playing track. Line is
+// nil before the first line and Next is nil on the last one; LineIndex is -1
+// before the first line. Times are relative to the start of playback, so they
+// line up with the player's position when the track is trimmed.
+type Position struct {
+	TrackID   int64 `json:"trackId"`
+	LineIndex int   `json:"lineIndex"`
+	Line      *Line `json:"line,omitempty"`
+	Next      *Line `json:"next,omitempty"`
+	Playing   bool  `json:"playing"`
+}
+
+type loadedLyrics struct {
+	trackID     int64
+	lines       []Line
+	trimStartMS int
+}
+
+// Service follows the player state and publishes lyric line changes for
+// tracks with synced lyrics, read from an .lrc file next to the audio file.
+type Service struct {
+	mu        sync.Mutex
+	db        *sql.DB
+	emit      Emitter
+	current   loadedLyrics
+	lastIndex int
+	lastState bool
+}
+
+func NewService(database *sql.DB, bus *eventbus.Bus) *Service {
+	service := &Service{db: database, emit: bus.Publish, lastIndex: -1}
+	eventbus.Subscribe(bus, player.EventStateChanged, service.HandlePlayerState)
+
+	return service
+}
+
+// GetLyrics returns the synced lyrics of a track. Tracks without lyrics
+// return an empty, unsynced result.
+func (s *Service) GetLyrics(trackID int64) (Lyrics, error) {
+	path, err := s.trackPath(trackID)
+	if err != nil {
+		return Lyrics{}, err
+	}
+
+	lines := readSidecar(path)
+	if lines == nil {
+		lines = []Line{}
+	}
+	return Lyrics{TrackID: trackID, Synced: len(lines) > 0, Lines: lines}, nil
+}
+
+// HandlePlayerState publishes a position event when the playing track or its
+// current lyric line changes. Player ticks arrive twice a second, so the
+// highlight lags a line change by at most one tick.
+func (s *Service) HandlePlayerState(state player.State) {
+	trackID := int64(0)
+	path := ""
+	if state.CurrentTrack != nil {
+		trackID = state.CurrentTrack.ID
+		path = state.CurrentTrack.Path
+	}
+
+	s.mu.Lock()
+	loaded := s.current
+	s.mu.Unlock()
+
+	if loaded.trackID != trackID {
+		loaded = loadedLyrics{trackID: trackID}
+		if trackID > 0 {
+			loaded.lines = readSidecar(path)
+			if len(loaded.lines) > 0 {
+				loaded.trimStartMS = s.trimStartMS(trackID)
+			}
+		}
+	}
+
+	position := positionAt(loaded, state.PositionMS)
+	position.Playing = state.Status == player.StatusPlaying
+
+	s.mu.Lock()
+	trackChanged := s.current.trackID != loaded.trackID
+	s.current = loaded
+	changed := trackChanged || position.LineIndex != s.lastIndex || position.Playing != s.lastState
+	s.lastIndex = position.LineIndex
+	s.lastState = position.Playing
+	emitter := s.emit
+	s.mu.Unlock()
+
+	if changed && emitter != nil {
+		emitter(EventPosition, position)
+	}
+}
+
+func positionAt(loaded loadedLyrics, positionMS int) Position {
+	position := Position{TrackID: loaded.trackID, LineIndex: -1}
+	if len(loaded.lines) == 0 {
+		return position
+	}
+
+	index := lineIndexAt(loaded.lines, positionMS+loaded.trimStartMS)
+	position.LineIndex = index
+	if index >= 0 {
+		line := relativeLine(loaded.lines[index], loaded.trimStartMS)
+		position.Line = &line
+	}
+	if index+1 < len(loaded.lines) {
+		next := relativeLine(loaded.lines[index+1], loaded.trimStartMS)
+		position.Next = &next
+	}
+
+	return position
+}
+
+func relativeLine(line Line, trimStartMS int) Line {
+	return Line{TimeMS: max(line.TimeMS-trimStartMS, 0), Text: line.Text}
+}
+
+func (s *Service) trimStartMS(trackID int64) int {
+	if s.db == nil {
+		return 0
+	}
+
+	trim, err := library.NewTrackTrimRepository(s.db).GetTrackTrims(context.Background(), trackID)
+	if err != nil {
+		return 0
+	}
+
+	return trim.StartMS
+}
+
+func (s *Service) trackPath(trackID int64) (string, error) {
+	if s.db == nil {
+		return "", errors.New("library is unavailable")
+	}
+
+	var path string
+	err := s.db.QueryRowContext(
+		context.Background(),
+		`SELECT f.path
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		WHERE t.id = ?`,
+		trackID,
+	).Scan(&path)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", library.ErrTrackNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("get path for track %d: %w", trackID, err)
+	}
+
+	return path, nil
+}
+
+// readSidecar reads the synced lines of the .lrc file next to path. Remote
+// tracks and tracks without one have no lyrics.
+func readSidecar(path string) []Line {
+	if path == "" || strings.Contains(path, "://") {
+		return nil
+	}
+
+	content, err := os.ReadFile(strings.TrimSuffix(path, filepath.Ext(path)) + ".lrc")
+	if err != nil {
+		return nil
+	}
+
+	return ParseLRC(content)
+}
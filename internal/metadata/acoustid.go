@@ -0,0 +1,160 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const acoustIDLookupURL = "https://api.acoustid.org/v2/lookup"
+
+// acoustIDRequestGap keeps requests within AcoustID's limit of three
+// requests per second.
+const acoustIDRequestGap = 350 * time.Millisecond
+
+const fingerprintTimeout = 30 * time.Second
+
+// ErrFingerprintUnavailable is returned when Chromaprint's fpcalc tool is
+// not installed.
+var ErrFingerprintUnavailable = errors.New("fpcalc is not installed")
+
+// Fingerprint is a Chromaprint fingerprint and the duration, in seconds,
+// it was computed over.
+type Fingerprint struct {
+	Duration    float64 `json:"duration"`
+	Fingerprint string  `json:"fingerprint"`
+}
+
+// ComputeFingerprint runs Chromaprint's fpcalc on a local audio file.
+func ComputeFingerprint(ctx context.Context, path string) (Fingerprint, error) {
+	binary, err := exec.LookPath("fpcalc")
+	if err != nil {
+		return Fingerprint{}, ErrFingerprintUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fingerprintTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, binary, "-json", path).Output()
+	if err != nil {
+		return Fingerprint{}, fmt.Errorf("fingerprint %s: %w", path, err)
+	}
+
+	var result Fingerprint
+	if err := json.Unmarshal(output, &result); err != nil {
+		return Fingerprint{}, fmt.Errorf("decode fingerprint: %w", err)
+	}
+	if result.Fingerprint == "" || result.Duration <= 0 {
+		return Fingerprint{}, errors.New("fpcalc returned no fingerprint")
+	}
+
+	return result, nil
+}
+
+type acoustIDResponse struct {
+	Status  string `json:"status"`
+	Results []struct {
+		Score      float64 `json:"score"`
+		Recordings []struct {
+			ID      string `json:"id"`
+			Title   string `json:"title"`
+			Artists []struct {
+				Name       string `json:"name"`
+				JoinPhrase string `json:"joinphrase"`
+			} `json:"artists"`
+			ReleaseGroups []struct {
+				ID       string `json:"id"`
+				Title    string `json:"title"`
+				Type     string `json:"type"`
+				Releases []struct {
+					ID    string `json:"id"`
+					Title string `json:"title"`
+					Date  struct {
+						Year int `json:"year"`
+					} `json:"date"`
+				} `json:"releases"`
+			} `json:"releasegroups"`
+		} `json:"recordings"`
+	} `json:"results"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// lookupAcoustID identifies a recording from its fingerprint.
+func lookupAcoustID(ctx context.Context, client *httpClient, apiKey string, fp Fingerprint, album string) ([]Candidate, error) {
+	values := url.Values{}
+	values.Set("client", apiKey)
+	values.Set("meta", "recordings releasegroups releases")
+	values.Set("duration", strconv.Itoa(int(fp.Duration)))
+	values.Set("fingerprint", fp.Fingerprint)
+
+	var response acoustIDResponse
+	if err := client.getJSON(ctx, acoustIDLookupURL+"?"+values.Encode(), &response); err != nil {
+		return nil, fmt.Errorf("lookup acoustid: %w", err)
+	}
+
+	return acoustIDCandidates(response, album)
+}
+
+func acoustIDCandidates(response acoustIDResponse, album string) ([]Candidate, error) {
+	if response.Status != "ok" {
+		if response.Error != nil && response.Error.Message != "" {
+			return nil, fmt.Errorf("lookup acoustid: %s", response.Error.Message)
+		}
+		return nil, fmt.Errorf("lookup acoustid: status %q", response.Status)
+	}
+
+	candidates := make([]Candidate, 0)
+	for _, result := range response.Results {
+		for _, recording := range result.Recordings {
+			var artist strings.Builder
+			for _, credit := range recording.Artists {
+				artist.WriteString(credit.Name)
+				artist.WriteString(credit.JoinPhrase)
+			}
+
+			candidate := Candidate{
+				Source:      SourceAcoustID,
+				Score:       int(result.Score * 100),
+				RecordingID: recording.ID,
+				Title:       strings.TrimSpace(recording.Title),
+				Artist:      strings.TrimSpace(artist.String()),
+			}
+			candidate.AlbumArtist = candidate.Artist
+
+			// Prefer the release group named like the track's album.
+			for index, group := range recording.ReleaseGroups {
+				if index > 0 && !strings.EqualFold(strings.TrimSpace(group.Title), strings.TrimSpace(album)) {
+					continue
+				}
+				candidate.ReleaseGroupID = group.ID
+				candidate.Album = strings.TrimSpace(group.Title)
+				candidate.ReleaseID = ""
+				candidate.Year = nil
+				for _, release := range group.Releases {
+					if candidate.ReleaseID == "" || (release.Date.Year > 0 && (candidate.Year == nil || release.Date.Year < *candidate.Year)) {
+						candidate.ReleaseID = release.ID
+						if release.Date.Year > 0 {
+							year := release.Date.Year
+							candidate.Year = &year
+						}
+					}
+				}
+			}
+
+			candidates = append(candidates, candidate)
+			if len(candidates) >= maxCandidates {
+				return candidates, nil
+			}
+		}
+	}
+
+	return candidates, nil
+}
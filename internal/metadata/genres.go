@@ -0,0 +1,356 @@
+package metadata
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+const (
+	// InferredFromAlbum marks genres voted by the tagged tracks of the same
+	// album; InferredFromArtist by the artist's other tagged tracks.
+	InferredFromAlbum  = "album"
+	InferredFromArtist = "artist"
+)
+
+const (
+	// minGenreShare is the share of the votes a genre needs to be inferred.
+	minGenreShare = 0.5
+	// artistGenreFactor lowers the confidence of artist votes, as artists
+	// change genres between albums more than albums do between tracks.
+	artistGenreFactor = 0.8
+)
+
+// InferredGenre is a probable genre for a track without a genre tag.
+// Confidence runs from 0 to 1; Votes is the number of tagged tracks it was
+// inferred from.
+type InferredGenre struct {
+	TrackID    int64   `json:"trackId"`
+	Title      string  `json:"title"`
+	Artist     string  `json:"artist"`
+	Album      string  `json:"album"`
+	Genre      string  `json:"genre"`
+	Source     string  `json:"source"`
+	Confidence float64 `json:"confidence"`
+	Votes      int     `json:"votes"`
+}
+
+type GenreInferenceResult struct {
+	Untagged int `json:"untagged"`
+	Inferred int `json:"inferred"`
+}
+
+// genreVotes counts the main genres of tagged tracks, case-insensitively,
+// keeping the first spelling seen.
+type genreVotes struct {
+	counts   map[string]int
+	spelling map[string]string
+	total    int
+}
+
+func (v *genreVotes) add(genre string) {
+	key := normalizeKey(genre)
+	if key == "" {
+		return
+	}
+	if v.counts == nil {
+		v.counts = make(map[string]int)
+		v.spelling = make(map[string]string)
+	}
+	if _, ok := v.spelling[key]; !ok {
+		v.spelling[key] = genre
+	}
+	v.counts[key]++
+	v.total++
+}
+
+// winner returns the most voted genre and its share, ties going to the
+// alphabetically first genre.
+func (v *genreVotes) winner() (string, float64, bool) {
+	if v == nil || v.total == 0 {
+		return "", 0, false
+	}
+
+	keys := make([]string, 0, len(v.counts))
+	for key := range v.counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	best := keys[0]
+	for _, key := range keys[1:] {
+		if v.counts[key] > v.counts[best] {
+			best = key
+		}
+	}
+
+	return v.spelling[best], float64(v.counts[best]) / float64(v.total), true
+}
+
+type genreTrack struct {
+	id        int64
+	genre     string
+	artistKey string
+	albumID   int64
+}
+
+// inferGenres votes a genre for every untagged track, from its album first
+// and otherwise from its artist. Tracks whose votes are split are left out.
+func inferGenres(tracks []genreTrack) []InferredGenre {
+	albumVotes := make(map[int64]*genreVotes)
+	artistVotes := make(map[string]*genreVotes)
+	for _, track := range tracks {
+		genres := library.SplitGenres(track.genre)
+		if len(genres) == 0 {
+			continue
+		}
+		if track.albumID > 0 {
+			if albumVotes[track.albumID] == nil {
+				albumVotes[track.albumID] = &genreVotes{}
+			}
+			albumVotes[track.albumID].add(genres[0])
+		}
+		if track.artistKey != "" {
+			if artistVotes[track.artistKey] == nil {
+				artistVotes[track.artistKey] = &genreVotes{}
+			}
+			artistVotes[track.artistKey].add(genres[0])
+		}
+	}
+
+	inferred := make([]InferredGenre, 0)
+	for _, track := range tracks {
+		if strings.TrimSpace(track.genre) != "" {
+			continue
+		}
+
+		votes, source, factor := albumVotes[track.albumID], InferredFromAlbum, 1.0
+		if track.albumID <= 0 || votes == nil {
+			votes, source, factor = artistVotes[track.artistKey], InferredFromArtist, artistGenreFactor
+		}
+		genre, share, ok := votes.winner()
+		if !ok || share < minGenreShare {
+			continue
+		}
+
+		inferred = append(inferred, InferredGenre{
+			TrackID:    track.id,
+			Genre:      genre,
+			Source:     source,
+			Confidence: share * factor,
+			Votes:      votes.total,
+		})
+	}
+
+	return inferred
+}
+
+// InferGenres replaces the pending inferred genres with fresh votes from the
+// current tags. Dismissed tracks keep their dismissal and are skipped.
+func (s *Service) InferGenres(ctx context.Context) (GenreInferenceResult, error) {
+	result := GenreInferenceResult{}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, fmt.Errorf("begin genre inference tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			t.id,
+			COALESCE(TRIM(t.genre), ''),
+			COALESCE(t.artist, ''),
+			COALESCE((SELECT MIN(at.album_id) FROM album_tracks at WHERE at.track_id = t.id), 0)
+		FROM tracks t
+	`)
+	if err != nil {
+		return result, fmt.Errorf("query tracks for genre inference: %w", err)
+	}
+
+	tracks := make([]genreTrack, 0)
+	for rows.Next() {
+		var track genreTrack
+		var artist string
+		if scanErr := rows.Scan(&track.id, &track.genre, &artist, &track.albumID); scanErr != nil {
+			rows.Close()
+			return result, fmt.Errorf("scan track for genre inference: %w", scanErr)
+		}
+		track.artistKey = normalizeKey(artist)
+		if track.genre == "" {
+			result.Untagged++
+		}
+		tracks = append(tracks, track)
+	}
+	rowsErr := rows.Err()
+	rows.Close()
+	if rowsErr != nil {
+		return result, fmt.Errorf("iterate tracks for genre inference: %w", rowsErr)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM track_inferred_genres
+		WHERE dismissed = 0
+		   OR track_id IN (SELECT id FROM tracks WHERE NULLIF(TRIM(genre), '') IS NOT NULL)
+	`); err != nil {
+		return result, fmt.Errorf("clear inferred genres: %w", err)
+	}
+
+	for _, inferred := range inferGenres(tracks) {
+		insert, err := tx.ExecContext(ctx, `
+			INSERT INTO track_inferred_genres(track_id, genre, source, confidence, votes)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT(track_id) DO NOTHING
+		`, inferred.TrackID, inferred.Genre, inferred.Source, inferred.Confidence, inferred.Votes)
+		if err != nil {
+			return result, fmt.Errorf("store inferred genre of track %d: %w", inferred.TrackID, err)
+		}
+		if affected, _ := insert.RowsAffected(); affected > 0 {
+			result.Inferred++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("commit genre inference tx: %w", err)
+	}
+
+	return result, nil
+}
+
+// ListInferredGenres returns the pending inferred genres of tracks that are
+// still untagged, most confident first.
+func (s *Service) ListInferredGenres(ctx context.Context) ([]InferredGenre, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			i.track_id,
+			COALESCE(NULLIF(TRIM(t.title), ''), 'Unknown Title'),
+			COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist'),
+			COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album'),
+			i.genre,
+			i.source,
+			i.confidence,
+			i.votes
+		FROM track_inferred_genres i
+		JOIN tracks t ON t.id = i.track_id
+		WHERE i.dismissed = 0
+		  AND NULLIF(TRIM(t.genre), '') IS NULL
+		ORDER BY i.confidence DESC, t.artist COLLATE NOCASE, t.album COLLATE NOCASE, t.disc_no, t.track_no, i.track_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list inferred genres: %w", err)
+	}
+	defer rows.Close()
+
+	inferred := make([]InferredGenre, 0)
+	for rows.Next() {
+		var genre InferredGenre
+		if scanErr := rows.Scan(&genre.TrackID, &genre.Title, &genre.Artist, &genre.Album, &genre.Genre, &genre.Source, &genre.Confidence, &genre.Votes); scanErr != nil {
+			return nil, fmt.Errorf("scan inferred genre: %w", scanErr)
+		}
+		inferred = append(inferred, genre)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate inferred genres: %w", rowsErr)
+	}
+
+	return inferred, nil
+}
+
+// ConfirmInferredGenres makes the inferred genres of tracks their genre. The
+// genres are recorded as enrichments, so rescans fill them in again while
+// the tags stay empty. It returns the number of tracks updated.
+func (s *Service) ConfirmInferredGenres(ctx context.Context, trackIDs []int64) (int, error) {
+	if len(trackIDs) == 0 {
+		return 0, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin confirm genres tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	confirmed := 0
+	seen := make(map[int64]struct{}, len(trackIDs))
+	for _, trackID := range trackIDs {
+		if _, ok := seen[trackID]; ok || trackID <= 0 {
+			continue
+		}
+		seen[trackID] = struct{}{}
+
+		var genre string
+		err := tx.QueryRowContext(ctx, `
+			SELECT i.genre
+			FROM track_inferred_genres i
+			JOIN tracks t ON t.id = i.track_id
+			WHERE i.track_id = ?
+			  AND i.dismissed = 0
+			  AND NULLIF(TRIM(t.genre), '') IS NULL
+		`, trackID).Scan(&genre)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("get inferred genre of track %d: %w", trackID, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE tracks SET genre = ? WHERE id = ?", genre, trackID); err != nil {
+			return 0, fmt.Errorf("set genre of track %d: %w", trackID, err)
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM track_genres WHERE track_id = ?", trackID); err != nil {
+			return 0, fmt.Errorf("clear genres of track %d: %w", trackID, err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO track_genres(track_id, genre, position) VALUES (?, ?, 0)", trackID, genre); err != nil {
+			return 0, fmt.Errorf("add genre %q to track %d: %w", genre, trackID, err)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO track_enrichments(track_id, genre, source, applied_at)
+			VALUES (?, ?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+			ON CONFLICT(track_id) DO UPDATE SET
+				genre = excluded.genre,
+				applied_at = excluded.applied_at
+		`, trackID, genre, SourceInferred); err != nil {
+			return 0, fmt.Errorf("record genre enrichment for track %d: %w", trackID, err)
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM track_inferred_genres WHERE track_id = ?", trackID); err != nil {
+			return 0, fmt.Errorf("clear inferred genre of track %d: %w", trackID, err)
+		}
+		confirmed++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit confirm genres tx: %w", err)
+	}
+
+	return confirmed, nil
+}
+
+// DismissInferredGenres rejects the inferred genres of tracks, so later
+// inference runs leave them untagged. It returns the number dismissed.
+func (s *Service) DismissInferredGenres(ctx context.Context, trackIDs []int64) (int, error) {
+	dismissed := 0
+	for _, trackID := range trackIDs {
+		result, err := s.db.ExecContext(ctx, `
+			UPDATE track_inferred_genres
+			SET dismissed = 1
+			WHERE track_id = ? AND dismissed = 0
+		`, trackID)
+		if err != nil {
+			return dismissed, fmt.Errorf("dismiss inferred genre of track %d: %w", trackID, err)
+		}
+		if affected, _ := result.RowsAffected(); affected > 0 {
+			dismissed++
+		}
+	}
+
+	return dismissed, nil
+}
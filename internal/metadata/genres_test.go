@@ -0,0 +1,42 @@
+package metadata
+
+import "testing"
+
+func TestInferGenresPrefersAlbumSiblingsOverArtist(t *testing.T) {
+	t.Parallel()
+
+	inferred := inferGenres([]genreTrack{
+		{id: 1, genre: "Jazz; Bebop", artistKey: "a", albumID: 10},
+		{id: 2, genre: "jazz", artistKey: "a", albumID: 10},
+		{id: 3, genre: "", artistKey: "a", albumID: 10},
+		{id: 4, genre: "Rock", artistKey: "a", albumID: 20},
+		{id: 5, genre: "Rock", artistKey: "a", albumID: 21},
+		{id: 6, genre: "Rock", artistKey: "a", albumID: 22},
+		// No tagged siblings: falls back to the artist, 3 of 5 votes.
+		{id: 7, genre: "", artistKey: "a", albumID: 30},
+		// Split votes are left out.
+		{id: 8, genre: "Pop", artistKey: "b", albumID: 40},
+		{id: 9, genre: "Soul", artistKey: "b", albumID: 41},
+		{id: 10, genre: "Funk", artistKey: "b", albumID: 42},
+		{id: 11, genre: " ", artistKey: "b", albumID: 43},
+		// Nothing to vote from.
+		{id: 12, genre: "", artistKey: "", albumID: 0},
+	})
+
+	if len(inferred) != 2 {
+		t.Fatalf("expected two inferred genres, got %+v", inferred)
+	}
+
+	album := inferred[0]
+	if album.TrackID != 3 || album.Genre != "Jazz" || album.Source != InferredFromAlbum || album.Confidence != 1 || album.Votes != 2 {
+		t.Fatalf("expected jazz from the album, got %+v", album)
+	}
+
+	artist := inferred[1]
+	if artist.TrackID != 7 || artist.Genre != "Rock" || artist.Source != InferredFromArtist || artist.Votes != 5 {
+		t.Fatalf("expected rock from the artist, got %+v", artist)
+	}
+	if expected := 0.6 * artistGenreFactor; artist.Confidence < expected-1e-9 || artist.Confidence > expected+1e-9 {
+		t.Fatalf("expected confidence %f, got %f", expected, artist.Confidence)
+	}
+}
@@ -0,0 +1,268 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	musicBrainzSearchURL = "https://musicbrainz.org/ws/2/recording/"
+	userAgent            = "Ben/1.0 (desktop music player)"
+	requestTimeout       = 15 * time.Second
+	maxResponseBytes     = 4 << 20
+	maxCandidates        = 8
+)
+
+// musicBrainzRequestGap keeps requests within MusicBrainz's limit of one
+// request per second per client.
+const musicBrainzRequestGap = 1100 * time.Millisecond
+
+// httpClient sends rate-limited GET requests with the app's user agent.
+type httpClient struct {
+	http *http.Client
+	gap  time.Duration
+
+	mu          sync.Mutex
+	lastRequest time.Time
+}
+
+func newHTTPClient(gap time.Duration) *httpClient {
+	return &httpClient{http: &http.Client{Timeout: requestTimeout}, gap: gap}
+}
+
+func (c *httpClient) getJSON(ctx context.Context, requestURL string, target any) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("build metadata request: %w", err)
+	}
+	request.Header.Set("User-Agent", userAgent)
+	request.Header.Set("Accept", "application/json")
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return fmt.Errorf("metadata request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("metadata request: %s", response.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("read metadata response: %w", err)
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("decode metadata response: %w", err)
+	}
+
+	return nil
+}
+
+// wait blocks until the request gap since the previous request has passed.
+func (c *httpClient) wait(ctx context.Context) error {
+	c.mu.Lock()
+	next := c.lastRequest.Add(c.gap)
+	if now := time.Now(); next.Before(now) {
+		next = now
+	}
+	c.lastRequest = next
+	c.mu.Unlock()
+
+	delay := time.Until(next)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type musicBrainzSearch struct {
+	Recordings []musicBrainzRecording `json:"recordings"`
+}
+
+type musicBrainzRecording struct {
+	ID           string               `json:"id"`
+	Score        int                  `json:"score"`
+	Title        string               `json:"title"`
+	ArtistCredit []musicBrainzCredit  `json:"artist-credit"`
+	Releases     []musicBrainzRelease `json:"releases"`
+	Tags         []musicBrainzTag     `json:"tags"`
+}
+
+type musicBrainzCredit struct {
+	Name       string `json:"name"`
+	JoinPhrase string `json:"joinphrase"`
+}
+
+type musicBrainzRelease struct {
+	ID           string              `json:"id"`
+	Title        string              `json:"title"`
+	Status       string              `json:"status"`
+	Date         string              `json:"date"`
+	ArtistCredit []musicBrainzCredit `json:"artist-credit"`
+	ReleaseGroup struct {
+		ID          string `json:"id"`
+		PrimaryType string `json:"primary-type"`
+	} `json:"release-group"`
+}
+
+type musicBrainzTag struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// searchMusicBrainz finds recordings matching the query's title, artist and
+// album.
+func searchMusicBrainz(ctx context.Context, client *httpClient, query Query) ([]Candidate, error) {
+	terms := make([]string, 0, 3)
+	if query.Title != "" {
+		terms = append(terms, "recording:"+quoteLucene(query.Title))
+	}
+	if query.Artist != "" {
+		terms = append(terms, "artist:"+quoteLucene(query.Artist))
+	}
+	if query.Album != "" {
+		terms = append(terms, "release:"+quoteLucene(query.Album))
+	}
+	if len(terms) == 0 {
+		return []Candidate{}, nil
+	}
+
+	values := url.Values{}
+	values.Set("query", strings.Join(terms, " AND "))
+	values.Set("fmt", "json")
+	values.Set("limit", strconv.Itoa(maxCandidates))
+
+	var result musicBrainzSearch
+	if err := client.getJSON(ctx, musicBrainzSearchURL+"?"+values.Encode(), &result); err != nil {
+		return nil, fmt.Errorf("search musicbrainz: %w", err)
+	}
+
+	return musicBrainzCandidates(result, query.Album), nil
+}
+
+// musicBrainzCandidates turns recordings into one candidate per recording,
+// using the release that best matches album: an exact title match first,
+// then the earliest official release.
+func musicBrainzCandidates(result musicBrainzSearch, album string) []Candidate {
+	candidates := make([]Candidate, 0, len(result.Recordings))
+	for _, recording := range result.Recordings {
+		candidate := Candidate{
+			Source:      SourceMusicBrainz,
+			Score:       recording.Score,
+			RecordingID: recording.ID,
+			Title:       strings.TrimSpace(recording.Title),
+			Artist:      creditName(recording.ArtistCredit),
+			Genre:       topTag(recording.Tags),
+		}
+
+		if release, ok := pickRelease(recording.Releases, album); ok {
+			candidate.ReleaseID = release.ID
+			candidate.ReleaseGroupID = release.ReleaseGroup.ID
+			candidate.Album = strings.TrimSpace(release.Title)
+			candidate.AlbumArtist = creditName(release.ArtistCredit)
+			candidate.Year = releaseYear(release.Date)
+		}
+		if candidate.AlbumArtist == "" {
+			candidate.AlbumArtist = candidate.Artist
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	sort.SliceStable(candidates, func(i int, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	return candidates
+}
+
+func pickRelease(releases []musicBrainzRelease, album string) (musicBrainzRelease, bool) {
+	if len(releases) == 0 {
+		return musicBrainzRelease{}, false
+	}
+
+	best := -1
+	for index, release := range releases {
+		if best < 0 || releaseRank(release, album) < releaseRank(releases[best], album) {
+			best = index
+		}
+	}
+
+	return releases[best], true
+}
+
+// releaseRank orders releases by how well they fit; lower is better.
+func releaseRank(release musicBrainzRelease, album string) string {
+	titleRank := "1"
+	if album != "" && strings.EqualFold(strings.TrimSpace(release.Title), strings.TrimSpace(album)) {
+		titleRank = "0"
+	}
+	statusRank := "1"
+	if strings.EqualFold(release.Status, "official") {
+		statusRank = "0"
+	}
+	date := release.Date
+	if date == "" {
+		date = "9999"
+	}
+
+	return titleRank + statusRank + date
+}
+
+func creditName(credits []musicBrainzCredit) string {
+	var builder strings.Builder
+	for _, credit := range credits {
+		builder.WriteString(credit.Name)
+		builder.WriteString(credit.JoinPhrase)
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+func topTag(tags []musicBrainzTag) string {
+	best := musicBrainzTag{}
+	for _, tag := range tags {
+		if tag.Count > best.Count {
+			best = tag
+		}
+	}
+	return strings.TrimSpace(best.Name)
+}
+
+func releaseYear(date string) *int {
+	if len(date) < 4 {
+		return nil
+	}
+	year, err := strconv.Atoi(date[:4])
+	if err != nil || year <= 0 {
+		return nil
+	}
+	return &year
+}
+
+// quoteLucene quotes a phrase for the MusicBrainz search syntax.
+func quoteLucene(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,52 @@
+package metadata
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMusicBrainzCandidatesPreferMatchingOfficialRelease(t *testing.T) {
+	t.Parallel()
+
+	var result musicBrainzSearch
+	if err := json.Unmarshal([]byte(`{"recordings":[
+		{"id":"rec-1","score":72,"title":"Song","artist-credit":[{"name":"A","joinphrase":" & "},{"name":"B"}],
+		 "tags":[{"name":"rock","count":1},{"name":"indie","count":4}],
+		 "releases":[
+			{"id":"rel-comp","title":"Best Of","status":"Official","date":"1999-01-01","release-group":{"id":"rg-comp"}},
+			{"id":"rel-bootleg","title":"Album","status":"Bootleg","date":"1990","release-group":{"id":"rg-boot"}},
+			{"id":"rel-album","title":"album","status":"Official","date":"1994-05-02","release-group":{"id":"rg-album"},
+			 "artist-credit":[{"name":"A"}]}
+		 ]},
+		{"id":"rec-2","score":100,"title":"Song (Live)","artist-credit":[{"name":"A"}],"releases":[]}
+	]}`), &result); err != nil {
+		t.Fatalf("decode search result: %v", err)
+	}
+
+	candidates := musicBrainzCandidates(result, "Album")
+	if len(candidates) != 2 || candidates[0].RecordingID != "rec-2" {
+		t.Fatalf("expected candidates ordered by score, got %+v", candidates)
+	}
+
+	candidate := candidates[1]
+	if candidate.ReleaseID != "rel-album" || candidate.ReleaseGroupID != "rg-album" {
+		t.Fatalf("expected the official release named like the album, got %+v", candidate)
+	}
+	if candidate.Year == nil || *candidate.Year != 1994 {
+		t.Fatalf("expected year 1994, got %v", candidate.Year)
+	}
+	if candidate.Artist != "A & B" || candidate.AlbumArtist != "A" || candidate.Genre != "indie" {
+		t.Fatalf("unexpected credits or genre: %+v", candidate)
+	}
+	if candidates[0].AlbumArtist != "A" {
+		t.Fatalf("expected the track artist as album artist without a release, got %q", candidates[0].AlbumArtist)
+	}
+}
+
+func TestQuoteLuceneEscapesQuotes(t *testing.T) {
+	t.Parallel()
+
+	if got := quoteLucene(`Say "Hi" \o/`); got != `"Say \"Hi\" \\o/"` {
+		t.Fatalf("unexpected quoted phrase %s", got)
+	}
+}
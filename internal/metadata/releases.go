@@ -0,0 +1,168 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const musicBrainzReleaseURL = "https://musicbrainz.org/ws/2/release/"
+
+// fetchedAtLayout matches SQLite's strftime('%Y-%m-%dT%H:%M:%fZ'), so
+// cutoffs compare with stored times as strings.
+const fetchedAtLayout = "2006-01-02T15:04:05.000Z"
+
+var musicBrainzIDPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// ReleaseTrackListResult counts the releases RefreshReleaseTrackLists
+// looked up. Releases cached within the cache lifetime are not counted.
+type ReleaseTrackListResult struct {
+	Releases int `json:"releases"`
+	Fetched  int `json:"fetched"`
+	Failed   int `json:"failed"`
+}
+
+type musicBrainzReleaseLookup struct {
+	Media []struct {
+		Position   int `json:"position"`
+		TrackCount int `json:"track-count"`
+	} `json:"media"`
+}
+
+// RefreshReleaseTrackLists fetches the track count of each disc of the
+// MusicBrainz releases in the library, from the release ids in tags or
+// chosen when enriching. Album gaps are checked against these counts
+// before the track totals in tags.
+func (s *Service) RefreshReleaseTrackLists(ctx context.Context) (ReleaseTrackListResult, error) {
+	result := ReleaseTrackListResult{}
+	if !s.OnlineLookupsEnabled(ctx) {
+		return result, ErrLookupsDisabled
+	}
+
+	releaseIDs, err := s.staleReleaseIDs(ctx)
+	if err != nil {
+		return result, err
+	}
+	result.Releases = len(releaseIDs)
+
+	for _, releaseID := range releaseIDs {
+		trackCounts, err := lookupReleaseTrackCounts(ctx, s.musicBrainz, releaseID)
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		if err != nil || len(trackCounts) == 0 {
+			result.Failed++
+			continue
+		}
+		if err := s.storeReleaseTrackCounts(ctx, releaseID, trackCounts); err != nil {
+			return result, err
+		}
+		result.Fetched++
+	}
+
+	return result, nil
+}
+
+// staleReleaseIDs returns the release ids of present tracks whose track
+// list is not cached or older than the cache lifetime.
+func (s *Service) staleReleaseIDs(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT LOWER(TRIM(release_id))
+		FROM (
+			SELECT CASE WHEN json_valid(t.tags_json) THEN json_extract(t.tags_json, '$.taglib_tags.MUSICBRAINZ_ALBUMID[0]') END AS release_id
+			FROM tracks t
+			JOIN files f ON f.id = t.file_id
+			WHERE f.file_exists = 1
+			UNION ALL
+			SELECT e.release_id
+			FROM track_enrichments e
+			JOIN tracks t ON t.id = e.track_id
+			JOIN files f ON f.id = t.file_id
+			WHERE f.file_exists = 1
+		)
+		WHERE NULLIF(TRIM(release_id), '') IS NOT NULL
+		  AND LOWER(TRIM(release_id)) NOT IN (
+			SELECT release_id FROM release_track_lists WHERE fetched_at > ?
+		  )
+		ORDER BY 1
+	`, time.Now().UTC().Add(-lookupCacheTTL).Format(fetchedAtLayout))
+	if err != nil {
+		return nil, fmt.Errorf("query release ids: %w", err)
+	}
+	defer rows.Close()
+
+	releaseIDs := make([]string, 0)
+	for rows.Next() {
+		var releaseID string
+		if err := rows.Scan(&releaseID); err != nil {
+			return nil, fmt.Errorf("scan release id: %w", err)
+		}
+		if musicBrainzIDPattern.MatchString(releaseID) {
+			releaseIDs = append(releaseIDs, releaseID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate release ids: %w", err)
+	}
+
+	return releaseIDs, nil
+}
+
+func (s *Service) storeReleaseTrackCounts(ctx context.Context, releaseID string, trackCounts map[int]int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin release track list tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM release_track_lists WHERE release_id = ?", releaseID); err != nil {
+		return fmt.Errorf("clear release track list: %w", err)
+	}
+	for discNo, trackCount := range trackCounts {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO release_track_lists(release_id, disc_no, track_count)
+			VALUES (?, ?, ?)
+		`, releaseID, discNo, trackCount); err != nil {
+			return fmt.Errorf("store release track list: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit release track list tx: %w", err)
+	}
+
+	return nil
+}
+
+// lookupReleaseTrackCounts looks a release up by its MusicBrainz id and
+// returns the track count of each disc.
+func lookupReleaseTrackCounts(ctx context.Context, client *httpClient, releaseID string) (map[int]int, error) {
+	var release musicBrainzReleaseLookup
+	requestURL := musicBrainzReleaseURL + url.PathEscape(strings.ToLower(releaseID)) + "?fmt=json"
+	if err := client.getJSON(ctx, requestURL, &release); err != nil {
+		return nil, fmt.Errorf("look up musicbrainz release %s: %w", releaseID, err)
+	}
+
+	return releaseTrackCounts(release), nil
+}
+
+func releaseTrackCounts(release musicBrainzReleaseLookup) map[int]int {
+	trackCounts := make(map[int]int, len(release.Media))
+	for index, medium := range release.Media {
+		if medium.TrackCount <= 0 {
+			continue
+		}
+		discNo := medium.Position
+		if discNo <= 0 {
+			discNo = index + 1
+		}
+		trackCounts[discNo] = medium.TrackCount
+	}
+
+	return trackCounts
+}
@@ -0,0 +1,41 @@
+package metadata
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestReleaseTrackCountsReadsEachDisc(t *testing.T) {
+	t.Parallel()
+
+	var release musicBrainzReleaseLookup
+	if err := json.Unmarshal([]byte(`{"id":"rel","media":[
+		{"position":1,"format":"CD","track-count":12},
+		{"position":2,"format":"CD","track-count":9},
+		{"position":3,"format":"DVD-Video","track-count":0},
+		{"format":"CD","track-count":4}
+	]}`), &release); err != nil {
+		t.Fatalf("decode release: %v", err)
+	}
+
+	want := map[int]int{1: 12, 2: 9, 4: 4}
+	if got := releaseTrackCounts(release); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestMusicBrainzIDPatternAcceptsOnlyReleaseIDs(t *testing.T) {
+	t.Parallel()
+
+	for id, want := range map[string]bool{
+		"0b9d2f5a-1c3e-4d6f-8a7b-9c0d1e2f3a4b": true,
+		"0B9D2F5A-1C3E-4D6F-8A7B-9C0D1E2F3A4B": false,
+		"../recording/0b9d2f5a":                false,
+		"":                                     false,
+	} {
+		if got := musicBrainzIDPattern.MatchString(id); got != want {
+			t.Fatalf("%q: got %v, want %v", id, got, want)
+		}
+	}
+}
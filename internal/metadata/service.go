@@ -0,0 +1,413 @@
+// Package metadata looks up missing track metadata online, from MusicBrainz
+// by title, artist and album or from AcoustID by audio fingerprint. Lookups
+// only suggest candidates; nothing is written to the library until the user
+// enriches tracks with a chosen candidate.
+package metadata
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rzxx/ben/internal/settings"
+)
+
+const (
+	// OnlineLookupsSettingKey turns online lookups on. They are off until
+	// the user opts in.
+	OnlineLookupsSettingKey = "metadata.online_lookups"
+	// AcoustIDKeySettingKey stores the AcoustID application key needed for
+	// fingerprint lookups.
+	AcoustIDKeySettingKey = "metadata.acoustid_key"
+)
+
+const (
+	SourceMusicBrainz = "musicbrainz"
+	SourceAcoustID    = "acoustid"
+	// SourceInferred marks genres confirmed from InferGenres.
+	SourceInferred = "inferred"
+)
+
+// lookupCacheTTL is how long a cached lookup is reused.
+const lookupCacheTTL = 30 * 24 * time.Hour
+
+var (
+	ErrLookupsDisabled  = errors.New("online metadata lookups are turned off")
+	ErrAcoustIDKeyUnset = errors.New("an AcoustID key is required for fingerprint lookups")
+	ErrTrackNotFound    = errors.New("track not found")
+)
+
+// Query describes the track to look up.
+type Query struct {
+	Title  string `json:"title"`
+	Artist string `json:"artist"`
+	Album  string `json:"album"`
+}
+
+// Candidate is a possible match for a track. Score runs from 0 to 100.
+type Candidate struct {
+	Source         string `json:"source"`
+	Score          int    `json:"score"`
+	RecordingID    string `json:"recordingId"`
+	ReleaseID      string `json:"releaseId"`
+	ReleaseGroupID string `json:"releaseGroupId"`
+	Title          string `json:"title"`
+	Artist         string `json:"artist"`
+	Album          string `json:"album"`
+	AlbumArtist    string `json:"albumArtist"`
+	Year           *int   `json:"year,omitempty"`
+	Genre          string `json:"genre"`
+}
+
+// TrackLookup is a track's current metadata and the candidates found for it.
+type TrackLookup struct {
+	TrackID     int64       `json:"trackId"`
+	Title       string      `json:"title"`
+	Artist      string      `json:"artist"`
+	Album       string      `json:"album"`
+	AlbumArtist string      `json:"albumArtist"`
+	Year        *int        `json:"year,omitempty"`
+	Genre       string      `json:"genre"`
+	Cached      bool        `json:"cached"`
+	Candidates  []Candidate `json:"candidates"`
+}
+
+// EnrichResult counts the tracks an enrich action filled fields in.
+type EnrichResult struct {
+	Tracks   int `json:"tracks"`
+	Updated  int `json:"updated"`
+	Years    int `json:"years"`
+	Artists  int `json:"albumArtists"`
+	Genres   int `json:"genres"`
+	Releases int `json:"releases"`
+}
+
+type Service struct {
+	db          *sql.DB
+	settings    *settings.Store
+	musicBrainz *httpClient
+	acoustID    *httpClient
+}
+
+func NewService(database *sql.DB) *Service {
+	service := &Service{
+		db:          database,
+		musicBrainz: newHTTPClient(musicBrainzRequestGap),
+		acoustID:    newHTTPClient(acoustIDRequestGap),
+	}
+	if database != nil {
+		service.settings = settings.NewStore(database)
+	}
+	return service
+}
+
+func (s *Service) OnlineLookupsEnabled(ctx context.Context) bool {
+	if s.settings == nil {
+		return false
+	}
+
+	enabled, err := strconv.ParseBool(s.settings.GetString(ctx, OnlineLookupsSettingKey, "false"))
+	return err == nil && enabled
+}
+
+func (s *Service) SetOnlineLookupsEnabled(ctx context.Context, enabled bool) (bool, error) {
+	if s.settings == nil {
+		return false, errors.New("settings are unavailable")
+	}
+	if err := s.settings.Set(ctx, OnlineLookupsSettingKey, strconv.FormatBool(enabled)); err != nil {
+		return s.OnlineLookupsEnabled(ctx), err
+	}
+
+	return enabled, nil
+}
+
+// HasAcoustIDKey reports whether fingerprint lookups are configured. The key
+// itself is never returned.
+func (s *Service) HasAcoustIDKey(ctx context.Context) bool {
+	return s.acoustIDKey(ctx) != ""
+}
+
+func (s *Service) SetAcoustIDKey(ctx context.Context, key string) error {
+	if s.settings == nil {
+		return errors.New("settings are unavailable")
+	}
+
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return s.settings.Delete(ctx, AcoustIDKeySettingKey)
+	}
+	return s.settings.Set(ctx, AcoustIDKeySettingKey, key)
+}
+
+func (s *Service) acoustIDKey(ctx context.Context) string {
+	if s.settings == nil {
+		return ""
+	}
+	return strings.TrimSpace(s.settings.GetString(ctx, AcoustIDKeySettingKey, ""))
+}
+
+// LookupTrack searches MusicBrainz for a track by its title, artist and
+// album.
+func (s *Service) LookupTrack(ctx context.Context, trackID int64) (TrackLookup, error) {
+	lookup, _, err := s.trackLookup(ctx, trackID)
+	if err != nil {
+		return TrackLookup{}, err
+	}
+
+	query := Query{Title: lookup.Title, Artist: lookup.Artist, Album: lookup.Album}
+	cacheKey := SourceMusicBrainz + "\x00" + normalizeKey(query.Title) + "\x00" + normalizeKey(query.Artist) + "\x00" + normalizeKey(query.Album)
+	lookup.Candidates, lookup.Cached, err = s.cachedLookup(ctx, cacheKey, func() ([]Candidate, error) {
+		return searchMusicBrainz(ctx, s.musicBrainz, query)
+	})
+	if err != nil {
+		return TrackLookup{}, err
+	}
+
+	return lookup, nil
+}
+
+// LookupTrackByFingerprint identifies a track by its audio fingerprint,
+// which also works for files with missing or wrong tags. It needs fpcalc and
+// an AcoustID key.
+func (s *Service) LookupTrackByFingerprint(ctx context.Context, trackID int64) (TrackLookup, error) {
+	lookup, path, err := s.trackLookup(ctx, trackID)
+	if err != nil {
+		return TrackLookup{}, err
+	}
+
+	apiKey := s.acoustIDKey(ctx)
+	if apiKey == "" {
+		return TrackLookup{}, ErrAcoustIDKeyUnset
+	}
+	fp, ok := s.storedFingerprint(ctx, trackID)
+	if !ok {
+		if _, err := os.Stat(path); err != nil {
+			return TrackLookup{}, fmt.Errorf("fingerprint lookups need a local file: %w", err)
+		}
+
+		fp, err = ComputeFingerprint(ctx, path)
+		if err != nil {
+			return TrackLookup{}, err
+		}
+	}
+
+	cacheKey := SourceAcoustID + "\x00" + fp.Fingerprint
+	lookup.Candidates, lookup.Cached, err = s.cachedLookup(ctx, cacheKey, func() ([]Candidate, error) {
+		return lookupAcoustID(ctx, s.acoustID, apiKey, fp, lookup.Album)
+	})
+	if err != nil {
+		return TrackLookup{}, err
+	}
+
+	return lookup, nil
+}
+
+// storedFingerprint returns the fingerprint the scanner stored for the
+// track's file, if fingerprinting during scans is on.
+func (s *Service) storedFingerprint(ctx context.Context, trackID int64) (Fingerprint, bool) {
+	var fp Fingerprint
+	err := s.db.QueryRowContext(ctx, `
+		SELECT f.fingerprint, COALESCE(f.fingerprint_duration, 0)
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		WHERE t.id = ? AND COALESCE(f.fingerprint, '') <> ''
+	`, trackID).Scan(&fp.Fingerprint, &fp.Duration)
+	if err != nil || fp.Duration <= 0 {
+		return Fingerprint{}, false
+	}
+
+	return fp, true
+}
+
+func (s *Service) trackLookup(ctx context.Context, trackID int64) (TrackLookup, string, error) {
+	if !s.OnlineLookupsEnabled(ctx) {
+		return TrackLookup{}, "", ErrLookupsDisabled
+	}
+
+	lookup := TrackLookup{TrackID: trackID, Candidates: []Candidate{}}
+	var path string
+	var year sql.NullInt64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT
+			COALESCE(TRIM(t.title), ''),
+			COALESCE(TRIM(t.artist), ''),
+			COALESCE(TRIM(t.album), ''),
+			COALESCE(TRIM(t.album_artist), ''),
+			t.year,
+			COALESCE(TRIM(t.genre), ''),
+			f.path
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		WHERE t.id = ?
+	`, trackID).Scan(&lookup.Title, &lookup.Artist, &lookup.Album, &lookup.AlbumArtist, &year, &lookup.Genre, &path)
+	if errors.Is(err, sql.ErrNoRows) {
+		return TrackLookup{}, "", ErrTrackNotFound
+	}
+	if err != nil {
+		return TrackLookup{}, "", fmt.Errorf("get track %d for lookup: %w", trackID, err)
+	}
+	if year.Valid && year.Int64 > 0 {
+		value := int(year.Int64)
+		lookup.Year = &value
+	}
+
+	return lookup, path, nil
+}
+
+// cachedLookup returns the cached candidates for key, or runs fetch and
+// caches its result.
+func (s *Service) cachedLookup(ctx context.Context, key string, fetch func() ([]Candidate, error)) ([]Candidate, bool, error) {
+	var candidatesJSON string
+	var fetchedAt string
+	err := s.db.QueryRowContext(ctx, "SELECT candidates_json, fetched_at FROM metadata_lookups WHERE cache_key = ?", key).Scan(&candidatesJSON, &fetchedAt)
+	if err == nil {
+		fetched, parseErr := time.Parse(time.RFC3339Nano, fetchedAt)
+		var candidates []Candidate
+		if parseErr == nil && time.Since(fetched) < lookupCacheTTL && json.Unmarshal([]byte(candidatesJSON), &candidates) == nil {
+			if candidates == nil {
+				candidates = []Candidate{}
+			}
+			return candidates, true, nil
+		}
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, false, fmt.Errorf("read metadata lookup cache: %w", err)
+	}
+
+	candidates, err := fetch()
+	if err != nil {
+		return nil, false, err
+	}
+
+	encoded, err := json.Marshal(candidates)
+	if err != nil {
+		return nil, false, fmt.Errorf("encode metadata candidates: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO metadata_lookups(cache_key, candidates_json, fetched_at)
+		VALUES (?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+		ON CONFLICT(cache_key) DO UPDATE SET
+			candidates_json = excluded.candidates_json,
+			fetched_at = excluded.fetched_at
+	`, key, string(encoded)); err != nil {
+		return nil, false, fmt.Errorf("cache metadata lookup: %w", err)
+	}
+
+	return candidates, false, nil
+}
+
+// Enrich fills the year, album artist and genre of tracks from a chosen
+// candidate, and records its release ids. Only empty fields are filled, so
+// tagged values are never replaced. The values are kept so later scans fill
+// them in again while the tags still leave them empty.
+func (s *Service) Enrich(ctx context.Context, trackIDs []int64, candidate Candidate) (EnrichResult, error) {
+	result := EnrichResult{}
+	if len(trackIDs) == 0 {
+		return result, nil
+	}
+
+	source := strings.TrimSpace(candidate.Source)
+	if source != SourceMusicBrainz && source != SourceAcoustID {
+		return result, fmt.Errorf("unknown metadata source %q", candidate.Source)
+	}
+
+	var year any
+	if candidate.Year != nil && *candidate.Year > 0 {
+		year = *candidate.Year
+	}
+	albumArtist := strings.TrimSpace(candidate.AlbumArtist)
+	genre := strings.TrimSpace(candidate.Genre)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, fmt.Errorf("begin enrich tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	seen := make(map[int64]struct{}, len(trackIDs))
+	for _, trackID := range trackIDs {
+		if _, ok := seen[trackID]; ok || trackID <= 0 {
+			continue
+		}
+		seen[trackID] = struct{}{}
+
+		var currentYear sql.NullInt64
+		var currentAlbumArtist string
+		var currentGenre string
+		err := tx.QueryRowContext(ctx, `
+			SELECT year, COALESCE(TRIM(album_artist), ''), COALESCE(TRIM(genre), '')
+			FROM tracks
+			WHERE id = ?
+		`, trackID).Scan(&currentYear, &currentAlbumArtist, &currentGenre)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return EnrichResult{}, fmt.Errorf("get track %d for enrich: %w", trackID, err)
+		}
+		result.Tracks++
+
+		fillYear := year != nil && (!currentYear.Valid || currentYear.Int64 <= 0)
+		fillAlbumArtist := albumArtist != "" && currentAlbumArtist == ""
+		fillGenre := genre != "" && currentGenre == ""
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE tracks
+			SET year = CASE WHEN ? THEN ? ELSE year END,
+				album_artist = CASE WHEN ? THEN ? ELSE album_artist END,
+				genre = CASE WHEN ? THEN ? ELSE genre END
+			WHERE id = ?
+		`, fillYear, year, fillAlbumArtist, albumArtist, fillGenre, genre, trackID); err != nil {
+			return EnrichResult{}, fmt.Errorf("enrich track %d: %w", trackID, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO track_enrichments(track_id, year, album_artist, genre, recording_id, release_id, release_group_id, source, applied_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+			ON CONFLICT(track_id) DO UPDATE SET
+				year = excluded.year,
+				album_artist = excluded.album_artist,
+				genre = excluded.genre,
+				recording_id = excluded.recording_id,
+				release_id = excluded.release_id,
+				release_group_id = excluded.release_group_id,
+				source = excluded.source,
+				applied_at = excluded.applied_at
+		`, trackID, year, albumArtist, genre, strings.TrimSpace(candidate.RecordingID), strings.TrimSpace(candidate.ReleaseID), strings.TrimSpace(candidate.ReleaseGroupID), source); err != nil {
+			return EnrichResult{}, fmt.Errorf("record enrichment for track %d: %w", trackID, err)
+		}
+
+		if fillYear {
+			result.Years++
+		}
+		if fillAlbumArtist {
+			result.Artists++
+		}
+		if fillGenre {
+			result.Genres++
+		}
+		if fillYear || fillAlbumArtist || fillGenre {
+			result.Updated++
+		}
+		if candidate.ReleaseGroupID != "" {
+			result.Releases++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return EnrichResult{}, fmt.Errorf("commit enrich tx: %w", err)
+	}
+
+	return result, nil
+}
+
+func normalizeKey(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
@@ -0,0 +1,200 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+
+	"github.com/rzxx/ben/internal/eventbus"
+	"github.com/rzxx/ben/internal/i18n"
+	"github.com/rzxx/ben/internal/player"
+	"github.com/rzxx/ben/internal/settings"
+)
+
+// TrackNotificationsSettingKey stores the track change notification
+// options as JSON.
+const TrackNotificationsSettingKey = "platform.track_notifications"
+
+// TrackNotifications controls the native notification shown when a new
+// track starts. WhenFocused and WhenUnfocused pick whether it is shown
+// while the player window has focus and while it does not.
+type TrackNotifications struct {
+	Enabled       bool `json:"enabled"`
+	WhenFocused   bool `json:"whenFocused"`
+	WhenUnfocused bool `json:"whenUnfocused"`
+}
+
+var defaultTrackNotifications = TrackNotifications{WhenUnfocused: true}
+
+// trackNotification is what the native sender shows. ImagePath is a local
+// cover file, or empty when the track has none.
+type trackNotification struct {
+	Title     string
+	Body      string
+	ImagePath string
+}
+
+// notificationSender shows notifications through the operating system.
+// Senders are created once and may be called from any goroutine.
+type notificationSender interface {
+	Send(notification trackNotification) error
+}
+
+// Notifier shows a native notification whenever the playing track changes.
+type Notifier struct {
+	mu          sync.Mutex
+	app         *application.App
+	settings    *settings.Store
+	sender      notificationSender
+	localizer   *i18n.Localizer
+	config      TrackNotifications
+	lastTrackID int64
+}
+
+func NewNotifier(store *settings.Store, bus *eventbus.Bus) *Notifier {
+	notifier := &Notifier{
+		settings:  store,
+		sender:    newNotificationSender(),
+		localizer: i18n.NewLocalizer(i18n.DefaultLocale),
+		config:    defaultTrackNotifications,
+	}
+	notifier.load()
+	eventbus.Subscribe(bus, player.EventStateChanged, notifier.HandlePlayerState)
+
+	return notifier
+}
+
+// SetLocalizer sets the locale of the placeholders shown for missing tags.
+func (n *Notifier) SetLocalizer(localizer *i18n.Localizer) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.localizer = localizer
+}
+
+// SetApp gives the notifier the application whose windows decide whether
+// the player has focus.
+func (n *Notifier) SetApp(app *application.App) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.app = app
+}
+
+func (n *Notifier) GetConfig() TrackNotifications {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.config
+}
+
+func (n *Notifier) SetConfig(config TrackNotifications) (TrackNotifications, error) {
+	if n.settings != nil {
+		encoded, err := json.Marshal(config)
+		if err != nil {
+			return n.GetConfig(), fmt.Errorf("encode track notifications: %w", err)
+		}
+		if err := n.settings.Set(context.Background(), TrackNotificationsSettingKey, string(encoded)); err != nil {
+			return n.GetConfig(), err
+		}
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.config = config
+	return config, nil
+}
+
+func (n *Notifier) load() {
+	if n.settings == nil {
+		return
+	}
+
+	raw, ok, err := n.settings.Get(context.Background(), TrackNotificationsSettingKey)
+	if err != nil || !ok {
+		return
+	}
+
+	var stored TrackNotifications
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+		return
+	}
+	n.config = stored
+}
+
+// HandlePlayerState notifies about a track that starts playing. A track
+// restored paused at startup and pause/resume are not announced.
+func (n *Notifier) HandlePlayerState(state player.State) {
+	if state.CurrentTrack == nil {
+		n.mu.Lock()
+		n.lastTrackID = 0
+		n.mu.Unlock()
+		return
+	}
+
+	n.mu.Lock()
+	changed := n.lastTrackID != state.CurrentTrack.ID
+	n.lastTrackID = state.CurrentTrack.ID
+	config := n.config
+	sender := n.sender
+	localizer := n.localizer
+	app := n.app
+	n.mu.Unlock()
+
+	if !changed || state.Status != player.StatusPlaying || sender == nil || !config.Enabled {
+		return
+	}
+	if focused := windowFocused(app); (focused && !config.WhenFocused) || (!focused && !config.WhenUnfocused) {
+		return
+	}
+
+	notification := trackNotificationFor(state, localizer)
+	go func() {
+		if err := sender.Send(notification); err != nil {
+			log.Printf("track notification failed: %v", err)
+		}
+	}()
+}
+
+func windowFocused(app *application.App) bool {
+	if app == nil || app.Window == nil {
+		return false
+	}
+
+	for _, window := range app.Window.GetAll() {
+		if window.IsFocused() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// trackNotificationFor shows the title, artist and album of the playing
+// track. Missing tags show the localized placeholder the player shows.
+func trackNotificationFor(state player.State, localizer *i18n.Localizer) trackNotification {
+	track := state.CurrentTrack
+	title := strings.TrimSpace(track.Title)
+	if title == "" {
+		title = i18n.UnknownTitle
+	}
+	artist := strings.TrimSpace(track.Artist)
+	if artist == "" {
+		artist = i18n.UnknownArtist
+	}
+
+	notification := trackNotification{
+		Title: localizer.Placeholder(title),
+		Body:  localizer.Placeholder(artist),
+	}
+	if album := strings.TrimSpace(track.Album); album != "" {
+		notification.Body += " — " + localizer.Placeholder(album)
+	}
+	if track.CoverPath != nil {
+		notification.ImagePath = strings.TrimSpace(*track.CoverPath)
+	}
+
+	return notification
+}
@@ -0,0 +1,58 @@
+//go:build darwin
+
+package platform
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// darwinSender posts notifications through Notification Center. AppleScript
+// notifications cannot carry an image, so covers are shown through
+// terminal-notifier when it is installed, and AppleScript is the fallback.
+type darwinSender struct {
+	terminalNotifier string
+}
+
+func newNotificationSender() notificationSender {
+	path, _ := exec.LookPath("terminal-notifier")
+	return darwinSender{terminalNotifier: path}
+}
+
+func (s darwinSender) Send(notification trackNotification) error {
+	command := exec.Command("osascript", "-e", fmt.Sprintf(
+		"display notification %s with title %s",
+		appleScriptString(notification.Body),
+		appleScriptString(notification.Title),
+	))
+	if s.terminalNotifier != "" {
+		command = exec.Command(s.terminalNotifier, terminalNotifierArgs(notification)...)
+	}
+
+	if output, err := command.CombinedOutput(); err != nil {
+		return fmt.Errorf("send notification: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// terminalNotifierArgs shows the cover next to the text. The group makes
+// each notification replace the previous one.
+func terminalNotifierArgs(notification trackNotification) []string {
+	args := []string{
+		"-title", notification.Title,
+		"-message", notification.Body,
+		"-group", "ben.track",
+	}
+	if notification.ImagePath != "" {
+		args = append(args, "-contentImage", notification.ImagePath)
+	}
+
+	return args
+}
+
+func appleScriptString(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ", "\r", " ")
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,65 @@
+//go:build linux
+
+package platform
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	notificationsBusName    = "org.freedesktop.Notifications"
+	notificationsObjectPath = "/org/freedesktop/Notifications"
+	notificationsAppName    = "Ben"
+)
+
+// dbusSender talks to the desktop notification daemon. Each notification
+// replaces the previous one, so skipping through tracks does not stack up
+// a pile of them.
+type dbusSender struct {
+	mu     sync.Mutex
+	lastID uint32
+}
+
+func newNotificationSender() notificationSender {
+	return &dbusSender{}
+}
+
+func (s *dbusSender) Send(notification trackNotification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return fmt.Errorf("connect to session bus: %w", err)
+	}
+
+	hints := map[string]dbus.Variant{
+		"category": dbus.MakeVariant("x-gnome.music"),
+	}
+	if notification.ImagePath != "" {
+		hints["image-path"] = dbus.MakeVariant(notification.ImagePath)
+	}
+
+	var id uint32
+	call := conn.Object(notificationsBusName, notificationsObjectPath).Call(
+		notificationsBusName+".Notify",
+		0,
+		notificationsAppName,
+		s.lastID,
+		notification.ImagePath,
+		notification.Title,
+		notification.Body,
+		[]string{},
+		hints,
+		int32(-1),
+	)
+	if err := call.Store(&id); err != nil {
+		return fmt.Errorf("send notification: %w", err)
+	}
+
+	s.lastID = id
+	return nil
+}
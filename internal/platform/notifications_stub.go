@@ -0,0 +1,7 @@
+//go:build !linux && !darwin && !windows
+
+package platform
+
+func newNotificationSender() notificationSender {
+	return nil
+}
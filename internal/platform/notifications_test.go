@@ -0,0 +1,62 @@
+package platform
+
+import (
+	"testing"
+
+	"github.com/rzxx/ben/internal/i18n"
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/player"
+)
+
+func TestTrackNotificationForShowsTheTrackAndItsCover(t *testing.T) {
+	t.Parallel()
+
+	coverPath := " /covers/1.jpg "
+	english := i18n.NewLocalizer(i18n.DefaultLocale)
+	german := i18n.NewLocalizer("de")
+	cases := []struct {
+		name      string
+		track     library.TrackSummary
+		localizer *i18n.Localizer
+		want      trackNotification
+	}{
+		{
+			name:      "all tags",
+			track:     library.TrackSummary{Title: "Song", Artist: "Band", Album: "Hits", CoverPath: &coverPath},
+			localizer: english,
+			want:      trackNotification{Title: "Song", Body: "Band — Hits", ImagePath: "/covers/1.jpg"},
+		},
+		{
+			name:      "no album or cover",
+			track:     library.TrackSummary{Title: "Song", Artist: "Band"},
+			localizer: english,
+			want:      trackNotification{Title: "Song", Body: "Band"},
+		},
+		{
+			name:      "missing tags",
+			track:     library.TrackSummary{Title: " ", Album: i18n.UnknownAlbum},
+			localizer: english,
+			want:      trackNotification{Title: i18n.UnknownTitle, Body: i18n.UnknownArtist + " — " + i18n.UnknownAlbum},
+		},
+		{
+			name:      "localized placeholders",
+			track:     library.TrackSummary{Album: "Hits"},
+			localizer: german,
+			want: trackNotification{
+				Title: german.Placeholder(i18n.UnknownTitle),
+				Body:  german.Placeholder(i18n.UnknownArtist) + " — Hits",
+			},
+		},
+	}
+	for _, tc := range cases {
+		track := tc.track
+		got := trackNotificationFor(player.State{Status: player.StatusPlaying, CurrentTrack: &track}, tc.localizer)
+		if got != tc.want {
+			t.Fatalf("%s: got %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+
+	if german.Placeholder(i18n.UnknownTitle) == i18n.UnknownTitle {
+		t.Fatalf("expected the German placeholder to be translated")
+	}
+}
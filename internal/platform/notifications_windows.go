@@ -0,0 +1,218 @@
+//go:build windows
+
+package platform
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/zzl/go-win32api/v2/win32"
+	"github.com/zzl/go-winrtapi/winrt"
+	"golang.org/x/sys/windows/registry"
+)
+
+const (
+	xmlDocumentClassName       = "Windows.Data.Xml.Dom.XmlDocument"
+	toastNotificationClassName = "Windows.UI.Notifications.ToastNotification"
+	toastManagerClassName      = "Windows.UI.Notifications.ToastNotificationManager"
+	toastDisplayName           = "Ben"
+)
+
+// toastSender shows Windows toast notifications. Unpackaged apps need their
+// AppUserModelID registered before Windows shows their toasts, which is
+// done once on the first notification.
+type toastSender struct {
+	registerOnce sync.Once
+	registerErr  error
+}
+
+func newNotificationSender() notificationSender {
+	return &toastSender{}
+}
+
+func (s *toastSender) Send(notification trackNotification) error {
+	s.registerOnce.Do(func() {
+		s.registerErr = registerToastAppID()
+	})
+	if s.registerErr != nil {
+		return s.registerErr
+	}
+
+	// WinRT objects belong to the thread that initialized the runtime.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	init := winrt.InitializeMt()
+	defer init.Uninitialize()
+
+	document, err := newToastDocument(toastXML(notification))
+	if err != nil {
+		return err
+	}
+	defer document.Release()
+
+	factory, err := toastNotificationFactory()
+	if err != nil {
+		return err
+	}
+	defer factory.Release()
+
+	toast := factory.CreateToastNotification(unsafe.Pointer(document))
+	if toast == nil {
+		return errors.New("create toast notification")
+	}
+	defer toast.Release()
+
+	manager, err := toastNotificationManager()
+	if err != nil {
+		return err
+	}
+	defer manager.Release()
+
+	notifier := manager.CreateToastNotifierWithId(processAppUserModelID)
+	if notifier == nil {
+		return errors.New("create toast notifier")
+	}
+	defer notifier.Release()
+
+	notifier.Show(toast)
+	return nil
+}
+
+func registerToastAppID() error {
+	key, _, err := registry.CreateKey(
+		registry.CURRENT_USER,
+		`Software\Classes\AppUserModelId\`+processAppUserModelID,
+		registry.SET_VALUE,
+	)
+	if err != nil {
+		return fmt.Errorf("register notification app id: %w", err)
+	}
+	defer key.Close()
+
+	if err := key.SetStringValue("DisplayName", toastDisplayName); err != nil {
+		return fmt.Errorf("register notification app name: %w", err)
+	}
+
+	return nil
+}
+
+// toastXML builds a generic toast with the cover as the app logo. The toast
+// is silent, since music is already playing.
+func toastXML(notification trackNotification) string {
+	var builder strings.Builder
+	builder.WriteString(`<toast><visual><binding template="ToastGeneric"><text>`)
+	_ = xml.EscapeText(&builder, []byte(notification.Title))
+	builder.WriteString(`</text><text>`)
+	_ = xml.EscapeText(&builder, []byte(notification.Body))
+	builder.WriteString(`</text>`)
+	if notification.ImagePath != "" {
+		source := (&url.URL{Scheme: "file", Path: "/" + filepath.ToSlash(notification.ImagePath)}).String()
+		builder.WriteString(`<image placement="appLogoOverride" src="`)
+		_ = xml.EscapeText(&builder, []byte(source))
+		builder.WriteString(`"/>`)
+	}
+	builder.WriteString(`</binding></visual><audio silent="true"/></toast>`)
+
+	return builder.String()
+}
+
+// The winrt bindings leave out Windows.Data.Xml.Dom, so the two interfaces
+// a toast document needs are declared here.
+var (
+	// F7F3A506-1E87-42D6-BCFB-B8C809FA5494
+	iidXmlDocument = syscall.GUID{Data1: 0xF7F3A506, Data2: 0x1E87, Data3: 0x42D6,
+		Data4: [8]byte{0xBC, 0xFB, 0xB8, 0xC8, 0x09, 0xFA, 0x54, 0x94}}
+	// 6CD0E74E-EE65-4489-9EBF-CA43E87BA637
+	iidXmlDocumentIO = syscall.GUID{Data1: 0x6CD0E74E, Data2: 0xEE65, Data3: 0x4489,
+		Data4: [8]byte{0x9E, 0xBF, 0xCA, 0x43, 0xE8, 0x7B, 0xA6, 0x37}}
+)
+
+type xmlDocumentIO struct {
+	win32.IInspectable
+}
+
+type xmlDocumentIOVtbl struct {
+	win32.IInspectableVtbl
+	LoadXml             uintptr
+	LoadXmlWithSettings uintptr
+	SaveToFileAsync     uintptr
+}
+
+func (this *xmlDocumentIO) vtbl() *xmlDocumentIOVtbl {
+	return (*xmlDocumentIOVtbl)(unsafe.Pointer(this.IUnknown.LpVtbl))
+}
+
+func (this *xmlDocumentIO) loadXML(content string) win32.HRESULT {
+	hs := winrt.NewHStr(content)
+	defer hs.Dispose()
+
+	hr, _, _ := syscall.SyscallN(this.vtbl().LoadXml, uintptr(unsafe.Pointer(this)), uintptr(hs.Ptr))
+	return win32.HRESULT(hr)
+}
+
+// newToastDocument returns the document as its IXmlDocument interface, which
+// is what the toast notification factory takes.
+func newToastDocument(content string) (*win32.IInspectable, error) {
+	hs := winrt.NewHStr(xmlDocumentClassName)
+	defer hs.Dispose()
+
+	var inspect *win32.IInspectable
+	hr := win32.RoActivateInstance(hs.Ptr, &inspect)
+	if win32.FAILED(hr) || inspect == nil {
+		return nil, fmt.Errorf("create toast document: %s", win32.HRESULT_ToString(hr))
+	}
+	defer inspect.Release()
+
+	var documentIO *xmlDocumentIO
+	hr = inspect.QueryInterface(&iidXmlDocumentIO, unsafe.Pointer(&documentIO))
+	if win32.FAILED(hr) || documentIO == nil {
+		return nil, fmt.Errorf("query toast document loader: %s", win32.HRESULT_ToString(hr))
+	}
+	defer documentIO.Release()
+	if hr := documentIO.loadXML(content); win32.FAILED(hr) {
+		return nil, fmt.Errorf("load toast document: %s", win32.HRESULT_ToString(hr))
+	}
+
+	var document *win32.IInspectable
+	hr = inspect.QueryInterface(&iidXmlDocument, unsafe.Pointer(&document))
+	if win32.FAILED(hr) || document == nil {
+		return nil, fmt.Errorf("query toast document: %s", win32.HRESULT_ToString(hr))
+	}
+
+	return document, nil
+}
+
+func toastNotificationFactory() (*winrt.IToastNotificationFactory, error) {
+	hs := winrt.NewHStr(toastNotificationClassName)
+	defer hs.Dispose()
+
+	var factory *winrt.IToastNotificationFactory
+	hr := win32.RoGetActivationFactory(hs.Ptr, &winrt.IID_IToastNotificationFactory, unsafe.Pointer(&factory))
+	if win32.FAILED(hr) || factory == nil {
+		return nil, fmt.Errorf("toast notification factory: %s", win32.HRESULT_ToString(hr))
+	}
+
+	return factory, nil
+}
+
+func toastNotificationManager() (*winrt.IToastNotificationManagerStatics, error) {
+	hs := winrt.NewHStr(toastManagerClassName)
+	defer hs.Dispose()
+
+	var manager *winrt.IToastNotificationManagerStatics
+	hr := win32.RoGetActivationFactory(hs.Ptr, &winrt.IID_IToastNotificationManagerStatics, unsafe.Pointer(&manager))
+	if win32.FAILED(hr) || manager == nil {
+		return nil, fmt.Errorf("toast notification manager: %s", win32.HRESULT_ToString(hr))
+	}
+
+	return manager, nil
+}
@@ -1,6 +1,6 @@
 package platform
 
-import "ben/internal/player"
+import "github.com/rzxx/ben/internal/player"
 
 type Service interface {
 	Start() error
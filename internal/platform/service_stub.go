@@ -3,14 +3,15 @@
 package platform
 
 import (
-	"ben/internal/player"
-
 	"github.com/wailsapp/wails/v3/pkg/application"
+
+	"github.com/rzxx/ben/internal/eventbus"
+	"github.com/rzxx/ben/internal/player"
 )
 
 type noopService struct{}
 
-func NewService(_ *application.App, _ *player.Service) Service {
+func NewService(_ *application.App, _ *player.Service, _ *eventbus.Bus) Service {
 	return &noopService{}
 }
 
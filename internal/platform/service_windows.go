@@ -3,9 +3,6 @@
 package platform
 
 import (
-	"ben/internal/platform/windows/smtc"
-	"ben/internal/platform/windows/thumbbar"
-	"ben/internal/player"
 	"log"
 	"sync"
 	"unsafe"
@@ -13,6 +10,11 @@ import (
 	"github.com/wailsapp/wails/v3/pkg/application"
 	"github.com/wailsapp/wails/v3/pkg/events"
 	"github.com/zzl/go-win32api/v2/win32"
+
+	"github.com/rzxx/ben/internal/eventbus"
+	"github.com/rzxx/ben/internal/platform/windows/smtc"
+	"github.com/rzxx/ben/internal/platform/windows/thumbbar"
+	"github.com/rzxx/ben/internal/player"
 )
 
 const (
@@ -37,13 +39,16 @@ type windowsService struct {
 	lastState     player.State
 }
 
-func NewService(app *application.App, playerService *player.Service) Service {
-	return &windowsService{
+func NewService(app *application.App, playerService *player.Service, bus *eventbus.Bus) Service {
+	service := &windowsService{
 		app:      app,
 		player:   playerService,
 		smtc:     smtc.NewService(playerService),
 		thumbbar: thumbbar.NewService(playerService),
 	}
+	eventbus.Subscribe(bus, player.EventStateChanged, service.HandlePlayerState)
+
+	return service
 }
 
 func (s *windowsService) Start() error {
@@ -3,7 +3,6 @@
 package smtc
 
 import (
-	"ben/internal/player"
 	"errors"
 	"fmt"
 	"log"
@@ -16,6 +15,8 @@ import (
 	"github.com/zzl/go-com/com"
 	"github.com/zzl/go-win32api/v2/win32"
 	"github.com/zzl/go-winrtapi/winrt"
+
+	"github.com/rzxx/ben/internal/player"
 )
 
 const (
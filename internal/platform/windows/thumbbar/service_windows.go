@@ -3,7 +3,6 @@
 package thumbbar
 
 import (
-	"ben/internal/player"
 	"fmt"
 	"log"
 	"os"
@@ -15,6 +14,8 @@ import (
 
 	"github.com/wailsapp/wails/v3/pkg/application"
 	"github.com/zzl/go-win32api/v2/win32"
+
+	"github.com/rzxx/ben/internal/player"
 )
 
 const (
@@ -0,0 +1,117 @@
+package player
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// AudioAccessibilitySettingKey stores the mono and balance options as JSON.
+const AudioAccessibilitySettingKey = "player.audio_accessibility"
+
+const mpvAudioFilterProperty = "af"
+
+// AudioAccessibility folds stereo down to mono and shifts the output between
+// the left and right channel. Balance runs from -1 (left only) to 1 (right
+// only); the louder side always stays at full level.
+type AudioAccessibility struct {
+	Mono    bool    `json:"mono"`
+	Balance float64 `json:"balance"`
+}
+
+func normalizeAudioAccessibility(config AudioAccessibility) AudioAccessibility {
+	balance := config.Balance
+	if math.IsNaN(balance) {
+		balance = 0
+	}
+	balance = math.Max(-1, math.Min(1, balance))
+
+	return AudioAccessibility{
+		Mono:    config.Mono,
+		Balance: math.Round(balance*100) / 100,
+	}
+}
+
+// audioFilter builds the backend filter chain for the options, or an empty
+// string when output is passed through untouched. Sources are converted to
+// stereo first so mono and multichannel files go through the same pan.
+func audioFilter(config AudioAccessibility) string {
+	if !config.Mono && config.Balance == 0 {
+		return ""
+	}
+
+	leftGain := math.Min(1, 1-config.Balance)
+	rightGain := math.Min(1, 1+config.Balance)
+
+	left := formatPanGain(leftGain) + "*c0"
+	right := formatPanGain(rightGain) + "*c1"
+	if config.Mono {
+		left = formatPanGain(leftGain/2) + "*c0+" + formatPanGain(leftGain/2) + "*c1"
+		right = formatPanGain(rightGain/2) + "*c0+" + formatPanGain(rightGain/2) + "*c1"
+	}
+
+	return fmt.Sprintf("lavfi=[aformat=channel_layouts=stereo,pan=stereo|c0=%s|c1=%s]", left, right)
+}
+
+func formatPanGain(gain float64) string {
+	return strconv.FormatFloat(gain, 'f', 3, 64)
+}
+
+func (s *Service) GetAudioAccessibility() AudioAccessibility {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accessibility
+}
+
+// SetAudioAccessibility saves the options and applies them to the main and
+// preview backends right away.
+func (s *Service) SetAudioAccessibility(config AudioAccessibility) (AudioAccessibility, error) {
+	normalized := normalizeAudioAccessibility(config)
+
+	if s.settings != nil {
+		encoded, err := json.Marshal(normalized)
+		if err != nil {
+			return s.GetAudioAccessibility(), fmt.Errorf("encode audio accessibility: %w", err)
+		}
+		if err := s.settings.Set(context.Background(), AudioAccessibilitySettingKey, string(encoded)); err != nil {
+			return s.GetAudioAccessibility(), err
+		}
+	}
+
+	s.mu.Lock()
+	s.accessibility = normalized
+	backend := s.backend
+	previewBackend := s.previewBackend
+	filter := s.audioFilterChainLocked()
+	s.mu.Unlock()
+
+	if backend != nil {
+		if err := backend.SetAudioFilter(filter); err != nil {
+			return normalized, err
+		}
+	}
+	if previewBackend != nil {
+		_ = previewBackend.SetAudioFilter(filter)
+	}
+
+	return normalized, nil
+}
+
+func (s *Service) loadAudioAccessibility() {
+	if s.settings == nil {
+		return
+	}
+
+	raw, ok, err := s.settings.Get(context.Background(), AudioAccessibilitySettingKey)
+	if err != nil || !ok {
+		return
+	}
+
+	var stored AudioAccessibility
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+		return
+	}
+	s.accessibility = normalizeAudioAccessibility(stored)
+}
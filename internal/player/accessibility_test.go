@@ -0,0 +1,29 @@
+package player
+
+import "testing"
+
+func TestAudioFilterPassesThroughByDefault(t *testing.T) {
+	t.Parallel()
+
+	if got := audioFilter(AudioAccessibility{}); got != "" {
+		t.Fatalf("expected no filter, got %q", got)
+	}
+}
+
+func TestAudioFilterCombinesMonoAndBalance(t *testing.T) {
+	t.Parallel()
+
+	config := normalizeAudioAccessibility(AudioAccessibility{Mono: true, Balance: 0.5})
+	want := "lavfi=[aformat=channel_layouts=stereo,pan=stereo|c0=0.250*c0+0.250*c1|c1=0.500*c0+0.500*c1]"
+	if got := audioFilter(config); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestNormalizeAudioAccessibilityClampsBalance(t *testing.T) {
+	t.Parallel()
+
+	if got := normalizeAudioAccessibility(AudioAccessibility{Balance: -3}); got.Balance != -1 {
+		t.Fatalf("expected balance clamped to -1, got %v", got.Balance)
+	}
+}
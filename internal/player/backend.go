@@ -8,6 +8,7 @@ type playbackBackend interface {
 	Pause() error
 	Seek(positionMS int) error
 	SetVolume(volume int) error
+	SetAudioFilter(filter string) error
 	PositionMS() (int, error)
 	DurationMS() (*int, error)
 	SetOnEOF(callback func())
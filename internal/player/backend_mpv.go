@@ -20,16 +20,20 @@ type mpvBackend struct {
 	client       *mpv.Mpv
 	onEOF        func()
 	onTrackStart func(path string)
+	onLoadError  func(path string)
 	closeOnce    sync.Once
 	closed       chan struct{}
 	stopLoop     chan struct{}
 	closing      bool
 	hasPreload   bool
 	preloadPath  string
+	currentPath  string
+	headers      map[string][]string
+	indexedLoad  bool
 	eventLoopWG  sync.WaitGroup
 }
 
-func newPlaybackBackend() (playbackBackend, error) {
+func newPlaybackBackend(options []BackendOption) (playbackBackend, error) {
 	client := mpv.New()
 	if client == nil {
 		return nil, errors.New("create libmpv instance")
@@ -41,6 +45,12 @@ func newPlaybackBackend() (playbackBackend, error) {
 	setOptionString(client, "keep-open", "no")
 	setOptionString(client, "gapless-audio", "yes")
 	setOptionString(client, "prefetch-playlist", "yes")
+	for _, option := range options {
+		if err := client.SetOptionString(option.Name, option.Value); err != nil {
+			client.TerminateDestroy()
+			return nil, fmt.Errorf("set backend option %q: %w", option.Name, err)
+		}
+	}
 
 	if err := client.Initialize(); err != nil {
 		client.TerminateDestroy()
@@ -48,9 +58,11 @@ func newPlaybackBackend() (playbackBackend, error) {
 	}
 
 	backend := &mpvBackend{
-		client:   client,
-		closed:   make(chan struct{}),
-		stopLoop: make(chan struct{}),
+		client:      client,
+		closed:      make(chan struct{}),
+		stopLoop:    make(chan struct{}),
+		headers:     make(map[string][]string),
+		indexedLoad: mpvLoadfileTakesIndex(client.GetPropertyString("mpv-version")),
 	}
 
 	_ = client.RequestEvent(mpv.EventEnd, true)
@@ -75,12 +87,13 @@ func (b *mpvBackend) Load(path string) error {
 		return fmt.Errorf("set pause before load: %w", err)
 	}
 
-	if err := client.Command([]string{"loadfile", path, "replace"}); err != nil {
+	if err := client.Command(mpvLoadfileArgs(path, "replace", b.headers[path], b.indexedLoad)); err != nil {
 		return fmt.Errorf("load file %q: %w", path, err)
 	}
 
 	b.hasPreload = false
 	b.preloadPath = ""
+	b.currentPath = path
 
 	return nil
 }
@@ -110,7 +123,7 @@ func (b *mpvBackend) PreloadNext(path string) error {
 		b.preloadPath = ""
 	}
 
-	if err := client.Command([]string{"loadfile", trimmedPath, "append"}); err != nil {
+	if err := client.Command(mpvLoadfileArgs(trimmedPath, "append", b.headers[trimmedPath], b.indexedLoad)); err != nil {
 		return fmt.Errorf("append preloaded file %q: %w", trimmedPath, err)
 	}
 
@@ -119,6 +132,24 @@ func (b *mpvBackend) PreloadNext(path string) error {
 	return nil
 }
 
+// SetFileHeaders sets the HTTP headers sent when path is loaded. They are
+// passed as options of that file only, so tracks from different remote
+// roots never share credentials.
+func (b *mpvBackend) SetFileHeaders(path string, headers []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	path = strings.TrimSpace(path)
+	if len(headers) == 0 {
+		delete(b.headers, path)
+		return
+	}
+	if len(b.headers) >= maxResolvedPaths {
+		clear(b.headers)
+	}
+	b.headers[path] = headers
+}
+
 func (b *mpvBackend) ClearPreloadedNext() error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -201,6 +232,106 @@ func (b *mpvBackend) SetVolume(volume int) error {
 	return nil
 }
 
+func (b *mpvBackend) SetAudioFilter(filter string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	client, err := b.requireClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if err := client.SetPropertyString(mpvAudioFilterProperty, filter); err != nil {
+		return fmt.Errorf("set audio filter: %w", err)
+	}
+
+	return nil
+}
+
+// SetOutputSampleRate sets the rate the audio output runs at, 0 for the
+// source rate. With reopen, gapless playback only holds between tracks of
+// the same format so the output can follow each track's rate.
+func (b *mpvBackend) SetOutputSampleRate(rate int, reopen bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	client, err := b.requireClientLocked()
+	if err != nil {
+		return err
+	}
+
+	gapless := "yes"
+	if reopen {
+		gapless = "weak"
+	}
+	if err := client.SetPropertyString("gapless-audio", gapless); err != nil {
+		return fmt.Errorf("set gapless audio: %w", err)
+	}
+	if err := client.SetPropertyString("audio-samplerate", strconv.Itoa(rate)); err != nil {
+		return fmt.Errorf("set output sample rate: %w", err)
+	}
+
+	return nil
+}
+
+// SetReplayGain sets which ReplayGain tags mpv applies while decoding.
+// Clipping protection uses the peak tags.
+func (b *mpvBackend) SetReplayGain(mode string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	client, err := b.requireClientLocked()
+	if err != nil {
+		return err
+	}
+
+	value := mode
+	if mode == ReplayGainOff {
+		value = "no"
+	}
+	if err := client.SetPropertyString("replaygain", value); err != nil {
+		return fmt.Errorf("set replaygain: %w", err)
+	}
+	if err := client.SetPropertyString("replaygain-clip", "yes"); err != nil {
+		return fmt.Errorf("set replaygain clipping: %w", err)
+	}
+
+	return nil
+}
+
+// OutputSampleRate returns the rate the audio output is open at, or false
+// while no output is open.
+func (b *mpvBackend) OutputSampleRate() (int, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	client, err := b.requireClientLocked()
+	if err != nil {
+		return 0, false
+	}
+
+	rate, ok, err := readIntPropertyLocked(client, "audio-out-params/samplerate")
+	if err != nil || !ok || rate <= 0 {
+		return 0, false
+	}
+
+	return int(rate), true
+}
+
+// Buffering reports whether mpv paused playback to wait for its cache to
+// fill.
+func (b *mpvBackend) Buffering() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	client, err := b.requireClientLocked()
+	if err != nil {
+		return false
+	}
+
+	value, err := client.GetProperty("paused-for-cache", mpv.FormatFlag)
+	if err != nil {
+		return false
+	}
+	buffering, ok := value.(bool)
+	return ok && buffering
+}
+
 func (b *mpvBackend) PositionMS() (int, error) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -258,6 +389,17 @@ func (b *mpvBackend) SetOnTrackStart(callback func(path string)) {
 	b.onTrackStart = callback
 }
 
+// SetOnLoadError registers a callback for files mpv could not open or
+// decode, with the path that failed.
+func (b *mpvBackend) SetOnLoadError(callback func(path string)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closing {
+		return
+	}
+	b.onLoadError = callback
+}
+
 func (b *mpvBackend) Close() error {
 	b.closeOnce.Do(func() {
 		b.mu.Lock()
@@ -331,6 +473,10 @@ func (b *mpvBackend) eventLoop() {
 			b.handleFileLoadedEvent()
 		case mpv.EventEnd:
 			end := event.EndFile()
+			if end.Reason == mpv.EndFileError {
+				b.handleLoadErrorEvent()
+				continue
+			}
 			if end.Reason != mpv.EndFileEOF {
 				continue
 			}
@@ -338,6 +484,11 @@ func (b *mpvBackend) eventLoop() {
 			b.mu.Lock()
 			onEOF := b.onEOF
 			closing := b.closing
+			// mpv moves on to the preloaded entry; a decode error there
+			// is reported for its path.
+			if b.hasPreload {
+				b.currentPath = b.preloadPath
+			}
 			b.mu.Unlock()
 			if !closing && onEOF != nil {
 				onEOF()
@@ -355,6 +506,9 @@ func (b *mpvBackend) handleFileLoadedEvent() {
 	}
 
 	path := strings.TrimSpace(client.GetPropertyString("path"))
+	if path != "" {
+		b.currentPath = path
+	}
 	if b.hasPreload && path != "" && pathEqual(path, b.preloadPath) {
 		b.hasPreload = false
 		b.preloadPath = ""
@@ -369,6 +523,30 @@ func (b *mpvBackend) handleFileLoadedEvent() {
 	}
 }
 
+func (b *mpvBackend) handleLoadErrorEvent() {
+	b.mu.Lock()
+	path := b.currentPath
+	callback := b.onLoadError
+	closing := b.closing
+	b.mu.Unlock()
+
+	if !closing && callback != nil && path != "" {
+		callback(path)
+	}
+}
+
+// Version returns the libmpv version, e.g. "mpv 0.38.0".
+func (b *mpvBackend) Version() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	client, err := b.requireClientLocked()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(client.GetPropertyString("mpv-version"))
+}
+
 func (b *mpvBackend) requireClientLocked() (*mpv.Mpv, error) {
 	if b.closing || b.client == nil {
 		return nil, errors.New("libmpv backend is closed")
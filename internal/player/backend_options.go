@@ -0,0 +1,250 @@
+package player
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// BackendOptionsSettingKey stores extra backend options as JSON. They are
+// read once at startup, so changes take effect after a restart.
+const BackendOptionsSettingKey = "player.backend_options"
+
+const (
+	maxBackendOptions     = 32
+	maxBackendValueLength = 1024
+)
+
+var backendOptionNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// allowedBackendOptions lists the tuning options users may pass through.
+// Anything else is rejected: the backend has many options that load scripts,
+// read config files, write logs or change the filter chain, and the player
+// sets several others itself, so a denylist cannot keep up with them.
+var allowedBackendOptions = map[string]struct{}{
+	"ao":                           {},
+	"audio-buffer":                 {},
+	"audio-channels":               {},
+	"audio-device":                 {},
+	"audio-exclusive":              {},
+	"audio-fallback-to-null":       {},
+	"audio-format":                 {},
+	"audio-normalize-downmix":      {},
+	"audio-pitch-correction":       {},
+	"audio-stream-silence":         {},
+	"audio-wait-open":              {},
+	"cache":                        {},
+	"cache-pause":                  {},
+	"cache-pause-initial":          {},
+	"cache-pause-wait":             {},
+	"cache-secs":                   {},
+	"demuxer-cache-wait":           {},
+	"demuxer-lavf-analyzeduration": {},
+	"demuxer-lavf-probesize":       {},
+	"demuxer-max-back-bytes":       {},
+	"demuxer-max-bytes":            {},
+	"demuxer-readahead-secs":       {},
+	"demuxer-seekable-cache":       {},
+	"hr-seek":                      {},
+	"network-timeout":              {},
+	"replaygain-fallback":          {},
+	"replaygain-preamp":            {},
+	"volume-max":                   {},
+}
+
+type BackendOption struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// BackendOptions are passed to the playback backend before it starts.
+// Filters is prepended to the accessibility filter chain; audio filters go
+// there rather than into an "af" option.
+type BackendOptions struct {
+	Options []BackendOption `json:"options"`
+	Filters string          `json:"filters"`
+}
+
+// BackendOptionsStatus compares the saved options with the ones the backend
+// started with. FallbackError is set when the backend rejected the saved
+// options at startup and was started without them.
+type BackendOptionsStatus struct {
+	Saved           BackendOptions `json:"saved"`
+	Applied         BackendOptions `json:"applied"`
+	RestartRequired bool           `json:"restartRequired"`
+	FallbackError   string         `json:"fallbackError,omitempty"`
+}
+
+func normalizeBackendOptions(config BackendOptions) (BackendOptions, error) {
+	if len(config.Options) > maxBackendOptions {
+		return BackendOptions{}, fmt.Errorf("at most %d backend options are allowed", maxBackendOptions)
+	}
+
+	normalized := BackendOptions{Options: make([]BackendOption, 0, len(config.Options))}
+	seen := make(map[string]struct{}, len(config.Options))
+	for _, option := range config.Options {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(option.Name), "--")))
+		if !backendOptionNamePattern.MatchString(name) {
+			return BackendOptions{}, fmt.Errorf("invalid backend option name %q", option.Name)
+		}
+		if _, allowed := allowedBackendOptions[name]; !allowed {
+			return BackendOptions{}, fmt.Errorf("backend option %q is not supported", name)
+		}
+		if _, duplicate := seen[name]; duplicate {
+			return BackendOptions{}, fmt.Errorf("backend option %q is set twice", name)
+		}
+		seen[name] = struct{}{}
+
+		value := strings.TrimSpace(option.Value)
+		if err := validateBackendValue(value); err != nil {
+			return BackendOptions{}, fmt.Errorf("backend option %q: %w", name, err)
+		}
+		normalized.Options = append(normalized.Options, BackendOption{Name: name, Value: value})
+	}
+
+	normalized.Filters = strings.TrimSpace(config.Filters)
+	if err := validateBackendValue(normalized.Filters); err != nil {
+		return BackendOptions{}, fmt.Errorf("backend filters: %w", err)
+	}
+
+	return normalized, nil
+}
+
+func validateBackendValue(value string) error {
+	if len(value) > maxBackendValueLength {
+		return fmt.Errorf("value is longer than %d characters", maxBackendValueLength)
+	}
+	if strings.ContainsAny(value, "\x00\r\n") {
+		return errors.New("value must be a single line")
+	}
+
+	return nil
+}
+
+func (o BackendOptions) empty() bool {
+	return len(o.Options) == 0 && o.Filters == ""
+}
+
+func (o BackendOptions) equal(other BackendOptions) bool {
+	if o.Filters != other.Filters || len(o.Options) != len(other.Options) {
+		return false
+	}
+	for index := range o.Options {
+		if o.Options[index] != other.Options[index] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Service) GetBackendOptions() BackendOptionsStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.backendOptionsStatusLocked()
+}
+
+// SetBackendOptions validates and saves the options for the next start.
+func (s *Service) SetBackendOptions(config BackendOptions) (BackendOptionsStatus, error) {
+	normalized, err := normalizeBackendOptions(config)
+	if err != nil {
+		return s.GetBackendOptions(), err
+	}
+
+	if s.settings != nil {
+		encoded, err := json.Marshal(normalized)
+		if err != nil {
+			return s.GetBackendOptions(), fmt.Errorf("encode backend options: %w", err)
+		}
+		if err := s.settings.Set(context.Background(), BackendOptionsSettingKey, string(encoded)); err != nil {
+			return s.GetBackendOptions(), err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.savedBackendOptions = normalized
+	return s.backendOptionsStatusLocked(), nil
+}
+
+// ResetBackendOptions drops all saved options so the next start uses the
+// player's defaults only.
+func (s *Service) ResetBackendOptions() (BackendOptionsStatus, error) {
+	if s.settings != nil {
+		if err := s.settings.Delete(context.Background(), BackendOptionsSettingKey); err != nil {
+			return s.GetBackendOptions(), err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.savedBackendOptions = BackendOptions{}
+	return s.backendOptionsStatusLocked(), nil
+}
+
+func (s *Service) backendOptionsStatusLocked() BackendOptionsStatus {
+	return BackendOptionsStatus{
+		Saved:           s.savedBackendOptions,
+		Applied:         s.appliedBackendOptions,
+		RestartRequired: !s.savedBackendOptions.equal(s.appliedBackendOptions),
+		FallbackError:   s.backendOptionsErr,
+	}
+}
+
+func (s *Service) loadBackendOptions() {
+	if s.settings == nil {
+		return
+	}
+
+	raw, ok, err := s.settings.Get(context.Background(), BackendOptionsSettingKey)
+	if err != nil || !ok {
+		return
+	}
+
+	var stored BackendOptions
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+		return
+	}
+	if normalized, err := normalizeBackendOptions(stored); err == nil {
+		s.savedBackendOptions = normalized
+	}
+}
+
+// startBackend creates the backend with the saved options. If the backend
+// rejects them it is started again without them, so a bad option can never
+// leave the player without audio.
+func (s *Service) startBackend() (playbackBackend, error) {
+	options := s.savedBackendOptions
+	backend, err := newPlaybackBackend(options.Options)
+	if err != nil && !options.empty() {
+		s.backendOptionsErr = err.Error()
+		options = BackendOptions{}
+		backend, err = newPlaybackBackend(nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.appliedBackendOptions = options
+	return backend, nil
+}
+
+// audioFilterChainLocked joins the user's custom filters with the
+// equalizer and accessibility filters.
+func (s *Service) audioFilterChainLocked() string {
+	filters := make([]string, 0, 3)
+	if s.appliedBackendOptions.Filters != "" {
+		filters = append(filters, s.appliedBackendOptions.Filters)
+	}
+	if equalizer := equalizerFilter(s.equalizer); equalizer != "" {
+		filters = append(filters, equalizer)
+	}
+	if accessibility := audioFilter(s.accessibility); accessibility != "" {
+		filters = append(filters, accessibility)
+	}
+
+	return strings.Join(filters, ",")
+}
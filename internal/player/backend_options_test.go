@@ -0,0 +1,63 @@
+package player
+
+import "testing"
+
+func TestNormalizeBackendOptionsCleansNames(t *testing.T) {
+	t.Parallel()
+
+	normalized, err := normalizeBackendOptions(BackendOptions{
+		Options: []BackendOption{{Name: " --Audio-Exclusive ", Value: " yes "}},
+		Filters: " lavfi=[loudnorm] ",
+	})
+	if err != nil {
+		t.Fatalf("normalize backend options: %v", err)
+	}
+	if len(normalized.Options) != 1 || normalized.Options[0] != (BackendOption{Name: "audio-exclusive", Value: "yes"}) {
+		t.Fatalf("unexpected options: %+v", normalized.Options)
+	}
+	if normalized.Filters != "lavfi=[loudnorm]" {
+		t.Fatalf("unexpected filters: %q", normalized.Filters)
+	}
+}
+
+func TestNormalizeBackendOptionsRejectsReservedAndInvalid(t *testing.T) {
+	t.Parallel()
+
+	cases := []BackendOptions{
+		{Options: []BackendOption{{Name: "scripts", Value: "evil.lua"}}},
+		{Options: []BackendOption{{Name: "audio device", Value: "x"}}},
+		{Options: []BackendOption{{Name: "ao", Value: "alsa"}, {Name: "ao", Value: "pulse"}}},
+		{Filters: "lavfi=[a]\nscript=x"},
+	}
+	for _, config := range cases {
+		if _, err := normalizeBackendOptions(config); err == nil {
+			t.Fatalf("expected %+v to be rejected", config)
+		}
+	}
+}
+
+func TestNormalizeBackendOptionsOnlyAllowsTuningOptions(t *testing.T) {
+	t.Parallel()
+
+	blocked := []string{
+		"scripts-append", "script-opts", "ytdl-path", "af-add", "af-append",
+		"log-file", "input-conf", "include", "config-dir", "af", "replaygain",
+		"volume", "gapless-audio", "load-scripts", "input-ipc-server",
+	}
+	for _, name := range blocked {
+		config := BackendOptions{Options: []BackendOption{{Name: name, Value: "x"}}}
+		if _, err := normalizeBackendOptions(config); err == nil {
+			t.Fatalf("expected backend option %q to be rejected", name)
+		}
+	}
+
+	allowed := []BackendOption{
+		{Name: "audio-device", Value: "alsa/default"},
+		{Name: "cache-secs", Value: "30"},
+		{Name: "demuxer-max-bytes", Value: "64MiB"},
+		{Name: "replaygain-preamp", Value: "-3"},
+	}
+	if _, err := normalizeBackendOptions(BackendOptions{Options: allowed}); err != nil {
+		t.Fatalf("expected tuning options to be accepted: %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package player
+
+// BackendStatus reports whether audio output is available. Error holds the
+// reason the backend could not be started.
+type BackendStatus struct {
+	Available bool   `json:"available"`
+	Version   string `json:"version,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// versionedBackend is implemented by backends that can report the version
+// of the library behind them.
+type versionedBackend interface {
+	Version() string
+}
+
+func (s *Service) GetBackendStatus() BackendStatus {
+	s.mu.Lock()
+	backend := s.backend
+	backendErr := s.backendErr
+	s.mu.Unlock()
+
+	if backend == nil {
+		if backendErr == "" {
+			backendErr = "playback backend is unavailable"
+		}
+		return BackendStatus{Error: backendErr}
+	}
+
+	status := BackendStatus{Available: true}
+	if versioned, ok := backend.(versionedBackend); ok {
+		status.Version = versioned.Version()
+	}
+
+	return status
+}
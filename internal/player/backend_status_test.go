@@ -0,0 +1,18 @@
+package player
+
+import "testing"
+
+func TestGetBackendStatusReportsStartError(t *testing.T) {
+	t.Parallel()
+
+	service := &Service{backendErr: "initialize libmpv: failed"}
+	status := service.GetBackendStatus()
+	if status.Available || status.Error != "initialize libmpv: failed" {
+		t.Fatalf("unexpected backend status %+v", status)
+	}
+
+	status = (&Service{}).GetBackendStatus()
+	if status.Available || status.Error == "" {
+		t.Fatalf("expected missing backend to report an error, got %+v", status)
+	}
+}
@@ -4,6 +4,6 @@ package player
 
 import "errors"
 
-func newPlaybackBackend() (playbackBackend, error) {
+func newPlaybackBackend([]BackendOption) (playbackBackend, error) {
 	return nil, errors.New("libmpv backend is not enabled; build with -tags libmpv")
 }
@@ -0,0 +1,325 @@
+package player
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// PlaybackDiagnosticsSettingKey turns the technical playback log on. It is
+// off by default; the log is meant for investigating stutter reports.
+const PlaybackDiagnosticsSettingKey = "player.playback_diagnostics"
+
+const (
+	DiagnosticLoad     = "load"
+	DiagnosticSeek     = "seek"
+	DiagnosticUnderrun = "underrun"
+)
+
+// maxPlaybackDiagnostics caps the log; the oldest rows are dropped first.
+const maxPlaybackDiagnostics = 5000
+
+const defaultDiagnosticEventsLimit = 200
+
+// bufferingBackend is implemented by backends that report when playback is
+// paused waiting for data, e.g. from a slow network drive.
+type bufferingBackend interface {
+	Buffering() bool
+}
+
+type PlaybackDiagnosticEvent struct {
+	ID         int64  `json:"id"`
+	TrackID    int64  `json:"trackId"`
+	Event      string `json:"event"`
+	DurationMS int    `json:"durationMs"`
+	Path       string `json:"path"`
+	Detail     string `json:"detail,omitempty"`
+	RecordedAt string `json:"recordedAt"`
+}
+
+// TrackPlaybackDiagnostics sums up the log of one track. Failures counts
+// loads and seeks that did not apply.
+type TrackPlaybackDiagnostics struct {
+	TrackID        int64  `json:"trackId"`
+	Title          string `json:"title"`
+	Artist         string `json:"artist"`
+	Path           string `json:"path"`
+	Loads          int    `json:"loads"`
+	AverageLoadMS  int    `json:"averageLoadMs"`
+	MaxLoadMS      int    `json:"maxLoadMs"`
+	Seeks          int    `json:"seeks"`
+	AverageSeekMS  int    `json:"averageSeekMs"`
+	MaxSeekMS      int    `json:"maxSeekMs"`
+	Underruns      int    `json:"underruns"`
+	UnderrunMS     int    `json:"underrunMs"`
+	Failures       int    `json:"failures"`
+	LastRecordedAt string `json:"lastRecordedAt"`
+}
+
+// PlaybackDiagnostics lists the logged tracks, those with the most
+// underruns first, and the latest events.
+type PlaybackDiagnostics struct {
+	Enabled bool                       `json:"enabled"`
+	Tracks  []TrackPlaybackDiagnostics `json:"tracks"`
+	Events  []PlaybackDiagnosticEvent  `json:"events"`
+}
+
+// pendingLoad is a load the backend accepted and has not started playing.
+type pendingLoad struct {
+	trackID   int64
+	path      string
+	startedAt time.Time
+}
+
+// stallTracker follows the buffering flag of the backend across ticks.
+type stallTracker struct {
+	trackID   int64
+	startedAt time.Time
+}
+
+// observe records the flag seen at now and returns a finished stall: the
+// track it happened on and how long it lasted. A stall also ends when the
+// track changes while buffering.
+func (t *stallTracker) observe(trackID int64, buffering bool, now time.Time) (int64, time.Duration, bool) {
+	if !t.startedAt.IsZero() && (!buffering || t.trackID != trackID) {
+		stalledTrackID, duration := t.trackID, now.Sub(t.startedAt)
+		t.startedAt = time.Time{}
+		if buffering {
+			t.trackID, t.startedAt = trackID, now
+		}
+		return stalledTrackID, duration, true
+	}
+	if buffering && t.startedAt.IsZero() {
+		t.trackID, t.startedAt = trackID, now
+	}
+
+	return 0, 0, false
+}
+
+func (s *Service) loadPlaybackDiagnostics() {
+	if s.settings == nil {
+		return
+	}
+
+	enabled, err := strconv.ParseBool(s.settings.GetString(context.Background(), PlaybackDiagnosticsSettingKey, "false"))
+	s.diagnosticsEnabled = err == nil && enabled
+}
+
+func (s *Service) PlaybackDiagnosticsEnabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.diagnosticsEnabled
+}
+
+func (s *Service) SetPlaybackDiagnosticsEnabled(enabled bool) (bool, error) {
+	if s.settings == nil {
+		return false, errors.New("settings are unavailable")
+	}
+	if err := s.settings.Set(context.Background(), PlaybackDiagnosticsSettingKey, strconv.FormatBool(enabled)); err != nil {
+		return s.PlaybackDiagnosticsEnabled(), err
+	}
+
+	s.mu.Lock()
+	s.diagnosticsEnabled = enabled
+	s.pendingLoad = nil
+	s.stalls = stallTracker{}
+	s.mu.Unlock()
+
+	return enabled, nil
+}
+
+// beginLoadDiagnostic starts timing a load; it ends when the backend starts
+// playing path.
+func (s *Service) beginLoadDiagnostic(trackID int64, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.diagnosticsEnabled {
+		return
+	}
+	s.pendingLoad = &pendingLoad{trackID: trackID, path: path, startedAt: time.Now()}
+}
+
+// finishLoadDiagnostic logs the pending load when path is the file it
+// loaded. A failed load is logged with its error.
+func (s *Service) finishLoadDiagnostic(path string, loadErr error) {
+	s.mu.Lock()
+	pending := s.pendingLoad
+	if pending == nil || !sameTrackPath(pending.path, path) {
+		s.mu.Unlock()
+		return
+	}
+	s.pendingLoad = nil
+	s.mu.Unlock()
+
+	detail := ""
+	if loadErr != nil {
+		detail = loadErr.Error()
+	}
+	s.recordDiagnostic(pending.trackID, DiagnosticLoad, time.Since(pending.startedAt), pending.path, detail)
+}
+
+// observeBuffering logs an underrun once the backend stops buffering.
+func (s *Service) observeBuffering(backend playbackBackend, trackID int64) {
+	reporter, ok := backend.(bufferingBackend)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	if !s.diagnosticsEnabled {
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+
+	buffering := reporter.Buffering()
+
+	s.mu.Lock()
+	stalledTrackID, duration, ended := s.stalls.observe(trackID, buffering, time.Now())
+	s.mu.Unlock()
+	if ended {
+		s.recordDiagnostic(stalledTrackID, DiagnosticUnderrun, duration, "", "")
+	}
+}
+
+func (s *Service) recordDiagnostic(trackID int64, event string, duration time.Duration, path string, detail string) {
+	s.mu.Lock()
+	enabled := s.diagnosticsEnabled
+	s.mu.Unlock()
+	if !enabled || s.db == nil || trackID <= 0 {
+		return
+	}
+
+	ctx := context.Background()
+	if _, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO playback_diagnostics(track_id, event_type, duration_ms, path, detail) VALUES (?, ?, ?, ?, ?)`,
+		trackID,
+		event,
+		duration.Milliseconds(),
+		path,
+		detail,
+	); err != nil {
+		return
+	}
+
+	_, _ = s.db.ExecContext(
+		ctx,
+		`DELETE FROM playback_diagnostics WHERE id <= (SELECT MAX(id) FROM playback_diagnostics) - ?`,
+		maxPlaybackDiagnostics,
+	)
+}
+
+// GetPlaybackDiagnostics returns the log, of one track when trackID is set,
+// with at most limit of the latest events.
+func (s *Service) GetPlaybackDiagnostics(trackID int64, limit int) (PlaybackDiagnostics, error) {
+	diagnostics := PlaybackDiagnostics{
+		Enabled: s.PlaybackDiagnosticsEnabled(),
+		Tracks:  []TrackPlaybackDiagnostics{},
+		Events:  []PlaybackDiagnosticEvent{},
+	}
+	if s.db == nil {
+		return diagnostics, nil
+	}
+	if limit <= 0 {
+		limit = defaultDiagnosticEventsLimit
+	}
+
+	ctx := context.Background()
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			d.track_id,
+			COALESCE(NULLIF(TRIM(t.title), ''), 'Unknown Title'),
+			COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist'),
+			COALESCE(f.path, ''),
+			SUM(CASE WHEN d.event_type = 'load' THEN 1 ELSE 0 END),
+			COALESCE(AVG(CASE WHEN d.event_type = 'load' AND d.detail = '' THEN d.duration_ms END), 0),
+			COALESCE(MAX(CASE WHEN d.event_type = 'load' THEN d.duration_ms END), 0) AS max_load_ms,
+			SUM(CASE WHEN d.event_type = 'seek' THEN 1 ELSE 0 END),
+			COALESCE(AVG(CASE WHEN d.event_type = 'seek' AND d.detail = '' THEN d.duration_ms END), 0),
+			COALESCE(MAX(CASE WHEN d.event_type = 'seek' THEN d.duration_ms END), 0),
+			SUM(CASE WHEN d.event_type = 'underrun' THEN 1 ELSE 0 END) AS underrun_count,
+			COALESCE(SUM(CASE WHEN d.event_type = 'underrun' THEN d.duration_ms END), 0) AS underrun_ms,
+			SUM(CASE WHEN d.detail <> '' THEN 1 ELSE 0 END),
+			MAX(d.recorded_at)
+		FROM playback_diagnostics d
+		JOIN tracks t ON t.id = d.track_id
+		LEFT JOIN files f ON f.id = t.file_id
+		WHERE (? = 0 OR d.track_id = ?)
+		GROUP BY d.track_id
+		ORDER BY underrun_count DESC, underrun_ms DESC, max_load_ms DESC, d.track_id
+	`, trackID, trackID)
+	if err != nil {
+		return diagnostics, fmt.Errorf("summarize playback diagnostics: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var track TrackPlaybackDiagnostics
+		var averageLoadMS float64
+		var averageSeekMS float64
+		if scanErr := rows.Scan(
+			&track.TrackID,
+			&track.Title,
+			&track.Artist,
+			&track.Path,
+			&track.Loads,
+			&averageLoadMS,
+			&track.MaxLoadMS,
+			&track.Seeks,
+			&averageSeekMS,
+			&track.MaxSeekMS,
+			&track.Underruns,
+			&track.UnderrunMS,
+			&track.Failures,
+			&track.LastRecordedAt,
+		); scanErr != nil {
+			return diagnostics, fmt.Errorf("scan playback diagnostics of a track: %w", scanErr)
+		}
+		track.AverageLoadMS = int(averageLoadMS)
+		track.AverageSeekMS = int(averageSeekMS)
+		diagnostics.Tracks = append(diagnostics.Tracks, track)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return diagnostics, fmt.Errorf("iterate playback diagnostics of tracks: %w", rowsErr)
+	}
+	rows.Close()
+
+	eventRows, err := s.db.QueryContext(ctx, `
+		SELECT id, track_id, event_type, duration_ms, path, detail, recorded_at
+		FROM playback_diagnostics
+		WHERE (? = 0 OR track_id = ?)
+		ORDER BY id DESC
+		LIMIT ?
+	`, trackID, trackID, limit)
+	if err != nil {
+		return diagnostics, fmt.Errorf("list playback diagnostics: %w", err)
+	}
+	defer eventRows.Close()
+
+	for eventRows.Next() {
+		var event PlaybackDiagnosticEvent
+		if scanErr := eventRows.Scan(&event.ID, &event.TrackID, &event.Event, &event.DurationMS, &event.Path, &event.Detail, &event.RecordedAt); scanErr != nil {
+			return diagnostics, fmt.Errorf("scan playback diagnostic: %w", scanErr)
+		}
+		diagnostics.Events = append(diagnostics.Events, event)
+	}
+	if rowsErr := eventRows.Err(); rowsErr != nil {
+		return diagnostics, fmt.Errorf("iterate playback diagnostics: %w", rowsErr)
+	}
+
+	return diagnostics, nil
+}
+
+func (s *Service) ClearPlaybackDiagnostics() error {
+	if s.db == nil {
+		return nil
+	}
+	if _, err := s.db.ExecContext(context.Background(), "DELETE FROM playback_diagnostics"); err != nil {
+		return fmt.Errorf("clear playback diagnostics: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package player
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStallTrackerReportsFinishedStalls(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var tracker stallTracker
+
+	if _, _, ended := tracker.observe(1, false, start); ended {
+		t.Fatal("expected no stall while playing normally")
+	}
+	if _, _, ended := tracker.observe(1, true, start); ended {
+		t.Fatal("expected a stall to be open while buffering")
+	}
+	if _, _, ended := tracker.observe(1, true, start.Add(500*time.Millisecond)); ended {
+		t.Fatal("expected a stall to stay open while still buffering")
+	}
+
+	trackID, duration, ended := tracker.observe(1, false, start.Add(1500*time.Millisecond))
+	if !ended || trackID != 1 || duration != 1500*time.Millisecond {
+		t.Fatalf("expected a 1.5s stall on track 1, got %v %d %v", ended, trackID, duration)
+	}
+	if _, _, ended := tracker.observe(1, false, start.Add(2*time.Second)); ended {
+		t.Fatal("expected a stall to be reported once")
+	}
+}
+
+func TestStallTrackerEndsStallOnTrackChange(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var tracker stallTracker
+	tracker.observe(1, true, start)
+
+	trackID, duration, ended := tracker.observe(2, true, start.Add(time.Second))
+	if !ended || trackID != 1 || duration != time.Second {
+		t.Fatalf("expected the stall of track 1 to end, got %v %d %v", ended, trackID, duration)
+	}
+
+	trackID, _, ended = tracker.observe(2, false, start.Add(2*time.Second))
+	if !ended || trackID != 2 {
+		t.Fatalf("expected the next track's stall to be reported, got %v %d", ended, trackID)
+	}
+}
@@ -0,0 +1,80 @@
+package player
+
+import (
+	"context"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+// durationProbeDelayMS gives mpv time to settle its duration estimate, which
+// for VBR MP3s without a complete Xing header improves as the stream is read.
+const durationProbeDelayMS = 5000
+
+const durationCorrectionMinMS = 2000
+
+// durationNeedsCorrection reports whether the tagged duration is far enough
+// from the duration the backend measured to be worth rewriting. Small drifts
+// from encoder padding are ignored.
+func durationNeedsCorrection(taggedMS *int, measuredMS int) bool {
+	if measuredMS <= 0 {
+		return false
+	}
+	if taggedMS == nil || *taggedMS <= 0 {
+		return true
+	}
+
+	difference := *taggedMS - measuredMS
+	if difference < 0 {
+		difference = -difference
+	}
+
+	return difference > max(durationCorrectionMinMS, measuredMS/100)
+}
+
+// verifyTrackDuration compares the tagged duration of the playing track with
+// the one reported by the backend once per track and corrects duration_ms
+// when they disagree. Tracks verified in an earlier session are skipped; a
+// rescan of a changed file clears the verification. Trimmed tracks report
+// the trimmed duration, so they are not verified.
+func (s *Service) verifyTrackDuration(track *library.TrackSummary) {
+	if s.db == nil || track == nil || s.trackTrim(track.ID) != (TrackTrim{}) {
+		return
+	}
+
+	s.mu.Lock()
+	if s.durationChecked == nil {
+		s.durationChecked = make(map[int64]struct{})
+	}
+	_, checked := s.durationChecked[track.ID]
+	current := s.hasCurrent && s.currentTrackID == track.ID
+	positionMS := s.positionMS
+	measured := s.durationMS
+	if !checked && current && positionMS >= durationProbeDelayMS && measured != nil {
+		s.durationChecked[track.ID] = struct{}{}
+	}
+	s.mu.Unlock()
+
+	if checked || !current || positionMS < durationProbeDelayMS || measured == nil {
+		return
+	}
+
+	correct := durationNeedsCorrection(track.DurationMS, *measured)
+	_, _ = s.db.ExecContext(
+		context.Background(),
+		`UPDATE tracks
+		 SET duration_ms = CASE WHEN ? = 1 THEN ? ELSE duration_ms END,
+		 	duration_verified_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+		 WHERE id = ? AND duration_verified_at IS NULL`,
+		boolToInt(correct),
+		*measured,
+		track.ID,
+	)
+}
+
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,29 @@
+package player
+
+import "testing"
+
+func TestDurationNeedsCorrection(t *testing.T) {
+	t.Parallel()
+
+	intPtr := func(value int) *int { return &value }
+
+	cases := []struct {
+		name     string
+		tagged   *int
+		measured int
+		want     bool
+	}{
+		{name: "missing tag", tagged: nil, measured: 180000, want: true},
+		{name: "zero tag", tagged: intPtr(0), measured: 180000, want: true},
+		{name: "padding drift", tagged: intPtr(181000), measured: 180000, want: false},
+		{name: "vbr estimate off", tagged: intPtr(240000), measured: 180000, want: true},
+		{name: "long mix within one percent", tagged: intPtr(3604000), measured: 3600000, want: false},
+		{name: "no measurement", tagged: intPtr(180000), measured: 0, want: false},
+	}
+
+	for _, tc := range cases {
+		if got := durationNeedsCorrection(tc.tagged, tc.measured); got != tc.want {
+			t.Errorf("%s: durationNeedsCorrection() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
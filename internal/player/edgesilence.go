@@ -0,0 +1,189 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// edgeSilenceNoiseDB is the level below which audio counts as silence.
+	edgeSilenceNoiseDB = -60
+	// edgeSilenceEdgeToleranceMS is how close to a track edge a silence
+	// must start or end to count as edge silence rather than a quiet
+	// passage.
+	edgeSilenceEdgeToleranceMS = 250
+	// minTrimmedSilenceMS is the shortest edge silence crossfade skips.
+	// Shorter silences keep the gapless switch to the preloaded file.
+	minTrimmedSilenceMS = 300
+	edgeSilenceTimeout  = 2 * time.Minute
+)
+
+// EdgeSilence is how much silence a track starts and ends with.
+// DurationMS is the decoded length of the file.
+type EdgeSilence struct {
+	LeadMS     int
+	TailMS     int
+	DurationMS int
+}
+
+// EdgeSilenceResolver returns the analyzed edge silence of a track, or a
+// zero value while it is unknown.
+type EdgeSilenceResolver func(trackID int64) EdgeSilence
+
+// SetEdgeSilenceResolver installs the lookup used to skip edge silence
+// while crossfade is on.
+func (s *Service) SetEdgeSilenceResolver(resolver EdgeSilenceResolver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.edgeSilence = resolver
+	s.trimCache = make(map[int64]TrackTrim)
+}
+
+// AnalyzeEdgeSilence decodes path with ffmpeg and measures the silence at
+// its start and end. durationMS is the tagged length, used when ffmpeg does
+// not report the end of a silence that runs to the end of the file.
+func AnalyzeEdgeSilence(ctx context.Context, path string, durationMS int) (EdgeSilence, error) {
+	ffmpegPath, ok := findFFmpeg()
+	if !ok {
+		return EdgeSilence{}, errTranscodeUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, edgeSilenceTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, ffmpegPath, ffmpegSilenceDetectArgs(path)...).CombinedOutput()
+	if err != nil {
+		return EdgeSilence{}, fmt.Errorf("detect silence in %q: %w: %s", path, err, lastLine(string(output)))
+	}
+
+	return parseEdgeSilence(string(output), durationMS), nil
+}
+
+// ffmpegSilenceDetectArgs decodes the first audio stream of source and logs
+// every silence longer than 50ms.
+func ffmpegSilenceDetectArgs(source string) []string {
+	return []string{
+		"-nostdin", "-hide_banner", "-nostats", "-loglevel", "info",
+		"-i", source,
+		"-map", "0:a:0", "-vn",
+		"-af", fmt.Sprintf("silencedetect=noise=%ddB:d=0.05", edgeSilenceNoiseDB),
+		"-f", "null", "-",
+	}
+}
+
+type silenceSpan struct {
+	startMS int
+	endMS   int // -1 while the silence runs to the end of the file
+}
+
+// parseEdgeSilence reads the silencedetect log. A silence counts as lead
+// silence when it starts at the beginning of the file and as tail silence
+// when it reaches the end, whether ffmpeg closes it at the end of the stream
+// or leaves it open.
+func parseEdgeSilence(output string, durationMS int) EdgeSilence {
+	spans := make([]silenceSpan, 0)
+	endMS := 0
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.Contains(line, "silencedetect") {
+			continue
+		}
+		if value, ok := silenceDetectValue(line, "silence_start:"); ok {
+			spans = append(spans, silenceSpan{startMS: max(value, 0), endMS: -1})
+			continue
+		}
+		if value, ok := silenceDetectValue(line, "silence_end:"); ok && len(spans) > 0 {
+			spans[len(spans)-1].endMS = value
+			endMS = max(endMS, value)
+		}
+	}
+
+	silence := EdgeSilence{DurationMS: max(durationMS, endMS)}
+	if len(spans) == 0 {
+		return silence
+	}
+
+	first := spans[0]
+	if first.startMS <= edgeSilenceEdgeToleranceMS {
+		if first.endMS < 0 {
+			// The whole file is silent.
+			silence.LeadMS = silence.DurationMS
+			silence.TailMS = silence.DurationMS
+			return silence
+		}
+		silence.LeadMS = first.endMS
+	}
+
+	last := spans[len(spans)-1]
+	if silence.DurationMS > 0 && (last.endMS < 0 || last.endMS >= silence.DurationMS-edgeSilenceEdgeToleranceMS) {
+		silence.TailMS = max(silence.DurationMS-last.startMS, 0)
+	}
+
+	return silence
+}
+
+func silenceDetectValue(line string, key string) (int, bool) {
+	index := strings.Index(line, key)
+	if index < 0 {
+		return 0, false
+	}
+
+	fields := strings.Fields(line[index+len(key):])
+	if len(fields) == 0 {
+		return 0, false
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return int(math.Round(seconds * 1000)), true
+}
+
+// edgeSilenceTrim extends trim past the silence at the edges of a track
+// while crossfade is on, so the fade runs over audio instead of over
+// silence and the next track starts at its first sound. Continuous mixes
+// keep their edges.
+func (s *Service) edgeSilenceTrim(trackID int64, trim TrackTrim) TrackTrim {
+	s.mu.Lock()
+	crossfadeMS := s.crossfadeMS
+	resolver := s.edgeSilence
+	s.mu.Unlock()
+
+	if crossfadeMS == 0 || resolver == nil || s.isContinuousMixTrack(trackID) {
+		return trim
+	}
+
+	return applyEdgeSilence(trim, resolver(trackID))
+}
+
+// applyEdgeSilence moves the trim points inward to where the audio starts
+// and ends. A user trim further in wins, and silences too short to matter
+// are left alone.
+func applyEdgeSilence(trim TrackTrim, silence EdgeSilence) TrackTrim {
+	if silence.DurationMS > 0 && silence.LeadMS+silence.TailMS >= silence.DurationMS {
+		// The file is silent throughout.
+		return trim
+	}
+
+	adjusted := trim
+	if silence.LeadMS >= minTrimmedSilenceMS && silence.LeadMS > adjusted.StartMS {
+		adjusted.StartMS = silence.LeadMS
+	}
+	if silence.TailMS >= minTrimmedSilenceMS && silence.DurationMS > 0 {
+		endMS := silence.DurationMS - silence.TailMS
+		if adjusted.EndMS == 0 || endMS < adjusted.EndMS {
+			adjusted.EndMS = endMS
+		}
+	}
+
+	if adjusted.EndMS > 0 && adjusted.EndMS <= adjusted.StartMS {
+		return trim
+	}
+
+	return adjusted
+}
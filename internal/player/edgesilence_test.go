@@ -0,0 +1,120 @@
+package player
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseEdgeSilenceFindsLeadAndTailSilence(t *testing.T) {
+	t.Parallel()
+
+	output := `Input #0, flac, from 'a.flac':
+  Duration: 00:03:00.00, start: 0.000000, bitrate: 900 kb/s
+[silencedetect @ 0x5581] silence_start: 0
+[silencedetect @ 0x5581] silence_end: 1.5 | silence_duration: 1.5
+[silencedetect @ 0x5581] silence_start: 62.25
+[silencedetect @ 0x5581] silence_end: 62.5 | silence_duration: 0.25
+[silencedetect @ 0x5581] silence_start: 176.8
+[silencedetect @ 0x5581] silence_end: 180 | silence_duration: 3.2
+size=N/A time=00:03:00.00 bitrate=N/A speed= 412x`
+
+	got := parseEdgeSilence(output, 179_900)
+	want := EdgeSilence{LeadMS: 1500, TailMS: 3200, DurationMS: 180_000}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestParseEdgeSilenceIgnoresQuietPassagesAwayFromTheEdges(t *testing.T) {
+	t.Parallel()
+
+	output := `[silencedetect @ 0x1] silence_start: 30.0
+[silencedetect @ 0x1] silence_end: 31.0 | silence_duration: 1.0`
+
+	if got := parseEdgeSilence(output, 200_000); got != (EdgeSilence{DurationMS: 200_000}) {
+		t.Fatalf("expected no edge silence, got %+v", got)
+	}
+	if got := parseEdgeSilence("", 200_000); got != (EdgeSilence{DurationMS: 200_000}) {
+		t.Fatalf("expected no edge silence without output, got %+v", got)
+	}
+}
+
+func TestParseEdgeSilenceTakesAnOpenSilenceToTheEnd(t *testing.T) {
+	t.Parallel()
+
+	// Older ffmpeg builds do not close a silence at the end of the stream.
+	output := `[silencedetect @ 0x1] silence_start: 235.5`
+	if got := parseEdgeSilence(output, 240_000); got != (EdgeSilence{TailMS: 4500, DurationMS: 240_000}) {
+		t.Fatalf("unexpected edge silence %+v", got)
+	}
+
+	silent := `[silencedetect @ 0x1] silence_start: 0`
+	if got := parseEdgeSilence(silent, 5_000); got != (EdgeSilence{LeadMS: 5_000, TailMS: 5_000, DurationMS: 5_000}) {
+		t.Fatalf("unexpected edge silence for a silent file %+v", got)
+	}
+}
+
+func TestApplyEdgeSilenceMovesTrimsToTheAudio(t *testing.T) {
+	t.Parallel()
+
+	silence := EdgeSilence{LeadMS: 1500, TailMS: 3200, DurationMS: 180_000}
+	cases := []struct {
+		name string
+		trim TrackTrim
+		want TrackTrim
+	}{
+		{name: "untrimmed", trim: TrackTrim{}, want: TrackTrim{StartMS: 1500, EndMS: 176_800}},
+		{name: "user trim further in wins", trim: TrackTrim{StartMS: 20_000, EndMS: 90_000}, want: TrackTrim{StartMS: 20_000, EndMS: 90_000}},
+		{name: "user trim inside the silence", trim: TrackTrim{StartMS: 500, EndMS: 179_000}, want: TrackTrim{StartMS: 1500, EndMS: 176_800}},
+	}
+	for _, tc := range cases {
+		if got := applyEdgeSilence(tc.trim, silence); got != tc.want {
+			t.Fatalf("%s: got %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+
+	short := EdgeSilence{LeadMS: 120, TailMS: 200, DurationMS: 180_000}
+	if got := applyEdgeSilence(TrackTrim{}, short); got != (TrackTrim{}) {
+		t.Fatalf("expected short silences to keep the gapless switch, got %+v", got)
+	}
+
+	silent := EdgeSilence{LeadMS: 5_000, TailMS: 5_000, DurationMS: 5_000}
+	if got := applyEdgeSilence(TrackTrim{}, silent); got != (TrackTrim{}) {
+		t.Fatalf("expected a silent file to play untrimmed, got %+v", got)
+	}
+}
+
+func TestEdgeSilenceTrimAppliesOnlyWithCrossfadeOutsideMixes(t *testing.T) {
+	t.Parallel()
+
+	service := &Service{mixCache: make(map[int64]bool), trimCache: make(map[int64]TrackTrim)}
+	service.SetEdgeSilenceResolver(func(int64) EdgeSilence {
+		return EdgeSilence{LeadMS: 2000, TailMS: 2000, DurationMS: 100_000}
+	})
+	service.SetContinuousMixResolver(func(trackID int64) bool { return trackID == 2 })
+
+	if got := service.trackTrim(1); got != (TrackTrim{}) {
+		t.Fatalf("expected no trim without crossfade, got %+v", got)
+	}
+
+	service.mu.Lock()
+	service.crossfadeMS = 4000
+	service.trimCache = make(map[int64]TrackTrim)
+	service.mu.Unlock()
+
+	if got := service.trackTrim(1); got != (TrackTrim{StartMS: 2000, EndMS: 98_000}) {
+		t.Fatalf("expected edge silence trimmed with crossfade, got %+v", got)
+	}
+	if got := service.trackTrim(2); got != (TrackTrim{}) {
+		t.Fatalf("expected a continuous mix to keep its edges, got %+v", got)
+	}
+}
+
+func TestFFmpegSilenceDetectArgsDecodeTheFirstAudioStream(t *testing.T) {
+	t.Parallel()
+
+	args := ffmpegSilenceDetectArgs("/music/a.flac")
+	if !slices.Contains(args, "silencedetect=noise=-60dB:d=0.05") || args[len(args)-1] != "-" || !slices.Contains(args, "0:a:0") {
+		t.Fatalf("unexpected args %v", args)
+	}
+}
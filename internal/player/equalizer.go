@@ -0,0 +1,215 @@
+package player
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// EqualizerSettingKey stores the equalizer as JSON.
+const EqualizerSettingKey = "player.equalizer"
+
+// EqualizerBandCount is the number of equalizer bands, one per octave.
+const EqualizerBandCount = 10
+
+// EqualizerMaxGainDB bounds each band's boost or cut.
+const EqualizerMaxGainDB = 12.0
+
+// EqualizerBands are the band center frequencies in Hz.
+var EqualizerBands = [EqualizerBandCount]int{31, 62, 125, 250, 500, 1000, 2000, 4000, 8000, 16000}
+
+// Built-in equalizer presets.
+const (
+	EqualizerPresetFlat      = "flat"
+	EqualizerPresetRock      = "rock"
+	EqualizerPresetVocal     = "vocal"
+	EqualizerPresetBassBoost = "bass_boost"
+)
+
+// Equalizer is a 10-band graphic equalizer. Gains are in dB, one per entry
+// of EqualizerBands. Preset names the built-in preset the gains match, or is
+// empty once a band was adjusted by hand.
+type Equalizer struct {
+	Enabled bool      `json:"enabled"`
+	Preset  string    `json:"preset"`
+	Gains   []float64 `json:"gains"`
+}
+
+// EqualizerPreset is a named set of band gains.
+type EqualizerPreset struct {
+	ID    string    `json:"id"`
+	Name  string    `json:"name"`
+	Gains []float64 `json:"gains"`
+}
+
+var equalizerPresets = []EqualizerPreset{
+	{ID: EqualizerPresetFlat, Name: "Flat", Gains: []float64{0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+	{ID: EqualizerPresetRock, Name: "Rock", Gains: []float64{5, 4, 3, 1, -1, -1, 1, 3, 4, 5}},
+	{ID: EqualizerPresetVocal, Name: "Vocal", Gains: []float64{-2, -2, -1, 1, 3, 4, 4, 2, 0, -1}},
+	{ID: EqualizerPresetBassBoost, Name: "Bass Boost", Gains: []float64{7, 6, 5, 3, 1, 0, 0, 0, 0, 0}},
+}
+
+// EqualizerPresets lists the built-in presets.
+func EqualizerPresets() []EqualizerPreset {
+	presets := make([]EqualizerPreset, 0, len(equalizerPresets))
+	for _, preset := range equalizerPresets {
+		preset.Gains = append([]float64(nil), preset.Gains...)
+		presets = append(presets, preset)
+	}
+	return presets
+}
+
+func findEqualizerPreset(id string) (EqualizerPreset, bool) {
+	for _, preset := range equalizerPresets {
+		if preset.ID == id {
+			return preset, true
+		}
+	}
+	return EqualizerPreset{}, false
+}
+
+func defaultEqualizer() Equalizer {
+	return Equalizer{Preset: EqualizerPresetFlat, Gains: make([]float64, EqualizerBandCount)}
+}
+
+// normalizeEqualizer clamps the gains. A known preset with no gains takes
+// the preset's gains; otherwise Preset is kept only while the gains still
+// match it.
+func normalizeEqualizer(config Equalizer) (Equalizer, error) {
+	presetID := strings.ToLower(strings.TrimSpace(config.Preset))
+	preset, known := findEqualizerPreset(presetID)
+	if presetID != "" && !known {
+		return Equalizer{}, fmt.Errorf("unknown equalizer preset %q", config.Preset)
+	}
+
+	gains := config.Gains
+	if len(gains) == 0 && known {
+		gains = preset.Gains
+	}
+	if len(gains) == 0 {
+		gains = make([]float64, EqualizerBandCount)
+	}
+	if len(gains) != EqualizerBandCount {
+		return Equalizer{}, fmt.Errorf("equalizer needs %d band gains, got %d", EqualizerBandCount, len(gains))
+	}
+
+	normalized := Equalizer{Enabled: config.Enabled, Gains: make([]float64, EqualizerBandCount)}
+	for index, gain := range gains {
+		if math.IsNaN(gain) {
+			gain = 0
+		}
+		gain = math.Max(-EqualizerMaxGainDB, math.Min(EqualizerMaxGainDB, gain))
+		normalized.Gains[index] = math.Round(gain*10) / 10
+	}
+
+	if known && equalGains(normalized.Gains, preset.Gains) {
+		normalized.Preset = preset.ID
+	}
+
+	return normalized, nil
+}
+
+func equalGains(left []float64, right []float64) bool {
+	if len(left) != len(right) {
+		return false
+	}
+	for index := range left {
+		if left[index] != right[index] {
+			return false
+		}
+	}
+	return true
+}
+
+// equalizerFilter builds the backend filter for the equalizer, or an empty
+// string when it is off or flat. Each band is an octave-wide peaking filter.
+func equalizerFilter(config Equalizer) string {
+	if !config.Enabled {
+		return ""
+	}
+
+	bands := make([]string, 0, EqualizerBandCount)
+	for index, gain := range config.Gains {
+		if index >= EqualizerBandCount || gain == 0 {
+			continue
+		}
+		bands = append(bands, fmt.Sprintf(
+			"equalizer=f=%d:t=o:w=1:g=%s",
+			EqualizerBands[index],
+			strconv.FormatFloat(gain, 'f', 1, 64),
+		))
+	}
+	if len(bands) == 0 {
+		return ""
+	}
+
+	return "lavfi=[" + strings.Join(bands, ",") + "]"
+}
+
+func (s *Service) GetEqualizer() Equalizer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	config := s.equalizer
+	config.Gains = append([]float64(nil), config.Gains...)
+	return config
+}
+
+// SetEqualizer saves the equalizer and applies it to the main and preview
+// backends right away.
+func (s *Service) SetEqualizer(config Equalizer) (Equalizer, error) {
+	normalized, err := normalizeEqualizer(config)
+	if err != nil {
+		return s.GetEqualizer(), err
+	}
+
+	if s.settings != nil {
+		encoded, err := json.Marshal(normalized)
+		if err != nil {
+			return s.GetEqualizer(), fmt.Errorf("encode equalizer: %w", err)
+		}
+		if err := s.settings.Set(context.Background(), EqualizerSettingKey, string(encoded)); err != nil {
+			return s.GetEqualizer(), err
+		}
+	}
+
+	s.mu.Lock()
+	s.equalizer = normalized
+	backend := s.backend
+	previewBackend := s.previewBackend
+	filter := s.audioFilterChainLocked()
+	s.mu.Unlock()
+
+	if backend != nil {
+		if err := backend.SetAudioFilter(filter); err != nil {
+			return normalized, err
+		}
+	}
+	if previewBackend != nil {
+		_ = previewBackend.SetAudioFilter(filter)
+	}
+
+	return s.GetEqualizer(), nil
+}
+
+func (s *Service) loadEqualizer() {
+	s.equalizer = defaultEqualizer()
+	if s.settings == nil {
+		return
+	}
+
+	raw, ok, err := s.settings.Get(context.Background(), EqualizerSettingKey)
+	if err != nil || !ok {
+		return
+	}
+
+	var stored Equalizer
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+		return
+	}
+	if normalized, err := normalizeEqualizer(stored); err == nil {
+		s.equalizer = normalized
+	}
+}
@@ -0,0 +1,50 @@
+package player
+
+import "testing"
+
+func TestEqualizerFilterSkipsFlatBands(t *testing.T) {
+	t.Parallel()
+
+	if got := equalizerFilter(defaultEqualizer()); got != "" {
+		t.Fatalf("expected no filter for a disabled equalizer, got %q", got)
+	}
+
+	config, err := normalizeEqualizer(Equalizer{Enabled: true, Gains: []float64{3, 0, 0, 0, 0, 0, 0, 0, 0, -2.5}})
+	if err != nil {
+		t.Fatalf("normalize equalizer: %v", err)
+	}
+	want := "lavfi=[equalizer=f=31:t=o:w=1:g=3.0,equalizer=f=16000:t=o:w=1:g=-2.5]"
+	if got := equalizerFilter(config); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestNormalizeEqualizerAppliesPresetAndClamps(t *testing.T) {
+	t.Parallel()
+
+	config, err := normalizeEqualizer(Equalizer{Enabled: true, Preset: "Bass_Boost"})
+	if err != nil {
+		t.Fatalf("normalize preset: %v", err)
+	}
+	if config.Preset != EqualizerPresetBassBoost || config.Gains[0] != 7 {
+		t.Fatalf("expected bass boost gains, got %+v", config)
+	}
+
+	config, err = normalizeEqualizer(Equalizer{Preset: EqualizerPresetRock, Gains: []float64{20, 0, 0, 0, 0, 0, 0, 0, 0, 0}})
+	if err != nil {
+		t.Fatalf("normalize gains: %v", err)
+	}
+	if config.Gains[0] != EqualizerMaxGainDB {
+		t.Fatalf("expected gain clamped to %v, got %v", EqualizerMaxGainDB, config.Gains[0])
+	}
+	if config.Preset != "" {
+		t.Fatalf("expected edited gains to clear the preset, got %q", config.Preset)
+	}
+
+	if _, err := normalizeEqualizer(Equalizer{Gains: []float64{1, 2}}); err == nil {
+		t.Fatal("expected a short gain list to be rejected")
+	}
+	if _, err := normalizeEqualizer(Equalizer{Preset: "jazz"}); err == nil {
+		t.Fatal("expected an unknown preset to be rejected")
+	}
+}
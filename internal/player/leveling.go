@@ -0,0 +1,214 @@
+package player
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rzxx/ben/internal/queue"
+)
+
+// SmartLevelingSettingKey turns queue-aware loudness leveling on.
+const SmartLevelingSettingKey = "player.smart_leveling"
+
+const (
+	// levelingLookahead is how many queued tracks, the current one
+	// included, get an adjustment ahead of time.
+	levelingLookahead  = 20
+	maxLevelingBoostDB = 6.0
+	maxLevelingCutDB   = -15.0
+)
+
+// LevelingState reports the adjustment applied to the current track.
+// Estimated is set for tracks without loudness tags, which are leveled
+// from the tagged tracks around them in the queue.
+type LevelingState struct {
+	GainDB    float64 `json:"gainDb"`
+	Estimated bool    `json:"estimated"`
+}
+
+// trackLoudness holds the ReplayGain tags of a queued track. Peak is
+// linear, 1 being full scale.
+type trackLoudness struct {
+	trackID int64
+	gainDB  *float64
+	peak    *float64
+}
+
+type levelingGain struct {
+	gainDB    float64
+	estimated bool
+}
+
+// planLeveling computes the volume adjustment of each track so they all
+// play at the ReplayGain reference loudness. Boosts are limited by the
+// track peak so leveling never clips. Untagged tracks get the median gain
+// of the tagged ones, so a mixed queue stays even instead of jumping on
+// every untagged track. When the backend already applies the tags,
+// tagged tracks need no adjustment and only untagged ones are leveled.
+func planLeveling(tracks []trackLoudness, replayGainApplied bool) map[int64]levelingGain {
+	known := make([]float64, 0, len(tracks))
+	for _, track := range tracks {
+		if track.gainDB != nil {
+			known = append(known, limitLevelingGain(*track.gainDB, track.peak))
+		}
+	}
+
+	estimate := 0.0
+	if len(known) > 0 {
+		sorted := slices.Clone(known)
+		slices.Sort(sorted)
+		middle := len(sorted) / 2
+		estimate = sorted[middle]
+		if len(sorted)%2 == 0 {
+			estimate = (sorted[middle-1] + sorted[middle]) / 2
+		}
+	}
+
+	plan := make(map[int64]levelingGain, len(tracks))
+	for _, track := range tracks {
+		switch {
+		case track.gainDB == nil:
+			plan[track.trackID] = levelingGain{gainDB: estimate, estimated: true}
+		case replayGainApplied:
+			plan[track.trackID] = levelingGain{}
+		default:
+			plan[track.trackID] = levelingGain{gainDB: limitLevelingGain(*track.gainDB, track.peak)}
+		}
+	}
+
+	return plan
+}
+
+func limitLevelingGain(gainDB float64, peak *float64) float64 {
+	gainDB = min(max(gainDB, maxLevelingCutDB), maxLevelingBoostDB)
+	if peak != nil && *peak > 0 {
+		gainDB = min(gainDB, -20*math.Log10(*peak))
+	}
+
+	return gainDB
+}
+
+func (s *Service) loadSmartLeveling() {
+	if s.settings == nil {
+		return
+	}
+
+	enabled, err := strconv.ParseBool(s.settings.GetString(context.Background(), SmartLevelingSettingKey, "false"))
+	s.levelingEnabled = err == nil && enabled
+}
+
+func (s *Service) SmartLevelingEnabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.levelingEnabled
+}
+
+// SetSmartLevelingEnabled saves the setting and re-levels the current
+// track right away.
+func (s *Service) SetSmartLevelingEnabled(enabled bool) (bool, error) {
+	if s.settings == nil {
+		return false, errors.New("settings are unavailable")
+	}
+	if err := s.settings.Set(context.Background(), SmartLevelingSettingKey, strconv.FormatBool(enabled)); err != nil {
+		return s.SmartLevelingEnabled(), err
+	}
+
+	s.mu.Lock()
+	s.levelingEnabled = enabled
+	s.levelingGains = nil
+	s.updatedAt = time.Now().UTC()
+	s.mu.Unlock()
+
+	if s.queue != nil {
+		s.precomputeLeveling(s.queue.GetState())
+	}
+	if backend := s.tryBackend(); backend != nil {
+		s.applyCrossfade(backend)
+	}
+
+	s.emitState(s.GetState())
+	return enabled, nil
+}
+
+// precomputeLeveling plans the adjustments of the current and upcoming
+// tracks. It runs whenever the next track is preloaded, so the gain of a
+// track is known before the backend switches to it.
+func (s *Service) precomputeLeveling(queueState queue.State) {
+	s.mu.Lock()
+	enabled := s.levelingEnabled
+	replayGainApplied := s.replayGainMode != ReplayGainOff
+	s.mu.Unlock()
+	if !enabled || s.db == nil || len(queueState.Entries) == 0 {
+		return
+	}
+
+	start := max(queueState.CurrentIndex, 0)
+	if start >= len(queueState.Entries) {
+		return
+	}
+	upcoming := queueState.Entries[start:min(start+levelingLookahead, len(queueState.Entries))]
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(upcoming)), ",")
+	args := make([]any, 0, len(upcoming))
+	for _, entry := range upcoming {
+		args = append(args, entry.ID)
+	}
+
+	rows, err := s.db.QueryContext(
+		context.Background(),
+		"SELECT id, replaygain_track_gain, replaygain_track_peak FROM tracks WHERE id IN ("+placeholders+")",
+		args...,
+	)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	tracks := make([]trackLoudness, 0, len(upcoming))
+	for rows.Next() {
+		var track trackLoudness
+		if rows.Scan(&track.trackID, &track.gainDB, &track.peak) != nil {
+			return
+		}
+		tracks = append(tracks, track)
+	}
+	if rows.Err() != nil {
+		return
+	}
+
+	plan := planLeveling(tracks, replayGainApplied)
+
+	s.mu.Lock()
+	s.levelingGains = plan
+	s.mu.Unlock()
+}
+
+// levelingGainDB returns the planned adjustment of a track, 0 while
+// leveling is off or the track was not planned yet.
+func (s *Service) levelingGainDB(trackID int64) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.levelingEnabled {
+		return 0
+	}
+	return s.levelingGains[trackID].gainDB
+}
+
+func (s *Service) levelingStateFor(trackID int64) *LevelingState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.levelingEnabled {
+		return nil
+	}
+
+	gain, ok := s.levelingGains[trackID]
+	if !ok {
+		return nil
+	}
+	return &LevelingState{GainDB: gain.gainDB, Estimated: gain.estimated}
+}
@@ -0,0 +1,81 @@
+package player
+
+import (
+	"math"
+	"testing"
+)
+
+func levelingGainPtr(value float64) *float64 {
+	return &value
+}
+
+func TestPlanLevelingLevelsTaggedTracks(t *testing.T) {
+	t.Parallel()
+
+	plan := planLeveling([]trackLoudness{
+		{trackID: 1, gainDB: levelingGainPtr(-8)},
+		{trackID: 2, gainDB: levelingGainPtr(4), peak: levelingGainPtr(0.5)},
+		{trackID: 3, gainDB: levelingGainPtr(-30)},
+		{trackID: 4, gainDB: levelingGainPtr(12)},
+	}, false)
+
+	if plan[1].gainDB != -8 || plan[1].estimated {
+		t.Fatalf("expected the tagged gain to be used, got %+v", plan[1])
+	}
+	if plan[2].gainDB != 4 {
+		t.Fatalf("expected a boost within the peak headroom to be kept, got %+v", plan[2])
+	}
+	if plan[3].gainDB != maxLevelingCutDB {
+		t.Fatalf("expected the cut to be limited, got %+v", plan[3])
+	}
+	if plan[4].gainDB != maxLevelingBoostDB {
+		t.Fatalf("expected the boost to be limited, got %+v", plan[4])
+	}
+}
+
+func TestPlanLevelingLimitsBoostToPeak(t *testing.T) {
+	t.Parallel()
+
+	plan := planLeveling([]trackLoudness{
+		{trackID: 1, gainDB: levelingGainPtr(5), peak: levelingGainPtr(0.9)},
+	}, false)
+
+	want := -20 * math.Log10(0.9)
+	if math.Abs(plan[1].gainDB-want) > 1e-9 {
+		t.Fatalf("expected the boost to stop at the peak (%.3f dB), got %+v", want, plan[1])
+	}
+}
+
+func TestPlanLevelingEstimatesUntaggedTracks(t *testing.T) {
+	t.Parallel()
+
+	tracks := []trackLoudness{
+		{trackID: 1, gainDB: levelingGainPtr(-9)},
+		{trackID: 2},
+		{trackID: 3, gainDB: levelingGainPtr(-3)},
+		{trackID: 4, gainDB: levelingGainPtr(-5)},
+		{trackID: 5, gainDB: levelingGainPtr(-1)},
+	}
+
+	plan := planLeveling(tracks, false)
+	if plan[2].gainDB != -4 || !plan[2].estimated {
+		t.Fatalf("expected the untagged track to get the median gain, got %+v", plan[2])
+	}
+
+	applied := planLeveling(tracks, true)
+	if applied[1].gainDB != 0 || applied[3].gainDB != 0 {
+		t.Fatalf("expected no adjustment on tracks the backend levels, got %+v %+v", applied[1], applied[3])
+	}
+	if applied[2].gainDB != -4 || !applied[2].estimated {
+		t.Fatalf("expected the untagged track to be leveled alongside ReplayGain, got %+v", applied[2])
+	}
+}
+
+func TestPlanLevelingWithoutTagsLeavesVolume(t *testing.T) {
+	t.Parallel()
+
+	plan := planLeveling([]trackLoudness{{trackID: 1}, {trackID: 2}}, false)
+	if plan[1].gainDB != 0 || plan[2].gainDB != 0 {
+		t.Fatalf("expected no adjustment without any loudness tags, got %+v", plan)
+	}
+}
@@ -0,0 +1,237 @@
+package player
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const EventPreviewChanged = "player:preview"
+
+const defaultPreviewMS = 15000
+
+const maxPreviewMS = 60000
+
+// PreviewState describes the excerpt playing on the preview backend.
+type PreviewState struct {
+	Active     bool   `json:"active"`
+	TrackID    int64  `json:"trackId,omitempty"`
+	StartMS    int    `json:"startMs"`
+	DurationMS int    `json:"durationMs"`
+	StartedAt  string `json:"startedAt,omitempty"`
+}
+
+// PlayPreview plays a short excerpt of a track on a separate backend so the
+// queue, the main player state and listening stats stay untouched. Main
+// playback is paused for the excerpt and resumed when it ends.
+func (s *Service) PlayPreview(trackID int64, startMS int, durationMS int) (PreviewState, error) {
+	if s.db == nil {
+		return PreviewState{}, errors.New("library database is unavailable")
+	}
+	if startMS < 0 {
+		startMS = 0
+	}
+	if durationMS <= 0 {
+		durationMS = defaultPreviewMS
+	}
+	if durationMS > maxPreviewMS {
+		durationMS = maxPreviewMS
+	}
+
+	var path string
+	var trackDurationMS sql.NullInt64
+	err := s.db.QueryRowContext(
+		context.Background(),
+		`SELECT f.path, t.duration_ms
+		 FROM tracks t
+		 JOIN files f ON f.id = t.file_id
+		 WHERE t.id = ? AND f.file_exists = 1`,
+		trackID,
+	).Scan(&path, &trackDurationMS)
+	if errors.Is(err, sql.ErrNoRows) {
+		return PreviewState{}, fmt.Errorf("track %d not found", trackID)
+	}
+	if err != nil {
+		return PreviewState{}, fmt.Errorf("get preview track %d: %w", trackID, err)
+	}
+
+	if trackDurationMS.Valid && trackDurationMS.Int64 > 0 && startMS >= int(trackDurationMS.Int64) {
+		startMS = max(int(trackDurationMS.Int64)-durationMS, 0)
+	}
+
+	backend, err := s.previewBackendForUse()
+	if err != nil {
+		return PreviewState{}, err
+	}
+
+	s.mu.Lock()
+	wasPlaying := s.status == StatusPlaying && !s.previewActive
+	resumeMain := s.previewResumeMain || wasPlaying
+	volume := s.volume
+	s.mu.Unlock()
+
+	if wasPlaying {
+		if _, err := s.Pause(); err != nil {
+			return PreviewState{}, err
+		}
+	}
+
+	playPath, err := s.playbackPath(backend, path)
+	if err == nil {
+		err = backend.Load(playPath)
+	}
+	if err != nil {
+		s.abortPreview(resumeMain)
+		return PreviewState{}, fmt.Errorf("load preview %q: %w", path, err)
+	}
+	_ = backend.SetVolume(volume)
+	if err := backend.Play(); err != nil {
+		s.abortPreview(resumeMain)
+		return PreviewState{}, fmt.Errorf("start preview: %w", err)
+	}
+	if startMS > 0 {
+		seekPreview(backend, startMS)
+	}
+
+	s.mu.Lock()
+	s.previewGeneration++
+	generation := s.previewGeneration
+	if s.previewTimer != nil {
+		s.previewTimer.Stop()
+	}
+	s.previewTimer = time.AfterFunc(time.Duration(durationMS)*time.Millisecond, func() {
+		s.finishPreview(generation, false)
+	})
+	s.previewActive = true
+	s.previewResumeMain = resumeMain
+	s.preview = PreviewState{
+		Active:     true,
+		TrackID:    trackID,
+		StartMS:    startMS,
+		DurationMS: durationMS,
+		StartedAt:  time.Now().UTC().Format(time.RFC3339),
+	}
+	preview := s.preview
+	s.mu.Unlock()
+
+	s.emitPreview(preview)
+	return preview, nil
+}
+
+// StopPreview ends the running excerpt and resumes main playback if the
+// preview paused it.
+func (s *Service) StopPreview() PreviewState {
+	s.finishPreview(0, false)
+	return s.GetPreviewState()
+}
+
+func (s *Service) GetPreviewState() PreviewState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.preview
+}
+
+func (s *Service) previewBackendForUse() (playbackBackend, error) {
+	s.mu.Lock()
+	backend := s.previewBackend
+	options := s.appliedBackendOptions.Options
+	s.mu.Unlock()
+	if backend != nil {
+		return backend, nil
+	}
+
+	created, err := newPlaybackBackend(options)
+	if err != nil {
+		return nil, fmt.Errorf("create preview backend: %w", err)
+	}
+
+	created.SetOnEOF(func() {
+		s.mu.Lock()
+		generation := s.previewGeneration
+		s.mu.Unlock()
+		s.finishPreview(generation, false)
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.previewBackend != nil {
+		_ = created.Close()
+		return s.previewBackend, nil
+	}
+	_ = created.SetAudioFilter(s.audioFilterChainLocked())
+	applyReplayGain(created, s.replayGainMode)
+	s.previewBackend = created
+	return created, nil
+}
+
+// abortPreview cleans up after an excerpt failed to start and gives
+// playback back to the main player when the preview had paused it.
+func (s *Service) abortPreview(resumeMain bool) {
+	s.mu.Lock()
+	active := s.previewActive
+	if active {
+		s.previewResumeMain = resumeMain
+	}
+	s.mu.Unlock()
+
+	if active {
+		s.finishPreview(0, false)
+		return
+	}
+	if resumeMain {
+		_, _ = s.Play()
+	}
+}
+
+// finishPreview stops the preview backend. A non-zero generation only stops
+// the preview it was scheduled for, so a stale timer cannot cut a newer
+// excerpt short. suppressResume is used when main playback takes over.
+func (s *Service) finishPreview(generation uint64, suppressResume bool) {
+	s.mu.Lock()
+	if !s.previewActive || (generation != 0 && generation != s.previewGeneration) {
+		s.mu.Unlock()
+		return
+	}
+	if s.previewTimer != nil {
+		s.previewTimer.Stop()
+		s.previewTimer = nil
+	}
+	backend := s.previewBackend
+	resumeMain := s.previewResumeMain && !suppressResume
+	s.previewActive = false
+	s.previewResumeMain = false
+	s.preview = PreviewState{}
+	preview := s.preview
+	s.mu.Unlock()
+
+	if backend != nil {
+		_ = backend.Pause()
+	}
+
+	s.emitPreview(preview)
+
+	if resumeMain {
+		_, _ = s.Play()
+	}
+}
+
+func (s *Service) emitPreview(preview PreviewState) {
+	s.mu.Lock()
+	emitter := s.emit
+	s.mu.Unlock()
+
+	if emitter != nil {
+		emitter(EventPreviewChanged, preview)
+	}
+}
+
+func seekPreview(backend playbackBackend, positionMS int) {
+	for attempt := 0; attempt < resumeSeekAttempts; attempt++ {
+		if err := backend.Seek(positionMS); err == nil {
+			return
+		}
+		time.Sleep(resumeSeekDelay)
+	}
+}
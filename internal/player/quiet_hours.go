@@ -0,0 +1,155 @@
+package player
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QuietHoursSettingKey stores the quiet hours schedule as JSON.
+const QuietHoursSettingKey = "player.quiet_hours"
+
+const defaultQuietHoursMaxVolume = 40
+
+// QuietHours caps the volume between Start and End, given as local "HH:MM"
+// times. A schedule whose start is after its end runs past midnight.
+type QuietHours struct {
+	Enabled   bool   `json:"enabled"`
+	Start     string `json:"start"`
+	End       string `json:"end"`
+	MaxVolume int    `json:"maxVolume"`
+}
+
+func DefaultQuietHours() QuietHours {
+	return QuietHours{Start: "23:00", End: "07:00", MaxVolume: defaultQuietHoursMaxVolume}
+}
+
+func normalizeQuietHours(config QuietHours) (QuietHours, error) {
+	start, err := parseClockMinutes(config.Start)
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("quiet hours start: %w", err)
+	}
+	end, err := parseClockMinutes(config.End)
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("quiet hours end: %w", err)
+	}
+	if start == end {
+		return QuietHours{}, errors.New("quiet hours start and end must differ")
+	}
+
+	return QuietHours{
+		Enabled:   config.Enabled,
+		Start:     formatClockMinutes(start),
+		End:       formatClockMinutes(end),
+		MaxVolume: clampVolume(config.MaxVolume),
+	}, nil
+}
+
+func parseClockMinutes(value string) (int, error) {
+	hours, minutes, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+
+	hour, hourErr := strconv.Atoi(hours)
+	minute, minuteErr := strconv.Atoi(minutes)
+	if hourErr != nil || minuteErr != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+
+	return hour*60 + minute, nil
+}
+
+func formatClockMinutes(value int) string {
+	return fmt.Sprintf("%02d:%02d", value/60, value%60)
+}
+
+func (q QuietHours) activeAt(at time.Time) bool {
+	if !q.Enabled {
+		return false
+	}
+
+	start, startErr := parseClockMinutes(q.Start)
+	end, endErr := parseClockMinutes(q.End)
+	if startErr != nil || endErr != nil {
+		return false
+	}
+
+	minute := at.Hour()*60 + at.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+
+	return minute >= start || minute < end
+}
+
+func (s *Service) GetQuietHours() QuietHours {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.quietHours
+}
+
+// SetQuietHours saves the schedule and applies the cap right away if the
+// schedule is active now.
+func (s *Service) SetQuietHours(config QuietHours) (QuietHours, error) {
+	normalized, err := normalizeQuietHours(config)
+	if err != nil {
+		return s.GetQuietHours(), err
+	}
+
+	if s.settings != nil {
+		encoded, err := json.Marshal(normalized)
+		if err != nil {
+			return s.GetQuietHours(), fmt.Errorf("encode quiet hours: %w", err)
+		}
+		if err := s.settings.Set(context.Background(), QuietHoursSettingKey, string(encoded)); err != nil {
+			return s.GetQuietHours(), err
+		}
+	}
+
+	s.mu.Lock()
+	s.quietHours = normalized
+	s.updatedAt = time.Now().UTC()
+	s.mu.Unlock()
+
+	if backend := s.tryBackend(); backend != nil {
+		s.applyCrossfade(backend)
+	}
+
+	s.emitState(s.GetState())
+	return normalized, nil
+}
+
+func (s *Service) loadQuietHours() {
+	s.quietHours = DefaultQuietHours()
+	if s.settings == nil {
+		return
+	}
+
+	raw, ok, err := s.settings.Get(context.Background(), QuietHoursSettingKey)
+	if err != nil || !ok {
+		return
+	}
+
+	var stored QuietHours
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+		return
+	}
+	if normalized, err := normalizeQuietHours(stored); err == nil {
+		s.quietHours = normalized
+	}
+}
+
+// cappedVolumeLocked returns the volume to send to the backend for the
+// user's volume at the given time.
+func (s *Service) cappedVolumeLocked(volume int, at time.Time) int {
+	if s.quietHours.activeAt(at) && volume > s.quietHours.MaxVolume {
+		return s.quietHours.MaxVolume
+	}
+
+	return volume
+}
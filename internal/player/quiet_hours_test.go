@@ -0,0 +1,44 @@
+package player
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHoursActiveAcrossMidnight(t *testing.T) {
+	t.Parallel()
+
+	config, err := normalizeQuietHours(QuietHours{Enabled: true, Start: "23:00", End: "7:00", MaxVolume: 30})
+	if err != nil {
+		t.Fatalf("normalize quiet hours: %v", err)
+	}
+	if config.End != "07:00" {
+		t.Fatalf("expected end to be normalized to 07:00, got %q", config.End)
+	}
+
+	at := func(hour int, minute int) time.Time {
+		return time.Date(2026, 3, 1, hour, minute, 0, 0, time.Local)
+	}
+	if !config.activeAt(at(23, 30)) || !config.activeAt(at(6, 59)) {
+		t.Fatalf("expected quiet hours to be active overnight")
+	}
+	if config.activeAt(at(7, 0)) || config.activeAt(at(22, 59)) {
+		t.Fatalf("expected quiet hours to be inactive during the day")
+	}
+
+	config.Enabled = false
+	if config.activeAt(at(23, 30)) {
+		t.Fatalf("expected disabled quiet hours to be inactive")
+	}
+}
+
+func TestNormalizeQuietHoursRejectsInvalidTimes(t *testing.T) {
+	t.Parallel()
+
+	if _, err := normalizeQuietHours(QuietHours{Start: "25:00", End: "07:00"}); err == nil {
+		t.Fatalf("expected invalid start to be rejected")
+	}
+	if _, err := normalizeQuietHours(QuietHours{Start: "07:00", End: "07:00"}); err == nil {
+		t.Fatalf("expected empty window to be rejected")
+	}
+}
@@ -0,0 +1,97 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReplayGainModeSettingKey stores the loudness normalization mode.
+const ReplayGainModeSettingKey = "player.replaygain_mode"
+
+const (
+	ReplayGainOff = "off"
+	// ReplayGainTrack levels every track on its own.
+	ReplayGainTrack = "track"
+	// ReplayGainAlbum applies one gain to a whole album, which keeps the
+	// dynamics between its tracks and their gapless transitions intact.
+	ReplayGainAlbum = "album"
+)
+
+// replayGainBackend is implemented by backends that apply ReplayGain and
+// R128 tags themselves. Gains are applied per file while decoding, so
+// gapless transitions are not interrupted.
+type replayGainBackend interface {
+	SetReplayGain(mode string) error
+}
+
+func normalizeReplayGainMode(mode string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(mode))
+	switch normalized {
+	case "":
+		return ReplayGainOff, nil
+	case ReplayGainOff, ReplayGainTrack, ReplayGainAlbum:
+		return normalized, nil
+	default:
+		return "", fmt.Errorf("unknown replaygain mode %q", mode)
+	}
+}
+
+func (s *Service) GetReplayGainMode() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.replayGainMode
+}
+
+// SetReplayGainMode saves the mode and applies it to the backend. The
+// playing track is normalized from the next track on.
+func (s *Service) SetReplayGainMode(mode string) (string, error) {
+	normalized, err := normalizeReplayGainMode(mode)
+	if err != nil {
+		return s.GetReplayGainMode(), err
+	}
+
+	if s.settings != nil {
+		if err := s.settings.Set(context.Background(), ReplayGainModeSettingKey, normalized); err != nil {
+			return s.GetReplayGainMode(), err
+		}
+	}
+
+	s.mu.Lock()
+	s.replayGainMode = normalized
+	s.updatedAt = time.Now().UTC()
+	backend := s.backend
+	preview := s.previewBackend
+	s.mu.Unlock()
+
+	applyReplayGain(backend, normalized)
+	applyReplayGain(preview, normalized)
+	if s.queue != nil {
+		s.precomputeLeveling(s.queue.GetState())
+	}
+
+	s.emitState(s.GetState())
+	return normalized, nil
+}
+
+func (s *Service) loadReplayGainMode() {
+	s.replayGainMode = ReplayGainOff
+	if s.settings == nil {
+		return
+	}
+
+	raw, ok, err := s.settings.Get(context.Background(), ReplayGainModeSettingKey)
+	if err != nil || !ok {
+		return
+	}
+	if normalized, err := normalizeReplayGainMode(raw); err == nil {
+		s.replayGainMode = normalized
+	}
+}
+
+func applyReplayGain(backend playbackBackend, mode string) {
+	if gainBackend, ok := backend.(replayGainBackend); ok {
+		_ = gainBackend.SetReplayGain(mode)
+	}
+}
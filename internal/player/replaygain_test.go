@@ -0,0 +1,21 @@
+package player
+
+import "testing"
+
+func TestNormalizeReplayGainMode(t *testing.T) {
+	t.Parallel()
+
+	for input, want := range map[string]string{"": ReplayGainOff, " Album ": ReplayGainAlbum, "track": ReplayGainTrack} {
+		got, err := normalizeReplayGainMode(input)
+		if err != nil {
+			t.Fatalf("normalize %q: %v", input, err)
+		}
+		if got != want {
+			t.Fatalf("expected %q for %q, got %q", want, input, got)
+		}
+	}
+
+	if _, err := normalizeReplayGainMode("loud"); err == nil {
+		t.Fatal("expected an unknown mode to be rejected")
+	}
+}
@@ -0,0 +1,92 @@
+package player
+
+import "time"
+
+// resumeSaveInterval throttles position writes while a resumable track
+// plays; pausing, stopping or changing tracks always writes.
+const resumeSaveInterval = 10 * time.Second
+
+// ResumeStore keeps per-track resume positions, e.g. for audiobooks. Tracks
+// the store does not handle report ok=false and are never saved.
+type ResumeStore interface {
+	ResumePosition(trackID int64) (positionMS int, ok bool)
+	SaveResumePosition(trackID int64, positionMS int, durationMS *int)
+}
+
+type resumeProgress struct {
+	trackID    int64
+	resumable  bool
+	positionMS int
+	durationMS *int
+	savedAt    time.Time
+}
+
+func (s *Service) SetResumeStore(store ResumeStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resumeStore = store
+}
+
+// resumePosition returns where a newly loaded track should start.
+func (s *Service) resumePosition(trackID int64) int {
+	s.mu.Lock()
+	store := s.resumeStore
+	s.mu.Unlock()
+
+	if store == nil || trackID <= 0 {
+		return 0
+	}
+
+	positionMS, ok := store.ResumePosition(trackID)
+	if !ok || positionMS <= 0 {
+		return 0
+	}
+
+	return positionMS
+}
+
+// recordResumePosition saves the position of resumable tracks from the
+// emitted state. The store is asked whether a track is resumable once per
+// track; when the track changes, the last known position of the previous
+// one is written so the store can tell whether it was finished.
+func (s *Service) recordResumePosition(state State) {
+	s.mu.Lock()
+	store := s.resumeStore
+	previous := s.resumeProgress
+	s.mu.Unlock()
+
+	if store == nil {
+		return
+	}
+
+	now := time.Now()
+	current := resumeProgress{}
+	if state.CurrentTrack != nil {
+		current = resumeProgress{
+			trackID:    state.CurrentTrack.ID,
+			positionMS: state.PositionMS,
+			durationMS: state.DurationMS,
+		}
+	}
+
+	if current.trackID == previous.trackID {
+		current.resumable = previous.resumable
+		current.savedAt = previous.savedAt
+	} else {
+		if previous.resumable {
+			store.SaveResumePosition(previous.trackID, previous.positionMS, previous.durationMS)
+		}
+		if current.trackID != 0 {
+			_, current.resumable = store.ResumePosition(current.trackID)
+		}
+	}
+
+	if current.resumable && (state.Status != StatusPlaying || now.Sub(current.savedAt) >= resumeSaveInterval) {
+		store.SaveResumePosition(current.trackID, current.positionMS, current.durationMS)
+		current.savedAt = now
+	}
+
+	s.mu.Lock()
+	s.resumeProgress = current
+	s.mu.Unlock()
+}
@@ -0,0 +1,47 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+type fakeResumeStore struct {
+	resumable map[int64]bool
+	saved     map[int64]int
+	lookups   int
+}
+
+func (s *fakeResumeStore) ResumePosition(trackID int64) (int, bool) {
+	s.lookups++
+	return s.saved[trackID], s.resumable[trackID]
+}
+
+func (s *fakeResumeStore) SaveResumePosition(trackID int64, positionMS int, _ *int) {
+	s.saved[trackID] = positionMS
+}
+
+func TestRecordResumePositionSavesOnlyResumableTracks(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeResumeStore{resumable: map[int64]bool{1: true}, saved: make(map[int64]int)}
+	service := &Service{}
+	service.SetResumeStore(store)
+
+	service.recordResumePosition(State{Status: StatusPaused, PositionMS: 4000, CurrentTrack: &library.TrackSummary{ID: 1}})
+	service.recordResumePosition(State{Status: StatusPlaying, PositionMS: 4500, CurrentTrack: &library.TrackSummary{ID: 1}})
+	if store.saved[1] != 4000 {
+		t.Fatalf("expected throttled save to keep 4000, got %d", store.saved[1])
+	}
+
+	service.recordResumePosition(State{Status: StatusPaused, PositionMS: 900, CurrentTrack: &library.TrackSummary{ID: 2}})
+	if store.saved[1] != 4500 {
+		t.Fatalf("expected last position of previous track to be saved, got %d", store.saved[1])
+	}
+	if _, saved := store.saved[2]; saved {
+		t.Fatal("expected music track not to be saved")
+	}
+	if store.lookups != 2 {
+		t.Fatalf("expected one lookup per track, got %d", store.lookups)
+	}
+}
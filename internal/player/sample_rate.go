@@ -0,0 +1,229 @@
+package player
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/queue"
+)
+
+// SampleRatePolicySettingKey stores how the output sample rate is chosen,
+// as JSON.
+const SampleRatePolicySettingKey = "player.sample_rate_policy"
+
+const (
+	// SampleRateFollowSource reopens the output at each track's own rate.
+	// Tracks with the same format still play gaplessly.
+	SampleRateFollowSource = "follow"
+	// SampleRateFixed keeps the output at FixedRate and resamples sources.
+	SampleRateFixed = "fixed"
+	// SampleRateHighestCommon keeps the output at the highest rate among the
+	// queued tracks, so nothing is downsampled and the device does not
+	// switch between tracks.
+	SampleRateHighestCommon = "highest"
+)
+
+const defaultFixedSampleRate = 48000
+
+var supportedOutputSampleRates = []int{44100, 48000, 88200, 96000, 176400, 192000, 352800, 384000}
+
+// SampleRatePolicy controls the sample rate the output device runs at.
+// FixedRate is only used by the fixed mode.
+type SampleRatePolicy struct {
+	Mode      string `json:"mode"`
+	FixedRate int    `json:"fixedRate"`
+}
+
+// sampleRateBackend is implemented by backends that can pick the output
+// sample rate. A rate of 0 leaves the source rate untouched; reopen lets
+// the backend reopen the device when the source format changes.
+type sampleRateBackend interface {
+	SetOutputSampleRate(rate int, reopen bool) error
+	OutputSampleRate() (int, bool)
+}
+
+func normalizeSampleRatePolicy(policy SampleRatePolicy) (SampleRatePolicy, error) {
+	mode := strings.ToLower(strings.TrimSpace(policy.Mode))
+	if mode == "" {
+		mode = SampleRateFollowSource
+	}
+
+	switch mode {
+	case SampleRateFollowSource, SampleRateHighestCommon:
+		return SampleRatePolicy{Mode: mode, FixedRate: defaultFixedSampleRate}, nil
+	case SampleRateFixed:
+		rate := policy.FixedRate
+		if rate == 0 {
+			rate = defaultFixedSampleRate
+		}
+		for _, supported := range supportedOutputSampleRates {
+			if rate == supported {
+				return SampleRatePolicy{Mode: mode, FixedRate: rate}, nil
+			}
+		}
+		return SampleRatePolicy{}, fmt.Errorf("unsupported output sample rate %d", policy.FixedRate)
+	default:
+		return SampleRatePolicy{}, fmt.Errorf("unknown sample rate policy %q", policy.Mode)
+	}
+}
+
+// outputSampleRate returns the rate to run the output at, 0 for the source
+// rate, and whether the output may reopen between tracks. Queued tracks
+// without a known rate are ignored by the highest common mode.
+func outputSampleRate(policy SampleRatePolicy, queuedRates []int) (int, bool) {
+	switch policy.Mode {
+	case SampleRateFixed:
+		return policy.FixedRate, false
+	case SampleRateHighestCommon:
+		highest := 0
+		for _, rate := range queuedRates {
+			highest = max(highest, rate)
+		}
+		return highest, false
+	default:
+		return 0, true
+	}
+}
+
+func (s *Service) GetSampleRatePolicy() SampleRatePolicy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sampleRatePolicy
+}
+
+// SetSampleRatePolicy saves the policy and applies it to the backend right
+// away. The new rate is heard from the next track on.
+func (s *Service) SetSampleRatePolicy(policy SampleRatePolicy) (SampleRatePolicy, error) {
+	normalized, err := normalizeSampleRatePolicy(policy)
+	if err != nil {
+		return s.GetSampleRatePolicy(), err
+	}
+
+	if s.settings != nil {
+		encoded, err := json.Marshal(normalized)
+		if err != nil {
+			return s.GetSampleRatePolicy(), fmt.Errorf("encode sample rate policy: %w", err)
+		}
+		if err := s.settings.Set(context.Background(), SampleRatePolicySettingKey, string(encoded)); err != nil {
+			return s.GetSampleRatePolicy(), err
+		}
+	}
+
+	s.mu.Lock()
+	s.sampleRatePolicy = normalized
+	s.mu.Unlock()
+
+	var queueState queue.State
+	if s.queue != nil {
+		queueState = s.queue.GetState()
+	}
+
+	return normalized, s.applySampleRatePolicy(queueState)
+}
+
+func (s *Service) loadSampleRatePolicy() {
+	s.sampleRatePolicy = SampleRatePolicy{Mode: SampleRateFollowSource, FixedRate: defaultFixedSampleRate}
+	if s.settings == nil {
+		return
+	}
+
+	raw, ok, err := s.settings.Get(context.Background(), SampleRatePolicySettingKey)
+	if err != nil || !ok {
+		return
+	}
+
+	var stored SampleRatePolicy
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+		return
+	}
+	if normalized, err := normalizeSampleRatePolicy(stored); err == nil {
+		s.sampleRatePolicy = normalized
+	}
+}
+
+// applySampleRatePolicy hands the rate for the policy and queue to the
+// backend. It only calls the backend when the rate changed, because the
+// highest common rate is recomputed on every queue change.
+func (s *Service) applySampleRatePolicy(queueState queue.State) error {
+	s.mu.Lock()
+	policy := s.sampleRatePolicy
+	backend, ok := s.backend.(sampleRateBackend)
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	queuedRates := []int(nil)
+	if policy.Mode == SampleRateHighestCommon {
+		queuedRates = s.queuedSampleRates(queueState.Entries)
+	}
+	rate, reopen := outputSampleRate(policy, queuedRates)
+
+	s.mu.Lock()
+	applied := s.sampleRateApplied && s.appliedSampleRate == rate && s.appliedSampleReopen == reopen
+	s.mu.Unlock()
+	if applied {
+		return nil
+	}
+
+	if err := backend.SetOutputSampleRate(rate, reopen); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.sampleRateApplied = true
+	s.appliedSampleRate = rate
+	s.appliedSampleReopen = reopen
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Service) queuedSampleRates(entries []library.TrackSummary) []int {
+	if s.db == nil || len(entries) == 0 {
+		return nil
+	}
+
+	rates := make([]int, 0, 1)
+	const batchSize = 500
+	for start := 0; start < len(entries); start += batchSize {
+		batch := entries[start:min(start+batchSize, len(entries))]
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(batch)), ",")
+		args := make([]any, 0, len(batch))
+		for _, entry := range batch {
+			args = append(args, entry.ID)
+		}
+
+		var rate *int
+		err := s.db.QueryRowContext(
+			context.Background(),
+			"SELECT MAX(sample_rate) FROM tracks WHERE id IN ("+placeholders+")",
+			args...,
+		).Scan(&rate)
+		if err == nil && rate != nil {
+			rates = append(rates, *rate)
+		}
+	}
+
+	return rates
+}
+
+// refreshOutputSampleRate reads the rate the output device is
+// running at for the player state.
+func (s *Service) refreshOutputSampleRate(backend playbackBackend) {
+	rateBackend, ok := backend.(sampleRateBackend)
+	if !ok {
+		return
+	}
+
+	rate, ok := rateBackend.OutputSampleRate()
+	if !ok {
+		rate = 0
+	}
+
+	s.mu.Lock()
+	s.outputSampleRate = rate
+	s.mu.Unlock()
+}
@@ -0,0 +1,42 @@
+package player
+
+import "testing"
+
+func TestNormalizeSampleRatePolicyValidatesFixedRates(t *testing.T) {
+	t.Parallel()
+
+	policy, err := normalizeSampleRatePolicy(SampleRatePolicy{Mode: " Fixed ", FixedRate: 96000})
+	if err != nil {
+		t.Fatalf("normalize sample rate policy: %v", err)
+	}
+	if policy != (SampleRatePolicy{Mode: SampleRateFixed, FixedRate: 96000}) {
+		t.Fatalf("unexpected policy: %+v", policy)
+	}
+	if policy, err := normalizeSampleRatePolicy(SampleRatePolicy{}); err != nil || policy.Mode != SampleRateFollowSource {
+		t.Fatalf("expected follow by default, got %+v %v", policy, err)
+	}
+	if _, err := normalizeSampleRatePolicy(SampleRatePolicy{Mode: SampleRateFixed, FixedRate: 12345}); err == nil {
+		t.Fatal("expected an unsupported fixed rate to be rejected")
+	}
+	if _, err := normalizeSampleRatePolicy(SampleRatePolicy{Mode: "loudest"}); err == nil {
+		t.Fatal("expected an unknown mode to be rejected")
+	}
+}
+
+func TestOutputSampleRateFollowsThePolicy(t *testing.T) {
+	t.Parallel()
+
+	queued := []int{44100, 96000, 48000}
+	if rate, reopen := outputSampleRate(SampleRatePolicy{Mode: SampleRateFollowSource}, queued); rate != 0 || !reopen {
+		t.Fatalf("expected the source rate with reopening, got %d %v", rate, reopen)
+	}
+	if rate, reopen := outputSampleRate(SampleRatePolicy{Mode: SampleRateFixed, FixedRate: 48000}, queued); rate != 48000 || reopen {
+		t.Fatalf("expected a fixed 48000, got %d %v", rate, reopen)
+	}
+	if rate, reopen := outputSampleRate(SampleRatePolicy{Mode: SampleRateHighestCommon}, queued); rate != 96000 || reopen {
+		t.Fatalf("expected the highest queued rate, got %d %v", rate, reopen)
+	}
+	if rate, _ := outputSampleRate(SampleRatePolicy{Mode: SampleRateHighestCommon}, nil); rate != 0 {
+		t.Fatalf("expected the source rate for an unknown queue, got %d", rate)
+	}
+}
@@ -1,8 +1,6 @@
 package player
 
 import (
-	"ben/internal/library"
-	"ben/internal/queue"
 	"context"
 	"database/sql"
 	"errors"
@@ -12,6 +10,11 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/rzxx/ben/internal/eventbus"
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/queue"
+	"github.com/rzxx/ben/internal/settings"
 )
 
 const EventStateChanged = "player:state"
@@ -41,81 +44,225 @@ const mpvPauseProperty = "pause"
 type Emitter func(eventName string, payload any)
 
 type State struct {
-	Status       string                `json:"status"`
-	PositionMS   int                   `json:"positionMs"`
-	Volume       int                   `json:"volume"`
-	CurrentTrack *library.TrackSummary `json:"currentTrack,omitempty"`
-	CurrentIndex int                   `json:"currentIndex"`
-	QueueLength  int                   `json:"queueLength"`
-	DurationMS   *int                  `json:"durationMs,omitempty"`
-	UpdatedAt    string                `json:"updatedAt"`
+	Status           string                `json:"status"`
+	PositionMS       int                   `json:"positionMs"`
+	Volume           int                   `json:"volume"`
+	CurrentTrack     *library.TrackSummary `json:"currentTrack,omitempty"`
+	CurrentIndex     int                   `json:"currentIndex"`
+	QueueLength      int                   `json:"queueLength"`
+	DurationMS       *int                  `json:"durationMs,omitempty"`
+	CrossfadeMS      int                   `json:"crossfadeMs"`
+	ContinuousMix    bool                  `json:"continuousMix"`
+	QuietHoursActive bool                  `json:"quietHoursActive"`
+	OutputSampleRate int                   `json:"outputSampleRate,omitempty"`
+	ReplayGainMode   string                `json:"replayGainMode"`
+	SleepTimer       *SleepTimerState      `json:"sleepTimer,omitempty"`
+	StopAfterCurrent bool                  `json:"stopAfterCurrent"`
+	Transcode        *TranscodeState       `json:"transcode,omitempty"`
+	Leveling         *LevelingState        `json:"leveling,omitempty"`
+	UpdatedAt        string                `json:"updatedAt"`
 }
 
 type Service struct {
-	mu             sync.Mutex
-	db             *sql.DB
-	queue          *queue.Service
-	status         string
-	positionMS     int
-	volume         int
-	durationMS     *int
-	updatedAt      time.Time
-	emit           Emitter
-	tickStop       chan struct{}
-	hasCurrent     bool
-	currentTrackID int64
-	backend        playbackBackend
-	backendErr     string
-	skipQueueSync  int
-	hasPreloaded   bool
-	preloadedTrack int64
+	mu                    sync.Mutex
+	db                    *sql.DB
+	queue                 *queue.Service
+	status                string
+	positionMS            int
+	volume                int
+	durationMS            *int
+	updatedAt             time.Time
+	emit                  Emitter
+	resolvePath           PathResolver
+	resolvedPaths         map[string]string
+	tickStop              chan struct{}
+	hasCurrent            bool
+	currentTrackID        int64
+	backend               playbackBackend
+	backendErr            string
+	skipQueueSync         int
+	hasPreloaded          bool
+	preloadedTrack        int64
+	crossfadeMS           int
+	appliedVolume         int
+	fadeOutTrackID        int64
+	fadeInTrackID         int64
+	continuousMix         ContinuousMixResolver
+	mixCache              map[int64]bool
+	durationChecked       map[int64]struct{}
+	settings              *settings.Store
+	quietHours            QuietHours
+	volumeOffset          VolumeOffsetResolver
+	offsetCache           map[int64]float64
+	trackTrims            TrackTrimResolver
+	trimCache             map[int64]TrackTrim
+	edgeSilence           EdgeSilenceResolver
+	accessibility         AudioAccessibility
+	equalizer             Equalizer
+	savedBackendOptions   BackendOptions
+	appliedBackendOptions BackendOptions
+	backendOptionsErr     string
+	resumeStore           ResumeStore
+	resumeProgress        resumeProgress
+	sampleRatePolicy      SampleRatePolicy
+	sampleRateApplied     bool
+	appliedSampleRate     int
+	appliedSampleReopen   bool
+	outputSampleRate      int
+	replayGainMode        string
+	sleepTimer            *time.Timer
+	sleepGeneration       uint64
+	sleepStartedAt        time.Time
+	sleepDeadline         time.Time
+	sleepFadeOut          bool
+	stopAfterCurrent      bool
+	transcodePreferences  TranscodePreferences
+	transcodes            map[int64]transcodedFile
+	transcodeOrder        []int64
+	transcodeDir          string
+	transcodeJob          *TranscodeState
+	transcodeJobTrackID   int64
+	diagnosticsEnabled    bool
+	levelingEnabled       bool
+	levelingGains         map[int64]levelingGain
+	pendingLoad           *pendingLoad
+	stalls                stallTracker
+
+	previewBackend    playbackBackend
+	previewActive     bool
+	previewResumeMain bool
+	previewGeneration uint64
+	previewTimer      *time.Timer
+	preview           PreviewState
 }
 
-func NewService(database *sql.DB, queueService *queue.Service) *Service {
+func NewService(database *sql.DB, queueService *queue.Service, bus *eventbus.Bus) *Service {
 	service := &Service{
 		db:     database,
+		emit:   bus.Publish,
 		queue:  queueService,
 		status: StatusIdle,
 		volume: defaultVolume,
 	}
+	if database != nil {
+		service.settings = settings.NewStore(database)
+	}
 
-	service.loadPlaybackStateSnapshot()
+	service.loadPlaybackStateSnapshot(false)
+	service.loadQuietHours()
+	service.loadAudioAccessibility()
+	service.loadEqualizer()
+	service.loadBackendOptions()
+	service.loadSampleRatePolicy()
+	service.loadReplayGainMode()
+	service.loadTranscodePreferences()
+	service.loadPlaybackDiagnostics()
+	service.loadSmartLeveling()
 
-	backend, err := newPlaybackBackend()
+	backend, err := service.startBackend()
 	if err != nil {
 		service.backendErr = err.Error()
 	} else {
 		service.backend = backend
 		service.backend.SetOnEOF(service.onBackendEOF)
 		service.backend.SetOnTrackStart(service.onBackendTrackStart)
-		_ = service.backend.SetVolume(service.volume)
+		if reporter, ok := service.backend.(loadErrorBackend); ok {
+			reporter.SetOnLoadError(service.onBackendLoadError)
+		}
+		service.appliedVolume = service.cappedVolumeLocked(service.volume, time.Now())
+		_ = service.backend.SetVolume(service.appliedVolume)
+		if err := service.backend.SetAudioFilter(service.audioFilterChainLocked()); err != nil && service.appliedBackendOptions.Filters != "" {
+			service.backendOptionsErr = err.Error()
+			service.appliedBackendOptions.Filters = ""
+			_ = service.backend.SetAudioFilter(service.audioFilterChainLocked())
+		}
+		applyReplayGain(service.backend, service.replayGainMode)
 	}
 
 	if queueService != nil {
+		_ = service.applySampleRatePolicy(queueService.GetState())
 		queueService.SetOnChange(service.onQueueChanged)
 	}
 
 	return service
 }
 
+// HydrateQueue loads the saved queue of a deferred queue service and restores
+// the paused position on its current track. Nothing is restored once
+// playback started before hydration finished.
+func (s *Service) HydrateQueue() State {
+	if s.queue == nil {
+		return s.GetState()
+	}
+
+	queueState, loaded := s.queue.Hydrate()
+	if !loaded {
+		return s.GetState()
+	}
+
+	s.mu.Lock()
+	untouched := s.status == StatusIdle && !s.hasCurrent
+	s.mu.Unlock()
+	if untouched {
+		s.loadPlaybackStateSnapshot(true)
+	}
+	_ = s.applySampleRatePolicy(queueState)
+
+	state := s.stateFromQueue(queueState)
+	s.emitState(state)
+	return state
+}
+
 func (s *Service) Close() error {
 	s.mu.Lock()
 	s.stopTickerLocked()
+	if s.previewTimer != nil {
+		s.previewTimer.Stop()
+		s.previewTimer = nil
+	}
+	s.stopSleepTimerLocked()
 	backend := s.backend
 	s.backend = nil
+	previewBackend := s.previewBackend
+	s.previewBackend = nil
+	s.previewActive = false
 	s.mu.Unlock()
 
+	if previewBackend != nil {
+		_ = previewBackend.Close()
+	}
+
+	var err error
 	if backend != nil {
-		return backend.Close()
+		err = backend.Close()
 	}
+	s.removeTranscodes()
 
-	return nil
+	return err
+}
+
+// Shutdown saves the playback position, including the resume position of
+// the current track, and closes the backends.
+func (s *Service) Shutdown() error {
+	if backend := s.tryBackend(); backend != nil {
+		s.refreshPlaybackPosition(backend)
+	}
+
+	state := s.GetState()
+	s.persistPlaybackState(state)
+	// Resume positions of a playing track are throttled; a stopped state
+	// is always written.
+	stopped := state
+	stopped.Status = StatusPaused
+	s.recordResumePosition(stopped)
+
+	return s.Close()
 }
 
-func (s *Service) SetEmitter(emitter Emitter) {
+func (s *Service) SetPathResolver(resolver PathResolver) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.emit = emitter
+	s.resolvePath = resolver
 }
 
 func (s *Service) GetState() State {
@@ -129,6 +276,8 @@ func (s *Service) Play() (State, error) {
 		return s.GetState(), err
 	}
 
+	s.finishPreview(0, true)
+
 	queueState := s.queue.GetState()
 	if queueState.Total == 0 {
 		return s.stateFromQueue(queueState), errors.New("queue is empty")
@@ -155,8 +304,9 @@ func (s *Service) Play() (State, error) {
 		return s.GetState(), err
 	}
 	s.syncPreloadedNext(backend, queueState)
+	s.applyCrossfade(backend)
 
-	if err := backend.Play(); err != nil {
+	if err := s.playBackend(backend); err != nil {
 		return s.GetState(), fmt.Errorf("start playback: %w", err)
 	}
 
@@ -239,7 +389,7 @@ func (s *Service) Next() (State, error) {
 	s.syncPreloadedNext(backend, queueState)
 
 	if wasPlaying {
-		if err := backend.Play(); err != nil {
+		if err := s.playBackend(backend); err != nil {
 			return s.GetState(), fmt.Errorf("start next track: %w", err)
 		}
 	} else {
@@ -297,7 +447,7 @@ func (s *Service) Previous() (State, error) {
 	s.syncPreloadedNext(backend, queueState)
 
 	if wasPlaying {
-		if err := backend.Play(); err != nil {
+		if err := s.playBackend(backend); err != nil {
 			return s.GetState(), fmt.Errorf("start previous track: %w", err)
 		}
 	} else {
@@ -370,9 +520,12 @@ func (s *Service) Seek(positionMS int) (State, error) {
 		return state, nil
 	}
 
+	seekStartedAt := time.Now()
 	if err := s.applySeekWithRetry(backend, positionMS); err != nil {
+		s.recordDiagnostic(queueState.CurrentTrack.ID, DiagnosticSeek, time.Since(seekStartedAt), s.backendPath(queueState.CurrentTrack), err.Error())
 		return s.GetState(), fmt.Errorf("seek playback: %w", err)
 	}
+	s.recordDiagnostic(queueState.CurrentTrack.ID, DiagnosticSeek, time.Since(seekStartedAt), s.backendPath(queueState.CurrentTrack), "")
 
 	if status == StatusPlaying {
 		s.refreshPlaybackPosition(backend)
@@ -390,12 +543,19 @@ func (s *Service) SetVolume(volume int) (State, error) {
 
 	volume = clampVolume(volume)
 
-	if err := backend.SetVolume(volume); err != nil {
+	s.mu.Lock()
+	target := s.cappedVolumeLocked(volume, time.Now())
+	currentTrackID := s.currentTrackID
+	s.mu.Unlock()
+	target = applyVolumeOffset(target, s.volumeOffsetDB(currentTrackID)+s.levelingGainDB(currentTrackID))
+
+	if err := backend.SetVolume(target); err != nil {
 		return s.GetState(), fmt.Errorf("set volume: %w", err)
 	}
 
 	s.mu.Lock()
 	s.volume = volume
+	s.appliedVolume = target
 	s.updatedAt = time.Now().UTC()
 	s.mu.Unlock()
 
@@ -414,12 +574,14 @@ func (s *Service) onQueueChanged(queueState queue.State) {
 		return
 	}
 
+	duration := s.trackDuration(queueState.CurrentTrack)
+
 	s.mu.Lock()
 	previousStatus := s.status
 	trackChanged := !s.hasCurrent || s.currentTrackID != queueState.CurrentTrack.ID
 	if trackChanged {
 		s.positionMS = 0
-		s.durationMS = trackDuration(queueState.CurrentTrack)
+		s.durationMS = duration
 	}
 	s.setCurrentTrackLocked(queueState.CurrentTrack, false)
 	s.updatedAt = time.Now().UTC()
@@ -429,10 +591,13 @@ func (s *Service) onQueueChanged(queueState queue.State) {
 	s.mu.Unlock()
 
 	backend := s.tryBackend()
+	if backend != nil {
+		_ = s.applySampleRatePolicy(queueState)
+	}
 	if backend != nil && trackChanged {
 		if err := s.loadTrack(backend, queueState.CurrentTrack, true); err == nil {
 			if previousStatus == StatusPlaying {
-				_ = backend.Play()
+				_ = s.playBackend(backend)
 			} else {
 				_ = backend.Pause()
 			}
@@ -463,6 +628,11 @@ func (s *Service) onBackendEOF() {
 	}
 	s.mu.Unlock()
 
+	if s.consumeStopAfterCurrent() {
+		s.stopAtTrackEnd(backend)
+		return
+	}
+
 	restore := s.beginQueueMutation()
 	queueState, moved := s.queue.AdvanceAutoplay()
 	restore()
@@ -476,10 +646,12 @@ func (s *Service) onBackendEOF() {
 	s.mu.Unlock()
 
 	if useGaplessTransition {
+		duration := s.trackDuration(queueState.CurrentTrack)
+
 		s.mu.Lock()
 		s.status = StatusPlaying
 		s.positionMS = 0
-		s.durationMS = trackDuration(queueState.CurrentTrack)
+		s.durationMS = duration
 		s.setCurrentTrackLocked(queueState.CurrentTrack, false)
 		s.hasPreloaded = false
 		s.preloadedTrack = 0
@@ -496,7 +668,7 @@ func (s *Service) onBackendEOF() {
 	}
 	s.syncPreloadedNext(backend, queueState)
 
-	if err := backend.Play(); err != nil {
+	if err := s.playBackend(backend); err != nil {
 		return
 	}
 
@@ -516,14 +688,16 @@ func (s *Service) onBackendTrackStart(path string) {
 		return
 	}
 
+	s.finishLoadDiagnostic(trimmedPath, nil)
+
 	queueState := s.queue.GetState()
 	if queueState.CurrentTrack == nil {
 		return
 	}
 
-	if !sameTrackPath(queueState.CurrentTrack.Path, trimmedPath) {
+	if !sameTrackPath(s.backendPath(queueState.CurrentTrack), trimmedPath) {
 		nextTrack, ok := s.queue.PeekAutoplayNext()
-		if !ok || nextTrack == nil || !sameTrackPath(nextTrack.Path, trimmedPath) {
+		if !ok || nextTrack == nil || !sameTrackPath(s.backendPath(nextTrack), trimmedPath) {
 			return
 		}
 
@@ -536,10 +710,12 @@ func (s *Service) onBackendTrackStart(path string) {
 		queueState = advancedState
 	}
 
+	duration := s.trackDuration(queueState.CurrentTrack)
+
 	s.mu.Lock()
 	s.setCurrentTrackLocked(queueState.CurrentTrack, false)
 	s.positionMS = 0
-	s.durationMS = trackDuration(queueState.CurrentTrack)
+	s.durationMS = duration
 	s.updatedAt = time.Now().UTC()
 	s.hasPreloaded = false
 	s.preloadedTrack = 0
@@ -549,12 +725,16 @@ func (s *Service) onBackendTrackStart(path string) {
 	if backend != nil {
 		s.syncPreloadedNext(backend, queueState)
 		s.refreshPlaybackPosition(backend)
+		s.applyCrossfade(backend)
 	}
 
 	s.emitState(s.stateFromQueue(queueState))
 }
 
-func (s *Service) loadPlaybackStateSnapshot() {
+// loadPlaybackStateSnapshot restores the saved status and position for the
+// queue's current track. With positionOnly the saved volume and crossfade are
+// left alone.
+func (s *Service) loadPlaybackStateSnapshot(positionOnly bool) {
 	if s.db == nil || s.queue == nil {
 		return
 	}
@@ -564,13 +744,14 @@ func (s *Service) loadPlaybackStateSnapshot() {
 		statusValue    sql.NullString
 		positionMS     sql.NullInt64
 		volume         sql.NullInt64
+		crossfadeMS    sql.NullInt64
 		updatedAt      sql.NullString
 	)
 
 	err := s.db.QueryRowContext(
 		context.Background(),
-		"SELECT current_track_id, status, position_ms, volume, updated_at FROM playback_state WHERE id = 1",
-	).Scan(&currentTrackID, &statusValue, &positionMS, &volume, &updatedAt)
+		"SELECT current_track_id, status, position_ms, volume, crossfade_ms, updated_at FROM playback_state WHERE id = 1",
+	).Scan(&currentTrackID, &statusValue, &positionMS, &volume, &crossfadeMS, &updatedAt)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return
 	}
@@ -600,7 +781,7 @@ func (s *Service) loadPlaybackStateSnapshot() {
 		}
 	}
 
-	loadedDuration := trackDuration(queueState.CurrentTrack)
+	loadedDuration := s.trackDuration(queueState.CurrentTrack)
 	if loadedDuration != nil && loadedPosition > *loadedDuration {
 		loadedPosition = *loadedDuration
 	}
@@ -614,9 +795,14 @@ func (s *Service) loadPlaybackStateSnapshot() {
 	s.mu.Lock()
 	s.status = loadedStatus
 	s.positionMS = loadedPosition
-	s.volume = loadedVolume
 	s.durationMS = loadedDuration
 	s.updatedAt = loadedUpdatedAt
+	if !positionOnly {
+		s.volume = loadedVolume
+		if crossfadeMS.Valid && crossfadeMS.Int64 > 0 && crossfadeMS.Int64 <= maxCrossfadeMS {
+			s.crossfadeMS = int(crossfadeMS.Int64)
+		}
+	}
 	s.mu.Unlock()
 }
 
@@ -653,19 +839,33 @@ func (s *Service) loadTrack(backend playbackBackend, track *library.TrackSummary
 		return nil
 	}
 
-	if err := backend.Load(track.Path); err != nil {
+	path, err := s.resolveBackendPath(backend, track)
+	if err != nil {
 		return fmt.Errorf("load track %q: %w", track.Path, err)
 	}
+	s.beginLoadDiagnostic(track.ID, path)
+	if err := s.loadBackendTrack(backend, track, path); err != nil {
+		s.finishLoadDiagnostic(path, err)
+		return fmt.Errorf("load track %q: %w", track.Path, err)
+	}
+	duration := s.trackDuration(track)
 
 	s.mu.Lock()
 	s.setCurrentTrackLocked(track, false)
 	s.positionMS = 0
-	s.durationMS = trackDuration(track)
+	s.durationMS = duration
 	s.hasPreloaded = false
 	s.preloadedTrack = 0
+	s.fadeOutTrackID = 0
+	s.fadeInTrackID = 0
 	s.updatedAt = time.Now().UTC()
 	s.mu.Unlock()
 
+	if resumePositionMS := s.resumePosition(track.ID); resumePositionMS > 0 || s.trackTrim(track.ID).StartMS > 0 {
+		_ = s.applySeekWithRetry(backend, resumePositionMS)
+	}
+
+	s.applyCrossfade(backend)
 	return nil
 }
 
@@ -673,9 +873,17 @@ func (s *Service) syncPreloadedNext(backend playbackBackend, queueState queue.St
 	if backend == nil || queueState.CurrentTrack == nil {
 		return
 	}
+	s.precomputeLeveling(queueState)
+
+	// A trimmed transition is driven by the player, so it cannot use the
+	// backend's gapless switch to a preloaded file, and stop after current
+	// needs the backend to stop at the end of the file.
+	s.mu.Lock()
+	stopAfterCurrent := s.stopAfterCurrent
+	s.mu.Unlock()
 
 	nextTrack, ok := s.queue.PeekAutoplayNext()
-	if !ok || nextTrack == nil {
+	if stopAfterCurrent || !ok || nextTrack == nil || s.trackTrim(queueState.CurrentTrack.ID).EndMS > 0 || s.trackTrim(nextTrack.ID).StartMS > 0 {
 		_ = backend.ClearPreloadedNext()
 		s.mu.Lock()
 		s.hasPreloaded = false
@@ -684,7 +892,11 @@ func (s *Service) syncPreloadedNext(backend playbackBackend, queueState queue.St
 		return
 	}
 
-	if err := backend.PreloadNext(nextTrack.Path); err != nil {
+	nextPath, err := s.resolveBackendPath(backend, nextTrack)
+	if err == nil {
+		err = backend.PreloadNext(nextPath)
+	}
+	if err != nil {
 		s.mu.Lock()
 		s.hasPreloaded = false
 		s.preloadedTrack = 0
@@ -698,20 +910,30 @@ func (s *Service) syncPreloadedNext(backend playbackBackend, queueState queue.St
 	s.mu.Unlock()
 }
 
-func (s *Service) refreshPlaybackPosition(backend playbackBackend) {
+// refreshPlaybackPosition reads the position from the backend and reports
+// whether it passed the end trim of the current track.
+func (s *Service) refreshPlaybackPosition(backend playbackBackend) bool {
 	positionMS, positionErr := backend.PositionMS()
 	durationMS, durationErr := backend.DurationMS()
+	s.refreshOutputSampleRate(backend)
+
+	s.mu.Lock()
+	currentTrackID := s.currentTrackID
+	s.mu.Unlock()
+	trim := s.trackTrim(currentTrackID)
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	if positionErr == nil {
-		s.positionMS = positionMS
+		s.positionMS = max(positionMS-trim.StartMS, 0)
 	}
 	if durationErr == nil {
-		s.durationMS = durationMS
+		s.durationMS = trimmedDuration(durationMS, trim)
 	}
 	s.updatedAt = time.Now().UTC()
+
+	return positionErr == nil && durationErr == nil && trim.endsEarly(durationMS) && positionMS >= trim.EndMS
 }
 
 func (s *Service) applySeekWithRetry(backend playbackBackend, targetPositionMS int) error {
@@ -719,9 +941,14 @@ func (s *Service) applySeekWithRetry(backend playbackBackend, targetPositionMS i
 		targetPositionMS = 0
 	}
 
+	s.mu.Lock()
+	currentTrackID := s.currentTrackID
+	s.mu.Unlock()
+	startMS := s.trackTrim(currentTrackID).StartMS
+
 	var lastErr error
 	for attempt := 0; attempt < resumeSeekAttempts; attempt++ {
-		if err := backend.Seek(targetPositionMS); err != nil {
+		if err := backend.Seek(startMS + targetPositionMS); err != nil {
 			lastErr = err
 			time.Sleep(resumeSeekDelay)
 			continue
@@ -746,10 +973,16 @@ func (s *Service) transitionToIdle(queueState queue.State, backend playbackBacke
 		backend = s.tryBackend()
 	}
 
+	duration := s.trackDuration(queueState.CurrentTrack)
+
 	if backend != nil {
 		_ = backend.Pause()
 		if resetPosition {
-			_ = backend.Seek(0)
+			startMS := 0
+			if queueState.CurrentTrack != nil {
+				startMS = s.trackTrim(queueState.CurrentTrack.ID).StartMS
+			}
+			_ = backend.Seek(startMS)
 		}
 		_ = backend.ClearPreloadedNext()
 	}
@@ -768,7 +1001,7 @@ func (s *Service) transitionToIdle(queueState queue.State, backend playbackBacke
 	} else {
 		s.setCurrentTrackLocked(queueState.CurrentTrack, false)
 		if resetPosition {
-			s.durationMS = trackDuration(queueState.CurrentTrack)
+			s.durationMS = duration
 		}
 		s.hasPreloaded = false
 		s.preloadedTrack = 0
@@ -792,7 +1025,7 @@ func (s *Service) currentDuration(track *library.TrackSummary) *int {
 		return &value
 	}
 
-	return trackDuration(track)
+	return s.trackDuration(track)
 }
 
 func (s *Service) ensureTickerLocked() {
@@ -848,7 +1081,13 @@ func (s *Service) onTick() {
 		return
 	}
 
-	s.refreshPlaybackPosition(backend)
+	s.observeBuffering(backend, queueState.CurrentTrack.ID)
+	if s.refreshPlaybackPosition(backend) {
+		s.onBackendEOF()
+		return
+	}
+	s.applyCrossfade(backend)
+	s.verifyTrackDuration(queueState.CurrentTrack)
 	s.emitState(s.stateFromQueue(queueState))
 }
 
@@ -873,17 +1112,24 @@ func (s *Service) stateFromQueue(queueState queue.State) State {
 	positionMS := s.positionMS
 	volume := s.volume
 	duration := s.durationMS
+	crossfadeMS := s.crossfadeMS
 	updatedAt := s.updatedAt
+	quietHoursActive := s.quietHours.activeAt(time.Now())
+	outputSampleRate := s.outputSampleRate
+	replayGainMode := s.replayGainMode
+	sleepTimer := s.sleepTimerStateLocked(time.Now())
+	stopAfterCurrent := s.stopAfterCurrent
 	s.mu.Unlock()
 
 	if queueState.CurrentTrack == nil {
 		status = StatusIdle
 		positionMS = 0
 		duration = nil
+		outputSampleRate = 0
 	}
 
 	if duration == nil {
-		duration = trackDuration(queueState.CurrentTrack)
+		duration = s.trackDuration(queueState.CurrentTrack)
 	}
 
 	if duration != nil && positionMS > *duration {
@@ -891,17 +1137,26 @@ func (s *Service) stateFromQueue(queueState queue.State) State {
 	}
 
 	state := State{
-		Status:       status,
-		PositionMS:   positionMS,
-		Volume:       volume,
-		CurrentIndex: queueState.CurrentIndex,
-		QueueLength:  queueState.Total,
-		DurationMS:   duration,
+		Status:           status,
+		PositionMS:       positionMS,
+		Volume:           volume,
+		CurrentIndex:     queueState.CurrentIndex,
+		QueueLength:      queueState.Total,
+		DurationMS:       duration,
+		CrossfadeMS:      crossfadeMS,
+		QuietHoursActive: quietHoursActive,
+		OutputSampleRate: outputSampleRate,
+		ReplayGainMode:   replayGainMode,
+		SleepTimer:       sleepTimer,
+		StopAfterCurrent: stopAfterCurrent,
 	}
 
 	if queueState.CurrentTrack != nil {
 		track := *queueState.CurrentTrack
 		state.CurrentTrack = &track
+		state.ContinuousMix = s.isContinuousMixTrack(track.ID)
+		state.Transcode = s.transcodeStateFor(track.ID)
+		state.Leveling = s.levelingStateFor(track.ID)
 	}
 
 	if !updatedAt.IsZero() {
@@ -913,6 +1168,7 @@ func (s *Service) stateFromQueue(queueState queue.State) State {
 
 func (s *Service) emitState(state State) {
 	s.persistPlaybackState(state)
+	s.recordResumePosition(state)
 
 	s.mu.Lock()
 	emitter := s.emit
@@ -990,15 +1246,6 @@ func clampVolume(volume int) int {
 	return volume
 }
 
-func trackDuration(track *library.TrackSummary) *int {
-	if track == nil || track.DurationMS == nil {
-		return nil
-	}
-
-	value := *track.DurationMS
-	return &value
-}
-
 func normalizePlayerStatus(value string) string {
 	switch strings.ToLower(strings.TrimSpace(value)) {
 	case StatusPlaying:
@@ -0,0 +1,179 @@
+package player
+
+import (
+	"fmt"
+	"time"
+)
+
+const maxSleepTimerMinutes = 12 * 60
+
+// sleepFadeDuration is how long before the sleep timer fires the volume
+// starts fading out, shortened for timers that run less than that.
+const sleepFadeDuration = 30 * time.Second
+
+// SleepTimerState is the running sleep timer as reported in State.
+type SleepTimerState struct {
+	EndsAt      string `json:"endsAt"`
+	RemainingMS int    `json:"remainingMs"`
+	FadeOut     bool   `json:"fadeOut"`
+}
+
+// StartSleepTimer pauses playback after durationMinutes, replacing a running
+// timer. With fadeOut the volume fades to silence over the last moments.
+func (s *Service) StartSleepTimer(durationMinutes int, fadeOut bool) (State, error) {
+	if durationMinutes <= 0 || durationMinutes > maxSleepTimerMinutes {
+		return s.GetState(), fmt.Errorf("sleep timer must be between 1 and %d minutes", maxSleepTimerMinutes)
+	}
+
+	duration := time.Duration(durationMinutes) * time.Minute
+
+	s.mu.Lock()
+	s.stopSleepTimerLocked()
+	s.sleepGeneration++
+	generation := s.sleepGeneration
+	s.sleepStartedAt = time.Now()
+	s.sleepDeadline = s.sleepStartedAt.Add(duration)
+	s.sleepFadeOut = fadeOut
+	s.sleepTimer = time.AfterFunc(duration, func() {
+		s.onSleepTimer(generation)
+	})
+	s.updatedAt = time.Now().UTC()
+	s.mu.Unlock()
+
+	state := s.GetState()
+	s.emitState(state)
+	return state, nil
+}
+
+// CancelSleepTimer stops a running sleep timer and undoes its fade.
+func (s *Service) CancelSleepTimer() State {
+	s.mu.Lock()
+	s.stopSleepTimerLocked()
+	s.updatedAt = time.Now().UTC()
+	s.mu.Unlock()
+
+	if backend := s.tryBackend(); backend != nil {
+		s.applyCrossfade(backend)
+	}
+
+	state := s.GetState()
+	s.emitState(state)
+	return state
+}
+
+// SetStopAfterCurrent pauses playback once the current track ends, with the
+// next track loaded so playing again continues the queue. The flag clears
+// itself when it fires.
+func (s *Service) SetStopAfterCurrent(enabled bool) State {
+	s.mu.Lock()
+	s.stopAfterCurrent = enabled
+	s.updatedAt = time.Now().UTC()
+	s.mu.Unlock()
+
+	// The next track must not start gaplessly while the flag is set.
+	if backend := s.tryBackend(); backend != nil {
+		s.syncPreloadedNext(backend, s.queue.GetState())
+	}
+
+	state := s.GetState()
+	s.emitState(state)
+	return state
+}
+
+func (s *Service) onSleepTimer(generation uint64) {
+	s.mu.Lock()
+	if generation != s.sleepGeneration || s.sleepTimer == nil {
+		s.mu.Unlock()
+		return
+	}
+	s.sleepTimer = nil
+	s.stopSleepTimerLocked()
+	playing := s.status == StatusPlaying
+	s.updatedAt = time.Now().UTC()
+	s.mu.Unlock()
+
+	if playing {
+		_, _ = s.Pause()
+	} else {
+		s.emitState(s.GetState())
+	}
+
+	// Restore the volume the fade lowered, ready for the next play.
+	if backend := s.tryBackend(); backend != nil {
+		s.applyCrossfade(backend)
+	}
+}
+
+func (s *Service) stopSleepTimerLocked() {
+	if s.sleepTimer != nil {
+		s.sleepTimer.Stop()
+		s.sleepTimer = nil
+	}
+	s.sleepDeadline = time.Time{}
+	s.sleepFadeOut = false
+}
+
+// sleepFadeFactorLocked is the volume factor of the sleep fade at now,
+// 1 when no fading timer is near its end.
+func (s *Service) sleepFadeFactorLocked(now time.Time) float64 {
+	if !s.sleepFadeOut || s.sleepDeadline.IsZero() {
+		return 1
+	}
+
+	fade := min(sleepFadeDuration, s.sleepDeadline.Sub(s.sleepStartedAt))
+	remaining := s.sleepDeadline.Sub(now)
+	if fade <= 0 || remaining >= fade {
+		return 1
+	}
+
+	return float64(max(remaining, 0)) / float64(fade)
+}
+
+func (s *Service) sleepTimerStateLocked(now time.Time) *SleepTimerState {
+	if s.sleepDeadline.IsZero() {
+		return nil
+	}
+
+	return &SleepTimerState{
+		EndsAt:      s.sleepDeadline.UTC().Format(time.RFC3339),
+		RemainingMS: int(max(s.sleepDeadline.Sub(now), 0) / time.Millisecond),
+		FadeOut:     s.sleepFadeOut,
+	}
+}
+
+// consumeStopAfterCurrent reports whether playback should stop at the end of
+// the current track, clearing the flag.
+func (s *Service) consumeStopAfterCurrent() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stop := s.stopAfterCurrent
+	s.stopAfterCurrent = false
+	return stop
+}
+
+// stopAtTrackEnd pauses with the next queued track loaded, or goes idle at
+// the end of the queue.
+func (s *Service) stopAtTrackEnd(backend playbackBackend) {
+	restore := s.beginQueueMutation()
+	queueState, moved := s.queue.AdvanceAutoplay()
+	restore()
+	if !moved {
+		s.transitionToIdle(queueState, backend, true)
+		return
+	}
+
+	if err := s.loadTrack(backend, queueState.CurrentTrack, true); err != nil {
+		s.transitionToIdle(queueState, backend, true)
+		return
+	}
+	_ = backend.Pause()
+
+	s.mu.Lock()
+	s.status = StatusPaused
+	s.updatedAt = time.Now().UTC()
+	s.stopTickerLocked()
+	s.mu.Unlock()
+
+	s.refreshPlaybackPosition(backend)
+	s.emitState(s.stateFromQueue(queueState))
+}
@@ -0,0 +1,49 @@
+package player
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSleepFadeFactorFadesOverTheLastSeconds(t *testing.T) {
+	t.Parallel()
+
+	startedAt := time.Date(2026, 1, 1, 22, 0, 0, 0, time.UTC)
+	service := &Service{
+		sleepStartedAt: startedAt,
+		sleepDeadline:  startedAt.Add(10 * time.Minute),
+		sleepFadeOut:   true,
+	}
+
+	if got := service.sleepFadeFactorLocked(startedAt.Add(5 * time.Minute)); got != 1 {
+		t.Fatalf("expected full volume before the fade, got %v", got)
+	}
+	if got := service.sleepFadeFactorLocked(service.sleepDeadline.Add(-15 * time.Second)); got != 0.5 {
+		t.Fatalf("expected half volume halfway through the fade, got %v", got)
+	}
+	if got := service.sleepFadeFactorLocked(service.sleepDeadline.Add(time.Second)); got != 0 {
+		t.Fatalf("expected silence past the deadline, got %v", got)
+	}
+
+	service.sleepFadeOut = false
+	if got := service.sleepFadeFactorLocked(service.sleepDeadline.Add(-time.Second)); got != 1 {
+		t.Fatalf("expected no fade without fade out, got %v", got)
+	}
+}
+
+func TestSleepTimerStateReportsRemainingTime(t *testing.T) {
+	t.Parallel()
+
+	service := &Service{}
+	if state := service.sleepTimerStateLocked(time.Now()); state != nil {
+		t.Fatalf("expected no timer state, got %+v", state)
+	}
+
+	now := time.Date(2026, 1, 1, 22, 0, 0, 0, time.UTC)
+	service.sleepStartedAt = now
+	service.sleepDeadline = now.Add(90 * time.Second)
+	state := service.sleepTimerStateLocked(now.Add(30 * time.Second))
+	if state == nil || state.RemainingMS != 60000 || state.EndsAt != "2026-01-01T22:01:30Z" {
+		t.Fatalf("expected a minute remaining, got %+v", state)
+	}
+}
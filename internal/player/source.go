@@ -0,0 +1,174 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+// maxResolvedPaths bounds the cache of resolved track locations.
+const maxResolvedPaths = 512
+
+// PlaybackSource is what the backend opens for a track: a file or a stream
+// URL, and the HTTP headers to send with its requests, such as the
+// authorization of a remote root. Credentials travel in headers so they
+// never show up in the URL the backend logs and reports back.
+type PlaybackSource struct {
+	Path    string
+	Headers []string
+}
+
+// PathResolver maps a stored track path to the source the backend opens.
+type PathResolver func(path string) (PlaybackSource, error)
+
+// headerBackend is implemented by backends that can send HTTP headers with
+// the requests for a file.
+type headerBackend interface {
+	SetFileHeaders(path string, headers []string)
+}
+
+// playbackPath resolves a stored path for backend and hands the backend the
+// headers of the source. The result is cached so the backend's track start
+// events can be matched against it without resolving again.
+func (s *Service) playbackPath(backend playbackBackend, path string) (string, error) {
+	source, err := s.playbackSource(path)
+	if err != nil {
+		return "", err
+	}
+
+	if headered, ok := backend.(headerBackend); ok {
+		headered.SetFileHeaders(source.Path, source.Headers)
+	}
+
+	return source.Path, nil
+}
+
+func (s *Service) playbackSource(path string) (PlaybackSource, error) {
+	s.mu.Lock()
+	resolver := s.resolvePath
+	s.mu.Unlock()
+
+	if resolver == nil {
+		return PlaybackSource{Path: path}, nil
+	}
+
+	source, err := resolver(path)
+	if err != nil {
+		return PlaybackSource{}, fmt.Errorf("resolve %s: %w", path, err)
+	}
+
+	s.mu.Lock()
+	if s.resolvedPaths == nil || len(s.resolvedPaths) >= maxResolvedPaths {
+		s.resolvedPaths = make(map[string]string)
+	}
+	s.resolvedPaths[path] = source.Path
+	s.mu.Unlock()
+
+	return source, nil
+}
+
+// resolvedPath returns the location a stored path was last resolved to, or
+// the path itself before it was resolved.
+func (s *Service) resolvedPath(path string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if resolved, ok := s.resolvedPaths[path]; ok {
+		return resolved
+	}
+
+	return path
+}
+
+// resolveBackendPath returns the location backend plays a track from: its
+// transcoded copy once there is one, the resolved track path otherwise.
+func (s *Service) resolveBackendPath(backend playbackBackend, track *library.TrackSummary) (string, error) {
+	s.mu.Lock()
+	transcoded, ok := s.transcodes[track.ID]
+	s.mu.Unlock()
+	if ok {
+		return transcoded.path, nil
+	}
+
+	return s.playbackPath(backend, track.Path)
+}
+
+// fetchSource copies a source that needs headers to destination, so tools
+// that would take the headers on their command line can read a local file.
+func fetchSource(ctx context.Context, source PlaybackSource, destination string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, source.Path, nil)
+	if err != nil {
+		return fmt.Errorf("build request for %s: %w", source.Path, err)
+	}
+	for _, header := range source.Headers {
+		name, value, ok := strings.Cut(header, ":")
+		if ok {
+			request.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("download %s: %w", source.Path, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("download %s: %s", source.Path, response.Status)
+	}
+
+	file, err := os.Create(destination)
+	if err != nil {
+		return fmt.Errorf("create download file: %w", err)
+	}
+	if _, err := io.Copy(file, response.Body); err != nil {
+		file.Close()
+		return fmt.Errorf("download %s: %w", source.Path, err)
+	}
+
+	return file.Close()
+}
+
+var mpvVersionPattern = regexp.MustCompile(`(\d+)\.(\d+)`)
+
+// mpvLoadfileTakesIndex reports whether a libmpv version expects an
+// insertion index before the per-file options of loadfile, which mpv 0.38
+// added.
+func mpvLoadfileTakesIndex(version string) bool {
+	match := mpvVersionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return true
+	}
+
+	major, _ := strconv.Atoi(match[1])
+	minor, _ := strconv.Atoi(match[2])
+	return major > 0 || minor >= 38
+}
+
+// mpvLoadfileArgs builds a loadfile command that sends headers with the
+// requests for path. Commas inside a header are escaped so they do not
+// split the header list, and the value is length-quoted for the option
+// list around it.
+func mpvLoadfileArgs(path string, flags string, headers []string, takesIndex bool) []string {
+	args := []string{"loadfile", path, flags}
+	if len(headers) == 0 {
+		return args
+	}
+
+	if takesIndex {
+		args = append(args, "-1")
+	}
+	escaped := make([]string, len(headers))
+	for i, header := range headers {
+		escaped[i] = strings.ReplaceAll(header, ",", `\,`)
+	}
+	value := strings.Join(escaped, ",")
+	return append(args, fmt.Sprintf("http-header-fields=%%%d%%%s", len(value), value))
+}
@@ -0,0 +1,47 @@
+package player
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestMpvLoadfileArgsPassHeadersAsFileOptions(t *testing.T) {
+	t.Parallel()
+
+	plain := mpvLoadfileArgs("/music/a.flac", "replace", nil, true)
+	if !slices.Equal(plain, []string{"loadfile", "/music/a.flac", "replace"}) {
+		t.Fatalf("unexpected args without headers: %v", plain)
+	}
+
+	headers := []string{"Authorization: Basic YWxpY2U6c2VjcmV0", "X-Note: a,b"}
+	value := `Authorization: Basic YWxpY2U6c2VjcmV0,X-Note: a\,b`
+	indexed := mpvLoadfileArgs("https://nas.local/a.flac", "append", headers, true)
+	want := []string{"loadfile", "https://nas.local/a.flac", "append", "-1", fmt.Sprintf("http-header-fields=%%%d%%%s", len(value), value)}
+	if !slices.Equal(indexed, want) {
+		t.Fatalf("unexpected args %v, want %v", indexed, want)
+	}
+
+	legacy := mpvLoadfileArgs("https://nas.local/a.flac", "append", headers, false)
+	if len(legacy) != 4 || legacy[3] != want[4] {
+		t.Fatalf("expected options right after the flags before mpv 0.38, got %v", legacy)
+	}
+}
+
+func TestMpvLoadfileTakesIndexFromVersion(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]bool{
+		"mpv 0.38.0":           true,
+		"mpv v0.39.0-dirty":    true,
+		"mpv 0.37.0":           false,
+		"mpv 0.34.1-ubuntu":    false,
+		"mpv 1.0.0":            true,
+		"mpv git-2024-unknown": true,
+	}
+	for version, want := range cases {
+		if got := mpvLoadfileTakesIndex(version); got != want {
+			t.Fatalf("mpvLoadfileTakesIndex(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
@@ -0,0 +1,549 @@
+package player
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+// TranscodeFallbackSettingKey stores the transcoding fallback preferences
+// as JSON.
+const TranscodeFallbackSettingKey = "player.transcode_fallback"
+
+const (
+	// TranscodeFormatFLAC converts to FLAC, which keeps the source lossless
+	// at about half the size of WAV.
+	TranscodeFormatFLAC = "flac"
+	// TranscodeFormatWAV converts to 24-bit PCM WAV for backends without a
+	// FLAC decoder.
+	TranscodeFormatWAV = "wav"
+	// TranscodeFormatOff never transcodes; the file is reported as
+	// unplayable instead.
+	TranscodeFormatOff = "off"
+)
+
+const (
+	TranscodeStatusRunning = "transcoding"
+	TranscodeStatusActive  = "active"
+	TranscodeStatusFailed  = "failed"
+)
+
+const transcodeTimeout = 5 * time.Minute
+
+// maxTranscodedFiles caps the converted files kept in the temp directory;
+// the oldest is removed first.
+const maxTranscodedFiles = 8
+
+const maxTranscodeCodecs = 64
+
+var errTranscodeUnavailable = errors.New("ffmpeg was not found on PATH")
+
+// loadErrorBackend is implemented by backends that report files they
+// accepted but could not decode after Load returned.
+type loadErrorBackend interface {
+	SetOnLoadError(callback func(path string))
+}
+
+// TranscodePreferences choose the format a file the backend cannot decode
+// is converted to with ffmpeg. Codecs overrides DefaultFormat per codec,
+// e.g. {"wma": "wav"} or {"ape": "off"}.
+type TranscodePreferences struct {
+	DefaultFormat string            `json:"defaultFormat"`
+	Codecs        map[string]string `json:"codecs"`
+}
+
+// TranscodeFallbackStatus reports the saved preferences and whether ffmpeg
+// can be found, without which the fallback never runs.
+type TranscodeFallbackStatus struct {
+	Preferences     TranscodePreferences `json:"preferences"`
+	FFmpegAvailable bool                 `json:"ffmpegAvailable"`
+	FFmpegPath      string               `json:"ffmpegPath,omitempty"`
+}
+
+// TranscodeState tells that the current track plays, or is being prepared
+// to play, from a transcoded copy. Error is set when the fallback failed.
+type TranscodeState struct {
+	Status string `json:"status"`
+	Codec  string `json:"codec,omitempty"`
+	Format string `json:"format"`
+	Error  string `json:"error,omitempty"`
+}
+
+type transcodedFile struct {
+	path   string
+	codec  string
+	format string
+}
+
+func defaultTranscodePreferences() TranscodePreferences {
+	return TranscodePreferences{DefaultFormat: TranscodeFormatFLAC, Codecs: map[string]string{}}
+}
+
+func normalizeTranscodeFormat(format string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(format))
+	switch normalized {
+	case "":
+		return TranscodeFormatFLAC, nil
+	case TranscodeFormatFLAC, TranscodeFormatWAV, TranscodeFormatOff:
+		return normalized, nil
+	default:
+		return "", fmt.Errorf("unknown transcode format %q", format)
+	}
+}
+
+func normalizeTranscodeCodec(codec string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(codec), "."))
+}
+
+func normalizeTranscodePreferences(preferences TranscodePreferences) (TranscodePreferences, error) {
+	defaultFormat, err := normalizeTranscodeFormat(preferences.DefaultFormat)
+	if err != nil {
+		return TranscodePreferences{}, err
+	}
+	if len(preferences.Codecs) > maxTranscodeCodecs {
+		return TranscodePreferences{}, fmt.Errorf("at most %d codec preferences are allowed", maxTranscodeCodecs)
+	}
+
+	normalized := TranscodePreferences{DefaultFormat: defaultFormat, Codecs: make(map[string]string, len(preferences.Codecs))}
+	for codec, format := range preferences.Codecs {
+		key := normalizeTranscodeCodec(codec)
+		if key == "" {
+			return TranscodePreferences{}, errors.New("codec name is required")
+		}
+		if _, duplicate := normalized.Codecs[key]; duplicate {
+			return TranscodePreferences{}, fmt.Errorf("codec %q is set twice", key)
+		}
+		value, err := normalizeTranscodeFormat(format)
+		if err != nil {
+			return TranscodePreferences{}, fmt.Errorf("codec %q: %w", key, err)
+		}
+		normalized.Codecs[key] = value
+	}
+
+	return normalized, nil
+}
+
+// transcodeFormatFor returns the format files of codec are converted to,
+// or TranscodeFormatOff.
+func transcodeFormatFor(preferences TranscodePreferences, codec string) string {
+	if format, ok := preferences.Codecs[normalizeTranscodeCodec(codec)]; ok {
+		return format
+	}
+	if preferences.DefaultFormat == "" {
+		return TranscodeFormatFLAC
+	}
+
+	return preferences.DefaultFormat
+}
+
+// ffmpegTranscodeArgs converts the first audio stream of source to target.
+// WAV is written as 24-bit PCM so high resolution sources are not cut to
+// 16 bits.
+func ffmpegTranscodeArgs(source string, target string, format string) []string {
+	codec := "flac"
+	if format == TranscodeFormatWAV {
+		codec = "pcm_s24le"
+	}
+
+	return []string{
+		"-nostdin", "-hide_banner", "-loglevel", "error", "-y",
+		"-i", source,
+		"-map", "0:a:0", "-vn",
+		"-c:a", codec,
+		"-f", format,
+		target,
+	}
+}
+
+func findFFmpeg() (string, bool) {
+	path, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return "", false
+	}
+
+	return path, true
+}
+
+func (s *Service) GetTranscodeFallback() TranscodeFallbackStatus {
+	s.mu.Lock()
+	preferences := s.transcodePreferences
+	s.mu.Unlock()
+
+	status := TranscodeFallbackStatus{Preferences: preferences}
+	status.FFmpegPath, status.FFmpegAvailable = findFFmpeg()
+	return status
+}
+
+// SetTranscodeFallback saves the preferences. Files already converted keep
+// playing from their copy until they leave the cache.
+func (s *Service) SetTranscodeFallback(preferences TranscodePreferences) (TranscodeFallbackStatus, error) {
+	normalized, err := normalizeTranscodePreferences(preferences)
+	if err != nil {
+		return s.GetTranscodeFallback(), err
+	}
+
+	if s.settings != nil {
+		encoded, err := json.Marshal(normalized)
+		if err != nil {
+			return s.GetTranscodeFallback(), fmt.Errorf("encode transcode preferences: %w", err)
+		}
+		if err := s.settings.Set(context.Background(), TranscodeFallbackSettingKey, string(encoded)); err != nil {
+			return s.GetTranscodeFallback(), err
+		}
+	}
+
+	s.mu.Lock()
+	s.transcodePreferences = normalized
+	s.mu.Unlock()
+
+	return s.GetTranscodeFallback(), nil
+}
+
+func (s *Service) loadTranscodePreferences() {
+	s.transcodePreferences = defaultTranscodePreferences()
+	if s.settings == nil {
+		return
+	}
+
+	raw, ok, err := s.settings.Get(context.Background(), TranscodeFallbackSettingKey)
+	if err != nil || !ok {
+		return
+	}
+
+	var stored TranscodePreferences
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+		return
+	}
+	if normalized, err := normalizeTranscodePreferences(stored); err == nil {
+		s.transcodePreferences = normalized
+	}
+}
+
+// backendPath returns the location the backend plays a track from: its
+// transcoded copy once there is one. It does not resolve the track again,
+// so it is cheap enough to match backend events with.
+func (s *Service) backendPath(track *library.TrackSummary) string {
+	s.mu.Lock()
+	transcoded, ok := s.transcodes[track.ID]
+	s.mu.Unlock()
+	if ok {
+		return transcoded.path
+	}
+
+	return s.resolvedPath(track.Path)
+}
+
+// loadBackendTrack loads a track from its resolved path into the backend.
+// When the backend rejects it right away, a transcoded copy is prepared in
+// the background, as for a file that fails after loading, so Play and Next
+// return at once and the state shows the conversion until the copy loads.
+func (s *Service) loadBackendTrack(backend playbackBackend, track *library.TrackSummary, path string) error {
+	loadErr := backend.Load(path)
+	if loadErr == nil {
+		return nil
+	}
+	if !s.startTranscodeFallback(backend, track) {
+		return loadErr
+	}
+
+	// The rejected load may leave the previous file in the backend.
+	_ = backend.Pause()
+	_ = backend.ClearPreloadedNext()
+	return nil
+}
+
+// onBackendLoadError runs the fallback for a file the backend opened but
+// could not decode. The conversion runs in the background; the track is
+// reloaded from its copy when the conversion finishes and it is still
+// current.
+func (s *Service) onBackendLoadError(path string) {
+	backend := s.tryBackend()
+	if backend == nil || s.queue == nil {
+		return
+	}
+	s.finishLoadDiagnostic(strings.TrimSpace(path), errors.New("backend could not decode the file"))
+
+	queueState := s.queue.GetState()
+	track := queueState.CurrentTrack
+	if track == nil || !sameTrackPath(s.backendPath(track), strings.TrimSpace(path)) {
+		return
+	}
+
+	s.mu.Lock()
+	_, converted := s.transcodes[track.ID]
+	running := s.transcodeRunningLocked(track.ID)
+	s.mu.Unlock()
+	if converted || running {
+		return
+	}
+
+	// A preloaded next track would start in place of the failed one.
+	_ = backend.ClearPreloadedNext()
+
+	go s.transcodeAndReload(backend, track)
+}
+
+// startTranscodeFallback starts converting a track the backend rejected
+// and reports whether a conversion is running for it. The running state
+// is set before it returns, so the state sent for Play or Next shows it.
+func (s *Service) startTranscodeFallback(backend playbackBackend, track *library.TrackSummary) bool {
+	if track == nil {
+		return false
+	}
+	codec := s.trackCodec(track)
+
+	s.mu.Lock()
+	format := transcodeFormatFor(s.transcodePreferences, codec)
+	_, converted := s.transcodes[track.ID]
+	running := s.transcodeRunningLocked(track.ID)
+	s.mu.Unlock()
+	// A copy the backend rejects is not converted again.
+	if format == TranscodeFormatOff || converted {
+		return false
+	}
+	if running {
+		return true
+	}
+	if _, ok := findFFmpeg(); !ok {
+		s.setTranscodeJob(track.ID, TranscodeState{Status: TranscodeStatusFailed, Codec: codec, Format: format, Error: errTranscodeUnavailable.Error()})
+		return false
+	}
+
+	s.setTranscodeJob(track.ID, TranscodeState{Status: TranscodeStatusRunning, Codec: codec, Format: format})
+	go s.transcodeAndReload(backend, track)
+	return true
+}
+
+// transcodeAndReload converts a track and loads the copy when the track is
+// still current, starting it when the player is playing.
+func (s *Service) transcodeAndReload(backend playbackBackend, track *library.TrackSummary) {
+	if _, err := s.transcodeTrack(track); err != nil {
+		if s.queue != nil {
+			s.emitState(s.stateFromQueue(s.queue.GetState()))
+		}
+		return
+	}
+	if s.queue == nil {
+		return
+	}
+
+	currentState := s.queue.GetState()
+	if currentState.CurrentTrack == nil || currentState.CurrentTrack.ID != track.ID {
+		return
+	}
+	if err := s.loadTrack(backend, currentState.CurrentTrack, true); err != nil {
+		return
+	}
+	s.syncPreloadedNext(backend, currentState)
+
+	s.mu.Lock()
+	playing := s.status == StatusPlaying
+	s.mu.Unlock()
+	if playing {
+		_ = backend.Play()
+	}
+
+	s.emitState(s.stateFromQueue(currentState))
+}
+
+// playBackend starts the backend unless the current track is still being
+// converted; the conversion starts it once the copy is loaded.
+func (s *Service) playBackend(backend playbackBackend) error {
+	s.mu.Lock()
+	converting := s.hasCurrent && s.transcodeRunningLocked(s.currentTrackID)
+	s.mu.Unlock()
+	if converting {
+		return nil
+	}
+
+	return backend.Play()
+}
+
+func (s *Service) transcodeRunningLocked(trackID int64) bool {
+	return s.transcodeJobTrackID == trackID && s.transcodeJob != nil && s.transcodeJob.Status == TranscodeStatusRunning
+}
+
+// transcodeTrack converts a track with ffmpeg in the format chosen for its
+// codec and returns the path of the copy. Progress and failures are kept
+// for the player state.
+func (s *Service) transcodeTrack(track *library.TrackSummary) (string, error) {
+	codec := s.trackCodec(track)
+
+	s.mu.Lock()
+	format := transcodeFormatFor(s.transcodePreferences, codec)
+	s.mu.Unlock()
+	if format == TranscodeFormatOff {
+		return "", fmt.Errorf("transcoding %s files is turned off", codec)
+	}
+
+	ffmpegPath, ok := findFFmpeg()
+	if !ok {
+		s.setTranscodeJob(track.ID, TranscodeState{Status: TranscodeStatusFailed, Codec: codec, Format: format, Error: errTranscodeUnavailable.Error()})
+		return "", errTranscodeUnavailable
+	}
+
+	s.setTranscodeJob(track.ID, TranscodeState{Status: TranscodeStatusRunning, Codec: codec, Format: format})
+	if s.queue != nil {
+		s.emitState(s.stateFromQueue(s.queue.GetState()))
+	}
+
+	var target string
+	source, err := s.playbackSource(track.Path)
+	if err == nil {
+		target, err = s.runTranscode(ffmpegPath, source, track.ID, format)
+	}
+	if err != nil {
+		s.setTranscodeJob(track.ID, TranscodeState{Status: TranscodeStatusFailed, Codec: codec, Format: format, Error: err.Error()})
+		return "", err
+	}
+
+	s.mu.Lock()
+	if s.transcodes == nil {
+		s.transcodes = make(map[int64]transcodedFile)
+	}
+	if previous, ok := s.transcodes[track.ID]; ok && previous.path != target {
+		_ = os.Remove(previous.path)
+	}
+	s.transcodes[track.ID] = transcodedFile{path: target, codec: codec, format: format}
+	s.transcodeOrder = append(removeTrackID(s.transcodeOrder, track.ID), track.ID)
+	for len(s.transcodeOrder) > maxTranscodedFiles {
+		evicted := s.transcodeOrder[0]
+		s.transcodeOrder = s.transcodeOrder[1:]
+		_ = os.Remove(s.transcodes[evicted].path)
+		delete(s.transcodes, evicted)
+	}
+	if s.transcodeJobTrackID == track.ID {
+		s.transcodeJob = nil
+		s.transcodeJobTrackID = 0
+	}
+	s.mu.Unlock()
+
+	return target, nil
+}
+
+// runTranscode converts source into the transcode folder. A source that
+// needs headers is downloaded first, so credentials never end up on the
+// ffmpeg command line.
+func (s *Service) runTranscode(ffmpegPath string, source PlaybackSource, trackID int64, format string) (string, error) {
+	s.mu.Lock()
+	if s.transcodeDir == "" {
+		dir, err := os.MkdirTemp("", "ben-transcode-")
+		if err != nil {
+			s.mu.Unlock()
+			return "", fmt.Errorf("create transcode directory: %w", err)
+		}
+		s.transcodeDir = dir
+	}
+	dir := s.transcodeDir
+	s.mu.Unlock()
+
+	target := filepath.Join(dir, fmt.Sprintf("%d.%s", trackID, format))
+	partial := target + ".part"
+
+	ctx, cancel := context.WithTimeout(context.Background(), transcodeTimeout)
+	defer cancel()
+
+	input := source.Path
+	if len(source.Headers) > 0 {
+		input = target + ".src"
+		defer os.Remove(input)
+		if err := fetchSource(ctx, source, input); err != nil {
+			return "", err
+		}
+	}
+
+	output, err := exec.CommandContext(ctx, ffmpegPath, ffmpegTranscodeArgs(input, partial, format)...).CombinedOutput()
+	if err != nil {
+		_ = os.Remove(partial)
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return "", fmt.Errorf("transcode to %s: %s", format, lastLine(message))
+		}
+		return "", fmt.Errorf("transcode to %s: %w", format, err)
+	}
+
+	if err := os.Rename(partial, target); err != nil {
+		_ = os.Remove(partial)
+		return "", fmt.Errorf("store transcoded file: %w", err)
+	}
+
+	return target, nil
+}
+
+func (s *Service) setTranscodeJob(trackID int64, state TranscodeState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transcodeJob = &state
+	s.transcodeJobTrackID = trackID
+}
+
+// transcodeStateFor returns the transcode state shown for the current
+// track, or nil when it plays from its own file.
+func (s *Service) transcodeStateFor(trackID int64) *TranscodeState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if transcoded, ok := s.transcodes[trackID]; ok {
+		return &TranscodeState{Status: TranscodeStatusActive, Codec: transcoded.codec, Format: transcoded.format}
+	}
+	if s.transcodeJob != nil && s.transcodeJobTrackID == trackID {
+		state := *s.transcodeJob
+		return &state
+	}
+
+	return nil
+}
+
+func (s *Service) trackCodec(track *library.TrackSummary) string {
+	if s.db != nil {
+		var codec string
+		err := s.db.QueryRowContext(context.Background(), `
+			SELECT COALESCE(f.codec, '')
+			FROM tracks t
+			JOIN files f ON f.id = t.file_id
+			WHERE t.id = ?
+		`, track.ID).Scan(&codec)
+		if err == nil && strings.TrimSpace(codec) != "" {
+			return normalizeTranscodeCodec(codec)
+		}
+	}
+
+	return normalizeTranscodeCodec(filepath.Ext(track.Path))
+}
+
+// removeTranscodes deletes the transcoded copies and their directory.
+func (s *Service) removeTranscodes() {
+	s.mu.Lock()
+	dir := s.transcodeDir
+	s.transcodeDir = ""
+	s.transcodes = nil
+	s.transcodeOrder = nil
+	s.mu.Unlock()
+
+	if dir != "" {
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func removeTrackID(trackIDs []int64, trackID int64) []int64 {
+	kept := trackIDs[:0]
+	for _, id := range trackIDs {
+		if id != trackID {
+			kept = append(kept, id)
+		}
+	}
+
+	return kept
+}
+
+func lastLine(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
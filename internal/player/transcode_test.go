@@ -0,0 +1,122 @@
+package player
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+func TestNormalizeTranscodePreferences(t *testing.T) {
+	t.Parallel()
+
+	normalized, err := normalizeTranscodePreferences(TranscodePreferences{
+		Codecs: map[string]string{" .WMA ": "WAV", "ape": "off"},
+	})
+	if err != nil {
+		t.Fatalf("normalize preferences: %v", err)
+	}
+	if normalized.DefaultFormat != TranscodeFormatFLAC {
+		t.Fatalf("expected flac by default, got %q", normalized.DefaultFormat)
+	}
+	if normalized.Codecs["wma"] != TranscodeFormatWAV || normalized.Codecs["ape"] != TranscodeFormatOff {
+		t.Fatalf("unexpected codec preferences %#v", normalized.Codecs)
+	}
+
+	if _, err := normalizeTranscodePreferences(TranscodePreferences{DefaultFormat: "mp3"}); err == nil {
+		t.Fatal("expected an unknown format to be rejected")
+	}
+	if _, err := normalizeTranscodePreferences(TranscodePreferences{Codecs: map[string]string{"WMA": "flac", "wma": "wav"}}); err == nil {
+		t.Fatal("expected a codec set twice to be rejected")
+	}
+}
+
+func TestTranscodeFormatFor(t *testing.T) {
+	t.Parallel()
+
+	preferences := TranscodePreferences{
+		DefaultFormat: TranscodeFormatFLAC,
+		Codecs:        map[string]string{"wma": TranscodeFormatWAV, "ape": TranscodeFormatOff},
+	}
+
+	for codec, want := range map[string]string{"WMA": TranscodeFormatWAV, ".ape": TranscodeFormatOff, "alac": TranscodeFormatFLAC} {
+		if got := transcodeFormatFor(preferences, codec); got != want {
+			t.Fatalf("expected %q for %q, got %q", want, codec, got)
+		}
+	}
+}
+
+func TestFFmpegTranscodeArgs(t *testing.T) {
+	t.Parallel()
+
+	args := ffmpegTranscodeArgs("in.wma", "out.part", TranscodeFormatWAV)
+	if !slices.Contains(args, "pcm_s24le") || args[len(args)-1] != "out.part" {
+		t.Fatalf("unexpected wav arguments %v", args)
+	}
+
+	index := slices.Index(args, "-f")
+	if index < 0 || args[index+1] != TranscodeFormatWAV {
+		t.Fatalf("expected the container to be forced for a .part target, got %v", args)
+	}
+
+	if args := ffmpegTranscodeArgs("in.wma", "out.part", TranscodeFormatFLAC); !slices.Contains(args, "flac") {
+		t.Fatalf("unexpected flac arguments %v", args)
+	}
+}
+
+func TestLoadBackendTrackReturnsTheLoadErrorWithoutAFallback(t *testing.T) {
+	t.Parallel()
+
+	track := &library.TrackSummary{ID: 7, Path: "song.wma"}
+	for name, service := range map[string]*Service{
+		"turned off": {transcodePreferences: TranscodePreferences{DefaultFormat: TranscodeFormatOff}},
+		"copy rejected": {
+			transcodePreferences: defaultTranscodePreferences(),
+			transcodes:           map[int64]transcodedFile{7: {path: "7.flac"}},
+		},
+	} {
+		backend := &fakeBackend{loadErr: errors.New("unsupported format")}
+		if err := service.loadBackendTrack(backend, track, "song.wma"); !errors.Is(err, backend.loadErr) {
+			t.Fatalf("%s: expected the load error, got %v", name, err)
+		}
+	}
+}
+
+func TestPlayBackendWaitsForTheConversionOfTheCurrentTrack(t *testing.T) {
+	t.Parallel()
+
+	backend := &fakeBackend{}
+	service := &Service{hasCurrent: true, currentTrackID: 7}
+	service.setTranscodeJob(7, TranscodeState{Status: TranscodeStatusRunning, Format: TranscodeFormatFLAC})
+	if err := service.playBackend(backend); err != nil || backend.plays != 0 {
+		t.Fatalf("expected playback held during the conversion, got plays=%d err=%v", backend.plays, err)
+	}
+	if state := service.transcodeStateFor(7); state == nil || state.Status != TranscodeStatusRunning {
+		t.Fatalf("expected the running conversion in the state, got %+v", state)
+	}
+
+	service.setTranscodeJob(7, TranscodeState{Status: TranscodeStatusFailed, Format: TranscodeFormatFLAC})
+	if err := service.playBackend(backend); err != nil || backend.plays != 1 {
+		t.Fatalf("expected playback started, got plays=%d err=%v", backend.plays, err)
+	}
+}
+
+type fakeBackend struct {
+	loadErr error
+	plays   int
+}
+
+func (b *fakeBackend) Load(string) error                 { return b.loadErr }
+func (b *fakeBackend) PreloadNext(string) error          { return nil }
+func (b *fakeBackend) ClearPreloadedNext() error         { return nil }
+func (b *fakeBackend) Play() error                       { b.plays++; return nil }
+func (b *fakeBackend) Pause() error                      { return nil }
+func (b *fakeBackend) Seek(int) error                    { return nil }
+func (b *fakeBackend) SetVolume(int) error               { return nil }
+func (b *fakeBackend) SetAudioFilter(string) error       { return nil }
+func (b *fakeBackend) PositionMS() (int, error)          { return 0, nil }
+func (b *fakeBackend) DurationMS() (*int, error)         { return nil, nil }
+func (b *fakeBackend) SetOnEOF(func())                   {}
+func (b *fakeBackend) SetOnTrackStart(func(path string)) {}
+func (b *fakeBackend) Close() error                      { return nil }
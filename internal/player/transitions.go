@@ -0,0 +1,164 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const maxCrossfadeMS = 12000
+
+// ContinuousMixResolver reports whether a track belongs to a continuous mix
+// that must play gapless without crossfade.
+type ContinuousMixResolver func(trackID int64) bool
+
+// SetContinuousMixResolver installs the lookup used to keep DJ mixes gapless.
+// Results are cached per track until the resolver or crossfade changes.
+func (s *Service) SetContinuousMixResolver(resolver ContinuousMixResolver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.continuousMix = resolver
+	s.mixCache = make(map[int64]bool)
+}
+
+// SetCrossfade sets the fade length applied between tracks; 0 disables it.
+// mpv decodes one file at a time, so the tracks do not overlap: the
+// transition fades the outgoing track out over its last crossfadeMS and the
+// incoming track back in. Analyzed edge silence is trimmed while crossfade
+// is on, so both fades run over audio and no silence is left between them.
+func (s *Service) SetCrossfade(crossfadeMS int) (State, error) {
+	if crossfadeMS < 0 || crossfadeMS > maxCrossfadeMS {
+		return s.GetState(), fmt.Errorf("crossfade must be between 0 and %d ms", maxCrossfadeMS)
+	}
+
+	s.mu.Lock()
+	s.crossfadeMS = crossfadeMS
+	s.mixCache = make(map[int64]bool)
+	s.trimCache = make(map[int64]TrackTrim)
+	s.updatedAt = time.Now().UTC()
+	s.mu.Unlock()
+
+	if backend := s.tryBackend(); backend != nil {
+		s.applyCrossfade(backend)
+	}
+
+	s.persistCrossfade(crossfadeMS)
+
+	state := s.GetState()
+	s.emitState(state)
+	return state, nil
+}
+
+// InvalidateContinuousMix drops cached mix lookups, e.g. after an album
+// override changes.
+func (s *Service) InvalidateContinuousMix() {
+	s.mu.Lock()
+	s.mixCache = make(map[int64]bool)
+	s.trimCache = make(map[int64]TrackTrim)
+	s.mu.Unlock()
+
+	state := s.GetState()
+	s.emitState(state)
+}
+
+func (s *Service) isContinuousMixTrack(trackID int64) bool {
+	if trackID <= 0 {
+		return false
+	}
+
+	s.mu.Lock()
+	resolver := s.continuousMix
+	cached, ok := s.mixCache[trackID]
+	s.mu.Unlock()
+
+	if resolver == nil {
+		return false
+	}
+	if ok {
+		return cached
+	}
+
+	continuousMix := resolver(trackID)
+
+	s.mu.Lock()
+	if s.mixCache != nil {
+		s.mixCache[trackID] = continuousMix
+	}
+	s.mu.Unlock()
+
+	return continuousMix
+}
+
+// applyCrossfade sets the backend volume for the current point of the fade,
+// starting from the quiet hours capped volume and applying the track's
+// volume offset and leveling gain. Tracks of a continuous mix on either side
+// of the transition keep full volume so the mix plays seamlessly. A fading
+// sleep timer lowers the result further as it runs out.
+func (s *Service) applyCrossfade(backend playbackBackend) {
+	s.mu.Lock()
+	crossfadeMS := s.crossfadeMS
+	volume := s.cappedVolumeLocked(s.volume, time.Now())
+	positionMS := s.positionMS
+	duration := s.durationMS
+	currentTrackID := s.currentTrackID
+	hasCurrent := s.hasCurrent
+	if s.fadeOutTrackID != 0 && s.fadeOutTrackID != currentTrackID {
+		s.fadeInTrackID = currentTrackID
+		s.fadeOutTrackID = 0
+	}
+	fadeIn := s.fadeInTrackID == currentTrackID
+	appliedVolume := s.appliedVolume
+	sleepFactor := s.sleepFadeFactorLocked(time.Now())
+	s.mu.Unlock()
+
+	target := volume
+	if crossfadeMS > 0 && hasCurrent && !s.isContinuousMixTrack(currentTrackID) {
+		factor := 1.0
+		if fadeIn && positionMS < crossfadeMS {
+			factor = float64(positionMS) / float64(crossfadeMS)
+		}
+
+		nextTrack, hasNext := s.queue.PeekAutoplayNext()
+		if hasNext && nextTrack != nil && duration != nil && !s.isContinuousMixTrack(nextTrack.ID) {
+			remainingMS := *duration - positionMS
+			if remainingMS < crossfadeMS {
+				factor = min(factor, float64(max(remainingMS, 0))/float64(crossfadeMS))
+				s.mu.Lock()
+				s.fadeOutTrackID = currentTrackID
+				s.mu.Unlock()
+			}
+		}
+
+		target = int(float64(volume) * factor)
+	}
+	target = int(float64(target) * sleepFactor)
+	if hasCurrent {
+		target = applyVolumeOffset(target, s.volumeOffsetDB(currentTrackID)+s.levelingGainDB(currentTrackID))
+	}
+
+	if target == appliedVolume {
+		return
+	}
+
+	if err := backend.SetVolume(target); err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.appliedVolume = target
+	s.mu.Unlock()
+}
+
+func (s *Service) persistCrossfade(crossfadeMS int) {
+	if s.db == nil {
+		return
+	}
+
+	_, _ = s.db.ExecContext(
+		context.Background(),
+		`INSERT INTO playback_state(id, crossfade_ms)
+		 VALUES (1, ?)
+		 ON CONFLICT(id) DO UPDATE SET crossfade_ms = excluded.crossfade_ms`,
+		crossfadeMS,
+	)
+}
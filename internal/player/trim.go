@@ -0,0 +1,101 @@
+package player
+
+import "github.com/rzxx/ben/internal/library"
+
+// TrackTrim is where playback of a track starts and stops in the file. An
+// EndMS of 0 plays to the end of the file.
+type TrackTrim struct {
+	StartMS int
+	EndMS   int
+}
+
+// TrackTrimResolver returns the trim points of a track.
+type TrackTrimResolver func(trackID int64) TrackTrim
+
+// SetTrackTrimResolver installs the lookup for per-track start and end trims.
+// Positions and durations in State are relative to the trimmed range, so a
+// trimmed track looks like a shorter one to everything reading the state.
+func (s *Service) SetTrackTrimResolver(resolver TrackTrimResolver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trackTrims = resolver
+	s.trimCache = make(map[int64]TrackTrim)
+}
+
+// InvalidateTrackTrims drops cached trims after one changes. The playing
+// track keeps its position in the file and picks up the new range.
+func (s *Service) InvalidateTrackTrims() {
+	s.mu.Lock()
+	s.trimCache = make(map[int64]TrackTrim)
+	s.mu.Unlock()
+
+	if backend := s.tryBackend(); backend != nil {
+		s.refreshPlaybackPosition(backend)
+		s.syncPreloadedNext(backend, s.queue.GetState())
+	}
+	s.emitState(s.GetState())
+}
+
+// trackTrim returns the trims of a track, moved past its edge silence while
+// crossfade is on.
+func (s *Service) trackTrim(trackID int64) TrackTrim {
+	if trackID <= 0 {
+		return TrackTrim{}
+	}
+
+	s.mu.Lock()
+	resolver := s.trackTrims
+	cached, ok := s.trimCache[trackID]
+	s.mu.Unlock()
+
+	if ok {
+		return cached
+	}
+
+	trim := TrackTrim{}
+	if resolver != nil {
+		trim = resolver(trackID)
+	}
+	trim = s.edgeSilenceTrim(trackID, trim)
+
+	s.mu.Lock()
+	if s.trimCache != nil {
+		s.trimCache[trackID] = trim
+	}
+	s.mu.Unlock()
+
+	return trim
+}
+
+// trackDuration returns the tagged duration of track within its trims.
+func (s *Service) trackDuration(track *library.TrackSummary) *int {
+	if track == nil {
+		return nil
+	}
+
+	return trimmedDuration(track.DurationMS, s.trackTrim(track.ID))
+}
+
+// trimmedDuration maps a file duration onto the trimmed range. An end trim
+// past the end of the file is ignored.
+func trimmedDuration(durationMS *int, trim TrackTrim) *int {
+	end := 0
+	if durationMS != nil && *durationMS > 0 {
+		end = *durationMS
+	}
+	if trim.EndMS > 0 && (end == 0 || trim.EndMS < end) {
+		end = trim.EndMS
+	}
+	if end == 0 {
+		return nil
+	}
+
+	value := max(end-trim.StartMS, 0)
+	return &value
+}
+
+// endsEarly reports whether trim stops playback before the file ends, in
+// which case the player advances itself instead of waiting for the backend.
+func (t TrackTrim) endsEarly(durationMS *int) bool {
+	return t.EndMS > 0 && (durationMS == nil || t.EndMS < *durationMS)
+}
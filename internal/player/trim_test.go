@@ -0,0 +1,36 @@
+package player
+
+import "testing"
+
+func TestTrimmedDurationMeasuresTheTrimmedRange(t *testing.T) {
+	t.Parallel()
+
+	durationMS := 300_000
+	if got := trimmedDuration(&durationMS, TrackTrim{StartMS: 20_000, EndMS: 280_000}); got == nil || *got != 260_000 {
+		t.Fatalf("expected 260000 ms between the trims, got %v", got)
+	}
+	if got := trimmedDuration(&durationMS, TrackTrim{StartMS: 20_000, EndMS: 400_000}); got == nil || *got != 280_000 {
+		t.Fatalf("expected an end trim past the file to be ignored, got %v", got)
+	}
+	if got := trimmedDuration(nil, TrackTrim{StartMS: 5_000, EndMS: 65_000}); got == nil || *got != 60_000 {
+		t.Fatalf("expected the end trim to bound an unknown duration, got %v", got)
+	}
+	if got := trimmedDuration(nil, TrackTrim{StartMS: 5_000}); got != nil {
+		t.Fatalf("expected unknown duration without end trim, got %v", *got)
+	}
+}
+
+func TestTrackTrimEndsEarlyOnlyBeforeTheFileEnds(t *testing.T) {
+	t.Parallel()
+
+	durationMS := 300_000
+	if !(TrackTrim{EndMS: 280_000}).endsEarly(&durationMS) {
+		t.Fatal("expected an end trim before the file end to end early")
+	}
+	if (TrackTrim{EndMS: 300_000}).endsEarly(&durationMS) {
+		t.Fatal("expected an end trim at the file end to leave the end to the backend")
+	}
+	if (TrackTrim{StartMS: 10_000}).endsEarly(&durationMS) {
+		t.Fatal("expected a start-only trim not to end early")
+	}
+}
@@ -0,0 +1,71 @@
+package player
+
+import "math"
+
+// maxBackendVolume matches mpv's default volume-max, which leaves headroom
+// for positive offsets on top of full volume.
+const maxBackendVolume = 130
+
+// VolumeOffsetResolver returns the manual loudness correction in dB for a
+// track, combining its album and track offsets.
+type VolumeOffsetResolver func(trackID int64) float64
+
+// SetVolumeOffsetResolver installs the lookup used to apply per-album and
+// per-track offsets. Results are cached per track until invalidated.
+func (s *Service) SetVolumeOffsetResolver(resolver VolumeOffsetResolver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.volumeOffset = resolver
+	s.offsetCache = make(map[int64]float64)
+}
+
+// InvalidateVolumeOffsets drops cached offsets after one changes and
+// re-applies the volume of the current track.
+func (s *Service) InvalidateVolumeOffsets() {
+	s.mu.Lock()
+	s.offsetCache = make(map[int64]float64)
+	s.mu.Unlock()
+
+	if backend := s.tryBackend(); backend != nil {
+		s.applyCrossfade(backend)
+	}
+}
+
+func (s *Service) volumeOffsetDB(trackID int64) float64 {
+	if trackID <= 0 {
+		return 0
+	}
+
+	s.mu.Lock()
+	resolver := s.volumeOffset
+	cached, ok := s.offsetCache[trackID]
+	s.mu.Unlock()
+
+	if resolver == nil {
+		return 0
+	}
+	if ok {
+		return cached
+	}
+
+	offsetDB := resolver(trackID)
+
+	s.mu.Lock()
+	if s.offsetCache != nil {
+		s.offsetCache[trackID] = offsetDB
+	}
+	s.mu.Unlock()
+
+	return offsetDB
+}
+
+// applyVolumeOffset scales an mpv volume by offsetDB. mpv's volume is cubic,
+// so a gain of g dB multiplies the volume by 10^(g/60).
+func applyVolumeOffset(volume int, offsetDB float64) int {
+	if offsetDB == 0 {
+		return volume
+	}
+
+	scaled := int(math.Round(float64(volume) * math.Pow(10, offsetDB/60)))
+	return min(max(scaled, 0), maxBackendVolume)
+}
@@ -0,0 +1,17 @@
+package player
+
+import "testing"
+
+func TestApplyVolumeOffsetUsesCubicVolumeScale(t *testing.T) {
+	t.Parallel()
+
+	if got := applyVolumeOffset(80, 0); got != 80 {
+		t.Fatalf("expected unchanged volume, got %d", got)
+	}
+	if got := applyVolumeOffset(80, -6); got != 64 {
+		t.Fatalf("expected -6 dB to give 64, got %d", got)
+	}
+	if got := applyVolumeOffset(100, 12); got != maxBackendVolume {
+		t.Fatalf("expected volume to be capped at %d, got %d", maxBackendVolume, got)
+	}
+}
@@ -0,0 +1,190 @@
+package playlist
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Rename changes the name of a playlist. A synced playlist is exported
+// again under its new name.
+func (s *Service) Rename(id int64, name string) (Playlist, error) {
+	normalizedName, err := normalizePlaylistName(name)
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	ctx := context.Background()
+	result, err := s.db.ExecContext(
+		ctx,
+		"UPDATE playlists SET name = ?, updated_at = ? WHERE id = ?",
+		normalizedName,
+		time.Now().UTC().Format(time.RFC3339),
+		id,
+	)
+	if err != nil {
+		return Playlist{}, fmt.Errorf("rename playlist %d: %w", id, err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return Playlist{}, fmt.Errorf("read renamed playlist count: %w", err)
+	} else if rowsAffected == 0 {
+		return Playlist{}, ErrPlaylistNotFound
+	}
+
+	s.afterMutation(Change{PlaylistID: id})
+	return s.getPlaylist(ctx, id)
+}
+
+// AddTracks appends tracks to the end of a playlist.
+func (s *Service) AddTracks(id int64, trackIDs []int64) (Playlist, error) {
+	return s.editEntries(id, func(entries []playlistEntry) ([]playlistEntry, error) {
+		for _, trackID := range trackIDs {
+			entries = append(entries, playlistEntry{trackID: trackID, visible: true})
+		}
+		return entries, nil
+	})
+}
+
+// RemoveTracks removes entries by their position in the playlist's track
+// list as returned by Get. Entries whose files are missing keep their place.
+func (s *Service) RemoveTracks(id int64, positions []int) (Playlist, error) {
+	return s.editEntries(id, func(entries []playlistEntry) ([]playlistEntry, error) {
+		visible := visibleEntryIndexes(entries)
+		remove := make(map[int]struct{}, len(positions))
+		for _, position := range positions {
+			if position < 0 || position >= len(visible) {
+				return nil, fmt.Errorf("playlist position %d is out of range", position)
+			}
+			remove[visible[position]] = struct{}{}
+		}
+
+		kept := make([]playlistEntry, 0, len(entries))
+		for index, entry := range entries {
+			if _, removed := remove[index]; !removed {
+				kept = append(kept, entry)
+			}
+		}
+		return kept, nil
+	})
+}
+
+// MoveTrack moves the entry at position from to position to, both in the
+// playlist's track list as returned by Get.
+func (s *Service) MoveTrack(id int64, from int, to int) (Playlist, error) {
+	return s.editEntries(id, func(entries []playlistEntry) ([]playlistEntry, error) {
+		return moveEntry(entries, from, to)
+	})
+}
+
+// playlistEntry is a stored entry. Entries whose files are missing are not
+// visible in Get, so positions from the UI skip them.
+type playlistEntry struct {
+	trackID int64
+	visible bool
+}
+
+func visibleEntryIndexes(entries []playlistEntry) []int {
+	indexes := make([]int, 0, len(entries))
+	for index, entry := range entries {
+		if entry.visible {
+			indexes = append(indexes, index)
+		}
+	}
+
+	return indexes
+}
+
+// moveEntry moves a visible entry so it ends up at visible position to.
+// Hidden entries stay where they are relative to their neighbours.
+func moveEntry(entries []playlistEntry, from int, to int) ([]playlistEntry, error) {
+	visible := visibleEntryIndexes(entries)
+	if from < 0 || from >= len(visible) || to < 0 || to >= len(visible) {
+		return nil, fmt.Errorf("playlist positions %d and %d must be within the playlist", from, to)
+	}
+	if from == to {
+		return entries, nil
+	}
+
+	// Inserting at the target's original index lands after the target when
+	// moving down, since the target shifted left by one, and before it when
+	// moving up.
+	moving := entries[visible[from]]
+	moved := slices.Delete(slices.Clone(entries), visible[from], visible[from]+1)
+	return slices.Insert(moved, visible[to], moving), nil
+}
+
+func (s *Service) editEntries(id int64, edit func(entries []playlistEntry) ([]playlistEntry, error)) (Playlist, error) {
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Playlist{}, fmt.Errorf("begin playlist edit tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := touchPlaylist(ctx, tx, id); err != nil {
+		return Playlist{}, err
+	}
+
+	entries, err := loadEntries(ctx, tx, id)
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	edited, err := edit(entries)
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	trackIDs := make([]int64, 0, len(edited))
+	for _, entry := range edited {
+		trackIDs = append(trackIDs, entry.trackID)
+	}
+	if err := replaceEntries(ctx, tx, id, trackIDs); err != nil {
+		return Playlist{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Playlist{}, fmt.Errorf("commit playlist edit: %w", err)
+	}
+
+	s.afterMutation(Change{PlaylistID: id})
+	return s.getPlaylist(ctx, id)
+}
+
+func loadEntries(ctx context.Context, tx *sql.Tx, id int64) ([]playlistEntry, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			pe.track_id,
+			EXISTS (
+				SELECT 1 FROM tracks t
+				JOIN files f ON f.id = t.file_id
+				WHERE t.id = pe.track_id AND f.file_exists = 1
+			)
+		FROM playlist_entries pe
+		WHERE pe.playlist_id = ?
+		ORDER BY pe.position ASC, pe.id ASC
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("list playlist entries for %d: %w", id, err)
+	}
+	defer rows.Close()
+
+	entries := make([]playlistEntry, 0)
+	for rows.Next() {
+		var entry playlistEntry
+		if scanErr := rows.Scan(&entry.trackID, &entry.visible); scanErr != nil {
+			return nil, fmt.Errorf("scan playlist entry row for %d: %w", id, scanErr)
+		}
+		entries = append(entries, entry)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate playlist entries for %d: %w", id, rowsErr)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,45 @@
+package playlist
+
+import (
+	"slices"
+	"testing"
+)
+
+func entryTrackIDsForTest(entries []playlistEntry) []int64 {
+	trackIDs := make([]int64, 0, len(entries))
+	for _, entry := range entries {
+		trackIDs = append(trackIDs, entry.trackID)
+	}
+	return trackIDs
+}
+
+func TestMoveEntryKeepsHiddenEntriesInPlace(t *testing.T) {
+	t.Parallel()
+
+	entries := []playlistEntry{
+		{trackID: 1, visible: true},
+		{trackID: 2},
+		{trackID: 3, visible: true},
+		{trackID: 4, visible: true},
+	}
+
+	down, err := moveEntry(entries, 0, 2)
+	if err != nil {
+		t.Fatalf("move entry down: %v", err)
+	}
+	if got := entryTrackIDsForTest(down); !slices.Equal(got, []int64{2, 3, 4, 1}) {
+		t.Fatalf("expected the first track after the last visible one, got %v", got)
+	}
+
+	up, err := moveEntry(entries, 2, 0)
+	if err != nil {
+		t.Fatalf("move entry up: %v", err)
+	}
+	if got := entryTrackIDsForTest(up); !slices.Equal(got, []int64{4, 1, 2, 3}) {
+		t.Fatalf("expected the last track first, got %v", got)
+	}
+
+	if _, err := moveEntry(entries, 0, 3); err == nil {
+		t.Fatal("expected a position past the visible tracks to be rejected")
+	}
+}
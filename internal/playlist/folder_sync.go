@@ -0,0 +1,453 @@
+package playlist
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/rzxx/ben/internal/settings"
+)
+
+const SyncFolderSettingKey = "playlists.sync_folder"
+
+const folderSyncDebounceDelay = 800 * time.Millisecond
+
+// folderSyncRemovalGrace is how long a removed playlist file may stay away
+// before its playlist is deleted. Editors that save by replacing the file
+// remove it and create it again well within this window.
+const folderSyncRemovalGrace = 3 * time.Second
+
+// FolderSync mirrors in-app playlists to .m3u8 files in a designated folder
+// and imports files that other players create or edit there.
+type FolderSync struct {
+	mu            sync.Mutex
+	playlists     *Service
+	settings      *settings.Store
+	folder        string
+	watcher       *fsnotify.Watcher
+	stop          chan struct{}
+	debounce      *time.Timer
+	pending       map[string]struct{}
+	removals      map[string]*time.Timer
+	debounceDelay time.Duration
+	removalGrace  time.Duration
+}
+
+func NewFolderSync(playlists *Service, store *settings.Store) *FolderSync {
+	folderSync := &FolderSync{
+		playlists:     playlists,
+		settings:      store,
+		pending:       make(map[string]struct{}),
+		removals:      make(map[string]*time.Timer),
+		debounceDelay: folderSyncDebounceDelay,
+		removalGrace:  folderSyncRemovalGrace,
+	}
+
+	if playlists != nil {
+		playlists.SetOnChange(folderSync.onPlaylistChanged)
+	}
+
+	return folderSync
+}
+
+func (f *FolderSync) Folder() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.folder
+}
+
+func (f *FolderSync) Start() error {
+	folder := ""
+	if f.settings != nil {
+		folder = f.settings.GetString(context.Background(), SyncFolderSettingKey, "")
+	}
+
+	return f.applyFolder(folder)
+}
+
+func (f *FolderSync) Stop() error {
+	return f.stopWatching()
+}
+
+// SetFolder changes the sync folder. An empty path disables folder sync
+// without touching files that were already written.
+func (f *FolderSync) SetFolder(path string) error {
+	folder := strings.TrimSpace(path)
+	if folder != "" {
+		absFolder, err := filepath.Abs(folder)
+		if err != nil {
+			return fmt.Errorf("resolve playlist folder: %w", err)
+		}
+		folder = filepath.Clean(absFolder)
+
+		info, err := os.Stat(folder)
+		if err != nil {
+			return fmt.Errorf("playlist folder: %w", err)
+		}
+		if !info.IsDir() {
+			return errors.New("playlist folder must be a directory")
+		}
+	}
+
+	if f.settings != nil {
+		ctx := context.Background()
+		var err error
+		if folder == "" {
+			err = f.settings.Delete(ctx, SyncFolderSettingKey)
+		} else {
+			err = f.settings.Set(ctx, SyncFolderSettingKey, folder)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return f.applyFolder(folder)
+}
+
+// SyncNow imports every playlist file in the folder and exports playlists
+// that do not have a file there yet.
+func (f *FolderSync) SyncNow() error {
+	folder := f.Folder()
+	if folder == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return fmt.Errorf("read playlist folder: %w", err)
+	}
+
+	var syncErrs []error
+	for _, entry := range entries {
+		if entry.IsDir() || !isPlaylistFile(entry.Name()) {
+			continue
+		}
+		if err := f.importFile(filepath.Join(folder, entry.Name())); err != nil {
+			syncErrs = append(syncErrs, err)
+		}
+	}
+
+	playlists, err := f.playlists.List()
+	if err != nil {
+		return err
+	}
+	for _, playlist := range playlists {
+		if playlist.SyncPath != nil && filepath.Dir(*playlist.SyncPath) == folder {
+			// The folder was readable, so a missing file was deleted while
+			// the app was closed.
+			if _, err := os.Stat(*playlist.SyncPath); errors.Is(err, os.ErrNotExist) {
+				if err := f.playlists.deleteFromFile(*playlist.SyncPath); err != nil {
+					syncErrs = append(syncErrs, err)
+				}
+			}
+			continue
+		}
+		if err := f.exportPlaylist(playlist.ID); err != nil {
+			syncErrs = append(syncErrs, err)
+		}
+	}
+
+	return errors.Join(syncErrs...)
+}
+
+func (f *FolderSync) applyFolder(folder string) error {
+	if err := f.stopWatching(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	f.folder = folder
+	f.mu.Unlock()
+
+	if folder == "" {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create playlist folder watcher: %w", err)
+	}
+	if err := watcher.Add(folder); err != nil {
+		watcher.Close()
+		return fmt.Errorf("watch playlist folder: %w", err)
+	}
+
+	stopCh := make(chan struct{})
+	f.mu.Lock()
+	f.watcher = watcher
+	f.stop = stopCh
+	f.mu.Unlock()
+
+	go f.watchLoop(watcher, stopCh)
+
+	return f.SyncNow()
+}
+
+func (f *FolderSync) stopWatching() error {
+	f.mu.Lock()
+	watcher := f.watcher
+	stopCh := f.stop
+	debounce := f.debounce
+	removals := f.removals
+	f.watcher = nil
+	f.stop = nil
+	f.debounce = nil
+	f.pending = make(map[string]struct{})
+	f.removals = make(map[string]*time.Timer)
+	f.mu.Unlock()
+
+	if debounce != nil {
+		debounce.Stop()
+	}
+	for _, timer := range removals {
+		timer.Stop()
+	}
+	if stopCh != nil {
+		close(stopCh)
+	}
+	if watcher != nil {
+		if err := watcher.Close(); err != nil {
+			return fmt.Errorf("close playlist folder watcher: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (f *FolderSync) watchLoop(watcher *fsnotify.Watcher, stopCh <-chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			f.handleEvent(event)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("playlist folder watcher: %v", err)
+		}
+	}
+}
+
+func (f *FolderSync) handleEvent(event fsnotify.Event) {
+	if !isPlaylistFile(event.Name) {
+		return
+	}
+
+	f.mu.Lock()
+	f.pending[filepath.Clean(event.Name)] = struct{}{}
+	if f.debounce != nil {
+		f.debounce.Stop()
+	}
+	f.debounce = time.AfterFunc(f.debounceDelay, f.flushPending)
+	f.mu.Unlock()
+}
+
+func (f *FolderSync) flushPending() {
+	f.mu.Lock()
+	paths := make([]string, 0, len(f.pending))
+	for path := range f.pending {
+		paths = append(paths, path)
+	}
+	f.pending = make(map[string]struct{})
+	f.debounce = nil
+	f.mu.Unlock()
+
+	for _, path := range paths {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			f.scheduleRemoval(path)
+			continue
+		}
+
+		f.cancelRemoval(path)
+		if err := f.importFile(path); err != nil {
+			log.Printf("playlist folder sync: %v", err)
+		}
+	}
+}
+
+// scheduleRemoval deletes the playlist of a removed file once the file has
+// stayed away for the grace period. Until then the playlist keeps its sync
+// path, so a file created again at the same path refreshes the same
+// playlist instead of importing a second one.
+func (f *FolderSync) scheduleRemoval(path string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if timer, ok := f.removals[path]; ok {
+		timer.Stop()
+	}
+	f.removals[path] = time.AfterFunc(f.removalGrace, func() {
+		f.confirmRemoval(path)
+	})
+}
+
+func (f *FolderSync) cancelRemoval(path string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if timer, ok := f.removals[path]; ok {
+		timer.Stop()
+		delete(f.removals, path)
+	}
+}
+
+func (f *FolderSync) confirmRemoval(path string) {
+	f.mu.Lock()
+	delete(f.removals, path)
+	f.mu.Unlock()
+
+	if _, err := os.Stat(path); err == nil {
+		if err := f.importFile(path); err != nil {
+			log.Printf("playlist folder sync: %v", err)
+		}
+		return
+	}
+
+	if err := f.playlists.deleteFromFile(path); err != nil {
+		log.Printf("playlist folder sync: %v", err)
+	}
+}
+
+func (f *FolderSync) importFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read playlist file %s: %w", path, err)
+	}
+
+	contentHash := hashContent(content)
+	_, storedHash, found, err := f.playlists.syncState(path)
+	if err != nil {
+		return err
+	}
+	if found && storedHash == contentHash {
+		return nil
+	}
+
+	name, entryPaths := parseM3U(content, filepath.Dir(path))
+	if strings.TrimSpace(name) == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	trackIDs, err := f.playlists.resolveTrackIDsByPath(entryPaths)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.playlists.importFromFile(name, path, contentHash, trackIDs); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (f *FolderSync) exportPlaylist(id int64) error {
+	folder := f.Folder()
+	if folder == "" {
+		return nil
+	}
+
+	detail, err := f.playlists.Get(id)
+	if err != nil {
+		return err
+	}
+
+	targetPath := ""
+	if detail.Playlist.SyncPath != nil && filepath.Dir(*detail.Playlist.SyncPath) == folder {
+		targetPath = *detail.Playlist.SyncPath
+	} else {
+		targetPath, err = f.availablePlaylistPath(folder, detail.Playlist.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	content := formatM3U(detail.Playlist.Name, detail.Tracks, folder)
+	if err := writeFileAtomic(targetPath, content); err != nil {
+		return fmt.Errorf("write playlist file %s: %w", targetPath, err)
+	}
+
+	return f.playlists.setSyncState(id, targetPath, hashContent(content))
+}
+
+func (f *FolderSync) availablePlaylistPath(folder string, name string) (string, error) {
+	fileName := playlistFileName(name)
+	baseName := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+
+	for attempt := 1; attempt < 1000; attempt++ {
+		candidate := filepath.Join(folder, fileName)
+		if attempt > 1 {
+			candidate = filepath.Join(folder, fmt.Sprintf("%s (%d).m3u8", baseName, attempt))
+		}
+
+		if _, err := os.Stat(candidate); errors.Is(err, os.ErrNotExist) {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("no free playlist file name for %q", name)
+}
+
+func (f *FolderSync) onPlaylistChanged(change Change) {
+	if change.fromSync || change.PlaylistID == 0 || f.Folder() == "" {
+		return
+	}
+
+	if change.Deleted {
+		if change.syncPath != "" && filepath.Dir(change.syncPath) == f.Folder() {
+			if err := os.Remove(change.syncPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				log.Printf("playlist folder sync: remove %s: %v", change.syncPath, err)
+			}
+		}
+		return
+	}
+
+	if err := f.exportPlaylist(change.PlaylistID); err != nil {
+		log.Printf("playlist folder sync: %v", err)
+	}
+}
+
+func writeFileAtomic(path string, content []byte) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(path), ".ben-playlist-*")
+	if err != nil {
+		return err
+	}
+	tempPath := tempFile.Name()
+
+	if _, err := tempFile.Write(content); err != nil {
+		tempFile.Close()
+		os.Remove(tempPath)
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	return nil
+}
+
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
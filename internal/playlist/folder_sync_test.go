@@ -0,0 +1,150 @@
+package playlist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/rzxx/ben/internal/db"
+)
+
+func TestFolderSyncReplaceSaveKeepsPlaylist(t *testing.T) {
+	t.Parallel()
+
+	service, folderSync, folder := newFolderSyncForTest(t)
+	path := filepath.Join(folder, "Road Trip.m3u8")
+	writePlaylistFileForTest(t, path, "Road Trip")
+	if err := folderSync.importFile(path); err != nil {
+		t.Fatalf("import playlist file: %v", err)
+	}
+	imported := listPlaylistsForTest(t, service)
+
+	// An editor saving by replace removes the file, and the removal is
+	// flushed before the new file shows up.
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("remove playlist file: %v", err)
+	}
+	folderSync.handleEvent(fsnotify.Event{Name: path, Op: fsnotify.Remove})
+	folderSync.flushPending()
+
+	writePlaylistFileForTest(t, path, "Road Trip Extended")
+	folderSync.handleEvent(fsnotify.Event{Name: path, Op: fsnotify.Create})
+	folderSync.flushPending()
+
+	time.Sleep(3 * folderSync.removalGrace)
+
+	playlists := listPlaylistsForTest(t, service)
+	if len(playlists) != 1 || playlists[0].ID != imported[0].ID {
+		t.Fatalf("expected the replaced file to refresh playlist %d, got %+v", imported[0].ID, playlists)
+	}
+	if playlists[0].Name != "Road Trip Extended" {
+		t.Fatalf("expected the new file content, got name %q", playlists[0].Name)
+	}
+	if playlists[0].SyncPath == nil || *playlists[0].SyncPath != path {
+		t.Fatalf("expected the playlist to stay bound to %s, got %v", path, playlists[0].SyncPath)
+	}
+}
+
+func TestFolderSyncDeletedFileRemovesPlaylist(t *testing.T) {
+	t.Parallel()
+
+	service, folderSync, folder := newFolderSyncForTest(t)
+	path := filepath.Join(folder, "Gym.m3u8")
+	writePlaylistFileForTest(t, path, "Gym")
+	if err := folderSync.importFile(path); err != nil {
+		t.Fatalf("import playlist file: %v", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("remove playlist file: %v", err)
+	}
+	folderSync.handleEvent(fsnotify.Event{Name: path, Op: fsnotify.Remove})
+	folderSync.flushPending()
+
+	if playlists := listPlaylistsForTest(t, service); len(playlists) != 1 {
+		t.Fatalf("expected the playlist to wait out the grace period, got %+v", playlists)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if len(listPlaylistsForTest(t, service)) == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expected the playlist of the deleted file to be removed")
+}
+
+func TestFolderSyncNowRemovesPlaylistsOfFilesDeletedWhileClosed(t *testing.T) {
+	t.Parallel()
+
+	service, folderSync, folder := newFolderSyncForTest(t)
+	kept := filepath.Join(folder, "Kept.m3u8")
+	deleted := filepath.Join(folder, "Deleted.m3u8")
+	writePlaylistFileForTest(t, kept, "Kept")
+	writePlaylistFileForTest(t, deleted, "Deleted")
+	if err := folderSync.SyncNow(); err != nil {
+		t.Fatalf("sync folder: %v", err)
+	}
+
+	if err := os.Remove(deleted); err != nil {
+		t.Fatalf("remove playlist file: %v", err)
+	}
+	if err := folderSync.SyncNow(); err != nil {
+		t.Fatalf("sync folder again: %v", err)
+	}
+
+	playlists := listPlaylistsForTest(t, service)
+	if len(playlists) != 1 || playlists[0].Name != "Kept" {
+		t.Fatalf("expected only the playlist with a file to remain, got %+v", playlists)
+	}
+}
+
+func newFolderSyncForTest(t *testing.T) (*Service, *FolderSync, string) {
+	t.Helper()
+
+	database, err := db.Bootstrap(filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatalf("bootstrap test database: %v", err)
+	}
+	t.Cleanup(func() {
+		database.Close()
+	})
+
+	service := NewService(database, nil)
+	folderSync := NewFolderSync(service, nil)
+	folderSync.debounceDelay = 10 * time.Millisecond
+	folderSync.removalGrace = 50 * time.Millisecond
+	t.Cleanup(func() {
+		folderSync.Stop()
+	})
+
+	// The folder is set without a watcher, so the test drives the events.
+	folder := t.TempDir()
+	folderSync.mu.Lock()
+	folderSync.folder = folder
+	folderSync.mu.Unlock()
+
+	return service, folderSync, folder
+}
+
+func writePlaylistFileForTest(t *testing.T, path string, name string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte("#EXTM3U\n#PLAYLIST:"+name+"\n"), 0o644); err != nil {
+		t.Fatalf("write playlist file: %v", err)
+	}
+}
+
+func listPlaylistsForTest(t *testing.T, service *Service) []Playlist {
+	t.Helper()
+
+	playlists, err := service.List()
+	if err != nil {
+		t.Fatalf("list playlists: %v", err)
+	}
+	return playlists
+}
@@ -0,0 +1,412 @@
+package playlist
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const maxPlaylistTagLength = 40
+
+var ErrFolderNotFound = errors.New("playlist folder not found")
+
+// Folder groups playlists in the sidebar. Folders nest; ParentID is nil for
+// top-level folders.
+type Folder struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	ParentID  *int64 `json:"parentId,omitempty"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+type Tag struct {
+	Name          string `json:"name"`
+	PlaylistCount int    `json:"playlistCount"`
+}
+
+func (s *Service) ListFolders() ([]Folder, error) {
+	rows, err := s.db.QueryContext(
+		context.Background(),
+		"SELECT id, name, parent_id, created_at, updated_at FROM playlist_folders ORDER BY LOWER(name), id",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list playlist folders: %w", err)
+	}
+	defer rows.Close()
+
+	folders := make([]Folder, 0)
+	for rows.Next() {
+		folder, scanErr := scanFolder(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("scan playlist folder row: %w", scanErr)
+		}
+		folders = append(folders, folder)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate playlist folder rows: %w", rowsErr)
+	}
+
+	return folders, nil
+}
+
+func (s *Service) CreateFolder(name string, parentID *int64) (Folder, error) {
+	normalizedName, err := normalizeFolderName(name)
+	if err != nil {
+		return Folder{}, err
+	}
+
+	ctx := context.Background()
+	if parentID != nil {
+		if _, err := s.getFolder(ctx, *parentID); err != nil {
+			return Folder{}, err
+		}
+	}
+
+	result, err := s.db.ExecContext(ctx, "INSERT INTO playlist_folders(name, parent_id) VALUES (?, ?)", normalizedName, parentID)
+	if err != nil {
+		return Folder{}, fmt.Errorf("insert playlist folder: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return Folder{}, fmt.Errorf("read playlist folder id: %w", err)
+	}
+
+	s.afterMutation(Change{FolderID: id})
+	return s.getFolder(ctx, id)
+}
+
+func (s *Service) RenameFolder(id int64, name string) (Folder, error) {
+	normalizedName, err := normalizeFolderName(name)
+	if err != nil {
+		return Folder{}, err
+	}
+
+	return s.updateFolder(id, "name = ?", normalizedName)
+}
+
+// MoveFolder moves a folder, with its playlists and subfolders, under
+// parentID, or to the top level when parentID is nil. A folder cannot be
+// moved into itself or one of its own subfolders.
+func (s *Service) MoveFolder(id int64, parentID *int64) (Folder, error) {
+	ctx := context.Background()
+	if parentID != nil {
+		ancestorID := *parentID
+		for {
+			if ancestorID == id {
+				return Folder{}, errors.New("a folder cannot be moved into itself")
+			}
+
+			ancestor, err := s.getFolder(ctx, ancestorID)
+			if err != nil {
+				return Folder{}, err
+			}
+			if ancestor.ParentID == nil {
+				break
+			}
+			ancestorID = *ancestor.ParentID
+		}
+	}
+
+	return s.updateFolder(id, "parent_id = ?", parentID)
+}
+
+// DeleteFolder removes a folder. Its playlists and subfolders move up to the
+// folder's parent instead of being deleted.
+func (s *Service) DeleteFolder(id int64) error {
+	ctx := context.Background()
+	folder, err := s.getFolder(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin delete playlist folder tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, "UPDATE playlists SET folder_id = ? WHERE folder_id = ?", folder.ParentID, id); err != nil {
+		return fmt.Errorf("move playlists out of folder %d: %w", id, err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE playlist_folders SET parent_id = ? WHERE parent_id = ?", folder.ParentID, id); err != nil {
+		return fmt.Errorf("move subfolders out of folder %d: %w", id, err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM playlist_folders WHERE id = ?", id); err != nil {
+		return fmt.Errorf("delete playlist folder %d: %w", id, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit delete playlist folder: %w", err)
+	}
+
+	s.afterMutation(Change{FolderID: id, Deleted: true})
+	return nil
+}
+
+// MovePlaylist puts a playlist into folderID, or at the top level when
+// folderID is nil.
+func (s *Service) MovePlaylist(id int64, folderID *int64) (Playlist, error) {
+	ctx := context.Background()
+	if folderID != nil {
+		if _, err := s.getFolder(ctx, *folderID); err != nil {
+			return Playlist{}, err
+		}
+	}
+
+	result, err := s.db.ExecContext(ctx, "UPDATE playlists SET folder_id = ? WHERE id = ?", folderID, id)
+	if err != nil {
+		return Playlist{}, fmt.Errorf("move playlist %d: %w", id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return Playlist{}, fmt.Errorf("read moved playlist count: %w", err)
+	}
+	if rowsAffected == 0 {
+		return Playlist{}, ErrPlaylistNotFound
+	}
+
+	s.afterMutation(Change{PlaylistID: id})
+	return s.getPlaylist(ctx, id)
+}
+
+// SetTags replaces the tags of a playlist. Tags are trimmed, lowercased and
+// deduplicated, so "Workout" and "workout " are the same tag.
+func (s *Service) SetTags(id int64, tags []string) (Playlist, error) {
+	normalizedTags, err := normalizeTags(tags)
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Playlist{}, fmt.Errorf("begin playlist tags tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := touchPlaylist(ctx, tx, id); err != nil {
+		return Playlist{}, err
+	}
+
+	if err := replaceTags(ctx, tx, id, normalizedTags); err != nil {
+		return Playlist{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Playlist{}, fmt.Errorf("commit playlist tags: %w", err)
+	}
+
+	s.afterMutation(Change{PlaylistID: id})
+	return s.getPlaylist(ctx, id)
+}
+
+// ListTags returns every tag in use with the number of playlists carrying
+// it, alphabetically.
+func (s *Service) ListTags() ([]Tag, error) {
+	rows, err := s.db.QueryContext(
+		context.Background(),
+		"SELECT tag, COUNT(*) FROM playlist_tags GROUP BY tag ORDER BY tag",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list playlist tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := make([]Tag, 0)
+	for rows.Next() {
+		var tag Tag
+		if scanErr := rows.Scan(&tag.Name, &tag.PlaylistCount); scanErr != nil {
+			return nil, fmt.Errorf("scan playlist tag row: %w", scanErr)
+		}
+		tags = append(tags, tag)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate playlist tag rows: %w", rowsErr)
+	}
+
+	return tags, nil
+}
+
+// ListByTag lists the playlists carrying tag, across all folders.
+func (s *Service) ListByTag(tag string) ([]Playlist, error) {
+	normalizedTag := normalizeTag(tag)
+	if normalizedTag == "" {
+		return s.List()
+	}
+
+	rows, err := s.db.QueryContext(context.Background(), playlistSelectSQL+`
+		WHERE p.id IN (SELECT playlist_id FROM playlist_tags WHERE tag = ?)
+		GROUP BY p.id
+		ORDER BY LOWER(p.name), p.id
+	`, normalizedTag)
+	if err != nil {
+		return nil, fmt.Errorf("list playlists tagged %s: %w", normalizedTag, err)
+	}
+	defer rows.Close()
+
+	playlists := make([]Playlist, 0)
+	for rows.Next() {
+		playlist, scanErr := scanPlaylist(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("scan playlist row: %w", scanErr)
+		}
+		playlists = append(playlists, playlist)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate playlist rows: %w", rowsErr)
+	}
+
+	return playlists, nil
+}
+
+func (s *Service) updateFolder(id int64, assignment string, value any) (Folder, error) {
+	ctx := context.Background()
+	result, err := s.db.ExecContext(
+		ctx,
+		"UPDATE playlist_folders SET "+assignment+", updated_at = ? WHERE id = ?",
+		value,
+		time.Now().UTC().Format(time.RFC3339),
+		id,
+	)
+	if err != nil {
+		return Folder{}, fmt.Errorf("update playlist folder %d: %w", id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return Folder{}, fmt.Errorf("read updated playlist folder count: %w", err)
+	}
+	if rowsAffected == 0 {
+		return Folder{}, ErrFolderNotFound
+	}
+
+	s.afterMutation(Change{FolderID: id})
+	return s.getFolder(ctx, id)
+}
+
+func (s *Service) getFolder(ctx context.Context, id int64) (Folder, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT id, name, parent_id, created_at, updated_at FROM playlist_folders WHERE id = ?", id)
+	folder, err := scanFolder(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Folder{}, ErrFolderNotFound
+	}
+	if err != nil {
+		return Folder{}, fmt.Errorf("get playlist folder %d: %w", id, err)
+	}
+
+	return folder, nil
+}
+
+type folderScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanFolder(row folderScanner) (Folder, error) {
+	var folder Folder
+	var parentID sql.NullInt64
+	if err := row.Scan(&folder.ID, &folder.Name, &parentID, &folder.CreatedAt, &folder.UpdatedAt); err != nil {
+		return Folder{}, err
+	}
+	if parentID.Valid {
+		folder.ParentID = &parentID.Int64
+	}
+
+	return folder, nil
+}
+
+func playlistTags(ctx context.Context, database *sql.DB, id int64) ([]string, error) {
+	rows, err := database.QueryContext(ctx, "SELECT tag FROM playlist_tags WHERE playlist_id = ? ORDER BY tag", id)
+	if err != nil {
+		return nil, fmt.Errorf("list tags for playlist %d: %w", id, err)
+	}
+	defer rows.Close()
+
+	tags := make([]string, 0)
+	for rows.Next() {
+		var tag string
+		if scanErr := rows.Scan(&tag); scanErr != nil {
+			return nil, fmt.Errorf("scan tag for playlist %d: %w", id, scanErr)
+		}
+		tags = append(tags, tag)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate tags for playlist %d: %w", id, rowsErr)
+	}
+
+	return tags, nil
+}
+
+func replaceTags(ctx context.Context, tx *sql.Tx, id int64, tags []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM playlist_tags WHERE playlist_id = ?", id); err != nil {
+		return fmt.Errorf("clear playlist %d tags: %w", id, err)
+	}
+
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO playlist_tags(playlist_id, tag) VALUES (?, ?)", id, tag); err != nil {
+			return fmt.Errorf("insert playlist %d tag %s: %w", id, tag, err)
+		}
+	}
+
+	return nil
+}
+
+func normalizeFolderName(name string) (string, error) {
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return "", errors.New("folder name is required")
+	}
+	if len([]rune(trimmed)) > maxPlaylistNameLength {
+		return "", fmt.Errorf("folder name must be at most %d characters", maxPlaylistNameLength)
+	}
+
+	return trimmed, nil
+}
+
+func normalizeTag(tag string) string {
+	visible := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, tag)
+
+	return strings.ToLower(strings.Join(strings.Fields(visible), " "))
+}
+
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		normalizedTag := normalizeTag(tag)
+		if normalizedTag == "" {
+			continue
+		}
+		if len([]rune(normalizedTag)) > maxPlaylistTagLength {
+			return nil, fmt.Errorf("playlist tags must be at most %d characters", maxPlaylistTagLength)
+		}
+		if _, duplicate := seen[normalizedTag]; duplicate {
+			continue
+		}
+		seen[normalizedTag] = struct{}{}
+		normalized = append(normalized, normalizedTag)
+	}
+
+	sort.Strings(normalized)
+	return normalized, nil
+}
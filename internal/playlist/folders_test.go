@@ -0,0 +1,94 @@
+package playlist
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/rzxx/ben/internal/db"
+)
+
+func TestFoldersMoveAndDeleteKeepPlaylists(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.Bootstrap(filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatalf("bootstrap test database: %v", err)
+	}
+	defer database.Close()
+	service := NewService(database, nil)
+
+	parent, err := service.CreateFolder("Moods", nil)
+	if err != nil {
+		t.Fatalf("create parent folder: %v", err)
+	}
+	child, err := service.CreateFolder("Evening", &parent.ID)
+	if err != nil {
+		t.Fatalf("create child folder: %v", err)
+	}
+	if _, err := service.MoveFolder(parent.ID, &child.ID); err == nil {
+		t.Fatalf("expected moving a folder into its subfolder to fail")
+	}
+	if renamed, err := service.RenameFolder(child.ID, "  Night  "); err != nil || renamed.Name != "Night" {
+		t.Fatalf("rename folder: %+v %v", renamed, err)
+	}
+
+	created, err := service.Create("Wind Down", nil)
+	if err != nil {
+		t.Fatalf("create playlist: %v", err)
+	}
+	moved, err := service.MovePlaylist(created.ID, &child.ID)
+	if err != nil || moved.FolderID == nil || *moved.FolderID != child.ID {
+		t.Fatalf("move playlist: %+v %v", moved, err)
+	}
+
+	if err := service.DeleteFolder(child.ID); err != nil {
+		t.Fatalf("delete folder: %v", err)
+	}
+	detail, err := service.Get(created.ID)
+	if err != nil {
+		t.Fatalf("get playlist after folder delete: %v", err)
+	}
+	if detail.Playlist.FolderID == nil || *detail.Playlist.FolderID != parent.ID {
+		t.Fatalf("expected playlist to move up to the parent folder, got %v", detail.Playlist.FolderID)
+	}
+}
+
+func TestPlaylistTagsNormalizeAndFilter(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.Bootstrap(filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatalf("bootstrap test database: %v", err)
+	}
+	defer database.Close()
+	service := NewService(database, nil)
+
+	workout, err := service.Create("Run", nil)
+	if err != nil {
+		t.Fatalf("create playlist: %v", err)
+	}
+	if _, err := service.Create("Sunday", nil); err != nil {
+		t.Fatalf("create playlist: %v", err)
+	}
+
+	tagged, err := service.SetTags(workout.ID, []string{"Workout", " workout ", "high  energy", ""})
+	if err != nil {
+		t.Fatalf("set tags: %v", err)
+	}
+	if len(tagged.Tags) != 2 || tagged.Tags[0] != "high energy" || tagged.Tags[1] != "workout" {
+		t.Fatalf("unexpected normalized tags %v", tagged.Tags)
+	}
+
+	filtered, err := service.ListByTag("WORKOUT")
+	if err != nil {
+		t.Fatalf("list by tag: %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].ID != workout.ID {
+		t.Fatalf("expected only the tagged playlist, got %+v", filtered)
+	}
+
+	tags, err := service.ListTags()
+	if err != nil || len(tags) != 2 || tags[1].PlaylistCount != 1 {
+		t.Fatalf("unexpected tag list %+v %v", tags, err)
+	}
+}
@@ -0,0 +1,127 @@
+package playlist
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+const m3uHeader = "#EXTM3U"
+
+const m3uPlaylistDirective = "#PLAYLIST:"
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+func isPlaylistFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".m3u8", ".m3u":
+		return true
+	default:
+		return false
+	}
+}
+
+// parseM3U returns the #PLAYLIST name (if any) and the entry paths of an
+// extended M3U document. Relative entries are resolved against baseDir.
+func parseM3U(content []byte, baseDir string) (string, []string) {
+	content = bytes.TrimPrefix(content, utf8BOM)
+
+	name := ""
+	paths := make([]string, 0)
+	for _, rawLine := range strings.Split(string(content), "\n") {
+		line := strings.TrimSpace(strings.TrimSuffix(rawLine, "\r"))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			if strings.HasPrefix(strings.ToUpper(line), m3uPlaylistDirective) {
+				name = strings.TrimSpace(line[len(m3uPlaylistDirective):])
+			}
+			continue
+		}
+
+		if resolved := resolveM3UEntry(line, baseDir); resolved != "" {
+			paths = append(paths, resolved)
+		}
+	}
+
+	return name, paths
+}
+
+func resolveM3UEntry(entry string, baseDir string) string {
+	if strings.HasPrefix(strings.ToLower(entry), "file://") {
+		parsed, err := url.Parse(entry)
+		if err != nil {
+			return ""
+		}
+
+		entryPath := parsed.Path
+		if runtime.GOOS == "windows" {
+			entryPath = strings.TrimPrefix(entryPath, "/")
+		}
+		return filepath.Clean(filepath.FromSlash(entryPath))
+	}
+
+	if strings.Contains(entry, "://") {
+		return ""
+	}
+
+	entryPath := entry
+	if runtime.GOOS != "windows" {
+		entryPath = strings.ReplaceAll(entryPath, "\\", "/")
+	}
+	entryPath = filepath.FromSlash(entryPath)
+	if !filepath.IsAbs(entryPath) {
+		entryPath = filepath.Join(baseDir, entryPath)
+	}
+
+	return filepath.Clean(entryPath)
+}
+
+// formatM3U renders tracks as an extended M3U document. Paths are written
+// relative to baseDir when possible so the file stays valid for other players
+// that see the same music folder under a different mount point.
+func formatM3U(name string, tracks []library.TrackSummary, baseDir string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString(m3uHeader + "\n")
+	buffer.WriteString(m3uPlaylistDirective + name + "\n")
+
+	for _, track := range tracks {
+		durationSeconds := -1
+		if track.DurationMS != nil && *track.DurationMS > 0 {
+			durationSeconds = *track.DurationMS / 1000
+		}
+
+		entryPath := track.Path
+		if relativePath, err := filepath.Rel(baseDir, track.Path); err == nil {
+			entryPath = relativePath
+		}
+
+		fmt.Fprintf(&buffer, "#EXTINF:%d,%s - %s\n", durationSeconds, track.Artist, track.Title)
+		buffer.WriteString(entryPath + "\n")
+	}
+
+	return buffer.Bytes()
+}
+
+func playlistFileName(name string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+
+	sanitized = strings.Trim(sanitized, " .")
+	if sanitized == "" {
+		sanitized = "Playlist"
+	}
+
+	return sanitized + ".m3u8"
+}
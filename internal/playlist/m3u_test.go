@@ -0,0 +1,64 @@
+package playlist
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+func TestParseM3UResolvesRelativeEntriesAndName(t *testing.T) {
+	t.Parallel()
+
+	baseDir := filepath.Join(string(filepath.Separator)+"music", "playlists")
+	content := "\xEF\xBB\xBF#EXTM3U\r\n#PLAYLIST:Road Trip\r\n#EXTINF:215,Artist - Song\r\n../Artist/Album/01 Song.flac\r\n\r\nhttp://example.com/stream.mp3\r\n"
+
+	name, paths := parseM3U([]byte(content), baseDir)
+	if name != "Road Trip" {
+		t.Fatalf("expected playlist name %q, got %q", "Road Trip", name)
+	}
+
+	expected := []string{filepath.Join(string(filepath.Separator)+"music", "Artist", "Album", "01 Song.flac")}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("unexpected entry paths: got %v, want %v", paths, expected)
+	}
+}
+
+func TestFormatM3UWritesRelativePaths(t *testing.T) {
+	t.Parallel()
+
+	baseDir := filepath.Join(string(filepath.Separator)+"music", "playlists")
+	durationMS := 215000
+	tracks := []library.TrackSummary{{
+		Title:      "Song",
+		Artist:     "Artist",
+		DurationMS: &durationMS,
+		Path:       filepath.Join(string(filepath.Separator)+"music", "Artist", "Song.flac"),
+	}}
+
+	content := string(formatM3U("Road Trip", tracks, baseDir))
+	if !strings.HasPrefix(content, "#EXTM3U\n#PLAYLIST:Road Trip\n") {
+		t.Fatalf("missing playlist header: %q", content)
+	}
+	if !strings.Contains(content, "#EXTINF:215,Artist - Song\n") {
+		t.Fatalf("missing EXTINF line: %q", content)
+	}
+
+	_, paths := parseM3U([]byte(content), baseDir)
+	if len(paths) != 1 || paths[0] != tracks[0].Path {
+		t.Fatalf("expected round-trip path %q, got %v", tracks[0].Path, paths)
+	}
+}
+
+func TestPlaylistFileNameSanitizesReservedCharacters(t *testing.T) {
+	t.Parallel()
+
+	if got := playlistFileName(`AC/DC: Best?`); got != "AC_DC_ Best_.m3u8" {
+		t.Fatalf("unexpected file name %q", got)
+	}
+	if got := playlistFileName("  ..  "); got != "Playlist.m3u8" {
+		t.Fatalf("unexpected fallback file name %q", got)
+	}
+}
@@ -0,0 +1,117 @@
+package playlist
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxPlaybackCrossfadeMS matches the player's own crossfade limit.
+const maxPlaybackCrossfadeMS = 12000
+
+// PlaybackSettings is the playback behavior applied while a playlist plays
+// from the queue. Nil fields leave the user's current setting alone. Gapless
+// turns crossfade off and wins over CrossfadeMS.
+type PlaybackSettings struct {
+	Shuffle     *bool   `json:"shuffle,omitempty"`
+	RepeatMode  *string `json:"repeatMode,omitempty"`
+	CrossfadeMS *int    `json:"crossfadeMs,omitempty"`
+	Gapless     *bool   `json:"gapless,omitempty"`
+}
+
+// IsEmpty reports whether the settings change nothing.
+func (p PlaybackSettings) IsEmpty() bool {
+	return p.Shuffle == nil && p.RepeatMode == nil && p.CrossfadeMS == nil && p.Gapless == nil
+}
+
+func (s *Service) GetPlaybackSettings(id int64) (PlaybackSettings, error) {
+	var playbackJSON sql.NullString
+	err := s.db.QueryRowContext(context.Background(), "SELECT playback_json FROM playlists WHERE id = ?", id).Scan(&playbackJSON)
+	if errors.Is(err, sql.ErrNoRows) {
+		return PlaybackSettings{}, ErrPlaylistNotFound
+	}
+	if err != nil {
+		return PlaybackSettings{}, fmt.Errorf("get playlist %d playback settings: %w", id, err)
+	}
+
+	return decodePlaybackSettings(playbackJSON), nil
+}
+
+// SetPlaybackSettings stores the playback settings of a playlist; empty
+// settings remove them.
+func (s *Service) SetPlaybackSettings(id int64, settings PlaybackSettings) (Playlist, error) {
+	normalized, err := normalizePlaybackSettings(settings)
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	ctx := context.Background()
+	result, err := s.db.ExecContext(ctx, "UPDATE playlists SET playback_json = ? WHERE id = ?", encodePlaybackSettings(normalized), id)
+	if err != nil {
+		return Playlist{}, fmt.Errorf("update playlist %d playback settings: %w", id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return Playlist{}, fmt.Errorf("read updated playlist count: %w", err)
+	}
+	if rowsAffected == 0 {
+		return Playlist{}, ErrPlaylistNotFound
+	}
+
+	s.afterMutation(Change{PlaylistID: id})
+	return s.getPlaylist(ctx, id)
+}
+
+func normalizePlaybackSettings(settings PlaybackSettings) (PlaybackSettings, error) {
+	normalized := settings
+	if settings.RepeatMode != nil {
+		mode := strings.ToLower(strings.TrimSpace(*settings.RepeatMode))
+		switch mode {
+		case "off", "all", "one":
+			normalized.RepeatMode = &mode
+		case "":
+			normalized.RepeatMode = nil
+		default:
+			return PlaybackSettings{}, fmt.Errorf("unsupported repeat mode %q", *settings.RepeatMode)
+		}
+	}
+	if settings.CrossfadeMS != nil && (*settings.CrossfadeMS < 0 || *settings.CrossfadeMS > maxPlaybackCrossfadeMS) {
+		return PlaybackSettings{}, fmt.Errorf("crossfade must be between 0 and %d ms", maxPlaybackCrossfadeMS)
+	}
+
+	return normalized, nil
+}
+
+// encodePlaybackSettings returns the stored form of settings, NULL when they
+// change nothing.
+func encodePlaybackSettings(settings PlaybackSettings) any {
+	if settings.IsEmpty() {
+		return nil
+	}
+
+	encoded, err := json.Marshal(settings)
+	if err != nil {
+		return nil
+	}
+
+	return string(encoded)
+}
+
+// decodePlaybackSettings reads stored settings; unreadable values count as
+// no settings rather than breaking playlist listing.
+func decodePlaybackSettings(value sql.NullString) PlaybackSettings {
+	if !value.Valid || strings.TrimSpace(value.String) == "" {
+		return PlaybackSettings{}
+	}
+
+	var settings PlaybackSettings
+	if err := json.Unmarshal([]byte(value.String), &settings); err != nil {
+		return PlaybackSettings{}
+	}
+
+	return settings
+}
@@ -0,0 +1,56 @@
+package playlist
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/rzxx/ben/internal/db"
+)
+
+func TestPlaybackSettingsRoundTripAndSurviveRestore(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.Bootstrap(filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatalf("bootstrap test database: %v", err)
+	}
+	defer database.Close()
+	service := NewService(database, nil)
+
+	created, err := service.Create("Gym", nil)
+	if err != nil {
+		t.Fatalf("create playlist: %v", err)
+	}
+
+	shuffle := true
+	repeatMode := " ALL "
+	crossfadeMS := 3000
+	updated, err := service.SetPlaybackSettings(created.ID, PlaybackSettings{Shuffle: &shuffle, RepeatMode: &repeatMode, CrossfadeMS: &crossfadeMS})
+	if err != nil {
+		t.Fatalf("set playback settings: %v", err)
+	}
+	if updated.Playback == nil || *updated.Playback.RepeatMode != "all" || *updated.Playback.CrossfadeMS != 3000 {
+		t.Fatalf("unexpected playback settings %+v", updated.Playback)
+	}
+
+	tooLong := 60000
+	if _, err := service.SetPlaybackSettings(created.ID, PlaybackSettings{CrossfadeMS: &tooLong}); err == nil {
+		t.Fatalf("expected out-of-range crossfade to be rejected")
+	}
+
+	snapshot, err := service.Snapshot(created.ID)
+	if err != nil {
+		t.Fatalf("snapshot playlist: %v", err)
+	}
+	if err := service.Delete(created.ID); err != nil {
+		t.Fatalf("delete playlist: %v", err)
+	}
+	if _, err := service.Restore(snapshot); err != nil {
+		t.Fatalf("restore playlist: %v", err)
+	}
+
+	restored, err := service.GetPlaybackSettings(created.ID)
+	if err != nil || restored.Shuffle == nil || !*restored.Shuffle {
+		t.Fatalf("expected restored playlist to keep its playback settings, got %+v %v", restored, err)
+	}
+}
@@ -0,0 +1,688 @@
+package playlist
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rzxx/ben/internal/eventbus"
+	"github.com/rzxx/ben/internal/library"
+)
+
+const EventChanged = "playlist:changed"
+
+const maxPlaylistNameLength = 200
+
+var ErrPlaylistNotFound = errors.New("playlist not found")
+
+type Emitter func(eventName string, payload any)
+
+type Playlist struct {
+	ID         int64             `json:"id"`
+	Name       string            `json:"name"`
+	TrackCount int               `json:"trackCount"`
+	DurationMS int               `json:"durationMs"`
+	SyncPath   *string           `json:"syncPath,omitempty"`
+	FolderID   *int64            `json:"folderId,omitempty"`
+	Tags       []string          `json:"tags"`
+	Playback   *PlaybackSettings `json:"playback,omitempty"`
+	CreatedAt  string            `json:"createdAt"`
+	UpdatedAt  string            `json:"updatedAt"`
+}
+
+type Detail struct {
+	Playlist Playlist               `json:"playlist"`
+	Tracks   []library.TrackSummary `json:"tracks"`
+}
+
+// Snapshot holds what is needed to recreate a playlist after it has been
+// deleted, including entries whose files are currently missing.
+type Snapshot struct {
+	ID        int64
+	Name      string
+	CreatedAt string
+	FolderID  *int64
+	Tags      []string
+	Playback  PlaybackSettings
+	TrackIDs  []int64
+}
+
+// Change reports a changed playlist, or a changed folder when FolderID is
+// set and PlaylistID is zero.
+type Change struct {
+	PlaylistID int64 `json:"playlistId"`
+	FolderID   int64 `json:"folderId,omitempty"`
+	Deleted    bool  `json:"deleted"`
+
+	syncPath string
+	fromSync bool
+}
+
+type ChangeListener func(change Change)
+
+type Service struct {
+	mu       sync.Mutex
+	db       *sql.DB
+	emit     Emitter
+	onChange ChangeListener
+}
+
+func NewService(database *sql.DB, bus *eventbus.Bus) *Service {
+	return &Service{db: database, emit: bus.Publish}
+}
+
+func (s *Service) SetOnChange(listener ChangeListener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = listener
+}
+
+func (s *Service) List() ([]Playlist, error) {
+	rows, err := s.db.QueryContext(context.Background(), playlistSelectSQL+`
+		GROUP BY p.id
+		ORDER BY LOWER(p.name), p.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list playlists: %w", err)
+	}
+	defer rows.Close()
+
+	playlists := make([]Playlist, 0)
+	for rows.Next() {
+		playlist, scanErr := scanPlaylist(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("scan playlist row: %w", scanErr)
+		}
+		playlists = append(playlists, playlist)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate playlist rows: %w", rowsErr)
+	}
+
+	return playlists, nil
+}
+
+func (s *Service) Get(id int64) (Detail, error) {
+	ctx := context.Background()
+
+	playlist, err := s.getPlaylist(ctx, id)
+	if err != nil {
+		return Detail{}, err
+	}
+
+	tracks, err := s.listTracks(ctx, id)
+	if err != nil {
+		return Detail{}, err
+	}
+
+	return Detail{Playlist: playlist, Tracks: tracks}, nil
+}
+
+func (s *Service) Create(name string, trackIDs []int64) (Playlist, error) {
+	normalizedName, err := normalizePlaylistName(name)
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Playlist{}, fmt.Errorf("begin create playlist tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	result, err := tx.ExecContext(ctx, "INSERT INTO playlists(name) VALUES (?)", normalizedName)
+	if err != nil {
+		return Playlist{}, fmt.Errorf("insert playlist: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return Playlist{}, fmt.Errorf("read playlist id: %w", err)
+	}
+
+	if err := replaceEntries(ctx, tx, id, trackIDs); err != nil {
+		return Playlist{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Playlist{}, fmt.Errorf("commit create playlist: %w", err)
+	}
+
+	s.afterMutation(Change{PlaylistID: id})
+	return s.getPlaylist(ctx, id)
+}
+
+func (s *Service) SetTracks(id int64, trackIDs []int64) (Playlist, error) {
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Playlist{}, fmt.Errorf("begin playlist update tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := touchPlaylist(ctx, tx, id); err != nil {
+		return Playlist{}, err
+	}
+
+	if err := replaceEntries(ctx, tx, id, trackIDs); err != nil {
+		return Playlist{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Playlist{}, fmt.Errorf("commit playlist update: %w", err)
+	}
+
+	s.afterMutation(Change{PlaylistID: id})
+	return s.getPlaylist(ctx, id)
+}
+
+func (s *Service) Delete(id int64) error {
+	ctx := context.Background()
+
+	var syncPath sql.NullString
+	err := s.db.QueryRowContext(ctx, "SELECT sync_path FROM playlists WHERE id = ?", id).Scan(&syncPath)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrPlaylistNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("get playlist %d: %w", id, err)
+	}
+
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM playlists WHERE id = ?", id); err != nil {
+		return fmt.Errorf("delete playlist %d: %w", id, err)
+	}
+
+	s.afterMutation(Change{PlaylistID: id, Deleted: true, syncPath: syncPath.String})
+	return nil
+}
+
+func (s *Service) Snapshot(id int64) (Snapshot, error) {
+	ctx := context.Background()
+
+	snapshot := Snapshot{ID: id}
+	var folderID sql.NullInt64
+	var playbackJSON sql.NullString
+	err := s.db.QueryRowContext(ctx, "SELECT name, created_at, folder_id, playback_json FROM playlists WHERE id = ?", id).Scan(
+		&snapshot.Name,
+		&snapshot.CreatedAt,
+		&folderID,
+		&playbackJSON,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Snapshot{}, ErrPlaylistNotFound
+	}
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("get playlist %d: %w", id, err)
+	}
+	if folderID.Valid {
+		snapshot.FolderID = &folderID.Int64
+	}
+	snapshot.Playback = decodePlaybackSettings(playbackJSON)
+
+	tags, err := playlistTags(ctx, s.db, id)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	snapshot.Tags = tags
+
+	trackIDs, err := s.entryTrackIDs(ctx, id)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	snapshot.TrackIDs = trackIDs
+
+	return snapshot, nil
+}
+
+// Restore recreates a deleted playlist under its original id. The sync path
+// is not restored; folder sync exports the playlist again as a new file.
+func (s *Service) Restore(snapshot Snapshot) (Playlist, error) {
+	normalizedName, err := normalizePlaylistName(snapshot.Name)
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Playlist{}, fmt.Errorf("begin restore playlist tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO playlists(id, name, created_at, updated_at, folder_id, playback_json)
+		 VALUES (?, ?, COALESCE(NULLIF(?, ''), strftime('%Y-%m-%dT%H:%M:%fZ', 'now')), ?, (SELECT id FROM playlist_folders WHERE id = ?), ?)`,
+		snapshot.ID,
+		normalizedName,
+		snapshot.CreatedAt,
+		time.Now().UTC().Format(time.RFC3339),
+		snapshot.FolderID,
+		encodePlaybackSettings(snapshot.Playback),
+	); err != nil {
+		return Playlist{}, fmt.Errorf("restore playlist %d: %w", snapshot.ID, err)
+	}
+
+	if err := replaceTags(ctx, tx, snapshot.ID, snapshot.Tags); err != nil {
+		return Playlist{}, err
+	}
+
+	if err := replaceEntries(ctx, tx, snapshot.ID, snapshot.TrackIDs); err != nil {
+		return Playlist{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Playlist{}, fmt.Errorf("commit restore playlist: %w", err)
+	}
+
+	s.afterMutation(Change{PlaylistID: snapshot.ID})
+	return s.getPlaylist(ctx, snapshot.ID)
+}
+
+func (s *Service) entryTrackIDs(ctx context.Context, id int64) ([]int64, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT track_id FROM playlist_entries WHERE playlist_id = ? ORDER BY position ASC, id ASC", id)
+	if err != nil {
+		return nil, fmt.Errorf("list playlist entries for %d: %w", id, err)
+	}
+	defer rows.Close()
+
+	trackIDs := make([]int64, 0)
+	for rows.Next() {
+		var trackID int64
+		if scanErr := rows.Scan(&trackID); scanErr != nil {
+			return nil, fmt.Errorf("scan playlist entry row for %d: %w", id, scanErr)
+		}
+		trackIDs = append(trackIDs, trackID)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate playlist entries for %d: %w", id, rowsErr)
+	}
+
+	return trackIDs, nil
+}
+
+// importFromFile creates or refreshes the playlist bound to syncPath. It is
+// used by folder sync, so the resulting change is not exported back to disk.
+func (s *Service) importFromFile(name string, syncPath string, syncHash string, trackIDs []int64) (int64, error) {
+	normalizedName, err := normalizePlaylistName(name)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin playlist import tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO playlists(name, sync_path, sync_hash, updated_at)
+		 VALUES (?, ?, ?, ?)
+		 ON CONFLICT(sync_path) DO UPDATE SET
+		 	name = excluded.name,
+		 	sync_hash = excluded.sync_hash,
+		 	updated_at = excluded.updated_at`,
+		normalizedName,
+		syncPath,
+		syncHash,
+		now,
+	); err != nil {
+		return 0, fmt.Errorf("upsert playlist from %s: %w", syncPath, err)
+	}
+
+	var id int64
+	if err := tx.QueryRowContext(ctx, "SELECT id FROM playlists WHERE sync_path = ?", syncPath).Scan(&id); err != nil {
+		return 0, fmt.Errorf("read imported playlist id for %s: %w", syncPath, err)
+	}
+
+	if err := replaceEntries(ctx, tx, id, trackIDs); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit playlist import: %w", err)
+	}
+
+	s.afterMutation(Change{PlaylistID: id, syncPath: syncPath, fromSync: true})
+	return id, nil
+}
+
+func (s *Service) syncState(syncPath string) (int64, string, bool, error) {
+	var (
+		id       int64
+		syncHash sql.NullString
+	)
+	err := s.db.QueryRowContext(
+		context.Background(),
+		"SELECT id, sync_hash FROM playlists WHERE sync_path = ?",
+		syncPath,
+	).Scan(&id, &syncHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, "", false, nil
+	}
+	if err != nil {
+		return 0, "", false, fmt.Errorf("get playlist for %s: %w", syncPath, err)
+	}
+
+	return id, syncHash.String, true, nil
+}
+
+func (s *Service) setSyncState(id int64, syncPath string, syncHash string) error {
+	if _, err := s.db.ExecContext(
+		context.Background(),
+		"UPDATE playlists SET sync_path = ?, sync_hash = ? WHERE id = ?",
+		syncPath,
+		syncHash,
+		id,
+	); err != nil {
+		return fmt.Errorf("record sync state for playlist %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// deleteFromFile deletes the playlist bound to a playlist file that was
+// removed from the sync folder.
+func (s *Service) deleteFromFile(syncPath string) error {
+	ctx := context.Background()
+
+	var id int64
+	err := s.db.QueryRowContext(ctx, "SELECT id FROM playlists WHERE sync_path = ?", syncPath).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get playlist for %s: %w", syncPath, err)
+	}
+
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM playlists WHERE id = ?", id); err != nil {
+		return fmt.Errorf("delete playlist of removed file %s: %w", syncPath, err)
+	}
+
+	s.afterMutation(Change{PlaylistID: id, Deleted: true, syncPath: syncPath, fromSync: true})
+	return nil
+}
+
+func (s *Service) resolveTrackIDsByPath(paths []string) ([]int64, error) {
+	ctx := context.Background()
+	trackIDs := make([]int64, 0, len(paths))
+
+	query := `
+		SELECT t.id
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		WHERE f.file_exists = 1 AND f.path = ?
+	`
+	if runtime.GOOS == "windows" {
+		query = `
+			SELECT t.id
+			FROM tracks t
+			JOIN files f ON f.id = t.file_id
+			WHERE f.file_exists = 1 AND LOWER(f.path) = LOWER(?)
+		`
+	}
+
+	for _, path := range paths {
+		var trackID int64
+		err := s.db.QueryRowContext(ctx, query, path).Scan(&trackID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("resolve playlist entry %s: %w", path, err)
+		}
+		trackIDs = append(trackIDs, trackID)
+	}
+
+	return trackIDs, nil
+}
+
+func (s *Service) getPlaylist(ctx context.Context, id int64) (Playlist, error) {
+	rows, err := s.db.QueryContext(ctx, playlistSelectSQL+`
+		WHERE p.id = ?
+		GROUP BY p.id
+	`, id)
+	if err != nil {
+		return Playlist{}, fmt.Errorf("get playlist %d: %w", id, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if rowsErr := rows.Err(); rowsErr != nil {
+			return Playlist{}, fmt.Errorf("get playlist %d: %w", id, rowsErr)
+		}
+		return Playlist{}, ErrPlaylistNotFound
+	}
+
+	playlist, err := scanPlaylist(rows)
+	if err != nil {
+		return Playlist{}, fmt.Errorf("scan playlist %d: %w", id, err)
+	}
+
+	return playlist, nil
+}
+
+func (s *Service) listTracks(ctx context.Context, id int64) ([]library.TrackSummary, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			t.id,
+			COALESCE(NULLIF(TRIM(t.title), ''), 'Unknown Title') AS track_title,
+			COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist') AS track_artist,
+			COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album') AS track_album,
+			COALESCE(NULLIF(TRIM(t.album_artist), ''), COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) AS track_album_artist,
+			t.disc_no,
+			t.track_no,
+			t.duration_ms,
+			f.path,
+			cover.cache_path
+		FROM playlist_entries pe
+		JOIN tracks t ON t.id = pe.track_id
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN covers cover ON cover.source_file_id = t.file_id
+		WHERE pe.playlist_id = ?
+		  AND f.file_exists = 1
+		ORDER BY pe.position ASC, pe.id ASC
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("list playlist tracks for %d: %w", id, err)
+	}
+	defer rows.Close()
+
+	tracks := make([]library.TrackSummary, 0)
+	for rows.Next() {
+		var track library.TrackSummary
+		var discNo sql.NullInt64
+		var trackNo sql.NullInt64
+		var durationMS sql.NullInt64
+		var coverPath sql.NullString
+		if scanErr := rows.Scan(
+			&track.ID,
+			&track.Title,
+			&track.Artist,
+			&track.Album,
+			&track.AlbumArtist,
+			&discNo,
+			&trackNo,
+			&durationMS,
+			&track.Path,
+			&coverPath,
+		); scanErr != nil {
+			return nil, fmt.Errorf("scan playlist track row for %d: %w", id, scanErr)
+		}
+		track.DiscNo = intPointer(discNo)
+		track.TrackNo = intPointer(trackNo)
+		track.DurationMS = intPointer(durationMS)
+		track.CoverPath = stringPointer(coverPath)
+		tracks = append(tracks, track)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate playlist tracks for %d: %w", id, rowsErr)
+	}
+
+	return tracks, nil
+}
+
+func (s *Service) afterMutation(change Change) {
+	s.mu.Lock()
+	emitter := s.emit
+	listener := s.onChange
+	s.mu.Unlock()
+
+	if emitter != nil {
+		emitter(EventChanged, change)
+	}
+	if listener != nil {
+		listener(change)
+	}
+}
+
+const playlistSelectSQL = `
+	SELECT
+		p.id,
+		p.name,
+		COUNT(t.id) AS track_count,
+		COALESCE(SUM(COALESCE(t.duration_ms, 0)), 0) AS duration_ms,
+		p.sync_path,
+		p.folder_id,
+		(SELECT GROUP_CONCAT(tag, char(31)) FROM (
+			SELECT tag FROM playlist_tags WHERE playlist_id = p.id ORDER BY tag
+		)) AS tags,
+		p.playback_json,
+		p.created_at,
+		p.updated_at
+	FROM playlists p
+	LEFT JOIN playlist_entries pe ON pe.playlist_id = p.id
+	LEFT JOIN tracks t ON t.id = pe.track_id
+		AND EXISTS (SELECT 1 FROM files f WHERE f.id = t.file_id AND f.file_exists = 1)
+`
+
+func scanPlaylist(rows *sql.Rows) (Playlist, error) {
+	var playlist Playlist
+	var syncPath sql.NullString
+	var folderID sql.NullInt64
+	var tags sql.NullString
+	var playbackJSON sql.NullString
+	if err := rows.Scan(
+		&playlist.ID,
+		&playlist.Name,
+		&playlist.TrackCount,
+		&playlist.DurationMS,
+		&syncPath,
+		&folderID,
+		&tags,
+		&playbackJSON,
+		&playlist.CreatedAt,
+		&playlist.UpdatedAt,
+	); err != nil {
+		return Playlist{}, err
+	}
+
+	playlist.SyncPath = stringPointer(syncPath)
+	if folderID.Valid {
+		playlist.FolderID = &folderID.Int64
+	}
+	playlist.Tags = make([]string, 0)
+	if tags.Valid && tags.String != "" {
+		playlist.Tags = strings.Split(tags.String, "\x1f")
+	}
+	if playback := decodePlaybackSettings(playbackJSON); !playback.IsEmpty() {
+		playlist.Playback = &playback
+	}
+	return playlist, nil
+}
+
+func touchPlaylist(ctx context.Context, tx *sql.Tx, id int64) error {
+	result, err := tx.ExecContext(
+		ctx,
+		"UPDATE playlists SET updated_at = ? WHERE id = ?",
+		time.Now().UTC().Format(time.RFC3339),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("update playlist %d: %w", id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("read updated playlist count: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrPlaylistNotFound
+	}
+
+	return nil
+}
+
+func replaceEntries(ctx context.Context, tx *sql.Tx, id int64, trackIDs []int64) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM playlist_entries WHERE playlist_id = ?", id); err != nil {
+		return fmt.Errorf("clear playlist %d entries: %w", id, err)
+	}
+
+	for position, trackID := range trackIDs {
+		if _, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO playlist_entries(playlist_id, position, track_id) VALUES (?, ?, ?)",
+			id,
+			position,
+			trackID,
+		); err != nil {
+			return fmt.Errorf("insert playlist %d entry %d: %w", id, trackID, err)
+		}
+	}
+
+	return nil
+}
+
+func normalizePlaylistName(name string) (string, error) {
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return "", errors.New("playlist name is required")
+	}
+	if len([]rune(trimmed)) > maxPlaylistNameLength {
+		return "", fmt.Errorf("playlist name must be at most %d characters", maxPlaylistNameLength)
+	}
+
+	return trimmed, nil
+}
+
+func intPointer(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+
+	intValue := int(value.Int64)
+	return &intValue
+}
+
+func stringPointer(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(value.String)
+	if trimmed == "" {
+		return nil
+	}
+
+	return &trimmed
+}
@@ -0,0 +1,110 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+// AlbumContextUpNextSettingKey controls how playing a single track from an
+// album page changes the queue. When enabled, the rest of the album is
+// inserted as up next and the existing queue is kept; otherwise the queue is
+// replaced by the album.
+const AlbumContextUpNextSettingKey = "queue.album_context_up_next"
+
+func (s *Service) SetAlbumContextUpNext(enabled bool) (State, error) {
+	if s.settings != nil {
+		if err := s.settings.Set(context.Background(), AlbumContextUpNextSettingKey, strconv.FormatBool(enabled)); err != nil {
+			return s.GetState(), err
+		}
+	}
+
+	s.mu.Lock()
+	s.albumContextUpNext = enabled
+	s.touchLocked()
+	state := s.snapshotLocked()
+	s.mu.Unlock()
+
+	s.afterMutation(state)
+	return state, nil
+}
+
+func (s *Service) loadAlbumContextUpNext() {
+	if s.settings == nil {
+		return
+	}
+
+	enabled, err := strconv.ParseBool(s.settings.GetString(context.Background(), AlbumContextUpNextSettingKey, "false"))
+	if err == nil {
+		s.albumContextUpNext = enabled
+	}
+}
+
+// PlayFromAlbum starts the first of trackIDs, which are the played track
+// followed by the rest of its album, and applies the album context policy.
+// With up next enabled the tracks are inserted after the current entry; any
+// upcoming entries left over from an earlier play of the same album are
+// dropped so the album is not queued twice, while everything else stays.
+func (s *Service) PlayFromAlbum(trackIDs []int64, source Source) (State, error) {
+	normalizedSource, err := normalizeSource(source)
+	if err != nil {
+		return s.GetState(), err
+	}
+	if normalizedSource.Kind != SourceAlbum {
+		return s.GetState(), errors.New("album context requires an album source")
+	}
+
+	s.mu.Lock()
+	upNext := s.albumContextUpNext
+	s.mu.Unlock()
+	if !upNext {
+		return s.SetQueueFromSource(trackIDs, 0, normalizedSource)
+	}
+
+	trackIDs, _, err = s.preferBestVersions(trackIDs, 0)
+	if err != nil {
+		return State{}, err
+	}
+
+	tracks, err := s.lookupTracks(trackIDs)
+	if err != nil {
+		return State{}, err
+	}
+	if len(tracks) == 0 {
+		return s.GetState(), errors.New("album context requires at least one track")
+	}
+
+	s.mu.Lock()
+	sources := s.sourcesForEntriesLocked()
+	insertAt := s.currentIndex + 1
+	if insertAt < 0 || insertAt > len(s.entries) {
+		insertAt = len(s.entries)
+	}
+
+	entries := make([]library.TrackSummary, 0, len(s.entries)+len(tracks))
+	nextSources := make([]Source, 0, len(s.entries)+len(tracks))
+	entries = append(entries, s.entries[:insertAt]...)
+	nextSources = append(nextSources, sources[:insertAt]...)
+	entries = append(entries, tracks...)
+	nextSources = append(nextSources, repeatSource(normalizedSource, len(tracks))...)
+	for index := insertAt; index < len(s.entries); index++ {
+		if sources[index] == normalizedSource {
+			continue
+		}
+		entries = append(entries, s.entries[index])
+		nextSources = append(nextSources, sources[index])
+	}
+
+	s.entries = entries
+	s.sources = nextSources
+	s.currentIndex = insertAt
+	s.syncShuffleAfterQueueMutationLocked()
+	s.touchLocked()
+	state := s.snapshotLocked()
+	s.mu.Unlock()
+
+	s.afterMutation(state)
+	return state, nil
+}
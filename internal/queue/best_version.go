@@ -0,0 +1,258 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+// PreferBestVersionSettingKey controls whether queue building swaps tracks
+// for the highest-quality version among their linked versions and detected
+// duplicates, e.g. the FLAC rip of a song that is also in the library as
+// MP3.
+const PreferBestVersionSettingKey = "queue.prefer_best_version"
+
+type trackVersion struct {
+	trackID int64
+	groupID int64
+	quality library.AudioQuality
+}
+
+func (s *Service) SetPreferBestVersion(enabled bool) (State, error) {
+	if s.settings != nil {
+		if err := s.settings.Set(context.Background(), PreferBestVersionSettingKey, strconv.FormatBool(enabled)); err != nil {
+			return s.GetState(), err
+		}
+	}
+
+	s.mu.Lock()
+	s.preferBestVersion = enabled
+	s.touchLocked()
+	state := s.snapshotLocked()
+	s.mu.Unlock()
+
+	s.afterMutation(state)
+	return state, nil
+}
+
+func (s *Service) loadPreferBestVersion() {
+	s.preferBestVersion = true
+	if s.settings == nil {
+		return
+	}
+
+	enabled, err := strconv.ParseBool(s.settings.GetString(context.Background(), PreferBestVersionSettingKey, "true"))
+	if err == nil {
+		s.preferBestVersion = enabled
+	}
+}
+
+// preferBestVersions replaces each track with the best playable version of
+// its group, the versions linked by hand together with the duplicates the
+// library detects, and keeps only the first occurrence of a group, so a
+// queue built from both copies of an album plays each song once. Banned
+// versions are never picked. Pass -1 as startIndex when there is none.
+func (s *Service) preferBestVersions(trackIDs []int64, startIndex int) ([]int64, int, error) {
+	s.mu.Lock()
+	enabled := s.preferBestVersion
+	banned := make(map[int64]struct{}, len(s.banned))
+	for trackID := range s.banned {
+		banned[trackID] = struct{}{}
+	}
+	s.mu.Unlock()
+
+	if !enabled || s.db == nil || len(trackIDs) == 0 {
+		return trackIDs, startIndex, nil
+	}
+
+	requested := uniqueIDs(trackIDs)
+	versions, err := s.lookupTrackVersions(requested)
+	if err != nil {
+		return nil, 0, err
+	}
+	duplicates, err := library.NewDuplicateFinder(s.db).DuplicatesOf(context.Background(), requested)
+	if err != nil {
+		return nil, 0, err
+	}
+	versions = mergeDuplicateGroups(versions, duplicates)
+
+	byTrack := make(map[int64]trackVersion, len(versions))
+	best := make(map[int64]trackVersion)
+	for _, version := range versions {
+		byTrack[version.trackID] = version
+		if _, isBanned := banned[version.trackID]; isBanned {
+			continue
+		}
+		if current, ok := best[version.groupID]; !ok || betterVersion(version, current) {
+			best[version.groupID] = version
+		}
+	}
+
+	picked, pickedStart := pickBestVersions(trackIDs, startIndex, byTrack, best)
+	return picked, pickedStart, nil
+}
+
+// pickBestVersions swaps each grouped track for the best version of its group
+// unless the requested one is just as good, drops repeated groups, and maps
+// startIndex onto the result. A start track whose group already appeared
+// earlier starts at that earlier entry.
+func pickBestVersions(trackIDs []int64, startIndex int, byTrack map[int64]trackVersion, best map[int64]trackVersion) ([]int64, int) {
+	picked := make([]int64, 0, len(trackIDs))
+	groupIndex := make(map[int64]int, len(trackIDs))
+	pickedStart := startIndex
+	for index, trackID := range trackIDs {
+		requested, linked := byTrack[trackID]
+		if !linked {
+			if index == startIndex {
+				pickedStart = len(picked)
+			}
+			picked = append(picked, trackID)
+			continue
+		}
+		if earlier, seen := groupIndex[requested.groupID]; seen {
+			if index == startIndex {
+				pickedStart = earlier
+			}
+			continue
+		}
+
+		if version, ok := best[requested.groupID]; ok && betterVersion(version, requested) {
+			trackID = version.trackID
+		}
+		if index == startIndex {
+			pickedStart = len(picked)
+		}
+		groupIndex[requested.groupID] = len(picked)
+		picked = append(picked, trackID)
+	}
+
+	return picked, pickedStart
+}
+
+// betterVersion ranks versions by library.BetterQuality.
+func betterVersion(candidate trackVersion, current trackVersion) bool {
+	return library.BetterQuality(candidate.quality, current.quality)
+}
+
+// mergeDuplicateGroups joins the link groups of versions with the detected
+// duplicate groups, merging groups that share a track. A merged group is
+// keyed by the smallest id in it.
+func mergeDuplicateGroups(versions []trackVersion, duplicates []library.DuplicateGroup) []trackVersion {
+	if len(duplicates) == 0 {
+		return versions
+	}
+
+	parent := make(map[int64]int64)
+	var find func(int64) int64
+	find = func(id int64) int64 {
+		next, ok := parent[id]
+		if !ok || next == id {
+			return id
+		}
+		root := find(next)
+		parent[id] = root
+		return root
+	}
+	union := func(a int64, b int64) {
+		rootA, rootB := find(a), find(b)
+		if rootA != rootB {
+			parent[max(rootA, rootB)] = min(rootA, rootB)
+		}
+	}
+
+	byTrack := make(map[int64]trackVersion, len(versions))
+	for _, version := range versions {
+		byTrack[version.trackID] = version
+		union(version.groupID, version.trackID)
+	}
+	for _, group := range duplicates {
+		for _, track := range group.Tracks {
+			if _, ok := byTrack[track.TrackID]; !ok {
+				byTrack[track.TrackID] = trackVersion{trackID: track.TrackID, quality: track.Quality()}
+			}
+			union(group.Tracks[0].TrackID, track.TrackID)
+		}
+	}
+
+	merged := make([]trackVersion, 0, len(byTrack))
+	for _, version := range byTrack {
+		version.groupID = find(version.trackID)
+		merged = append(merged, version)
+	}
+	sort.Slice(merged, func(i int, j int) bool {
+		if merged[i].groupID != merged[j].groupID {
+			return merged[i].groupID < merged[j].groupID
+		}
+		return merged[i].trackID < merged[j].trackID
+	})
+
+	return merged
+}
+
+// lookupTrackVersions lists the playable members of every link group that
+// contains one of trackIDs. Tracks that are not linked are left out.
+func (s *Service) lookupTrackVersions(trackIDs []int64) ([]trackVersion, error) {
+	placeholders := make([]string, len(trackIDs))
+	args := make([]any, len(trackIDs))
+	for i, id := range trackIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	query := fmt.Sprintf(`
+		WITH member AS (
+			SELECT t.id AS track_id, COALESCE(l.canonical_track_id, t.id) AS group_id
+			FROM tracks t
+			LEFT JOIN track_links l ON l.track_id = t.id
+		),
+		requested AS (
+			SELECT DISTINCT group_id FROM member WHERE track_id IN (%s)
+		),
+		linked AS (
+			SELECT group_id
+			FROM member
+			WHERE group_id IN (SELECT group_id FROM requested)
+			GROUP BY group_id
+			HAVING COUNT(*) > 1
+		)
+		SELECT
+			m.track_id,
+			m.group_id,
+			LOWER(COALESCE(TRIM(t.codec), '')),
+			COALESCE(t.bit_depth, 0),
+			COALESCE(t.sample_rate, 0),
+			COALESCE(t.bitrate, 0)
+		FROM member m
+		JOIN tracks t ON t.id = m.track_id
+		JOIN files f ON f.id = t.file_id
+		WHERE f.file_exists = 1
+		  AND m.group_id IN (SELECT group_id FROM linked)
+		ORDER BY m.group_id, m.track_id
+	`, strings.Join(placeholders, ","))
+
+	rows, err := s.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query track versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]trackVersion, 0)
+	for rows.Next() {
+		var version trackVersion
+		var codec string
+		if err := rows.Scan(&version.trackID, &version.groupID, &codec, &version.quality.BitDepth, &version.quality.SampleRate, &version.quality.Bitrate); err != nil {
+			return nil, fmt.Errorf("scan track version: %w", err)
+		}
+		version.quality.Lossless = library.IsLosslessCodec(codec)
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate track versions: %w", err)
+	}
+
+	return versions, nil
+}
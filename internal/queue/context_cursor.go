@@ -0,0 +1,197 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxContextCursors bounds how many albums, artists and playlists keep a
+// remembered position; the least recently played ones are forgotten first.
+const maxContextCursors = 200
+
+// ContextCursor is the remembered position inside an album, artist or
+// playlist: the track that was current when the queue last played from it,
+// and that track's position among the context's entries.
+type ContextCursor struct {
+	Source       Source `json:"source"`
+	TrackID      int64  `json:"trackId"`
+	ContextIndex int    `json:"contextIndex"`
+	UpdatedAt    string `json:"updatedAt"`
+}
+
+type contextCursorKey struct {
+	key          string
+	trackID      int64
+	contextIndex int
+}
+
+// contextKey identifies the context a source belongs to regardless of
+// letter case. Plain queue entries, searches and radios are not contexts
+// worth resuming and return "".
+func contextKey(source Source) string {
+	switch source.Kind {
+	case SourceAlbum:
+		return SourceAlbum + ":" + strings.ToLower(source.AlbumTitle) + "\x00" + strings.ToLower(source.AlbumArtist)
+	case SourceArtist:
+		return SourceArtist + ":" + strings.ToLower(source.ArtistName)
+	case SourcePlaylist:
+		return fmt.Sprintf("%s:%d", SourcePlaylist, source.PlaylistID)
+	default:
+		return ""
+	}
+}
+
+// contextCursorAt returns the cursor for the current entry of state, or
+// false when it does not come from a resumable context.
+func contextCursorAt(state State) (ContextCursor, bool) {
+	if state.CurrentIndex < 0 || state.CurrentIndex >= len(state.Entries) || state.CurrentIndex >= len(state.Sources) {
+		return ContextCursor{}, false
+	}
+
+	source := state.Sources[state.CurrentIndex]
+	if contextKey(source) == "" {
+		return ContextCursor{}, false
+	}
+
+	contextIndex := 0
+	for index := 0; index < state.CurrentIndex; index++ {
+		if state.Sources[index] == source {
+			contextIndex++
+		}
+	}
+
+	return ContextCursor{
+		Source:       source,
+		TrackID:      state.Entries[state.CurrentIndex].ID,
+		ContextIndex: contextIndex,
+	}, true
+}
+
+// resumeIndex finds where to start trackIDs for a remembered cursor. The
+// remembered position wins when it still holds the track, so a track that
+// appears twice resumes at the right copy; otherwise the first copy of the
+// track is used, and a context that lost the track starts over.
+func resumeIndex(trackIDs []int64, cursor ContextCursor) int {
+	if cursor.ContextIndex >= 0 && cursor.ContextIndex < len(trackIDs) && trackIDs[cursor.ContextIndex] == cursor.TrackID {
+		return cursor.ContextIndex
+	}
+	for index, trackID := range trackIDs {
+		if trackID == cursor.TrackID {
+			return index
+		}
+	}
+
+	return 0
+}
+
+// ResumeFromSource replaces the queue with trackIDs from source, starting at
+// the track that was playing when the queue last left that context.
+// Contexts without a remembered position start at the first track.
+func (s *Service) ResumeFromSource(trackIDs []int64, source Source) (State, error) {
+	normalizedSource, err := normalizeSource(source)
+	if err != nil {
+		return s.GetState(), err
+	}
+
+	startIndex := 0
+	cursor, found, err := s.GetContextCursor(normalizedSource)
+	if err != nil {
+		return s.GetState(), err
+	}
+	if found {
+		startIndex = resumeIndex(trackIDs, cursor)
+	}
+
+	return s.SetQueueFromSource(trackIDs, startIndex, normalizedSource)
+}
+
+// GetContextCursor returns the remembered position inside source.
+func (s *Service) GetContextCursor(source Source) (ContextCursor, bool, error) {
+	normalizedSource, err := normalizeSource(source)
+	if err != nil {
+		return ContextCursor{}, false, err
+	}
+
+	key := contextKey(normalizedSource)
+	if key == "" || s.db == nil {
+		return ContextCursor{}, false, nil
+	}
+
+	cursor := ContextCursor{Source: normalizedSource}
+	err = s.db.QueryRowContext(
+		context.Background(),
+		"SELECT track_id, context_index, updated_at FROM queue_context_cursors WHERE context_key = ?",
+		key,
+	).Scan(&cursor.TrackID, &cursor.ContextIndex, &cursor.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ContextCursor{}, false, nil
+	}
+	if err != nil {
+		return ContextCursor{}, false, fmt.Errorf("get context cursor: %w", err)
+	}
+
+	return cursor, true, nil
+}
+
+// rememberContextCursor stores the position of the current entry within its
+// context. It only writes when the position moved, since every queue
+// mutation passes through here.
+func (s *Service) rememberContextCursor(state State) {
+	if s.db == nil {
+		return
+	}
+
+	cursor, ok := contextCursorAt(state)
+	if !ok {
+		return
+	}
+
+	current := contextCursorKey{key: contextKey(cursor.Source), trackID: cursor.TrackID, contextIndex: cursor.ContextIndex}
+	s.mu.Lock()
+	unchanged := s.lastCursor == current
+	s.lastCursor = current
+	s.mu.Unlock()
+	if unchanged {
+		return
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO queue_context_cursors(context_key, source, track_id, context_index, updated_at)
+		VALUES (?, ?, ?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+		ON CONFLICT(context_key) DO UPDATE SET
+			source = excluded.source,
+			track_id = excluded.track_id,
+			context_index = excluded.context_index,
+			updated_at = excluded.updated_at
+	`,
+		current.key,
+		encodeSource(cursor.Source),
+		cursor.TrackID,
+		cursor.ContextIndex,
+	); err != nil {
+		return
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM queue_context_cursors
+		WHERE context_key NOT IN (
+			SELECT context_key FROM queue_context_cursors ORDER BY updated_at DESC LIMIT ?
+		)
+	`, maxContextCursors); err != nil {
+		return
+	}
+
+	_ = tx.Commit()
+}
@@ -0,0 +1,96 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+// ReplaceFromAlbumOfIndex replaces the queue with the album of the entry at
+// index, for "play this album" on a queue row. The playing track keeps
+// playing: it stays current inside the album when it belongs to it and is
+// kept in front of the album otherwise.
+func (s *Service) ReplaceFromAlbumOfIndex(index int) (State, error) {
+	entry, err := s.entryAt(index)
+	if err != nil {
+		return s.GetState(), err
+	}
+
+	trackIDs, err := library.NewBrowseRepository(s.db).GetAlbumQueueTrackIDs(context.Background(), entry.Album, entry.AlbumArtist)
+	if err != nil {
+		return s.GetState(), err
+	}
+
+	return s.replaceKeepingCurrent(trackIDs, Source{Kind: SourceAlbum, AlbumTitle: entry.Album, AlbumArtist: entry.AlbumArtist})
+}
+
+// ReplaceFromArtistOfIndex is ReplaceFromAlbumOfIndex for the artist of the
+// entry, queued in album order.
+func (s *Service) ReplaceFromArtistOfIndex(index int) (State, error) {
+	entry, err := s.entryAt(index)
+	if err != nil {
+		return s.GetState(), err
+	}
+
+	trackIDs, err := library.NewBrowseRepository(s.db).GetArtistQueueTrackIDs(context.Background(), entry.Artist)
+	if err != nil {
+		return s.GetState(), err
+	}
+
+	return s.replaceKeepingCurrent(trackIDs, Source{Kind: SourceArtist, ArtistName: entry.Artist})
+}
+
+func (s *Service) entryAt(index int) (library.TrackSummary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.validIndexLocked(index) {
+		return library.TrackSummary{}, fmt.Errorf("queue index %d out of range", index)
+	}
+
+	return s.entries[index], nil
+}
+
+func (s *Service) replaceKeepingCurrent(trackIDs []int64, source Source) (State, error) {
+	normalizedSource, err := normalizeSource(source)
+	if err != nil {
+		return s.GetState(), err
+	}
+
+	tracks, err := s.lookupTracks(trackIDs)
+	if err != nil {
+		return State{}, err
+	}
+
+	s.mu.Lock()
+	entries := tracks
+	sources := repeatSource(normalizedSource, len(tracks))
+	currentIndex := normalizeCurrentIndex(len(tracks), 0)
+	if s.validIndexLocked(s.currentIndex) {
+		current := s.entries[s.currentIndex]
+		currentIndex = -1
+		for position, track := range tracks {
+			if track.ID == current.ID {
+				currentIndex = position
+				break
+			}
+		}
+		if currentIndex < 0 {
+			entries = append([]library.TrackSummary{current}, tracks...)
+			sources = append([]Source{s.sourceAtLocked(s.currentIndex)}, sources...)
+			currentIndex = 0
+		}
+	}
+
+	s.entries = entries
+	s.sources = sources
+	s.currentIndex = currentIndex
+	s.syncShuffleAfterQueueMutationLocked()
+	s.touchLocked()
+	state := s.snapshotLocked()
+	s.mu.Unlock()
+
+	s.afterMutation(state)
+	return state, nil
+}
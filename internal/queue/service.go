@@ -1,7 +1,6 @@
 package queue
 
 import (
-	"ben/internal/library"
 	"context"
 	"database/sql"
 	"errors"
@@ -10,6 +9,10 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/rzxx/ben/internal/eventbus"
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/settings"
 )
 
 const EventStateChanged = "queue:state"
@@ -44,20 +47,25 @@ type ShuffleDebugState struct {
 }
 
 type State struct {
-	Entries      []library.TrackSummary `json:"entries"`
-	CurrentIndex int                    `json:"currentIndex"`
-	CurrentTrack *library.TrackSummary  `json:"currentTrack,omitempty"`
-	RepeatMode   string                 `json:"repeatMode"`
-	Shuffle      bool                   `json:"shuffle"`
-	ShuffleDebug *ShuffleDebugState     `json:"shuffleDebug,omitempty"`
-	Total        int                    `json:"total"`
-	UpdatedAt    string                 `json:"updatedAt"`
+	Entries            []library.TrackSummary `json:"entries"`
+	Sources            []Source               `json:"sources"`
+	CurrentIndex       int                    `json:"currentIndex"`
+	CurrentTrack       *library.TrackSummary  `json:"currentTrack,omitempty"`
+	RepeatMode         string                 `json:"repeatMode"`
+	Shuffle            bool                   `json:"shuffle"`
+	ShuffleVideos      bool                   `json:"shuffleVideos"`
+	AlbumContextUpNext bool                   `json:"albumContextUpNext"`
+	PreferBestVersion  bool                   `json:"preferBestVersion"`
+	ShuffleDebug       *ShuffleDebugState     `json:"shuffleDebug,omitempty"`
+	Total              int                    `json:"total"`
+	UpdatedAt          string                 `json:"updatedAt"`
 }
 
 type Service struct {
 	mu                    sync.Mutex
 	db                    *sql.DB
 	entries               []library.TrackSummary
+	sources               []Source
 	currentIndex          int
 	repeatMode            string
 	shuffle               bool
@@ -66,28 +74,56 @@ type Service struct {
 	lastShuffle           []int
 	shuffleSessionVersion int
 	shuffleCycleVersion   int
+	banned                map[int64]struct{}
+	settings              *settings.Store
+	shuffleVideos         bool
+	albumContextUpNext    bool
+	preferBestVersion     bool
+	lastCursor            contextCursorKey
+	hydrated              bool
 	updatedAt             time.Time
 	emit                  Emitter
 	onChange              ChangeListener
 	rng                   *rand.Rand
 }
 
-func NewService(database *sql.DB) *Service {
+func NewService(database *sql.DB, bus *eventbus.Bus) *Service {
+	service := NewDeferredService(database, bus)
+	service.loadSnapshot()
+	return service
+}
+
+// NewDeferredService returns a service with an empty queue, leaving the saved
+// queue to Hydrate so a large queue never delays startup.
+func NewDeferredService(database *sql.DB, bus *eventbus.Bus) *Service {
 	service := &Service{
 		db:           database,
+		emit:         bus.Publish,
 		currentIndex: -1,
 		repeatMode:   RepeatModeOff,
 		rng:          rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
+	if database != nil {
+		service.settings = settings.NewStore(database)
+	}
 
-	service.loadSnapshot()
+	service.loadShuffleVideos()
+	service.loadAlbumContextUpNext()
+	service.loadPreferBestVersion()
 	return service
 }
 
-func (s *Service) SetEmitter(emitter Emitter) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.emit = emitter
+// Hydrate loads the saved queue of a deferred service and emits it. It
+// reports false without touching the queue when the queue was already loaded
+// or was changed since startup, so edits made before hydration are kept.
+func (s *Service) Hydrate() (State, bool) {
+	if !s.loadSnapshot() {
+		return s.GetState(), false
+	}
+
+	state := s.GetState()
+	s.emitState(state)
+	return state, true
 }
 
 func (s *Service) SetOnChange(listener ChangeListener) {
@@ -96,6 +132,60 @@ func (s *Service) SetOnChange(listener ChangeListener) {
 	s.onChange = listener
 }
 
+// SetBannedTracks replaces the set of tracks that shuffle and autoplay skip.
+func (s *Service) SetBannedTracks(trackIDs []int64) {
+	s.mu.Lock()
+	s.banned = make(map[int64]struct{}, len(trackIDs))
+	for _, trackID := range trackIDs {
+		s.banned[trackID] = struct{}{}
+	}
+	if s.shuffle {
+		s.lastShuffle = nil
+		s.resetShuffleSessionLocked()
+	}
+	s.mu.Unlock()
+}
+
+func (s *Service) SetTrackBanned(trackID int64, banned bool) {
+	s.mu.Lock()
+	if s.banned == nil {
+		s.banned = make(map[int64]struct{})
+	}
+	if banned {
+		s.banned[trackID] = struct{}{}
+	} else {
+		delete(s.banned, trackID)
+	}
+	if s.shuffle {
+		s.lastShuffle = nil
+		s.resetShuffleSessionLocked()
+	}
+	s.mu.Unlock()
+}
+
+// SetTrackRating refreshes the rating and favorite flag shown on queue
+// entries of a track after they change in the library.
+func (s *Service) SetTrackRating(trackID int64, rating *int, favorite bool) {
+	s.mu.Lock()
+	changed := false
+	for index := range s.entries {
+		if s.entries[index].ID != trackID {
+			continue
+		}
+		s.entries[index].Rating = rating
+		s.entries[index].Favorite = favorite
+		changed = true
+	}
+	if !changed {
+		s.mu.Unlock()
+		return
+	}
+	state := s.snapshotLocked()
+	s.mu.Unlock()
+
+	s.emitState(state)
+}
+
 func (s *Service) GetState() State {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -154,6 +244,22 @@ func (s *Service) SetShuffle(enabled bool) State {
 }
 
 func (s *Service) SetQueue(trackIDs []int64, startIndex int) (State, error) {
+	return s.SetQueueFromSource(trackIDs, startIndex, Source{Kind: SourceQueue})
+}
+
+// SetQueueFromSource replaces the queue and records source as the origin of
+// every entry.
+func (s *Service) SetQueueFromSource(trackIDs []int64, startIndex int, source Source) (State, error) {
+	normalizedSource, err := normalizeSource(source)
+	if err != nil {
+		return s.GetState(), err
+	}
+
+	trackIDs, startIndex, err = s.preferBestVersions(trackIDs, startIndex)
+	if err != nil {
+		return State{}, err
+	}
+
 	tracks, err := s.lookupTracks(trackIDs)
 	if err != nil {
 		return State{}, err
@@ -161,6 +267,7 @@ func (s *Service) SetQueue(trackIDs []int64, startIndex int) (State, error) {
 
 	s.mu.Lock()
 	s.entries = tracks
+	s.sources = repeatSource(normalizedSource, len(tracks))
 	s.currentIndex = normalizeCurrentIndex(len(tracks), startIndex)
 	s.syncShuffleAfterQueueMutationLocked()
 	s.touchLocked()
@@ -172,12 +279,27 @@ func (s *Service) SetQueue(trackIDs []int64, startIndex int) (State, error) {
 }
 
 func (s *Service) AppendTracks(trackIDs []int64) (State, error) {
+	return s.AppendTracksFromSource(trackIDs, Source{Kind: SourceQueue})
+}
+
+func (s *Service) AppendTracksFromSource(trackIDs []int64, source Source) (State, error) {
+	normalizedSource, err := normalizeSource(source)
+	if err != nil {
+		return s.GetState(), err
+	}
+
+	trackIDs, _, err = s.preferBestVersions(trackIDs, -1)
+	if err != nil {
+		return State{}, err
+	}
+
 	tracks, err := s.lookupTracks(trackIDs)
 	if err != nil {
 		return State{}, err
 	}
 
 	s.mu.Lock()
+	s.sources = append(s.sourcesForEntriesLocked(), repeatSource(normalizedSource, len(tracks))...)
 	s.entries = append(s.entries, tracks...)
 	if s.currentIndex < 0 && len(s.entries) > 0 {
 		s.currentIndex = 0
@@ -199,6 +321,8 @@ func (s *Service) RemoveTrack(index int) (State, error) {
 		return state, fmt.Errorf("queue index %d out of range", index)
 	}
 
+	s.sources = s.sourcesForEntriesLocked()
+	s.sources = append(s.sources[:index], s.sources[index+1:]...)
 	s.entries = append(s.entries[:index], s.entries[index+1:]...)
 	if len(s.entries) == 0 {
 		s.currentIndex = -1
@@ -243,6 +367,7 @@ func (s *Service) SetCurrentIndex(index int) (State, error) {
 func (s *Service) Clear() State {
 	s.mu.Lock()
 	s.entries = nil
+	s.sources = nil
 	s.currentIndex = -1
 	s.shuffleOrder = nil
 	s.shuffleTrail = nil
@@ -257,6 +382,54 @@ func (s *Service) Clear() State {
 	return state
 }
 
+// Restore replaces the queue with the entries, sources and current track of
+// an earlier state. Entries whose files have gone missing are dropped.
+func (s *Service) Restore(previous State) (State, error) {
+	if len(previous.Entries) == 0 {
+		return s.Clear(), nil
+	}
+
+	trackIDs := make([]int64, len(previous.Entries))
+	for index, track := range previous.Entries {
+		trackIDs[index] = track.ID
+	}
+
+	tracks, err := s.lookupTracks(trackIDs)
+	if err != nil {
+		return s.GetState(), err
+	}
+
+	sources := make([]Source, 0, len(tracks))
+	currentIndex := 0
+	next := 0
+	for index, track := range previous.Entries {
+		if next >= len(tracks) || tracks[next].ID != track.ID {
+			continue
+		}
+		source := Source{Kind: SourceQueue}
+		if index < len(previous.Sources) {
+			source = previous.Sources[index]
+		}
+		sources = append(sources, source)
+		if index == previous.CurrentIndex {
+			currentIndex = next
+		}
+		next++
+	}
+
+	s.mu.Lock()
+	s.entries = tracks
+	s.sources = sources
+	s.currentIndex = currentIndex
+	s.syncShuffleAfterQueueMutationLocked()
+	s.touchLocked()
+	state := s.snapshotLocked()
+	s.mu.Unlock()
+
+	s.afterMutation(state)
+	return state, nil
+}
+
 func (s *Service) Next() (State, bool) {
 	return s.advance(nextModeManual)
 }
@@ -345,17 +518,34 @@ func (s *Service) resolveNextIndexLocked(mode nextMode, consume bool) (int, bool
 		return nextIndex, true
 	}
 
-	if s.currentIndex < total-1 {
-		return s.currentIndex + 1, true
+	for index := s.currentIndex + 1; index < total; index++ {
+		if !s.isBannedIndexLocked(index) {
+			return index, true
+		}
 	}
 
 	if s.repeatMode == RepeatModeAll {
-		return 0, true
+		for index := 0; index <= s.currentIndex; index++ {
+			if !s.isBannedIndexLocked(index) {
+				return index, true
+			}
+		}
 	}
 
 	return -1, false
 }
 
+// isBannedIndexLocked reports whether the entry at index is a banned track.
+// Banned entries stay in the queue so they can still be picked directly.
+func (s *Service) isBannedIndexLocked(index int) bool {
+	if index < 0 || index >= len(s.entries) {
+		return false
+	}
+
+	_, banned := s.banned[s.entries[index].ID]
+	return banned
+}
+
 func (s *Service) isShuffleSessionConsistentLocked() bool {
 	if !s.shuffle {
 		return true
@@ -446,10 +636,14 @@ func (s *Service) lookupTracks(trackIDs []int64) ([]library.TrackSummary, error)
 			t.track_no,
 			t.duration_ms,
 			f.path,
-			cover.cache_path
+			cover.cache_path,
+			f.is_video,
+			tr.rating,
+			COALESCE(tr.favorite, 0)
 		FROM tracks t
 		JOIN files f ON f.id = t.file_id
 		LEFT JOIN covers cover ON cover.source_file_id = t.file_id
+		LEFT JOIN track_ratings tr ON tr.track_id = t.id
 		WHERE f.file_exists = 1
 		  AND t.id IN (%s)
 	`, strings.Join(placeholders, ","))
@@ -467,6 +661,7 @@ func (s *Service) lookupTracks(trackIDs []int64) ([]library.TrackSummary, error)
 		var trackNo sql.NullInt64
 		var durationMS sql.NullInt64
 		var coverPath sql.NullString
+		var rating sql.NullInt64
 		if scanErr := rows.Scan(
 			&track.ID,
 			&track.Title,
@@ -478,6 +673,9 @@ func (s *Service) lookupTracks(trackIDs []int64) ([]library.TrackSummary, error)
 			&durationMS,
 			&track.Path,
 			&coverPath,
+			&track.IsVideo,
+			&rating,
+			&track.Favorite,
 		); scanErr != nil {
 			return nil, fmt.Errorf("scan queue track row: %w", scanErr)
 		}
@@ -485,6 +683,7 @@ func (s *Service) lookupTracks(trackIDs []int64) ([]library.TrackSummary, error)
 		track.TrackNo = intPointer(trackNo)
 		track.DurationMS = intPointer(durationMS)
 		track.CoverPath = stringPointer(coverPath)
+		track.Rating = intPointer(rating)
 		trackByID[track.ID] = track
 	}
 
@@ -510,6 +709,7 @@ func (s *Service) lookupTracks(trackIDs []int64) ([]library.TrackSummary, error)
 
 func (s *Service) afterMutation(state State) {
 	s.persistSnapshot(state)
+	s.rememberContextCursor(state)
 	s.emitState(state)
 	s.notifyChange(state)
 }
@@ -539,11 +739,15 @@ func (s *Service) snapshotLocked() State {
 	copy(entries, s.entries)
 
 	state := State{
-		Entries:      entries,
-		CurrentIndex: s.currentIndex,
-		RepeatMode:   s.repeatMode,
-		Shuffle:      s.shuffle,
-		Total:        len(entries),
+		Entries:            entries,
+		Sources:            s.sourcesForEntriesLocked(),
+		CurrentIndex:       s.currentIndex,
+		RepeatMode:         s.repeatMode,
+		Shuffle:            s.shuffle,
+		ShuffleVideos:      s.shuffleVideos,
+		AlbumContextUpNext: s.albumContextUpNext,
+		PreferBestVersion:  s.preferBestVersion,
+		Total:              len(entries),
 	}
 
 	if s.currentIndex >= 0 && s.currentIndex < len(entries) {
@@ -593,11 +797,13 @@ func (s *Service) shuffleDebugStateLocked() *ShuffleDebugState {
 
 func (s *Service) touchLocked() {
 	s.updatedAt = time.Now().UTC()
+	s.hydrated = true
 }
 
-func (s *Service) loadSnapshot() {
+// loadSnapshot reads the saved queue and reports whether it was applied.
+func (s *Service) loadSnapshot() bool {
 	if s.db == nil {
-		return
+		return false
 	}
 
 	ctx := context.Background()
@@ -614,7 +820,7 @@ func (s *Service) loadSnapshot() {
 		"SELECT current_track_id, repeat_mode, shuffle, updated_at FROM playback_state WHERE id = 1",
 	).Scan(&currentTrackID, &repeatMode, &shuffleInt, &updatedAt)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return
+		return false
 	}
 
 	rows, err := s.db.QueryContext(ctx, `
@@ -628,26 +834,34 @@ func (s *Service) loadSnapshot() {
 			t.track_no,
 			t.duration_ms,
 			f.path,
-			cover.cache_path
+			cover.cache_path,
+			f.is_video,
+			tr.rating,
+			COALESCE(tr.favorite, 0),
+			qe.source
 		FROM queue_entries qe
 		JOIN tracks t ON t.id = qe.track_id
 		JOIN files f ON f.id = t.file_id
 		LEFT JOIN covers cover ON cover.source_file_id = t.file_id
+		LEFT JOIN track_ratings tr ON tr.track_id = t.id
 		WHERE f.file_exists = 1
 		ORDER BY qe.position ASC, qe.id ASC
 	`)
 	if err != nil {
-		return
+		return false
 	}
 	defer rows.Close()
 
 	entries := make([]library.TrackSummary, 0)
+	sources := make([]Source, 0)
 	for rows.Next() {
 		var track library.TrackSummary
 		var discNo sql.NullInt64
 		var trackNo sql.NullInt64
 		var durationMS sql.NullInt64
 		var coverPath sql.NullString
+		var rating sql.NullInt64
+		var source sql.NullString
 		if scanErr := rows.Scan(
 			&track.ID,
 			&track.Title,
@@ -659,17 +873,23 @@ func (s *Service) loadSnapshot() {
 			&durationMS,
 			&track.Path,
 			&coverPath,
+			&track.IsVideo,
+			&rating,
+			&track.Favorite,
+			&source,
 		); scanErr != nil {
-			return
+			return false
 		}
 		track.DiscNo = intPointer(discNo)
 		track.TrackNo = intPointer(trackNo)
 		track.DurationMS = intPointer(durationMS)
 		track.CoverPath = stringPointer(coverPath)
+		track.Rating = intPointer(rating)
 		entries = append(entries, track)
+		sources = append(sources, decodeSource(source.String))
 	}
 	if rowsErr := rows.Err(); rowsErr != nil {
-		return
+		return false
 	}
 
 	newRepeatMode := RepeatModeOff
@@ -700,7 +920,13 @@ func (s *Service) loadSnapshot() {
 	}
 
 	s.mu.Lock()
+	if s.hydrated {
+		s.mu.Unlock()
+		return false
+	}
+	s.hydrated = true
 	s.entries = entries
+	s.sources = sources
 	s.currentIndex = currentIndex
 	s.repeatMode = newRepeatMode
 	s.shuffle = shuffleInt.Valid && shuffleInt.Int64 == 1
@@ -709,6 +935,7 @@ func (s *Service) loadSnapshot() {
 	}
 	s.updatedAt = loadedAt
 	s.mu.Unlock()
+	return true
 }
 
 func (s *Service) syncShuffleAfterQueueMutationLocked() {
@@ -788,7 +1015,7 @@ func (s *Service) refillShuffleOrderLocked() {
 
 	candidates := make([]int, 0, total-1)
 	for index := range s.entries {
-		if index == s.currentIndex {
+		if index == s.currentIndex || s.isBannedIndexLocked(index) || s.isSkippedVideoIndexLocked(index) {
 			continue
 		}
 		candidates = append(candidates, index)
@@ -1459,12 +1686,16 @@ func (s *Service) persistSnapshot(state State) {
 	}
 
 	for position, track := range state.Entries {
+		source := Source{Kind: SourceQueue}
+		if position < len(state.Sources) {
+			source = state.Sources[position]
+		}
 		if _, err := tx.ExecContext(
 			ctx,
 			"INSERT INTO queue_entries(position, track_id, source) VALUES (?, ?, ?)",
 			position,
 			track.ID,
-			"queue",
+			encodeSource(source),
 		); err != nil {
 			return
 		}
@@ -1,14 +1,17 @@
 package queue
 
 import (
-	"ben/internal/db"
-	"ben/internal/library"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"math/rand"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/rzxx/ben/internal/db"
+	"github.com/rzxx/ben/internal/library"
 )
 
 func TestAdvanceAutoplayRepeatModes(t *testing.T) {
@@ -61,6 +64,46 @@ func TestAdvanceAutoplayRepeatModes(t *testing.T) {
 	}
 }
 
+func TestBannedTracksAreSkippedByAutoplayAndShuffle(t *testing.T) {
+	t.Parallel()
+
+	service, database := newQueueServiceForTest(t)
+	defer database.Close()
+
+	first := insertTrackForTest(t, database, "Kept One")
+	banned := insertTrackForTest(t, database, "Banned")
+	third := insertTrackForTest(t, database, "Kept Two")
+
+	if _, err := service.SetQueue([]int64{first, banned, third}, 0); err != nil {
+		t.Fatalf("set queue: %v", err)
+	}
+	service.SetTrackBanned(banned, true)
+
+	state, moved := service.AdvanceAutoplay()
+	if !moved || state.CurrentTrack == nil || state.CurrentTrack.ID != third {
+		t.Fatalf("expected autoplay to skip the banned track")
+	}
+
+	if _, err := service.SetCurrentIndex(1); err != nil {
+		t.Fatalf("select banned track: %v", err)
+	}
+	if current := service.CurrentTrack(); current == nil || current.ID != banned {
+		t.Fatalf("expected banned track to stay playable when picked directly")
+	}
+
+	if _, err := service.SetCurrentIndex(0); err != nil {
+		t.Fatalf("select first track: %v", err)
+	}
+	service.SetShuffle(true)
+	state, moved = service.Next()
+	if !moved || state.CurrentTrack == nil || state.CurrentTrack.ID != third {
+		t.Fatalf("expected shuffle to skip the banned track")
+	}
+	if _, moved = service.Next(); moved {
+		t.Fatalf("expected shuffle cycle to end without the banned track")
+	}
+}
+
 func TestQueueSnapshotRestoredOnStartup(t *testing.T) {
 	t.Parallel()
 
@@ -78,7 +121,7 @@ func TestQueueSnapshotRestoredOnStartup(t *testing.T) {
 	}
 	service.SetShuffle(true)
 
-	reloaded := NewService(database)
+	reloaded := NewService(database, nil)
 	state := reloaded.GetState()
 
 	if state.Total != 2 {
@@ -95,6 +138,83 @@ func TestQueueSnapshotRestoredOnStartup(t *testing.T) {
 	}
 }
 
+func TestDeferredQueueHydratesOnce(t *testing.T) {
+	t.Parallel()
+
+	service, database := newQueueServiceForTest(t)
+	defer database.Close()
+
+	first := insertTrackForTest(t, database, "Track A")
+	second := insertTrackForTest(t, database, "Track B")
+
+	if _, err := service.SetQueue([]int64{first, second}, 1); err != nil {
+		t.Fatalf("set queue: %v", err)
+	}
+
+	deferred := NewDeferredService(database, nil)
+	if total := deferred.GetState().Total; total != 0 {
+		t.Fatalf("expected an empty queue before hydration, got %d entries", total)
+	}
+
+	state, loaded := deferred.Hydrate()
+	if !loaded || state.Total != 2 {
+		t.Fatalf("expected hydration to load 2 entries, got loaded=%v total=%d", loaded, state.Total)
+	}
+	if _, loaded := deferred.Hydrate(); loaded {
+		t.Fatalf("expected a second hydration to be skipped")
+	}
+
+	edited := NewDeferredService(database, nil)
+	if _, err := edited.SetQueue([]int64{first}, 0); err != nil {
+		t.Fatalf("set queue before hydration: %v", err)
+	}
+	if state, loaded := edited.Hydrate(); loaded || state.Total != 1 {
+		t.Fatalf("expected hydration to keep the edited queue, got loaded=%v total=%d", loaded, state.Total)
+	}
+}
+
+func TestQueueSourcesPersistAndResolve(t *testing.T) {
+	t.Parallel()
+
+	service, database := newQueueServiceForTest(t)
+	defer database.Close()
+
+	first := insertTrackForTest(t, database, "Source One")
+	second := insertTrackForTest(t, database, "Source Two")
+
+	if _, err := service.SetQueueFromSource([]int64{first}, 0, Source{Kind: SourceSearch, Query: " one "}); err != nil {
+		t.Fatalf("set queue from source: %v", err)
+	}
+	if _, err := service.AppendTracks([]int64{second}); err != nil {
+		t.Fatalf("append tracks: %v", err)
+	}
+	if _, err := service.AppendTracksFromSource([]int64{second}, Source{Kind: SourcePlaylist}); err == nil {
+		t.Fatalf("expected playlist source without id to be rejected")
+	}
+
+	state := NewService(database, nil).GetState()
+	if len(state.Sources) != 2 {
+		t.Fatalf("expected 2 restored sources, got %d", len(state.Sources))
+	}
+	if state.Sources[0].Kind != SourceSearch || state.Sources[0].Query != "one" {
+		t.Fatalf("expected search source to be restored, got %+v", state.Sources[0])
+	}
+	if state.Sources[1].Kind != SourceQueue {
+		t.Fatalf("expected plain queue source, got %+v", state.Sources[1])
+	}
+
+	location, err := service.ResolveSource(0)
+	if err != nil {
+		t.Fatalf("resolve source: %v", err)
+	}
+	if !location.Available || location.TrackID != first || location.TrackPosition != -1 {
+		t.Fatalf("unexpected source location %+v", location)
+	}
+	if _, err := service.ResolveSource(5); err == nil {
+		t.Fatalf("expected out of range index to fail")
+	}
+}
+
 func TestShuffleNoRepeatsPerCycleAndStopsWhenRepeatOff(t *testing.T) {
 	t.Parallel()
 
@@ -617,6 +737,68 @@ func TestShuffleNextCycleIsNotTooCloseToPreviousCycle(t *testing.T) {
 	}
 }
 
+func TestResumeFromSourceReturnsToLastTrackOfContext(t *testing.T) {
+	t.Parallel()
+
+	service, database := newQueueServiceForTest(t)
+	defer database.Close()
+
+	first := insertTrackForTest(t, database, "Resume One")
+	second := insertTrackForTest(t, database, "Resume Two")
+	third := insertTrackForTest(t, database, "Resume Three")
+	playlist := Source{Kind: SourcePlaylist, PlaylistID: 7}
+	album := Source{Kind: SourceAlbum, AlbumTitle: "Album", AlbumArtist: "Artist"}
+
+	if _, err := service.SetQueueFromSource([]int64{first, second, third}, 0, playlist); err != nil {
+		t.Fatalf("play playlist: %v", err)
+	}
+	if _, err := service.SetCurrentIndex(2); err != nil {
+		t.Fatalf("move within playlist: %v", err)
+	}
+	if _, err := service.SetQueueFromSource([]int64{first, second}, 1, album); err != nil {
+		t.Fatalf("play album: %v", err)
+	}
+
+	state, err := service.ResumeFromSource([]int64{first, second, third}, playlist)
+	if err != nil {
+		t.Fatalf("resume playlist: %v", err)
+	}
+	if state.CurrentTrack == nil || state.CurrentTrack.ID != third {
+		t.Fatalf("expected playlist to resume at its third track, got %+v", state.CurrentTrack)
+	}
+
+	state, err = service.ResumeFromSource([]int64{second, first}, album)
+	if err != nil {
+		t.Fatalf("resume album: %v", err)
+	}
+	if state.CurrentTrack == nil || state.CurrentTrack.ID != second {
+		t.Fatalf("expected album to resume at the moved track, got %+v", state.CurrentTrack)
+	}
+
+	state, err = service.ResumeFromSource([]int64{first, second}, Source{Kind: SourceArtist, ArtistName: "Artist"})
+	if err != nil {
+		t.Fatalf("play unseen artist: %v", err)
+	}
+	if state.CurrentIndex != 0 {
+		t.Fatalf("expected a context without a cursor to start at the top, got %d", state.CurrentIndex)
+	}
+}
+
+func TestResumeIndexPrefersRememberedCopy(t *testing.T) {
+	t.Parallel()
+
+	cursor := ContextCursor{TrackID: 5, ContextIndex: 3}
+	if index := resumeIndex([]int64{5, 6, 7, 5}, cursor); index != 3 {
+		t.Fatalf("expected remembered copy at 3, got %d", index)
+	}
+	if index := resumeIndex([]int64{6, 5, 7}, cursor); index != 1 {
+		t.Fatalf("expected first copy after a reorder, got %d", index)
+	}
+	if index := resumeIndex([]int64{6, 7}, cursor); index != 0 {
+		t.Fatalf("expected a context without the track to start over, got %d", index)
+	}
+}
+
 func newQueueServiceForTest(t *testing.T) (*Service, *sql.DB) {
 	t.Helper()
 
@@ -626,7 +808,7 @@ func newQueueServiceForTest(t *testing.T) (*Service, *sql.DB) {
 		t.Fatalf("bootstrap test database: %v", err)
 	}
 
-	return NewService(database), database
+	return NewService(database, nil), database
 }
 
 func insertTrackForTest(t *testing.T, database *sql.DB, title string) int64 {
@@ -705,5 +887,254 @@ func insertTrackWithMetadataForTest(t *testing.T, database *sql.DB, title string
 		t.Fatalf("read track id: %v", err)
 	}
 
+	if _, err := database.Exec(`INSERT INTO track_artists(track_id, artist) VALUES (?, ?)`, trackID, artist); err != nil {
+		t.Fatalf("insert track artist row: %v", err)
+	}
+
 	return trackID
 }
+
+func TestPlayFromAlbumKeepsUserQueueWhenUpNextEnabled(t *testing.T) {
+	t.Parallel()
+
+	service, database := newQueueServiceForTest(t)
+	defer database.Close()
+
+	current := insertTrackForTest(t, database, "Current")
+	user := insertTrackForTest(t, database, "User Added")
+	albumFirst := insertTrackForTest(t, database, "Album One")
+	albumSecond := insertTrackForTest(t, database, "Album Two")
+	album := Source{Kind: SourceAlbum, AlbumTitle: "Album", AlbumArtist: "Artist"}
+
+	if _, err := service.SetQueue([]int64{current, user}, 0); err != nil {
+		t.Fatalf("set queue: %v", err)
+	}
+	if _, err := service.SetAlbumContextUpNext(true); err != nil {
+		t.Fatalf("enable album context: %v", err)
+	}
+	if _, err := service.PlayFromAlbum([]int64{albumFirst, albumSecond}, album); err != nil {
+		t.Fatalf("play from album: %v", err)
+	}
+	state, err := service.PlayFromAlbum([]int64{albumSecond}, album)
+	if err != nil {
+		t.Fatalf("play from album again: %v", err)
+	}
+
+	expected := []int64{current, albumFirst, albumSecond, user}
+	if len(state.Entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(state.Entries))
+	}
+	for index, trackID := range expected {
+		if state.Entries[index].ID != trackID {
+			t.Fatalf("expected track %d at %d, got %d", trackID, index, state.Entries[index].ID)
+		}
+	}
+	if state.CurrentIndex != 2 {
+		t.Fatalf("expected second album track to be current, got %d", state.CurrentIndex)
+	}
+	if !NewService(database, nil).GetState().AlbumContextUpNext {
+		t.Fatalf("expected album context preference to persist")
+	}
+}
+
+func TestReplaceFromArtistOfIndexKeepsPlayingTrack(t *testing.T) {
+	t.Parallel()
+
+	service, database := newQueueServiceForTest(t)
+	defer database.Close()
+
+	playing := insertTrackWithMetadataForTest(t, database, "Playing", "Other", "Elsewhere", 1, 1)
+	second := insertTrackWithMetadataForTest(t, database, "Second", "Singer", "Debut", 1, 2)
+	first := insertTrackWithMetadataForTest(t, database, "First", "Singer", "Debut", 1, 1)
+
+	if _, err := service.SetQueue([]int64{playing, second}, 0); err != nil {
+		t.Fatalf("set queue: %v", err)
+	}
+
+	state, err := service.ReplaceFromArtistOfIndex(1)
+	if err != nil {
+		t.Fatalf("replace from artist: %v", err)
+	}
+
+	expected := []int64{playing, first, second}
+	if len(state.Entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(state.Entries))
+	}
+	for index, trackID := range expected {
+		if state.Entries[index].ID != trackID {
+			t.Fatalf("expected track %d at %d, got %d", trackID, index, state.Entries[index].ID)
+		}
+	}
+	if state.CurrentIndex != 0 || state.Sources[1].Kind != SourceArtist || state.Sources[1].ArtistName != "Singer" {
+		t.Fatalf("expected the playing track first and artist sources after it, got %d %+v", state.CurrentIndex, state.Sources)
+	}
+
+	if _, err := service.SetCurrentIndex(2); err != nil {
+		t.Fatalf("set current index: %v", err)
+	}
+	state, err = service.ReplaceFromArtistOfIndex(1)
+	if err != nil {
+		t.Fatalf("replace from artist again: %v", err)
+	}
+	if len(state.Entries) != 2 || state.CurrentIndex != 1 || state.Entries[1].ID != second {
+		t.Fatalf("expected the playing track to stay current inside the artist, got %d of %d", state.CurrentIndex, len(state.Entries))
+	}
+}
+
+func TestSetQueuePrefersBestLinkedVersion(t *testing.T) {
+	t.Parallel()
+
+	service, database := newQueueServiceForTest(t)
+	defer database.Close()
+
+	intro := insertTrackForTest(t, database, "Intro")
+	lossy := insertTrackForTest(t, database, "Song MP3")
+	lossless := insertTrackForTest(t, database, "Song FLAC")
+	if _, err := database.Exec(`UPDATE tracks SET codec = 'mp3', bitrate = 320 WHERE id = ?`, lossy); err != nil {
+		t.Fatalf("set lossy codec: %v", err)
+	}
+	if _, err := database.Exec(`UPDATE tracks SET codec = 'flac', bit_depth = 16 WHERE id = ?`, lossless); err != nil {
+		t.Fatalf("set lossless codec: %v", err)
+	}
+	if _, err := database.Exec(`INSERT INTO track_links(track_id, canonical_track_id) VALUES (?, ?)`, lossless, lossy); err != nil {
+		t.Fatalf("link versions: %v", err)
+	}
+
+	state, err := service.SetQueue([]int64{intro, lossy, lossless}, 2)
+	if err != nil {
+		t.Fatalf("set queue: %v", err)
+	}
+	if len(state.Entries) != 2 || state.Entries[0].ID != intro || state.Entries[1].ID != lossless {
+		t.Fatalf("expected intro and the FLAC version, got %+v", state.Entries)
+	}
+	if state.CurrentIndex != 1 {
+		t.Fatalf("expected start to map onto the kept version, got %d", state.CurrentIndex)
+	}
+
+	if _, err := service.SetPreferBestVersion(false); err != nil {
+		t.Fatalf("disable best version: %v", err)
+	}
+	state, err = service.SetQueue([]int64{lossy}, 0)
+	if err != nil {
+		t.Fatalf("set queue without preference: %v", err)
+	}
+	if len(state.Entries) != 1 || state.Entries[0].ID != lossy {
+		t.Fatalf("expected requested version when preference is off, got %+v", state.Entries)
+	}
+}
+
+func TestSetQueuePrefersBestDetectedDuplicate(t *testing.T) {
+	t.Parallel()
+
+	service, database := newQueueServiceForTest(t)
+	defer database.Close()
+
+	lossy := insertTrackForTest(t, database, "Song MP3")
+	lossless := insertTrackForTest(t, database, "Song FLAC")
+	other := insertTrackForTest(t, database, "Other")
+	if _, err := database.Exec(`UPDATE tracks SET title = 'Song', codec = 'mp3', bitrate = 320 WHERE id = ?`, lossy); err != nil {
+		t.Fatalf("set lossy version: %v", err)
+	}
+	if _, err := database.Exec(`UPDATE tracks SET title = 'Song', codec = 'flac', bit_depth = 16, duration_ms = 181000 WHERE id = ?`, lossless); err != nil {
+		t.Fatalf("set lossless version: %v", err)
+	}
+
+	// The versions are not linked; they match as duplicates by title,
+	// artist and duration.
+	state, err := service.SetQueue([]int64{lossy, other}, 0)
+	if err != nil {
+		t.Fatalf("set queue: %v", err)
+	}
+	if len(state.Entries) != 2 || state.Entries[0].ID != lossless || state.Entries[1].ID != other {
+		t.Fatalf("expected the FLAC duplicate and the other track, got %+v", state.Entries)
+	}
+
+	state, err = service.SetQueue([]int64{lossy, other, lossless}, 2)
+	if err != nil {
+		t.Fatalf("set queue with both versions: %v", err)
+	}
+	if len(state.Entries) != 2 || state.CurrentIndex != 0 {
+		t.Fatalf("expected the song once with start on it, got %d of %+v", state.CurrentIndex, state.Entries)
+	}
+}
+
+func TestExportImportQueueRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	service, database := newQueueServiceForTest(t)
+	defer database.Close()
+
+	first := insertTrackForTest(t, database, "First")
+	second := insertTrackForTest(t, database, "Second")
+	rootPath := t.TempDir()
+	if _, err := database.Exec(`INSERT INTO watched_roots(path, enabled) VALUES (?, 1)`, rootPath); err != nil {
+		t.Fatalf("insert watched root: %v", err)
+	}
+	for _, trackID := range []int64{first, second} {
+		if _, err := database.Exec(
+			`UPDATE files SET path = ? WHERE id = (SELECT file_id FROM tracks WHERE id = ?)`,
+			filepath.Join(rootPath, "Album", fmt.Sprintf("%d.mp3", trackID)),
+			trackID,
+		); err != nil {
+			t.Fatalf("move track into root: %v", err)
+		}
+	}
+	if _, err := service.SetQueue([]int64{first, second}, 1); err != nil {
+		t.Fatalf("set queue: %v", err)
+	}
+
+	exportDir := t.TempDir()
+	for _, name := range []string{"session.json", "session.m3u8"} {
+		exportPath := filepath.Join(exportDir, name)
+		if err := service.ExportQueue(exportPath); err != nil {
+			t.Fatalf("export %s: %v", name, err)
+		}
+		service.Clear()
+
+		result, err := service.ImportQueue(exportPath)
+		if err != nil {
+			t.Fatalf("import %s: %v", name, err)
+		}
+		if result.Matched != 2 || len(result.Missing) != 0 {
+			t.Fatalf("expected every entry of %s to match, got %+v", name, result)
+		}
+		if result.State.Entries[0].ID != first || result.State.Entries[1].ID != second || result.State.CurrentIndex != 1 {
+			t.Fatalf("unexpected imported queue from %s: %+v", name, result.State)
+		}
+	}
+}
+
+func TestImportQueueFallsBackToTags(t *testing.T) {
+	t.Parallel()
+
+	service, database := newQueueServiceForTest(t)
+	defer database.Close()
+
+	trackID := insertTrackForTest(t, database, "Tagged")
+	shared := SharedQueue{
+		FormatVersion: sharedQueueFormatVersion,
+		Entries: []SharedQueueEntry{
+			{Path: "Elsewhere/Tagged.flac", Title: "tagged", Artist: "Artist", Album: "Album"},
+			{Path: "Elsewhere/Unknown.flac", Title: "Unknown", Artist: "Nobody"},
+		},
+	}
+	content, err := json.Marshal(shared)
+	if err != nil {
+		t.Fatalf("encode shared queue: %v", err)
+	}
+	importPath := filepath.Join(t.TempDir(), "shared.json")
+	if err := os.WriteFile(importPath, content, 0o644); err != nil {
+		t.Fatalf("write shared queue: %v", err)
+	}
+
+	result, err := service.ImportQueue(importPath)
+	if err != nil {
+		t.Fatalf("import queue: %v", err)
+	}
+	if result.Matched != 1 || result.State.Entries[0].ID != trackID {
+		t.Fatalf("expected tag match for the first entry, got %+v", result)
+	}
+	if len(result.Missing) != 1 || result.Missing[0] != "Nobody - Unknown" {
+		t.Fatalf("expected the unknown entry to be reported, got %v", result.Missing)
+	}
+}
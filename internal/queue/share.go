@@ -0,0 +1,345 @@
+package queue
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+const sharedQueueFormatVersion = 1
+
+const (
+	m3uHeader        = "#EXTM3U"
+	m3uCurrentPrefix = "#EXTBEN-CURRENT:"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// SharedQueue is the JSON form of an exported queue. Paths are relative to
+// the library folder that holds each file, so another Ben user with the same
+// files under a different folder can import it; the tags are a fallback for
+// entries whose path does not resolve.
+type SharedQueue struct {
+	FormatVersion int                `json:"formatVersion"`
+	ExportedAt    string             `json:"exportedAt"`
+	CurrentIndex  int                `json:"currentIndex"`
+	Entries       []SharedQueueEntry `json:"entries"`
+}
+
+type SharedQueueEntry struct {
+	Path        string `json:"path"`
+	Title       string `json:"title"`
+	Artist      string `json:"artist"`
+	Album       string `json:"album"`
+	AlbumArtist string `json:"albumArtist"`
+	DiscNo      *int   `json:"discNo,omitempty"`
+	TrackNo     *int   `json:"trackNo,omitempty"`
+	DurationMS  *int   `json:"durationMs,omitempty"`
+}
+
+type QueueImportResult struct {
+	State   State    `json:"state"`
+	Matched int      `json:"matched"`
+	Missing []string `json:"missing"`
+}
+
+// ExportQueue writes the current queue to path. Files ending in .json get the
+// full SharedQueue; anything else is written as an extended M3U8 playlist.
+func (s *Service) ExportQueue(path string) error {
+	trimmedPath := strings.TrimSpace(path)
+	if trimmedPath == "" {
+		return errors.New("export path is required")
+	}
+
+	state := s.GetState()
+	if len(state.Entries) == 0 {
+		return errors.New("queue is empty")
+	}
+
+	roots, err := s.localRootPaths(context.Background())
+	if err != nil {
+		return err
+	}
+
+	shared := SharedQueue{
+		FormatVersion: sharedQueueFormatVersion,
+		ExportedAt:    time.Now().UTC().Format(time.RFC3339),
+		CurrentIndex:  state.CurrentIndex,
+		Entries:       make([]SharedQueueEntry, 0, len(state.Entries)),
+	}
+	for _, track := range state.Entries {
+		shared.Entries = append(shared.Entries, SharedQueueEntry{
+			Path:        rootRelativePath(track.Path, roots),
+			Title:       track.Title,
+			Artist:      track.Artist,
+			Album:       track.Album,
+			AlbumArtist: track.AlbumArtist,
+			DiscNo:      track.DiscNo,
+			TrackNo:     track.TrackNo,
+			DurationMS:  track.DurationMS,
+		})
+	}
+
+	var content []byte
+	if strings.EqualFold(filepath.Ext(trimmedPath), ".json") {
+		content, err = json.MarshalIndent(shared, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode shared queue: %w", err)
+		}
+	} else {
+		content = formatSharedQueueM3U(shared)
+	}
+
+	if err := os.WriteFile(trimmedPath, content, 0o644); err != nil {
+		return fmt.Errorf("write shared queue: %w", err)
+	}
+
+	return nil
+}
+
+// ImportQueue replaces the queue with the entries of a file written by
+// ExportQueue or any M3U8 playlist. Entries are matched by path against the
+// file's own folder and every library folder, then by tags; unmatched
+// entries are reported instead of failing the import.
+func (s *Service) ImportQueue(path string) (QueueImportResult, error) {
+	trimmedPath := strings.TrimSpace(path)
+	content, err := os.ReadFile(trimmedPath)
+	if err != nil {
+		return QueueImportResult{}, fmt.Errorf("read shared queue: %w", err)
+	}
+
+	var shared SharedQueue
+	if strings.EqualFold(filepath.Ext(trimmedPath), ".json") {
+		if err := json.Unmarshal(bytes.TrimPrefix(content, utf8BOM), &shared); err != nil {
+			return QueueImportResult{}, fmt.Errorf("decode shared queue: %w", err)
+		}
+		if shared.FormatVersion > sharedQueueFormatVersion {
+			return QueueImportResult{}, fmt.Errorf("shared queue format %d is newer than this version of Ben", shared.FormatVersion)
+		}
+	} else {
+		shared = parseSharedQueueM3U(content)
+	}
+
+	ctx := context.Background()
+	roots, err := s.localRootPaths(ctx)
+	if err != nil {
+		return QueueImportResult{}, err
+	}
+	baseDirs := append([]string{filepath.Dir(trimmedPath)}, roots...)
+
+	result := QueueImportResult{Missing: make([]string, 0)}
+	trackIDs := make([]int64, 0, len(shared.Entries))
+	startIndex := 0
+	for index, entry := range shared.Entries {
+		trackID, found, err := s.resolveSharedEntry(ctx, entry, baseDirs)
+		if err != nil {
+			return QueueImportResult{}, err
+		}
+		if !found {
+			result.Missing = append(result.Missing, sharedEntryLabel(entry))
+			continue
+		}
+		if index == shared.CurrentIndex {
+			startIndex = len(trackIDs)
+		}
+		trackIDs = append(trackIDs, trackID)
+	}
+	if len(trackIDs) == 0 {
+		return QueueImportResult{}, errors.New("none of the shared tracks are in the library")
+	}
+
+	state, err := s.SetQueue(trackIDs, startIndex)
+	if err != nil {
+		return QueueImportResult{}, err
+	}
+
+	result.State = state
+	result.Matched = len(trackIDs)
+	return result, nil
+}
+
+func (s *Service) localRootPaths(ctx context.Context) ([]string, error) {
+	if s.db == nil {
+		return []string{}, nil
+	}
+
+	roots, err := library.NewWatchedRootRepository(s.db).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(roots))
+	for _, root := range roots {
+		if root.Enabled && !root.IsRemote() {
+			paths = append(paths, filepath.Clean(root.Path))
+		}
+	}
+
+	return paths, nil
+}
+
+func (s *Service) resolveSharedEntry(ctx context.Context, entry SharedQueueEntry, baseDirs []string) (int64, bool, error) {
+	pathQuery := `
+		SELECT t.id
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		WHERE f.file_exists = 1 AND f.path = ?
+	`
+	if runtime.GOOS == "windows" {
+		pathQuery = `
+			SELECT t.id
+			FROM tracks t
+			JOIN files f ON f.id = t.file_id
+			WHERE f.file_exists = 1 AND LOWER(f.path) = LOWER(?)
+		`
+	}
+
+	for _, candidate := range sharedEntryPaths(entry.Path, baseDirs) {
+		var trackID int64
+		err := s.db.QueryRowContext(ctx, pathQuery, candidate).Scan(&trackID)
+		if err == nil {
+			return trackID, true, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return 0, false, fmt.Errorf("resolve shared entry %s: %w", entry.Path, err)
+		}
+	}
+
+	if strings.TrimSpace(entry.Title) == "" {
+		return 0, false, nil
+	}
+
+	var trackID int64
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT t.id
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		WHERE f.file_exists = 1
+		  AND LOWER(COALESCE(NULLIF(TRIM(t.title), ''), 'Unknown Title')) = LOWER(?)
+		  AND LOWER(COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) = LOWER(?)
+		  AND (? = '' OR LOWER(COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album')) = LOWER(?))
+		  AND (? IS NULL OR t.disc_no IS NULL OR t.disc_no = ?)
+		  AND (? IS NULL OR t.track_no IS NULL OR t.track_no = ?)
+		ORDER BY t.id
+		LIMIT 1`,
+		strings.TrimSpace(entry.Title),
+		strings.TrimSpace(entry.Artist),
+		strings.TrimSpace(entry.Album),
+		strings.TrimSpace(entry.Album),
+		entry.DiscNo,
+		entry.DiscNo,
+		entry.TrackNo,
+		entry.TrackNo,
+	).Scan(&trackID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("match shared entry %s: %w", sharedEntryLabel(entry), err)
+	}
+
+	return trackID, true, nil
+}
+
+// rootRelativePath returns path relative to the library folder containing
+// it, with forward slashes so the file reads the same on every platform.
+// Paths outside every folder are kept absolute.
+func rootRelativePath(path string, roots []string) string {
+	for _, root := range roots {
+		relativePath, err := filepath.Rel(root, path)
+		if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+			continue
+		}
+		return filepath.ToSlash(relativePath)
+	}
+
+	return path
+}
+
+func sharedEntryPaths(entryPath string, baseDirs []string) []string {
+	trimmed := strings.TrimSpace(entryPath)
+	if trimmed == "" {
+		return nil
+	}
+
+	nativePath := trimmed
+	if runtime.GOOS != "windows" {
+		nativePath = strings.ReplaceAll(nativePath, "\\", "/")
+	}
+	nativePath = filepath.FromSlash(nativePath)
+	if filepath.IsAbs(nativePath) {
+		return []string{filepath.Clean(nativePath)}
+	}
+
+	candidates := make([]string, 0, len(baseDirs))
+	for _, baseDir := range baseDirs {
+		candidates = append(candidates, filepath.Join(baseDir, nativePath))
+	}
+
+	return candidates
+}
+
+func sharedEntryLabel(entry SharedQueueEntry) string {
+	if strings.TrimSpace(entry.Title) != "" {
+		return entry.Artist + " - " + entry.Title
+	}
+
+	return entry.Path
+}
+
+// formatSharedQueueM3U renders the queue as extended M3U8. The current entry
+// is kept in a comment directive that other players ignore.
+func formatSharedQueueM3U(shared SharedQueue) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString(m3uHeader + "\n")
+	fmt.Fprintf(&buffer, "%s%d\n", m3uCurrentPrefix, shared.CurrentIndex)
+
+	for _, entry := range shared.Entries {
+		durationSeconds := -1
+		if entry.DurationMS != nil && *entry.DurationMS > 0 {
+			durationSeconds = *entry.DurationMS / 1000
+		}
+
+		fmt.Fprintf(&buffer, "#EXTINF:%d,%s - %s\n", durationSeconds, entry.Artist, entry.Title)
+		buffer.WriteString(entry.Path + "\n")
+	}
+
+	return buffer.Bytes()
+}
+
+func parseSharedQueueM3U(content []byte) SharedQueue {
+	content = bytes.TrimPrefix(content, utf8BOM)
+
+	shared := SharedQueue{FormatVersion: sharedQueueFormatVersion, Entries: make([]SharedQueueEntry, 0)}
+	for _, rawLine := range strings.Split(string(content), "\n") {
+		line := strings.TrimSpace(strings.TrimSuffix(rawLine, "\r"))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			if strings.HasPrefix(strings.ToUpper(line), m3uCurrentPrefix) {
+				_, _ = fmt.Sscanf(line[len(m3uCurrentPrefix):], "%d", &shared.CurrentIndex)
+			}
+			continue
+		}
+		if strings.Contains(line, "://") {
+			continue
+		}
+
+		shared.Entries = append(shared.Entries, SharedQueueEntry{Path: line})
+	}
+
+	return shared
+}
@@ -0,0 +1,223 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	SourceQueue    = "queue"
+	SourceAlbum    = "album"
+	SourceArtist   = "artist"
+	SourcePlaylist = "playlist"
+	SourceSearch   = "search"
+	SourceRadio    = "radio"
+)
+
+// Source records where a queue entry came from so the UI can navigate back
+// to it. Only the fields of the source's kind are set.
+type Source struct {
+	Kind        string `json:"kind"`
+	AlbumTitle  string `json:"albumTitle,omitempty"`
+	AlbumArtist string `json:"albumArtist,omitempty"`
+	ArtistName  string `json:"artistName,omitempty"`
+	PlaylistID  int64  `json:"playlistId,omitempty"`
+	Query       string `json:"query,omitempty"`
+}
+
+// SourceLocation is a resolved "go to source" target. Available is false
+// when the album, artist or playlist no longer exists; TrackPosition is the
+// track's zero-based position in a playlist source, or -1.
+type SourceLocation struct {
+	Source        Source `json:"source"`
+	TrackID       int64  `json:"trackId"`
+	Available     bool   `json:"available"`
+	TrackPosition int    `json:"trackPosition"`
+}
+
+func normalizeSource(source Source) (Source, error) {
+	normalized := Source{Kind: strings.ToLower(strings.TrimSpace(source.Kind))}
+	switch normalized.Kind {
+	case "", SourceQueue:
+		normalized.Kind = SourceQueue
+	case SourceAlbum:
+		normalized.AlbumTitle = strings.TrimSpace(source.AlbumTitle)
+		normalized.AlbumArtist = strings.TrimSpace(source.AlbumArtist)
+		if normalized.AlbumTitle == "" || normalized.AlbumArtist == "" {
+			return Source{}, errors.New("album source requires title and album artist")
+		}
+	case SourceArtist:
+		normalized.ArtistName = strings.TrimSpace(source.ArtistName)
+		if normalized.ArtistName == "" {
+			return Source{}, errors.New("artist source requires a name")
+		}
+	case SourcePlaylist:
+		if source.PlaylistID <= 0 {
+			return Source{}, errors.New("playlist source requires a playlist id")
+		}
+		normalized.PlaylistID = source.PlaylistID
+	case SourceSearch:
+		normalized.Query = strings.TrimSpace(source.Query)
+	case SourceRadio:
+		normalized.ArtistName = strings.TrimSpace(source.ArtistName)
+	default:
+		return Source{}, fmt.Errorf("unsupported queue source %q", source.Kind)
+	}
+
+	return normalized, nil
+}
+
+// encodeSource stores plain queue entries as "queue", which is what the
+// column held before provenance was recorded.
+func encodeSource(source Source) string {
+	if source.Kind == "" || source.Kind == SourceQueue {
+		return SourceQueue
+	}
+
+	encoded, err := json.Marshal(source)
+	if err != nil {
+		return SourceQueue
+	}
+
+	return string(encoded)
+}
+
+func decodeSource(value string) Source {
+	trimmed := strings.TrimSpace(value)
+	if !strings.HasPrefix(trimmed, "{") {
+		return Source{Kind: SourceQueue}
+	}
+
+	var source Source
+	if err := json.Unmarshal([]byte(trimmed), &source); err != nil {
+		return Source{Kind: SourceQueue}
+	}
+
+	normalized, err := normalizeSource(source)
+	if err != nil {
+		return Source{Kind: SourceQueue}
+	}
+
+	return normalized
+}
+
+func repeatSource(source Source, count int) []Source {
+	sources := make([]Source, count)
+	for index := range sources {
+		sources[index] = source
+	}
+
+	return sources
+}
+
+// ResolveSource checks that the source of the entry at index still exists
+// and locates the track inside it where that is meaningful.
+func (s *Service) ResolveSource(index int) (SourceLocation, error) {
+	s.mu.Lock()
+	if index < 0 || index >= len(s.entries) {
+		s.mu.Unlock()
+		return SourceLocation{}, fmt.Errorf("queue index %d out of range", index)
+	}
+	location := SourceLocation{
+		Source:        s.sourceAtLocked(index),
+		TrackID:       s.entries[index].ID,
+		TrackPosition: -1,
+	}
+	s.mu.Unlock()
+
+	if s.db == nil {
+		return location, nil
+	}
+
+	ctx := context.Background()
+	var err error
+	switch location.Source.Kind {
+	case SourceAlbum:
+		location.Available, err = s.exists(
+			ctx,
+			`SELECT 1 FROM albums a
+			 WHERE LOWER(COALESCE(NULLIF(TRIM(a.title), ''), 'Unknown Album')) = LOWER(?)
+			   AND LOWER(COALESCE(NULLIF(TRIM(a.album_artist), ''), 'Unknown Artist')) = LOWER(?)
+			 LIMIT 1`,
+			location.Source.AlbumTitle,
+			location.Source.AlbumArtist,
+		)
+	case SourceArtist:
+		location.Available, err = s.exists(ctx, "SELECT 1 FROM artists WHERE LOWER(name) = LOWER(?) LIMIT 1", location.Source.ArtistName)
+	case SourcePlaylist:
+		location.Available, err = s.exists(ctx, "SELECT 1 FROM playlists WHERE id = ?", location.Source.PlaylistID)
+		if err == nil && location.Available {
+			location.TrackPosition, err = s.playlistTrackPosition(ctx, location.Source.PlaylistID, location.TrackID)
+		}
+	case SourceSearch, SourceRadio:
+		location.Available = true
+	}
+	if err != nil {
+		return SourceLocation{}, fmt.Errorf("resolve queue source: %w", err)
+	}
+
+	return location, nil
+}
+
+func (s *Service) sourceAtLocked(index int) Source {
+	if index < 0 || index >= len(s.sources) {
+		return Source{Kind: SourceQueue}
+	}
+
+	return s.sources[index]
+}
+
+// sourcesForEntriesLocked returns a copy of the sources padded to the length
+// of the queue, so callers can index it alongside entries.
+func (s *Service) sourcesForEntriesLocked() []Source {
+	sources := make([]Source, len(s.entries))
+	for index := range sources {
+		sources[index] = s.sourceAtLocked(index)
+	}
+
+	return sources
+}
+
+func (s *Service) exists(ctx context.Context, query string, args ...any) (bool, error) {
+	var found int
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *Service) playlistTrackPosition(ctx context.Context, playlistID int64, trackID int64) (int, error) {
+	var firstPosition sql.NullInt64
+	if err := s.db.QueryRowContext(
+		ctx,
+		"SELECT MIN(position) FROM playlist_entries WHERE playlist_id = ? AND track_id = ?",
+		playlistID,
+		trackID,
+	).Scan(&firstPosition); err != nil {
+		return -1, err
+	}
+	if !firstPosition.Valid {
+		return -1, nil
+	}
+
+	var position int
+	if err := s.db.QueryRowContext(
+		ctx,
+		"SELECT COUNT(1) FROM playlist_entries WHERE playlist_id = ? AND position < ?",
+		playlistID,
+		firstPosition.Int64,
+	).Scan(&position); err != nil {
+		return -1, err
+	}
+
+	return position, nil
+}
@@ -0,0 +1,54 @@
+package queue
+
+import (
+	"context"
+	"strconv"
+)
+
+// ShuffleVideosSettingKey controls whether shuffle picks entries that come
+// from video files. They are left out by default.
+const ShuffleVideosSettingKey = "queue.shuffle_videos"
+
+func (s *Service) SetShuffleVideos(enabled bool) (State, error) {
+	if s.settings != nil {
+		if err := s.settings.Set(context.Background(), ShuffleVideosSettingKey, strconv.FormatBool(enabled)); err != nil {
+			return s.GetState(), err
+		}
+	}
+
+	s.mu.Lock()
+	if s.shuffleVideos != enabled {
+		s.shuffleVideos = enabled
+		if s.shuffle {
+			s.lastShuffle = nil
+			s.resetShuffleSessionLocked()
+		}
+	}
+	s.touchLocked()
+	state := s.snapshotLocked()
+	s.mu.Unlock()
+
+	s.afterMutation(state)
+	return state, nil
+}
+
+func (s *Service) loadShuffleVideos() {
+	if s.settings == nil {
+		return
+	}
+
+	enabled, err := strconv.ParseBool(s.settings.GetString(context.Background(), ShuffleVideosSettingKey, "false"))
+	if err == nil {
+		s.shuffleVideos = enabled
+	}
+}
+
+// isSkippedVideoIndexLocked reports whether shuffle should pass over the
+// entry at index. Video entries still play in order or when picked.
+func (s *Service) isSkippedVideoIndexLocked(index int) bool {
+	if s.shuffleVideos || index < 0 || index >= len(s.entries) {
+		return false
+	}
+
+	return s.entries[index].IsVideo
+}
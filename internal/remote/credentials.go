@@ -0,0 +1,90 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/secrets"
+)
+
+// Credentials keeps the passwords of remote roots in the keychain, keyed by
+// root id. A nil Credentials has no passwords, which suits roots that are
+// open or authenticate with SSH keys.
+type Credentials struct {
+	secrets *secrets.Store
+}
+
+func NewCredentials(store *secrets.Store) *Credentials {
+	return &Credentials{secrets: store}
+}
+
+func passwordKey(rootID int64) string {
+	return "remote-root/" + strconv.FormatInt(rootID, 10)
+}
+
+// Password returns the stored password of a root, or "" without one.
+func (c *Credentials) Password(rootID int64) (string, error) {
+	if c == nil {
+		return "", nil
+	}
+
+	password, _, err := c.secrets.Get(passwordKey(rootID))
+	return password, err
+}
+
+// SetPassword stores the password of a root. An empty password deletes it.
+func (c *Credentials) SetPassword(rootID int64, password string) error {
+	if c == nil {
+		if password == "" {
+			return nil
+		}
+		return errors.New("remote passwords cannot be stored without a keychain")
+	}
+
+	return c.secrets.Set(passwordKey(rootID), password)
+}
+
+// DeletePassword forgets the password of a root.
+func (c *Credentials) DeletePassword(rootID int64) error {
+	if c == nil {
+		return nil
+	}
+
+	return c.secrets.Delete(passwordKey(rootID))
+}
+
+// Open connects to a remote root with its stored password.
+func (c *Credentials) Open(ctx context.Context, root library.WatchedRoot) (Source, error) {
+	password, err := c.Password(root.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return Open(ctx, root.Path, root.Username, password)
+}
+
+// MigrateLegacyPasswords moves the passwords older versions stored in the
+// library database into the keychain. A password the keychain refuses stays
+// in the database, so the next start tries again.
+func (c *Credentials) MigrateLegacyPasswords(ctx context.Context, roots *library.WatchedRootRepository) error {
+	passwords, err := roots.LegacyPasswords(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for rootID, password := range passwords {
+		if err := c.SetPassword(rootID, password); err != nil {
+			errs = append(errs, fmt.Errorf("move password of remote root %d to the keychain: %w", rootID, err))
+			continue
+		}
+		if err := roots.ClearLegacyPassword(ctx, rootID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,113 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+// PlaybackResolver maps stored remote file URLs to a location the playback
+// backend can stream and the HTTP headers to send with it. Credentials
+// travel in an Authorization header instead of the URL, so the backend never
+// logs or reports them. SFTP files are streamed through a loopback server.
+//
+// The roots are cached and reloaded when the repository reports a change,
+// so loading a track does not query the database.
+type PlaybackResolver struct {
+	roots       *library.WatchedRootRepository
+	credentials *Credentials
+	stream      *sftpStream
+
+	mu         sync.Mutex
+	loaded     bool
+	generation int64
+	remote     []library.WatchedRoot
+	passwords  map[int64]string
+}
+
+func NewPlaybackResolver(roots *library.WatchedRootRepository, credentials *Credentials) *PlaybackResolver {
+	return &PlaybackResolver{
+		roots:       roots,
+		credentials: credentials,
+		stream:      newSFTPStream(),
+	}
+}
+
+// Resolve returns the location the backend opens for path and the headers
+// to send with it. Paths outside remote roots are returned as they are.
+func (r *PlaybackResolver) Resolve(path string) (string, []string, error) {
+	if r == nil || r.roots == nil || !IsRemotePath(path) {
+		return path, nil, nil
+	}
+
+	root, password, err := r.rootFor(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if KindOf(root.Path) == library.RootKindSFTP {
+		return r.stream.URL(root.ID, sftpRoot{url: root.Path, username: root.Username, password: password}, path)
+	}
+
+	return path, authorizationHeader(root.Username, password), nil
+}
+
+// Close stops streaming SFTP files.
+func (r *PlaybackResolver) Close() error {
+	if r == nil {
+		return nil
+	}
+
+	return r.stream.Close()
+}
+
+// rootFor returns the remote root that serves path and its password.
+func (r *PlaybackResolver) rootFor(path string) (library.WatchedRoot, string, error) {
+	generation := r.roots.Generation()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.loaded || r.generation != generation {
+		roots, err := r.roots.List(context.Background())
+		if err != nil {
+			return library.WatchedRoot{}, "", fmt.Errorf("load remote roots: %w", err)
+		}
+
+		r.remote = r.remote[:0]
+		for _, root := range roots {
+			if root.IsRemote() {
+				r.remote = append(r.remote, root)
+			}
+		}
+		r.passwords = make(map[int64]string)
+		r.generation = generation
+		r.loaded = true
+	}
+
+	var match *library.WatchedRoot
+	for i := range r.remote {
+		root := &r.remote[i]
+		if strings.HasPrefix(path, root.Path) && (match == nil || len(root.Path) > len(match.Path)) {
+			match = root
+		}
+	}
+	if match == nil {
+		return library.WatchedRoot{}, "", fmt.Errorf("no remote root serves %s", path)
+	}
+
+	password, ok := r.passwords[match.ID]
+	if !ok {
+		var err error
+		password, err = r.credentials.Password(match.ID)
+		if err != nil {
+			return library.WatchedRoot{}, "", err
+		}
+		r.passwords[match.ID] = password
+	}
+
+	return *match, password, nil
+}
@@ -0,0 +1,172 @@
+package remote
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rzxx/ben/internal/db"
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/secrets"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestPlaybackResolverSendsWebDAVCredentialsInHeaders(t *testing.T) {
+	keyring.MockInit()
+	database, roots := newRootRepositoryForTest(t)
+	credentials := NewCredentials(secrets.NewStore("ben-test"))
+
+	root, err := roots.AddRemote(context.Background(), library.RootKindWebDAV, "https://nas.local/music/", "alice")
+	if err != nil {
+		t.Fatalf("add remote root: %v", err)
+	}
+	if err := credentials.SetPassword(root.ID, "secret"); err != nil {
+		t.Fatalf("store password: %v", err)
+	}
+
+	resolver := NewPlaybackResolver(roots, credentials)
+	defer resolver.Close()
+
+	fileURL := "https://nas.local/music/Artist/01%20Song.flac"
+	resolved, headers, err := resolver.Resolve(fileURL)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if resolved != fileURL || strings.Contains(resolved, "secret") {
+		t.Fatalf("expected the url without credentials, got %q", resolved)
+	}
+	want := "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte("alice:secret"))
+	if len(headers) != 1 || headers[0] != want {
+		t.Fatalf("unexpected headers %v", headers)
+	}
+
+	if local, headers, err := resolver.Resolve("/music/local.flac"); err != nil || local != "/music/local.flac" || headers != nil {
+		t.Fatalf("expected local paths to pass through, got %q %v %v", local, headers, err)
+	}
+
+	// The cached roots must not hide a root removed meanwhile.
+	if err := roots.Delete(context.Background(), root.ID); err != nil {
+		t.Fatalf("delete root: %v", err)
+	}
+	if _, _, err := resolver.Resolve(fileURL); err == nil {
+		t.Fatalf("expected an error for a file whose root was removed")
+	}
+
+	// Database failures are reported instead of falling back to the bare URL.
+	if _, err := roots.AddRemote(context.Background(), library.RootKindWebDAV, "https://other.local/", ""); err != nil {
+		t.Fatalf("add remote root: %v", err)
+	}
+	database.Close()
+	if _, _, err := resolver.Resolve("https://other.local/a.flac"); err == nil {
+		t.Fatalf("expected the roots query error to be surfaced")
+	}
+}
+
+func TestPlaybackResolverStreamsSFTPFilesBehindToken(t *testing.T) {
+	keyring.MockInit()
+	_, roots := newRootRepositoryForTest(t)
+	credentials := NewCredentials(secrets.NewStore("ben-test"))
+
+	rootDir := t.TempDir()
+	writeSFTPFileForTest(t, filepath.Join(rootDir, "01 Song.flac"), "streamed audio")
+	address, hostKeys := startSFTPServerForTest(t, "secret")
+	rootURL := "sftp://" + address + filepath.ToSlash(rootDir) + "/"
+
+	root, err := roots.AddRemote(context.Background(), library.RootKindSFTP, rootURL, "alice")
+	if err != nil {
+		t.Fatalf("add remote root: %v", err)
+	}
+	if err := credentials.SetPassword(root.ID, "secret"); err != nil {
+		t.Fatalf("store password: %v", err)
+	}
+
+	resolver := NewPlaybackResolver(roots, credentials)
+	defer resolver.Close()
+	resolver.stream.dial = func(ctx context.Context, rootURL string, username string, password string) (*SFTPClient, error) {
+		return dialSFTP(ctx, rootURL, username, []ssh.AuthMethod{ssh.Password(password)}, hostKeys)
+	}
+
+	streamURL, headers, err := resolver.Resolve(rootURL + "01%20Song.flac")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if !strings.HasPrefix(streamURL, "http://127.0.0.1:") || len(headers) != 1 {
+		t.Fatalf("unexpected stream %q %v", streamURL, headers)
+	}
+
+	unauthorized, err := http.Get(streamURL)
+	if err != nil {
+		t.Fatalf("get without token: %v", err)
+	}
+	unauthorized.Body.Close()
+	if unauthorized.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected requests without the token to be refused, got %s", unauthorized.Status)
+	}
+
+	request, err := http.NewRequest(http.MethodGet, streamURL, nil)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	name, value, _ := strings.Cut(headers[0], ":")
+	request.Header.Set(name, strings.TrimSpace(value))
+	request.Header.Set("Range", "bytes=9-13")
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("get range: %v", err)
+	}
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+	if response.StatusCode != http.StatusPartialContent || string(body) != "audio" {
+		t.Fatalf("unexpected range response %s %q", response.Status, body)
+	}
+}
+
+func TestMigrateLegacyPasswordsMovesThemToTheKeychain(t *testing.T) {
+	keyring.MockInit()
+	database, roots := newRootRepositoryForTest(t)
+	credentials := NewCredentials(secrets.NewStore("ben-test"))
+
+	root, err := roots.AddRemote(context.Background(), library.RootKindWebDAV, "https://nas.local/legacy/", "alice")
+	if err != nil {
+		t.Fatalf("add remote root: %v", err)
+	}
+	if _, err := database.Exec("UPDATE watched_roots SET remote_password = 'old-secret' WHERE id = ?", root.ID); err != nil {
+		t.Fatalf("store legacy password: %v", err)
+	}
+
+	if err := credentials.MigrateLegacyPasswords(context.Background(), roots); err != nil {
+		t.Fatalf("migrate passwords: %v", err)
+	}
+
+	if password, err := credentials.Password(root.ID); err != nil || password != "old-secret" {
+		t.Fatalf("expected the password in the keychain, got %q %v", password, err)
+	}
+	var stored sql.NullString
+	if err := database.QueryRow("SELECT remote_password FROM watched_roots WHERE id = ?", root.ID).Scan(&stored); err != nil {
+		t.Fatalf("read legacy password: %v", err)
+	}
+	if stored.Valid {
+		t.Fatalf("expected the database copy to be cleared, got %q", stored.String)
+	}
+}
+
+func newRootRepositoryForTest(t *testing.T) (*sql.DB, *library.WatchedRootRepository) {
+	t.Helper()
+
+	database, err := db.Bootstrap(filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatalf("bootstrap db: %v", err)
+	}
+	t.Cleanup(func() {
+		database.Close()
+	})
+
+	return database, library.NewWatchedRootRepository(database)
+}
@@ -0,0 +1,297 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// defaultIdentityFiles are the private keys ssh tries when none is
+// configured. Keys protected by a passphrase are skipped; they can still be
+// used through a running agent.
+var defaultIdentityFiles = []string{"id_ed25519", "id_ecdsa", "id_rsa"}
+
+// SFTPClient lists and downloads files below a folder of an SSH server. Host
+// keys are checked against the user's known_hosts file, so a server has to
+// be trusted with ssh once before it can be added.
+type SFTPClient struct {
+	base   *url.URL
+	ssh    *ssh.Client
+	client *sftp.Client
+	agent  net.Conn
+}
+
+// DialSFTP connects to the server of an sftp:// root URL. It signs in with
+// the keys of a running SSH agent, the default key files in ~/.ssh and the
+// password, in that order.
+func DialSFTP(ctx context.Context, rootURL string, username string, password string) (*SFTPClient, error) {
+	hostKeys, err := knownHostsCallback()
+	if err != nil {
+		return nil, err
+	}
+
+	auth, agentConn := sftpAuthMethods(password)
+	client, err := dialSFTP(ctx, rootURL, username, auth, hostKeys)
+	if err != nil {
+		if agentConn != nil {
+			agentConn.Close()
+		}
+		return nil, err
+	}
+	client.agent = agentConn
+
+	return client, nil
+}
+
+func dialSFTP(ctx context.Context, rootURL string, username string, auth []ssh.AuthMethod, hostKeys ssh.HostKeyCallback) (*SFTPClient, error) {
+	base, err := url.Parse(rootURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse remote root url: %w", err)
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+
+	address := base.Host
+	if base.Port() == "" {
+		address = net.JoinHostPort(base.Hostname(), "22")
+	}
+	if username == "" {
+		current, err := user.Current()
+		if err != nil {
+			return nil, fmt.Errorf("sftp root %s needs a username: %w", base.Redacted(), err)
+		}
+		username = current.Username
+	}
+
+	config := &ssh.ClientConfig{
+		User:            username,
+		Auth:            auth,
+		HostKeyCallback: hostKeys,
+		Timeout:         requestTimeout,
+	}
+
+	dialer := net.Dialer{Timeout: requestTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s: %w", address, err)
+	}
+
+	sshConn, channels, requests, err := ssh.NewClientConn(conn, address, config)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("sign in to %s: %w", address, describeHostKeyError(address, err))
+	}
+	sshClient := ssh.NewClient(sshConn, channels, requests)
+
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		sshClient.Close()
+		return nil, fmt.Errorf("start sftp session on %s: %w", address, err)
+	}
+
+	return &SFTPClient{base: base, ssh: sshClient, client: client}, nil
+}
+
+// Walk lists every file below the root folder. Symbolic links are not
+// followed, so a link back up the tree cannot loop the walk.
+func (c *SFTPClient) Walk(ctx context.Context) ([]Entry, error) {
+	rootDir := c.base.Path
+	entries := make([]Entry, 0, 256)
+
+	walker := c.client.Walk(rootDir)
+	for walker.Step() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		current := walker.Path()
+		if err := walker.Err(); err != nil {
+			if path.Clean(current) == path.Clean(rootDir) {
+				return nil, fmt.Errorf("list %s: %w", c.base.Redacted(), err)
+			}
+			continue
+		}
+
+		relativePath := strings.TrimPrefix(current, rootDir)
+		info := walker.Stat()
+		if info.IsDir() {
+			if relativePath != "" && strings.Count(relativePath, "/")+1 > maxListingDepth {
+				walker.SkipDir()
+			}
+			continue
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		entries = append(entries, Entry{
+			URL:          c.fileURL(current),
+			RelativePath: relativePath,
+			Size:         info.Size(),
+			ModTime:      info.ModTime().UTC(),
+		})
+	}
+
+	return entries, nil
+}
+
+// Download copies a remote file to destination, creating parent folders.
+func (c *SFTPClient) Download(ctx context.Context, fileURL string, destination string) error {
+	remoteFile, err := c.OpenFile(fileURL)
+	if err != nil {
+		return err
+	}
+	defer remoteFile.Close()
+
+	// Closing the remote file is the only way to interrupt a running copy.
+	stop := context.AfterFunc(ctx, func() {
+		remoteFile.Close()
+	})
+	defer stop()
+
+	if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+		return fmt.Errorf("create download folder: %w", err)
+	}
+
+	file, err := os.Create(destination)
+	if err != nil {
+		return fmt.Errorf("create download file: %w", err)
+	}
+
+	if _, err := io.Copy(file, remoteFile); err != nil {
+		file.Close()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("download %s: %w", fileURL, err)
+	}
+
+	return file.Close()
+}
+
+// OpenFile opens a file of the root for reading. The file supports seeking,
+// so it can be streamed with range requests.
+func (c *SFTPClient) OpenFile(fileURL string) (*sftp.File, error) {
+	parsed, err := url.Parse(fileURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse remote file url: %w", err)
+	}
+	if parsed.Host != c.base.Host || !strings.HasPrefix(parsed.Path, c.base.Path) {
+		return nil, fmt.Errorf("%s is not below %s", fileURL, c.base.Redacted())
+	}
+
+	file, err := c.client.Open(parsed.Path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", fileURL, err)
+	}
+
+	return file, nil
+}
+
+// Close ends the SFTP session and the SSH connection under it.
+func (c *SFTPClient) Close() error {
+	err := errors.Join(c.client.Close(), c.ssh.Close())
+	if c.agent != nil {
+		c.agent.Close()
+	}
+
+	return err
+}
+
+func (c *SFTPClient) fileURL(remotePath string) string {
+	fileURL := *c.base
+	fileURL.Path = remotePath
+	fileURL.RawPath = ""
+	fileURL.User = nil
+
+	return fileURL.String()
+}
+
+func knownHostsCallback() (ssh.HostKeyCallback, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("find known_hosts: %w", err)
+	}
+
+	knownHostsPath := filepath.Join(home, ".ssh", "known_hosts")
+	callback, err := knownhosts.New(knownHostsPath)
+	if err != nil {
+		return nil, fmt.Errorf("read %s, connect to the server with ssh once to trust its host key: %w", knownHostsPath, err)
+	}
+
+	return callback, nil
+}
+
+// describeHostKeyError explains a host key the known_hosts file does not
+// list, which is the usual reason a first connection fails.
+func describeHostKeyError(address string, err error) error {
+	var keyErr *knownhosts.KeyError
+	if errors.As(err, &keyErr) && len(keyErr.Want) == 0 {
+		return fmt.Errorf("host key of %s is not in known_hosts, connect with ssh once to trust it: %w", address, err)
+	}
+
+	return err
+}
+
+// sftpAuthMethods returns the ways to sign in and the agent connection the
+// agent keys sign through, which must stay open while the client is used.
+func sftpAuthMethods(password string) ([]ssh.AuthMethod, net.Conn) {
+	methods := make([]ssh.AuthMethod, 0, 3)
+	signers := make([]ssh.Signer, 0, len(defaultIdentityFiles))
+
+	var agentConn net.Conn
+	if socket := os.Getenv("SSH_AUTH_SOCK"); socket != "" {
+		if conn, err := net.Dial("unix", socket); err == nil {
+			if agentSigners, err := agent.NewClient(conn).Signers(); err == nil && len(agentSigners) > 0 {
+				agentConn = conn
+				signers = append(signers, agentSigners...)
+			} else {
+				conn.Close()
+			}
+		}
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		for _, name := range defaultIdentityFiles {
+			key, err := os.ReadFile(filepath.Join(home, ".ssh", name))
+			if err != nil {
+				continue
+			}
+			if signer, err := ssh.ParsePrivateKey(key); err == nil {
+				signers = append(signers, signer)
+			}
+		}
+	}
+
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+	if password != "" {
+		methods = append(methods,
+			ssh.Password(password),
+			ssh.KeyboardInteractive(func(_ string, _ string, questions []string, _ []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = password
+				}
+				return answers, nil
+			}),
+		)
+	}
+
+	return methods, agentConn
+}
@@ -0,0 +1,188 @@
+package remote
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSFTPClientWalksAndDownloadsFiles(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	writeSFTPFileForTest(t, filepath.Join(rootDir, "Artist One", "01 Song.flac"), "audio")
+	writeSFTPFileForTest(t, filepath.Join(rootDir, "loose.mp3"), "mp3")
+
+	address, hostKeys := startSFTPServerForTest(t, "secret")
+	rootURL := "sftp://" + address + filepath.ToSlash(rootDir) + "/"
+
+	client, err := dialSFTP(context.Background(), rootURL, "alice", []ssh.AuthMethod{ssh.Password("secret")}, hostKeys)
+	if err != nil {
+		t.Fatalf("dial sftp: %v", err)
+	}
+	defer client.Close()
+
+	entries, err := client.Walk(context.Background())
+	if err != nil {
+		t.Fatalf("walk: %v", err)
+	}
+
+	relativePaths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		relativePaths = append(relativePaths, entry.RelativePath)
+	}
+	sort.Strings(relativePaths)
+	if len(relativePaths) != 2 || relativePaths[0] != "Artist One/01 Song.flac" || relativePaths[1] != "loose.mp3" {
+		t.Fatalf("unexpected listing %v", relativePaths)
+	}
+
+	for _, entry := range entries {
+		if entry.RelativePath != "Artist One/01 Song.flac" {
+			continue
+		}
+		if entry.Size != 5 || entry.ModTime.IsZero() || !strings.HasPrefix(entry.URL, rootURL) {
+			t.Fatalf("unexpected entry %+v", entry)
+		}
+
+		destination := filepath.Join(t.TempDir(), "song.flac")
+		if err := client.Download(context.Background(), entry.URL, destination); err != nil {
+			t.Fatalf("download: %v", err)
+		}
+		if content, _ := os.ReadFile(destination); string(content) != "audio" {
+			t.Fatalf("unexpected downloaded content %q", content)
+		}
+	}
+
+	if _, err := client.OpenFile("sftp://elsewhere.invalid/etc/passwd"); err == nil {
+		t.Fatalf("expected files outside the root to be refused")
+	}
+}
+
+func TestSFTPClientRejectsWrongPasswordAndUnknownHostKey(t *testing.T) {
+	t.Parallel()
+
+	address, hostKeys := startSFTPServerForTest(t, "secret")
+	rootURL := "sftp://" + address + "/"
+
+	if _, err := dialSFTP(context.Background(), rootURL, "alice", []ssh.AuthMethod{ssh.Password("wrong")}, hostKeys); err == nil {
+		t.Fatalf("expected sign in with a wrong password to fail")
+	}
+
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	otherSigner, err := ssh.NewSignerFromKey(otherKey)
+	if err != nil {
+		t.Fatalf("create signer: %v", err)
+	}
+	if _, err := dialSFTP(context.Background(), rootURL, "alice", []ssh.AuthMethod{ssh.Password("secret")}, ssh.FixedHostKey(otherSigner.PublicKey())); err == nil {
+		t.Fatalf("expected a changed host key to be refused")
+	}
+}
+
+func writeSFTPFileForTest(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("create folder: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+}
+
+// startSFTPServerForTest serves the local filesystem over SFTP to user
+// alice and returns the address and a callback that trusts its host key.
+func startSFTPServerForTest(t *testing.T, password string) (string, ssh.HostKeyCallback) {
+	t.Helper()
+
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate host key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(hostKey)
+	if err != nil {
+		t.Fatalf("create host key signer: %v", err)
+	}
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(meta ssh.ConnMetadata, given []byte) (*ssh.Permissions, error) {
+			if meta.User() == "alice" && string(given) == password {
+				return nil, nil
+			}
+			return nil, errors.New("access denied")
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSFTPConnForTest(conn, config)
+		}
+	}()
+
+	return listener.Addr().String(), ssh.FixedHostKey(signer.PublicKey())
+}
+
+func serveSFTPConnForTest(conn net.Conn, config *ssh.ServerConfig) {
+	defer conn.Close()
+
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "only sessions are served")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+
+		go func() {
+			defer channel.Close()
+			for request := range channelRequests {
+				isSFTP := request.Type == "subsystem" && len(request.Payload) > 4 && string(request.Payload[4:]) == "sftp"
+				_ = request.Reply(isSFTP, nil)
+				if !isSFTP {
+					continue
+				}
+
+				server, err := sftp.NewServer(channel)
+				if err != nil {
+					return
+				}
+				_ = server.Serve()
+				server.Close()
+				return
+			}
+		}()
+	}
+}
@@ -0,0 +1,33 @@
+package remote
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+// Source lists and downloads the files of a remote root.
+type Source interface {
+	Walk(ctx context.Context) ([]Entry, error)
+	Download(ctx context.Context, fileURL string, destination string) error
+	Close() error
+}
+
+// KindOf returns the watched root kind served at a normalized root URL.
+func KindOf(rootURL string) string {
+	if strings.HasPrefix(strings.ToLower(rootURL), "sftp://") {
+		return library.RootKindSFTP
+	}
+
+	return library.RootKindWebDAV
+}
+
+// Open connects to the remote root at a normalized root URL.
+func Open(ctx context.Context, rootURL string, username string, password string) (Source, error) {
+	if KindOf(rootURL) == library.RootKindSFTP {
+		return DialSFTP(ctx, rootURL, username, password)
+	}
+
+	return NewClient(rootURL, username, password)
+}
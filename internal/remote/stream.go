@@ -0,0 +1,224 @@
+package remote
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+)
+
+// sftpRoot is what the stream needs to connect to an SFTP root.
+type sftpRoot struct {
+	url      string
+	username string
+	password string
+}
+
+type streamedFile struct {
+	rootID int64
+	url    string
+}
+
+// sftpStream serves files of SFTP roots over a loopback HTTP server, so the
+// playback backend can stream and seek them like any other URL instead of
+// waiting for a download. Requests must carry a bearer token that is made
+// per run and only handed to the backend in a header.
+type sftpStream struct {
+	dial func(ctx context.Context, rootURL string, username string, password string) (*SFTPClient, error)
+
+	mu       sync.Mutex
+	token    string
+	baseURL  string
+	server   *http.Server
+	roots    map[int64]sftpRoot
+	clients  map[int64]*SFTPClient
+	files    map[string]streamedFile
+	closed   bool
+	startErr error
+}
+
+func newSFTPStream() *sftpStream {
+	return &sftpStream{
+		dial:    DialSFTP,
+		roots:   make(map[int64]sftpRoot),
+		clients: make(map[int64]*SFTPClient),
+		files:   make(map[string]streamedFile),
+	}
+}
+
+// URL registers a file of an SFTP root and returns the loopback URL and
+// headers the backend plays it with.
+func (s *sftpStream) URL(rootID int64, root sftpRoot, fileURL string) (string, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.startLocked(); err != nil {
+		return "", nil, err
+	}
+
+	if current, ok := s.roots[rootID]; ok && current != root {
+		// The root changed address or credentials; reconnect on next read.
+		if client := s.clients[rootID]; client != nil {
+			client.Close()
+			delete(s.clients, rootID)
+		}
+	}
+	s.roots[rootID] = root
+
+	sum := sha256.Sum256([]byte(fileURL))
+	key := hex.EncodeToString(sum[:16])
+	s.files[key] = streamedFile{rootID: rootID, url: fileURL}
+
+	name := "track"
+	if parsed, err := url.Parse(fileURL); err == nil && path.Base(parsed.Path) != "/" {
+		name = path.Base(parsed.Path)
+	}
+
+	return s.baseURL + "/sftp/" + key + "/" + url.PathEscape(name), []string{"Authorization: Bearer " + s.token}, nil
+}
+
+func (s *sftpStream) startLocked() error {
+	if s.closed {
+		return errors.New("remote stream is closed")
+	}
+	if s.server != nil || s.startErr != nil {
+		return s.startErr
+	}
+
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		s.startErr = fmt.Errorf("create stream token: %w", err)
+		return s.startErr
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		s.startErr = fmt.Errorf("start remote stream: %w", err)
+		return s.startErr
+	}
+
+	s.token = hex.EncodeToString(token)
+	s.baseURL = "http://" + listener.Addr().String()
+	s.server = &http.Server{Handler: http.HandlerFunc(s.serve)}
+	go func(server *http.Server) {
+		_ = server.Serve(listener)
+	}(s.server)
+
+	return nil
+}
+
+func (s *sftpStream) serve(rw http.ResponseWriter, request *http.Request) {
+	s.mu.Lock()
+	token := s.token
+	s.mu.Unlock()
+
+	bearer, _ := strings.CutPrefix(request.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+		http.Error(rw, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if request.Method != http.MethodGet && request.Method != http.MethodHead {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(request.URL.Path, "/sftp/"), "/", 2)
+	s.mu.Lock()
+	file, ok := s.files[parts[0]]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(rw, request)
+		return
+	}
+
+	client, err := s.client(request.Context(), file.rootID)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	remoteFile, err := client.OpenFile(file.url)
+	if err != nil {
+		// A dropped connection shows up here; reconnect on the next request.
+		s.dropClient(file.rootID, client)
+		http.Error(rw, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer remoteFile.Close()
+
+	info, err := remoteFile.Stat()
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	http.ServeContent(rw, request, info.Name(), info.ModTime(), remoteFile)
+}
+
+func (s *sftpStream) client(ctx context.Context, rootID int64) (*SFTPClient, error) {
+	s.mu.Lock()
+	if client := s.clients[rootID]; client != nil {
+		s.mu.Unlock()
+		return client, nil
+	}
+	root, ok := s.roots[rootID]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("remote root %d is not streamed", rootID)
+	}
+
+	client, err := s.dial(ctx, root.url, root.username, root.password)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current := s.clients[rootID]; current != nil || s.closed {
+		// Another request connected first, or the stream closed meanwhile.
+		client.Close()
+		if current == nil {
+			return nil, errors.New("remote stream is closed")
+		}
+		return current, nil
+	}
+	s.clients[rootID] = client
+
+	return client, nil
+}
+
+func (s *sftpStream) dropClient(rootID int64, client *SFTPClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.clients[rootID] == client {
+		delete(s.clients, rootID)
+		client.Close()
+	}
+}
+
+// Close stops the server and disconnects from every root.
+func (s *sftpStream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for rootID, client := range s.clients {
+		client.Close()
+		delete(s.clients, rootID)
+	}
+	if s.server == nil {
+		return nil
+	}
+
+	return s.server.Close()
+}
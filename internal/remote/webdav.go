@@ -0,0 +1,389 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const requestTimeout = 30 * time.Second
+
+// maxListingDepth bounds directory recursion so a server that reports
+// itself as a child collection cannot send the walker into a loop.
+const maxListingDepth = 32
+
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:">
+  <d:prop>
+    <d:resourcetype/>
+    <d:getcontentlength/>
+    <d:getlastmodified/>
+  </d:prop>
+</d:propfind>`
+
+var ErrUnsupportedScheme = errors.New("unsupported remote root scheme")
+
+// Entry is one file reported by a remote listing.
+type Entry struct {
+	URL          string
+	RelativePath string
+	Size         int64
+	ModTime      time.Time
+}
+
+// Client lists and downloads files below a WebDAV collection.
+type Client struct {
+	base     *url.URL
+	username string
+	password string
+	http     *http.Client
+}
+
+// NormalizeURL validates a remote root address and returns the canonical
+// form stored as the root path: credentials are stripped, webdav:// and
+// webdavs:// are mapped onto http and https, ssh:// onto sftp://, and the
+// path ends in a slash. Any userinfo in the address is returned separately.
+func NormalizeURL(rawURL string) (string, string, string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", "", "", fmt.Errorf("parse remote url: %w", err)
+	}
+
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https":
+		parsed.Scheme = strings.ToLower(parsed.Scheme)
+	case "webdav", "dav":
+		parsed.Scheme = "http"
+	case "webdavs", "davs":
+		parsed.Scheme = "https"
+	case "sftp", "ssh":
+		parsed.Scheme = "sftp"
+	default:
+		return "", "", "", fmt.Errorf("%w %q", ErrUnsupportedScheme, parsed.Scheme)
+	}
+	if parsed.Host == "" {
+		return "", "", "", errors.New("remote url must include a host")
+	}
+
+	username := ""
+	password := ""
+	if parsed.User != nil {
+		username = parsed.User.Username()
+		password, _ = parsed.User.Password()
+		parsed.User = nil
+	}
+
+	if !strings.HasSuffix(parsed.Path, "/") {
+		parsed.Path += "/"
+	}
+	parsed.RawPath = ""
+	parsed.RawQuery = ""
+	parsed.Fragment = ""
+
+	return parsed.String(), username, password, nil
+}
+
+func NewClient(rootURL string, username string, password string) (*Client, error) {
+	base, err := url.Parse(rootURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse remote root url: %w", err)
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+
+	return &Client{
+		base:     base,
+		username: username,
+		password: password,
+		http:     &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+// Walk lists every file below the root collection.
+func (c *Client) Walk(ctx context.Context) ([]Entry, error) {
+	type pendingDir struct {
+		url   *url.URL
+		depth int
+	}
+
+	entries := make([]Entry, 0, 256)
+	visited := map[string]struct{}{c.base.Path: {}}
+	pending := []pendingDir{{url: c.base}}
+
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+
+		responses, err := c.propfind(ctx, current.url)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, response := range responses {
+			resolved, err := current.url.Parse(strings.TrimSpace(response.Href))
+			if err != nil {
+				continue
+			}
+			if resolved.Host != c.base.Host || !strings.HasPrefix(resolved.Path, c.base.Path) {
+				continue
+			}
+
+			prop := response.okProp()
+			if prop == nil {
+				continue
+			}
+
+			if prop.ResourceType.Collection != nil {
+				dirPath := resolved.Path
+				if !strings.HasSuffix(dirPath, "/") {
+					dirPath += "/"
+				}
+				if _, seen := visited[dirPath]; seen || current.depth+1 > maxListingDepth {
+					continue
+				}
+				visited[dirPath] = struct{}{}
+
+				dirURL := *resolved
+				dirURL.Path = dirPath
+				dirURL.RawPath = ""
+				pending = append(pending, pendingDir{url: &dirURL, depth: current.depth + 1})
+				continue
+			}
+
+			size, _ := strconv.ParseInt(strings.TrimSpace(prop.ContentLength), 10, 64)
+			modTime, _ := http.ParseTime(strings.TrimSpace(prop.LastModified))
+
+			fileURL := *resolved
+			fileURL.RawPath = ""
+			fileURL.User = nil
+			entries = append(entries, Entry{
+				URL:          fileURL.String(),
+				RelativePath: strings.TrimPrefix(resolved.Path, c.base.Path),
+				Size:         size,
+				ModTime:      modTime.UTC(),
+			})
+		}
+	}
+
+	return entries, nil
+}
+
+// Download copies a remote file to destination, creating parent folders.
+func (c *Client) Download(ctx context.Context, fileURL string, destination string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return fmt.Errorf("build download request: %w", err)
+	}
+	c.authorize(request)
+
+	// Downloads can take far longer than a listing request.
+	client := *c.http
+	client.Timeout = 0
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("download %s: %w", fileURL, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("download %s: %s", fileURL, response.Status)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+		return fmt.Errorf("create download folder: %w", err)
+	}
+
+	file, err := os.Create(destination)
+	if err != nil {
+		return fmt.Errorf("create download file: %w", err)
+	}
+
+	if _, err := io.Copy(file, response.Body); err != nil {
+		file.Close()
+		return fmt.Errorf("download %s: %w", fileURL, err)
+	}
+
+	return file.Close()
+}
+
+// IsRemotePath reports whether a stored file path is a remote URL.
+func IsRemotePath(value string) bool {
+	lower := strings.ToLower(strings.TrimSpace(value))
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "sftp://")
+}
+
+// RelativeDir returns the slash-separated parent folder of a relative path.
+func RelativeDir(relativePath string) string {
+	dir := path.Dir(relativePath)
+	if dir == "." {
+		return ""
+	}
+
+	return dir
+}
+
+// Close releases the client. WebDAV requests hold no connection state, so
+// there is nothing to release; it satisfies Source.
+func (c *Client) Close() error {
+	return nil
+}
+
+func (c *Client) authorize(request *http.Request) {
+	if c.username != "" {
+		request.SetBasicAuth(c.username, c.password)
+	}
+}
+
+// authorizationHeader returns the HTTP header that authorizes requests for
+// files of a WebDAV root, in the "Name: value" form players accept.
+func authorizationHeader(username string, password string) []string {
+	if username == "" {
+		return nil
+	}
+
+	token := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	return []string{"Authorization: Basic " + token}
+}
+
+type multistatus struct {
+	Responses []davResponse `xml:"DAV: response"`
+}
+
+type davResponse struct {
+	Href      string        `xml:"DAV: href"`
+	Propstats []davPropstat `xml:"DAV: propstat"`
+}
+
+type davPropstat struct {
+	Status string  `xml:"DAV: status"`
+	Prop   davProp `xml:"DAV: prop"`
+}
+
+type davProp struct {
+	ResourceType struct {
+		Collection *struct{} `xml:"DAV: collection"`
+	} `xml:"DAV: resourcetype"`
+	ContentLength string `xml:"DAV: getcontentlength"`
+	LastModified  string `xml:"DAV: getlastmodified"`
+}
+
+func (r davResponse) okProp() *davProp {
+	for i := range r.Propstats {
+		fields := strings.Fields(r.Propstats[i].Status)
+		if len(fields) >= 2 && fields[1] == "200" {
+			return &r.Propstats[i].Prop
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) propfind(ctx context.Context, dirURL *url.URL) ([]davResponse, error) {
+	request, err := http.NewRequestWithContext(ctx, "PROPFIND", dirURL.String(), strings.NewReader(propfindBody))
+	if err != nil {
+		return nil, fmt.Errorf("build listing request: %w", err)
+	}
+	request.Header.Set("Depth", "1")
+	request.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	c.authorize(request)
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", dirURL.Redacted(), err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("list %s: %s", dirURL.Redacted(), response.Status)
+	}
+
+	var status multistatus
+	if err := xml.NewDecoder(response.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("decode listing for %s: %w", dirURL.Redacted(), err)
+	}
+
+	return status.Responses, nil
+}
+
+// Upload stores body at relativePath below the root collection, creating
+// the root collection first if the server reports it missing.
+func (c *Client) Upload(ctx context.Context, relativePath string, body []byte) error {
+	fileURL := c.base.JoinPath(relativePath).String()
+
+	status, err := c.put(ctx, fileURL, body)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusConflict || status == http.StatusNotFound {
+		if err := c.makeCollection(ctx); err != nil {
+			return err
+		}
+		status, err = c.put(ctx, fileURL, body)
+		if err != nil {
+			return err
+		}
+	}
+
+	if status < 200 || status > 299 {
+		return fmt.Errorf("upload %s: %s", relativePath, http.StatusText(status))
+	}
+
+	return nil
+}
+
+// FileURL returns the URL of relativePath below the root collection.
+func (c *Client) FileURL(relativePath string) string {
+	return c.base.JoinPath(relativePath).String()
+}
+
+func (c *Client) put(ctx context.Context, fileURL string, body []byte) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, fileURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("build upload request: %w", err)
+	}
+	c.authorize(request)
+
+	client := *c.http
+	client.Timeout = 0
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, fmt.Errorf("upload %s: %w", fileURL, err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	return response.StatusCode, nil
+}
+
+func (c *Client) makeCollection(ctx context.Context) error {
+	request, err := http.NewRequestWithContext(ctx, "MKCOL", c.base.String(), nil)
+	if err != nil {
+		return fmt.Errorf("build folder request: %w", err)
+	}
+	c.authorize(request)
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return fmt.Errorf("create folder %s: %w", c.base.Redacted(), err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusCreated && response.StatusCode != http.StatusMethodNotAllowed {
+		return fmt.Errorf("create folder %s: %s", c.base.Redacted(), response.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// DiscoverMethod returns the Document, as OpenRPC specifies.
+const DiscoverMethod = "rpc.discover"
+
+// maxRequestBytes bounds a request body.
+const maxRequestBytes = 4 << 20
+
+// JSON-RPC 2.0 error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeServerError    = -32000
+)
+
+type request struct {
+	JSONRPC string            `json:"jsonrpc"`
+	Method  string            `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+type response struct {
+	JSONRPC string
+	Result  any
+	Error   *Error
+	ID      json.RawMessage
+}
+
+// MarshalJSON writes exactly one of result and error, as JSON-RPC 2.0
+// requires: a successful call of a method that returns only an error still
+// has a null result.
+func (r response) MarshalJSON() ([]byte, error) {
+	if r.Error != nil {
+		return json.Marshal(struct {
+			JSONRPC string          `json:"jsonrpc"`
+			Error   *Error          `json:"error"`
+			ID      json.RawMessage `json:"id"`
+		}{r.JSONRPC, r.Error, r.ID})
+	}
+
+	return json.Marshal(struct {
+		JSONRPC string          `json:"jsonrpc"`
+		Result  any             `json:"result"`
+		ID      json.RawMessage `json:"id"`
+	}{r.JSONRPC, r.Result, r.ID})
+}
+
+// Error is a JSON-RPC error object.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type boundMethod struct {
+	receiver reflect.Value
+	method   reflect.Method
+}
+
+// Handler serves the service methods over JSON-RPC 2.0: POST a request, or
+// a batch of them, with positional params; GET returns the Document.
+type Handler struct {
+	document Document
+	methods  map[string]boundMethod
+}
+
+// NewHandler binds the exported methods of services, which are pointers to
+// service structs, as "Service.Method".
+func NewHandler(title string, version string, services []any) *Handler {
+	handler := &Handler{
+		document: Describe(title, version, services),
+		methods:  make(map[string]boundMethod),
+	}
+	for _, service := range services {
+		value := reflect.ValueOf(service)
+		for _, method := range exportedMethods(value.Type()) {
+			handler.methods[serviceName(value.Type())+"."+method.Name] = boundMethod{receiver: value, method: method}
+		}
+	}
+
+	return handler
+}
+
+func (h *Handler) Document() Document {
+	return h.document
+}
+
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		writeJSON(rw, h.document)
+		return
+	case http.MethodPost:
+	default:
+		rw.Header().Set("Allow", "GET, POST")
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxRequestBytes))
+	if err != nil {
+		writeJSON(rw, errorResponse(nil, CodeParseError, err.Error()))
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(body, &batch); err != nil || len(batch) == 0 {
+			writeJSON(rw, errorResponse(nil, CodeInvalidRequest, "invalid batch"))
+			return
+		}
+
+		responses := make([]response, 0, len(batch))
+		for _, raw := range batch {
+			if result, ok := h.handle(raw); ok {
+				responses = append(responses, result)
+			}
+		}
+		if len(responses) == 0 {
+			rw.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(rw, responses)
+		return
+	}
+
+	result, ok := h.handle(body)
+	if !ok {
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(rw, result)
+}
+
+// positionalParams splits the params of a request. Go methods have no
+// parameter names at run time, so by-name params are rejected; missing or
+// null params mean none.
+func positionalParams(raw json.RawMessage) ([]json.RawMessage, *Error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+	if raw[0] != '[' {
+		return nil, &Error{Code: CodeInvalidParams, Message: "params must be an array of positional arguments"}
+	}
+
+	var params []json.RawMessage
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+	}
+
+	return params, nil
+}
+
+// handle runs one request. It reports false for notifications, which get
+// no response.
+func (h *Handler) handle(raw json.RawMessage) (response, bool) {
+	var call request
+	if err := json.Unmarshal(raw, &call); err != nil {
+		return errorResponse(nil, CodeParseError, err.Error()), true
+	}
+	if call.JSONRPC != "2.0" || call.Method == "" {
+		return errorResponse(call.ID, CodeInvalidRequest, "expected a JSON-RPC 2.0 request"), true
+	}
+
+	result, rpcErr := h.call(call)
+	if len(call.ID) == 0 {
+		return response{}, false
+	}
+	if rpcErr != nil {
+		return errorResponse(call.ID, rpcErr.Code, rpcErr.Message), true
+	}
+	return response{JSONRPC: "2.0", Result: result, ID: call.ID}, true
+}
+
+func (h *Handler) call(call request) (any, *Error) {
+	if call.Method == DiscoverMethod {
+		return h.document, nil
+	}
+
+	bound, ok := h.methods[call.Method]
+	if !ok {
+		return nil, &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("method %s not found", call.Method)}
+	}
+
+	params, rpcErr := positionalParams(call.Params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	methodType := bound.method.Type
+	if len(params) != methodType.NumIn()-1 {
+		return nil, &Error{Code: CodeInvalidParams, Message: fmt.Sprintf("%s takes %d params, got %d", call.Method, methodType.NumIn()-1, len(params))}
+	}
+
+	args := make([]reflect.Value, 0, methodType.NumIn())
+	args = append(args, bound.receiver)
+	for index, raw := range params {
+		arg := reflect.New(methodType.In(index + 1))
+		if err := json.Unmarshal(raw, arg.Interface()); err != nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: fmt.Sprintf("param %d: %v", index, err)}
+		}
+		args = append(args, arg.Elem())
+	}
+
+	var result any
+	for _, out := range bound.method.Func.Call(args) {
+		if out.Type() == errorType {
+			if !out.IsNil() {
+				return nil, &Error{Code: CodeServerError, Message: out.Interface().(error).Error()}
+			}
+			continue
+		}
+		if result == nil {
+			result = out.Interface()
+		}
+	}
+
+	return result, nil
+}
+
+func errorResponse(id json.RawMessage, code int, message string) response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return response{JSONRPC: "2.0", Error: &Error{Code: code, Message: message}, ID: id}
+}
+
+func writeJSON(rw http.ResponseWriter, payload any) {
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(payload)
+}
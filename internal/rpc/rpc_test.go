@@ -0,0 +1,141 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testItem struct {
+	ID       int64    `json:"id"`
+	Title    string   `json:"title"`
+	Rating   *int     `json:"rating,omitempty"`
+	Children []string `json:"children"`
+	internal string
+}
+
+type TestService struct{}
+
+func (s *TestService) GetItem(id int64) (testItem, error) {
+	if id <= 0 {
+		return testItem{}, errors.New("item not found")
+	}
+	return testItem{ID: id, Title: "Item", Children: []string{}}, nil
+}
+
+func (s *TestService) Rename(id int64, title string) error {
+	return nil
+}
+
+func (s *TestService) ServiceName() string {
+	return "test"
+}
+
+func TestDescribeListsMethodsAndStructs(t *testing.T) {
+	t.Parallel()
+
+	document := Describe("Test", "1", []any{&TestService{}})
+
+	names := make([]string, 0, len(document.Methods))
+	for _, method := range document.Methods {
+		names = append(names, method.Name)
+	}
+	if strings.Join(names, ",") != "TestService.GetItem,TestService.Rename" {
+		t.Fatalf("unexpected methods %v", names)
+	}
+
+	getItem := document.Methods[0]
+	if len(getItem.Params) != 1 || getItem.Params[0].Schema["type"] != "integer" {
+		t.Fatalf("unexpected params %+v", getItem.Params)
+	}
+	if getItem.Result == nil || getItem.Result.Schema["$ref"] != "#/components/schemas/rpc.testItem" {
+		t.Fatalf("unexpected result %+v", getItem.Result)
+	}
+	if document.Methods[1].Result != nil {
+		t.Fatalf("expected a method returning only an error to have no result")
+	}
+
+	item := document.Components.Schemas["rpc.testItem"]
+	properties := item["properties"].(map[string]Schema)
+	if _, ok := properties["internal"]; ok || len(properties) != 4 {
+		t.Fatalf("unexpected properties %v", properties)
+	}
+	if required := item["required"].([]string); strings.Join(required, ",") != "id,title,children" {
+		t.Fatalf("unexpected required fields %v", required)
+	}
+}
+
+func TestHandlerCallsMethods(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHandler("Test", "1", []any{&TestService{}})
+	post := func(body string) map[string]any {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body)))
+		var decoded map[string]any
+		if err := json.Unmarshal(recorder.Body.Bytes(), &decoded); err != nil {
+			t.Fatalf("decode response %q: %v", recorder.Body.String(), err)
+		}
+		return decoded
+	}
+
+	result := post(`{"jsonrpc":"2.0","method":"TestService.GetItem","params":[7],"id":1}`)
+	if item, ok := result["result"].(map[string]any); !ok || item["id"] != float64(7) {
+		t.Fatalf("unexpected result %v", result)
+	}
+
+	failed := post(`{"jsonrpc":"2.0","method":"TestService.GetItem","params":[0],"id":2}`)
+	if rpcErr, ok := failed["error"].(map[string]any); !ok || rpcErr["message"] != "item not found" {
+		t.Fatalf("expected the method error, got %v", failed)
+	}
+	if _, ok := failed["result"]; ok {
+		t.Fatalf("expected no result next to an error, got %v", failed)
+	}
+
+	renamed := post(`{"jsonrpc":"2.0","method":"TestService.Rename","params":[1,"Other"],"id":6}`)
+	if result, ok := renamed["result"]; !ok || result != nil {
+		t.Fatalf("expected a null result for a method returning only an error, got %v", renamed)
+	}
+	if _, ok := renamed["error"]; ok {
+		t.Fatalf("expected no error, got %v", renamed)
+	}
+
+	missing := post(`{"jsonrpc":"2.0","method":"TestService.ServiceName","params":[],"id":3}`)
+	if rpcErr, ok := missing["error"].(map[string]any); !ok || rpcErr["code"] != float64(CodeMethodNotFound) {
+		t.Fatalf("expected lifecycle methods to be hidden, got %v", missing)
+	}
+
+	invalid := post(`{"jsonrpc":"2.0","method":"TestService.Rename","params":[1],"id":4}`)
+	if rpcErr, ok := invalid["error"].(map[string]any); !ok || rpcErr["code"] != float64(CodeInvalidParams) {
+		t.Fatalf("expected invalid params, got %v", invalid)
+	}
+
+	discovered := post(`{"jsonrpc":"2.0","method":"rpc.discover","id":5}`)
+	if document, ok := discovered["result"].(map[string]any); !ok || document["openrpc"] != OpenRPCVersion {
+		t.Fatalf("unexpected discovery %v", discovered)
+	}
+}
+
+func TestHandlerRejectsParamsThatAreNotAnArray(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHandler("Test", "1", []any{&TestService{}})
+	for _, params := range []string{`{"id":7}`, `7`, `"7"`} {
+		recorder := httptest.NewRecorder()
+		body := `{"jsonrpc":"2.0","method":"TestService.GetItem","params":` + params + `,"id":1}`
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body)))
+
+		var decoded map[string]any
+		if err := json.Unmarshal(recorder.Body.Bytes(), &decoded); err != nil {
+			t.Fatalf("decode response %q: %v", recorder.Body.String(), err)
+		}
+		rpcErr, ok := decoded["error"].(map[string]any)
+		if !ok || rpcErr["code"] != float64(CodeInvalidParams) || decoded["id"] != float64(1) {
+			t.Fatalf("params %s: expected invalid params for the request, got %v", params, decoded)
+		}
+	}
+}
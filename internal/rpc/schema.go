@@ -0,0 +1,286 @@
+package rpc
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OpenRPCVersion is the version of the OpenRPC specification Document
+// follows.
+const OpenRPCVersion = "1.2.6"
+
+// Schema is a JSON Schema.
+type Schema map[string]any
+
+// Document describes the service layer in the OpenRPC format, with the
+// events the app emits in the x-events extension.
+type Document struct {
+	OpenRPC    string     `json:"openrpc"`
+	Info       Info       `json:"info"`
+	Methods    []Method   `json:"methods"`
+	Components Components `json:"components"`
+	Events     []Event    `json:"x-events"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Method is one callable service method, named "Service.Method". Params
+// are positional.
+type Method struct {
+	Name           string              `json:"name"`
+	ParamStructure string              `json:"paramStructure"`
+	Params         []ContentDescriptor `json:"params"`
+	Result         *ContentDescriptor  `json:"result,omitempty"`
+}
+
+type ContentDescriptor struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required,omitempty"`
+	Schema   Schema `json:"schema"`
+}
+
+// Components holds the named struct schemas methods and events refer to.
+type Components struct {
+	Schemas map[string]Schema `json:"schemas"`
+}
+
+// Event is an event the app emits and the schema of its payload.
+type Event struct {
+	Name    string `json:"name"`
+	Payload Schema `json:"payload"`
+}
+
+type registeredEvent struct {
+	name        string
+	payloadType reflect.Type
+}
+
+var (
+	eventsMu sync.Mutex
+	events   []registeredEvent
+)
+
+// RegisterEvent adds an event and its payload type to every Document
+// described afterwards.
+func RegisterEvent[T any](name string) {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	events = append(events, registeredEvent{name: name, payloadType: reflect.TypeFor[T]()})
+}
+
+// lifecycleMethods are called by the application, not by clients.
+var lifecycleMethods = map[string]bool{
+	"ServiceName":     true,
+	"ServiceStartup":  true,
+	"ServiceShutdown": true,
+	"ServeHTTP":       true,
+}
+
+var (
+	errorType   = reflect.TypeFor[error]()
+	timeType    = reflect.TypeFor[time.Time]()
+	rawJSONType = reflect.TypeFor[json.RawMessage]()
+)
+
+// Describe builds the Document of services, which are pointers to the
+// service structs bound to the frontend.
+func Describe(title string, version string, services []any) Document {
+	builder := &schemaBuilder{schemas: make(map[string]Schema)}
+	document := Document{
+		OpenRPC: OpenRPCVersion,
+		Info:    Info{Title: title, Version: version},
+		Methods: []Method{},
+		Events:  []Event{},
+	}
+
+	for _, service := range services {
+		serviceType := reflect.TypeOf(service)
+		for _, method := range exportedMethods(serviceType) {
+			document.Methods = append(document.Methods, builder.method(serviceName(serviceType)+"."+method.Name, method.Type))
+		}
+	}
+	sort.Slice(document.Methods, func(i int, j int) bool {
+		return document.Methods[i].Name < document.Methods[j].Name
+	})
+
+	eventsMu.Lock()
+	registered := append([]registeredEvent(nil), events...)
+	eventsMu.Unlock()
+	for _, event := range registered {
+		document.Events = append(document.Events, Event{Name: event.name, Payload: builder.schema(event.payloadType)})
+	}
+	sort.SliceStable(document.Events, func(i int, j int) bool {
+		return document.Events[i].Name < document.Events[j].Name
+	})
+
+	document.Components.Schemas = builder.schemas
+	return document
+}
+
+func serviceName(serviceType reflect.Type) string {
+	for serviceType.Kind() == reflect.Pointer {
+		serviceType = serviceType.Elem()
+	}
+	return serviceType.Name()
+}
+
+// exportedMethods lists the methods clients may call. Method types
+// include the receiver as their first parameter.
+func exportedMethods(serviceType reflect.Type) []reflect.Method {
+	methods := make([]reflect.Method, 0, serviceType.NumMethod())
+	for index := range serviceType.NumMethod() {
+		method := serviceType.Method(index)
+		if method.IsExported() && !lifecycleMethods[method.Name] {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+type schemaBuilder struct {
+	schemas map[string]Schema
+}
+
+func (b *schemaBuilder) method(name string, methodType reflect.Type) Method {
+	method := Method{Name: name, ParamStructure: "by-position", Params: []ContentDescriptor{}}
+	for index := 1; index < methodType.NumIn(); index++ {
+		method.Params = append(method.Params, ContentDescriptor{
+			Name:     "arg" + strconv.Itoa(index-1),
+			Required: true,
+			Schema:   b.schema(methodType.In(index)),
+		})
+	}
+
+	for index := range methodType.NumOut() {
+		out := methodType.Out(index)
+		if out == errorType {
+			continue
+		}
+		method.Result = &ContentDescriptor{Name: "result", Schema: b.schema(out)}
+		break
+	}
+
+	return method
+}
+
+func (b *schemaBuilder) schema(valueType reflect.Type) Schema {
+	switch valueType {
+	case timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case rawJSONType:
+		return Schema{}
+	}
+
+	switch valueType.Kind() {
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Pointer:
+		return Schema{"oneOf": []Schema{b.schema(valueType.Elem()), {"type": "null"}}}
+	case reflect.Slice, reflect.Array:
+		if valueType.Elem().Kind() == reflect.Uint8 {
+			return Schema{"type": "string", "contentEncoding": "base64"}
+		}
+		return Schema{"type": "array", "items": b.schema(valueType.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": b.schema(valueType.Elem())}
+	case reflect.Struct:
+		return b.structRef(valueType)
+	default:
+		return Schema{}
+	}
+}
+
+// structRef describes a named struct once in the components and refers to
+// it; anonymous structs are described inline.
+func (b *schemaBuilder) structRef(structType reflect.Type) Schema {
+	if structType.Name() == "" {
+		return b.structSchema(structType)
+	}
+
+	name := schemaName(structType)
+	if _, ok := b.schemas[name]; !ok {
+		// Reserve the name first, so recursive types refer to themselves.
+		b.schemas[name] = Schema{}
+		b.schemas[name] = b.structSchema(structType)
+	}
+	return Schema{"$ref": "#/components/schemas/" + name}
+}
+
+func (b *schemaBuilder) structSchema(structType reflect.Type) Schema {
+	properties := make(map[string]Schema)
+	required := make([]string, 0)
+	b.addFields(structType, properties, &required)
+
+	schema := Schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// addFields adds the fields encoding/json would write for structType,
+// inlining embedded structs without a JSON name.
+func (b *schemaBuilder) addFields(structType reflect.Type, properties map[string]Schema, required *[]string) {
+	for index := range structType.NumField() {
+		field := structType.Field(index)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				b.addFields(embedded, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = b.schema(field.Type)
+		if !strings.Contains(options, "omitempty") && !strings.Contains(options, "omitzero") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// schemaName names a struct by its package and type, e.g. "library.TrackSummary".
+func schemaName(structType reflect.Type) string {
+	name := structType.Name()
+	if index := strings.IndexByte(name, '['); index >= 0 {
+		name = name[:index]
+	}
+
+	pkg := structType.PkgPath()
+	if slash := strings.LastIndexByte(pkg, '/'); slash >= 0 {
+		pkg = pkg[slash+1:]
+	}
+	if pkg == "" {
+		return name
+	}
+	return pkg + "." + name
+}
@@ -0,0 +1,266 @@
+package scanner
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rzxx/ben/internal/archive"
+	"github.com/rzxx/ben/internal/library"
+)
+
+// ArchiveIndexingSettingKey enables indexing audio inside .zip archives, as
+// sold by Bandcamp and others. Archives are read-only: their entries are
+// extracted to a staging folder to read tags and artwork, and extracted
+// again for playback.
+const ArchiveIndexingSettingKey = "library.index_zip_archives"
+
+func (s *Service) ArchiveIndexingEnabled(ctx context.Context) bool {
+	enabled, err := strconv.ParseBool(s.settings.GetString(ctx, ArchiveIndexingSettingKey, "false"))
+	return err == nil && enabled
+}
+
+func (s *Service) SetArchiveIndexingEnabled(ctx context.Context, enabled bool) (bool, error) {
+	if err := s.settings.Set(ctx, ArchiveIndexingSettingKey, strconv.FormatBool(enabled)); err != nil {
+		return s.ArchiveIndexingEnabled(ctx), err
+	}
+
+	return enabled, nil
+}
+
+// scanZipArchive indexes the audio entries of an archive under root. Entries
+// whose size and modification time match the last scan are not extracted
+// again. The entries are recorded in scan_seen_paths for missing file
+// reconciliation. An unreadable archive is skipped rather than failing the
+// scan.
+func scanZipArchive(
+	ctx context.Context,
+	tx *sql.Tx,
+	root library.WatchedRoot,
+	archivePath string,
+	scannedAt string,
+	mode scanMode,
+	coverCacheDir string,
+) (scanTotals, error) {
+	totals := scanTotals{}
+
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		totals.skipped++
+		return totals, nil
+	}
+	defer reader.Close()
+
+	stagingDir, err := os.MkdirTemp("", "ben-archive-scan-*")
+	if err != nil {
+		return scanTotals{}, fmt.Errorf("create archive staging folder: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	// Entries are staged below the archive's own relative path, so fallback
+	// metadata reads the archive name like a folder name.
+	relativeArchive := filepath.Base(archivePath)
+	if rel, relErr := filepath.Rel(root.Path, archivePath); relErr == nil {
+		relativeArchive = rel
+	}
+	stagingBase := filepath.Join(stagingDir, strings.TrimSuffix(relativeArchive, filepath.Ext(relativeArchive)))
+
+	artworkByDir := make(map[string][]*zip.File)
+	for _, file := range reader.File {
+		if archive.ValidEntryName(file.Name) && isSupportedArtworkExtension(strings.ToLower(path.Ext(file.Name))) {
+			dir := path.Dir(file.Name)
+			artworkByDir[dir] = append(artworkByDir[dir], file)
+		}
+	}
+
+	extractedArtworkDirs := make(map[string]struct{})
+	extractArtwork := func(dir string) {
+		dirs := []string{dir}
+		if dir != "." && shouldSearchParentForSidecar(dir) {
+			dirs = append(dirs, path.Dir(dir))
+		}
+
+		for _, artworkDir := range dirs {
+			if _, done := extractedArtworkDirs[artworkDir]; done {
+				continue
+			}
+			extractedArtworkDirs[artworkDir] = struct{}{}
+
+			for _, artwork := range artworkByDir[artworkDir] {
+				// Unreadable artwork only costs the sidecar cover.
+				_ = archive.ExtractFile(artwork, filepath.Join(stagingBase, filepath.FromSlash(artwork.Name)))
+			}
+		}
+	}
+
+	for _, file := range reader.File {
+		if err := ctx.Err(); err != nil {
+			return scanTotals{}, err
+		}
+		if !archive.ValidEntryName(file.Name) || !isPlayableExtension(strings.ToLower(path.Ext(file.Name))) {
+			continue
+		}
+
+		totals.filesSeen++
+		if seenErr := markPathSeenIncremental(ctx, tx, archive.EntryPath(archivePath, file.Name)); seenErr != nil {
+			return scanTotals{}, seenErr
+		}
+
+		indexed, err := upsertArchiveFileAndTrack(ctx, tx, root.ID, archivePath, file, stagingDir, stagingBase, scannedAt, mode, coverCacheDir, extractArtwork)
+		if errors.Is(err, errArchiveEntryUnreadable) {
+			totals.skipped++
+			continue
+		}
+		if err != nil {
+			return scanTotals{}, err
+		}
+
+		if indexed {
+			totals.indexed++
+			totals.libraryChanged = true
+		}
+	}
+
+	return totals, nil
+}
+
+var errArchiveEntryUnreadable = errors.New("archive entry is unreadable")
+
+func upsertArchiveFileAndTrack(
+	ctx context.Context,
+	tx *sql.Tx,
+	rootID int64,
+	archivePath string,
+	file *zip.File,
+	stagingDir string,
+	stagingBase string,
+	scannedAt string,
+	mode scanMode,
+	coverCacheDir string,
+	extractArtwork func(dir string),
+) (bool, error) {
+	entryPath := archive.EntryPath(archivePath, file.Name)
+
+	var (
+		fileID       int64
+		currentSize  int64
+		currentMTime int64
+	)
+
+	err := tx.QueryRowContext(
+		ctx,
+		"SELECT id, size, mtime_ns FROM files WHERE path = ?",
+		entryPath,
+	).Scan(&fileID, &currentSize, &currentMTime)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("get file row %s: %w", entryPath, err)
+	}
+	found := err == nil
+
+	newSize := int64(file.UncompressedSize64)
+	newMTime := int64(0)
+	if !file.Modified.IsZero() {
+		newMTime = file.Modified.UnixNano()
+	}
+
+	metadataCurrent := false
+	if found && mode != scanModeRepair && currentSize == newSize && currentMTime == newMTime {
+		metadataCurrent, err = hasCurrentTrackMetadata(ctx, tx, fileID, entryPath)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if metadataCurrent {
+		if _, err := tx.ExecContext(
+			ctx,
+			"UPDATE files SET root_id = ?, file_exists = 1, last_seen_at = ? WHERE id = ?",
+			rootID,
+			scannedAt,
+			fileID,
+		); err != nil {
+			return false, fmt.Errorf("update file %s: %w", entryPath, err)
+		}
+
+		return false, nil
+	}
+
+	localPath := filepath.Join(stagingBase, filepath.FromSlash(file.Name))
+	if extractErr := archive.ExtractFile(file, localPath); extractErr != nil {
+		if found {
+			// Keep the previous index entry; the stale size and mtime make
+			// the next scan retry the extraction.
+			if _, err := tx.ExecContext(
+				ctx,
+				"UPDATE files SET file_exists = 1, last_seen_at = ? WHERE id = ?",
+				scannedAt,
+				fileID,
+			); err != nil {
+				return false, fmt.Errorf("update file %s: %w", entryPath, err)
+			}
+		}
+		return false, fmt.Errorf("%w: %v", errArchiveEntryUnreadable, extractErr)
+	}
+	defer os.Remove(localPath)
+
+	extractArtwork(path.Dir(file.Name))
+
+	if found {
+		if _, err := tx.ExecContext(
+			ctx,
+			`UPDATE files
+			 SET root_id = ?, size = ?, mtime_ns = ?, file_exists = 1, last_seen_at = ?
+			 WHERE id = ?`,
+			rootID,
+			newSize,
+			newMTime,
+			scannedAt,
+			fileID,
+		); err != nil {
+			return false, fmt.Errorf("update file %s: %w", entryPath, err)
+		}
+	} else {
+		result, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO files(path, root_id, size, mtime_ns, file_exists, last_seen_at, first_seen_at)
+			 VALUES (?, ?, ?, ?, 1, ?, ?)`,
+			entryPath,
+			rootID,
+			newSize,
+			newMTime,
+			scannedAt,
+			scannedAt,
+		)
+		if err != nil {
+			return false, fmt.Errorf("insert file %s: %w", entryPath, err)
+		}
+
+		fileID, err = result.LastInsertId()
+		if err != nil {
+			return false, fmt.Errorf("read file id %s: %w", entryPath, err)
+		}
+	}
+
+	metadata, err := deriveMetadata(stagingDir, localPath)
+	if err != nil {
+		return false, err
+	}
+	metadata.tags["archive_path"] = archivePath
+
+	if err := upsertTrackMetadata(ctx, tx, fileID, entryPath, metadata); err != nil {
+		return false, err
+	}
+
+	if _, err := syncCoverForFile(ctx, tx, fileID, localPath, coverCacheDir, true); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
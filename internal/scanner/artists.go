@@ -0,0 +1,52 @@
+package scanner
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+// replaceTrackArtists stores every artist credited on the track of fileID,
+// from its ARTISTS tag or else split from its artist credit. A track
+// without an artist is credited to "Unknown Artist".
+func replaceTrackArtists(ctx context.Context, tx *sql.Tx, fileID int64, metadata extractedMetadata) error {
+	var trackID int64
+	if err := tx.QueryRowContext(ctx, "SELECT id FROM tracks WHERE file_id = ?", fileID).Scan(&trackID); err != nil {
+		return fmt.Errorf("find track of file %d: %w", fileID, err)
+	}
+
+	artists := metadata.artists
+	if len(artists) == 0 {
+		artists = library.SplitArtists(metadata.artist)
+	}
+	if len(artists) == 0 {
+		artists = []string{"Unknown Artist"}
+	}
+
+	// The sort tag names the whole credit, so it only romanizes a single
+	// artist.
+	sortName := ""
+	if len(artists) == 1 {
+		sortName = metadata.artistSort
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM track_artists WHERE track_id = ?", trackID); err != nil {
+		return fmt.Errorf("clear artists of track %d: %w", trackID, err)
+	}
+	for position, artist := range artists {
+		if _, err := tx.ExecContext(
+			ctx,
+			"INSERT OR IGNORE INTO track_artists(track_id, artist, position, romanized) VALUES (?, ?, ?, ?)",
+			trackID,
+			artist,
+			position,
+			nullableString(library.RomanizedAlias(artist, sortName)),
+		); err != nil {
+			return fmt.Errorf("add artist %q to track %d: %w", artist, trackID, err)
+		}
+	}
+
+	return nil
+}
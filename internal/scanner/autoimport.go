@@ -0,0 +1,764 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/rzxx/ben/internal/archive"
+	"github.com/rzxx/ben/internal/eventbus"
+)
+
+// EventImported reports the files moved out of the auto-import folder.
+const EventImported = "scanner:imported"
+
+const (
+	AutoImportFolderSettingKey   = "library.auto_import.folder"
+	AutoImportRootSettingKey     = "library.auto_import.root_id"
+	AutoImportOrganizeSettingKey = "library.auto_import.organize"
+	AutoImportPatternSettingKey  = "library.auto_import.pattern"
+)
+
+const DefaultAutoImportPattern = "{albumartist}/{album}/{track} {title}"
+
+// autoImportSettleDelay is how long a file must stay untouched before it is
+// imported, so downloads that are still being written are left alone.
+const autoImportSettleDelay = 3 * time.Second
+
+// partialDownloadExtensions are what browsers and download managers name
+// files while they are still being written.
+var partialDownloadExtensions = map[string]struct{}{
+	".crdownload": {},
+	".download":   {},
+	".part":       {},
+	".partial":    {},
+	".tmp":        {},
+}
+
+// AutoImportConfig describes the auto-import folder. An empty Folder
+// disables it. Imported files go to the watched root RootID, either at the
+// same relative path or, with Organize, at the path Pattern builds from
+// their tags.
+type AutoImportConfig struct {
+	Folder   string `json:"folder"`
+	RootID   int64  `json:"rootId"`
+	Organize bool   `json:"organize"`
+	Pattern  string `json:"pattern"`
+}
+
+type ImportedFile struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type ImportFailure struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// ImportSnapshotter records the files an import moved, so the import can be
+// rolled back with RestoreImports.
+type ImportSnapshotter func(ctx context.Context, files []ImportedFile) error
+
+type ImportResult struct {
+	Imported []ImportedFile  `json:"imported"`
+	Failed   []ImportFailure `json:"failed"`
+	At       string          `json:"at"`
+}
+
+// AutoImporter watches the auto-import folder and moves files dropped there
+// into the library, then has the scanner index them.
+type AutoImporter struct {
+	mu       sync.Mutex
+	importMu sync.Mutex
+	scanner  *Service
+	config   AutoImportConfig
+	emit     Emitter
+	watcher  *fsnotify.Watcher
+	stop     chan struct{}
+	debounce *time.Timer
+	snapshot ImportSnapshotter
+	// restored holds the modification times of files moved back into the
+	// import folder by RestoreImports, which are left alone until they
+	// change. It is guarded by importMu.
+	restored map[string]time.Time
+}
+
+func NewAutoImporter(scanService *Service, bus *eventbus.Bus) *AutoImporter {
+	return &AutoImporter{scanner: scanService, emit: bus.Publish, restored: make(map[string]time.Time)}
+}
+
+func (a *AutoImporter) SetSnapshotter(snapshotter ImportSnapshotter) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.snapshot = snapshotter
+}
+
+func (a *AutoImporter) Start() error {
+	return a.apply(a.loadConfig(context.Background()))
+}
+
+func (a *AutoImporter) Stop() error {
+	return a.stopWatching()
+}
+
+func (a *AutoImporter) Config() AutoImportConfig {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.config
+}
+
+func (a *AutoImporter) loadConfig(ctx context.Context) AutoImportConfig {
+	store := a.scanner.settings
+	config := AutoImportConfig{
+		Folder:  store.GetString(ctx, AutoImportFolderSettingKey, ""),
+		Pattern: store.GetString(ctx, AutoImportPatternSettingKey, DefaultAutoImportPattern),
+	}
+	config.RootID, _ = strconv.ParseInt(store.GetString(ctx, AutoImportRootSettingKey, "0"), 10, 64)
+	config.Organize, _ = strconv.ParseBool(store.GetString(ctx, AutoImportOrganizeSettingKey, "false"))
+
+	return config
+}
+
+// SetConfig validates and stores the auto-import settings and restarts the
+// watcher. Files already in the folder are imported right away.
+func (a *AutoImporter) SetConfig(ctx context.Context, config AutoImportConfig) (AutoImportConfig, error) {
+	normalized, err := a.normalizeConfig(ctx, config)
+	if err != nil {
+		return a.Config(), err
+	}
+
+	store := a.scanner.settings
+	values := map[string]string{
+		AutoImportFolderSettingKey:   normalized.Folder,
+		AutoImportRootSettingKey:     strconv.FormatInt(normalized.RootID, 10),
+		AutoImportOrganizeSettingKey: strconv.FormatBool(normalized.Organize),
+		AutoImportPatternSettingKey:  normalized.Pattern,
+	}
+	for key, value := range values {
+		if err := store.Set(ctx, key, value); err != nil {
+			return a.Config(), err
+		}
+	}
+
+	return normalized, a.apply(normalized)
+}
+
+func (a *AutoImporter) normalizeConfig(ctx context.Context, config AutoImportConfig) (AutoImportConfig, error) {
+	normalized := AutoImportConfig{
+		Folder:   strings.TrimSpace(config.Folder),
+		RootID:   config.RootID,
+		Organize: config.Organize,
+		Pattern:  strings.TrimSpace(config.Pattern),
+	}
+	if normalized.Pattern == "" {
+		normalized.Pattern = DefaultAutoImportPattern
+	}
+	if normalized.Organize && !strings.Contains(normalized.Pattern, "{title}") {
+		return AutoImportConfig{}, errors.New("import pattern must contain {title}")
+	}
+	if normalized.Folder == "" {
+		return normalized, nil
+	}
+
+	absFolder, err := filepath.Abs(normalized.Folder)
+	if err != nil {
+		return AutoImportConfig{}, fmt.Errorf("resolve import folder: %w", err)
+	}
+	normalized.Folder = filepath.Clean(absFolder)
+	info, err := os.Stat(normalized.Folder)
+	if err != nil {
+		return AutoImportConfig{}, fmt.Errorf("import folder: %w", err)
+	}
+	if !info.IsDir() {
+		return AutoImportConfig{}, errors.New("import folder must be a directory")
+	}
+
+	if _, err := a.targetRootPath(ctx, normalized.RootID); err != nil {
+		return AutoImportConfig{}, err
+	}
+	roots, err := a.scanner.roots.List(ctx)
+	if err != nil {
+		return AutoImportConfig{}, fmt.Errorf("list watched roots: %w", err)
+	}
+	for _, root := range roots {
+		if root.IsRemote() {
+			continue
+		}
+		if isSameOrNestedPath(normalized.Folder, root.Path) || isSameOrNestedPath(root.Path, normalized.Folder) {
+			return AutoImportConfig{}, fmt.Errorf("import folder overlaps the library folder %s", root.Path)
+		}
+	}
+
+	return normalized, nil
+}
+
+func (a *AutoImporter) targetRootPath(ctx context.Context, rootID int64) (string, error) {
+	roots, err := a.scanner.roots.List(ctx)
+	if err != nil {
+		return "", fmt.Errorf("list watched roots: %w", err)
+	}
+	for _, root := range roots {
+		if root.ID != rootID {
+			continue
+		}
+		if root.IsRemote() || !root.Enabled {
+			return "", errors.New("imports need an enabled local library folder")
+		}
+		return filepath.Clean(root.Path), nil
+	}
+
+	return "", errors.New("library folder for imports not found")
+}
+
+func (a *AutoImporter) apply(config AutoImportConfig) error {
+	if err := a.stopWatching(); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.config = config
+	a.mu.Unlock()
+
+	if config.Folder == "" {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create import folder watcher: %w", err)
+	}
+	if err := addImportWatchDirs(watcher, config.Folder); err != nil {
+		watcher.Close()
+		return fmt.Errorf("watch import folder: %w", err)
+	}
+
+	stopCh := make(chan struct{})
+	a.mu.Lock()
+	a.watcher = watcher
+	a.stop = stopCh
+	a.mu.Unlock()
+
+	go a.watchLoop(watcher, stopCh)
+	a.schedule(0)
+
+	return nil
+}
+
+func addImportWatchDirs(watcher *fsnotify.Watcher, folder string) error {
+	return filepath.WalkDir(folder, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+}
+
+func (a *AutoImporter) stopWatching() error {
+	a.mu.Lock()
+	watcher := a.watcher
+	stopCh := a.stop
+	debounce := a.debounce
+	a.watcher = nil
+	a.stop = nil
+	a.debounce = nil
+	a.mu.Unlock()
+
+	if debounce != nil {
+		debounce.Stop()
+	}
+	if stopCh != nil {
+		close(stopCh)
+	}
+	if watcher != nil {
+		if err := watcher.Close(); err != nil {
+			return fmt.Errorf("close import folder watcher: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (a *AutoImporter) watchLoop(watcher *fsnotify.Watcher, stopCh <-chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					_ = addImportWatchDirs(watcher, event.Name)
+				}
+			}
+			if event.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Rename) != 0 {
+				a.schedule(autoImportSettleDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("auto-import watcher: %v", err)
+		}
+	}
+}
+
+func (a *AutoImporter) schedule(delay time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stop == nil {
+		return
+	}
+	if a.debounce != nil {
+		a.debounce.Stop()
+	}
+	a.debounce = time.AfterFunc(delay, func() {
+		result, settling, err := a.importPending(time.Now())
+		if err != nil {
+			log.Printf("auto-import: %v", err)
+		}
+		if settling {
+			a.schedule(autoImportSettleDelay)
+		}
+		if len(result.Imported) > 0 || len(result.Failed) > 0 {
+			a.publish(result)
+		}
+	})
+}
+
+// ImportNow imports every settled file in the folder.
+func (a *AutoImporter) ImportNow() (ImportResult, error) {
+	result, settling, err := a.importPending(time.Now())
+	if settling {
+		a.schedule(autoImportSettleDelay)
+	}
+	if len(result.Imported) > 0 || len(result.Failed) > 0 {
+		a.publish(result)
+	}
+
+	return result, err
+}
+
+func (a *AutoImporter) publish(result ImportResult) {
+	a.mu.Lock()
+	emitter := a.emit
+	a.mu.Unlock()
+
+	if emitter != nil {
+		emitter(EventImported, result)
+	}
+}
+
+type importCandidate struct {
+	path     string
+	artwork  bool
+	archive  bool
+	playable bool
+}
+
+// importPending moves settled files into the library. It reports whether
+// some files were left because they are still being written. Every move is
+// planned and handed to the snapshotter before the first file moves, so an
+// import that stops halfway can still be rolled back.
+func (a *AutoImporter) importPending(now time.Time) (ImportResult, bool, error) {
+	a.importMu.Lock()
+	defer a.importMu.Unlock()
+
+	ctx := context.Background()
+	config := a.Config()
+	result := ImportResult{Imported: []ImportedFile{}, Failed: []ImportFailure{}, At: now.UTC().Format(time.RFC3339)}
+	if config.Folder == "" {
+		return result, false, nil
+	}
+
+	targetDir, err := a.targetRootPath(ctx, config.RootID)
+	if err != nil {
+		return result, false, err
+	}
+	indexArchives := a.scanner.ArchiveIndexingEnabled(ctx)
+
+	candidates := make([]importCandidate, 0)
+	settling := false
+	walkErr := filepath.WalkDir(config.Folder, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil || entry.IsDir() {
+			return nil
+		}
+
+		extension := strings.ToLower(filepath.Ext(path))
+		if _, partial := partialDownloadExtensions[extension]; partial {
+			settling = true
+			return nil
+		}
+		candidate := importCandidate{
+			path:     path,
+			artwork:  isSupportedArtworkExtension(extension),
+			archive:  indexArchives && archive.IsArchive(path),
+			playable: isPlayableExtension(extension),
+		}
+		if !candidate.artwork && !candidate.archive && !candidate.playable {
+			return nil
+		}
+
+		info, infoErr := entry.Info()
+		if infoErr != nil {
+			return nil
+		}
+		if restoredAt, ok := a.restored[path]; ok {
+			if info.ModTime().Equal(restoredAt) {
+				return nil
+			}
+			delete(a.restored, path)
+		}
+		if now.Sub(info.ModTime()) < autoImportSettleDelay {
+			settling = true
+			return nil
+		}
+
+		candidates = append(candidates, candidate)
+		return nil
+	})
+	if walkErr != nil {
+		return result, settling, fmt.Errorf("read import folder: %w", walkErr)
+	}
+
+	moves, failures := planImportMoves(config, targetDir, candidates)
+	result.Failed = append(result.Failed, failures...)
+	if len(moves) > 0 {
+		planned := make([]ImportedFile, 0, len(moves))
+		for _, move := range moves {
+			planned = append(planned, move.file)
+		}
+		a.recordSnapshot(ctx, planned)
+	}
+
+	moved := make([]string, 0, len(moves))
+	for _, move := range moves {
+		finalPath, moveErr := moveImportFile(move.file.From, move.file.To)
+		if moveErr != nil {
+			result.Failed = append(result.Failed, ImportFailure{Path: move.file.From, Error: moveErr.Error()})
+			continue
+		}
+		if move.playable {
+			moveLyricsSidecar(move.file.From, finalPath)
+		}
+
+		result.Imported = append(result.Imported, ImportedFile{From: move.file.From, To: finalPath})
+		moved = append(moved, finalPath)
+	}
+
+	removeEmptyImportDirs(config.Folder)
+	if len(moved) > 0 {
+		a.scanner.NotifyPathsChanged(moved)
+	}
+
+	return result, settling, nil
+}
+
+func (a *AutoImporter) recordSnapshot(ctx context.Context, files []ImportedFile) {
+	a.mu.Lock()
+	snapshotter := a.snapshot
+	a.mu.Unlock()
+
+	if snapshotter == nil {
+		return
+	}
+	if err := snapshotter(ctx, files); err != nil {
+		log.Printf("auto-import: snapshot import: %v", err)
+	}
+}
+
+// RestoreImports moves imported files back to where they were found, last
+// first, and has the scanner drop them from the library. Files moved or
+// deleted since are skipped, and a file is not moved over one that took its
+// old place. Restored files are not imported again until they change.
+func (a *AutoImporter) RestoreImports(files []ImportedFile) error {
+	a.importMu.Lock()
+	defer a.importMu.Unlock()
+
+	var errs []error
+	restored := make([]string, 0, len(files))
+	for index := len(files) - 1; index >= 0; index-- {
+		file := files[index]
+		if _, err := os.Stat(file.To); err != nil {
+			continue
+		}
+		if _, err := os.Stat(file.From); err == nil {
+			errs = append(errs, fmt.Errorf("restore %s: %s already exists", file.To, file.From))
+			continue
+		}
+
+		if _, err := moveImportFile(file.To, file.From); err != nil {
+			errs = append(errs, fmt.Errorf("restore %s: %w", file.To, err))
+			continue
+		}
+		moveLyricsSidecar(file.To, file.From)
+		if info, err := os.Stat(file.From); err == nil {
+			a.restored[file.From] = info.ModTime()
+		}
+		// Remove fails on folders that still hold files, which is intended.
+		_ = os.Remove(filepath.Dir(file.To))
+
+		restored = append(restored, file.To)
+	}
+
+	if len(restored) > 0 {
+		a.scanner.NotifyPathsChanged(restored)
+	}
+
+	return errors.Join(errs...)
+}
+
+type importMove struct {
+	file     ImportedFile
+	playable bool
+}
+
+// planImportMoves picks the library path of every candidate. Audio goes
+// first so artwork can follow its album into the library. Paths taken on
+// disk or by an earlier move of the plan are numbered.
+func planImportMoves(config AutoImportConfig, targetDir string, candidates []importCandidate) ([]importMove, []ImportFailure) {
+	destinationDirs := make(map[string]string)
+	reserved := make(map[string]struct{})
+	moves := make([]importMove, 0, len(candidates))
+	failures := make([]ImportFailure, 0)
+	for _, pass := range []func(importCandidate) bool{
+		func(candidate importCandidate) bool { return candidate.playable || candidate.archive },
+		func(candidate importCandidate) bool { return candidate.artwork },
+	} {
+		for _, candidate := range candidates {
+			if !pass(candidate) {
+				continue
+			}
+
+			sourceDir := filepath.Dir(candidate.path)
+			destination := ""
+			switch {
+			case candidate.artwork:
+				dir, ok := destinationDirs[sourceDir]
+				if !ok {
+					if config.Organize {
+						// Artwork without audio next to it has no album to join.
+						continue
+					}
+					dir = filepath.Join(targetDir, relativeImportPath(config.Folder, sourceDir))
+				}
+				destination = filepath.Join(dir, filepath.Base(candidate.path))
+			case config.Organize && candidate.playable:
+				metadata, metaErr := deriveMetadata(config.Folder, candidate.path)
+				if metaErr != nil {
+					failures = append(failures, ImportFailure{Path: candidate.path, Error: metaErr.Error()})
+					continue
+				}
+				destination = filepath.Join(targetDir, renderImportPattern(config.Pattern, metadata)+strings.ToLower(filepath.Ext(candidate.path)))
+			case config.Organize:
+				destination = filepath.Join(targetDir, filepath.Base(candidate.path))
+			default:
+				destination = filepath.Join(targetDir, relativeImportPath(config.Folder, candidate.path))
+			}
+
+			destination = uniqueImportPath(destination, reserved)
+			reserved[destination] = struct{}{}
+			if candidate.playable {
+				if _, ok := destinationDirs[sourceDir]; !ok {
+					destinationDirs[sourceDir] = filepath.Dir(destination)
+				}
+			}
+
+			moves = append(moves, importMove{
+				file:     ImportedFile{From: candidate.path, To: destination},
+				playable: candidate.playable,
+			})
+		}
+	}
+
+	return moves, failures
+}
+
+func relativeImportPath(folder string, path string) string {
+	relative, err := filepath.Rel(folder, path)
+	if err != nil || !filepath.IsLocal(relative) {
+		return filepath.Base(path)
+	}
+
+	return relative
+}
+
+// renderImportPattern builds the relative library path of a track, without
+// extension, from a pattern such as "{albumartist}/{album}/{track} {title}".
+// Every folder and file name is made safe for the file system.
+func renderImportPattern(pattern string, metadata extractedMetadata) string {
+	number := func(value *int) string {
+		if value == nil || *value <= 0 {
+			return ""
+		}
+		return fmt.Sprintf("%02d", *value)
+	}
+	year := ""
+	if metadata.year != nil && *metadata.year > 0 {
+		year = strconv.Itoa(*metadata.year)
+	}
+	albumArtist := metadata.albumArtist
+	if strings.TrimSpace(albumArtist) == "" {
+		albumArtist = metadata.artist
+	}
+
+	replacer := strings.NewReplacer(
+		"{artist}", sanitizePathPart(metadata.artist),
+		"{albumartist}", sanitizePathPart(albumArtist),
+		"{album}", sanitizePathPart(metadata.album),
+		"{title}", sanitizePathPart(metadata.title),
+		"{track}", number(metadata.trackNo),
+		"{disc}", number(metadata.discNo),
+		"{year}", year,
+	)
+
+	parts := strings.Split(filepath.ToSlash(pattern), "/")
+	rendered := make([]string, 0, len(parts))
+	for _, part := range parts {
+		value := strings.Join(strings.Fields(replacer.Replace(part)), " ")
+		value = strings.Trim(value, " .-_")
+		if value == "" {
+			value = "Unknown"
+		}
+		rendered = append(rendered, value)
+	}
+
+	return filepath.Join(rendered...)
+}
+
+// sanitizePathPart replaces characters that are not allowed in Windows file
+// names, which is the strictest file system the library may live on.
+func sanitizePathPart(value string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if r < 32 || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, value)
+
+	return strings.TrimRight(strings.TrimSpace(cleaned), ". ")
+}
+
+// moveImportFile moves source to destination, numbering the name when the
+// destination is taken, and copies when a rename across drives fails.
+func moveImportFile(source string, destination string) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+		return "", fmt.Errorf("create import destination: %w", err)
+	}
+
+	destination = uniqueImportPath(destination, nil)
+	if err := renameImportFile(source, destination); err == nil {
+		return destination, nil
+	}
+
+	if err := copyImportFile(source, destination); err != nil {
+		return "", err
+	}
+	if err := os.Remove(source); err != nil {
+		return destination, fmt.Errorf("remove imported file: %w", err)
+	}
+
+	return destination, nil
+}
+
+// renameImportFile is os.Rename, replaced in tests to exercise the copy
+// used across drives.
+var renameImportFile = os.Rename
+
+// uniqueImportPath numbers path until it names neither a file on disk nor
+// one of reserved.
+func uniqueImportPath(path string, reserved map[string]struct{}) string {
+	free := func(candidate string) bool {
+		if _, taken := reserved[candidate]; taken {
+			return false
+		}
+		_, err := os.Stat(candidate)
+		return errors.Is(err, os.ErrNotExist)
+	}
+	if free(path) {
+		return path
+	}
+
+	extension := filepath.Ext(path)
+	base := strings.TrimSuffix(path, extension)
+	for index := 2; ; index++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, index, extension)
+		if free(candidate) {
+			return candidate
+		}
+	}
+}
+
+func copyImportFile(source string, destination string) error {
+	input, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("open import file: %w", err)
+	}
+	defer input.Close()
+
+	output, err := os.CreateTemp(filepath.Dir(destination), ".import-*")
+	if err != nil {
+		return fmt.Errorf("create import copy: %w", err)
+	}
+	tempPath := output.Name()
+
+	if _, err := io.Copy(output, input); err != nil {
+		output.Close()
+		os.Remove(tempPath)
+		return fmt.Errorf("copy import file: %w", err)
+	}
+	if err := output.Close(); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("copy import file: %w", err)
+	}
+	if err := os.Rename(tempPath, destination); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("move import copy: %w", err)
+	}
+
+	return nil
+}
+
+// moveLyricsSidecar keeps an .lrc file next to its track under the track's
+// new name.
+func moveLyricsSidecar(source string, destination string) {
+	sidecar := strings.TrimSuffix(source, filepath.Ext(source)) + ".lrc"
+	if _, err := os.Stat(sidecar); err != nil {
+		return
+	}
+
+	_, _ = moveImportFile(sidecar, strings.TrimSuffix(destination, filepath.Ext(destination))+".lrc")
+}
+
+// removeEmptyImportDirs removes folders emptied by an import, deepest
+// first, keeping the import folder itself.
+func removeEmptyImportDirs(folder string) {
+	dirs := make([]string, 0)
+	_ = filepath.WalkDir(folder, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr == nil && entry.IsDir() && path != folder {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+
+	for index := len(dirs) - 1; index >= 0; index-- {
+		// Remove fails on folders that still hold files, which is intended.
+		_ = os.Remove(dirs[index])
+	}
+}
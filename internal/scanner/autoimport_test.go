@@ -0,0 +1,212 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRenderImportPatternFillsMissingTagsAndSanitizesNames(t *testing.T) {
+	t.Parallel()
+
+	trackNo := 3
+	year := 1999
+	cases := []struct {
+		name     string
+		pattern  string
+		metadata extractedMetadata
+		want     string
+	}{
+		{
+			name:     "all tags",
+			pattern:  DefaultAutoImportPattern,
+			metadata: extractedMetadata{title: "Song", artist: "Band", albumArtist: "Various", album: "Hits", trackNo: &trackNo},
+			want:     filepath.Join("Various", "Hits", "03 Song"),
+		},
+		{
+			name:     "album artist falls back to artist",
+			pattern:  DefaultAutoImportPattern,
+			metadata: extractedMetadata{title: "Song", artist: "Band", album: "Hits", trackNo: &trackNo},
+			want:     filepath.Join("Band", "Hits", "03 Song"),
+		},
+		{
+			name:     "missing tags",
+			pattern:  "{albumartist}/{year} {album}/{track} {title}",
+			metadata: extractedMetadata{title: "Song"},
+			want:     filepath.Join("Unknown", "Unknown", "Song"),
+		},
+		{
+			name:     "path separators in tags",
+			pattern:  DefaultAutoImportPattern,
+			metadata: extractedMetadata{title: `Either/Or\Both`, artist: "AC/DC", album: "Live: At the Hall?", trackNo: &trackNo},
+			want:     filepath.Join("AC_DC", "Live_ At the Hall", "03 Either_Or_Both"),
+		},
+		{
+			name:     "year",
+			pattern:  "{artist}/{year} - {album}/{title}",
+			metadata: extractedMetadata{title: "Song", artist: "Band", album: "Hits", year: &year},
+			want:     filepath.Join("Band", "1999 - Hits", "Song"),
+		},
+	}
+	for _, tc := range cases {
+		if got := renderImportPattern(tc.pattern, tc.metadata); got != tc.want {
+			t.Fatalf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestUniqueImportPathNumbersTakenNames(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "01 Song.flac")
+	if got := uniqueImportPath(path, nil); got != path {
+		t.Fatalf("expected a free path to be kept, got %q", got)
+	}
+
+	writeImportTestFile(t, path, "one")
+	writeImportTestFile(t, filepath.Join(dir, "01 Song (2).flac"), "two")
+	if got, want := uniqueImportPath(path, nil), filepath.Join(dir, "01 Song (3).flac"); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	reserved := map[string]struct{}{filepath.Join(dir, "01 Song (3).flac"): {}}
+	if got, want := uniqueImportPath(path, reserved), filepath.Join(dir, "01 Song (4).flac"); got != want {
+		t.Fatalf("expected reserved paths to be skipped, got %q, want %q", got, want)
+	}
+}
+
+// Not parallel: it replaces renameImportFile.
+func TestMoveImportFileCopiesWhenRenameFails(t *testing.T) {
+	rename := renameImportFile
+	renameImportFile = func(string, string) error { return errors.New("invalid cross-device link") }
+	t.Cleanup(func() { renameImportFile = rename })
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "import", "song.flac")
+	writeImportTestFile(t, source, "audio")
+
+	destination := filepath.Join(dir, "library", "Band", "song.flac")
+	finalPath, err := moveImportFile(source, destination)
+	if err != nil {
+		t.Fatalf("move import file: %v", err)
+	}
+	if finalPath != destination {
+		t.Fatalf("expected %q, got %q", destination, finalPath)
+	}
+	if content, err := os.ReadFile(destination); err != nil || string(content) != "audio" {
+		t.Fatalf("expected the copy at the destination, got %q err=%v", content, err)
+	}
+	if _, err := os.Stat(source); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected the source removed after the copy, got %v", err)
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(destination), ".import-*"))
+	if len(leftovers) != 0 {
+		t.Fatalf("expected no temporary copies, got %v", leftovers)
+	}
+}
+
+func TestImportPendingMovesSettledFilesAndRestoresThem(t *testing.T) {
+	t.Parallel()
+
+	service, _ := newScannerForTest(t)
+	// Keeps NotifyPathsChanged from starting a scan of the temporary folders.
+	service.shuttingDown = true
+
+	libraryDir := t.TempDir()
+	importDir := t.TempDir()
+	root := addRootForTest(t, service, libraryDir)
+	importer := NewAutoImporter(service, nil)
+	importer.config = AutoImportConfig{Folder: importDir, RootID: root.ID}
+
+	now := time.Now()
+	settled := now.Add(-time.Minute)
+	song := filepath.Join(importDir, "Album", "01 Song.flac")
+	cover := filepath.Join(importDir, "Album", "cover.jpg")
+	fresh := filepath.Join(importDir, "Album", "02 Song.flac")
+	partial := filepath.Join(importDir, "Album", "03 Song.flac.part")
+	for _, path := range []string{song, cover, fresh, partial} {
+		writeImportTestFile(t, path, filepath.Base(path))
+	}
+	for _, path := range []string{song, cover, partial} {
+		if err := os.Chtimes(path, settled, settled); err != nil {
+			t.Fatalf("set file time: %v", err)
+		}
+	}
+	// A file of the same name already in the library is not overwritten.
+	writeImportTestFile(t, filepath.Join(libraryDir, "Album", "01 Song.flac"), "existing")
+
+	var snapshots [][]ImportedFile
+	importer.SetSnapshotter(func(_ context.Context, files []ImportedFile) error {
+		for _, file := range files {
+			if _, err := os.Stat(file.From); err != nil {
+				t.Errorf("expected the snapshot before %s moved: %v", file.From, err)
+			}
+		}
+		snapshots = append(snapshots, files)
+		return nil
+	})
+
+	result, settling, err := importer.importPending(now)
+	if err != nil {
+		t.Fatalf("import pending: %v", err)
+	}
+	if !settling {
+		t.Fatalf("expected the fresh and partial files to keep the import settling")
+	}
+	want := []ImportedFile{
+		{From: song, To: filepath.Join(libraryDir, "Album", "01 Song (2).flac")},
+		{From: cover, To: filepath.Join(libraryDir, "Album", "cover.jpg")},
+	}
+	if len(result.Imported) != len(want) || len(result.Failed) != 0 {
+		t.Fatalf("expected %+v imported, got %+v", want, result)
+	}
+	for index, file := range want {
+		if result.Imported[index] != file {
+			t.Fatalf("import %d: got %+v, want %+v", index, result.Imported[index], file)
+		}
+	}
+	if len(snapshots) != 1 || len(snapshots[0]) != len(want) {
+		t.Fatalf("expected one snapshot of the planned moves, got %+v", snapshots)
+	}
+	for _, path := range []string{fresh, partial} {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("expected %s left in the import folder: %v", path, err)
+		}
+	}
+
+	if err := importer.RestoreImports(result.Imported); err != nil {
+		t.Fatalf("restore imports: %v", err)
+	}
+	for _, file := range want {
+		if _, err := os.Stat(file.From); err != nil {
+			t.Fatalf("expected %s restored: %v", file.From, err)
+		}
+		if _, err := os.Stat(file.To); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("expected %s gone from the library, got %v", file.To, err)
+		}
+	}
+
+	// Restored files stay in the import folder until they change.
+	result, _, err = importer.importPending(now)
+	if err != nil {
+		t.Fatalf("import pending after restore: %v", err)
+	}
+	if len(result.Imported) != 0 {
+		t.Fatalf("expected restored files left alone, got %+v", result.Imported)
+	}
+}
+
+func writeImportTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("create folder: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+package scanner
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// maxRecordedScans bounds how many scans keep their change list; older
+// scans are forgotten first.
+const maxRecordedScans = 50
+
+const maxChangedAlbums = 200
+
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeUpdated = "updated"
+	ChangeCover   = "cover"
+)
+
+// LibraryChanges lists the albums that scans after a given scan changed.
+// LatestScanID is the newest scan with changes; pass it to the next call to
+// only see what changed since.
+type LibraryChanges struct {
+	LatestScanID int64         `json:"latestScanId"`
+	Albums       []AlbumChange `json:"albums"`
+}
+
+// AlbumChange sums up what scans changed in one album. A track that
+// changed in several scans is listed once per scan.
+type AlbumChange struct {
+	Album        string         `json:"album"`
+	AlbumArtist  string         `json:"albumArtist"`
+	LastScanID   int64          `json:"lastScanId"`
+	ChangedAt    string         `json:"changedAt"`
+	Added        []ChangedTrack `json:"added"`
+	Removed      []ChangedTrack `json:"removed"`
+	Updated      []ChangedTrack `json:"updated"`
+	CoverChanged bool           `json:"coverChanged"`
+}
+
+type ChangedTrack struct {
+	TrackID int64  `json:"trackId"`
+	Title   string `json:"title"`
+	Artist  string `json:"artist"`
+}
+
+const libraryStateSelectSQL = `
+	SELECT
+		t.id,
+		COALESCE(NULLIF(TRIM(t.title), ''), 'Unknown Title'),
+		COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist'),
+		COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album'),
+		COALESCE(NULLIF(TRIM(t.album_artist), ''), COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')),
+		t.updated_at,
+		(SELECT c.hash FROM covers c WHERE c.source_file_id = t.file_id ORDER BY c.id DESC LIMIT 1)
+	FROM tracks t
+	JOIN files f ON f.id = t.file_id
+	WHERE f.file_exists = 1 AND f.is_audiobook = 0
+`
+
+// captureLibraryState remembers the visible tracks before a scan changes
+// them, in a temporary table of the scan transaction.
+func captureLibraryState(ctx context.Context, tx *sql.Tx) error {
+	return fillLibraryStateTable(ctx, tx, "scan_library_before")
+}
+
+// recordLibraryChanges compares the library with the state captured before
+// the scan and stores the differences under a new scan id. Scans that
+// changed nothing are not recorded. Repair scans re-read every file, so
+// their metadata updates are not reported. It returns the new scan id, or
+// 0 when nothing was recorded.
+func recordLibraryChanges(ctx context.Context, tx *sql.Tx, mode scanMode) (int64, error) {
+	if err := fillLibraryStateTable(ctx, tx, "scan_library_after"); err != nil {
+		return 0, err
+	}
+
+	result, err := tx.ExecContext(ctx, "INSERT INTO scan_runs(mode) VALUES (?)", string(mode))
+	if err != nil {
+		return 0, fmt.Errorf("insert scan run: %w", err)
+	}
+	scanID, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("read scan run id: %w", err)
+	}
+
+	reportUpdates := mode != scanModeRepair
+	statements := []struct {
+		change string
+		query  string
+	}{
+		{ChangeAdded, `
+			SELECT a.track_id, a.title, a.artist, a.album, a.album_artist
+			FROM scan_library_after a
+			LEFT JOIN scan_library_before b ON b.track_id = a.track_id
+			WHERE b.track_id IS NULL`},
+		{ChangeRemoved, `
+			SELECT b.track_id, b.title, b.artist, b.album, b.album_artist
+			FROM scan_library_before b
+			LEFT JOIN scan_library_after a ON a.track_id = b.track_id
+			WHERE a.track_id IS NULL`},
+		{ChangeUpdated, `
+			SELECT a.track_id, a.title, a.artist, a.album, a.album_artist
+			FROM scan_library_after a
+			JOIN scan_library_before b ON b.track_id = a.track_id
+			WHERE b.updated_at IS NOT a.updated_at AND ?`},
+		{ChangeCover, `
+			SELECT a.track_id, a.title, a.artist, a.album, a.album_artist
+			FROM scan_library_after a
+			JOIN scan_library_before b ON b.track_id = a.track_id
+			WHERE b.cover_hash IS NOT a.cover_hash`},
+	}
+
+	recorded := int64(0)
+	for _, statement := range statements {
+		args := []any{scanID, statement.change}
+		if statement.change == ChangeUpdated {
+			args = append(args, reportUpdates)
+		}
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO scan_track_changes(scan_id, change, track_id, title, artist, album, album_artist)
+			SELECT ?, ?, changed.* FROM (`+statement.query+`) changed
+		`, args...)
+		if err != nil {
+			return 0, fmt.Errorf("record %s tracks: %w", statement.change, err)
+		}
+		count, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("read %s track count: %w", statement.change, err)
+		}
+		recorded += count
+	}
+
+	if recorded == 0 {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM scan_runs WHERE id = ?", scanID); err != nil {
+			return 0, fmt.Errorf("delete empty scan run: %w", err)
+		}
+		return 0, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM scan_runs
+		WHERE id NOT IN (SELECT id FROM scan_runs ORDER BY id DESC LIMIT ?)
+	`, maxRecordedScans); err != nil {
+		return 0, fmt.Errorf("prune scan runs: %w", err)
+	}
+
+	return scanID, nil
+}
+
+func fillLibraryStateTable(ctx context.Context, tx *sql.Tx, table string) error {
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TEMP TABLE IF NOT EXISTS `+table+`(
+			track_id INTEGER PRIMARY KEY,
+			title TEXT NOT NULL,
+			artist TEXT NOT NULL,
+			album TEXT NOT NULL,
+			album_artist TEXT NOT NULL,
+			updated_at TEXT,
+			cover_hash TEXT
+		)
+	`); err != nil {
+		return fmt.Errorf("create %s: %w", table, err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM "+table); err != nil {
+		return fmt.Errorf("clear %s: %w", table, err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO "+table+libraryStateSelectSQL); err != nil {
+		return fmt.Errorf("fill %s: %w", table, err)
+	}
+
+	return nil
+}
+
+// GetLibraryChanges lists the albums changed by scans after sinceScanID,
+// newest first. Pass 0 to get every recorded scan.
+func (s *Service) GetLibraryChanges(ctx context.Context, sinceScanID int64) (LibraryChanges, error) {
+	changes := LibraryChanges{Albums: []AlbumChange{}}
+	if err := s.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(id), 0) FROM scan_runs").Scan(&changes.LatestScanID); err != nil {
+		return LibraryChanges{}, fmt.Errorf("read latest scan run: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT c.scan_id, r.finished_at, c.change, c.track_id, c.title, c.artist, c.album, c.album_artist
+		FROM scan_track_changes c
+		JOIN scan_runs r ON r.id = c.scan_id
+		WHERE c.scan_id > ?
+		ORDER BY c.scan_id DESC, c.album_artist COLLATE NOCASE ASC, c.album COLLATE NOCASE ASC, c.id ASC
+	`, sinceScanID)
+	if err != nil {
+		return LibraryChanges{}, fmt.Errorf("list library changes: %w", err)
+	}
+	defer rows.Close()
+
+	type albumKey struct {
+		album       string
+		albumArtist string
+	}
+	indexes := make(map[albumKey]int)
+	for rows.Next() {
+		var (
+			scanID    int64
+			changedAt string
+			change    string
+			key       albumKey
+			track     ChangedTrack
+		)
+		if err := rows.Scan(&scanID, &changedAt, &change, &track.TrackID, &track.Title, &track.Artist, &key.album, &key.albumArtist); err != nil {
+			return LibraryChanges{}, fmt.Errorf("scan library change row: %w", err)
+		}
+
+		index, ok := indexes[key]
+		if !ok {
+			if len(changes.Albums) >= maxChangedAlbums {
+				continue
+			}
+			index = len(changes.Albums)
+			indexes[key] = index
+			changes.Albums = append(changes.Albums, AlbumChange{
+				Album:       key.album,
+				AlbumArtist: key.albumArtist,
+				LastScanID:  scanID,
+				ChangedAt:   changedAt,
+				Added:       []ChangedTrack{},
+				Removed:     []ChangedTrack{},
+				Updated:     []ChangedTrack{},
+			})
+		}
+
+		album := &changes.Albums[index]
+		switch change {
+		case ChangeAdded:
+			album.Added = append(album.Added, track)
+		case ChangeRemoved:
+			album.Removed = append(album.Removed, track)
+		case ChangeUpdated:
+			album.Updated = append(album.Updated, track)
+		case ChangeCover:
+			album.CoverChanged = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return LibraryChanges{}, fmt.Errorf("iterate library changes: %w", err)
+	}
+
+	return changes, nil
+}
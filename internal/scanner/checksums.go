@@ -0,0 +1,306 @@
+package scanner
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// EventChecksumMismatch lists the files a verification run found changed on
+// disk although their size and modification time did not.
+const EventChecksumMismatch = "scanner:checksum-mismatch"
+
+const (
+	ChecksumStatusOK       = "ok"
+	ChecksumStatusMismatch = "mismatch"
+)
+
+var ErrVerificationRunning = errors.New("file verification already in progress")
+
+// ChecksumScope limits a verification run to one root, to the files under
+// Path, or both. The zero scope covers every present file.
+type ChecksumScope struct {
+	RootID int64  `json:"rootId"`
+	Path   string `json:"path"`
+}
+
+// ChecksumReport counts the outcome of a verification run. Stored files got
+// their first checksum, or a new one because they were rewritten since.
+type ChecksumReport struct {
+	Checked    int                 `json:"checked"`
+	Stored     int                 `json:"stored"`
+	Verified   int                 `json:"verified"`
+	Unreadable int                 `json:"unreadable"`
+	Mismatches []CorruptedChecksum `json:"mismatches"`
+}
+
+// CorruptedChecksum is a file whose content no longer matches its stored
+// checksum.
+type CorruptedChecksum struct {
+	FileID         int64  `json:"fileId"`
+	TrackID        int64  `json:"trackId,omitempty"`
+	Path           string `json:"path"`
+	Title          string `json:"title"`
+	Artist         string `json:"artist"`
+	Checksum       string `json:"checksum"`
+	ActualChecksum string `json:"actualChecksum"`
+	ComputedAt     string `json:"computedAt"`
+	VerifiedAt     string `json:"verifiedAt"`
+}
+
+// ChecksumMismatches is the payload of EventChecksumMismatch.
+type ChecksumMismatches struct {
+	Files []CorruptedChecksum `json:"files"`
+}
+
+type checksumFile struct {
+	id       int64
+	path     string
+	stored   sql.NullString
+	size     sql.NullInt64
+	mtimeNS  sql.NullInt64
+	computed sql.NullString
+}
+
+// VerifyFiles hashes every present file in scope. Files without a checksum,
+// or rewritten since it was stored, get a new one; the others are compared
+// with it, and mismatches are stored, returned and announced with
+// EventChecksumMismatch. It reads whole files, so it runs apart from scans
+// and can be canceled through ctx.
+func (s *Service) VerifyFiles(ctx context.Context, scope ChecksumScope) (ChecksumReport, error) {
+	s.mu.Lock()
+	if s.verifying {
+		s.mu.Unlock()
+		return ChecksumReport{}, ErrVerificationRunning
+	}
+	s.verifying = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.verifying = false
+		s.mu.Unlock()
+	}()
+
+	files, err := s.listChecksumFiles(ctx, scope)
+	if err != nil {
+		return ChecksumReport{}, err
+	}
+
+	report := ChecksumReport{Mismatches: []CorruptedChecksum{}}
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		info, statErr := os.Stat(file.path)
+		if statErr != nil || !info.Mode().IsRegular() {
+			report.Unreadable++
+			continue
+		}
+		checksum, hashErr := hashFile(file.path)
+		if hashErr != nil {
+			report.Unreadable++
+			continue
+		}
+		report.Checked++
+
+		now := time.Now().UTC().Format(time.RFC3339Nano)
+		size := info.Size()
+		mtimeNS := info.ModTime().UnixNano()
+		if !file.stored.Valid || file.size.Int64 != size || file.mtimeNS.Int64 != mtimeNS {
+			if _, err := s.db.ExecContext(ctx, `
+				INSERT INTO file_checksums(file_id, checksum, size, mtime_ns, status, actual_checksum, computed_at, verified_at)
+				VALUES (?, ?, ?, ?, ?, NULL, ?, ?)
+				ON CONFLICT(file_id) DO UPDATE SET
+					checksum = excluded.checksum,
+					size = excluded.size,
+					mtime_ns = excluded.mtime_ns,
+					status = excluded.status,
+					actual_checksum = NULL,
+					computed_at = excluded.computed_at,
+					verified_at = excluded.verified_at
+			`, file.id, checksum, size, mtimeNS, ChecksumStatusOK, now, now); err != nil {
+				return report, fmt.Errorf("store checksum for %s: %w", file.path, err)
+			}
+			report.Stored++
+			continue
+		}
+
+		if checksum == file.stored.String {
+			if _, err := s.db.ExecContext(
+				ctx,
+				"UPDATE file_checksums SET status = ?, actual_checksum = NULL, verified_at = ? WHERE file_id = ?",
+				ChecksumStatusOK,
+				now,
+				file.id,
+			); err != nil {
+				return report, fmt.Errorf("record verified checksum for %s: %w", file.path, err)
+			}
+			report.Verified++
+			continue
+		}
+
+		if _, err := s.db.ExecContext(
+			ctx,
+			"UPDATE file_checksums SET status = ?, actual_checksum = ?, verified_at = ? WHERE file_id = ?",
+			ChecksumStatusMismatch,
+			checksum,
+			now,
+			file.id,
+		); err != nil {
+			return report, fmt.Errorf("record checksum mismatch for %s: %w", file.path, err)
+		}
+		report.Mismatches = append(report.Mismatches, CorruptedChecksum{
+			FileID:         file.id,
+			Path:           file.path,
+			Checksum:       file.stored.String,
+			ActualChecksum: checksum,
+			ComputedAt:     file.computed.String,
+			VerifiedAt:     now,
+		})
+	}
+
+	if len(report.Mismatches) > 0 {
+		// The listing adds the track details the loop did not need.
+		if corrupted, err := s.ListCorruptedChecksums(ctx); err == nil {
+			report.Mismatches = filterCorruptedChecksums(corrupted, report.Mismatches)
+		}
+
+		s.mu.Lock()
+		emitter := s.emit
+		s.mu.Unlock()
+		if emitter != nil {
+			emitter(EventChecksumMismatch, ChecksumMismatches{Files: report.Mismatches})
+		}
+	}
+
+	return report, nil
+}
+
+// ListCorruptedChecksums returns the files whose last verification found a
+// checksum mismatch, until a later run finds them intact or rewritten.
+func (s *Service) ListCorruptedChecksums(ctx context.Context) ([]CorruptedChecksum, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			f.id,
+			COALESCE(t.id, 0),
+			f.path,
+			COALESCE(TRIM(t.title), ''),
+			COALESCE(TRIM(t.artist), ''),
+			c.checksum,
+			COALESCE(c.actual_checksum, ''),
+			c.computed_at,
+			COALESCE(c.verified_at, '')
+		FROM file_checksums c
+		JOIN files f ON f.id = c.file_id
+		LEFT JOIN tracks t ON t.file_id = f.id
+		WHERE c.status = ?
+		ORDER BY f.path
+	`, ChecksumStatusMismatch)
+	if err != nil {
+		return nil, fmt.Errorf("list corrupted checksums: %w", err)
+	}
+	defer rows.Close()
+
+	corrupted := make([]CorruptedChecksum, 0)
+	for rows.Next() {
+		var file CorruptedChecksum
+		if scanErr := rows.Scan(
+			&file.FileID,
+			&file.TrackID,
+			&file.Path,
+			&file.Title,
+			&file.Artist,
+			&file.Checksum,
+			&file.ActualChecksum,
+			&file.ComputedAt,
+			&file.VerifiedAt,
+		); scanErr != nil {
+			return nil, fmt.Errorf("scan corrupted checksum: %w", scanErr)
+		}
+		corrupted = append(corrupted, file)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate corrupted checksums: %w", rowsErr)
+	}
+
+	return corrupted, nil
+}
+
+func (s *Service) listChecksumFiles(ctx context.Context, scope ChecksumScope) ([]checksumFile, error) {
+	query := `
+		SELECT f.id, f.path, c.checksum, c.size, c.mtime_ns, c.computed_at
+		FROM files f
+		LEFT JOIN file_checksums c ON c.file_id = f.id
+		WHERE f.file_exists = 1`
+	args := make([]any, 0, 3)
+	if scope.RootID > 0 {
+		query += " AND f.root_id = ?"
+		args = append(args, scope.RootID)
+	}
+	if path := strings.TrimSpace(scope.Path); path != "" {
+		cleanPath := filepath.Clean(path)
+		query += ` AND (f.path = ? OR f.path LIKE ? ESCAPE '\')`
+		args = append(args, cleanPath, likePrefixPattern(cleanPath))
+	}
+	query += " ORDER BY f.id"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list files to verify: %w", err)
+	}
+	defer rows.Close()
+
+	files := make([]checksumFile, 0)
+	for rows.Next() {
+		var file checksumFile
+		if scanErr := rows.Scan(&file.id, &file.path, &file.stored, &file.size, &file.mtimeNS, &file.computed); scanErr != nil {
+			return nil, fmt.Errorf("scan file to verify: %w", scanErr)
+		}
+		files = append(files, file)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate files to verify: %w", rowsErr)
+	}
+
+	return files, nil
+}
+
+// filterCorruptedChecksums keeps the entries of corrupted found by this run,
+// in the listing's order.
+func filterCorruptedChecksums(corrupted []CorruptedChecksum, found []CorruptedChecksum) []CorruptedChecksum {
+	foundIDs := make(map[int64]struct{}, len(found))
+	for _, file := range found {
+		foundIDs[file.FileID] = struct{}{}
+	}
+
+	filtered := make([]CorruptedChecksum, 0, len(found))
+	for _, file := range corrupted {
+		if _, ok := foundIDs[file.FileID]; ok {
+			filtered = append(filtered, file)
+		}
+	}
+	return filtered
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
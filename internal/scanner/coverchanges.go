@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"context"
+	"database/sql"
+)
+
+// EventCoversChanged lists albums whose artwork an incremental scan
+// replaced, so open views can swap covers without a full refresh.
+const EventCoversChanged = "covers:changed"
+
+// CoversChanged is the payload of EventCoversChanged.
+type CoversChanged struct {
+	ScanID int64              `json:"scanId"`
+	Albums []AlbumCoverChange `json:"albums"`
+}
+
+// AlbumCoverChange is an album and the cache path of its new cover, empty
+// when the album no longer has one.
+type AlbumCoverChange struct {
+	Album       string `json:"album"`
+	AlbumArtist string `json:"albumArtist"`
+	CoverPath   string `json:"coverPath"`
+}
+
+// publishCoverChanges emits EventCoversChanged for the albums the scan
+// recorded cover changes in. It runs after the scan committed, so the
+// paths are those of the rebuilt albums.
+func (s *Service) publishCoverChanges(ctx context.Context, scanID int64) {
+	s.mu.Lock()
+	emitter := s.emit
+	s.mu.Unlock()
+	if emitter == nil {
+		return
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT changed.album, changed.album_artist, cover.cache_path
+		FROM scan_track_changes changed
+		LEFT JOIN albums a ON a.title = changed.album AND a.album_artist = changed.album_artist
+		LEFT JOIN covers cover ON cover.id = a.cover_id
+		WHERE changed.scan_id = ? AND changed.change = ?
+		ORDER BY changed.album_artist COLLATE NOCASE, changed.album COLLATE NOCASE
+	`, scanID, ChangeCover)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	payload := CoversChanged{ScanID: scanID, Albums: []AlbumCoverChange{}}
+	for rows.Next() {
+		var change AlbumCoverChange
+		var coverPath sql.NullString
+		if err := rows.Scan(&change.Album, &change.AlbumArtist, &coverPath); err != nil {
+			return
+		}
+		change.CoverPath = coverPath.String
+		payload.Albums = append(payload.Albums, change)
+	}
+	if rows.Err() != nil || len(payload.Albums) == 0 {
+		return
+	}
+
+	emitter(EventCoversChanged, payload)
+}
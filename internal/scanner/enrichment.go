@@ -0,0 +1,69 @@
+package scanner
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// applyTrackEnrichments fills values the user enriched tracks with back into
+// tracks whose tags leave them empty, e.g. after a rescan rewrote the track
+// from its file. Tagged values always win.
+func applyTrackEnrichments(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE tracks
+		SET
+			year = CASE
+				WHEN (tracks.year IS NULL OR tracks.year <= 0) AND e.year > 0 THEN e.year
+				ELSE tracks.year
+			END,
+			album_artist = CASE
+				WHEN NULLIF(TRIM(tracks.album_artist), '') IS NULL AND e.album_artist <> '' THEN e.album_artist
+				ELSE tracks.album_artist
+			END,
+			genre = CASE
+				WHEN NULLIF(TRIM(tracks.genre), '') IS NULL AND e.genre <> '' THEN e.genre
+				ELSE tracks.genre
+			END
+		FROM track_enrichments e
+		WHERE e.track_id = tracks.id
+		  AND (
+			((tracks.year IS NULL OR tracks.year <= 0) AND e.year > 0)
+			OR (NULLIF(TRIM(tracks.album_artist), '') IS NULL AND e.album_artist <> '')
+			OR (NULLIF(TRIM(tracks.genre), '') IS NULL AND e.genre <> '')
+		  )
+	`); err != nil {
+		return fmt.Errorf("apply track enrichments: %w", err)
+	}
+
+	return nil
+}
+
+// RefreshDerivedLibrary rebuilds albums and artists from the tracks, for
+// changes made outside a scan such as enriched metadata.
+func (s *Service) RefreshDerivedLibrary(ctx context.Context) error {
+	s.mu.Lock()
+	running := s.running
+	s.mu.Unlock()
+	if running {
+		return errors.New("scan already in progress")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin derived library tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := rebuildDerivedLibrary(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit derived library tx: %w", err)
+	}
+
+	return nil
+}
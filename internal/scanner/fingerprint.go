@@ -0,0 +1,267 @@
+package scanner
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/rzxx/ben/internal/metadata"
+)
+
+// FingerprintSettingKey enables the Chromaprint fingerprint phase of scans.
+// It needs fpcalc on the PATH and is off by default, because fingerprinting
+// decodes every file once.
+const FingerprintSettingKey = "library.fingerprint_files"
+
+// maxFingerprintsPerScan bounds the phase, so the first scans of a large
+// library fingerprint it over several runs instead of one long scan.
+const maxFingerprintsPerScan = 500
+
+// trackIdentityExpr derives tracks.identity_key from a track's tags. The
+// backfill in migration 046 uses the same expression.
+const trackIdentityExpr = `CASE
+	WHEN TRIM(COALESCE(title, '')) = '' OR TRIM(COALESCE(artist, '')) = '' THEN NULL
+	ELSE LOWER(TRIM(artist)) || char(31) || LOWER(TRIM(COALESCE(album, ''))) || char(31)
+		|| COALESCE(disc_no, 0) || char(31) || COALESCE(track_no, 0) || char(31) || LOWER(TRIM(title))
+END`
+
+// identityDurationToleranceMS is how far the lengths of two files may differ
+// for a tag identity match, which covers a re-encode but not another edit.
+const identityDurationToleranceMS = 2000
+
+// How a moved file was recognized, strongest first.
+const (
+	movedByFingerprint = "fingerprint"
+	movedByQuickHash   = "quickHash"
+	movedByIdentity    = "identity"
+)
+
+type movedFile struct {
+	missingFileID int64
+	trackID       int64
+	fileID        int64
+	path          string
+	rootPath      string
+	matchedBy     string
+}
+
+func (s *Service) FingerprintingEnabled(ctx context.Context) bool {
+	enabled, err := strconv.ParseBool(s.settings.GetString(ctx, FingerprintSettingKey, "false"))
+	return err == nil && enabled
+}
+
+func (s *Service) SetFingerprintingEnabled(ctx context.Context, enabled bool) (bool, error) {
+	if err := s.settings.Set(ctx, FingerprintSettingKey, strconv.FormatBool(enabled)); err != nil {
+		return s.FingerprintingEnabled(ctx), err
+	}
+
+	return enabled, nil
+}
+
+// fingerprintFiles fingerprints up to limit present files that have no
+// fingerprint yet and returns how many it stored. It stops without an error
+// when fpcalc is not installed.
+func fingerprintFiles(ctx context.Context, tx *sql.Tx, limit int) (int, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, path
+		FROM files
+		WHERE file_exists = 1
+		  AND fingerprint IS NULL
+		ORDER BY id
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("list files to fingerprint: %w", err)
+	}
+
+	type pendingFile struct {
+		id   int64
+		path string
+	}
+	pending := make([]pendingFile, 0)
+	for rows.Next() {
+		var file pendingFile
+		if scanErr := rows.Scan(&file.id, &file.path); scanErr != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan file to fingerprint: %w", scanErr)
+		}
+		pending = append(pending, file)
+	}
+	rowsErr := rows.Err()
+	rows.Close()
+	if rowsErr != nil {
+		return 0, fmt.Errorf("iterate files to fingerprint: %w", rowsErr)
+	}
+
+	fingerprinted := 0
+	for _, file := range pending {
+		if err := ctx.Err(); err != nil {
+			return fingerprinted, err
+		}
+
+		fp, fpErr := metadata.ComputeFingerprint(ctx, file.path)
+		if errors.Is(fpErr, metadata.ErrFingerprintUnavailable) {
+			return fingerprinted, nil
+		}
+
+		var fingerprint string
+		var duration sql.NullFloat64
+		if fpErr == nil {
+			fingerprint = fp.Fingerprint
+			duration = sql.NullFloat64{Float64: fp.Duration, Valid: true}
+			fingerprinted++
+		}
+		if _, err := tx.ExecContext(
+			ctx,
+			"UPDATE files SET fingerprint = ?, fingerprint_duration = ? WHERE id = ?",
+			fingerprint,
+			duration,
+			file.id,
+		); err != nil {
+			return fingerprinted, fmt.Errorf("store fingerprint for %s: %w", file.path, err)
+		}
+	}
+
+	return fingerprinted, nil
+}
+
+// updateTrackIdentity recomputes the identity key of the track of fileID
+// from its stored tags.
+func updateTrackIdentity(ctx context.Context, tx *sql.Tx, fileID int64) error {
+	_, err := tx.ExecContext(ctx, "UPDATE tracks SET identity_key = "+trackIdentityExpr+" WHERE file_id = ?", fileID)
+	return err
+}
+
+// relinkMovedTracks finds missing files of a root that reappeared under
+// another path, moved or renamed while the watcher was not looking, by
+// their fingerprint, their quick hash or, failing both, the tag identity of
+// their tracks. The track of the missing file takes over the new file, so
+// its play history, ratings and playlist entries stay, and the track
+// created for the new file is dropped.
+func relinkMovedTracks(ctx context.Context, tx *sql.Tx, rootID int64) (bool, error) {
+	// One branch per kind of match, so each can use its index.
+	rows, err := tx.QueryContext(ctx, `
+		WITH missing AS (
+			SELECT f.id, f.fingerprint, f.hash_quick, f.last_seen_at, t.id AS track_id, t.identity_key, t.duration_ms
+			FROM files f
+			JOIN tracks t ON t.file_id = f.id
+			WHERE f.file_exists = 0
+			  AND f.root_id = ?
+		),
+		matches AS (
+			SELECT missing.id AS missing_id, missing.track_id, moved.id AS moved_id, missing.last_seen_at, ? AS matched_by
+			FROM missing
+			JOIN files moved ON moved.fingerprint = missing.fingerprint
+			WHERE COALESCE(missing.fingerprint, '') <> ''
+			UNION ALL
+			SELECT missing.id, missing.track_id, moved.id, missing.last_seen_at, ?
+			FROM missing
+			JOIN files moved ON moved.hash_quick = missing.hash_quick
+			WHERE COALESCE(missing.hash_quick, '') <> ''
+			UNION ALL
+			SELECT missing.id, missing.track_id, moved_track.file_id, missing.last_seen_at, ?
+			FROM missing
+			JOIN tracks moved_track ON moved_track.identity_key = missing.identity_key
+			WHERE missing.identity_key IS NOT NULL
+			  AND ABS(COALESCE(moved_track.duration_ms, 0) - COALESCE(missing.duration_ms, 0)) <= ?
+		)
+		SELECT matches.missing_id, matches.track_id, moved.id, moved.path, COALESCE(root.path, ''), matches.matched_by
+		FROM matches
+		JOIN files moved ON moved.id = matches.moved_id
+		JOIN tracks moved_track ON moved_track.file_id = moved.id
+		LEFT JOIN watched_roots root ON root.id = moved.root_id
+		WHERE moved.file_exists = 1
+		  AND moved.id <> matches.missing_id
+		  AND COALESCE(moved.first_seen_at, '') >= COALESCE(matches.last_seen_at, '')
+		ORDER BY matches.missing_id, moved.id
+	`, rootID, movedByFingerprint, movedByQuickHash, movedByIdentity, identityDurationToleranceMS)
+	if err != nil {
+		return false, fmt.Errorf("find moved files for root %d: %w", rootID, err)
+	}
+
+	candidates := make([]movedFile, 0)
+	for rows.Next() {
+		var candidate movedFile
+		if scanErr := rows.Scan(&candidate.missingFileID, &candidate.trackID, &candidate.fileID, &candidate.path, &candidate.rootPath, &candidate.matchedBy); scanErr != nil {
+			rows.Close()
+			return false, fmt.Errorf("scan moved file: %w", scanErr)
+		}
+		candidates = append(candidates, candidate)
+	}
+	rowsErr := rows.Err()
+	rows.Close()
+	if rowsErr != nil {
+		return false, fmt.Errorf("iterate moved files: %w", rowsErr)
+	}
+
+	relinked := false
+	usedMissing := make(map[int64]struct{})
+	usedMoved := make(map[int64]struct{})
+	for _, candidate := range orderMovedFiles(candidates) {
+		if _, used := usedMissing[candidate.missingFileID]; used {
+			continue
+		}
+		if _, used := usedMoved[candidate.fileID]; used {
+			continue
+		}
+		if candidate.rootPath == "" {
+			continue
+		}
+
+		// Metadata is derived before anything changes, so an error leaves
+		// both tracks as they were.
+		cleanPath := filepath.Clean(candidate.path)
+		fileMetadata, metaErr := deriveMetadata(candidate.rootPath, cleanPath)
+		if metaErr != nil {
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM tracks WHERE file_id = ?", candidate.fileID); err != nil {
+			return false, fmt.Errorf("drop track of moved file %s: %w", cleanPath, err)
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE tracks SET file_id = ? WHERE id = ?", candidate.fileID, candidate.trackID); err != nil {
+			return false, fmt.Errorf("relink track %d to %s: %w", candidate.trackID, cleanPath, err)
+		}
+		if err := upsertTrackMetadata(ctx, tx, candidate.fileID, cleanPath, fileMetadata); err != nil {
+			return false, err
+		}
+
+		usedMissing[candidate.missingFileID] = struct{}{}
+		usedMoved[candidate.fileID] = struct{}{}
+		relinked = true
+	}
+
+	return relinked, nil
+}
+
+// orderMovedFiles puts content matches before tag identity matches, and
+// drops identity matches that are ambiguous: a missing file with several
+// candidates, or a file claimed by several missing ones, is more likely a
+// second copy of a song than the moved file.
+func orderMovedFiles(candidates []movedFile) []movedFile {
+	identityPerMissing := make(map[int64]int)
+	identityPerMoved := make(map[int64]int)
+	for _, candidate := range candidates {
+		if candidate.matchedBy == movedByIdentity {
+			identityPerMissing[candidate.missingFileID]++
+			identityPerMoved[candidate.fileID]++
+		}
+	}
+
+	ordered := make([]movedFile, 0, len(candidates))
+	identityMatches := make([]movedFile, 0)
+	for _, candidate := range candidates {
+		if candidate.matchedBy != movedByIdentity {
+			ordered = append(ordered, candidate)
+			continue
+		}
+		if identityPerMissing[candidate.missingFileID] == 1 && identityPerMoved[candidate.fileID] == 1 {
+			identityMatches = append(identityMatches, candidate)
+		}
+	}
+
+	return append(ordered, identityMatches...)
+}
@@ -0,0 +1,39 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rzxx/ben/internal/metadata"
+)
+
+// SetGenreInference sets what votes genres for untagged tracks after each
+// scan that changed the library. Without it genres are only inferred on
+// demand.
+func (s *Service) SetGenreInference(inferrer *metadata.Service) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.genres = inferrer
+}
+
+// inferGenres refreshes the pending inferred genres once the scan has
+// committed. A failure is reported as a warning and does not fail the scan.
+func (s *Service) inferGenres(ctx context.Context) {
+	s.mu.Lock()
+	inferrer := s.genres
+	s.mu.Unlock()
+	if inferrer == nil {
+		return
+	}
+
+	if _, err := inferrer.InferGenres(ctx); err != nil {
+		s.emitProgress(Progress{
+			Phase:   "derive",
+			Message: fmt.Sprintf("genre inference warning: %v", err),
+			Percent: 98,
+			Status:  "running",
+			At:      time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+}
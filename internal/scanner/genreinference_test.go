@@ -0,0 +1,88 @@
+package scanner
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.senan.xyz/taglib"
+
+	"github.com/rzxx/ben/internal/metadata"
+)
+
+func TestScanInfersGenresOfUntaggedTracks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	service, database := newScannerForTest(t)
+	service.SetGenreInference(metadata.NewService(database))
+
+	rootPath := t.TempDir()
+	albumPath := filepath.Join(rootPath, "Band", "Album")
+	if err := os.MkdirAll(albumPath, 0o755); err != nil {
+		t.Fatalf("create album folder: %v", err)
+	}
+	for _, track := range []struct {
+		name  string
+		title string
+		genre string
+	}{
+		{name: "01.wav", title: "One", genre: "Jazz"},
+		{name: "02.wav", title: "Two", genre: "Jazz"},
+		{name: "03.wav", title: "Three"},
+	} {
+		tags := map[string][]string{
+			taglib.Title:       {track.title},
+			taglib.Artist:      {"Band"},
+			taglib.AlbumArtist: {"Band"},
+			taglib.Album:       {"Album"},
+		}
+		if track.genre != "" {
+			tags[taglib.Genre] = []string{track.genre}
+		}
+		writeTaggedWAVForTest(t, filepath.Join(albumPath, track.name), tags)
+	}
+	addRootForTest(t, service, rootPath)
+
+	if _, err := service.performScan(ctx, scanModeFull); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+
+	inferred, err := metadata.NewService(database).ListInferredGenres(ctx)
+	if err != nil {
+		t.Fatalf("list inferred genres: %v", err)
+	}
+	if len(inferred) != 1 || inferred[0].Title != "Three" || inferred[0].Genre != "Jazz" {
+		t.Fatalf("expected Jazz inferred for the untagged track, got %+v", inferred)
+	}
+}
+
+// writeTaggedWAVForTest writes a tenth of a second of 8 kHz mono silence
+// with tags.
+func writeTaggedWAVForTest(t *testing.T, path string, tags map[string][]string) {
+	t.Helper()
+
+	const samples = 800
+	data := []byte("RIFF")
+	data = binary.LittleEndian.AppendUint32(data, 36+samples*2)
+	data = append(data, "WAVEfmt "...)
+	data = binary.LittleEndian.AppendUint32(data, 16)
+	data = binary.LittleEndian.AppendUint16(data, 1) // PCM
+	data = binary.LittleEndian.AppendUint16(data, 1) // mono
+	data = binary.LittleEndian.AppendUint32(data, 8000)
+	data = binary.LittleEndian.AppendUint32(data, 8000*2)
+	data = binary.LittleEndian.AppendUint16(data, 2)
+	data = binary.LittleEndian.AppendUint16(data, 16)
+	data = append(data, "data"...)
+	data = binary.LittleEndian.AppendUint32(data, samples*2)
+	data = append(data, make([]byte, samples*2)...)
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write wav: %v", err)
+	}
+	if err := taglib.WriteTags(path, tags, 0); err != nil {
+		t.Fatalf("tag wav: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+// replaceTrackGenres stores the genres of the track of fileID, one row per
+// value of a multi-value genre tag.
+func replaceTrackGenres(ctx context.Context, tx *sql.Tx, fileID int64, genres []string) error {
+	var trackID int64
+	if err := tx.QueryRowContext(ctx, "SELECT id FROM tracks WHERE file_id = ?", fileID).Scan(&trackID); err != nil {
+		return fmt.Errorf("find track of file %d: %w", fileID, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM track_genres WHERE track_id = ?", trackID); err != nil {
+		return fmt.Errorf("clear genres of track %d: %w", trackID, err)
+	}
+	for position, genre := range genres {
+		if _, err := tx.ExecContext(
+			ctx,
+			"INSERT OR IGNORE INTO track_genres(track_id, genre, position) VALUES (?, ?, ?)",
+			trackID,
+			genre,
+			position,
+		); err != nil {
+			return fmt.Errorf("add genre %q to track %d: %w", genre, trackID, err)
+		}
+	}
+
+	return nil
+}
+
+// splitTrackGenres fills in the genres of tracks that have a genre but no
+// split genres: tracks indexed before genres were split, and tracks whose
+// genre was filled in by metadata enrichment.
+func splitTrackGenres(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT t.id, t.genre
+		FROM tracks t
+		WHERE NULLIF(TRIM(t.genre), '') IS NOT NULL
+		  AND NOT EXISTS (SELECT 1 FROM track_genres g WHERE g.track_id = t.id)
+	`)
+	if err != nil {
+		return fmt.Errorf("query tracks for genre split: %w", err)
+	}
+
+	pending := make(map[int64][]string)
+	for rows.Next() {
+		var trackID int64
+		var genre string
+		if scanErr := rows.Scan(&trackID, &genre); scanErr != nil {
+			rows.Close()
+			return fmt.Errorf("scan track for genre split: %w", scanErr)
+		}
+		pending[trackID] = library.SplitGenres(genre)
+	}
+	rowsErr := rows.Err()
+	rows.Close()
+	if rowsErr != nil {
+		return fmt.Errorf("iterate tracks for genre split: %w", rowsErr)
+	}
+
+	for trackID, genres := range pending {
+		for position, genre := range genres {
+			if _, err := tx.ExecContext(
+				ctx,
+				"INSERT OR IGNORE INTO track_genres(track_id, genre, position) VALUES (?, ?, ?)",
+				trackID,
+				genre,
+				position,
+			); err != nil {
+				return fmt.Errorf("add genre %q to track %d: %w", genre, trackID, err)
+			}
+		}
+	}
+
+	return nil
+}
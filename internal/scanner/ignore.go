@@ -0,0 +1,222 @@
+package scanner
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+// ignoreFileName holds gitignore-style patterns for the folder it is in and
+// everything below it.
+const ignoreFileName = ".benignore"
+
+type ignoreRule struct {
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// ignoreMatcher answers whether a path below a watched root is excluded by
+// the root's exclude rules or the .benignore files on the way down from the
+// root. Rules are read once per directory and results for directories are
+// cached, so one matcher should be used per walk.
+type ignoreMatcher struct {
+	root        string
+	rootRules   []ignoreRule
+	skipHidden  bool
+	minFileSize int64
+	rules       map[string][]ignoreRule
+	dirCache    map[string]bool
+}
+
+// newIgnoreMatcher returns a matcher for a root. Exclude patterns of the
+// root rules behave as the first lines of a .benignore file in the root.
+func newIgnoreMatcher(root string, rootRules library.RootRules) *ignoreMatcher {
+	matcher := &ignoreMatcher{
+		root:        filepath.Clean(root),
+		skipHidden:  rootRules.SkipHidden,
+		minFileSize: rootRules.MinFileSize,
+		rules:       make(map[string][]ignoreRule),
+		dirCache:    make(map[string]bool),
+	}
+	for _, pattern := range rootRules.Exclude {
+		if rule, ok := parseIgnoreRule(pattern); ok {
+			matcher.rootRules = append(matcher.rootRules, rule)
+		}
+	}
+
+	return matcher
+}
+
+func isIgnoreFile(filePath string) bool {
+	return filepath.Base(filePath) == ignoreFileName
+}
+
+// ignored reports whether path is excluded, either by a rule matching it or
+// because one of its parent directories is excluded.
+func (m *ignoreMatcher) ignored(filePath string, isDir bool) bool {
+	cleanPath := filepath.Clean(filePath)
+	if pathCompareKey(cleanPath) == pathCompareKey(m.root) || !isSameOrNestedPath(cleanPath, m.root) {
+		return false
+	}
+
+	if isDir {
+		if cached, ok := m.dirCache[cleanPath]; ok {
+			return cached
+		}
+	}
+
+	parent := filepath.Dir(cleanPath)
+	result := m.ignored(parent, true) ||
+		(m.skipHidden && strings.HasPrefix(filepath.Base(cleanPath), ".")) ||
+		m.matches(cleanPath, isDir)
+	if isDir {
+		m.dirCache[cleanPath] = result
+	}
+
+	return result
+}
+
+// matches applies the rules of every directory from the root down to the
+// path's parent. As in gitignore, the last matching rule wins, so deeper
+// files and later lines override earlier ones.
+func (m *ignoreMatcher) matches(cleanPath string, isDir bool) bool {
+	directories := make([]string, 0, 8)
+	for dir := filepath.Dir(cleanPath); ; dir = filepath.Dir(dir) {
+		directories = append(directories, dir)
+		if pathCompareKey(dir) == pathCompareKey(m.root) || !isSameOrNestedPath(dir, m.root) || dir == filepath.Dir(dir) {
+			break
+		}
+	}
+
+	ignored := false
+	for index := len(directories) - 1; index >= 0; index-- {
+		dir := directories[index]
+		relative, err := filepath.Rel(dir, cleanPath)
+		if err != nil {
+			continue
+		}
+		relative = filepath.ToSlash(relative)
+
+		for _, rule := range m.rulesFor(dir) {
+			if rule.dirOnly && !isDir {
+				continue
+			}
+			if rule.match(relative) {
+				ignored = !rule.negate
+			}
+		}
+	}
+
+	return ignored
+}
+
+// tooSmall reports whether a file is below the minimum size of the root
+// rules.
+func (m *ignoreMatcher) tooSmall(info fs.FileInfo) bool {
+	return m.minFileSize > 0 && info.Size() < m.minFileSize
+}
+
+func (m *ignoreMatcher) rulesFor(dir string) []ignoreRule {
+	if rules, ok := m.rules[dir]; ok {
+		return rules
+	}
+
+	rules := readIgnoreFile(filepath.Join(dir, ignoreFileName))
+	if pathCompareKey(dir) == pathCompareKey(m.root) && len(m.rootRules) > 0 {
+		rules = append(append(make([]ignoreRule, 0, len(m.rootRules)+len(rules)), m.rootRules...), rules...)
+	}
+	m.rules[dir] = rules
+	return rules
+}
+
+func readIgnoreFile(filePath string) []ignoreRule {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	rules := make([]ignoreRule, 0)
+	lines := bufio.NewScanner(file)
+	for lines.Scan() {
+		if rule, ok := parseIgnoreRule(lines.Text()); ok {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+func parseIgnoreRule(line string) (ignoreRule, bool) {
+	pattern := strings.TrimRight(strings.TrimSuffix(line, "\r"), " \t")
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return ignoreRule{}, false
+	}
+
+	rule := ignoreRule{}
+	if strings.HasPrefix(pattern, "!") {
+		rule.negate = true
+		pattern = pattern[1:]
+	} else if strings.HasPrefix(pattern, `\!`) || strings.HasPrefix(pattern, `\#`) {
+		pattern = pattern[1:]
+	}
+
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	if strings.Contains(pattern, "/") {
+		rule.anchored = true
+		pattern = strings.TrimPrefix(pattern, "/")
+	}
+	if pattern == "" {
+		return ignoreRule{}, false
+	}
+
+	rule.pattern = pattern
+	return rule, true
+}
+
+// match tests a slash-separated path relative to the rule's directory.
+// Patterns without a slash match the last path element at any depth.
+func (r ignoreRule) match(relative string) bool {
+	if !r.anchored {
+		return matchGlobSegments([]string{r.pattern}, []string{path.Base(relative)})
+	}
+
+	return matchGlobSegments(strings.Split(r.pattern, "/"), strings.Split(relative, "/"))
+}
+
+// matchGlobSegments matches path elements one by one; a "**" element matches
+// any number of elements, including none.
+func matchGlobSegments(pattern []string, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for skip := 0; skip <= len(segments); skip++ {
+				if matchGlobSegments(pattern[1:], segments[skip:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(segments) == 0 {
+			return false
+		}
+		matched, err := path.Match(pattern[0], segments[0])
+		if err != nil || !matched {
+			return false
+		}
+		pattern = pattern[1:]
+		segments = segments[1:]
+	}
+
+	return len(segments) == 0
+}
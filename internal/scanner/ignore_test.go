@@ -0,0 +1,119 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+func TestIgnoreMatcherAppliesGitignoreRules(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeImportTestFile(t, filepath.Join(root, ignoreFileName), `
+# comments and blank lines are skipped
+*.log
+!keep.log
+/top.flac
+Samples/
+**/Stems/**
+Live/**/*.wav
+\!bang.flac
+`)
+	writeImportTestFile(t, filepath.Join(root, "Album", ignoreFileName), "!*.log\nbonus.flac\n")
+
+	matcher := newIgnoreMatcher(root, library.RootRules{Exclude: []string{"*.cue"}})
+	cases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "debug.log", want: true},
+		{path: "Other/debug.log", want: true},
+		{path: "keep.log", want: false},
+		// A deeper .benignore overrides the root's rules for its folder.
+		{path: "Album/debug.log", want: false},
+		{path: "Album/bonus.flac", want: true},
+		{path: "Other/bonus.flac", want: false},
+		// A leading slash anchors the pattern to the folder of the rule.
+		{path: "top.flac", want: true},
+		{path: "Album/top.flac", want: false},
+		// A trailing slash matches directories only, and everything in them.
+		{path: "Samples", isDir: true, want: true},
+		{path: "Samples/kick.flac", want: true},
+		{path: "Album/Samples", isDir: true, want: true},
+		{path: "Album/Samples", want: false},
+		// "**" matches any number of folders, including none.
+		{path: "Stems/bass.flac", want: true},
+		{path: "Album/Disc 1/Stems/bass.flac", want: true},
+		{path: "Live/set.wav", want: true},
+		{path: "Live/2024/Night/set.wav", want: true},
+		{path: "Album/Live/set.wav", want: false},
+		{path: `!bang.flac`, want: true},
+		// Exclude patterns of the root rules come first.
+		{path: "Album/disc.cue", want: true},
+		{path: "Album/01 Song.flac", want: false},
+		{path: ".", isDir: true, want: false},
+	}
+	for _, tc := range cases {
+		if got := matcher.ignored(filepath.Join(root, filepath.FromSlash(tc.path)), tc.isDir); got != tc.want {
+			t.Fatalf("%s (dir %v): got %v, want %v", tc.path, tc.isDir, got, tc.want)
+		}
+	}
+}
+
+func TestIgnoreMatcherSkipsHiddenFilesAndSmallFiles(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	matcher := newIgnoreMatcher(root, library.RootRules{SkipHidden: true, MinFileSize: 100})
+	cases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: ".hidden.flac", want: true},
+		{path: ".cache", isDir: true, want: true},
+		{path: ".cache/song.flac", want: true},
+		{path: "Album/song.flac", want: false},
+	}
+	for _, tc := range cases {
+		if got := matcher.ignored(filepath.Join(root, filepath.FromSlash(tc.path)), tc.isDir); got != tc.want {
+			t.Fatalf("%s: got %v, want %v", tc.path, got, tc.want)
+		}
+	}
+
+	sizes := []struct {
+		size int64
+		want bool
+	}{
+		{size: 0, want: true},
+		{size: 99, want: true},
+		{size: 100, want: false},
+		{size: 5000, want: false},
+	}
+	for _, tc := range sizes {
+		if got := matcher.tooSmall(fakeFileInfo{size: tc.size}); got != tc.want {
+			t.Fatalf("size %d: got %v, want %v", tc.size, got, tc.want)
+		}
+	}
+
+	unlimited := newIgnoreMatcher(root, library.RootRules{})
+	if unlimited.tooSmall(fakeFileInfo{size: 0}) {
+		t.Fatalf("expected no minimum size without the rule")
+	}
+}
+
+type fakeFileInfo struct {
+	size int64
+}
+
+func (f fakeFileInfo) Name() string       { return "song.flac" }
+func (f fakeFileInfo) Size() int64        { return f.size }
+func (f fakeFileInfo) Mode() os.FileMode  { return 0o644 }
+func (f fakeFileInfo) ModTime() time.Time { return time.Time{} }
+func (f fakeFileInfo) IsDir() bool        { return false }
+func (f fakeFileInfo) Sys() any           { return nil }
@@ -0,0 +1,142 @@
+package scanner
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rzxx/ben/internal/coverart"
+)
+
+// coverSourceKindOnline marks a cover downloaded for an album that had no
+// embedded or sidecar art. Its source path is the URL it came from. Scans
+// keep it until the files gain art of their own.
+const coverSourceKindOnline = "online"
+
+var ErrAlbumNotFound = errors.New("album not found")
+
+// AttachAlbumCover stores a downloaded image as the cover of every file of
+// the album that has no cover yet, and points the album at it.
+func (s *Service) AttachAlbumCover(ctx context.Context, title string, albumArtist string, imageData []byte, sourceURL string) (AlbumCoverChange, error) {
+	change := AlbumCoverChange{Album: title, AlbumArtist: albumArtist}
+	if strings.TrimSpace(s.coverCacheDir) == "" {
+		return change, errors.New("cover cache is unavailable")
+	}
+
+	format, width, height := decodeCoverImage(imageData)
+	mimeType := mimeTypeFromImageFormat(format)
+	if mimeType == "" {
+		return change, errors.New("downloaded cover is not a supported image")
+	}
+
+	hashBytes := sha256.Sum256(imageData)
+	hash := hex.EncodeToString(hashBytes[:])
+	cachePath := coverart.VariantPathForHash(s.coverCacheDir, hash, coverart.VariantDetail)
+	if err := os.MkdirAll(s.coverCacheDir, 0o755); err != nil {
+		return change, fmt.Errorf("create cover cache dir: %w", err)
+	}
+	if err := ensureCoverThumbnails(cachePath, hash, imageData); err != nil {
+		return change, fmt.Errorf("create cover thumbnails: %w", err)
+	}
+	dominantColor, blurHash := coverPlaceholder(cachePath)
+	perceptualHash := coverPerceptualHash(cachePath)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return change, fmt.Errorf("begin album cover tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT DISTINCT t.file_id
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		WHERE f.file_exists = 1
+		  AND f.is_audiobook = 0
+		  AND COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album') = ?
+		  AND COALESCE(NULLIF(TRIM(t.album_artist), ''), COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) = ?
+		  AND NOT EXISTS (SELECT 1 FROM covers c WHERE c.source_file_id = t.file_id)
+	`, title, albumArtist)
+	if err != nil {
+		return change, fmt.Errorf("query album files for cover: %w", err)
+	}
+	fileIDs := make([]int64, 0)
+	for rows.Next() {
+		var fileID int64
+		if scanErr := rows.Scan(&fileID); scanErr != nil {
+			rows.Close()
+			return change, fmt.Errorf("scan album file for cover: %w", scanErr)
+		}
+		fileIDs = append(fileIDs, fileID)
+	}
+	rowsErr := rows.Err()
+	rows.Close()
+	if rowsErr != nil {
+		return change, fmt.Errorf("iterate album files for cover: %w", rowsErr)
+	}
+	if len(fileIDs) == 0 {
+		return change, ErrAlbumNotFound
+	}
+
+	var firstCoverID int64
+	for _, fileID := range fileIDs {
+		result, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO covers(source_file_id, mime, width, height, cache_path, hash, source_kind, source_path, dominant_color, blurhash, phash) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			fileID,
+			mimeType,
+			nullablePositiveInt(width),
+			nullablePositiveInt(height),
+			cachePath,
+			hash,
+			coverSourceKindOnline,
+			nullableString(sourceURL),
+			dominantColor,
+			blurHash,
+			perceptualHash,
+		)
+		if err != nil {
+			return change, fmt.Errorf("insert online cover for file %d: %w", fileID, err)
+		}
+		if firstCoverID == 0 {
+			firstCoverID, _ = result.LastInsertId()
+		}
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		"UPDATE albums SET cover_id = ? WHERE title = ? AND album_artist = ? AND cover_id IS NULL",
+		firstCoverID,
+		title,
+		albumArtist,
+	); err != nil {
+		return change, fmt.Errorf("link online cover to album: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return change, fmt.Errorf("commit album cover tx: %w", err)
+	}
+
+	change.CoverPath = cachePath
+	s.mu.Lock()
+	emitter := s.emit
+	s.mu.Unlock()
+	if emitter != nil {
+		emitter(EventCoversChanged, CoversChanged{Albums: []AlbumCoverChange{change}})
+	}
+
+	return change, nil
+}
+
+// keepOnlineCover reports whether a file without embedded or sidecar art
+// should keep its existing cover row.
+func keepOnlineCover(sourceKind sql.NullString) bool {
+	return strings.EqualFold(strings.TrimSpace(sourceKind.String), coverSourceKindOnline)
+}
@@ -0,0 +1,194 @@
+package scanner
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rzxx/ben/internal/archive"
+	"github.com/rzxx/ben/internal/library"
+)
+
+// progressInterval is the shortest time between two per-file progress
+// events, so large scans do not flood the frontend.
+const progressInterval = 250 * time.Millisecond
+
+// RootProgress is the progress of a scan through one watched folder.
+// Discovered is the number of audio files and archives a quick walk found
+// before the scan; Remaining is what is left of them.
+type RootProgress struct {
+	RootID     int64  `json:"rootId"`
+	Path       string `json:"path"`
+	Discovered int    `json:"discovered"`
+	Processed  int    `json:"processed"`
+	Remaining  int    `json:"remaining"`
+	Done       bool   `json:"done"`
+}
+
+// scanProgress follows a scan through its local roots and reports the
+// files processed, throughput and the estimated time remaining. Its
+// methods do nothing on a nil receiver, for callers that do not report.
+type scanProgress struct {
+	service     *Service
+	percentFrom int
+	percentTo   int
+	startedAt   time.Time
+	lastEmitAt  time.Time
+	roots       []RootProgress
+	current     int
+	currentPath string
+}
+
+// newScanProgress counts the files of roots and returns a tracker whose
+// percentages run from percentFrom to percentTo.
+func (s *Service) newScanProgress(ctx context.Context, roots []library.WatchedRoot, indexArchives bool, percentFrom int, percentTo int) *scanProgress {
+	s.emitProgress(Progress{
+		Phase:   "count",
+		Message: s.text("scan.countingFiles"),
+		Percent: percentFrom,
+		Status:  "running",
+		At:      time.Now().UTC().Format(time.RFC3339),
+	})
+
+	progress := &scanProgress{
+		service:     s,
+		percentFrom: percentFrom,
+		percentTo:   percentTo,
+		roots:       make([]RootProgress, 0, len(roots)),
+		current:     -1,
+	}
+	for _, root := range roots {
+		progress.roots = append(progress.roots, RootProgress{
+			RootID:     root.ID,
+			Path:       root.Path,
+			Discovered: countRootFiles(ctx, root, indexArchives),
+		})
+	}
+	for index := range progress.roots {
+		progress.roots[index].Remaining = progress.roots[index].Discovered
+	}
+	progress.startedAt = time.Now()
+
+	return progress
+}
+
+// countRootFiles counts the files scanRoot would process under root,
+// without reading them.
+func countRootFiles(ctx context.Context, root library.WatchedRoot, indexArchives bool) int {
+	count := 0
+	ignore := newIgnoreMatcher(root.Path, root.Rules)
+	_ = filepath.WalkDir(root.Path, func(path string, entry fs.DirEntry, walkErr error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if walkErr != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			if ignore.ignored(path, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ignore.ignored(path, false) {
+			return nil
+		}
+		if indexArchives && archive.IsArchive(path) {
+			count++
+			return nil
+		}
+		if isPlayableExtension(strings.ToLower(filepath.Ext(path))) {
+			if ignore.minFileSize > 0 {
+				if info, err := entry.Info(); err != nil || ignore.tooSmall(info) {
+					return nil
+				}
+			}
+			count++
+		}
+		return nil
+	})
+
+	return count
+}
+
+// startRoot marks the root at index as the one being scanned.
+func (p *scanProgress) startRoot(index int) {
+	if p == nil || index < 0 || index >= len(p.roots) {
+		return
+	}
+
+	p.current = index
+	p.currentPath = ""
+	p.emit(true)
+}
+
+// fileDone counts a processed file of the current root.
+func (p *scanProgress) fileDone(path string) {
+	if p == nil || p.current < 0 {
+		return
+	}
+
+	root := &p.roots[p.current]
+	root.Processed++
+	// Files added since the count still count as discovered.
+	root.Discovered = max(root.Discovered, root.Processed)
+	root.Remaining = root.Discovered - root.Processed
+	p.currentPath = path
+	p.emit(false)
+}
+
+// finishRoot marks the current root done; files the count found but the
+// scan did not, e.g. deleted in between, are no longer remaining.
+func (p *scanProgress) finishRoot() {
+	if p == nil || p.current < 0 {
+		return
+	}
+
+	root := &p.roots[p.current]
+	root.Discovered = root.Processed
+	root.Remaining = 0
+	root.Done = true
+}
+
+func (p *scanProgress) emit(force bool) {
+	now := time.Now()
+	if !force && now.Sub(p.lastEmitAt) < progressInterval {
+		return
+	}
+	p.lastEmitAt = now
+
+	p.service.emitProgress(p.snapshot(now))
+}
+
+func (p *scanProgress) snapshot(now time.Time) Progress {
+	discovered, processed, remaining := 0, 0, 0
+	for _, root := range p.roots {
+		discovered += root.Discovered
+		processed += root.Processed
+		remaining += root.Remaining
+	}
+
+	percent := p.percentFrom
+	if discovered > 0 {
+		percent += (p.percentTo - p.percentFrom) * processed / discovered
+	}
+
+	progress := Progress{
+		Phase:       "scan",
+		Message:     p.service.text("scan.scanningRoot", p.roots[p.current].Path),
+		Percent:     percent,
+		Status:      "running",
+		At:          now.UTC().Format(time.RFC3339),
+		Roots:       append([]RootProgress(nil), p.roots...),
+		CurrentPath: p.currentPath,
+	}
+	if elapsed := now.Sub(p.startedAt).Seconds(); elapsed > 0 && processed > 0 {
+		progress.FilesPerSecond = float64(processed) / elapsed
+		eta := int(float64(remaining)/progress.FilesPerSecond + 0.5)
+		progress.ETASeconds = &eta
+	}
+
+	return progress
+}
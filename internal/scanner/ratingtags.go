@@ -0,0 +1,255 @@
+package scanner
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RatingPrecedenceSettingKey decides who wins when a file's rating tags
+// disagree with a rating already stored in the library.
+const RatingPrecedenceSettingKey = "library.rating_tag_precedence"
+
+const (
+	RatingPrecedenceDatabase = "database"
+	RatingPrecedenceTags     = "tags"
+)
+
+type tagRating struct {
+	rating   *int
+	favorite *bool
+}
+
+func normalizeRatingPrecedence(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", RatingPrecedenceDatabase:
+		return RatingPrecedenceDatabase, nil
+	case RatingPrecedenceTags:
+		return RatingPrecedenceTags, nil
+	default:
+		return "", fmt.Errorf("unsupported rating precedence %q", value)
+	}
+}
+
+func (s *Service) RatingTagPrecedence(ctx context.Context) string {
+	precedence, err := normalizeRatingPrecedence(s.settings.GetString(ctx, RatingPrecedenceSettingKey, RatingPrecedenceDatabase))
+	if err != nil {
+		return RatingPrecedenceDatabase
+	}
+
+	return precedence
+}
+
+func (s *Service) SetRatingTagPrecedence(ctx context.Context, value string) (string, error) {
+	precedence, err := normalizeRatingPrecedence(value)
+	if err != nil {
+		return s.RatingTagPrecedence(ctx), err
+	}
+
+	if err := s.settings.Set(ctx, RatingPrecedenceSettingKey, precedence); err != nil {
+		return s.RatingTagPrecedence(ctx), err
+	}
+
+	return precedence, nil
+}
+
+// importTagRatings seeds ratings and favorites from RATING, FMPS_RATING,
+// POPM and LOVE style tags written by other players. Each track is read once;
+// later tag edits do not override what was rated in Ben.
+func importTagRatings(ctx context.Context, tx *sql.Tx, precedence string) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT t.id, t.tags_json, r.rating, COALESCE(r.favorite, 0)
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN track_ratings r ON r.track_id = t.id
+		WHERE f.file_exists = 1
+		  AND t.rating_tags_imported_at IS NULL
+	`)
+	if err != nil {
+		return fmt.Errorf("query tracks for rating tag import: %w", err)
+	}
+
+	type pendingImport struct {
+		trackID        int64
+		tags           tagRating
+		storedRating   sql.NullInt64
+		storedFavorite bool
+	}
+
+	pending := make([]pendingImport, 0)
+	for rows.Next() {
+		var item pendingImport
+		var tagsJSON sql.NullString
+		var favoriteInt int
+		if scanErr := rows.Scan(&item.trackID, &tagsJSON, &item.storedRating, &favoriteInt); scanErr != nil {
+			rows.Close()
+			return fmt.Errorf("scan rating tag import row: %w", scanErr)
+		}
+		item.storedFavorite = favoriteInt == 1
+		item.tags = parseTagRating(tagsJSON.String)
+		pending = append(pending, item)
+	}
+	rowsErr := rows.Err()
+	rows.Close()
+	if rowsErr != nil {
+		return fmt.Errorf("iterate rating tag import rows: %w", rowsErr)
+	}
+
+	importedAt := time.Now().UTC().Format(time.RFC3339)
+	for _, item := range pending {
+		rating := item.storedRating
+		favorite := item.storedFavorite
+		if item.tags.rating != nil && (!rating.Valid || precedence == RatingPrecedenceTags) {
+			rating = sql.NullInt64{Int64: int64(*item.tags.rating), Valid: true}
+		}
+		if item.tags.favorite != nil && (!favorite || precedence == RatingPrecedenceTags) {
+			favorite = *item.tags.favorite
+		}
+
+		if rating != item.storedRating || favorite != item.storedFavorite {
+			if _, err := tx.ExecContext(
+				ctx,
+				`INSERT INTO track_ratings(track_id, rating, favorite, updated_at)
+				 VALUES (?, ?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+				 ON CONFLICT(track_id) DO UPDATE SET
+				 	rating = excluded.rating,
+				 	favorite = excluded.favorite,
+				 	updated_at = excluded.updated_at`,
+				item.trackID,
+				rating,
+				boolToInt(favorite),
+			); err != nil {
+				return fmt.Errorf("import rating tags for track %d: %w", item.trackID, err)
+			}
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			"UPDATE tracks SET rating_tags_imported_at = ? WHERE id = ?",
+			importedAt,
+			item.trackID,
+		); err != nil {
+			return fmt.Errorf("mark rating tags imported for track %d: %w", item.trackID, err)
+		}
+	}
+
+	return nil
+}
+
+func parseTagRating(tagsJSON string) tagRating {
+	if strings.TrimSpace(tagsJSON) == "" {
+		return tagRating{}
+	}
+
+	var stored struct {
+		Tags map[string][]string `json:"taglib_tags"`
+	}
+	if err := json.Unmarshal([]byte(tagsJSON), &stored); err != nil || len(stored.Tags) == 0 {
+		return tagRating{}
+	}
+
+	tags := make(map[string][]string, len(stored.Tags))
+	for key, values := range stored.Tags {
+		tags[strings.ToUpper(key)] = values
+	}
+
+	result := tagRating{}
+	if value := firstTagValue(tags, "FMPS_RATING"); value != "" {
+		if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && parsed > 0 && parsed <= 1 {
+			stars := max(1, int(math.Round(parsed*5)))
+			result.rating = &stars
+		}
+	}
+	if result.rating == nil {
+		result.rating = starsFromRatingValue(firstTagValue(tags, "RATING", "RATING WMP", "WM/SHAREDUSERRATING"))
+	}
+	if result.rating == nil {
+		result.rating = starsFromPopularimeter(firstTagValue(tags, "POPM"))
+	}
+
+	if value := firstTagValue(tags, "LOVE", "LOVED", "FAVORITE", "FMPS_LOVED"); value != "" {
+		loved := isTruthyTag(value)
+		result.favorite = &loved
+	}
+
+	return result
+}
+
+// starsFromRatingValue maps the scales other players write into RATING onto
+// 1-5 stars: plain stars, tenths, percent and the 0-255 POPM byte.
+func starsFromRatingValue(value string) *int {
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || parsed <= 0 {
+		return nil
+	}
+
+	var stars int
+	switch {
+	case parsed <= 5:
+		stars = int(math.Round(parsed))
+	case parsed <= 10:
+		stars = int(math.Round(parsed / 2))
+	case parsed <= 100:
+		stars = int(math.Round(parsed / 20))
+	case parsed <= 255:
+		return starsFromPopularimeter(strconv.Itoa(int(parsed)))
+	default:
+		return nil
+	}
+
+	stars = min(5, max(1, stars))
+	return &stars
+}
+
+// starsFromPopularimeter converts an ID3 POPM rating byte, either bare or in
+// the "email|rating|counter" form, using the Windows Media Player ranges.
+func starsFromPopularimeter(value string) *int {
+	trimmed := strings.TrimSpace(value)
+	if parts := strings.Split(trimmed, "|"); len(parts) >= 2 {
+		trimmed = strings.TrimSpace(parts[1])
+	}
+
+	parsed, err := strconv.Atoi(trimmed)
+	if err != nil || parsed <= 0 || parsed > 255 {
+		return nil
+	}
+
+	var stars int
+	switch {
+	case parsed < 32:
+		stars = 1
+	case parsed < 96:
+		stars = 2
+	case parsed < 160:
+		stars = 3
+	case parsed < 224:
+		stars = 4
+	default:
+		stars = 5
+	}
+
+	return &stars
+}
+
+func isTruthyTag(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "loved", "love":
+		return true
+	}
+
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	return err == nil && parsed > 0
+}
+
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,337 @@
+package scanner
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/remote"
+)
+
+var errRemoteRootOffline = errors.New("remote root is offline")
+
+var errRemoteFileUnavailable = errors.New("remote file is unavailable")
+
+func splitRootsByKind(roots []library.WatchedRoot) ([]library.WatchedRoot, []library.WatchedRoot) {
+	localRoots := make([]library.WatchedRoot, 0, len(roots))
+	remoteRoots := make([]library.WatchedRoot, 0)
+	for _, root := range roots {
+		if root.IsRemote() {
+			remoteRoots = append(remoteRoots, root)
+			continue
+		}
+		localRoots = append(localRoots, root)
+	}
+
+	return localRoots, remoteRoots
+}
+
+func (s *Service) scanRemoteRoots(ctx context.Context, batch *scanTx, roots []library.WatchedRoot, mode scanMode) (scanTotals, error) {
+	totals := scanTotals{}
+
+	s.mu.Lock()
+	credentials := s.credentials
+	s.mu.Unlock()
+
+	for i, root := range roots {
+		s.emitProgress(Progress{
+			Phase:   "scan",
+			Message: s.text("scan.scanningRemote", root.Path),
+			Percent: 80 + ((i * 8) / len(roots)),
+			Status:  "running",
+			At:      time.Now().UTC().Format(time.RFC3339),
+		})
+
+		rootTotals, err := scanRemoteRoot(ctx, batch, root, credentials, mode, s.coverCacheDir)
+		if errors.Is(err, errRemoteRootOffline) {
+			// An unreachable share keeps its indexed tracks so the library
+			// does not empty itself every time the NAS sleeps.
+			s.emitProgress(Progress{
+				Phase:   "scan",
+				Message: s.text("scan.remoteOffline", root.Path),
+				Percent: 80 + ((i * 8) / len(roots)),
+				Status:  "running",
+				At:      time.Now().UTC().Format(time.RFC3339),
+			})
+			continue
+		}
+		if err != nil {
+			return scanTotals{}, err
+		}
+
+		totals.filesSeen += rootTotals.filesSeen
+		totals.indexed += rootTotals.indexed
+		totals.skipped += rootTotals.skipped
+		totals.libraryChanged = totals.libraryChanged || rootTotals.libraryChanged
+	}
+
+	return totals, nil
+}
+
+// scanRemoteRoot lists a remote root and indexes its audio files. Files whose
+// size and modification time match the last scan are not downloaded again;
+// changed files are fetched into a staging folder so tags and artwork can be
+// read with the same code paths as local files. Files the listing no longer
+// has are only marked missing once the whole listing was indexed, so the
+// batches committed meanwhile never hide a track.
+func scanRemoteRoot(ctx context.Context, batch *scanTx, root library.WatchedRoot, credentials *remote.Credentials, mode scanMode, coverCacheDir string) (scanTotals, error) {
+	rootTotals := scanTotals{}
+	scannedAt := time.Now().UTC().Format(time.RFC3339)
+
+	var entries []remote.Entry
+	client, listErr := credentials.Open(ctx, root)
+	if listErr == nil {
+		defer client.Close()
+		entries, listErr = client.Walk(ctx)
+	}
+	if listErr != nil {
+		if ctx.Err() != nil {
+			return scanTotals{}, ctx.Err()
+		}
+		if err := setRemoteRootStatus(ctx, batch.tx, root.ID, listErr.Error(), scannedAt); err != nil {
+			return scanTotals{}, err
+		}
+		return scanTotals{}, fmt.Errorf("%w: %v", errRemoteRootOffline, listErr)
+	}
+
+	if err := clearIncrementalSeenTable(ctx, batch.tx); err != nil {
+		return scanTotals{}, err
+	}
+
+	stagingDir, err := os.MkdirTemp("", "ben-remote-scan-*")
+	if err != nil {
+		return scanTotals{}, fmt.Errorf("create remote staging folder: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	artworkByDir := make(map[string][]remote.Entry)
+	for _, entry := range entries {
+		if isSupportedArtworkExtension(strings.ToLower(path.Ext(entry.RelativePath))) {
+			dir := remote.RelativeDir(entry.RelativePath)
+			artworkByDir[dir] = append(artworkByDir[dir], entry)
+		}
+	}
+
+	fetchedArtworkDirs := make(map[string]struct{})
+	fetchArtwork := func(dir string) {
+		dirs := []string{dir}
+		if dir != "" && shouldSearchParentForSidecar(dir) {
+			dirs = append(dirs, remote.RelativeDir(dir))
+		}
+
+		for _, artworkDir := range dirs {
+			if _, done := fetchedArtworkDirs[artworkDir]; done {
+				continue
+			}
+			fetchedArtworkDirs[artworkDir] = struct{}{}
+
+			for _, artwork := range artworkByDir[artworkDir] {
+				// Missing artwork only costs the sidecar cover, not the track.
+				_ = client.Download(ctx, artwork.URL, filepath.Join(stagingDir, filepath.FromSlash(artwork.RelativePath)))
+			}
+		}
+	}
+
+	for _, entry := range entries {
+		if !isPlayableExtension(strings.ToLower(path.Ext(entry.RelativePath))) {
+			continue
+		}
+
+		rootTotals.filesSeen++
+		if err := markPathSeenIncremental(ctx, batch.tx, entry.URL); err != nil {
+			return scanTotals{}, err
+		}
+		indexed, err := upsertRemoteFileAndTrack(ctx, batch.tx, client, root.ID, entry, stagingDir, scannedAt, mode, coverCacheDir, fetchArtwork)
+		if errors.Is(err, errRemoteFileUnavailable) {
+			rootTotals.skipped++
+			continue
+		}
+		if err != nil {
+			return scanTotals{}, err
+		}
+
+		if indexed {
+			rootTotals.indexed++
+			rootTotals.libraryChanged = true
+		}
+		if err := batch.fileProcessed(ctx); err != nil {
+			return scanTotals{}, err
+		}
+	}
+
+	filesMissing, err := markUnseenFilesMissing(ctx, batch.tx, root.ID)
+	if err != nil {
+		return scanTotals{}, err
+	}
+	rootTotals.libraryChanged = rootTotals.libraryChanged || filesMissing
+
+	tracksCleaned, err := cleanupMissingTracks(ctx, batch.tx, []library.WatchedRoot{root})
+	if err != nil {
+		return scanTotals{}, err
+	}
+	rootTotals.libraryChanged = rootTotals.libraryChanged || tracksCleaned
+
+	if err := setRemoteRootStatus(ctx, batch.tx, root.ID, "", scannedAt); err != nil {
+		return scanTotals{}, err
+	}
+
+	return rootTotals, nil
+}
+
+func upsertRemoteFileAndTrack(
+	ctx context.Context,
+	tx *sql.Tx,
+	client remote.Source,
+	rootID int64,
+	entry remote.Entry,
+	stagingDir string,
+	scannedAt string,
+	mode scanMode,
+	coverCacheDir string,
+	fetchArtwork func(dir string),
+) (bool, error) {
+	var (
+		fileID       int64
+		currentSize  int64
+		currentMTime int64
+	)
+
+	err := tx.QueryRowContext(
+		ctx,
+		"SELECT id, size, mtime_ns FROM files WHERE path = ?",
+		entry.URL,
+	).Scan(&fileID, &currentSize, &currentMTime)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("get file row %s: %w", entry.URL, err)
+	}
+	found := err == nil
+
+	newMTime := int64(0)
+	if !entry.ModTime.IsZero() {
+		newMTime = entry.ModTime.UnixNano()
+	}
+
+	metadataCurrent := false
+	if found && mode != scanModeRepair && currentSize == entry.Size && currentMTime == newMTime {
+		metadataCurrent, err = hasCurrentTrackMetadata(ctx, tx, fileID, entry.URL)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if metadataCurrent {
+		if _, err := tx.ExecContext(
+			ctx,
+			"UPDATE files SET root_id = ?, file_exists = 1, last_seen_at = ? WHERE id = ?",
+			rootID,
+			scannedAt,
+			fileID,
+		); err != nil {
+			return false, fmt.Errorf("update file %s: %w", entry.URL, err)
+		}
+
+		return false, nil
+	}
+
+	localPath := filepath.Join(stagingDir, filepath.FromSlash(entry.RelativePath))
+	if downloadErr := client.Download(ctx, entry.URL, localPath); downloadErr != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if found {
+			// Keep the previous index entry; the stale size and mtime make
+			// the next scan retry the download.
+			if _, err := tx.ExecContext(
+				ctx,
+				"UPDATE files SET file_exists = 1, last_seen_at = ? WHERE id = ?",
+				scannedAt,
+				fileID,
+			); err != nil {
+				return false, fmt.Errorf("update file %s: %w", entry.URL, err)
+			}
+		}
+		return false, fmt.Errorf("%w: %v", errRemoteFileUnavailable, downloadErr)
+	}
+	defer os.Remove(localPath)
+
+	fetchArtwork(remote.RelativeDir(entry.RelativePath))
+
+	if found {
+		if _, err := tx.ExecContext(
+			ctx,
+			`UPDATE files
+			 SET root_id = ?, size = ?, mtime_ns = ?, file_exists = 1, last_seen_at = ?
+			 WHERE id = ?`,
+			rootID,
+			entry.Size,
+			newMTime,
+			scannedAt,
+			fileID,
+		); err != nil {
+			return false, fmt.Errorf("update file %s: %w", entry.URL, err)
+		}
+	} else {
+		result, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO files(path, root_id, size, mtime_ns, file_exists, last_seen_at, first_seen_at)
+			 VALUES (?, ?, ?, ?, 1, ?, ?)`,
+			entry.URL,
+			rootID,
+			entry.Size,
+			newMTime,
+			scannedAt,
+			scannedAt,
+		)
+		if err != nil {
+			return false, fmt.Errorf("insert file %s: %w", entry.URL, err)
+		}
+
+		fileID, err = result.LastInsertId()
+		if err != nil {
+			return false, fmt.Errorf("read file id %s: %w", entry.URL, err)
+		}
+	}
+
+	metadata, err := deriveMetadata(stagingDir, localPath)
+	if err != nil {
+		return false, err
+	}
+	metadata.tags["remote_url"] = entry.URL
+
+	if err := upsertTrackMetadata(ctx, tx, fileID, entry.URL, metadata); err != nil {
+		return false, err
+	}
+
+	if _, err := syncCoverForFile(ctx, tx, fileID, localPath, coverCacheDir, true); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func setRemoteRootStatus(ctx context.Context, tx *sql.Tx, rootID int64, lastError string, checkedAt string) error {
+	offline := 0
+	if lastError != "" {
+		offline = 1
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		"UPDATE watched_roots SET offline = ?, last_error = NULLIF(?, ''), last_checked_at = ? WHERE id = ?",
+		offline,
+		lastError,
+		checkedAt,
+		rootID,
+	); err != nil {
+		return fmt.Errorf("update remote root %d status: %w", rootID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"strconv"
+	"strings"
+)
+
+// r128ReferenceOffsetDB lifts R128 gains, relative to -23 LUFS, to the
+// ReplayGain reference level of -18 LUFS.
+const r128ReferenceOffsetDB = 5
+
+// applyReplayGainTags reads REPLAYGAIN_* tags, falling back to the R128
+// gains Opus files carry instead.
+func applyReplayGainTags(metadata *extractedMetadata, tags map[string][]string) {
+	metadata.trackGain = parseGainTag(firstTagValue(tags, "REPLAYGAIN_TRACK_GAIN"))
+	metadata.trackPeak = parsePeakTag(firstTagValue(tags, "REPLAYGAIN_TRACK_PEAK"))
+	metadata.albumGain = parseGainTag(firstTagValue(tags, "REPLAYGAIN_ALBUM_GAIN"))
+	metadata.albumPeak = parsePeakTag(firstTagValue(tags, "REPLAYGAIN_ALBUM_PEAK"))
+
+	if metadata.trackGain == nil {
+		metadata.trackGain = parseR128GainTag(firstTagValue(tags, "R128_TRACK_GAIN"))
+	}
+	if metadata.albumGain == nil {
+		metadata.albumGain = parseR128GainTag(firstTagValue(tags, "R128_ALBUM_GAIN"))
+	}
+}
+
+// parseGainTag reads values like "-7.25 dB".
+func parseGainTag(value string) *float64 {
+	trimmed := strings.TrimSpace(value)
+	if len(trimmed) >= 2 && strings.EqualFold(trimmed[len(trimmed)-2:], "db") {
+		trimmed = strings.TrimSpace(trimmed[:len(trimmed)-2])
+	}
+	if trimmed == "" {
+		return nil
+	}
+
+	gain, err := strconv.ParseFloat(trimmed, 64)
+	if err != nil || gain < -64 || gain > 64 {
+		return nil
+	}
+
+	return &gain
+}
+
+func parsePeakTag(value string) *float64 {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return nil
+	}
+
+	peak, err := strconv.ParseFloat(trimmed, 64)
+	if err != nil || peak <= 0 || peak > 16 {
+		return nil
+	}
+
+	return &peak
+}
+
+// parseR128GainTag reads the Q7.8 fixed point gains of RFC 7845.
+func parseR128GainTag(value string) *float64 {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return nil
+	}
+
+	fixed, err := strconv.ParseInt(trimmed, 10, 16)
+	if err != nil {
+		return nil
+	}
+
+	gain := float64(fixed)/256 + r128ReferenceOffsetDB
+	return &gain
+}
+
+func nullableFloat(value *float64) any {
+	if value == nil {
+		return nil
+	}
+
+	return *value
+}
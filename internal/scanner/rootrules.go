@@ -0,0 +1,76 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+// Global exclude rules, applied to every watched root on top of its own.
+// Patterns are stored one per line.
+const (
+	ExcludePatternsSettingKey   = "library.exclude.patterns"
+	ExcludeSkipHiddenSettingKey = "library.exclude.skip_hidden"
+	ExcludeMinSizeSettingKey    = "library.exclude.min_file_size"
+)
+
+// ExcludeRules returns the global exclude rules.
+func (s *Service) ExcludeRules(ctx context.Context) library.RootRules {
+	rules := library.RootRules{Exclude: make([]string, 0)}
+	for _, pattern := range strings.Split(s.settings.GetString(ctx, ExcludePatternsSettingKey, ""), "\n") {
+		if pattern != "" {
+			rules.Exclude = append(rules.Exclude, pattern)
+		}
+	}
+	rules.SkipHidden, _ = strconv.ParseBool(s.settings.GetString(ctx, ExcludeSkipHiddenSettingKey, "false"))
+	rules.MinFileSize, _ = strconv.ParseInt(s.settings.GetString(ctx, ExcludeMinSizeSettingKey, "0"), 10, 64)
+
+	return rules
+}
+
+// SetExcludeRules stores the global exclude rules. Like changed roots, it
+// refreshes the watcher and rescans the roots, so newly excluded files
+// leave the library.
+func (s *Service) SetExcludeRules(ctx context.Context, rules library.RootRules) (library.RootRules, error) {
+	normalized, err := library.NormalizeRootRules(rules)
+	if err != nil {
+		return s.ExcludeRules(ctx), err
+	}
+
+	values := map[string]string{
+		ExcludePatternsSettingKey:   strings.Join(normalized.Exclude, "\n"),
+		ExcludeSkipHiddenSettingKey: strconv.FormatBool(normalized.SkipHidden),
+		ExcludeMinSizeSettingKey:    strconv.FormatInt(normalized.MinFileSize, 10),
+	}
+	for key, value := range values {
+		if err := s.settings.Set(ctx, key, value); err != nil {
+			return s.ExcludeRules(ctx), err
+		}
+	}
+
+	s.NotifyWatchedRootsChanged()
+	return normalized, nil
+}
+
+// listRoots lists the watched roots with the global exclude rules merged
+// into their own. Everything in the scanner lists roots through it.
+func (s *Service) listRoots(ctx context.Context) ([]library.WatchedRoot, error) {
+	if s.roots == nil {
+		return nil, errors.New("watched roots are unavailable")
+	}
+
+	roots, err := s.roots.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	global := s.ExcludeRules(ctx)
+	for index := range roots {
+		roots[index].Rules = global.Merge(roots[index].Rules)
+	}
+
+	return roots, nil
+}
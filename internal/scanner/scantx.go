@@ -0,0 +1,145 @@
+package scanner
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// scanCommitInterval is how many files a scan walks between commits, so a
+// canceled scan keeps the files it already indexed and other writers are
+// not locked out for a whole scan.
+const scanCommitInterval = 500
+
+// scanTx is the transaction of a running scan. It is replaced by a new one
+// at every commit; all of them run on one connection, so the scan's
+// temporary tables survive the commits.
+type scanTx struct {
+	conn     *sql.Conn
+	tx       *sql.Tx
+	pending  int
+	interval int
+	// onCommit, if set, runs after every batch commit.
+	onCommit func()
+	// committed is set once a batch was committed, after which a failed
+	// scan has left changes behind that the derived tables do not reflect.
+	committed bool
+}
+
+func beginScanTx(ctx context.Context, database *sql.DB) (*scanTx, error) {
+	conn, err := database.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reserve scan connection: %w", err)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("begin scan tx: %w", err)
+	}
+
+	return &scanTx{conn: conn, tx: tx, interval: scanCommitInterval}, nil
+}
+
+// fileProcessed counts a walked file and commits once interval files are
+// pending. A canceled ctx rolls back only the files since the
+// last commit.
+func (b *scanTx) fileProcessed(ctx context.Context) error {
+	b.pending++
+	if b.pending < b.interval {
+		return nil
+	}
+
+	if err := b.tx.Commit(); err != nil {
+		b.tx = nil
+		return fmt.Errorf("commit scan batch: %w", err)
+	}
+	b.pending = 0
+	b.committed = true
+	if b.onCommit != nil {
+		b.onCommit()
+	}
+
+	tx, err := b.conn.BeginTx(ctx, nil)
+	if err != nil {
+		b.tx = nil
+		return fmt.Errorf("begin scan tx: %w", err)
+	}
+	b.tx = tx
+	return nil
+}
+
+func (b *scanTx) commit() error {
+	err := b.tx.Commit()
+	b.tx = nil
+	if err != nil {
+		return fmt.Errorf("commit scan tx: %w", err)
+	}
+	return nil
+}
+
+// close rolls back the open transaction, if any, and releases the
+// connection.
+func (b *scanTx) close() {
+	if b.tx != nil {
+		_ = b.tx.Rollback()
+	}
+	_ = b.conn.Close()
+}
+
+// repairPartialScan refreshes the albums, artists and covers after a scan
+// that failed or was canceled once some of its batches were committed, so
+// the tracks it kept do not wait for the next scan to show up in them.
+func (s *Service) repairPartialScan(ctx context.Context) {
+	ctx = context.WithoutCancel(ctx)
+
+	s.emitProgress(Progress{
+		Phase:   "derive",
+		Message: s.text("scan.refreshingCatalog"),
+		Percent: 96,
+		Status:  "running",
+		At:      time.Now().UTC().Format(time.RFC3339),
+	})
+
+	if err := rebuildAfterPartialScan(ctx, s.db); err != nil {
+		s.emitProgress(Progress{
+			Phase:   "derive",
+			Message: fmt.Sprintf("catalog refresh warning: %v", err),
+			Percent: 97,
+			Status:  "running",
+			At:      time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
+	if err := cleanupOrphanedCoverFiles(ctx, s.db, s.coverCacheDir); err != nil {
+		s.emitProgress(Progress{
+			Phase:   "cleanup",
+			Message: fmt.Sprintf("cover cache cleanup warning: %v", err),
+			Percent: 97,
+			Status:  "running",
+			At:      time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+}
+
+func rebuildAfterPartialScan(ctx context.Context, database *sql.DB) error {
+	tx, err := database.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin catalog refresh tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := cleanupMissingCovers(ctx, tx); err != nil {
+		return err
+	}
+	if err := rebuildDerivedLibrary(ctx, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit catalog refresh tx: %w", err)
+	}
+	return nil
+}
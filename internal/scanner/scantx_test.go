@@ -0,0 +1,260 @@
+package scanner
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rzxx/ben/internal/db"
+	"github.com/rzxx/ben/internal/library"
+)
+
+func TestScanTxCommitsEveryInterval(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	_, database := newScannerForTest(t)
+
+	batch, err := beginScanTx(ctx, database)
+	if err != nil {
+		t.Fatalf("begin scan tx: %v", err)
+	}
+	batch.interval = 2
+	commits := 0
+	batch.onCommit = func() { commits++ }
+
+	for index := range 5 {
+		if _, err := batch.tx.ExecContext(
+			ctx,
+			"INSERT INTO files(path, size, mtime_ns, file_exists) VALUES (?, 1, 1, 1)",
+			fmt.Sprintf("/music/%d.mp3", index),
+		); err != nil {
+			t.Fatalf("insert file: %v", err)
+		}
+		if err := batch.fileProcessed(ctx); err != nil {
+			t.Fatalf("file processed: %v", err)
+		}
+	}
+	// The fifth file is still pending and goes away with the rollback.
+	batch.close()
+
+	if commits != 2 || !batch.committed {
+		t.Fatalf("expected 2 commits, got %d (committed %v)", commits, batch.committed)
+	}
+	if count := countRows(t, database, "SELECT COUNT(*) FROM files"); count != 4 {
+		t.Fatalf("expected the 4 committed files to stay, got %d", count)
+	}
+}
+
+func TestMarkUnseenFilesMissingIgnoresClockResolution(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	service, database := newScannerForTest(t)
+	root := addRootForTest(t, service, t.TempDir())
+
+	// Both files were seen in the same second the scan runs in; only the
+	// seen table tells them apart.
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, path := range []string{"/music/seen.mp3", "/music/gone.mp3"} {
+		if _, err := database.Exec(
+			"INSERT INTO files(path, root_id, size, mtime_ns, file_exists, last_seen_at) VALUES (?, ?, 1, 1, 1, ?)",
+			path,
+			root.ID,
+			now,
+		); err != nil {
+			t.Fatalf("insert file: %v", err)
+		}
+	}
+
+	batch, err := beginScanTx(ctx, database)
+	if err != nil {
+		t.Fatalf("begin scan tx: %v", err)
+	}
+	defer batch.close()
+
+	if err := prepareIncrementalSeenTable(ctx, batch.tx); err != nil {
+		t.Fatalf("prepare seen table: %v", err)
+	}
+	if err := markPathSeenIncremental(ctx, batch.tx, "/music/seen.mp3"); err != nil {
+		t.Fatalf("mark seen: %v", err)
+	}
+
+	changed, err := markUnseenFilesMissing(ctx, batch.tx, root.ID)
+	if err != nil {
+		t.Fatalf("mark unseen files missing: %v", err)
+	}
+	if !changed {
+		t.Fatal("expected a file to be marked missing")
+	}
+	if err := batch.commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	if exists := fileExists(t, database, "/music/seen.mp3"); !exists {
+		t.Fatal("expected the seen file to stay")
+	}
+	if exists := fileExists(t, database, "/music/gone.mp3"); exists {
+		t.Fatal("expected the unseen file to be marked missing")
+	}
+}
+
+func TestFullScanMarksRemovedFilesMissing(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	service, database := newScannerForTest(t)
+	rootPath := writeFakeLibrary(t, 3)
+	addRootForTest(t, service, rootPath)
+
+	if _, err := service.performScan(ctx, scanModeFull); err != nil {
+		t.Fatalf("first scan: %v", err)
+	}
+	removed := filepath.Join(rootPath, "Artist", "Album", "02 - Track.mp3")
+	if err := os.Remove(removed); err != nil {
+		t.Fatalf("remove file: %v", err)
+	}
+	if _, err := service.performScan(ctx, scanModeFull); err != nil {
+		t.Fatalf("second scan: %v", err)
+	}
+
+	if count := countRows(t, database, "SELECT COUNT(*) FROM files WHERE file_exists = 1"); count != 2 {
+		t.Fatalf("expected 2 files left, got %d", count)
+	}
+	if exists := fileExists(t, database, removed); exists {
+		t.Fatal("expected the removed file to be marked missing")
+	}
+}
+
+func TestCanceledScanKeepsBatchesAndRefreshesCatalog(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	service, database := newScannerForTest(t)
+	rootPath := writeFakeLibrary(t, 6)
+	addRootForTest(t, service, rootPath)
+	service.commitInterval = 2
+	service.batchCommitted = cancel
+
+	if _, err := service.performScan(ctx, scanModeFull); err == nil {
+		t.Fatal("expected the canceled scan to fail")
+	}
+
+	if count := countRows(t, database, "SELECT COUNT(*) FROM files WHERE file_exists = 1"); count != 2 {
+		t.Fatalf("expected the first batch of 2 files to stay, got %d", count)
+	}
+	if count := countRows(t, database, "SELECT COUNT(*) FROM albums"); count != 1 {
+		t.Fatalf("expected the album of the kept tracks, got %d albums", count)
+	}
+	if count := countRows(t, database, "SELECT COUNT(*) FROM artists"); count != 1 {
+		t.Fatalf("expected the artist of the kept tracks, got %d artists", count)
+	}
+}
+
+func TestFailedScanWithoutCommitsLeavesLibraryAlone(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	service, database := newScannerForTest(t)
+	addRootForTest(t, service, writeFakeLibrary(t, 3))
+	cancel()
+
+	if _, err := service.performScan(ctx, scanModeFull); err == nil {
+		t.Fatal("expected the canceled scan to fail")
+	}
+	if count := countRows(t, database, "SELECT COUNT(*) FROM files"); count != 0 {
+		t.Fatalf("expected no files, got %d", count)
+	}
+}
+
+func newScannerForTest(t *testing.T) (*Service, *sql.DB) {
+	t.Helper()
+
+	database, err := db.Bootstrap(filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatalf("bootstrap scanner test database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	return NewService(database, library.NewWatchedRootRepository(database), "", nil), database
+}
+
+func addRootForTest(t *testing.T, service *Service, rootPath string) library.WatchedRoot {
+	t.Helper()
+
+	root, err := service.roots.Add(context.Background(), rootPath)
+	if err != nil {
+		t.Fatalf("add root: %v", err)
+	}
+	return root
+}
+
+// writeFakeLibrary writes count unreadable "audio" files into one album
+// folder; the scanner indexes them by their paths.
+func writeFakeLibrary(t *testing.T, count int) string {
+	t.Helper()
+
+	rootPath := t.TempDir()
+	albumPath := filepath.Join(rootPath, "Artist", "Album")
+	if err := os.MkdirAll(albumPath, 0o755); err != nil {
+		t.Fatalf("create album folder: %v", err)
+	}
+	for index := 1; index <= count; index++ {
+		path := filepath.Join(albumPath, fmt.Sprintf("%02d - Track.mp3", index))
+		if err := os.WriteFile(path, []byte("not audio"), 0o644); err != nil {
+			t.Fatalf("write fake track: %v", err)
+		}
+	}
+
+	return rootPath
+}
+
+func countRows(t *testing.T, database *sql.DB, query string) int {
+	t.Helper()
+
+	var count int
+	if err := database.QueryRow(query).Scan(&count); err != nil {
+		t.Fatalf("count rows: %v", err)
+	}
+	return count
+}
+
+func fileExists(t *testing.T, database *sql.DB, path string) bool {
+	t.Helper()
+
+	var exists bool
+	if err := database.QueryRow("SELECT file_exists FROM files WHERE path = ?", path).Scan(&exists); err != nil {
+		t.Fatalf("read file %s: %v", path, err)
+	}
+	return exists
+}
+
+func TestIncrementalScanCommitsInBatches(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	service, database := newScannerForTest(t)
+	rootPath := writeFakeLibrary(t, 5)
+	addRootForTest(t, service, rootPath)
+	service.commitInterval = 2
+	commits := 0
+	service.batchCommitted = func() { commits++ }
+
+	service.markDirtyPath(filepath.Join(rootPath, "Artist"))
+	if _, err := service.performScan(ctx, scanModeIncremental); err != nil {
+		t.Fatalf("incremental scan: %v", err)
+	}
+
+	if commits != 2 {
+		t.Fatalf("expected 2 batch commits, got %d", commits)
+	}
+	if count := countRows(t, database, "SELECT COUNT(*) FROM files WHERE file_exists = 1"); count != 5 {
+		t.Fatalf("expected 5 files, got %d", count)
+	}
+}
@@ -0,0 +1,326 @@
+package scanner
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ScanWorkersSettingKey sets how many files a scan reads tags and artwork
+// from at once. 0 picks one worker per CPU, up to maxAutoScanWorkers.
+const ScanWorkersSettingKey = "library.scan_workers"
+
+const (
+	maxScanWorkers     = 32
+	maxAutoScanWorkers = 8
+	// scanQueuePerWorker bounds the files walked ahead of the database
+	// writes, per worker.
+	scanQueuePerWorker = 16
+)
+
+// ScanWorkers returns the configured number of scan workers, 0 for
+// automatic.
+func (s *Service) ScanWorkers(ctx context.Context) int {
+	workers, err := strconv.Atoi(s.settings.GetString(ctx, ScanWorkersSettingKey, "0"))
+	if err != nil {
+		return 0
+	}
+	return min(max(workers, 0), maxScanWorkers)
+}
+
+// SetScanWorkers stores the number of scan workers, clamped to 0 through
+// 32, and returns the value in effect. Running scans keep their workers.
+func (s *Service) SetScanWorkers(ctx context.Context, workers int) (int, error) {
+	workers = min(max(workers, 0), maxScanWorkers)
+	if err := s.settings.Set(ctx, ScanWorkersSettingKey, strconv.Itoa(workers)); err != nil {
+		return s.ScanWorkers(ctx), err
+	}
+
+	return workers, nil
+}
+
+func (s *Service) scanWorkerCount(ctx context.Context) int {
+	if workers := s.ScanWorkers(ctx); workers > 0 {
+		return workers
+	}
+	return min(runtime.NumCPU(), maxAutoScanWorkers)
+}
+
+// preparedFile is what a scan worker read from a file before its database
+// row is written. Either part is nil when the worker did not need it.
+type preparedFile struct {
+	metadata *extractedMetadata
+	cover    *preparedCover
+}
+
+// scanJob is a walked file on its way through the workers to the writer.
+// done is closed once the file is prepared.
+type scanJob struct {
+	path     string
+	archive  bool
+	info     fs.FileInfo
+	prepared *preparedFile
+	err      error
+	done     chan struct{}
+}
+
+// knownFile is what the library holds for a file, enough for a worker to
+// tell whether the writer will read its tags.
+type knownFile struct {
+	size            int64
+	mtimeNS         int64
+	metadataCurrent bool
+}
+
+// loadKnownFiles returns the files of a root by path.
+func loadKnownFiles(ctx context.Context, tx *sql.Tx, rootID int64) (map[string]knownFile, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			f.path,
+			f.size,
+			f.mtime_ns,
+			COALESCE(INSTR(t.tags_json, ?) > 0, 0)
+		FROM files f
+		LEFT JOIN tracks t ON t.file_id = f.id
+		WHERE f.root_id = ?
+	`, fmt.Sprintf(`"metadata_version":%d`, metadataVersion), rootID)
+	if err != nil {
+		return nil, fmt.Errorf("list known files: %w", err)
+	}
+	defer rows.Close()
+
+	known := make(map[string]knownFile)
+	for rows.Next() {
+		var path string
+		var file knownFile
+		if scanErr := rows.Scan(&path, &file.size, &file.mtimeNS, &file.metadataCurrent); scanErr != nil {
+			return nil, fmt.Errorf("scan known file: %w", scanErr)
+		}
+		known[path] = file
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate known files: %w", rowsErr)
+	}
+
+	return known, nil
+}
+
+// needsMetadata predicts whether upsertFileAndTrack will read the tags of a
+// file, as it does for new, changed and outdated files and in repair
+// scans. Tag region changes are only found by the writer, which then reads
+// the tags itself.
+func needsMetadata(known map[string]knownFile, path string, info fs.FileInfo, mode scanMode) bool {
+	if mode == scanModeRepair {
+		return true
+	}
+
+	file, ok := known[path]
+	return !ok ||
+		file.size != info.Size() ||
+		file.mtimeNS != info.ModTime().UnixNano() ||
+		!file.metadataCurrent
+}
+
+// coverDerivatives are computed from the cached image of a cover, so they
+// are the same for every file with that artwork.
+type coverDerivatives struct {
+	dominantColor  any
+	blurHash       any
+	perceptualHash any
+}
+
+// knownCovers holds the derivatives of covers by image hash, loaded from
+// the library and filled in by the workers of a walk, so the tracks of an
+// album decode their shared artwork once rather than once per track. Its
+// methods do nothing on a nil receiver.
+type knownCovers struct {
+	mu     sync.Mutex
+	byHash map[string]coverDerivatives
+}
+
+// loadKnownCovers returns the covers whose placeholder and perceptual hash
+// are already stored.
+func loadKnownCovers(ctx context.Context, tx *sql.Tx) (*knownCovers, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT LOWER(hash), dominant_color, blurhash, phash
+		FROM covers
+		WHERE hash IS NOT NULL
+		  AND blurhash IS NOT NULL
+		  AND phash IS NOT NULL
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list known covers: %w", err)
+	}
+	defer rows.Close()
+
+	covers := &knownCovers{byHash: make(map[string]coverDerivatives)}
+	for rows.Next() {
+		var hash string
+		var derived coverDerivatives
+		if scanErr := rows.Scan(&hash, &derived.dominantColor, &derived.blurHash, &derived.perceptualHash); scanErr != nil {
+			return nil, fmt.Errorf("scan known cover: %w", scanErr)
+		}
+		covers.byHash[hash] = derived
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate known covers: %w", rowsErr)
+	}
+
+	return covers, nil
+}
+
+func (k *knownCovers) get(hash string) (coverDerivatives, bool) {
+	if k == nil {
+		return coverDerivatives{}, false
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	derived, ok := k.byHash[hash]
+	return derived, ok
+}
+
+func (k *knownCovers) put(hash string, derived coverDerivatives) {
+	if k == nil || derived.blurHash == nil || derived.perceptualHash == nil {
+		return
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.byHash[hash] = derived
+}
+
+// prepareFile reads the tags and artwork the writer will need for a file,
+// without the database. Workers call it concurrently: taglib runs every
+// call in its own WebAssembly module instance and is safe for concurrent
+// use.
+func prepareFile(rootPath string, path string, coverCacheDir string, covers *knownCovers) (*preparedFile, error) {
+	metadata, err := deriveMetadata(rootPath, path)
+	if err != nil {
+		return nil, err
+	}
+
+	prepared := &preparedFile{metadata: &metadata}
+	if strings.TrimSpace(coverCacheDir) != "" {
+		cover, err := prepareCover(path, coverCacheDir, covers)
+		if err != nil {
+			return nil, err
+		}
+		prepared.cover = cover
+	}
+
+	return prepared, nil
+}
+
+// scanPipeline runs the workers of one root walk. Jobs reach the writer in
+// walk order through ordered; each is prepared by whichever worker picks it
+// up from work.
+type scanPipeline struct {
+	ctx     context.Context
+	work    chan *scanJob
+	ordered chan *scanJob
+	workers sync.WaitGroup
+}
+
+func newScanPipeline(ctx context.Context, workers int, prepare func(job *scanJob)) *scanPipeline {
+	workers = max(workers, 1)
+	pipeline := &scanPipeline{
+		ctx:     ctx,
+		work:    make(chan *scanJob),
+		ordered: make(chan *scanJob, workers*scanQueuePerWorker),
+	}
+
+	for range workers {
+		pipeline.workers.Add(1)
+		go func() {
+			defer pipeline.workers.Done()
+			for job := range pipeline.work {
+				if ctx.Err() == nil {
+					prepare(job)
+				}
+				close(job.done)
+			}
+		}()
+	}
+
+	return pipeline
+}
+
+// submit queues a job for the writer and, unless it needs no preparation,
+// for the workers. It returns false once ctx is done.
+func (p *scanPipeline) submit(job *scanJob, prepare bool) bool {
+	job.done = make(chan struct{})
+	select {
+	case p.ordered <- job:
+	case <-p.ctx.Done():
+		return false
+	}
+
+	if !prepare {
+		close(job.done)
+		return true
+	}
+	select {
+	case p.work <- job:
+		return true
+	case <-p.ctx.Done():
+		close(job.done)
+		return false
+	}
+}
+
+// close ends the walk; the writer drains the remaining jobs.
+func (p *scanPipeline) close() {
+	close(p.work)
+	close(p.ordered)
+}
+
+// wait returns once every worker has stopped.
+func (p *scanPipeline) wait() {
+	p.workers.Wait()
+}
+
+// keyedMutex locks by key, so unrelated keys do not wait on each other.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// thumbnailLocks serializes writing the thumbnails of a cover, which the
+// tracks of an album share, so parallel scan workers encode them once.
+var thumbnailLocks keyedMutex
+
+func (k *keyedMutex) lock(key string) func() {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*keyedLock)
+	}
+	entry, ok := k.locks[key]
+	if !ok {
+		entry = &keyedLock{}
+		k.locks[key] = entry
+	}
+	entry.refs++
+	k.mu.Unlock()
+
+	entry.mu.Lock()
+	return func() {
+		entry.mu.Unlock()
+		k.mu.Lock()
+		entry.refs--
+		if entry.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
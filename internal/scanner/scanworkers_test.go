@@ -0,0 +1,227 @@
+package scanner
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScanPipelineKeepsWalkOrder(t *testing.T) {
+	t.Parallel()
+
+	// Later jobs finish first, so the order can only come from the
+	// pipeline.
+	const jobs = 40
+	pipeline := newScanPipeline(context.Background(), 4, func(job *scanJob) {
+		time.Sleep(time.Duration(jobs-len(job.path)) * 50 * time.Microsecond)
+		job.prepared = &preparedFile{}
+	})
+
+	go func() {
+		defer pipeline.close()
+		for index := range jobs {
+			path := string(make([]byte, index+1))
+			if !pipeline.submit(&scanJob{path: path}, index%3 != 0) {
+				return
+			}
+		}
+	}()
+
+	next := 1
+	for job := range pipeline.ordered {
+		<-job.done
+		if len(job.path) != next {
+			t.Fatalf("expected job %d, got %d", next, len(job.path))
+		}
+		if prepared := job.prepared != nil; prepared != ((next-1)%3 != 0) {
+			t.Fatalf("job %d prepared %v", next, prepared)
+		}
+		next++
+	}
+	pipeline.wait()
+
+	if next != jobs+1 {
+		t.Fatalf("expected %d jobs, got %d", jobs, next-1)
+	}
+}
+
+func TestScanPipelineStopsOnCancel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var prepared atomic.Int32
+	pipeline := newScanPipeline(ctx, 2, func(job *scanJob) {
+		prepared.Add(1)
+	})
+
+	walked := make(chan int, 1)
+	go func() {
+		defer pipeline.close()
+		count := 0
+		for {
+			if !pipeline.submit(&scanJob{path: "track.mp3"}, true) {
+				walked <- count
+				return
+			}
+			count++
+			if count == 5 {
+				cancel()
+			}
+		}
+	}()
+
+	// The writer drains what was queued before the cancel.
+	for job := range pipeline.ordered {
+		<-job.done
+	}
+	pipeline.wait()
+
+	if count := <-walked; count > 5+2*scanQueuePerWorker {
+		t.Fatalf("expected the walk to stop after the cancel, it queued %d jobs", count)
+	}
+	if prepared.Load() > 5+2 {
+		t.Fatalf("expected workers to skip canceled jobs, %d were prepared", prepared.Load())
+	}
+}
+
+func TestScanRootIndexesFilesInWalkOrder(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	service, database := newScannerForTest(t)
+	root := addRootForTest(t, service, writeFakeLibrary(t, 20))
+
+	batch, err := beginScanTx(ctx, database)
+	if err != nil {
+		t.Fatalf("begin scan tx: %v", err)
+	}
+	defer batch.close()
+	if err := prepareIncrementalSeenTable(ctx, batch.tx); err != nil {
+		t.Fatalf("prepare seen table: %v", err)
+	}
+
+	totals, err := scanRoot(ctx, batch, root, scanModeFull, "", false, false, 4, nil)
+	if err != nil {
+		t.Fatalf("scan root: %v", err)
+	}
+	if totals.filesSeen != 20 || totals.indexed != 20 {
+		t.Fatalf("unexpected totals %+v", totals)
+	}
+	if err := batch.commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	rows, err := database.Query("SELECT path FROM files ORDER BY id")
+	if err != nil {
+		t.Fatalf("list files: %v", err)
+	}
+	defer rows.Close()
+
+	previous := ""
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			t.Fatalf("scan path: %v", err)
+		}
+		if path <= previous {
+			t.Fatalf("expected walk order, %s came after %s", path, previous)
+		}
+		previous = path
+	}
+}
+
+// Not parallel: it counts the goroutines of the whole test binary.
+func TestScanRootStopsWorkersWhenWriterFails(t *testing.T) {
+	ctx := context.Background()
+	service, database := newScannerForTest(t)
+	rootPath := writeFakeLibrary(t, 30)
+	writeCoverImage(t, filepath.Join(rootPath, "Artist", "Album", "cover.png"))
+	root := addRootForTest(t, service, rootPath)
+
+	// A cover cache below a regular file cannot be created, so preparing
+	// the first file fails.
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatalf("write blocker: %v", err)
+	}
+
+	batch, err := beginScanTx(ctx, database)
+	if err != nil {
+		t.Fatalf("begin scan tx: %v", err)
+	}
+	defer batch.close()
+	if err := prepareIncrementalSeenTable(ctx, batch.tx); err != nil {
+		t.Fatalf("prepare seen table: %v", err)
+	}
+
+	before := runtime.NumGoroutine()
+	if _, err := scanRoot(ctx, batch, root, scanModeFull, filepath.Join(blocker, "covers"), false, false, 4, nil); err == nil {
+		t.Fatal("expected the scan to fail")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Fatalf("expected the walker and workers to stop, %d goroutines left over %d", after, before)
+	}
+}
+
+func TestKnownCoversReusesDerivatives(t *testing.T) {
+	t.Parallel()
+
+	albumPath := t.TempDir()
+	writeCoverImage(t, filepath.Join(albumPath, "cover.png"))
+	trackPath := filepath.Join(albumPath, "01 - Track.mp3")
+	if err := os.WriteFile(trackPath, []byte("not audio"), 0o644); err != nil {
+		t.Fatalf("write track: %v", err)
+	}
+	cacheDir := t.TempDir()
+
+	covers := &knownCovers{byHash: make(map[string]coverDerivatives)}
+	first, err := prepareCover(trackPath, cacheDir, covers)
+	if err != nil {
+		t.Fatalf("prepare cover: %v", err)
+	}
+	if first.candidate == nil || first.blurHash == nil || first.perceptualHash == nil {
+		t.Fatalf("expected a cover with derivatives, got %+v", first)
+	}
+
+	stored := coverDerivatives{dominantColor: "#010203", blurHash: "stored", perceptualHash: "0000000000000000"}
+	covers.put(first.hash, stored)
+	second, err := prepareCover(trackPath, cacheDir, covers)
+	if err != nil {
+		t.Fatalf("prepare cover again: %v", err)
+	}
+	if second.blurHash != "stored" || second.dominantColor != "#010203" {
+		t.Fatalf("expected the known derivatives to be reused, got %+v", second)
+	}
+}
+
+func writeCoverImage(t *testing.T, path string) {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := range 64 {
+		for x := range 64 {
+			img.Set(x, y, color.NRGBA{R: uint8(x * 4), G: uint8(y * 4), B: 128, A: 255})
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create cover: %v", err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, img); err != nil {
+		t.Fatalf("encode cover: %v", err)
+	}
+}
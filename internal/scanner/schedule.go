@@ -0,0 +1,229 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+// scheduleRecheckInterval bounds how long the scheduler sleeps, so clock
+// and time zone changes are noticed.
+const scheduleRecheckInterval = time.Hour
+
+// scheduleState is the scheduler of automatic root scans. startupPending
+// holds the roots whose startup scan waits for their quiet hours to end.
+type scheduleState struct {
+	running        bool
+	stop           chan struct{}
+	wake           chan struct{}
+	startedAt      time.Time
+	startupPending map[int64]struct{}
+}
+
+// StartScheduler starts the automatic scans of the watched roots: the
+// startup scans right away, unless in quiet hours, and the interval
+// rescans when due. The scans are ordinary incremental scans of the roots
+// and report progress as usual.
+func (s *Service) StartScheduler() error {
+	roots, err := s.listRoots(context.Background())
+	if err != nil {
+		return fmt.Errorf("list watched roots for scheduler: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.schedule.running || s.shuttingDown {
+		return nil
+	}
+
+	pending := make(map[int64]struct{})
+	for _, root := range roots {
+		if root.Enabled && root.Schedule.ScanOnStartup {
+			pending[root.ID] = struct{}{}
+		}
+	}
+
+	stop := make(chan struct{})
+	wake := make(chan struct{}, 1)
+	s.schedule = scheduleState{
+		running:        true,
+		stop:           stop,
+		wake:           wake,
+		startedAt:      time.Now(),
+		startupPending: pending,
+	}
+	go s.scheduleLoop(stop, wake)
+
+	return nil
+}
+
+// StopScheduler stops the automatic scans; a scan already queued still
+// runs.
+func (s *Service) StopScheduler() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.schedule.running {
+		return
+	}
+
+	close(s.schedule.stop)
+	s.schedule = scheduleState{}
+}
+
+// NotifyRootSchedulesChanged makes the scheduler recompute when the roots
+// are due.
+func (s *Service) NotifyRootSchedulesChanged() {
+	s.mu.Lock()
+	wake := s.schedule.wake
+	s.mu.Unlock()
+
+	if wake == nil {
+		return
+	}
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Service) scheduleLoop(stop <-chan struct{}, wake <-chan struct{}) {
+	for {
+		wait := scheduleRecheckInterval
+		next, err := s.runDueScans(context.Background(), time.Now())
+		if err != nil {
+			log.Printf("scan scheduler: %v", err)
+		} else if !next.IsZero() {
+			wait = min(max(time.Until(next), time.Second), scheduleRecheckInterval)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// runDueScans queues an incremental scan of the roots due at now and
+// returns when the next root is due, zero if none is.
+func (s *Service) runDueScans(ctx context.Context, now time.Time) (time.Time, error) {
+	roots, err := s.listRoots(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("list watched roots: %w", err)
+	}
+
+	s.mu.Lock()
+	if !s.schedule.running {
+		s.mu.Unlock()
+		return time.Time{}, nil
+	}
+	startedAt := s.schedule.startedAt
+	pending := make(map[int64]struct{}, len(s.schedule.startupPending))
+	for id := range s.schedule.startupPending {
+		pending[id] = struct{}{}
+	}
+	s.mu.Unlock()
+
+	var next time.Time
+	queued := false
+	for _, root := range roots {
+		if !root.Enabled {
+			continue
+		}
+		_, startup := pending[root.ID]
+
+		due, ok := rootScanDue(root, startup, startedAt)
+		if !ok {
+			continue
+		}
+		due = afterQuietHours(root.Schedule, due)
+		if due.After(now) {
+			if next.IsZero() || due.Before(next) {
+				next = due
+			}
+			continue
+		}
+
+		s.markRootDirty(root)
+		queued = true
+		if err := s.roots.MarkScheduledScan(ctx, root.ID, now.UTC().Format(time.RFC3339)); err != nil {
+			return time.Time{}, err
+		}
+		s.mu.Lock()
+		delete(s.schedule.startupPending, root.ID)
+		s.mu.Unlock()
+
+		if root.Schedule.RescanHours > 0 {
+			due := afterQuietHours(root.Schedule, now.Add(time.Duration(root.Schedule.RescanHours)*time.Hour))
+			if next.IsZero() || due.Before(next) {
+				next = due
+			}
+		}
+	}
+
+	if queued {
+		s.mu.Lock()
+		s.queueScanLocked(scanModeIncremental)
+		s.mu.Unlock()
+	}
+
+	return next, nil
+}
+
+// rootScanDue returns when a root is next due, ignoring quiet hours: now
+// for a pending startup scan, otherwise its interval after its last
+// scheduled scan or, before the first one, after the scheduler started.
+func rootScanDue(root library.WatchedRoot, startup bool, startedAt time.Time) (time.Time, bool) {
+	if startup {
+		return startedAt, true
+	}
+	if root.Schedule.RescanHours <= 0 {
+		return time.Time{}, false
+	}
+
+	last := startedAt
+	if root.LastScheduledScanAt != nil {
+		if parsed, err := time.Parse(time.RFC3339, *root.LastScheduledScanAt); err == nil {
+			last = parsed
+		}
+	}
+
+	return last.Add(time.Duration(root.Schedule.RescanHours) * time.Hour), true
+}
+
+// inQuietHours reports whether the local hour of at is in the quiet hours
+// of a schedule.
+func inQuietHours(schedule library.RootSchedule, at time.Time) bool {
+	start, end := schedule.QuietHoursStart, schedule.QuietHoursEnd
+	if start == end {
+		return false
+	}
+
+	hour := at.Local().Hour()
+	if start < end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
+
+// afterQuietHours moves at to the end of the quiet hours it falls in.
+func afterQuietHours(schedule library.RootSchedule, at time.Time) time.Time {
+	if !inQuietHours(schedule, at) {
+		return at
+	}
+
+	local := at.Local()
+	end := time.Date(local.Year(), local.Month(), local.Day(), schedule.QuietHoursEnd, 0, 0, 0, local.Location())
+	if !end.After(local) {
+		end = end.AddDate(0, 0, 1)
+	}
+
+	return end
+}
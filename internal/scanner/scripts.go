@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+// detectTrackScripts fills in the script, language and search text of
+// tracks indexed before detection existed. Tracks written by a scan get
+// them on upsert, so after the first pass this finds nothing.
+func detectTrackScripts(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, COALESCE(title, ''), COALESCE(artist, ''), COALESCE(album, '')
+		FROM tracks
+		WHERE script IS NULL
+	`)
+	if err != nil {
+		return fmt.Errorf("query tracks for script detection: %w", err)
+	}
+
+	type detected struct {
+		trackID    int64
+		script     string
+		language   string
+		searchText string
+	}
+
+	pending := make([]detected, 0)
+	for rows.Next() {
+		var trackID int64
+		var title, artist, album string
+		if scanErr := rows.Scan(&trackID, &title, &artist, &album); scanErr != nil {
+			rows.Close()
+			return fmt.Errorf("scan track for script detection: %w", scanErr)
+		}
+		script, language := library.DetectScript(title, artist, album)
+		pending = append(pending, detected{
+			trackID:    trackID,
+			script:     script,
+			language:   language,
+			searchText: library.SearchText(title, artist, album),
+		})
+	}
+	rowsErr := rows.Err()
+	rows.Close()
+	if rowsErr != nil {
+		return fmt.Errorf("iterate tracks for script detection: %w", rowsErr)
+	}
+
+	for _, item := range pending {
+		if _, err := tx.ExecContext(
+			ctx,
+			"UPDATE tracks SET script = ?, language = ?, search_text = ? WHERE id = ?",
+			item.script,
+			item.language,
+			item.searchText,
+			item.trackID,
+		); err != nil {
+			return fmt.Errorf("store script of track %d: %w", item.trackID, err)
+		}
+	}
+
+	return nil
+}
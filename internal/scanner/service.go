@@ -1,8 +1,6 @@
 package scanner
 
 import (
-	"ben/internal/coverart"
-	"ben/internal/library"
 	"bytes"
 	"context"
 	"crypto/sha256"
@@ -30,14 +28,25 @@ import (
 	"go.senan.xyz/taglib"
 	_ "image/jpeg"
 	_ "image/png"
+
+	"github.com/rzxx/ben/internal/archive"
+	"github.com/rzxx/ben/internal/coverart"
+	"github.com/rzxx/ben/internal/eventbus"
+	"github.com/rzxx/ben/internal/i18n"
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/metadata"
+	"github.com/rzxx/ben/internal/remote"
+	"github.com/rzxx/ben/internal/settings"
 )
 
 const EventProgress = "scanner:progress"
 
-const metadataVersion = 2
+const metadataVersion = 6
 
 const watcherDebounceDelay = 1200 * time.Millisecond
 
+const scanShutdownPollInterval = 25 * time.Millisecond
+
 type scanMode string
 
 const (
@@ -59,6 +68,7 @@ var supportedExtensions = map[string]struct{}{
 	".alac": {},
 	".flac": {},
 	".m4a":  {},
+	".m4b":  {},
 	".mp3":  {},
 	".ogg":  {},
 	".opus": {},
@@ -66,6 +76,16 @@ var supportedExtensions = map[string]struct{}{
 	".wma":  {},
 }
 
+// supportedVideoExtensions are containers indexed for their audio track
+// only; the backend never opens a video output.
+var supportedVideoExtensions = map[string]struct{}{
+	".m4v":  {},
+	".mkv":  {},
+	".mov":  {},
+	".mp4":  {},
+	".webm": {},
+}
+
 var supportedArtworkExtensions = map[string]struct{}{
 	".jpg":  {},
 	".jpeg": {},
@@ -80,23 +100,44 @@ func isSupportedAudioExtension(extension string) bool {
 	return ok
 }
 
+func isSupportedVideoExtension(extension string) bool {
+	_, ok := supportedVideoExtensions[strings.ToLower(strings.TrimSpace(extension))]
+	return ok
+}
+
+// isPlayableExtension reports whether files with the extension are indexed
+// as tracks, either audio files or video containers played as audio.
+func isPlayableExtension(extension string) bool {
+	return isSupportedAudioExtension(extension) || isSupportedVideoExtension(extension)
+}
+
 func isSupportedArtworkExtension(extension string) bool {
 	_, ok := supportedArtworkExtensions[strings.ToLower(strings.TrimSpace(extension))]
 	return ok
 }
 
+// Progress is the payload of EventProgress. While local folders are
+// scanned it also carries per-root counts, the file being processed, the
+// throughput in files per second and the estimated seconds remaining.
 type Progress struct {
-	Phase   string `json:"phase"`
-	Message string `json:"message"`
-	Percent int    `json:"percent"`
-	Status  string `json:"status"`
-	At      string `json:"at"`
-}
-
+	Phase          string         `json:"phase"`
+	Message        string         `json:"message"`
+	Percent        int            `json:"percent"`
+	Status         string         `json:"status"`
+	At             string         `json:"at"`
+	Roots          []RootProgress `json:"roots,omitempty"`
+	CurrentPath    string         `json:"currentPath,omitempty"`
+	FilesPerSecond float64        `json:"filesPerSecond,omitempty"`
+	ETASeconds     *int           `json:"etaSeconds,omitempty"`
+}
+
+// Status describes the scanner. LastStatus is how the last scan ended:
+// "completed", "failed" or "canceled".
 type Status struct {
 	Running       bool   `json:"running"`
 	LastRunAt     string `json:"lastRunAt"`
 	LastMode      string `json:"lastMode,omitempty"`
+	LastStatus    string `json:"lastStatus,omitempty"`
 	LastError     string `json:"lastError,omitempty"`
 	LastFilesSeen int    `json:"lastFilesSeen"`
 	LastIndexed   int    `json:"lastIndexed"`
@@ -112,6 +153,7 @@ type Service struct {
 	pendingMode   scanMode
 	lastRun       time.Time
 	lastMode      string
+	lastStatus    string
 	lastError     string
 	lastFilesSeen int
 	lastIndexed   int
@@ -119,38 +161,76 @@ type Service struct {
 	emit          Emitter
 	db            *sql.DB
 	roots         *library.WatchedRootRepository
+	credentials   *remote.Credentials
+	settings      *settings.Store
 	coverCacheDir string
 	watcher       *fsnotify.Watcher
 	watching      bool
+	watchErr      string
+	watchErrAt    time.Time
+	scanCancel    context.CancelFunc
+	shuttingDown  bool
 	watchStop     chan struct{}
 	rootsChanged  chan struct{}
 	watchDebounce *time.Timer
 	watchedDirs   map[string]struct{}
 	dirtyPaths    map[string]struct{}
+	remoteDirty   bool
+	translate     Translator
+	genres        *metadata.Service
+	verifying     bool
+	schedule      scheduleState
+	// commitInterval and batchCommitted override the scan batch size and
+	// observe its commits; tests use them, 0 and nil keep the defaults.
+	commitInterval int
+	batchCommitted func()
 }
 
+// Translator formats a progress message from the i18n catalog.
+type Translator func(key string, args ...any) string
+
 type scanTotals struct {
 	filesSeen      int
 	indexed        int
 	skipped        int
 	libraryChanged bool
+	changeScanID   int64
 }
 
-func NewService(database *sql.DB, roots *library.WatchedRootRepository, coverCacheDir string) *Service {
+func NewService(database *sql.DB, roots *library.WatchedRootRepository, coverCacheDir string, bus *eventbus.Bus) *Service {
 	return &Service{
 		db:            database,
+		emit:          bus.Publish,
 		roots:         roots,
+		settings:      settings.NewStore(database),
 		coverCacheDir: coverCacheDir,
 		rootsChanged:  make(chan struct{}, 1),
 		watchedDirs:   make(map[string]struct{}),
 		dirtyPaths:    make(map[string]struct{}),
+		translate:     i18n.NewLocalizer(i18n.DefaultLocale).T,
 	}
 }
 
-func (s *Service) SetEmitter(emitter Emitter) {
+func (s *Service) SetTranslator(translate Translator) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.emit = emitter
+	s.translate = translate
+}
+
+func (s *Service) text(key string, args ...any) string {
+	s.mu.Lock()
+	translate := s.translate
+	s.mu.Unlock()
+
+	return translate(key, args...)
+}
+
+// SetRemoteCredentials sets where the passwords of remote roots are read
+// from when they are scanned.
+func (s *Service) SetRemoteCredentials(credentials *remote.Credentials) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.credentials = credentials
 }
 
 func (s *Service) StartWatching() error {
@@ -163,6 +243,7 @@ func (s *Service) StartWatching() error {
 
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
+		s.recordWatcherError(fmt.Sprintf("create fs watcher: %v", err))
 		return fmt.Errorf("create fs watcher: %w", err)
 	}
 
@@ -182,10 +263,6 @@ func (s *Service) StartWatching() error {
 	s.mu.Unlock()
 
 	go s.watchLoop(watcher, stopCh)
-	s.NotifyWatchedRootsChanged()
-	if err := s.markEnabledRootsDirty(context.Background()); err == nil {
-		s.scheduleWatcherIncrementalScan()
-	}
 
 	return nil
 }
@@ -225,6 +302,8 @@ func (s *Service) StopWatching() error {
 }
 
 func (s *Service) NotifyWatchedRootsChanged() {
+	s.NotifyRootSchedulesChanged()
+
 	s.mu.Lock()
 	watching := s.watching
 	ch := s.rootsChanged
@@ -240,13 +319,31 @@ func (s *Service) NotifyWatchedRootsChanged() {
 	}
 }
 
+// NotifyPathsChanged queues an incremental scan of paths changed outside
+// the file watcher, such as files moved in by the auto-importer.
+func (s *Service) NotifyPathsChanged(paths []string) {
+	for _, path := range paths {
+		s.markDirtyPath(path)
+	}
+
+	s.queueIncrementalScan()
+}
+
 func (s *Service) watchLoop(watcher *fsnotify.Watcher, stopCh <-chan struct{}) {
+	// Only the directories are watched here; the scheduler rescans the roots
+	// on startup as their schedules ask.
+	if err := s.refreshWatcherRoots(watcher); err != nil {
+		s.recordWatcherError(fmt.Sprintf("watcher refresh failed: %v", err))
+		s.queueRecoveryScan(scanModeFull, "watcher", "watch root refresh failed")
+	}
+
 	for {
 		select {
 		case <-stopCh:
 			return
 		case <-s.rootsChanged:
 			if err := s.refreshWatcherRoots(watcher); err != nil {
+				s.recordWatcherError(fmt.Sprintf("watcher refresh failed: %v", err))
 				s.emitProgress(Progress{
 					Phase:   "watcher",
 					Message: fmt.Sprintf("watcher refresh failed: %v", err),
@@ -259,6 +356,7 @@ func (s *Service) watchLoop(watcher *fsnotify.Watcher, stopCh <-chan struct{}) {
 			}
 
 			if err := s.markEnabledRootsDirty(context.Background()); err != nil {
+				s.recordWatcherError(fmt.Sprintf("watch root sync failed: %v", err))
 				s.emitProgress(Progress{
 					Phase:   "watcher",
 					Message: fmt.Sprintf("watch root sync failed: %v", err),
@@ -283,6 +381,7 @@ func (s *Service) watchLoop(watcher *fsnotify.Watcher, stopCh <-chan struct{}) {
 			if !ok {
 				return
 			}
+			s.recordWatcherError(fmt.Sprintf("watcher error: %v", err))
 			s.emitProgress(Progress{
 				Phase:   "watcher",
 				Message: fmt.Sprintf("watcher error: %v", err),
@@ -296,19 +395,19 @@ func (s *Service) watchLoop(watcher *fsnotify.Watcher, stopCh <-chan struct{}) {
 }
 
 func (s *Service) refreshWatcherRoots(watcher *fsnotify.Watcher) error {
-	roots, err := s.roots.List(context.Background())
+	roots, err := s.listRoots(context.Background())
 	if err != nil {
 		return fmt.Errorf("list watched roots for watcher: %w", err)
 	}
 
 	desired := make(map[string]struct{})
 	for _, root := range roots {
-		if !root.Enabled {
+		if !root.Enabled || root.IsRemote() {
 			continue
 		}
 
 		rootPath := filepath.Clean(root.Path)
-		dirs, collectErr := collectWatchDirs(rootPath)
+		dirs, collectErr := collectWatchDirs(rootPath, newIgnoreMatcher(rootPath, root.Rules))
 		if collectErr != nil {
 			continue
 		}
@@ -350,7 +449,7 @@ func (s *Service) refreshWatcherRoots(watcher *fsnotify.Watcher) error {
 	return nil
 }
 
-func collectWatchDirs(rootPath string) ([]string, error) {
+func collectWatchDirs(rootPath string, ignore *ignoreMatcher) ([]string, error) {
 	info, err := os.Stat(rootPath)
 	if err != nil {
 		return nil, err
@@ -367,6 +466,9 @@ func collectWatchDirs(rootPath string) ([]string, error) {
 		if !entry.IsDir() {
 			return nil
 		}
+		if ignore.ignored(path, true) {
+			return filepath.SkipDir
+		}
 
 		dirs = append(dirs, filepath.Clean(path))
 		return nil
@@ -378,6 +480,20 @@ func collectWatchDirs(rootPath string) ([]string, error) {
 	return dirs, nil
 }
 
+// ignoreMatcherFor returns a matcher rooted at the watched root that owns
+// path, so its exclude rules and .benignore files above a newly created
+// directory still apply.
+func (s *Service) ignoreMatcherFor(path string) *ignoreMatcher {
+	roots, err := s.listRoots(context.Background())
+	if err == nil {
+		if root, ok := findOwningRoot(path, sortRootsByDepth(roots)); ok {
+			return newIgnoreMatcher(root.Path, root.Rules)
+		}
+	}
+
+	return newIgnoreMatcher(path, library.RootRules{})
+}
+
 func copyStringSet(input map[string]struct{}) map[string]struct{} {
 	output := make(map[string]struct{}, len(input))
 	for value := range input {
@@ -388,21 +504,33 @@ func copyStringSet(input map[string]struct{}) map[string]struct{} {
 }
 
 func (s *Service) markEnabledRootsDirty(ctx context.Context) error {
-	roots, err := s.roots.List(ctx)
+	roots, err := s.listRoots(ctx)
 	if err != nil {
 		return fmt.Errorf("list watched roots for dirty queue: %w", err)
 	}
 
 	for _, root := range roots {
-		if !root.Enabled {
-			continue
+		if root.Enabled {
+			s.markRootDirty(root)
 		}
-		s.markDirtyPath(root.Path)
 	}
 
 	return nil
 }
 
+// markRootDirty queues a root for the next incremental scan. Remote roots
+// are rescanned together.
+func (s *Service) markRootDirty(root library.WatchedRoot) {
+	if root.IsRemote() {
+		s.mu.Lock()
+		s.remoteDirty = true
+		s.mu.Unlock()
+		return
+	}
+
+	s.markDirtyPath(root.Path)
+}
+
 func (s *Service) markDirtyPath(path string) {
 	cleanPath := filepath.Clean(strings.TrimSpace(path))
 	if cleanPath == "" || cleanPath == "." {
@@ -431,6 +559,15 @@ func (s *Service) consumeDirtyPaths() []string {
 	return compactDirtyPaths(paths)
 }
 
+func (s *Service) consumeRemoteDirty() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dirty := s.remoteDirty
+	s.remoteDirty = false
+	return dirty
+}
+
 func compactDirtyPaths(paths []string) []string {
 	if len(paths) == 0 {
 		return nil
@@ -498,8 +635,24 @@ func isSameOrNestedPath(path string, parent string) bool {
 }
 
 func (s *Service) handleWatcherEvent(watcher *fsnotify.Watcher, event fsnotify.Event) bool {
+	if isIgnoreFile(event.Name) {
+		if err := s.refreshWatcherRoots(watcher); err != nil {
+			s.queueRecoveryScan(scanModeFull, "watcher", "refreshing watched directories failed")
+		}
+		return true
+	}
+
+	// Excluded files never reached the library, so their events are moot.
+	// Files excluded after they were indexed leave it with the rescan that
+	// follows a rule change.
+	info, statErr := os.Stat(event.Name)
+	isDir := statErr == nil && info.IsDir()
+	if s.ignoreMatcherFor(event.Name).ignored(event.Name, isDir) {
+		return false
+	}
+
 	if event.Op&fsnotify.Create != 0 {
-		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+		if isDir {
 			if err := s.addWatchDirTree(watcher, filepath.Clean(event.Name)); err != nil {
 				s.emitProgress(Progress{
 					Phase:   "watcher",
@@ -517,7 +670,7 @@ func (s *Service) handleWatcherEvent(watcher *fsnotify.Watcher, event fsnotify.E
 }
 
 func (s *Service) addWatchDirTree(watcher *fsnotify.Watcher, rootPath string) error {
-	dirs, err := collectWatchDirs(rootPath)
+	dirs, err := collectWatchDirs(rootPath, s.ignoreMatcherFor(rootPath))
 	if err != nil {
 		return err
 	}
@@ -567,6 +720,9 @@ func shouldTriggerIncremental(path string, op fsnotify.Op) bool {
 	if op&(fsnotify.Create|fsnotify.Write) == 0 {
 		return false
 	}
+	if isIgnoreFile(path) {
+		return true
+	}
 
 	info, err := os.Stat(path)
 	if err == nil && info.IsDir() {
@@ -574,7 +730,7 @@ func shouldTriggerIncremental(path string, op fsnotify.Op) bool {
 	}
 
 	extension := strings.ToLower(filepath.Ext(path))
-	if isSupportedAudioExtension(extension) {
+	if isPlayableExtension(extension) || archive.IsArchive(path) {
 		return true
 	}
 
@@ -649,6 +805,9 @@ func (s *Service) shouldQueueRecoveryLocked(mode scanMode) bool {
 }
 
 func (s *Service) queueScanLocked(mode scanMode) {
+	if s.shuttingDown {
+		return
+	}
 	if s.running {
 		s.pendingMode = pickPendingMode(s.pendingMode, mode)
 		return
@@ -683,6 +842,10 @@ func (s *Service) TriggerIncrementalScan() error {
 
 func (s *Service) triggerScan(mode scanMode) error {
 	s.mu.Lock()
+	if s.shuttingDown {
+		s.mu.Unlock()
+		return errors.New("scanner is shutting down")
+	}
 	if s.running {
 		s.mu.Unlock()
 		return errors.New("scan already in progress")
@@ -697,7 +860,56 @@ func (s *Service) startScanLocked(mode scanMode) {
 	s.running = true
 	s.currentMode = mode
 	s.lastError = ""
-	go s.runScan(mode)
+	ctx, cancel := context.WithCancel(context.Background())
+	s.scanCancel = cancel
+	go s.runScan(ctx, mode)
+}
+
+// CancelScan stops the running scan. The files indexed up to the last batch
+// commit stay; the interrupted scan reports the "canceled" status and queued
+// scans are dropped. It reports whether a scan was running.
+func (s *Service) CancelScan() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running || s.scanCancel == nil {
+		return false
+	}
+
+	s.scanCancel()
+	s.pendingMode = ""
+	return true
+}
+
+// Shutdown stops the watcher, cancels the running scan so its open batch
+// rolls back, and waits for it to finish or for ctx to end. No scan starts
+// afterwards.
+func (s *Service) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.shuttingDown = true
+	s.pendingMode = ""
+	s.mu.Unlock()
+
+	stopErr := s.StopWatching()
+	s.StopScheduler()
+	s.CancelScan()
+
+	ticker := time.NewTicker(scanShutdownPollInterval)
+	defer ticker.Stop()
+	for {
+		s.mu.Lock()
+		running := s.running
+		s.mu.Unlock()
+		if !running {
+			return stopErr
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait for scan to stop: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
 }
 
 func (s *Service) GetStatus() Status {
@@ -707,6 +919,7 @@ func (s *Service) GetStatus() Status {
 	status := Status{
 		Running:       s.running,
 		LastMode:      s.lastMode,
+		LastStatus:    s.lastStatus,
 		LastError:     s.lastError,
 		LastFilesSeen: s.lastFilesSeen,
 		LastIndexed:   s.lastIndexed,
@@ -719,18 +932,29 @@ func (s *Service) GetStatus() Status {
 	return status
 }
 
-func (s *Service) runScan(mode scanMode) {
-	ctx := context.Background()
+func (s *Service) runScan(ctx context.Context, mode scanMode) {
 	totals, err := s.performScan(ctx, mode)
+	canceled := ctx.Err() != nil
 
 	s.mu.Lock()
 	s.running = false
 	s.currentMode = ""
+	s.scanCancel()
+	s.scanCancel = nil
 	nextMode := s.pendingMode
 	s.pendingMode = ""
-	if err != nil {
+	if canceled {
+		nextMode = ""
+	}
+	switch {
+	case err != nil && canceled:
+		s.lastStatus = "canceled"
+		s.lastError = ""
+	case err != nil:
+		s.lastStatus = "failed"
 		s.lastError = err.Error()
-	} else {
+	default:
+		s.lastStatus = "completed"
 		s.lastError = ""
 		s.lastRun = time.Now().UTC()
 		s.lastMode = string(mode)
@@ -743,6 +967,17 @@ func (s *Service) runScan(mode scanMode) {
 	}
 	s.mu.Unlock()
 
+	if err != nil && canceled {
+		s.emitProgress(Progress{
+			Phase:   "canceled",
+			Message: s.text("scan.canceled"),
+			Percent: 100,
+			Status:  "canceled",
+			At:      time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
 	if err != nil {
 		if mode == scanModeIncremental {
 			s.queueRecoveryScan(scanModeFull, "repair", "incremental scan failed")
@@ -760,9 +995,8 @@ func (s *Service) runScan(mode scanMode) {
 
 	s.emitProgress(Progress{
 		Phase: string(mode),
-		Message: fmt.Sprintf(
-			"%s scan complete: %d files seen, %d indexed, %d skipped",
-			scanModeLabel(mode),
+		Message: s.text(
+			"scan.complete."+string(mode),
 			totals.filesSeen,
 			totals.indexed,
 			totals.skipped,
@@ -784,23 +1018,16 @@ func scanModeLabel(mode scanMode) string {
 	return "Full"
 }
 
-func (s *Service) performScan(ctx context.Context, mode scanMode) (scanTotals, error) {
-	startMessage := "Starting full scan"
-	if mode == scanModeIncremental {
-		startMessage = "Starting incremental scan"
-	} else if mode == scanModeRepair {
-		startMessage = "Starting repair scan"
-	}
-
+func (s *Service) performScan(ctx context.Context, mode scanMode) (_ scanTotals, err error) {
 	s.emitProgress(Progress{
 		Phase:   "start",
-		Message: startMessage,
+		Message: s.text("scan.start." + string(mode)),
 		Percent: 5,
 		Status:  "running",
 		At:      time.Now().UTC().Format(time.RFC3339),
 	})
 
-	roots, err := s.roots.List(ctx)
+	roots, err := s.listRoots(ctx)
 	if err != nil {
 		return scanTotals{}, fmt.Errorf("list watched roots: %w", err)
 	}
@@ -815,7 +1042,7 @@ func (s *Service) performScan(ctx context.Context, mode scanMode) (scanTotals, e
 	if len(enabledRoots) == 0 {
 		s.emitProgress(Progress{
 			Phase:   "done",
-			Message: "No enabled watched folders configured",
+			Message: s.text("scan.noRoots"),
 			Percent: 100,
 			Status:  "completed",
 			At:      time.Now().UTC().Format(time.RFC3339),
@@ -823,24 +1050,38 @@ func (s *Service) performScan(ctx context.Context, mode scanMode) (scanTotals, e
 		return scanTotals{}, nil
 	}
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	ratingPrecedence := s.RatingTagPrecedence(ctx)
+	verifyTags := s.TagVerificationEnabled(ctx)
+	indexArchives := s.ArchiveIndexingEnabled(ctx)
+	fingerprintFilesEnabled := s.FingerprintingEnabled(ctx)
+	scanWorkers := s.scanWorkerCount(ctx)
+
+	batch, err := beginScanTx(ctx, s.db)
 	if err != nil {
-		return scanTotals{}, fmt.Errorf("begin scan tx: %w", err)
+		return scanTotals{}, err
 	}
-
+	if s.commitInterval > 0 {
+		batch.interval = s.commitInterval
+	}
+	batch.onCommit = s.batchCommitted
 	defer func() {
-		if tx != nil {
-			_ = tx.Rollback()
+		batch.close()
+		if err != nil && batch.committed {
+			s.repairPartialScan(ctx)
 		}
 	}()
 
-	if isFullTraversalMode(mode) {
-		if err := markRootsAsMissing(ctx, tx, enabledRoots); err != nil {
-			return scanTotals{}, err
-		}
-	}
+	localRoots, remoteRoots := splitRootsByKind(enabledRoots)
+	scanRemote := s.consumeRemoteDirty() || isFullTraversalMode(mode)
+	cleanupVerifiedRoots := false
 
-	if err := prepareIncrementalSeenTable(ctx, tx); err != nil {
+	// Full scans mark the files they did not see as missing after each
+	// walk, so the batches committed meanwhile never hide a track. What a
+	// walk saw is kept in scan_seen_paths on the scan's connection.
+	if err := prepareIncrementalSeenTable(ctx, batch.tx); err != nil {
+		return scanTotals{}, err
+	}
+	if err := captureLibraryState(ctx, batch.tx); err != nil {
 		return scanTotals{}, err
 	}
 
@@ -854,13 +1095,13 @@ func (s *Service) performScan(ctx context.Context, mode scanMode) (scanTotals, e
 		if len(dirtyPaths) > 0 {
 			s.emitProgress(Progress{
 				Phase:   "scan",
-				Message: fmt.Sprintf("Applying %d filesystem change(s)", len(dirtyPaths)),
+				Message: s.text("scan.applyingChanges", len(dirtyPaths)),
 				Percent: 14,
 				Status:  "running",
 				At:      time.Now().UTC().Format(time.RFC3339),
 			})
 
-			incrementalTotals, scanErr := scanDirtyPathsIncremental(ctx, tx, enabledRoots, dirtyPaths, s.coverCacheDir)
+			incrementalTotals, scanErr := scanDirtyPathsIncremental(ctx, batch, localRoots, dirtyPaths, s.coverCacheDir, verifyTags, indexArchives)
 			if scanErr != nil {
 				return scanTotals{}, scanErr
 			}
@@ -869,23 +1110,19 @@ func (s *Service) performScan(ctx context.Context, mode scanMode) (scanTotals, e
 		} else {
 			s.emitProgress(Progress{
 				Phase:   "scan",
-				Message: "No queued filesystem events, running full incremental verification",
+				Message: s.text("scan.verifying"),
 				Percent: 12,
 				Status:  "running",
 				At:      time.Now().UTC().Format(time.RFC3339),
 			})
 
-			for i, root := range enabledRoots {
-				progress := 14 + ((i * 66) / len(enabledRoots))
-				s.emitProgress(Progress{
-					Phase:   "scan",
-					Message: fmt.Sprintf("Scanning %s", root.Path),
-					Percent: progress,
-					Status:  "running",
-					At:      time.Now().UTC().Format(time.RFC3339),
-				})
+			scanRemote = true
+			progress := s.newScanProgress(ctx, localRoots, indexArchives, 14, 80)
+			for i, root := range localRoots {
+				progress.startRoot(i)
 
-				rootTotals, scanErr := scanRoot(ctx, tx, root, mode, s.coverCacheDir)
+				rootTotals, scanErr := scanRoot(ctx, batch, root, mode, s.coverCacheDir, verifyTags, indexArchives, scanWorkers, progress)
+				progress.finishRoot()
 				totals.filesSeen += rootTotals.filesSeen
 				totals.indexed += rootTotals.indexed
 				totals.skipped += rootTotals.skipped
@@ -894,31 +1131,23 @@ func (s *Service) performScan(ctx context.Context, mode scanMode) (scanTotals, e
 					return scanTotals{}, scanErr
 				}
 
-				filesReconciled, err := reconcileMissingFilesIncremental(ctx, tx, root.ID)
+				filesReconciled, err := markUnseenFilesMissing(ctx, batch.tx, root.ID)
 				if err != nil {
 					return scanTotals{}, err
 				}
 				totals.libraryChanged = totals.libraryChanged || filesReconciled
-
-				tracksCleaned, err := cleanupMissingTracks(ctx, tx, []library.WatchedRoot{root})
-				if err != nil {
-					return scanTotals{}, err
-				}
-				totals.libraryChanged = totals.libraryChanged || tracksCleaned
 			}
+			// Missing tracks are cleaned up once every root was verified,
+			// so a file moved to a later root can still be relinked.
+			cleanupVerifiedRoots = true
 		}
 	} else {
-		for i, root := range enabledRoots {
-			progress := 10 + ((i * 70) / len(enabledRoots))
-			s.emitProgress(Progress{
-				Phase:   "scan",
-				Message: fmt.Sprintf("Scanning %s", root.Path),
-				Percent: progress,
-				Status:  "running",
-				At:      time.Now().UTC().Format(time.RFC3339),
-			})
+		progress := s.newScanProgress(ctx, localRoots, indexArchives, 10, 80)
+		for i, root := range localRoots {
+			progress.startRoot(i)
 
-			rootTotals, scanErr := scanRoot(ctx, tx, root, mode, s.coverCacheDir)
+			rootTotals, scanErr := scanRoot(ctx, batch, root, mode, s.coverCacheDir, verifyTags, indexArchives, scanWorkers, progress)
+			progress.finishRoot()
 			totals.filesSeen += rootTotals.filesSeen
 			totals.indexed += rootTotals.indexed
 			totals.skipped += rootTotals.skipped
@@ -926,57 +1155,119 @@ func (s *Service) performScan(ctx context.Context, mode scanMode) (scanTotals, e
 			if scanErr != nil {
 				return scanTotals{}, scanErr
 			}
+
+			if _, err := markUnseenFilesMissing(ctx, batch.tx, root.ID); err != nil {
+				return scanTotals{}, err
+			}
+		}
+	}
+
+	if scanRemote && len(remoteRoots) > 0 {
+		remoteTotals, err := s.scanRemoteRoots(ctx, batch, remoteRoots, mode)
+		if err != nil {
+			return scanTotals{}, err
+		}
+		totals.filesSeen += remoteTotals.filesSeen
+		totals.indexed += remoteTotals.indexed
+		totals.skipped += remoteTotals.skipped
+		totals.libraryChanged = totals.libraryChanged || remoteTotals.libraryChanged
+	}
+
+	if fingerprintFilesEnabled {
+		s.emitProgress(Progress{
+			Phase:   "fingerprint",
+			Message: s.text("scan.fingerprinting"),
+			Percent: 85,
+			Status:  "running",
+			At:      time.Now().UTC().Format(time.RFC3339),
+		})
+
+		if _, err := fingerprintFiles(ctx, batch.tx, maxFingerprintsPerScan); err != nil {
+			return scanTotals{}, err
 		}
 	}
 
 	s.emitProgress(Progress{
 		Phase:   "cleanup",
-		Message: "Removing stale track entries",
+		Message: s.text("scan.removingStale"),
 		Percent: 90,
 		Status:  "running",
 		At:      time.Now().UTC().Format(time.RFC3339),
 	})
 
 	if isFullTraversalMode(mode) {
-		tracksCleaned, err := cleanupMissingTracks(ctx, tx, enabledRoots)
+		tracksCleaned, err := cleanupMissingTracks(ctx, batch.tx, enabledRoots)
+		if err != nil {
+			return scanTotals{}, err
+		}
+		totals.libraryChanged = totals.libraryChanged || tracksCleaned
+	} else if cleanupVerifiedRoots {
+		tracksCleaned, err := cleanupMissingTracks(ctx, batch.tx, localRoots)
 		if err != nil {
 			return scanTotals{}, err
 		}
 		totals.libraryChanged = totals.libraryChanged || tracksCleaned
 	}
 
-	coversCleaned, err := cleanupMissingCovers(ctx, tx)
+	coversCleaned, err := cleanupMissingCovers(ctx, batch.tx)
 	if err != nil {
 		return scanTotals{}, err
 	}
 	totals.libraryChanged = totals.libraryChanged || coversCleaned
 
+	if err := importTagRatings(ctx, batch.tx, ratingPrecedence); err != nil {
+		return scanTotals{}, err
+	}
+	if err := applyTrackEnrichments(ctx, batch.tx); err != nil {
+		return scanTotals{}, err
+	}
+	if err := detectTrackScripts(ctx, batch.tx); err != nil {
+		return scanTotals{}, err
+	}
+	if err := splitTrackGenres(ctx, batch.tx); err != nil {
+		return scanTotals{}, err
+	}
+
+	if totals.libraryChanged {
+		scanID, err := recordLibraryChanges(ctx, batch.tx, mode)
+		if err != nil {
+			return scanTotals{}, err
+		}
+		totals.changeScanID = scanID
+	}
+
 	if totals.libraryChanged || isFullTraversalMode(mode) {
 		s.emitProgress(Progress{
 			Phase:   "derive",
-			Message: "Refreshing artists, albums, and album track mappings",
+			Message: s.text("scan.refreshingCatalog"),
 			Percent: 96,
 			Status:  "running",
 			At:      time.Now().UTC().Format(time.RFC3339),
 		})
 
-		if err := rebuildDerivedLibrary(ctx, tx); err != nil {
+		if err := rebuildDerivedLibrary(ctx, batch.tx); err != nil {
 			return scanTotals{}, err
 		}
 	} else {
 		s.emitProgress(Progress{
 			Phase:   "derive",
-			Message: "No library changes detected, skipping derived catalog refresh",
+			Message: s.text("scan.noChanges"),
 			Percent: 96,
 			Status:  "running",
 			At:      time.Now().UTC().Format(time.RFC3339),
 		})
 	}
 
-	if err := tx.Commit(); err != nil {
-		return scanTotals{}, fmt.Errorf("commit scan tx: %w", err)
+	if err := batch.commit(); err != nil {
+		return scanTotals{}, err
+	}
+
+	if mode == scanModeIncremental && totals.changeScanID != 0 {
+		s.publishCoverChanges(ctx, totals.changeScanID)
+	}
+	if totals.libraryChanged {
+		s.inferGenres(ctx)
 	}
-	tx = nil
 
 	if cleanupErr := cleanupOrphanedCoverFiles(ctx, s.db, s.coverCacheDir); cleanupErr != nil {
 		s.emitProgress(Progress{
@@ -995,20 +1286,6 @@ func isFullTraversalMode(mode scanMode) bool {
 	return mode == scanModeFull || mode == scanModeRepair
 }
 
-func markRootsAsMissing(ctx context.Context, tx *sql.Tx, roots []library.WatchedRoot) error {
-	for _, root := range roots {
-		if _, err := tx.ExecContext(
-			ctx,
-			"UPDATE files SET file_exists = 0 WHERE root_id = ?",
-			root.ID,
-		); err != nil {
-			return fmt.Errorf("mark files missing for root %d: %w", root.ID, err)
-		}
-	}
-
-	return nil
-}
-
 func prepareIncrementalSeenTable(ctx context.Context, tx *sql.Tx) error {
 	if _, err := tx.ExecContext(
 		ctx,
@@ -1040,7 +1317,11 @@ func markPathSeenIncremental(ctx context.Context, tx *sql.Tx, path string) error
 	return nil
 }
 
-func reconcileMissingFilesIncremental(ctx context.Context, tx *sql.Tx, rootID int64) (bool, error) {
+// markUnseenFilesMissing marks the files of a root that the last walk did
+// not record in scan_seen_paths as missing. Unlike a last_seen_at cutoff it
+// does not depend on clock resolution, so a file seen by an earlier scan in
+// the same second is still marked.
+func markUnseenFilesMissing(ctx context.Context, tx *sql.Tx, rootID int64) (bool, error) {
 	result, err := tx.ExecContext(
 		ctx,
 		`UPDATE files
@@ -1051,12 +1332,12 @@ func reconcileMissingFilesIncremental(ctx context.Context, tx *sql.Tx, rootID in
 		rootID,
 	)
 	if err != nil {
-		return false, fmt.Errorf("reconcile missing files for root %d: %w", rootID, err)
+		return false, fmt.Errorf("mark unseen files missing for root %d: %w", rootID, err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return false, fmt.Errorf("read reconciled missing file count for root %d: %w", rootID, err)
+		return false, fmt.Errorf("read missing file count for root %d: %w", rootID, err)
 	}
 
 	return rowsAffected > 0, nil
@@ -1072,7 +1353,7 @@ func reconcileMissingFilesIncrementalByPrefix(ctx context.Context, tx *sql.Tx, r
 		 SET file_exists = 0
 		 WHERE root_id = ?
 		   AND file_exists = 1
-		   AND (path = ? OR path LIKE ? ESCAPE '\\')
+		   AND (path = ? OR path LIKE ? ESCAPE '\')
 		   AND path NOT IN (SELECT path FROM scan_seen_paths)`,
 		rootID,
 		cleanPrefix,
@@ -1100,7 +1381,7 @@ func markPathMissingIncremental(ctx context.Context, tx *sql.Tx, rootID int64, p
 		 SET file_exists = 0
 		 WHERE root_id = ?
 		   AND file_exists = 1
-		   AND (path = ? OR path LIKE ? ESCAPE '\\')`,
+		   AND (path = ? OR path LIKE ? ESCAPE '\')`,
 		rootID,
 		cleanPath,
 		pattern,
@@ -1161,10 +1442,12 @@ func sortRootsByDepth(roots []library.WatchedRoot) []library.WatchedRoot {
 
 func scanDirtyPathsIncremental(
 	ctx context.Context,
-	tx *sql.Tx,
+	batch *scanTx,
 	enabledRoots []library.WatchedRoot,
 	dirtyPaths []string,
 	coverCacheDir string,
+	verifyTags bool,
+	indexArchives bool,
 ) (scanTotals, error) {
 	rootListByDepth := sortRootsByDepth(enabledRoots)
 	affectedRootIDs := make(map[int64]struct{})
@@ -1182,17 +1465,30 @@ func scanDirtyPathsIncremental(
 		coverRefreshTargets[key] = coverRefreshTarget{rootID: root.ID, directoryPath: cleanDirectory}
 	}
 
+	ignoreMatchers := make(map[int64]*ignoreMatcher)
+
 	for _, dirtyPath := range dirtyPaths {
 		cleanPath := filepath.Clean(dirtyPath)
+		if isIgnoreFile(cleanPath) {
+			cleanPath = filepath.Dir(cleanPath)
+		}
 		root, hasRoot := findOwningRoot(cleanPath, rootListByDepth)
 		if !hasRoot {
 			continue
 		}
+		ignore, ok := ignoreMatchers[root.ID]
+		if !ok {
+			ignore = newIgnoreMatcher(root.Path, root.Rules)
+			ignoreMatchers[root.ID] = ignore
+		}
 
 		info, statErr := os.Stat(cleanPath)
+		if statErr == nil && !info.IsDir() && (ignore.ignored(cleanPath, false) || ignore.tooSmall(info)) {
+			statErr = os.ErrNotExist
+		}
 		if statErr == nil {
 			if info.IsDir() {
-				dirTotals, err := scanIncrementalDirectory(ctx, tx, root, cleanPath, coverCacheDir)
+				dirTotals, err := scanIncrementalDirectory(ctx, batch, root, cleanPath, ignore, coverCacheDir, verifyTags, indexArchives)
 				if err != nil {
 					return scanTotals{}, err
 				}
@@ -1211,12 +1507,36 @@ func scanDirtyPathsIncremental(
 				markCoverRefresh(root, filepath.Dir(cleanPath))
 				continue
 			}
-			if !isSupportedAudioExtension(extension) {
+			if indexArchives && archive.IsArchive(cleanPath) {
+				if err := clearIncrementalSeenTable(ctx, batch.tx); err != nil {
+					return scanTotals{}, err
+				}
+				archiveTotals, err := scanZipArchive(ctx, batch.tx, root, cleanPath, scannedAt, scanModeIncremental, coverCacheDir)
+				if err != nil {
+					return scanTotals{}, err
+				}
+				entriesReconciled, err := reconcileMissingFilesIncrementalByPrefix(ctx, batch.tx, root.ID, cleanPath)
+				if err != nil {
+					return scanTotals{}, err
+				}
+				totals.filesSeen += archiveTotals.filesSeen
+				totals.indexed += archiveTotals.indexed
+				totals.skipped += archiveTotals.skipped
+				if archiveTotals.libraryChanged || entriesReconciled {
+					totals.libraryChanged = true
+					affectedRootIDs[root.ID] = struct{}{}
+				}
+				if err := batch.fileProcessed(ctx); err != nil {
+					return scanTotals{}, err
+				}
+				continue
+			}
+			if !isPlayableExtension(extension) {
 				continue
 			}
 
 			totals.filesSeen++
-			indexed, upsertErr := upsertFileAndTrack(ctx, tx, root.ID, root.Path, cleanPath, info, scannedAt, scanModeIncremental, coverCacheDir)
+			indexed, upsertErr := upsertFileAndTrack(ctx, batch.tx, root.ID, root.Path, cleanPath, info, scannedAt, scanModeIncremental, coverCacheDir, verifyTags, nil)
 			if upsertErr != nil {
 				return scanTotals{}, upsertErr
 			}
@@ -1225,6 +1545,9 @@ func scanDirtyPathsIncremental(
 				totals.libraryChanged = true
 				affectedRootIDs[root.ID] = struct{}{}
 			}
+			if err := batch.fileProcessed(ctx); err != nil {
+				return scanTotals{}, err
+			}
 			continue
 		}
 
@@ -1239,7 +1562,7 @@ func scanDirtyPathsIncremental(
 			continue
 		}
 
-		changed, err := markPathMissingIncremental(ctx, tx, root.ID, cleanPath)
+		changed, err := markPathMissingIncremental(ctx, batch.tx, root.ID, cleanPath)
 		if err != nil {
 			return scanTotals{}, err
 		}
@@ -1250,7 +1573,7 @@ func scanDirtyPathsIncremental(
 	}
 
 	if len(coverRefreshTargets) > 0 {
-		refreshed, changed, err := refreshCoverArtForDirectories(ctx, tx, coverRefreshTargets, coverCacheDir)
+		refreshed, changed, err := refreshCoverArtForDirectories(ctx, batch.tx, coverRefreshTargets, coverCacheDir)
 		if err != nil {
 			return scanTotals{}, err
 		}
@@ -1270,7 +1593,7 @@ func scanDirtyPathsIncremental(
 		affectedRoots = append(affectedRoots, root)
 	}
 
-	tracksCleaned, err := cleanupMissingTracks(ctx, tx, affectedRoots)
+	tracksCleaned, err := cleanupMissingTracks(ctx, batch.tx, affectedRoots)
 	if err != nil {
 		return scanTotals{}, err
 	}
@@ -1322,7 +1645,7 @@ func refreshCoverArtForDirectories(
 			 FROM files
 			 WHERE root_id = ?
 			   AND file_exists = 1
-			   AND (path = ? OR path LIKE ? ESCAPE '\\')`,
+			   AND (path = ? OR path LIKE ? ESCAPE '\')`,
 			target.rootID,
 			target.directoryPath,
 			pattern,
@@ -1343,6 +1666,11 @@ func refreshCoverArtForDirectories(
 				continue
 			}
 			processedFileIDs[fileID] = struct{}{}
+			if archive.IsEntryPath(path) {
+				// Archive covers come from inside the archive and only
+				// change with it.
+				continue
+			}
 
 			coverChanged, coverErr := syncCoverForFile(ctx, tx, fileID, filepath.Clean(path), coverCacheDir, true)
 			if coverErr != nil {
@@ -1369,12 +1697,15 @@ func refreshCoverArtForDirectories(
 
 func scanIncrementalDirectory(
 	ctx context.Context,
-	tx *sql.Tx,
+	batch *scanTx,
 	root library.WatchedRoot,
 	directoryPath string,
+	ignore *ignoreMatcher,
 	coverCacheDir string,
+	verifyTags bool,
+	indexArchives bool,
 ) (scanTotals, error) {
-	if err := clearIncrementalSeenTable(ctx, tx); err != nil {
+	if err := clearIncrementalSeenTable(ctx, batch.tx); err != nil {
 		return scanTotals{}, err
 	}
 
@@ -1388,11 +1719,29 @@ func scanIncrementalDirectory(
 		}
 
 		if entry.IsDir() {
+			if ignore.ignored(path, true) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
+		if ignore.ignored(path, false) {
+			return nil
+		}
+
+		if indexArchives && archive.IsArchive(path) {
+			archiveTotals, archiveErr := scanZipArchive(ctx, batch.tx, root, filepath.Clean(path), scannedAt, scanModeIncremental, coverCacheDir)
+			totals.filesSeen += archiveTotals.filesSeen
+			totals.indexed += archiveTotals.indexed
+			totals.skipped += archiveTotals.skipped
+			totals.libraryChanged = totals.libraryChanged || archiveTotals.libraryChanged
+			if archiveErr != nil {
+				return archiveErr
+			}
+			return batch.fileProcessed(ctx)
+		}
 
 		extension := strings.ToLower(filepath.Ext(path))
-		if !isSupportedAudioExtension(extension) {
+		if !isPlayableExtension(extension) {
 			return nil
 		}
 
@@ -1401,10 +1750,13 @@ func scanIncrementalDirectory(
 			totals.skipped++
 			return nil
 		}
+		if ignore.tooSmall(info) {
+			return nil
+		}
 
 		cleanPath := filepath.Clean(path)
 		totals.filesSeen++
-		indexed, upsertErr := upsertFileAndTrack(ctx, tx, root.ID, root.Path, cleanPath, info, scannedAt, scanModeIncremental, coverCacheDir)
+		indexed, upsertErr := upsertFileAndTrack(ctx, batch.tx, root.ID, root.Path, cleanPath, info, scannedAt, scanModeIncremental, coverCacheDir, verifyTags, nil)
 		if upsertErr != nil {
 			return upsertErr
 		}
@@ -1413,17 +1765,17 @@ func scanIncrementalDirectory(
 			totals.libraryChanged = true
 		}
 
-		if seenErr := markPathSeenIncremental(ctx, tx, cleanPath); seenErr != nil {
+		if seenErr := markPathSeenIncremental(ctx, batch.tx, cleanPath); seenErr != nil {
 			return seenErr
 		}
 
-		return nil
+		return batch.fileProcessed(ctx)
 	})
 	if err != nil {
 		return scanTotals{}, fmt.Errorf("walk incremental directory %s: %w", directoryPath, err)
 	}
 
-	missingReconciled, err := reconcileMissingFilesIncrementalByPrefix(ctx, tx, root.ID, directoryPath)
+	missingReconciled, err := reconcileMissingFilesIncrementalByPrefix(ctx, batch.tx, root.ID, directoryPath)
 	if err != nil {
 		return scanTotals{}, err
 	}
@@ -1436,6 +1788,12 @@ func cleanupMissingTracks(ctx context.Context, tx *sql.Tx, roots []library.Watch
 	changed := false
 
 	for _, root := range roots {
+		relinked, err := relinkMovedTracks(ctx, tx, root.ID)
+		if err != nil {
+			return false, err
+		}
+		changed = changed || relinked
+
 		result, err := tx.ExecContext(
 			ctx,
 			"DELETE FROM tracks WHERE file_id IN (SELECT id FROM files WHERE root_id = ? AND file_exists = 0)",
@@ -1570,7 +1928,41 @@ func cleanupOrphanedCoverFiles(ctx context.Context, database *sql.DB, coverCache
 	return nil
 }
 
+// refreshVideoFlags marks files in video containers by extension, which
+// also covers remote roots whose paths are URLs.
+func refreshVideoFlags(ctx context.Context, tx *sql.Tx) error {
+	extensions := make([]string, 0, len(supportedVideoExtensions))
+	for extension := range supportedVideoExtensions {
+		extensions = append(extensions, extension)
+	}
+	sort.Strings(extensions)
+
+	conditions := make([]string, 0, len(extensions))
+	args := make([]any, 0, len(extensions))
+	for _, extension := range extensions {
+		conditions = append(conditions, "LOWER(path) LIKE ?")
+		args = append(args, "%"+extension)
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		"UPDATE files SET is_video = CASE WHEN "+strings.Join(conditions, " OR ")+" THEN 1 ELSE 0 END",
+		args...,
+	); err != nil {
+		return fmt.Errorf("refresh video flags: %w", err)
+	}
+
+	return nil
+}
+
 func rebuildDerivedLibrary(ctx context.Context, tx *sql.Tx) error {
+	if err := library.RefreshAudiobookFlags(ctx, tx); err != nil {
+		return err
+	}
+	if err := refreshVideoFlags(ctx, tx); err != nil {
+		return err
+	}
+
 	if _, err := tx.ExecContext(ctx, "DELETE FROM album_tracks"); err != nil {
 		return fmt.Errorf("clear album_tracks: %w", err)
 	}
@@ -1584,13 +1976,18 @@ func rebuildDerivedLibrary(ctx context.Context, tx *sql.Tx) error {
 	}
 
 	if _, err := tx.ExecContext(ctx, `
-		INSERT INTO artists(name, sort_name)
-		SELECT artist_name, LOWER(artist_name)
+		INSERT INTO artists(name, sort_name, romanized)
+		SELECT artist_name, LOWER(artist_name), romanized
 		FROM (
-			SELECT DISTINCT COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist') AS artist_name
-			FROM tracks t
+			SELECT
+				MIN(ta.artist) AS artist_name,
+				MAX(ta.romanized) AS romanized
+			FROM track_artists ta
+			JOIN tracks t ON t.id = ta.track_id
 			JOIN files f ON f.id = t.file_id
 			WHERE f.file_exists = 1
+			  AND f.is_audiobook = 0
+			GROUP BY ta.artist
 		) artist_rows
 		ORDER BY LOWER(artist_name)
 	`); err != nil {
@@ -1606,12 +2003,17 @@ func rebuildDerivedLibrary(ctx context.Context, tx *sql.Tx) error {
 				COALESCE(NULLIF(TRIM(t.album_artist), ''), COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')) AS album_artist_name,
 				t.year AS year,
 				t.disc_no AS disc_no,
-				t.track_no AS track_no
+				t.track_no AS track_no,
+				t.album_romanized AS album_romanized,
+				CASE WHEN NULLIF(TRIM(t.album_artist), '') IS NULL THEN t.artist_romanized ELSE t.album_artist_romanized END AS artist_romanized,
+				NULLIF(TRIM(t.composer), '') AS composer,
+				t.catalog_key AS catalog_key
 			FROM tracks t
 			JOIN files f ON f.id = t.file_id
 			WHERE f.file_exists = 1
+			  AND f.is_audiobook = 0
 		)
-		INSERT INTO albums(title, album_artist, year, cover_id, sort_key)
+		INSERT INTO albums(title, album_artist, year, cover_id, sort_key, romanized, artist_romanized, composer, catalog_key)
 		SELECT
 			tr.album_title,
 			tr.album_artist_name,
@@ -1625,7 +2027,20 @@ func rebuildDerivedLibrary(ctx context.Context, tx *sql.Tx) error {
 				ORDER BY COALESCE(tr2.disc_no, 0), COALESCE(tr2.track_no, 0), tr2.track_id
 				LIMIT 1
 			) AS cover_id,
-			LOWER(tr.album_artist_name || ' ' || tr.album_title) AS sort_key
+			LOWER(tr.album_artist_name || ' ' || tr.album_title) AS sort_key,
+			MAX(tr.album_romanized) AS romanized,
+			MAX(tr.artist_romanized) AS artist_romanized,
+			(
+				SELECT tr3.composer
+				FROM track_rows tr3
+				WHERE tr3.album_title = tr.album_title
+				  AND tr3.album_artist_name = tr.album_artist_name
+				  AND tr3.composer IS NOT NULL
+				GROUP BY tr3.composer
+				ORDER BY COUNT(1) DESC, tr3.composer
+				LIMIT 1
+			) AS composer,
+			MIN(tr.catalog_key) AS catalog_key
 		FROM track_rows tr
 		GROUP BY tr.album_title, tr.album_artist_name
 		ORDER BY LOWER(tr.album_artist_name), LOWER(tr.album_title)
@@ -1644,6 +2059,7 @@ func rebuildDerivedLibrary(ctx context.Context, tx *sql.Tx) error {
 			FROM tracks t
 			JOIN files f ON f.id = t.file_id
 			WHERE f.file_exists = 1
+			  AND f.is_audiobook = 0
 		)
 		INSERT INTO album_tracks(album_id, track_id, disc_no, track_no)
 		SELECT
@@ -1680,7 +2096,80 @@ const (
 	coverSourceKindFile     = "file"
 )
 
+// preparedCover is the artwork selected for a file, with its thumbnails
+// cached and its placeholders computed. Preparing it needs no database, so
+// scan workers do it in parallel. A nil candidate means the file has no
+// artwork.
+type preparedCover struct {
+	candidate      *coverCandidate
+	hash           string
+	mimeType       string
+	sourceKind     string
+	sourcePath     string
+	cachePath      string
+	dominantColor  any
+	blurHash       any
+	perceptualHash any
+	thumbnailErr   error
+}
+
+// prepareCover reads the artwork of a file and caches it with its
+// thumbnails. The placeholder and perceptual hash are only computed for
+// images covers does not know yet; a nil covers computes them every time.
+func prepareCover(fullPath string, coverCacheDir string, covers *knownCovers) (*preparedCover, error) {
+	embeddedCandidate := readEmbeddedCoverCandidate(fullPath)
+	sidecarCandidates := readSidecarCoverCandidates(fullPath)
+	selectedCandidate := selectCoverCandidate(embeddedCandidate, sidecarCandidates)
+	if selectedCandidate == nil {
+		return &preparedCover{}, nil
+	}
+
+	hashBytes := sha256.Sum256(selectedCandidate.imageData)
+	hash := hex.EncodeToString(hashBytes[:])
+
+	mimeType := strings.TrimSpace(selectedCandidate.mimeType)
+	if mimeType == "" {
+		mimeType = mimeTypeFromImageFormat(selectedCandidate.format)
+	}
+
+	sourceKind, sourcePath := normalizeCoverSourceReference(selectedCandidate, fullPath)
+
+	cachePath := coverart.VariantPathForHash(coverCacheDir, hash, coverart.VariantDetail)
+
+	if err := os.MkdirAll(coverCacheDir, 0o755); err != nil {
+		return nil, fmt.Errorf("create cover cache dir: %w", err)
+	}
+
+	cover := &preparedCover{
+		candidate:  selectedCandidate,
+		hash:       hash,
+		mimeType:   mimeType,
+		sourceKind: sourceKind,
+		sourcePath: sourcePath,
+		cachePath:  cachePath,
+	}
+	if thumbErr := ensureCoverThumbnails(cachePath, hash, selectedCandidate.imageData); thumbErr != nil {
+		cover.thumbnailErr = thumbErr
+		return cover, nil
+	}
+
+	derived, ok := covers.get(hash)
+	if !ok {
+		derived.dominantColor, derived.blurHash = coverPlaceholder(cachePath)
+		derived.perceptualHash = coverPerceptualHash(cachePath)
+		covers.put(hash, derived)
+	}
+	cover.dominantColor, cover.blurHash, cover.perceptualHash = derived.dominantColor, derived.blurHash, derived.perceptualHash
+	return cover, nil
+}
+
 func syncCoverForFile(ctx context.Context, tx *sql.Tx, fileID int64, fullPath string, coverCacheDir string, force bool) (bool, error) {
+	return syncPreparedCover(ctx, tx, fileID, fullPath, coverCacheDir, force, nil)
+}
+
+// syncPreparedCover is syncCoverForFile with the artwork a scan worker
+// already prepared; with a nil cover it is prepared here when needed.
+func syncPreparedCover(ctx context.Context, tx *sql.Tx, fileID int64, fullPath string, coverCacheDir string, force bool, cover *preparedCover) (bool, error) {
 	if strings.TrimSpace(coverCacheDir) == "" {
 		return false, nil
 	}
@@ -1691,14 +2180,16 @@ func syncCoverForFile(ctx context.Context, tx *sql.Tx, fileID int64, fullPath st
 		existingPath       sql.NullString
 		existingSourceKind sql.NullString
 		existingSourcePath sql.NullString
+		existingBlurHash   sql.NullString
+		existingPHash      sql.NullString
 	)
 
 	existingFound := true
 	err := tx.QueryRowContext(
 		ctx,
-		"SELECT id, hash, cache_path, source_kind, source_path FROM covers WHERE source_file_id = ?",
+		"SELECT id, hash, cache_path, source_kind, source_path, blurhash, phash FROM covers WHERE source_file_id = ?",
 		fileID,
-	).Scan(&existingID, &existingHash, &existingPath, &existingSourceKind, &existingSourcePath)
+	).Scan(&existingID, &existingHash, &existingPath, &existingSourceKind, &existingSourcePath, &existingBlurHash, &existingPHash)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			existingFound = false
@@ -1720,16 +2211,36 @@ func syncCoverForFile(ctx context.Context, tx *sql.Tx, fileID int64, fullPath st
 			hasCoverSourceReference(existingSourceKind.String, existingSourcePath.String) {
 			if _, statErr := os.Stat(existingCachePath); statErr == nil {
 				_ = ensureCoverThumbnailsFromCachePath(existingCachePath)
+				if !existingBlurHash.Valid || !existingPHash.Valid {
+					dominantColor, blurHash := coverPlaceholder(existingCachePath)
+					if _, updateErr := tx.ExecContext(
+						ctx,
+						"UPDATE covers SET dominant_color = ?, blurhash = ?, phash = ? WHERE id = ?",
+						dominantColor,
+						blurHash,
+						coverPerceptualHash(existingCachePath),
+						existingID,
+					); updateErr != nil {
+						return false, fmt.Errorf("update cover placeholder for file %d: %w", fileID, updateErr)
+					}
+				}
 				return false, nil
 			}
 		}
 	}
 
-	embeddedCandidate := readEmbeddedCoverCandidate(fullPath)
-	sidecarCandidates := readSidecarCoverCandidates(fullPath)
-	selectedCandidate := selectCoverCandidate(embeddedCandidate, sidecarCandidates)
+	if cover == nil {
+		prepared, err := prepareCover(fullPath, coverCacheDir, nil)
+		if err != nil {
+			return false, err
+		}
+		cover = prepared
+	}
 
-	if selectedCandidate == nil {
+	if cover.candidate == nil {
+		if existingFound && keepOnlineCover(existingSourceKind) {
+			return false, nil
+		}
 		if existingFound {
 			if _, deleteErr := tx.ExecContext(ctx, "DELETE FROM covers WHERE id = ?", existingID); deleteErr != nil {
 				return false, fmt.Errorf("delete cover row for file %d: %w", fileID, deleteErr)
@@ -1739,27 +2250,17 @@ func syncCoverForFile(ctx context.Context, tx *sql.Tx, fileID int64, fullPath st
 
 		return false, nil
 	}
-
-	hashBytes := sha256.Sum256(selectedCandidate.imageData)
-	hash := hex.EncodeToString(hashBytes[:])
-
-	mimeType := strings.TrimSpace(selectedCandidate.mimeType)
-	if mimeType == "" {
-		mimeType = mimeTypeFromImageFormat(selectedCandidate.format)
-	}
-
-	sourceKind, sourcePath := normalizeCoverSourceReference(selectedCandidate, fullPath)
-
-	cachePath := coverart.VariantPathForHash(coverCacheDir, hash, coverart.VariantDetail)
-
-	if err := os.MkdirAll(coverCacheDir, 0o755); err != nil {
-		return false, fmt.Errorf("create cover cache dir: %w", err)
-	}
-
-	if thumbErr := ensureCoverThumbnails(cachePath, hash, selectedCandidate.imageData); thumbErr != nil {
+	if cover.thumbnailErr != nil {
 		return false, nil
 	}
 
+	selectedCandidate := cover.candidate
+	hash := cover.hash
+	mimeType := cover.mimeType
+	sourceKind, sourcePath := cover.sourceKind, cover.sourcePath
+	cachePath := cover.cachePath
+	dominantColor, blurHash, perceptualHash := cover.dominantColor, cover.blurHash, cover.perceptualHash
+
 	coverChanged := !existingFound
 	if existingFound {
 		previousHash := strings.TrimSpace(existingHash.String)
@@ -1776,7 +2277,7 @@ func syncCoverForFile(ctx context.Context, tx *sql.Tx, fileID int64, fullPath st
 
 		if _, updateErr := tx.ExecContext(
 			ctx,
-			"UPDATE covers SET mime = ?, width = ?, height = ?, cache_path = ?, hash = ?, source_kind = ?, source_path = ? WHERE id = ?",
+			"UPDATE covers SET mime = ?, width = ?, height = ?, cache_path = ?, hash = ?, source_kind = ?, source_path = ?, dominant_color = ?, blurhash = ?, phash = ? WHERE id = ?",
 			nullableString(mimeType),
 			nullablePositiveInt(selectedCandidate.width),
 			nullablePositiveInt(selectedCandidate.height),
@@ -1784,6 +2285,9 @@ func syncCoverForFile(ctx context.Context, tx *sql.Tx, fileID int64, fullPath st
 			hash,
 			nullableString(sourceKind),
 			nullableString(sourcePath),
+			dominantColor,
+			blurHash,
+			perceptualHash,
 			existingID,
 		); updateErr != nil {
 			return false, fmt.Errorf("update cover row for file %d: %w", fileID, updateErr)
@@ -1794,7 +2298,7 @@ func syncCoverForFile(ctx context.Context, tx *sql.Tx, fileID int64, fullPath st
 
 	if _, insertErr := tx.ExecContext(
 		ctx,
-		"INSERT INTO covers(source_file_id, mime, width, height, cache_path, hash, source_kind, source_path) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		"INSERT INTO covers(source_file_id, mime, width, height, cache_path, hash, source_kind, source_path, dominant_color, blurhash, phash) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
 		fileID,
 		nullableString(mimeType),
 		nullablePositiveInt(selectedCandidate.width),
@@ -1803,6 +2307,9 @@ func syncCoverForFile(ctx context.Context, tx *sql.Tx, fileID int64, fullPath st
 		hash,
 		nullableString(sourceKind),
 		nullableString(sourcePath),
+		dominantColor,
+		blurHash,
+		perceptualHash,
 	); insertErr != nil {
 		return false, fmt.Errorf("insert cover row for file %d: %w", fileID, insertErr)
 	}
@@ -2212,6 +2719,47 @@ func decodeCoverImage(imageData []byte) (string, int, int) {
 	return strings.ToLower(strings.TrimSpace(format)), config.Width, config.Height
 }
 
+// coverPlaceholder computes the grid placeholder from the small player
+// thumbnail. It returns nulls when the thumbnail cannot be decoded so a
+// missing placeholder never blocks storing the cover itself.
+func coverPlaceholder(cachePath string) (any, any) {
+	coverHash := coverart.HashFromCachePath(cachePath)
+	if coverHash == "" {
+		return nil, nil
+	}
+
+	thumbnailPath := coverart.VariantPathForHash(filepath.Dir(cachePath), coverHash, coverart.VariantPlayer)
+	placeholder, err := coverart.PlaceholderFromFile(thumbnailPath)
+	if err != nil {
+		return nil, nil
+	}
+
+	return placeholder.DominantColor, placeholder.BlurHash
+}
+
+// coverPerceptualHash hashes the player thumbnail, like coverPlaceholder;
+// the hash samples far fewer pixels than it has.
+func coverPerceptualHash(cachePath string) any {
+	coverHash := coverart.HashFromCachePath(cachePath)
+	if coverHash == "" {
+		return nil
+	}
+
+	thumbnailPath := coverart.VariantPathForHash(filepath.Dir(cachePath), coverHash, coverart.VariantPlayer)
+	perceptualHash, err := coverart.PerceptualHashFromFile(thumbnailPath)
+	if err != nil {
+		return nil
+	}
+
+	return perceptualHash
+}
+
+// EnsureCoverThumbnails writes any missing thumbnail variants for a cached
+// cover. Covers whose thumbnails all exist are left untouched.
+func EnsureCoverThumbnails(cachePath string) error {
+	return ensureCoverThumbnailsFromCachePath(cachePath)
+}
+
 func ensureCoverThumbnailsFromCachePath(cachePath string) error {
 	coverHash := coverart.HashFromCachePath(cachePath)
 	if coverHash == "" {
@@ -2245,6 +2793,9 @@ func ensureCoverThumbnails(cachePath string, coverHash string, imageData []byte)
 		return nil
 	}
 
+	unlock := thumbnailLocks.lock(coverHash)
+	defer unlock()
+
 	specs := coverart.DefaultThumbnailSpecs()
 	missingSpecs := make([]coverart.ThumbnailSpec, 0, len(specs))
 	cacheDirectory := filepath.Dir(cachePath)
@@ -2292,7 +2843,13 @@ func writeCoverThumbnail(path string, source *image.NRGBA, size int) error {
 		return err
 	}
 
-	if err := os.WriteFile(path, buffer.Bytes(), 0o644); err != nil {
+	// Written aside and renamed, so a reader never sees half a thumbnail.
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, buffer.Bytes(), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
 		return err
 	}
 
@@ -2431,63 +2988,159 @@ func clampFloat(value float64, minimum float64, maximum float64) float64 {
 	return value
 }
 
-func scanRoot(ctx context.Context, tx *sql.Tx, root library.WatchedRoot, mode scanMode, coverCacheDir string) (scanTotals, error) {
+func scanRoot(ctx context.Context, batch *scanTx, root library.WatchedRoot, mode scanMode, coverCacheDir string, verifyTags bool, indexArchives bool, workers int, progress *scanProgress) (scanTotals, error) {
 	rootTotals := scanTotals{}
 	scannedAt := time.Now().UTC().Format(time.RFC3339)
 
-	if mode == scanModeIncremental {
-		if err := clearIncrementalSeenTable(ctx, tx); err != nil {
+	if err := clearIncrementalSeenTable(ctx, batch.tx); err != nil {
+		return scanTotals{}, err
+	}
+
+	known, err := loadKnownFiles(ctx, batch.tx, root.ID)
+	if err != nil {
+		return scanTotals{}, err
+	}
+	// Repair scans recompute every cover from its image.
+	var covers *knownCovers
+	if mode != scanModeRepair {
+		covers, err = loadKnownCovers(ctx, batch.tx)
+		if err != nil {
 			return scanTotals{}, err
 		}
 	}
 
-	err := filepath.WalkDir(root.Path, func(path string, entry fs.DirEntry, walkErr error) error {
-		if walkErr != nil {
-			rootTotals.skipped++
-			return nil
-		}
+	// Workers read tags and artwork while this goroutine, the only one
+	// using the scan transaction, writes the files in walk order.
+	walkCtx, stopWalk := context.WithCancel(ctx)
+	defer stopWalk()
+	pipeline := newScanPipeline(walkCtx, workers, func(job *scanJob) {
+		job.prepared, job.err = prepareFile(root.Path, job.path, coverCacheDir, covers)
+	})
 
-		if entry.IsDir() {
-			return nil
-		}
+	walkSkipped := 0
+	var walkDirErr error
+	go func() {
+		defer pipeline.close()
 
-		extension := strings.ToLower(filepath.Ext(path))
-		if !isSupportedAudioExtension(extension) {
-			return nil
-		}
+		ignore := newIgnoreMatcher(root.Path, root.Rules)
+		walkDirErr = filepath.WalkDir(root.Path, func(path string, entry fs.DirEntry, walkErr error) error {
+			if err := walkCtx.Err(); err != nil {
+				return err
+			}
+			if walkErr != nil {
+				walkSkipped++
+				return nil
+			}
 
-		info, infoErr := entry.Info()
-		if infoErr != nil {
-			rootTotals.skipped++
-			return nil
-		}
+			if entry.IsDir() {
+				if ignore.ignored(path, true) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if ignore.ignored(path, false) {
+				return nil
+			}
 
-		rootTotals.filesSeen++
-		indexed, upsertErr := upsertFileAndTrack(ctx, tx, root.ID, root.Path, path, info, scannedAt, mode, coverCacheDir)
-		if upsertErr != nil {
-			return upsertErr
-		}
+			if indexArchives && archive.IsArchive(path) {
+				if !pipeline.submit(&scanJob{path: path, archive: true}, false) {
+					return walkCtx.Err()
+				}
+				return nil
+			}
 
-		if mode == scanModeIncremental {
-			if seenErr := markPathSeenIncremental(ctx, tx, filepath.Clean(path)); seenErr != nil {
-				return seenErr
+			extension := strings.ToLower(filepath.Ext(path))
+			if !isPlayableExtension(extension) {
+				return nil
+			}
+
+			info, infoErr := entry.Info()
+			if infoErr != nil {
+				walkSkipped++
+				return nil
 			}
+			if ignore.tooSmall(info) {
+				return nil
+			}
+
+			job := &scanJob{path: path, info: info}
+			if !pipeline.submit(job, needsMetadata(known, filepath.Clean(path), info, mode)) {
+				return walkCtx.Err()
+			}
+			return nil
+		})
+	}()
+
+	var writeErr error
+	for job := range pipeline.ordered {
+		if writeErr != nil {
+			continue
 		}
+		<-job.done
 
-		if indexed {
-			rootTotals.indexed++
-			rootTotals.libraryChanged = true
+		writeErr = writeScanJob(ctx, batch, root, job, scannedAt, mode, coverCacheDir, verifyTags, &rootTotals)
+		if writeErr != nil {
+			stopWalk()
+			continue
 		}
+		progress.fileDone(job.path)
+	}
+	pipeline.wait()
 
-		return nil
-	})
-	if err != nil {
-		return scanTotals{}, fmt.Errorf("walk root %s: %w", root.Path, err)
+	if writeErr == nil {
+		writeErr = walkDirErr
+	}
+	if writeErr == nil {
+		writeErr = ctx.Err()
+	}
+	rootTotals.skipped += walkSkipped
+	if writeErr != nil {
+		return scanTotals{}, fmt.Errorf("walk root %s: %w", root.Path, writeErr)
 	}
 
 	return rootTotals, nil
 }
 
+// writeScanJob stores a walked file, or the entries of a walked archive, in
+// the scan transaction.
+func writeScanJob(ctx context.Context, batch *scanTx, root library.WatchedRoot, job *scanJob, scannedAt string, mode scanMode, coverCacheDir string, verifyTags bool, rootTotals *scanTotals) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if job.archive {
+		archiveTotals, archiveErr := scanZipArchive(ctx, batch.tx, root, filepath.Clean(job.path), scannedAt, mode, coverCacheDir)
+		rootTotals.filesSeen += archiveTotals.filesSeen
+		rootTotals.indexed += archiveTotals.indexed
+		rootTotals.skipped += archiveTotals.skipped
+		rootTotals.libraryChanged = rootTotals.libraryChanged || archiveTotals.libraryChanged
+		if archiveErr != nil {
+			return archiveErr
+		}
+		return batch.fileProcessed(ctx)
+	}
+	if job.err != nil {
+		return job.err
+	}
+
+	rootTotals.filesSeen++
+	indexed, upsertErr := upsertFileAndTrack(ctx, batch.tx, root.ID, root.Path, job.path, job.info, scannedAt, mode, coverCacheDir, verifyTags, job.prepared)
+	if upsertErr != nil {
+		return upsertErr
+	}
+
+	if seenErr := markPathSeenIncremental(ctx, batch.tx, filepath.Clean(job.path)); seenErr != nil {
+		return seenErr
+	}
+
+	if indexed {
+		rootTotals.indexed++
+		rootTotals.libraryChanged = true
+	}
+
+	return batch.fileProcessed(ctx)
+}
+
 func upsertFileAndTrack(
 	ctx context.Context,
 	tx *sql.Tx,
@@ -2498,6 +3151,8 @@ func upsertFileAndTrack(
 	scannedAt string,
 	mode scanMode,
 	coverCacheDir string,
+	verifyTags bool,
+	prepared *preparedFile,
 ) (bool, error) {
 	cleanPath := filepath.Clean(path)
 
@@ -2507,13 +3162,15 @@ func upsertFileAndTrack(
 		currentSize   int64
 		currentMTime  int64
 		currentExists int
+		currentHash   sql.NullString
+		currentQuick  sql.NullString
 	)
 
 	err := tx.QueryRowContext(
 		ctx,
-		"SELECT id, root_id, size, mtime_ns, file_exists FROM files WHERE path = ?",
+		"SELECT id, root_id, size, mtime_ns, file_exists, tag_hash, hash_quick FROM files WHERE path = ?",
 		cleanPath,
-	).Scan(&fileID, &currentRoot, &currentSize, &currentMTime, &currentExists)
+	).Scan(&fileID, &currentRoot, &currentSize, &currentMTime, &currentExists, &currentHash, &currentQuick)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return false, fmt.Errorf("get file row %s: %w", cleanPath, err)
 	}
@@ -2525,13 +3182,14 @@ func upsertFileAndTrack(
 	if errors.Is(err, sql.ErrNoRows) {
 		result, insertErr := tx.ExecContext(
 			ctx,
-			`INSERT INTO files(path, root_id, size, mtime_ns, file_exists, last_seen_at)
-			 VALUES (?, ?, ?, ?, 1, ?)`,
+			`INSERT INTO files(path, root_id, size, mtime_ns, file_exists, last_seen_at, first_seen_at)
+			 VALUES (?, ?, ?, ?, 1, ?, ?)`,
 			cleanPath,
 			rootID,
 			newSize,
 			newMTime,
 			scannedAt,
+			scannedAt,
 		)
 		if insertErr != nil {
 			return false, fmt.Errorf("insert file %s: %w", cleanPath, insertErr)
@@ -2564,6 +3222,16 @@ func upsertFileAndTrack(
 				return false, fmt.Errorf("update file %s: %w", cleanPath, updateErr)
 			}
 		}
+
+		if metadataNeedsUpdate {
+			if _, clearErr := tx.ExecContext(
+				ctx,
+				"UPDATE files SET fingerprint = NULL, fingerprint_duration = NULL WHERE id = ?",
+				fileID,
+			); clearErr != nil {
+				return false, fmt.Errorf("clear fingerprint of %s: %w", cleanPath, clearErr)
+			}
+		}
 	}
 
 	if !metadataNeedsUpdate {
@@ -2572,20 +3240,26 @@ func upsertFileAndTrack(
 		}
 	}
 
+	if verifyTags {
+		tagsChanged, err := checkTagRegion(ctx, tx, fileID, cleanPath, newSize, currentHash)
+		if err != nil {
+			return false, err
+		}
+		metadataNeedsUpdate = metadataNeedsUpdate || tagsChanged
+	}
+
+	if metadataNeedsUpdate || !currentQuick.Valid {
+		if err := updateQuickHash(ctx, tx, fileID, cleanPath, newSize); err != nil {
+			return false, err
+		}
+	}
+
 	if !metadataNeedsUpdate {
-		var storedTags sql.NullString
-		tagErr := tx.QueryRowContext(
-			ctx,
-			"SELECT tags_json FROM tracks WHERE file_id = ?",
-			fileID,
-		).Scan(&storedTags)
-		if errors.Is(tagErr, sql.ErrNoRows) {
-			metadataNeedsUpdate = true
-		} else if tagErr != nil {
-			return false, fmt.Errorf("check track metadata for file %s: %w", cleanPath, tagErr)
-		} else {
-			metadataNeedsUpdate = !strings.Contains(storedTags.String, `"metadata_version":2`)
+		metadataCurrent, err := hasCurrentTrackMetadata(ctx, tx, fileID, cleanPath)
+		if err != nil {
+			return false, err
 		}
+		metadataNeedsUpdate = !metadataCurrent
 	}
 
 	if !metadataNeedsUpdate {
@@ -2597,14 +3271,56 @@ func upsertFileAndTrack(
 		return coverChanged, nil
 	}
 
-	metadata, metaErr := deriveMetadata(rootPath, cleanPath)
-	if metaErr != nil {
-		return false, metaErr
+	if prepared == nil || prepared.metadata == nil {
+		metadata, metaErr := deriveMetadata(rootPath, cleanPath)
+		if metaErr != nil {
+			return false, metaErr
+		}
+		prepared = &preparedFile{metadata: &metadata}
 	}
 
+	if err := upsertTrackMetadata(ctx, tx, fileID, cleanPath, *prepared.metadata); err != nil {
+		return false, err
+	}
+
+	if _, err := syncPreparedCover(ctx, tx, fileID, cleanPath, coverCacheDir, true, prepared.cover); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func hasCurrentTrackMetadata(ctx context.Context, tx *sql.Tx, fileID int64, cleanPath string) (bool, error) {
+	var storedTags sql.NullString
+	err := tx.QueryRowContext(
+		ctx,
+		"SELECT tags_json FROM tracks WHERE file_id = ?",
+		fileID,
+	).Scan(&storedTags)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("check track metadata for file %s: %w", cleanPath, err)
+	}
+
+	return strings.Contains(storedTags.String, fmt.Sprintf(`"metadata_version":%d`, metadataVersion)), nil
+}
+
+func upsertTrackMetadata(ctx context.Context, tx *sql.Tx, fileID int64, cleanPath string, metadata extractedMetadata) error {
 	tagsJSON, marshalErr := json.Marshal(metadata.tags)
 	if marshalErr != nil {
-		return false, fmt.Errorf("marshal tags for %s: %w", cleanPath, marshalErr)
+		return fmt.Errorf("marshal tags for %s: %w", cleanPath, marshalErr)
+	}
+	script, language := library.DetectScript(metadata.title, metadata.artist, metadata.album)
+	artistRomanized := library.RomanizedAlias(metadata.artist, metadata.artistSort)
+	albumArtistRomanized := library.RomanizedAlias(metadata.albumArtist, metadata.albumArtistSort)
+	albumRomanized := library.RomanizedAlias(metadata.album, metadata.albumSort)
+	movementNo := metadata.movementNo
+	if movementNo == nil && metadata.work != "" {
+		if number := library.MovementNumber(metadata.title); number > 0 {
+			movementNo = &number
+		}
 	}
 
 	if _, upsertErr := tx.ExecContext(
@@ -2624,10 +3340,26 @@ func upsertFileAndTrack(
 			sample_rate,
 			bit_depth,
 			bitrate,
+			replaygain_track_gain,
+			replaygain_track_peak,
+			replaygain_album_gain,
+			replaygain_album_peak,
 			tags_json,
+			script,
+			language,
+			search_text,
+			artist_romanized,
+			album_artist_romanized,
+			album_romanized,
+			composer,
+			conductor,
+			work,
+			movement_no,
+			movement_name,
+			catalog_key,
 			updated_at
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(file_id) DO UPDATE SET
 			title = excluded.title,
 			artist = excluded.artist,
@@ -2642,7 +3374,24 @@ func upsertFileAndTrack(
 			sample_rate = excluded.sample_rate,
 			bit_depth = excluded.bit_depth,
 			bitrate = excluded.bitrate,
+			replaygain_track_gain = excluded.replaygain_track_gain,
+			replaygain_track_peak = excluded.replaygain_track_peak,
+			replaygain_album_gain = excluded.replaygain_album_gain,
+			replaygain_album_peak = excluded.replaygain_album_peak,
 			tags_json = excluded.tags_json,
+			script = excluded.script,
+			language = excluded.language,
+			search_text = excluded.search_text,
+			artist_romanized = excluded.artist_romanized,
+			album_artist_romanized = excluded.album_artist_romanized,
+			album_romanized = excluded.album_romanized,
+			composer = excluded.composer,
+			conductor = excluded.conductor,
+			work = excluded.work,
+			movement_no = excluded.movement_no,
+			movement_name = excluded.movement_name,
+			catalog_key = excluded.catalog_key,
+			duration_verified_at = NULL,
 			updated_at = excluded.updated_at`,
 		fileID,
 		metadata.title,
@@ -2658,17 +3407,37 @@ func upsertFileAndTrack(
 		nullableInt(metadata.sampleRate),
 		nullableInt(metadata.bitDepth),
 		nullableInt(metadata.bitrate),
+		nullableFloat(metadata.trackGain),
+		nullableFloat(metadata.trackPeak),
+		nullableFloat(metadata.albumGain),
+		nullableFloat(metadata.albumPeak),
 		string(tagsJSON),
+		script,
+		language,
+		library.SearchText(metadata.title, metadata.artist, metadata.album, artistRomanized, albumRomanized, metadata.composer, metadata.work),
+		nullableString(artistRomanized),
+		nullableString(albumArtistRomanized),
+		nullableString(albumRomanized),
+		nullableString(metadata.composer),
+		nullableString(metadata.conductor),
+		nullableString(metadata.work),
+		nullableInt(movementNo),
+		nullableString(metadata.movementName),
+		nullableString(library.CatalogSortKey(metadata.work, metadata.title, metadata.album)),
 		time.Now().UTC().Format(time.RFC3339),
 	); upsertErr != nil {
-		return false, fmt.Errorf("upsert track %s: %w", cleanPath, upsertErr)
+		return fmt.Errorf("upsert track %s: %w", cleanPath, upsertErr)
 	}
 
-	if _, err := syncCoverForFile(ctx, tx, fileID, cleanPath, coverCacheDir, true); err != nil {
-		return false, err
+	if err := updateTrackIdentity(ctx, tx, fileID); err != nil {
+		return fmt.Errorf("update identity of track %s: %w", cleanPath, err)
 	}
 
-	return true, nil
+	if err := replaceTrackArtists(ctx, tx, fileID, metadata); err != nil {
+		return err
+	}
+
+	return replaceTrackGenres(ctx, tx, fileID, metadata.genres)
 }
 
 type extractedMetadata struct {
@@ -2685,7 +3454,28 @@ type extractedMetadata struct {
 	bitrate     *int
 	discNo      *int
 	trackNo     *int
-	tags        map[string]any
+	trackGain   *float64
+	trackPeak   *float64
+	albumGain   *float64
+	albumPeak   *float64
+	// artistSort, albumArtistSort and albumSort are the sort tags, read
+	// for the romanized aliases of names in other scripts.
+	artistSort      string
+	albumArtistSort string
+	albumSort       string
+	// genres are the values of a multi-value genre tag; genre keeps the
+	// tag as written.
+	genres []string
+	// artists are the values of an ARTISTS tag; when it is missing the
+	// artist credit is split instead.
+	artists []string
+	// composer, conductor, work and the movement are the classical tags.
+	composer     string
+	conductor    string
+	work         string
+	movementNo   *int
+	movementName string
+	tags         map[string]any
 }
 
 func deriveMetadata(rootPath string, fullPath string) (extractedMetadata, error) {
@@ -2790,6 +3580,11 @@ func applyTagValues(metadata *extractedMetadata, tags map[string][]string) {
 	if value := firstTagValue(tags, taglib.Artist, "ARTIST"); value != "" {
 		metadata.artist = value
 	}
+	// A multi-value ARTIST tag is displayed as one credit.
+	if values := library.SplitArtists(tags[taglib.Artist]...); len(values) > 1 {
+		metadata.artist = strings.Join(values, "; ")
+	}
+	metadata.artists = library.SplitArtists(tags["ARTISTS"]...)
 	if value := firstTagValue(tags, taglib.AlbumArtist, "ALBUMARTIST"); value != "" {
 		metadata.albumArtist = value
 	}
@@ -2799,6 +3594,15 @@ func applyTagValues(metadata *extractedMetadata, tags map[string][]string) {
 	if value := firstTagValue(tags, taglib.Genre, "GENRE"); value != "" {
 		metadata.genre = value
 	}
+	metadata.genres = library.SplitGenres(tags[taglib.Genre]...)
+	metadata.artistSort = firstTagValue(tags, taglib.ArtistSort, "TSOP", "SOAR")
+	metadata.albumArtistSort = firstTagValue(tags, taglib.AlbumArtistSort, "TSO2", "SOAA")
+	metadata.albumSort = firstTagValue(tags, taglib.AlbumSort, "TSOA", "SOAL")
+	metadata.composer = firstTagValue(tags, taglib.Composer, "TCOM")
+	metadata.conductor = firstTagValue(tags, taglib.Conductor, "TPE3")
+	metadata.work = firstTagValue(tags, taglib.Work, "TIT1")
+	metadata.movementName = firstTagValue(tags, taglib.MovementName, "MVNM")
+	metadata.movementNo = parseNumericTag(firstTagValue(tags, taglib.MovementNumber, "MVIN"))
 
 	if trackNo := parseNumericTag(firstTagValue(tags, taglib.TrackNumber, "TRACKNUMBER", "TRCK")); trackNo != nil {
 		metadata.trackNo = trackNo
@@ -2815,6 +3619,7 @@ func applyTagValues(metadata *extractedMetadata, tags map[string][]string) {
 	if codec := firstTagValue(tags, taglib.FileType, "FILETYPE"); codec != "" {
 		metadata.codec = normalizeCodec(codec)
 	}
+	applyReplayGainTags(metadata, tags)
 
 	if metadata.albumArtist == "" {
 		metadata.albumArtist = metadata.artist
@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// TagVerificationSettingKey enables hashing the tag regions of unchanged
+// files during scans. Some taggers keep the modification time when they
+// rewrite tags, so without it such edits wait for a repair scan.
+const TagVerificationSettingKey = "library.verify_tag_regions"
+
+// tagRegionBytes is how much of each end of a file is hashed. ID3v2, FLAC,
+// Vorbis and most MP4 tags sit at the start and ID3v1 and APE tags at the
+// end; embedded artwork past the region is not covered.
+const tagRegionBytes = 64 << 10
+
+func (s *Service) TagVerificationEnabled(ctx context.Context) bool {
+	enabled, err := strconv.ParseBool(s.settings.GetString(ctx, TagVerificationSettingKey, "false"))
+	return err == nil && enabled
+}
+
+func (s *Service) SetTagVerificationEnabled(ctx context.Context, enabled bool) (bool, error) {
+	if err := s.settings.Set(ctx, TagVerificationSettingKey, strconv.FormatBool(enabled)); err != nil {
+		return s.TagVerificationEnabled(ctx), err
+	}
+
+	return enabled, nil
+}
+
+// hashTagRegion hashes the first and last tagRegionBytes of a file, or the
+// whole file when it is smaller than both regions together.
+func hashTagRegion(path string, size int64) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if size <= 2*tagRegionBytes {
+		if _, err := io.Copy(hash, file); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	}
+
+	if _, err := io.CopyN(hash, file, tagRegionBytes); err != nil {
+		return "", err
+	}
+	if _, err := file.Seek(size-tagRegionBytes, io.SeekStart); err != nil {
+		return "", err
+	}
+	if _, err := io.CopyN(hash, file, tagRegionBytes); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// checkTagRegion compares the tag region hash of a file with the stored one
+// and stores the new hash. It reports a change only when a hash was stored
+// before, so the first verified scan records a baseline without re-reading
+// every file. Unreadable files report no change and are left to the usual
+// size and mtime checks.
+func checkTagRegion(ctx context.Context, tx *sql.Tx, fileID int64, cleanPath string, size int64, storedHash sql.NullString) (bool, error) {
+	tagHash, err := hashTagRegion(cleanPath, size)
+	if err != nil || (storedHash.Valid && storedHash.String == tagHash) {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE files SET tag_hash = ? WHERE id = ?", tagHash, fileID); err != nil {
+		return false, fmt.Errorf("update tag hash for %s: %w", cleanPath, err)
+	}
+
+	return storedHash.Valid, nil
+}
+
+// updateQuickHash stores the size and the hash of both ends of a file, which
+// identical copies of the file share. Duplicate detection groups files by
+// it. Unreadable files keep their previous hash.
+func updateQuickHash(ctx context.Context, tx *sql.Tx, fileID int64, cleanPath string, size int64) error {
+	regionHash, err := hashTagRegion(cleanPath, size)
+	if err != nil {
+		return nil
+	}
+
+	quickHash := strconv.FormatInt(size, 10) + ":" + regionHash
+	if _, err := tx.ExecContext(ctx, "UPDATE files SET hash_quick = ? WHERE id = ?", quickHash, fileID); err != nil {
+		return fmt.Errorf("update quick hash for %s: %w", cleanPath, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package scanner
+
+import "time"
+
+// WatcherStatus reports whether library folders are watched for changes and
+// the last error the watcher ran into, if any.
+type WatcherStatus struct {
+	Watching    bool   `json:"watching"`
+	LastError   string `json:"lastError,omitempty"`
+	LastErrorAt string `json:"lastErrorAt,omitempty"`
+}
+
+func (s *Service) GetWatcherStatus() WatcherStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := WatcherStatus{Watching: s.watching, LastError: s.watchErr}
+	if !s.watchErrAt.IsZero() {
+		status.LastErrorAt = s.watchErrAt.UTC().Format(time.RFC3339)
+	}
+
+	return status
+}
+
+func (s *Service) recordWatcherError(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watchErr = message
+	s.watchErrAt = time.Now()
+}
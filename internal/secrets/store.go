@@ -0,0 +1,74 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Service names the keychain entries of the app.
+const Service = "ben"
+
+// Store keeps passwords and keys in the operating system keychain: the
+// Keychain on macOS, the Credential Manager on Windows and the Secret
+// Service on Linux. Secrets never reach the library database, so they are
+// not part of its backups either.
+type Store struct {
+	service string
+}
+
+func NewStore(service string) *Store {
+	return &Store{service: service}
+}
+
+// Get returns the secret stored under key and whether there is one.
+func (s *Store) Get(key string) (string, bool, error) {
+	if s == nil {
+		return "", false, nil
+	}
+
+	value, err := keyring.Get(s.service, key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("read secret %q from the keychain: %w", key, err)
+	}
+
+	return value, true, nil
+}
+
+// Set stores value under key. An empty value deletes the secret.
+func (s *Store) Set(key string, value string) error {
+	if s == nil {
+		return errors.New("keychain is unavailable")
+	}
+	if strings.TrimSpace(key) == "" {
+		return errors.New("secret key is required")
+	}
+	if value == "" {
+		return s.Delete(key)
+	}
+
+	if err := keyring.Set(s.service, key, value); err != nil {
+		return fmt.Errorf("store secret %q in the keychain: %w", key, err)
+	}
+
+	return nil
+}
+
+// Delete removes the secret stored under key, if any.
+func (s *Store) Delete(key string) error {
+	if s == nil {
+		return nil
+	}
+
+	err := keyring.Delete(s.service, key)
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("delete secret %q from the keychain: %w", key, err)
+	}
+
+	return nil
+}
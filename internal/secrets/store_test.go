@@ -0,0 +1,34 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestStoreRoundTripsAndDeletesSecrets(t *testing.T) {
+	keyring.MockInit()
+	store := NewStore("ben-test")
+
+	if _, ok, err := store.Get("remote-root/1"); err != nil || ok {
+		t.Fatalf("expected no secret before set, got ok=%v err=%v", ok, err)
+	}
+
+	if err := store.Set("remote-root/1", "hunter2"); err != nil {
+		t.Fatalf("set secret: %v", err)
+	}
+	value, ok, err := store.Get("remote-root/1")
+	if err != nil || !ok || value != "hunter2" {
+		t.Fatalf("unexpected secret %q ok=%v err=%v", value, ok, err)
+	}
+
+	if err := store.Set("remote-root/1", ""); err != nil {
+		t.Fatalf("clear secret: %v", err)
+	}
+	if _, ok, _ := store.Get("remote-root/1"); ok {
+		t.Fatalf("expected empty value to delete the secret")
+	}
+	if err := store.Delete("remote-root/1"); err != nil {
+		t.Fatalf("delete of a missing secret should succeed: %v", err)
+	}
+}
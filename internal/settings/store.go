@@ -0,0 +1,81 @@
+package settings
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(database *sql.DB) *Store {
+	return &Store{db: database}
+}
+
+func (s *Store) Get(ctx context.Context, key string) (string, bool, error) {
+	if s.db == nil {
+		return "", false, nil
+	}
+
+	var value string
+	err := s.db.QueryRowContext(ctx, "SELECT value FROM app_settings WHERE key = ?", key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("read setting %q: %w", key, err)
+	}
+
+	return value, true, nil
+}
+
+func (s *Store) GetString(ctx context.Context, key string, fallback string) string {
+	value, ok, err := s.Get(ctx, key)
+	if err != nil || !ok {
+		return fallback
+	}
+
+	return value
+}
+
+func (s *Store) Set(ctx context.Context, key string, value string) error {
+	if s.db == nil {
+		return errors.New("settings store is unavailable")
+	}
+	if strings.TrimSpace(key) == "" {
+		return errors.New("setting key is required")
+	}
+
+	if _, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO app_settings(key, value, updated_at)
+		 VALUES (?, ?, ?)
+		 ON CONFLICT(key) DO UPDATE SET
+		 	value = excluded.value,
+		 	updated_at = excluded.updated_at`,
+		key,
+		value,
+		time.Now().UTC().Format(time.RFC3339),
+	); err != nil {
+		return fmt.Errorf("write setting %q: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *Store) Delete(ctx context.Context, key string) error {
+	if s.db == nil {
+		return nil
+	}
+
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM app_settings WHERE key = ?", key); err != nil {
+		return fmt.Errorf("delete setting %q: %w", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,444 @@
+package snapshot
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRollbackWindow is how long a snapshot can be rolled back.
+const DefaultRollbackWindow = 15 * time.Minute
+
+// DefaultHistoryLimit is how many operations are kept for rollback.
+const DefaultHistoryLimit = 5
+
+const copyTablePrefix = "library_snapshot_"
+
+var (
+	ErrNoSnapshot      = errors.New("no operation to roll back")
+	ErrSnapshotExpired = errors.New("rollback window has passed")
+)
+
+var tableNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// Info describes the snapshot taken before a bulk operation. Kind is set
+// for operations that changed files, which a Restorer reverts.
+type Info struct {
+	ID        int64    `json:"id"`
+	Label     string   `json:"label"`
+	Tables    []string `json:"tables"`
+	Kind      string   `json:"kind,omitempty"`
+	CreatedAt string   `json:"createdAt"`
+	ExpiresAt string   `json:"expiresAt"`
+}
+
+// Restorer reverts the file changes recorded by CaptureFiles.
+type Restorer func(ctx context.Context, payload json.RawMessage) error
+
+// Service keeps copies of the tables touched by the last few bulk
+// operations so they can be rolled back, newest first. Callers list every
+// table the operation changes, including tables that would be changed
+// through ON DELETE CASCADE. Operations on files record what they changed
+// instead, and the restorer registered for their kind reverts it.
+type Service struct {
+	mu        sync.Mutex
+	db        *sql.DB
+	window    time.Duration
+	limit     int
+	now       func() time.Time
+	restorers map[string]Restorer
+}
+
+func NewService(database *sql.DB) *Service {
+	return &Service{
+		db:        database,
+		window:    DefaultRollbackWindow,
+		limit:     DefaultHistoryLimit,
+		now:       time.Now,
+		restorers: make(map[string]Restorer),
+	}
+}
+
+// SetRestorer registers how file changes of kind are rolled back.
+func (s *Service) SetRestorer(kind string, restorer Restorer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.restorers[kind] = restorer
+}
+
+// Capture copies the tables before an operation labelled label runs.
+func (s *Service) Capture(ctx context.Context, label string, tables ...string) error {
+	if len(tables) == 0 {
+		return errors.New("snapshot requires at least one table")
+	}
+	for _, table := range tables {
+		if !tableNamePattern.MatchString(table) {
+			return fmt.Errorf("invalid snapshot table %q", table)
+		}
+	}
+
+	encodedTables, err := json.Marshal(tables)
+	if err != nil {
+		return fmt.Errorf("encode snapshot tables: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin snapshot transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	id, err := s.insertLocked(ctx, tx, label, string(encodedTables), "", "")
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		var found int
+		if err := tx.QueryRowContext(
+			ctx,
+			"SELECT COUNT(1) FROM sqlite_master WHERE type = 'table' AND name = ?",
+			table,
+		).Scan(&found); err != nil {
+			return fmt.Errorf("check snapshot table %s: %w", table, err)
+		}
+		if found == 0 {
+			return fmt.Errorf("snapshot table %q does not exist", table)
+		}
+
+		if _, err := tx.ExecContext(ctx, "CREATE TABLE "+copyTableName(id, table)+" AS SELECT * FROM "+table); err != nil {
+			return fmt.Errorf("copy snapshot table %s: %w", table, err)
+		}
+	}
+
+	if err := s.pruneLocked(ctx, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit snapshot transaction: %w", err)
+	}
+
+	return nil
+}
+
+// CaptureFiles records the file changes of an operation labelled label so
+// the restorer registered for kind can revert them. payload is encoded as
+// JSON and handed back to the restorer as is.
+func (s *Service) CaptureFiles(ctx context.Context, label string, kind string, payload any) error {
+	if strings.TrimSpace(kind) == "" {
+		return errors.New("file snapshot requires a kind")
+	}
+
+	encodedPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode snapshot files: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin snapshot transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := s.insertLocked(ctx, tx, label, "[]", kind, string(encodedPayload)); err != nil {
+		return err
+	}
+	if err := s.pruneLocked(ctx, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit snapshot transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) insertLocked(ctx context.Context, tx *sql.Tx, label string, tablesJSON string, kind string, filesJSON string) (int64, error) {
+	result, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO library_snapshots(label, tables_json, files_kind, files_json, created_at) VALUES (?, ?, ?, ?, ?)",
+		label,
+		tablesJSON,
+		kind,
+		filesJSON,
+		s.now().UTC().Format(time.RFC3339Nano),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("save snapshot: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("read snapshot id: %w", err)
+	}
+
+	return id, nil
+}
+
+// Last returns the newest snapshot if it can still be rolled back.
+func (s *Service) Last(ctx context.Context) (Info, bool, error) {
+	history, err := s.List(ctx)
+	if err != nil || len(history) == 0 {
+		return Info{}, false, err
+	}
+
+	return history[0], true, nil
+}
+
+// List returns the snapshots that can still be rolled back, newest first.
+func (s *Service) List(ctx context.Context) ([]Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.readLocked(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]Info, 0, len(records))
+	for _, record := range records {
+		if s.expired(record.createdAt) {
+			break
+		}
+		history = append(history, record.info)
+	}
+
+	return history, nil
+}
+
+// RollbackLastOperation reverts the newest snapshot and drops it, so an
+// operation can only be rolled back once. Older snapshots are rolled back
+// by calling it again.
+func (s *Service) RollbackLastOperation(ctx context.Context) (Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.readLocked(ctx, s.db)
+	if err != nil {
+		return Info{}, err
+	}
+	if len(records) == 0 {
+		return Info{}, ErrNoSnapshot
+	}
+	record := records[0]
+	if s.expired(record.createdAt) {
+		return Info{}, ErrSnapshotExpired
+	}
+
+	if record.info.Kind != "" {
+		restorer, ok := s.restorers[record.info.Kind]
+		if !ok {
+			return Info{}, fmt.Errorf("cannot roll back %s", record.info.Label)
+		}
+		// A failed restore keeps the snapshot so it can be retried.
+		if err := restorer(ctx, record.payload); err != nil {
+			return Info{}, fmt.Errorf("roll back %s: %w", record.info.Label, err)
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Info{}, fmt.Errorf("begin rollback transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "PRAGMA defer_foreign_keys = ON"); err != nil {
+		return Info{}, fmt.Errorf("defer foreign keys: %w", err)
+	}
+	for _, table := range record.info.Tables {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table); err != nil {
+			return Info{}, fmt.Errorf("clear table %s: %w", table, err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO "+table+" SELECT * FROM "+copyTableName(record.info.ID, table)); err != nil {
+			return Info{}, fmt.Errorf("restore table %s: %w", table, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM library_snapshots WHERE id = ?", record.info.ID); err != nil {
+		return Info{}, fmt.Errorf("drop snapshot: %w", err)
+	}
+	if err := dropOrphanCopiesLocked(ctx, tx); err != nil {
+		return Info{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Info{}, fmt.Errorf("commit rollback transaction: %w", err)
+	}
+
+	return record.info, nil
+}
+
+func (s *Service) expired(createdAt time.Time) bool {
+	return s.now().After(createdAt.Add(s.window))
+}
+
+type snapshotRecord struct {
+	info      Info
+	payload   json.RawMessage
+	createdAt time.Time
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// readLocked returns every stored snapshot, newest first.
+func (s *Service) readLocked(ctx context.Context, db queryer) ([]snapshotRecord, error) {
+	rows, err := db.QueryContext(
+		ctx,
+		"SELECT id, label, tables_json, files_kind, files_json, created_at FROM library_snapshots ORDER BY id DESC",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("read snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]snapshotRecord, 0)
+	for rows.Next() {
+		var record snapshotRecord
+		var tablesJSON, filesJSON string
+		if err := rows.Scan(
+			&record.info.ID,
+			&record.info.Label,
+			&tablesJSON,
+			&record.info.Kind,
+			&filesJSON,
+			&record.info.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan snapshot row: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(tablesJSON), &record.info.Tables); err != nil {
+			return nil, fmt.Errorf("decode snapshot tables: %w", err)
+		}
+		record.payload = json.RawMessage(filesJSON)
+		record.createdAt, err = time.Parse(time.RFC3339Nano, record.info.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("parse snapshot time: %w", err)
+		}
+		record.info.ExpiresAt = record.createdAt.Add(s.window).UTC().Format(time.RFC3339Nano)
+
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate snapshot rows: %w", err)
+	}
+
+	return records, nil
+}
+
+// pruneLocked drops the snapshots beyond the history limit and those whose
+// rollback window has passed, together with their table copies.
+func (s *Service) pruneLocked(ctx context.Context, tx *sql.Tx) error {
+	records, err := s.readLocked(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	for index, record := range records {
+		if index < s.limit && !s.expired(record.createdAt) {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM library_snapshots WHERE id = ?", record.info.ID); err != nil {
+			return fmt.Errorf("drop snapshot: %w", err)
+		}
+	}
+
+	return dropOrphanCopiesLocked(ctx, tx)
+}
+
+// dropOrphanCopiesLocked removes the table copies whose snapshot record is
+// gone, including copies named by the single snapshot kept before history.
+func dropOrphanCopiesLocked(ctx context.Context, tx *sql.Tx) error {
+	kept := make(map[int64]struct{})
+	idRows, err := tx.QueryContext(ctx, "SELECT id FROM library_snapshots")
+	if err != nil {
+		return fmt.Errorf("list snapshots: %w", err)
+	}
+	for idRows.Next() {
+		var id int64
+		if scanErr := idRows.Scan(&id); scanErr != nil {
+			_ = idRows.Close()
+			return fmt.Errorf("scan snapshot id: %w", scanErr)
+		}
+		kept[id] = struct{}{}
+	}
+	if rowsErr := idRows.Err(); rowsErr != nil {
+		_ = idRows.Close()
+		return fmt.Errorf("iterate snapshot ids: %w", rowsErr)
+	}
+	_ = idRows.Close()
+
+	rows, err := tx.QueryContext(
+		ctx,
+		"SELECT name FROM sqlite_master WHERE type = 'table' AND name LIKE ? ESCAPE '\\'",
+		`library\_snapshot\_%`,
+	)
+	if err != nil {
+		return fmt.Errorf("list snapshot tables: %w", err)
+	}
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if scanErr := rows.Scan(&name); scanErr != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan snapshot table row: %w", scanErr)
+		}
+		names = append(names, name)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		_ = rows.Close()
+		return fmt.Errorf("iterate snapshot table rows: %w", rowsErr)
+	}
+	_ = rows.Close()
+
+	for _, name := range names {
+		if !tableNamePattern.MatchString(name) {
+			continue
+		}
+		if id, ok := copyTableSnapshotID(name); ok {
+			if _, keep := kept[id]; keep {
+				continue
+			}
+		}
+		if _, err := tx.ExecContext(ctx, "DROP TABLE "+name); err != nil {
+			return fmt.Errorf("drop snapshot table %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func copyTableName(id int64, table string) string {
+	return copyTablePrefix + strconv.FormatInt(id, 10) + "_" + table
+}
+
+func copyTableSnapshotID(name string) (int64, bool) {
+	rest := strings.TrimPrefix(name, copyTablePrefix)
+	digits, _, found := strings.Cut(rest, "_")
+	if !found {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,208 @@
+package snapshot
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rzxx/ben/internal/db"
+)
+
+func newTestService(t *testing.T) (*Service, *sql.DB) {
+	t.Helper()
+
+	database, err := db.Bootstrap(filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatalf("bootstrap test database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	if _, err := database.Exec("CREATE TABLE notes(id INTEGER PRIMARY KEY, body TEXT NOT NULL)"); err != nil {
+		t.Fatalf("create notes table: %v", err)
+	}
+
+	return NewService(database), database
+}
+
+func setNote(t *testing.T, database *sql.DB, body string) {
+	t.Helper()
+
+	if _, err := database.Exec("INSERT INTO notes(id, body) VALUES (1, ?) ON CONFLICT(id) DO UPDATE SET body = excluded.body", body); err != nil {
+		t.Fatalf("set note: %v", err)
+	}
+}
+
+func assertNote(t *testing.T, database *sql.DB, want string) {
+	t.Helper()
+
+	var body string
+	if err := database.QueryRow("SELECT body FROM notes WHERE id = 1").Scan(&body); err != nil {
+		t.Fatalf("read note: %v", err)
+	}
+	if body != want {
+		t.Fatalf("expected note %q, got %q", want, body)
+	}
+}
+
+func countCopyTables(t *testing.T, database *sql.DB) int {
+	t.Helper()
+
+	var count int
+	if err := database.QueryRow(
+		"SELECT COUNT(1) FROM sqlite_master WHERE type = 'table' AND name LIKE 'library\\_snapshot\\_%' ESCAPE '\\'",
+	).Scan(&count); err != nil {
+		t.Fatalf("count snapshot tables: %v", err)
+	}
+
+	return count
+}
+
+func TestRollbackLastOperationRollsBackTheHistoryNewestFirst(t *testing.T) {
+	t.Parallel()
+
+	service, database := newTestService(t)
+	ctx := context.Background()
+
+	setNote(t, database, "first")
+	if err := service.Capture(ctx, "Edit one", "notes"); err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	setNote(t, database, "second")
+	if err := service.Capture(ctx, "Edit two", "notes"); err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	setNote(t, database, "third")
+
+	history, err := service.List(ctx)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(history) != 2 || history[0].Label != "Edit two" || history[1].Label != "Edit one" {
+		t.Fatalf("expected both operations newest first, got %+v", history)
+	}
+
+	info, err := service.RollbackLastOperation(ctx)
+	if err != nil || info.Label != "Edit two" {
+		t.Fatalf("expected Edit two rolled back, got %+v err=%v", info, err)
+	}
+	assertNote(t, database, "second")
+
+	info, err = service.RollbackLastOperation(ctx)
+	if err != nil || info.Label != "Edit one" {
+		t.Fatalf("expected Edit one rolled back, got %+v err=%v", info, err)
+	}
+	assertNote(t, database, "first")
+
+	if _, err := service.RollbackLastOperation(ctx); !errors.Is(err, ErrNoSnapshot) {
+		t.Fatalf("expected ErrNoSnapshot, got %v", err)
+	}
+	if count := countCopyTables(t, database); count != 0 {
+		t.Fatalf("expected every table copy dropped, got %d", count)
+	}
+}
+
+func TestCaptureKeepsOnlyTheHistoryLimit(t *testing.T) {
+	t.Parallel()
+
+	service, database := newTestService(t)
+	ctx := context.Background()
+	setNote(t, database, "note")
+
+	for range DefaultHistoryLimit + 2 {
+		if err := service.Capture(ctx, "Edit", "notes"); err != nil {
+			t.Fatalf("capture: %v", err)
+		}
+	}
+
+	history, err := service.List(ctx)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(history) != DefaultHistoryLimit {
+		t.Fatalf("expected %d snapshots, got %d", DefaultHistoryLimit, len(history))
+	}
+	if count := countCopyTables(t, database); count != DefaultHistoryLimit {
+		t.Fatalf("expected %d table copies, got %d", DefaultHistoryLimit, count)
+	}
+}
+
+func TestCaptureDropsCopiesOfTheSingleSnapshot(t *testing.T) {
+	t.Parallel()
+
+	service, database := newTestService(t)
+	if _, err := database.Exec("CREATE TABLE library_snapshot_notes AS SELECT * FROM notes"); err != nil {
+		t.Fatalf("create legacy copy: %v", err)
+	}
+
+	if err := service.Capture(context.Background(), "Edit", "notes"); err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	if count := countCopyTables(t, database); count != 1 {
+		t.Fatalf("expected only the new table copy, got %d", count)
+	}
+}
+
+func TestRollbackLastOperationRunsTheRestorerOfFileSnapshots(t *testing.T) {
+	t.Parallel()
+
+	service, _ := newTestService(t)
+	ctx := context.Background()
+
+	var restored []string
+	fail := true
+	service.SetRestorer("files", func(_ context.Context, payload json.RawMessage) error {
+		if fail {
+			return errors.New("disk unavailable")
+		}
+		return json.Unmarshal(payload, &restored)
+	})
+
+	if err := service.CaptureFiles(ctx, "Move files", "files", []string{"a.flac", "b.flac"}); err != nil {
+		t.Fatalf("capture files: %v", err)
+	}
+
+	// A failed restore keeps the snapshot for another try.
+	if _, err := service.RollbackLastOperation(ctx); err == nil {
+		t.Fatalf("expected the restorer error")
+	}
+	if _, ok, _ := service.Last(ctx); !ok {
+		t.Fatalf("expected the snapshot kept after a failed restore")
+	}
+
+	fail = false
+	info, err := service.RollbackLastOperation(ctx)
+	if err != nil || info.Kind != "files" {
+		t.Fatalf("expected the file snapshot rolled back, got %+v err=%v", info, err)
+	}
+	if len(restored) != 2 || restored[0] != "a.flac" || restored[1] != "b.flac" {
+		t.Fatalf("expected the payload handed to the restorer, got %v", restored)
+	}
+}
+
+func TestRollbackLastOperationRefusesExpiredSnapshots(t *testing.T) {
+	t.Parallel()
+
+	service, database := newTestService(t)
+	ctx := context.Background()
+	now := time.Now()
+	service.now = func() time.Time { return now }
+
+	setNote(t, database, "before")
+	if err := service.Capture(ctx, "Edit", "notes"); err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	setNote(t, database, "after")
+
+	now = now.Add(DefaultRollbackWindow + time.Second)
+	if _, ok, err := service.Last(ctx); err != nil || ok {
+		t.Fatalf("expected no snapshot after the window, got ok=%v err=%v", ok, err)
+	}
+	if _, err := service.RollbackLastOperation(ctx); !errors.Is(err, ErrSnapshotExpired) {
+		t.Fatalf("expected ErrSnapshotExpired, got %v", err)
+	}
+	assertNote(t, database, "after")
+}
@@ -23,6 +23,7 @@ const dashboardSessionGap = 20 * time.Minute
 
 type Dashboard struct {
 	Range              string             `json:"range"`
+	Modules            []string           `json:"modules"`
 	WindowStart        *string            `json:"windowStart,omitempty"`
 	GeneratedAt        string             `json:"generatedAt"`
 	Summary            DashboardSummary   `json:"summary"`
@@ -40,7 +41,9 @@ type Dashboard struct {
 	PeakHour           int                `json:"peakHour"`
 	PeakWeekday        int                `json:"peakWeekday"`
 	Session            SessionStats       `json:"session"`
+	SessionLabels      []SessionLabelStat `json:"sessionLabels"`
 	BehaviorWindowDays int                `json:"behaviorWindowDays"`
+	CollapseVersions   string             `json:"collapseVersions,omitempty"`
 }
 
 type DashboardSummary struct {
@@ -182,6 +185,10 @@ func genreKeyExpr(trackAlias string) string {
 }
 
 func (s *Service) GetDashboard(rangeKey string, limit int) (Dashboard, error) {
+	return s.cachedDashboard(rangeKey, limit, VersionCollapseNone)
+}
+
+func (s *Service) getDashboard(rangeKey string, limit int, collapse string) (Dashboard, error) {
 	if s.db == nil {
 		return Dashboard{}, nil
 	}
@@ -191,18 +198,23 @@ func (s *Service) GetDashboard(rangeKey string, limit int) (Dashboard, error) {
 	now := time.Now().UTC()
 	rangeName, rangeStart := normalizeDashboardRange(rangeKey, now)
 	normalizedLimit := normalizeTopLimit(limit)
+	layout := s.GetDashboardLayout()
+	limits := layout.moduleLimits(normalizedLimit)
 
 	dashboard := Dashboard{
 		Range:              rangeName,
+		CollapseVersions:   collapse,
+		Modules:            layout.moduleIDs(),
 		GeneratedAt:        now.Format(time.RFC3339),
 		Heatmap:            make([]HeatmapDay, 0, dashboardShortDays),
-		TopTracks:          make([]TrackStat, 0, normalizedLimit),
-		TopArtists:         make([]ArtistStat, 0, normalizedLimit),
-		TopAlbums:          make([]AlbumStat, 0, normalizedLimit),
-		TopGenres:          make([]GenreStat, 0, normalizedLimit),
-		ReplayTracks:       make([]ReplayTrackStat, 0, normalizedLimit),
+		TopTracks:          make([]TrackStat, 0, limits[DashboardModuleTopTracks]),
+		TopArtists:         make([]ArtistStat, 0, limits[DashboardModuleTopArtists]),
+		TopAlbums:          make([]AlbumStat, 0, limits[DashboardModuleTopAlbums]),
+		TopGenres:          make([]GenreStat, 0, limits[DashboardModuleTopGenres]),
+		ReplayTracks:       make([]ReplayTrackStat, 0, limits[DashboardModuleReplayTracks]),
 		HourlyProfile:      make([]HourStat, 0, 24),
 		WeekdayProfile:     make([]WeekdayStat, 0, 7),
+		SessionLabels:      make([]SessionLabelStat, 0),
 		PeakHour:           -1,
 		PeakWeekday:        -1,
 		BehaviorWindowDays: dashboardBehaviorWindowDays,
@@ -213,7 +225,7 @@ func (s *Service) GetDashboard(rangeKey string, limit int) (Dashboard, error) {
 	}
 
 	ctx := context.Background()
-	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	tx, err := s.reader().BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
 		return Dashboard{}, err
 	}
@@ -229,67 +241,95 @@ func (s *Service) GetDashboard(rangeKey string, limit int) (Dashboard, error) {
 	dashboard.Quality = DashboardQuality{Score: summary.CompletionScore}
 	dashboard.Discovery = buildDiscovery(summary)
 
-	tracks, err := s.readDashboardTopTracks(ctx, tx, rangeStart, normalizedLimit)
-	if err != nil {
-		return Dashboard{}, err
+	if moduleLimit, ok := limits[DashboardModuleTopTracks]; ok {
+		tracks, err := s.readCollapsedTopTracks(ctx, tx, rangeStart, moduleLimit, collapse)
+		if err != nil {
+			return Dashboard{}, err
+		}
+		dashboard.TopTracks = tracks
 	}
-	dashboard.TopTracks = tracks
 
-	artists, err := s.readDashboardTopArtists(ctx, tx, rangeStart, normalizedLimit)
-	if err != nil {
-		return Dashboard{}, err
+	if moduleLimit, ok := limits[DashboardModuleTopArtists]; ok {
+		artists, err := s.readDashboardTopArtists(ctx, tx, rangeStart, moduleLimit)
+		if err != nil {
+			return Dashboard{}, err
+		}
+		dashboard.TopArtists = artists
 	}
-	dashboard.TopArtists = artists
 
-	albums, err := s.readDashboardTopAlbums(ctx, tx, rangeStart, normalizedLimit)
-	if err != nil {
-		return Dashboard{}, err
+	if moduleLimit, ok := limits[DashboardModuleTopAlbums]; ok {
+		albums, err := s.readDashboardTopAlbums(ctx, tx, rangeStart, moduleLimit)
+		if err != nil {
+			return Dashboard{}, err
+		}
+		dashboard.TopAlbums = albums
 	}
-	dashboard.TopAlbums = albums
 
-	genres, err := s.readDashboardTopGenres(ctx, tx, rangeStart, normalizedLimit)
-	if err != nil {
-		return Dashboard{}, err
+	if moduleLimit, ok := limits[DashboardModuleTopGenres]; ok {
+		genres, err := s.readDashboardTopGenres(ctx, tx, rangeStart, moduleLimit)
+		if err != nil {
+			return Dashboard{}, err
+		}
+		dashboard.TopGenres = genres
 	}
-	dashboard.TopGenres = genres
 
-	replays, err := s.readDashboardReplayTracks(ctx, tx, rangeStart, normalizedLimit)
-	if err != nil {
-		return Dashboard{}, err
+	if moduleLimit, ok := limits[DashboardModuleReplayTracks]; ok {
+		replays, err := s.readDashboardReplayTracks(ctx, tx, rangeStart, moduleLimit)
+		if err != nil {
+			return Dashboard{}, err
+		}
+		dashboard.ReplayTracks = replays
 	}
-	dashboard.ReplayTracks = replays
 
-	streak, err := s.readListeningStreak(ctx, tx)
-	if err != nil {
-		return Dashboard{}, err
+	if _, ok := limits[DashboardModuleStreak]; ok {
+		streak, err := s.readListeningStreak(ctx, tx)
+		if err != nil {
+			return Dashboard{}, err
+		}
+		dashboard.Streak = streak
 	}
-	dashboard.Streak = streak
 
-	heatmap, err := s.readHeatmap(ctx, tx, now)
-	if err != nil {
-		return Dashboard{}, err
+	if _, ok := limits[DashboardModuleHeatmap]; ok {
+		heatmap, err := s.readHeatmap(ctx, tx, now)
+		if err != nil {
+			return Dashboard{}, err
+		}
+		dashboard.Heatmap = heatmap
 	}
-	dashboard.Heatmap = heatmap
 
-	hourly, peakHour, err := s.readHourlyProfile(ctx, tx, now)
-	if err != nil {
-		return Dashboard{}, err
+	if _, ok := limits[DashboardModuleHourlyProfile]; ok {
+		hourly, peakHour, err := s.readHourlyProfile(ctx, tx, now)
+		if err != nil {
+			return Dashboard{}, err
+		}
+		dashboard.HourlyProfile = hourly
+		dashboard.PeakHour = peakHour
 	}
-	dashboard.HourlyProfile = hourly
-	dashboard.PeakHour = peakHour
 
-	weekday, peakWeekday, err := s.readWeekdayProfile(ctx, tx, now)
-	if err != nil {
-		return Dashboard{}, err
+	if _, ok := limits[DashboardModuleWeekdayProfile]; ok {
+		weekday, peakWeekday, err := s.readWeekdayProfile(ctx, tx, now)
+		if err != nil {
+			return Dashboard{}, err
+		}
+		dashboard.WeekdayProfile = weekday
+		dashboard.PeakWeekday = peakWeekday
 	}
-	dashboard.WeekdayProfile = weekday
-	dashboard.PeakWeekday = peakWeekday
 
-	sessionStats, err := s.readSessionStats(ctx, tx, now)
-	if err != nil {
-		return Dashboard{}, err
+	if _, ok := limits[DashboardModuleSession]; ok {
+		sessionStats, err := s.readSessionStats(ctx, tx, now)
+		if err != nil {
+			return Dashboard{}, err
+		}
+		dashboard.Session = sessionStats
+	}
+
+	if _, ok := limits[DashboardModuleSessionLabels]; ok {
+		sessionLabels, err := s.readSessionLabels(ctx, tx, rangeStart)
+		if err != nil {
+			return Dashboard{}, err
+		}
+		dashboard.SessionLabels = sessionLabels
 	}
-	dashboard.Session = sessionStats
 
 	if commitErr := tx.Commit(); commitErr != nil {
 		return Dashboard{}, commitErr
@@ -320,6 +360,7 @@ func (s *Service) readDashboardSummary(ctx context.Context, queryer dashboardQue
 			JOIN files f ON f.id = t.file_id
 			WHERE
 				f.file_exists = 1
+				AND f.is_audiobook = 0
 				AND (
 					tm.played_ms > 0
 					OR tm.complete_count > 0
@@ -384,6 +425,7 @@ func (s *Service) readDashboardTopTracks(ctx context.Context, queryer dashboardQ
 		LEFT JOIN covers cover ON cover.source_file_id = t.file_id
 		WHERE
 			f.file_exists = 1
+			AND f.is_audiobook = 0
 			AND (
 				tm.played_ms > 0
 				OR tm.complete_count > 0
@@ -400,7 +442,7 @@ func (s *Service) readDashboardTopTracks(ctx context.Context, queryer dashboardQ
 	}
 	defer rows.Close()
 
-	tracks := make([]TrackStat, 0, limit)
+	tracks := make([]TrackStat, 0, max(limit, 0))
 	for rows.Next() {
 		var item TrackStat
 		var coverPath sql.NullString
@@ -431,10 +473,10 @@ func (s *Service) readDashboardTopTracks(ctx context.Context, queryer dashboardQ
 
 func (s *Service) readDashboardTopArtists(ctx context.Context, queryer dashboardQueryer, rangeStart *time.Time, limit int) ([]ArtistStat, error) {
 	args := append(trackMetricsArgs(rangeStart), limit)
-	artistLabel := artistLabelExpr("t")
-	artistKey := artistKeyExpr("t")
 
-	query := trackMetricsCTE() + fmt.Sprintf(`
+	// Every artist credited on a track gets its plays, so a featured
+	// artist ranks too.
+	query := trackMetricsCTE() + `
 		, normalized_tracks AS (
 			SELECT
 				t.id AS track_id,
@@ -442,12 +484,14 @@ func (s *Service) readDashboardTopArtists(ctx context.Context, queryer dashboard
 				tm.complete_count,
 				tm.skip_count,
 				tm.partial_count,
-				%s AS artist_label,
-				%s AS artist_key
+				ta.artist AS artist_label,
+				LOWER(ta.artist) AS artist_key
 			FROM track_metrics tm
 			JOIN tracks t ON t.id = tm.track_id
+			JOIN track_artists ta ON ta.track_id = t.id
 			JOIN files f ON f.id = t.file_id
 			WHERE f.file_exists = 1
+			  AND f.is_audiobook = 0
 		)
 		SELECT
 			MIN(nt.artist_label) AS artist_name,
@@ -458,7 +502,7 @@ func (s *Service) readDashboardTopArtists(ctx context.Context, queryer dashboard
 		HAVING COALESCE(SUM(nt.played_ms), 0) > 0 OR COALESCE(SUM(nt.complete_count + nt.skip_count + nt.partial_count), 0) > 0
 		ORDER BY played_ms DESC, LOWER(artist_name)
 		LIMIT ?
-	`, artistLabel, artistKey)
+	`
 
 	rows, err := queryer.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -509,6 +553,7 @@ func (s *Service) readDashboardTopAlbums(ctx context.Context, queryer dashboardQ
 			LEFT JOIN albums a ON a.id = at.album_id
 			LEFT JOIN covers cover ON cover.id = a.cover_id
 			WHERE f.file_exists = 1
+			  AND f.is_audiobook = 0
 		)
 		SELECT
 			MIN(nt.album_title_label) AS album_title,
@@ -578,6 +623,7 @@ func (s *Service) readDashboardTopGenres(ctx context.Context, queryer dashboardQ
 			JOIN tracks t ON t.id = tm.track_id
 			JOIN files f ON f.id = t.file_id
 			WHERE f.file_exists = 1
+			  AND f.is_audiobook = 0
 		)
 		SELECT
 			MIN(nt.genre_label) AS genre_name,
@@ -642,6 +688,7 @@ func (s *Service) readDashboardReplayTracks(ctx context.Context, queryer dashboa
 		JOIN files f ON f.id = t.file_id
 		LEFT JOIN covers cover ON cover.source_file_id = t.file_id
 		WHERE f.file_exists = 1
+		  AND f.is_audiobook = 0
 		ORDER BY plays_per_day DESC, rm.total_plays DESC, rm.played_ms DESC, LOWER(track_title)
 		LIMIT ?
 	`
@@ -899,7 +946,10 @@ func (s *Service) readWeekdayProfile(ctx context.Context, queryer dashboardQuery
 		return nil, -1, rowsErr
 	}
 
-	weekdayLabels := []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+	s.mu.Lock()
+	localizer := s.localizer
+	s.mu.Unlock()
+
 	peakWeekday := -1
 	peakPlayed := -1
 	profile := make([]WeekdayStat, 0, 7)
@@ -908,13 +958,9 @@ func (s *Service) readWeekdayProfile(ctx context.Context, queryer dashboardQuery
 		if totalPlayed > 0 {
 			share = float64(playedMS) * 100 / float64(totalPlayed)
 		}
-		label := ""
-		if weekday >= 0 && weekday < len(weekdayLabels) {
-			label = weekdayLabels[weekday]
-		}
 		profile = append(profile, WeekdayStat{
 			Weekday:  weekday,
-			Label:    label,
+			Label:    localizer.Weekday(weekday),
 			PlayedMS: playedMS,
 			Share:    share,
 		})
@@ -1076,7 +1122,7 @@ func trackMetricsCTE() string {
 					COALESCE(SUM(complete_count), 0) AS complete_count,
 					COALESCE(SUM(skip_count), 0) AS skip_count,
 					COALESCE(SUM(partial_count), 0) AS partial_count
-				FROM play_stats_daily
+				FROM play_stats_combined
 				WHERE (? = '' OR day >= ?)
 				GROUP BY track_id
 			) AS metrics
@@ -1104,7 +1150,7 @@ func dayMetricsCTE() string {
 				COALESCE(SUM(complete_count), 0) AS complete_count,
 				COALESCE(SUM(skip_count), 0) AS skip_count,
 				COALESCE(SUM(partial_count), 0) AS partial_count
-			FROM play_stats_daily
+			FROM play_stats_combined
 			WHERE (? = '' OR day >= ?)
 			GROUP BY day
 		),
@@ -1142,7 +1188,7 @@ func dayTrackMetricsCTE() string {
 				track_id,
 				COALESCE(SUM(played_ms), 0) AS played_ms,
 				COALESCE(SUM(complete_count + skip_count + partial_count), 0) AS play_count
-			FROM play_stats_daily
+			FROM play_stats_combined
 			WHERE (? = '' OR day >= ?)
 			GROUP BY day, track_id
 		),
@@ -0,0 +1,114 @@
+package stats
+
+import "time"
+
+// dashboardCacheStaleEvents is how many play events have to be recorded
+// after a dashboard was computed before it is recomputed. Heartbeats arrive
+// every 30 seconds, so this is roughly ten minutes of listening.
+const dashboardCacheStaleEvents = 20
+
+type dashboardCacheKey struct {
+	rangeName string
+	day       string
+	limit     int
+	collapse  string
+}
+
+type dashboardCacheEntry struct {
+	dashboard  Dashboard
+	events     int64
+	refreshing bool
+}
+
+// cachedDashboard returns the dashboard for the range from the cache, which
+// holds one entry per range and day. A stale entry is still returned while a
+// fresh one is computed in the background; only a missing entry is computed
+// before returning.
+func (s *Service) cachedDashboard(rangeKey string, limit int, collapse string) (Dashboard, error) {
+	if s.db == nil {
+		return Dashboard{}, nil
+	}
+
+	now := time.Now().UTC()
+	rangeName, _ := normalizeDashboardRange(rangeKey, now)
+	key := dashboardCacheKey{
+		rangeName: rangeName,
+		day:       now.Format(dayKeyLayout),
+		limit:     normalizeTopLimit(limit),
+		collapse:  collapse,
+	}
+
+	s.mu.Lock()
+	events := s.dashboardEvents
+	generation := s.dashboardCacheGeneration
+	if entry, ok := s.dashboardCache[key]; ok {
+		if events-entry.events >= dashboardCacheStaleEvents && !entry.refreshing {
+			entry.refreshing = true
+			go s.refreshDashboard(key, rangeKey, limit, collapse, events, generation)
+		}
+		dashboard := entry.dashboard
+		s.mu.Unlock()
+		return dashboard, nil
+	}
+	s.mu.Unlock()
+
+	dashboard, err := s.getDashboard(rangeKey, limit, collapse)
+	if err != nil {
+		return Dashboard{}, err
+	}
+
+	s.storeDashboard(key, dashboard, events, generation)
+	return dashboard, nil
+}
+
+func (s *Service) refreshDashboard(key dashboardCacheKey, rangeKey string, limit int, collapse string, events int64, generation int) {
+	dashboard, err := s.getDashboard(rangeKey, limit, collapse)
+	if err != nil {
+		s.mu.Lock()
+		if entry, ok := s.dashboardCache[key]; ok {
+			entry.refreshing = false
+		}
+		s.mu.Unlock()
+		return
+	}
+
+	s.storeDashboard(key, dashboard, events, generation)
+}
+
+// storeDashboard caches a computed dashboard and drops entries from earlier
+// days, whose range windows no longer line up with today's. Dashboards
+// computed before the cache was invalidated are discarded.
+func (s *Service) storeDashboard(key dashboardCacheKey, dashboard Dashboard, events int64, generation int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if generation != s.dashboardCacheGeneration {
+		return
+	}
+
+	if s.dashboardCache == nil {
+		s.dashboardCache = make(map[dashboardCacheKey]*dashboardCacheEntry)
+	}
+	for cachedKey := range s.dashboardCache {
+		if cachedKey.day != key.day {
+			delete(s.dashboardCache, cachedKey)
+		}
+	}
+	s.dashboardCache[key] = &dashboardCacheEntry{dashboard: dashboard, events: events}
+}
+
+// noteDashboardEvents counts persisted play events towards cache staleness.
+func (s *Service) noteDashboardEvents(count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dashboardEvents += int64(count)
+}
+
+// InvalidateDashboardCache drops every cached dashboard, e.g. after the
+// layout changed or history was edited.
+func (s *Service) InvalidateDashboardCache() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dashboardCache = nil
+	s.dashboardCacheGeneration++
+}
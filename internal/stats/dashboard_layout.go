@@ -0,0 +1,196 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DashboardLayoutSettingKey stores the dashboard layout as JSON.
+const DashboardLayoutSettingKey = "stats.dashboard_layout"
+
+// Dashboard modules. The summary, quality and discovery figures are always
+// computed since the other modules are read relative to them.
+const (
+	DashboardModuleTopTracks      = "topTracks"
+	DashboardModuleTopArtists     = "topArtists"
+	DashboardModuleTopAlbums      = "topAlbums"
+	DashboardModuleTopGenres      = "topGenres"
+	DashboardModuleReplayTracks   = "replayTracks"
+	DashboardModuleStreak         = "streak"
+	DashboardModuleHeatmap        = "heatmap"
+	DashboardModuleHourlyProfile  = "hourlyProfile"
+	DashboardModuleWeekdayProfile = "weekdayProfile"
+	DashboardModuleSession        = "session"
+	DashboardModuleSessionLabels  = "sessionLabels"
+)
+
+var defaultDashboardModules = []string{
+	DashboardModuleTopTracks,
+	DashboardModuleTopArtists,
+	DashboardModuleTopAlbums,
+	DashboardModuleTopGenres,
+	DashboardModuleReplayTracks,
+	DashboardModuleStreak,
+	DashboardModuleHeatmap,
+	DashboardModuleHourlyProfile,
+	DashboardModuleWeekdayProfile,
+	DashboardModuleSession,
+	DashboardModuleSessionLabels,
+}
+
+var dashboardListModules = map[string]bool{
+	DashboardModuleTopTracks:    true,
+	DashboardModuleTopArtists:   true,
+	DashboardModuleTopAlbums:    true,
+	DashboardModuleTopGenres:    true,
+	DashboardModuleReplayTracks: true,
+}
+
+// DashboardModule is one enabled dashboard module. Limit sets the length of
+// top lists; zero uses the limit passed to GetDashboard.
+type DashboardModule struct {
+	ID    string `json:"id"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+// DashboardLayout lists the enabled modules in display order. Modules that
+// are left out are not queried.
+type DashboardLayout struct {
+	Modules []DashboardModule `json:"modules"`
+}
+
+func DefaultDashboardLayout() DashboardLayout {
+	modules := make([]DashboardModule, 0, len(defaultDashboardModules))
+	for _, id := range defaultDashboardModules {
+		modules = append(modules, DashboardModule{ID: id})
+	}
+
+	return DashboardLayout{Modules: modules}
+}
+
+func isDashboardModule(id string) bool {
+	for _, candidate := range defaultDashboardModules {
+		if candidate == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeDashboardLayout(layout DashboardLayout) (DashboardLayout, error) {
+	normalized := DashboardLayout{Modules: make([]DashboardModule, 0, len(layout.Modules))}
+	seen := make(map[string]bool, len(layout.Modules))
+	for _, module := range layout.Modules {
+		id := strings.TrimSpace(module.ID)
+		if !isDashboardModule(id) {
+			return DashboardLayout{}, fmt.Errorf("unknown dashboard module %q", module.ID)
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		limit := 0
+		if dashboardListModules[id] && module.Limit > 0 {
+			limit = normalizeTopLimit(module.Limit)
+		}
+		normalized.Modules = append(normalized.Modules, DashboardModule{ID: id, Limit: limit})
+	}
+
+	return normalized, nil
+}
+
+// moduleLimits maps each enabled module to its list length, falling back to
+// the requested limit.
+func (l DashboardLayout) moduleLimits(fallback int) map[string]int {
+	limits := make(map[string]int, len(l.Modules))
+	for _, module := range l.Modules {
+		limit := fallback
+		if module.Limit > 0 {
+			limit = module.Limit
+		}
+		limits[module.ID] = limit
+	}
+
+	return limits
+}
+
+func (l DashboardLayout) moduleIDs() []string {
+	ids := make([]string, 0, len(l.Modules))
+	for _, module := range l.Modules {
+		ids = append(ids, module.ID)
+	}
+
+	return ids
+}
+
+func (s *Service) GetDashboardLayout() DashboardLayout {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dashboardLayout
+}
+
+func (s *Service) SetDashboardLayout(layout DashboardLayout) (DashboardLayout, error) {
+	normalized, err := normalizeDashboardLayout(layout)
+	if err != nil {
+		return s.GetDashboardLayout(), err
+	}
+
+	if s.settings != nil {
+		encoded, err := json.Marshal(normalized)
+		if err != nil {
+			return s.GetDashboardLayout(), fmt.Errorf("encode dashboard layout: %w", err)
+		}
+		if err := s.settings.Set(context.Background(), DashboardLayoutSettingKey, string(encoded)); err != nil {
+			return s.GetDashboardLayout(), err
+		}
+	}
+
+	s.mu.Lock()
+	s.dashboardLayout = normalized
+	s.mu.Unlock()
+	s.InvalidateDashboardCache()
+
+	return normalized, nil
+}
+
+func (s *Service) ResetDashboardLayout() (DashboardLayout, error) {
+	if s.settings != nil {
+		if err := s.settings.Delete(context.Background(), DashboardLayoutSettingKey); err != nil {
+			return s.GetDashboardLayout(), err
+		}
+	}
+
+	layout := DefaultDashboardLayout()
+	s.mu.Lock()
+	s.dashboardLayout = layout
+	s.mu.Unlock()
+	s.InvalidateDashboardCache()
+
+	return layout, nil
+}
+
+func (s *Service) loadDashboardLayout() {
+	s.dashboardLayout = DefaultDashboardLayout()
+	if s.settings == nil {
+		return
+	}
+
+	raw, ok, err := s.settings.Get(context.Background(), DashboardLayoutSettingKey)
+	if err != nil || !ok {
+		return
+	}
+
+	var stored DashboardLayout
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+		return
+	}
+	normalized, err := normalizeDashboardLayout(stored)
+	if err != nil {
+		return
+	}
+	s.dashboardLayout = normalized
+}
@@ -1,6 +1,9 @@
 package stats
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestCompletionScore_AllCompletions(t *testing.T) {
 	score := completionScore(12, 0, 0)
@@ -29,3 +32,107 @@ func TestDiscoveryScore_AllRepeatsIsZero(t *testing.T) {
 		t.Fatalf("expected discovery score 0, got %f", discovery.Score)
 	}
 }
+
+func TestNormalizeDashboardLayout_DedupesAndClampsLimits(t *testing.T) {
+	layout, err := normalizeDashboardLayout(DashboardLayout{Modules: []DashboardModule{
+		{ID: DashboardModuleHeatmap, Limit: 10},
+		{ID: DashboardModuleTopTracks, Limit: 100},
+		{ID: DashboardModuleHeatmap},
+	}})
+	if err != nil {
+		t.Fatalf("normalize layout: %v", err)
+	}
+	if len(layout.Modules) != 2 {
+		t.Fatalf("expected 2 modules, got %d", len(layout.Modules))
+	}
+	if layout.Modules[0].ID != DashboardModuleHeatmap || layout.Modules[0].Limit != 0 {
+		t.Fatalf("expected heatmap without a limit first, got %+v", layout.Modules[0])
+	}
+	if layout.Modules[1].Limit != maxTopLimit {
+		t.Fatalf("expected top tracks limit %d, got %d", maxTopLimit, layout.Modules[1].Limit)
+	}
+
+	if _, err := normalizeDashboardLayout(DashboardLayout{Modules: []DashboardModule{{ID: "weather"}}}); err == nil {
+		t.Fatalf("expected unknown module to be rejected")
+	}
+}
+
+func TestDashboardLayout_SkipsDisabledModulesAndPersists(t *testing.T) {
+	service, database := newStatsServiceForTest(t)
+	defer database.Close()
+
+	if _, err := service.SetDashboardLayout(DashboardLayout{Modules: []DashboardModule{
+		{ID: DashboardModuleTopArtists, Limit: 2},
+	}}); err != nil {
+		t.Fatalf("set dashboard layout: %v", err)
+	}
+
+	dashboard, err := service.GetDashboard(DashboardRangeLong, 10)
+	if err != nil {
+		t.Fatalf("get dashboard: %v", err)
+	}
+	if len(dashboard.Modules) != 1 || dashboard.Modules[0] != DashboardModuleTopArtists {
+		t.Fatalf("expected only top artists, got %v", dashboard.Modules)
+	}
+	if dashboard.PeakHour != -1 || len(dashboard.HourlyProfile) != 0 {
+		t.Fatalf("expected hourly profile to be skipped")
+	}
+
+	reloaded := NewService(database, nil).GetDashboardLayout()
+	if len(reloaded.Modules) != 1 || reloaded.Modules[0].Limit != 2 {
+		t.Fatalf("expected layout to persist, got %+v", reloaded)
+	}
+
+	reset, err := service.ResetDashboardLayout()
+	if err != nil {
+		t.Fatalf("reset dashboard layout: %v", err)
+	}
+	if len(reset.Modules) != len(defaultDashboardModules) {
+		t.Fatalf("expected default modules after reset, got %d", len(reset.Modules))
+	}
+}
+
+func TestDashboardCache_ServesCachedUntilEventThreshold(t *testing.T) {
+	service, database := newStatsServiceForTest(t)
+	defer database.Close()
+
+	trackID := insertTrackForStatsTest(t, database, "Cached", "Band")
+	playedAt := time.Now().UTC().Add(-time.Hour)
+	insertPlayEventForStatsTest(t, database, trackID, EventHeartbeat, 30000, playedAt)
+
+	first, err := service.GetDashboard(DashboardRangeLong, 10)
+	if err != nil {
+		t.Fatalf("get dashboard: %v", err)
+	}
+
+	insertPlayEventForStatsTest(t, database, trackID, EventHeartbeat, 30000, playedAt.Add(time.Minute))
+	service.noteDashboardEvents(1)
+
+	cached, err := service.GetDashboard(DashboardRangeLong, 10)
+	if err != nil {
+		t.Fatalf("get cached dashboard: %v", err)
+	}
+	if cached.Summary.TotalPlayedMS != first.Summary.TotalPlayedMS {
+		t.Fatalf("expected cached summary below the event threshold")
+	}
+
+	service.noteDashboardEvents(dashboardCacheStaleEvents)
+	if _, err := service.GetDashboard(DashboardRangeLong, 10); err != nil {
+		t.Fatalf("get stale dashboard: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		refreshed, err := service.GetDashboard(DashboardRangeLong, 10)
+		if err != nil {
+			t.Fatalf("get refreshed dashboard: %v", err)
+		}
+		if refreshed.Summary.TotalPlayedMS == 60000 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected dashboard to be recomputed in the background")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,163 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HeartbeatIntervalSettingKey stores the listening time, in seconds, each
+// heartbeat event records.
+const HeartbeatIntervalSettingKey = "stats.heartbeat_interval_seconds"
+
+const (
+	defaultHeartbeatInterval = 30 * time.Second
+	minHeartbeatInterval     = 10 * time.Second
+	maxHeartbeatInterval     = 5 * time.Minute
+)
+
+// eventCommitInterval is how long queued heartbeats wait, so the events of
+// a few state changes are written in one transaction.
+const eventCommitInterval = 5 * time.Second
+
+// maxEventsPerInsert keeps a batched INSERT well below SQLite's limit on
+// bound parameters.
+const maxEventsPerInsert = 100
+
+func (s *Service) loadHeartbeatInterval() {
+	s.heartbeatInterval = defaultHeartbeatInterval
+	if s.settings == nil {
+		return
+	}
+
+	raw := s.settings.GetString(context.Background(), HeartbeatIntervalSettingKey, "")
+	seconds, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil {
+		return
+	}
+	s.heartbeatInterval = clampHeartbeatInterval(time.Duration(seconds) * time.Second)
+}
+
+// HeartbeatIntervalSeconds returns how much listening time each heartbeat
+// records. Longer intervals write less often; the time played since the
+// last heartbeat is still written on pauses, track ends and Flush.
+func (s *Service) HeartbeatIntervalSeconds() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return int(s.heartbeatInterval / time.Second)
+}
+
+// SetHeartbeatIntervalSeconds stores the heartbeat interval, clamped to
+// 10 seconds through 5 minutes, and returns the value in effect.
+func (s *Service) SetHeartbeatIntervalSeconds(seconds int) (int, error) {
+	interval := clampHeartbeatInterval(time.Duration(seconds) * time.Second)
+	if s.settings != nil {
+		if err := s.settings.Set(context.Background(), HeartbeatIntervalSettingKey, strconv.Itoa(int(interval/time.Second))); err != nil {
+			return s.HeartbeatIntervalSeconds(), err
+		}
+	}
+
+	s.mu.Lock()
+	s.heartbeatInterval = interval
+	s.mu.Unlock()
+	return int(interval / time.Second), nil
+}
+
+func clampHeartbeatInterval(interval time.Duration) time.Duration {
+	return min(max(interval, minHeartbeatInterval), maxHeartbeatInterval)
+}
+
+// queueEvents holds events for the next group commit, which runs
+// eventCommitInterval after the first of them was queued.
+func (s *Service) queueEvents(events []playEvent, sessionLabel string) {
+	if len(events) == 0 || s.db == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range events {
+		event.sessionLabel = sessionLabel
+		s.queuedEvents = append(s.queuedEvents, event)
+	}
+	if s.commitTimer == nil {
+		s.commitTimer = time.AfterFunc(eventCommitInterval, func() {
+			s.persistEvents(nil, "")
+		})
+	}
+}
+
+// persistEvents writes the queued events and then events in one
+// transaction, with as few INSERT statements as the batch size allows.
+func (s *Service) persistEvents(events []playEvent, sessionLabel string) {
+	if s.db == nil {
+		return
+	}
+
+	s.mu.Lock()
+	batch := s.queuedEvents
+	s.queuedEvents = nil
+	if s.commitTimer != nil {
+		s.commitTimer.Stop()
+		s.commitTimer = nil
+	}
+	s.mu.Unlock()
+
+	for _, event := range events {
+		event.sessionLabel = sessionLabel
+		batch = append(batch, event)
+	}
+	batch = validPlayEvents(batch)
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for start := 0; start < len(batch); start += maxEventsPerInsert {
+		chunk := batch[start:min(start+maxEventsPerInsert, len(batch))]
+		query, args := playEventsInsert(chunk)
+		if _, execErr := tx.ExecContext(ctx, query, args...); execErr != nil {
+			return
+		}
+	}
+
+	if tx.Commit() == nil {
+		s.noteDashboardEvents(len(batch))
+	}
+}
+
+func validPlayEvents(events []playEvent) []playEvent {
+	valid := events[:0]
+	for _, event := range events {
+		if event.trackID > 0 {
+			valid = append(valid, event)
+		}
+	}
+	return valid
+}
+
+// playEventsInsert builds one multi-row INSERT for events.
+func playEventsInsert(events []playEvent) (string, []any) {
+	rows := make([]string, 0, len(events))
+	args := make([]any, 0, len(events)*5)
+	for _, event := range events {
+		rows = append(rows, "(?, ?, ?, ?, NULLIF(?, ''))")
+		args = append(args, event.trackID, event.eventType, event.position, event.at.UTC().Format(time.RFC3339), event.sessionLabel)
+	}
+
+	return fmt.Sprintf(
+		"INSERT INTO play_events(track_id, event_type, position_ms, ts, session_label) VALUES %s",
+		strings.Join(rows, ", "),
+	), args
+}
@@ -0,0 +1,97 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/player"
+)
+
+func TestHeartbeatsWaitForGroupCommit(t *testing.T) {
+	t.Parallel()
+
+	service, database := newStatsServiceForTest(t)
+	defer database.Close()
+
+	trackID := insertTrackForStatsTest(t, database, "Long Session", "Testing Artist")
+	durationMS := 10 * 60 * 1000
+	startedAt := time.Date(2026, time.March, 2, 20, 0, 0, 0, time.UTC)
+	track := &library.TrackSummary{ID: trackID, DurationMS: &durationMS}
+
+	for step := 0; step <= 3; step++ {
+		service.HandlePlayerState(player.State{
+			Status:       player.StatusPlaying,
+			PositionMS:   step * 20000,
+			CurrentTrack: track,
+			DurationMS:   &durationMS,
+			UpdatedAt:    startedAt.Add(time.Duration(step) * 20 * time.Second).Format(time.RFC3339),
+		})
+	}
+
+	countEvents := func() (int, int) {
+		t.Helper()
+		var rows, playedMS int
+		if err := database.QueryRow(
+			`SELECT COUNT(1), COALESCE(SUM(position_ms), 0) FROM play_events WHERE track_id = ? AND event_type = ?`,
+			trackID,
+			EventHeartbeat,
+		).Scan(&rows, &playedMS); err != nil {
+			t.Fatalf("query heartbeat rows: %v", err)
+		}
+		return rows, playedMS
+	}
+
+	if rows, _ := countEvents(); rows != 0 {
+		t.Fatalf("expected heartbeats to wait for the group commit, got %d rows", rows)
+	}
+
+	service.persistEvents(nil, "")
+	if rows, playedMS := countEvents(); rows != 2 || playedMS != 60000 {
+		t.Fatalf("expected two 30s heartbeats in one commit, got %d rows with %d ms", rows, playedMS)
+	}
+}
+
+func TestSetHeartbeatIntervalClampsAndPersists(t *testing.T) {
+	t.Parallel()
+
+	service, database := newStatsServiceForTest(t)
+	defer database.Close()
+
+	if got := service.HeartbeatIntervalSeconds(); got != 30 {
+		t.Fatalf("expected the default interval of 30s, got %d", got)
+	}
+
+	got, err := service.SetHeartbeatIntervalSeconds(1)
+	if err != nil {
+		t.Fatalf("set heartbeat interval: %v", err)
+	}
+	if got != 10 {
+		t.Fatalf("expected the interval to be clamped to 10s, got %d", got)
+	}
+
+	if _, err := service.SetHeartbeatIntervalSeconds(120); err != nil {
+		t.Fatalf("set heartbeat interval: %v", err)
+	}
+	if reloaded := NewService(database, nil).HeartbeatIntervalSeconds(); reloaded != 120 {
+		t.Fatalf("expected the stored interval to be loaded, got %d", reloaded)
+	}
+}
+
+func TestPlayEventsInsertBuildsOneStatement(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, time.March, 2, 20, 0, 0, 0, time.UTC)
+	query, args := playEventsInsert([]playEvent{
+		{trackID: 1, eventType: EventHeartbeat, position: 30000, at: at},
+		{trackID: 2, eventType: EventComplete, position: 180000, at: at, sessionLabel: "Focus"},
+	})
+
+	if want := "VALUES (?, ?, ?, ?, NULLIF(?, '')), (?, ?, ?, ?, NULLIF(?, ''))"; !strings.HasSuffix(query, want) {
+		t.Fatalf("unexpected batched insert: %s", query)
+	}
+	if len(args) != 10 || args[9] != "Focus" {
+		t.Fatalf("unexpected insert arguments: %v", args)
+	}
+}
@@ -0,0 +1,262 @@
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/rzxx/ben/internal/library"
+)
+
+// introWindowMS is how far into a track a play may be and still count as
+// starting at the top, and how late a seek may start to count as skipping
+// the intro.
+const introWindowMS = 10000
+
+// introSkipMinJumpMS ignores small nudges forward.
+const introSkipMinJumpMS = 5000
+
+// introSkipMaxTargetMS bounds what counts as an intro.
+const introSkipMaxTargetMS = 120000
+
+// introSkipObservationLimit keeps suggestions to recent listening, so a
+// track whose intro grew on the listener stops being suggested.
+const introSkipObservationLimit = 10
+
+const introSkipMinSeeks = 3
+
+const introSkipMinPercent = 60
+
+// introSkipSpreadMS is how close seek targets must be to the suggestion to
+// count as the same habit.
+const introSkipSpreadMS = 8000
+
+const introObservationRetentionDays = 365
+
+const defaultTrimSuggestionLimit = 20
+
+// TrimSuggestion proposes a start trim for a track the listener keeps
+// seeking past the intro of.
+type TrimSuggestion struct {
+	TrackID          int64   `json:"trackId"`
+	Title            string  `json:"title"`
+	Artist           string  `json:"artist"`
+	Album            string  `json:"album"`
+	CoverPath        *string `json:"coverPath,omitempty"`
+	DurationMS       *int    `json:"durationMs,omitempty"`
+	SuggestedStartMS int     `json:"suggestedStartMs"`
+	SeekCount        int     `json:"seekCount"`
+	PlayCount        int     `json:"playCount"`
+}
+
+type introObservation struct {
+	trackID     int64
+	skippedToMS int
+	at          time.Time
+}
+
+// detectIntroSkip reports whether a move from previousMS to positionMS
+// after elapsedMS of playback is a seek past the intro.
+func detectIntroSkip(previousMS int, positionMS int, elapsedMS int) bool {
+	if previousMS > introWindowMS || positionMS > introSkipMaxTargetMS {
+		return false
+	}
+
+	return positionMS-(previousMS+max(elapsedMS, 0)) >= introSkipMinJumpMS
+}
+
+// suggestIntroTrim returns the start trim for a track's recent intro
+// observations, where 0 means the intro was played. Enough plays must skip
+// the intro, and most skips must land near the same spot; the suggestion
+// is the median target rounded down to the second so no music is lost.
+func suggestIntroTrim(skippedTo []int) (int, int, bool) {
+	seeks := make([]int, 0, len(skippedTo))
+	for _, target := range skippedTo {
+		if target > 0 {
+			seeks = append(seeks, target)
+		}
+	}
+	if len(seeks) < introSkipMinSeeks || len(seeks)*100 < len(skippedTo)*introSkipMinPercent {
+		return 0, len(seeks), false
+	}
+
+	slices.Sort(seeks)
+	median := seeks[len(seeks)/2]
+
+	clustered := 0
+	for _, target := range seeks {
+		if target >= median-introSkipSpreadMS && target <= median+introSkipSpreadMS {
+			clustered++
+		}
+	}
+	if clustered*100 < len(seeks)*introSkipMinPercent {
+		return 0, len(seeks), false
+	}
+
+	return median / 1000 * 1000, len(seeks), true
+}
+
+func (s *Service) persistIntroObservation(observation introObservation) {
+	if observation.trackID <= 0 || s.db == nil {
+		return
+	}
+
+	var skippedTo any
+	if observation.skippedToMS > 0 {
+		skippedTo = observation.skippedToMS
+	}
+
+	_, _ = s.db.ExecContext(
+		context.Background(),
+		"INSERT INTO track_intro_observations(track_id, skipped_to_ms, ts) VALUES (?, ?, ?)",
+		observation.trackID,
+		skippedTo,
+		observation.at.UTC().Format(time.RFC3339),
+	)
+}
+
+// GetTrimSuggestions lists tracks whose intro the listener consistently
+// seeks past, most skipped first. Tracks that already have a start trim
+// are left out.
+func (s *Service) GetTrimSuggestions(limit int) ([]TrimSuggestion, error) {
+	if limit <= 0 {
+		limit = defaultTrimSuggestionLimit
+	}
+	if s.db == nil {
+		return []TrimSuggestion{}, nil
+	}
+
+	rows, err := s.db.QueryContext(context.Background(), `
+		WITH recent AS (
+			SELECT
+				track_id,
+				COALESCE(skipped_to_ms, 0) AS skipped_to_ms,
+				ROW_NUMBER() OVER (PARTITION BY track_id ORDER BY ts DESC, id DESC) AS recency
+			FROM track_intro_observations
+		)
+		SELECT
+			t.id,
+			COALESCE(NULLIF(TRIM(t.title), ''), 'Unknown Title'),
+			COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist'),
+			COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album'),
+			cover.cache_path,
+			t.duration_ms,
+			r.skipped_to_ms
+		FROM recent r
+		JOIN tracks t ON t.id = r.track_id
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN covers cover ON cover.source_file_id = t.file_id
+		LEFT JOIN track_trims tt ON tt.track_id = t.id
+		WHERE r.recency <= ?
+		  AND f.file_exists = 1
+		  AND COALESCE(tt.start_ms, 0) = 0
+		ORDER BY t.id, r.recency
+	`, introSkipObservationLimit)
+	if err != nil {
+		return nil, fmt.Errorf("query intro observations: %w", err)
+	}
+	defer rows.Close()
+
+	suggestions := make([]TrimSuggestion, 0)
+	var current TrimSuggestion
+	var skippedTo []int
+	flush := func() {
+		if current.TrackID == 0 {
+			return
+		}
+		startMS, seekCount, ok := suggestIntroTrim(skippedTo)
+		if ok && (current.DurationMS == nil || startMS < *current.DurationMS/2) {
+			current.SuggestedStartMS = startMS
+			current.SeekCount = seekCount
+			current.PlayCount = len(skippedTo)
+			suggestions = append(suggestions, current)
+		}
+	}
+
+	for rows.Next() {
+		var item TrimSuggestion
+		var coverPath sql.NullString
+		var durationMS sql.NullInt64
+		var target int
+		if err := rows.Scan(&item.TrackID, &item.Title, &item.Artist, &item.Album, &coverPath, &durationMS, &target); err != nil {
+			return nil, fmt.Errorf("scan intro observation: %w", err)
+		}
+		if item.TrackID != current.TrackID {
+			flush()
+			item.CoverPath = nullableStringPointer(coverPath)
+			if durationMS.Valid && durationMS.Int64 > 0 {
+				value := int(durationMS.Int64)
+				item.DurationMS = &value
+			}
+			current = item
+			skippedTo = skippedTo[:0]
+		}
+		skippedTo = append(skippedTo, target)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate intro observations: %w", err)
+	}
+	flush()
+
+	slices.SortStableFunc(suggestions, func(a TrimSuggestion, b TrimSuggestion) int {
+		return b.SeekCount - a.SeekCount
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions, nil
+}
+
+// ApplyTrimSuggestion sets the suggested start trim on a track, keeping any
+// end trim. The observations are cleared because later positions are
+// relative to the new start.
+func (s *Service) ApplyTrimSuggestion(trackID int64) (library.TrackTrim, error) {
+	suggestions, err := s.GetTrimSuggestions(math.MaxInt)
+	if err != nil {
+		return library.TrackTrim{}, err
+	}
+
+	index := slices.IndexFunc(suggestions, func(suggestion TrimSuggestion) bool {
+		return suggestion.TrackID == trackID
+	})
+	if index < 0 {
+		return library.TrackTrim{}, errors.New("no trim suggestion for this track")
+	}
+
+	ctx := context.Background()
+	trims := library.NewTrackTrimRepository(s.db)
+	existing, err := trims.GetTrackTrims(ctx, trackID)
+	if err != nil {
+		return library.TrackTrim{}, err
+	}
+
+	trim, err := trims.SetTrackTrims(ctx, trackID, suggestions[index].SuggestedStartMS, existing.EndMS)
+	if err != nil {
+		return library.TrackTrim{}, err
+	}
+
+	if err := s.DismissTrimSuggestion(trackID); err != nil {
+		return library.TrackTrim{}, err
+	}
+
+	return trim, nil
+}
+
+// DismissTrimSuggestion forgets the intro observations of a track, so it is
+// only suggested again once the listener keeps skipping the intro.
+func (s *Service) DismissTrimSuggestion(trackID int64) error {
+	if s.db == nil {
+		return nil
+	}
+
+	if _, err := s.db.ExecContext(context.Background(), "DELETE FROM track_intro_observations WHERE track_id = ?", trackID); err != nil {
+		return fmt.Errorf("clear intro observations for track %d: %w", trackID, err)
+	}
+
+	return nil
+}
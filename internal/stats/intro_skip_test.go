@@ -0,0 +1,39 @@
+package stats
+
+import "testing"
+
+func TestDetectIntroSkipNeedsAJumpFromTheIntro(t *testing.T) {
+	t.Parallel()
+
+	if !detectIntroSkip(2000, 24000, 500) {
+		t.Fatal("expected a jump from 2s to 24s to skip the intro")
+	}
+	if detectIntroSkip(2000, 5000, 500) {
+		t.Fatal("expected a small nudge not to count")
+	}
+	if detectIntroSkip(2000, 24000, 21000) {
+		t.Fatal("expected regular playback not to count")
+	}
+	if detectIntroSkip(30000, 60000, 500) {
+		t.Fatal("expected a seek from mid-track not to count")
+	}
+	if detectIntroSkip(1000, 200000, 500) {
+		t.Fatal("expected a seek past the intro window not to count")
+	}
+}
+
+func TestSuggestIntroTrimWantsAConsistentHabit(t *testing.T) {
+	t.Parallel()
+
+	startMS, seekCount, ok := suggestIntroTrim([]int{21400, 0, 19800, 20500, 22100})
+	if !ok || startMS != 21000 || seekCount != 4 {
+		t.Fatalf("expected a 21s suggestion from 4 seeks, got %d from %d (ok=%v)", startMS, seekCount, ok)
+	}
+
+	if _, _, ok := suggestIntroTrim([]int{20000, 0, 0, 21000, 0}); ok {
+		t.Fatal("expected occasional skips not to be suggested")
+	}
+	if _, _, ok := suggestIntroTrim([]int{15000, 45000, 90000}); ok {
+		t.Fatal("expected scattered seek targets not to be suggested")
+	}
+}
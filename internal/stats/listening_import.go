@@ -0,0 +1,503 @@
+package stats
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxDeviceNameLength = 64
+
+const maxUnmatchedSamples = 20
+
+const scrobblerLogHeader = "#AUDIOSCROBBLER"
+
+// ListeningImportResult summarizes an imported listening log. Plays already
+// imported from the same device are counted as duplicates, so a log can be
+// imported again after the device appended to it.
+type ListeningImportResult struct {
+	Device           string   `json:"device"`
+	Imported         int      `json:"imported"`
+	Duplicates       int      `json:"duplicates"`
+	Unmatched        int      `json:"unmatched"`
+	Invalid          int      `json:"invalid"`
+	UnmatchedSamples []string `json:"unmatchedSamples"`
+}
+
+// externalPlay is one play read from a listening log. playedMS is zero when
+// the log does not say how much was heard.
+type externalPlay struct {
+	at       time.Time
+	trackID  int64
+	artist   string
+	title    string
+	album    string
+	playedMS int
+	skipped  bool
+}
+
+func (p externalPlay) label() string {
+	return p.artist + " - " + p.title
+}
+
+// ImportListeningLogFile reads a listening log from a phone or portable
+// player and records its plays under device.
+func (s *Service) ImportListeningLogFile(path string, device string) (ListeningImportResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ListeningImportResult{}, fmt.Errorf("open listening log: %w", err)
+	}
+	defer file.Close()
+
+	return s.ImportListeningLog(file, device)
+}
+
+// ImportListeningLog records the plays of a listening log under device. Two
+// formats are read: the .scrobbler.log written by Rockbox and similar
+// players, and CSV with a header naming at least timestamp, artist and title
+// (album, played_ms, duration_ms, track_id and skipped are optional).
+//
+// Plays inside the raw event window become play events tagged with the
+// device; older plays are added to the daily rollup directly, since their
+// days have already been compacted.
+func (s *Service) ImportListeningLog(reader io.Reader, device string) (ListeningImportResult, error) {
+	normalizedDevice := strings.Join(strings.Fields(device), " ")
+	if normalizedDevice == "" {
+		return ListeningImportResult{}, errors.New("device name is required")
+	}
+	if len([]rune(normalizedDevice)) > maxDeviceNameLength {
+		return ListeningImportResult{}, fmt.Errorf("device name exceeds %d characters", maxDeviceNameLength)
+	}
+	if s.db == nil {
+		return ListeningImportResult{Device: normalizedDevice}, nil
+	}
+
+	plays, invalid, err := parseListeningLog(reader)
+	if err != nil {
+		return ListeningImportResult{}, err
+	}
+
+	result := ListeningImportResult{
+		Device:           normalizedDevice,
+		Invalid:          invalid,
+		UnmatchedSamples: make([]string, 0),
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ListeningImportResult{}, fmt.Errorf("begin listening import: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	index, err := loadListeningTrackIndex(ctx, tx)
+	if err != nil {
+		return ListeningImportResult{}, err
+	}
+
+	now := time.Now().UTC()
+	cutoff := startOfUTCDay(now).AddDate(0, 0, -rawEventRetentionDays)
+	for _, play := range plays {
+		track, ok := index.match(play)
+		if !ok || play.at.After(now) {
+			result.Unmatched++
+			if len(result.UnmatchedSamples) < maxUnmatchedSamples {
+				result.UnmatchedSamples = append(result.UnmatchedSamples, play.label())
+			}
+			continue
+		}
+
+		at := play.at.UTC().Format(time.RFC3339)
+		inserted, err := tx.ExecContext(
+			ctx,
+			"INSERT OR IGNORE INTO play_imports(device, track_id, ts) VALUES (?, ?, ?)",
+			normalizedDevice,
+			track.id,
+			at,
+		)
+		if err != nil {
+			return ListeningImportResult{}, fmt.Errorf("record imported play: %w", err)
+		}
+		if affected, _ := inserted.RowsAffected(); affected == 0 {
+			result.Duplicates++
+			continue
+		}
+
+		playedMS := play.playedMS
+		if playedMS <= 0 && !play.skipped {
+			playedMS = track.durationMS
+		}
+
+		if play.at.Before(cutoff) {
+			err = addImportedDailyPlay(ctx, tx, track.id, play, playedMS)
+		} else {
+			err = insertImportedPlayEvents(ctx, tx, track.id, at, play, playedMS, normalizedDevice)
+		}
+		if err != nil {
+			return ListeningImportResult{}, err
+		}
+		result.Imported++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ListeningImportResult{}, fmt.Errorf("commit listening import: %w", err)
+	}
+	if result.Imported > 0 {
+		s.InvalidateDashboardCache()
+	}
+
+	return result, nil
+}
+
+func insertImportedPlayEvents(ctx context.Context, tx *sql.Tx, trackID int64, at string, play externalPlay, playedMS int, device string) error {
+	endEvent := EventComplete
+	if play.skipped {
+		endEvent = EventSkip
+	}
+
+	if playedMS > 0 {
+		if _, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO play_events(track_id, event_type, position_ms, ts, device) VALUES (?, ?, ?, ?, ?)",
+			trackID,
+			EventHeartbeat,
+			playedMS,
+			at,
+			device,
+		); err != nil {
+			return fmt.Errorf("insert imported play event: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO play_events(track_id, event_type, position_ms, ts, device) VALUES (?, ?, ?, ?, ?)",
+		trackID,
+		endEvent,
+		playedMS,
+		at,
+		device,
+	); err != nil {
+		return fmt.Errorf("insert imported play event: %w", err)
+	}
+
+	return nil
+}
+
+func addImportedDailyPlay(ctx context.Context, tx *sql.Tx, trackID int64, play externalPlay, playedMS int) error {
+	heartbeats := 0
+	if playedMS > 0 {
+		heartbeats = 1
+	}
+	completes := 1
+	skips := 0
+	if play.skipped {
+		completes = 0
+		skips = 1
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO play_stats_daily(day, track_id, played_ms, heartbeat_count, complete_count, skip_count, partial_count, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, 0, ?)
+		 ON CONFLICT(day, track_id) DO UPDATE SET
+		   played_ms = play_stats_daily.played_ms + excluded.played_ms,
+		   heartbeat_count = play_stats_daily.heartbeat_count + excluded.heartbeat_count,
+		   complete_count = play_stats_daily.complete_count + excluded.complete_count,
+		   skip_count = play_stats_daily.skip_count + excluded.skip_count,
+		   updated_at = excluded.updated_at`,
+		play.at.UTC().Format(dayKeyLayout),
+		trackID,
+		playedMS,
+		heartbeats,
+		completes,
+		skips,
+		time.Now().UTC().Format(time.RFC3339),
+	); err != nil {
+		return fmt.Errorf("add imported daily play: %w", err)
+	}
+
+	return nil
+}
+
+type listeningTrack struct {
+	id         int64
+	album      string
+	durationMS int
+}
+
+// listeningTrackIndex matches plays by track id or by case-insensitive
+// artist and title, preferring the track on the logged album.
+type listeningTrackIndex struct {
+	byID  map[int64]listeningTrack
+	byKey map[string][]listeningTrack
+}
+
+func listeningTrackKey(artist string, title string) string {
+	return strings.ToLower(strings.TrimSpace(artist)) + "\x1f" + strings.ToLower(strings.TrimSpace(title))
+}
+
+func loadListeningTrackIndex(ctx context.Context, queryer dashboardQueryer) (listeningTrackIndex, error) {
+	rows, err := queryer.QueryContext(ctx, `
+		SELECT t.id, COALESCE(t.artist, ''), COALESCE(t.title, ''), COALESCE(t.album, ''), COALESCE(t.duration_ms, 0)
+		FROM tracks t
+		JOIN files f ON f.id = t.file_id
+		WHERE f.file_exists = 1
+		ORDER BY t.id
+	`)
+	if err != nil {
+		return listeningTrackIndex{}, fmt.Errorf("load tracks for listening import: %w", err)
+	}
+	defer rows.Close()
+
+	index := listeningTrackIndex{
+		byID:  make(map[int64]listeningTrack),
+		byKey: make(map[string][]listeningTrack),
+	}
+	for rows.Next() {
+		var track listeningTrack
+		var artist string
+		var title string
+		if scanErr := rows.Scan(&track.id, &artist, &title, &track.album, &track.durationMS); scanErr != nil {
+			return listeningTrackIndex{}, fmt.Errorf("scan listening track row: %w", scanErr)
+		}
+		index.byID[track.id] = track
+		key := listeningTrackKey(artist, title)
+		index.byKey[key] = append(index.byKey[key], track)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return listeningTrackIndex{}, fmt.Errorf("iterate listening track rows: %w", rowsErr)
+	}
+
+	return index, nil
+}
+
+func (i listeningTrackIndex) match(play externalPlay) (listeningTrack, bool) {
+	if play.trackID > 0 {
+		track, ok := i.byID[play.trackID]
+		return track, ok
+	}
+
+	candidates := i.byKey[listeningTrackKey(play.artist, play.title)]
+	if len(candidates) == 0 {
+		return listeningTrack{}, false
+	}
+	for _, candidate := range candidates {
+		if play.album != "" && strings.EqualFold(strings.TrimSpace(candidate.album), strings.TrimSpace(play.album)) {
+			return candidate, true
+		}
+	}
+
+	return candidates[0], true
+}
+
+// parseListeningLog reads every play from the log and counts the rows it
+// could not read.
+func parseListeningLog(reader io.Reader) ([]externalPlay, int, error) {
+	buffered := bufio.NewReader(reader)
+	head, err := buffered.Peek(len(scrobblerLogHeader))
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, 0, fmt.Errorf("read listening log: %w", err)
+	}
+
+	if bytes.HasPrefix(head, []byte(scrobblerLogHeader)) {
+		return parseScrobblerLog(buffered)
+	}
+
+	return parseListeningCSV(buffered)
+}
+
+// parseScrobblerLog reads the Audioscrobbler 1.1 portable log: tab-separated
+// artist, album, title, track number, duration in seconds, L(istened) or
+// S(kipped), unix timestamp and MusicBrainz id. With "#TZ/UNKNOWN" the
+// timestamps hold the player's local wall clock.
+func parseScrobblerLog(reader io.Reader) ([]externalPlay, int, error) {
+	plays := make([]externalPlay, 0)
+	invalid := 0
+	localClock := false
+
+	lines := bufio.NewScanner(reader)
+	for lines.Scan() {
+		line := strings.TrimRight(lines.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			if strings.HasPrefix(line, "#TZ/") {
+				localClock = strings.TrimPrefix(line, "#TZ/") != "UTC"
+			}
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) < 7 {
+			invalid++
+			continue
+		}
+
+		seconds, err := strconv.ParseInt(strings.TrimSpace(fields[6]), 10, 64)
+		if err != nil || strings.TrimSpace(fields[0]) == "" || strings.TrimSpace(fields[2]) == "" {
+			invalid++
+			continue
+		}
+		at := time.Unix(seconds, 0).UTC()
+		if localClock {
+			at = time.Date(at.Year(), at.Month(), at.Day(), at.Hour(), at.Minute(), at.Second(), 0, time.Local)
+		}
+
+		play := externalPlay{
+			at:      at,
+			artist:  strings.TrimSpace(fields[0]),
+			album:   strings.TrimSpace(fields[1]),
+			title:   strings.TrimSpace(fields[2]),
+			skipped: strings.EqualFold(strings.TrimSpace(fields[5]), "S"),
+		}
+		if durationSeconds, err := strconv.Atoi(strings.TrimSpace(fields[4])); err == nil && durationSeconds > 0 && !play.skipped {
+			play.playedMS = durationSeconds * 1000
+		}
+		plays = append(plays, play)
+	}
+	if err := lines.Err(); err != nil {
+		return nil, 0, fmt.Errorf("read scrobbler log: %w", err)
+	}
+
+	return plays, invalid, nil
+}
+
+func parseListeningCSV(reader io.Reader) ([]externalPlay, int, error) {
+	records := csv.NewReader(reader)
+	records.FieldsPerRecord = -1
+	records.TrimLeadingSpace = true
+
+	header, err := records.Read()
+	if errors.Is(err, io.EOF) {
+		return []externalPlay{}, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("read listening log header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for index, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = index
+	}
+	timestampColumn, ok := firstColumn(columns, "timestamp", "ts", "played_at", "time", "date")
+	if !ok {
+		return nil, 0, errors.New("listening log has no timestamp column")
+	}
+	_, hasTrackID := columns["track_id"]
+	_, hasArtist := columns["artist"]
+	_, hasTitle := columns["title"]
+	if !hasTrackID && (!hasArtist || !hasTitle) {
+		return nil, 0, errors.New("listening log needs track_id or artist and title columns")
+	}
+
+	field := func(record []string, name string) string {
+		index, ok := columns[name]
+		if !ok || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+
+	plays := make([]externalPlay, 0)
+	invalid := 0
+	for {
+		record, err := records.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				invalid++
+				continue
+			}
+			return nil, 0, fmt.Errorf("read listening log: %w", err)
+		}
+
+		if timestampColumn >= len(record) {
+			invalid++
+			continue
+		}
+		at, ok := parseListeningTimestamp(strings.TrimSpace(record[timestampColumn]))
+		if !ok {
+			invalid++
+			continue
+		}
+
+		play := externalPlay{
+			at:      at,
+			artist:  field(record, "artist"),
+			title:   field(record, "title"),
+			album:   field(record, "album"),
+			skipped: parseListeningBool(field(record, "skipped")),
+		}
+		if trackID, err := strconv.ParseInt(field(record, "track_id"), 10, 64); err == nil && trackID > 0 {
+			play.trackID = trackID
+		} else if play.artist == "" || play.title == "" {
+			invalid++
+			continue
+		}
+		if playedMS, err := strconv.Atoi(field(record, "played_ms")); err == nil && playedMS > 0 {
+			play.playedMS = playedMS
+		} else if durationMS, err := strconv.Atoi(field(record, "duration_ms")); err == nil && durationMS > 0 && !play.skipped {
+			play.playedMS = durationMS
+		}
+		plays = append(plays, play)
+	}
+
+	return plays, invalid, nil
+}
+
+func firstColumn(columns map[string]int, names ...string) (int, bool) {
+	for _, name := range names {
+		if index, ok := columns[name]; ok {
+			return index, true
+		}
+	}
+
+	return 0, false
+}
+
+// parseListeningTimestamp accepts RFC 3339, "2006-01-02 15:04:05" in UTC and
+// unix seconds or milliseconds.
+func parseListeningTimestamp(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed.UTC(), true
+	}
+	if parsed, err := time.Parse(time.DateTime, value); err == nil {
+		return parsed.UTC(), true
+	}
+	if number, err := strconv.ParseInt(value, 10, 64); err == nil && number > 0 {
+		if number > 1_000_000_000_000 {
+			return time.UnixMilli(number).UTC(), true
+		}
+		return time.Unix(number, 0).UTC(), true
+	}
+
+	return time.Time{}, false
+}
+
+func parseListeningBool(value string) bool {
+	switch strings.ToLower(value) {
+	case "1", "true", "yes", "s", "skip", "skipped":
+		return true
+	default:
+		return false
+	}
+}
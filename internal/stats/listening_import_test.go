@@ -0,0 +1,103 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseScrobblerLogReadsPlaysAndSkips(t *testing.T) {
+	log := strings.Join([]string{
+		"#AUDIOSCROBBLER/1.1",
+		"#TZ/UTC",
+		"#CLIENT/Rockbox",
+		"Band\tAlbum\tSong\t1\t200\tL\t1700000000\t",
+		"Band\tAlbum\tOther\t2\t180\tS\t1700000300\t",
+		"broken line",
+	}, "\n")
+
+	plays, invalid, err := parseListeningLog(strings.NewReader(log))
+	if err != nil {
+		t.Fatalf("parse scrobbler log: %v", err)
+	}
+	if len(plays) != 2 || invalid != 1 {
+		t.Fatalf("expected 2 plays and 1 invalid line, got %d and %d", len(plays), invalid)
+	}
+	if plays[0].title != "Song" || plays[0].playedMS != 200000 || !plays[0].at.Equal(time.Unix(1700000000, 0)) {
+		t.Fatalf("unexpected listened play %+v", plays[0])
+	}
+	if !plays[1].skipped || plays[1].playedMS != 0 {
+		t.Fatalf("expected skipped play without played time, got %+v", plays[1])
+	}
+}
+
+func TestParseListeningCSVRequiresTimestamp(t *testing.T) {
+	if _, _, err := parseListeningLog(strings.NewReader("artist,title\nBand,Song\n")); err == nil {
+		t.Fatalf("expected log without timestamps to be rejected")
+	}
+
+	plays, invalid, err := parseListeningLog(strings.NewReader(
+		"Timestamp,Artist,Title,Played_ms\n2024-03-01T10:00:00Z,Band,Song,90000\nyesterday,Band,Song,1\n",
+	))
+	if err != nil {
+		t.Fatalf("parse listening csv: %v", err)
+	}
+	if len(plays) != 1 || invalid != 1 {
+		t.Fatalf("expected 1 play and 1 invalid row, got %d and %d", len(plays), invalid)
+	}
+	if plays[0].playedMS != 90000 {
+		t.Fatalf("expected played ms 90000, got %d", plays[0].playedMS)
+	}
+}
+
+func TestImportListeningLogAttributesDeviceAndSkipsDuplicates(t *testing.T) {
+	service, database := newStatsServiceForTest(t)
+	defer database.Close()
+
+	trackID := insertTrackForStatsTest(t, database, "Song", "Band")
+	recent := time.Now().UTC().Add(-2 * time.Hour).Format(time.RFC3339)
+	old := time.Now().UTC().AddDate(0, 0, -90).Format(time.RFC3339)
+	log := fmt.Sprintf(
+		"timestamp,artist,title,played_ms\n%s,band,SONG,60000\n%s,Band,Song,30000\n%s,Band,Missing,1000\n",
+		recent,
+		old,
+		recent,
+	)
+
+	result, err := service.ImportListeningLog(strings.NewReader(log), " My  Phone ")
+	if err != nil {
+		t.Fatalf("import listening log: %v", err)
+	}
+	if result.Device != "My Phone" || result.Imported != 2 || result.Unmatched != 1 {
+		t.Fatalf("unexpected import result %+v", result)
+	}
+
+	var deviceEvents int
+	if err := database.QueryRow(
+		"SELECT COUNT(1) FROM play_events WHERE track_id = ? AND device = ?",
+		trackID,
+		"My Phone",
+	).Scan(&deviceEvents); err != nil {
+		t.Fatalf("count device events: %v", err)
+	}
+	if deviceEvents != 2 {
+		t.Fatalf("expected heartbeat and complete events, got %d", deviceEvents)
+	}
+
+	tracks, err := service.GetTopTracks(DashboardRangeLong, 10, false)
+	if err != nil {
+		t.Fatalf("get top tracks: %v", err)
+	}
+	if len(tracks) != 1 || tracks[0].PlayedMS != 90000 || tracks[0].CompleteCount != 2 {
+		t.Fatalf("expected imported plays in top tracks, got %+v", tracks)
+	}
+
+	again, err := service.ImportListeningLog(strings.NewReader(log), "My Phone")
+	if err != nil {
+		t.Fatalf("import listening log again: %v", err)
+	}
+	if again.Imported != 0 || again.Duplicates != 2 {
+		t.Fatalf("expected duplicates on reimport, got %+v", again)
+	}
+}
@@ -0,0 +1,194 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	ListeningPairsAlbum  = "album"
+	ListeningPairsArtist = "artist"
+)
+
+const (
+	defaultListeningPairLimit = 20
+	// minListeningPairSessions keeps one-off transitions out of the pairs.
+	minListeningPairSessions = 2
+)
+
+// ListeningPair reports that To was played right after From in Sessions
+// listening sessions. For artist pairs the artist fields repeat the names.
+type ListeningPair struct {
+	Kind         string `json:"kind"`
+	From         string `json:"from"`
+	FromArtist   string `json:"fromArtist"`
+	To           string `json:"to"`
+	ToArtist     string `json:"toArtist"`
+	Sessions     int    `json:"sessions"`
+	LastPlayedAt string `json:"lastPlayedAt"`
+}
+
+type pairPlay struct {
+	at     time.Time
+	name   string
+	artist string
+}
+
+type pairKey struct {
+	fromName   string
+	fromArtist string
+	toName     string
+	toArtist   string
+}
+
+// GetListeningPairs finds albums or artists often played one after the
+// other within a session, most frequent first. A non-empty from keeps
+// only the pairs starting at that album or artist, for picking where a
+// radio station goes next.
+func (s *Service) GetListeningPairs(kind string, from string, limit int) ([]ListeningPair, error) {
+	if limit <= 0 {
+		limit = defaultListeningPairLimit
+	}
+	if kind != ListeningPairsAlbum && kind != ListeningPairsArtist {
+		return nil, fmt.Errorf("unknown listening pair kind %q", kind)
+	}
+	if s.db == nil {
+		return []ListeningPair{}, nil
+	}
+
+	rows, err := s.reader().QueryContext(context.Background(), `
+		SELECT
+			pe.ts,
+			COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album'),
+			COALESCE(NULLIF(TRIM(t.album_artist), ''), COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')),
+			COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist')
+		FROM play_events pe
+		JOIN tracks t ON t.id = pe.track_id
+		JOIN files f ON f.id = t.file_id
+		WHERE pe.event_type IN (?, ?)
+		  AND f.is_audiobook = 0
+		ORDER BY pe.ts ASC, pe.id ASC
+	`, EventComplete, EventPartial)
+	if err != nil {
+		return nil, fmt.Errorf("query listening pair plays: %w", err)
+	}
+	defer rows.Close()
+
+	plays := make([]pairPlay, 0)
+	for rows.Next() {
+		var ts string
+		var album string
+		var albumArtist string
+		var artist string
+		if err := rows.Scan(&ts, &album, &albumArtist, &artist); err != nil {
+			return nil, fmt.Errorf("scan listening pair play: %w", err)
+		}
+		at, ok := parseTimestamp(ts)
+		if !ok {
+			continue
+		}
+		if kind == ListeningPairsAlbum {
+			plays = append(plays, pairPlay{at: at, name: album, artist: albumArtist})
+		} else {
+			plays = append(plays, pairPlay{at: at, name: artist, artist: artist})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate listening pair plays: %w", err)
+	}
+
+	pairs := listeningPairs(kind, plays, dashboardSessionGap)
+	if from = strings.TrimSpace(from); from != "" {
+		filtered := pairs[:0]
+		for _, pair := range pairs {
+			if strings.EqualFold(pair.From, from) {
+				filtered = append(filtered, pair)
+			}
+		}
+		pairs = filtered
+	}
+	if len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+
+	return pairs, nil
+}
+
+// listeningPairs counts, per ordered pair, the sessions in which one album
+// or artist directly followed another. Plays further apart than gap start a
+// new session, and consecutive plays of the same album or artist are one
+// visit.
+func listeningPairs(kind string, plays []pairPlay, gap time.Duration) []ListeningPair {
+	sessions := make(map[pairKey]int)
+	lastPlayed := make(map[pairKey]time.Time)
+	seenInSession := make(map[pairKey]struct{})
+
+	var previous pairPlay
+	hasPrevious := false
+	for _, play := range plays {
+		if hasPrevious && play.at.Sub(previous.at) > gap {
+			hasPrevious = false
+			clear(seenInSession)
+		}
+		if hasPrevious && !samePairSubject(previous, play) {
+			key := pairKey{
+				fromName:   strings.ToLower(previous.name),
+				fromArtist: strings.ToLower(previous.artist),
+				toName:     strings.ToLower(play.name),
+				toArtist:   strings.ToLower(play.artist),
+			}
+			if _, seen := seenInSession[key]; !seen {
+				seenInSession[key] = struct{}{}
+				sessions[key]++
+			}
+			lastPlayed[key] = play.at
+		}
+		previous = play
+		hasPrevious = true
+	}
+
+	labels := make(map[string]pairPlay, len(plays))
+	for _, play := range plays {
+		labels[strings.ToLower(play.name)+"\x00"+strings.ToLower(play.artist)] = play
+	}
+
+	pairs := make([]ListeningPair, 0)
+	for key, count := range sessions {
+		if count < minListeningPairSessions {
+			continue
+		}
+		fromLabel := labels[key.fromName+"\x00"+key.fromArtist]
+		toLabel := labels[key.toName+"\x00"+key.toArtist]
+		pairs = append(pairs, ListeningPair{
+			Kind:         kind,
+			From:         fromLabel.name,
+			FromArtist:   fromLabel.artist,
+			To:           toLabel.name,
+			ToArtist:     toLabel.artist,
+			Sessions:     count,
+			LastPlayedAt: lastPlayed[key].UTC().Format(time.RFC3339),
+		})
+	}
+
+	sort.Slice(pairs, func(i int, j int) bool {
+		if pairs[i].Sessions != pairs[j].Sessions {
+			return pairs[i].Sessions > pairs[j].Sessions
+		}
+		if pairs[i].LastPlayedAt != pairs[j].LastPlayedAt {
+			return pairs[i].LastPlayedAt > pairs[j].LastPlayedAt
+		}
+		if pairs[i].From != pairs[j].From {
+			return pairs[i].From < pairs[j].From
+		}
+		return pairs[i].To < pairs[j].To
+	})
+
+	return pairs
+}
+
+func samePairSubject(left pairPlay, right pairPlay) bool {
+	return strings.EqualFold(left.name, right.name) && strings.EqualFold(left.artist, right.artist)
+}
@@ -0,0 +1,35 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListeningPairsCountsSessionsNotPlays(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return start.Add(time.Duration(minutes) * time.Minute)
+	}
+	plays := []pairPlay{
+		// First session: A, A, B, A, B counts A→B once.
+		{at: at(0), name: "A", artist: "X"},
+		{at: at(4), name: "A", artist: "X"},
+		{at: at(8), name: "B", artist: "Y"},
+		{at: at(12), name: "a", artist: "x"},
+		{at: at(16), name: "B", artist: "Y"},
+		// Second session after a long gap: A→B again, then B→C once.
+		{at: at(120), name: "A", artist: "X"},
+		{at: at(124), name: "B", artist: "Y"},
+		{at: at(128), name: "C", artist: "Z"},
+	}
+
+	pairs := listeningPairs(ListeningPairsAlbum, plays, 20*time.Minute)
+	if len(pairs) != 1 {
+		t.Fatalf("expected only A→B to repeat across sessions, got %+v", pairs)
+	}
+	if pairs[0].To != "B" || pairs[0].ToArtist != "Y" || pairs[0].Sessions != 2 {
+		t.Fatalf("expected A→B in two sessions, got %+v", pairs[0])
+	}
+}
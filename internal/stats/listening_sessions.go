@@ -0,0 +1,322 @@
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/player"
+	"github.com/rzxx/ben/internal/queue"
+)
+
+// SessionSnapshotsSettingKey turns recording listening sessions on or off.
+const SessionSnapshotsSettingKey = "stats.session_snapshots"
+
+const defaultListeningSessionLimit = 30
+
+// Parts of the day a session started in, in local time.
+const (
+	PartOfDayMorning   = "morning"
+	PartOfDayAfternoon = "afternoon"
+	PartOfDayEvening   = "evening"
+	PartOfDayNight     = "night"
+)
+
+var ErrListeningSessionNotFound = errors.New("listening session not found")
+
+// SessionContext is one stretch of a session played from the same source,
+// such as an album or a playlist.
+type SessionContext struct {
+	Source    queue.Source `json:"source"`
+	Label     string       `json:"label"`
+	StartedAt string       `json:"startedAt"`
+}
+
+// ListeningSession is a recorded session: plays with no gap longer than the
+// dashboard's session gap. EndedAt is when its last track started. Weekday is numbered like SQLite's %w and, with
+// PartOfDay, lets the UI name it like "Friday evening".
+type ListeningSession struct {
+	ID         int64            `json:"id"`
+	StartedAt  string           `json:"startedAt"`
+	EndedAt    string           `json:"endedAt"`
+	Weekday    int              `json:"weekday"`
+	PartOfDay  string           `json:"partOfDay"`
+	TrackCount int              `json:"trackCount"`
+	Contexts   []SessionContext `json:"contexts"`
+}
+
+// sessionQueue is the stored queue of a session.
+type sessionQueue struct {
+	TrackIDs     []int64        `json:"trackIds"`
+	Sources      []queue.Source `json:"sources"`
+	CurrentIndex int            `json:"currentIndex"`
+}
+
+// listeningSession is the session being recorded. It is written on every
+// track change, so the stored row always holds the session as it ended.
+type listeningSession struct {
+	id           int64
+	startedAt    time.Time
+	lastPlayedAt time.Time
+	trackID      int64
+	trackCount   int
+	contexts     []SessionContext
+}
+
+// SetQueue gives the service the queue whose sources and entries are
+// recorded with each listening session.
+func (s *Service) SetQueue(queueService *queue.Service) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = queueService
+}
+
+func (s *Service) SessionSnapshotsEnabled() bool {
+	if s.settings == nil {
+		return false
+	}
+
+	enabled, err := strconv.ParseBool(s.settings.GetString(context.Background(), SessionSnapshotsSettingKey, "true"))
+	return err == nil && enabled
+}
+
+func (s *Service) SetSessionSnapshotsEnabled(enabled bool) (bool, error) {
+	if s.settings == nil {
+		return false, errors.New("settings are unavailable")
+	}
+	if err := s.settings.Set(context.Background(), SessionSnapshotsSettingKey, strconv.FormatBool(enabled)); err != nil {
+		return s.SessionSnapshotsEnabled(), err
+	}
+
+	return enabled, nil
+}
+
+// recordListeningSession follows the playing track into the current
+// session, starting a new one after a gap longer than dashboardSessionGap.
+func (s *Service) recordListeningSession(state player.State, now time.Time) {
+	if state.Status != player.StatusPlaying || state.CurrentTrack == nil {
+		return
+	}
+
+	s.mu.Lock()
+	queueService := s.queue
+	session := s.listeningSession
+	if session != nil && now.Sub(session.lastPlayedAt) > dashboardSessionGap {
+		session = nil
+	}
+	if session != nil {
+		session.lastPlayedAt = now
+	}
+	changed := session == nil || session.trackID != state.CurrentTrack.ID
+	s.listeningSession = session
+	s.mu.Unlock()
+
+	if !changed || queueService == nil || !s.SessionSnapshotsEnabled() {
+		return
+	}
+
+	queueState := queueService.GetState()
+	source := queue.Source{Kind: queue.SourceQueue}
+	if queueState.CurrentIndex >= 0 && queueState.CurrentIndex < len(queueState.Sources) {
+		source = queueState.Sources[queueState.CurrentIndex]
+	}
+	label := s.sourceLabel(source)
+
+	s.mu.Lock()
+	if s.listeningSession != session {
+		s.mu.Unlock()
+		return
+	}
+	if session == nil {
+		session = &listeningSession{startedAt: now, lastPlayedAt: now}
+		s.listeningSession = session
+	}
+	session.trackID = state.CurrentTrack.ID
+	session.trackCount++
+	if last := len(session.contexts) - 1; last < 0 || session.contexts[last].Source != source {
+		session.contexts = append(session.contexts, SessionContext{
+			Source:    source,
+			Label:     label,
+			StartedAt: now.Format(time.RFC3339),
+		})
+	}
+	record := *session
+	record.contexts = append([]SessionContext(nil), session.contexts...)
+	s.mu.Unlock()
+
+	id, err := s.persistListeningSession(record, queueState)
+	if err != nil || record.id != 0 {
+		return
+	}
+
+	s.mu.Lock()
+	if s.listeningSession == session {
+		session.id = id
+	}
+	s.mu.Unlock()
+}
+
+func (s *Service) persistListeningSession(session listeningSession, queueState queue.State) (int64, error) {
+	stored := sessionQueue{
+		TrackIDs:     make([]int64, 0, len(queueState.Entries)),
+		Sources:      queueState.Sources,
+		CurrentIndex: queueState.CurrentIndex,
+	}
+	for _, entry := range queueState.Entries {
+		stored.TrackIDs = append(stored.TrackIDs, entry.ID)
+	}
+	queueJSON, err := json.Marshal(stored)
+	if err != nil {
+		return 0, fmt.Errorf("encode session queue: %w", err)
+	}
+	contextsJSON, err := json.Marshal(session.contexts)
+	if err != nil {
+		return 0, fmt.Errorf("encode session contexts: %w", err)
+	}
+
+	ctx := context.Background()
+	endedAt := session.lastPlayedAt.UTC().Format(time.RFC3339)
+	if session.id != 0 {
+		if _, err := s.db.ExecContext(
+			ctx,
+			"UPDATE listening_sessions SET ended_at = ?, track_count = ?, contexts_json = ?, queue_json = ? WHERE id = ?",
+			endedAt,
+			session.trackCount,
+			string(contextsJSON),
+			string(queueJSON),
+			session.id,
+		); err != nil {
+			return 0, fmt.Errorf("update listening session: %w", err)
+		}
+		return session.id, nil
+	}
+
+	result, err := s.db.ExecContext(
+		ctx,
+		"INSERT INTO listening_sessions(started_at, ended_at, track_count, contexts_json, queue_json) VALUES (?, ?, ?, ?, ?)",
+		session.startedAt.UTC().Format(time.RFC3339),
+		endedAt,
+		session.trackCount,
+		string(contextsJSON),
+		string(queueJSON),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert listening session: %w", err)
+	}
+
+	return result.LastInsertId()
+}
+
+// sourceLabel names a source for the history view. Playlists are looked up
+// so a renamed playlist keeps the name it had when it was played.
+func (s *Service) sourceLabel(source queue.Source) string {
+	switch source.Kind {
+	case queue.SourceAlbum:
+		return source.AlbumTitle
+	case queue.SourceArtist, queue.SourceRadio:
+		return source.ArtistName
+	case queue.SourceSearch:
+		return source.Query
+	case queue.SourcePlaylist:
+		var name string
+		if err := s.db.QueryRowContext(context.Background(), "SELECT name FROM playlists WHERE id = ?", source.PlaylistID).Scan(&name); err == nil {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// GetListeningSessions lists recorded sessions, newest first.
+func (s *Service) GetListeningSessions(limit int) ([]ListeningSession, error) {
+	if limit <= 0 {
+		limit = defaultListeningSessionLimit
+	}
+	if s.db == nil {
+		return []ListeningSession{}, nil
+	}
+
+	rows, err := s.db.QueryContext(context.Background(), `
+		SELECT id, started_at, ended_at, track_count, contexts_json
+		FROM listening_sessions
+		ORDER BY started_at DESC, id DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list listening sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := make([]ListeningSession, 0)
+	for rows.Next() {
+		var session ListeningSession
+		var contextsJSON string
+		if err := rows.Scan(&session.ID, &session.StartedAt, &session.EndedAt, &session.TrackCount, &contextsJSON); err != nil {
+			return nil, fmt.Errorf("scan listening session: %w", err)
+		}
+		if err := json.Unmarshal([]byte(contextsJSON), &session.Contexts); err != nil || session.Contexts == nil {
+			session.Contexts = []SessionContext{}
+		}
+		if startedAt, ok := parseTimestamp(session.StartedAt); ok {
+			local := startedAt.Local()
+			session.Weekday = int(local.Weekday())
+			session.PartOfDay = partOfDay(local.Hour())
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate listening sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// LoadListeningSession replaces the queue with the queue a session ended
+// with, at the track that was playing.
+func (s *Service) LoadListeningSession(id int64) (queue.State, error) {
+	s.mu.Lock()
+	queueService := s.queue
+	s.mu.Unlock()
+	if s.db == nil || queueService == nil {
+		return queue.State{}, errors.New("listening sessions are unavailable")
+	}
+
+	var queueJSON string
+	err := s.db.QueryRowContext(context.Background(), "SELECT queue_json FROM listening_sessions WHERE id = ?", id).Scan(&queueJSON)
+	if errors.Is(err, sql.ErrNoRows) {
+		return queueService.GetState(), ErrListeningSessionNotFound
+	}
+	if err != nil {
+		return queueService.GetState(), fmt.Errorf("get listening session %d: %w", id, err)
+	}
+
+	var stored sessionQueue
+	if err := json.Unmarshal([]byte(queueJSON), &stored); err != nil {
+		return queueService.GetState(), fmt.Errorf("decode listening session %d: %w", id, err)
+	}
+
+	previous := queue.State{Sources: stored.Sources, CurrentIndex: stored.CurrentIndex}
+	for _, trackID := range stored.TrackIDs {
+		previous.Entries = append(previous.Entries, library.TrackSummary{ID: trackID})
+	}
+
+	return queueService.Restore(previous)
+}
+
+func partOfDay(hour int) string {
+	switch {
+	case hour >= 5 && hour < 12:
+		return PartOfDayMorning
+	case hour >= 12 && hour < 17:
+		return PartOfDayAfternoon
+	case hour >= 17 && hour < 22:
+		return PartOfDayEvening
+	default:
+		return PartOfDayNight
+	}
+}
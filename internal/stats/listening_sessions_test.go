@@ -0,0 +1,21 @@
+package stats
+
+import "testing"
+
+func TestPartOfDayBoundaries(t *testing.T) {
+	t.Parallel()
+
+	cases := map[int]string{
+		4:  PartOfDayNight,
+		5:  PartOfDayMorning,
+		12: PartOfDayAfternoon,
+		17: PartOfDayEvening,
+		21: PartOfDayEvening,
+		22: PartOfDayNight,
+	}
+	for hour, want := range cases {
+		if got := partOfDay(hour); got != want {
+			t.Fatalf("expected %q at %d:00, got %q", want, hour, got)
+		}
+	}
+}
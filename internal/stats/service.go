@@ -7,7 +7,11 @@ import (
 	"sync"
 	"time"
 
-	"ben/internal/player"
+	"github.com/rzxx/ben/internal/eventbus"
+	"github.com/rzxx/ben/internal/i18n"
+	"github.com/rzxx/ben/internal/player"
+	"github.com/rzxx/ben/internal/queue"
+	"github.com/rzxx/ben/internal/settings"
 )
 
 const EventHeartbeat = "heartbeat"
@@ -18,8 +22,6 @@ const EventSkip = "skip"
 
 const EventPartial = "partial"
 
-const heartbeatInterval = 30 * time.Second
-
 const compactionCheckInterval = 6 * time.Hour
 
 const rawEventRetentionDays = 30
@@ -76,6 +78,7 @@ type TrackStat struct {
 	CompleteCount int     `json:"completeCount"`
 	SkipCount     int     `json:"skipCount"`
 	PartialCount  int     `json:"partialCount"`
+	VersionCount  int     `json:"versionCount,omitempty"`
 }
 
 type ArtistStat struct {
@@ -85,8 +88,12 @@ type ArtistStat struct {
 }
 
 type Service struct {
-	mu sync.Mutex
-	db *sql.DB
+	mu        sync.Mutex
+	db        *sql.DB
+	readPool  *sql.DB
+	localizer *i18n.Localizer
+	settings  *settings.Store
+	queue     *queue.Service
 
 	activeTrackID   int64
 	activeDuration  int
@@ -97,23 +104,87 @@ type Service struct {
 	pendingPlayedMS int
 	lastObservedAt  time.Time
 
-	lastCompactionAt  time.Time
-	compactionRunning bool
+	heartbeatInterval time.Duration
+	queuedEvents      []playEvent
+	commitTimer       *time.Timer
+
+	activeFromIntro   bool
+	activeIntroSkipMS int
+
+	sessionLabel          string
+	sessionLabelStartedAt time.Time
+	listeningSession      *listeningSession
+
+	lastCompactionAt   time.Time
+	compactionRunning  bool
+	compactionDisabled bool
+
+	dashboardLayout          DashboardLayout
+	dashboardCache           map[dashboardCacheKey]*dashboardCacheEntry
+	dashboardCacheGeneration int
+	dashboardEvents          int64
 }
 
 type playEvent struct {
-	trackID   int64
-	eventType string
-	position  int
-	at        time.Time
+	trackID      int64
+	eventType    string
+	position     int
+	at           time.Time
+	sessionLabel string
 }
 
-func NewService(database *sql.DB) *Service {
-	service := &Service{db: database}
-	service.maybeCompact(time.Now().UTC())
+func NewService(database *sql.DB, bus *eventbus.Bus) *Service {
+	service := &Service{db: database, localizer: i18n.NewLocalizer(i18n.DefaultLocale)}
+	if database != nil {
+		service.settings = settings.NewStore(database)
+	}
+	service.loadDashboardLayout()
+	service.loadHeartbeatInterval()
+	eventbus.Subscribe(bus, player.EventStateChanged, service.HandlePlayerState)
 	return service
 }
 
+// CompactIfDue folds expired raw play events into daily rows unless that ran
+// recently. NewService leaves the first run to the caller so it stays off
+// the startup path.
+func (s *Service) CompactIfDue() {
+	s.maybeCompact(time.Now().UTC())
+}
+
+// DisableCompaction stops reads from folding old play events, for a service
+// opened on a read-only database.
+func (s *Service) DisableCompaction() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.compactionDisabled = true
+}
+
+// SetReadPool runs dashboard queries on a separate read-only pool, so they
+// never wait behind scan writes. Play events are still written through the
+// main connection.
+func (s *Service) SetReadPool(reader *sql.DB) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readPool = reader
+}
+
+// reader returns the pool dashboard queries run on.
+func (s *Service) reader() *sql.DB {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.readPool != nil {
+		return s.readPool
+	}
+	return s.db
+}
+
+// SetLocalizer sets the locale used for dashboard labels.
+func (s *Service) SetLocalizer(localizer *i18n.Localizer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.localizer = localizer
+}
+
 func (s *Service) HandlePlayerState(state player.State) {
 	if s.db == nil {
 		return
@@ -128,20 +199,35 @@ func (s *Service) HandlePlayerState(state player.State) {
 	}
 
 	events := make([]playEvent, 0, 4)
+	// Heartbeats of uninterrupted playback wait for the next group commit;
+	// pauses and track ends are written at once.
+	deferrable := true
+	var observation *introObservation
 
 	s.mu.Lock()
 	if s.active {
+		if s.activeFromIntro && s.activeTrackID == trackID {
+			elapsed := 0
+			if s.activePlayback {
+				elapsed = elapsedMS(s.lastObservedAt, observedAt)
+			}
+			if detectIntroSkip(s.activePosition, positionMS, elapsed) {
+				s.activeIntroSkipMS = positionMS
+			}
+		}
+
 		if s.activePlayback {
 			deltaMS := elapsedMS(s.lastObservedAt, observedAt)
 			if deltaMS > 0 {
 				s.activePlayedMS += deltaMS
 				s.pendingPlayedMS += deltaMS
-				for s.pendingPlayedMS >= int(heartbeatInterval/time.Millisecond) {
-					s.pendingPlayedMS -= int(heartbeatInterval / time.Millisecond)
+				intervalMS := int(s.heartbeatInterval / time.Millisecond)
+				for s.pendingPlayedMS >= intervalMS {
+					s.pendingPlayedMS -= intervalMS
 					events = append(events, playEvent{
 						trackID:   s.activeTrackID,
 						eventType: EventHeartbeat,
-						position:  int(heartbeatInterval / time.Millisecond),
+						position:  intervalMS,
 						at:        observedAt,
 					})
 				}
@@ -157,10 +243,12 @@ func (s *Service) HandlePlayerState(state player.State) {
 				at:        observedAt,
 			})
 			s.pendingPlayedMS = 0
+			deferrable = false
 		}
 
 		finalize := shouldFinalizeTrack(s.activeTrackID, trackID, status)
 		if finalize {
+			deferrable = false
 			if s.pendingPlayedMS > 0 {
 				events = append(events, playEvent{
 					trackID:   s.activeTrackID,
@@ -180,6 +268,10 @@ func (s *Service) HandlePlayerState(state player.State) {
 					at:        observedAt,
 				})
 			}
+			// A play skipped outright says nothing about the intro.
+			if s.activeFromIntro && (s.activeIntroSkipMS > 0 || (eventType != "" && eventType != EventSkip)) {
+				observation = &introObservation{trackID: s.activeTrackID, skippedToMS: s.activeIntroSkipMS, at: observedAt}
+			}
 
 			s.active = false
 			s.activePlayback = false
@@ -188,6 +280,8 @@ func (s *Service) HandlePlayerState(state player.State) {
 			s.activePosition = 0
 			s.activePlayedMS = 0
 			s.pendingPlayedMS = 0
+			s.activeFromIntro = false
+			s.activeIntroSkipMS = 0
 		}
 	}
 
@@ -197,6 +291,8 @@ func (s *Service) HandlePlayerState(state player.State) {
 			s.activeTrackID = trackID
 			s.activePlayedMS = 0
 			s.pendingPlayedMS = 0
+			s.activeFromIntro = positionMS <= introWindowMS
+			s.activeIntroSkipMS = 0
 		}
 
 		s.activeDuration = durationMS
@@ -211,12 +307,55 @@ func (s *Service) HandlePlayerState(state player.State) {
 	}
 
 	s.lastObservedAt = observedAt
+	sessionLabel := s.sessionLabel
 	s.mu.Unlock()
 
-	s.persistEvents(events)
+	if deferrable {
+		s.queueEvents(events, sessionLabel)
+	} else {
+		s.persistEvents(events, sessionLabel)
+	}
+	s.recordListeningSession(state, time.Now().UTC())
+	if observation != nil {
+		s.persistIntroObservation(*observation)
+	}
 	s.maybeCompact(time.Now().UTC())
 }
 
+// Flush writes the listening time of the playing track that has not reached
+// a heartbeat yet, along with the heartbeats waiting for the group commit,
+// e.g. before the app exits. The track stays active.
+func (s *Service) Flush() {
+	if s.db == nil {
+		return
+	}
+
+	now := time.Now().UTC()
+
+	s.mu.Lock()
+	events := make([]playEvent, 0, 1)
+	if s.active && s.activePlayback {
+		if deltaMS := elapsedMS(s.lastObservedAt, now); deltaMS > 0 {
+			s.activePlayedMS += deltaMS
+			s.pendingPlayedMS += deltaMS
+		}
+		s.lastObservedAt = now
+	}
+	if s.active && s.pendingPlayedMS > 0 {
+		events = append(events, playEvent{
+			trackID:   s.activeTrackID,
+			eventType: EventHeartbeat,
+			position:  s.pendingPlayedMS,
+			at:        now,
+		})
+		s.pendingPlayedMS = 0
+	}
+	sessionLabel := s.sessionLabel
+	s.mu.Unlock()
+
+	s.persistEvents(events, sessionLabel)
+}
+
 func (s *Service) GetOverview(limit int) (Overview, error) {
 	if s.db == nil {
 		return Overview{}, nil
@@ -250,7 +389,7 @@ func (s *Service) GetOverview(limit int) (Overview, error) {
 				complete_count,
 				skip_count,
 				partial_count
-			FROM play_stats_daily
+			FROM play_stats_combined
 		) AS metrics
 	`, EventHeartbeat, EventComplete, EventSkip, EventPartial).Scan(
 		&overview.TotalPlayedMS,
@@ -301,7 +440,7 @@ func (s *Service) readTopTracks(ctx context.Context, limit int) ([]TrackStat, er
 					complete_count,
 					skip_count,
 					partial_count
-				FROM play_stats_daily
+				FROM play_stats_combined
 			) AS metrics
 			GROUP BY track_id
 		)
@@ -321,6 +460,7 @@ func (s *Service) readTopTracks(ctx context.Context, limit int) ([]TrackStat, er
 		LEFT JOIN covers cover ON cover.source_file_id = t.file_id
 		WHERE
 			f.file_exists = 1
+			AND f.is_audiobook = 0
 			AND (
 				tm.played_ms > 0
 				OR tm.complete_count > 0
@@ -394,19 +534,21 @@ func (s *Service) readTopArtists(ctx context.Context, limit int) ([]ArtistStat,
 					complete_count,
 					skip_count,
 					partial_count
-				FROM play_stats_daily
+				FROM play_stats_combined
 			) AS metrics
 			GROUP BY track_id
 		)
 		SELECT
-			COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist') AS artist_name,
+			MIN(ta.artist) AS artist_name,
 			COALESCE(SUM(tm.played_ms), 0) AS played_ms,
 			COUNT(DISTINCT t.id) AS track_count
 		FROM track_metrics tm
 		JOIN tracks t ON t.id = tm.track_id
+		JOIN track_artists ta ON ta.track_id = t.id
 		JOIN files f ON f.id = t.file_id
 		WHERE f.file_exists = 1
-		GROUP BY artist_name
+		  AND f.is_audiobook = 0
+		GROUP BY ta.artist
 		HAVING COALESCE(SUM(tm.played_ms), 0) > 0
 		ORDER BY played_ms DESC, LOWER(artist_name)
 		LIMIT ?
@@ -432,42 +574,6 @@ func (s *Service) readTopArtists(ctx context.Context, limit int) ([]ArtistStat,
 	return artists, nil
 }
 
-func (s *Service) persistEvents(events []playEvent) {
-	if len(events) == 0 || s.db == nil {
-		return
-	}
-
-	ctx := context.Background()
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return
-	}
-
-	defer func() {
-		_ = tx.Rollback()
-	}()
-
-	for _, event := range events {
-		if event.trackID <= 0 {
-			continue
-		}
-
-		at := event.at.UTC().Format(time.RFC3339)
-		if _, execErr := tx.ExecContext(
-			ctx,
-			"INSERT INTO play_events(track_id, event_type, position_ms, ts) VALUES (?, ?, ?, ?)",
-			event.trackID,
-			event.eventType,
-			event.position,
-			at,
-		); execErr != nil {
-			return
-		}
-	}
-
-	_ = tx.Commit()
-}
-
 func (s *Service) maybeCompact(reference time.Time) {
 	if s.db == nil {
 		return
@@ -476,7 +582,7 @@ func (s *Service) maybeCompact(reference time.Time) {
 	now := reference.UTC()
 
 	s.mu.Lock()
-	if s.compactionRunning {
+	if s.compactionRunning || s.compactionDisabled {
 		s.mu.Unlock()
 		return
 	}
@@ -557,10 +663,54 @@ func (s *Service) compactOldEvents(reference time.Time) {
 		return
 	}
 
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO play_stats_label_daily(
+			day,
+			label,
+			played_ms,
+			complete_count,
+			skip_count,
+			partial_count,
+			updated_at
+		)
+		SELECT
+			substr(ts, 1, 10) AS day,
+			session_label,
+			COALESCE(SUM(CASE WHEN event_type = ? THEN COALESCE(position_ms, 0) ELSE 0 END), 0) AS played_ms,
+			COALESCE(SUM(CASE WHEN event_type = ? THEN 1 ELSE 0 END), 0) AS complete_count,
+			COALESCE(SUM(CASE WHEN event_type = ? THEN 1 ELSE 0 END), 0) AS skip_count,
+			COALESCE(SUM(CASE WHEN event_type = ? THEN 1 ELSE 0 END), 0) AS partial_count,
+			? AS updated_at
+		FROM play_events
+		WHERE ts < ?
+		  AND session_label IS NOT NULL
+		GROUP BY day, session_label
+		ON CONFLICT(day, label) DO UPDATE SET
+			played_ms = excluded.played_ms,
+			complete_count = excluded.complete_count,
+			skip_count = excluded.skip_count,
+			partial_count = excluded.partial_count,
+			updated_at = excluded.updated_at
+	`,
+		EventHeartbeat,
+		EventComplete,
+		EventSkip,
+		EventPartial,
+		updatedAt,
+		cutoffTimestamp,
+	); err != nil {
+		return
+	}
+
 	if _, err := tx.ExecContext(ctx, `DELETE FROM play_events WHERE ts < ?`, cutoffTimestamp); err != nil {
 		return
 	}
 
+	introCutoff := startOfUTCDay(reference).AddDate(0, 0, -introObservationRetentionDays).Format(time.RFC3339)
+	if _, err := tx.ExecContext(ctx, `DELETE FROM track_intro_observations WHERE ts < ?`, introCutoff); err != nil {
+		return
+	}
+
 	_ = tx.Commit()
 }
 
@@ -1,13 +1,14 @@
 package stats
 
 import (
-	"ben/internal/db"
-	"ben/internal/library"
-	"ben/internal/player"
 	"database/sql"
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/rzxx/ben/internal/db"
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/player"
 )
 
 func TestClassifyTrackEndShortTrackComplete(t *testing.T) {
@@ -253,7 +254,7 @@ func newStatsServiceForTest(t *testing.T) (*Service, *sql.DB) {
 		t.Fatalf("bootstrap stats test database: %v", err)
 	}
 
-	return NewService(database), database
+	return NewService(database, nil), database
 }
 
 func insertTrackForStatsTest(t *testing.T, database *sql.DB, title string, artist string) int64 {
@@ -292,6 +293,10 @@ func insertTrackForStatsTest(t *testing.T, database *sql.DB, title string, artis
 		t.Fatalf("read track id: %v", err)
 	}
 
+	if _, err := database.Exec(`INSERT INTO track_artists(track_id, artist) VALUES (?, ?)`, trackID, artist); err != nil {
+		t.Fatalf("insert track artist row: %v", err)
+	}
+
 	return trackID
 }
 
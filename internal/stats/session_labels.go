@@ -0,0 +1,126 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const maxSessionLabelLength = 64
+
+// SessionLabelState describes the label attached to plays recorded right now.
+type SessionLabelState struct {
+	Label     string  `json:"label,omitempty"`
+	Active    bool    `json:"active"`
+	StartedAt *string `json:"startedAt,omitempty"`
+}
+
+type SessionLabelStat struct {
+	Label     string  `json:"label"`
+	PlayedMS  int     `json:"playedMs"`
+	PlayCount int     `json:"playCount"`
+	Share     float64 `json:"share"`
+}
+
+// StartSessionLabel tags every play event from now on with label until the
+// session is ended or another label is started.
+func (s *Service) StartSessionLabel(label string) (SessionLabelState, error) {
+	normalized := strings.Join(strings.Fields(label), " ")
+	if normalized == "" {
+		return s.GetSessionLabel(), errors.New("session label is required")
+	}
+	if len([]rune(normalized)) > maxSessionLabelLength {
+		return s.GetSessionLabel(), fmt.Errorf("session label exceeds %d characters", maxSessionLabelLength)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessionLabel = strings.ToLower(normalized)
+	s.sessionLabelStartedAt = time.Now().UTC()
+	return s.sessionLabelStateLocked(), nil
+}
+
+func (s *Service) EndSessionLabel() SessionLabelState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessionLabel = ""
+	s.sessionLabelStartedAt = time.Time{}
+	return s.sessionLabelStateLocked()
+}
+
+func (s *Service) GetSessionLabel() SessionLabelState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessionLabelStateLocked()
+}
+
+func (s *Service) sessionLabelStateLocked() SessionLabelState {
+	if s.sessionLabel == "" {
+		return SessionLabelState{}
+	}
+
+	startedAt := s.sessionLabelStartedAt.Format(time.RFC3339)
+	return SessionLabelState{Label: s.sessionLabel, Active: true, StartedAt: &startedAt}
+}
+
+func (s *Service) readSessionLabels(ctx context.Context, queryer dashboardQueryer, rangeStart *time.Time) ([]SessionLabelStat, error) {
+	args := []any{EventHeartbeat, EventComplete, EventSkip, EventPartial}
+	args = append(args, rangeArgs(rangeStart)...)
+
+	rows, err := queryer.QueryContext(ctx, `
+		SELECT
+			label,
+			COALESCE(SUM(played_ms), 0) AS played_ms,
+			COALESCE(SUM(play_count), 0) AS play_count
+		FROM (
+			SELECT
+				session_label AS label,
+				COALESCE(SUM(CASE WHEN event_type = ? THEN COALESCE(position_ms, 0) ELSE 0 END), 0) AS played_ms,
+				COALESCE(SUM(CASE WHEN event_type IN (?, ?, ?) THEN 1 ELSE 0 END), 0) AS play_count
+			FROM play_events
+			WHERE session_label IS NOT NULL
+			  AND (? = '' OR ts >= ?)
+			GROUP BY session_label
+			UNION ALL
+			SELECT
+				label,
+				COALESCE(SUM(played_ms), 0) AS played_ms,
+				COALESCE(SUM(complete_count + skip_count + partial_count), 0) AS play_count
+			FROM play_stats_label_daily
+			WHERE (? = '' OR day >= ?)
+			GROUP BY label
+		) AS metrics
+		GROUP BY label
+		HAVING COALESCE(SUM(played_ms), 0) > 0 OR COALESCE(SUM(play_count), 0) > 0
+		ORDER BY played_ms DESC, label
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	labels := make([]SessionLabelStat, 0)
+	totalPlayedMS := 0
+	for rows.Next() {
+		var item SessionLabelStat
+		if scanErr := rows.Scan(&item.Label, &item.PlayedMS, &item.PlayCount); scanErr != nil {
+			return nil, scanErr
+		}
+		totalPlayedMS += item.PlayedMS
+		labels = append(labels, item)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, rowsErr
+	}
+
+	if totalPlayedMS > 0 {
+		for index := range labels {
+			labels[index].Share = float64(labels[index].PlayedMS) * 100 / float64(totalPlayedMS)
+		}
+	}
+
+	return labels, nil
+}
@@ -0,0 +1,24 @@
+package stats
+
+import "testing"
+
+func TestSessionLabelNormalizesAndEnds(t *testing.T) {
+	service := NewService(nil, nil)
+
+	if _, err := service.StartSessionLabel("   "); err == nil {
+		t.Fatalf("expected empty label to be rejected")
+	}
+
+	state, err := service.StartSessionLabel("  Late   Night Studying ")
+	if err != nil {
+		t.Fatalf("start session label: %v", err)
+	}
+	if !state.Active || state.Label != "late night studying" || state.StartedAt == nil {
+		t.Fatalf("unexpected session label state %+v", state)
+	}
+
+	state = service.EndSessionLabel()
+	if state.Active || state.Label != "" {
+		t.Fatalf("expected session label to end, got %+v", state)
+	}
+}
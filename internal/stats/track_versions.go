@@ -0,0 +1,289 @@
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type TrackVersionStats struct {
+	CanonicalTrackID int64       `json:"canonicalTrackId"`
+	Combined         bool        `json:"combined"`
+	Totals           TrackStat   `json:"totals"`
+	Versions         []TrackStat `json:"versions"`
+}
+
+func linkedTrackMetricsCTE() string {
+	return `
+		, linked_track_metrics AS (
+			SELECT
+				COALESCE(tl.canonical_track_id, tm.track_id) AS track_id,
+				COALESCE(SUM(tm.played_ms), 0) AS played_ms,
+				COALESCE(SUM(tm.complete_count), 0) AS complete_count,
+				COALESCE(SUM(tm.skip_count), 0) AS skip_count,
+				COALESCE(SUM(tm.partial_count), 0) AS partial_count
+			FROM track_metrics tm
+			LEFT JOIN track_links tl ON tl.track_id = tm.track_id
+			GROUP BY COALESCE(tl.canonical_track_id, tm.track_id)
+		)
+	`
+}
+
+// GetTopTracks returns the most played tracks for a dashboard range. With
+// combineLinked set, plays of linked versions are credited to their canonical
+// track instead of being ranked separately.
+func (s *Service) GetTopTracks(rangeKey string, limit int, combineLinked bool) ([]TrackStat, error) {
+	if s.db == nil {
+		return []TrackStat{}, nil
+	}
+
+	s.maybeCompact(time.Now().UTC())
+
+	_, rangeStart := normalizeDashboardRange(rangeKey, time.Now().UTC())
+	normalizedLimit := normalizeTopLimit(limit)
+	ctx := context.Background()
+
+	if !combineLinked {
+		return s.readDashboardTopTracks(ctx, s.reader(), rangeStart, normalizedLimit)
+	}
+
+	return s.readLinkedTopTracks(ctx, s.reader(), rangeStart, normalizedLimit)
+}
+
+func (s *Service) readLinkedTopTracks(ctx context.Context, queryer dashboardQueryer, rangeStart *time.Time, limit int) ([]TrackStat, error) {
+	args := append(trackMetricsArgs(rangeStart), limit)
+
+	query := trackMetricsCTE() + linkedTrackMetricsCTE() + `
+		SELECT
+			t.id,
+			COALESCE(NULLIF(TRIM(t.title), ''), 'Unknown Title') AS track_title,
+			COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist') AS track_artist,
+			COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album') AS track_album,
+			cover.cache_path,
+			ltm.played_ms,
+			ltm.complete_count,
+			ltm.skip_count,
+			ltm.partial_count,
+			1 + (SELECT COUNT(1) FROM track_links tl WHERE tl.canonical_track_id = t.id) AS version_count
+		FROM linked_track_metrics ltm
+		JOIN tracks t ON t.id = ltm.track_id
+		JOIN files f ON f.id = t.file_id
+		LEFT JOIN covers cover ON cover.source_file_id = t.file_id
+		WHERE
+			f.file_exists = 1
+			AND f.is_audiobook = 0
+			AND (
+				ltm.played_ms > 0
+				OR ltm.complete_count > 0
+				OR ltm.skip_count > 0
+				OR ltm.partial_count > 0
+			)
+		ORDER BY ltm.played_ms DESC, ltm.complete_count DESC, ltm.partial_count DESC, ltm.skip_count ASC, LOWER(track_title)
+		LIMIT ?
+	`
+
+	rows, err := queryer.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tracks := make([]TrackStat, 0, limit)
+	for rows.Next() {
+		var item TrackStat
+		var coverPath sql.NullString
+		if scanErr := rows.Scan(
+			&item.TrackID,
+			&item.Title,
+			&item.Artist,
+			&item.Album,
+			&coverPath,
+			&item.PlayedMS,
+			&item.CompleteCount,
+			&item.SkipCount,
+			&item.PartialCount,
+			&item.VersionCount,
+		); scanErr != nil {
+			return nil, scanErr
+		}
+
+		item.CoverPath = nullableStringPointer(coverPath)
+		tracks = append(tracks, item)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, rowsErr
+	}
+
+	return tracks, nil
+}
+
+// GetTrackStats returns all-time stats for a single track. With combineLinked
+// set, every version linked to the same canonical recording is listed and the
+// totals cover the whole group.
+func (s *Service) GetTrackStats(trackID int64, combineLinked bool) (TrackVersionStats, error) {
+	if s.db == nil {
+		return TrackVersionStats{}, nil
+	}
+	if trackID <= 0 {
+		return TrackVersionStats{}, errors.New("track is required")
+	}
+
+	s.maybeCompact(time.Now().UTC())
+
+	ctx := context.Background()
+	tx, err := s.reader().BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return TrackVersionStats{}, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	canonicalTrackID := trackID
+	trackIDs := []int64{trackID}
+	if combineLinked {
+		canonicalTrackID, trackIDs, err = readTrackVersionGroup(ctx, tx, trackID)
+		if err != nil {
+			return TrackVersionStats{}, err
+		}
+	}
+
+	versions, err := readTrackVersionStats(ctx, tx, trackIDs)
+	if err != nil {
+		return TrackVersionStats{}, err
+	}
+	if len(versions) == 0 {
+		return TrackVersionStats{}, fmt.Errorf("track %d not found", trackID)
+	}
+
+	result := TrackVersionStats{
+		CanonicalTrackID: canonicalTrackID,
+		Combined:         combineLinked,
+		Versions:         versions,
+	}
+	for _, version := range versions {
+		if version.TrackID == canonicalTrackID {
+			result.Totals = version
+			result.Totals.PlayedMS = 0
+			result.Totals.CompleteCount = 0
+			result.Totals.SkipCount = 0
+			result.Totals.PartialCount = 0
+			break
+		}
+	}
+	for _, version := range versions {
+		result.Totals.PlayedMS += version.PlayedMS
+		result.Totals.CompleteCount += version.CompleteCount
+		result.Totals.SkipCount += version.SkipCount
+		result.Totals.PartialCount += version.PartialCount
+	}
+	result.Totals.TrackID = canonicalTrackID
+	result.Totals.VersionCount = len(versions)
+
+	if commitErr := tx.Commit(); commitErr != nil {
+		return TrackVersionStats{}, commitErr
+	}
+
+	return result, nil
+}
+
+func readTrackVersionGroup(ctx context.Context, queryer dashboardQueryer, trackID int64) (int64, []int64, error) {
+	canonicalTrackID := trackID
+	err := queryer.QueryRowContext(
+		ctx,
+		"SELECT canonical_track_id FROM track_links WHERE track_id = ?",
+		trackID,
+	).Scan(&canonicalTrackID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, nil, err
+	}
+
+	rows, err := queryer.QueryContext(
+		ctx,
+		"SELECT track_id FROM track_links WHERE canonical_track_id = ? ORDER BY track_id",
+		canonicalTrackID,
+	)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	trackIDs := []int64{canonicalTrackID}
+	for rows.Next() {
+		var linkedTrackID int64
+		if scanErr := rows.Scan(&linkedTrackID); scanErr != nil {
+			return 0, nil, scanErr
+		}
+		trackIDs = append(trackIDs, linkedTrackID)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return 0, nil, rowsErr
+	}
+
+	return canonicalTrackID, trackIDs, nil
+}
+
+func readTrackVersionStats(ctx context.Context, queryer dashboardQueryer, trackIDs []int64) ([]TrackStat, error) {
+	placeholders := make([]string, len(trackIDs))
+	args := trackMetricsArgs(nil)
+	for i, trackID := range trackIDs {
+		placeholders[i] = "?"
+		args = append(args, trackID)
+	}
+
+	query := trackMetricsCTE() + fmt.Sprintf(`
+		SELECT
+			t.id,
+			COALESCE(NULLIF(TRIM(t.title), ''), 'Unknown Title') AS track_title,
+			COALESCE(NULLIF(TRIM(t.artist), ''), 'Unknown Artist') AS track_artist,
+			COALESCE(NULLIF(TRIM(t.album), ''), 'Unknown Album') AS track_album,
+			cover.cache_path,
+			COALESCE(tm.played_ms, 0),
+			COALESCE(tm.complete_count, 0),
+			COALESCE(tm.skip_count, 0),
+			COALESCE(tm.partial_count, 0)
+		FROM tracks t
+		LEFT JOIN track_metrics tm ON tm.track_id = t.id
+		LEFT JOIN covers cover ON cover.source_file_id = t.file_id
+		WHERE t.id IN (%s)
+		ORDER BY COALESCE(tm.played_ms, 0) DESC, t.id
+	`, strings.Join(placeholders, ","))
+
+	rows, err := queryer.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make([]TrackStat, 0, len(trackIDs))
+	for rows.Next() {
+		var item TrackStat
+		var coverPath sql.NullString
+		if scanErr := rows.Scan(
+			&item.TrackID,
+			&item.Title,
+			&item.Artist,
+			&item.Album,
+			&coverPath,
+			&item.PlayedMS,
+			&item.CompleteCount,
+			&item.SkipCount,
+			&item.PartialCount,
+		); scanErr != nil {
+			return nil, scanErr
+		}
+
+		item.CoverPath = nullableStringPointer(coverPath)
+		versions = append(versions, item)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, rowsErr
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,122 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetTopTracksCombinesLinkedVersions(t *testing.T) {
+	t.Parallel()
+
+	service, database := newStatsServiceForTest(t)
+	defer database.Close()
+
+	originalID := insertTrackForStatsTest(t, database, "Song", "Band")
+	liveID := insertTrackForStatsTest(t, database, "Song (Live)", "Band")
+	if _, err := database.Exec(
+		`INSERT INTO track_links(track_id, canonical_track_id) VALUES (?, ?)`,
+		liveID,
+		originalID,
+	); err != nil {
+		t.Fatalf("insert track link: %v", err)
+	}
+
+	playedAt := time.Now().UTC().Add(-time.Hour)
+	insertPlayEventForStatsTest(t, database, originalID, EventHeartbeat, 30000, playedAt)
+	insertPlayEventForStatsTest(t, database, liveID, EventHeartbeat, 60000, playedAt.Add(time.Minute))
+
+	separate, err := service.GetTopTracks(DashboardRangeLong, 10, false)
+	if err != nil {
+		t.Fatalf("get separate top tracks: %v", err)
+	}
+	if len(separate) != 2 {
+		t.Fatalf("expected 2 separate top tracks, got %d", len(separate))
+	}
+
+	combined, err := service.GetTopTracks(DashboardRangeLong, 10, true)
+	if err != nil {
+		t.Fatalf("get combined top tracks: %v", err)
+	}
+	if len(combined) != 1 {
+		t.Fatalf("expected 1 combined top track, got %d", len(combined))
+	}
+	if combined[0].TrackID != originalID {
+		t.Fatalf("expected canonical track %d, got %d", originalID, combined[0].TrackID)
+	}
+	if combined[0].PlayedMS != 90000 {
+		t.Fatalf("expected combined played ms 90000, got %d", combined[0].PlayedMS)
+	}
+	if combined[0].VersionCount != 2 {
+		t.Fatalf("expected version count 2, got %d", combined[0].VersionCount)
+	}
+
+	versionStats, err := service.GetTrackStats(liveID, true)
+	if err != nil {
+		t.Fatalf("get track stats: %v", err)
+	}
+	if versionStats.CanonicalTrackID != originalID {
+		t.Fatalf("expected canonical track %d, got %d", originalID, versionStats.CanonicalTrackID)
+	}
+	if len(versionStats.Versions) != 2 {
+		t.Fatalf("expected 2 versions, got %d", len(versionStats.Versions))
+	}
+	if versionStats.Totals.PlayedMS != 90000 {
+		t.Fatalf("expected total played ms 90000, got %d", versionStats.Totals.PlayedMS)
+	}
+}
+
+func TestNormalizeVersionTitleDropsRemasterMarkers(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"Song":                      "song",
+		"Song (2011 Remaster)":      "song",
+		"Song - Remastered 2009":    "song",
+		"Song [Deluxe Edition]":     "song",
+		"Song (Live)":               "song (live)",
+		"Song - Radio Edit":         "song - radio edit",
+		"  Song   (Mono Version)  ": "song",
+	}
+	for title, expected := range cases {
+		if got := normalizeVersionTitle(title); got != expected {
+			t.Fatalf("normalize %q: expected %q, got %q", title, expected, got)
+		}
+	}
+}
+
+func TestDashboardCollapsesVersionsByTitleAndArtist(t *testing.T) {
+	t.Parallel()
+
+	service, database := newStatsServiceForTest(t)
+	defer database.Close()
+
+	originalID := insertTrackForStatsTest(t, database, "Song", "Band")
+	remasterID := insertTrackForStatsTest(t, database, "Song (2011 Remaster)", "Band")
+
+	playedAt := time.Now().UTC().Add(-time.Hour)
+	insertPlayEventForStatsTest(t, database, originalID, EventHeartbeat, 30000, playedAt)
+	insertPlayEventForStatsTest(t, database, remasterID, EventHeartbeat, 60000, playedAt.Add(time.Minute))
+
+	dashboard, err := service.GetDashboardCollapsed(DashboardRangeLong, 10, VersionCollapseTitleArtist)
+	if err != nil {
+		t.Fatalf("get collapsed dashboard: %v", err)
+	}
+	if len(dashboard.TopTracks) != 1 {
+		t.Fatalf("expected 1 collapsed top track, got %d", len(dashboard.TopTracks))
+	}
+	if dashboard.TopTracks[0].TrackID != remasterID || dashboard.TopTracks[0].PlayedMS != 90000 {
+		t.Fatalf("unexpected collapsed top track %+v", dashboard.TopTracks[0])
+	}
+
+	versions, err := service.GetTrackVersions(originalID, VersionCollapseTitleArtist)
+	if err != nil {
+		t.Fatalf("get track versions: %v", err)
+	}
+	if len(versions.Versions) != 2 || versions.Totals.PlayedMS != 90000 {
+		t.Fatalf("unexpected version breakdown %+v", versions)
+	}
+
+	if _, err := service.GetDashboardCollapsed(DashboardRangeLong, 10, "album"); err == nil {
+		t.Fatalf("expected unknown collapse mode to be rejected")
+	}
+}
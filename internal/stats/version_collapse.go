@@ -0,0 +1,243 @@
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Version collapse modes for dashboard top tracks. Linked uses the track
+// links set up by the user; title_artist treats tracks with the same artist
+// and the same title, ignoring remaster and edition suffixes, as versions of
+// one recording.
+const (
+	VersionCollapseNone        = ""
+	VersionCollapseLinked      = "linked"
+	VersionCollapseTitleArtist = "title_artist"
+)
+
+var (
+	versionBracketPattern = regexp.MustCompile(`\s*[\(\[][^\)\]]*\b(remaster|remastered|mono|stereo|deluxe|anniversary|expanded|reissue|bonus)\b[^\)\]]*[\)\]]`)
+	versionSuffixPattern  = regexp.MustCompile(`\s+-\s+[^-]*\b(remaster|remastered|mono|stereo|deluxe|anniversary|expanded|reissue|bonus)\b.*$`)
+)
+
+func normalizeVersionCollapse(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "none", "off":
+		return VersionCollapseNone, nil
+	case VersionCollapseLinked:
+		return VersionCollapseLinked, nil
+	case VersionCollapseTitleArtist:
+		return VersionCollapseTitleArtist, nil
+	default:
+		return "", fmt.Errorf("unsupported version collapse %q", value)
+	}
+}
+
+// normalizeVersionTitle lowercases a title and drops remaster and edition
+// markers, so "Song (2011 Remaster)" and "Song - Remastered" match "Song".
+// Live versions, edits and mixes are different recordings and are kept apart.
+func normalizeVersionTitle(title string) string {
+	normalized := strings.ToLower(strings.TrimSpace(title))
+	normalized = versionBracketPattern.ReplaceAllString(normalized, "")
+	normalized = versionSuffixPattern.ReplaceAllString(normalized, "")
+	return strings.Join(strings.Fields(normalized), " ")
+}
+
+func versionGroupKey(title string, artist string) string {
+	return normalizeVersionTitle(title) + "\x1f" + strings.ToLower(strings.TrimSpace(artist))
+}
+
+// GetDashboardCollapsed is GetDashboard with top tracks collapsed by the given
+// mode. Each collapsed entry reports its VersionCount; GetTrackVersions
+// returns the per-version breakdown behind it.
+func (s *Service) GetDashboardCollapsed(rangeKey string, limit int, collapse string) (Dashboard, error) {
+	normalizedCollapse, err := normalizeVersionCollapse(collapse)
+	if err != nil {
+		return Dashboard{}, err
+	}
+
+	return s.cachedDashboard(rangeKey, limit, normalizedCollapse)
+}
+
+func (s *Service) readCollapsedTopTracks(ctx context.Context, queryer dashboardQueryer, rangeStart *time.Time, limit int, collapse string) ([]TrackStat, error) {
+	switch collapse {
+	case VersionCollapseLinked:
+		return s.readLinkedTopTracks(ctx, queryer, rangeStart, limit)
+	case VersionCollapseTitleArtist:
+		return s.readTitleArtistTopTracks(ctx, queryer, rangeStart, limit)
+	default:
+		return s.readDashboardTopTracks(ctx, queryer, rangeStart, limit)
+	}
+}
+
+// readTitleArtistTopTracks groups every played track in Go, since the title
+// normalization cannot be expressed in SQLite; a limit of -1 lifts the SQL
+// LIMIT. The most played version of a group represents it.
+func (s *Service) readTitleArtistTopTracks(ctx context.Context, queryer dashboardQueryer, rangeStart *time.Time, limit int) ([]TrackStat, error) {
+	tracks, err := s.readDashboardTopTracks(ctx, queryer, rangeStart, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]TrackStat, 0, len(tracks))
+	groupIndex := make(map[string]int, len(tracks))
+	for _, track := range tracks {
+		key := versionGroupKey(track.Title, track.Artist)
+		index, ok := groupIndex[key]
+		if !ok {
+			track.VersionCount = 1
+			groupIndex[key] = len(groups)
+			groups = append(groups, track)
+			continue
+		}
+
+		group := &groups[index]
+		group.PlayedMS += track.PlayedMS
+		group.CompleteCount += track.CompleteCount
+		group.SkipCount += track.SkipCount
+		group.PartialCount += track.PartialCount
+		group.VersionCount++
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].PlayedMS != groups[j].PlayedMS {
+			return groups[i].PlayedMS > groups[j].PlayedMS
+		}
+		if groups[i].CompleteCount != groups[j].CompleteCount {
+			return groups[i].CompleteCount > groups[j].CompleteCount
+		}
+		if groups[i].PartialCount != groups[j].PartialCount {
+			return groups[i].PartialCount > groups[j].PartialCount
+		}
+		return groups[i].SkipCount < groups[j].SkipCount
+	})
+	if len(groups) > limit {
+		groups = groups[:limit]
+	}
+
+	return groups, nil
+}
+
+// GetTrackVersions returns the all-time per-version breakdown behind a
+// collapsed top track. With no collapse mode only the track itself is listed.
+func (s *Service) GetTrackVersions(trackID int64, collapse string) (TrackVersionStats, error) {
+	normalizedCollapse, err := normalizeVersionCollapse(collapse)
+	if err != nil {
+		return TrackVersionStats{}, err
+	}
+	if normalizedCollapse != VersionCollapseTitleArtist {
+		return s.GetTrackStats(trackID, normalizedCollapse == VersionCollapseLinked)
+	}
+	if s.db == nil {
+		return TrackVersionStats{}, nil
+	}
+	if trackID <= 0 {
+		return TrackVersionStats{}, errors.New("track is required")
+	}
+
+	s.maybeCompact(time.Now().UTC())
+
+	ctx := context.Background()
+	tx, err := s.reader().BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return TrackVersionStats{}, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	trackIDs, err := readTitleArtistVersionGroup(ctx, tx, trackID)
+	if err != nil {
+		return TrackVersionStats{}, err
+	}
+
+	versions, err := readTrackVersionStats(ctx, tx, trackIDs)
+	if err != nil {
+		return TrackVersionStats{}, err
+	}
+	if len(versions) == 0 {
+		return TrackVersionStats{}, fmt.Errorf("track %d not found", trackID)
+	}
+
+	result := TrackVersionStats{
+		CanonicalTrackID: versions[0].TrackID,
+		Combined:         true,
+		Totals:           versions[0],
+		Versions:         versions,
+	}
+	result.Totals.PlayedMS = 0
+	result.Totals.CompleteCount = 0
+	result.Totals.SkipCount = 0
+	result.Totals.PartialCount = 0
+	for _, version := range versions {
+		result.Totals.PlayedMS += version.PlayedMS
+		result.Totals.CompleteCount += version.CompleteCount
+		result.Totals.SkipCount += version.SkipCount
+		result.Totals.PartialCount += version.PartialCount
+	}
+	result.Totals.VersionCount = len(versions)
+
+	if commitErr := tx.Commit(); commitErr != nil {
+		return TrackVersionStats{}, commitErr
+	}
+
+	return result, nil
+}
+
+// readTitleArtistVersionGroup lists the tracks by the same artist whose
+// normalized title matches the given track's.
+func readTitleArtistVersionGroup(ctx context.Context, queryer dashboardQueryer, trackID int64) ([]int64, error) {
+	var title string
+	var artistKey string
+	err := queryer.QueryRowContext(
+		ctx,
+		"SELECT COALESCE(t.title, ''), "+artistKeyExpr("t")+" FROM tracks t WHERE t.id = ?",
+		trackID,
+	).Scan(&title, &artistKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("track %d not found", trackID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := queryer.QueryContext(
+		ctx,
+		`SELECT t.id, COALESCE(t.title, '')
+		 FROM tracks t
+		 JOIN files f ON f.id = t.file_id
+		 WHERE f.file_exists = 1
+		   AND f.is_audiobook = 0
+		   AND `+artistKeyExpr("t")+` = ?
+		 ORDER BY t.id`,
+		artistKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	titleKey := normalizeVersionTitle(title)
+	trackIDs := []int64{trackID}
+	for rows.Next() {
+		var candidateID int64
+		var candidateTitle string
+		if scanErr := rows.Scan(&candidateID, &candidateTitle); scanErr != nil {
+			return nil, scanErr
+		}
+		if candidateID != trackID && normalizeVersionTitle(candidateTitle) == titleKey {
+			trackIDs = append(trackIDs, candidateID)
+		}
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, rowsErr
+	}
+
+	return trackIDs, nil
+}
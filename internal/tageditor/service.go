@@ -0,0 +1,345 @@
+// Package tageditor writes tag edits made in the app back to the audio files.
+// The library itself is not changed here; the edited files are rescanned so
+// tracks, albums and artists pick the new tags up the same way as edits made
+// in any other tagger.
+package tageditor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.senan.xyz/taglib"
+
+	"github.com/rzxx/ben/internal/archive"
+	"github.com/rzxx/ben/internal/remote"
+)
+
+// maxTracksPerEdit bounds one edit so a stray select-all cannot rewrite the
+// whole library.
+const maxTracksPerEdit = 500
+
+// artistsTag is the multi-value artist credit written by MusicBrainz Picard
+// and other taggers.
+const artistsTag = "ARTISTS"
+
+var (
+	ErrNoChanges     = errors.New("no tag changes given")
+	ErrTooManyTracks = fmt.Errorf("tag edits are limited to %d tracks at a time", maxTracksPerEdit)
+	ErrTrackNotFound = errors.New("track not found")
+	ErrReadOnlyFile  = errors.New("tags of remote and archived files cannot be edited")
+)
+
+// Edit holds the tags to change. Nil fields keep what the files have.
+type Edit struct {
+	Title   *string `json:"title,omitempty"`
+	Artist  *string `json:"artist,omitempty"`
+	Album   *string `json:"album,omitempty"`
+	TrackNo *int    `json:"trackNo,omitempty"`
+	Genre   *string `json:"genre,omitempty"`
+}
+
+// Failure is a track whose file could not be written.
+type Failure struct {
+	TrackID int64  `json:"trackId"`
+	Path    string `json:"path"`
+	Error   string `json:"error"`
+}
+
+// Result lists the tracks whose files were written and those that failed.
+// Changes holds what was written to each file so the edit can be undone.
+type Result struct {
+	Updated []int64      `json:"updated"`
+	Failed  []Failure    `json:"failed"`
+	Changes []FileChange `json:"-"`
+}
+
+// FileChange is what an edit wrote to one file. Before holds the values the
+// edited tags had, empty for tags the file did not have.
+type FileChange struct {
+	Path   string              `json:"path"`
+	Before map[string][]string `json:"before"`
+	After  map[string][]string `json:"after"`
+}
+
+// Rescanner queues an incremental scan of changed files.
+type Rescanner func(paths []string)
+
+type Service struct {
+	db *sql.DB
+
+	mu     sync.Mutex
+	rescan Rescanner
+}
+
+func NewService(database *sql.DB) *Service {
+	return &Service{db: database}
+}
+
+// SetRescanner sets what rescans the files after an edit.
+func (s *Service) SetRescanner(rescan Rescanner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rescan = rescan
+}
+
+// EditTracks writes edit to the files of trackIDs and queues a rescan of the
+// files that changed. The tags the files had are read first, so the result
+// can undo the edit. A file that fails is reported in the result and does
+// not stop the others.
+func (s *Service) EditTracks(ctx context.Context, trackIDs []int64, edit Edit) (Result, error) {
+	result := Result{Updated: []int64{}, Failed: []Failure{}, Changes: []FileChange{}}
+
+	tags, err := tagMap(edit)
+	if err != nil {
+		return result, err
+	}
+	trackIDs = uniqueTrackIDs(trackIDs)
+	if len(trackIDs) == 0 {
+		return result, nil
+	}
+	if len(trackIDs) > maxTracksPerEdit {
+		return result, ErrTooManyTracks
+	}
+
+	paths, err := s.trackPaths(ctx, trackIDs)
+	if err != nil {
+		return result, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	written := make(map[string]error, len(paths))
+	changed := make([]string, 0, len(paths))
+	for _, trackID := range trackIDs {
+		path, ok := paths[trackID]
+		if !ok {
+			result.Failed = append(result.Failed, Failure{TrackID: trackID, Error: ErrTrackNotFound.Error()})
+			continue
+		}
+
+		// Tracks cut from one file share its tags; write the file once.
+		writeErr, done := written[path]
+		if !done {
+			var before map[string][]string
+			before, writeErr = s.editFile(path, tags)
+			written[path] = writeErr
+			if writeErr == nil {
+				changed = append(changed, path)
+				result.Changes = append(result.Changes, FileChange{Path: path, Before: before, After: tags})
+			}
+		}
+
+		if writeErr != nil {
+			result.Failed = append(result.Failed, Failure{TrackID: trackID, Path: path, Error: writeErr.Error()})
+			continue
+		}
+		result.Updated = append(result.Updated, trackID)
+	}
+
+	if len(changed) > 0 && s.rescan != nil {
+		s.rescan(changed)
+	}
+
+	return result, nil
+}
+
+// Revert writes back the tags the files had before the changes and queues
+// a rescan. Files that fail do not stop the others.
+func (s *Service) Revert(changes []FileChange) error {
+	return s.writeChanges(changes, func(change FileChange) map[string][]string { return change.Before })
+}
+
+// Reapply writes the changes again after a Revert.
+func (s *Service) Reapply(changes []FileChange) error {
+	return s.writeChanges(changes, func(change FileChange) map[string][]string { return change.After })
+}
+
+func (s *Service) writeChanges(changes []FileChange, tagsOf func(FileChange) map[string][]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	changed := make([]string, 0, len(changes))
+	for _, change := range changes {
+		if err := s.writeFile(change.Path, tagsOf(change)); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		changed = append(changed, change.Path)
+	}
+
+	if len(changed) > 0 && s.rescan != nil {
+		s.rescan(changed)
+	}
+
+	return errors.Join(errs...)
+}
+
+// editFile writes tags to path and returns the values the edited tags had.
+func (s *Service) editFile(path string, tags map[string][]string) (map[string][]string, error) {
+	if err := checkWritable(path); err != nil {
+		return nil, err
+	}
+
+	current, err := readTags(path)
+	if err != nil {
+		return nil, err
+	}
+	before := previousTags(current, tags)
+
+	if err := writeTags(path, tags); err != nil {
+		return nil, err
+	}
+
+	return before, nil
+}
+
+func (s *Service) writeFile(path string, tags map[string][]string) error {
+	if err := checkWritable(path); err != nil {
+		return err
+	}
+
+	return writeTags(path, tags)
+}
+
+func checkWritable(path string) error {
+	if remote.IsRemotePath(path) || archive.IsEntryPath(path) {
+		return ErrReadOnlyFile
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", path)
+	}
+
+	return nil
+}
+
+func (s *Service) trackPaths(ctx context.Context, trackIDs []int64) (map[int64]string, error) {
+	paths := make(map[int64]string, len(trackIDs))
+	if s.db == nil {
+		return paths, nil
+	}
+
+	for _, trackID := range trackIDs {
+		var path string
+		err := s.db.QueryRowContext(ctx, `
+			SELECT f.path
+			FROM tracks t
+			JOIN files f ON f.id = t.file_id
+			WHERE t.id = ? AND f.file_exists = 1
+		`, trackID).Scan(&path)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get path of track %d: %w", trackID, err)
+		}
+		paths[trackID] = path
+	}
+
+	return paths, nil
+}
+
+// tagMap turns an edit into the tags to write, leaving unset fields out so
+// the files keep them.
+func tagMap(edit Edit) (map[string][]string, error) {
+	tags := make(map[string][]string)
+
+	text := []struct {
+		key   string
+		name  string
+		value *string
+	}{
+		{taglib.Title, "title", edit.Title},
+		{taglib.Artist, "artist", edit.Artist},
+		{taglib.Album, "album", edit.Album},
+		{taglib.Genre, "genre", edit.Genre},
+	}
+	for _, field := range text {
+		if field.value == nil {
+			continue
+		}
+		value := strings.TrimSpace(*field.value)
+		if value == "" {
+			return nil, fmt.Errorf("%s cannot be empty", field.name)
+		}
+		tags[field.key] = []string{value}
+	}
+
+	// The scanner credits ARTISTS over ARTIST, so a stale list would hide
+	// the new artist. An empty value removes it; undo writes it back.
+	if edit.Artist != nil {
+		tags[artistsTag] = []string{}
+	}
+
+	if edit.TrackNo != nil {
+		if *edit.TrackNo <= 0 {
+			return nil, errors.New("track number must be positive")
+		}
+		tags[taglib.TrackNumber] = []string{strconv.Itoa(*edit.TrackNo)}
+	}
+
+	if len(tags) == 0 {
+		return nil, ErrNoChanges
+	}
+
+	return tags, nil
+}
+
+// previousTags returns the current values of the tags an edit changes.
+// Tags the file does not have map to an empty value, which removes them
+// when written back.
+func previousTags(current map[string][]string, tags map[string][]string) map[string][]string {
+	before := make(map[string][]string, len(tags))
+	for key := range tags {
+		before[key] = slices.Clone(current[key])
+		if before[key] == nil {
+			before[key] = []string{}
+		}
+	}
+
+	return before
+}
+
+func uniqueTrackIDs(trackIDs []int64) []int64 {
+	seen := make(map[int64]struct{}, len(trackIDs))
+	unique := make([]int64, 0, len(trackIDs))
+	for _, trackID := range trackIDs {
+		if trackID <= 0 {
+			continue
+		}
+		if _, ok := seen[trackID]; ok {
+			continue
+		}
+		seen[trackID] = struct{}{}
+		unique = append(unique, trackID)
+	}
+	return unique
+}
+
+func readTags(path string) (map[string][]string, error) {
+	tags, err := taglib.ReadTags(path)
+	if err != nil {
+		return nil, fmt.Errorf("read tags of %s: %w", path, err)
+	}
+	return tags, nil
+}
+
+func writeTags(path string, tags map[string][]string) error {
+	if err := taglib.WriteTags(path, tags, 0); err != nil {
+		return fmt.Errorf("write tags to %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package tageditor
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"go.senan.xyz/taglib"
+)
+
+func TestTagMapWritesOnlySetFields(t *testing.T) {
+	t.Parallel()
+
+	artist := "  Nina Simone "
+	trackNo := 4
+	tags, err := tagMap(Edit{Artist: &artist, TrackNo: &trackNo})
+	if err != nil {
+		t.Fatalf("tag map: %v", err)
+	}
+	if len(tags) != 3 {
+		t.Fatalf("expected 3 tags, got %v", tags)
+	}
+	if !slices.Equal(tags[taglib.Artist], []string{"Nina Simone"}) {
+		t.Fatalf("expected trimmed artist, got %v", tags[taglib.Artist])
+	}
+	if artists, ok := tags[artistsTag]; !ok || len(artists) != 0 {
+		t.Fatalf("expected the artist edit to clear ARTISTS, got %#v", artists)
+	}
+	if !slices.Equal(tags[taglib.TrackNumber], []string{"4"}) {
+		t.Fatalf("expected track number 4, got %v", tags[taglib.TrackNumber])
+	}
+}
+
+func TestTagMapRejectsEmptyEdits(t *testing.T) {
+	t.Parallel()
+
+	if _, err := tagMap(Edit{}); !errors.Is(err, ErrNoChanges) {
+		t.Fatalf("expected ErrNoChanges, got %v", err)
+	}
+
+	blank := " "
+	if _, err := tagMap(Edit{Title: &blank}); err == nil {
+		t.Fatalf("expected a blank title to be rejected")
+	}
+
+	zero := 0
+	if _, err := tagMap(Edit{TrackNo: &zero}); err == nil {
+		t.Fatalf("expected track number 0 to be rejected")
+	}
+}
+
+func TestUniqueTrackIDsDropsDuplicatesAndInvalidIDs(t *testing.T) {
+	t.Parallel()
+
+	if got := uniqueTrackIDs([]int64{3, 1, 3, 0, -2, 1, 5}); !slices.Equal(got, []int64{3, 1, 5}) {
+		t.Fatalf("expected [3 1 5], got %v", got)
+	}
+}
+
+func TestPreviousTagsKeepsOnlyEditedTags(t *testing.T) {
+	t.Parallel()
+
+	current := map[string][]string{
+		taglib.Title:  {"Old Title"},
+		taglib.Artist: {"A", "B"},
+		taglib.Album:  {"Kept Album"},
+	}
+	tags := map[string][]string{
+		taglib.Artist: {"Nina Simone"},
+		taglib.Genre:  {"Jazz"},
+	}
+
+	before := previousTags(current, tags)
+	if len(before) != 2 {
+		t.Fatalf("expected only the edited tags, got %v", before)
+	}
+	if !slices.Equal(before[taglib.Artist], []string{"A", "B"}) {
+		t.Fatalf("expected the previous artists, got %v", before[taglib.Artist])
+	}
+	// A tag the file did not have is written back empty, which removes it.
+	if genre, ok := before[taglib.Genre]; !ok || genre == nil || len(genre) != 0 {
+		t.Fatalf("expected an empty genre to remove the tag, got %#v", genre)
+	}
+
+	current[taglib.Artist][0] = "changed"
+	if before[taglib.Artist][0] != "A" {
+		t.Fatalf("expected previous tags to be copied")
+	}
+}
+
+func TestRevertRescansTheFilesItWrites(t *testing.T) {
+	t.Parallel()
+
+	service := NewService(nil)
+	var rescanned []string
+	service.SetRescanner(func(paths []string) {
+		rescanned = append(rescanned, paths...)
+	})
+
+	err := service.Revert([]FileChange{
+		{Path: "https://nas.local/a.flac", Before: map[string][]string{taglib.Title: {"A"}}},
+		{Path: "/missing/b.flac", Before: map[string][]string{taglib.Title: {"B"}}},
+	})
+	if !errors.Is(err, ErrReadOnlyFile) {
+		t.Fatalf("expected the remote file to be reported, got %v", err)
+	}
+	if len(rescanned) != 0 {
+		t.Fatalf("expected no rescan when nothing was written, got %v", rescanned)
+	}
+}
+
+func TestArtistEditClearsArtistsAndUndoRestoresThem(t *testing.T) {
+	t.Parallel()
+
+	path := writeSilentWAV(t)
+	if err := taglib.WriteTags(path, map[string][]string{
+		taglib.Artist: {"Simon & Garfunkel"},
+		artistsTag:    {"Paul Simon", "Art Garfunkel"},
+	}, 0); err != nil {
+		t.Fatalf("write initial tags: %v", err)
+	}
+
+	artist := "Paul Simon"
+	tags, err := tagMap(Edit{Artist: &artist})
+	if err != nil {
+		t.Fatalf("tag map: %v", err)
+	}
+	service := NewService(nil)
+	before, err := service.editFile(path, tags)
+	if err != nil {
+		t.Fatalf("edit file: %v", err)
+	}
+
+	edited, err := readTags(path)
+	if err != nil {
+		t.Fatalf("read edited tags: %v", err)
+	}
+	if !slices.Equal(edited[taglib.Artist], []string{"Paul Simon"}) || len(edited[artistsTag]) != 0 {
+		t.Fatalf("expected only the new artist, got %v", edited)
+	}
+
+	if err := service.Revert([]FileChange{{Path: path, Before: before, After: tags}}); err != nil {
+		t.Fatalf("revert: %v", err)
+	}
+	reverted, err := readTags(path)
+	if err != nil {
+		t.Fatalf("read reverted tags: %v", err)
+	}
+	if !slices.Equal(reverted[taglib.Artist], []string{"Simon & Garfunkel"}) {
+		t.Fatalf("expected the previous artist back, got %v", reverted[taglib.Artist])
+	}
+	if !slices.Equal(reverted[artistsTag], []string{"Paul Simon", "Art Garfunkel"}) {
+		t.Fatalf("expected the previous ARTISTS back, got %v", reverted[artistsTag])
+	}
+}
+
+// writeSilentWAV writes a tenth of a second of 8 kHz mono silence, the
+// smallest file taglib reads and writes tags on.
+func writeSilentWAV(t *testing.T) string {
+	t.Helper()
+
+	const samples = 800
+	data := []byte("RIFF")
+	data = binary.LittleEndian.AppendUint32(data, 36+samples*2)
+	data = append(data, "WAVEfmt "...)
+	data = binary.LittleEndian.AppendUint32(data, 16)
+	data = binary.LittleEndian.AppendUint16(data, 1) // PCM
+	data = binary.LittleEndian.AppendUint16(data, 1) // mono
+	data = binary.LittleEndian.AppendUint32(data, 8000)
+	data = binary.LittleEndian.AppendUint32(data, 8000*2)
+	data = binary.LittleEndian.AppendUint16(data, 2)
+	data = binary.LittleEndian.AppendUint16(data, 16)
+	data = append(data, "data"...)
+	data = binary.LittleEndian.AppendUint32(data, samples*2)
+	data = append(data, make([]byte, samples*2)...)
+
+	path := filepath.Join(t.TempDir(), "track.wav")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write wav: %v", err)
+	}
+
+	return path
+}
@@ -0,0 +1,174 @@
+package undo
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/rzxx/ben/internal/eventbus"
+)
+
+const EventChanged = "undo:changed"
+
+const DefaultLimit = 50
+
+var (
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrNothingToRedo = errors.New("nothing to redo")
+)
+
+type Emitter func(eventName string, payload any)
+
+// Operation is a destructive action that has already been applied. Undo
+// reverts it and Redo applies it again; both must call the domain services
+// directly so they are not recorded a second time.
+type Operation struct {
+	Label string
+	Undo  func() error
+	Redo  func() error
+}
+
+type State struct {
+	CanUndo   bool   `json:"canUndo"`
+	CanRedo   bool   `json:"canRedo"`
+	UndoLabel string `json:"undoLabel,omitempty"`
+	RedoLabel string `json:"redoLabel,omitempty"`
+	UndoCount int    `json:"undoCount"`
+	RedoCount int    `json:"redoCount"`
+}
+
+// Journal keeps a bounded history of operations. Recording a new operation
+// drops everything that was undone, as in a text editor.
+type Journal struct {
+	mu     sync.Mutex
+	run    sync.Mutex
+	limit  int
+	done   []Operation
+	undone []Operation
+	emit   Emitter
+}
+
+func NewJournal(limit int, bus *eventbus.Bus) *Journal {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	return &Journal{limit: limit, emit: bus.Publish}
+}
+
+func (j *Journal) Record(operation Operation) {
+	if operation.Undo == nil || operation.Redo == nil {
+		return
+	}
+	operation.Label = strings.TrimSpace(operation.Label)
+
+	j.run.Lock()
+	defer j.run.Unlock()
+
+	j.mu.Lock()
+	j.done = append(j.done, operation)
+	if len(j.done) > j.limit {
+		j.done = append([]Operation(nil), j.done[len(j.done)-j.limit:]...)
+	}
+	j.undone = nil
+	state := j.stateLocked()
+	j.mu.Unlock()
+
+	j.emitState(state)
+}
+
+func (j *Journal) Undo() (State, error) {
+	return j.step(false)
+}
+
+func (j *Journal) Redo() (State, error) {
+	return j.step(true)
+}
+
+func (j *Journal) State() State {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.stateLocked()
+}
+
+func (j *Journal) Clear() State {
+	j.run.Lock()
+	defer j.run.Unlock()
+
+	j.mu.Lock()
+	j.done = nil
+	j.undone = nil
+	state := j.stateLocked()
+	j.mu.Unlock()
+
+	j.emitState(state)
+	return state
+}
+
+// step moves the newest operation between the undo and redo stacks. run
+// keeps Record from reordering the stacks while the operation is applied;
+// a failed operation stays where it was.
+func (j *Journal) step(redo bool) (State, error) {
+	j.run.Lock()
+	defer j.run.Unlock()
+
+	j.mu.Lock()
+	from := &j.done
+	to := &j.undone
+	emptyErr := ErrNothingToUndo
+	if redo {
+		from, to = to, from
+		emptyErr = ErrNothingToRedo
+	}
+	if len(*from) == 0 {
+		state := j.stateLocked()
+		j.mu.Unlock()
+		return state, emptyErr
+	}
+	operation := (*from)[len(*from)-1]
+	j.mu.Unlock()
+
+	apply := operation.Undo
+	if redo {
+		apply = operation.Redo
+	}
+	if err := apply(); err != nil {
+		return j.State(), err
+	}
+
+	j.mu.Lock()
+	*from = (*from)[:len(*from)-1]
+	*to = append(*to, operation)
+	state := j.stateLocked()
+	j.mu.Unlock()
+
+	j.emitState(state)
+	return state, nil
+}
+
+func (j *Journal) stateLocked() State {
+	state := State{
+		CanUndo:   len(j.done) > 0,
+		CanRedo:   len(j.undone) > 0,
+		UndoCount: len(j.done),
+		RedoCount: len(j.undone),
+	}
+	if state.CanUndo {
+		state.UndoLabel = j.done[len(j.done)-1].Label
+	}
+	if state.CanRedo {
+		state.RedoLabel = j.undone[len(j.undone)-1].Label
+	}
+
+	return state
+}
+
+func (j *Journal) emitState(state State) {
+	j.mu.Lock()
+	emitter := j.emit
+	j.mu.Unlock()
+
+	if emitter != nil {
+		emitter(EventChanged, state)
+	}
+}
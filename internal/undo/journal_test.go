@@ -0,0 +1,84 @@
+package undo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestJournalUndoRedoMovesOperationsBetweenStacks(t *testing.T) {
+	t.Parallel()
+
+	journal := NewJournal(10, nil)
+	value := 1
+	journal.Record(Operation{
+		Label: "Set value",
+		Undo:  func() error { value = 1; return nil },
+		Redo:  func() error { value = 2; return nil },
+	})
+	value = 2
+
+	state, err := journal.Undo()
+	if err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	if value != 1 || state.CanUndo || !state.CanRedo || state.RedoLabel != "Set value" {
+		t.Fatalf("unexpected state after undo: value=%d %+v", value, state)
+	}
+
+	state, err = journal.Redo()
+	if err != nil {
+		t.Fatalf("redo: %v", err)
+	}
+	if value != 2 || !state.CanUndo || state.CanRedo || state.UndoLabel != "Set value" {
+		t.Fatalf("unexpected state after redo: value=%d %+v", value, state)
+	}
+
+	if _, err := journal.Redo(); !errors.Is(err, ErrNothingToRedo) {
+		t.Fatalf("expected ErrNothingToRedo, got %v", err)
+	}
+}
+
+func TestJournalRecordDropsRedoAndKeepsLimit(t *testing.T) {
+	t.Parallel()
+
+	journal := NewJournal(2, nil)
+	noop := func() error { return nil }
+	for _, label := range []string{"first", "second", "third"} {
+		journal.Record(Operation{Label: label, Undo: noop, Redo: noop})
+	}
+
+	state := journal.State()
+	if state.UndoCount != 2 || state.UndoLabel != "third" {
+		t.Fatalf("expected two newest operations, got %+v", state)
+	}
+
+	if _, err := journal.Undo(); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	journal.Record(Operation{Label: "fourth", Undo: noop, Redo: noop})
+
+	state = journal.State()
+	if state.CanRedo || state.UndoCount != 2 || state.UndoLabel != "fourth" {
+		t.Fatalf("expected redo history to be dropped, got %+v", state)
+	}
+}
+
+func TestJournalFailedUndoKeepsOperation(t *testing.T) {
+	t.Parallel()
+
+	journal := NewJournal(0, nil)
+	failure := errors.New("restore failed")
+	journal.Record(Operation{
+		Label: "Delete playlist",
+		Undo:  func() error { return failure },
+		Redo:  func() error { return nil },
+	})
+
+	state, err := journal.Undo()
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected undo error, got %v", err)
+	}
+	if !state.CanUndo || state.CanRedo {
+		t.Fatalf("expected operation to stay on the undo stack, got %+v", state)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/rzxx/ben/internal/eventbus"
+	"github.com/rzxx/ben/internal/jobs"
+	"github.com/rzxx/ben/internal/scanner"
+)
+
+const (
+	JobKindScan       = "scan"
+	JobKindBackup     = "backup"
+	JobKindImport     = "import"
+	JobKindSyncExport = "syncExport"
+	JobKindVerify     = "verify"
+)
+
+type JobsService struct {
+	jobs    *jobs.Manager
+	scanner *scanner.Service
+
+	mu       sync.Mutex
+	scanTask *jobs.Task
+}
+
+func NewJobsService(jobManager *jobs.Manager, scanService *scanner.Service, bus *eventbus.Bus) *JobsService {
+	service := &JobsService{jobs: jobManager, scanner: scanService}
+	eventbus.Subscribe(bus, scanner.EventProgress, service.HandleScanProgress)
+
+	return service
+}
+
+func (s *JobsService) ListJobs() []jobs.Job {
+	return s.jobs.List()
+}
+
+func (s *JobsService) CancelJob(id string) error {
+	return s.jobs.Cancel(id)
+}
+
+// HandleScanProgress turns the scanner's progress events into one job per
+// scan. Watcher events are not scans and are left to the status endpoint.
+func (s *JobsService) HandleScanProgress(progress scanner.Progress) {
+	if progress.Phase == "watcher" {
+		return
+	}
+
+	s.mu.Lock()
+	task := s.scanTask
+	if progress.Status != "running" {
+		s.scanTask = nil
+	} else if task == nil {
+		task = s.jobs.Start(JobKindScan, "Library scan", func() { s.scanner.CancelScan() })
+		s.scanTask = task
+	}
+	s.mu.Unlock()
+
+	if task == nil {
+		return
+	}
+
+	switch progress.Status {
+	case "running":
+		task.Progress(progress.Percent, progress.Message)
+	case "completed":
+		task.Complete(progress.Message)
+	case "canceled":
+		task.Canceled()
+	default:
+		task.Fail(errors.New(progress.Message))
+	}
+}
+
+// runJob runs work as a job that can be canceled through its context.
+func runJob[T any](manager *jobs.Manager, kind string, label string, work func(ctx context.Context) (T, error)) (T, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	task := manager.Start(kind, label, cancel)
+	result, err := work(ctx)
+	if err != nil {
+		task.Fail(err)
+		return result, err
+	}
+
+	task.Complete("")
+	return result, nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rzxx/ben/internal/keybindings"
+)
+
+type KeybindingsService struct {
+	keybindings *keybindings.Service
+}
+
+func NewKeybindingsService(keybindingsService *keybindings.Service) *KeybindingsService {
+	return &KeybindingsService{keybindings: keybindingsService}
+}
+
+func (s *KeybindingsService) ListKeybindings() ([]keybindings.Binding, error) {
+	return s.keybindings.List(context.Background())
+}
+
+func (s *KeybindingsService) SetKeybinding(commandID string, keys []string) ([]keybindings.Binding, error) {
+	return s.keybindings.SetBinding(context.Background(), commandID, keys)
+}
+
+func (s *KeybindingsService) ResetKeybinding(commandID string) ([]keybindings.Binding, error) {
+	return s.keybindings.ResetBinding(context.Background(), commandID)
+}
+
+func (s *KeybindingsService) ResetAllKeybindings() ([]keybindings.Binding, error) {
+	return s.keybindings.ResetAll(context.Background())
+}
@@ -1,16 +1,55 @@
 package main
 
 import (
-	"ben/internal/library"
 	"context"
+	"log"
+
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/queue"
+	"github.com/rzxx/ben/internal/snapshot"
+	"github.com/rzxx/ben/internal/undo"
 )
 
 type LibraryService struct {
-	browse *library.BrowseRepository
+	browse     *library.BrowseRepository
+	links      *library.TrackLinkRepository
+	ratings    *library.RatingRepository
+	moods      *library.MoodRepository
+	provenance *library.ProvenanceRepository
+	scripts    *library.ScriptRepository
+	genres     *library.GenreRepository
+	duplicates *library.DuplicateFinder
+	queue      *queue.Service
+	journal    *undo.Journal
+	snapshots  *snapshot.Service
 }
 
-func NewLibraryService(browse *library.BrowseRepository) *LibraryService {
-	return &LibraryService{browse: browse}
+func NewLibraryService(
+	browse *library.BrowseRepository,
+	links *library.TrackLinkRepository,
+	ratings *library.RatingRepository,
+	moods *library.MoodRepository,
+	provenance *library.ProvenanceRepository,
+	scripts *library.ScriptRepository,
+	genres *library.GenreRepository,
+	duplicates *library.DuplicateFinder,
+	queueDomain *queue.Service,
+	journal *undo.Journal,
+	snapshots *snapshot.Service,
+) *LibraryService {
+	return &LibraryService{
+		browse:     browse,
+		links:      links,
+		ratings:    ratings,
+		moods:      moods,
+		provenance: provenance,
+		scripts:    scripts,
+		genres:     genres,
+		duplicates: duplicates,
+		queue:      queueDomain,
+		journal:    journal,
+		snapshots:  snapshots,
+	}
 }
 
 func (s *LibraryService) ListArtists(search string, limit int, offset int) (library.ArtistsPage, error) {
@@ -18,21 +57,86 @@ func (s *LibraryService) ListArtists(search string, limit int, offset int) (libr
 }
 
 func (s *LibraryService) ListAlbums(search string, artist string, limit int, offset int) (library.AlbumsPage, error) {
-	return s.browse.ListAlbums(context.Background(), search, artist, limit, offset)
+	return s.browse.ListAlbums(context.Background(), search, artist, limit, offset, false)
+}
+
+// GetCoverReport lists covers shared by several albums and albums without
+// a cover, for curating artwork.
+func (s *LibraryService) GetCoverReport(limit int) (library.CoverReport, error) {
+	return s.browse.GetCoverReport(context.Background(), limit)
+}
+
+// ListAlbumsSorted lists albums in a sort mode: "artist" or "color".
+func (s *LibraryService) ListAlbumsSorted(search string, artist string, sortMode string, limit int, offset int) (library.AlbumsPage, error) {
+	return s.browse.ListAlbumsSorted(context.Background(), search, artist, sortMode, limit, offset, false)
+}
+
+func (s *LibraryService) ListAlbumsWithStats(search string, artist string, limit int, offset int) (library.AlbumsPage, error) {
+	return s.browse.ListAlbums(context.Background(), search, artist, limit, offset, true)
+}
+
+// ListAlbumsByRating lists albums like ListAlbumsSorted, narrowed to
+// favorites or to a minimum star rating.
+func (s *LibraryService) ListAlbumsByRating(search string, artist string, sortMode string, filter library.RatingFilter, limit int, offset int) (library.AlbumsPage, error) {
+	return s.browse.ListAlbumsByRating(context.Background(), search, artist, sortMode, filter, limit, offset, false)
 }
 
 func (s *LibraryService) ListTracks(search string, artist string, album string, limit int, offset int) (library.TracksPage, error) {
-	return s.browse.ListTracks(context.Background(), search, artist, album, limit, offset)
+	return s.browse.ListTracks(context.Background(), search, artist, album, limit, offset, false)
+}
+
+func (s *LibraryService) ListTracksWithStats(search string, artist string, album string, limit int, offset int) (library.TracksPage, error) {
+	return s.browse.ListTracks(context.Background(), search, artist, album, limit, offset, true)
+}
+
+// ListTracksByRating lists tracks like ListTracks, narrowed to favorites
+// or to a minimum star rating.
+func (s *LibraryService) ListTracksByRating(search string, artist string, album string, filter library.RatingFilter, limit int, offset int) (library.TracksPage, error) {
+	return s.browse.ListTracksByRating(context.Background(), search, artist, album, filter, limit, offset, false)
+}
+
+func (s *LibraryService) ListTracksByRatingWithStats(search string, artist string, album string, filter library.RatingFilter, limit int, offset int) (library.TracksPage, error) {
+	return s.browse.ListTracksByRating(context.Background(), search, artist, album, filter, limit, offset, true)
+}
+
+// ListRecentlyAdded lists albums newest first by when they were indexed.
+func (s *LibraryService) ListRecentlyAdded(limit int, offset int) (library.AlbumsPage, error) {
+	return s.browse.ListRecentlyAdded(context.Background(), limit, offset)
+}
+
+// ListRecentlyPlayed lists played tracks, most recently played first.
+func (s *LibraryService) ListRecentlyPlayed(limit int, offset int) (library.TracksPage, error) {
+	return s.browse.ListRecentlyPlayed(context.Background(), limit, offset)
 }
 
 func (s *LibraryService) GetArtistDetail(name string, limit int, offset int) (library.ArtistDetail, error) {
 	return s.browse.GetArtistDetail(context.Background(), name, limit, offset)
 }
 
+func (s *LibraryService) GetArtistTimeline(name string) (library.ArtistTimeline, error) {
+	return s.browse.GetArtistTimeline(context.Background(), name)
+}
+
 func (s *LibraryService) GetAlbumDetail(title string, albumArtist string, limit int, offset int) (library.AlbumDetail, error) {
 	return s.browse.GetAlbumDetail(context.Background(), title, albumArtist, limit, offset)
 }
 
+func (s *LibraryService) GetAlbumCoverPreviews(keys []library.AlbumKey) ([]library.AlbumCoverPreview, error) {
+	return s.browse.GetAlbumCoverPreviews(context.Background(), keys)
+}
+
+func (s *LibraryService) GetIncompleteAlbums() ([]library.IncompleteAlbum, error) {
+	return s.browse.GetIncompleteAlbums(context.Background())
+}
+
+func (s *LibraryService) ListBoxSets() ([]library.BoxSetSummary, error) {
+	return s.browse.ListBoxSets(context.Background())
+}
+
+func (s *LibraryService) GetBoxSetDetail(name string, albumArtist string) (library.BoxSetDetail, error) {
+	return s.browse.GetBoxSetDetail(context.Background(), name, albumArtist)
+}
+
 func (s *LibraryService) GetAlbumQueueTrackIDs(title string, albumArtist string) ([]int64, error) {
 	return s.browse.GetAlbumQueueTrackIDs(context.Background(), title, albumArtist)
 }
@@ -52,3 +156,347 @@ func (s *LibraryService) GetArtistTopTracks(name string, limit int) ([]library.A
 func (s *LibraryService) GetArtistQueueTrackIDsFromTopTrack(name string, trackID int64) ([]int64, error) {
 	return s.browse.GetArtistQueueTrackIDsFromTopTrack(context.Background(), name, trackID)
 }
+
+// BuildArtistRadio returns track IDs for a radio queue seeded by an artist,
+// to be queued with a radio source.
+func (s *LibraryService) BuildArtistRadio(name string) ([]int64, error) {
+	return s.browse.BuildArtistRadio(context.Background(), name)
+}
+
+func (s *LibraryService) ListMoods() ([]library.MoodSummary, error) {
+	return s.moods.ListMoods(context.Background())
+}
+
+func (s *LibraryService) GetTrackMoods(trackID int64) (library.TrackMoods, error) {
+	return s.moods.GetTrackMoods(context.Background(), trackID)
+}
+
+func (s *LibraryService) SetTrackMoods(trackID int64, moods []string) (library.TrackMoods, error) {
+	return s.moods.SetTrackMoods(context.Background(), trackID, moods)
+}
+
+// EstimateMoods refreshes the moods estimated from MOOD and BPM tags and
+// returns how many tracks got one.
+func (s *LibraryService) EstimateMoods() (int, error) {
+	return s.moods.EstimateMoods(context.Background())
+}
+
+func (s *LibraryService) ListTracksByMood(moods []string, search string, limit int, offset int) (library.TracksPage, error) {
+	return s.moods.ListTracksByMood(context.Background(), moods, search, limit, offset, false)
+}
+
+func (s *LibraryService) ListTracksByMoodWithStats(moods []string, search string, limit int, offset int) (library.TracksPage, error) {
+	return s.moods.ListTracksByMood(context.Background(), moods, search, limit, offset, true)
+}
+
+// GetMoodQueueTrackIDs returns a shuffled queue of tracks carrying any of
+// the moods, for "play by mood".
+func (s *LibraryService) GetMoodQueueTrackIDs(moods []string) ([]int64, error) {
+	return s.moods.GetMoodQueueTrackIDs(context.Background(), moods)
+}
+
+// ListLanguages lists the scripts and languages detected in track titles,
+// artists and albums, with their track counts.
+func (s *LibraryService) ListLanguages() ([]library.LanguageSummary, error) {
+	return s.scripts.ListLanguages(context.Background())
+}
+
+// ListTracksByLanguage lists tracks written in a script, such as "cjk", and
+// optionally one language of it, such as "ja".
+func (s *LibraryService) ListTracksByLanguage(script string, language string, search string, limit int, offset int) (library.TracksPage, error) {
+	return s.scripts.ListTracksByLanguage(context.Background(), script, language, search, limit, offset, false)
+}
+
+func (s *LibraryService) ListTracksByLanguageWithStats(script string, language string, search string, limit int, offset int) (library.TracksPage, error) {
+	return s.scripts.ListTracksByLanguage(context.Background(), script, language, search, limit, offset, true)
+}
+
+// ListArtistSections returns the jump-to index of the artist list, with
+// separate sections per script.
+func (s *LibraryService) ListArtistSections() ([]library.IndexSection, error) {
+	return s.scripts.ListArtistSections(context.Background())
+}
+
+// ListAlbumSections returns the jump-to index of the album list by album
+// artist.
+func (s *LibraryService) ListAlbumSections() ([]library.IndexSection, error) {
+	return s.scripts.ListAlbumSections(context.Background())
+}
+
+// ListGenres lists the genres split from genre tags with their track and
+// album counts.
+func (s *LibraryService) ListGenres() ([]library.GenreSummary, error) {
+	return s.genres.ListGenres(context.Background())
+}
+
+func (s *LibraryService) GetGenreDetail(name string, limit int, offset int) (library.GenreDetail, error) {
+	return s.genres.GetGenreDetail(context.Background(), name, limit, offset)
+}
+
+// GetGenreQueueTrackIDs returns the tracks of a genre in album order, for
+// playing the whole genre.
+func (s *LibraryService) GetGenreQueueTrackIDs(name string) ([]int64, error) {
+	return s.genres.GetGenreQueueTrackIDs(context.Background(), name)
+}
+
+// ListComposers lists the composers from COMPOSER tags with their work,
+// track and album counts.
+func (s *LibraryService) ListComposers(search string, limit int, offset int) (library.ComposersPage, error) {
+	return s.browse.ListComposers(context.Background(), search, limit, offset)
+}
+
+// GetComposerDetail pages through a composer's works in catalog order.
+func (s *LibraryService) GetComposerDetail(name string, limit int, offset int) (library.ComposerDetail, error) {
+	return s.browse.GetComposerDetail(context.Background(), name, limit, offset)
+}
+
+// GetWorkDetail lists the recordings of a work with their movements.
+func (s *LibraryService) GetWorkDetail(composer string, work string) (library.WorkDetail, error) {
+	return s.browse.GetWorkDetail(context.Background(), composer, work)
+}
+
+func (s *LibraryService) GetWorkQueueTrackIDs(composer string, work string) ([]int64, error) {
+	return s.browse.GetWorkQueueTrackIDs(context.Background(), composer, work)
+}
+
+func (s *LibraryService) GetTrackProvenance(trackID int64) (library.TrackProvenance, error) {
+	return s.provenance.GetTrackProvenance(context.Background(), trackID)
+}
+
+func (s *LibraryService) SetTrackProvenance(trackID int64, provenance library.TrackProvenance) (library.TrackProvenance, error) {
+	return s.provenance.SetTrackProvenance(context.Background(), trackID, provenance)
+}
+
+// ImportProvenanceFromTags refreshes the provenance read from COMMENT and
+// WWWAUDIOFILE tags and returns how many files got one.
+func (s *LibraryService) ImportProvenanceFromTags() (int, error) {
+	return s.provenance.ImportProvenanceFromTags(context.Background())
+}
+
+func (s *LibraryService) ListProvenanceValues(field string) ([]library.ProvenanceSummary, error) {
+	return s.provenance.ListProvenanceValues(context.Background(), field)
+}
+
+func (s *LibraryService) ListTracksByProvenance(field string, value string, search string, limit int, offset int) (library.TracksPage, error) {
+	return s.provenance.ListTracksByProvenance(context.Background(), field, value, search, limit, offset, false)
+}
+
+func (s *LibraryService) ListTracksByProvenanceWithStats(field string, value string, search string, limit int, offset int) (library.TracksPage, error) {
+	return s.provenance.ListTracksByProvenance(context.Background(), field, value, search, limit, offset, true)
+}
+
+// LinkTracks snapshots the version groups first so the merge can be rolled
+// back from the snapshot service.
+func (s *LibraryService) LinkTracks(canonicalTrackID int64, trackIDs []int64) (library.TrackLinkGroup, error) {
+	ctx := context.Background()
+	if err := s.snapshots.Capture(ctx, "Link tracks", "track_links"); err != nil {
+		return library.TrackLinkGroup{}, err
+	}
+
+	return s.links.Link(ctx, canonicalTrackID, trackIDs)
+}
+
+func (s *LibraryService) UnlinkTracks(trackIDs []int64) error {
+	ctx := context.Background()
+	if err := s.snapshots.Capture(ctx, "Unlink tracks", "track_links"); err != nil {
+		return err
+	}
+
+	return s.links.Unlink(ctx, trackIDs)
+}
+
+func (s *LibraryService) GetTrackLinks(trackID int64) (library.TrackLinkGroup, error) {
+	return s.links.GetGroup(context.Background(), trackID)
+}
+
+// FindDuplicateTracks reports identical files and tracks with the same
+// title and artist whose durations differ by at most toleranceMS.
+func (s *LibraryService) FindDuplicateTracks(toleranceMS int) (library.DuplicateReport, error) {
+	return s.duplicates.FindDuplicates(context.Background(), toleranceMS)
+}
+
+// HideDuplicateTracks snapshots the hidden duplicates first so the change
+// can be rolled back from the snapshot service.
+func (s *LibraryService) HideDuplicateTracks(keptTrackID int64, trackIDs []int64) (int, error) {
+	ctx := context.Background()
+	if err := s.snapshots.Capture(ctx, "Hide duplicates", "hidden_duplicates"); err != nil {
+		return 0, err
+	}
+	before, err := s.duplicates.ListHiddenDuplicates(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	hidden, err := s.duplicates.HideDuplicates(ctx, keptTrackID, trackIDs)
+	if err != nil {
+		return hidden, err
+	}
+
+	s.recordHiddenDuplicates("Hide duplicates", before)
+	return hidden, nil
+}
+
+func (s *LibraryService) ShowDuplicateTracks(trackIDs []int64) error {
+	ctx := context.Background()
+	if err := s.snapshots.Capture(ctx, "Show duplicates", "hidden_duplicates"); err != nil {
+		return err
+	}
+	before, err := s.duplicates.ListHiddenDuplicates(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := s.duplicates.ShowDuplicates(ctx, trackIDs); err != nil {
+		return err
+	}
+
+	s.recordHiddenDuplicates("Show duplicates", before)
+	return nil
+}
+
+// KeepBestDuplicateTracks hides all but the highest-quality version of
+// every duplicate group.
+func (s *LibraryService) KeepBestDuplicateTracks(toleranceMS int) (int, error) {
+	ctx := context.Background()
+	if err := s.snapshots.Capture(ctx, "Keep best duplicates", "hidden_duplicates"); err != nil {
+		return 0, err
+	}
+	before, err := s.duplicates.ListHiddenDuplicates(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	hidden, err := s.duplicates.KeepBestDuplicates(ctx, toleranceMS)
+	if err != nil {
+		return hidden, err
+	}
+
+	s.recordHiddenDuplicates("Keep best duplicates", before)
+	return hidden, nil
+}
+
+// recordHiddenDuplicates journals a change to the hidden duplicates, from
+// before to what is hidden now, so it can be undone like a ban.
+func (s *LibraryService) recordHiddenDuplicates(label string, before []library.HiddenDuplicate) {
+	after, err := s.duplicates.ListHiddenDuplicates(context.Background())
+	if err != nil {
+		log.Printf("record %q for undo: %v", label, err)
+		return
+	}
+
+	s.journal.Record(undo.Operation{
+		Label: label,
+		Undo: func() error {
+			return s.duplicates.RestoreHiddenDuplicates(context.Background(), before)
+		},
+		Redo: func() error {
+			return s.duplicates.RestoreHiddenDuplicates(context.Background(), after)
+		},
+	})
+}
+
+// FindDuplicateAlbums reports pairs of albums that look like the same
+// album ripped twice, scored by cover and metadata similarity.
+func (s *LibraryService) FindDuplicateAlbums(minConfidence float64) (library.DuplicateAlbumReport, error) {
+	return s.duplicates.FindDuplicateAlbums(context.Background(), minConfidence)
+}
+
+func (s *LibraryService) SetTrackFavorite(trackID int64, favorite bool) (library.TrackRating, error) {
+	return s.refreshQueuedRating(s.ratings.SetFavorite(context.Background(), trackID, favorite))
+}
+
+// ToggleTrackFavorite flips a track's favorite flag.
+func (s *LibraryService) ToggleTrackFavorite(trackID int64) (library.TrackRating, error) {
+	current, err := s.ratings.Get(context.Background(), trackID)
+	if err != nil {
+		return current, err
+	}
+
+	return s.SetTrackFavorite(trackID, !current.Favorite)
+}
+
+func (s *LibraryService) SetTrackRating(trackID int64, rating int) (library.TrackRating, error) {
+	return s.refreshQueuedRating(s.ratings.SetRating(context.Background(), trackID, rating))
+}
+
+func (s *LibraryService) refreshQueuedRating(rating library.TrackRating, err error) (library.TrackRating, error) {
+	if err != nil {
+		return rating, err
+	}
+
+	s.queue.SetTrackRating(rating.TrackID, rating.Rating, rating.Favorite)
+	return rating, nil
+}
+
+func (s *LibraryService) SetAlbumFavorite(title string, albumArtist string, favorite bool) (library.AlbumRating, error) {
+	return s.ratings.SetAlbumFavorite(context.Background(), title, albumArtist, favorite)
+}
+
+// ToggleAlbumFavorite flips an album's favorite flag.
+func (s *LibraryService) ToggleAlbumFavorite(title string, albumArtist string) (library.AlbumRating, error) {
+	current, err := s.ratings.GetAlbumRating(context.Background(), title, albumArtist)
+	if err != nil {
+		return current, err
+	}
+
+	return s.SetAlbumFavorite(title, albumArtist, !current.Favorite)
+}
+
+// SetAlbumRating stores a 1-5 star album rating; 0 clears it.
+func (s *LibraryService) SetAlbumRating(title string, albumArtist string, rating int) (library.AlbumRating, error) {
+	return s.ratings.SetAlbumRating(context.Background(), title, albumArtist, rating)
+}
+
+func (s *LibraryService) GetAlbumRating(title string, albumArtist string) (library.AlbumRating, error) {
+	return s.ratings.GetAlbumRating(context.Background(), title, albumArtist)
+}
+
+func (s *LibraryService) SetTrackBanned(trackID int64, banned bool) (library.TrackRating, error) {
+	previous, err := s.ratings.Get(context.Background(), trackID)
+	if err != nil {
+		return previous, err
+	}
+
+	rating, err := s.applyTrackBan(trackID, banned)
+	if err != nil {
+		return rating, err
+	}
+
+	if previous.Banned != rating.Banned {
+		label := "Unban track"
+		if rating.Banned {
+			label = "Ban track"
+		}
+		s.journal.Record(undo.Operation{
+			Label: label,
+			Undo: func() error {
+				_, err := s.applyTrackBan(trackID, previous.Banned)
+				return err
+			},
+			Redo: func() error {
+				_, err := s.applyTrackBan(trackID, rating.Banned)
+				return err
+			},
+		})
+	}
+
+	return rating, nil
+}
+
+func (s *LibraryService) applyTrackBan(trackID int64, banned bool) (library.TrackRating, error) {
+	rating, err := s.ratings.SetBanned(context.Background(), trackID, banned)
+	if err != nil {
+		return rating, err
+	}
+
+	s.queue.SetTrackBanned(trackID, rating.Banned)
+	return rating, nil
+}
+
+func (s *LibraryService) ListBannedTrackIDs() ([]int64, error) {
+	return s.ratings.ListBannedTrackIDs(context.Background())
+}
+
+func (s *LibraryService) GetTrackRating(trackID int64) (library.TrackRating, error) {
+	return s.ratings.Get(context.Background(), trackID)
+}
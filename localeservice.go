@@ -0,0 +1,23 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rzxx/ben/internal/i18n"
+)
+
+type LocaleService struct {
+	i18n *i18n.Service
+}
+
+func NewLocaleService(i18nService *i18n.Service) *LocaleService {
+	return &LocaleService{i18n: i18nService}
+}
+
+func (s *LocaleService) GetLocale() i18n.LocaleState {
+	return s.i18n.State()
+}
+
+func (s *LocaleService) SetLocale(locale string) (i18n.LocaleState, error) {
+	return s.i18n.SetLocale(context.Background(), locale)
+}
@@ -0,0 +1,15 @@
+package main
+
+import "github.com/rzxx/ben/internal/lyrics"
+
+type LyricsService struct {
+	lyrics *lyrics.Service
+}
+
+func NewLyricsService(lyricsDomain *lyrics.Service) *LyricsService {
+	return &LyricsService{lyrics: lyricsDomain}
+}
+
+func (s *LyricsService) GetLyrics(trackID int64) (lyrics.Lyrics, error) {
+	return s.lyrics.GetLyrics(trackID)
+}
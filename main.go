@@ -1,18 +1,41 @@
 package main
 
 import (
-	"ben/internal/config"
-	"ben/internal/db"
-	"ben/internal/library"
-	"ben/internal/platform"
-	"ben/internal/player"
-	"ben/internal/queue"
-	"ben/internal/scanner"
-	"ben/internal/stats"
+	"context"
 	"embed"
 	"log"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+
+	"github.com/rzxx/ben/internal/announce"
+	"github.com/rzxx/ben/internal/archive"
+	"github.com/rzxx/ben/internal/backup"
+	"github.com/rzxx/ben/internal/commandpalette"
+	"github.com/rzxx/ben/internal/config"
+	"github.com/rzxx/ben/internal/coverfetch"
+	"github.com/rzxx/ben/internal/db"
+	"github.com/rzxx/ben/internal/devicesync"
+	"github.com/rzxx/ben/internal/eventbus"
+	"github.com/rzxx/ben/internal/i18n"
+	"github.com/rzxx/ben/internal/jobs"
+	"github.com/rzxx/ben/internal/keybindings"
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/lyrics"
+	"github.com/rzxx/ben/internal/metadata"
+	"github.com/rzxx/ben/internal/platform"
+	"github.com/rzxx/ben/internal/player"
+	"github.com/rzxx/ben/internal/playlist"
+	"github.com/rzxx/ben/internal/queue"
+	"github.com/rzxx/ben/internal/remote"
+	"github.com/rzxx/ben/internal/rpc"
+	"github.com/rzxx/ben/internal/scanner"
+	"github.com/rzxx/ben/internal/secrets"
+	"github.com/rzxx/ben/internal/settings"
+	"github.com/rzxx/ben/internal/snapshot"
+	"github.com/rzxx/ben/internal/stats"
+	"github.com/rzxx/ben/internal/tageditor"
+	"github.com/rzxx/ben/internal/undo"
 )
 
 // Wails uses Go's `embed` package to embed the frontend files into the binary.
@@ -24,59 +47,232 @@ import (
 var assets embed.FS
 
 func init() {
-	application.RegisterEvent[scanner.Progress](scanner.EventProgress)
-	application.RegisterEvent[queue.State](queue.EventStateChanged)
-	application.RegisterEvent[player.State](player.EventStateChanged)
+	registerEvent[scanner.Progress](scanner.EventProgress)
+	registerEvent[scanner.ImportResult](scanner.EventImported)
+	registerEvent[scanner.CoversChanged](scanner.EventCoversChanged)
+	registerEvent[scanner.ChecksumMismatches](scanner.EventChecksumMismatch)
+	registerEvent[queue.State](queue.EventStateChanged)
+	registerEvent[player.State](player.EventStateChanged)
+	registerEvent[player.PreviewState](player.EventPreviewChanged)
+	registerEvent[playlist.Change](playlist.EventChanged)
+	registerEvent[MiniPlayerState](EventMiniPlayerChanged)
+	registerEvent[AlbumPalette](EventAlbumPalette)
+	registerEvent[[]keybindings.Binding](keybindings.EventChanged)
+	registerEvent[i18n.LocaleState](i18n.EventLocaleChanged)
+	registerEvent[announce.Announcement](announce.EventAnnouncement)
+	registerEvent[undo.State](undo.EventChanged)
+	registerEvent[[]jobs.Job](jobs.EventChanged)
+	registerEvent[lyrics.Position](lyrics.EventPosition)
+}
+
+// registerEvent registers an event with Wails and adds it to the RPC schema.
+func registerEvent[T any](name string) {
+	application.RegisterEvent[T](name)
+	rpc.RegisterEvent[T](name)
 }
 
 func main() {
+	startup := NewStartupTimer()
+
 	paths, err := config.ResolvePaths("ben")
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	sqliteDB, err := db.Bootstrap(paths.DBPath)
+	if restored, err := backup.ApplyPendingRestore(paths.DBPath); err != nil {
+		log.Fatal(err)
+	} else if restored {
+		log.Printf("restored library database from backup")
+	}
+
+	sqliteDB, err := db.BootstrapPool(paths.DBPath, db.DefaultWritePool())
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer sqliteDB.Close()
 
+	readDB, err := db.OpenReadPool(paths.DBPath, db.DefaultReadPool())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer readDB.Close()
+	startup.Mark("database opened")
+
+	bus := eventbus.New()
+	settingsStore := settings.NewStore(sqliteDB)
 	watchedRoots := library.NewWatchedRootRepository(sqliteDB)
 	browseRepo := library.NewBrowseRepository(sqliteDB)
-	queueDomain := queue.NewService(sqliteDB)
-	playerDomain := player.NewService(sqliteDB, queueDomain)
+	browseRepo.SetReadPool(readDB)
+	trackLinks := library.NewTrackLinkRepository(sqliteDB)
+	trackRatings := library.NewRatingRepository(sqliteDB)
+	trackMoods := library.NewMoodRepository(sqliteDB)
+	trackProvenance := library.NewProvenanceRepository(sqliteDB)
+	trackScripts := library.NewScriptRepository(readDB)
+	trackGenres := library.NewGenreRepository(readDB)
+	duplicateFinder := library.NewDuplicateFinder(sqliteDB)
+	albumMixes := library.NewAlbumMixRepository(sqliteDB)
+	volumeOffsets := library.NewVolumeOffsetRepository(sqliteDB)
+	trackTrims := library.NewTrackTrimRepository(sqliteDB)
+	edgeSilences := library.NewEdgeSilenceRepository(sqliteDB)
+	audiobooks := library.NewAudiobookRepository(sqliteDB)
+	queueDomain := queue.NewDeferredService(sqliteDB, bus)
+	if bannedTrackIDs, banErr := trackRatings.ListBannedTrackIDs(context.Background()); banErr != nil {
+		log.Printf("load banned tracks: %v", banErr)
+	} else {
+		queueDomain.SetBannedTracks(bannedTrackIDs)
+	}
+	playerDomain := player.NewService(sqliteDB, queueDomain, bus)
 	defer playerDomain.Close()
-	statsDomain := stats.NewService(sqliteDB)
-	scannerDomain := scanner.NewService(sqliteDB, watchedRoots, paths.CoverCacheDir)
-	settingsService := NewSettingsService(watchedRoots, scannerDomain)
-	libraryService := NewLibraryService(browseRepo)
+	secretStore := secrets.NewStore(secrets.Service)
+	remoteCredentials := remote.NewCredentials(secretStore)
+	if err := remoteCredentials.MigrateLegacyPasswords(context.Background(), watchedRoots); err != nil {
+		log.Printf("move remote passwords to the keychain: %v", err)
+	}
+	remotePlayback := remote.NewPlaybackResolver(watchedRoots, remoteCredentials)
+	defer remotePlayback.Close()
+	archivePlayback := archive.NewCache(paths.ArchiveCacheDir)
+	playerDomain.SetPathResolver(func(path string) (player.PlaybackSource, error) {
+		resolved, headers, err := remotePlayback.Resolve(path)
+		if err != nil {
+			return player.PlaybackSource{}, err
+		}
+		return player.PlaybackSource{Path: archivePlayback.Resolve(resolved), Headers: headers}, nil
+	})
+	playerDomain.SetContinuousMixResolver(func(trackID int64) bool {
+		continuousMix, err := albumMixes.IsContinuousMixTrack(context.Background(), trackID)
+		return err == nil && continuousMix
+	})
+	playerDomain.SetResumeStore(audiobookResumeStore{audiobooks: audiobooks})
+	playerDomain.SetVolumeOffsetResolver(func(trackID int64) float64 {
+		offset, err := volumeOffsets.GetTrackVolumeOffset(context.Background(), trackID)
+		if err != nil {
+			return 0
+		}
+		return offset.EffectiveDB
+	})
+	playerDomain.SetTrackTrimResolver(func(trackID int64) player.TrackTrim {
+		trim, err := trackTrims.GetTrackTrims(context.Background(), trackID)
+		if err != nil {
+			return player.TrackTrim{}
+		}
+		endMS := 0
+		if trim.EndMS != nil {
+			endMS = *trim.EndMS
+		}
+		return player.TrackTrim{StartMS: trim.StartMS, EndMS: endMS}
+	})
+	playerDomain.SetEdgeSilenceResolver(func(trackID int64) player.EdgeSilence {
+		silence, ok, err := edgeSilences.GetTrackEdgeSilence(context.Background(), trackID)
+		if err != nil || !ok {
+			return player.EdgeSilence{}
+		}
+		return player.EdgeSilence{LeadMS: silence.LeadMS, TailMS: silence.TailMS, DurationMS: silence.DurationMS}
+	})
+	statsDomain := stats.NewService(sqliteDB, bus)
+	statsDomain.SetReadPool(readDB)
+	statsDomain.SetQueue(queueDomain)
+	lyricsDomain := lyrics.NewService(sqliteDB, bus)
+	metadataDomain := metadata.NewService(sqliteDB)
+	scannerDomain := scanner.NewService(sqliteDB, watchedRoots, paths.CoverCacheDir, bus)
+	scannerDomain.SetRemoteCredentials(remoteCredentials)
+	scannerDomain.SetGenreInference(metadataDomain)
+	tagEditorDomain := tageditor.NewService(sqliteDB)
+	coverFetchDomain := coverfetch.NewService(sqliteDB)
+	tagEditorDomain.SetRescanner(scannerDomain.NotifyPathsChanged)
+	autoImporter := scanner.NewAutoImporter(scannerDomain, bus)
+	playlistDomain := playlist.NewService(sqliteDB, bus)
+	playlistSync := playlist.NewFolderSync(playlistDomain, settingsStore)
+	backupDomain := backup.NewService(sqliteDB, settingsStore, secretStore, paths.DBPath)
+	deviceSyncDomain := devicesync.NewService(sqliteDB, settingsStore)
+	keybindingsDomain := keybindings.NewService(settingsStore, bus)
+	announceDomain := announce.NewService(settingsStore, bus)
+	commandPaletteDomain := commandpalette.NewService(sqliteDB)
+	undoJournal := undo.NewJournal(undo.DefaultLimit, bus)
+	librarySnapshots := snapshot.NewService(sqliteDB)
+	trackNotifier := platform.NewNotifier(settingsStore, bus)
+	i18nDomain := i18n.NewService(settingsStore, bus)
+	i18nDomain.Load(context.Background(), i18n.SystemLocale())
+	i18nDomain.OnChange(func(localizer *i18n.Localizer) {
+		db.SetLocaleCompare(localizer.Compare)
+		scannerDomain.SetTranslator(localizer.T)
+		statsDomain.SetLocalizer(localizer)
+		announceDomain.SetLocalizer(localizer)
+		trackNotifier.SetLocalizer(localizer)
+	})
+	settingsService := NewSettingsService(watchedRoots, remoteCredentials, scannerDomain)
+	audiobookService := NewAudiobookService(audiobooks, scannerDomain)
+	libraryService := NewLibraryService(browseRepo, trackLinks, trackRatings, trackMoods, trackProvenance, trackScripts, trackGenres, duplicateFinder, queueDomain, undoJournal, librarySnapshots)
 	coverService := NewCoverService(sqliteDB, paths.CoverCacheDir)
-	themeService := NewThemeService(paths.CoverCacheDir)
-	queueService := NewQueueService(queueDomain)
-	playerService := NewPlayerService(playerDomain)
-	statsService := NewStatsService(statsDomain)
-	scannerService := NewScannerService(scannerDomain)
+	themeService := NewThemeService(browseRepo, paths.CoverCacheDir)
+	queueService := NewQueueService(queueDomain, undoJournal)
+	playerService := NewPlayerService(playerDomain, albumMixes, volumeOffsets, trackTrims)
+	jobManager := jobs.NewManager(bus)
+	statsService := NewStatsService(statsDomain, playerDomain, jobManager)
+	scannerService := NewScannerService(scannerDomain, autoImporter, jobManager, librarySnapshots)
+	playlistService := NewPlaylistService(playlistDomain, playlistSync, queueDomain, undoJournal)
+	playlistPlayback := NewPlaylistPlayback(playlistDomain, queueDomain, playerDomain, settingsStore, bus)
+	backupService := NewBackupService(backupDomain, jobManager)
+	deviceSyncService := NewDeviceSyncService(deviceSyncDomain, jobManager, librarySnapshots)
+	miniPlayerService := NewMiniPlayerService(settingsStore)
+	keybindingsService := NewKeybindingsService(keybindingsDomain)
+	localeService := NewLocaleService(i18nDomain)
+	accessibilityService := NewAccessibilityService(announceDomain)
+	commandPaletteService := NewCommandPaletteService(commandPaletteDomain)
+	notificationService := NewNotificationService(trackNotifier)
+	undoService := NewUndoService(undoJournal)
+	snapshotService := NewSnapshotService(librarySnapshots)
+	jobsService := NewJobsService(jobManager, scannerDomain, bus)
+	lyricsService := NewLyricsService(lyricsDomain)
+	metadataService := NewMetadataService(metadataDomain, scannerDomain)
+	tagEditorService := NewTagEditorService(tagEditorDomain, undoJournal, librarySnapshots)
+	coverFetchService := NewCoverFetchService(coverFetchDomain, scannerDomain)
+	coverWarmer := NewCoverWarmer(sqliteDB, themeService, playerDomain, jobManager, bus)
+	NewEdgeSilenceAnalyzer(edgeSilences, playerDomain, jobManager, bus)
+	statusService := NewStatusService(sqliteDB, paths.CoverCacheDir, playerDomain, scannerDomain, backupDomain)
 	bootstrapService := NewBootstrapService(
 		browseRepo,
 		queueDomain,
 		playerDomain,
 		scannerDomain,
+		startup,
 	)
+	startup.Mark("services created")
+
+	services := []application.Service{
+		application.NewService(settingsService),
+		application.NewService(libraryService),
+		application.NewService(bootstrapService),
+		application.NewServiceWithOptions(coverService, application.ServiceOptions{Route: "/covers"}),
+		application.NewService(themeService),
+		application.NewService(queueService),
+		application.NewService(playerService),
+		application.NewService(statsService),
+		application.NewService(scannerService),
+		application.NewService(playlistService),
+		application.NewService(backupService),
+		application.NewService(deviceSyncService),
+		application.NewService(miniPlayerService),
+		application.NewService(keybindingsService),
+		application.NewService(localeService),
+		application.NewService(accessibilityService),
+		application.NewService(commandPaletteService),
+		application.NewService(undoService),
+		application.NewService(snapshotService),
+		application.NewService(audiobookService),
+		application.NewService(statusService),
+		application.NewService(jobsService),
+		application.NewService(lyricsService),
+		application.NewService(metadataService),
+		application.NewService(tagEditorService),
+		application.NewService(coverFetchService),
+		application.NewService(notificationService),
+	}
+	services = append(services, application.NewServiceWithOptions(NewRPCService(services), application.ServiceOptions{Route: "/rpc"}))
 
 	app := application.New(application.Options{
 		Name:        "Ben",
 		Description: "Desktop music player",
-		Services: []application.Service{
-			application.NewService(settingsService),
-			application.NewService(libraryService),
-			application.NewService(bootstrapService),
-			application.NewServiceWithOptions(coverService, application.ServiceOptions{Route: "/covers"}),
-			application.NewService(themeService),
-			application.NewService(queueService),
-			application.NewService(playerService),
-			application.NewService(statsService),
-			application.NewService(scannerService),
-		},
+		Services:    services,
 		Assets: application.AssetOptions{
 			Handler: application.AssetFileServerFS(assets),
 		},
@@ -85,7 +281,23 @@ func main() {
 		},
 	})
 
-	platformService := platform.NewService(app, playerDomain)
+	shutdown := NewShutdownCoordinator(shutdownTimeout)
+	shutdown.Add("scanner", func(ctx context.Context) error {
+		if err := autoImporter.Stop(); err != nil {
+			log.Printf("stop auto-import folder: %v", err)
+		}
+		return scannerDomain.Shutdown(ctx)
+	})
+	shutdown.Add("stats", func(context.Context) error {
+		statsDomain.Flush()
+		return nil
+	})
+	shutdown.Add("player", func(context.Context) error {
+		return playerDomain.Shutdown()
+	})
+	app.OnShutdown(shutdown.Run)
+
+	platformService := platform.NewService(app, playerDomain, bus)
 	if err := platformService.Start(); err != nil {
 		log.Printf("platform integration disabled: %v", err)
 	}
@@ -95,29 +307,50 @@ func main() {
 		}
 	}()
 	platformService.HandlePlayerState(playerDomain.GetState())
+	trackNotifier.SetApp(app)
+	trackNotifier.HandlePlayerState(playerDomain.GetState())
 
-	scannerDomain.SetEmitter(func(eventName string, payload any) {
-		app.Event.Emit(eventName, payload)
-	})
-	queueDomain.SetEmitter(func(eventName string, payload any) {
-		app.Event.Emit(eventName, payload)
-	})
-	playerDomain.SetEmitter(func(eventName string, payload any) {
+	bus.SubscribeAll(func(eventName string, payload any) {
 		app.Event.Emit(eventName, payload)
-		if eventName == player.EventStateChanged {
-			if state, ok := payload.(player.State); ok {
-				platformService.HandlePlayerState(state)
-				statsDomain.HandlePlayerState(state)
-			}
-		}
 	})
+	if err := coverWarmer.Start(); err != nil {
+		log.Printf("cover warm-up disabled: %v", err)
+	}
+	playlistPlayback.Start()
 
 	if err := scannerDomain.StartWatching(); err != nil {
 		log.Printf("scanner watcher disabled: %v", err)
 	}
 	defer scannerDomain.StopWatching()
+	if err := scannerDomain.StartScheduler(); err != nil {
+		log.Printf("scheduled scans disabled: %v", err)
+	}
+	defer scannerDomain.StopScheduler()
+
+	if err := autoImporter.Start(); err != nil {
+		log.Printf("auto-import folder disabled: %v", err)
+	}
+	defer autoImporter.Stop()
+
+	if err := playlistSync.Start(); err != nil {
+		log.Printf("playlist folder sync disabled: %v", err)
+	}
+	defer playlistSync.Stop()
+
+	backupDomain.Start()
+	defer backupDomain.Stop()
+
+	deviceSyncDomain.Start()
+	defer deviceSyncDomain.Stop()
 
-	app.Window.NewWithOptions(application.WebviewWindowOptions{
+	// The saved queue and stats compaction wait for the first paint, so a
+	// large library never holds the window back.
+	startup.Defer("queue hydrated", func() {
+		playerDomain.HydrateQueue()
+	})
+	startup.Defer("stats compacted", statsDomain.CompactIfDue)
+
+	mainWindow := app.Window.NewWithOptions(application.WebviewWindowOptions{
 		Title:     "Ben",
 		Frameless: true,
 		MinWidth:  1080,
@@ -133,6 +366,11 @@ func main() {
 		BackgroundColour: application.NewRGB(10, 10, 10),
 		URL:              "/",
 	})
+	mainWindow.OnWindowEvent(events.Common.WindowRuntimeReady, func(*application.WindowEvent) {
+		startup.WindowReady()
+	})
+	startup.Mark("window created")
+	startup.Start()
 
 	err = app.Run()
 	if err != nil {
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rzxx/ben/internal/metadata"
+	"github.com/rzxx/ben/internal/scanner"
+)
+
+type MetadataService struct {
+	metadata *metadata.Service
+	scanner  *scanner.Service
+}
+
+func NewMetadataService(metadataDomain *metadata.Service, scanService *scanner.Service) *MetadataService {
+	return &MetadataService{metadata: metadataDomain, scanner: scanService}
+}
+
+// GetOnlineLookups reports whether the user opted in to online metadata
+// lookups.
+func (s *MetadataService) GetOnlineLookups() bool {
+	return s.metadata.OnlineLookupsEnabled(context.Background())
+}
+
+func (s *MetadataService) SetOnlineLookups(enabled bool) (bool, error) {
+	return s.metadata.SetOnlineLookupsEnabled(context.Background(), enabled)
+}
+
+func (s *MetadataService) HasAcoustIDKey() bool {
+	return s.metadata.HasAcoustIDKey(context.Background())
+}
+
+// SetAcoustIDKey stores the key for fingerprint lookups; an empty key
+// removes it.
+func (s *MetadataService) SetAcoustIDKey(key string) error {
+	return s.metadata.SetAcoustIDKey(context.Background(), key)
+}
+
+// LookupTrack searches MusicBrainz for candidates matching a track's tags.
+func (s *MetadataService) LookupTrack(trackID int64) (metadata.TrackLookup, error) {
+	return s.metadata.LookupTrack(context.Background(), trackID)
+}
+
+// LookupTrackByFingerprint identifies a track by its audio through AcoustID.
+func (s *MetadataService) LookupTrackByFingerprint(trackID int64) (metadata.TrackLookup, error) {
+	return s.metadata.LookupTrackByFingerprint(context.Background(), trackID)
+}
+
+// Enrich fills the empty year, album artist and genre of tracks from a
+// chosen candidate and refreshes albums and artists.
+func (s *MetadataService) Enrich(trackIDs []int64, candidate metadata.Candidate) (metadata.EnrichResult, error) {
+	ctx := context.Background()
+	result, err := s.metadata.Enrich(ctx, trackIDs, candidate)
+	if err != nil {
+		return result, err
+	}
+	if result.Updated > 0 {
+		if err := s.scanner.RefreshDerivedLibrary(ctx); err != nil {
+			return result, fmt.Errorf("refresh albums after enrich: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// RefreshReleaseTrackLists fetches the track lists of the MusicBrainz
+// releases in the library, which GetIncompleteAlbums checks albums against.
+func (s *MetadataService) RefreshReleaseTrackLists() (metadata.ReleaseTrackListResult, error) {
+	return s.metadata.RefreshReleaseTrackLists(context.Background())
+}
+
+// InferGenres votes probable genres for untagged tracks from their album
+// siblings and the artist's other tracks. They stay apart from tagged
+// genres until confirmed. Scans that change the library run it too.
+func (s *MetadataService) InferGenres() (metadata.GenreInferenceResult, error) {
+	return s.metadata.InferGenres(context.Background())
+}
+
+func (s *MetadataService) ListInferredGenres() ([]metadata.InferredGenre, error) {
+	return s.metadata.ListInferredGenres(context.Background())
+}
+
+// ConfirmInferredGenres applies the inferred genres of tracks as their
+// genre.
+func (s *MetadataService) ConfirmInferredGenres(trackIDs []int64) (int, error) {
+	return s.metadata.ConfirmInferredGenres(context.Background(), trackIDs)
+}
+
+// DismissInferredGenres rejects the inferred genres of tracks, so they are
+// not inferred again.
+func (s *MetadataService) DismissInferredGenres(trackIDs []int64) (int, error) {
+	return s.metadata.DismissInferredGenres(context.Background(), trackIDs)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+
+	"github.com/rzxx/ben/internal/settings"
+)
+
+const EventMiniPlayerChanged = "miniplayer:changed"
+
+const miniPlayerWindowName = "mini-player"
+
+const miniPlayerGeometryKey = "window.mini_player"
+
+const (
+	miniPlayerDefaultWidth  = 360
+	miniPlayerDefaultHeight = 132
+)
+
+// MiniPlayerState reports whether the mini player window is open and the
+// geometry it reopens with. The window loads the regular frontend with
+// ?window=mini and follows the shared player and queue events.
+type MiniPlayerState struct {
+	Open   bool `json:"open"`
+	X      int  `json:"x"`
+	Y      int  `json:"y"`
+	Width  int  `json:"width"`
+	Height int  `json:"height"`
+}
+
+type miniPlayerGeometry struct {
+	X      int  `json:"x"`
+	Y      int  `json:"y"`
+	Width  int  `json:"width"`
+	Height int  `json:"height"`
+	Placed bool `json:"placed"`
+}
+
+type MiniPlayerService struct {
+	mu     sync.Mutex
+	store  *settings.Store
+	window *application.WebviewWindow
+}
+
+func NewMiniPlayerService(store *settings.Store) *MiniPlayerService {
+	return &MiniPlayerService{store: store}
+}
+
+func (s *MiniPlayerService) GetMiniPlayerState() MiniPlayerState {
+	s.mu.Lock()
+	open := s.window != nil
+	s.mu.Unlock()
+
+	return miniPlayerStateFromGeometry(open, s.loadGeometry())
+}
+
+// ToggleMiniPlayer opens the compact always-on-top player, or closes it and
+// remembers where it was.
+func (s *MiniPlayerService) ToggleMiniPlayer() (MiniPlayerState, error) {
+	s.mu.Lock()
+	window := s.window
+	s.mu.Unlock()
+
+	if window != nil {
+		s.saveGeometry(window)
+		window.Close()
+		return s.GetMiniPlayerState(), nil
+	}
+
+	app := application.Get()
+	if app == nil {
+		return MiniPlayerState{}, errors.New("application is not running")
+	}
+
+	geometry := s.loadGeometry()
+	options := application.WebviewWindowOptions{
+		Name:             miniPlayerWindowName,
+		Title:            "Ben Mini Player",
+		Width:            geometry.Width,
+		Height:           geometry.Height,
+		MinWidth:         280,
+		MinHeight:        96,
+		AlwaysOnTop:      true,
+		Frameless:        true,
+		BackgroundColour: application.NewRGB(10, 10, 10),
+		URL:              "/?window=mini",
+	}
+	if geometry.Placed {
+		options.InitialPosition = application.WindowXY
+		options.X = geometry.X
+		options.Y = geometry.Y
+	}
+
+	created := app.Window.NewWithOptions(options)
+	created.OnWindowEvent(events.Common.WindowClosing, func(*application.WindowEvent) {
+		s.saveGeometry(created)
+
+		s.mu.Lock()
+		if s.window == created {
+			s.window = nil
+		}
+		s.mu.Unlock()
+
+		s.emitChanged()
+	})
+
+	s.mu.Lock()
+	s.window = created
+	s.mu.Unlock()
+
+	created.Show()
+	s.emitChanged()
+
+	return s.GetMiniPlayerState(), nil
+}
+
+func (s *MiniPlayerService) loadGeometry() miniPlayerGeometry {
+	geometry := miniPlayerGeometry{Width: miniPlayerDefaultWidth, Height: miniPlayerDefaultHeight}
+
+	raw := s.store.GetString(context.Background(), miniPlayerGeometryKey, "")
+	if raw == "" {
+		return geometry
+	}
+
+	var stored miniPlayerGeometry
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+		return geometry
+	}
+	if stored.Width > 0 && stored.Height > 0 {
+		geometry.Width = stored.Width
+		geometry.Height = stored.Height
+	}
+	if stored.Placed {
+		geometry.X = stored.X
+		geometry.Y = stored.Y
+		geometry.Placed = true
+	}
+
+	return geometry
+}
+
+func (s *MiniPlayerService) saveGeometry(window *application.WebviewWindow) {
+	x, y := window.Position()
+	width, height := window.Size()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	encoded, err := json.Marshal(miniPlayerGeometry{X: x, Y: y, Width: width, Height: height, Placed: true})
+	if err != nil {
+		return
+	}
+
+	_ = s.store.Set(context.Background(), miniPlayerGeometryKey, string(encoded))
+}
+
+func miniPlayerStateFromGeometry(open bool, geometry miniPlayerGeometry) MiniPlayerState {
+	return MiniPlayerState{
+		Open:   open,
+		X:      geometry.X,
+		Y:      geometry.Y,
+		Width:  geometry.Width,
+		Height: geometry.Height,
+	}
+}
+
+func (s *MiniPlayerService) emitChanged() {
+	if app := application.Get(); app != nil {
+		app.Event.Emit(EventMiniPlayerChanged, s.GetMiniPlayerState())
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import "github.com/rzxx/ben/internal/platform"
+
+type NotificationService struct {
+	notifier *platform.Notifier
+}
+
+func NewNotificationService(notifier *platform.Notifier) *NotificationService {
+	return &NotificationService{notifier: notifier}
+}
+
+// GetTrackNotifications returns when a native notification is shown for a
+// new track.
+func (s *NotificationService) GetTrackNotifications() platform.TrackNotifications {
+	return s.notifier.GetConfig()
+}
+
+func (s *NotificationService) SetTrackNotifications(config platform.TrackNotifications) (platform.TrackNotifications, error) {
+	return s.notifier.SetConfig(config)
+}
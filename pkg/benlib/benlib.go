@@ -0,0 +1,106 @@
+package benlib
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rzxx/ben/internal/db"
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/queue"
+	"github.com/rzxx/ben/internal/stats"
+)
+
+// appSlug names the app's directory in the user config directory.
+const appSlug = "ben"
+
+var ErrLibraryNotFound = errors.New("library database not found")
+
+// ErrSchemaOutdated is returned for a library the app has not migrated to
+// the schema of this package yet. Starting the current app once upgrades it.
+var ErrSchemaOutdated = errors.New("library database schema is out of date")
+
+// Library is an open, read-only Ben library.
+type Library struct {
+	db     *sql.DB
+	browse *library.BrowseRepository
+	queue  *queue.Service
+	stats  *stats.Service
+}
+
+// DefaultPath returns the path of the library database of the current user.
+func DefaultPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user config dir: %w", err)
+	}
+
+	return filepath.Join(configDir, appSlug, "library.db"), nil
+}
+
+// OpenDefault opens the library database of the current user.
+func OpenDefault() (*Library, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+
+	return Open(path)
+}
+
+// Open opens the library database at path read-only. It never creates or
+// migrates a database.
+func Open(path string) (*Library, error) {
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrLibraryNotFound, path)
+		}
+		return nil, fmt.Errorf("stat library database: %w", err)
+	}
+
+	database, err := db.OpenReadPool(path, db.DefaultReadPool())
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := db.PendingMigrations(database)
+	if err != nil {
+		database.Close()
+		return nil, fmt.Errorf("%w: %v", ErrSchemaOutdated, err)
+	}
+	if len(pending) > 0 {
+		database.Close()
+		return nil, fmt.Errorf("%w: %d migrations pending", ErrSchemaOutdated, len(pending))
+	}
+
+	statsService := stats.NewService(database, nil)
+	statsService.DisableCompaction()
+
+	return &Library{
+		db:     database,
+		browse: library.NewBrowseRepository(database),
+		queue:  queue.NewService(database, nil),
+		stats:  statsService,
+	}, nil
+}
+
+// Library returns the reader for artists, albums and tracks.
+func (l *Library) Library() LibraryReader {
+	return libraryReader{browse: l.browse}
+}
+
+// Queue returns the play queue as it was when Open was called.
+func (l *Library) Queue() QueueReader {
+	return queueReader{queue: l.queue}
+}
+
+// Stats returns the reader for listening statistics.
+func (l *Library) Stats() StatsReader {
+	return statsReader{stats: l.stats}
+}
+
+func (l *Library) Close() error {
+	return l.db.Close()
+}
@@ -0,0 +1,67 @@
+package benlib
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/rzxx/ben/internal/db"
+)
+
+func TestOpenReadsMigratedLibrary(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "library.db")
+	database, err := db.Bootstrap(path)
+	if err != nil {
+		t.Fatalf("bootstrap library: %v", err)
+	}
+	if _, err := database.Exec("INSERT INTO files(id, path) VALUES (1, '/music/song.flac')"); err != nil {
+		t.Fatalf("insert file: %v", err)
+	}
+	if _, err := database.Exec("INSERT INTO tracks(file_id, title, artist, album, album_artist) VALUES (1, 'Song', 'Band', 'Record', 'Band')"); err != nil {
+		t.Fatalf("insert track: %v", err)
+	}
+	database.Close()
+
+	lib, err := Open(path)
+	if err != nil {
+		t.Fatalf("open library: %v", err)
+	}
+	defer lib.Close()
+
+	tracks, err := lib.Library().ListTracks(context.Background(), "", "", "", 10, 0, false)
+	if err != nil {
+		t.Fatalf("list tracks: %v", err)
+	}
+	if len(tracks.Items) != 1 || tracks.Items[0].Title != "Song" {
+		t.Fatalf("expected the stored track, got %+v", tracks.Items)
+	}
+	if state := lib.Queue().GetState(); len(state.Entries) != 0 {
+		t.Fatalf("expected an empty queue, got %d entries", len(state.Entries))
+	}
+	if _, err := lib.Stats().GetOverview(5); err != nil {
+		t.Fatalf("read overview: %v", err)
+	}
+}
+
+func TestOpenRejectsMissingAndOutdatedLibraries(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if _, err := Open(filepath.Join(dir, "missing.db")); !errors.Is(err, ErrLibraryNotFound) {
+		t.Fatalf("expected ErrLibraryNotFound, got %v", err)
+	}
+
+	path := filepath.Join(dir, "empty.db")
+	database, err := db.Open(path, db.DefaultWritePool())
+	if err != nil {
+		t.Fatalf("create empty database: %v", err)
+	}
+	database.Close()
+
+	if _, err := Open(path); !errors.Is(err, ErrSchemaOutdated) {
+		t.Fatalf("expected ErrSchemaOutdated, got %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+// Package benlib is the stable Go API for reading a Ben library from other
+// programs, such as scripts or alternative frontends.
+//
+// Open a library with Open, or OpenDefault for the library of the current
+// user, then read it through the Library, Queue and Stats readers:
+//
+//	lib, err := benlib.OpenDefault()
+//	if err != nil {
+//		return err
+//	}
+//	defer lib.Close()
+//
+//	albums, err := lib.Library().ListAlbums(ctx, "", "", 50, 0, true)
+//
+// Access is read-only: the database is opened with writes disabled, so it
+// is safe to read while the app is running. The exported types are copies of
+// the app's own, limited to fields that are stable; fields may be added in
+// later versions, but existing fields and the reader interfaces keep their
+// meaning.
+package benlib
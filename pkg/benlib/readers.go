@@ -0,0 +1,230 @@
+package benlib
+
+import (
+	"context"
+
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/queue"
+	"github.com/rzxx/ben/internal/stats"
+)
+
+// The readers copy the app's internal types into the ones of this package,
+// so fields the app adds later stay out of the public API until they are
+// added here on purpose.
+
+type libraryReader struct {
+	browse *library.BrowseRepository
+}
+
+func (r libraryReader) ListArtists(ctx context.Context, search string, limit int, offset int) (ArtistsPage, error) {
+	page, err := r.browse.ListArtists(ctx, search, limit, offset)
+	if err != nil {
+		return ArtistsPage{}, err
+	}
+
+	items := make([]Artist, 0, len(page.Items))
+	for _, item := range page.Items {
+		items = append(items, artistFromLibrary(item))
+	}
+	return ArtistsPage{Items: items, Page: PageInfo(page.Page)}, nil
+}
+
+func (r libraryReader) ListAlbums(ctx context.Context, search string, artist string, limit int, offset int, withStats bool) (AlbumsPage, error) {
+	page, err := r.browse.ListAlbums(ctx, search, artist, limit, offset, withStats)
+	if err != nil {
+		return AlbumsPage{}, err
+	}
+
+	return AlbumsPage{Items: albumsFromLibrary(page.Items), Page: PageInfo(page.Page)}, nil
+}
+
+func (r libraryReader) ListTracks(ctx context.Context, search string, artist string, album string, limit int, offset int, withStats bool) (TracksPage, error) {
+	page, err := r.browse.ListTracks(ctx, search, artist, album, limit, offset, withStats)
+	if err != nil {
+		return TracksPage{}, err
+	}
+
+	return TracksPage{Items: tracksFromLibrary(page.Items), Page: PageInfo(page.Page)}, nil
+}
+
+func (r libraryReader) GetArtistDetail(ctx context.Context, name string, limit int, offset int) (ArtistDetail, error) {
+	detail, err := r.browse.GetArtistDetail(ctx, name, limit, offset)
+	if err != nil {
+		return ArtistDetail{}, err
+	}
+
+	return ArtistDetail{
+		Artist: Artist{
+			Name:       detail.Name,
+			TrackCount: detail.TrackCount,
+			AlbumCount: detail.AlbumCount,
+		},
+		Albums: albumsFromLibrary(detail.Albums),
+		Page:   PageInfo(detail.Page),
+	}, nil
+}
+
+func (r libraryReader) GetAlbumDetail(ctx context.Context, title string, albumArtist string, limit int, offset int) (AlbumDetail, error) {
+	detail, err := r.browse.GetAlbumDetail(ctx, title, albumArtist, limit, offset)
+	if err != nil {
+		return AlbumDetail{}, err
+	}
+
+	return AlbumDetail{
+		Album: Album{
+			Title:       detail.Title,
+			AlbumArtist: detail.AlbumArtist,
+			Year:        detail.Year,
+			TrackCount:  detail.TrackCount,
+			CoverPath:   detail.CoverPath,
+			Rating:      detail.Rating,
+			Favorite:    detail.Favorite,
+		},
+		Tracks: tracksFromLibrary(detail.Tracks),
+		Page:   PageInfo(detail.Page),
+	}, nil
+}
+
+type queueReader struct {
+	queue *queue.Service
+}
+
+func (r queueReader) GetState() QueueState {
+	state := r.queue.GetState()
+
+	sources := make([]QueueSource, 0, len(state.Sources))
+	for _, source := range state.Sources {
+		sources = append(sources, QueueSource(source))
+	}
+
+	return QueueState{
+		Entries:      tracksFromLibrary(state.Entries),
+		Sources:      sources,
+		CurrentIndex: state.CurrentIndex,
+		RepeatMode:   state.RepeatMode,
+		Shuffle:      state.Shuffle,
+		UpdatedAt:    state.UpdatedAt,
+	}
+}
+
+type statsReader struct {
+	stats *stats.Service
+}
+
+func (r statsReader) GetOverview(limit int) (Overview, error) {
+	overview, err := r.stats.GetOverview(limit)
+	if err != nil {
+		return Overview{}, err
+	}
+
+	return Overview{
+		TotalPlayedMS: overview.TotalPlayedMS,
+		TracksPlayed:  overview.TracksPlayed,
+		CompleteCount: overview.CompleteCount,
+		SkipCount:     overview.SkipCount,
+		PartialCount:  overview.PartialCount,
+		TopTracks:     trackStatsFromStats(overview.TopTracks),
+		TopArtists:    artistStatsFromStats(overview.TopArtists),
+	}, nil
+}
+
+func (r statsReader) GetDashboard(rangeKey string, limit int) (Dashboard, error) {
+	dashboard, err := r.stats.GetDashboard(rangeKey, limit)
+	if err != nil {
+		return Dashboard{}, err
+	}
+
+	albums := make([]AlbumStat, 0, len(dashboard.TopAlbums))
+	for _, album := range dashboard.TopAlbums {
+		albums = append(albums, AlbumStat(album))
+	}
+
+	return Dashboard{
+		Range:         dashboard.Range,
+		WindowStart:   dashboard.WindowStart,
+		GeneratedAt:   dashboard.GeneratedAt,
+		TotalPlayedMS: dashboard.Summary.TotalPlayedMS,
+		TotalPlays:    dashboard.Summary.TotalPlays,
+		TracksPlayed:  dashboard.Summary.TracksPlayed,
+		ArtistsPlayed: dashboard.Summary.ArtistsPlayed,
+		AlbumsPlayed:  dashboard.Summary.AlbumsPlayed,
+		TopTracks:     trackStatsFromStats(dashboard.TopTracks),
+		TopArtists:    artistStatsFromStats(dashboard.TopArtists),
+		TopAlbums:     albums,
+	}, nil
+}
+
+func artistFromLibrary(artist library.ArtistSummary) Artist {
+	return Artist{
+		Name:       artist.Name,
+		TrackCount: artist.TrackCount,
+		AlbumCount: artist.AlbumCount,
+	}
+}
+
+func albumsFromLibrary(albums []library.AlbumSummary) []Album {
+	items := make([]Album, 0, len(albums))
+	for _, album := range albums {
+		items = append(items, Album{
+			Title:        album.Title,
+			AlbumArtist:  album.AlbumArtist,
+			Year:         album.Year,
+			TrackCount:   album.TrackCount,
+			CoverPath:    album.CoverPath,
+			PlayCount:    album.PlayCount,
+			LastPlayedAt: album.LastPlayedAt,
+			Rating:       album.Rating,
+			Favorite:     album.Favorite,
+		})
+	}
+	return items
+}
+
+func tracksFromLibrary(tracks []library.TrackSummary) []Track {
+	items := make([]Track, 0, len(tracks))
+	for _, track := range tracks {
+		items = append(items, Track{
+			ID:           track.ID,
+			Title:        track.Title,
+			Artist:       track.Artist,
+			Album:        track.Album,
+			AlbumArtist:  track.AlbumArtist,
+			DiscNo:       track.DiscNo,
+			TrackNo:      track.TrackNo,
+			DurationMS:   track.DurationMS,
+			Path:         track.Path,
+			CoverPath:    track.CoverPath,
+			PlayCount:    track.PlayCount,
+			LastPlayedAt: track.LastPlayedAt,
+			Rating:       track.Rating,
+			Favorite:     track.Favorite,
+		})
+	}
+	return items
+}
+
+func trackStatsFromStats(tracks []stats.TrackStat) []TrackStat {
+	items := make([]TrackStat, 0, len(tracks))
+	for _, track := range tracks {
+		items = append(items, TrackStat{
+			TrackID:       track.TrackID,
+			Title:         track.Title,
+			Artist:        track.Artist,
+			Album:         track.Album,
+			CoverPath:     track.CoverPath,
+			PlayedMS:      track.PlayedMS,
+			CompleteCount: track.CompleteCount,
+			SkipCount:     track.SkipCount,
+			PartialCount:  track.PartialCount,
+		})
+	}
+	return items
+}
+
+func artistStatsFromStats(artists []stats.ArtistStat) []ArtistStat {
+	items := make([]ArtistStat, 0, len(artists))
+	for _, artist := range artists {
+		items = append(items, ArtistStat(artist))
+	}
+	return items
+}
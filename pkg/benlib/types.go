@@ -0,0 +1,190 @@
+package benlib
+
+import (
+	"context"
+
+	"github.com/rzxx/ben/internal/stats"
+)
+
+// PageInfo describes one page of a listing. Total counts every match, not
+// only the ones on the page.
+type PageInfo struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
+type Artist struct {
+	Name       string `json:"name"`
+	TrackCount int    `json:"trackCount"`
+	AlbumCount int    `json:"albumCount"`
+}
+
+// Album is an album of the library. PlayCount and LastPlayedAt are only set
+// when a listing was asked for stats; CoverPath is a file in the app's
+// cover cache.
+type Album struct {
+	Title        string  `json:"title"`
+	AlbumArtist  string  `json:"albumArtist"`
+	Year         *int    `json:"year,omitempty"`
+	TrackCount   int     `json:"trackCount"`
+	CoverPath    *string `json:"coverPath,omitempty"`
+	PlayCount    *int    `json:"playCount,omitempty"`
+	LastPlayedAt *string `json:"lastPlayedAt,omitempty"`
+	Rating       *int    `json:"rating,omitempty"`
+	Favorite     bool    `json:"favorite,omitempty"`
+}
+
+// Track is a track of the library. Path is the audio file, or a URL for
+// tracks of remote roots.
+type Track struct {
+	ID           int64   `json:"id"`
+	Title        string  `json:"title"`
+	Artist       string  `json:"artist"`
+	Album        string  `json:"album"`
+	AlbumArtist  string  `json:"albumArtist"`
+	DiscNo       *int    `json:"discNo,omitempty"`
+	TrackNo      *int    `json:"trackNo,omitempty"`
+	DurationMS   *int    `json:"durationMs,omitempty"`
+	Path         string  `json:"path"`
+	CoverPath    *string `json:"coverPath,omitempty"`
+	PlayCount    *int    `json:"playCount,omitempty"`
+	LastPlayedAt *string `json:"lastPlayedAt,omitempty"`
+	Rating       *int    `json:"rating,omitempty"`
+	Favorite     bool    `json:"favorite,omitempty"`
+}
+
+type ArtistsPage struct {
+	Items []Artist `json:"items"`
+	Page  PageInfo `json:"page"`
+}
+
+type AlbumsPage struct {
+	Items []Album  `json:"items"`
+	Page  PageInfo `json:"page"`
+}
+
+type TracksPage struct {
+	Items []Track  `json:"items"`
+	Page  PageInfo `json:"page"`
+}
+
+// ArtistDetail is an artist with one page of their albums.
+type ArtistDetail struct {
+	Artist
+	Albums []Album  `json:"albums"`
+	Page   PageInfo `json:"page"`
+}
+
+// AlbumDetail is an album with one page of its tracks.
+type AlbumDetail struct {
+	Album
+	Tracks []Track  `json:"tracks"`
+	Page   PageInfo `json:"page"`
+}
+
+// QueueState is the play queue. CurrentIndex is -1 when nothing is
+// selected; RepeatMode is "off", "all" or "one".
+type QueueState struct {
+	Entries      []Track       `json:"entries"`
+	Sources      []QueueSource `json:"sources"`
+	CurrentIndex int           `json:"currentIndex"`
+	RepeatMode   string        `json:"repeatMode"`
+	Shuffle      bool          `json:"shuffle"`
+	UpdatedAt    string        `json:"updatedAt"`
+}
+
+// QueueSource is where a run of queue entries was added from. Kind is
+// "album", "artist", "playlist", "search" or "manual"; only the fields of
+// that kind are set.
+type QueueSource struct {
+	Kind        string `json:"kind"`
+	AlbumTitle  string `json:"albumTitle,omitempty"`
+	AlbumArtist string `json:"albumArtist,omitempty"`
+	ArtistName  string `json:"artistName,omitempty"`
+	PlaylistID  int64  `json:"playlistId,omitempty"`
+	Query       string `json:"query,omitempty"`
+}
+
+// Overview sums up all listening. Play times are in milliseconds.
+type Overview struct {
+	TotalPlayedMS int          `json:"totalPlayedMs"`
+	TracksPlayed  int          `json:"tracksPlayed"`
+	CompleteCount int          `json:"completeCount"`
+	SkipCount     int          `json:"skipCount"`
+	PartialCount  int          `json:"partialCount"`
+	TopTracks     []TrackStat  `json:"topTracks"`
+	TopArtists    []ArtistStat `json:"topArtists"`
+}
+
+// Dashboard sums up the listening of one range. WindowStart is unset for
+// all time.
+type Dashboard struct {
+	Range         string       `json:"range"`
+	WindowStart   *string      `json:"windowStart,omitempty"`
+	GeneratedAt   string       `json:"generatedAt"`
+	TotalPlayedMS int          `json:"totalPlayedMs"`
+	TotalPlays    int          `json:"totalPlays"`
+	TracksPlayed  int          `json:"tracksPlayed"`
+	ArtistsPlayed int          `json:"artistsPlayed"`
+	AlbumsPlayed  int          `json:"albumsPlayed"`
+	TopTracks     []TrackStat  `json:"topTracks"`
+	TopArtists    []ArtistStat `json:"topArtists"`
+	TopAlbums     []AlbumStat  `json:"topAlbums"`
+}
+
+type TrackStat struct {
+	TrackID       int64   `json:"trackId"`
+	Title         string  `json:"title"`
+	Artist        string  `json:"artist"`
+	Album         string  `json:"album"`
+	CoverPath     *string `json:"coverPath,omitempty"`
+	PlayedMS      int     `json:"playedMs"`
+	CompleteCount int     `json:"completeCount"`
+	SkipCount     int     `json:"skipCount"`
+	PartialCount  int     `json:"partialCount"`
+}
+
+type ArtistStat struct {
+	Name       string `json:"name"`
+	PlayedMS   int    `json:"playedMs"`
+	TrackCount int    `json:"trackCount"`
+}
+
+type AlbumStat struct {
+	Title       string  `json:"title"`
+	AlbumArtist string  `json:"albumArtist"`
+	PlayedMS    int     `json:"playedMs"`
+	PlayCount   int     `json:"playCount"`
+	TrackCount  int     `json:"trackCount"`
+	CoverPath   *string `json:"coverPath,omitempty"`
+}
+
+// Ranges of Dashboard: the last 30 days, the last 180 days and all time.
+const (
+	DashboardRangeShort = stats.DashboardRangeShort
+	DashboardRangeMid   = stats.DashboardRangeMid
+	DashboardRangeLong  = stats.DashboardRangeLong
+)
+
+// LibraryReader browses artists, albums and tracks. Searches match titles,
+// artists and albums; empty filters match everything. withStats adds play
+// counts and last played times.
+type LibraryReader interface {
+	ListArtists(ctx context.Context, search string, limit int, offset int) (ArtistsPage, error)
+	ListAlbums(ctx context.Context, search string, artist string, limit int, offset int, withStats bool) (AlbumsPage, error)
+	ListTracks(ctx context.Context, search string, artist string, album string, limit int, offset int, withStats bool) (TracksPage, error)
+	GetArtistDetail(ctx context.Context, name string, limit int, offset int) (ArtistDetail, error)
+	GetAlbumDetail(ctx context.Context, title string, albumArtist string, limit int, offset int) (AlbumDetail, error)
+}
+
+// QueueReader reads the play queue as the app last saved it.
+type QueueReader interface {
+	GetState() QueueState
+}
+
+// StatsReader reads listening statistics. limit bounds the top lists.
+type StatsReader interface {
+	GetOverview(limit int) (Overview, error)
+	GetDashboard(rangeKey string, limit int) (Dashboard, error)
+}
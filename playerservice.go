@@ -1,13 +1,26 @@
 package main
 
-import "ben/internal/player"
+import (
+	"context"
+
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/player"
+)
 
 type PlayerService struct {
-	player *player.Service
+	player  *player.Service
+	mixes   *library.AlbumMixRepository
+	offsets *library.VolumeOffsetRepository
+	trims   *library.TrackTrimRepository
 }
 
-func NewPlayerService(playerService *player.Service) *PlayerService {
-	return &PlayerService{player: playerService}
+func NewPlayerService(
+	playerService *player.Service,
+	mixes *library.AlbumMixRepository,
+	offsets *library.VolumeOffsetRepository,
+	trims *library.TrackTrimRepository,
+) *PlayerService {
+	return &PlayerService{player: playerService, mixes: mixes, offsets: offsets, trims: trims}
 }
 
 func (s *PlayerService) GetState() player.State {
@@ -41,3 +54,206 @@ func (s *PlayerService) Seek(positionMS int) (player.State, error) {
 func (s *PlayerService) SetVolume(volume int) (player.State, error) {
 	return s.player.SetVolume(volume)
 }
+
+func (s *PlayerService) SetCrossfade(crossfadeMS int) (player.State, error) {
+	return s.player.SetCrossfade(crossfadeMS)
+}
+
+func (s *PlayerService) GetQuietHours() player.QuietHours {
+	return s.player.GetQuietHours()
+}
+
+func (s *PlayerService) SetQuietHours(config player.QuietHours) (player.QuietHours, error) {
+	return s.player.SetQuietHours(config)
+}
+
+func (s *PlayerService) GetAudioAccessibility() player.AudioAccessibility {
+	return s.player.GetAudioAccessibility()
+}
+
+func (s *PlayerService) SetAudioAccessibility(config player.AudioAccessibility) (player.AudioAccessibility, error) {
+	return s.player.SetAudioAccessibility(config)
+}
+
+func (s *PlayerService) GetBackendOptions() player.BackendOptionsStatus {
+	return s.player.GetBackendOptions()
+}
+
+func (s *PlayerService) SetBackendOptions(config player.BackendOptions) (player.BackendOptionsStatus, error) {
+	return s.player.SetBackendOptions(config)
+}
+
+func (s *PlayerService) ResetBackendOptions() (player.BackendOptionsStatus, error) {
+	return s.player.ResetBackendOptions()
+}
+
+func (s *PlayerService) GetSampleRatePolicy() player.SampleRatePolicy {
+	return s.player.GetSampleRatePolicy()
+}
+
+func (s *PlayerService) SetSampleRatePolicy(policy player.SampleRatePolicy) (player.SampleRatePolicy, error) {
+	return s.player.SetSampleRatePolicy(policy)
+}
+
+// StartSleepTimer pauses playback after durationMinutes, optionally fading
+// the volume out first.
+func (s *PlayerService) StartSleepTimer(durationMinutes int, fadeOut bool) (player.State, error) {
+	return s.player.StartSleepTimer(durationMinutes, fadeOut)
+}
+
+func (s *PlayerService) CancelSleepTimer() player.State {
+	return s.player.CancelSleepTimer()
+}
+
+func (s *PlayerService) SetStopAfterCurrent(enabled bool) player.State {
+	return s.player.SetStopAfterCurrent(enabled)
+}
+
+func (s *PlayerService) GetEqualizer() player.Equalizer {
+	return s.player.GetEqualizer()
+}
+
+// SetEqualizer saves the 10-band equalizer. Passing only a preset name
+// loads that preset's gains.
+func (s *PlayerService) SetEqualizer(config player.Equalizer) (player.Equalizer, error) {
+	return s.player.SetEqualizer(config)
+}
+
+func (s *PlayerService) GetEqualizerPresets() []player.EqualizerPreset {
+	return player.EqualizerPresets()
+}
+
+func (s *PlayerService) GetReplayGainMode() string {
+	return s.player.GetReplayGainMode()
+}
+
+// SetReplayGainMode picks loudness normalization: "off", "track" or
+// "album".
+func (s *PlayerService) SetReplayGainMode(mode string) (string, error) {
+	return s.player.SetReplayGainMode(mode)
+}
+
+func (s *PlayerService) SmartLevelingEnabled() bool {
+	return s.player.SmartLevelingEnabled()
+}
+
+// SetSmartLevelingEnabled turns on leveling of the queue to an even
+// loudness, including tracks without ReplayGain tags.
+func (s *PlayerService) SetSmartLevelingEnabled(enabled bool) (bool, error) {
+	return s.player.SetSmartLevelingEnabled(enabled)
+}
+
+func (s *PlayerService) GetTranscodeFallback() player.TranscodeFallbackStatus {
+	return s.player.GetTranscodeFallback()
+}
+
+// SetTranscodeFallback picks the format, "flac", "wav" or "off", files the
+// backend cannot decode are converted to with ffmpeg, by default and per
+// codec.
+func (s *PlayerService) SetTranscodeFallback(preferences player.TranscodePreferences) (player.TranscodeFallbackStatus, error) {
+	return s.player.SetTranscodeFallback(preferences)
+}
+
+func (s *PlayerService) PlaybackDiagnosticsEnabled() bool {
+	return s.player.PlaybackDiagnosticsEnabled()
+}
+
+// SetPlaybackDiagnosticsEnabled turns the technical playback log of load
+// times, seek latencies and underruns on or off.
+func (s *PlayerService) SetPlaybackDiagnosticsEnabled(enabled bool) (bool, error) {
+	return s.player.SetPlaybackDiagnosticsEnabled(enabled)
+}
+
+// GetPlaybackDiagnostics returns the playback log of every track, or of one
+// track when trackID is set.
+func (s *PlayerService) GetPlaybackDiagnostics(trackID int64, limit int) (player.PlaybackDiagnostics, error) {
+	return s.player.GetPlaybackDiagnostics(trackID, limit)
+}
+
+func (s *PlayerService) ClearPlaybackDiagnostics() error {
+	return s.player.ClearPlaybackDiagnostics()
+}
+
+func (s *PlayerService) GetAlbumMix(title string, albumArtist string) (library.AlbumMix, error) {
+	return s.mixes.GetAlbumMix(context.Background(), title, albumArtist)
+}
+
+// SetAlbumMixOverride forces an album on or off continuous-mix playback; a
+// nil override returns it to tag-based detection.
+func (s *PlayerService) SetAlbumMixOverride(title string, albumArtist string, override *bool) (library.AlbumMix, error) {
+	mix, err := s.mixes.SetAlbumMixOverride(context.Background(), title, albumArtist, override)
+	if err != nil {
+		return library.AlbumMix{}, err
+	}
+
+	s.player.InvalidateContinuousMix()
+	return mix, nil
+}
+
+func (s *PlayerService) PlayPreview(trackID int64, startMS int, durationMS int) (player.PreviewState, error) {
+	return s.player.PlayPreview(trackID, startMS, durationMS)
+}
+
+func (s *PlayerService) StopPreview() player.PreviewState {
+	return s.player.StopPreview()
+}
+
+func (s *PlayerService) GetPreviewState() player.PreviewState {
+	return s.player.GetPreviewState()
+}
+
+func (s *PlayerService) GetTrackVolumeOffset(trackID int64) (library.TrackVolumeOffset, error) {
+	return s.offsets.GetTrackVolumeOffset(context.Background(), trackID)
+}
+
+// SetTrackVolumeOffset stores a dB correction for one track; nil clears it.
+func (s *PlayerService) SetTrackVolumeOffset(trackID int64, offsetDB *float64) (library.TrackVolumeOffset, error) {
+	offset, err := s.offsets.SetTrackVolumeOffset(context.Background(), trackID, offsetDB)
+	if err != nil {
+		return library.TrackVolumeOffset{}, err
+	}
+
+	s.player.InvalidateVolumeOffsets()
+	return offset, nil
+}
+
+func (s *PlayerService) GetAlbumVolumeOffset(title string, albumArtist string) (library.AlbumVolumeOffset, error) {
+	return s.offsets.GetAlbumVolumeOffset(context.Background(), title, albumArtist)
+}
+
+// SetAlbumVolumeOffset stores a dB correction for every track of an album;
+// nil clears it.
+func (s *PlayerService) SetAlbumVolumeOffset(title string, albumArtist string, offsetDB *float64) (library.AlbumVolumeOffset, error) {
+	offset, err := s.offsets.SetAlbumVolumeOffset(context.Background(), title, albumArtist, offsetDB)
+	if err != nil {
+		return library.AlbumVolumeOffset{}, err
+	}
+
+	s.player.InvalidateVolumeOffsets()
+	return offset, nil
+}
+
+func (s *PlayerService) GetTrackTrims(trackID int64) (library.TrackTrim, error) {
+	return s.trims.GetTrackTrims(context.Background(), trackID)
+}
+
+// SetTrackTrims makes playback of a track start at startMS and stop at endMS;
+// a nil endMS plays to the end of the file.
+func (s *PlayerService) SetTrackTrims(trackID int64, startMS int, endMS *int) (library.TrackTrim, error) {
+	trim, err := s.trims.SetTrackTrims(context.Background(), trackID, startMS, endMS)
+	if err != nil {
+		return library.TrackTrim{}, err
+	}
+
+	s.player.InvalidateTrackTrims()
+	return trim, nil
+}
+
+func (s *PlayerService) ClearTrackTrims(trackID int64) error {
+	if err := s.trims.ClearTrackTrims(context.Background(), trackID); err != nil {
+		return err
+	}
+
+	s.player.InvalidateTrackTrims()
+	return nil
+}
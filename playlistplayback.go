@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+
+	"github.com/rzxx/ben/internal/eventbus"
+	"github.com/rzxx/ben/internal/player"
+	"github.com/rzxx/ben/internal/playlist"
+	"github.com/rzxx/ben/internal/queue"
+	"github.com/rzxx/ben/internal/settings"
+)
+
+// playlistPlaybackRestoreSettingKey keeps the values a playlist overrode, so
+// they are reverted even if Ben quits while the playlist plays.
+const playlistPlaybackRestoreSettingKey = "playlists.playback_restore"
+
+type playbackValues struct {
+	Shuffle     *bool   `json:"shuffle,omitempty"`
+	RepeatMode  *string `json:"repeatMode,omitempty"`
+	CrossfadeMS *int    `json:"crossfadeMs,omitempty"`
+}
+
+type playlistPlaybackOverride struct {
+	PlaylistID int64          `json:"playlistId"`
+	Previous   playbackValues `json:"previous"`
+	Applied    playbackValues `json:"applied"`
+}
+
+// PlaylistPlayback applies a playlist's playback settings while the current
+// queue entry comes from that playlist and reverts them once playback moves
+// elsewhere. A setting the user changed in the meantime is left as it is.
+type PlaylistPlayback struct {
+	playlists *playlist.Service
+	queue     *queue.Service
+	player    *player.Service
+	settings  *settings.Store
+	bus       *eventbus.Bus
+
+	mu       sync.Mutex
+	override *playlistPlaybackOverride
+}
+
+func NewPlaylistPlayback(playlists *playlist.Service, queueService *queue.Service, playerService *player.Service, store *settings.Store, bus *eventbus.Bus) *PlaylistPlayback {
+	return &PlaylistPlayback{playlists: playlists, queue: queueService, player: playerService, settings: store, bus: bus}
+}
+
+// Start restores an override left over from the last session, brings the
+// settings in line with the restored queue and starts following the queue.
+// Queue changes before Start are ignored, so they cannot revert an override
+// that was not restored yet.
+func (p *PlaylistPlayback) Start() {
+	raw := p.settings.GetString(context.Background(), playlistPlaybackRestoreSettingKey, "")
+	if raw != "" {
+		var override playlistPlaybackOverride
+		if err := json.Unmarshal([]byte(raw), &override); err == nil && override.PlaylistID > 0 {
+			p.mu.Lock()
+			p.override = &override
+			p.mu.Unlock()
+		}
+	}
+
+	p.sync()
+	eventbus.Subscribe(p.bus, queue.EventStateChanged, p.HandleQueueState)
+}
+
+// HandleQueueState re-evaluates the active playlist. Applying settings
+// changes the queue again, so the work runs off the publishing goroutine.
+func (p *PlaylistPlayback) HandleQueueState(queue.State) {
+	go p.sync()
+}
+
+func (p *PlaylistPlayback) sync() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	playlistID := activePlaylistID(p.queue.GetState())
+	if p.override != nil && p.override.PlaylistID == playlistID {
+		return
+	}
+
+	if p.override != nil {
+		p.revertLocked(*p.override)
+		p.override = nil
+	}
+	if playlistID > 0 {
+		playback, err := p.playlists.GetPlaybackSettings(playlistID)
+		if err != nil {
+			log.Printf("playlist playback settings: %v", err)
+		} else if !playback.IsEmpty() {
+			override := p.applyLocked(playlistID, playback)
+			p.override = &override
+		}
+	}
+
+	p.persistLocked()
+}
+
+func (p *PlaylistPlayback) applyLocked(playlistID int64, playback playlist.PlaybackSettings) playlistPlaybackOverride {
+	override := playlistPlaybackOverride{PlaylistID: playlistID}
+	queueState := p.queue.GetState()
+
+	if playback.Shuffle != nil {
+		previous := queueState.Shuffle
+		p.queue.SetShuffle(*playback.Shuffle)
+		override.Previous.Shuffle = &previous
+		override.Applied.Shuffle = playback.Shuffle
+	}
+	if playback.RepeatMode != nil {
+		previous := queueState.RepeatMode
+		if _, err := p.queue.SetRepeatMode(*playback.RepeatMode); err != nil {
+			log.Printf("apply playlist repeat mode: %v", err)
+		} else {
+			override.Previous.RepeatMode = &previous
+			override.Applied.RepeatMode = playback.RepeatMode
+		}
+	}
+
+	crossfadeMS := playback.CrossfadeMS
+	if playback.Gapless != nil && *playback.Gapless {
+		gapless := 0
+		crossfadeMS = &gapless
+	}
+	if crossfadeMS != nil {
+		previous := p.player.GetState().CrossfadeMS
+		if _, err := p.player.SetCrossfade(*crossfadeMS); err != nil {
+			log.Printf("apply playlist crossfade: %v", err)
+		} else {
+			override.Previous.CrossfadeMS = &previous
+			override.Applied.CrossfadeMS = crossfadeMS
+		}
+	}
+
+	return override
+}
+
+func (p *PlaylistPlayback) revertLocked(override playlistPlaybackOverride) {
+	queueState := p.queue.GetState()
+
+	if override.Previous.Shuffle != nil && queueState.Shuffle == *override.Applied.Shuffle {
+		p.queue.SetShuffle(*override.Previous.Shuffle)
+	}
+	if override.Previous.RepeatMode != nil && queueState.RepeatMode == *override.Applied.RepeatMode {
+		if _, err := p.queue.SetRepeatMode(*override.Previous.RepeatMode); err != nil {
+			log.Printf("revert playlist repeat mode: %v", err)
+		}
+	}
+	if override.Previous.CrossfadeMS != nil && p.player.GetState().CrossfadeMS == *override.Applied.CrossfadeMS {
+		if _, err := p.player.SetCrossfade(*override.Previous.CrossfadeMS); err != nil {
+			log.Printf("revert playlist crossfade: %v", err)
+		}
+	}
+}
+
+func (p *PlaylistPlayback) persistLocked() {
+	ctx := context.Background()
+	if p.override == nil {
+		if err := p.settings.Delete(ctx, playlistPlaybackRestoreSettingKey); err != nil {
+			log.Printf("clear playlist playback override: %v", err)
+		}
+		return
+	}
+
+	encoded, err := json.Marshal(p.override)
+	if err != nil {
+		return
+	}
+	if err := p.settings.Set(ctx, playlistPlaybackRestoreSettingKey, string(encoded)); err != nil {
+		log.Printf("store playlist playback override: %v", err)
+	}
+}
+
+// activePlaylistID returns the playlist the current queue entry was played
+// from, or 0.
+func activePlaylistID(state queue.State) int64 {
+	if state.CurrentIndex < 0 || state.CurrentIndex >= len(state.Sources) {
+		return 0
+	}
+
+	source := state.Sources[state.CurrentIndex]
+	if source.Kind != queue.SourcePlaylist {
+		return 0
+	}
+
+	return source.PlaylistID
+}
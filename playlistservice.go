@@ -0,0 +1,250 @@
+package main
+
+import (
+	"github.com/rzxx/ben/internal/playlist"
+	"github.com/rzxx/ben/internal/queue"
+	"github.com/rzxx/ben/internal/undo"
+)
+
+type PlaylistService struct {
+	playlists  *playlist.Service
+	folderSync *playlist.FolderSync
+	queue      *queue.Service
+	journal    *undo.Journal
+}
+
+func NewPlaylistService(playlists *playlist.Service, folderSync *playlist.FolderSync, queueService *queue.Service, journal *undo.Journal) *PlaylistService {
+	return &PlaylistService{playlists: playlists, folderSync: folderSync, queue: queueService, journal: journal}
+}
+
+func (s *PlaylistService) ListPlaylists() ([]playlist.Playlist, error) {
+	return s.playlists.List()
+}
+
+func (s *PlaylistService) GetPlaylist(id int64) (playlist.Detail, error) {
+	return s.playlists.Get(id)
+}
+
+func (s *PlaylistService) CreatePlaylist(name string, trackIDs []int64) (playlist.Playlist, error) {
+	return s.playlists.Create(name, trackIDs)
+}
+
+func (s *PlaylistService) SetPlaylistTracks(id int64, trackIDs []int64) (playlist.Playlist, error) {
+	previous, err := s.playlists.Snapshot(id)
+	if err != nil {
+		return playlist.Playlist{}, err
+	}
+
+	updated, err := s.playlists.SetTracks(id, trackIDs)
+	if err != nil {
+		return updated, err
+	}
+
+	next := append([]int64(nil), trackIDs...)
+	s.journal.Record(undo.Operation{
+		Label: "Edit playlist " + previous.Name,
+		Undo: func() error {
+			_, err := s.playlists.SetTracks(id, previous.TrackIDs)
+			return err
+		},
+		Redo: func() error {
+			_, err := s.playlists.SetTracks(id, next)
+			return err
+		},
+	})
+
+	return updated, nil
+}
+
+func (s *PlaylistService) RenamePlaylist(id int64, name string) (playlist.Playlist, error) {
+	previous, err := s.playlists.Snapshot(id)
+	if err != nil {
+		return playlist.Playlist{}, err
+	}
+
+	renamed, err := s.playlists.Rename(id, name)
+	if err != nil {
+		return renamed, err
+	}
+
+	next := renamed.Name
+	s.journal.Record(undo.Operation{
+		Label: "Rename playlist " + previous.Name,
+		Undo: func() error {
+			_, err := s.playlists.Rename(id, previous.Name)
+			return err
+		},
+		Redo: func() error {
+			_, err := s.playlists.Rename(id, next)
+			return err
+		},
+	})
+
+	return renamed, nil
+}
+
+func (s *PlaylistService) AddTracksToPlaylist(id int64, trackIDs []int64) (playlist.Playlist, error) {
+	return s.editTracks(id, func() (playlist.Playlist, error) {
+		return s.playlists.AddTracks(id, trackIDs)
+	})
+}
+
+// RemovePlaylistTracks removes tracks by their positions in GetPlaylist.
+func (s *PlaylistService) RemovePlaylistTracks(id int64, positions []int) (playlist.Playlist, error) {
+	return s.editTracks(id, func() (playlist.Playlist, error) {
+		return s.playlists.RemoveTracks(id, positions)
+	})
+}
+
+func (s *PlaylistService) MovePlaylistTrack(id int64, from int, to int) (playlist.Playlist, error) {
+	return s.editTracks(id, func() (playlist.Playlist, error) {
+		return s.playlists.MoveTrack(id, from, to)
+	})
+}
+
+// editTracks runs an entry edit and records it for undo like
+// SetPlaylistTracks does.
+func (s *PlaylistService) editTracks(id int64, edit func() (playlist.Playlist, error)) (playlist.Playlist, error) {
+	previous, err := s.playlists.Snapshot(id)
+	if err != nil {
+		return playlist.Playlist{}, err
+	}
+
+	updated, err := edit()
+	if err != nil {
+		return updated, err
+	}
+
+	next, err := s.playlists.Snapshot(id)
+	if err != nil {
+		return updated, nil
+	}
+
+	s.journal.Record(undo.Operation{
+		Label: "Edit playlist " + previous.Name,
+		Undo: func() error {
+			_, err := s.playlists.SetTracks(id, previous.TrackIDs)
+			return err
+		},
+		Redo: func() error {
+			_, err := s.playlists.SetTracks(id, next.TrackIDs)
+			return err
+		},
+	})
+
+	return updated, nil
+}
+
+// PlayPlaylist replaces the queue with the playlist, starting at
+// startIndex, the same way album and artist queues are started.
+func (s *PlaylistService) PlayPlaylist(id int64, startIndex int) (queue.State, error) {
+	trackIDs, err := s.playlistTrackIDs(id)
+	if err != nil {
+		return s.queue.GetState(), err
+	}
+
+	return s.queue.SetQueueFromSource(trackIDs, startIndex, queue.Source{Kind: queue.SourcePlaylist, PlaylistID: id})
+}
+
+func (s *PlaylistService) AppendPlaylistToQueue(id int64) (queue.State, error) {
+	trackIDs, err := s.playlistTrackIDs(id)
+	if err != nil {
+		return s.queue.GetState(), err
+	}
+
+	return s.queue.AppendTracksFromSource(trackIDs, queue.Source{Kind: queue.SourcePlaylist, PlaylistID: id})
+}
+
+func (s *PlaylistService) playlistTrackIDs(id int64) ([]int64, error) {
+	detail, err := s.playlists.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	trackIDs := make([]int64, 0, len(detail.Tracks))
+	for _, track := range detail.Tracks {
+		trackIDs = append(trackIDs, track.ID)
+	}
+
+	return trackIDs, nil
+}
+
+func (s *PlaylistService) DeletePlaylist(id int64) error {
+	snapshot, err := s.playlists.Snapshot(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.playlists.Delete(id); err != nil {
+		return err
+	}
+
+	s.journal.Record(undo.Operation{
+		Label: "Delete playlist " + snapshot.Name,
+		Undo: func() error {
+			_, err := s.playlists.Restore(snapshot)
+			return err
+		},
+		Redo: func() error {
+			return s.playlists.Delete(id)
+		},
+	})
+
+	return nil
+}
+
+func (s *PlaylistService) GetSyncFolder() string {
+	return s.folderSync.Folder()
+}
+
+func (s *PlaylistService) SetSyncFolder(path string) error {
+	return s.folderSync.SetFolder(path)
+}
+
+func (s *PlaylistService) SyncFolderNow() error {
+	return s.folderSync.SyncNow()
+}
+
+func (s *PlaylistService) ListPlaylistFolders() ([]playlist.Folder, error) {
+	return s.playlists.ListFolders()
+}
+
+func (s *PlaylistService) CreatePlaylistFolder(name string, parentID *int64) (playlist.Folder, error) {
+	return s.playlists.CreateFolder(name, parentID)
+}
+
+func (s *PlaylistService) RenamePlaylistFolder(id int64, name string) (playlist.Folder, error) {
+	return s.playlists.RenameFolder(id, name)
+}
+
+func (s *PlaylistService) MovePlaylistFolder(id int64, parentID *int64) (playlist.Folder, error) {
+	return s.playlists.MoveFolder(id, parentID)
+}
+
+func (s *PlaylistService) DeletePlaylistFolder(id int64) error {
+	return s.playlists.DeleteFolder(id)
+}
+
+func (s *PlaylistService) MovePlaylist(id int64, folderID *int64) (playlist.Playlist, error) {
+	return s.playlists.MovePlaylist(id, folderID)
+}
+
+func (s *PlaylistService) SetPlaylistTags(id int64, tags []string) (playlist.Playlist, error) {
+	return s.playlists.SetTags(id, tags)
+}
+
+func (s *PlaylistService) ListPlaylistTags() ([]playlist.Tag, error) {
+	return s.playlists.ListTags()
+}
+
+func (s *PlaylistService) ListPlaylistsByTag(tag string) ([]playlist.Playlist, error) {
+	return s.playlists.ListByTag(tag)
+}
+
+func (s *PlaylistService) GetPlaylistPlaybackSettings(id int64) (playlist.PlaybackSettings, error) {
+	return s.playlists.GetPlaybackSettings(id)
+}
+
+func (s *PlaylistService) SetPlaylistPlaybackSettings(id int64, settings playlist.PlaybackSettings) (playlist.Playlist, error) {
+	return s.playlists.SetPlaybackSettings(id, settings)
+}
@@ -1,13 +1,17 @@
 package main
 
-import "ben/internal/queue"
+import (
+	"github.com/rzxx/ben/internal/queue"
+	"github.com/rzxx/ben/internal/undo"
+)
 
 type QueueService struct {
-	queue *queue.Service
+	queue   *queue.Service
+	journal *undo.Journal
 }
 
-func NewQueueService(queueService *queue.Service) *QueueService {
-	return &QueueService{queue: queueService}
+func NewQueueService(queueService *queue.Service, journal *undo.Journal) *QueueService {
+	return &QueueService{queue: queueService, journal: journal}
 }
 
 func (s *QueueService) GetState() queue.State {
@@ -22,6 +26,47 @@ func (s *QueueService) AppendTracks(trackIDs []int64) (queue.State, error) {
 	return s.queue.AppendTracks(trackIDs)
 }
 
+func (s *QueueService) SetQueueFromSource(trackIDs []int64, startIndex int, source queue.Source) (queue.State, error) {
+	return s.queue.SetQueueFromSource(trackIDs, startIndex, source)
+}
+
+// ResumeFromSource replaces the queue with a context, starting at the track
+// that was playing when the queue last left it.
+func (s *QueueService) ResumeFromSource(trackIDs []int64, source queue.Source) (queue.State, error) {
+	return s.queue.ResumeFromSource(trackIDs, source)
+}
+
+// GetContextCursor returns the remembered position inside a context, or nil
+// when it was never played.
+func (s *QueueService) GetContextCursor(source queue.Source) (*queue.ContextCursor, error) {
+	cursor, found, err := s.queue.GetContextCursor(source)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	return &cursor, nil
+}
+
+func (s *QueueService) AppendTracksFromSource(trackIDs []int64, source queue.Source) (queue.State, error) {
+	return s.queue.AppendTracksFromSource(trackIDs, source)
+}
+
+func (s *QueueService) ResolveSource(index int) (queue.SourceLocation, error) {
+	return s.queue.ResolveSource(index)
+}
+
+// ReplaceFromAlbumOfIndex replaces the queue with the album of the entry at
+// index, keeping the playing track.
+func (s *QueueService) ReplaceFromAlbumOfIndex(index int) (queue.State, error) {
+	return s.queue.ReplaceFromAlbumOfIndex(index)
+}
+
+// ReplaceFromArtistOfIndex replaces the queue with the artist of the entry
+// at index, keeping the playing track.
+func (s *QueueService) ReplaceFromArtistOfIndex(index int) (queue.State, error) {
+	return s.queue.ReplaceFromArtistOfIndex(index)
+}
+
 func (s *QueueService) RemoveTrack(index int) (queue.State, error) {
 	return s.queue.RemoveTrack(index)
 }
@@ -31,7 +76,23 @@ func (s *QueueService) SetCurrentIndex(index int) (queue.State, error) {
 }
 
 func (s *QueueService) Clear() queue.State {
-	return s.queue.Clear()
+	previous := s.queue.GetState()
+	state := s.queue.Clear()
+	if len(previous.Entries) > 0 {
+		s.journal.Record(undo.Operation{
+			Label: "Clear queue",
+			Undo: func() error {
+				_, err := s.queue.Restore(previous)
+				return err
+			},
+			Redo: func() error {
+				s.queue.Clear()
+				return nil
+			},
+		})
+	}
+
+	return state
 }
 
 func (s *QueueService) SetRepeatMode(mode string) (queue.State, error) {
@@ -41,3 +102,27 @@ func (s *QueueService) SetRepeatMode(mode string) (queue.State, error) {
 func (s *QueueService) SetShuffle(enabled bool) queue.State {
 	return s.queue.SetShuffle(enabled)
 }
+
+func (s *QueueService) SetShuffleVideos(enabled bool) (queue.State, error) {
+	return s.queue.SetShuffleVideos(enabled)
+}
+
+func (s *QueueService) SetAlbumContextUpNext(enabled bool) (queue.State, error) {
+	return s.queue.SetAlbumContextUpNext(enabled)
+}
+
+func (s *QueueService) PlayFromAlbum(trackIDs []int64, source queue.Source) (queue.State, error) {
+	return s.queue.PlayFromAlbum(trackIDs, source)
+}
+
+func (s *QueueService) SetPreferBestVersion(enabled bool) (queue.State, error) {
+	return s.queue.SetPreferBestVersion(enabled)
+}
+
+func (s *QueueService) ExportQueue(path string) error {
+	return s.queue.ExportQueue(path)
+}
+
+func (s *QueueService) ImportQueue(path string) (queue.QueueImportResult, error) {
+	return s.queue.ImportQueue(path)
+}
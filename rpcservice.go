@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+
+	"github.com/rzxx/ben/internal/rpc"
+)
+
+// rpcSchemaVersion is bumped when service methods or their types change
+// incompatibly for clients generated from the schema.
+const rpcSchemaVersion = "1"
+
+// RPCService serves the bound services over JSON-RPC 2.0 on the asset
+// server, for remote-control clients. GET returns their OpenRPC schema.
+type RPCService struct {
+	handler *rpc.Handler
+}
+
+func NewRPCService(services []application.Service) *RPCService {
+	instances := make([]any, 0, len(services))
+	for _, service := range services {
+		instances = append(instances, service.Instance())
+	}
+	return &RPCService{handler: rpc.NewHandler("Ben", rpcSchemaVersion, instances)}
+}
+
+func (s *RPCService) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	s.handler.ServeHTTP(rw, req)
+}
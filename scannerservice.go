@@ -1,13 +1,41 @@
 package main
 
-import "ben/internal/scanner"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rzxx/ben/internal/jobs"
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/scanner"
+	"github.com/rzxx/ben/internal/snapshot"
+)
+
+// snapshotKindImport is the snapshot kind of the files moved by an
+// auto-import.
+const snapshotKindImport = "import"
 
 type ScannerService struct {
-	scanner *scanner.Service
+	scanner    *scanner.Service
+	autoImport *scanner.AutoImporter
+	jobs       *jobs.Manager
 }
 
-func NewScannerService(scanService *scanner.Service) *ScannerService {
-	return &ScannerService{scanner: scanService}
+// NewScannerService has every auto-import recorded by the snapshot service,
+// so the moves can be rolled back like other bulk operations.
+func NewScannerService(scanService *scanner.Service, autoImport *scanner.AutoImporter, jobManager *jobs.Manager, snapshots *snapshot.Service) *ScannerService {
+	autoImport.SetSnapshotter(func(ctx context.Context, files []scanner.ImportedFile) error {
+		return snapshots.CaptureFiles(ctx, fmt.Sprintf("Import %d files", len(files)), snapshotKindImport, files)
+	})
+	snapshots.SetRestorer(snapshotKindImport, func(_ context.Context, payload json.RawMessage) error {
+		var files []scanner.ImportedFile
+		if err := json.Unmarshal(payload, &files); err != nil {
+			return fmt.Errorf("decode imported files: %w", err)
+		}
+		return autoImport.RestoreImports(files)
+	})
+
+	return &ScannerService{scanner: scanService, autoImport: autoImport, jobs: jobManager}
 }
 
 func (s *ScannerService) TriggerFullScan() error {
@@ -25,3 +53,99 @@ func (s *ScannerService) TriggerIncrementalScan() error {
 func (s *ScannerService) GetStatus() scanner.Status {
 	return s.scanner.GetStatus()
 }
+
+// GetRatingTagPrecedence returns "database" or "tags", the side that wins
+// when imported rating tags disagree with a stored rating.
+func (s *ScannerService) GetRatingTagPrecedence() string {
+	return s.scanner.RatingTagPrecedence(context.Background())
+}
+
+func (s *ScannerService) SetRatingTagPrecedence(precedence string) (string, error) {
+	return s.scanner.SetRatingTagPrecedence(context.Background(), precedence)
+}
+
+// GetTagVerification reports whether scans hash the tag regions of files
+// whose size and modification time did not change.
+func (s *ScannerService) GetTagVerification() bool {
+	return s.scanner.TagVerificationEnabled(context.Background())
+}
+
+func (s *ScannerService) SetTagVerification(enabled bool) (bool, error) {
+	return s.scanner.SetTagVerificationEnabled(context.Background(), enabled)
+}
+
+// GetFingerprinting reports whether scans compute Chromaprint fingerprints
+// with fpcalc, used to find duplicates and relink moved files.
+func (s *ScannerService) GetFingerprinting() bool {
+	return s.scanner.FingerprintingEnabled(context.Background())
+}
+
+func (s *ScannerService) SetFingerprinting(enabled bool) (bool, error) {
+	return s.scanner.SetFingerprintingEnabled(context.Background(), enabled)
+}
+
+// GetArchiveIndexing reports whether scans index audio inside .zip
+// archives.
+func (s *ScannerService) GetArchiveIndexing() bool {
+	return s.scanner.ArchiveIndexingEnabled(context.Background())
+}
+
+func (s *ScannerService) SetArchiveIndexing(enabled bool) (bool, error) {
+	return s.scanner.SetArchiveIndexingEnabled(context.Background(), enabled)
+}
+
+// GetScanWorkers returns how many files scans read tags and artwork from
+// at once, 0 for one per CPU.
+func (s *ScannerService) GetScanWorkers() int {
+	return s.scanner.ScanWorkers(context.Background())
+}
+
+func (s *ScannerService) SetScanWorkers(workers int) (int, error) {
+	return s.scanner.SetScanWorkers(context.Background(), workers)
+}
+
+// GetExcludeRules returns the exclude rules applied to every watched root
+// on top of its own.
+func (s *ScannerService) GetExcludeRules() library.RootRules {
+	return s.scanner.ExcludeRules(context.Background())
+}
+
+func (s *ScannerService) SetExcludeRules(rules library.RootRules) (library.RootRules, error) {
+	return s.scanner.SetExcludeRules(context.Background(), rules)
+}
+
+// GetAutoImportConfig returns the folder whose new files are moved into the
+// library, and how they are organized there.
+func (s *ScannerService) GetAutoImportConfig() scanner.AutoImportConfig {
+	return s.autoImport.Config()
+}
+
+func (s *ScannerService) SetAutoImportConfig(config scanner.AutoImportConfig) (scanner.AutoImportConfig, error) {
+	return s.autoImport.SetConfig(context.Background(), config)
+}
+
+func (s *ScannerService) ImportNow() (scanner.ImportResult, error) {
+	return s.autoImport.ImportNow()
+}
+
+// GetLibraryChanges lists the albums that scans after sinceScanID added,
+// removed or updated tracks in.
+func (s *ScannerService) GetLibraryChanges(sinceScanID int64) (scanner.LibraryChanges, error) {
+	return s.scanner.GetLibraryChanges(context.Background(), sinceScanID)
+}
+
+// VerifyFiles checksums the files in scope as a job: the first run stores
+// checksums, later runs report files that changed without being rewritten.
+func (s *ScannerService) VerifyFiles(scope scanner.ChecksumScope) (scanner.ChecksumReport, error) {
+	return runJob(s.jobs, JobKindVerify, "File verification", func(ctx context.Context) (scanner.ChecksumReport, error) {
+		return s.scanner.VerifyFiles(ctx, scope)
+	})
+}
+
+func (s *ScannerService) ListCorruptedChecksums() ([]scanner.CorruptedChecksum, error) {
+	return s.scanner.ListCorruptedChecksums(context.Background())
+}
+
+func (s *ScannerService) CancelScan() bool {
+	return s.scanner.CancelScan()
+}
@@ -1,25 +1,30 @@
 package main
 
 import (
-	"ben/internal/library"
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"path/filepath"
 	"strings"
+
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/remote"
 )
 
 type SettingsService struct {
-	roots    *library.WatchedRootRepository
-	notifier watchedRootsNotifier
+	roots       *library.WatchedRootRepository
+	credentials *remote.Credentials
+	notifier    watchedRootsNotifier
 }
 
 type watchedRootsNotifier interface {
 	NotifyWatchedRootsChanged()
+	NotifyRootSchedulesChanged()
 }
 
-func NewSettingsService(roots *library.WatchedRootRepository, notifier watchedRootsNotifier) *SettingsService {
-	return &SettingsService{roots: roots, notifier: notifier}
+func NewSettingsService(roots *library.WatchedRootRepository, credentials *remote.Credentials, notifier watchedRootsNotifier) *SettingsService {
+	return &SettingsService{roots: roots, credentials: credentials, notifier: notifier}
 }
 
 func (s *SettingsService) ListWatchedRoots() ([]library.WatchedRoot, error) {
@@ -41,12 +46,44 @@ func (s *SettingsService) AddWatchedRoot(path string) (library.WatchedRoot, erro
 	return root, nil
 }
 
+// AddRemoteRoot registers a WebDAV or SFTP share as a library root.
+// Credentials may be passed separately or embedded in the URL; explicit
+// values win. The password is kept in the keychain, not the library.
+func (s *SettingsService) AddRemoteRoot(rawURL string, username string, password string) (library.WatchedRoot, error) {
+	rootURL, urlUsername, urlPassword, err := remote.NormalizeURL(rawURL)
+	if err != nil {
+		return library.WatchedRoot{}, err
+	}
+
+	if strings.TrimSpace(username) == "" {
+		username = urlUsername
+		if password == "" {
+			password = urlPassword
+		}
+	}
+
+	root, err := s.roots.AddRemote(context.Background(), remote.KindOf(rootURL), rootURL, strings.TrimSpace(username))
+	if err != nil {
+		return library.WatchedRoot{}, err
+	}
+	if err := s.credentials.SetPassword(root.ID, password); err != nil {
+		_ = s.roots.Delete(context.Background(), root.ID)
+		return library.WatchedRoot{}, err
+	}
+
+	s.notifyRootsChanged()
+	return root, nil
+}
+
 func (s *SettingsService) RemoveWatchedRoot(id int64) error {
 	err := s.roots.Delete(context.Background(), id)
 	if errors.Is(err, library.ErrWatchedRootNotFound) {
 		return fmt.Errorf("watched root %d does not exist", id)
 	}
 	if err == nil {
+		if err := s.credentials.DeletePassword(id); err != nil {
+			log.Printf("forget password of watched root %d: %v", id, err)
+		}
 		s.notifyRootsChanged()
 	}
 	return err
@@ -63,6 +100,39 @@ func (s *SettingsService) SetWatchedRootEnabled(id int64, enabled bool) error {
 	return err
 }
 
+// UpdateWatchedRootRules replaces the exclude patterns, hidden file and
+// minimum size rules of a root. The root is rescanned, so files the rules
+// now exclude leave the library.
+func (s *SettingsService) UpdateWatchedRootRules(id int64, rules library.RootRules) (library.WatchedRoot, error) {
+	root, err := s.roots.UpdateRules(context.Background(), id, rules)
+	if errors.Is(err, library.ErrWatchedRootNotFound) {
+		return library.WatchedRoot{}, fmt.Errorf("watched root %d does not exist", id)
+	}
+	if err != nil {
+		return library.WatchedRoot{}, err
+	}
+
+	s.notifyRootsChanged()
+	return root, nil
+}
+
+// UpdateWatchedRootSchedule replaces when a root is scanned automatically:
+// on startup, every few hours, and outside its quiet hours.
+func (s *SettingsService) UpdateWatchedRootSchedule(id int64, schedule library.RootSchedule) (library.WatchedRoot, error) {
+	root, err := s.roots.UpdateSchedule(context.Background(), id, schedule)
+	if errors.Is(err, library.ErrWatchedRootNotFound) {
+		return library.WatchedRoot{}, fmt.Errorf("watched root %d does not exist", id)
+	}
+	if err != nil {
+		return library.WatchedRoot{}, err
+	}
+
+	if s.notifier != nil {
+		s.notifier.NotifyRootSchedulesChanged()
+	}
+	return root, nil
+}
+
 func (s *SettingsService) notifyRootsChanged() {
 	if s.notifier == nil {
 		return
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// shutdownTimeout bounds the whole shutdown, so a stuck step can never keep
+// the app from quitting.
+const shutdownTimeout = 5 * time.Second
+
+type shutdownStep struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// ShutdownCoordinator runs the cleanup steps in the order they were added
+// before the app exits. Steps share one deadline; once it passes the
+// remaining steps are skipped and the app exits anyway.
+type ShutdownCoordinator struct {
+	timeout time.Duration
+
+	mu    sync.Mutex
+	steps []shutdownStep
+	once  sync.Once
+}
+
+func NewShutdownCoordinator(timeout time.Duration) *ShutdownCoordinator {
+	return &ShutdownCoordinator{timeout: timeout}
+}
+
+func (c *ShutdownCoordinator) Add(name string, run func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.steps = append(c.steps, shutdownStep{name: name, run: run})
+}
+
+// Run runs the steps once; later calls return straight away.
+func (c *ShutdownCoordinator) Run() {
+	c.once.Do(c.run)
+}
+
+func (c *ShutdownCoordinator) run() {
+	c.mu.Lock()
+	steps := append([]shutdownStep(nil), c.steps...)
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	for _, step := range steps {
+		done := make(chan error, 1)
+		go func() {
+			done <- step.run(ctx)
+		}()
+
+		select {
+		case err := <-done:
+			if err != nil {
+				log.Printf("shutdown %s: %v", step.name, err)
+			}
+		case <-ctx.Done():
+			log.Printf("shutdown %s: timed out, skipping remaining steps", step.name)
+			return
+		}
+	}
+}
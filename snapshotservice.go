@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rzxx/ben/internal/snapshot"
+)
+
+type SnapshotService struct {
+	snapshots *snapshot.Service
+}
+
+func NewSnapshotService(snapshots *snapshot.Service) *SnapshotService {
+	return &SnapshotService{snapshots: snapshots}
+}
+
+// GetLastOperation returns the operation that can still be rolled back, or
+// nil when there is none.
+func (s *SnapshotService) GetLastOperation() (*snapshot.Info, error) {
+	info, ok, err := s.snapshots.Last(context.Background())
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// ListOperations returns the operations that can still be rolled back,
+// newest first. RollbackLastOperation rolls back the first.
+func (s *SnapshotService) ListOperations() ([]snapshot.Info, error) {
+	return s.snapshots.List(context.Background())
+}
+
+func (s *SnapshotService) RollbackLastOperation() (snapshot.Info, error) {
+	return s.snapshots.RollbackLastOperation(context.Background())
+}
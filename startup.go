@@ -0,0 +1,115 @@
+package main
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// startupDeferredFallback runs the deferred steps even when the window never
+// reports that its runtime is ready, so the queue is always restored.
+const startupDeferredFallback = 5 * time.Second
+
+// StartupMark is one named point of startup, timed from process start.
+type StartupMark struct {
+	Name      string `json:"name"`
+	ElapsedMS int64  `json:"elapsedMs"`
+}
+
+// StartupReport lists the startup marks in the order they were reached.
+// WindowReadyMS is how long the window took to show, zero until it has.
+type StartupReport struct {
+	Marks         []StartupMark `json:"marks"`
+	WindowReadyMS int64         `json:"windowReadyMs"`
+	DeferredDone  bool          `json:"deferredDone"`
+}
+
+type startupStep struct {
+	name string
+	run  func()
+}
+
+// StartupTimer records startup timings and holds the non-essential loading
+// that waits until the window has painted.
+type StartupTimer struct {
+	startedAt time.Time
+
+	mu            sync.Mutex
+	marks         []StartupMark
+	windowReadyMS int64
+	deferredDone  bool
+	steps         []startupStep
+	once          sync.Once
+}
+
+func NewStartupTimer() *StartupTimer {
+	return &StartupTimer{startedAt: time.Now()}
+}
+
+// Mark records that startup reached name.
+func (t *StartupTimer) Mark(name string) {
+	elapsed := time.Since(t.startedAt).Milliseconds()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.marks = append(t.marks, StartupMark{Name: name, ElapsedMS: elapsed})
+}
+
+// Defer queues run until the window is ready. Steps run in order on one
+// background goroutine.
+func (t *StartupTimer) Defer(name string, run func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.steps = append(t.steps, startupStep{name: name, run: run})
+}
+
+// Start arms the fallback that runs the deferred steps if WindowReady is
+// never called.
+func (t *StartupTimer) Start() {
+	time.AfterFunc(startupDeferredFallback, func() {
+		t.once.Do(func() {
+			t.Mark("window ready timeout")
+			go t.runDeferred()
+		})
+	})
+}
+
+// WindowReady records that the first window painted and starts the deferred
+// steps. Later calls do nothing.
+func (t *StartupTimer) WindowReady() {
+	t.once.Do(func() {
+		t.Mark("window ready")
+
+		t.mu.Lock()
+		t.windowReadyMS = t.marks[len(t.marks)-1].ElapsedMS
+		t.mu.Unlock()
+		log.Printf("startup: window ready after %dms", t.windowReadyMS)
+
+		go t.runDeferred()
+	})
+}
+
+func (t *StartupTimer) runDeferred() {
+	t.mu.Lock()
+	steps := append([]startupStep(nil), t.steps...)
+	t.mu.Unlock()
+
+	for _, step := range steps {
+		step.run()
+		t.Mark(step.name)
+	}
+
+	t.mu.Lock()
+	t.deferredDone = true
+	t.mu.Unlock()
+}
+
+func (t *StartupTimer) Report() StartupReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return StartupReport{
+		Marks:         append([]StartupMark(nil), t.marks...),
+		WindowReadyMS: t.windowReadyMS,
+		DeferredDone:  t.deferredDone,
+	}
+}
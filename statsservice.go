@@ -1,13 +1,21 @@
 package main
 
-import "ben/internal/stats"
+import (
+	"github.com/rzxx/ben/internal/jobs"
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/player"
+	"github.com/rzxx/ben/internal/queue"
+	"github.com/rzxx/ben/internal/stats"
+)
 
 type StatsService struct {
-	stats *stats.Service
+	stats  *stats.Service
+	player *player.Service
+	jobs   *jobs.Manager
 }
 
-func NewStatsService(statsDomain *stats.Service) *StatsService {
-	return &StatsService{stats: statsDomain}
+func NewStatsService(statsDomain *stats.Service, playerDomain *player.Service, jobManager *jobs.Manager) *StatsService {
+	return &StatsService{stats: statsDomain, player: playerDomain, jobs: jobManager}
 }
 
 func (s *StatsService) GetOverview(limit int) (stats.Overview, error) {
@@ -17,3 +25,109 @@ func (s *StatsService) GetOverview(limit int) (stats.Overview, error) {
 func (s *StatsService) GetDashboard(rangeKey string, limit int) (stats.Dashboard, error) {
 	return s.stats.GetDashboard(rangeKey, limit)
 }
+
+func (s *StatsService) GetTopTracks(rangeKey string, limit int, combineLinked bool) ([]stats.TrackStat, error) {
+	return s.stats.GetTopTracks(rangeKey, limit, combineLinked)
+}
+
+func (s *StatsService) GetTrackStats(trackID int64, combineLinked bool) (stats.TrackVersionStats, error) {
+	return s.stats.GetTrackStats(trackID, combineLinked)
+}
+
+func (s *StatsService) StartSessionLabel(label string) (stats.SessionLabelState, error) {
+	return s.stats.StartSessionLabel(label)
+}
+
+func (s *StatsService) EndSessionLabel() stats.SessionLabelState {
+	return s.stats.EndSessionLabel()
+}
+
+func (s *StatsService) GetSessionLabel() stats.SessionLabelState {
+	return s.stats.GetSessionLabel()
+}
+
+func (s *StatsService) GetDashboardCollapsed(rangeKey string, limit int, collapse string) (stats.Dashboard, error) {
+	return s.stats.GetDashboardCollapsed(rangeKey, limit, collapse)
+}
+
+func (s *StatsService) GetTrackVersions(trackID int64, collapse string) (stats.TrackVersionStats, error) {
+	return s.stats.GetTrackVersions(trackID, collapse)
+}
+
+func (s *StatsService) GetDashboardLayout() stats.DashboardLayout {
+	return s.stats.GetDashboardLayout()
+}
+
+func (s *StatsService) SetDashboardLayout(layout stats.DashboardLayout) (stats.DashboardLayout, error) {
+	return s.stats.SetDashboardLayout(layout)
+}
+
+func (s *StatsService) ResetDashboardLayout() (stats.DashboardLayout, error) {
+	return s.stats.ResetDashboardLayout()
+}
+
+func (s *StatsService) ImportListeningLog(path string, device string) (stats.ListeningImportResult, error) {
+	task := s.jobs.Start(JobKindImport, "Listening log import", nil)
+	result, err := s.stats.ImportListeningLogFile(path, device)
+	if err != nil {
+		task.Fail(err)
+		return result, err
+	}
+
+	task.Complete("")
+	return result, nil
+}
+
+func (s *StatsService) GetTrimSuggestions(limit int) ([]stats.TrimSuggestion, error) {
+	return s.stats.GetTrimSuggestions(limit)
+}
+
+// ApplyTrimSuggestion trims the intro the listener keeps skipping.
+func (s *StatsService) ApplyTrimSuggestion(trackID int64) (library.TrackTrim, error) {
+	trim, err := s.stats.ApplyTrimSuggestion(trackID)
+	if err != nil {
+		return library.TrackTrim{}, err
+	}
+
+	s.player.InvalidateTrackTrims()
+	return trim, nil
+}
+
+func (s *StatsService) DismissTrimSuggestion(trackID int64) error {
+	return s.stats.DismissTrimSuggestion(trackID)
+}
+
+// GetListeningPairs lists albums or artists often played one after the
+// other; kind is "album" or "artist".
+func (s *StatsService) GetListeningPairs(kind string, from string, limit int) ([]stats.ListeningPair, error) {
+	return s.stats.GetListeningPairs(kind, from, limit)
+}
+
+// GetListeningSessions lists recorded listening sessions with the albums,
+// playlists and other sources played in each, newest first.
+func (s *StatsService) GetListeningSessions(limit int) ([]stats.ListeningSession, error) {
+	return s.stats.GetListeningSessions(limit)
+}
+
+// LoadListeningSession replaces the queue with the one a session ended with.
+func (s *StatsService) LoadListeningSession(id int64) (queue.State, error) {
+	return s.stats.LoadListeningSession(id)
+}
+
+func (s *StatsService) GetSessionSnapshots() bool {
+	return s.stats.SessionSnapshotsEnabled()
+}
+
+func (s *StatsService) SetSessionSnapshots(enabled bool) (bool, error) {
+	return s.stats.SetSessionSnapshotsEnabled(enabled)
+}
+
+// GetHeartbeatInterval returns the seconds of listening time each stats
+// heartbeat records.
+func (s *StatsService) GetHeartbeatInterval() int {
+	return s.stats.HeartbeatIntervalSeconds()
+}
+
+func (s *StatsService) SetHeartbeatInterval(seconds int) (int, error) {
+	return s.stats.SetHeartbeatIntervalSeconds(seconds)
+}
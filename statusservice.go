@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rzxx/ben/internal/backup"
+	"github.com/rzxx/ben/internal/db"
+	"github.com/rzxx/ben/internal/player"
+	"github.com/rzxx/ben/internal/scanner"
+)
+
+const (
+	AppStatusOK       = "ok"
+	AppStatusDegraded = "degraded"
+	AppStatusError    = "error"
+)
+
+// databaseCheckInterval limits how often the integrity check runs, since it
+// reads the whole database file.
+const databaseCheckInterval = 10 * time.Minute
+
+// AppStatus aggregates the health of the backend for the status indicator.
+// Issues carry a stable code the frontend can translate and a message with
+// what the user can do about it.
+type AppStatus struct {
+	Overall    string                `json:"overall"`
+	CheckedAt  string                `json:"checkedAt"`
+	Playback   player.BackendStatus  `json:"playback"`
+	Watcher    scanner.WatcherStatus `json:"watcher"`
+	Database   DatabaseStatus        `json:"database"`
+	CoverCache CoverCacheStatus      `json:"coverCache"`
+	Jobs       []BackgroundJob       `json:"jobs"`
+	Issues     []AppStatusIssue      `json:"issues"`
+}
+
+type DatabaseStatus struct {
+	Healthy   bool   `json:"healthy"`
+	Problem   string `json:"problem,omitempty"`
+	CheckedAt string `json:"checkedAt,omitempty"`
+}
+
+type CoverCacheStatus struct {
+	Path     string `json:"path"`
+	Exists   bool   `json:"exists"`
+	Writable bool   `json:"writable"`
+	Error    string `json:"error,omitempty"`
+}
+
+type BackgroundJob struct {
+	Name    string `json:"name"`
+	Running bool   `json:"running"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+type AppStatusIssue struct {
+	Component string `json:"component"`
+	Severity  string `json:"severity"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+}
+
+type StatusService struct {
+	db            *sql.DB
+	coverCacheDir string
+	player        *player.Service
+	scanner       *scanner.Service
+	backup        *backup.Service
+
+	mu             sync.Mutex
+	databaseStatus DatabaseStatus
+	databaseAt     time.Time
+}
+
+func NewStatusService(
+	database *sql.DB,
+	coverCacheDir string,
+	playerService *player.Service,
+	scannerService *scanner.Service,
+	backupService *backup.Service,
+) *StatusService {
+	return &StatusService{
+		db:            database,
+		coverCacheDir: coverCacheDir,
+		player:        playerService,
+		scanner:       scannerService,
+		backup:        backupService,
+	}
+}
+
+func (s *StatusService) GetAppStatus() AppStatus {
+	ctx := context.Background()
+	status := AppStatus{
+		Overall:    AppStatusOK,
+		CheckedAt:  time.Now().UTC().Format(time.RFC3339),
+		Playback:   s.player.GetBackendStatus(),
+		Watcher:    s.scanner.GetWatcherStatus(),
+		Database:   s.checkDatabase(ctx, false),
+		CoverCache: s.checkCoverCache(),
+		Jobs:       make([]BackgroundJob, 0, 2),
+		Issues:     make([]AppStatusIssue, 0),
+	}
+
+	scanStatus := s.scanner.GetStatus()
+	status.Jobs = append(status.Jobs, BackgroundJob{Name: "scan", Running: scanStatus.Running, Detail: scanStatus.LastMode})
+	if backupStatus, err := s.backup.Status(ctx); err == nil {
+		status.Jobs = append(status.Jobs, BackgroundJob{Name: "backup", Running: backupStatus.Running})
+		if backupStatus.LastError != nil {
+			status.addIssue("backup", AppStatusDegraded, "backup_failed", "The last backup failed: "+*backupStatus.LastError+". Check the backup folder in settings.")
+		}
+	}
+
+	if !status.Playback.Available {
+		status.addIssue("playback", AppStatusError, "playback_unavailable", "Audio playback is unavailable: "+status.Playback.Error+". Install libmpv and restart Ben, or reset the backend options.")
+	}
+	if !status.Database.Healthy {
+		status.addIssue("database", AppStatusError, "database_corrupt", "The library database failed its integrity check: "+status.Database.Problem+". Restore a backup from settings.")
+	}
+	if !status.Watcher.Watching {
+		status.addIssue("watcher", AppStatusDegraded, "watcher_stopped", "Library folders are not watched for changes. Run a scan to pick up new files.")
+	} else if status.Watcher.LastError != "" {
+		status.addIssue("watcher", AppStatusDegraded, "watcher_error", "The folder watcher reported an error: "+status.Watcher.LastError+".")
+	}
+	if scanStatus.LastError != "" {
+		status.addIssue("scan", AppStatusDegraded, "scan_failed", "The last scan failed: "+scanStatus.LastError+". Check that the library folders are reachable and scan again.")
+	}
+	if !status.CoverCache.Writable {
+		status.addIssue("coverCache", AppStatusDegraded, "cover_cache_unwritable", "Cover art cannot be cached in "+status.CoverCache.Path+". Check the folder's permissions and free space.")
+	}
+
+	return status
+}
+
+// RecheckDatabase runs the integrity check now instead of reusing the last
+// result.
+func (s *StatusService) RecheckDatabase() DatabaseStatus {
+	return s.checkDatabase(context.Background(), true)
+}
+
+func (s *AppStatus) addIssue(component string, severity string, code string, message string) {
+	s.Issues = append(s.Issues, AppStatusIssue{Component: component, Severity: severity, Code: code, Message: message})
+	if severity == AppStatusError || s.Overall == AppStatusOK {
+		s.Overall = severity
+	}
+}
+
+func (s *StatusService) checkDatabase(ctx context.Context, force bool) DatabaseStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if !force && !s.databaseAt.IsZero() && now.Sub(s.databaseAt) < databaseCheckInterval {
+		return s.databaseStatus
+	}
+
+	status := DatabaseStatus{Healthy: true, CheckedAt: now.UTC().Format(time.RFC3339)}
+	problem, err := db.QuickCheck(ctx, s.db)
+	if err != nil {
+		problem = err.Error()
+	}
+	if problem != "" {
+		status.Healthy = false
+		status.Problem = problem
+	}
+
+	s.databaseStatus = status
+	s.databaseAt = now
+	return status
+}
+
+// checkCoverCache confirms the cache folder exists and accepts new files by
+// writing and removing a probe file.
+func (s *StatusService) checkCoverCache() CoverCacheStatus {
+	status := CoverCacheStatus{Path: s.coverCacheDir}
+
+	info, err := os.Stat(s.coverCacheDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			status.Error = err.Error()
+			return status
+		}
+		if err := os.MkdirAll(s.coverCacheDir, 0o755); err != nil {
+			status.Error = err.Error()
+			return status
+		}
+	} else if !info.IsDir() {
+		status.Error = "cover cache path is not a folder"
+		return status
+	}
+	status.Exists = true
+
+	probe, err := os.CreateTemp(s.coverCacheDir, ".status-*")
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	probePath := probe.Name()
+	_ = probe.Close()
+	_ = os.Remove(probePath)
+	status.Writable = true
+
+	return status
+}
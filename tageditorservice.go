@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/rzxx/ben/internal/snapshot"
+	"github.com/rzxx/ben/internal/tageditor"
+	"github.com/rzxx/ben/internal/undo"
+)
+
+// snapshotKindTags is the snapshot kind of batch tag edits.
+const snapshotKindTags = "tags"
+
+type TagEditorService struct {
+	editor    *tageditor.Service
+	journal   *undo.Journal
+	snapshots *snapshot.Service
+}
+
+func NewTagEditorService(editor *tageditor.Service, journal *undo.Journal, snapshots *snapshot.Service) *TagEditorService {
+	snapshots.SetRestorer(snapshotKindTags, func(_ context.Context, payload json.RawMessage) error {
+		var changes []tageditor.FileChange
+		if err := json.Unmarshal(payload, &changes); err != nil {
+			return fmt.Errorf("decode tag changes: %w", err)
+		}
+		return editor.Revert(changes)
+	})
+
+	return &TagEditorService{editor: editor, journal: journal, snapshots: snapshots}
+}
+
+// EditTracks writes the edited tags to the files of the tracks and rescans
+// them, so the library and its albums follow the new tags. Undo writes back
+// the tags the files had. Batch edits are also kept by the snapshot service,
+// so they can be rolled back after later edits pushed them out of the undo
+// history.
+func (s *TagEditorService) EditTracks(trackIDs []int64, edit tageditor.Edit) (tageditor.Result, error) {
+	ctx := context.Background()
+	result, err := s.editor.EditTracks(ctx, trackIDs, edit)
+	if err != nil || len(result.Changes) == 0 {
+		return result, err
+	}
+
+	changes := result.Changes
+	s.journal.Record(undo.Operation{
+		Label: "Edit tags",
+		Undo: func() error {
+			return s.editor.Revert(changes)
+		},
+		Redo: func() error {
+			return s.editor.Reapply(changes)
+		},
+	})
+	if len(changes) > 1 {
+		// The files are already written, so a failed snapshot only costs
+		// the rollback.
+		if err := s.snapshots.CaptureFiles(ctx, fmt.Sprintf("Edit tags of %d tracks", len(changes)), snapshotKindTags, changes); err != nil {
+			log.Printf("snapshot tag edit: %v", err)
+		}
+	}
+
+	return result, nil
+}
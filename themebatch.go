@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/palette"
+)
+
+// EventAlbumPalette carries one palette of a batch started by
+// ExtractPalettesForAlbums.
+const EventAlbumPalette = "theme:album-palette"
+
+const maxPaletteWorkers = 4
+
+// AlbumPalette is the palette of one album cover. Palette is nil for albums
+// without a cover or whose cover could not be read; Error says why for the
+// latter.
+type AlbumPalette struct {
+	RequestID   string                `json:"requestId"`
+	Title       string                `json:"title"`
+	AlbumArtist string                `json:"albumArtist"`
+	Palette     *palette.ThemePalette `json:"palette,omitempty"`
+	Error       string                `json:"error,omitempty"`
+}
+
+// AlbumPaletteBatch holds the palettes that were ready right away. Pending
+// palettes arrive as EventAlbumPalette events with the same RequestID.
+type AlbumPaletteBatch struct {
+	RequestID string         `json:"requestId"`
+	Ready     []AlbumPalette `json:"ready"`
+	Pending   int            `json:"pending"`
+}
+
+var paletteBatchCounter atomic.Int64
+
+// ExtractPalettesForAlbums returns the stored palettes of the albums'
+// covers and extracts the missing ones in the background with a bounded
+// worker pool, so an album grid can be themed with one call.
+func (s *ThemeService) ExtractPalettesForAlbums(keys []library.AlbumKey) (AlbumPaletteBatch, error) {
+	ctx := context.Background()
+	batch := AlbumPaletteBatch{
+		RequestID: strconv.FormatInt(paletteBatchCounter.Add(1), 10),
+		Ready:     make([]AlbumPalette, 0, len(keys)),
+	}
+
+	covers, err := s.browse.GetAlbumCovers(ctx, keys)
+	if err != nil {
+		return AlbumPaletteBatch{}, err
+	}
+
+	coverIDs := make([]int64, 0, len(covers))
+	for _, cover := range covers {
+		if cover.CoverID != 0 {
+			coverIDs = append(coverIDs, cover.CoverID)
+		}
+	}
+	stored, err := s.browse.GetCoverPalettes(ctx, coverIDs)
+	if err != nil {
+		return AlbumPaletteBatch{}, err
+	}
+
+	missing := make([]library.AlbumCover, 0)
+	for _, cover := range covers {
+		result := AlbumPalette{RequestID: batch.RequestID, Title: cover.Title, AlbumArtist: cover.AlbumArtist}
+		if cover.CoverID == 0 {
+			batch.Ready = append(batch.Ready, result)
+			continue
+		}
+		if themePalette, ok := stored[cover.CoverID]; ok {
+			result.Palette = &themePalette
+			batch.Ready = append(batch.Ready, result)
+			continue
+		}
+		missing = append(missing, cover)
+	}
+
+	batch.Pending = len(missing)
+	if len(missing) > 0 {
+		go s.extractAlbumPalettes(batch.RequestID, missing)
+	}
+
+	return batch, nil
+}
+
+func (s *ThemeService) extractAlbumPalettes(requestID string, covers []library.AlbumCover) {
+	options := palette.DefaultExtractOptions()
+	// Parallelism comes from the pool; one image per worker keeps the CPU
+	// use bounded.
+	options.WorkerCount = 1
+
+	jobs := make(chan library.AlbumCover)
+	var wg sync.WaitGroup
+	for range min(maxPaletteWorkers, runtime.NumCPU(), len(covers)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for cover := range jobs {
+				result := AlbumPalette{RequestID: requestID, Title: cover.Title, AlbumArtist: cover.AlbumArtist}
+				themePalette, err := s.GenerateFromCover(cover.CachePath, options)
+				if err != nil {
+					result.Error = err.Error()
+				} else {
+					result.Palette = &themePalette
+					// A palette that cannot be stored is extracted again next time.
+					_ = s.browse.SaveCoverPalette(context.Background(), cover.CoverID, themePalette)
+				}
+				emitAlbumPalette(result)
+			}
+		}()
+	}
+
+	for _, cover := range covers {
+		jobs <- cover
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func emitAlbumPalette(result AlbumPalette) {
+	if app := application.Get(); app != nil {
+		app.Event.Emit(EventAlbumPalette, result)
+	}
+}
@@ -1,13 +1,15 @@
 package main
 
 import (
-	"ben/internal/palette"
 	"errors"
 	"fmt"
 	"os"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/rzxx/ben/internal/library"
+	"github.com/rzxx/ben/internal/palette"
 )
 
 const maxThemeCacheEntries = 96
@@ -19,14 +21,19 @@ type themeCacheEntry struct {
 }
 
 type ThemeService struct {
+	browse    *library.BrowseRepository
 	resolver  *CoverService
 	extractor *palette.Extractor
 	cacheMu   sync.RWMutex
 	cache     map[string]themeCacheEntry
+
+	ambientMu      sync.Mutex
+	ambientLimiter ambientLimiter
 }
 
-func NewThemeService(coverCacheDir string) *ThemeService {
+func NewThemeService(browse *library.BrowseRepository, coverCacheDir string) *ThemeService {
 	return &ThemeService{
+		browse:    browse,
 		resolver:  NewCoverService(nil, coverCacheDir),
 		extractor: palette.NewExtractor(),
 		cache:     make(map[string]themeCacheEntry),
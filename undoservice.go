@@ -0,0 +1,23 @@
+package main
+
+import "github.com/rzxx/ben/internal/undo"
+
+type UndoService struct {
+	journal *undo.Journal
+}
+
+func NewUndoService(journal *undo.Journal) *UndoService {
+	return &UndoService{journal: journal}
+}
+
+func (s *UndoService) GetState() undo.State {
+	return s.journal.State()
+}
+
+func (s *UndoService) Undo() (undo.State, error) {
+	return s.journal.Undo()
+}
+
+func (s *UndoService) Redo() (undo.State, error) {
+	return s.journal.Redo()
+}